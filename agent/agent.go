@@ -59,8 +59,54 @@ const (
 	StorageAddr            = "STORAGE_ADDR"
 	AgentServiceName       = "AGENT_SERVICE_NAME"
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
+	MongoCacheSize         = "MONGO_CACHE_SIZE"
+	MongoJournalCommit     = "MONGO_JOURNAL_COMMIT_INTERVAL"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+
+	// The following keys hold the API server's rate limits; see
+	// apiserver.RateLimitConfig.
+	APIMaxConcurrentLogins    = "API_MAX_CONCURRENT_LOGINS"
+	APILoginAttemptsPerMinute = "API_LOGIN_ATTEMPTS_PER_MINUTE"
+	APIConnCallsPerSecond     = "API_CONN_CALLS_PER_SECOND"
+	APIEntityCallsPerSecond   = "API_ENTITY_CALLS_PER_SECOND"
+
+	// AuditLogFile holds the path of the file to which the API server
+	// logs state-changing API calls. No file is written if it is unset.
+	AuditLogFile = "AUDIT_LOG_FILE"
+
+	// MongoReadPreferences holds the mongo read preferences of the
+	// API server's categories of read-only calls, as a comma-separated
+	// list of category=preference pairs, for example "status=nearest".
+	MongoReadPreferences = "MONGO_READ_PREFERENCES"
+
+	// WatcherCoalesceWindow and WatcherCoalesceMaxDelay hold the
+	// coalescing of the state server's watchers, as durations such as
+	// "500ms"; see state.WatcherCoalescing. The maximum delay defaults
+	// to ten times the window.
+	WatcherCoalesceWindow   = "WATCHER_COALESCE_WINDOW"
+	WatcherCoalesceMaxDelay = "WATCHER_COALESCE_MAX_DELAY"
+
+	// MongoCertRotation holds the serial number of the last mongo
+	// certificate rotation applied by the machine agent.
+	MongoCertRotation = "MONGO_CERT_ROTATION"
+
+	// APIAddressesSRV holds the name of the DNS SRV records from which
+	// the agent resolves the API server addresses, for example
+	// "_juju-api._tcp.example.com". The addresses in the agent config,
+	// kept up to date by the apiaddressupdater worker, are used if it
+	// is unset or the lookup fails.
+	APIAddressesSRV = "API_ADDRESSES_SRV"
+
+	// DiskSpaceThreshold holds the space, in MiB, below which the
+	// machine agent considers a filesystem it uses to be low on space;
+	// see diskspace.DefaultThreshold.
+	DiskSpaceThreshold = "DISK_SPACE_THRESHOLD"
+
+	// DiskSpaceCleanups holds the cleanups the machine agent runs when
+	// a filesystem it uses is low on space, as a comma-separated list
+	// of "tools" and "logs". No cleanups are run if it is unset.
+	DiskSpaceCleanups = "DISK_SPACE_CLEANUPS"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -170,6 +216,10 @@ type ConfigSetterOnly interface {
 	// SetAPIHostPorts sets the API host/port addresses to connect to.
 	SetAPIHostPorts(servers [][]network.HostPort)
 
+	// SetCACert sets the CA certificate used to validate the
+	// state and API servers. It may hold several certificates.
+	SetCACert(caCert string)
+
 	// Migrate takes an existing agent config and applies the given
 	// parameters to change it.
 	//
@@ -381,8 +431,9 @@ func ConfigPath(dataDir string, tag names.Tag) string {
 // ReadConfig reads configuration data from the given location.
 func ReadConfig(configFilePath string) (ConfigSetterWriter, error) {
 	var (
-		format formatter
-		config *configInternal
+		format    formatter
+		config    *configInternal
+		plaintext bool
 	)
 	configData, err := ioutil.ReadFile(configFilePath)
 	if err != nil {
@@ -410,7 +461,7 @@ func ReadConfig(configFilePath string) (ConfigSetterWriter, error) {
 		config, err = format.unmarshal(configData)
 	} else {
 		// Does not exist, just parse the data.
-		format, config, err = parseConfigData(configData)
+		format, config, plaintext, err = parseConfigData(configData, dir)
 	}
 	if err != nil {
 		return nil, err
@@ -428,6 +479,13 @@ func ReadConfig(configFilePath string) (ConfigSetterWriter, error) {
 		if err != nil && !os.IsNotExist(err) {
 			return nil, fmt.Errorf("cannot remove legacy format file %q: %v", legacyFormatPath, err)
 		}
+	} else if plaintext {
+		// The config was written where the machine-local key was not
+		// available, as by cloud-init; seal the secrets now.
+		if err := config.Write(); err != nil {
+			return nil, fmt.Errorf("cannot seal agent config secrets: %v", err)
+		}
+		logger.Debugf("sealed agent config secrets")
 	}
 	return config, nil
 }
@@ -480,6 +538,10 @@ func (c *configInternal) SetUpgradedToVersion(newVersion version.Number) {
 	c.upgradedToVersion = newVersion
 }
 
+func (c *configInternal) SetCACert(caCert string) {
+	c.caCert = caCert
+}
+
 func (c *configInternal) SetAPIHostPorts(servers [][]network.HostPort) {
 	if c.apiDetails == nil {
 		return
@@ -516,15 +578,19 @@ func (c *configInternal) SetPassword(newPassword string) {
 }
 
 func (c *configInternal) Write() error {
-	data, err := c.fileContents()
-	if err != nil {
-		return err
-	}
 	// Make sure the config dir gets created.
 	configDir := filepath.Dir(c.configFilePath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("cannot create agent config dir %q: %v", configDir, err)
 	}
+	sealer, err := NewSealer(configDir)
+	if err != nil {
+		return errors.Annotate(err, "cannot seal agent config secrets")
+	}
+	data, err := c.fileContents(sealer)
+	if err != nil {
+		return err
+	}
 	return utils.AtomicWriteFile(c.configFilePath, data, 0600)
 }
 
@@ -587,7 +653,23 @@ func (c *configInternal) APIAddresses() ([]string, error) {
 	if c.apiDetails == nil {
 		return []string{}, errors.New("No apidetails in config")
 	}
-	return append([]string{}, c.apiDetails.addresses...), nil
+	return c.apiAddresses(), nil
+}
+
+var lookupSRVHostPorts = network.LookupSRVHostPorts
+
+// apiAddresses returns the API server addresses resolved from the DNS
+// SRV records named in the config, if any, falling back to the
+// addresses held in the config.
+func (c *configInternal) apiAddresses() []string {
+	if name := c.Value(APIAddressesSRV); name != "" {
+		hps, err := lookupSRVHostPorts(name)
+		if err == nil {
+			return network.HostPortsToStrings(hps)
+		}
+		logger.Warningf("cannot resolve API addresses from %q, using addresses in agent config: %v", name, err)
+	}
+	return append([]string{}, c.apiDetails.addresses...)
 }
 
 func (c *configInternal) OldPassword() string {
@@ -637,8 +719,10 @@ func checkAddrs(addrs []string, what string) error {
 	return nil
 }
 
-func (c *configInternal) fileContents() ([]byte, error) {
-	data, err := currentFormat.marshal(c)
+// fileContents returns the contents of the agent config file, with the
+// secrets sealed by the given sealer if it is not nil.
+func (c *configInternal) fileContents(sealer Sealer) ([]byte, error) {
+	data, err := currentFormat.marshal(c, sealer)
 	if err != nil {
 		return nil, err
 	}
@@ -649,7 +733,10 @@ func (c *configInternal) fileContents() ([]byte, error) {
 }
 
 func (c *configInternal) WriteCommands(renderer shell.Renderer) ([]string, error) {
-	data, err := c.fileContents()
+	// The machine-local key does not exist on the target machine yet,
+	// so the secrets are written in plain text, and sealed by the agent
+	// when it first reads its config.
+	data, err := c.fileContents(nil)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -662,7 +749,7 @@ func (c *configInternal) WriteCommands(renderer shell.Renderer) ([]string, error
 
 func (c *configInternal) APIInfo() *api.Info {
 	servingInfo, isStateServer := c.StateServingInfo()
-	addrs := c.apiDetails.addresses
+	addrs := c.apiAddresses()
 	if isStateServer {
 		port := servingInfo.APIPort
 		localAPIAddr := net.JoinHostPort("localhost", strconv.Itoa(port))
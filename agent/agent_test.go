@@ -614,6 +614,39 @@ func (*suite) TestAPIInfoDoesntAddLocalhostWhenNoServingInfoPreferIPv6On(c *gc.C
 	c.Assert(apiinfo.Addrs, gc.DeepEquals, attrParams.APIAddresses)
 }
 
+func (s *suite) TestAPIAddressesFromSRV(c *gc.C) {
+	s.PatchValue(agent.LookupSRVHostPorts, func(name string) ([]network.HostPort, error) {
+		c.Assert(name, gc.Equals, "_juju-api._tcp.example.com")
+		return network.NewHostPorts(17070, "api.example.com"), nil
+	})
+	attrParams := attributeParams
+	attrParams.Values = map[string]string{
+		agent.APIAddressesSRV: "_juju-api._tcp.example.com",
+	}
+	conf, err := agent.NewAgentConfig(attrParams)
+	c.Assert(err, jc.ErrorIsNil)
+	addrs, err := conf.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"api.example.com:17070"})
+	c.Assert(conf.APIInfo().Addrs, jc.DeepEquals, []string{"api.example.com:17070"})
+}
+
+func (s *suite) TestAPIAddressesFromSRVFallsBack(c *gc.C) {
+	s.PatchValue(agent.LookupSRVHostPorts, func(name string) ([]network.HostPort, error) {
+		return nil, fmt.Errorf("lookup failed")
+	})
+	attrParams := attributeParams
+	attrParams.Values = map[string]string{
+		agent.APIAddressesSRV: "_juju-api._tcp.example.com",
+	}
+	conf, err := agent.NewAgentConfig(attrParams)
+	c.Assert(err, jc.ErrorIsNil)
+	addrs, err := conf.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, attrParams.APIAddresses)
+	c.Assert(conf.APIInfo().Addrs, jc.DeepEquals, attrParams.APIAddresses)
+}
+
 func (*suite) TestSetPassword(c *gc.C) {
 	attrParams := attributeParams
 	servingInfo := stateServingInfo()
@@ -674,6 +707,16 @@ func (*suite) TestSetUpgradedToVersion(c *gc.C) {
 	c.Assert(conf.UpgradedToVersion(), gc.Equals, expectVers)
 }
 
+func (*suite) TestSetCACert(c *gc.C) {
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conf.CACert(), gc.Equals, attributeParams.CACert)
+
+	conf.SetCACert("new-ca-cert")
+	c.Assert(conf.CACert(), gc.Equals, "new-ca-cert")
+	c.Assert(conf.APIInfo().CACert, gc.Equals, "new-ca-cert")
+}
+
 func (*suite) TestSetAPIHostPorts(c *gc.C) {
 	conf, err := agent.NewAgentConfig(attributeParams)
 	c.Assert(err, jc.ErrorIsNil)
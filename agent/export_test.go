@@ -54,4 +54,5 @@ func ConfigFileExists(config Config) bool {
 var (
 	MachineJobFromParams = machineJobFromParams
 	IsLocalEnv           = &isLocalEnv
+	LookupSRVHostPorts   = &lookupSRVHostPorts
 )
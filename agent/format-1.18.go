@@ -73,6 +73,11 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 	if err := goyaml.Unmarshal(data, &format); err != nil {
 		return nil, err
 	}
+	return format.configInternal()
+}
+
+// configInternal returns the agent config held in the serialization.
+func (format *format_1_18Serialization) configInternal() (*configInternal, error) {
 	if format.UpgradedToVersion == nil || *format.UpgradedToVersion == version.Zero {
 		// Assume we upgrade from 1.16.
 		upgradedToVersion := version.MustParse("1.16.0")
@@ -153,7 +158,10 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 	return config, nil
 }
 
-func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
+// newFormat_1_18Serialization returns the serialization of the given
+// agent config. The 1.18 format is no longer written; this is used by
+// the formats that extend it.
+func newFormat_1_18Serialization(config *configInternal) *format_1_18Serialization {
 	var envTag string
 	if config.environment.Id() != "" {
 		envTag = config.environment.String()
@@ -188,5 +196,5 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		format.APIAddresses = config.apiDetails.addresses
 		format.APIPassword = config.apiDetails.password
 	}
-	return goyaml.Marshal(format)
+	return format
 }
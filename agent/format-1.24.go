@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"encoding/base64"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+var format_1_24 = formatter_1_24{}
+
+// formatter_1_24 is the formatter for the 1.24 format. It extends the
+// 1.18 format by holding the agent's secrets sealed with a
+// machine-local key, rather than in plain text.
+type formatter_1_24 struct {
+}
+
+// Ensure that the formatter_1_24 struct implements the sealingFormatter
+// interface.
+var _ sealingFormatter = formatter_1_24{}
+
+// format_1_24Serialization holds information for a given agent.
+type format_1_24Serialization struct {
+	format_1_18Serialization `yaml:",inline"`
+
+	// Secrets holds the base64 encoded, sealed serialization of the
+	// secrets that would otherwise be held in plain text in the fields
+	// above.
+	Secrets string `yaml:",omitempty"`
+}
+
+// format_1_24Secrets holds the secrets of a given agent.
+type format_1_24Secrets struct {
+	StatePassword  string `yaml:",omitempty"`
+	APIPassword    string `yaml:",omitempty"`
+	OldPassword    string `yaml:",omitempty"`
+	StateServerKey string `yaml:",omitempty"`
+	CAPrivateKey   string `yaml:",omitempty"`
+	SharedSecret   string `yaml:",omitempty"`
+	SystemIdentity string `yaml:",omitempty"`
+}
+
+func init() {
+	registerFormat(format_1_24)
+}
+
+func (formatter_1_24) version() string {
+	return "1.24"
+}
+
+func (f formatter_1_24) unmarshal(data []byte) (*configInternal, error) {
+	config, _, err := f.unmarshalSealed(data, "")
+	return config, err
+}
+
+func (formatter_1_24) unmarshalSealed(data []byte, agentDir string) (*configInternal, bool, error) {
+	var format format_1_24Serialization
+	if err := goyaml.Unmarshal(data, &format); err != nil {
+		return nil, false, err
+	}
+	plaintext := format.secrets() != format_1_24Secrets{}
+	if format.Secrets != "" {
+		if agentDir == "" {
+			return nil, false, errors.New("cannot unseal agent secrets without an agent directory")
+		}
+		sealer, err := NewSealer(agentDir)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		if err := format.unseal(sealer); err != nil {
+			return nil, false, errors.Annotate(err, "cannot unseal agent secrets")
+		}
+	}
+	config, err := format.configInternal()
+	if err != nil {
+		return nil, false, err
+	}
+	return config, plaintext, nil
+}
+
+// marshal returns the serialization of the given config. If sealer is
+// not nil, the secrets are sealed with it; otherwise they are written
+// in plain text, to be sealed when the config is next read on the
+// agent's machine, where the key is kept.
+func (formatter_1_24) marshal(config *configInternal, sealer Sealer) ([]byte, error) {
+	format := &format_1_24Serialization{
+		format_1_18Serialization: *newFormat_1_18Serialization(config),
+	}
+	if sealer != nil {
+		if err := format.seal(sealer); err != nil {
+			return nil, errors.Annotate(err, "cannot seal agent secrets")
+		}
+	}
+	return goyaml.Marshal(format)
+}
+
+// secrets returns the secrets held in plain text in the serialization.
+func (format *format_1_24Serialization) secrets() format_1_24Secrets {
+	return format_1_24Secrets{
+		StatePassword:  format.StatePassword,
+		APIPassword:    format.APIPassword,
+		OldPassword:    format.OldPassword,
+		StateServerKey: format.StateServerKey,
+		CAPrivateKey:   format.CAPrivateKey,
+		SharedSecret:   format.SharedSecret,
+		SystemIdentity: format.SystemIdentity,
+	}
+}
+
+// setSecrets sets the secrets held in plain text in the serialization.
+func (format *format_1_24Serialization) setSecrets(secrets format_1_24Secrets) {
+	format.StatePassword = secrets.StatePassword
+	format.APIPassword = secrets.APIPassword
+	format.OldPassword = secrets.OldPassword
+	format.StateServerKey = secrets.StateServerKey
+	format.CAPrivateKey = secrets.CAPrivateKey
+	format.SharedSecret = secrets.SharedSecret
+	format.SystemIdentity = secrets.SystemIdentity
+}
+
+// seal moves the plain text secrets into the sealed Secrets field.
+func (format *format_1_24Serialization) seal(sealer Sealer) error {
+	data, err := goyaml.Marshal(format.secrets())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sealed, err := sealer.Seal(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	format.setSecrets(format_1_24Secrets{})
+	format.Secrets = base64.StdEncoding.EncodeToString(sealed)
+	return nil
+}
+
+// unseal restores the plain text secrets from the sealed Secrets field.
+func (format *format_1_24Serialization) unseal(sealer Sealer) error {
+	sealed, err := base64.StdEncoding.DecodeString(format.Secrets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := sealer.Unseal(sealed)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var secrets format_1_24Secrets
+	if err := goyaml.Unmarshal(data, &secrets); err != nil {
+		return errors.Trace(err)
+	}
+	format.setSecrets(secrets)
+	format.Secrets = ""
+	return nil
+}
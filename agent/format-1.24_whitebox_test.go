@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloudinit"
+	"github.com/juju/juju/testing"
+)
+
+type format_1_24Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&format_1_24Suite{})
+
+func newTestStateMachineConfig(c *gc.C) *configInternal {
+	servingInfo := params.StateServingInfo{
+		Cert:           "some special cert",
+		PrivateKey:     "a special key",
+		CAPrivateKey:   "ca special key",
+		StatePort:      12345,
+		APIPort:        23456,
+		SharedSecret:   "a shared secret",
+		SystemIdentity: "a system identity",
+	}
+	params := agentParams
+	params.DataDir = c.MkDir()
+	config, err := NewStateMachineConfig(params, servingInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	return config.(*configInternal)
+}
+
+func assertNoPlaintextSecrets(c *gc.C, configPath string) {
+	data, err := ioutil.ReadFile(configPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.HasPrefix, "# format 1.24\n")
+	c.Assert(string(data), jc.Contains, "secrets: ")
+	for _, secret := range []string{
+		"sekrit", "a special key", "ca special key", "a shared secret", "a system identity",
+	} {
+		c.Check(strings.Contains(string(data), secret), jc.IsFalse, gc.Commentf("%q found", secret))
+	}
+}
+
+func (*format_1_24Suite) TestWriteSealsSecrets(c *gc.C) {
+	config := newTestStateMachineConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoPlaintextSecrets(c, config.configFilePath)
+	assertFileExists(c, filepath.Join(config.Dir(), agentKeyFilename))
+
+	readConfig, err := ReadConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig, jc.DeepEquals, config)
+}
+
+func (*format_1_24Suite) TestReadWithWrongKey(c *gc.C) {
+	config := newTestConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Remove(filepath.Join(config.Dir(), agentKeyFilename))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = ReadConfig(config.configFilePath)
+	c.Assert(err, gc.ErrorMatches, "cannot unseal agent secrets: .*")
+}
+
+func (*format_1_24Suite) TestReadSealsPlaintextSecrets(c *gc.C) {
+	// WriteCommands writes the secrets in plain text, as the key is not
+	// yet available on the target machine.
+	config := newTestStateMachineConfig(c)
+	data, err := config.fileContents(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, "statepassword: sekrit")
+	err = os.MkdirAll(config.Dir(), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = utils.AtomicWriteFile(config.configFilePath, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	readConfig, err := ReadConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig, jc.DeepEquals, config)
+	assertNoPlaintextSecrets(c, config.configFilePath)
+}
+
+func (*format_1_24Suite) TestMigrateFrom1_18(c *gc.C) {
+	config := newTestStateMachineConfig(c)
+	data, err := goyaml.Marshal(newFormat_1_18Serialization(config))
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(config.Dir(), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = utils.AtomicWriteFile(config.configFilePath, append([]byte("# format 1.18\n"), data...), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	readConfig, err := ReadConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig, jc.DeepEquals, config)
+	assertNoPlaintextSecrets(c, config.configFilePath)
+
+	readConfig, err = ReadConfig(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readConfig, jc.DeepEquals, config)
+	c.Assert(Password(readConfig), gc.Equals, "sekrit")
+}
+
+func (*format_1_24Suite) TestWriteCommandsWritesPlaintext(c *gc.C) {
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	config := newTestConfig(c)
+	commands, err := config.WriteCommands(cloudcfg.ShellRenderer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(commands[1], jc.Contains, "# format 1.24\n")
+	c.Assert(commands[1], jc.Contains, "statepassword: sekrit")
+	c.Assert(commands[1], gc.Not(jc.Contains), "secrets: ")
+	assertFileNotExist(c, filepath.Join(config.Dir(), agentKeyFilename))
+}
+
+func (*format_1_24Suite) TestUnsealWithKeySealer(c *gc.C) {
+	// The secrets can be unsealed away from the agent directory, given
+	// the contents of its agent.key, as when restoring a backup.
+	config := newTestStateMachineConfig(c)
+	err := config.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := ioutil.ReadFile(filepath.Join(config.Dir(), agentKeyFilename))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(config.configFilePath)
+	c.Assert(err, jc.ErrorIsNil)
+	var format format_1_24Serialization
+	err = goyaml.Unmarshal(data, &format)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sealer, err := NewKeySealer(key)
+	c.Assert(err, jc.ErrorIsNil)
+	err = format.unseal(sealer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(format.StatePassword, gc.Equals, "sekrit")
+}
+
+func (*format_1_24Suite) TestNewKeySealerInvalidKey(c *gc.C) {
+	_, err := NewKeySealer([]byte("too short"))
+	c.Assert(err, gc.ErrorMatches, "agent key has invalid size 9")
+}
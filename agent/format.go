@@ -14,7 +14,9 @@ import (
 // Current agent config format is defined as follows:
 // # format <version>\n   (very first line; <version> is 1.18 or later)
 // <config-encoded-as-yaml>
-// All of this is saved in a single agent.conf file.
+// All of this is saved in a single agent.conf file. From 1.24, the
+// agent's passwords and keys are held sealed with a machine-local key
+// (see Sealer) rather than in plain text.
 //
 // Historically the format file in the agent config directory was used
 // to identify the method of serialization. This was used by
@@ -44,6 +46,18 @@ type formatter interface {
 	unmarshal(data []byte) (*configInternal, error)
 }
 
+// sealingFormatter is implemented by formats that hold the agent's
+// secrets sealed with a machine-local key (see Sealer).
+type sealingFormatter interface {
+	formatter
+
+	// unmarshalSealed is like unmarshal, but unseals the secrets with
+	// the key for the given agent directory. It also reports whether
+	// the data held any secrets in plain text, so that the config can
+	// be written again with them sealed.
+	unmarshalSealed(data []byte, agentDir string) (config *configInternal, plaintext bool, err error)
+}
+
 func registerFormat(format formatter) {
 	formats[format.version()] = format
 }
@@ -55,7 +69,7 @@ func registerFormat(format formatter) {
 // - Remove the marshal() method from the old format;
 
 // currentFormat holds the current agent config version's formatter.
-var currentFormat = format_1_18
+var currentFormat = format_1_24
 
 // agentConfigFilename is the default file name of used for the agent
 // config.
@@ -82,23 +96,33 @@ func getFormatter(version string) (formatter, error) {
 	return format, nil
 }
 
-func parseConfigData(data []byte) (formatter, *configInternal, error) {
+// parseConfigData parses the contents of an agent config file held in
+// the given agent directory. It also reports whether the config holds
+// secrets in plain text that the format would have sealed.
+func parseConfigData(data []byte, agentDir string) (formatter, *configInternal, bool, error) {
 	i := bytes.IndexByte(data, '\n')
 	if i == -1 {
-		return nil, nil, fmt.Errorf("invalid agent config format: %s", string(data))
+		return nil, nil, false, fmt.Errorf("invalid agent config format: %s", string(data))
 	}
 	version, configData := string(data[0:i]), data[i+1:]
 	if !strings.HasPrefix(version, formatPrefix) {
-		return nil, nil, fmt.Errorf("malformed agent config format %q", version)
+		return nil, nil, false, fmt.Errorf("malformed agent config format %q", version)
 	}
 	version = strings.TrimPrefix(version, formatPrefix)
 	format, err := getFormatter(version)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
+	}
+	if sealing, ok := format.(sealingFormatter); ok {
+		config, plaintext, err := sealing.unmarshalSealed(configData, agentDir)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return format, config, plaintext, nil
 	}
 	config, err := format.unmarshal(configData)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
-	return format, config, nil
+	return format, config, false, nil
 }
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// agentKeyFilename is the name of the file in the agent directory that
+// holds the key used by the file-based sealer.
+const agentKeyFilename = "agent.key"
+
+// agentKeySize is the size in bytes of the file-based sealer's key,
+// which makes it an AES-256 key.
+const agentKeySize = 32
+
+// Sealer seals and unseals the secrets held in an agent's config with a
+// key that does not leave the machine, so the secrets are not stored in
+// plain text in agent.conf.
+type Sealer interface {
+	// Seal returns the given data encrypted with the machine-local key.
+	Seal(data []byte) ([]byte, error)
+
+	// Unseal returns the data encrypted by Seal.
+	Unseal(sealed []byte) ([]byte, error)
+}
+
+// NewSealer returns the Sealer used for the agent config in the given
+// agent directory. By default the key is kept in a file next to the
+// agent config, readable only by its owner; a sealer backed by a TPM
+// can be used by replacing this.
+var NewSealer = func(agentDir string) (Sealer, error) {
+	return newFileSealer(agentDir)
+}
+
+// fileSealer is a Sealer that encrypts data with AES-GCM, using a key
+// stored in a file in the agent directory.
+type fileSealer struct {
+	aead cipher.AEAD
+}
+
+// newFileSealer returns a fileSealer using the key in the given agent
+// directory, creating the key if there is none yet.
+func newFileSealer(agentDir string) (*fileSealer, error) {
+	key, err := readOrCreateKey(filepath.Join(agentDir, agentKeyFilename))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get agent key")
+	}
+	return newKeySealer(key)
+}
+
+// NewKeySealer returns a Sealer using the given key, as held in the
+// agent.key file of an agent directory. It allows the secrets in an
+// agent config to be unsealed away from the agent's machine, as when
+// restoring a backup of the agent directory.
+func NewKeySealer(key []byte) (Sealer, error) {
+	return newKeySealer(key)
+}
+
+func newKeySealer(key []byte) (*fileSealer, error) {
+	if len(key) != agentKeySize {
+		return nil, errors.Errorf("agent key has invalid size %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &fileSealer{aead}, nil
+}
+
+func readOrCreateKey(path string) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err == nil {
+		if len(key) != agentKeySize {
+			return nil, errors.Errorf("key in %q has invalid size %d", path, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	key = make([]byte, agentKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := utils.AtomicWriteFile(path, key, 0600); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
+// Seal implements Sealer. The random nonce is prepended to the
+// encrypted data.
+func (s *fileSealer) Seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Unseal implements Sealer.
+func (s *fileSealer) Unseal(sealed []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("sealed data too short")
+	}
+	data, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
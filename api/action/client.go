@@ -49,6 +49,15 @@ func (c *Client) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return results, err
 }
 
+// EnqueueAll takes a list of Actions whose receivers are services, and
+// queues each up on all the units of its service, on no more than the
+// Action's Parallelism of them at once if that is positive.
+func (c *Client) EnqueueAll(arg params.Actions) (params.ServiceActionResults, error) {
+	results := params.ServiceActionResults{}
+	err := c.facade.FacadeCall("EnqueueAll", arg, &results)
+	return results, err
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and returns
 // all of the Actions that have been queued or run by each of those
 // Entities.
@@ -76,6 +85,21 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
+// ListSchedules returns the Actions that are scheduled to be queued up
+// later, in the order in which they are next queued up.
+func (c *Client) ListSchedules() (params.ActionResults, error) {
+	results := params.ActionResults{}
+	err := c.facade.FacadeCall("ListSchedules", nil, &results)
+	return results, err
+}
+
+// RemoveSchedules cancels the ActionSchedules with the given ids.
+func (c *Client) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	err := c.facade.FacadeCall("RemoveSchedules", arg, &results)
+	return results, err
+}
+
 // Cancel attempts to cancel a queued up Action from running.
 func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
 	results := params.ActionResults{}
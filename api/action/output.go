@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// OutputWatcher reports the output of an action as it is written.
+type OutputWatcher interface {
+	// Next blocks until the action has written more output, or has
+	// finished, and returns the output written since the last call.
+	Next() (params.ActionOutputWatcherNextResult, error)
+
+	// Stop stops the watcher.
+	Stop() error
+}
+
+// WatchActionOutput returns an OutputWatcher that reports the output
+// of the given action, from its start, while it runs.
+func (c *Client) WatchActionOutput(tag names.ActionTag) (OutputWatcher, error) {
+	var results params.ActionOutputWatchResults
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	if err := c.facade.FacadeCall("WatchActionOutput", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &outputWatcher{
+		caller: c.facade.RawAPICaller(),
+		id:     result.ActionOutputWatcherId,
+	}, nil
+}
+
+type outputWatcher struct {
+	caller base.APICaller
+	id     string
+}
+
+// Next is part of the OutputWatcher interface.
+func (w *outputWatcher) Next() (params.ActionOutputWatcherNextResult, error) {
+	var result params.ActionOutputWatcherNextResult
+	err := w.caller.APICall(
+		"ActionOutputWatcher", w.caller.BestFacadeVersion("ActionOutputWatcher"),
+		w.id, "Next", nil, &result)
+	return result, err
+}
+
+// Stop is part of the OutputWatcher interface.
+func (w *outputWatcher) Stop() error {
+	return w.caller.APICall(
+		"ActionOutputWatcher", w.caller.BestFacadeVersion("ActionOutputWatcher"),
+		w.id, "Stop", nil, nil)
+}
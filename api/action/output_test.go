@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/action"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type outputSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&outputSuite{})
+
+var outputTag = names.NewActionTag("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+
+func (s *outputSuite) patchResults(c *gc.C, results []params.ActionOutputWatchResult) func() {
+	return action.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "WatchActionOutput")
+			c.Check(paramsIn, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: outputTag.String()}},
+			})
+			result, ok := resp.(*params.ActionOutputWatchResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = results
+			return nil
+		},
+	)
+}
+
+func (s *outputSuite) TestWatchActionOutput(c *gc.C) {
+	cleanup := s.patchResults(c, []params.ActionOutputWatchResult{{ActionOutputWatcherId: "1"}})
+	defer cleanup()
+
+	w, err := s.client.WatchActionOutput(outputTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.NotNil)
+}
+
+func (s *outputSuite) TestWatchActionOutputError(c *gc.C) {
+	cleanup := s.patchResults(c, []params.ActionOutputWatchResult{{
+		Error: &params.Error{Message: "action not found"},
+	}})
+	defer cleanup()
+
+	_, err := s.client.WatchActionOutput(outputTag)
+	c.Assert(err, gc.ErrorMatches, "action not found")
+}
+
+func (s *outputSuite) TestWatchActionOutputWrongResults(c *gc.C) {
+	cleanup := s.patchResults(c, nil)
+	defer cleanup()
+
+	_, err := s.client.WatchActionOutput(outputTag)
+	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 0")
+}
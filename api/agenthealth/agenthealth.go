@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agenthealth
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const agentHealthFacade = "AgentHealth"
+
+// State provides access to an agenthealth worker's view of the state.
+type State struct {
+	facade base.FacadeCaller
+	tag    names.Tag
+}
+
+// NewState creates a new client-side AgentHealth facade.
+func NewState(caller base.APICaller, authTag names.Tag) *State {
+	return &State{
+		base.NewFacadeCaller(caller, agentHealthFacade),
+		authTag,
+	}
+}
+
+// SetAgentHealth records the health of the agent identified by the
+// authenticated tag.
+func (st *State) SetAgentHealth(health params.AgentHealth) error {
+	args := params.SetAgentHealthArgs{
+		Args: []params.SetAgentHealth{{
+			Tag:    st.tag.String(),
+			Health: health,
+		}},
+	}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetAgentHealth", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agenthealth_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/agenthealth"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&AgentHealthSuite{})
+
+type AgentHealthSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealth(c *gc.C) {
+	health := params.AgentHealth{
+		HookQueueDepth:   3,
+		LastAPICall:      time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+		DataDirAvailable: 2048,
+	}
+
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "AgentHealth")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetAgentHealth")
+		c.Check(arg, gc.DeepEquals, params.SetAgentHealthArgs{
+			Args: []params.SetAgentHealth{{
+				Tag:    "unit-mysql-0",
+				Health: health,
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: nil,
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := agenthealth.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	err := st.SetAgentHealth(health)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthCallError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("blargh")
+	})
+	st := agenthealth.NewState(apiCaller, names.NewMachineTag("0"))
+	err := st.SetAgentHealth(params.AgentHealth{})
+	c.Check(err, gc.ErrorMatches, "blargh")
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthResultError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	st := agenthealth.NewState(apiCaller, names.NewMachineTag("0"))
+	err := st.SetAgentHealth(params.AgentHealth{})
+	c.Check(err, gc.ErrorMatches, "permission denied")
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -17,7 +18,9 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/parallel"
 	"golang.org/x/net/websocket"
+	"gopkg.in/macaroon.v1"
 
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/network"
@@ -81,6 +84,17 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// mu guards lastSuccessfulCall and watcherMux.
+	mu sync.Mutex
+
+	// lastSuccessfulCall holds the time at which the most recent
+	// API call to complete without error returned.
+	lastSuccessfulCall time.Time
+
+	// watcherMux holds the Multiplexer used by the connection's
+	// watchers, once one has been started.
+	watcherMux *watcher.Multiplexer
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -109,6 +123,25 @@ type Info struct {
 	// EnvironTag holds the environ tag for the environment we are
 	// trying to connect to.
 	EnvironTag names.EnvironTag
+
+	// UseMacaroons specifies that the user logs in with macaroons
+	// discharged by the environment's identity provider, rather than
+	// with Tag and Password, which must not be set.
+	UseMacaroons bool `yaml:",omitempty"`
+
+	// Macaroons holds macaroons, such as those of scoped credentials,
+	// that the user logs in with rather than with Tag and Password,
+	// which must not be set.
+	Macaroons []macaroon.Slice `yaml:"-"`
+}
+
+// MacaroonDischarger acquires the discharges of a macaroon's third-party
+// caveats, such as the one requiring the user to log in to the identity
+// provider.
+type MacaroonDischarger interface {
+	// DischargeAll returns the macaroon bound to the discharges of
+	// all its third-party caveats.
+	DischargeAll(m *macaroon.Macaroon) (macaroon.Slice, error)
 }
 
 // DialOpts holds configuration parameters that control the
@@ -125,6 +158,10 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// Discharger, if set, is used to discharge the macaroons that the
+	// API server requires a user logging in with macaroons to present.
+	Discharger MacaroonDischarger
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -149,8 +186,46 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 // This unexported open method is used both directly above in the Open
 // function, and also the OpenWithVersion function below to explicitly cause
 // the API server to think that the client is older than it really is.
+//
+// If the API server connected to is overloaded or going away when
+// logging in, the connection is attempted again with the other
+// addresses.
 func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce string) error) (*State, error) {
-	conn, err := Connect(info, "", nil, opts)
+	addrs := info.Addrs
+	for {
+		attemptInfo := *info
+		attemptInfo.Addrs = addrs
+		st, err := openAttempt(&attemptInfo, opts, loginFunc)
+		unhealthy, ok := err.(*unhealthyServerError)
+		if !ok {
+			return st, err
+		}
+		apiEndpointHealth.recordFailure(unhealthy.addr)
+		addrs = removeAddr(addrs, unhealthy.addr)
+		if len(addrs) == 0 {
+			return nil, unhealthy.err
+		}
+		logger.Infof("cannot log in to API server at %q, trying other addresses: %v", unhealthy.addr, unhealthy.err)
+	}
+}
+
+// unhealthyServerError is returned by openAttempt when logging in
+// failed because the API server is overloaded or going away.
+type unhealthyServerError struct {
+	addr string
+	err  error
+}
+
+func (e *unhealthyServerError) Error() string {
+	return e.err.Error()
+}
+
+func openAttempt(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce string) error) (*State, error) {
+	// Ask the server to compress large messages; servers that do
+	// not support compression ignore the header.
+	header := http.Header{}
+	header.Set(jsoncodec.CompressionHeader, jsoncodec.CompressionDeflate)
+	conn, err := Connect(info, "", header, opts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -168,11 +243,17 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 		password: info.Password,
 		certPool: conn.Config().TlsConfig.RootCAs,
 	}
-	if info.Tag != nil || info.Password != "" {
-		if err := loginFunc(st, info.Tag.String(), info.Password, info.Nonce); err != nil {
-			conn.Close()
-			return nil, err
+	if info.UseMacaroons || len(info.Macaroons) > 0 {
+		err = st.loginWithMacaroons(info.Macaroons, opts.Discharger)
+	} else if info.Tag != nil || info.Password != "" {
+		err = loginFunc(st, info.Tag.String(), info.Password, info.Nonce)
+	}
+	if err != nil {
+		conn.Close()
+		if isUnhealthyServerError(err) {
+			return nil, &unhealthyServerError{addr: st.addr, err: err}
 		}
+		return nil, err
 	}
 	st.broken = make(chan struct{})
 	st.closed = make(chan struct{})
@@ -201,7 +282,8 @@ func OpenWithVersion(info *Info, opts DialOpts, loginVersion int) (*State, error
 // Connect establishes a websocket connection to the API server using
 // the Info, API path tail and (optional) request headers provided. If
 // multiple API addresses are provided in Info they will be tried
-// concurrently - the first successful connection wins.
+// concurrently - the first successful connection wins. The addresses
+// are dialed at intervals, in the order given by dialOrder.
 //
 // The path tail may be blank, in which case the default value will be
 // used. Otherwise, it must start with a "/".
@@ -220,17 +302,7 @@ func Connect(info *Info, pathTail string, header http.Header, opts DialOpts) (*w
 	}
 	pool.AddCert(xcert)
 
-	// If they exist, only use localhost addresses.
-	var addrs []string
-	for _, addr := range info.Addrs {
-		if strings.HasPrefix(addr, "localhost:") {
-			addrs = append(addrs, addr)
-			break
-		}
-	}
-	if len(addrs) == 0 {
-		addrs = info.Addrs
-	}
+	addrs := dialOrder(info.Addrs)
 
 	path := makeAPIPath(info.EnvironTag.Id(), pathTail)
 
@@ -327,8 +399,10 @@ func createWebsocketDialer(cfg *websocket.Config, opts DialOpts) func(<-chan str
 			logger.Infof("dialing %q", cfg.Location)
 			conn, err := websocket.DialConfig(cfg)
 			if err == nil {
+				apiEndpointHealth.recordSuccess(cfg.Location.Host)
 				return conn, nil
 			}
+			apiEndpointHealth.recordFailure(cfg.Location.Host)
 			if a.HasNext() {
 				logger.Debugf("error dialing %q, will retry: %v", cfg.Location, err)
 			} else {
@@ -369,7 +443,41 @@ func (s *State) APICall(facade string, version int, id, method string, args, res
 		Id:      id,
 		Action:  method,
 	}, args, response)
-	return params.ClientError(err)
+	if err == nil {
+		s.mu.Lock()
+		s.lastSuccessfulCall = time.Now()
+		s.mu.Unlock()
+	}
+	err = params.ClientError(err)
+	if err, ok := err.(*params.Error); ok && err.TraceId != "" {
+		logger.Debugf("%s(%d).%s call failed, server trace id %s: %v", facade, version, method, err.TraceId, err)
+	}
+	return err
+}
+
+// LastSuccessfulCall returns the time at which the most recent API call
+// made on the connection completed without error. It returns the zero
+// time if no call has yet succeeded.
+func (s *State) LastSuccessfulCall() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSuccessfulCall
+}
+
+// WatcherMultiplexer returns the Multiplexer that delivers the changes
+// of the connection's NotifyWatchers and StringsWatchers, or nil if the
+// API server does not support multiplexing them. It is part of the
+// watcher.MultiplexingCaller interface.
+func (s *State) WatcherMultiplexer() *watcher.Multiplexer {
+	if _, ok := s.facadeVersions["WatcherMultiplexer"]; !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcherMux == nil {
+		s.watcherMux = watcher.NewMultiplexer(s)
+	}
+	return s.watcherMux
 }
 
 func (s *State) Close() error {
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"golang.org/x/net/websocket"
 
@@ -19,8 +20,11 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
 )
 
@@ -30,6 +34,12 @@ type apiclientSuite struct {
 
 var _ = gc.Suite(&apiclientSuite{})
 
+func (s *apiclientSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api.ResetEndpointHealth()
+	s.AddCleanup(func(*gc.C) { api.ResetEndpointHealth() })
+}
+
 func (s *apiclientSuite) TestConnectToEnv(c *gc.C) {
 	info := s.APIInfo(c)
 	conn, err := api.Connect(info, "", nil, api.DialOpts{})
@@ -172,6 +182,81 @@ func (s *apiclientSuite) TestConnectMultipleError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unable to connect to "wss://.*/environment/[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}/api"`)
 }
 
+func (s *apiclientSuite) TestDialOrder(c *gc.C) {
+	addrs := []string{
+		"54.1.2.3:17070",
+		"10.0.0.1:17070",
+		"example.com:17070",
+		"192.168.1.1:17070",
+	}
+	c.Assert(api.DialOrder(addrs), jc.DeepEquals, []string{
+		"10.0.0.1:17070",
+		"192.168.1.1:17070",
+		"54.1.2.3:17070",
+		"example.com:17070",
+	})
+
+	// Addresses that could not be connected to are dialed last.
+	api.RecordEndpointFailure("10.0.0.1:17070")
+	api.RecordEndpointFailure("54.1.2.3:17070")
+	c.Assert(api.DialOrder(addrs), jc.DeepEquals, []string{
+		"192.168.1.1:17070",
+		"example.com:17070",
+		"10.0.0.1:17070",
+		"54.1.2.3:17070",
+	})
+}
+
+func (s *apiclientSuite) TestDialOrderPrefersHealthyLocalhost(c *gc.C) {
+	addrs := []string{"10.0.0.1:17070", "localhost:17070"}
+	c.Assert(api.DialOrder(addrs), jc.DeepEquals, []string{"localhost:17070"})
+
+	api.RecordEndpointFailure("localhost:17070")
+	c.Assert(api.DialOrder(addrs), jc.DeepEquals, []string{"10.0.0.1:17070", "localhost:17070"})
+}
+
+func (s *apiclientSuite) TestOpenFailsOverWhenServerOverloaded(c *gc.C) {
+	// Start an API server that lets each agent log in only once.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(s.State, listener, apiserver.ServerConfig{
+		Cert: []byte(coretesting.ServerCert),
+		Key:  []byte(coretesting.ServerKey),
+		Tag:  names.NewMachineTag("0"),
+		RateLimit: apiserver.RateLimitConfig{
+			LoginAttemptsPerMinute: 1,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+	limitedAddr := listener.Addr().String()
+
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	info := s.APIInfo(c)
+	info.Tag = machine.Tag()
+	info.Password = password
+	info.Nonce = "fake_nonce"
+	info.Addrs = []string{limitedAddr}
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+
+	// The second login to the limited server is rejected, so the
+	// other server is used.
+	_, port, err := net.SplitHostPort(s.APIInfo(c).Addrs[0])
+	c.Assert(err, jc.ErrorIsNil)
+	otherAddr := net.JoinHostPort("127.0.0.1", port)
+	info.Addrs = []string{limitedAddr, otherAddr}
+	st, err = api.Open(info, api.DialOpts{DialAddressInterval: coretesting.LongWait})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	c.Assert(st.Addr(), gc.Equals, otherAddr)
+
+	// The limited server is now dialed last.
+	c.Assert(api.DialOrder(info.Addrs), jc.DeepEquals, []string{otherAddr, limitedAddr})
+}
+
 func (s *apiclientSuite) TestOpen(c *gc.C) {
 	info := s.APIInfo(c)
 	st, err := api.Open(info, api.DialOpts{})
@@ -188,6 +273,27 @@ func (s *apiclientSuite) TestOpen(c *gc.C) {
 	c.Assert(remoteVersion, gc.Equals, version.Current.Number)
 }
 
+func (s *apiclientSuite) TestLastSuccessfulCall(c *gc.C) {
+	before := time.Now()
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// Logging in is itself a successful call.
+	loggedIn := st.LastSuccessfulCall()
+	c.Assert(loggedIn.Before(before), jc.IsFalse)
+
+	err = st.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st.LastSuccessfulCall().Before(loggedIn), jc.IsFalse)
+
+	// A failed call leaves the time unchanged.
+	pinged := st.LastSuccessfulCall()
+	err = st.APICall("NoSuchFacade", 1, "", "NoSuchMethod", nil, nil)
+	c.Assert(err, gc.NotNil)
+	c.Assert(st.LastSuccessfulCall(), gc.Equals, pinged)
+}
+
 func (s *apiclientSuite) TestOpenHonorsEnvironTag(c *gc.C) {
 	info := s.APIInfo(c)
 
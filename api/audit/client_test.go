@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/audit"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestEntries(c *gc.C) {
+	filter := params.AuditFilter{Caller: "user-admin", Limit: 5}
+	entry := params.AuditEntry{
+		Time:   time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+		Caller: "user-admin",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Audit")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Entries")
+		c.Check(arg, jc.DeepEquals, filter)
+		c.Assert(result, gc.FitsTypeOf, &params.AuditEntriesResult{})
+		*(result.(*params.AuditEntriesResult)) = params.AuditEntriesResult{
+			Entries: []params.AuditEntry{entry},
+		}
+		callCount++
+		return nil
+	})
+
+	entries, err := audit.NewClient(apiCaller).Entries(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callCount, gc.Equals, 1)
+	c.Assert(entries, jc.DeepEquals, []params.AuditEntry{entry})
+}
+
+func (s *auditSuite) TestEntriesError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	_, err := audit.NewClient(apiCaller).Entries(params.AuditFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
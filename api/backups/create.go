@@ -10,10 +10,17 @@ import (
 )
 
 // Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// returns the metadata associated with the resulting backup. An
+// incremental backup holds only the changes since the most recent full
+// backup. If upload is true, the backup archive is also uploaded to
+// the environment provider's object store.
+func (c *Client) Create(notes string, incremental, upload bool) (*params.BackupsMetadataResult, error) {
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:       notes,
+		Incremental: incremental,
+		Upload:      upload,
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -143,6 +143,16 @@ func (c *Client) restore(backupId string, newClient ClientConnection) error {
 	return nil
 }
 
+// RestoreInfo returns the outcome of the most recent restore,
+// including the machines whose agents it could not update.
+func (c *Client) RestoreInfo() (params.RestoreInfoResult, error) {
+	var result params.RestoreInfoResult
+	if err := c.facade.FacadeCall("RestoreInfo", nil, &result); err != nil {
+		return params.RestoreInfoResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
 func finishAttempt(client *Client, closer closerFunc) (error, error) {
 	var remoteError error
 	defer closer()
@@ -6,6 +6,7 @@ package block
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -63,6 +64,43 @@ func (c *Client) SwitchBlockOn(blockType, msg string) error {
 	return nil
 }
 
+// SwitchBlockOnForEntity switches desired block on for the service or
+// machine with the given tag only.
+// Valid block types are "BlockDestroy", "BlockRemove" and "BlockChange".
+func (c *Client) SwitchBlockOnForEntity(blockType string, tag names.Tag, msg string) error {
+	args := params.BlockSwitchParams{
+		Type:    blockType,
+		Message: msg,
+		Tag:     tag.String(),
+	}
+	result := params.ErrorResult{}
+	if err := c.facade.FacadeCall("SwitchBlockOn", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// SwitchBlockOffForEntity switches desired block off for the service or
+// machine with the given tag.
+// Valid block types are "BlockDestroy", "BlockRemove" and "BlockChange".
+func (c *Client) SwitchBlockOffForEntity(blockType string, tag names.Tag) error {
+	args := params.BlockSwitchParams{
+		Type: blockType,
+		Tag:  tag.String(),
+	}
+	result := params.ErrorResult{}
+	if err := c.facade.FacadeCall("SwitchBlockOff", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // SwitchBlockOff switches desired block off for the current environment.
 // Valid block types are "BlockDestroy", "BlockRemove" and "BlockChange".
 func (c *Client) SwitchBlockOff(blockType string) error {
@@ -5,6 +5,7 @@ package block_test
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -54,6 +55,54 @@ func (s *blockMockSuite) TestSwitchBlockOn(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *blockMockSuite) TestSwitchBlockOnForEntity(c *gc.C) {
+	called := false
+	blockType := state.ChangeBlock.String()
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Block")
+			c.Check(request, gc.Equals, "SwitchBlockOn")
+			c.Check(a, gc.DeepEquals, params.BlockSwitchParams{
+				Type:    blockType,
+				Message: "protect mysql",
+				Tag:     "service-mysql",
+			})
+			return nil
+		})
+	blockClient := block.NewClient(apiCaller)
+	err := blockClient.SwitchBlockOnForEntity(blockType, names.NewServiceTag("mysql"), "protect mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *blockMockSuite) TestSwitchBlockOffForEntity(c *gc.C) {
+	called := false
+	blockType := state.ChangeBlock.String()
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(request, gc.Equals, "SwitchBlockOff")
+			c.Check(a, gc.DeepEquals, params.BlockSwitchParams{
+				Type: blockType,
+				Tag:  "machine-0",
+			})
+			return nil
+		})
+	blockClient := block.NewClient(apiCaller)
+	err := blockClient.SwitchBlockOffForEntity(blockType, names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *blockMockSuite) TestSwitchBlockOnError(c *gc.C) {
 	called := false
 	errmsg := "test error"
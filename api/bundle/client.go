@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundle provides access to the bundle API facade, through
+// which charm bundles are deployed.
+package bundle
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the bundle API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the bundle API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Bundle")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// GetChanges returns the changes required to deploy the given
+// YAML-encoded bundle, without making them.
+func (c *Client) GetChanges(bundleYAML string) ([]params.BundleChange, error) {
+	var results params.BundleChangesResults
+	args := params.BundleChangesParams{BundleDataYAML: bundleYAML}
+	if err := c.facade.FacadeCall("GetChanges", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := verificationError(results.Errors); err != nil {
+		return nil, err
+	}
+	return results.Changes, nil
+}
+
+// Deploy deploys the given YAML-encoded bundle, and returns the
+// outcome of each of the changes doing so. The error of the first
+// change that failed, if any, is also returned.
+func (c *Client) Deploy(bundleYAML string) ([]params.BundleStepResult, error) {
+	var results params.BundleDeployResults
+	args := params.BundleChangesParams{BundleDataYAML: bundleYAML}
+	if err := c.facade.FacadeCall("Deploy", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := verificationError(results.Errors); err != nil {
+		return nil, err
+	}
+	for _, step := range results.Steps {
+		if step.Error != nil {
+			return results.Steps, errors.Annotatef(step.Error, "cannot %s", step.Description)
+		}
+	}
+	return results.Steps, nil
+}
+
+// ExportBundle returns the YAML-encoded bundle that recreates the
+// services of the environment, the relations between them and the
+// machines their units are placed on.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.BundleExportResult
+	if err := c.facade.FacadeCall("ExportBundle", nil, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.BundleDataYAML, nil
+}
+
+func verificationError(errs []string) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errors.Errorf("invalid bundle: %s", errs[0])
+	}
+	return errors.Errorf("invalid bundle:\n  %s", strings.Join(errs, "\n  "))
+}
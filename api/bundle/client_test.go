@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&bundleMockSuite{})
+
+var changes = []params.BundleChange{{
+	Id:          "addCharm-0",
+	Method:      "addCharm",
+	Description: "add charm cs:trusty/mysql-42",
+}, {
+	Id:          "deploy-1",
+	Method:      "deploy",
+	Description: "deploy service mysql using cs:trusty/mysql-42",
+	Requires:    []string{"addCharm-0"},
+}}
+
+func (s *bundleMockSuite) TestGetChanges(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Bundle")
+			c.Check(request, gc.Equals, "GetChanges")
+			c.Check(a, jc.DeepEquals, params.BundleChangesParams{BundleDataYAML: "bundle"})
+			result, ok := response.(*params.BundleChangesResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Changes = changes
+			return nil
+		})
+	client := bundle.NewClient(apiCaller)
+	result, err := client.GetChanges("bundle")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, changes)
+}
+
+func (s *bundleMockSuite) TestGetChangesInvalid(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			result := response.(*params.BundleChangesResults)
+			result.Errors = []string{"first", "second"}
+			return nil
+		})
+	client := bundle.NewClient(apiCaller)
+	_, err := client.GetChanges("bundle")
+	c.Assert(err, gc.ErrorMatches, "invalid bundle:\n  first\n  second")
+}
+
+func (s *bundleMockSuite) TestDeploy(c *gc.C) {
+	steps := []params.BundleStepResult{{
+		BundleChange: changes[0],
+		Done:         true,
+		Result:       "cs:trusty/mysql-42",
+	}, {
+		BundleChange: changes[1],
+		Error:        &params.Error{Message: "boom"},
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "Deploy")
+			result, ok := response.(*params.BundleDeployResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Steps = steps
+			return nil
+		})
+	client := bundle.NewClient(apiCaller)
+	result, err := client.Deploy("bundle")
+	c.Assert(err, gc.ErrorMatches, "cannot deploy service mysql using cs:trusty/mysql-42: boom")
+	c.Assert(result, jc.DeepEquals, steps)
+}
+
+func (s *bundleMockSuite) TestExportBundle(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Bundle")
+			c.Check(request, gc.Equals, "ExportBundle")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.BundleExportResult)
+			c.Assert(ok, jc.IsTrue)
+			result.BundleDataYAML = "bundle"
+			return nil
+		})
+	client := bundle.NewClient(apiCaller)
+	result, err := client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, "bundle")
+}
@@ -42,14 +42,30 @@ func (c *Client) CharmInfo(charmURL string) (*CharmInfo, error) {
 	return info, nil
 }
 
+// listPageSize is the number of charm URLs requested at a time when
+// listing charms.
+var listPageSize = 500
+
 // List returns a list of charm URLs currently in the state.
 // If supplied parameter contains any names, the result will be filtered
 // to return only the charms with supplied names.
 func (c *Client) List(names []string) ([]string, error) {
-	charms := &params.CharmsListResult{}
-	args := params.CharmsList{Names: names}
-	if err := c.facade.FacadeCall("List", args, charms); err != nil {
-		return nil, errors.Trace(err)
+	var charmURLs []string
+	args := params.CharmsList{
+		Names:    names,
+		ListPage: params.ListPage{Limit: listPageSize},
+	}
+	for {
+		charms := &params.CharmsListResult{}
+		if err := c.facade.FacadeCall("List", args, charms); err != nil {
+			return nil, errors.Trace(err)
+		}
+		charmURLs = append(charmURLs, charms.CharmURLs...)
+		// API servers that do not support paging return all the
+		// charm URLs, and no continuation token.
+		if charms.Continuation == "" {
+			return charmURLs, nil
+		}
+		args.Continuation = charms.Continuation
 	}
-	return charms.CharmURLs, nil
 }
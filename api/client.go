@@ -4,6 +4,7 @@
 package api
 
 import (
+	"archive/zip"
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
@@ -24,6 +25,7 @@ import (
 	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/charmdelta"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
@@ -82,6 +84,10 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// AgentHealth holds the health most recently reported by the
+	// machine agent, if any.
+	AgentHealth *params.AgentHealth
 }
 
 // ServiceStatus holds status info about a service.
@@ -123,6 +129,10 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// AgentHealth holds the health most recently reported by the
+	// unit agent, if any.
+	AgentHealth *params.AgentHealth
 }
 
 // RelationStatus holds status info about a relation.
@@ -173,6 +183,17 @@ func (c *Client) Status(patterns []string) (*Status, error) {
 	return &result, nil
 }
 
+// StatusHistory returns up to size of the most recent statuses set on
+// the given machine or unit, most recent first.
+func (c *Client) StatusHistory(tag names.Tag, size int) ([]params.StatusHistoryEntry, error) {
+	var results params.StatusHistoryResults
+	args := params.StatusHistory{Tag: tag.String(), Size: size}
+	if err := c.facade.FacadeCall("StatusHistory", args, &results); err != nil {
+		return nil, err
+	}
+	return results.Statuses, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -204,6 +225,19 @@ func (c *Client) ServiceSet(service string, options map[string]string) error {
 	return c.facade.FacadeCall("NewServiceSetForClientAPI", p, nil)
 }
 
+// ServiceSetAtRevision sets configuration options on a service, as
+// ServiceSet does, but only if the service's configuration is still at
+// the given revision, as returned by ServiceGet. If it is not, an error
+// satisfying params.IsCodeConflict is returned.
+func (c *Client) ServiceSetAtRevision(service string, options map[string]string, revision int64) error {
+	p := params.ServiceSet{
+		ServiceName:    service,
+		Options:        options,
+		ConfigRevision: &revision,
+	}
+	return c.facade.FacadeCall("NewServiceSetForClientAPI", p, nil)
+}
+
 // ServiceUnset resets configuration options on a service.
 func (c *Client) ServiceUnset(service string, options []string) error {
 	p := params.ServiceUnset{
@@ -222,13 +256,13 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
-// RetryProvisioning updates the provisioning status of a machine allowing the
-// provisioner to retry.
-func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
+// RetryProvisioning retries the failed provisioning of the given
+// machines, units, volumes and filesystems.
+func (c *Client) RetryProvisioning(entities ...names.Tag) ([]params.ErrorResult, error) {
 	p := params.Entities{}
-	p.Entities = make([]params.Entity, len(machines))
-	for i, machine := range machines {
-		p.Entities[i] = params.Entity{Tag: machine.String()}
+	p.Entities = make([]params.Entity, len(entities))
+	for i, entity := range entities {
+		p.Entities[i] = params.Entity{Tag: entity.String()}
 	}
 	var results params.ErrorResults
 	err := c.facade.FacadeCall("RetryProvisioning", p, &results)
@@ -263,6 +297,34 @@ func (c *Client) ServiceSetYAML(service string, yaml string) error {
 	return c.facade.FacadeCall("ServiceSetYAML", p, nil)
 }
 
+// ValidateServiceSetConfig checks the given configuration options
+// against the charm of the service, without setting them. It returns
+// a message for each invalid option, keyed by option name.
+func (c *Client) ValidateServiceSetConfig(service string, options map[string]string) (map[string]string, error) {
+	return c.validateServiceSetConfig(params.ValidateServiceSetConfig{
+		ServiceName: service,
+		Options:     options,
+	})
+}
+
+// ValidateServiceSetConfigYAML is like ValidateServiceSetConfig, but
+// takes the configuration options in YAML format, as ServiceSetYAML
+// does.
+func (c *Client) ValidateServiceSetConfigYAML(service string, yaml string) (map[string]string, error) {
+	return c.validateServiceSetConfig(params.ValidateServiceSetConfig{
+		ServiceName: service,
+		ConfigYAML:  yaml,
+	})
+}
+
+func (c *Client) validateServiceSetConfig(p params.ValidateServiceSetConfig) (map[string]string, error) {
+	var result params.ServiceConfigValidationResult
+	if err := c.facade.FacadeCall("ValidateServiceSetConfig", p, &result); err != nil {
+		return nil, err
+	}
+	return result.Errors, nil
+}
+
 // ServiceGet returns the configuration for the named service.
 func (c *Client) ServiceGet(service string) (*params.ServiceGetResults, error) {
 	var results params.ServiceGetResults
@@ -273,18 +335,37 @@ func (c *Client) ServiceGet(service string) (*params.ServiceGetResults, error) {
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, error) {
+	return c.AddRelationWithSelector(nil, endpoints...)
+}
+
+// AddRelationWithSelector adds a container scoped relation between the
+// specified endpoints, which only attaches subordinates to the principal
+// units carrying all the annotations in selector.
+func (c *Client) AddRelationWithSelector(selector map[string]string, endpoints ...string) (*params.AddRelationResults, error) {
 	var addRelRes params.AddRelationResults
-	params := params.AddRelation{Endpoints: endpoints}
+	params := params.AddRelation{
+		Endpoints:           endpoints,
+		SubordinateSelector: selector,
+	}
 	err := c.facade.FacadeCall("AddRelation", params, &addRelRes)
 	return &addRelRes, err
 }
 
 // DestroyRelation removes the relation between the specified endpoints.
+// The relation drains for the environment's relation-drain-timeout
+// before it is broken.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
 	return c.facade.FacadeCall("DestroyRelation", params, nil)
 }
 
+// ForceDestroyRelation removes the relation between the specified
+// endpoints, breaking it without waiting for it to drain.
+func (c *Client) ForceDestroyRelation(endpoints ...string) error {
+	params := params.DestroyRelation{Endpoints: endpoints, Force: true}
+	return c.facade.FacadeCall("DestroyRelation", params, nil)
+}
+
 // ServiceCharmRelations returns the service's charms relation names.
 func (c *Client) ServiceCharmRelations(service string) ([]string, error) {
 	var results params.ServiceCharmRelationsResults
@@ -340,6 +421,21 @@ func (c *Client) ForceDestroyMachines(machines ...string) error {
 	return c.facade.FacadeCall("DestroyMachines", params, nil)
 }
 
+// UpgradeSeriesPrepare starts upgrading the series of the given
+// machine: its units run their pre-series-upgrade hooks and then pause
+// until UpgradeSeriesComplete is called.
+func (c *Client) UpgradeSeriesPrepare(machine, series string) error {
+	params := params.UpgradeSeriesPrepare{MachineId: machine, Series: series}
+	return c.facade.FacadeCall("UpgradeSeriesPrepare", params, nil)
+}
+
+// UpgradeSeriesComplete resumes the units of the given machine once
+// its operating system has been upgraded.
+func (c *Client) UpgradeSeriesComplete(machine string) error {
+	params := params.UpgradeSeriesComplete{MachineId: machine}
+	return c.facade.FacadeCall("UpgradeSeriesComplete", params, nil)
+}
+
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceExpose(service string) error {
@@ -381,6 +477,13 @@ func (c *Client) ServiceDeployWithNetworks(
 	return c.facade.FacadeCall("ServiceDeployWithNetworks", params, nil)
 }
 
+// ServiceDeployWithBindings works exactly like ServiceDeployWithNetworks,
+// but takes all its arguments, including the space the service's
+// endpoints are bound to, in one struct.
+func (c *Client) ServiceDeployWithBindings(args params.ServiceDeploy) error {
+	return c.facade.FacadeCall("ServiceDeployWithNetworks", args, nil)
+}
+
 // ServiceDeploy obtains the charm, either locally or from the charm store,
 // and deploys it.
 func (c *Client) ServiceDeploy(charmURL string, serviceName string, numUnits int, configYAML string, cons constraints.Value, toMachineSpec string) error {
@@ -660,6 +763,33 @@ func (c *Client) EnvironmentUnset(keys ...string) error {
 	return c.facade.FacadeCall("EnvironmentUnset", args, nil)
 }
 
+// StageEnvironmentConfig validates the given changes to the environment
+// config, and returns the changes they would make without applying
+// them. The changes are applied by passing the returned revision to
+// ApplyEnvironmentConfig.
+func (c *Client) StageEnvironmentConfig(config map[string]interface{}, unset []string) (params.StagedEnvironmentConfig, error) {
+	args := params.EnvironmentConfigChanges{
+		Config: config,
+		Unset:  unset,
+	}
+	var result params.StagedEnvironmentConfig
+	err := c.facade.FacadeCall("StageEnvironmentConfig", args, &result)
+	return result, err
+}
+
+// ApplyEnvironmentConfig applies the given changes to the environment
+// config, staged by StageEnvironmentConfig, but only if the config is
+// still at the given revision. If it is not, an error satisfying
+// params.IsCodeConflict is returned.
+func (c *Client) ApplyEnvironmentConfig(config map[string]interface{}, unset []string, revision int64) error {
+	args := params.EnvironmentConfigChanges{
+		Config:   config,
+		Unset:    unset,
+		Revision: revision,
+	}
+	return c.facade.FacadeCall("ApplyEnvironmentConfig", args, nil)
+}
+
 // SetEnvironAgentVersion sets the environment agent-version setting
 // to the given value.
 func (c *Client) SetEnvironAgentVersion(version version.Number) error {
@@ -667,6 +797,28 @@ func (c *Client) SetEnvironAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
 }
 
+// SetEnvironAgentVersionWithCanaries sets the environment
+// agent-version setting to the given value, but only the state servers
+// and the given canary machines upgrade until ResumeUpgrade is called.
+func (c *Client) SetEnvironAgentVersionWithCanaries(version version.Number, canaries []string) error {
+	args := params.SetEnvironAgentVersion{Version: version, CanaryMachines: canaries}
+	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
+}
+
+// ResumeUpgrade lets all agents upgrade once the canary machines of
+// the current upgrade have.
+func (c *Client) ResumeUpgrade() error {
+	return c.facade.FacadeCall("ResumeUpgrade", nil, nil)
+}
+
+// UpgradeStatus reports the progress of the environment's agents
+// towards the environment's agent version.
+func (c *Client) UpgradeStatus() (params.UpgradeStatusResult, error) {
+	var result params.UpgradeStatusResult
+	err := c.facade.FacadeCall("UpgradeStatus", nil, &result)
+	return result, err
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -705,6 +857,51 @@ func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 	return results.Results, err
 }
 
+// RunWatcher reports the results of commands started by RunStreamed.
+type RunWatcher interface {
+	// Next blocks until more of the commands have completed, and
+	// returns their results. Done is set in the result once every
+	// result has been reported.
+	Next() (params.RunWatcherNextResult, error)
+
+	// Stop stops the watcher.
+	Stop() error
+}
+
+// RunStreamed runs the Commands on the same targets as Run, returning a
+// RunWatcher that reports the result for each target as it completes.
+func (c *Client) RunStreamed(run params.RunParams) (RunWatcher, error) {
+	var result params.RunWatcherId
+	if err := c.facade.FacadeCall("RunStreamed", run, &result); err != nil {
+		return nil, err
+	}
+	return &runWatcher{
+		caller: c.facade.RawAPICaller(),
+		id:     result.RunWatcherId,
+	}, nil
+}
+
+type runWatcher struct {
+	caller base.APICaller
+	id     string
+}
+
+// Next is part of the RunWatcher interface.
+func (w *runWatcher) Next() (params.RunWatcherNextResult, error) {
+	var result params.RunWatcherNextResult
+	err := w.caller.APICall(
+		"RunWatcher", w.caller.BestFacadeVersion("RunWatcher"),
+		w.id, "Next", nil, &result)
+	return result, err
+}
+
+// Stop is part of the RunWatcher interface.
+func (w *runWatcher) Stop() error {
+	return w.caller.APICall(
+		"RunWatcher", w.caller.BestFacadeVersion("RunWatcher"),
+		w.id, "Stop", nil, nil)
+}
+
 // DestroyEnvironment puts the environment into a "dying" state,
 // and removes all non-manager machine instances. DestroyEnvironment
 // will fail if there are any manually-provisioned non-manager machines
@@ -718,6 +915,22 @@ func (c *Client) DestroyEnvironment() error {
 // charm URL. If the API server does not support charm uploads, an
 // error satisfying params.IsCodeNotImplemented() is returned.
 func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, error) {
+	return c.addLocalCharm(curl, ch, nil)
+}
+
+// AddLocalCharmDelta is like AddLocalCharm, but it uploads only the
+// files of the charm that differ from the local charm with the given
+// URL, which must already be in the environment, such as the charm a
+// service is being upgraded from. If the API server does not support
+// such uploads, the whole charm is uploaded.
+func (c *Client) AddLocalCharmDelta(curl *charm.URL, ch charm.Charm, base *charm.URL) (*charm.URL, error) {
+	if base.Schema != "local" {
+		return nil, errors.Errorf("expected base charm URL with local: schema, got %q", base.String())
+	}
+	return c.addLocalCharm(curl, ch, base)
+}
+
+func (c *Client) addLocalCharm(curl *charm.URL, ch charm.Charm, base *charm.URL) (*charm.URL, error) {
 	if curl.Schema != "local" {
 		return nil, errors.Errorf("expected charm URL with local: schema, got %q", curl.String())
 	}
@@ -747,17 +960,104 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 		return nil, errors.Errorf("unknown charm type %T", ch)
 	}
 
+	uploadURL := fmt.Sprintf("%s/charms?series=%s", c.st.serverRoot, curl.Series)
+	if base != nil {
+		delta, err := c.charmDelta(archive, base)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if delta != nil {
+			defer os.Remove(delta.Name())
+			defer delta.Close()
+			archive = delta
+			uploadURL += "&delta-from=" + url.QueryEscape(base.String())
+		}
+	}
+	return c.uploadCharm(uploadURL, archive)
+}
+
+// charmDelta writes the files of the given charm archive that differ
+// from the charm with the given URL to a temporary delta archive, and
+// returns it rewound. It returns a nil file if the API server does not
+// report the digests of the files of the charm, in which case the whole
+// charm must be uploaded.
+func (c *Client) charmDelta(archive *os.File, base *charm.URL) (*os.File, error) {
+	baseDigests, err := c.charmDigests(base)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get digests of base charm")
+	}
+	if len(baseDigests) == 0 {
+		logger.Debugf("API server does not support charm delta uploads")
+		return nil, nil
+	}
+	info, err := archive.Stat()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zipr, err := zip.NewReader(archive, info.Size())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm archive")
+	}
+	delta, err := ioutil.TempFile("", "charm-delta")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create temp file")
+	}
+	if err := charmdelta.Write(delta, zipr, baseDigests); err != nil {
+		cleanupFile(delta)
+		return nil, errors.Annotate(err, "cannot write charm delta")
+	}
+	if _, err := delta.Seek(0, 0); err != nil {
+		cleanupFile(delta)
+		return nil, errors.Annotate(err, "cannot rewind charm delta")
+	}
+	return delta, nil
+}
+
+// cleanupFile closes and removes the given file.
+func cleanupFile(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// charmDigests returns the digests of the files of the charm with the
+// given URL, as reported by the API server.
+func (c *Client) charmDigests(curl *charm.URL) (map[string]string, error) {
+	digestsURL := fmt.Sprintf("%s/charms?url=%s&digests=1", c.st.serverRoot, url.QueryEscape(curl.String()))
+	req, err := http.NewRequest("GET", digestsURL, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create digests request")
+	}
+	req.SetBasicAuth(c.st.tag, c.st.password)
+	jsonResponse, err := c.sendCharmsRequest(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return jsonResponse.Digests, nil
+}
+
+// uploadCharm posts the given charm archive to the charms endpoint URL
+// and returns the URL of the charm added to the environment.
+func (c *Client) uploadCharm(uploadURL string, archive io.Reader) (*charm.URL, error) {
 	// Prepare the upload request.
-	url := fmt.Sprintf("%s/charms?series=%s", c.st.serverRoot, curl.Series)
-	req, err := http.NewRequest("POST", url, archive)
+	req, err := http.NewRequest("POST", uploadURL, archive)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create upload request")
 	}
 	req.SetBasicAuth(c.st.tag, c.st.password)
 	req.Header.Set("Content-Type", "application/zip")
+	jsonResponse, err := c.sendCharmsRequest(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "charm upload failed")
+	}
+	if jsonResponse.Error != "" {
+		return nil, errors.Errorf("error uploading charm: %v", jsonResponse.Error)
+	}
+	return charm.MustParseURL(jsonResponse.CharmURL), nil
+}
 
-	// Send the request.
-
+// sendCharmsRequest sends the given request to the charms endpoint and
+// returns its decoded response.
+func (c *Client) sendCharmsRequest(req *http.Request) (*params.CharmsResponse, error) {
 	// BUG(dimitern) 2013-12-17 bug #1261780
 	// Due to issues with go 1.1.2, fixed later, we cannot use a
 	// regular TLS client with the CACert here, because we get "x509:
@@ -768,27 +1068,24 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 	// the tag and password) passed in api.Open()'s info argument.
 	resp, err := utils.GetNonValidatingHTTPClient().Do(req)
 	if err != nil {
-		return nil, errors.Annotate(err, "cannot upload charm")
+		return nil, errors.Annotate(err, "cannot send request")
 	}
 	defer resp.Body.Close()
 
 	// Now parse the response & return.
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.Annotate(err, "cannot read charm upload response")
+		return nil, errors.Annotate(err, "cannot read response")
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("charm upload failed: %v (%s)", resp.StatusCode, bytes.TrimSpace(body))
+		return nil, errors.Errorf("%v (%s)", resp.StatusCode, bytes.TrimSpace(body))
 	}
 
 	var jsonResponse params.CharmsResponse
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, errors.Annotate(err, "cannot unmarshal upload response")
+		return nil, errors.Annotate(err, "cannot unmarshal response")
 	}
-	if jsonResponse.Error != "" {
-		return nil, errors.Errorf("error uploading charm: %v", jsonResponse.Error)
-	}
-	return charm.MustParseURL(jsonResponse.CharmURL), nil
+	return &jsonResponse, nil
 }
 
 // AddCharm adds the given charm URL (which must include revision) to
@@ -796,7 +1093,14 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 // supported, only charm store URLs. See also AddLocalCharm() in the
 // client-side API.
 func (c *Client) AddCharm(curl *charm.URL) error {
-	args := params.CharmURL{URL: curl.String()}
+	args := params.AddCharm{URL: curl.String()}
+	return c.facade.FacadeCall("AddCharm", args, nil)
+}
+
+// AddCharmWithChannel is like AddCharm, but downloads the charm from
+// the given charm store channel rather than the stable channel.
+func (c *Client) AddCharmWithChannel(curl *charm.URL, channel string) error {
+	args := params.AddCharm{URL: curl.String(), Channel: channel}
 	return c.facade.FacadeCall("AddCharm", args, nil)
 }
 
@@ -13,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -78,6 +79,38 @@ func (s *clientSuite) TestAddLocalCharm(c *gc.C) {
 	c.Assert(savedURL.String(), gc.Equals, curl.WithRevision(43).String())
 }
 
+func (s *clientSuite) TestAddLocalCharmDelta(c *gc.C) {
+	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL(
+		fmt.Sprintf("local:quantal/%s-%d", charmArchive.Meta().Name, charmArchive.Revision()),
+	)
+	client := s.APIState.Client()
+
+	_, err := client.AddLocalCharmDelta(curl, charmArchive, charm.MustParseURL("cs:quantal/dummy-1"))
+	c.Assert(err, gc.ErrorMatches, `expected base charm URL with local: schema, got "cs:quantal/dummy-1"`)
+
+	baseURL, err := client.AddLocalCharm(curl, charmArchive)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Upload a changed charm directory as a delta from the first.
+	charmDir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
+	charmDir.SetDiskRevision(42)
+	err = ioutil.WriteFile(filepath.Join(charmDir.Path, "utils.js"), []byte("// changed"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	savedURL, err := client.AddLocalCharmDelta(curl, charmDir, baseURL)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(savedURL.String(), gc.Equals, curl.WithRevision(42).String())
+
+	sch, err := s.State.Charm(savedURL)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sch.Revision(), gc.Equals, 42)
+	c.Assert(sch.IsUploaded(), jc.IsTrue)
+
+	// The base charm must be in the environment.
+	_, err = client.AddLocalCharmDelta(curl, charmDir, curl.WithRevision(99))
+	c.Assert(err, gc.ErrorMatches, `cannot get digests of base charm: 404 .*`)
+}
+
 func (s *clientSuite) TestAddLocalCharmError(c *gc.C) {
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
 	c.Assert(err, jc.ErrorIsNil)
@@ -510,6 +543,30 @@ func (s *clientSuite) TestEnvironmentUnset(c *gc.C) {
 	c.Assert(found, jc.IsFalse)
 }
 
+func (s *clientSuite) TestStageAndApplyEnvironmentConfig(c *gc.C) {
+	client := s.APIState.Client()
+	config := map[string]interface{}{"some-name": "value"}
+	staged, err := client.StageEnvironmentConfig(config, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(staged.Changes, jc.DeepEquals, []params.EnvironmentConfigChange{
+		{Key: "some-name", NewValue: "value"},
+	})
+	env, err := client.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	_, found := env["some-name"]
+	c.Assert(found, jc.IsFalse)
+
+	err = client.ApplyEnvironmentConfig(config, nil, staged.Revision)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err = client.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env["some-name"], gc.Equals, "value")
+
+	// The staged revision is now out of date.
+	err = client.ApplyEnvironmentConfig(config, nil, staged.Revision)
+	c.Assert(err, jc.Satisfies, params.IsCodeConflict)
+}
+
 // badReader raises err when Read is called.
 type badReader struct {
 	err error
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the cross model API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the cross model API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "CrossModel")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Offer offers the given endpoints of a service, under the given name,
+// for use by other environments. If no endpoints are given, all those
+// that can be used across environments are offered.
+func (c *Client) Offer(offerName, serviceName string, endpoints []string, description string) error {
+	args := params.ServiceOffers{Offers: []params.ServiceOffer{{
+		OfferName:   offerName,
+		ServiceName: serviceName,
+		Endpoints:   endpoints,
+		Description: description,
+	}}}
+	return c.oneError("Offer", args)
+}
+
+// ListOffers returns the offers made by the current environment.
+func (c *Client) ListOffers() ([]params.ServiceOffer, error) {
+	var result params.ServiceOffers
+	if err := c.facade.FacadeCall("ListOffers", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Offers, nil
+}
+
+// RemoveOffer withdraws the named offer.
+func (c *Client) RemoveOffer(offerName string) error {
+	return c.oneError("RemoveOffers", params.OfferNames{Names: []string{offerName}})
+}
+
+// Consume adds a remote service to the current environment for the
+// named offer made by another environment. If serviceName is empty, the
+// remote service takes the name of the offer.
+func (c *Client) Consume(envTag names.EnvironTag, offerName, serviceName string) error {
+	args := params.ConsumeOffers{Offers: []params.ConsumeOffer{{
+		EnvironTag:  envTag.String(),
+		OfferName:   offerName,
+		ServiceName: serviceName,
+	}}}
+	return c.oneError("Consume", args)
+}
+
+// EnterScope enters a unit of a remote service into the scope of the
+// given relation with the supplied settings, or replaces its settings
+// if it is already in scope.
+func (c *Client) EnterScope(relationTag names.RelationTag, unitTag names.UnitTag, settings map[string]interface{}) error {
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{{
+		RelationTag: relationTag.String(),
+		Unit:        unitTag.String(),
+		Settings:    settings,
+	}}}
+	return c.oneError("EnterScope", args)
+}
+
+// LeaveScope removes a unit of a remote service from the scope of the
+// given relation.
+func (c *Client) LeaveScope(relationTag names.RelationTag, unitTag names.UnitTag) error {
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{{
+		RelationTag: relationTag.String(),
+		Unit:        unitTag.String(),
+	}}}
+	return c.oneError("LeaveScope", args)
+}
+
+func (c *Client) oneError(request string, args interface{}) error {
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(request, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/crossmodel"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type crossmodelMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&crossmodelMockSuite{})
+
+func (s *crossmodelMockSuite) TestOffer(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "CrossModel")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Offer")
+			c.Check(a, jc.DeepEquals, params.ServiceOffers{Offers: []params.ServiceOffer{{
+				OfferName:   "hosted-mysql",
+				ServiceName: "mysql",
+				Endpoints:   []string{"server"},
+				Description: "a database",
+			}}})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{}}
+			return nil
+		})
+	client := crossmodel.NewClient(apiCaller)
+	err := client.Offer("hosted-mysql", "mysql", []string{"server"}, "a database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *crossmodelMockSuite) TestListOffers(c *gc.C) {
+	offers := []params.ServiceOffer{{
+		OfferName:   "hosted-mysql",
+		ServiceName: "mysql",
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "ListOffers")
+			result, ok := response.(*params.ServiceOffers)
+			c.Assert(ok, jc.IsTrue)
+			result.Offers = offers
+			return nil
+		})
+	client := crossmodel.NewClient(apiCaller)
+	result, err := client.ListOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, offers)
+}
+
+func (s *crossmodelMockSuite) TestConsumeError(c *gc.C) {
+	envTag := names.NewEnvironTag("6a6d4eb8-3be4-4cd5-8a5c-26e96b4cb3b1")
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "Consume")
+			c.Check(a, jc.DeepEquals, params.ConsumeOffers{Offers: []params.ConsumeOffer{{
+				EnvironTag: envTag.String(),
+				OfferName:  "hosted-mysql",
+			}}})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{
+				Error: common.ServerError(errors.New("test error")),
+			}}
+			return nil
+		})
+	client := crossmodel.NewClient(apiCaller)
+	err := client.Consume(envTag, "hosted-mysql", "")
+	c.Assert(err, gc.ErrorMatches, "test error")
+}
+
+func (s *crossmodelMockSuite) TestEnterScope(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "EnterScope")
+			c.Check(a, jc.DeepEquals, params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{{
+				RelationTag: "relation-wordpress.db#mysql.server",
+				Unit:        "unit-mysql-0",
+				Settings:    map[string]interface{}{"user": "admin"},
+			}}})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{}}
+			return nil
+		})
+	client := crossmodel.NewClient(apiCaller)
+	err := client.EnterScope(
+		names.NewRelationTag("wordpress:db mysql:server"),
+		names.NewUnitTag("mysql/0"),
+		map[string]interface{}{"user": "admin"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
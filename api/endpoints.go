@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/rpc"
+)
+
+// unhealthyEndpointPeriod is how long an API address that could not be
+// connected to is dialed only after the other addresses.
+var unhealthyEndpointPeriod = time.Minute
+
+// endpointHealth records the API addresses that recently could not be
+// connected to.
+type endpointHealth struct {
+	mu       sync.Mutex
+	failures map[string]time.Time
+}
+
+var apiEndpointHealth = &endpointHealth{
+	failures: make(map[string]time.Time),
+}
+
+func (h *endpointHealth) recordFailure(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures[addr] = time.Now()
+}
+
+func (h *endpointHealth) recordSuccess(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.failures, addr)
+}
+
+// healthy reports whether no attempt to connect to the address has
+// failed recently.
+func (h *endpointHealth) healthy(addr string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	failed, ok := h.failures[addr]
+	if !ok {
+		return true
+	}
+	if time.Since(failed) >= unhealthyEndpointPeriod {
+		delete(h.failures, addr)
+		return true
+	}
+	return false
+}
+
+// dialOrder returns the API addresses in the order they should be
+// dialed. When a healthy localhost address is present, only that one
+// is used, so that an agent running on a state server talks to its
+// own API server. Otherwise, addresses that recently could not be
+// connected to are dialed last and, among the others, cloud-local
+// addresses are dialed first; the given order, in which callers list
+// the most recently successful addresses first, is otherwise kept.
+func dialOrder(addrs []string) []string {
+	for _, addr := range addrs {
+		if strings.HasPrefix(addr, "localhost:") && apiEndpointHealth.healthy(addr) {
+			return []string{addr}
+		}
+	}
+	ranked := &rankedAddrs{
+		addrs: make([]string, len(addrs)),
+		ranks: make([]int, len(addrs)),
+	}
+	for i, addr := range addrs {
+		ranked.addrs[i] = addr
+		if !apiEndpointHealth.healthy(addr) {
+			ranked.ranks[i] += 2
+		}
+		if !isCloudLocal(addr) {
+			ranked.ranks[i]++
+		}
+	}
+	sort.Stable(ranked)
+	return ranked.addrs
+}
+
+// isCloudLocal reports whether the host of the given address is a
+// cloud-local IP address.
+func isCloudLocal(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	return network.NewAddress(host).Scope == network.ScopeCloudLocal
+}
+
+type rankedAddrs struct {
+	addrs []string
+	ranks []int
+}
+
+func (r *rankedAddrs) Len() int {
+	return len(r.addrs)
+}
+
+func (r *rankedAddrs) Less(i, j int) bool {
+	return r.ranks[i] < r.ranks[j]
+}
+
+func (r *rankedAddrs) Swap(i, j int) {
+	r.addrs[i], r.addrs[j] = r.addrs[j], r.addrs[i]
+	r.ranks[i], r.ranks[j] = r.ranks[j], r.ranks[i]
+}
+
+// isUnhealthyServerError reports whether the error, returned when
+// logging in to an API server, means that the server is overloaded or
+// going away, so that another API server should be tried.
+func isUnhealthyServerError(err error) bool {
+	switch errors.Cause(err) {
+	case rpc.ErrShutdown, io.EOF, io.ErrUnexpectedEOF:
+		return true
+	}
+	return params.IsCodeTryAgain(err)
+}
+
+// removeAddr returns addrs without the given address.
+func removeAddr(addrs []string, addr string) []string {
+	var result []string
+	for _, a := range addrs {
+		if a != addr {
+			result = append(result, a)
+		}
+	}
+	return result
+}
@@ -4,6 +4,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/network"
 )
@@ -16,8 +18,23 @@ var (
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	NewHTTPClient         = &newHTTPClient
+	DialOrder             = dialOrder
 )
 
+// RecordEndpointFailure records that the given API address could not
+// be connected to.
+func RecordEndpointFailure(addr string) {
+	apiEndpointHealth.recordFailure(addr)
+}
+
+// ResetEndpointHealth forgets all the API addresses that could not be
+// connected to.
+func ResetEndpointHealth() {
+	apiEndpointHealth.mu.Lock()
+	defer apiEndpointHealth.mu.Unlock()
+	apiEndpointHealth.failures = make(map[string]time.Time)
+}
+
 // SetServerRoot allows changing the URL to the internal API server
 // that AddLocalCharm uses in order to test NotImplementedError.
 func SetServerRoot(c *Client, root string) {
@@ -12,23 +12,31 @@ package api
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
 	"Action":                       0,
+	"ActionOutputWatcher":          0,
 	"Agent":                        1,
+	"AgentHealth":                  1,
 	"AllWatcher":                   0,
 	"Annotations":                  1,
+	"Audit":                        1,
 	"Backups":                      0,
 	"Block":                        1,
+	"Bundle":                       1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
 	"Client":                       0,
+	"CrossModel":                   1,
 	"Deployer":                     0,
 	"DiskFormatter":                1,
 	"DiskManager":                  1,
 	"Environment":                  0,
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
+	"Find":                         1,
 	"Firewaller":                   1,
 	"HighAvailability":             1,
 	"ImageManager":                 1,
+	"ImageMetadata":                1,
+	"InstanceConsole":              1,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
 	"LeadershipService":            1,
@@ -42,15 +50,20 @@ var facadeVersions = map[string]int{
 	"Provisioner":                  0,
 	"Reboot":                       1,
 	"RelationUnitsWatcher":         0,
-	"Rsyslog":                      0,
+	"Resources":                    1,
+	"RunWatcher":                   0,
+	"ScopedCredentials":            1,
 	"Service":                      1,
+	"Spaces":                       1,
 	"Storage":                      1,
 	"StorageProvisioner":           1,
 	"StringsWatcher":               0,
+	"ToolsManager":                 1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
-	"UserManager":                  0,
+	"Uniter":                       3,
+	"UserManager":                  1,
 	"VolumeAttachmentsWatcher":     1,
+	"WatcherMultiplexer":           1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
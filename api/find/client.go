@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package find provides access to the API facade that selects entities
+// across the environment by their annotations.
+package find
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Find API facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new find client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Find")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Find returns the tags of the environment, machines, services and
+// units carrying all the given annotations, sorted by tag.
+func (c *Client) Find(annotations map[string]string) ([]string, error) {
+	var result params.FindResults
+	args := params.FindArgs{Annotations: annotations}
+	if err := c.facade.FacadeCall("Find", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tags := make([]string, len(result.Entities))
+	for i, entity := range result.Entities {
+		tags[i] = entity.Tag
+	}
+	return tags, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package find_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/find"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type findSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&findSuite{})
+
+func (s *findSuite) TestFind(c *gc.C) {
+	query := map[string]string{"tag": "production"}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Find")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Find")
+		c.Check(arg, jc.DeepEquals, params.FindArgs{Annotations: query})
+		c.Assert(result, gc.FitsTypeOf, &params.FindResults{})
+		*(result.(*params.FindResults)) = params.FindResults{
+			Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "service-mysql"}},
+		}
+		callCount++
+		return nil
+	})
+
+	tags, err := find.NewClient(apiCaller).Find(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callCount, gc.Equals, 1)
+	c.Assert(tags, jc.DeepEquals, []string{"machine-0", "service-mysql"})
+}
+
+func (s *findSuite) TestFindError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	_, err := find.NewClient(apiCaller).Find(nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
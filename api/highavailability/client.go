@@ -33,8 +33,10 @@ func NewClient(caller base.APICallCloser) *Client {
 }
 
 // EnsureAvailability ensures the availability of Juju state servers.
+// If autoReplace is true, state servers that later become unavailable
+// are replaced automatically.
 func (c *Client) EnsureAvailability(
-	numStateServers int, cons constraints.Value, series string, placement []string,
+	numStateServers int, cons constraints.Value, series string, placement []string, autoReplace bool,
 ) (params.StateServersChanges, error) {
 
 	var results params.StateServersChangeResults
@@ -45,6 +47,7 @@ func (c *Client) EnsureAvailability(
 			Constraints:     cons,
 			Series:          series,
 			Placement:       placement,
+			AutoReplace:     autoReplace,
 		}}}
 
 	var err error
@@ -53,6 +56,9 @@ func (c *Client) EnsureAvailability(
 		if len(placement) > 0 {
 			return params.StateServersChanges{}, errors.Errorf("placement directives not supported with this version of Juju")
 		}
+		if autoReplace {
+			return params.StateServersChanges{}, errors.Errorf("automatic replacement not supported with this version of Juju")
+		}
 		caller := c.facade.RawAPICaller()
 		err = caller.APICall("Client", caller.BestFacadeVersion("Client"), "", "EnsureAvailability", arg, &results)
 	} else {
@@ -71,3 +77,68 @@ func (c *Client) EnsureAvailability(
 	}
 	return result.Result, nil
 }
+
+// RotateMongoCertificates starts a rolling rotation of the certificates
+// served by the state servers' mongo instances, issued by a new CA if
+// rotateCA is true.
+func (c *Client) RotateMongoCertificates(rotateCA bool) (params.CertRotationResult, error) {
+	var result params.CertRotationResult
+	arg := params.RotateCertificates{RotateCA: rotateCA}
+	if err := c.facade.FacadeCall("RotateMongoCertificates", arg, &result); err != nil {
+		return params.CertRotationResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ReplicaSetStatus returns the state, health, votes and replication lag
+// of each member of the state servers' mongo replica set.
+func (c *Client) ReplicaSetStatus() (params.ReplicaSetStatusResult, error) {
+	var result params.ReplicaSetStatusResult
+	if err := c.facade.FacadeCall("ReplicaSetStatus", nil, &result); err != nil {
+		return params.ReplicaSetStatusResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// MigrateStateServer starts replacing the state server on the given
+// machine with a new machine, started according to the given
+// constraints, series and placement directive. It returns the id of
+// the new machine.
+func (c *Client) MigrateStateServer(machineId string, cons constraints.Value, series, placement string) (string, error) {
+	var result params.MigrateStateServerResult
+	arg := params.MigrateStateServer{
+		MachineTag:  names.NewMachineTag(machineId).String(),
+		Constraints: cons,
+		Series:      series,
+		Placement:   placement,
+	}
+	if err := c.facade.FacadeCall("MigrateStateServer", arg, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	tag, err := names.ParseMachineTag(result.MachineTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return tag.Id(), nil
+}
+
+// RetireStateServer removes the state server on the given machine once
+// it has been replaced by MigrateStateServer. It reports false if the
+// machine still holds its replica set vote, in which case it should be
+// called again later.
+func (c *Client) RetireStateServer(machineId string) (bool, error) {
+	var result params.RetireStateServerResult
+	arg := params.Entity{Tag: names.NewMachineTag(machineId).String()}
+	if err := c.facade.FacadeCall("RetireStateServer", arg, &result); err != nil {
+		return false, errors.Trace(err)
+	}
+	return result.Retired, nil
+}
+
+// DrainAPIServer puts the API server the client is connected to in
+// drain mode, before its controller is restarted for maintenance. The
+// server closes its connections, including the client's, once their
+// in-flight requests have completed, and then stops.
+func (c *Client) DrainAPIServer() error {
+	return c.facade.FacadeCall("DrainAPIServer", nil, nil)
+}
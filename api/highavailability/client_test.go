@@ -57,7 +57,7 @@ func assertEnsureAvailability(c *gc.C, s *jujutesting.JujuConnSuite) {
 
 	emptyCons := constraints.Value{}
 	client := highavailability.NewClient(s.APIState)
-	result, err := client.EnsureAvailability(3, emptyCons, "", nil)
+	result, err := client.EnsureAvailability(3, emptyCons, "", nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(result.Maintained, gc.DeepEquals, []string{"machine-0"})
@@ -76,6 +76,39 @@ func (s *clientSuite) TestClientEnsureAvailability(c *gc.C) {
 	assertEnsureAvailability(c, &s.JujuConnSuite)
 }
 
+func (s *clientSuite) TestClientRotateMongoCertificates(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := highavailability.NewClient(s.APIState)
+	result, err := client.RotateMongoCertificates(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Serial, gc.Equals, int64(1))
+	c.Assert(result.Machines, jc.DeepEquals, []string{"machine-0"})
+	c.Assert(result.CACert, gc.Equals, coretesting.CACert)
+
+	_, err = client.RotateMongoCertificates(false)
+	c.Assert(err, gc.ErrorMatches, "certificate rotation 1 still in progress")
+}
+
+func (s *clientSuite) TestClientMigrateStateServer(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := highavailability.NewClient(s.APIState)
+	newId, err := client.MigrateStateServer("0", constraints.MustParse("mem=8G"), "", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newId, gc.Equals, "1")
+
+	retired, err := client.RetireStateServer("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retired, jc.IsTrue)
+
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.MachineIds, jc.DeepEquals, []string{"1"})
+}
+
 func (s *clientSuite) TestClientEnsureAvailabilityVersion(c *gc.C) {
 	client := highavailability.NewClient(s.APIState)
 	c.Assert(client.BestAPIVersion(), gc.Equals, 1)
@@ -98,6 +131,12 @@ func (s *clientLegacySuite) TestEnsureAvailabilityLegacy(c *gc.C) {
 
 func (s *clientLegacySuite) TestEnsureAvailabilityLegacyRejectsPlacement(c *gc.C) {
 	client := highavailability.NewClient(s.APIState)
-	_, err := client.EnsureAvailability(3, constraints.Value{}, "", []string{"machine"})
+	_, err := client.EnsureAvailability(3, constraints.Value{}, "", []string{"machine"}, false)
 	c.Assert(err, gc.ErrorMatches, "placement directives not supported with this version of Juju")
 }
+
+func (s *clientLegacySuite) TestEnsureAvailabilityLegacyRejectsAutoReplace(c *gc.C) {
+	client := highavailability.NewClient(s.APIState)
+	_, err := client.EnsureAvailability(3, constraints.Value{}, "", nil, true)
+	c.Assert(err, gc.ErrorMatches, "automatic replacement not supported with this version of Juju")
+}
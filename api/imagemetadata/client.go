@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package imagemetadata provides access to the ImageMetadata API
+// facade, through which custom cloud image metadata is stored on the
+// controller, listed and deleted.
+package imagemetadata
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the ImageMetadata API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the ImageMetadata API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ImageMetadata")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// List returns the stored image metadata that matches the given filter.
+func (c *Client) List(filter params.ImageMetadataFilter) ([]params.CloudImageMetadata, error) {
+	var result params.ListCloudImageMetadataResult
+	if err := c.facade.FacadeCall("List", filter, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Result, nil
+}
+
+// Save stores the given image metadata on the controller.
+func (c *Client) Save(metadata []params.CloudImageMetadata) error {
+	var results params.ErrorResults
+	args := params.MetadataSaveParams{Metadata: metadata}
+	if err := c.facade.FacadeCall("Save", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// Delete removes the stored metadata of the image with the given id.
+func (c *Client) Delete(imageId string) error {
+	var results params.ErrorResults
+	args := params.MetadataImageIds{Ids: []string{imageId}}
+	if err := c.facade.FacadeCall("Delete", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
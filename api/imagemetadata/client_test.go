@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagemetadata_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/imagemetadata"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type imageMetadataMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&imageMetadataMockSuite{})
+
+var image = params.CloudImageMetadata{
+	ImageId: "ami-1",
+	Region:  "us-east-1",
+	Series:  "trusty",
+	Arch:    "amd64",
+}
+
+func (s *imageMetadataMockSuite) TestList(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ImageMetadata")
+			c.Check(request, gc.Equals, "List")
+			c.Check(a, jc.DeepEquals, params.ImageMetadataFilter{
+				Region: "us-east-1",
+				Series: []string{"trusty"},
+			})
+			result, ok := response.(*params.ListCloudImageMetadataResult)
+			c.Assert(ok, jc.IsTrue)
+			result.Result = []params.CloudImageMetadata{image}
+			return nil
+		})
+	client := imagemetadata.NewClient(apiCaller)
+	found, err := client.List(params.ImageMetadataFilter{
+		Region: "us-east-1",
+		Series: []string{"trusty"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.CloudImageMetadata{image})
+}
+
+func (s *imageMetadataMockSuite) TestSave(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ImageMetadata")
+			c.Check(request, gc.Equals, "Save")
+			c.Check(a, jc.DeepEquals, params.MetadataSaveParams{
+				Metadata: []params.CloudImageMetadata{image},
+			})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{}}
+			return nil
+		})
+	client := imagemetadata.NewClient(apiCaller)
+	err := client.Save([]params.CloudImageMetadata{image})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *imageMetadataMockSuite) TestSaveError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			result := response.(*params.ErrorResults)
+			result.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}}
+			return nil
+		})
+	client := imagemetadata.NewClient(apiCaller)
+	err := client.Save([]params.CloudImageMetadata{image})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *imageMetadataMockSuite) TestDelete(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ImageMetadata")
+			c.Check(request, gc.Equals, "Delete")
+			c.Check(a, jc.DeepEquals, params.MetadataImageIds{Ids: []string{"ami-1"}})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "not found", Code: params.CodeNotFound},
+			}}
+			return nil
+		})
+	client := imagemetadata.NewClient(apiCaller)
+	err := client.Delete("ami-1")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
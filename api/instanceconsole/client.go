@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instanceconsole provides access to the InstanceConsole API
+// facade, through which the console output of machine instances is
+// retrieved.
+package instanceconsole
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the InstanceConsole API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the InstanceConsole API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "InstanceConsole")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ConsoleOutput returns the console output of the instance of the
+// machine with the given id.
+func (c *Client) ConsoleOutput(machineId string) (string, error) {
+	if !names.IsValidMachine(machineId) {
+		return "", errors.NotValidf("machine id %q", machineId)
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	if err := c.facade.FacadeCall("ConsoleOutput", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].Result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instanceconsole_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/instanceconsole"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type instanceConsoleMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&instanceConsoleMockSuite{})
+
+func (s *instanceConsoleMockSuite) TestConsoleOutput(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "InstanceConsole")
+			c.Check(request, gc.Equals, "ConsoleOutput")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-1"}},
+			})
+			result, ok := response.(*params.StringResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.StringResult{{Result: "Cloud-init finished\n"}}
+			return nil
+		})
+	client := instanceconsole.NewClient(apiCaller)
+	output, err := client.ConsoleOutput("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.Equals, "Cloud-init finished\n")
+}
+
+func (s *instanceConsoleMockSuite) TestConsoleOutputError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			result := response.(*params.StringResults)
+			result.Results = []params.StringResult{{
+				Error: &params.Error{Message: "boom"},
+			}}
+			return nil
+		})
+	client := instanceconsole.NewClient(apiCaller)
+	_, err := client.ConsoleOutput("1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *instanceConsoleMockSuite) TestConsoleOutputInvalidMachine(c *gc.C) {
+	client := instanceconsole.NewClient(basetesting.APICallerFunc(nil))
+	_, err := client.ConsoleOutput("foo")
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+}
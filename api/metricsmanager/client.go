@@ -7,6 +7,7 @@ package metricsmanager
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -24,6 +25,7 @@ type Client struct {
 type MetricsManagerClient interface {
 	CleanupOldMetrics() error
 	SendMetrics() error
+	SetMeterStatus(unit names.UnitTag, code, info string) error
 }
 
 var _ MetricsManagerClient = (*Client)(nil)
@@ -68,3 +70,18 @@ func (c *Client) SendMetrics() error {
 	}
 	return results.OneError()
 }
+
+// SetMeterStatus sets the meter status of the given unit.
+func (c *Client) SetMeterStatus(unit names.UnitTag, code, info string) error {
+	p := params.MeterStatusParams{Statuses: []params.MeterStatusParam{{
+		Tag:  unit.String(),
+		Code: code,
+		Info: info,
+	}}}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("SetMeterStatus", p, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
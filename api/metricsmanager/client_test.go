@@ -4,6 +4,7 @@
 package metricsmanager_test
 
 import (
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -69,3 +70,22 @@ func (s *metricsManagerSuite) TestSendMetricsFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *metricsManagerSuite) TestSetMeterStatus(c *gc.C) {
+	var called bool
+	metricsmanager.PatchFacadeCall(s, s.manager, func(request string, args, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "SetMeterStatus")
+		c.Assert(args, jc.DeepEquals, params.MeterStatusParams{Statuses: []params.MeterStatusParam{{
+			Tag:  "unit-mysql-0",
+			Code: "RED",
+			Info: "payment overdue",
+		}}})
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.manager.SetMeterStatus(names.NewUnitTag("mysql/0"), "RED", "payment overdue")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
@@ -106,6 +106,13 @@ func (st *State) ContainerConfig() (result params.ContainerConfig, err error) {
 	return result, err
 }
 
+// SetAvailabilityZones records the availability zones discovered from
+// the provider, replacing any recorded before.
+func (st *State) SetAvailabilityZones(zones []params.AvailabilityZone) error {
+	args := params.SetAvailabilityZones{Zones: zones}
+	return st.facade.FacadeCall("SetAvailabilityZones", args, nil)
+}
+
 // MachinesWithTransientErrors returns a slice of machines and corresponding status information
 // for those machines which have transient provisioning errors.
 func (st *State) MachinesWithTransientErrors() ([]*Machine, []params.StatusResult, error) {
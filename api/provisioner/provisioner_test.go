@@ -693,6 +693,16 @@ func (s *provisionerSuite) TestContainerConfig(c *gc.C) {
 	c.Assert(result.PreferIPv6, jc.IsTrue)
 }
 
+func (s *provisionerSuite) TestSetAvailabilityZones(c *gc.C) {
+	err := s.provisioner.SetAvailabilityZones([]params.AvailabilityZone{
+		{Name: "zone-a", Available: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []state.AvailabilityZone{{Name: "zone-a", Available: true}})
+}
+
 func (s *provisionerSuite) TestSetSupportedContainers(c *gc.C) {
 	apiMachine, err := s.provisioner.Machine(s.machine.Tag().(names.MachineTag))
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// httpClient represents the methods of api.State (see api/http.go)
+// needed by resources for direct HTTP requests.
+type httpClient interface {
+	// SendHTTPRequestReader sends an HTTP PUT request relative to the client.
+	SendHTTPRequestReader(path string, attached io.Reader, meta interface{}, name string) (*http.Request, *http.Response, error)
+}
+
+type apiState interface {
+	base.APICallCloser
+	httpClient
+}
+
+// Client allows access to the resources API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+	http   httpClient
+}
+
+// NewClient creates a new client for accessing the resources API.
+func NewClient(st apiState) *Client {
+	frontend, backend := base.NewClientFacade(st, "Resources")
+	return &Client{ClientFacade: frontend, facade: backend, http: st}
+}
+
+// ListResources returns the resources attached to the named service.
+func (c *Client) ListResources(serviceName string) ([]params.ResourceMetadata, error) {
+	args := params.Entities{Entities: []params.Entity{{
+		Tag: names.NewServiceTag(serviceName).String(),
+	}}}
+	var results params.ResourcesResults
+	if err := c.facade.FacadeCall("ListResources", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Resources, nil
+}
+
+// Upload attaches the content read from r to the named service as the
+// named resource, replacing any existing revision of it, and returns
+// the metadata of the new revision.
+func (c *Client) Upload(serviceName, name string, r io.ReadSeeker) (params.ResourceMetadata, error) {
+	// Hash the content first, so the server can verify it.
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return params.ResourceMetadata{}, errors.Annotate(err, "while hashing resource")
+	}
+	if _, err := r.Seek(0, 0); err != nil {
+		return params.ResourceMetadata{}, errors.Trace(err)
+	}
+	meta := params.ResourceMetadata{
+		ServiceName: serviceName,
+		Name:        name,
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}
+
+	// Send the request.
+	_, resp, err := c.http.SendHTTPRequestReader("resources", r, &meta, name)
+	if err != nil {
+		return params.ResourceMetadata{}, errors.Annotate(err, "while sending HTTP request")
+	}
+
+	// Handle the response.
+	if resp.StatusCode != http.StatusOK {
+		failure, err := apihttp.ExtractAPIError(resp)
+		if err != nil {
+			return params.ResourceMetadata{}, errors.Annotate(err, "while extracting failure")
+		}
+		return params.ResourceMetadata{}, errors.Trace(failure)
+	}
+	var result params.ResourceMetadata
+	if err := apihttp.ExtractJSONResult(resp, &result); err != nil {
+		return params.ResourceMetadata{}, errors.Annotate(err, "while extracting result")
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/resources"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type resourcesMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&resourcesMockSuite{})
+
+// fakeState implements the API connection needed by the client.
+type fakeState struct {
+	basetesting.APICallerFunc
+	path     string
+	meta     interface{}
+	attached string
+	resp     *http.Response
+}
+
+func (f *fakeState) SendHTTPRequestReader(path string, attached io.Reader, meta interface{}, name string) (*http.Request, *http.Response, error) {
+	f.path = path
+	f.meta = meta
+	data, err := ioutil.ReadAll(attached)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.attached = string(data)
+	return nil, f.resp, nil
+}
+
+func jsonResponse(c *gc.C, status int, result interface{}) *http.Response {
+	body, err := json.Marshal(result)
+	c.Assert(err, jc.ErrorIsNil)
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+	resp.Header.Set("Content-Type", apihttp.CTypeJSON)
+	return resp
+}
+
+func (s *resourcesMockSuite) TestListResources(c *gc.C) {
+	expected := []params.ResourceMetadata{{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Revision:    2,
+	}}
+	st := &fakeState{APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Check(objType, gc.Equals, "Resources")
+		c.Check(request, gc.Equals, "ListResources")
+		c.Check(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}})
+		result, ok := response.(*params.ResourcesResults)
+		c.Assert(ok, jc.IsTrue)
+		result.Results = []params.ResourcesResult{{Resources: expected}}
+		return nil
+	}}
+	client := resources.NewClient(st)
+	list, err := client.ListResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(list, jc.DeepEquals, expected)
+}
+
+func (s *resourcesMockSuite) TestUpload(c *gc.C) {
+	st := &fakeState{resp: jsonResponse(c, http.StatusOK, params.ResourceMetadata{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Revision:    1,
+	})}
+	client := resources.NewClient(st)
+	result, err := client.Upload("wordpress", "theme", strings.NewReader("abc"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Revision, gc.Equals, 1)
+	c.Check(st.path, gc.Equals, "resources")
+	c.Check(st.attached, gc.Equals, "abc")
+	c.Check(st.meta, jc.DeepEquals, &params.ResourceMetadata{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Size:        3,
+		SHA256:      "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+}
+
+func (s *resourcesMockSuite) TestUploadError(c *gc.C) {
+	st := &fakeState{resp: jsonResponse(c, http.StatusBadRequest, params.Error{Message: "boom"})}
+	client := resources.NewClient(st)
+	_, err := client.Upload("wordpress", "theme", strings.NewReader("abc"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,13 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	gc "gopkg.in/check.v1"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	gc.TestingT(t)
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package scopedcredentials provides the client side of the API used to
+// create and revoke scoped credentials.
+package scopedcredentials
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods to create and revoke the time-limited
+// credentials that give access to part of the API on behalf of the
+// logged in user.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ScopedCredentials")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateScopedCredential creates a credential giving access to the
+// given facades, and only to the given entities if any are specified,
+// that remains valid for the given time; if expiry is zero, the server
+// chooses it.
+func (c *Client) CreateScopedCredential(facades, entities []string, expiry time.Duration) (params.ScopedCredential, error) {
+	args := params.CreateScopedCredentials{
+		Credentials: []params.CreateScopedCredential{{
+			Facades:  facades,
+			Entities: entities,
+			Expiry:   expiry,
+		}},
+	}
+	var results params.ScopedCredentialResults
+	if err := c.facade.FacadeCall("CreateScopedCredentials", args, &results); err != nil {
+		return params.ScopedCredential{}, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return params.ScopedCredential{}, errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ScopedCredential{}, errors.Trace(result.Error)
+	}
+	return *result.Result, nil
+}
+
+// RevokeScopedCredential revokes the scoped credential with the given
+// id.
+func (c *Client) RevokeScopedCredential(id string) error {
+	args := params.ScopedCredentialIds{Ids: []string{id}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeScopedCredentials", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package scopedcredentials_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/scopedcredentials"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type scopedCredentialsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *scopedcredentials.Client
+}
+
+var _ = gc.Suite(&scopedCredentialsSuite{})
+
+func (s *scopedCredentialsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = scopedcredentials.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+}
+
+func (s *scopedCredentialsSuite) TestCreateAndLogin(c *gc.C) {
+	cred, err := s.client.CreateScopedCredential([]string{"Client"}, nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Id, gc.Not(gc.Equals), "")
+
+	info := s.APIInfo(c)
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = []macaroon.Slice{{cred.Macaroon}}
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *scopedCredentialsSuite) TestCreateInvalid(c *gc.C) {
+	_, err := s.client.CreateScopedCredential([]string{"NoSuchFacade"}, nil, 0)
+	c.Assert(err, gc.ErrorMatches, `facade "NoSuchFacade" not valid`)
+}
+
+func (s *scopedCredentialsSuite) TestRevoke(c *gc.C) {
+	cred, err := s.client.CreateScopedCredential([]string{"Client"}, nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.client.RevokeScopedCredential(cred.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ScopedCredential(cred.Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.client.RevokeScopedCredential(cred.Id)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
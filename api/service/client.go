@@ -9,6 +9,7 @@ package service
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -41,3 +42,24 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 	}
 	return errors.Trace(results.OneError())
 }
+
+// ConfigDiff returns the charm defaults, current config settings and
+// the settings last observed by each unit of the given service.
+func (c *Client) ConfigDiff(service string) (params.ServiceConfigDiffResult, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(service).String()}},
+	}
+	var results params.ServiceConfigDiffResults
+	err := c.facade.FacadeCall("ConfigDiff", args, &results)
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ServiceConfigDiffResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ServiceConfigDiffResult{}, result.Error
+	}
+	return result, nil
+}
@@ -70,3 +70,40 @@ func (s *serviceSuite) TestSetServiceMetricCredentialsNoMocks(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(service.MetricCredentials(), gc.DeepEquals, []byte("creds"))
 }
+
+func (s *serviceSuite) TestConfigDiff(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "ConfigDiff")
+		c.Assert(a, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "service-wordpress"}},
+		})
+		result := response.(*params.ServiceConfigDiffResults)
+		result.Results = []params.ServiceConfigDiffResult{{
+			Defaults: params.ConfigSettings{"blog-title": "My Title"},
+			Settings: params.ConfigSettings{"blog-title": "New Title"},
+			Units:    []params.UnitObservedConfig{{Unit: "wordpress/0"}},
+		}}
+		return nil
+	})
+	diff, err := s.client.ConfigDiff("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(diff, jc.DeepEquals, params.ServiceConfigDiffResult{
+		Defaults: params.ConfigSettings{"blog-title": "My Title"},
+		Settings: params.ConfigSettings{"blog-title": "New Title"},
+		Units:    []params.UnitObservedConfig{{Unit: "wordpress/0"}},
+	})
+}
+
+func (s *serviceSuite) TestConfigDiffFails(c *gc.C) {
+	service.PatchFacadeCall(s, s.client, func(request string, args, response interface{}) error {
+		result := response.(*params.ServiceConfigDiffResults)
+		result.Results = make([]params.ServiceConfigDiffResult, 1)
+		result.Results[0].Error = common.ServerError(common.ErrPerm)
+		return nil
+	})
+	_, err := s.client.ConfigDiff("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
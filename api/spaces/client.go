@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package spaces provides access to the spaces API facade, through
+// which network spaces are created and listed.
+package spaces
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the spaces API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the spaces API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Spaces")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateSpace creates a network space with the given name, holding
+// the subnets with the given CIDRs.
+func (c *Client) CreateSpace(name string, subnets []string) error {
+	var results params.ErrorResults
+	args := params.CreateSpacesParams{
+		Spaces: []params.CreateSpaceParams{{Name: name, SubnetCIDRs: subnets}},
+	}
+	if err := c.facade.FacadeCall("CreateSpaces", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListSpaces returns all the network spaces of the environment and
+// their subnets.
+func (c *Client) ListSpaces() ([]params.Space, error) {
+	var results params.ListSpacesResults
+	if err := c.facade.FacadeCall("ListSpaces", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
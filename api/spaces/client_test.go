@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type spacesMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&spacesMockSuite{})
+
+func (s *spacesMockSuite) TestCreateSpace(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Spaces")
+			c.Check(request, gc.Equals, "CreateSpaces")
+			c.Check(a, jc.DeepEquals, params.CreateSpacesParams{
+				Spaces: []params.CreateSpaceParams{{
+					Name:        "dmz",
+					SubnetCIDRs: []string{"10.0.0.0/24"},
+				}},
+			})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{}}
+			return nil
+		})
+	client := spaces.NewClient(apiCaller)
+	err := client.CreateSpace("dmz", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *spacesMockSuite) TestCreateSpaceError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			result := response.(*params.ErrorResults)
+			result.Results = []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}}
+			return nil
+		})
+	client := spaces.NewClient(apiCaller)
+	err := client.CreateSpace("dmz", nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *spacesMockSuite) TestListSpaces(c *gc.C) {
+	expected := []params.Space{{
+		Name:    "dmz",
+		Subnets: []params.SubnetInfo{{CIDR: "10.0.0.0/24"}},
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Spaces")
+			c.Check(request, gc.Equals, "ListSpaces")
+			c.Check(a, gc.IsNil)
+			result, ok := response.(*params.ListSpacesResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = expected
+			return nil
+		})
+	client := spaces.NewClient(apiCaller)
+	result, err := client.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
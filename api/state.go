@@ -9,8 +9,10 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/agenthealth"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/charmrevisionupdater"
 	"github.com/juju/juju/api/deployer"
@@ -25,7 +27,6 @@ import (
 	"github.com/juju/juju/api/networker"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
-	"github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/api/upgrader"
@@ -62,8 +63,12 @@ func (st *State) loginV2(tag, password, nonce string) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return st.setLoginResultV1(tag, result)
+}
+
+func (st *State) setLoginResultV1(tag string, result params.LoginResultV1) error {
 	servers := params.NetworkHostsPorts(result.Servers)
-	err = st.setLoginResult(tag, result.EnvironTag, result.ServerTag, servers, result.Facades)
+	err := st.setLoginResult(tag, result.EnvironTag, result.ServerTag, servers, result.Facades)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -74,6 +79,43 @@ func (st *State) loginV2(tag, password, nonce string) error {
 	return nil
 }
 
+// loginWithMacaroons logs in as the user authenticated by the given
+// macaroons, or by the environment's identity provider. If the API
+// server requires a macaroon to be discharged, the discharger is used
+// to do so before logging in again.
+func (st *State) loginWithMacaroons(macaroons []macaroon.Slice, discharger MacaroonDischarger) error {
+	initial := len(macaroons)
+	for {
+		var result params.LoginResultV1
+		request := &params.LoginRequest{
+			Macaroons: macaroons,
+		}
+		err := st.APICall("Admin", 2, "", "Login", request, &result)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if result.DischargeRequired == nil {
+			if result.UserInfo == nil {
+				return errors.New("no user info in login result")
+			}
+			return st.setLoginResultV1(result.UserInfo.Identity, result)
+		}
+		// Only ask for the discharge of the first macaroon; if the
+		// discharged one is still refused, it is not going to work.
+		if len(macaroons) > initial {
+			return errors.Errorf("cannot log in with macaroons: %s", result.DischargeRequiredReason)
+		}
+		if discharger == nil {
+			return errors.Errorf("cannot log in: macaroon discharge required but no discharger available")
+		}
+		ms, err := discharger.DischargeAll(result.DischargeRequired)
+		if err != nil {
+			return errors.Annotate(err, "cannot get discharge")
+		}
+		macaroons = append(macaroons, ms)
+	}
+}
+
 func (st *State) loginV1(tag, password, nonce string) error {
 	var result struct {
 		// TODO (cmars): remove once we can drop 1.18 login compatibility
@@ -265,6 +307,12 @@ func (st *State) DiskManager() (*diskmanager.State, error) {
 	return diskmanager.NewState(st, machineTag), nil
 }
 
+// AgentHealth returns a version of the state that provides functionality
+// required by the agenthealth worker.
+func (st *State) AgentHealth() *agenthealth.State {
+	return agenthealth.NewState(st, st.authTag)
+}
+
 // DiskFormatter returns a version of the state that provides functionality
 // required by the diskformatter worker.
 func (st *State) DiskFormatter() (*diskformatter.State, error) {
@@ -337,11 +385,6 @@ func (st *State) CharmRevisionUpdater() *charmrevisionupdater.State {
 	return charmrevisionupdater.NewState(st)
 }
 
-// Rsyslog returns access to the Rsyslog API
-func (st *State) Rsyslog() *rsyslog.State {
-	return rsyslog.NewState(st)
-}
-
 // ServerVersion holds the version of the API server that we are connected to.
 // It is possible that this version is Zero if the server does not report this
 // during login. The second result argument indicates if the version number is
@@ -54,13 +54,28 @@ func (c *Client) convert(found []params.StorageDetailsResult) ([]params.StorageD
 	return storages, allErr.Combine()
 }
 
-// List lists all storage.
+// listPageSize is the number of storage instances requested at a time
+// when listing storage.
+var listPageSize = 500
+
+// List lists all storage. The storage is requested a page at a time,
+// so that listing a lot of it does not make a very large response.
 func (c *Client) List() ([]params.StorageInfo, error) {
-	found := params.StorageInfosResult{}
-	if err := c.facade.FacadeCall("List", nil, &found); err != nil {
-		return nil, errors.Trace(err)
+	var all []params.StorageInfo
+	page := params.ListPage{Limit: listPageSize}
+	for {
+		found := params.StorageInfosResult{}
+		if err := c.facade.FacadeCall("List", page, &found); err != nil {
+			return nil, errors.Trace(err)
+		}
+		all = append(all, found.Results...)
+		// API servers that do not support paging return all the
+		// storage, and no continuation token.
+		if found.Continuation == "" {
+			return all, nil
+		}
+		page.Continuation = found.Continuation
 	}
-	return found.Results, nil
 }
 
 // ListPools returns a list of pools that matches given filter.
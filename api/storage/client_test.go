@@ -114,7 +114,7 @@ func (s *storageMockSuite) TestList(c *gc.C) {
 			c.Check(objType, gc.Equals, "Storage")
 			c.Check(id, gc.Equals, "")
 			c.Check(request, gc.Equals, "List")
-			c.Check(a, gc.IsNil)
+			c.Check(a, jc.DeepEquals, params.ListPage{Limit: *storage.ListPageSize})
 
 			if results, k := result.(*params.StorageInfosResult); k {
 				instances := []params.StorageInfo{
@@ -157,6 +157,48 @@ func (s *storageMockSuite) TestList(c *gc.C) {
 	c.Assert(found, jc.DeepEquals, expected)
 }
 
+func (s *storageMockSuite) TestListPages(c *gc.C) {
+	s.PatchValue(storage.ListPageSize, 1)
+	var pages []params.ListPage
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(request, gc.Equals, "List")
+			page := a.(params.ListPage)
+			pages = append(pages, page)
+			results := result.(*params.StorageInfosResult)
+			switch page.Continuation {
+			case "":
+				results.Results = []params.StorageInfo{{
+					StorageDetails: params.StorageDetails{StorageTag: "storage-data-0"},
+				}}
+				results.Continuation = "first"
+			case "first":
+				results.Results = []params.StorageInfo{{
+					StorageDetails: params.StorageDetails{StorageTag: "storage-data-1"},
+				}}
+			default:
+				c.Errorf("unexpected continuation token %q", page.Continuation)
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	found, err := storageClient.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, []params.StorageInfo{{
+		StorageDetails: params.StorageDetails{StorageTag: "storage-data-0"},
+	}, {
+		StorageDetails: params.StorageDetails{StorageTag: "storage-data-1"},
+	}})
+	c.Assert(pages, jc.DeepEquals, []params.ListPage{
+		{Limit: 1},
+		{Limit: 1, Continuation: "first"},
+	})
+}
+
 func (s *storageMockSuite) TestListFacadeCallError(c *gc.C) {
 	msg := "facade failure"
 	apiCaller := basetesting.APICallerFunc(
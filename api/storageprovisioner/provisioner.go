@@ -270,6 +270,22 @@ func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.Er
 	return results.Results, nil
 }
 
+// SetProvisioningErrors records why volumes and filesystems could not be
+// provisioned. They will not be offered for provisioning again until
+// provisioning is retried.
+func (st *State) SetProvisioningErrors(errs []params.StorageProvisioningError) ([]params.ErrorResult, error) {
+	args := params.StorageProvisioningErrors{Errors: errs}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetProvisioningErrors", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(errs) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(errs), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetVolumeAttachmentInfo records the details of newly provisioned volume attachments.
 func (st *State) SetVolumeAttachmentInfo(volumeAttachments []params.VolumeAttachment) ([]params.ErrorResult, error) {
 	args := params.VolumeAttachments{VolumeAttachments: volumeAttachments}
@@ -472,6 +472,39 @@ func (s *provisionerSuite) TestSetFilesystemInfo(c *gc.C) {
 	c.Assert(errorResults[0].Error, gc.IsNil)
 }
 
+func (s *provisionerSuite) TestSetProvisioningErrors(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetProvisioningErrors")
+		c.Check(arg, gc.DeepEquals, params.StorageProvisioningErrors{
+			Errors: []params.StorageProvisioningError{
+				{Tag: "volume-100", Message: "no space left"},
+				{Tag: "filesystem-100", Message: "quota exceeded"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}, {Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetProvisioningErrors([]params.StorageProvisioningError{
+		{Tag: "volume-100", Message: "no space left"},
+		{Tag: "filesystem-100", Message: "quota exceeded"},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 2)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+	c.Assert(errorResults[1].Error, gc.IsNil)
+}
+
 func (s *provisionerSuite) TestSetVolumeAttachmentInfo(c *gc.C) {
 	volumeAttachments := []params.VolumeAttachment{{
 		VolumeTag: "volume-100", MachineTag: "machine-200", DeviceName: "xvdf1",
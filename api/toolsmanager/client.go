@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager
+
+import (
+	"time"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/version"
+)
+
+// Client provides access to the toolsmanager, used to prune the tools
+// stored by the state server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new toolsmanager client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ToolsManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// PruneTools removes the tools that were added longer ago than the
+// retention period, and that no agent uses, returning their versions.
+// If dryRun is true, the versions are returned but nothing is removed.
+func (c *Client) PruneTools(retention time.Duration, dryRun bool) ([]version.Binary, error) {
+	p := params.PruneToolsParams{
+		Retention: retention,
+		DryRun:    dryRun,
+	}
+	var result params.PruneToolsResult
+	err := c.facade.FacadeCall("PruneTools", p, &result)
+	return result.Versions, err
+}
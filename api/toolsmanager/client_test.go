@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/toolsmanager"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type toolsmanagerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&toolsmanagerSuite{})
+
+func (s *toolsmanagerSuite) TestPruneTools(c *gc.C) {
+	var callCount int
+	pruned := []version.Binary{version.MustParseBinary("1.2.3-trusty-amd64")}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ToolsManager")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "PruneTools")
+		c.Check(arg, gc.DeepEquals, params.PruneToolsParams{
+			Retention: 24 * time.Hour,
+			DryRun:    true,
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.PruneToolsResult{})
+		*(result.(*params.PruneToolsResult)) = params.PruneToolsResult{
+			Versions: pruned,
+		}
+		callCount++
+		return nil
+	})
+
+	tm := toolsmanager.NewClient(apiCaller)
+	versions, err := tm.PruneTools(24*time.Hour, true)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Check(versions, jc.DeepEquals, pruned)
+}
+
+func (s *toolsmanagerSuite) TestPruneToolsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("blam")
+	})
+
+	tm := toolsmanager.NewClient(apiCaller)
+	_, err := tm.PruneTools(time.Hour, false)
+	c.Check(err, gc.ErrorMatches, "blam")
+}
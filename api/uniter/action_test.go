@@ -101,6 +101,22 @@ func (s *actionSuite) TestActionComplete(c *gc.C) {
 	c.Assert(completed[0].Name(), gc.Equals, "fakeaction")
 }
 
+func (s *actionSuite) TestActionOutput(c *gc.C) {
+	action, err := s.uniterSuite.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.uniter.ActionBegin(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.uniter.ActionOutput(action.ActionTag(), params.ActionOutputStderr, "uh oh\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output, err := action.Output(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 1)
+	c.Assert(output[0].Stream, gc.Equals, state.ActionOutputStderr)
+	c.Assert(output[0].Data, gc.Equals, "uh oh\n")
+}
+
 func (s *actionSuite) TestActionFail(c *gc.C) {
 	completed, err := s.uniterSuite.wordpressUnit.CompletedActions()
 	c.Assert(err, jc.ErrorIsNil)
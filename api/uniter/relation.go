@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/names"
 
@@ -17,10 +18,11 @@ import (
 // Relation represents a relation between one or two service
 // endpoints.
 type Relation struct {
-	st   *State
-	tag  names.RelationTag
-	id   int
-	life params.Life
+	st            *State
+	tag           names.RelationTag
+	id            int
+	life          params.Life
+	drainDeadline *time.Time
 }
 
 // Tag returns the relation tag.
@@ -46,6 +48,15 @@ func (r *Relation) Life() params.Life {
 	return r.life
 }
 
+// DrainDeadline returns the time at which the draining relation will be
+// broken, and whether it is draining at all.
+func (r *Relation) DrainDeadline() (time.Time, bool) {
+	if r.drainDeadline == nil {
+		return time.Time{}, false
+	}
+	return *r.drainDeadline, true
+}
+
 // Refresh refreshes the contents of the relation from the underlying
 // state. It returns an error that satisfies errors.IsNotFound if the
 // relation has been removed.
@@ -54,10 +65,11 @@ func (r *Relation) Refresh() error {
 	if err != nil {
 		return err
 	}
-	// NOTE: The life cycle information is the only
-	// thing that can change - id, tag and endpoint
+	// NOTE: The life cycle and drain information are the
+	// only things that can change - id, tag and endpoint
 	// information are static.
 	r.life = result.Life
+	r.drainDeadline = result.DrainDeadline
 
 	return nil
 }
@@ -4,6 +4,8 @@
 package uniter_test
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -73,6 +75,28 @@ func (s *relationSuite) TestRefresh(c *gc.C) {
 	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
 }
 
+func (s *relationSuite) TestRefreshDrainDeadline(c *gc.C) {
+	_, draining := s.apiRelation.DrainDeadline()
+	c.Assert(draining, jc.IsFalse)
+
+	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = myRelUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.stateRelation.Drain(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.stateRelation.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	expected, _ := s.stateRelation.DrainDeadline()
+
+	err = s.apiRelation.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.apiRelation.Life(), gc.Equals, params.Alive)
+	deadline, draining := s.apiRelation.DrainDeadline()
+	c.Assert(draining, jc.IsTrue)
+	c.Assert(deadline.Equal(expected), jc.IsTrue)
+}
+
 func (s *relationSuite) TestEndpoint(c *gc.C) {
 	apiEndpoint, err := s.apiRelation.Endpoint()
 	c.Assert(err, jc.ErrorIsNil)
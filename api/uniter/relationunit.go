@@ -162,6 +162,40 @@ func (ru *RelationUnit) ReadSettings(uname string) (params.Settings, error) {
 	return result.Settings, nil
 }
 
+// ReadSettingsForUnits returns a map holding the settings of each of the
+// given remote units, read with a single API call. Units whose settings
+// cannot be read are omitted; ReadSettings reports why.
+func (ru *RelationUnit) ReadSettingsForUnits(unitNames []string) (map[string]params.Settings, error) {
+	args := params.RelationUnitPairs{
+		RelationUnitPairs: make([]params.RelationUnitPair, len(unitNames)),
+	}
+	for i, uname := range unitNames {
+		if !names.IsValidUnit(uname) {
+			return nil, errors.Errorf("%q is not a valid unit", uname)
+		}
+		args.RelationUnitPairs[i] = params.RelationUnitPair{
+			Relation:   ru.relation.tag.String(),
+			LocalUnit:  ru.unit.tag.String(),
+			RemoteUnit: names.NewUnitTag(uname).String(),
+		}
+	}
+	var results params.SettingsResults
+	err := ru.st.facade.FacadeCall("ReadRemoteSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(unitNames) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(unitNames), len(results.Results))
+	}
+	settings := make(map[string]params.Settings)
+	for i, result := range results.Results {
+		if result.Error == nil {
+			settings[unitNames[i]] = result.Settings
+		}
+	}
+	return settings, nil
+}
+
 // Watch returns a watcher that notifies of changes to counterpart
 // units in the relation.
 func (ru *RelationUnit) Watch() (watcher.RelationUnitsWatcher, error) {
@@ -241,6 +241,25 @@ func (s *relationUnitSuite) TestReadSettings(c *gc.C) {
 	})
 }
 
+func (s *relationUnitSuite) TestReadSettingsForUnits(c *gc.C) {
+	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = myRelUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInScope(c, myRelUnit, true)
+
+	// Units whose settings cannot be read are omitted.
+	_, apiRelUnit := s.getRelationUnits(c)
+	gotSettings, err := apiRelUnit.ReadSettingsForUnits([]string{"mysql/0", "logging/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotSettings, gc.DeepEquals, map[string]params.Settings{
+		"mysql/0": {"some": "settings"},
+	})
+
+	_, err = apiRelUnit.ReadSettingsForUnits([]string{"mysql"})
+	c.Assert(err, gc.ErrorMatches, "\"mysql\" is not a valid unit")
+}
+
 func (s *relationUnitSuite) TestReadSettingsInvalidUnitTag(c *gc.C) {
 	// First try to read the settings which are not set.
 	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// httpClient represents the methods of api.State (see api/http.go)
+// needed by the uniter for direct HTTP requests.
+type httpClient interface {
+	// SendHTTPRequest sends an HTTP GET request relative to the client.
+	SendHTTPRequest(path string, args interface{}) (*http.Request, *http.Response, error)
+}
+
+// Resource returns the content of the named resource attached to the
+// unit's service. The caller must close the returned reader.
+func (st *State) Resource(name string) (io.ReadCloser, error) {
+	if st.http == nil {
+		return nil, errors.NotSupportedf("downloading resources over this connection")
+	}
+	serviceName, err := names.UnitService(st.unitTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := params.ResourceDownloadArgs{
+		ServiceName: serviceName,
+		Name:        name,
+	}
+	_, resp, err := st.http.SendHTTPRequest("resources", &args)
+	if err != nil {
+		return nil, errors.Annotate(err, "while sending HTTP request")
+	}
+	if resp.StatusCode != http.StatusOK {
+		failure, err := apihttp.ExtractAPIError(resp)
+		if err != nil {
+			return nil, errors.Annotate(err, "while extracting failure")
+		}
+		return nil, errors.Trace(failure)
+	}
+	return resp.Body, nil
+}
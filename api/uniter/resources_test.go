@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"io/ioutil"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/resourcestorage"
+)
+
+type resourcesSuite struct {
+	uniterSuite
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) TestResource(c *gc.C) {
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	_, err = storage.AddResource(strings.NewReader("abc"), resourcestorage.Metadata{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Size:        3,
+		SHA256:      "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rc, err := s.uniter.Resource("theme")
+	c.Assert(err, jc.ErrorIsNil)
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "abc")
+}
+
+func (s *resourcesSuite) TestResourceNotFound(c *gc.C) {
+	_, err := s.uniter.Resource("theme")
+	c.Assert(err, gc.ErrorMatches, `resource "theme" of service "wordpress" not found`)
+}
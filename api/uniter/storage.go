@@ -39,7 +39,7 @@ func (sa *StorageAccessor) UnitStorageAttachments(unitTag names.UnitTag) ([]para
 		return nil, errors.Trace(err)
 	}
 	if len(results.Results) != 1 {
-		panic(errors.Errorf("expected 1 result, got %d", len(results.Results)))
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
 	}
 	result := results.Results[0]
 	if result.Error != nil {
@@ -78,27 +78,40 @@ func (sa *StorageAccessor) StorageAttachment(storageTag names.StorageTag, unitTa
 		// StorageAttachment() was introduced in UniterAPIV2.
 		return params.StorageAttachment{}, errors.NotImplementedf("StorageAttachment() (need V2+)")
 	}
-	args := params.StorageAttachmentIds{
-		Ids: []params.StorageAttachmentId{{
-			StorageTag: storageTag.String(),
-			UnitTag:    unitTag.String(),
-		}},
-	}
-	var results params.StorageAttachmentResults
-	err := sa.facade.FacadeCall("StorageAttachments", args, &results)
+	results, err := sa.StorageAttachments([]params.StorageAttachmentId{{
+		StorageTag: storageTag.String(),
+		UnitTag:    unitTag.String(),
+	}})
 	if err != nil {
-		return params.StorageAttachment{}, errors.Trace(err)
-	}
-	if len(results.Results) != 1 {
-		panic(errors.Errorf("expected 1 result, got %d", len(results.Results)))
+		return params.StorageAttachment{}, err
 	}
-	result := results.Results[0]
+	result := results[0]
 	if result.Error != nil {
 		return params.StorageAttachment{}, result.Error
 	}
 	return result.Result, nil
 }
 
+// StorageAttachments returns the storage attachments with the specified
+// ids. There is one result for each id, in order; the server validates
+// all the ids up front, so an invalid or inaccessible id only causes
+// its own result to carry an error, which the caller must check.
+func (sa *StorageAccessor) StorageAttachments(ids []params.StorageAttachmentId) ([]params.StorageAttachmentResult, error) {
+	if sa.facade.BestAPIVersion() < 2 {
+		// StorageAttachments() was introduced in UniterAPIV2.
+		return nil, errors.NotImplementedf("StorageAttachments() (need V2+)")
+	}
+	var results params.StorageAttachmentResults
+	err := sa.facade.FacadeCall("StorageAttachments", params.StorageAttachmentIds{ids}, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // WatchStorageAttachmentInfos starts a watcher for changes to the info
 // of the storage attachment with the specified unit and storage tags.
 func (sa *StorageAccessor) WatchStorageAttachment(storageTag names.StorageTag, unitTag names.UnitTag) (watcher.NotifyWatcher, error) {
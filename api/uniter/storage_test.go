@@ -33,7 +33,7 @@ func (s *storageSuite) TestUnitStorageAttachments(c *gc.C) {
 	var called bool
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "UnitStorageAttachments")
 		c.Check(arg, gc.DeepEquals, params.Entities{
@@ -64,9 +64,8 @@ func (s *storageSuite) TestStorageAttachmentResultCountMismatch(c *gc.C) {
 		return nil
 	})
 	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
-	c.Assert(func() {
-		st.UnitStorageAttachments(names.NewUnitTag("mysql/0"))
-	}, gc.PanicMatches, "expected 1 result, got 2")
+	_, err := st.UnitStorageAttachments(names.NewUnitTag("mysql/0"))
+	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
 
 func (s *storageSuite) TestAPIErrors(c *gc.C) {
@@ -82,7 +81,7 @@ func (s *storageSuite) TestWatchUnitStorageAttachments(c *gc.C) {
 	var called bool
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "WatchUnitStorageAttachments")
 		c.Check(arg, gc.DeepEquals, params.Entities{
@@ -108,7 +107,7 @@ func (s *storageSuite) TestWatchStorageAttachments(c *gc.C) {
 	var called bool
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "WatchStorageAttachmentInfos")
 		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
@@ -145,7 +144,7 @@ func (s *storageSuite) TestStorageAttachments(c *gc.C) {
 	var called bool
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "StorageAttachments")
 		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
@@ -171,10 +170,58 @@ func (s *storageSuite) TestStorageAttachments(c *gc.C) {
 	c.Assert(attachment, gc.DeepEquals, storageAttachment)
 }
 
+func (s *storageSuite) TestStorageAttachmentsBulk(c *gc.C) {
+	storageAttachment := params.StorageAttachment{
+		StorageTag: "storage-data-0",
+		OwnerTag:   "service-mysql",
+		UnitTag:    "unit-mysql-0",
+		Kind:       params.StorageKindBlock,
+		Location:   "/dev/sda",
+	}
+	ids := []params.StorageAttachmentId{{
+		StorageTag: "storage-data-0",
+		UnitTag:    "unit-mysql-0",
+	}, {
+		StorageTag: "invalid",
+		UnitTag:    "unit-mysql-0",
+	}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "StorageAttachments")
+		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{ids})
+		*(result.(*params.StorageAttachmentResults)) = params.StorageAttachmentResults{
+			Results: []params.StorageAttachmentResult{
+				{Result: storageAttachment},
+				{Error: &params.Error{Code: params.CodeNotValid, Message: `storage tag "invalid" not valid`}},
+			},
+		}
+		return nil
+	})
+
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	results, err := st.StorageAttachments(ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Result, gc.DeepEquals, storageAttachment)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, jc.Satisfies, params.IsCodeNotValid)
+}
+
+func (s *storageSuite) TestStorageAttachmentsBulkResultCountMismatch(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StorageAttachmentResults)) = params.StorageAttachmentResults{
+			[]params.StorageAttachmentResult{{}},
+		}
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	_, err := st.StorageAttachments([]params.StorageAttachmentId{{}, {}})
+	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 1")
+}
+
 func (s *storageSuite) TestEnsureStorageAttachmentDead(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "EnsureStorageAttachmentsDead")
 		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
@@ -200,7 +247,7 @@ func (s *storageSuite) TestEnsureStorageAttachmentDead(c *gc.C) {
 func (s *storageSuite) TestRemoveStorageAttachment(c *gc.C) {
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "Uniter")
-		c.Check(version, gc.Equals, 2)
+		c.Check(version, gc.Equals, 3)
 		c.Check(id, gc.Equals, "")
 		c.Check(request, gc.Equals, "RemoveStorageAttachments")
 		c.Check(arg, gc.DeepEquals, params.StorageAttachmentIds{
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 // Unit represents a juju unit as seen by a uniter worker.
@@ -86,7 +87,7 @@ func (u *Unit) UnitStatus() (params.StatusResult, error) {
 		return params.StatusResult{}, errors.Trace(err)
 	}
 	if len(results.Results) != 1 {
-		panic(errors.Errorf("expected 1 result, got %d", len(results.Results)))
+		return params.StatusResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
 	}
 	result := results.Results[0]
 	if result.Error != nil {
@@ -187,6 +188,60 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	return charm.Settings(result.Settings), nil
 }
 
+// SetObservedConfigSettings records the config settings observed by
+// the unit's charm in a config-changed hook.
+func (u *Unit) SetObservedConfigSettings(settings charm.Settings) error {
+	var result params.ErrorResults
+	args := params.EntitiesConfigSettings{
+		Entities: []params.EntityConfigSettings{
+			{Tag: u.tag.String(), Settings: params.ConfigSettings(settings)},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetObservedConfigSettings", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// UpgradeSeriesStatus returns the progress of the unit in the series
+// upgrade of its machine. An error satisfying params.IsCodeNotFound is
+// returned if the unit is not taking part in a series upgrade.
+func (u *Unit) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, error) {
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UpgradeSeriesStatus", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return params.UpgradeSeriesStatus(result.Result), nil
+}
+
+// SetUpgradeSeriesStatus records the progress of the unit in the
+// series upgrade of its machine.
+func (u *Unit) SetUpgradeSeriesStatus(status params.UpgradeSeriesStatus) error {
+	var result params.ErrorResults
+	args := params.SetUpgradeSeriesStatus{
+		Entities: []params.EntityUpgradeSeriesStatus{
+			{Tag: u.tag.String(), Status: status},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetUpgradeSeriesStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // ServiceName returns the service name.
 func (u *Unit) ServiceName() string {
 	service, err := names.UnitService(u.Name())
@@ -379,6 +434,107 @@ func (u *Unit) PrivateAddress() (string, error) {
 	return result.Result, nil
 }
 
+// EndpointAddresses returns the addresses on which the unit should
+// serve the relation endpoint with the given name.
+func (u *Unit) EndpointAddresses(endpoint string) ([]string, error) {
+	if u.st.facade.BestAPIVersion() < 2 {
+		// EndpointAddresses() was introduced in UniterAPIV2.
+		return nil, errors.NotImplementedf("EndpointAddresses() (need V2+)")
+	}
+	var results params.StringsResults
+	args := params.UnitEndpoints{
+		Entities: []params.UnitEndpoint{{Tag: u.tag.String(), Endpoint: endpoint}},
+	}
+	err := u.st.facade.FacadeCall("EndpointAddresses", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// HookContextSnapshot holds the state of a unit needed to set up the
+// context a hook runs in.
+type HookContextSnapshot struct {
+	// ConfigSettings holds the unit's charm config settings. It is
+	// nil when the unit's charm is not yet known.
+	ConfigSettings charm.Settings
+
+	// RelationTags holds the relations whose scope the unit has
+	// entered.
+	RelationTags []names.RelationTag
+
+	// PublicAddress and PrivateAddress hold the unit's addresses,
+	// and are empty when not yet set.
+	PublicAddress  string
+	PrivateAddress string
+
+	// MachinePorts holds all the port ranges opened on the unit's
+	// assigned machine.
+	MachinePorts map[network.PortRange]params.RelationUnit
+
+	MeterStatusCode string
+	MeterStatusInfo string
+
+	// Storage holds the unit's provisioned storage attachments.
+	Storage []params.StorageAttachment
+}
+
+// HookContextSnapshot returns, in a single call, the state of the unit
+// needed to set up a hook context.
+func (u *Unit) HookContextSnapshot() (*HookContextSnapshot, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		// HookContextSnapshot() was introduced in UniterAPIV3.
+		return nil, errors.NotImplementedf("HookContextSnapshot() (need V3+)")
+	}
+	var results params.HookContextSnapshotResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookContextSnapshot", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	snapshot := &HookContextSnapshot{
+		PublicAddress:   result.Result.PublicAddress,
+		PrivateAddress:  result.Result.PrivateAddress,
+		MachinePorts:    make(map[network.PortRange]params.RelationUnit),
+		MeterStatusCode: result.Result.MeterStatusCode,
+		MeterStatusInfo: result.Result.MeterStatusInfo,
+		Storage:         result.Result.Storage,
+	}
+	if result.Result.ConfigSettings != nil {
+		snapshot.ConfigSettings = charm.Settings(result.Result.ConfigSettings)
+	}
+	for _, rel := range result.Result.RelationTags {
+		tag, err := names.ParseRelationTag(rel)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		snapshot.RelationTags = append(snapshot.RelationTags, tag)
+	}
+	for _, ports := range result.Result.MachinePorts {
+		snapshot.MachinePorts[ports.PortRange.NetworkPortRange()] = params.RelationUnit{
+			Unit:     ports.UnitTag,
+			Relation: ports.RelationTag,
+		}
+	}
+	return snapshot, nil
+}
+
 // AvailabilityZone returns the availability zone of the unit.
 func (u *Unit) AvailabilityZone() (string, error) {
 	var results params.StringResults
@@ -401,13 +557,20 @@ func (u *Unit) AvailabilityZone() (string, error) {
 // OpenPorts sets the policy of the port range with protocol to be
 // opened.
 func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
+	return u.OpenPortsFrom(protocol, fromPort, toPort, "")
+}
+
+// OpenPortsFrom sets the policy of the port range with protocol to be
+// opened to the given source CIDR. An empty sourceCIDR means all sources.
+func (u *Unit) OpenPortsFrom(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	var result params.ErrorResults
 	args := params.EntitiesPortRanges{
 		Entities: []params.EntityPortRange{{
-			Tag:      u.tag.String(),
-			Protocol: protocol,
-			FromPort: fromPort,
-			ToPort:   toPort,
+			Tag:        u.tag.String(),
+			Protocol:   protocol,
+			FromPort:   fromPort,
+			ToPort:     toPort,
+			SourceCIDR: sourceCIDR,
 		}},
 	}
 	err := u.st.facade.FacadeCall("OpenPorts", args, &result)
@@ -420,13 +583,20 @@ func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) error {
 // ClosePorts sets the policy of the port range with protocol to be
 // closed.
 func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
+	return u.ClosePortsFrom(protocol, fromPort, toPort, "")
+}
+
+// ClosePortsFrom sets the policy of the port range with protocol to be
+// closed to the given source CIDR. An empty sourceCIDR means all sources.
+func (u *Unit) ClosePortsFrom(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	var result params.ErrorResults
 	args := params.EntitiesPortRanges{
 		Entities: []params.EntityPortRange{{
-			Tag:      u.tag.String(),
-			Protocol: protocol,
-			FromPort: fromPort,
-			ToPort:   toPort,
+			Tag:        u.tag.String(),
+			Protocol:   protocol,
+			FromPort:   fromPort,
+			ToPort:     toPort,
+			SourceCIDR: sourceCIDR,
 		}},
 	}
 	err := u.st.facade.FacadeCall("ClosePorts", args, &result)
@@ -562,6 +732,29 @@ func (u *Unit) WatchConfigSettings() (watcher.NotifyWatcher, error) {
 	return w, nil
 }
 
+// WatchUpgradeSeriesNotifications returns a watcher for observing
+// changes to the series upgrade of the unit's machine. The unit must
+// be assigned to a machine before this method is called.
+func (u *Unit) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchUpgradeSeriesNotifications", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // WatchAddresses returns a watcher for observing changes to the
 // unit's addresses. The unit must be assigned to a machine before
 // this method is called, and the returned watcher will be valid only
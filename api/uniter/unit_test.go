@@ -333,6 +333,54 @@ func (s *unitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(address, gc.Equals, "1.2.3.4")
 }
 
+func (s *unitSuite) TestEndpointAddresses(c *gc.C) {
+	_, err := s.apiUnit.EndpointAddresses("db")
+	c.Assert(err, gc.ErrorMatches, `private address of unit "wordpress/0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	err = s.wordpressMachine.SetAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses, err := s.apiUnit.EndpointAddresses("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"1.2.3.4"})
+}
+
+func (s *unitSuite) TestHookContextSnapshot(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wordpressCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressMachine.SetAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressUnit.OpenPorts("tcp", 80, 81)
+	c.Assert(err, jc.ErrorIsNil)
+	rel, _, _ := s.addRelatedService(c, "wordpress", "logging", s.wordpressUnit)
+
+	snapshot, err := s.apiUnit.HookContextSnapshot()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.ConfigSettings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+	c.Assert(snapshot.RelationTags, gc.DeepEquals, []names.RelationTag{rel.Tag().(names.RelationTag)})
+	c.Assert(snapshot.PublicAddress, gc.Equals, "1.2.3.4")
+	c.Assert(snapshot.PrivateAddress, gc.Equals, "1.2.3.4")
+	c.Assert(snapshot.MachinePorts, gc.DeepEquals, map[network.PortRange]params.RelationUnit{
+		{FromPort: 80, ToPort: 81, Protocol: "tcp"}: {Unit: "unit-wordpress-0"},
+	})
+	c.Assert(snapshot.MeterStatusCode, gc.Not(gc.Equals), "")
+	c.Assert(snapshot.Storage, gc.HasLen, 0)
+}
+
+func (s *unitSuite) TestHookContextSnapshotV2(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+	apiUnit, err := s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = apiUnit.HookContextSnapshot()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *unitSuite) TestAvailabilityZone(c *gc.C) {
 	uniter.PatchUnitResponse(s, s.apiUnit, "AvailabilityZone",
 		func(result interface{}) error {
@@ -387,6 +435,32 @@ func (s *unitSuite) TestOpenClosePortRanges(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestOpenClosePortRangesFrom(c *gc.C) {
+	err := s.apiUnit.OpenPortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.OpenPortsFrom("tcp", 443, 443, "192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, SourceCIDR: "10.0.0.0/8"},
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, SourceCIDR: "192.168.0.0/16"},
+	})
+
+	err = s.apiUnit.ClosePortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err = s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, SourceCIDR: "192.168.0.0/16"},
+	})
+
+	err = s.apiUnit.OpenPortsFrom("tcp", 80, 80, "not-a-cidr")
+	c.Assert(err, gc.ErrorMatches, `invalid port range 80-80/tcp: invalid source CIDR "not-a-cidr"`)
+}
+
 func (s *unitSuite) TestOpenClosePort(c *gc.C) {
 	ports, err := s.wordpressUnit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
@@ -442,6 +516,51 @@ func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, s.wordpressCharm.String())
 }
 
+func (s *unitSuite) TestSetObservedConfigSettings(c *gc.C) {
+	err := s.apiUnit.SetObservedConfigSettings(charm.Settings{"blog-title": "My Title"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, observed := s.wordpressUnit.ObservedConfigSettings()
+	c.Assert(observed, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+}
+
+func (s *unitSuite) TestUpgradeSeriesStatus(c *gc.C) {
+	_, err := s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesPrepareStarted)
+
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	stateStatus, err := s.wordpressUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateStatus, gc.Equals, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *unitSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w, err := s.apiUnit.WatchUpgradeSeriesNotifications()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	// Make sure ConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
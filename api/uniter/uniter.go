@@ -29,6 +29,10 @@ type State struct {
 	facade             base.FacadeCaller
 	// unitTag contains the authenticated unit's tag.
 	unitTag names.UnitTag
+
+	// http makes direct HTTP requests, when the connection
+	// supports them.
+	http httpClient
 }
 
 // newStateForVersion creates a new client-side Uniter facade for the
@@ -50,6 +54,9 @@ func newStateForVersion(
 		facade:          facadeCaller,
 		unitTag:         authTag,
 	}
+	if http, ok := caller.(httpClient); ok {
+		state.http = http
+	}
 
 	if version >= 2 {
 		newWatcher := func(result params.NotifyWatchResult) watcher.NotifyWatcher {
@@ -80,9 +87,12 @@ var newStateV1 = newStateForVersionFn(1)
 // newStateV2 creates a new client-side Uniter facade, version 2.
 var newStateV2 = newStateForVersionFn(2)
 
+// newStateV3 creates a new client-side Uniter facade, version 3.
+var newStateV3 = newStateForVersionFn(3)
+
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV2
+var NewState = newStateV3
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
@@ -207,10 +217,11 @@ func (st *State) Relation(relationTag names.RelationTag) (*Relation, error) {
 		return nil, err
 	}
 	return &Relation{
-		id:   result.Id,
-		tag:  relationTag,
-		life: result.Life,
-		st:   st,
+		id:            result.Id,
+		tag:           relationTag,
+		life:          result.Life,
+		drainDeadline: result.DrainDeadline,
+		st:            st,
 	}, nil
 }
 
@@ -279,6 +290,33 @@ func (st *State) ActionFinish(tag names.ActionTag, status string, results map[st
 	return nil
 }
 
+// ActionOutput records a chunk of the output of a running action,
+// written to the given stream.
+func (st *State) ActionOutput(tag names.ActionTag, stream, data string) error {
+	var outcome params.ErrorResults
+
+	args := params.ActionOutputChunks{
+		Chunks: []params.ActionOutputChunk{{
+			ActionTag: tag.String(),
+			Stream:    stream,
+			Data:      data,
+		}},
+	}
+
+	err := st.facade.FacadeCall("AppendActionsOutput", args, &outcome)
+	if err != nil {
+		return err
+	}
+	if len(outcome.Results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(outcome.Results))
+	}
+	result := outcome.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // RelationById returns the existing relation with the given id.
 func (st *State) RelationById(id int) (*Relation, error) {
 	var results params.RelationResults
@@ -298,10 +336,11 @@ func (st *State) RelationById(id int) (*Relation, error) {
 	}
 	relationTag := names.NewRelationTag(result.Key)
 	return &Relation{
-		id:   result.Id,
-		tag:  relationTag,
-		life: result.Life,
-		st:   st,
+		id:            result.Id,
+		tag:           relationTag,
+		life:          result.Life,
+		drainDeadline: result.DrainDeadline,
+		st:            st,
 	}, nil
 }
 
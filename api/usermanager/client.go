@@ -33,11 +33,18 @@ func NewClient(st base.APICallCloser) *Client {
 
 // AddUser creates a new local user in the juju server.
 func (c *Client) AddUser(username, displayName, password string) (names.UserTag, error) {
+	return c.AddUserWithAccess(username, displayName, password, "")
+}
+
+// AddUserWithAccess creates a new local user with the given access
+// level, "read" or "write", in the juju server. An empty access level
+// gives the user write access.
+func (c *Client) AddUserWithAccess(username, displayName, password, access string) (names.UserTag, error) {
 	if !names.IsValidUser(username) {
 		return names.UserTag{}, fmt.Errorf("invalid user name %q", username)
 	}
 	userArgs := params.AddUsers{
-		Users: []params.AddUser{{Username: username, DisplayName: displayName, Password: password}},
+		Users: []params.AddUser{{Username: username, DisplayName: displayName, Password: password, Access: access}},
 	}
 	var results params.AddUserResults
 	err := c.facade.FacadeCall("AddUser", userArgs, &results)
@@ -166,3 +173,46 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// ShareEnvironment gives the users the given access, "read" or "write",
+// to the environment the client is connected to. Users who already
+// have access to the environment are given the new access level.
+func (c *Client) ShareEnvironment(access string, users ...names.UserTag) error {
+	return c.modifyEnvironAccess(params.AddEnvUser, access, users)
+}
+
+// UnshareEnvironment removes the users' access to the environment the
+// client is connected to.
+func (c *Client) UnshareEnvironment(users ...names.UserTag) error {
+	return c.modifyEnvironAccess(params.RemoveEnvUser, "", users)
+}
+
+func (c *Client) modifyEnvironAccess(action params.EnvironAction, access string, users []names.UserTag) error {
+	if c.BestAPIVersion() < 1 {
+		// ShareEnvironment was introduced in UserManagerAPIV1.
+		return errors.NotImplementedf("ShareEnvironment() (need V1+)")
+	}
+	var args params.ModifyEnvironAccessRequest
+	for _, user := range users {
+		args.Changes = append(args.Changes, params.ModifyEnvironAccess{
+			UserTag: user.String(),
+			Action:  action,
+			Access:  access,
+		})
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironment", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(users) {
+		return errors.Errorf("expected %d results, got %d", len(users), len(result.Results))
+	}
+	for i, r := range result.Results {
+		if action == params.RemoveEnvUser && r.Error != nil && r.Error.Code == params.CodeNotFound {
+			logger.Warningf("environment was not previously shared with user %s", users[i].Username())
+			result.Results[i].Error = nil
+		}
+	}
+	return result.Combine()
+}
@@ -5,9 +5,11 @@ package usermanager_test
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -39,6 +41,15 @@ func (s *usermanagerSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.PasswordValid("password"), jc.IsTrue)
 }
 
+func (s *usermanagerSuite) TestAddUserWithAccess(c *gc.C) {
+	tag, err := s.usermanager.AddUserWithAccess("auditor", "", "password", "read")
+	c.Assert(err, jc.ErrorIsNil)
+
+	user, err := s.State.User(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsReadOnly(), jc.IsTrue)
+}
+
 func (s *usermanagerSuite) TestAddExistingUser(c *gc.C) {
 	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
 
@@ -177,3 +188,39 @@ func (s *usermanagerSuite) TestSetUserPasswordBadName(c *gc.C) {
 	err := s.usermanager.SetPassword("not@home", "new-password")
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
+
+func (s *usermanagerSuite) TestShareEnvironment(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	err := s.usermanager.ShareEnvironment("read", user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+}
+
+func (s *usermanagerSuite) TestShareEnvironmentNeedsV1(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, response interface{}) error {
+			c.Fatalf("unexpected call to %s.%s", objType, request)
+			return nil
+		})
+	client := usermanager.NewClient(apiCaller)
+	err := client.ShareEnvironment("read", names.NewUserTag("foobar"))
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = client.UnshareEnvironment(names.NewUserTag("foobar"))
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *usermanagerSuite) TestUnshareEnvironment(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err := s.usermanager.UnshareEnvironment(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Unsharing with a user without access is not an error.
+	err = s.usermanager.UnshareEnvironment(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// MultiplexingCaller is implemented by API connections that deliver
+// the changes of their NotifyWatchers and StringsWatchers through a
+// Multiplexer.
+type MultiplexingCaller interface {
+	base.APICaller
+
+	// WatcherMultiplexer returns the connection's Multiplexer, or nil
+	// if the API server cannot multiplex watchers.
+	WatcherMultiplexer() *Multiplexer
+}
+
+// Multiplexer receives the changes of all the NotifyWatchers and
+// StringsWatchers of a connection through a single outstanding
+// WatcherMultiplexer.Next call. Without it each watcher would hold a
+// Next call of its own, and the goroutines serving it at both ends.
+type Multiplexer struct {
+	tomb      tomb.Tomb
+	caller    base.APICaller
+	started   chan struct{}
+	startOnce sync.Once
+
+	// mu guards the fields below.
+	mu        sync.Mutex
+	receivers map[string]*muxReceiver
+	dead      bool
+}
+
+// NewMultiplexer returns a Multiplexer that uses the given caller. It
+// makes no calls until a watcher is added, and stops when a call fails,
+// which happens when the connection is closed.
+func NewMultiplexer(caller base.APICaller) *Multiplexer {
+	m := &Multiplexer{
+		caller:    caller,
+		started:   make(chan struct{}),
+		receivers: make(map[string]*muxReceiver),
+	}
+	go func() {
+		defer m.tomb.Done()
+		m.tomb.Kill(m.loop())
+		m.fail()
+	}()
+	return m
+}
+
+// multiplexerFor returns the Multiplexer of the given caller, or nil if
+// its watchers are not multiplexed.
+func multiplexerFor(caller base.APICaller) *Multiplexer {
+	if mc, ok := caller.(MultiplexingCaller); ok {
+		return mc.WatcherMultiplexer()
+	}
+	return nil
+}
+
+// Kill stops the Multiplexer; it is part of the worker.Worker interface.
+// A Next call in progress is not interrupted, so the Multiplexer only
+// stops once there are changes or the connection is closed.
+func (m *Multiplexer) Kill() {
+	m.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (m *Multiplexer) Wait() error {
+	return m.tomb.Wait()
+}
+
+func (m *Multiplexer) loop() error {
+	select {
+	case <-m.tomb.Dying():
+		return tomb.ErrDying
+	case <-m.started:
+	}
+	for {
+		var result params.MultiplexedWatcherChanges
+		err := m.call("Next", nil, &result)
+		select {
+		case <-m.tomb.Dying():
+			return tomb.ErrDying
+		default:
+		}
+		if err != nil {
+			return err
+		}
+		m.mu.Lock()
+		for _, change := range result.Changes {
+			if r, ok := m.receivers[change.WatcherId]; ok {
+				r.receive(change)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+func (m *Multiplexer) call(method string, args, result interface{}) error {
+	return m.caller.APICall("WatcherMultiplexer", m.caller.BestFacadeVersion("WatcherMultiplexer"), "", method, args, result)
+}
+
+// add starts multiplexing the watcher with the given id, which is a
+// NotifyWatcher if notify is true and a StringsWatcher otherwise. The
+// returned muxReceiver receives the watcher's changes.
+func (m *Multiplexer) add(id string, notify bool) (*muxReceiver, error) {
+	r := newMuxReceiver(notify)
+	m.mu.Lock()
+	if m.dead {
+		m.mu.Unlock()
+		return nil, errors.New("watcher multiplexer stopped")
+	}
+	m.receivers[id] = r
+	m.mu.Unlock()
+
+	var args params.MultiplexWatcherIds
+	if notify {
+		args.NotifyWatcherIds = []string{id}
+	} else {
+		args.StringsWatcherIds = []string{id}
+	}
+	var results params.ErrorResults
+	err := m.call("Add", args, &results)
+	if err == nil {
+		err = results.OneError()
+	}
+	if err != nil {
+		m.remove(id)
+		return nil, errors.Annotate(err, "cannot multiplex watcher")
+	}
+	m.startOnce.Do(func() { close(m.started) })
+	return r, nil
+}
+
+// remove stops delivering changes for the watcher with the given id.
+func (m *Multiplexer) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.receivers, id)
+}
+
+// fail stops the multiplexed watchers with the error the Multiplexer
+// stopped with.
+func (m *Multiplexer) fail() {
+	err := m.tomb.Err()
+	if err == nil {
+		err = errors.New("watcher multiplexer stopped")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.receivers {
+		r.stop(err)
+	}
+	m.receivers = nil
+	m.dead = true
+}
+
+// muxReceiver holds the changes delivered to a multiplexed watcher that
+// it has not yet read, so that delivering them never blocks the
+// Multiplexer. The events of a StringsWatcher are kept separate, while
+// those of a NotifyWatcher are coalesced.
+type muxReceiver struct {
+	notify bool
+
+	// ready receives a value when a change is delivered.
+	ready chan struct{}
+
+	// mu guards the fields below.
+	mu     sync.Mutex
+	events [][]string
+	err    error
+}
+
+func newMuxReceiver(notify bool) *muxReceiver {
+	return &muxReceiver{
+		notify: notify,
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+// receive records a change delivered by the API server.
+func (r *muxReceiver) receive(change params.MultiplexedWatcherChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if change.Error != nil {
+		r.err = change.Error
+	} else if !r.notify || len(r.events) == 0 {
+		r.events = append(r.events, change.Changes)
+	}
+	r.wake()
+}
+
+// stop records that the watcher has stopped with the given error.
+func (r *muxReceiver) stop(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+	r.wake()
+}
+
+// wake wakes a waiting call to next. It must be called with mu held.
+func (r *muxReceiver) wake() {
+	select {
+	case r.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for a change to be delivered and returns it, or the error
+// the watcher stopped with. Errors are returned only once any changes
+// delivered before them have been returned. It returns tomb.ErrDying
+// if dying is closed first.
+func (r *muxReceiver) next(dying <-chan struct{}) ([]string, error) {
+	for {
+		r.mu.Lock()
+		if len(r.events) > 0 {
+			changes := r.events[0]
+			r.events = r.events[1:]
+			r.mu.Unlock()
+			return changes, nil
+		}
+		err := r.err
+		r.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-r.ready:
+		case <-dying:
+			return nil, tomb.ErrDying
+		}
+	}
+}
@@ -119,6 +119,58 @@ func (w *commonWatcher) commonLoop() {
 	wg.Wait()
 }
 
+// commonMuxLoop is used instead of commonLoop by watchers whose
+// changes are delivered by a Multiplexer. It runs in a single
+// goroutine, and no Next call is outstanding for the watcher. The
+// changes are sent to w.in as a *params.StringsWatchResult.
+func (w *commonWatcher) commonMuxLoop(mux *Multiplexer, id string, notify bool) {
+	defer close(w.in)
+	receiver, err := mux.add(id, notify)
+	if err != nil {
+		w.tomb.Kill(err)
+		return
+	}
+	defer func() {
+		// Once stopped, the watcher's changes are no longer
+		// wanted; the server reports the stopped watcher to the
+		// Multiplexer, which ignores it.
+		mux.remove(id)
+		if err := w.call("Stop", nil); err != nil {
+			logger.Errorf("error trying to stop watcher: %v", err)
+		}
+	}()
+	for {
+		changes, err := receiver.next(w.tomb.Dying())
+		if err == tomb.ErrDying {
+			return
+		} else if err != nil {
+			if params.IsCodeStopped(err) || params.IsCodeNotFound(err) {
+				if w.tomb.Err() != tomb.ErrStillAlive {
+					err = tomb.ErrDying
+				}
+			}
+			w.tomb.Kill(err)
+			return
+		}
+		select {
+		case <-w.tomb.Dying():
+			return
+		case w.in <- &params.StringsWatchResult{Changes: changes}:
+		}
+	}
+}
+
+// start starts the loop that sends the watcher's changes to w.in,
+// with the watcher's changes delivered by the caller's Multiplexer
+// if it has one.
+func (w *commonWatcher) start(caller base.APICaller, id string, notify bool) {
+	if mux := multiplexerFor(caller); mux != nil {
+		go w.commonMuxLoop(mux, id, notify)
+	} else {
+		go w.commonLoop()
+	}
+}
+
 func (w *commonWatcher) Stop() error {
 	w.tomb.Kill(nil)
 	return w.tomb.Wait()
@@ -158,7 +210,7 @@ func (w *notifyWatcher) loop() error {
 	w.newResult = func() interface{} { return nil }
 	w.call = makeWatcherAPICaller(w.caller, "NotifyWatcher", w.notifyWatcherId)
 	w.commonWatcher.init()
-	go w.commonLoop()
+	w.start(w.caller, w.notifyWatcherId, true)
 
 	for {
 		select {
@@ -210,7 +262,7 @@ func (w *stringsWatcher) loop(initialChanges []string) error {
 	w.newResult = func() interface{} { return new(params.StringsWatchResult) }
 	w.call = makeWatcherAPICaller(w.caller, "StringsWatcher", w.stringsWatcherId)
 	w.commonWatcher.init()
-	go w.commonLoop()
+	w.start(w.caller, w.stringsWatcherId, false)
 
 	for {
 		select {
@@ -11,6 +11,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/testing"
@@ -105,6 +106,57 @@ func (s *watcherSuite) TestNotifyWatcherStopsWithPendingSend(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *watcherSuite) watchMachine(c *gc.C) params.NotifyWatchResult {
+	var results params.NotifyWatchResults
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	err := s.stateAPI.APICall("Machiner", s.stateAPI.BestFacadeVersion("Machiner"), "", "Watch", args, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	return result
+}
+
+// nonMultiplexingCaller hides the Multiplexer of the connection it
+// wraps, as an API server without the WatcherMultiplexer facade would.
+type nonMultiplexingCaller struct {
+	base.APICaller
+}
+
+func (s *watcherSuite) TestWatchMachineWithoutMultiplexer(c *gc.C) {
+	w := watcher.NewNotifyWatcher(nonMultiplexingCaller{s.stateAPI}, s.watchMachine(c))
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.rawMachine.SetProvisioned("i-manager", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *watcherSuite) TestMultiplexedWatchersStopIndependently(c *gc.C) {
+	c.Assert(s.stateAPI.WatcherMultiplexer(), gc.NotNil)
+	w1 := watcher.NewNotifyWatcher(s.stateAPI, s.watchMachine(c))
+	wc1 := statetesting.NewNotifyWatcherC(c, s.State, w1)
+	wc1.AssertOneChange()
+	w2 := watcher.NewNotifyWatcher(s.stateAPI, s.watchMachine(c))
+	wc2 := statetesting.NewNotifyWatcherC(c, s.State, w2)
+	wc2.AssertOneChange()
+
+	statetesting.AssertStop(c, w1)
+	wc1.AssertClosed()
+
+	// The remaining watcher still receives its changes.
+	err := s.rawMachine.SetProvisioned("i-manager", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc2.AssertOneChange()
+
+	statetesting.AssertStop(c, w2)
+	wc2.AssertClosed()
+}
+
 func (s *watcherSuite) TestWatchUnitsKeepsEvents(c *gc.C) {
 	// Create two services, relate them, and add one unit to each - a
 	// principal and a subordinate.
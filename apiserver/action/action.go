@@ -87,7 +87,8 @@ func (a *ActionAPI) FindActionTagsByPrefix(arg params.FindTags) (params.FindTags
 // Enqueue takes a list of Actions and queues them up to be executed by
 // the designated ActionReceiver, returning the params.Action for each
 // enqueued Action, or an error if there was a problem enqueueing the
-// Action.
+// Action. An Action with a Schedule is not queued up immediately, but
+// scheduled; its result holds the resulting ActionSchedule.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
@@ -97,6 +98,15 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
+		if action.Schedule != nil {
+			sched, err := a.state.AddActionSchedule(receiver.Tag(), action.Name, action.Parameters, action.Schedule.At, action.Schedule.Cron)
+			if err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+			response.Results[i] = makeScheduleResult(sched)
+			continue
+		}
 		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
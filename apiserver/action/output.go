@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterFacade(
+		"ActionOutputWatcher", 0, newActionOutputWatcherAPI,
+		reflect.TypeOf((*ActionOutputWatcherAPI)(nil)),
+	)
+}
+
+// WatchActionOutput starts an ActionOutputWatcher for each of the given
+// Actions, through which their output can be followed while they run.
+func (a *ActionAPI) WatchActionOutput(arg params.Entities) (params.ActionOutputWatchResults, error) {
+	response := params.ActionOutputWatchResults{Results: make([]params.ActionOutputWatchResult, len(arg.Entities))}
+	for i, entity := range arg.Entities {
+		currentResult := &response.Results[i]
+		actionTag, err := names.ParseActionTag(entity.Tag)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		action, err := a.state.ActionByTag(actionTag)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		w := &outputWatcher{
+			st:      a.state,
+			tag:     actionTag,
+			watcher: action.Watch(),
+		}
+		currentResult.ActionOutputWatcherId = a.resources.Register(w)
+	}
+	return response, nil
+}
+
+// outputWatcher follows the output of an action, keeping track of how
+// much of it has been reported.
+type outputWatcher struct {
+	st      *state.State
+	tag     names.ActionTag
+	watcher state.NotifyWatcher
+	next    int
+}
+
+// Stop is part of the common.Resource interface.
+func (w *outputWatcher) Stop() error {
+	return w.watcher.Stop()
+}
+
+// nextOutput blocks until the action has written output that has not
+// yet been reported, or has finished.
+func (w *outputWatcher) nextOutput() (params.ActionOutputWatcherNextResult, error) {
+	for {
+		if _, ok := <-w.watcher.Changes(); !ok {
+			err := w.watcher.Err()
+			if err == nil {
+				err = common.ErrStoppedWatcher
+			}
+			return params.ActionOutputWatcherNextResult{}, err
+		}
+		action, err := w.st.ActionByTag(w.tag)
+		if err != nil {
+			return params.ActionOutputWatcherNextResult{}, errors.Trace(err)
+		}
+		output, err := action.Output(w.next)
+		if err != nil {
+			return params.ActionOutputWatcherNextResult{}, errors.Trace(err)
+		}
+		status := action.Status()
+		finished := status != state.ActionPending && status != state.ActionRunning
+		if len(output) == 0 && !finished {
+			continue
+		}
+		w.next += len(output)
+		result := params.ActionOutputWatcherNextResult{
+			Output: make([]params.ActionOutput, len(output)),
+			Status: string(status),
+		}
+		for i, chunk := range output {
+			result.Output[i] = params.ActionOutput{
+				Stream:    chunk.Stream,
+				Data:      chunk.Data,
+				Timestamp: chunk.Timestamp,
+			}
+		}
+		return result, nil
+	}
+}
+
+// ActionOutputWatcherAPI implements the API methods of an
+// ActionOutputWatcher.
+type ActionOutputWatcherAPI struct {
+	watcher   *outputWatcher
+	id        string
+	resources *common.Resources
+}
+
+func newActionOutputWatcherAPI(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	w, ok := resources.Get(id).(*outputWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &ActionOutputWatcherAPI{
+		watcher:   w,
+		id:        id,
+		resources: resources,
+	}, nil
+}
+
+// Next returns the output written by the action since the last call
+// to Next, blocking until there is some or the action has finished.
+func (w *ActionOutputWatcherAPI) Next() (params.ActionOutputWatcherNextResult, error) {
+	return w.watcher.nextOutput()
+}
+
+// Stop stops the watcher.
+func (w *ActionOutputWatcherAPI) Stop() error {
+	return w.resources.Stop(w.id)
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/action"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func (s *actionSuite) TestWatchActionOutput(c *gc.C) {
+	api, err := action.NewActionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	a, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	a, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.AppendOutput(state.ActionOutputStdout, "one\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.WatchActionOutput(params.Entities{Entities: []params.Entity{
+		{Tag: a.ActionTag().String()},
+		{Tag: "action-invalid"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, common.ErrBadId.Error())
+
+	facade, err := action.NewActionOutputWatcherAPI(s.State, s.resources, s.authorizer, results.Results[0].ActionOutputWatcherId)
+	c.Assert(err, jc.ErrorIsNil)
+	w := facade.(*action.ActionOutputWatcherAPI)
+	defer w.Stop()
+
+	next, err := w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Status, gc.Equals, params.ActionRunning)
+	c.Assert(next.Output, gc.HasLen, 1)
+	c.Assert(next.Output[0].Stream, gc.Equals, params.ActionOutputStdout)
+	c.Assert(next.Output[0].Data, gc.Equals, "one\n")
+
+	err = a.AppendOutput(state.ActionOutputStderr, "two\n")
+	c.Assert(err, jc.ErrorIsNil)
+	next, err = w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Output, gc.HasLen, 1)
+	c.Assert(next.Output[0].Stream, gc.Equals, params.ActionOutputStderr)
+	c.Assert(next.Output[0].Data, gc.Equals, "two\n")
+
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	next, err = w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Output, gc.HasLen, 0)
+	c.Assert(next.Status, gc.Equals, params.ActionCompleted)
+}
+
+func (s *actionSuite) TestActionOutputWatcherRequiresClient(c *gc.C) {
+	auth := s.authorizer
+	auth.Tag = s.machine0.Tag()
+	_, err := action.NewActionOutputWatcherAPI(s.State, s.resources, auth, "1")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ListSchedules returns all the Actions that are scheduled to be
+// queued up later, in the order in which they are next queued up.
+func (a *ActionAPI) ListSchedules() (params.ActionResults, error) {
+	schedules, err := a.state.ActionSchedules()
+	if err != nil {
+		return params.ActionResults{}, common.ServerError(err)
+	}
+	response := params.ActionResults{Results: make([]params.ActionResult, len(schedules))}
+	for i, sched := range schedules {
+		response.Results[i] = makeScheduleResult(sched)
+	}
+	return response, nil
+}
+
+// RemoveSchedules cancels the ActionSchedules with the given ids.
+// Actions already queued up by the schedules are not affected.
+func (a *ActionAPI) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	response := params.ErrorResults{Results: make([]params.ErrorResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		sched, err := a.state.ActionSchedule(id)
+		if err == nil {
+			err = sched.Remove()
+		}
+		response.Results[i].Error = common.ServerError(err)
+	}
+	return response, nil
+}
+
+func makeScheduleResult(sched *state.ActionSchedule) params.ActionResult {
+	return params.ActionResult{
+		Action: &params.Action{
+			Receiver:   sched.Receiver(),
+			Name:       sched.Name(),
+			Parameters: sched.Parameters(),
+			Schedule: &params.ActionSchedule{
+				Id:      sched.Id(),
+				Cron:    sched.Cron(),
+				NextRun: sched.NextRun(),
+			},
+		},
+		Status: params.ActionScheduled,
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func (s *actionSuite) TestEnqueueScheduled(c *gc.C) {
+	at := time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC)
+	res, err := s.action.Enqueue(params.Actions{Actions: []params.Action{{
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+		Schedule: &params.ActionSchedule{At: at},
+	}, {
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+		Schedule: &params.ActionSchedule{Cron: "@daily"},
+	}, {
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+		Schedule: &params.ActionSchedule{Cron: "nonsense"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Check(res.Results[0].Status, gc.Equals, params.ActionScheduled)
+	c.Check(res.Results[0].Action.Tag, gc.Equals, "")
+	c.Check(res.Results[0].Action.Schedule.Id, gc.Not(gc.Equals), "")
+	c.Check(res.Results[0].Action.Schedule.NextRun, gc.DeepEquals, at)
+
+	c.Assert(res.Results[1].Error, gc.IsNil)
+	c.Check(res.Results[1].Action.Schedule.Cron, gc.Equals, "@daily")
+
+	c.Check(res.Results[2].Error, gc.ErrorMatches, `cannot schedule action "fakeaction" on unit "wordpress/0": invalid schedule "nonsense": .*`)
+
+	// Nothing has been queued up yet.
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actions, gc.HasLen, 0)
+
+	list, err := s.action.ListSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(list.Results, gc.HasLen, 2)
+	c.Check(list.Results[0].Action.Schedule.Id, gc.Equals, res.Results[0].Action.Schedule.Id)
+	c.Check(list.Results[1].Action.Schedule.Id, gc.Equals, res.Results[1].Action.Schedule.Id)
+
+	removed, err := s.action.RemoveSchedules(params.ActionScheduleIds{Ids: []string{
+		res.Results[0].Action.Schedule.Id, "missing",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 2)
+	c.Check(removed.Results[0].Error, gc.IsNil)
+	c.Check(removed.Results[1].Error, gc.ErrorMatches, `action schedule "missing" not found`)
+
+	list, err = s.action.ListSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(list.Results, gc.HasLen, 1)
+	c.Check(list.Results[0].Action.Schedule.Cron, gc.Equals, "@daily")
+}
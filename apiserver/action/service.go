@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// EnqueueAll takes a list of Actions whose receivers are services, and
+// queues each up on all the units of its service. An Action with a
+// positive Parallelism is queued up on no more than that many units at
+// once, and on each of the remaining units in turn as those finish.
+func (a *ActionAPI) EnqueueAll(arg params.Actions) (params.ServiceActionResults, error) {
+	response := params.ServiceActionResults{Results: make([]params.ServiceActionResult, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		serviceTag, err := names.ParseServiceTag(action.Receiver)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		service, err := a.state.Service(serviceTag.Id())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		enqueued, waiting, err := service.EnqueueActions(action.Name, action.Parameters, action.Parallelism)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		currentResult.Actions = make([]params.ActionResult, len(enqueued))
+		for j, enqueuedAction := range enqueued {
+			currentResult.Actions[j] = makeActionResult(names.NewUnitTag(enqueuedAction.Receiver()), enqueuedAction)
+		}
+		for _, unitName := range waiting {
+			currentResult.Waiting = append(currentResult.Waiting, names.NewUnitTag(unitName).String())
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	jujuFactory "github.com/juju/juju/testing/factory"
+)
+
+func (s *actionSuite) TestEnqueueAll(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	unit1 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+
+	res, err := s.action.EnqueueAll(params.Actions{Actions: []params.Action{{
+		Receiver:    s.wordpress.Tag().String(),
+		Name:        "fakeaction",
+		Parallelism: 1,
+	}, {
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+	}, {
+		Receiver: s.wordpress.Tag().String(),
+		Name:     "nonsense",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Actions, gc.HasLen, 1)
+	c.Check(res.Results[0].Actions[0].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Check(res.Results[0].Actions[0].Status, gc.Equals, params.ActionPending)
+	c.Check(res.Results[0].Waiting, jc.DeepEquals, []string{unit1.Tag().String()})
+
+	c.Check(res.Results[1].Error, gc.ErrorMatches, "id not found")
+	c.Check(res.Results[2].Error, gc.ErrorMatches, `cannot enqueue action "nonsense" on service "wordpress": action "nonsense" not defined on unit "wordpress/0"`)
+
+	actions, err := unit1.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actions, gc.HasLen, 0)
+}
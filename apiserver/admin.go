@@ -71,17 +71,39 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 	var agentPingerNeeded = true
 	var isUser bool
 	kind, err := names.TagKind(req.AuthTag)
-	if err != nil || kind != names.UserTagKind {
+	// Logins without a tag are of users authenticated by macaroons.
+	if req.AuthTag != "" && (err != nil || kind != names.UserTagKind) {
 		// Users are not rate limited, all other entities are
+		if !a.srv.rateLimiter.allowLoginAttempt(a.loginRateLimitKey(req.AuthTag)) {
+			logger.Debugf("rate limiting login attempts for %q, try again later", req.AuthTag)
+			return fail, common.ErrTryAgain
+		}
 		if !a.srv.limiter.Acquire() {
 			logger.Debugf("rate limiting, try again later")
+			a.srv.rateLimiter.concurrentLoginRejected()
 			return fail, common.ErrTryAgain
 		}
 		defer a.srv.limiter.Release()
 	} else {
 		isUser = true
 	}
-	entity, err := doCheckCreds(a.root.state, req)
+	var entity state.Entity
+	var scopedCred *state.ScopedCredential
+	if req.AuthTag == "" && authentication.IsScopedCredential(req.Macaroons) {
+		entity, scopedCred, err = a.checkScopedCreds(req)
+	} else if req.AuthTag == "" {
+		entity, err = a.checkMacaroonCreds(req)
+	} else {
+		entity, err = doCheckCreds(a.root.state, req)
+	}
+	if dischargeErr, ok := errors.Cause(err).(*authentication.DischargeRequiredError); ok {
+		// The client needs to get the macaroon discharged by the
+		// identity provider and log in again.
+		return params.LoginResultV1{
+			DischargeRequired:       dischargeErr.Macaroon,
+			DischargeRequiredReason: dischargeErr.Cause.Error(),
+		}, nil
+	}
 	if err != nil {
 		if a.maintenanceInProgress() {
 			// An upgrade, restore or similar operation is in
@@ -114,6 +136,18 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		agentPingerNeeded = false
 	}
 	a.root.entity = entity
+	authedApi = newAuditingRoot(authedApi, a.srv.auditLog, a.root.state, entity.Tag().String())
+
+	if !isEnvironManager(entity) {
+		key := a.rateLimitKey(entity.Tag().String())
+		authedApi = newThrottledRoot(authedApi, a.srv.rateLimiter, key)
+	}
+	if isReadOnlyUser(a.root.state, entity) {
+		authedApi = newReadOnlyRoot(authedApi)
+	}
+	if scopedCred != nil {
+		authedApi = newScopedRoot(authedApi, scopedCred.Facades(), scopedCred.Entities())
+	}
 
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag().String())
@@ -206,6 +240,24 @@ func (a *admin) checkCredsOfStateServerMachine(req params.LoginRequest) (state.E
 	return nil, common.ErrBadCreds
 }
 
+// rateLimitKey returns the key used to apply per-entity rate limits to
+// the entity with the given tag in the connection's environment.
+func (a *admin) rateLimitKey(tag string) string {
+	return a.root.state.EnvironUUID() + ":" + tag
+}
+
+// loginRateLimitKey returns the key used to limit the login attempts
+// made with the given tag. The tag is not yet authenticated, so the key
+// includes the host the attempts come from; otherwise anyone could use
+// up an agent's login attempts by logging in with its tag.
+func (a *admin) loginRateLimitKey(tag string) string {
+	var host string
+	if a.reqNotifier != nil {
+		host = a.reqNotifier.remoteHost()
+	}
+	return a.rateLimitKey(tag) + "@" + host
+}
+
 func (a *admin) maintenanceInProgress() bool {
 	if a.srv.validator == nil {
 		return false
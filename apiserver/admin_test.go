@@ -16,9 +16,14 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -30,6 +35,7 @@ import (
 type baseLoginSuite struct {
 	jujutesting.JujuConnSuite
 	setAdminApi func(*apiserver.Server)
+	rateLimit   apiserver.RateLimitConfig
 }
 
 type loginSuite struct {
@@ -47,6 +53,7 @@ var _ = gc.Suite(&loginSuite{
 func (s *baseLoginSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 	loggo.GetLogger("juju.apiserver").SetLogLevel(loggo.TRACE)
+	s.rateLimit = apiserver.RateLimitConfig{}
 }
 
 type loginV0Suite struct {
@@ -413,6 +420,88 @@ func (s *loginSuite) TestLoginRateLimited(c *gc.C) {
 	}
 }
 
+func (s *loginSuite) TestLoginAttemptsRateLimitedPerEntity(c *gc.C) {
+	s.rateLimit = apiserver.RateLimitConfig{LoginAttemptsPerMinute: 2}
+	info, cleanup := s.setupMachineAndServer(c)
+	defer cleanup()
+
+	for i := 0; i < 2; i++ {
+		st, err := api.Open(info, fastDialOpts)
+		c.Assert(err, jc.ErrorIsNil)
+		st.Close()
+	}
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+
+	// Other agents are unaffected.
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	info.Tag = machine.Tag()
+	info.Password = password
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+}
+
+func (s *loginSuite) TestAPICallsRateLimited(c *gc.C) {
+	s.rateLimit = apiserver.RateLimitConfig{ConnCallsPerSecond: 0.001}
+	info, cleanup := s.setupMachineAndServer(c)
+	defer cleanup()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Machiner().Machine(info.Tag.(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Machiner().Machine(info.Tag.(names.MachineTag))
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+
+	// Pings are never limited.
+	err = st.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestMutatingCallsAudited(c *gc.C) {
+	info, cleanup := s.setupMachineAndServer(c)
+	defer cleanup()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	machine, err := st.Machiner().Machine(info.Tag.(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the state-changing SetStatus call is recorded.
+	entries, err := s.State.AuditEntries(state.AuditFilter{Caller: info.Tag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Facade, gc.Equals, "Machiner")
+	c.Assert(entries[0].Method, gc.Equals, "SetStatus")
+	c.Assert(entries[0].Entities, jc.DeepEquals, []string{info.Tag.String()})
+	c.Assert(entries[0].Result, gc.Equals, "")
+}
+
+func (s *loginSuite) TestReadOnlyUserCannotChangeState(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	password := "password"
+	u := s.Factory.MakeUser(c, &factory.UserParams{Password: password, Access: state.UserAccessRead})
+	info.Tag = u.Tag()
+	info.Password = password
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().EnvironmentSet(map[string]interface{}{"some-key": "value"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
 func (s *loginSuite) TestUsersLoginWhileRateLimited(c *gc.C) {
 	info, cleanup := s.setupMachineAndServer(c)
 	defer cleanup()
@@ -661,6 +750,7 @@ func (s *baseLoginSuite) setupServerForEnvironmentWithValidator(c *gc.C, envTag
 			Key:       []byte(coretesting.ServerKey),
 			Validator: validator,
 			Tag:       names.NewMachineTag("0"),
+			RateLimit: s.rateLimit,
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -759,6 +849,191 @@ func (s *loginAncientSuite) TestAncientLoginDegrades(c *gc.C) {
 	c.Assert(envTag.String(), gc.Equals, apiserver.PreFacadeEnvironTag.String())
 }
 
+// testDischarger discharges macaroons with a local identity service
+// that declares the given user name.
+type testDischarger struct {
+	service  *bakery.Service
+	username string
+}
+
+func (d *testDischarger) DischargeAll(m *macaroon.Macaroon) (macaroon.Slice, error) {
+	return bakery.DischargeAll(m, func(_ string, cav macaroon.Caveat) (*macaroon.Macaroon, error) {
+		checker := bakery.ThirdPartyCheckerFunc(func(_, condition string) ([]checkers.Caveat, error) {
+			return []checkers.Caveat{{Condition: "declared username " + d.username}}, nil
+		})
+		return d.service.Discharge(checker, cav.Id)
+	})
+}
+
+func (s *loginSuite) setUpIdentityProvider(c *gc.C) *testDischarger {
+	idService, err := bakery.NewService(bakery.NewServiceParams{
+		Location: "https://identity.example.com",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	publicKey, err := idService.PublicKey().MarshalText()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"identity-url":        "https://identity.example.com",
+		"identity-public-key": string(publicKey),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return &testDischarger{service: idService, username: "bob@external"}
+}
+
+func (s *loginSuite) TestLoginWithMacaroons(c *gc.C) {
+	discharger := s.setUpIdentityProvider(c)
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "bob@external"})
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.UseMacaroons = true
+	opts := fastDialOpts
+	opts.Discharger = discharger
+	st, err := api.Open(info, opts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The user can use the client facade.
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestLoginWithMacaroonsFromOtherServer(c *gc.C) {
+	discharger := s.setUpIdentityProvider(c)
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "bob@external"})
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	// Have a macaroon minted by one API server discharged...
+	st := s.openAPIWithoutLogin(c, info)
+	defer st.Close()
+	var result params.LoginResultV1
+	err := st.APICall("Admin", 2, "", "Login", &params.LoginRequest{}, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.DischargeRequired, gc.NotNil)
+	ms, err := discharger.DischargeAll(result.DischargeRequired)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ... and log in with it to another one, as a client does after
+	// a failover or a restart of the API server.
+	otherInfo, otherCleanup := s.setupServerWithValidator(c, nil)
+	defer otherCleanup()
+	otherInfo.Tag = nil
+	otherInfo.Password = ""
+	otherInfo.Macaroons = []macaroon.Slice{ms}
+	otherSt, err := api.Open(otherInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer otherSt.Close()
+	_, err = otherSt.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestLoginWithMacaroonsNotEnvironUser(c *gc.C) {
+	discharger := s.setUpIdentityProvider(c)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.UseMacaroons = true
+	opts := fastDialOpts
+	opts.Discharger = discharger
+	_, err := api.Open(info, opts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestLoginDischargeRequired(c *gc.C) {
+	s.setUpIdentityProvider(c)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	st := s.openAPIWithoutLogin(c, info)
+	defer st.Close()
+	var result params.LoginResultV1
+	err := st.APICall("Admin", 2, "", "Login", &params.LoginRequest{}, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.DischargeRequired, gc.NotNil)
+	c.Assert(result.DischargeRequiredReason, gc.Equals, "no macaroons provided")
+	c.Assert(result.UserInfo, gc.IsNil)
+
+	// Not being logged in, the connection cannot make other calls.
+	_, err = st.Machiner().Machine(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, `unknown object type "Machiner"`)
+}
+
+func (s *loginSuite) TestLoginWithMacaroonsWithoutIdentityProvider(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.UseMacaroons = true
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) addScopedCredential(c *gc.C, owner names.UserTag, facades []string) (*state.ScopedCredential, *macaroon.Macaroon) {
+	cred, err := s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:   owner,
+		Facades: facades,
+		Expires: time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := authentication.NewScopedCredentialMacaroon(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	return cred, m
+}
+
+func (s *loginSuite) TestLoginWithScopedCredential(c *gc.C) {
+	_, m := s.addScopedCredential(c, s.AdminUserTag(c), []string{"Client"})
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = []macaroon.Slice{{m}}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The credential acts for its owner, on the facades it allows.
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = usermanager.NewClient(st).UserInfo(nil, false)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestLoginWithRevokedScopedCredential(c *gc.C) {
+	cred, m := s.addScopedCredential(c, s.AdminUserTag(c), []string{"Client"})
+	err := s.State.RemoveScopedCredential(cred.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = []macaroon.Slice{{m}}
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestLoginWithScopedCredentialOfDisabledUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, m := s.addScopedCredential(c, user.UserTag(), []string{"Client"})
+	err := user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = []macaroon.Slice{{m}}
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
 func (s *loginSuite) TestStateServerEnvironment(c *gc.C) {
 	info, cleanup := s.setupServerWithValidator(c, nil)
 	defer cleanup()
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agenthealth implements the API facade through which machine
+// and unit agents report their health.
+package agenthealth
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AgentHealth", 1, NewAgentHealthAPI)
+}
+
+// AgentHealthAPI provides access to the AgentHealth API facade.
+type AgentHealthAPI struct {
+	st          state.EntityFinder
+	getAuthFunc common.GetAuthFunc
+}
+
+var getState = func(st *state.State) state.EntityFinder {
+	return st
+}
+
+// NewAgentHealthAPI creates a new server-side AgentHealth API facade.
+func NewAgentHealthAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*AgentHealthAPI, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	authEntityTag := authorizer.GetAuthTag()
+	getAuthFunc := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			// An agent can only report its own health.
+			return tag == authEntityTag
+		}, nil
+	}
+	return &AgentHealthAPI{
+		st:          getState(st),
+		getAuthFunc: getAuthFunc,
+	}, nil
+}
+
+// SetAgentHealth records the health reported by each of the given agents.
+func (api *AgentHealthAPI) SetAgentHealth(args params.SetAgentHealthArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if len(args.Args) == 0 {
+		return result, nil
+	}
+	canAccess, err := api.getAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			err = api.setAgentHealth(tag, arg.Health)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *AgentHealthAPI) setAgentHealth(tag names.Tag, health params.AgentHealth) error {
+	entity, err := api.st.FindEntity(tag)
+	if err != nil {
+		return err
+	}
+	setter, ok := entity.(state.AgentHealthSetter)
+	if !ok {
+		return common.NotSupportedError(tag, fmt.Sprintf("setting agent health, %T", entity))
+	}
+	return setter.SetAgentHealth(state.AgentHealth{
+		HookQueueDepth:   health.HookQueueDepth,
+		LastAPICall:      health.LastAPICall,
+		DataDirAvailable: health.DataDirAvailable,
+	})
+}
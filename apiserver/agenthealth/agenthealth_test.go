@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agenthealth_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/agenthealth"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&AgentHealthSuite{})
+
+type AgentHealthSuite struct {
+	coretesting.BaseSuite
+	st *mockState
+}
+
+func (s *AgentHealthSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &mockState{entities: map[names.Tag]state.Entity{
+		names.NewMachineTag("0"):    &mockEntity{tag: names.NewMachineTag("0")},
+		names.NewUnitTag("mysql/0"): &mockEntity{tag: names.NewUnitTag("mysql/0")},
+		names.NewMachineTag("2"):    &mockUnsupported{tag: names.NewMachineTag("2")},
+		names.NewMachineTag("1"):    &mockEntity{tag: names.NewMachineTag("1")},
+		names.NewUnitTag("wordpress/0"): &mockEntity{
+			tag: names.NewUnitTag("wordpress/0"),
+			err: errors.New("boom"),
+		},
+	}}
+	agenthealth.PatchState(s, s.st)
+}
+
+func (s *AgentHealthSuite) newAPI(c *gc.C, tag names.Tag) *agenthealth.AgentHealthAPI {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: tag}
+	api, err := agenthealth.NewAgentHealthAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *AgentHealthSuite) TestNewAgentHealthAPIRequiresAgent(c *gc.C) {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	_, err := agenthealth.NewAgentHealthAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealth(c *gc.C) {
+	health := params.AgentHealth{
+		HookQueueDepth:   2,
+		LastAPICall:      time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+		DataDirAvailable: 512,
+	}
+	api := s.newAPI(c, names.NewMachineTag("0"))
+	results, err := api.SetAgentHealth(params.SetAgentHealthArgs{
+		Args: []params.SetAgentHealth{
+			{Tag: "machine-0", Health: health},
+			{Tag: "machine-1", Health: health},
+			{Tag: "unit-mysql-0", Health: health},
+			{Tag: "invalid", Health: health},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	entity := s.st.entities[names.NewMachineTag("0")].(*mockEntity)
+	c.Assert(entity.health, jc.DeepEquals, &state.AgentHealth{
+		HookQueueDepth:   2,
+		LastAPICall:      health.LastAPICall,
+		DataDirAvailable: 512,
+	})
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthUnitAgent(c *gc.C) {
+	api := s.newAPI(c, names.NewUnitTag("wordpress/0"))
+	results, err := api.SetAgentHealth(params.SetAgentHealthArgs{
+		Args: []params.SetAgentHealth{{Tag: "unit-wordpress-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthNotSupported(c *gc.C) {
+	api := s.newAPI(c, names.NewMachineTag("2"))
+	results, err := api.SetAgentHealth(params.SetAgentHealthArgs{
+		Args: []params.SetAgentHealth{{Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `entity "machine-2" does not support setting agent health, .*`)
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthEmptyArgs(c *gc.C) {
+	api := s.newAPI(c, names.NewMachineTag("0"))
+	results, err := api.SetAgentHealth(params.SetAgentHealthArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+}
+
+type mockState struct {
+	entities map[names.Tag]state.Entity
+}
+
+func (st *mockState) FindEntity(tag names.Tag) (state.Entity, error) {
+	entity, ok := st.entities[tag]
+	if !ok {
+		return nil, errors.NotFoundf("%s", tag)
+	}
+	return entity, nil
+}
+
+type mockEntity struct {
+	tag    names.Tag
+	err    error
+	health *state.AgentHealth
+}
+
+func (e *mockEntity) Tag() names.Tag {
+	return e.tag
+}
+
+func (e *mockEntity) SetAgentHealth(health state.AgentHealth) error {
+	e.health = &health
+	return e.err
+}
+
+type mockUnsupported struct {
+	tag names.Tag
+}
+
+func (e *mockUnsupported) Tag() names.Tag {
+	return e.tag
+}
@@ -6,6 +6,7 @@ package apiserver
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
@@ -18,13 +19,13 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
-	"github.com/juju/utils/featureflag"
 	"golang.org/x/net/websocket"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/feature"
+	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
@@ -33,7 +34,7 @@ import (
 var logger = loggo.GetLogger("juju.apiserver")
 
 // loginRateLimit defines how many concurrent Login requests we will
-// accept
+// accept by default.
 const loginRateLimit = 10
 
 // Server holds the server side of the API.
@@ -46,8 +47,13 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	rateLimiter       *rateLimiter
+	metrics           *apiMetrics
+	auditLog          *auditLog
+	sessionPool       *mongo.SessionPool
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
+	macaroonAuth      *authentication.ExternalMacaroonAuthenticator
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
@@ -67,6 +73,19 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// RateLimit holds the limits applied to logins and API calls.
+	RateLimit RateLimitConfig
+
+	// AuditLogPath, if set, holds the path of a file to which
+	// state-changing API calls are logged, in addition to the
+	// environment's audit log.
+	AuditLogPath string
+
+	// ReadPreferences holds the mongo read preferences used by
+	// categories of read-only API calls, such as common.ReadStatus.
+	// Calls in categories not mentioned read from the primary.
+	ReadPreferences map[string]mongo.ReadPreference
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -182,19 +201,34 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 	if err != nil {
 		return nil, err
 	}
+	auditLog, err := newAuditLog(cfg.AuditLogPath)
+	if err != nil {
+		return nil, err
+	}
+	macaroonAuth, err := newMacaroonAuthenticator(s)
+	if err != nil {
+		// Users can still log in with passwords.
+		logger.Errorf("cannot authenticate users with macaroons: %v", err)
+	}
+	rateLimit := cfg.RateLimit.withDefaults()
 	srv := &Server{
-		state:     s,
-		addr:      net.JoinHostPort("localhost", listeningPort),
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:       s,
+		addr:        net.JoinHostPort("localhost", listeningPort),
+		tag:         cfg.Tag,
+		dataDir:     cfg.DataDir,
+		logDir:      cfg.LogDir,
+		limiter:     utils.NewLimiter(rateLimit.MaxConcurrentLogins),
+		rateLimiter: newRateLimiter(rateLimit),
+		metrics:     newAPIMetrics(),
+		auditLog:    auditLog,
+		sessionPool: mongo.NewSessionPool(s.MongoSession(), cfg.ReadPreferences),
+		validator:   cfg.Validator,
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
 			2: newAdminApiV2,
 		},
+		macaroonAuth: macaroonAuth,
 	}
 	// TODO(rog) check that *srvRoot is a valid type for using
 	// as an RPC server.
@@ -219,6 +253,19 @@ func (srv *Server) Stop() error {
 	return srv.tomb.Wait()
 }
 
+// Drain puts the server in drain mode, so that its controller can be
+// restarted for maintenance without interrupting the environment: the
+// server stops accepting connections, and closes the existing ones
+// once their in-flight requests have completed, which makes the
+// connected agents reconnect to the other API servers. The server then
+// stops without error, so that the agent running it does not restart
+// it. Drain returns immediately; Wait returns when the server has
+// stopped.
+func (srv *Server) Drain() {
+	logger.Infof("draining API server")
+	srv.tomb.Kill(nil)
+}
+
 // Kill implements worker.Worker.Kill.
 func (srv *Server) Kill() {
 	srv.tomb.Kill(nil)
@@ -230,20 +277,23 @@ func (srv *Server) Wait() error {
 }
 
 type requestNotifier struct {
-	id    int64
-	start time.Time
+	id      int64
+	start   time.Time
+	metrics *apiMetrics
 
-	mu   sync.Mutex
-	tag_ string
+	mu         sync.Mutex
+	tag_       string
+	remoteAddr string
 }
 
 var globalCounter int64
 
-func newRequestNotifier() *requestNotifier {
+func newRequestNotifier(metrics *apiMetrics) *requestNotifier {
 	return &requestNotifier{
-		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
-		start: time.Now(),
+		id:      atomic.AddInt64(&globalCounter, 1),
+		tag_:    "<unknown>",
+		start:   time.Now(),
+		metrics: metrics,
 	}
 }
 
@@ -260,17 +310,27 @@ func (n *requestNotifier) tag() (tag string) {
 	return
 }
 
+// tracePrefix returns the prefix of the trace ids of the requests made
+// on the connection. It matches the connection id shown in the log.
+func (n *requestNotifier) tracePrefix() string {
+	return fmt.Sprintf("%X", n.id)
+}
+
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
 		return
 	}
+	if !logger.IsDebugEnabled() {
+		return
+	}
 	// TODO(rog) 2013-10-11 remove secrets from some requests.
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
+	traceId := rpc.TraceId(n.tracePrefix(), hdr.RequestId)
 	if logger.IsTraceEnabled() {
-		logger.Tracef("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+		logger.Tracef("<- [%s] %s %s", traceId, n.tag(), jsoncodec.DumpRequest(hdr, body))
 	} else {
-		logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
+		logger.Debugf("<- [%s] %s %s", traceId, n.tag(), jsoncodec.DumpRequest(hdr, "'params redacted'"))
 	}
 }
 
@@ -278,20 +338,47 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 	if req.Type == "Pinger" && req.Action == "Ping" {
 		return
 	}
+	n.metrics.recordCall(req, hdr.Error != "", timeSpent)
+	traceId := rpc.TraceId(n.tracePrefix(), hdr.RequestId)
+	if hdr.Error != "" {
+		// Failed requests are always logged, so that the trace id
+		// returned to the client can be found in the logs.
+		logger.Infof("-> [%s] %s %s %s[%q].%s failed: %s",
+			traceId, n.tag(), timeSpent, req.Type, req.Id, req.Action, hdr.Error)
+	}
+	if !logger.IsDebugEnabled() {
+		return
+	}
 	// TODO(rog) 2013-10-11 remove secrets from some responses.
 	// Until secrets are removed, we only log the body of the requests at trace level
 	// which is below the default level of debug.
 	if logger.IsTraceEnabled() {
-		logger.Tracef("-> [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+		logger.Tracef("-> [%s] %s %s %s", traceId, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, body))
 	} else {
-		logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
+		logger.Debugf("-> [%s] %s %s %s %s[%q].%s", traceId, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, "'body redacted'"), req.Type, req.Id, req.Action)
 	}
 }
 
 func (n *requestNotifier) join(req *http.Request) {
+	n.mu.Lock()
+	n.remoteAddr = req.RemoteAddr
+	n.mu.Unlock()
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
 
+// remoteHost returns the host the connection comes from, or the empty
+// string if it is not known.
+func (n *requestNotifier) remoteHost() string {
+	n.mu.Lock()
+	addr := n.remoteAddr
+	n.mu.Unlock()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
 func (n *requestNotifier) leave() {
 	logger.Infof("[%X] %s API connection terminated after %v", n.id, n.tag(), time.Since(n.start))
 }
@@ -313,6 +400,8 @@ func handleAll(mux *pat.PatternServeMux, pattern string, handler http.Handler) {
 
 func (srv *Server) run(lis net.Listener) {
 	defer srv.tomb.Done()
+	defer srv.auditLog.close()
+	defer srv.sessionPool.Close()
 	defer srv.wg.Wait() // wait for any outstanding requests to complete.
 	srv.wg.Add(1)
 	go func() {
@@ -334,15 +423,13 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/log",
 		&debugLogHandler{
 			httpHandler: httpHandler{ssState: srv.state},
-			logDir:      srv.logDir},
+		},
+	)
+	handleAll(mux, "/environment/:envuuid/logsink",
+		&logSinkHandler{
+			httpHandler: httpHandler{ssState: srv.state},
+		},
 	)
-	if featureflag.Enabled(feature.DbLog) {
-		handleAll(mux, "/environment/:envuuid/logsink",
-			&logSinkHandler{
-				httpHandler: httpHandler{ssState: srv.state},
-			},
-		)
-	}
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
 			httpHandler: httpHandler{ssState: srv.state},
@@ -357,6 +444,11 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{ssState: srv.state},
 		}},
 	)
+	handleAll(mux, "/environment/:envuuid/tools/streams/v1/:filename",
+		&toolsMetadataHandler{toolsHandler{
+			httpHandler{ssState: srv.state},
+		}},
+	)
 	handleAll(mux, "/environment/:envuuid/tools/:version",
 		&toolsDownloadHandler{toolsHandler{
 			httpHandler{ssState: srv.state},
@@ -369,6 +461,9 @@ func (srv *Server) run(lis net.Listener) {
 			stateServerEnvOnly: true,
 		}},
 	)
+	handleAll(mux, "/environment/:envuuid/resources",
+		&resourcesHandler{httpHandler{ssState: srv.state}},
+	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
 		&imagesDownloadHandler{httpHandler{ssState: srv.state}},
@@ -377,7 +472,7 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/log",
 		&debugLogHandler{
 			httpHandler: httpHandler{ssState: srv.state},
-			logDir:      srv.logDir},
+		},
 	)
 	handleAll(mux, "/charms",
 		&charmsHandler{
@@ -394,13 +489,27 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{ssState: srv.state},
 		}},
 	)
+	handleAll(mux, "/debug/pprof/", &introspectionHandler{
+		httpHandler: httpHandler{
+			ssState:            srv.state,
+			stateServerEnvOnly: true,
+		},
+		handler: newPprofHandler(),
+	})
+	handleAll(mux, "/metrics", &introspectionHandler{
+		httpHandler: httpHandler{
+			ssState:            srv.state,
+			stateServerEnvOnly: true,
+		},
+		handler: http.HandlerFunc(srv.serveMetrics),
+	})
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
 }
 
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
-	reqNotifier := newRequestNotifier()
+	reqNotifier := newRequestNotifier(srv.metrics)
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
 	wsServer := websocket.Server{
@@ -414,6 +523,8 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 			if srv.tomb.Err() != tomb.ErrStillAlive {
 				return
 			}
+			srv.metrics.connOpened()
+			defer srv.metrics.connClosed()
 			envUUID := req.URL.Query().Get(":envuuid")
 			logger.Tracef("got a request for env %q", envUUID)
 			if err := srv.serveConn(conn, reqNotifier, envUUID); err != nil {
@@ -424,23 +535,34 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	wsServer.ServeHTTP(w, req)
 }
 
+// RateLimitMetrics returns the number of logins and API calls the server
+// has rejected because of its rate limits.
+func (srv *Server) RateLimitMetrics() RateLimitMetrics {
+	return srv.rateLimiter.metrics()
+}
+
 // Addr returns the address that the server is listening on.
 func (srv *Server) Addr() string {
 	return srv.addr
 }
 
 func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
-	codec := jsoncodec.NewWebsocket(wsConn)
+	var codec *jsoncodec.Codec
+	if wsConn.Request().Header.Get(jsoncodec.CompressionHeader) == jsoncodec.CompressionDeflate {
+		// The client can decode compressed messages, which greatly
+		// reduces the bandwidth used sending watcher deltas.
+		codec = jsoncodec.NewCompressedWebsocket(wsConn)
+	} else {
+		codec = jsoncodec.NewWebsocket(wsConn)
+	}
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
-	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
-	}
-	conn := rpc.NewConn(codec, notifier)
+	// The notifier is always used, so that failed requests are
+	// logged with their trace ids; it only does anything more if
+	// debug logging is enabled.
+	conn := rpc.NewConn(codec, reqNotifier)
+	conn.SetTracePrefix(reqNotifier.tracePrefix())
 
 	var h *apiHandler
 	st, _, err := validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
@@ -450,6 +572,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
+		srv.metrics.addResources(h.getResources())
+		defer srv.metrics.removeResources(h.getResources())
 		adminApis := make(map[int]interface{})
 		for apiVersion, factory := range srv.adminApiFactories {
 			adminApis[apiVersion] = factory(srv, h, reqNotifier)
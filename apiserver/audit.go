@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// maxAuditArgsLen holds the maximum length of the argument summary
+// recorded for an API call.
+const maxAuditArgsLen = 1024
+
+// auditLog records state-changing API calls in the audit log of the
+// environment they were made against and, optionally, in a file.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newAuditLog returns a new auditLog. If path is not empty, entries
+// are also appended to the file at that path, one JSON object per line.
+func newAuditLog(path string) (*auditLog, error) {
+	log := &auditLog{}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot open audit log file")
+		}
+		log.file = file
+	}
+	return log, nil
+}
+
+// auditFileEntry is the form in which entries are written to the audit
+// log file.
+type auditFileEntry struct {
+	EnvUUID  string    `json:"env-uuid"`
+	Time     time.Time `json:"time"`
+	Caller   string    `json:"caller"`
+	Facade   string    `json:"facade"`
+	Version  int       `json:"version"`
+	Id       string    `json:"id,omitempty"`
+	Method   string    `json:"method"`
+	Entities []string  `json:"entities,omitempty"`
+	Args     string    `json:"args,omitempty"`
+	Result   string    `json:"result,omitempty"`
+}
+
+// record adds the entry to the audit log of the given environment.
+// Failures are logged rather than returned, so that they do not affect
+// the outcome of the call being recorded.
+func (a *auditLog) record(st *state.State, entry state.AuditEntry) {
+	if err := st.AddAuditEntry(entry); err != nil {
+		logger.Errorf("cannot record %s.%s call by %s: %v", entry.Facade, entry.Method, entry.Caller, err)
+	}
+	if a.file == nil {
+		return
+	}
+	data, err := json.Marshal(auditFileEntry{
+		EnvUUID:  st.EnvironUUID(),
+		Time:     entry.Time,
+		Caller:   entry.Caller,
+		Facade:   entry.Facade,
+		Version:  entry.Version,
+		Id:       entry.Id,
+		Method:   entry.Method,
+		Entities: entry.Entities,
+		Args:     entry.Args,
+		Result:   entry.Result,
+	})
+	if err != nil {
+		logger.Errorf("cannot marshal audit entry: %v", err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		logger.Errorf("cannot write audit log file: %v", err)
+	}
+}
+
+// close closes the audit log file, if there is one.
+func (a *auditLog) close() error {
+	if a.file == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+// auditingRoot records the state-changing API calls made by a single
+// logged-in entity.
+type auditingRoot struct {
+	rpc.MethodFinder
+	log    *auditLog
+	st     *state.State
+	caller string
+}
+
+// newAuditingRoot returns a new auditingRoot recording calls made by
+// the entity with the given tag against the given environment.
+func newAuditingRoot(finder rpc.MethodFinder, log *auditLog, st *state.State, caller string) *auditingRoot {
+	return &auditingRoot{
+		MethodFinder: finder,
+		log:          log,
+		st:           st,
+		caller:       caller,
+	}
+}
+
+// FindMethod returns a caller that records the call in the audit log
+// once it completes, if the call could change state.
+func (r *auditingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isMutatingCall(rootName, methodName) {
+		return caller, nil
+	}
+	return &auditingCaller{
+		MethodCaller: caller,
+		root:         r,
+		facade:       rootName,
+		version:      version,
+		method:       methodName,
+	}, nil
+}
+
+// auditingCaller records a single API call as it is made.
+type auditingCaller struct {
+	rpcreflect.MethodCaller
+	root    *auditingRoot
+	facade  string
+	version int
+	method  string
+}
+
+// Call is defined on rpcreflect.MethodCaller.
+func (c *auditingCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	result, err := c.MethodCaller.Call(objId, arg)
+	args, entities := summarizeAuditArgs(c.method, arg)
+	c.root.log.record(c.root.st, state.AuditEntry{
+		Time:     time.Now(),
+		Caller:   c.root.caller,
+		Facade:   c.facade,
+		Version:  c.version,
+		Id:       objId,
+		Method:   c.method,
+		Entities: entities,
+		Args:     args,
+		Result:   summarizeAuditResult(result, err),
+	})
+	return result, err
+}
+
+// summarizeAuditArgs returns a summary of the arguments to an API call,
+// and the tags of the entities they name.
+func summarizeAuditArgs(method string, arg reflect.Value) (string, []string) {
+	if !arg.IsValid() {
+		return "", nil
+	}
+	data, err := json.Marshal(arg.Interface())
+	if err != nil {
+		return "", nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", nil
+	}
+	entities := set.NewStrings()
+	collectAuditTags(decoded, entities)
+	var summary string
+	if strings.Contains(method, "Password") || method == "AddUser" {
+		summary = "'params redacted'"
+	} else {
+		summary = string(data)
+		if len(summary) > maxAuditArgsLen {
+			summary = summary[:maxAuditArgsLen] + "..."
+		}
+	}
+	if entities.IsEmpty() {
+		return summary, nil
+	}
+	return summary, entities.SortedValues()
+}
+
+// collectAuditTags adds to tags all the valid tags found in the decoded
+// JSON value v under keys naming a tag, such as "Tag" or "MachineTag".
+func collectAuditTags(v interface{}, tags set.Strings) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			s, ok := value.(string)
+			if ok && strings.HasSuffix(strings.ToLower(key), "tag") {
+				if _, err := names.ParseTag(s); err == nil {
+					tags.Add(s)
+				}
+				continue
+			}
+			collectAuditTags(value, tags)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectAuditTags(value, tags)
+		}
+	}
+}
+
+// summarizeAuditResult returns a summary of the outcome of an API call.
+// It is empty if the call succeeded completely.
+func summarizeAuditResult(result reflect.Value, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if !result.IsValid() {
+		return ""
+	}
+	var errs []*params.Error
+	switch result := result.Interface().(type) {
+	case params.ErrorResult:
+		errs = append(errs, result.Error)
+	case params.ErrorResults:
+		for _, r := range result.Results {
+			errs = append(errs, r.Error)
+		}
+	default:
+		return ""
+	}
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return strings.Join(messages, "; ")
+}
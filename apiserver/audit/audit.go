@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package audit implements the API facade through which clients query
+// the environment's audit log.
+package audit
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Audit", 1, NewAuditAPI)
+}
+
+// AuditLog provides access to an environment's audit log.
+type AuditLog interface {
+	AuditEntries(filter state.AuditFilter) ([]state.AuditEntry, error)
+}
+
+// AuditAPI provides access to the Audit API facade.
+type AuditAPI struct {
+	st AuditLog
+}
+
+var getState = func(st *state.State) AuditLog {
+	return st
+}
+
+// NewAuditAPI creates a new server-side Audit API facade.
+func NewAuditAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*AuditAPI, error) {
+	// Only clients can read the audit log.
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &AuditAPI{st: getState(st)}, nil
+}
+
+// Entries returns the audit log entries matching the given filter, most
+// recent first.
+func (api *AuditAPI) Entries(args params.AuditFilter) (params.AuditEntriesResult, error) {
+	if args.Limit < 0 {
+		return params.AuditEntriesResult{}, errors.NotValidf("negative limit")
+	}
+	entries, err := api.st.AuditEntries(state.AuditFilter{
+		Caller: args.Caller,
+		Entity: args.Entity,
+		Facade: args.Facade,
+		Method: args.Method,
+		Since:  args.Since,
+		Limit:  args.Limit,
+	})
+	if err != nil {
+		return params.AuditEntriesResult{}, errors.Trace(err)
+	}
+	result := params.AuditEntriesResult{
+		Entries: make([]params.AuditEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		result.Entries[i] = params.AuditEntry{
+			Time:     entry.Time,
+			Caller:   entry.Caller,
+			Facade:   entry.Facade,
+			Version:  entry.Version,
+			Id:       entry.Id,
+			Method:   entry.Method,
+			Entities: entry.Entities,
+			Args:     entry.Args,
+			Result:   entry.Result,
+		}
+	}
+	return result, nil
+}
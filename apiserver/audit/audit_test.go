@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/audit"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&AuditSuite{})
+
+type AuditSuite struct {
+	coretesting.BaseSuite
+	st *mockState
+}
+
+type mockState struct {
+	filter  state.AuditFilter
+	entries []state.AuditEntry
+	err     error
+}
+
+func (m *mockState) AuditEntries(filter state.AuditFilter) ([]state.AuditEntry, error) {
+	m.filter = filter
+	return m.entries, m.err
+}
+
+func (s *AuditSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &mockState{}
+	audit.PatchState(s, s.st)
+}
+
+func (s *AuditSuite) newAPI(c *gc.C) *audit.AuditAPI {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	api, err := audit.NewAuditAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *AuditSuite) TestNewAuditAPIRequiresClient(c *gc.C) {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := audit.NewAuditAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AuditSuite) TestEntries(c *gc.C) {
+	when := time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.st.entries = []state.AuditEntry{{
+		Time:     when,
+		Caller:   "user-admin",
+		Facade:   "Client",
+		Method:   "DestroyServiceUnits",
+		Entities: []string{"unit-wordpress-0"},
+		Args:     `{"UnitNames":["wordpress/0"]}`,
+		Result:   "unit is already dying",
+	}}
+	filter := params.AuditFilter{
+		Caller: "user-admin",
+		Entity: "unit-wordpress-0",
+		Facade: "Client",
+		Method: "DestroyServiceUnits",
+		Since:  when.Add(-time.Hour),
+		Limit:  10,
+	}
+	result, err := s.newAPI(c).Entries(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.filter, jc.DeepEquals, state.AuditFilter{
+		Caller: "user-admin",
+		Entity: "unit-wordpress-0",
+		Facade: "Client",
+		Method: "DestroyServiceUnits",
+		Since:  when.Add(-time.Hour),
+		Limit:  10,
+	})
+	c.Assert(result, jc.DeepEquals, params.AuditEntriesResult{
+		Entries: []params.AuditEntry{{
+			Time:     when,
+			Caller:   "user-admin",
+			Facade:   "Client",
+			Method:   "DestroyServiceUnits",
+			Entities: []string{"unit-wordpress-0"},
+			Args:     `{"UnitNames":["wordpress/0"]}`,
+			Result:   "unit is already dying",
+		}},
+	})
+}
+
+func (s *AuditSuite) TestEntriesNegativeLimit(c *gc.C) {
+	_, err := s.newAPI(c).Entries(params.AuditFilter{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "negative limit not valid")
+}
+
+func (s *AuditSuite) TestEntriesError(c *gc.C) {
+	s.st.err = errors.New("boom")
+	_, err := s.newAPI(c).Entries(params.AuditFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit
+
+import "github.com/juju/juju/state"
+
+type Patcher interface {
+	PatchValue(ptr, value interface{})
+}
+
+func PatchState(p Patcher, st AuditLog) {
+	p.PatchValue(&getState, func(*state.State) AuditLog {
+		return st
+	})
+}
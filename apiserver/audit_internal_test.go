@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type auditInternalSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&auditInternalSuite{})
+
+func (s *auditInternalSuite) TestIsMutatingCall(c *gc.C) {
+	for i, test := range []struct {
+		rootName   string
+		methodName string
+		mutating   bool
+	}{
+		{"Client", "ServiceDeploy", true},
+		{"Client", "FullStatus", false},
+		{"Client", "EnvironmentGet", false},
+		{"Client", "EnvironmentSet", true},
+		{"Uniter", "EnterScope", true},
+		{"Uniter", "WatchUnitAddresses", false},
+		{"Machiner", "SetStatus", true},
+		{"Machiner", "Life", false},
+		{"NotifyWatcher", "Stop", false},
+		{"Admin", "Login", false},
+		// Calls are taken to change state unless listed otherwise.
+		{"Client", "InjectMachines", true},
+		{"Client", "ApplyEnvironmentConfig", true},
+		{"Client", "StageEnvironmentConfig", true},
+		{"HighAvailability", "DrainAPIServer", true},
+		{"HighAvailability", "MigrateStateServer", true},
+		{"HighAvailability", "RetireStateServer", true},
+		{"HighAvailability", "RotateMongoCertificates", true},
+		{"HighAvailability", "ReplicaSetStatus", false},
+		{"ImageMetadata", "Save", true},
+		{"ToolsManager", "PruneTools", true},
+		{"CrossModel", "Offer", true},
+		{"CrossModel", "Consume", true},
+		{"MetricsManager", "SendMetrics", true},
+		{"MetricsManager", "CleanupOldMetrics", true},
+		{"Uniter", "AppendActionsOutput", true},
+		{"NoSuchFacade", "Get", true},
+	} {
+		c.Logf("test %d: %s.%s", i, test.rootName, test.methodName)
+		c.Check(isMutatingCall(test.rootName, test.methodName), gc.Equals, test.mutating)
+	}
+}
+
+func (s *auditInternalSuite) TestAuditingRootAuditsMutatingCalls(c *gc.C) {
+	root := newAuditingRoot(fakeMethodFinder{}, &auditLog{}, nil, "user-admin")
+	for i, test := range []struct {
+		rootName   string
+		methodName string
+		audited    bool
+	}{
+		{"Client", "InjectMachines", true},
+		{"Client", "ApplyEnvironmentConfig", true},
+		{"HighAvailability", "RotateMongoCertificates", true},
+		{"ImageMetadata", "Save", true},
+		{"ToolsManager", "PruneTools", true},
+		{"CrossModel", "Offer", true},
+		{"MetricsManager", "SendMetrics", true},
+		{"Uniter", "AppendActionsOutput", true},
+		{"Client", "FullStatus", false},
+		{"AllWatcher", "Next", false},
+	} {
+		c.Logf("test %d: %s.%s", i, test.rootName, test.methodName)
+		caller, err := root.FindMethod(test.rootName, 0, test.methodName)
+		c.Assert(err, jc.ErrorIsNil)
+		_, audited := caller.(*auditingCaller)
+		c.Check(audited, gc.Equals, test.audited)
+	}
+}
+
+// fakeMethodFinder finds every method, without a caller.
+type fakeMethodFinder struct{}
+
+func (fakeMethodFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	return nil, nil
+}
+
+func (s *auditInternalSuite) TestSummarizeAuditArgs(c *gc.C) {
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "machine-1", Status: params.StatusStarted},
+			{Tag: "unit-mysql-0", Status: params.StatusError},
+			{Tag: "bad-tag"},
+		},
+	}
+	summary, entities := summarizeAuditArgs("SetStatus", reflect.ValueOf(args))
+	c.Assert(strings.HasPrefix(summary, `{"Entities":[{"Tag":"machine-1","Status":"started"`), jc.IsTrue)
+	c.Assert(entities, jc.DeepEquals, []string{"machine-1", "unit-mysql-0"})
+
+	summary, entities = summarizeAuditArgs("AddUser", reflect.ValueOf(params.AddUsers{}))
+	c.Assert(summary, gc.Equals, "'params redacted'")
+	c.Assert(entities, gc.IsNil)
+
+	summary, entities = summarizeAuditArgs("SetStatus", reflect.Value{})
+	c.Assert(summary, gc.Equals, "")
+	c.Assert(entities, gc.IsNil)
+}
+
+func (s *auditInternalSuite) TestSummarizeAuditArgsTruncated(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: strings.Repeat("x", 2*maxAuditArgsLen)}},
+	}
+	summary, _ := summarizeAuditArgs("Remove", reflect.ValueOf(args))
+	c.Assert(summary, gc.HasLen, maxAuditArgsLen+len("..."))
+	c.Assert(strings.HasSuffix(summary, "..."), jc.IsTrue)
+}
+
+func (s *auditInternalSuite) TestSummarizeAuditResult(c *gc.C) {
+	c.Assert(summarizeAuditResult(reflect.Value{}, errors.New("boom")), gc.Equals, "boom")
+	c.Assert(summarizeAuditResult(reflect.Value{}, nil), gc.Equals, "")
+	c.Assert(summarizeAuditResult(reflect.ValueOf(params.ErrorResult{}), nil), gc.Equals, "")
+	results := params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "foo"}},
+			{Error: &params.Error{Message: "bar"}},
+		},
+	}
+	c.Assert(summarizeAuditResult(reflect.ValueOf(results), nil), gc.Equals, "foo; bar")
+	c.Assert(summarizeAuditResult(reflect.ValueOf(params.StringResult{Result: "x"}), nil), gc.Equals, "")
+}
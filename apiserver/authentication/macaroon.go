@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+)
+
+const (
+	// usernameKey is the key of the declared caveat through which the
+	// identity provider declares the name of the authenticated user.
+	usernameKey = "username"
+
+	// declaredCondition is the name of the first-party caveats that
+	// declare attributes of the authenticated user.
+	declaredCondition = "declared"
+
+	// timeBeforeCondition is the name of the first-party caveats that
+	// limit the time until which a macaroon is valid.
+	timeBeforeCondition = "time-before"
+
+	// authenticatedUserCondition is the condition of the third-party
+	// caveat discharged by the identity provider.
+	authenticatedUserCondition = "is-authenticated-user"
+)
+
+// DefaultMacaroonExpiry is how long the macaroons minted by an
+// ExternalMacaroonAuthenticator remain valid when its MacaroonExpiry
+// is not set.
+const DefaultMacaroonExpiry = 24 * time.Hour
+
+// ExternalMacaroonAuthenticator authenticates users with macaroons
+// discharged by an external identity provider, so that no password
+// for the users need be stored in state.
+type ExternalMacaroonAuthenticator struct {
+	// Service holds the bakery service that mints and checks the
+	// macaroons.
+	Service *bakery.Service
+
+	// IdentityLocation holds the URL of the identity provider that
+	// discharges the macaroons' third-party caveats.
+	IdentityLocation string
+
+	// MacaroonExpiry holds how long the minted macaroons remain
+	// valid.
+	MacaroonExpiry time.Duration
+
+	// Clock returns the current time. If it is nil, time.Now is used.
+	Clock func() time.Time
+}
+
+// DischargeRequiredError is returned by
+// ExternalMacaroonAuthenticator.Authenticate when the client must
+// discharge the macaroon's third-party caveats at the identity
+// provider, and log in with the result.
+type DischargeRequiredError struct {
+	Cause    error
+	Macaroon *macaroon.Macaroon
+}
+
+// Error implements error.
+func (e *DischargeRequiredError) Error() string {
+	return fmt.Sprintf("macaroon discharge required: %v", e.Cause)
+}
+
+// IsDischargeRequiredError reports whether the cause of the given error
+// is a *DischargeRequiredError.
+func IsDischargeRequiredError(err error) bool {
+	_, ok := errors.Cause(err).(*DischargeRequiredError)
+	return ok
+}
+
+// Authenticate checks the given macaroons and returns the tag of the
+// user that the identity provider declared in the first one that is
+// valid. If none is, it returns a *DischargeRequiredError holding a new
+// macaroon for the client to discharge.
+func (a *ExternalMacaroonAuthenticator) Authenticate(ms []macaroon.Slice) (names.UserTag, error) {
+	cause := errors.New("no macaroons provided")
+	for _, m := range ms {
+		username, err := a.check(m)
+		if err == nil {
+			if !names.IsValidUser(username) {
+				return names.UserTag{}, errors.NotValidf("user name %q", username)
+			}
+			return names.NewUserTag(username), nil
+		}
+		cause = err
+	}
+	m, err := a.newMacaroon()
+	if err != nil {
+		return names.UserTag{}, errors.Annotate(err, "cannot create macaroon")
+	}
+	return names.UserTag{}, &DischargeRequiredError{
+		Cause:    cause,
+		Macaroon: m,
+	}
+}
+
+// check checks the given macaroon and its discharges, and returns the
+// user name declared in them.
+func (a *ExternalMacaroonAuthenticator) check(ms macaroon.Slice) (string, error) {
+	declared, err := declaredAttributes(ms)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	username := declared[usernameKey]
+	if username == "" {
+		return "", errors.New("no user name declared")
+	}
+	checker := bakery.FirstPartyCheckerFunc(func(caveat string) error {
+		return a.checkFirstPartyCaveat(caveat, declared)
+	})
+	if err := a.Service.Check(ms, checker); err != nil {
+		return "", errors.Trace(err)
+	}
+	return username, nil
+}
+
+func (a *ExternalMacaroonAuthenticator) checkFirstPartyCaveat(caveat string, declared map[string]string) error {
+	name, arg := parseCondition(caveat)
+	switch name {
+	case declaredCondition:
+		// The declarations were collected, and checked to be
+		// consistent, by declaredAttributes.
+		key, value := parseCondition(arg)
+		if declared[key] != value {
+			return errors.Errorf("caveat %q not satisfied", caveat)
+		}
+		return nil
+	case timeBeforeCondition:
+		return checkTimeBefore(caveat, arg, a.now())
+	}
+	return errors.Errorf("caveat %q not recognized", caveat)
+}
+
+// checkTimeBefore checks that now is before the time given as the
+// argument of a time-before caveat.
+func checkTimeBefore(caveat, arg string, now time.Time) error {
+	t, err := time.Parse(time.RFC3339Nano, arg)
+	if err != nil {
+		return errors.Annotatef(err, "invalid caveat %q", caveat)
+	}
+	if !now.Before(t) {
+		return errors.New("macaroon has expired")
+	}
+	return nil
+}
+
+// newMacaroon returns a new macaroon that must be discharged by the
+// identity provider.
+func (a *ExternalMacaroonAuthenticator) newMacaroon() (*macaroon.Macaroon, error) {
+	expiry := a.MacaroonExpiry
+	if expiry == 0 {
+		expiry = DefaultMacaroonExpiry
+	}
+	return a.Service.NewMacaroon("", nil, []checkers.Caveat{{
+		Condition: timeBeforeCondition + " " + a.now().Add(expiry).UTC().Format(time.RFC3339Nano),
+	}, {
+		Location:  a.IdentityLocation,
+		Condition: authenticatedUserCondition,
+	}})
+}
+
+func (a *ExternalMacaroonAuthenticator) now() time.Time {
+	if a.Clock != nil {
+		return a.Clock()
+	}
+	return time.Now()
+}
+
+// declaredAttributes returns the attributes declared by the first-party
+// caveats of the given macaroons. Anyone can add first-party caveats to
+// a macaroon, but not remove them, so conflicting declarations of an
+// attribute are an error.
+func declaredAttributes(ms macaroon.Slice) (map[string]string, error) {
+	declared := make(map[string]string)
+	for _, m := range ms {
+		for _, cav := range m.Caveats() {
+			if cav.Location != "" {
+				continue
+			}
+			name, arg := parseCondition(cav.Id)
+			if name != declaredCondition {
+				continue
+			}
+			key, value := parseCondition(arg)
+			if old, ok := declared[key]; ok && old != value {
+				return nil, errors.Errorf("conflicting declarations of %q", key)
+			}
+			declared[key] = value
+		}
+	}
+	return declared, nil
+}
+
+// parseCondition splits a caveat condition into its name and argument.
+func parseCondition(condition string) (name, arg string) {
+	parts := strings.SplitN(condition, " ", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
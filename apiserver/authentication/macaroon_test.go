@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+	"gopkg.in/macaroon-bakery.v0/bakery/checkers"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/testing"
+)
+
+type macaroonAuthenticatorSuite struct {
+	testing.BaseSuite
+	idService     *bakery.Service
+	authenticator *authentication.ExternalMacaroonAuthenticator
+	now           time.Time
+	username      string
+}
+
+var _ = gc.Suite(&macaroonAuthenticatorSuite{})
+
+func (s *macaroonAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	var err error
+	s.idService, err = bakery.NewService(bakery.NewServiceParams{
+		Location: "identity",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := bakery.NewService(bakery.NewServiceParams{
+		Location: "juju",
+		Locator: bakery.PublicKeyLocatorMap{
+			"identity": s.idService.PublicKey(),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.now = time.Now()
+	s.username = "bob@external"
+	s.authenticator = &authentication.ExternalMacaroonAuthenticator{
+		Service:          service,
+		IdentityLocation: "identity",
+		Clock:            func() time.Time { return s.now },
+	}
+}
+
+// discharge discharges the third-party caveats of the given macaroon,
+// with the identity service declaring s.username.
+func (s *macaroonAuthenticatorSuite) discharge(c *gc.C, m *macaroon.Macaroon) macaroon.Slice {
+	ms, err := bakery.DischargeAll(m, func(_ string, cav macaroon.Caveat) (*macaroon.Macaroon, error) {
+		checker := bakery.ThirdPartyCheckerFunc(func(_, condition string) ([]checkers.Caveat, error) {
+			c.Check(condition, gc.Equals, "is-authenticated-user")
+			return []checkers.Caveat{{Condition: "declared username " + s.username}}, nil
+		})
+		return s.idService.Discharge(checker, cav.Id)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return ms
+}
+
+func (s *macaroonAuthenticatorSuite) dischargeRequired(c *gc.C, ms []macaroon.Slice) *macaroon.Macaroon {
+	_, err := s.authenticator.Authenticate(ms)
+	c.Assert(err, jc.Satisfies, authentication.IsDischargeRequiredError)
+	return err.(*authentication.DischargeRequiredError).Macaroon
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	tag, err := s.authenticator.Authenticate([]macaroon.Slice{s.discharge(c, m)})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag.String(), gc.Equals, "user-bob@external")
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticateWithoutMacaroons(c *gc.C) {
+	_, err := s.authenticator.Authenticate(nil)
+	c.Assert(err, gc.ErrorMatches, "macaroon discharge required: no macaroons provided")
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticateUndischarged(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	_, err := s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "macaroon discharge required: no user name declared")
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticateExpired(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	ms := s.discharge(c, m)
+	s.now = s.now.Add(authentication.DefaultMacaroonExpiry + time.Second)
+	_, err := s.authenticator.Authenticate([]macaroon.Slice{ms})
+	c.Assert(err, gc.ErrorMatches, "macaroon discharge required: .*macaroon has expired")
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticateConflictingDeclarations(c *gc.C) {
+	m := s.dischargeRequired(c, nil)
+	ms := s.discharge(c, m)
+	// Anyone can add first-party caveats; declaring another user must
+	// not authenticate them.
+	err := ms[0].AddFirstPartyCaveat("declared username alice")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{ms})
+	c.Assert(err, gc.ErrorMatches, `macaroon discharge required: conflicting declarations of "username"`)
+}
+
+func (s *macaroonAuthenticatorSuite) TestAuthenticateInvalidUsername(c *gc.C) {
+	s.username = "not/valid"
+	m := s.dischargeRequired(c, nil)
+	_, err := s.authenticator.Authenticate([]macaroon.Slice{s.discharge(c, m)})
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+// ScopedCredentialLocation is the location of the macaroons minted for
+// scoped credentials, which tells them apart from the macaroons of the
+// external identity provider.
+const ScopedCredentialLocation = "juju scoped credential"
+
+// ScopedCredentialGetter gets the scoped credentials recorded in state.
+type ScopedCredentialGetter interface {
+	ScopedCredential(id string) (*state.ScopedCredential, error)
+}
+
+// NewScopedCredentialMacaroon returns the macaroon that authenticates
+// logins with the given scoped credential until it expires.
+func NewScopedCredentialMacaroon(cred *state.ScopedCredential) (*macaroon.Macaroon, error) {
+	m, err := macaroon.New(cred.RootKey(), cred.Id(), ScopedCredentialLocation)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	expiry := cred.Expires().UTC().Format(time.RFC3339Nano)
+	if err := m.AddFirstPartyCaveat(timeBeforeCondition + " " + expiry); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+// IsScopedCredential reports whether the given macaroons were minted
+// for a scoped credential.
+func IsScopedCredential(ms []macaroon.Slice) bool {
+	return len(ms) > 0 && len(ms[0]) > 0 && ms[0][0].Location() == ScopedCredentialLocation
+}
+
+// ScopedCredentialAuthenticator authenticates logins with the macaroons
+// minted for scoped credentials.
+type ScopedCredentialAuthenticator struct {
+	// Credentials gets the scoped credentials that the macaroons
+	// were minted for.
+	Credentials ScopedCredentialGetter
+
+	// Clock returns the current time. If it is nil, time.Now is used.
+	Clock func() time.Time
+}
+
+// Authenticate returns the scoped credential of the first of the given
+// macaroons that is valid. If none is, it returns common.ErrBadCreds.
+func (a *ScopedCredentialAuthenticator) Authenticate(ms []macaroon.Slice) (*state.ScopedCredential, error) {
+	for _, m := range ms {
+		if len(m) == 0 {
+			continue
+		}
+		cred, err := a.Credentials.ScopedCredential(m[0].Id())
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := a.check(cred, m); err != nil {
+			continue
+		}
+		return cred, nil
+	}
+	return nil, common.ErrBadCreds
+}
+
+// check checks the macaroon minted for the given credential. Those
+// macaroons have no third-party caveats, and anyone holding one may
+// only restrict it further with time-before caveats.
+func (a *ScopedCredentialAuthenticator) check(cred *state.ScopedCredential, ms macaroon.Slice) error {
+	now := a.now()
+	if !now.Before(cred.Expires()) {
+		return errors.New("credential has expired")
+	}
+	checker := func(caveat string) error {
+		name, arg := parseCondition(caveat)
+		if name != timeBeforeCondition {
+			return errors.Errorf("caveat %q not recognized", caveat)
+		}
+		return checkTimeBefore(caveat, arg, now)
+	}
+	return ms[0].Verify(cred.RootKey(), checker, ms[1:])
+}
+
+func (a *ScopedCredentialAuthenticator) now() time.Time {
+	if a.Clock != nil {
+		return a.Clock()
+	}
+	return time.Now()
+}
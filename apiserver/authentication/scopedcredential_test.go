@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type scopedCredentialSuite struct {
+	jujutesting.JujuConnSuite
+	now           time.Time
+	authenticator *authentication.ScopedCredentialAuthenticator
+}
+
+var _ = gc.Suite(&scopedCredentialSuite{})
+
+func (s *scopedCredentialSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.now = time.Now()
+	s.authenticator = &authentication.ScopedCredentialAuthenticator{
+		Credentials: s.State,
+		Clock:       func() time.Time { return s.now },
+	}
+}
+
+func (s *scopedCredentialSuite) addCredential(c *gc.C) (*state.ScopedCredential, *macaroon.Macaroon) {
+	cred, err := s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:   names.NewUserTag("admin"),
+		Facades: []string{"Client"},
+		Expires: s.now.Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := authentication.NewScopedCredentialMacaroon(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	return cred, m
+}
+
+func (s *scopedCredentialSuite) TestAuthenticate(c *gc.C) {
+	cred, m := s.addCredential(c)
+	ms := []macaroon.Slice{{m}}
+	c.Assert(authentication.IsScopedCredential(ms), jc.IsTrue)
+
+	got, err := s.authenticator.Authenticate(ms)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Id(), gc.Equals, cred.Id())
+}
+
+func (s *scopedCredentialSuite) TestAuthenticateExpired(c *gc.C) {
+	_, m := s.addCredential(c)
+	s.now = s.now.Add(2 * time.Hour)
+	_, err := s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *scopedCredentialSuite) TestAuthenticateFurtherRestricted(c *gc.C) {
+	_, m := s.addCredential(c)
+	// The holder of a credential may shorten its life.
+	err := m.AddFirstPartyCaveat("time-before " + s.now.Add(time.Minute).UTC().Format(time.RFC3339Nano))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.now = s.now.Add(2 * time.Minute)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *scopedCredentialSuite) TestAuthenticateUnknownCaveat(c *gc.C) {
+	_, m := s.addCredential(c)
+	err := m.AddFirstPartyCaveat("allow-everything")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *scopedCredentialSuite) TestAuthenticateRevoked(c *gc.C) {
+	cred, m := s.addCredential(c)
+	err := s.State.RemoveScopedCredential(cred.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *scopedCredentialSuite) TestAuthenticateWrongKey(c *gc.C) {
+	cred, _ := s.addCredential(c)
+	m, err := macaroon.New([]byte("not the root key"), cred.Id(), authentication.ScopedCredentialLocation)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.Authenticate([]macaroon.Slice{{m}})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
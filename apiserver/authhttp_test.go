@@ -129,6 +129,16 @@ func (s *userAuthHttpSuite) setupOtherEnvironment(c *gc.C) *state.State {
 	return envState
 }
 
+// useReadOnlyUser makes the requests sent by authRequest come from a
+// user with read-only access to the environment.
+func (s *userAuthHttpSuite) useReadOnlyUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: s.password,
+		Access:   state.UserAccessRead,
+	})
+	s.userTag = user.UserTag()
+}
+
 func (s *userAuthHttpSuite) authRequest(c *gc.C, method, uri, contentType string, body io.Reader) (*http.Response, error) {
 	return s.sendRequest(c, s.userTag.String(), s.password, method, uri, contentType, body)
 }
@@ -39,8 +39,10 @@ func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 	defer stateWrapper.cleanup()
 
-	if err := stateWrapper.authenticateUser(req); err != nil {
-		h.authError(resp, h)
+	// Backups hold the environment's secrets, so users with read-only
+	// access may not download them either.
+	if err := stateWrapper.authenticateWriteUser(req); err != nil {
+		h.userAuthError(resp, h, err)
 		return
 	}
 
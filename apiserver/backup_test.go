@@ -111,6 +111,17 @@ func (s *backupsSuite) TestAuthRequiresClientNotMachine(c *gc.C) {
 	s.checkErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
 }
 
+func (s *backupsSuite) TestRefusesReadOnlyUser(c *gc.C) {
+	s.useReadOnlyUser(c)
+	for _, method := range []string{"GET", "PUT"} {
+		c.Logf("testing HTTP method: %s", method)
+		resp, err := s.authRequest(c, method, s.backupURL(c), "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.checkErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+	}
+	c.Check(s.fake.Calls, gc.HasLen, 0)
+}
+
 type backupsDownloadSuite struct {
 	baseBackupsSuite
 	body []byte
@@ -100,6 +100,8 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.BaseID = meta.BaseID
+	result.Scheduled = meta.Scheduled
 
 	result.Environment = meta.Origin.Environment
 	result.Machine = meta.Origin.Machine
@@ -123,6 +125,8 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Hostname = result.Hostname
 	meta.Origin.Version = result.Version
 	meta.Notes = result.Notes
+	meta.BaseID = result.BaseID
+	meta.Scheduled = result.Scheduled
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
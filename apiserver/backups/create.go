@@ -8,10 +8,17 @@ import (
 	"github.com/juju/replicaset"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 )
 
-var waitUntilReady = replicaset.WaitUntilReady
+var (
+	waitUntilReady  = replicaset.WaitUntilReady
+	oplogRange      = backups.OplogRange
+	providerStorage = getProviderStorage
+)
 
 // Create is the API method that requests juju to create a new backup
 // of its state.  It returns the metadata for that backup.
@@ -28,6 +35,15 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 		return p, errors.Annotatef(err, "HA not ready; try again later")
 	}
 
+	// Fail early if the archive could not be uploaded.
+	var stor storage.Storage
+	if args.Upload {
+		stor, err = providerStorage(a.st)
+		if err != nil {
+			return p, errors.Trace(err)
+		}
+	}
+
 	mgoInfo := a.st.MongoConnectionInfo()
 	dbInfo, err := backups.NewDBInfo(mgoInfo, session)
 	if err != nil {
@@ -40,10 +56,68 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
+	oldest, newest, err := oplogRange(session)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	meta.OplogPosition = newest
+	if args.Incremental {
+		base, err := incrementalBase(backupsMethods, oldest)
+		if err != nil {
+			return p, errors.Trace(err)
+		}
+		meta.BaseID = base.ID()
+		dbInfo.Since = base.OplogPosition
+	}
+
 	err = backupsMethods.Create(meta, a.paths, dbInfo)
 	if err != nil {
 		return p, errors.Trace(err)
 	}
 
-	return ResultFromMetadata(meta), nil
+	result := ResultFromMetadata(meta)
+	if stor != nil {
+		result.Uploaded, err = backups.UploadArchive(backupsMethods, stor, meta.ID())
+		if err != nil {
+			return p, errors.Trace(err)
+		}
+	}
+	return result, nil
+}
+
+// incrementalBase returns the full backup on which a new incremental
+// backup is based. The oplog must still hold every entry since that
+// backup was taken, the oldest entry being at oldestPosition.
+func incrementalBase(backupsMethods backups.Backups, oldestPosition int64) (*backups.Metadata, error) {
+	list, err := backupsMethods.List()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	base, err := backups.LatestFullBackup(list)
+	if errors.IsNotFound(err) {
+		return nil, errors.New("no full backup on which to base an incremental backup")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if base.OplogPosition < oldestPosition {
+		return nil, errors.Errorf("oplog no longer covers full backup %q; create a full backup", base.ID())
+	}
+	return base, nil
+}
+
+// getProviderStorage returns the environment provider's object store.
+func getProviderStorage(st *state.State) (storage.Storage, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	envStorage, ok := env.(environs.EnvironStorage)
+	if !ok {
+		return nil, errors.NotSupportedf("uploading backups to %q provider storage", cfg.Type())
+	}
+	return envStorage.Storage(), nil
 }
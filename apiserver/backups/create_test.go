@@ -4,12 +4,20 @@
 package backups_test
 
 import (
+	"bytes"
+	"io/ioutil"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state"
+	statebackups "github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
 )
 
 func (s *backupsSuite) TestCreateOkay(c *gc.C) {
@@ -52,3 +60,74 @@ func (s *backupsSuite) TestCreateError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) patchIncremental(c *gc.C, oldest, newest int64) *backupstesting.FakeBackups {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	s.PatchValue(backups.OplogRange, func(*mgo.Session) (int64, int64, error) {
+		return oldest, newest, nil
+	})
+	base := backupstesting.NewMetadataStarted()
+	base.SetID("base")
+	base.OplogPosition = 42
+	fake := s.setBackups(c, s.meta, "")
+	fake.MetaList = []*statebackups.Metadata{base}
+	return fake
+}
+
+func (s *backupsSuite) TestCreateIncremental(c *gc.C) {
+	fake := s.patchIncremental(c, 10, 100)
+	args := params.BackupsCreateArgs{Incremental: true}
+	_, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"List", "Create"})
+	c.Check(fake.DBInfoArg.Since, gc.Equals, int64(42))
+}
+
+func (s *backupsSuite) TestCreateIncrementalOplogRolledOver(c *gc.C) {
+	fake := s.patchIncremental(c, 50, 100)
+	args := params.BackupsCreateArgs{Incremental: true}
+	_, err := s.api.Create(args)
+
+	c.Check(err, gc.ErrorMatches, `oplog no longer covers full backup "base"; create a full backup`)
+	c.Check(fake.Calls, jc.DeepEquals, []string{"List"})
+}
+
+func (s *backupsSuite) TestCreateIncrementalNoFullBackup(c *gc.C) {
+	fake := s.patchIncremental(c, 10, 100)
+	fake.MetaList = nil
+	args := params.BackupsCreateArgs{Incremental: true}
+	_, err := s.api.Create(args)
+
+	c.Check(err, gc.ErrorMatches, "no full backup on which to base an incremental backup")
+}
+
+func (s *backupsSuite) TestCreateUpload(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(backups.ProviderStorage, func(*state.State) (storage.Storage, error) {
+		return stor, nil
+	})
+	s.meta.SetID("spam")
+	err = s.meta.MarkComplete(int64(len("<archive>")), "<checksum>")
+	c.Assert(err, jc.ErrorIsNil)
+	fake := s.setBackups(c, s.meta, "")
+	fake.Archive = ioutil.NopCloser(bytes.NewBufferString("<archive>"))
+
+	args := params.BackupsCreateArgs{Upload: true}
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Uploaded, gc.Equals, "juju-backups/spam.tar.gz")
+
+	r, err := stor.Get(result.Uploaded)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<archive>")
+}
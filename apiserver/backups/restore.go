@@ -99,3 +99,15 @@ func (a *API) FinishRestore() error {
 	logger.Infof("Succesfully restored")
 	return info.SetStatus(state.RestoreChecked)
 }
+
+// RestoreInfo implements the server side of Backups.RestoreInfo.
+func (a *API) RestoreInfo() (params.RestoreInfoResult, error) {
+	info, err := a.st.RestoreInfoSetter()
+	if err != nil {
+		return params.RestoreInfoResult{}, errors.Trace(err)
+	}
+	return params.RestoreInfoResult{
+		Status:              string(info.Status()),
+		UnreachableMachines: info.UnreachableMachines(),
+	}, nil
+}
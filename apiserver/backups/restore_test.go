@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func (s *backupsSuite) TestRestoreInfo(c *gc.C) {
+	info, err := s.State.RestoreInfoSetter()
+	c.Assert(err, jc.ErrorIsNil)
+	err = info.SetStatus(state.RestoreFinished)
+	c.Assert(err, jc.ErrorIsNil)
+	err = info.SetUnreachableMachines([]string{"1", "3"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.RestoreInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.RestoreInfoResult{
+		Status:              string(state.RestoreFinished),
+		UnreachableMachines: []string{"1", "3"},
+	})
+}
+
+func (s *backupsSuite) TestRestoreInfoNoRestore(c *gc.C) {
+	result, err := s.api.RestoreInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.RestoreInfoResult{
+		Status: string(state.UnknownRestoreStatus),
+	})
+}
@@ -5,6 +5,7 @@ package block
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -87,12 +88,30 @@ func convertBlock(b state.Block) params.BlockResult {
 
 // SwitchBlockOn implements Block.SwitchBlockOn().
 func (a *API) SwitchBlockOn(args params.BlockSwitchParams) params.ErrorResult {
-	err := a.access.SwitchBlockOn(state.ParseBlockType(args.Type), args.Message)
+	t := state.ParseBlockType(args.Type)
+	var err error
+	if args.Tag == "" {
+		err = a.access.SwitchBlockOn(t, args.Message)
+	} else {
+		var tag names.Tag
+		if tag, err = names.ParseTag(args.Tag); err == nil {
+			err = a.access.SwitchBlockOnForEntity(t, tag, args.Message)
+		}
+	}
 	return params.ErrorResult{Error: common.ServerError(err)}
 }
 
 // SwitchBlockOff implements Block.SwitchBlockOff().
 func (a *API) SwitchBlockOff(args params.BlockSwitchParams) params.ErrorResult {
-	err := a.access.SwitchBlockOff(state.ParseBlockType(args.Type))
+	t := state.ParseBlockType(args.Type)
+	var err error
+	if args.Tag == "" {
+		err = a.access.SwitchBlockOff(t)
+	} else {
+		var tag names.Tag
+		if tag, err = names.ParseTag(args.Tag); err == nil {
+			err = a.access.SwitchBlockOffForEntity(t, tag)
+		}
+	}
 	return params.ErrorResult{Error: common.ServerError(err)}
 }
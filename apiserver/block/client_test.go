@@ -79,3 +79,44 @@ func (s *blockSuite) TestSwitchBlockOff(c *gc.C) {
 	c.Assert(err.Error, gc.IsNil)
 	s.assertBlockList(c, 0)
 }
+
+func (s *blockSuite) TestSwitchBlockOnForEntity(c *gc.C) {
+	service := s.Factory.MakeService(c, nil)
+	on := params.BlockSwitchParams{
+		Type:    state.ChangeBlock.String(),
+		Message: "for TestSwitchBlockOnForEntity",
+		Tag:     service.Tag().String(),
+	}
+	err := s.api.SwitchBlockOn(on)
+	c.Assert(err.Error, gc.IsNil)
+	all, listErr := s.api.List()
+	c.Assert(listErr, jc.ErrorIsNil)
+	c.Assert(all.Results, gc.HasLen, 1)
+	c.Assert(all.Results[0].Result.Tag, gc.Equals, service.Tag().String())
+
+	// The environment is not blocked.
+	_, found, stateErr := s.State.GetBlockForType(state.ChangeBlock)
+	c.Assert(stateErr, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+
+	off := params.BlockSwitchParams{
+		Type: state.ChangeBlock.String(),
+		Tag:  service.Tag().String(),
+	}
+	err = s.api.SwitchBlockOff(off)
+	c.Assert(err.Error, gc.IsNil)
+	s.assertBlockList(c, 0)
+}
+
+func (s *blockSuite) TestSwitchBlockOnForInvalidEntity(c *gc.C) {
+	on := params.BlockSwitchParams{
+		Type: state.ChangeBlock.String(),
+		Tag:  "unit-mysql-0",
+	}
+	err := s.api.SwitchBlockOn(on)
+	c.Assert(err.Error, gc.ErrorMatches, "blocking unit mysql/0 not valid")
+	on.Tag = "invalid"
+	err = s.api.SwitchBlockOn(on)
+	c.Assert(err.Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+	s.assertBlockList(c, 0)
+}
@@ -3,12 +3,18 @@
 
 package block
 
-import "github.com/juju/juju/state"
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
 
 type blockAccess interface {
 	AllBlocks() ([]state.Block, error)
 	SwitchBlockOn(t state.BlockType, msg string) error
 	SwitchBlockOff(t state.BlockType) error
+	SwitchBlockOnForEntity(t state.BlockType, tag names.Tag, msg string) error
+	SwitchBlockOffForEntity(t state.BlockType, tag names.Tag) error
 }
 
 type stateShim struct {
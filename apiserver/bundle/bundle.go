@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundle provides the API through which charm bundles are
+// deployed by the API server.
+package bundle
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/charm.v5-unstable/charmrepo"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.bundle")
+
+func init() {
+	common.RegisterStandardFacade("Bundle", 1, NewAPI)
+}
+
+// charmStore is used to find the latest revisions of the charm store
+// charms in bundles.
+var charmStore charmrepo.Interface = charmrepo.LegacyStore
+
+// API implements the Bundle facade.
+type API struct {
+	st     *state.State
+	client *client.Client
+	check  *common.BlockChecker
+}
+
+// NewAPI returns a new Bundle API facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// The changes that deploy a bundle are made as they would be by
+	// a client, so that they are validated in the same way.
+	client, err := client.NewClient(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &API{
+		st:     st,
+		client: client,
+		check:  common.NewBlockChecker(st),
+	}, nil
+}
+
+// GetChanges returns the changes required to deploy the given bundle,
+// without making them.
+func (api *API) GetChanges(args params.BundleChangesParams) (params.BundleChangesResults, error) {
+	var results params.BundleChangesResults
+	changes, errs, err := api.plan(args.BundleDataYAML)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Errors = errs
+	for _, ch := range changes {
+		results.Changes = append(results.Changes, ch.BundleChange)
+	}
+	return results, nil
+}
+
+// Deploy deploys the given bundle. The bundle is verified, and its
+// charms and placements resolved, before any change is made to the
+// environment; nothing is changed if that fails. The changes are then
+// made in order, stopping at the first that fails, and the outcome of
+// each is reported. The changes already made when one fails are left
+// in place.
+func (api *API) Deploy(args params.BundleChangesParams) (params.BundleDeployResults, error) {
+	var results params.BundleDeployResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	changes, errs, err := api.plan(args.BundleDataYAML)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Errors = errs
+	values := make(map[string]string)
+	failed := false
+	for _, ch := range changes {
+		step := params.BundleStepResult{BundleChange: ch.BundleChange}
+		if !failed {
+			value, err := ch.apply(values)
+			if err != nil {
+				logger.Debugf("bundle change %s failed: %v", ch.Id, err)
+				step.Error = common.ServerError(err)
+				failed = true
+			} else {
+				values[ch.Id] = value
+				step.Done = true
+				step.Result = value
+			}
+		}
+		results.Steps = append(results.Steps, step)
+	}
+	return results, nil
+}
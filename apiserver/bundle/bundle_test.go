@@ -0,0 +1,266 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable"
+
+	"github.com/juju/juju/apiserver/bundle"
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type bundleSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	api    *bundle.API
+	bundle string
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+func (s *bundleSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = bundle.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	mysql := s.AddTestingCharm(c, "mysql")
+	s.bundle = fmt.Sprintf(`
+series: quantal
+services:
+    wordpress:
+        charm: %s
+        num_units: 1
+        to: ["lxc:0"]
+        options:
+            blog-title: my blog
+        annotations:
+            gui-x: "10"
+    mysql:
+        charm: %s
+        num_units: 1
+        to: ["0"]
+machines:
+    "0": {}
+relations:
+    - ["wordpress:db", "mysql:server"]
+`, wordpress.URL(), mysql.URL())
+}
+
+func (s *bundleSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := bundle.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *bundleSuite) TestGetChanges(c *gc.C) {
+	results, err := s.api.GetChanges(params.BundleChangesParams{BundleDataYAML: s.bundle})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, gc.HasLen, 0)
+
+	type step struct {
+		id, method string
+		requires   []string
+	}
+	var steps []step
+	for _, change := range results.Changes {
+		steps = append(steps, step{change.Id, change.Method, change.Requires})
+	}
+	c.Assert(steps, jc.DeepEquals, []step{
+		{"addCharm-0", "addCharm", nil},
+		{"deploy-1", "deploy", []string{"addCharm-0"}},
+		{"addCharm-2", "addCharm", nil},
+		{"deploy-3", "deploy", []string{"addCharm-2"}},
+		{"setAnnotations-4", "setAnnotations", []string{"deploy-3"}},
+		{"addMachine-5", "addMachine", nil},
+		{"addRelation-6", "addRelation", []string{"deploy-3", "deploy-1"}},
+		{"addUnit-mysql-0", "addUnit", []string{"deploy-1", "addMachine-5"}},
+		{"addMachine-8", "addMachine", []string{"addMachine-5"}},
+		{"addUnit-wordpress-0", "addUnit", []string{"deploy-3", "addMachine-8"}},
+	})
+	c.Assert(results.Changes[8].Description, gc.Equals, "add a new lxc container on new machine 0")
+	c.Assert(results.Changes[9].Description, gc.Equals, "add unit wordpress/0 to a new lxc container on new machine 0")
+
+	// Nothing has been changed.
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *bundleSuite) TestGetChangesInvalidBundle(c *gc.C) {
+	results, err := s.api.GetChanges(params.BundleChangesParams{BundleDataYAML: `
+services:
+    wordpress:
+        charm: local:quantal/wordpress-3
+relations:
+    - ["wordpress:db", "mysql:server"]
+`})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Changes, gc.HasLen, 0)
+	c.Assert(results.Errors, gc.Not(gc.HasLen), 0)
+
+	results, err = s.api.GetChanges(params.BundleChangesParams{BundleDataYAML: "services: ["})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, gc.HasLen, 1)
+}
+
+func (s *bundleSuite) TestDeploy(c *gc.C) {
+	results, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: s.bundle})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, gc.HasLen, 0)
+	c.Assert(results.Steps, gc.HasLen, 10)
+	for _, step := range results.Steps {
+		c.Check(step.Error, gc.IsNil)
+		c.Check(step.Done, jc.IsTrue)
+	}
+	c.Assert(results.Steps[9].Result, gc.Equals, "wordpress/0")
+
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := wordpress.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "my blog")
+	annotations, err := s.State.Annotations(wordpress)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, jc.DeepEquals, map[string]string{"gui-x": "10"})
+
+	s.assertUnitMachine(c, "mysql/0", "0")
+	s.assertUnitMachine(c, "wordpress/0", "0/lxc/0")
+
+	_, err = s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bundleSuite) assertUnitMachine(c *gc.C, unitName, machineId string) {
+	unit, err := s.State.Unit(unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	assigned, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assigned, gc.Equals, machineId)
+}
+
+func (s *bundleSuite) TestDeployServiceExists(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	results, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: s.bundle})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, jc.DeepEquals, []string{`service "mysql" already exists`})
+	c.Assert(results.Steps, gc.HasLen, 0)
+
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *bundleSuite) TestDeployStopsAtFailure(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	results, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: fmt.Sprintf(`
+series: quantal
+services:
+    dummy:
+        charm: %s
+        num_units: 1
+        options:
+            no-such-option: 42
+`, ch.URL())})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, gc.HasLen, 0)
+	c.Assert(results.Steps, gc.HasLen, 3)
+	c.Check(results.Steps[0].Done, jc.IsTrue)
+	c.Check(results.Steps[1].Done, jc.IsFalse)
+	c.Check(results.Steps[1].Error, gc.ErrorMatches, `.*unknown option "no-such-option"`)
+	c.Check(results.Steps[2].Done, jc.IsFalse)
+	c.Check(results.Steps[2].Error, gc.IsNil)
+
+	_, err = s.State.Service("dummy")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *bundleSuite) TestDeployLocalCharmNotFound(c *gc.C) {
+	results, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: `
+services:
+    dummy:
+        charm: local:quantal/dummy-42
+`})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, jc.DeepEquals, []string{
+		`cannot resolve charm for service "dummy": charm "local:quantal/dummy-42" not found`,
+	})
+}
+
+func (s *bundleSuite) TestExportBundle(c *gc.C) {
+	results, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: s.bundle})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Errors, gc.HasLen, 0)
+	mysql, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	bd, err := charm.ReadBundleData(strings.NewReader(result.BundleDataYAML))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(bd.Services, gc.HasLen, 2)
+	wordpress := bd.Services["wordpress"]
+	c.Check(wordpress.Charm, gc.Equals, "local:quantal/wordpress-3")
+	c.Check(wordpress.NumUnits, gc.Equals, 1)
+	c.Check(wordpress.To, jc.DeepEquals, []string{"lxc:0"})
+	c.Check(wordpress.Options, jc.DeepEquals, map[string]interface{}{"blog-title": "my blog"})
+	c.Check(wordpress.Annotations, jc.DeepEquals, map[string]string{"gui-x": "10"})
+	c.Check(bd.Services["mysql"].To, jc.DeepEquals, []string{"0"})
+	c.Check(bd.Services["mysql"].Constraints, gc.Equals, "mem=4096M")
+	c.Check(bd.Machines, gc.HasLen, 1)
+	c.Check(bd.Machines["0"], gc.NotNil)
+	c.Assert(bd.Relations, gc.HasLen, 1)
+	endpoints := append([]string(nil), bd.Relations[0]...)
+	sort.Strings(endpoints)
+	c.Check(endpoints, jc.DeepEquals, []string{"mysql:server", "wordpress:db"})
+}
+
+func (s *bundleSuite) TestExportBundleSubordinate(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("logging", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	bd, err := charm.ReadBundleData(strings.NewReader(result.BundleDataYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bd.Services["logging"].NumUnits, gc.Equals, 0)
+	c.Check(bd.Services["wordpress"].NumUnits, gc.Equals, 0)
+	c.Check(bd.Machines, gc.HasLen, 0)
+	c.Check(bd.Relations, gc.HasLen, 1)
+}
+
+func (s *bundleSuite) TestBlockDeploy(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockDeploy")
+	_, err := s.api.Deploy(params.BundleChangesParams{BundleDataYAML: s.bundle})
+	s.AssertBlocked(c, err, "TestBlockDeploy")
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,511 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/juju/charm.v5-unstable/charmrepo"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// change is one step of the deployment of a bundle.
+type change struct {
+	params.BundleChange
+
+	// apply makes the change, given the results of the steps made so
+	// far keyed by step id, and returns the result of the step.
+	apply func(results map[string]string) (string, error)
+}
+
+// planner works out the changes required to deploy a bundle.
+type planner struct {
+	api  *API
+	data *charm.BundleData
+
+	// series is the series of charms and machines that do not
+	// specify their own.
+	series string
+
+	// repo is used to find the latest revision of charm store charms.
+	repo charmrepo.Interface
+
+	changes []*change
+
+	// charms maps the charm URLs of the bundle to the ids of the
+	// steps that add them.
+	charms map[string]string
+
+	// services, machines and units map the service names, bundle
+	// machine ids and unit names of the bundle to the ids of the steps
+	// that add them.
+	services map[string]string
+	machines map[string]string
+	units    map[string]string
+}
+
+// plan verifies the YAML-encoded bundle data, and works out the changes
+// required to deploy it. If the bundle cannot be deployed, the reasons
+// why are returned in place of the changes; an error is only returned
+// if the environment cannot be examined.
+func (api *API) plan(data string) ([]*change, []string, error) {
+	bd, err := charm.ReadBundleData(strings.NewReader(data))
+	if err != nil {
+		return nil, []string{err.Error()}, nil
+	}
+	if err := bd.Verify(verifyConstraints); err != nil {
+		if verr, ok := err.(*charm.VerificationError); ok {
+			errs := make([]string, len(verr.Errors))
+			for i, err := range verr.Errors {
+				errs[i] = err.Error()
+			}
+			return nil, errs, nil
+		}
+		return nil, []string{err.Error()}, nil
+	}
+	envConfig, err := api.st.EnvironConfig()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	p := &planner{
+		api:      api,
+		data:     bd,
+		series:   bd.Series,
+		repo:     config.SpecializeCharmRepo(charmStore, envConfig),
+		charms:   make(map[string]string),
+		services: make(map[string]string),
+		machines: make(map[string]string),
+		units:    make(map[string]string),
+	}
+	if p.series == "" {
+		p.series = config.PreferredSeries(envConfig)
+	}
+	if errs, err := p.check(); err != nil || len(errs) > 0 {
+		return nil, errs, err
+	}
+	errs := p.addChanges()
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+	changes, err := sortChanges(p.changes)
+	if err != nil {
+		return nil, []string{err.Error()}, nil
+	}
+	return changes, nil, nil
+}
+
+func verifyConstraints(s string) error {
+	_, err := constraints.Parse(s)
+	return err
+}
+
+// check returns the reasons why the bundle cannot be deployed in the
+// current state of the environment.
+func (p *planner) check() ([]string, error) {
+	var errs []string
+	for _, name := range p.serviceNames() {
+		_, err := p.api.st.Service(name)
+		if err == nil {
+			errs = append(errs, fmt.Sprintf("service %q already exists", name))
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+	}
+	return errs, nil
+}
+
+func (p *planner) serviceNames() []string {
+	result := make([]string, 0, len(p.data.Services))
+	for name := range p.data.Services {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p *planner) machineIds() []string {
+	ids := make([]string, 0, len(p.data.Machines))
+	for id := range p.data.Machines {
+		ids = append(ids, id)
+	}
+	// Machine ids are verified to be numbers.
+	sort.Sort(machineIdsByNumber(ids))
+	return ids
+}
+
+type machineIdsByNumber []string
+
+func (ids machineIdsByNumber) Len() int      { return len(ids) }
+func (ids machineIdsByNumber) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+func (ids machineIdsByNumber) Less(i, j int) bool {
+	a, _ := strconv.Atoi(ids[i])
+	b, _ := strconv.Atoi(ids[j])
+	return a < b
+}
+
+// add records a new change of the given kind and returns its id.
+func (p *planner) add(method, description string, requires []string, apply func(map[string]string) (string, error)) string {
+	id := fmt.Sprintf("%s-%d", method, len(p.changes))
+	p.changes = append(p.changes, &change{
+		BundleChange: params.BundleChange{
+			Id:          id,
+			Method:      method,
+			Description: description,
+			Requires:    requires,
+		},
+		apply: apply,
+	})
+	return id
+}
+
+// addChanges records the changes that deploy the bundle, and returns
+// the reasons why any cannot be made.
+func (p *planner) addChanges() []string {
+	var errs []string
+	for _, name := range p.serviceNames() {
+		if err := p.addService(name, p.data.Services[name]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, id := range p.machineIds() {
+		p.addMachine(id, p.data.Machines[id])
+	}
+	for _, endpoints := range p.data.Relations {
+		p.addRelation(endpoints)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	// Units are added once every service and machine has been, so
+	// that they can be placed alongside any of them.
+	for _, name := range p.serviceNames() {
+		if err := p.addUnits(name, p.data.Services[name]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// resolveCharm returns the URL, including series and revision, of the
+// charm named in the bundle.
+func (p *planner) resolveCharm(name string) (*charm.URL, error) {
+	ref, err := charm.ParseReference(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	series := ref.Series
+	if series == "" {
+		series = p.series
+	}
+	curl, err := ref.URL(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if curl.Schema != "cs" {
+		// Local charms cannot be uploaded by the bundle, so
+		// they must already be in the environment.
+		if curl.Revision < 0 {
+			return nil, errors.Errorf("local charm URL %q must include revision", curl)
+		}
+		if _, err := p.api.st.Charm(curl); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return curl, nil
+	}
+	if curl.Revision >= 0 {
+		return curl, nil
+	}
+	revision, err := charmrepo.Latest(p.repo, curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return curl.WithRevision(revision), nil
+}
+
+// addCharm records a change adding the charm with the given URL to
+// the environment, unless one has already been recorded, and returns
+// the id of the change.
+func (p *planner) addCharm(curl *charm.URL) string {
+	if id, ok := p.charms[curl.String()]; ok {
+		return id
+	}
+	id := p.add("addCharm", fmt.Sprintf("add charm %s", curl), nil, func(map[string]string) (string, error) {
+		if curl.Schema != "cs" {
+			return curl.String(), nil
+		}
+		err := p.api.client.AddCharm(params.AddCharm{URL: curl.String()})
+		return curl.String(), errors.Trace(err)
+	})
+	p.charms[curl.String()] = id
+	return id
+}
+
+func (p *planner) addService(name string, spec *charm.ServiceSpec) error {
+	curl, err := p.resolveCharm(spec.Charm)
+	if err != nil {
+		return errors.Annotatef(err, "cannot resolve charm for service %q", name)
+	}
+	var configYAML string
+	if len(spec.Options) > 0 {
+		data, err := goyaml.Marshal(map[string]interface{}{name: spec.Options})
+		if err != nil {
+			return errors.Annotatef(err, "cannot marshal options for service %q", name)
+		}
+		configYAML = string(data)
+	}
+	cons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	charmId := p.addCharm(curl)
+	id := p.add("deploy", fmt.Sprintf("deploy service %s using %s", name, curl), []string{charmId}, func(map[string]string) (string, error) {
+		err := p.api.client.ServiceDeploy(params.ServiceDeploy{
+			ServiceName: name,
+			CharmUrl:    curl.String(),
+			ConfigYAML:  configYAML,
+			Constraints: cons,
+		})
+		return name, errors.Trace(err)
+	})
+	p.services[name] = id
+	if len(spec.Annotations) > 0 {
+		tag := names.NewServiceTag(name)
+		p.addAnnotations(id, fmt.Sprintf("service %s", name), spec.Annotations, func(map[string]string) names.Tag {
+			return tag
+		})
+	}
+	return nil
+}
+
+func (p *planner) addMachine(bundleId string, spec *charm.MachineSpec) {
+	if spec == nil {
+		spec = &charm.MachineSpec{}
+	}
+	series := spec.Series
+	if series == "" {
+		series = p.series
+	}
+	cons, _ := constraints.Parse(spec.Constraints)
+	id := p.add("addMachine", fmt.Sprintf("add new machine %s", bundleId), nil, func(map[string]string) (string, error) {
+		return p.addOneMachine(params.AddMachineParams{
+			Series:      series,
+			Constraints: cons,
+		})
+	})
+	p.machines[bundleId] = id
+	if len(spec.Annotations) > 0 {
+		p.addAnnotations(id, fmt.Sprintf("new machine %s", bundleId), spec.Annotations, func(results map[string]string) names.Tag {
+			return names.NewMachineTag(results[id])
+		})
+	}
+}
+
+// addOneMachine adds a machine, able to host units, with the given
+// parameters and returns its id.
+func (p *planner) addOneMachine(args params.AddMachineParams) (string, error) {
+	args.Jobs = []multiwatcher.MachineJob{multiwatcher.JobHostUnits}
+	results, err := p.api.client.AddMachinesV2(params.AddMachines{
+		MachineParams: []params.AddMachineParams{args},
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Machines) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Machines))
+	}
+	if err := results.Machines[0].Error; err != nil {
+		return "", err
+	}
+	return results.Machines[0].Machine, nil
+}
+
+func (p *planner) addRelation(endpoints []string) {
+	var requires []string
+	for _, ep := range endpoints {
+		service := strings.SplitN(ep, ":", 2)[0]
+		requires = append(requires, p.services[service])
+	}
+	description := fmt.Sprintf("add relation %s", strings.Join(endpoints, " - "))
+	p.add("addRelation", description, requires, func(map[string]string) (string, error) {
+		_, err := p.api.client.AddRelation(params.AddRelation{Endpoints: endpoints})
+		return "", errors.Trace(err)
+	})
+}
+
+func (p *planner) addAnnotations(requires, description string, annotations map[string]string, tag func(map[string]string) names.Tag) {
+	p.add("setAnnotations", "set annotations for "+description, []string{requires}, func(results map[string]string) (string, error) {
+		err := p.api.client.SetAnnotations(params.SetAnnotations{
+			Tag:   tag(results).String(),
+			Pairs: annotations,
+		})
+		return "", errors.Trace(err)
+	})
+}
+
+// addUnits records the changes adding the units of the service, placed
+// as the bundle requires.
+func (p *planner) addUnits(name string, spec *charm.ServiceSpec) error {
+	serviceId := p.services[name]
+	for i := 0; i < spec.NumUnits; i++ {
+		// Units without a placement of their own are placed as
+		// the last one given, or on new machines if none is.
+		placement := "new"
+		if len(spec.To) > i {
+			placement = spec.To[i]
+		} else if len(spec.To) > 0 {
+			placement = spec.To[len(spec.To)-1]
+		}
+		to, err := charm.ParsePlacement(placement)
+		if err != nil {
+			return errors.Annotatef(err, "invalid placement for service %q", name)
+		}
+		unitName := fmt.Sprintf("%s/%d", name, i)
+		requires := []string{serviceId}
+		// machine returns the id of the machine the unit is placed
+		// on, or an empty string for a new machine.
+		var machine func(results map[string]string) (string, error)
+		// where describes the machine.
+		var where string
+		switch {
+		case to.Service != "":
+			targetSpec, ok := p.data.Services[to.Service]
+			if !ok || targetSpec.NumUnits == 0 {
+				return errors.Errorf("cannot place unit %s with service %s: no units in bundle", unitName, to.Service)
+			}
+			unit := to.Unit
+			if unit < 0 {
+				// Units are spread across those of the
+				// target service.
+				unit = i % targetSpec.NumUnits
+			}
+			target := fmt.Sprintf("%s/%d", to.Service, unit)
+			targetId, ok := p.units[target]
+			if !ok {
+				if to.Service == name || targetSpec.NumUnits <= unit {
+					return errors.Errorf("cannot place unit %s with unit %s: unit not in bundle", unitName, target)
+				}
+				// The target service's units are added later;
+				// the changes are sorted before they are made.
+				targetId = p.unitId(to.Service, unit)
+			}
+			requires = append(requires, targetId)
+			where = "the machine of unit " + target
+			machine = func(results map[string]string) (string, error) {
+				unit, err := p.api.st.Unit(results[targetId])
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				return unit.AssignedMachineId()
+			}
+		case to.Machine == "new":
+			where = "a new machine"
+			machine = func(map[string]string) (string, error) { return "", nil }
+		default:
+			machineId := p.machines[to.Machine]
+			requires = append(requires, machineId)
+			where = "new machine " + to.Machine
+			machine = func(results map[string]string) (string, error) {
+				return results[machineId], nil
+			}
+		}
+		if to.ContainerType != "" {
+			containerType, err := instance.ParseContainerType(to.ContainerType)
+			if err != nil {
+				return errors.Annotatef(err, "invalid placement for service %q", name)
+			}
+			host := machine
+			where = fmt.Sprintf("a new %s container on %s", containerType, where)
+			containerId := p.add("addMachine", "add "+where, requires[1:], func(results map[string]string) (string, error) {
+				parentId, err := host(results)
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				return p.addOneMachine(params.AddMachineParams{
+					Series:        p.series,
+					ContainerType: containerType,
+					ParentId:      parentId,
+				})
+			})
+			requires = []string{serviceId, containerId}
+			machine = func(results map[string]string) (string, error) {
+				return results[containerId], nil
+			}
+		}
+		id := p.unitId(name, i)
+		p.changes = append(p.changes, &change{
+			BundleChange: params.BundleChange{
+				Id:          id,
+				Method:      "addUnit",
+				Description: fmt.Sprintf("add unit %s to %s", unitName, where),
+				Requires:    requires,
+			},
+			apply: func(results map[string]string) (string, error) {
+				machineId, err := machine(results)
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				result, err := p.api.client.AddServiceUnits(params.AddServiceUnits{
+					ServiceName:   name,
+					NumUnits:      1,
+					ToMachineSpec: machineId,
+				})
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				return result.Units[0], nil
+			},
+		})
+		p.units[unitName] = id
+	}
+	return nil
+}
+
+// unitId returns the id of the change adding the unit with the given
+// index to the named service.
+func (p *planner) unitId(service string, index int) string {
+	return fmt.Sprintf("addUnit-%s-%d", service, index)
+}
+
+// sortChanges orders the changes so that each comes after those it
+// requires, keeping the changes in their original order otherwise.
+func sortChanges(changes []*change) ([]*change, error) {
+	sorted := make([]*change, 0, len(changes))
+	done := make(map[string]bool)
+	for len(sorted) < len(changes) {
+		progress := false
+		for _, ch := range changes {
+			if done[ch.Id] {
+				continue
+			}
+			ready := true
+			for _, id := range ch.Requires {
+				if !done[id] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, ch)
+				done[ch.Id] = true
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, errors.New("cannot deploy bundle: units are placed in a cycle")
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5-unstable"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// ExportBundle returns the YAML-encoded bundle that recreates the
+// services of the environment, with their charms, config, constraints
+// and annotations, the relations between them and the machines their
+// units are placed on.
+//
+// Units in nested containers are placed in a container of the same
+// type directly on the top level machine, as bundle placements cannot
+// express deeper nesting. Local charms are referred to by URL, and must
+// be made available to the environment the bundle is deployed to.
+func (api *API) ExportBundle() (params.BundleExportResult, error) {
+	var result params.BundleExportResult
+	bd, err := api.bundleData()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := bd.Verify(verifyConstraints); err != nil {
+		return result, errors.Annotate(err, "cannot export bundle")
+	}
+	data, err := goyaml.Marshal(bd)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.BundleDataYAML = string(data)
+	return result, nil
+}
+
+// exporter builds the bundle data describing the environment.
+type exporter struct {
+	st   *state.State
+	data *charm.BundleData
+}
+
+func (api *API) bundleData() (*charm.BundleData, error) {
+	envConfig, err := api.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e := &exporter{
+		st: api.st,
+		data: &charm.BundleData{
+			Series:   config.PreferredSeries(envConfig),
+			Services: make(map[string]*charm.ServiceSpec),
+		},
+	}
+	services, err := api.st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range services {
+		if svc.Life() != state.Alive {
+			continue
+		}
+		if err := e.addService(svc); err != nil {
+			return nil, errors.Annotatef(err, "cannot export service %q", svc.Name())
+		}
+	}
+	if err := e.addRelations(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e.data, nil
+}
+
+func (e *exporter) addService(svc *state.Service) error {
+	curl, _ := svc.CharmURL()
+	spec := &charm.ServiceSpec{
+		Charm: curl.String(),
+	}
+	settings, err := svc.ConfigSettings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(settings) > 0 {
+		spec.Options = map[string]interface{}(settings)
+	}
+	annotations, err := e.st.Annotations(svc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	if svc.IsPrincipal() {
+		cons, err := svc.Constraints()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		spec.Constraints = cons.String()
+		if err := e.addUnits(svc, spec); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	e.data.Services[svc.Name()] = spec
+	return nil
+}
+
+// addUnits records the number of units of the principal service, and
+// the placement of each, adding the machines they are placed on.
+func (e *exporter) addUnits(svc *state.Service, spec *charm.ServiceSpec) error {
+	units, err := svc.AllUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sort.Sort(unitsByNumber(units))
+	placed := false
+	for _, unit := range units {
+		if unit.Life() != state.Alive {
+			continue
+		}
+		placement := "new"
+		machineId, err := unit.AssignedMachineId()
+		if err == nil {
+			placement, err = e.placement(machineId)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			placed = true
+		} else if !errors.IsNotAssigned(err) {
+			return errors.Trace(err)
+		}
+		spec.NumUnits++
+		spec.To = append(spec.To, placement)
+	}
+	if !placed {
+		spec.To = nil
+	}
+	return nil
+}
+
+// placement returns the bundle placement of a unit assigned to the
+// given machine, adding the top level machine to the bundle.
+func (e *exporter) placement(machineId string) (string, error) {
+	topId := state.TopParentId(machineId)
+	if err := e.addMachine(topId); err != nil {
+		return "", errors.Trace(err)
+	}
+	if containerType := state.ContainerTypeFromId(machineId); containerType != "" {
+		return fmt.Sprintf("%s:%s", containerType, topId), nil
+	}
+	return topId, nil
+}
+
+func (e *exporter) addMachine(id string) error {
+	if _, ok := e.data.Machines[id]; ok {
+		return nil
+	}
+	m, err := e.st.Machine(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spec := &charm.MachineSpec{}
+	if series := m.Series(); series != e.data.Series {
+		spec.Series = series
+	}
+	cons, err := m.Constraints()
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	spec.Constraints = cons.String()
+	annotations, err := e.st.Annotations(m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	if e.data.Machines == nil {
+		e.data.Machines = make(map[string]*charm.MachineSpec)
+	}
+	e.data.Machines[id] = spec
+	return nil
+}
+
+// addRelations records the relations between the exported services.
+// Peer relations are established by deploying the services, so they
+// are left out.
+func (e *exporter) addRelations() error {
+	relations, err := e.st.AllRelations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rel := range relations {
+		if rel.Life() != state.Alive {
+			continue
+		}
+		eps := rel.Endpoints()
+		if len(eps) != 2 {
+			continue
+		}
+		endpoints := make([]string, len(eps))
+		for i, ep := range eps {
+			if _, ok := e.data.Services[ep.ServiceName]; !ok {
+				endpoints = nil
+				break
+			}
+			endpoints[i] = ep.String()
+		}
+		if endpoints != nil {
+			e.data.Relations = append(e.data.Relations, endpoints)
+		}
+	}
+	sort.Sort(relationsByEndpoints(e.data.Relations))
+	return nil
+}
+
+type unitsByNumber []*state.Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+// unitNumber returns the number of the unit with the given name.
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
+
+type relationsByEndpoints [][]string
+
+func (r relationsByEndpoints) Len() int      { return len(r) }
+func (r relationsByEndpoints) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r relationsByEndpoints) Less(i, j int) bool {
+	return strings.Join(r[i], " ") < strings.Join(r[j], " ")
+}
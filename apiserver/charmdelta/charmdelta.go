@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmdelta implements the delta uploads of local charm
+// archives. When a local charm is upgraded, the client uploads only
+// the archive entries that differ from the charm already stored in the
+// environment, along with a manifest describing the complete archive;
+// the API server rebuilds the full archive from the stored charm and
+// the delta.
+package charmdelta
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+)
+
+// ManifestName is the name of the entry of a delta archive that holds
+// its manifest.
+const ManifestName = ".juju-delta-manifest.json"
+
+// Manifest describes the complete charm archive a delta archive is
+// built from.
+type Manifest struct {
+	// Entries holds the entries of the complete archive, in order.
+	Entries []ManifestEntry `json:"entries"`
+}
+
+// ManifestEntry describes an entry of a complete charm archive.
+type ManifestEntry struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+}
+
+// Digests returns the digests of the entries of the given charm
+// archive, keyed by entry name. The digest of an entry is the hex
+// encoded SHA256 hash of its mode followed by its contents, so that
+// entries whose permissions changed are also uploaded again.
+func Digests(zipr *zip.Reader) (map[string]string, error) {
+	digests := make(map[string]string)
+	for _, f := range zipr.File {
+		digest, err := entryDigest(f)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read %q", f.Name)
+		}
+		digests[f.Name] = digest
+	}
+	return digests, nil
+}
+
+func entryDigest(f *zip.File) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%o\n", f.Mode())
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Write writes to w a delta archive holding the entries of the given
+// charm archive whose digests differ from the given digests of the
+// base charm archive.
+func Write(w io.Writer, archive *zip.Reader, baseDigests map[string]string) error {
+	digests, err := Digests(archive)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var manifest Manifest
+	zipw := zip.NewWriter(w)
+	for _, f := range archive.File {
+		if f.Name == ManifestName {
+			return errors.Errorf("charm archive has reserved entry %q", ManifestName)
+		}
+		digest := digests[f.Name]
+		manifest.Entries = append(manifest.Entries, ManifestEntry{
+			Name:   f.Name,
+			Digest: digest,
+		})
+		if baseDigests[f.Name] == digest {
+			continue
+		}
+		if err := copyEntry(zipw, f); err != nil {
+			return errors.Annotatef(err, "cannot copy %q", f.Name)
+		}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	mw, err := zipw.Create(ManifestName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := mw.Write(data); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(zipw.Close())
+}
+
+// Apply writes to w the complete charm archive described by the
+// manifest of the given delta archive, taking the entries that are
+// not in the delta from the base charm archive. The digest of every
+// entry is checked against the manifest.
+func Apply(w io.Writer, base, delta *zip.Reader) error {
+	manifest, err := readManifest(delta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	entries := make(map[string]*zip.File)
+	for _, f := range base.File {
+		entries[f.Name] = f
+	}
+	for _, f := range delta.File {
+		if f.Name != ManifestName {
+			entries[f.Name] = f
+		}
+	}
+	zipw := zip.NewWriter(w)
+	for _, entry := range manifest.Entries {
+		f, ok := entries[entry.Name]
+		if !ok {
+			return errors.NotFoundf("charm archive entry %q", entry.Name)
+		}
+		digest, err := entryDigest(f)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read %q", f.Name)
+		}
+		if digest != entry.Digest {
+			return errors.Errorf("digest mismatch for charm archive entry %q", entry.Name)
+		}
+		if err := copyEntry(zipw, f); err != nil {
+			return errors.Annotatef(err, "cannot copy %q", f.Name)
+		}
+	}
+	return errors.Trace(zipw.Close())
+}
+
+func readManifest(delta *zip.Reader) (*Manifest, error) {
+	for _, f := range delta.File {
+		if f.Name != ManifestName {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var manifest Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, errors.Annotate(err, "cannot parse delta manifest")
+		}
+		return &manifest, nil
+	}
+	return nil, errors.NotFoundf("delta manifest")
+}
+
+// copyEntry copies the given archive entry, with its header, to zipw.
+func copyEntry(zipw *zip.Writer, f *zip.File) error {
+	header := f.FileHeader
+	w, err := zipw.CreateHeader(&header)
+	if err != nil {
+		return err
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmdelta_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/charmdelta"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type charmDeltaSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&charmDeltaSuite{})
+
+type entry struct {
+	name     string
+	mode     os.FileMode
+	contents string
+}
+
+func makeArchive(c *gc.C, entries ...entry) *zip.Reader {
+	var buf bytes.Buffer
+	zipw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(e.mode)
+		w, err := zipw.CreateHeader(header)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = w.Write([]byte(e.contents))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(zipw.Close(), jc.ErrorIsNil)
+	zipr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+	return zipr
+}
+
+func readArchive(c *gc.C, data []byte) []entry {
+	zipr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	var entries []entry
+	for _, f := range zipr.File {
+		r, err := f.Open()
+		c.Assert(err, jc.ErrorIsNil)
+		contents, err := ioutil.ReadAll(r)
+		r.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		entries = append(entries, entry{f.Name, f.Mode(), string(contents)})
+	}
+	return entries
+}
+
+func (s *charmDeltaSuite) TestDigestsIncludeMode(c *gc.C) {
+	digests, err := charmdelta.Digests(makeArchive(c,
+		entry{"hooks/install", 0755, "#!/bin/sh"},
+		entry{"hooks/start", 0644, "#!/bin/sh"},
+	))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(digests, gc.HasLen, 2)
+	c.Assert(digests["hooks/install"], gc.Not(gc.Equals), digests["hooks/start"])
+}
+
+func (s *charmDeltaSuite) TestWriteAndApply(c *gc.C) {
+	base := makeArchive(c,
+		entry{"metadata.yaml", 0644, "name: dummy"},
+		entry{"hooks/install", 0644, "#!/bin/sh"},
+		entry{"revision", 0644, "1"},
+		entry{"removed", 0644, "gone"},
+	)
+	baseDigests, err := charmdelta.Digests(base)
+	c.Assert(err, jc.ErrorIsNil)
+	expected := []entry{
+		{"metadata.yaml", 0644, "name: dummy"},
+		{"hooks/install", 0755, "#!/bin/sh"},
+		{"revision", 0644, "2"},
+		{"added", 0644, "new"},
+	}
+	changed := makeArchive(c, expected...)
+
+	var delta bytes.Buffer
+	err = charmdelta.Write(&delta, changed, baseDigests)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the changed entries, and the manifest, are in the delta.
+	var names []string
+	for _, e := range readArchive(c, delta.Bytes()) {
+		names = append(names, e.name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"hooks/install", "revision", "added", charmdelta.ManifestName,
+	})
+
+	deltar, err := zip.NewReader(bytes.NewReader(delta.Bytes()), int64(delta.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+	var result bytes.Buffer
+	err = charmdelta.Apply(&result, base, deltar)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readArchive(c, result.Bytes()), jc.DeepEquals, expected)
+}
+
+func (s *charmDeltaSuite) TestApplyChecksDigests(c *gc.C) {
+	base := makeArchive(c, entry{"metadata.yaml", 0644, "name: dummy"})
+	changed := makeArchive(c, entry{"metadata.yaml", 0644, "name: dummy"})
+	var delta bytes.Buffer
+	err := charmdelta.Write(&delta, changed, map[string]string{
+		"metadata.yaml": "",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	deltar, err := zip.NewReader(bytes.NewReader(delta.Bytes()), int64(delta.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The delta holds the entry, so it applies to any base.
+	other := makeArchive(c, entry{"metadata.yaml", 0644, "name: other"})
+	err = charmdelta.Apply(ioutil.Discard, other, deltar)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An entry missing from the delta must match in the base.
+	baseDigests, err := charmdelta.Digests(base)
+	c.Assert(err, jc.ErrorIsNil)
+	delta.Reset()
+	err = charmdelta.Write(&delta, changed, baseDigests)
+	c.Assert(err, jc.ErrorIsNil)
+	deltar, err = zip.NewReader(bytes.NewReader(delta.Bytes()), int64(delta.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+	err = charmdelta.Apply(ioutil.Discard, other, deltar)
+	c.Assert(err, gc.ErrorMatches, `digest mismatch for charm archive entry "metadata.yaml"`)
+}
+
+func (s *charmDeltaSuite) TestApplyMissingEntry(c *gc.C) {
+	changed := makeArchive(c, entry{"metadata.yaml", 0644, "name: dummy"})
+	digests, err := charmdelta.Digests(changed)
+	c.Assert(err, jc.ErrorIsNil)
+	var delta bytes.Buffer
+	err = charmdelta.Write(&delta, changed, digests)
+	c.Assert(err, jc.ErrorIsNil)
+	deltar, err := zip.NewReader(bytes.NewReader(delta.Bytes()), int64(delta.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = charmdelta.Apply(ioutil.Discard, makeArchive(c), deltar)
+	c.Assert(err, gc.ErrorMatches, `charm archive entry "metadata.yaml" not found`)
+}
+
+func (s *charmDeltaSuite) TestApplyRequiresManifest(c *gc.C) {
+	err := charmdelta.Apply(ioutil.Discard, makeArchive(c), makeArchive(c))
+	c.Assert(err, gc.ErrorMatches, `delta manifest not found`)
+}
@@ -25,6 +25,7 @@ import (
 	ziputil "github.com/juju/utils/zip"
 	"gopkg.in/juju/charm.v5-unstable"
 
+	"github.com/juju/juju/apiserver/charmdelta"
 	"github.com/juju/juju/apiserver/client"
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
@@ -53,7 +54,7 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "POST":
 		if err := stateWrapper.authenticateUser(r); err != nil {
-			h.authError(w, h)
+			h.userAuthError(w, h, err)
 			return
 		}
 		// Add a local charm to the store provider.
@@ -67,7 +68,8 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		// Retrieve or list charm files.
 		// Requires "url" (charm URL) and an optional "file" (the path to the
-		// charm file) to be included in the query.
+		// charm file) or "digests" (to list the digests of the charm
+		// files) to be included in the query.
 		if charmArchivePath, filePath, err := h.processGet(r, stateWrapper.state); err != nil {
 			// An error occurred retrieving the charm bundle.
 			if errors.IsNotFound(err) {
@@ -75,6 +77,9 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			} else {
 				h.sendError(w, http.StatusBadRequest, err.Error())
 			}
+		} else if r.URL.Query().Get("digests") != "" {
+			// The client requested the digests of the charm files.
+			sendBundleContent(w, r, charmArchivePath, h.digestsSender)
 		} else if filePath == "" {
 			// The client requested the list of charm files.
 			sendBundleContent(w, r, charmArchivePath, h.manifestSender)
@@ -129,6 +134,28 @@ func (h *charmsHandler) manifestSender(w http.ResponseWriter, r *http.Request, b
 	h.sendJSON(w, http.StatusOK, &params.CharmsResponse{Files: manifest.SortedValues()})
 }
 
+// digestsSender sends a JSON-encoded response to the client including
+// the digests of the entries of the charm archive, as used to upload
+// only the changed files of a charm.
+func (h *charmsHandler) digestsSender(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive) {
+	zipReader, err := zip.OpenReader(bundle.Path)
+	if err != nil {
+		http.Error(
+			w, fmt.Sprintf("unable to read charm: %v", err),
+			http.StatusInternalServerError)
+		return
+	}
+	defer zipReader.Close()
+	digests, err := charmdelta.Digests(&zipReader.Reader)
+	if err != nil {
+		http.Error(
+			w, fmt.Sprintf("unable to read archive in %q: %v", bundle.Path, err),
+			http.StatusInternalServerError)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, &params.CharmsResponse{Digests: digests})
+}
+
 // archiveEntrySender returns a bundleContentSenderFunc which is responsible for
 // sending the contents of filePath included in the given charm bundle. If filePath
 // does not identify a file or a symlink, a 403 forbidden error is returned.
@@ -211,11 +238,22 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 	if _, err := io.Copy(tempFile, r.Body); err != nil {
 		return nil, fmt.Errorf("error processing file upload: %v", err)
 	}
-	err = h.processUploadedArchive(tempFile.Name())
-	if err != nil {
+	archivePath := tempFile.Name()
+	if deltaFrom := query.Get("delta-from"); deltaFrom != "" {
+		// Only the changed files were uploaded; rebuild the
+		// complete archive from the charm they were changed from.
+		baseURL, err := charm.ParseURL(deltaFrom)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot parse delta-from charm URL")
+		}
+		if archivePath, err = h.applyDelta(st, baseURL, tempFile.Name()); err != nil {
+			return nil, errors.Annotate(err, "cannot apply charm delta")
+		}
+		defer os.Remove(archivePath)
+	} else if err := h.processUploadedArchive(archivePath); err != nil {
 		return nil, err
 	}
-	archive, err := charm.ReadCharmArchive(tempFile.Name())
+	archive, err := charm.ReadCharmArchive(archivePath)
 	if err != nil {
 		return nil, fmt.Errorf("invalid charm archive: %v", err)
 	}
@@ -240,6 +278,41 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 	return preparedURL, nil
 }
 
+// applyDelta rebuilds the complete charm archive from the delta
+// archive at deltaPath and the archive of the charm with the given URL,
+// returning the path of a temporary file holding it.
+func (h *charmsHandler) applyDelta(st *state.State, baseURL *charm.URL, deltaPath string) (_ string, err error) {
+	basePath, err := h.cachedCharmArchive(st, baseURL)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	base, err := zip.OpenReader(basePath)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot open base charm archive")
+	}
+	defer base.Close()
+	delta, err := zip.OpenReader(deltaPath)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot open delta archive")
+	}
+	defer delta.Close()
+
+	tempFile, err := ioutil.TempFile("", "charm")
+	if err != nil {
+		return "", errors.Annotate(err, "cannot create temp file")
+	}
+	defer tempFile.Close()
+	defer func() {
+		if err != nil {
+			os.Remove(tempFile.Name())
+		}
+	}()
+	if err := charmdelta.Apply(tempFile, &base.Reader, &delta.Reader); err != nil {
+		return "", errors.Trace(err)
+	}
+	return tempFile.Name(), nil
+}
+
 // processUploadedArchive opens the given charm archive from path,
 // inspects it to see if it has all files at the root of the archive
 // or it has subdirs. It repackages the archive so it has all the
@@ -392,20 +465,31 @@ func (h *charmsHandler) processGet(r *http.Request, st *state.State) (string, st
 		filePath = path.Clean(file)
 	}
 
+	charmArchivePath, err := h.cachedCharmArchive(st, curl)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return charmArchivePath, filePath, nil
+}
+
+// cachedCharmArchive returns the path of the archive of the charm with
+// the given URL in the charms cache, downloading it from environment
+// storage if it is not cached yet.
+func (h *charmsHandler) cachedCharmArchive(st *state.State, curl *charm.URL) (string, error) {
 	// Prepare the bundle directories.
-	name := charm.Quote(curlString)
+	name := charm.Quote(curl.String())
 	charmArchivePath := filepath.Join(h.dataDir, "charm-get-cache", name+".zip")
 
 	// Check if the charm archive is already in the cache.
 	if _, err := os.Stat(charmArchivePath); os.IsNotExist(err) {
 		// Download the charm archive and save it to the cache.
 		if err = h.downloadCharm(st, curl, charmArchivePath); err != nil {
-			return "", "", errors.Annotate(err, "unable to retrieve and save the charm")
+			return "", errors.Annotate(err, "unable to retrieve and save the charm")
 		}
 	} else if err != nil {
-		return "", "", errors.Annotate(err, "cannot access the charms cache")
+		return "", errors.Annotate(err, "cannot access the charms cache")
 	}
-	return charmArchivePath, filePath, nil
+	return charmArchivePath, nil
 }
 
 // downloadCharm downloads the given charm name from the provider storage and
@@ -4,6 +4,8 @@
 package charms
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5-unstable"
@@ -73,7 +75,9 @@ func (a *API) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
 
 // List returns a list of charm URLs currently in the state.
 // If supplied parameter contains any names, the result will be filtered
-// to return only the charms with supplied names.
+// to return only the charms with supplied names. The charm URLs are
+// sorted, and the supplied page parameters may limit the result to a
+// page of them.
 func (a *API) List(args params.CharmsList) (params.CharmsListResult, error) {
 	charms, err := a.access.AllCharms()
 	if err != nil {
@@ -92,5 +96,13 @@ func (a *API) List(args params.CharmsList) (params.CharmsListResult, error) {
 		}
 		charmURLs = append(charmURLs, charmURL.String())
 	}
-	return params.CharmsListResult{CharmURLs: charmURLs}, nil
+	sort.Strings(charmURLs)
+	start, end, continuation, err := common.Page(charmURLs, args.ListPage)
+	if err != nil {
+		return params.CharmsListResult{}, errors.Trace(err)
+	}
+	return params.CharmsListResult{
+		CharmURLs:    charmURLs[start:end],
+		Continuation: continuation,
+	}, nil
 }
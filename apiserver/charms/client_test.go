@@ -146,6 +146,24 @@ func (s *charmsSuite) TestListCharmsFilteredOnly(c *gc.C) {
 	s.assertListCharms(c, []string{"dummy", "wordpress"}, []string{"dummy"}, []string{"local:quantal/dummy-1"})
 }
 
+func (s *charmsSuite) TestListCharmsPages(c *gc.C) {
+	for _, aCharm := range []string{"wordpress", "dummy", "mysql"} {
+		s.AddTestingCharm(c, aCharm)
+	}
+	found, err := s.api.List(params.CharmsList{ListPage: params.ListPage{Limit: 2}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(found.CharmURLs, jc.DeepEquals, []string{"local:quantal/dummy-1", "local:quantal/mysql-1"})
+	c.Assert(found.Continuation, gc.Not(gc.Equals), "")
+
+	found, err = s.api.List(params.CharmsList{ListPage: params.ListPage{
+		Limit:        2,
+		Continuation: found.Continuation,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(found.CharmURLs, jc.DeepEquals, []string{"local:quantal/wordpress-3"})
+	c.Assert(found.Continuation, gc.Equals, "")
+}
+
 func (s *charmsSuite) assertListCharms(c *gc.C, someCharms, args, expected []string) {
 	for _, aCharm := range someCharms {
 		s.AddTestingCharm(c, aCharm)
@@ -4,6 +4,7 @@
 package apiserver_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -14,11 +15,13 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5-unstable"
 
+	"github.com/juju/juju/apiserver/charmdelta"
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -87,6 +90,24 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
 }
 
+func (s *charmsSuite) TestUploadRefusesReadOnlyUser(c *gc.C) {
+	s.useReadOnlyUser(c)
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	resp, err := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+
+	_, err = s.State.Charm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *charmsSuite) TestGETAllowsReadOnlyUser(c *gc.C) {
+	s.useReadOnlyUser(c)
+	resp, err := s.authRequest(c, "GET", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -177,6 +198,77 @@ func (s *charmsSuite) TestUploadRespectsLocalRevision(c *gc.C) {
 	c.Assert(downloadedSHA256, gc.Equals, expectedSHA256)
 }
 
+func (s *charmsSuite) TestUploadSharesIdenticalArchives(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.uploadRequest(c, s.charmsURI(c, "?series=trusty"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both charms are stored under the hash of their archive.
+	quantal, err := s.State.Charm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	trusty, err := s.State.Charm(charm.MustParseURL("local:trusty/dummy-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quantal.StoragePath(), gc.Equals, "charms/sha256-"+quantal.BundleSha256())
+	c.Assert(trusty.StoragePath(), gc.Equals, quantal.StoragePath())
+}
+
+func (s *charmsSuite) TestUploadDelta(c *gc.C) {
+	// Add the dummy charm, and get the digests of its files.
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&digests=1")
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	baseDigests := jsonResponse(c, body).Digests
+	c.Assert(baseDigests, gc.Not(gc.HasLen), 0)
+
+	// Change a file, and upload only the changed files.
+	dir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
+	err = ioutil.WriteFile(filepath.Join(dir.Path, "utils.js"), []byte("// blah blah"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	dir.SetDiskRevision(2)
+	var buffer bytes.Buffer
+	err = dir.ArchiveTo(&buffer)
+	c.Assert(err, jc.ErrorIsNil)
+	changed, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+	deltaPath := filepath.Join(c.MkDir(), "delta.zip")
+	deltaFile, err := os.Create(deltaPath)
+	c.Assert(err, jc.ErrorIsNil)
+	err = charmdelta.Write(deltaFile, changed, baseDigests)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deltaFile.Close(), jc.ErrorIsNil)
+
+	resp, err = s.uploadRequest(c, s.charmsURI(c, "?series=quantal&delta-from=local:quantal/dummy-1"), true, deltaPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUploadResponse(c, resp, "local:quantal/dummy-2")
+
+	// The stored charm is the complete changed charm.
+	sch, err := s.State.Charm(charm.MustParseURL("local:quantal/dummy-2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sch.Meta().Name, gc.Equals, "dummy")
+	uri = s.charmsURI(c, "?url=local:quantal/dummy-2&file=utils.js")
+	resp, err = s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertGetFileResponse(c, resp, "// blah blah", "application/javascript")
+	uri = s.charmsURI(c, "?url=local:quantal/dummy-2&file=metadata.yaml")
+	resp, err = s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resp.Body.Close()
+}
+
+func (s *charmsSuite) TestUploadDeltaFailsWithMissingBase(c *gc.C) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	resp, err := s.uploadRequest(c, s.charmsURI(c, "?series=quantal&delta-from=local:quantal/dummy-1"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `cannot apply charm delta: unable to retrieve and save the charm: cannot get charm from state: charm "local:quantal/dummy-1" not found`)
+}
+
 func (s *charmsSuite) TestUploadAllowsTopLevelPath(c *gc.C) {
 	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
 	// Backwards compatibility check, that we can upload charms to
@@ -446,6 +538,33 @@ func (s *charmsSuite) TestGetReturnsManifest(c *gc.C) {
 	c.Assert(ctype, gc.Equals, apihttp.CTypeJSON)
 }
 
+func (s *charmsSuite) TestGetReturnsDigests(c *gc.C) {
+	// Add the dummy charm.
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := s.uploadRequest(
+		c, s.charmsURI(c, "?series=quantal"), true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	uri := s.charmsURI(c, "?url=local:quantal/dummy-1&digests=1")
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	charmResponse := jsonResponse(c, body)
+	c.Check(charmResponse.Error, gc.Equals, "")
+
+	// The digests are those of the files of the stored archive.
+	uri = s.charmsURI(c, "?url=local:quantal/dummy-1&file=*")
+	resp, err = s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	archive := assertResponse(c, resp, http.StatusOK, "application/zip")
+	zipr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	c.Assert(err, jc.ErrorIsNil)
+	expected, err := charmdelta.Digests(zipr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(charmResponse.Digests, jc.DeepEquals, expected)
+	c.Check(expected["metadata.yaml"], gc.Not(gc.Equals), "")
+}
+
 func (s *charmsSuite) TestGetUsesCache(c *gc.C) {
 	// Add a fake charm archive in the cache directory.
 	cacheDir := filepath.Join(s.DataDir(), "charm-get-cache")
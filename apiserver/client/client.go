@@ -95,14 +95,14 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 // (Deprecated) Use NewServiceSetForClientAPI instead, to preserve values set to
 // an empty string, and use ServiceUnset to unset values.
 func (c *Client) ServiceSet(p params.ServiceSet) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(p.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
 	}
-	return serviceSetSettingsStrings(svc, p.Options)
+	return serviceSetSettingsStrings(svc, p.Options, p.ConfigRevision)
 }
 
 // NewServiceSetForClientAPI implements the server side of
@@ -118,13 +118,13 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 	if err != nil {
 		return err
 	}
-	return newServiceSetSettingsStringsForClientAPI(svc, p.Options)
+	return newServiceSetSettingsStringsForClientAPI(svc, p.Options, p.ConfigRevision)
 }
 
 // ServiceUnset implements the server side of Client.ServiceUnset.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnset(p params.ServiceUnset) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(p.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
@@ -141,7 +141,7 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(p.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
@@ -172,7 +172,7 @@ func (c *Client) ServiceCharmRelations(p params.ServiceCharmRelations) (params.S
 
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(unitServiceTags(p.UnitName)...); err != nil {
 		return errors.Trace(err)
 	}
 	unit, err := c.api.state.Unit(p.UnitName)
@@ -182,6 +182,42 @@ func (c *Client) Resolved(p params.Resolved) error {
 	return unit.Resolve(p.Retry)
 }
 
+// serviceTags returns the tags of the named services, for checking the
+// blocks in place for them. Invalid names are skipped, since no service
+// can have them.
+func serviceTags(serviceNames ...string) []names.Tag {
+	tags := make([]names.Tag, 0, len(serviceNames))
+	for _, name := range serviceNames {
+		if names.IsValidService(name) {
+			tags = append(tags, names.NewServiceTag(name))
+		}
+	}
+	return tags
+}
+
+// unitServiceTags returns the tags of the services of the named units,
+// for checking the blocks in place for them.
+func unitServiceTags(unitNames ...string) []names.Tag {
+	serviceNames := make([]string, 0, len(unitNames))
+	for _, name := range unitNames {
+		if serviceName, err := names.UnitService(name); err == nil {
+			serviceNames = append(serviceNames, serviceName)
+		}
+	}
+	return serviceTags(serviceNames...)
+}
+
+// endpointServiceTags returns the tags of the services of the given
+// relation endpoints, of the form "<service>[:<relation>]", for
+// checking the blocks in place for them.
+func endpointServiceTags(endpoints []string) []names.Tag {
+	serviceNames := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		serviceNames[i] = strings.SplitN(endpoint, ":", 2)[0]
+	}
+	return serviceTags(serviceNames...)
+}
+
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
 	switch {
@@ -242,7 +278,7 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -256,7 +292,7 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -312,7 +348,7 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 		if curl.Schema != "cs" {
 			return fmt.Errorf(`charm url has unsupported schema %q`, curl.Schema)
 		}
-		err = c.AddCharm(params.CharmURL{args.CharmUrl})
+		err = c.AddCharm(params.AddCharm{URL: args.CharmUrl})
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -373,7 +409,7 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 
 	var settings charm.Settings
 	if len(args.ConfigYAML) > 0 {
-		settings, err = ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
+		settings, err = parseSettingsYAML(ch.Config(), args.ConfigYAML, args.ServiceName)
 	} else if len(args.Config) > 0 {
 		// Parse config in a compatible way (see function comment).
 		settings, err = parseSettingsCompatible(ch, args.Config)
@@ -391,14 +427,16 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 		jjj.DeployServiceParams{
 			ServiceName: args.ServiceName,
 			// TODO(dfc) ServiceOwner should be a tag
-			ServiceOwner:   c.api.auth.GetAuthTag().String(),
-			Charm:          ch,
-			NumUnits:       args.NumUnits,
-			ConfigSettings: settings,
-			Constraints:    args.Constraints,
-			ToMachineSpec:  args.ToMachineSpec,
-			Networks:       requestedNetworks,
-			Storage:        storageConstraints,
+			ServiceOwner:     c.api.auth.GetAuthTag().String(),
+			Charm:            ch,
+			NumUnits:         args.NumUnits,
+			ConfigSettings:   settings,
+			Constraints:      args.Constraints,
+			ToMachineSpec:    args.ToMachineSpec,
+			Networks:         requestedNetworks,
+			Storage:          storageConstraints,
+			SpaceBinding:     args.SpaceBinding,
+			EndpointBindings: args.EndpointBindings,
 		})
 	return err
 }
@@ -430,7 +468,7 @@ func validateCharmStorage(args params.ServiceDeploy, ch *state.Charm) error {
 // All parameters in params.ServiceUpdate except the service name are optional.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	if !args.ForceCharmUrl {
-		if err := c.check.ChangeAllowed(); err != nil {
+		if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -456,7 +494,7 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 			return err
 		}
 	} else if len(args.SettingsStrings) > 0 {
-		if err = serviceSetSettingsStrings(service, args.SettingsStrings); err != nil {
+		if err = serviceSetSettingsStrings(service, args.SettingsStrings, nil); err != nil {
 			return err
 		}
 	}
@@ -495,7 +533,7 @@ func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL,
 	if curl.Revision < 0 {
 		return fmt.Errorf("charm url must include revision")
 	}
-	err := c.AddCharm(params.CharmURL{curl.String()})
+	err := c.AddCharm(params.AddCharm{URL: curl.String()})
 	if err != nil {
 		return err
 	}
@@ -522,7 +560,7 @@ func serviceSetSettingsYAML(service *state.Service, settings string) error {
 
 // serviceSetSettingsStrings updates the settings for the given service,
 // taking the configuration from a map of strings.
-func serviceSetSettingsStrings(service *state.Service, settings map[string]string) error {
+func serviceSetSettingsStrings(service *state.Service, settings map[string]string, revision *int64) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -532,7 +570,7 @@ func serviceSetSettingsStrings(service *state.Service, settings map[string]strin
 	if err != nil {
 		return err
 	}
-	return service.UpdateConfigSettings(changes)
+	return updateConfigSettings(service, changes, revision)
 }
 
 // newServiceSetSettingsStringsForClientAPI updates the settings for the given
@@ -540,7 +578,7 @@ func serviceSetSettingsStrings(service *state.Service, settings map[string]strin
 //
 // TODO(Nate): replace serviceSetSettingsStrings with this onces the GUI no
 // longer expects to be able to unset values by sending an empty string.
-func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string) error {
+func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string, revision *int64) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -552,7 +590,17 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 		return err
 	}
 
-	return service.UpdateConfigSettings(changes)
+	return updateConfigSettings(service, changes, revision)
+}
+
+// updateConfigSettings updates the settings for the given service. If
+// revision is not nil, the settings are only updated if they are still
+// at that revision.
+func updateConfigSettings(service *state.Service, changes charm.Settings, revision *int64) error {
+	if revision == nil {
+		return service.UpdateConfigSettings(changes)
+	}
+	return service.UpdateConfigSettingsAtRevision(changes, *revision)
 }
 
 // ServiceSetCharm sets the charm for a given service.
@@ -560,7 +608,7 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	// when forced, don't block
 	if !args.Force {
-		if err := c.check.ChangeAllowed(); err != nil {
+		if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -595,7 +643,7 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 
 // AddServiceUnits adds a given number of units to a service.
 func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddServiceUnitsResults, error) {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 		return params.AddServiceUnitsResults{}, errors.Trace(err)
 	}
 	units, err := addServiceUnits(c.api.state, args)
@@ -611,7 +659,7 @@ func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddService
 
 // DestroyServiceUnits removes a given set of service units.
 func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
-	if err := c.check.RemoveAllowed(); err != nil {
+	if err := c.check.RemoveAllowedFor(unitServiceTags(args.UnitNames...)...); err != nil {
 		return errors.Trace(err)
 	}
 	var errs []string
@@ -638,7 +686,7 @@ func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 // ServiceDestroy destroys a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceDestroy(args params.ServiceDestroy) error {
-	if err := c.check.RemoveAllowed(); err != nil {
+	if err := c.check.RemoveAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -671,7 +719,7 @@ func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, erro
 // SetServiceConstraints sets the constraints for a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(serviceTags(args.ServiceName)...); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -691,14 +739,14 @@ func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults, error) {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(endpointServiceTags(args.Endpoints)...); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
 	inEps, err := c.api.state.InferEndpoints(args.Endpoints...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
-	rel, err := c.api.state.AddRelation(inEps...)
+	rel, err := c.api.state.AddRelationWithSelector(args.SubordinateSelector, inEps...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
@@ -715,7 +763,7 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(args params.DestroyRelation) error {
-	if err := c.check.RemoveAllowed(); err != nil {
+	if err := c.check.RemoveAllowedFor(endpointServiceTags(args.Endpoints)...); err != nil {
 		return errors.Trace(err)
 	}
 	eps, err := c.api.state.InferEndpoints(args.Endpoints...)
@@ -726,7 +774,14 @@ func (c *Client) DestroyRelation(args params.DestroyRelation) error {
 	if err != nil {
 		return err
 	}
-	return rel.Destroy()
+	if args.Force {
+		return rel.Destroy()
+	}
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return rel.Drain(cfg.RelationDrainTimeout())
 }
 
 // AddMachines adds new machines with the supplied parameters.
@@ -927,7 +982,7 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 			continue
 		default:
 			{
-				if err := c.check.RemoveAllowed(); err != nil {
+				if err := c.check.RemoveAllowedFor(machine.Tag()); err != nil {
 					return errors.Trace(err)
 				}
 				err = machine.Destroy()
@@ -940,6 +995,35 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 	return destroyErr("machines", args.MachineNames, errs)
 }
 
+// UpgradeSeriesPrepare starts upgrading the series of a machine: its
+// units run their pre-series-upgrade hooks and then pause, so that the
+// operating system of the machine can be upgraded.
+func (c *Client) UpgradeSeriesPrepare(args params.UpgradeSeriesPrepare) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := c.api.state.Machine(args.MachineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.PrepareUpgradeSeries(args.Series)
+}
+
+// UpgradeSeriesComplete resumes the units of a machine once its
+// operating system has been upgraded. The units run their
+// post-series-upgrade hooks, after which the machine's series is
+// updated.
+func (c *Client) UpgradeSeriesComplete(args params.UpgradeSeriesComplete) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := c.api.state.Machine(args.MachineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.CompleteUpgradeSeries()
+}
+
 // CharmInfo returns information about the requested charm.
 func (c *Client) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
 	curl, err := charm.ParseURL(args.CharmURL)
@@ -1126,9 +1210,12 @@ func parseSettingsCompatible(ch *state.Charm, settings map[string]string) (charm
 		}
 		setSettings[name] = value
 	}
-	// Validate the settings.
+	// Validate the settings, reporting all the invalid ones.
 	changes, err := ch.Config().ParseSettingsStrings(setSettings)
 	if err != nil {
+		if errs := settingsStringsErrors(ch.Config(), setSettings); len(errs) > 0 {
+			return nil, settingsError(errs)
+		}
 		return nil, err
 	}
 	// Validate the unsettings and merge them into the changes.
@@ -1166,16 +1253,6 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	// Make sure we don't allow changing agent-version.
-	checkAgentVersion := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
-		if v, found := updateAttrs["agent-version"]; found {
-			oldVersion, _ := oldConfig.AgentVersion()
-			if v != oldVersion.String() {
-				return fmt.Errorf("agent-version cannot be changed")
-			}
-		}
-		return nil
-	}
 	// Replace any deprecated attributes with their new values.
 	attrs := config.ProcessDeprecatedAttributes(args.Config)
 	// TODO(waigani) 2014-3-11 #1167616
@@ -1184,6 +1261,54 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 	return c.api.state.UpdateEnvironConfig(attrs, nil, checkAgentVersion)
 }
 
+// checkAgentVersion makes sure we don't allow changing agent-version
+// through the environment config.
+func checkAgentVersion(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
+	if v, found := updateAttrs["agent-version"]; found {
+		oldVersion, _ := oldConfig.AgentVersion()
+		if v != oldVersion.String() {
+			return fmt.Errorf("agent-version cannot be changed")
+		}
+	}
+	return nil
+}
+
+// StageEnvironmentConfig validates the given changes to the environment
+// config against the provider and the config schema, and returns the
+// changes they would make along with the revision of the config they
+// were validated against. Nothing is changed until that revision is
+// passed to ApplyEnvironmentConfig with the same changes.
+func (c *Client) StageEnvironmentConfig(args params.EnvironmentConfigChanges) (params.StagedEnvironmentConfig, error) {
+	var result params.StagedEnvironmentConfig
+	attrs := config.ProcessDeprecatedAttributes(args.Config)
+	update, err := c.api.state.ValidateEnvironConfigUpdate(attrs, args.Unset, checkAgentVersion)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Revision = update.Revision
+	result.Changes = make([]params.EnvironmentConfigChange, len(update.Changes))
+	for i, change := range update.Changes {
+		result.Changes[i] = params.EnvironmentConfigChange{
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	return result, nil
+}
+
+// ApplyEnvironmentConfig applies changes to the environment config
+// staged with StageEnvironmentConfig, provided the config is still at
+// the revision returned by it. If it is not, an error with code
+// params.CodeConflict is returned and nothing is changed.
+func (c *Client) ApplyEnvironmentConfig(args params.EnvironmentConfigChanges) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	attrs := config.ProcessDeprecatedAttributes(args.Config)
+	return c.api.state.UpdateEnvironConfigAtRevision(attrs, args.Unset, checkAgentVersion, args.Revision)
+}
+
 // EnvironmentUnset implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
@@ -1201,9 +1326,72 @@ func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) erro
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
+	if len(args.CanaryMachines) > 0 {
+		return c.api.state.SetEnvironAgentVersionWithCanaries(args.Version, args.CanaryMachines)
+	}
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
+// ResumeUpgrade lets all agents upgrade once the canary machines of
+// the current upgrade have.
+func (c *Client) ResumeUpgrade() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.ResumeUpgrade()
+}
+
+// UpgradeStatus reports the progress of the environment's agents
+// towards the environment's agent version.
+func (c *Client) UpgradeStatus() (params.UpgradeStatusResult, error) {
+	var result params.UpgradeStatusResult
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	agentVersion, ok := cfg.AgentVersion()
+	if !ok {
+		return result, errors.New("agent version not set in environment config")
+	}
+	result.TargetVersion = agentVersion
+	result.Wave = string(state.UpgradeWaveAll)
+	plan, err := c.api.state.UpgradePlan()
+	if errors.IsNotFound(err) {
+		plan = nil
+	} else if err != nil {
+		return result, errors.Trace(err)
+	} else if plan.TargetVersion() != agentVersion {
+		// The plan is left over from an earlier upgrade.
+		plan = nil
+	}
+	if plan != nil {
+		previousVersion := plan.PreviousVersion()
+		result.PreviousVersion = &previousVersion
+		result.Wave = string(plan.Wave())
+		result.Canaries = plan.Canaries()
+	}
+
+	machines, err := c.api.state.AllMachines()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, m := range machines {
+		status := params.MachineUpgradeStatus{
+			Id:     m.Id(),
+			InWave: plan == nil || m.IsManager() || plan.MachineInWave(m.Id()),
+		}
+		agentTools, err := m.AgentTools()
+		if err == nil {
+			status.AgentVersion = agentTools.Version.Number.String()
+			status.Upgraded = agentTools.Version.Number == agentVersion
+		} else if !errors.IsNotFound(err) {
+			return result, errors.Trace(err)
+		}
+		result.Machines = append(result.Machines, status)
+	}
+	return result, nil
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -1232,12 +1420,17 @@ func destroyErr(desc string, ids, errs []string) error {
 
 // AddCharm adds the given charm URL (which must include revision) to
 // the environment, if it does not exist yet. Local charms are not
-// supported, only charm store URLs. See also AddLocalCharm().
-func (c *Client) AddCharm(args params.CharmURL) error {
+// supported, only charm store URLs. The charm is downloaded from the
+// given charm store channel, or from the stable channel if none is
+// given. See also AddLocalCharm().
+func (c *Client) AddCharm(args params.AddCharm) error {
 	charmURL, err := charm.ParseURL(args.URL)
 	if err != nil {
 		return err
 	}
+	if err := config.ValidateCharmChannel(args.Channel); err != nil {
+		return err
+	}
 	if charmURL.Schema != "cs" {
 		return fmt.Errorf("only charm store charm URLs are supported, with cs: schema")
 	}
@@ -1259,7 +1452,11 @@ func (c *Client) AddCharm(args params.CharmURL) error {
 	if err != nil {
 		return err
 	}
-	repo := config.SpecializeCharmRepo(charmStore, envConfig)
+	repo, err := config.CharmRepoForChannel(charmStore, args.Channel)
+	if err != nil {
+		return errors.Annotatef(err, "cannot download charm %q", charmURL.String())
+	}
+	repo = config.SpecializeCharmRepo(repo, envConfig)
 	downloadedCharm, err := repo.Get(charmURL)
 	if err != nil {
 		return errors.Annotatef(err, "cannot download charm %q", charmURL.String())
@@ -1295,14 +1492,19 @@ func (c *Client) AddCharm(args params.CharmURL) error {
 }
 
 // StoreCharmArchive stores a charm archive in environment storage.
+// Archives are stored under their SHA256 hash, so that charms with
+// identical archives share the stored data.
 func StoreCharmArchive(st *state.State, curl *charm.URL, ch charm.Charm, r io.Reader, size int64, sha256 string) error {
 	storage := newStateStorage(st.EnvironUUID(), st.MongoSession())
-	storagePath, err := charmArchiveStoragePath(curl)
+	storagePath := charmArchiveStoragePath(sha256)
+	stored, err := storageHasPath(storage, storagePath)
 	if err != nil {
-		return errors.Annotate(err, "cannot generate charm archive name")
+		return errors.Annotate(err, "cannot check charm storage")
 	}
-	if err := storage.Put(storagePath, r, size); err != nil {
-		return errors.Annotate(err, "cannot add charm to storage")
+	if !stored {
+		if err := storage.Put(storagePath, r, size); err != nil {
+			return errors.Annotate(err, "cannot add charm to storage")
+		}
 	}
 
 	// Now update the charm data in state and mark it as no longer pending.
@@ -1311,7 +1513,10 @@ func StoreCharmArchive(st *state.State, curl *charm.URL, ch charm.Charm, r io.Re
 		alreadyUploaded := err == state.ErrCharmRevisionAlreadyModified ||
 			errors.Cause(err) == state.ErrCharmRevisionAlreadyModified ||
 			state.IsCharmAlreadyUploadedError(err)
-		if err := storage.Remove(storagePath); err != nil {
+		if stored || charmUsesStoragePath(st, curl, storagePath) {
+			// The archive is used by another charm, or by the
+			// charm somebody else uploaded at the same time.
+		} else if err := storage.Remove(storagePath); err != nil {
 			if alreadyUploaded {
 				logger.Errorf("cannot remove duplicated charm archive from storage: %v", err)
 			} else {
@@ -1358,29 +1563,82 @@ func (c *Client) resolveCharm(ref *charm.Reference, repo charmrepo.Interface) (*
 	return repo.Resolve(ref)
 }
 
-// charmArchiveStoragePath returns a string that is suitable as a
-// storage path, using a random UUID to avoid colliding with concurrent
-// uploads.
-func charmArchiveStoragePath(curl *charm.URL) (string, error) {
-	uuid, err := utils.NewUUID()
+// charmArchiveStoragePath returns the storage path of the charm
+// archive with the given SHA256 hash.
+func charmArchiveStoragePath(sha256 string) string {
+	return "charms/sha256-" + sha256
+}
+
+// storageHasPath reports whether data is stored at the given path.
+func storageHasPath(storage statestorage.Storage, path string) (bool, error) {
+	r, _, err := storage.Get(path)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	r.Close()
+	return true, nil
+}
+
+// charmUsesStoragePath reports whether the charm with the given URL is
+// recorded in state with the given storage path.
+func charmUsesStoragePath(st *state.State, curl *charm.URL, storagePath string) bool {
+	ch, err := st.Charm(curl)
 	if err != nil {
-		return "", err
+		return false
 	}
-	return fmt.Sprintf("charms/%s-%s", curl.String(), uuid), nil
+	return ch.StoragePath() == storagePath
 }
 
-// RetryProvisioning marks a provisioning error as transient on the machines.
+// RetryProvisioning retries the failed provisioning of the given
+// entities: it marks the provisioning errors of machines as transient,
+// resolves units in an error state retrying their failed hooks, and
+// clears the provisioning errors of volumes and filesystems so that the
+// storage provisioner attempts to provision them again.
 func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
-	entityStatus := make([]params.EntityStatus, len(p.Entities))
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(p.Entities)),
+	}
 	for i, entity := range p.Entities {
-		entityStatus[i] = params.EntityStatus{Tag: entity.Tag, Data: map[string]interface{}{"transient": true}}
+		err := c.retryProvisioning(entity.Tag)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *Client) retryProvisioning(tagString string) error {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return common.ErrPerm
+	}
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		results, err := c.api.statusSetter.UpdateStatus(params.SetStatus{
+			Entities: []params.EntityStatus{{
+				Tag:  tagString,
+				Data: map[string]interface{}{"transient": true},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		return results.OneError()
+	case names.UnitTag:
+		unit, err := c.api.state.Unit(tag.Id())
+		if err != nil {
+			return err
+		}
+		return unit.Resolve(true)
+	case names.VolumeTag:
+		return c.api.state.RetryVolumeProvisioning(tag)
+	case names.FilesystemTag:
+		return c.api.state.RetryFilesystemProvisioning(tag)
 	}
-	return c.api.statusSetter.UpdateStatus(params.SetStatus{
-		Entities: entityStatus,
-	})
+	return common.ErrPerm
 }
 
 // APIHostPorts returns the API host/port addresses stored in state.
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/utils/featureflag"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/juju/charm.v5-unstable/charmrepo"
 	charmtesting "gopkg.in/juju/charm.v5-unstable/testing"
 	"gopkg.in/mgo.v2"
 
@@ -484,6 +485,126 @@ func (s *serverSuite) TestBlockChangesSetEnvironAgentVersion(c *gc.C) {
 	s.assertSetEnvironAgentVersionBlocked(c, "TestBlockChangesSetEnvironAgentVersion")
 }
 
+func (s *serverSuite) setUpCanaryUpgrade(c *gc.C) (previous version.Number) {
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	previous, ok := envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	for i := 0; i < 2; i++ {
+		machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = machine.SetAgentVersion(version.Binary{Number: previous, Series: "quantal", Arch: "amd64"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	args := params.SetEnvironAgentVersion{
+		Version:        version.MustParse("9.8.7"),
+		CanaryMachines: []string{"1"},
+	}
+	err = s.client.SetEnvironAgentVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	return previous
+}
+
+func (s *serverSuite) TestSetEnvironAgentVersionWithCanaries(c *gc.C) {
+	previous := s.setUpCanaryUpgrade(c)
+	plan, err := s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.PreviousVersion(), gc.Equals, previous)
+	c.Assert(plan.TargetVersion(), gc.Equals, version.MustParse("9.8.7"))
+	c.Assert(plan.Canaries(), jc.DeepEquals, []string{"1"})
+	c.Assert(plan.Wave(), gc.Equals, state.UpgradeWaveCanary)
+}
+
+func (s *serverSuite) TestUpgradeStatus(c *gc.C) {
+	previous := s.setUpCanaryUpgrade(c)
+	machine, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.MustParseBinary("9.8.7-quantal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.UpgradeStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.UpgradeStatusResult{
+		PreviousVersion: &previous,
+		TargetVersion:   version.MustParse("9.8.7"),
+		Wave:            "canary",
+		Canaries:        []string{"1"},
+		Machines: []params.MachineUpgradeStatus{{
+			Id:           "0",
+			AgentVersion: previous.String(),
+		}, {
+			Id:           "1",
+			AgentVersion: "9.8.7",
+			InWave:       true,
+			Upgraded:     true,
+		}},
+	})
+}
+
+func (s *serverSuite) TestUpgradeStatusWithoutCanaries(c *gc.C) {
+	result, err := s.client.UpgradeStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.PreviousVersion, gc.IsNil)
+	c.Assert(result.TargetVersion, gc.Equals, version.Current.Number)
+	c.Assert(result.Wave, gc.Equals, "all")
+	c.Assert(result.Machines, gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestResumeUpgrade(c *gc.C) {
+	s.setUpCanaryUpgrade(c)
+	err := s.client.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Wave(), gc.Equals, state.UpgradeWaveAll)
+}
+
+func (s *serverSuite) TestBlockChangesResumeUpgrade(c *gc.C) {
+	s.setUpCanaryUpgrade(c)
+	s.BlockAllChanges(c, "TestBlockChangesResumeUpgrade")
+	err := s.client.ResumeUpgrade()
+	s.AssertBlocked(c, err, "TestBlockChangesResumeUpgrade")
+}
+
+func (s *serverSuite) TestUpgradeSeries(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.UpgradeSeriesPrepare(params.UpgradeSeriesPrepare{
+		MachineId: machine.Id(),
+		Series:    "trusty",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	lock, err := machine.UpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lock.ToSeries(), gc.Equals, "trusty")
+
+	err = s.client.UpgradeSeriesComplete(params.UpgradeSeriesComplete{MachineId: machine.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Series(), gc.Equals, "trusty")
+}
+
+func (s *serverSuite) TestUpgradeSeriesPrepareMachineNotFound(c *gc.C) {
+	err := s.client.UpgradeSeriesPrepare(params.UpgradeSeriesPrepare{
+		MachineId: "42",
+		Series:    "trusty",
+	})
+	c.Assert(err, gc.ErrorMatches, "machine 42 not found")
+}
+
+func (s *serverSuite) TestBlockChangesUpgradeSeriesPrepare(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestBlockChangesUpgradeSeriesPrepare")
+	err = s.client.UpgradeSeriesPrepare(params.UpgradeSeriesPrepare{
+		MachineId: machine.Id(),
+		Series:    "trusty",
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesUpgradeSeriesPrepare")
+}
+
 func (s *serverSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	// Create a provisioned state server.
 	machine, err := s.State.AddMachine("series", state.JobManageEnviron)
@@ -649,6 +770,27 @@ func (s *clientSuite) TestClientServiceSet(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestClientServiceSetAtRevision(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	results, err := s.APIState.Client().ServiceGet("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceSetAtRevision("dummy", map[string]string{
+		"title": "foobar",
+	}, results.ConfigRevision)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The configuration has changed since it was read.
+	err = s.APIState.Client().ServiceSetAtRevision("dummy", map[string]string{
+		"title": "barfoo",
+	}, results.ConfigRevision)
+	c.Assert(err, gc.ErrorMatches, "settings changed since they were read")
+	c.Assert(err, jc.Satisfies, params.IsCodeConflict)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "foobar"})
+}
+
 func (s *serverSuite) assertServiceSetBlocked(c *gc.C, dummy *state.Service, msg string) {
 	err := s.client.ServiceSet(params.ServiceSet{
 		ServiceName: "dummy",
@@ -1241,6 +1383,16 @@ func (s *clientSuite) TestBlockChangesServiceExpose(c *gc.C) {
 	s.assertServiceExposeBlocked(c, "TestBlockChangesServiceExpose")
 }
 
+func (s *clientSuite) TestBlockEntityChangesServiceExpose(c *gc.C) {
+	s.setupServiceExpose(c)
+	s.BlockEntityChanges(c, names.NewServiceTag("dummy-service"), "TestBlockEntityChangesServiceExpose")
+	err := s.APIState.Client().ServiceExpose("dummy-service")
+	s.AssertBlocked(c, err, "TestBlockEntityChangesServiceExpose")
+	// Other services are not blocked.
+	err = s.APIState.Client().ServiceExpose("exposed-service")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 var serviceUnexposeTests = []struct {
 	about    string
 	service  string
@@ -1867,6 +2019,63 @@ func (s *clientSuite) TestClientServiceDeployConfigError(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *clientSuite) TestClientServiceDeployConfigErrors(c *gc.C) {
+	s.makeMockCharmStore()
+	curl, _ := addCharm(c, "dummy")
+	err := s.APIState.Client().ServiceDeploy(
+		curl.String(), "service-name", 1, "service-name:\n  skill-level: fred\n  bogus: x", constraints.Value{}, "",
+	)
+	c.Assert(err, gc.ErrorMatches, `unknown option "bogus"; option "skill-level" expected int, got "fred"`)
+	_, err = s.State.Service("service-name")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestClientValidateServiceSetConfig(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	invalid, err := s.APIState.Client().ValidateServiceSetConfig("dummy", map[string]string{
+		"title":       "foobar",
+		"skill-level": "lots",
+		"bogus":       "x",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invalid, gc.DeepEquals, map[string]string{
+		"skill-level": `option "skill-level" expected int, got "lots"`,
+		"bogus":       `unknown option "bogus"`,
+	})
+
+	invalid, err = s.APIState.Client().ValidateServiceSetConfig("dummy", map[string]string{
+		"title":       "foobar",
+		"skill-level": "9000",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invalid, gc.HasLen, 0)
+
+	// Nothing was set.
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestClientValidateServiceSetConfigYAML(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	invalid, err := s.APIState.Client().ValidateServiceSetConfigYAML("dummy", "dummy:\n  title: foobar\n  skill-level: lots\n  bogus: x\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invalid, gc.DeepEquals, map[string]string{
+		"skill-level": `option "skill-level" expected int, got "lots"`,
+		"bogus":       `unknown option "bogus"`,
+	})
+
+	_, err = s.APIState.Client().ValidateServiceSetConfigYAML("dummy", "other:\n  title: foobar\n")
+	c.Assert(err, gc.ErrorMatches, `no settings found for "dummy"`)
+}
+
+func (s *clientSuite) TestClientValidateServiceSetConfigNoService(c *gc.C) {
+	_, err := s.APIState.Client().ValidateServiceSetConfig("missing", map[string]string{"title": "foobar"})
+	c.Assert(err, gc.ErrorMatches, `service "missing" not found`)
+}
+
 func (s *clientSuite) TestClientServiceDeployToMachine(c *gc.C) {
 	s.makeMockCharmStore()
 	curl, bundle := addCharm(c, "dummy")
@@ -2382,6 +2591,19 @@ func (s *clientSuite) TestSuccessfullyAddRelation(c *gc.C) {
 	s.assertAddRelation(c, endpoints)
 }
 
+func (s *clientSuite) TestAddRelationWithSelector(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	selector := map[string]string{"tier": "prod"}
+	_, err := s.APIState.Client().AddRelationWithSelector(selector, "mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.State.InferEndpoints("mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.EndpointsRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.SubordinateSelector(), jc.DeepEquals, selector)
+}
+
 func (s *clientSuite) TestBlockDestroyAddRelation(c *gc.C) {
 	s.BlockDestroyEnvironment(c, "TestBlockDestroyAddRelation")
 	s.assertAddRelation(c, []string{"wordpress", "mysql"})
@@ -2875,6 +3097,65 @@ func (s *serverSuite) TestClientEnvironmentSetCannotChangeAgentVersion(c *gc.C)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *serverSuite) TestClientStageEnvironmentConfig(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"abc": 123}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.EnvironmentConfigChanges{
+		Config: map[string]interface{}{"some-key": "value"},
+		Unset:  []string{"abc"},
+	}
+	result, err := s.client.StageEnvironmentConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Changes, jc.DeepEquals, []params.EnvironmentConfigChange{
+		{Key: "abc", OldValue: 123},
+		{Key: "some-key", NewValue: "value"},
+	})
+	// Nothing changes until the staged changes are applied.
+	s.assertEnvValue(c, "abc", 123)
+	s.assertEnvValueMissing(c, "some-key")
+
+	args.Revision = result.Revision
+	err = s.client.ApplyEnvironmentConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvValueMissing(c, "abc")
+	s.assertEnvValue(c, "some-key", "value")
+}
+
+func (s *serverSuite) TestClientStageEnvironmentConfigInvalid(c *gc.C) {
+	_, err := s.client.StageEnvironmentConfig(params.EnvironmentConfigChanges{
+		Config: map[string]interface{}{"state-port": "1"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot change state-port from .* to 1`)
+	_, err = s.client.StageEnvironmentConfig(params.EnvironmentConfigChanges{
+		Config: map[string]interface{}{"agent-version": "9.9.9"},
+	})
+	c.Assert(err, gc.ErrorMatches, "agent-version cannot be changed")
+}
+
+func (s *serverSuite) TestClientApplyEnvironmentConfigChanged(c *gc.C) {
+	args := params.EnvironmentConfigChanges{
+		Config: map[string]interface{}{"some-key": "value"},
+	}
+	result, err := s.client.StageEnvironmentConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"other-key": "other value"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args.Revision = result.Revision
+	err = s.client.ApplyEnvironmentConfig(args)
+	c.Assert(err, gc.Equals, state.ErrSettingsChanged)
+	s.assertEnvValueMissing(c, "some-key")
+}
+
+func (s *serverSuite) TestBlockChangesClientApplyEnvironmentConfig(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesClientApplyEnvironmentConfig")
+	err := s.client.ApplyEnvironmentConfig(params.EnvironmentConfigChanges{
+		Config: map[string]interface{}{"some-key": "value"},
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesClientApplyEnvironmentConfig")
+}
+
 func (s *serverSuite) TestClientEnvironmentUnset(c *gc.C) {
 	err := s.State.UpdateEnvironConfig(map[string]interface{}{"abc": 123}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -3476,6 +3757,48 @@ func (s *clientSuite) TestAddCharm(c *gc.C) {
 	s.assertUploaded(c, storage, sch.StoragePath(), sch.BundleSha256())
 }
 
+// channelCharmStore is a mock charm store that serves the charm store
+// channels other than stable from separate mock stores.
+type channelCharmStore struct {
+	*charmtesting.MockCharmStore
+	channels map[string]*charmtesting.MockCharmStore
+}
+
+func (s *channelCharmStore) WithChannel(channel string) charmrepo.Interface {
+	return s.channels[channel]
+}
+
+func (s *clientSuite) TestAddCharmWithChannel(c *gc.C) {
+	edge := charmtesting.NewMockCharmStore()
+	s.PatchValue(client.CharmStore, &channelCharmStore{
+		MockCharmStore: charmtesting.NewMockCharmStore(),
+		channels:       map[string]*charmtesting.MockCharmStore{"edge": edge},
+	})
+	bundle := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	curl := charm.MustParseURL(fmt.Sprintf("cs:precise/dummy-%d", bundle.Revision()))
+	err := edge.SetCharm(curl, bundle)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	err = client.AddCharmWithChannel(curl, "beta")
+	c.Assert(err, gc.ErrorMatches, `charm channel "beta" not valid`)
+	err = client.AddCharm(curl)
+	c.Assert(err, gc.ErrorMatches, `cannot download charm ".*": charm not found in mock store: .*`)
+
+	err = client.AddCharmWithChannel(curl, "edge")
+	c.Assert(err, jc.ErrorIsNil)
+	sch, err := s.State.Charm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sch.IsUploaded(), jc.IsTrue)
+}
+
+func (s *clientSuite) TestAddCharmWithUnsupportedChannel(c *gc.C) {
+	s.makeMockCharmStore()
+	curl, _ := addCharm(c, "dummy")
+	err := s.APIState.Client().AddCharmWithChannel(curl, "candidate")
+	c.Assert(err, gc.ErrorMatches, `cannot download charm ".*": charm channel "candidate" not supported`)
+}
+
 var resolveCharmCases = []struct {
 	schema, defaultSeries, charmName string
 	parseErr                         string
@@ -3617,19 +3940,17 @@ func (s *clientSuite) TestAddCharmConcurrently(c *gc.C) {
 
 	blobs.Lock()
 
-	c.Assert(blobs.m, gc.HasLen, 10)
+	// The archives are stored under their hash, so all the uploads
+	// share a single storage path, which remains.
+	c.Assert(blobs.m, gc.HasLen, 1)
 
 	// Verify there is only a single uploaded charm remains and it
 	// contains the correct data.
 	sch, err := s.State.Charm(curl)
 	c.Assert(err, jc.ErrorIsNil)
 	storagePath := sch.StoragePath()
+	c.Assert(storagePath, gc.Equals, "charms/sha256-"+sch.BundleSha256())
 	c.Assert(blobs.m[storagePath], jc.IsTrue)
-	for path, exists := range blobs.m {
-		if path != storagePath {
-			c.Assert(exists, jc.IsFalse)
-		}
-	}
 
 	storage := statestorage.NewStorage(s.State.EnvironUUID(), s.State.MongoSession())
 	s.assertUploaded(c, storage, sch.StoragePath(), sch.BundleSha256())
@@ -3684,6 +4005,33 @@ func (s *clientSuite) TestRetryProvisioning(c *gc.C) {
 	c.Assert(data["transient"], jc.IsTrue)
 }
 
+func (s *clientSuite) TestRetryProvisioningUnit(c *gc.C) {
+	u := s.setupResolved(c)
+	results, err := s.APIState.Client().RetryProvisioning(u.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Resolved(), gc.Equals, state.ResolvedRetryHooks)
+}
+
+func (s *clientSuite) TestRetryProvisioningErrors(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().RetryProvisioning(
+		names.NewUnitTag("wordpress/0"),
+		names.NewVolumeTag("42"),
+		names.NewFilesystemTag("42"),
+		names.NewServiceTag("wordpress"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{&params.Error{Message: `unit "wordpress/0" is not in an error state`}},
+		{&params.Error{Message: `volume "42" not found`, Code: params.CodeNotFound}},
+		{&params.Error{Message: `filesystem "42" not found`, Code: params.CodeNotFound}},
+		{&params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	})
+}
+
 func (s *clientSuite) setupRetryProvisioning(c *gc.C) *state.Machine {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -3847,6 +4195,13 @@ func (s *clientSuite) TestBlockRemoveDestroyMachines(c *gc.C) {
 	s.assertBlockedErrorAndLiveliness(c, err, "TestBlockRemoveDestroyMachines", m0, m1, m2, u)
 }
 
+func (s *clientSuite) TestBlockEntityRemovalDestroyMachines(c *gc.C) {
+	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
+	s.BlockEntityRemoval(c, m2.Tag(), "TestBlockEntityRemovalDestroyMachines")
+	err := s.APIState.Client().DestroyMachines("2")
+	s.assertBlockedErrorAndLiveliness(c, err, "TestBlockEntityRemovalDestroyMachines", m0, m1, m2, u)
+}
+
 func (s *clientSuite) TestBlockChangesDestroyMachines(c *gc.C) {
 	m0, m1, m2, u := s.setupDestroyMachinesTest(c)
 	s.BlockAllChanges(c, "TestBlockChangesDestroyMachines")
@@ -21,8 +21,10 @@ var (
 
 // Status exports
 var (
-	ProcessMachines   = processMachines
-	MakeMachineStatus = makeMachineStatus
+	ProcessMachines        = processMachines
+	MakeMachineStatus      = makeMachineStatus
+	StatusCacheMinEntities = &statusCacheMinEntities
+	StatusCacheMaxAge      = &statusCacheMaxAge
 )
 
 type MachineAndContainers machineAndContainers
@@ -16,7 +16,7 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 	if err != nil {
 		return params.ServiceGetResults{}, err
 	}
-	settings, err := service.ConfigSettings()
+	settings, revision, err := service.ConfigSettingsRevision()
 	if err != nil {
 		return params.ServiceGetResults{}, err
 	}
@@ -33,10 +33,11 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 		}
 	}
 	return params.ServiceGetResults{
-		Service:     args.ServiceName,
-		Charm:       charm.Meta().Name,
-		Config:      configInfo,
-		Constraints: constraints,
+		Service:        args.ServiceName,
+		Charm:          charm.Meta().Name,
+		Config:         configInfo,
+		ConfigRevision: revision,
+		Constraints:    constraints,
 	}, nil
 }
 
@@ -22,6 +22,10 @@ var _ = gc.Suite(&getSuite{})
 
 func (s *getSuite) TestClientServiceGetSmoketest(c *gc.C) {
 	s.setUpScenario(c)
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, revision, err := svc.ConfigSettingsRevision()
+	c.Assert(err, jc.ErrorIsNil)
 	results, err := s.APIState.Client().ServiceGet("wordpress")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, &params.ServiceGetResults{
@@ -35,6 +39,7 @@ func (s *getSuite) TestClientServiceGetSmoketest(c *gc.C) {
 				"default":     true,
 			},
 		},
+		ConfigRevision: revision,
 	})
 }
 
@@ -154,10 +159,13 @@ func (s *getSuite) TestServiceGet(c *gc.C) {
 			err := svc.UpdateConfigSettings(t.config)
 			c.Assert(err, jc.ErrorIsNil)
 		}
+		_, revision, err := svc.ConfigSettingsRevision()
+		c.Assert(err, jc.ErrorIsNil)
 		expect := t.expect
 		expect.Constraints = constraintsv
 		expect.Service = svc.Name()
 		expect.Charm = ch.Meta().Name
+		expect.ConfigRevision = revision
 		apiclient := s.APIState.Client()
 		got, err := apiclient.ServiceGet(svc.Name())
 		c.Assert(err, jc.ErrorIsNil)
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
-	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -81,16 +80,89 @@ func (c *Client) getDataDir() string {
 	return dataResource.String()
 }
 
+// selectMachines returns the ids of the machines whose hardware has all
+// of the given tags, and of those with an address on any of the given
+// network spaces. Machines that have not been provisioned have neither,
+// so are never selected.
+func selectMachines(st *state.State, tags, spaces []string) ([]string, error) {
+	if len(tags) == 0 && len(spaces) == 0 {
+		return nil, nil
+	}
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tagSet := set.NewStrings(tags...)
+	spaceSet := set.NewStrings(spaces...)
+	var ids []string
+	for _, machine := range machines {
+		if !tagSet.IsEmpty() {
+			hc, err := machine.HardwareCharacteristics()
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if hc.Tags != nil && tagSet.Difference(set.NewStrings(*hc.Tags...)).IsEmpty() {
+				ids = append(ids, machine.Id())
+				continue
+			}
+		}
+		for _, address := range machine.Addresses() {
+			if address.NetworkName != "" && spaceSet.Contains(address.NetworkName) {
+				ids = append(ids, machine.Id())
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
 // Run the commands specified on the machines identified through the
-// list of machines, units and services.
+// list of machines, units and services, and the machine selectors.
 func (c *Client) Run(run params.RunParams) (results params.RunResults, err error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.RunResults{}, errors.Trace(err)
 	}
-	units, err := getAllUnitNames(c.api.state, run.Units, run.Services)
+	execParams, err := c.runParams(run)
 	if err != nil {
 		return results, err
 	}
+	return ParallelExecute(c.getDataDir(), execParams), nil
+}
+
+// RunStreamed starts running the commands on the same targets as Run,
+// and returns the id of a RunWatcher through which the result for each
+// target is reported as soon as it completes.
+func (c *Client) RunStreamed(run params.RunParams) (params.RunWatcherId, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.RunWatcherId{}, errors.Trace(err)
+	}
+	execParams, err := c.runParams(run)
+	if err != nil {
+		return params.RunWatcherId{}, err
+	}
+	w := newRunWatcher(c.getDataDir(), execParams)
+	return params.RunWatcherId{RunWatcherId: c.api.resources.Register(w)}, nil
+}
+
+// runParams returns a RemoteExec for each of the targets of the run.
+func (c *Client) runParams(run params.RunParams) ([]*RemoteExec, error) {
+	units, err := getAllUnitNames(c.api.state, run.Units, run.Services)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := selectMachines(c.api.state, run.Tags, run.Spaces)
+	if err != nil {
+		return nil, err
+	}
+	machineIds := append([]string(nil), run.Machines...)
+	explicit := set.NewStrings(run.Machines...)
+	for _, id := range selected {
+		if !explicit.Contains(id) {
+			machineIds = append(machineIds, id)
+		}
+	}
 	// We want to create a RemoteExec for each unit and each machine.
 	// If we have both a unit and a machine request, we run it twice,
 	// once for the unit inside the exec context using juju-run, and
@@ -103,23 +175,23 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 		machineId, _ := unit.AssignedMachineId()
 		machine, err := c.api.state.Machine(machineId)
 		if err != nil {
-			return results, err
+			return nil, err
 		}
 		command := fmt.Sprintf("juju-run %s %s", unit.Name(), quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
 		execParam.UnitId = unit.Name()
 		params = append(params, execParam)
 	}
-	for _, machineId := range run.Machines {
+	for _, machineId := range machineIds {
 		machine, err := c.api.state.Machine(machineId)
 		if err != nil {
-			return results, err
+			return nil, err
 		}
 		command := fmt.Sprintf("juju-run --no-context %s", quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
 		params = append(params, execParam)
 	}
-	return ParallelExecute(c.getDataDir(), params), nil
+	return params, nil
 }
 
 // RunOnAllMachines attempts to run the specified command on all the machines.
@@ -152,13 +224,24 @@ type RemoteExec struct {
 // ParallelExecute executes all of the requests defined in the params,
 // using the system identity stored in the dataDir.
 func ParallelExecute(dataDir string, runParams []*RemoteExec) params.RunResults {
-	logger.Debugf("exec %#v", runParams)
-	var outstanding sync.WaitGroup
-	var lock sync.Mutex
+	results := make(chan params.RunResult, len(runParams))
+	startExecute(dataDir, runParams, results)
 	var result []params.RunResult
+	for range runParams {
+		result = append(result, <-results)
+	}
+	sort.Sort(MachineOrder(result))
+	return params.RunResults{result}
+}
+
+// startExecute starts executing all of the requests defined in the
+// params, using the system identity stored in the dataDir, and sends
+// the result of each on the channel as it completes. The channel must
+// have room for all the results.
+func startExecute(dataDir string, runParams []*RemoteExec, results chan<- params.RunResult) {
+	logger.Debugf("exec %#v", runParams)
 	identity := filepath.Join(dataDir, agent.SystemIdentity)
 	for _, param := range runParams {
-		outstanding.Add(1)
 		logger.Debugf("exec on %s: %#v", param.MachineId, *param)
 		param.IdentityFile = identity
 		go func(param *RemoteExec) {
@@ -172,17 +255,9 @@ func ParallelExecute(dataDir string, runParams []*RemoteExec) params.RunResults
 			if err != nil {
 				execResponse.Error = fmt.Sprint(err)
 			}
-
-			lock.Lock()
-			defer lock.Unlock()
-			result = append(result, execResponse)
-			outstanding.Done()
+			results <- execResponse
 		}(param)
 	}
-
-	outstanding.Wait()
-	sort.Sort(MachineOrder(result))
-	return params.RunResults{result}
 }
 
 // MachineOrder is used to provide the api to sort the results by the machine
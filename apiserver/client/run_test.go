@@ -5,6 +5,7 @@ package client_test
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	gitjujutesting "github.com/juju/testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -317,6 +319,101 @@ func (s *runSuite) TestRunMachineAndService(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
 
+func (s *runSuite) TestRunMachinesByTagAndSpace(c *gc.C) {
+	addMachine := func(address, space string, tags ...string) {
+		machine := s.addMachine(c)
+		err := machine.SetProvisioned(instance.Id("i-"+machine.Id()), "fake_nonce", &instance.HardwareCharacteristics{Tags: &tags})
+		c.Assert(err, jc.ErrorIsNil)
+		addr := network.NewAddress(address)
+		addr.NetworkName = space
+		err = machine.SetAddresses(addr)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	addMachine("10.3.2.1", "private", "ssd", "gpu")
+	addMachine("10.3.2.2", "private", "ssd")
+	addMachine("10.3.2.3", "private", "gpu", "ssd", "fast")
+	addMachine("10.3.2.4", "public")
+	// Machines that have not been provisioned are never selected.
+	s.addMachineWithAddress(c, "10.3.2.5")
+
+	s.mockSSH(c, echoInput)
+
+	client := s.APIState.Client()
+	results, err := client.Run(
+		params.RunParams{
+			Commands: "hostname",
+			Timeout:  testing.LongWait,
+			Tags:     []string{"gpu", "ssd"},
+			Spaces:   []string{"public"},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var machineIds []string
+	for _, result := range results {
+		c.Check(result.Stdout, gc.DeepEquals, []byte(expectedCommand[0]))
+		machineIds = append(machineIds, result.MachineId)
+	}
+	c.Check(machineIds, jc.DeepEquals, []string{"0", "2", "3"})
+}
+
+func (s *runSuite) TestRunStreamed(c *gc.C) {
+	s.addMachineWithAddress(c, "10.3.2.1")
+
+	charm := s.AddTestingCharm(c, "dummy")
+	owner := s.Factory.MakeUser(c, nil).Tag()
+	magic, err := s.State.AddService("magic", owner.String(), charm, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnit(c, magic)
+	s.addUnit(c, magic)
+
+	s.mockSSH(c, echoInput)
+
+	w, err := s.APIState.Client().RunStreamed(
+		params.RunParams{
+			Commands: "hostname",
+			Timeout:  testing.LongWait,
+			Machines: []string{"0"},
+			Services: []string{"magic"},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Check(w.Stop(), jc.ErrorIsNil)
+	}()
+
+	var results []params.RunResult
+	for {
+		next, err := w.Next()
+		c.Assert(err, jc.ErrorIsNil)
+		results = append(results, next.Results...)
+		if next.Done {
+			break
+		}
+		c.Assert(next.Results, gc.Not(gc.HasLen), 0)
+	}
+	sort.Sort(client.MachineOrder(results))
+	c.Assert(results, jc.DeepEquals, []params.RunResult{
+		{
+			ExecResponse: exec.ExecResponse{Stdout: []byte(expectedCommand[0])},
+			MachineId:    "0",
+		},
+		{
+			ExecResponse: exec.ExecResponse{Stdout: []byte(expectedCommand[1])},
+			MachineId:    "1",
+			UnitId:       "magic/0",
+		},
+		{
+			ExecResponse: exec.ExecResponse{Stdout: []byte(expectedCommand[2])},
+			MachineId:    "2",
+			UnitId:       "magic/1",
+		},
+	})
+
+	// Once every result has been reported, Next returns immediately.
+	next, err := w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(next, jc.DeepEquals, params.RunWatcherNextResult{Done: true})
+}
+
 func (s *runSuite) TestBlockRunMachineAndService(c *gc.C) {
 	// Make three machines.
 	s.addMachineWithAddress(c, "10.3.2.1")
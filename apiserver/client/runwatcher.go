@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterFacade(
+		"RunWatcher", 0, newRunWatcherAPI,
+		reflect.TypeOf((*RunWatcherAPI)(nil)),
+	)
+}
+
+// runWatcher reports the results of commands started by RunStreamed as
+// each of them completes.
+type runWatcher struct {
+	results chan params.RunResult
+	stop    chan struct{}
+	once    sync.Once
+
+	mu      sync.Mutex
+	pending int
+}
+
+// newRunWatcher starts executing the requests defined in the params,
+// and returns a runWatcher that reports their results.
+func newRunWatcher(dataDir string, runParams []*RemoteExec) *runWatcher {
+	w := &runWatcher{
+		results: make(chan params.RunResult, len(runParams)),
+		stop:    make(chan struct{}),
+		pending: len(runParams),
+	}
+	startExecute(dataDir, runParams, w.results)
+	return w
+}
+
+// Stop is part of the common.Resource interface. Commands that are
+// still running are left to complete, but their results are discarded.
+func (w *runWatcher) Stop() error {
+	w.once.Do(func() { close(w.stop) })
+	return nil
+}
+
+// next blocks until at least one command has completed since the last
+// call, and returns the results of all that have. Once every result has
+// been reported, next returns immediately with Done set.
+func (w *runWatcher) next() (params.RunWatcherNextResult, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == 0 {
+		return params.RunWatcherNextResult{Done: true}, nil
+	}
+	var results []params.RunResult
+	select {
+	case <-w.stop:
+		return params.RunWatcherNextResult{}, common.ErrStoppedWatcher
+	case result := <-w.results:
+		results = append(results, result)
+	}
+collect:
+	for w.pending > len(results) {
+		select {
+		case result := <-w.results:
+			results = append(results, result)
+		default:
+			break collect
+		}
+	}
+	w.pending -= len(results)
+	sort.Sort(MachineOrder(results))
+	return params.RunWatcherNextResult{
+		Results: results,
+		Done:    w.pending == 0,
+	}, nil
+}
+
+// RunWatcherAPI implements the API methods of a RunWatcher.
+type RunWatcherAPI struct {
+	watcher   *runWatcher
+	id        string
+	resources *common.Resources
+}
+
+func newRunWatcherAPI(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	w, ok := resources.Get(id).(*runWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &RunWatcherAPI{
+		watcher:   w,
+		id:        id,
+		resources: resources,
+	}, nil
+}
+
+// Next returns the results of the commands that have completed since
+// the last call to Next, blocking until there is at least one.
+func (w *RunWatcherAPI) Next() (params.RunWatcherNextResult, error) {
+	return w.watcher.next()
+}
+
+// Stop stops the watcher.
+func (w *RunWatcherAPI) Stop() error {
+	return w.resources.Stop(w.id)
+}
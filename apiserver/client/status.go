@@ -8,10 +8,12 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
@@ -22,20 +24,40 @@ import (
 
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
-	cfg, err := c.api.state.EnvironConfig()
+	if len(args.Patterns) > 0 {
+		return c.fullStatus(args)
+	}
+	// The unfiltered status is cached until anything shown in it is
+	// seen to change; see statusCache.
+	return statusCaches.get(c.api.state, func() (api.Status, error) {
+		return c.fullStatus(args)
+	})
+}
+
+// fullStatus computes the status of the environment from state.
+//
+// Status is read with the read preference configured for the
+// common.ReadStatus category, so it may be served by a secondary.
+func (c *Client) fullStatus(args params.StatusParams) (api.Status, error) {
+	st, release, err := common.ReadState(c.api.state, c.api.resources, common.ReadStatus)
+	if err != nil {
+		return api.Status{}, errors.Trace(err)
+	}
+	defer release()
+	cfg, err := st.EnvironConfig()
 	if err != nil {
 		return api.Status{}, errors.Annotate(err, "could not get environ config")
 	}
 	var noStatus api.Status
 	var context statusContext
 	if context.services, context.units, context.latestCharms, err =
-		fetchAllServicesAndUnits(c.api.state, len(args.Patterns) <= 0); err != nil {
+		fetchAllServicesAndUnits(st, len(args.Patterns) <= 0); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
-	} else if context.machines, err = fetchMachines(c.api.state, nil); err != nil {
+	} else if context.machines, err = fetchMachines(st, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
-	} else if context.relations, err = fetchRelations(c.api.state); err != nil {
+	} else if context.relations, err = fetchRelations(st); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch relations")
-	} else if context.networks, err = fetchNetworks(c.api.state); err != nil {
+	} else if context.networks, err = fetchNetworks(st); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
 	}
 
@@ -138,6 +160,32 @@ func (c *Client) Status() (api.LegacyStatus, error) {
 	return legacyStatus, nil
 }
 
+// StatusHistory returns the most recent statuses set on the given
+// machine or unit, most recent first.
+func (c *Client) StatusHistory(args params.StatusHistory) (params.StatusHistoryResults, error) {
+	tag, err := names.ParseTag(args.Tag)
+	if err != nil {
+		return params.StatusHistoryResults{}, errors.Trace(err)
+	}
+	entries, err := c.api.state.StatusHistory(tag, args.Size)
+	if err != nil {
+		return params.StatusHistoryResults{}, errors.Trace(err)
+	}
+	results := params.StatusHistoryResults{
+		Statuses: make([]params.StatusHistoryEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		results.Statuses[i] = params.StatusHistoryEntry{
+			Kind:   string(entry.Kind),
+			Status: params.Status(entry.Status),
+			Info:   entry.Info,
+			Data:   entry.Data,
+			Since:  entry.Since,
+		}
+	}
+	return results, nil
+}
+
 type statusContext struct {
 	// machines: top-level machine id -> list of machines nested in
 	// this machine.
@@ -361,10 +409,29 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 	} else {
 		status.Hardware = hc.String()
 	}
+	status.AgentHealth = processAgentHealth(machine)
 	status.Containers = make(map[string]api.MachineStatus)
 	return
 }
 
+// processAgentHealth returns the health most recently reported by the
+// entity's agent, or nil if none is available.
+func processAgentHealth(entity state.AgentHealthGetter) *params.AgentHealth {
+	health, err := entity.AgentHealth()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		logger.Debugf("cannot get agent health: %v", err)
+		return nil
+	}
+	return &params.AgentHealth{
+		HookQueueDepth:   health.HookQueueDepth,
+		LastAPICall:      health.LastAPICall,
+		DataDirAvailable: health.DataDirAvailable,
+		Reported:         health.Reported,
+	}
+}
+
 func (context *statusContext) processRelations() []api.RelationStatus {
 	var out []api.RelationStatus
 	relations := context.getAllRelations()
@@ -529,6 +596,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	status.AgentVersion = status.Workload.Version
 	status.Life = status.Workload.Life
 	status.Err = status.Workload.Err
+	status.AgentHealth = processAgentHealth(unit)
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		status.Subordinates = make(map[string]api.UnitStatus)
@@ -4,12 +4,17 @@
 package client_test
 
 import (
+	"time"
+
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -45,6 +50,49 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusCached(c *gc.C) {
+	s.PatchValue(client.StatusCacheMinEntities, 1)
+	s.PatchValue(client.StatusCacheMaxAge, time.Hour)
+	s.addMachine(c)
+	apiClient := s.APIState.Client()
+	status, err := apiClient.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Machines, gc.HasLen, 1)
+
+	// The environment is now large enough for its status to be
+	// cached, until the allwatcher sees it change.
+	s.addMachine(c)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.BackingState.StartSync()
+		status, err = apiClient.Status(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(status.Machines) == 2 {
+			return
+		}
+	}
+	c.Fatalf("cached status not invalidated")
+}
+
+func (s *statusSuite) TestStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "disk full", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.APIState.Client().StatusHistory(machine.Tag(), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Kind, gc.Equals, "machine")
+	c.Check(history[0].Status, gc.Equals, params.StatusError)
+	c.Check(history[0].Info, gc.Equals, "disk full")
+}
+
+func (s *statusSuite) TestStatusHistoryUnsupportedEntity(c *gc.C) {
+	_, err := s.APIState.Client().StatusHistory(names.NewServiceTag("wordpress"), 1)
+	c.Assert(err, gc.ErrorMatches, `status history for service "wordpress" not supported`)
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
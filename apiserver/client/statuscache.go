@@ -0,0 +1,422 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/presence"
+)
+
+var (
+	// statusCacheMinEntities holds the number of machines and units
+	// an environment must have before its status is cached. Smaller
+	// environments are quick enough to compute status for on every
+	// call, so they are spared the cost of the watchers, and their
+	// status never lags behind state.
+	statusCacheMinEntities = 100
+
+	// statusCacheMaxAge holds the time after which a cached status is
+	// recomputed even if nothing was seen to change. Some of what
+	// status shows, such as agent versions, is seen by neither the
+	// allwatcher nor the presence watcher, so this bounds how long
+	// changes to it take to show.
+	statusCacheMaxAge = 10 * time.Second
+)
+
+// uncachedDeltaKinds holds the kinds of entity that do not appear in
+// status, so that changes to them do not invalidate the cached status.
+var uncachedDeltaKinds = set.NewStrings("action", "annotation", "block")
+
+// allWatcher is the part of a state.Multiwatcher used by a statusCache.
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+}
+
+// agentPresenceWatcher is the part of a *state.State used by a
+// statusCache to learn when the agents shown in status come and go.
+type agentPresenceWatcher interface {
+	WatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error
+	UnwatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error
+}
+
+// statusCache holds the most recently computed, unfiltered status of an
+// environment. The status is still computed from state, entity by
+// entity, exactly as it is when it is not cached; the allwatcher and
+// the presence of the agents shown are only watched to tell when the
+// cached status must be thrown away, which is whenever anything shown
+// in status changes. Status cannot be assembled from the allwatcher's
+// entity infos themselves, as they do not hold agent presence and
+// versions, networks or available charm upgrades.
+//
+// The cache therefore speeds up FullStatus calls made while the
+// environment is quiet, but not the first call after a change. As
+// with any allwatcher client, the cached status may lag changes to
+// state by a few seconds.
+type statusCache struct {
+	presence        agentPresenceWatcher
+	presenceChanges chan presence.Change
+	done            chan struct{}
+
+	// computeMu serializes the computation of status, so that many
+	// concurrent calls on an invalidated cache compute it only once.
+	// It also guards watched.
+	computeMu sync.Mutex
+	watched   map[names.Tag]bool
+
+	// mu guards the fields below.
+	mu        sync.Mutex
+	gen       int
+	status    *api.Status
+	cachedGen int
+	expires   time.Time
+	dead      bool
+	alive     map[string]bool
+}
+
+// newStatusCache returns a new statusCache fed by the given watchers.
+// The cache stops being used, and onDead is called, when the allwatcher
+// fails.
+func newStatusCache(w allWatcher, pw agentPresenceWatcher, onDead func()) *statusCache {
+	cache := &statusCache{
+		presence:        pw,
+		presenceChanges: make(chan presence.Change),
+		done:            make(chan struct{}),
+		watched:         make(map[names.Tag]bool),
+		alive:           make(map[string]bool),
+	}
+	go cache.presenceLoop()
+	go cache.loop(w, onDead)
+	return cache
+}
+
+func (c *statusCache) loop(w allWatcher, onDead func()) {
+	defer onDead()
+	for {
+		deltas, err := w.Next()
+		if err != nil {
+			logger.Debugf("status cache stopped: %v", err)
+			c.stop()
+			return
+		}
+		for _, delta := range deltas {
+			if !uncachedDeltaKinds.Contains(delta.Entity.EntityId().Kind) {
+				c.invalidate()
+				break
+			}
+		}
+	}
+}
+
+// presenceLoop invalidates the cached status whenever an agent shown
+// in it comes or goes. The changes must be consumed until the cache
+// stops watching agent presence, or the presence watcher blocks.
+func (c *statusCache) presenceLoop() {
+	for {
+		select {
+		case change := <-c.presenceChanges:
+			c.mu.Lock()
+			// The first change for an agent reports its presence
+			// when it started being watched.
+			if alive, ok := c.alive[change.Key]; ok && alive != change.Alive {
+				c.gen++
+			}
+			c.alive[change.Key] = change.Alive
+			c.mu.Unlock()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *statusCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+}
+
+// stop marks the cache dead and stops watching agent presence.
+func (c *statusCache) stop() {
+	c.computeMu.Lock()
+	defer c.computeMu.Unlock()
+	c.mu.Lock()
+	c.dead = true
+	c.status = nil
+	c.mu.Unlock()
+	c.watchAgents(nil)
+	close(c.done)
+}
+
+// watchAgents watches the presence of the given agents, and of no
+// others. It must be called with computeMu held.
+func (c *statusCache) watchAgents(tags []names.Tag) {
+	wanted := make(map[names.Tag]bool)
+	for _, tag := range tags {
+		wanted[tag] = true
+	}
+	for tag := range c.watched {
+		if wanted[tag] {
+			continue
+		}
+		if err := c.presence.UnwatchAgentPresence(tag, c.presenceChanges); err != nil {
+			logger.Debugf("cannot stop watching presence of %s: %v", tag, err)
+		}
+		delete(c.watched, tag)
+	}
+	for tag := range wanted {
+		if c.watched[tag] {
+			continue
+		}
+		if err := c.presence.WatchAgentPresence(tag, c.presenceChanges); err != nil {
+			logger.Debugf("cannot watch presence of %s: %v", tag, err)
+			continue
+		}
+		c.watched[tag] = true
+	}
+}
+
+// get returns the cached status if it is current, and otherwise the
+// result of compute, which is cached for later calls. The caller owns
+// the returned status, which it may modify.
+func (c *statusCache) get(compute func() (api.Status, error)) (api.Status, error) {
+	c.computeMu.Lock()
+	defer c.computeMu.Unlock()
+
+	c.mu.Lock()
+	if c.status != nil && c.cachedGen == c.gen && time.Now().Before(c.expires) {
+		status := copyStatus(*c.status)
+		c.mu.Unlock()
+		return status, nil
+	}
+	gen := c.gen
+	dead := c.dead
+	c.mu.Unlock()
+	if dead {
+		return compute()
+	}
+
+	status, err := compute()
+	if err != nil {
+		return status, err
+	}
+	// The cache is only marked dead with computeMu held, so it is
+	// still alive.
+	c.watchAgents(statusAgentTags(status))
+	cached := copyStatus(status)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// If anything changed while status was being computed, the
+	// cached status is already out of date and will be recomputed
+	// on the next call.
+	c.status = &cached
+	c.cachedGen = gen
+	c.expires = time.Now().Add(statusCacheMaxAge)
+	return status, nil
+}
+
+// statusCaches holds the status caches for large environments, keyed
+// by the *state.State they are computed from. A cache is removed when
+// its State is closed, which stops the allwatcher feeding it.
+var statusCaches = &statusCacheSet{
+	caches: make(map[*state.State]*statusCache),
+}
+
+type statusCacheSet struct {
+	mu     sync.Mutex
+	caches map[*state.State]*statusCache
+}
+
+// get returns the status of st's environment, as computed by compute,
+// from the environment's status cache if it has one. A cache is
+// created for the environment once it's found to be large enough.
+func (s *statusCacheSet) get(st *state.State, compute func() (api.Status, error)) (api.Status, error) {
+	s.mu.Lock()
+	cache := s.caches[st]
+	s.mu.Unlock()
+	if cache != nil {
+		return cache.get(compute)
+	}
+
+	status, err := compute()
+	if err != nil || statusEntityCount(status) < statusCacheMinEntities {
+		return status, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.caches[st] == nil {
+		logger.Debugf("caching status for environment %q", st.EnvironUUID())
+		var cache *statusCache
+		cache = newStatusCache(st.Watch(), st, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.caches[st] == cache {
+				delete(s.caches, st)
+			}
+		})
+		s.caches[st] = cache
+	}
+	return status, nil
+}
+
+// statusEntityCount returns the number of machines, containers and
+// units in the status.
+func statusEntityCount(status api.Status) int {
+	count := 0
+	var countMachines func(map[string]api.MachineStatus)
+	countMachines = func(machines map[string]api.MachineStatus) {
+		for _, machine := range machines {
+			count++
+			countMachines(machine.Containers)
+		}
+	}
+	countMachines(status.Machines)
+	for _, service := range status.Services {
+		count += len(service.Units)
+	}
+	return count
+}
+
+// statusAgentTags returns the tags of the machines, containers and
+// units in the status, whose agents' presence is shown in it.
+func statusAgentTags(status api.Status) []names.Tag {
+	var tags []names.Tag
+	var addMachines func(map[string]api.MachineStatus)
+	addMachines = func(machines map[string]api.MachineStatus) {
+		for id, machine := range machines {
+			if names.IsValidMachine(id) {
+				tags = append(tags, names.NewMachineTag(id))
+			}
+			addMachines(machine.Containers)
+		}
+	}
+	var addUnits func(map[string]api.UnitStatus)
+	addUnits = func(units map[string]api.UnitStatus) {
+		for name, unit := range units {
+			if names.IsValidUnit(name) {
+				tags = append(tags, names.NewUnitTag(name))
+			}
+			addUnits(unit.Subordinates)
+		}
+	}
+	addMachines(status.Machines)
+	for _, service := range status.Services {
+		addUnits(service.Units)
+	}
+	return tags
+}
+
+// copyStatus returns a deep copy of the given status, so that the
+// cached status is not shared with callers.
+func copyStatus(status api.Status) api.Status {
+	result := status
+	if status.Machines != nil {
+		result.Machines = make(map[string]api.MachineStatus, len(status.Machines))
+		for id, machine := range status.Machines {
+			result.Machines[id] = copyMachineStatus(machine)
+		}
+	}
+	if status.Services != nil {
+		result.Services = make(map[string]api.ServiceStatus, len(status.Services))
+		for name, service := range status.Services {
+			result.Services[name] = copyServiceStatus(service)
+		}
+	}
+	if status.Networks != nil {
+		result.Networks = make(map[string]api.NetworkStatus, len(status.Networks))
+		for name, network := range status.Networks {
+			result.Networks[name] = network
+		}
+	}
+	if status.Relations != nil {
+		result.Relations = make([]api.RelationStatus, len(status.Relations))
+		for i, relation := range status.Relations {
+			relation.Endpoints = append([]api.EndpointStatus(nil), relation.Endpoints...)
+			result.Relations[i] = relation
+		}
+	}
+	return result
+}
+
+func copyMachineStatus(machine api.MachineStatus) api.MachineStatus {
+	machine.Agent = copyAgentStatus(machine.Agent)
+	if machine.Containers != nil {
+		containers := make(map[string]api.MachineStatus, len(machine.Containers))
+		for id, container := range machine.Containers {
+			containers[id] = copyMachineStatus(container)
+		}
+		machine.Containers = containers
+	}
+	if machine.Jobs != nil {
+		machine.Jobs = append([]multiwatcher.MachineJob(nil), machine.Jobs...)
+	}
+	if machine.AgentHealth != nil {
+		health := *machine.AgentHealth
+		machine.AgentHealth = &health
+	}
+	return machine
+}
+
+func copyServiceStatus(service api.ServiceStatus) api.ServiceStatus {
+	if service.Relations != nil {
+		relations := make(map[string][]string, len(service.Relations))
+		for name, related := range service.Relations {
+			relations[name] = copyStrings(related)
+		}
+		service.Relations = relations
+	}
+	service.Networks.Enabled = copyStrings(service.Networks.Enabled)
+	service.Networks.Disabled = copyStrings(service.Networks.Disabled)
+	service.SubordinateTo = copyStrings(service.SubordinateTo)
+	if service.Units != nil {
+		units := make(map[string]api.UnitStatus, len(service.Units))
+		for name, unit := range service.Units {
+			units[name] = copyUnitStatus(unit)
+		}
+		service.Units = units
+	}
+	return service
+}
+
+func copyUnitStatus(unit api.UnitStatus) api.UnitStatus {
+	unit.UnitAgent = copyAgentStatus(unit.UnitAgent)
+	unit.Workload = copyAgentStatus(unit.Workload)
+	unit.OpenedPorts = copyStrings(unit.OpenedPorts)
+	if unit.Subordinates != nil {
+		subordinates := make(map[string]api.UnitStatus, len(unit.Subordinates))
+		for name, subordinate := range unit.Subordinates {
+			subordinates[name] = copyUnitStatus(subordinate)
+		}
+		unit.Subordinates = subordinates
+	}
+	if unit.AgentHealth != nil {
+		health := *unit.AgentHealth
+		unit.AgentHealth = &health
+	}
+	return unit
+}
+
+func copyAgentStatus(agent api.AgentStatus) api.AgentStatus {
+	if agent.Data != nil {
+		data := make(map[string]interface{}, len(agent.Data))
+		for key, value := range agent.Data {
+			data[key] = value
+		}
+		agent.Data = data
+	}
+	return agent
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
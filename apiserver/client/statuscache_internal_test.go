@@ -0,0 +1,267 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/presence"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type statusCacheSuite struct {
+	coretesting.BaseSuite
+
+	watcher  *fakeAllWatcher
+	presence *fakePresenceWatcher
+	dead     chan struct{}
+	cache    *statusCache
+	computed int
+}
+
+var _ = gc.Suite(&statusCacheSuite{})
+
+func (s *statusCacheSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.watcher = &fakeAllWatcher{
+		deltas:  make(chan []multiwatcher.Delta),
+		stopped: make(chan struct{}),
+	}
+	s.presence = &fakePresenceWatcher{
+		watched: make(map[names.Tag]chan<- presence.Change),
+	}
+	s.dead = make(chan struct{})
+	s.cache = newStatusCache(s.watcher, s.presence, func() { close(s.dead) })
+	s.computed = 0
+	s.AddCleanup(func(c *gc.C) {
+		s.watcher.stop()
+		select {
+		case <-s.dead:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("status cache did not stop")
+		}
+	})
+}
+
+func (s *statusCacheSuite) compute() (api.Status, error) {
+	s.computed++
+	return api.Status{
+		EnvironmentName: "dummyenv",
+		Machines: map[string]api.MachineStatus{
+			"0": {Containers: map[string]api.MachineStatus{"0/lxc/0": {}}},
+		},
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {Units: map[string]api.UnitStatus{
+				"wordpress/0": {Subordinates: map[string]api.UnitStatus{"logging/0": {}}},
+			}},
+		},
+	}, nil
+}
+
+func (s *statusCacheSuite) assertGet(c *gc.C, expectComputed int) {
+	status, err := s.cache.get(s.compute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.EnvironmentName, gc.Equals, "dummyenv")
+	c.Assert(s.computed, gc.Equals, expectComputed)
+}
+
+// sendDeltas sends the deltas to the cache, followed by an ignored
+// delta so that the deltas are known to have been processed on return.
+func (s *statusCacheSuite) sendDeltas(c *gc.C, deltas ...multiwatcher.Delta) {
+	barrier := multiwatcher.Delta{Entity: &multiwatcher.AnnotationInfo{Tag: "machine-0"}}
+	for _, d := range [][]multiwatcher.Delta{deltas, {barrier}} {
+		select {
+		case s.watcher.deltas <- d:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("status cache did not receive deltas")
+		}
+	}
+}
+
+func (s *statusCacheSuite) TestCachesStatus(c *gc.C) {
+	s.assertGet(c, 1)
+	s.assertGet(c, 1)
+}
+
+func (s *statusCacheSuite) TestDeltasInvalidateStatus(c *gc.C) {
+	s.assertGet(c, 1)
+	s.sendDeltas(c, multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{Name: "wordpress/0"}})
+	s.assertGet(c, 2)
+	s.assertGet(c, 2)
+}
+
+func (s *statusCacheSuite) TestIgnoredDeltasDoNotInvalidateStatus(c *gc.C) {
+	s.assertGet(c, 1)
+	s.sendDeltas(c,
+		multiwatcher.Delta{Entity: &multiwatcher.BlockInfo{Id: "0"}},
+		multiwatcher.Delta{Entity: &multiwatcher.ActionInfo{Id: "1"}},
+	)
+	s.assertGet(c, 1)
+}
+
+func (s *statusCacheSuite) TestStatusExpires(c *gc.C) {
+	s.PatchValue(&statusCacheMaxAge, time.Duration(0))
+	s.assertGet(c, 1)
+	s.assertGet(c, 2)
+}
+
+func (s *statusCacheSuite) TestErrorsNotCached(c *gc.C) {
+	_, err := s.cache.get(func() (api.Status, error) {
+		return api.Status{}, errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.assertGet(c, 1)
+}
+
+func (s *statusCacheSuite) TestDeadCacheNotUsed(c *gc.C) {
+	s.assertGet(c, 1)
+	s.watcher.stop()
+	select {
+	case <-s.dead:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("status cache did not stop")
+	}
+	s.assertGet(c, 2)
+	s.assertGet(c, 3)
+}
+
+func (s *statusCacheSuite) TestWatchesAgentPresence(c *gc.C) {
+	s.assertGet(c, 1)
+	c.Assert(s.presence.watchedTags(), jc.DeepEquals, []string{
+		"machine-0", "machine-0-lxc-0", "unit-logging-0", "unit-wordpress-0",
+	})
+}
+
+func (s *statusCacheSuite) TestPresenceChangesInvalidateStatus(c *gc.C) {
+	s.assertGet(c, 1)
+	s.presence.send(c, names.NewUnitTag("wordpress/0"), true)
+	s.assertGet(c, 1)
+	s.presence.send(c, names.NewUnitTag("wordpress/0"), false)
+	s.assertGet(c, 2)
+	s.assertGet(c, 2)
+}
+
+func (s *statusCacheSuite) TestDeadCacheUnwatchesAgentPresence(c *gc.C) {
+	s.assertGet(c, 1)
+	s.watcher.stop()
+	select {
+	case <-s.dead:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("status cache did not stop")
+	}
+	c.Assert(s.presence.watchedTags(), gc.HasLen, 0)
+}
+
+func (s *statusCacheSuite) TestCachedStatusNotShared(c *gc.C) {
+	status, err := s.cache.get(s.compute)
+	c.Assert(err, jc.ErrorIsNil)
+	delete(status.Machines, "0")
+	status.Services["wordpress"].Units["wordpress/0"].Subordinates["logging/1"] = api.UnitStatus{}
+
+	status, err = s.cache.get(s.compute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.computed, gc.Equals, 1)
+	c.Assert(status.Machines, gc.HasLen, 1)
+	c.Assert(status.Services["wordpress"].Units["wordpress/0"].Subordinates, gc.HasLen, 1)
+	delete(status.Services, "wordpress")
+
+	status, err = s.cache.get(s.compute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.computed, gc.Equals, 1)
+	c.Assert(status.Services, gc.HasLen, 1)
+}
+
+func (s *statusCacheSuite) TestStatusEntityCount(c *gc.C) {
+	status := api.Status{
+		Machines: map[string]api.MachineStatus{
+			"0": {Containers: map[string]api.MachineStatus{"0/lxc/0": {}}},
+			"1": {},
+		},
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {Units: map[string]api.UnitStatus{"wordpress/0": {}, "wordpress/1": {}}},
+		},
+	}
+	c.Assert(statusEntityCount(status), gc.Equals, 5)
+}
+
+type fakeAllWatcher struct {
+	deltas  chan []multiwatcher.Delta
+	stopped chan struct{}
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	select {
+	case deltas := <-w.deltas:
+		return deltas, nil
+	case <-w.stopped:
+		return nil, errors.New("watcher stopped")
+	}
+}
+
+func (w *fakeAllWatcher) stop() {
+	select {
+	case <-w.stopped:
+	default:
+		close(w.stopped)
+	}
+}
+
+type fakePresenceWatcher struct {
+	mu      sync.Mutex
+	watched map[names.Tag]chan<- presence.Change
+}
+
+func (w *fakePresenceWatcher) WatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watched[tag] = ch
+	return nil
+}
+
+func (w *fakePresenceWatcher) UnwatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.watched, tag)
+	return nil
+}
+
+func (w *fakePresenceWatcher) watchedTags() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var tags []string
+	for tag := range w.watched {
+		tags = append(tags, tag.String())
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// send reports a change to the presence of the agent with the given
+// tag, followed by an unchanged presence so that the change is known
+// to have been processed on return.
+func (w *fakePresenceWatcher) send(c *gc.C, tag names.Tag, alive bool) {
+	w.mu.Lock()
+	ch := w.watched[tag]
+	w.mu.Unlock()
+	c.Assert(ch, gc.NotNil)
+	for _, change := range []presence.Change{
+		{Key: tag.String(), Alive: alive},
+		{Key: "barrier", Alive: true},
+	} {
+		select {
+		case ch <- change:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("status cache did not receive presence change")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5-unstable"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ValidateServiceSetConfig checks the given configuration values
+// against the options declared by the charm of the service, without
+// changing the service's configuration. The result holds a message for
+// each invalid option.
+func (c *Client) ValidateServiceSetConfig(p params.ValidateServiceSetConfig) (params.ServiceConfigValidationResult, error) {
+	var result params.ServiceConfigValidationResult
+	svc, err := c.api.state.Service(p.ServiceName)
+	if err != nil {
+		return result, err
+	}
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return result, err
+	}
+	if p.ConfigYAML != "" {
+		result.Errors, err = settingsYAMLErrors(ch.Config(), p.ConfigYAML, p.ServiceName)
+		if err != nil {
+			return result, err
+		}
+	} else {
+		result.Errors = settingsStringsErrors(ch.Config(), p.Options)
+	}
+	return result, nil
+}
+
+// settingsStringsErrors checks each of the given option values against
+// the charm config, and returns a message for each invalid option,
+// keyed by option name.
+func settingsStringsErrors(config *charm.Config, settings map[string]string) map[string]string {
+	errs := make(map[string]string)
+	for name, value := range settings {
+		if _, err := config.ParseSettingsStrings(map[string]string{name: value}); err != nil {
+			errs[name] = err.Error()
+		}
+	}
+	return errs
+}
+
+// settingsYAMLErrors is like settingsStringsErrors, but takes the
+// option values of the named service from YAML.
+func settingsYAMLErrors(config *charm.Config, yamlData, serviceName string) (map[string]string, error) {
+	var allSettings map[string]map[string]interface{}
+	if err := goyaml.Unmarshal([]byte(yamlData), &allSettings); err != nil {
+		return nil, errors.Errorf("cannot parse settings data: %v", err)
+	}
+	settings, ok := allSettings[serviceName]
+	if !ok {
+		return nil, errors.Errorf("no settings found for %q", serviceName)
+	}
+	errs := make(map[string]string)
+	for name, value := range settings {
+		data, err := goyaml.Marshal(map[string]interface{}{
+			serviceName: map[string]interface{}{name: value},
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := config.ParseSettingsYAML(data, serviceName); err != nil {
+			errs[name] = err.Error()
+		}
+	}
+	return errs, nil
+}
+
+// settingsError returns an error describing all the invalid options,
+// in name order, or nil if there are none.
+func settingsError(errs map[string]string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = errs[name]
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// parseSettingsYAML is like charm.Config.ParseSettingsYAML, but it
+// reports all the invalid options rather than only the first one.
+func parseSettingsYAML(config *charm.Config, yamlData, serviceName string) (charm.Settings, error) {
+	settings, err := config.ParseSettingsYAML([]byte(yamlData), serviceName)
+	if err == nil {
+		return settings, nil
+	}
+	if errs, yamlErr := settingsYAMLErrors(config, yamlData, serviceName); yamlErr == nil && len(errs) > 0 {
+		return nil, settingsError(errs)
+	}
+	return nil, err
+}
@@ -5,12 +5,14 @@ package common
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/state"
 )
 
 type BlockGetter interface {
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+	GetBlockForEntity(t state.BlockType, tag names.Tag) (state.Block, bool, error)
 }
 
 // BlockChecker checks for current blocks if any.
@@ -26,38 +28,54 @@ func NewBlockChecker(s BlockGetter) *BlockChecker {
 // Change block prevents all operations that may change
 // current environment in any way from running successfully.
 func (c *BlockChecker) ChangeAllowed() error {
-	return c.checkBlock(state.ChangeBlock)
+	return c.ChangeAllowedFor()
+}
+
+// ChangeAllowedFor checks if change block is in place for the
+// environment or for any of the services and machines with the
+// given tags.
+func (c *BlockChecker) ChangeAllowedFor(tags ...names.Tag) error {
+	return c.checkBlock(state.ChangeBlock, tags)
 }
 
 // RemoveAllowed checks if remove block is in place.
 // Remove block prevents removal of machine, service, unit
 // and relation from current environment.
 func (c *BlockChecker) RemoveAllowed() error {
-	if err := c.checkBlock(state.RemoveBlock); err != nil {
+	return c.RemoveAllowedFor()
+}
+
+// RemoveAllowedFor checks if remove or change block is in place for
+// the environment or for any of the services and machines with the
+// given tags.
+func (c *BlockChecker) RemoveAllowedFor(tags ...names.Tag) error {
+	if err := c.checkBlock(state.RemoveBlock, tags); err != nil {
 		return err
 	}
 	// Check if change block has been enabled
-	return c.checkBlock(state.ChangeBlock)
+	return c.checkBlock(state.ChangeBlock, tags)
 }
 
 // DestroyAllowed checks if destroy block is in place.
 // Destroy block prevents destruction of current environment.
 func (c *BlockChecker) DestroyAllowed() error {
-	if err := c.checkBlock(state.DestroyBlock); err != nil {
+	if err := c.checkBlock(state.DestroyBlock, nil); err != nil {
 		return err
 	}
 	// Check if remove block has been enabled
-	if err := c.checkBlock(state.RemoveBlock); err != nil {
+	if err := c.checkBlock(state.RemoveBlock, nil); err != nil {
 		return err
 	}
 	// Check if change block has been enabled
-	return c.checkBlock(state.ChangeBlock)
+	return c.checkBlock(state.ChangeBlock, nil)
 }
 
-// checkBlock checks if specified operation must be blocked.
+// checkBlock checks if specified operation must be blocked, either
+// for the whole environment or for any of the entities with the
+// given tags.
 // If it does, the method throws specific error that can be examined
 // to stop operation execution.
-func (c *BlockChecker) checkBlock(blockType state.BlockType) error {
+func (c *BlockChecker) checkBlock(blockType state.BlockType, tags []names.Tag) error {
 	aBlock, isEnabled, err := c.getter.GetBlockForType(blockType)
 	if err != nil {
 		return errors.Trace(err)
@@ -65,5 +83,14 @@ func (c *BlockChecker) checkBlock(blockType state.BlockType) error {
 	if isEnabled {
 		return ErrOperationBlocked(aBlock.Message())
 	}
+	for _, tag := range tags {
+		aBlock, isEnabled, err := c.getter.GetBlockForEntity(blockType, tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if isEnabled {
+			return ErrOperationBlocked(aBlock.Message())
+		}
+	}
 	return nil
 }
@@ -32,6 +32,7 @@ type blockCheckerSuite struct {
 	testing.FakeJujuHomeSuite
 	aBlock                  state.Block
 	destroy, remove, change state.Block
+	entityBlocks            map[names.Tag]state.Block
 
 	blockchecker *common.BlockChecker
 }
@@ -43,6 +44,7 @@ func (s *blockCheckerSuite) SetUpTest(c *gc.C) {
 	s.destroy = mockBlock{t: state.DestroyBlock, m: "Mock BLOCK testing: DESTROY"}
 	s.remove = mockBlock{t: state.RemoveBlock, m: "Mock BLOCK testing: REMOVE"}
 	s.change = mockBlock{t: state.ChangeBlock, m: "Mock BLOCK testing: CHANGE"}
+	s.entityBlocks = make(map[names.Tag]state.Block)
 	s.blockchecker = common.NewBlockChecker(s)
 }
 
@@ -54,6 +56,13 @@ func (mock *blockCheckerSuite) GetBlockForType(t state.BlockType) (state.Block,
 	}
 }
 
+func (mock *blockCheckerSuite) GetBlockForEntity(t state.BlockType, tag names.Tag) (state.Block, bool, error) {
+	if block, ok := mock.entityBlocks[tag]; ok && block.Type() == t {
+		return block, true, nil
+	}
+	return nil, false, nil
+}
+
 func (s *blockCheckerSuite) TestDestroyBlockChecker(c *gc.C) {
 	s.aBlock = s.destroy
 	s.assertErrorBlocked(c, true, s.blockchecker.DestroyAllowed(), s.destroy.Message())
@@ -87,6 +96,22 @@ func (s *blockCheckerSuite) TestChangeBlockChecker(c *gc.C) {
 	s.assertErrorBlocked(c, true, s.blockchecker.ChangeAllowed(), s.change.Message())
 }
 
+func (s *blockCheckerSuite) TestEntityBlockChecker(c *gc.C) {
+	// The environment is only blocked from being destroyed.
+	s.aBlock = s.destroy
+	mysql := names.NewServiceTag("mysql")
+	wordpress := names.NewServiceTag("wordpress")
+	s.entityBlocks[mysql] = s.change
+	s.assertErrorBlocked(c, false, s.blockchecker.ChangeAllowed(), s.change.Message())
+	s.assertErrorBlocked(c, false, s.blockchecker.ChangeAllowedFor(wordpress), s.change.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.ChangeAllowedFor(wordpress, mysql), s.change.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(mysql), s.change.Message())
+
+	s.entityBlocks[mysql] = s.remove
+	s.assertErrorBlocked(c, false, s.blockchecker.ChangeAllowedFor(mysql), s.remove.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(mysql), s.remove.Message())
+}
+
 func (s *blockCheckerSuite) assertErrorBlocked(c *gc.C, blocked bool, err error, msg string) {
 	if blocked {
 		c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ParseTags validates all the tags passed to a bulk API call up front,
+// so that a single malformed entry cannot abort the whole call. The
+// returned slices have the same length as tags; for each entry, exactly
+// one of the tag and the error is nil.
+//
+// A tag that cannot be parsed, is not of one of the given kinds (any
+// kind is accepted if none are given), or is rejected by canAccess
+// yields ErrPerm, so that clients cannot use a bulk call to probe for
+// entities they are not allowed to access. A nil canAccess accepts all
+// well-formed tags.
+func ParseTags(tags []string, canAccess AuthFunc, kinds ...string) ([]names.Tag, []error) {
+	parsed := make([]names.Tag, len(tags))
+	errs := make([]error, len(tags))
+	for i, tag := range tags {
+		parsed[i], errs[i] = parseTag(tag, canAccess, kinds)
+	}
+	return parsed, errs
+}
+
+func parseTag(tag string, canAccess AuthFunc, kinds []string) (names.Tag, error) {
+	t, err := names.ParseTag(tag)
+	if err != nil {
+		return nil, ErrPerm
+	}
+	if len(kinds) > 0 && !isKind(t, kinds) {
+		return nil, ErrPerm
+	}
+	if canAccess != nil && !canAccess(t) {
+		return nil, ErrPerm
+	}
+	return t, nil
+}
+
+func isKind(tag names.Tag, kinds []string) bool {
+	for _, kind := range kinds {
+		if tag.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityTags returns the tags of the entities passed to a bulk API
+// call, in order, for use with ParseTags.
+func EntityTags(args params.Entities) []string {
+	tags := make([]string, len(args.Entities))
+	for i, entity := range args.Entities {
+		tags[i] = entity.Tag
+	}
+	return tags
+}
+
+// StorageAttachmentTags holds the parsed tags identifying a storage
+// attachment.
+type StorageAttachmentTags struct {
+	StorageTag names.StorageTag
+	UnitTag    names.UnitTag
+}
+
+// ParseStorageAttachmentIds validates all the storage attachment ids
+// passed to a bulk API call up front. The returned slices have the same
+// length as ids; an entry's tags are only meaningful if its error is nil.
+//
+// A unit tag that is malformed or rejected by canAccess yields ErrPerm;
+// a malformed storage tag of an accessible unit yields a NotValid error.
+func ParseStorageAttachmentIds(ids []params.StorageAttachmentId, canAccess AuthFunc) ([]StorageAttachmentTags, []error) {
+	parsed := make([]StorageAttachmentTags, len(ids))
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		unitTag, err := names.ParseUnitTag(id.UnitTag)
+		if err != nil || (canAccess != nil && !canAccess(unitTag)) {
+			errs[i] = ErrPerm
+			continue
+		}
+		storageTag, err := names.ParseStorageTag(id.StorageTag)
+		if err != nil {
+			errs[i] = errors.NotValidf("storage tag %q", id.StorageTag)
+			continue
+		}
+		parsed[i] = StorageAttachmentTags{storageTag, unitTag}
+	}
+	return parsed, errs
+}
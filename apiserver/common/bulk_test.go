@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type bulkSuite struct{}
+
+var _ = gc.Suite(&bulkSuite{})
+
+func (*bulkSuite) TestEntityTags(c *gc.C) {
+	tags := common.EntityTags(params.Entities{
+		Entities: []params.Entity{{"unit-x-0"}, {"machine-1"}},
+	})
+	c.Assert(tags, jc.DeepEquals, []string{"unit-x-0", "machine-1"})
+}
+
+func (*bulkSuite) TestParseTags(c *gc.C) {
+	canAccess := func(tag names.Tag) bool {
+		return tag != u("x/1")
+	}
+	tags, errs := common.ParseTags(
+		[]string{"unit-x-0", "unit-x-1", "invalid", "machine-0", "unit-x-2"},
+		canAccess, names.UnitTagKind,
+	)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{u("x/0"), nil, nil, nil, u("x/2")})
+	c.Assert(errs, jc.DeepEquals, []error{nil, common.ErrPerm, common.ErrPerm, common.ErrPerm, nil})
+}
+
+func (*bulkSuite) TestParseTagsAnyKind(c *gc.C) {
+	tags, errs := common.ParseTags([]string{"unit-x-0", "machine-0", ""}, nil)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{u("x/0"), names.NewMachineTag("0"), nil})
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil, common.ErrPerm})
+}
+
+func (*bulkSuite) TestParseStorageAttachmentIds(c *gc.C) {
+	canAccess := func(tag names.Tag) bool {
+		return tag == u("x/0")
+	}
+	ids, errs := common.ParseStorageAttachmentIds([]params.StorageAttachmentId{
+		{StorageTag: "storage-data-0", UnitTag: "unit-x-0"},
+		{StorageTag: "storage-data-0", UnitTag: "unit-x-1"},
+		{StorageTag: "storage-data-0", UnitTag: "machine-0"},
+		{StorageTag: "unit-x-0", UnitTag: "unit-x-0"},
+	}, canAccess)
+	c.Assert(ids, gc.HasLen, 4)
+	c.Assert(ids[0], jc.DeepEquals, common.StorageAttachmentTags{
+		StorageTag: names.NewStorageTag("data/0"),
+		UnitTag:    names.NewUnitTag("x/0"),
+	})
+	c.Assert(errs, gc.HasLen, 4)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.Equals, common.ErrPerm)
+	c.Assert(errs[2], gc.Equals, common.ErrPerm)
+	c.Assert(errs[3], jc.Satisfies, errors.IsNotValid)
+	c.Assert(errs[3], gc.ErrorMatches, `storage tag "unit-x-0" not valid`)
+}
@@ -0,0 +1,23 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+// Drainer is implemented by an API server that can be put in drain
+// mode, in which it stops serving once its in-flight requests have
+// completed.
+type Drainer interface {
+	Drain()
+}
+
+// DrainerResource holds the API server so that it can be registered
+// with an API connection's resources, as "drainer". The server is not
+// stopped when the resource is.
+type DrainerResource struct {
+	Drainer
+}
+
+// Stop is part of the Resource interface.
+func (DrainerResource) Stop() error {
+	return nil
+}
@@ -92,6 +92,7 @@ var singletonErrorCodes = map[error]string{
 	state.ErrCannotEnterScope:    params.CodeCannotEnterScope,
 	state.ErrUnitHasSubordinates: params.CodeUnitHasSubordinates,
 	state.ErrDead:                params.CodeDead,
+	state.ErrSettingsChanged:     params.CodeConflict,
 	txn.ErrExcessiveContention:   params.CodeExcessiveContention,
 	leadership.ErrClaimDenied:    params.CodeLeadershipClaimDenied,
 	ErrBadId:                     params.CodeNotFound,
@@ -142,6 +143,8 @@ func ServerError(err error) *params.Error {
 		code = params.CodeNoAddressSet
 	case errors.IsNotProvisioned(err):
 		code = params.CodeNotProvisioned
+	case errors.IsNotValid(err):
+		code = params.CodeNotValid
 	case state.IsUpgradeInProgressError(err):
 		code = params.CodeUpgradeInProgress
 	case IsUnknownEnviromentError(err):
@@ -49,6 +49,10 @@ var errorTransformTests = []struct {
 	err:        state.ErrDead,
 	code:       params.CodeDead,
 	helperFunc: params.IsCodeDead,
+}, {
+	err:        state.ErrSettingsChanged,
+	code:       params.CodeConflict,
+	helperFunc: params.IsCodeConflict,
 }, {
 	err:        txn.ErrExcessiveContention,
 	code:       params.CodeExcessiveContention,
@@ -81,6 +85,10 @@ var errorTransformTests = []struct {
 	err:        errors.NotProvisionedf("machine 0"),
 	code:       params.CodeNotProvisioned,
 	helperFunc: params.IsCodeNotProvisioned,
+}, {
+	err:        errors.NotValidf("storage tag \"foo\""),
+	code:       params.CodeNotValid,
+	helperFunc: params.IsCodeNotValid,
 }, {
 	err:        errors.AlreadyExistsf("blah"),
 	code:       params.CodeAlreadyExists,
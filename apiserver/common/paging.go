@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Page returns the bounds, as a slice [start:end] of keys, of the page
+// of listed items selected by the given parameters, along with the
+// continuation token to request the next page with, which is empty
+// when there are no items left. The keys identify the listed items
+// and must be sorted.
+//
+// The continuation token records the key of the last item in the
+// page, so that listing resumes after it even when items are added or
+// removed between the calls.
+func Page(keys []string, page params.ListPage) (start, end int, continuation string, err error) {
+	if page.Limit < 0 {
+		return 0, 0, "", errors.NotValidf("limit %d", page.Limit)
+	}
+	if page.Continuation != "" {
+		last, err := base64.URLEncoding.DecodeString(page.Continuation)
+		if err != nil {
+			return 0, 0, "", errors.NotValidf("continuation token %q", page.Continuation)
+		}
+		start = sort.SearchStrings(keys, string(last))
+		if start < len(keys) && keys[start] == string(last) {
+			start++
+		}
+	}
+	end = len(keys)
+	if page.Limit > 0 && start+page.Limit < end {
+		end = start + page.Limit
+		continuation = base64.URLEncoding.EncodeToString([]byte(keys[end-1]))
+	}
+	return start, end, continuation, nil
+}
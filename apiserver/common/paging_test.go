@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type pagingSuite struct{}
+
+var _ = gc.Suite(&pagingSuite{})
+
+func (*pagingSuite) TestNoLimit(c *gc.C) {
+	start, end, continuation, err := common.Page([]string{"a", "b", "c"}, params.ListPage{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(start, gc.Equals, 0)
+	c.Assert(end, gc.Equals, 3)
+	c.Assert(continuation, gc.Equals, "")
+}
+
+func (*pagingSuite) TestPages(c *gc.C) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	var pages [][]string
+	page := params.ListPage{Limit: 2}
+	for {
+		start, end, continuation, err := common.Page(keys, page)
+		c.Assert(err, jc.ErrorIsNil)
+		pages = append(pages, keys[start:end])
+		if continuation == "" {
+			break
+		}
+		page.Continuation = continuation
+	}
+	c.Assert(pages, gc.DeepEquals, [][]string{{"a", "b"}, {"c", "d"}, {"e"}})
+}
+
+func (*pagingSuite) TestExactLastPage(c *gc.C) {
+	start, end, continuation, err := common.Page([]string{"a", "b"}, params.ListPage{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(start, gc.Equals, 0)
+	c.Assert(end, gc.Equals, 2)
+	c.Assert(continuation, gc.Equals, "")
+}
+
+func (*pagingSuite) TestContinuesAfterRemovedItem(c *gc.C) {
+	_, _, continuation, err := common.Page([]string{"a", "b", "c", "d"}, params.ListPage{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// "b", the last item of the first page, has gone away.
+	keys := []string{"a", "c", "d"}
+	start, end, _, err := common.Page(keys, params.ListPage{Limit: 2, Continuation: continuation})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys[start:end], gc.DeepEquals, []string{"c", "d"})
+}
+
+func (*pagingSuite) TestInvalid(c *gc.C) {
+	_, _, _, err := common.Page(nil, params.ListPage{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "limit -1 not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, _, _, err = common.Page(nil, params.ListPage{Continuation: "!!"})
+	c.Assert(err, gc.ErrorMatches, `continuation token "!!" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -149,6 +149,24 @@ func (resourceSuite) TestStopAll(c *gc.C) {
 	c.Assert(rs.Count(), gc.Equals, 0)
 }
 
+type fakeWatcher struct {
+	fakeResource
+}
+
+func (w *fakeWatcher) Err() error {
+	return nil
+}
+
+func (resourceSuite) TestCountWatchers(c *gc.C) {
+	rs := common.NewResources()
+	rs.Register(&fakeResource{})
+	rs.Register(&fakeWatcher{})
+	err := rs.RegisterNamed("fake", &fakeWatcher{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rs.Count(), gc.Equals, 3)
+	c.Assert(rs.CountWatchers(), gc.Equals, 2)
+}
+
 func (resourceSuite) TestStringResource(c *gc.C) {
 	rs := common.NewResources()
 	r1 := common.StringResource("foobar")
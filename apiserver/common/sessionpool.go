@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+)
+
+// The following are the categories of read-only API calls whose mongo
+// read preference can be configured; see mongo.SessionPool.
+const (
+	// ReadStatus is the category of calls reporting the status of
+	// an environment.
+	ReadStatus = "status"
+)
+
+// SessionPoolResource holds the API server's mongo session pool so
+// that it can be registered with an API connection's resources. The
+// pool belongs to the server, so stopping the resource does nothing.
+type SessionPoolResource struct {
+	*mongo.SessionPool
+}
+
+// Stop is part of the Resource interface.
+func (SessionPoolResource) Stop() error {
+	return nil
+}
+
+// ReadState returns a State for st's environment that reads with the
+// read preference configured for the given category in the session
+// pool registered as "sessionPool" in resources. The returned function
+// must be called when the State is no longer needed. If no session pool
+// is registered, st itself is returned.
+func ReadState(st *state.State, resources *Resources, category string) (*state.State, func(), error) {
+	pool, ok := resources.Get("sessionPool").(SessionPoolResource)
+	if !ok || pool.SessionPool == nil {
+		return st, func() {}, nil
+	}
+	session, err := pool.Session(category)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "cannot get %s session", category)
+	}
+	return st.WithSession(session), session.Close, nil
+}
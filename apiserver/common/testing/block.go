@@ -6,6 +6,7 @@ package testing
 import (
 	"fmt"
 
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -42,6 +43,29 @@ func (s BlockHelper) on(c *gc.C, blockType multiwatcher.BlockType, msg string) {
 		gc.IsNil)
 }
 
+// onForEntity switches on desired block for the service or machine with
+// the given tag and asserts that no errors were encountered.
+func (s BlockHelper) onForEntity(c *gc.C, blockType multiwatcher.BlockType, tag names.Tag, msg string) {
+	c.Assert(
+		s.client.SwitchBlockOnForEntity(
+			fmt.Sprintf("%v", blockType),
+			tag,
+			msg),
+		gc.IsNil)
+}
+
+// BlockEntityChanges blocks all operations that could change the
+// service or machine with the given tag.
+func (s BlockHelper) BlockEntityChanges(c *gc.C, tag names.Tag, msg string) {
+	s.onForEntity(c, multiwatcher.BlockChange, tag, msg)
+}
+
+// BlockEntityRemoval blocks all operations that remove the service or
+// machine with the given tag.
+func (s BlockHelper) BlockEntityRemoval(c *gc.C, tag names.Tag, msg string) {
+	s.onForEntity(c, multiwatcher.BlockRemove, tag, msg)
+}
+
 // BlockAllChanges blocks all operations that could change environment.
 func (s BlockHelper) BlockAllChanges(c *gc.C, msg string) {
 	s.on(c, multiwatcher.BlockChange, msg)
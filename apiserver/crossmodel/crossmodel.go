@@ -0,0 +1,188 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package crossmodel provides the API through which services are
+// offered by one environment and consumed by another hosted by the
+// same controller.
+package crossmodel
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("CrossModel", 1, NewAPI)
+}
+
+// API implements the CrossModel facade.
+type API struct {
+	st         *state.State
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewAPI returns a new CrossModel API facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:         st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// Offer offers endpoints of services for use by other environments.
+func (api *API) Offer(args params.ServiceOffers) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Offers))}
+	for i, offer := range args.Offers {
+		_, err := api.st.AddServiceOffer(state.AddServiceOfferArgs{
+			Name:        offer.OfferName,
+			ServiceName: offer.ServiceName,
+			Endpoints:   offer.Endpoints,
+			Description: offer.Description,
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ListOffers returns the offers made by the environment.
+func (api *API) ListOffers() (params.ServiceOffers, error) {
+	offers, err := api.st.ServiceOffers()
+	if err != nil {
+		return params.ServiceOffers{}, errors.Trace(err)
+	}
+	result := params.ServiceOffers{Offers: make([]params.ServiceOffer, len(offers))}
+	for i, offer := range offers {
+		result.Offers[i] = params.ServiceOffer{
+			EnvironTag:  offer.EnvironTag().String(),
+			OfferName:   offer.Name(),
+			ServiceName: offer.ServiceName(),
+			Endpoints:   offer.Endpoints(),
+			Description: offer.Description(),
+		}
+	}
+	return result, nil
+}
+
+// RemoveOffers withdraws the named offers. Services already consuming
+// them are not affected.
+func (api *API) RemoveOffers(args params.OfferNames) (params.ErrorResults, error) {
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Names))}
+	for i, name := range args.Names {
+		offer, err := api.st.ServiceOffer(name)
+		if err == nil {
+			err = offer.Remove()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Consume adds remote services for offers made by other environments,
+// with which local services can then be related. The authenticated user
+// must have access to the environment making each offer.
+func (api *API) Consume(args params.ConsumeOffers) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Offers))}
+	for i, offer := range args.Offers {
+		result.Results[i].Error = common.ServerError(api.consume(offer))
+	}
+	return result, nil
+}
+
+func (api *API) consume(offer params.ConsumeOffer) error {
+	envTag, err := names.ParseEnvironTag(offer.EnvironTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.checkEnvironAccess(envTag); err != nil {
+		return err
+	}
+	_, err = api.st.ConsumeOffer(envTag, offer.OfferName, offer.ServiceName)
+	return err
+}
+
+// checkEnvironAccess returns common.ErrPerm unless the authenticated
+// user may use the given environment.
+func (api *API) checkEnvironAccess(envTag names.EnvironTag) error {
+	userTag, ok := api.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	if _, err := api.st.GetEnvironment(envTag); errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	st, err := api.st.ForEnviron(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+	if _, err := st.EnvironmentUser(userTag); errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// EnterScope enters units of remote services into the scopes of their
+// relations, or updates their settings if they are already in scope.
+func (api *API) EnterScope(args params.RemoteRelationUnits) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Units))}
+	for i, arg := range args.Units {
+		ru, err := api.remoteRelationUnit(arg)
+		if err == nil {
+			err = ru.EnterScope(arg.Settings)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// LeaveScope removes units of remote services from the scopes of their
+// relations.
+func (api *API) LeaveScope(args params.RemoteRelationUnits) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Units))}
+	for i, arg := range args.Units {
+		ru, err := api.remoteRelationUnit(arg)
+		if err == nil {
+			err = ru.LeaveScope()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) remoteRelationUnit(arg params.RemoteRelationUnit) (*state.RemoteRelationUnit, error) {
+	relTag, err := names.ParseRelationTag(arg.RelationTag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	unitTag, err := names.ParseUnitTag(arg.Unit)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	rel, err := api.st.KeyRelation(relTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rel.RemoteUnit(unitTag.Id())
+}
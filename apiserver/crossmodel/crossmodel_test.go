@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/crossmodel"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type crossmodelSuite struct {
+	jujutesting.JujuConnSuite
+
+	// otherState is the environment that offers mysql.
+	otherState *state.State
+	api        *crossmodel.API
+	otherAPI   *crossmodel.API
+}
+
+var _ = gc.Suite(&crossmodelSuite{})
+
+func (s *crossmodelSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.otherState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.otherState.Close() })
+	f := factory.NewFactory(s.otherState)
+	f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = crossmodel.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	s.otherAPI, err = crossmodel.NewAPI(s.otherState, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *crossmodelSuite) offer(c *gc.C) {
+	results, err := s.otherAPI.Offer(params.ServiceOffers{Offers: []params.ServiceOffer{{
+		OfferName:   "hosted-mysql",
+		ServiceName: "mysql",
+		Description: "a database",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+}
+
+func (s *crossmodelSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := crossmodel.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *crossmodelSuite) TestOfferAndList(c *gc.C) {
+	s.offer(c)
+	results, err := s.otherAPI.Offer(params.ServiceOffers{Offers: []params.ServiceOffer{{
+		OfferName:   "hosted-mysql",
+		ServiceName: "mysql",
+	}, {
+		OfferName:   "other",
+		ServiceName: "mysql",
+		Endpoints:   []string{"no-such"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `cannot add offer "hosted-mysql": offer "hosted-mysql" already exists`)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `cannot add offer "other": .*`)
+
+	offers, err := s.otherAPI.ListOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers.Offers, jc.DeepEquals, []params.ServiceOffer{{
+		EnvironTag:  s.otherState.EnvironTag().String(),
+		OfferName:   "hosted-mysql",
+		ServiceName: "mysql",
+		Endpoints:   []string{"juju-info", "server"},
+		Description: "a database",
+	}})
+}
+
+func (s *crossmodelSuite) TestRemoveOffers(c *gc.C) {
+	s.offer(c)
+	results, err := s.otherAPI.RemoveOffers(params.OfferNames{Names: []string{"hosted-mysql", "no-such"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	offers, err := s.otherAPI.ListOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers.Offers, gc.HasLen, 0)
+}
+
+func (s *crossmodelSuite) TestConsume(c *gc.C) {
+	s.offer(c)
+	results, err := s.api.Consume(params.ConsumeOffers{Offers: []params.ConsumeOffer{{
+		EnvironTag:  s.otherState.EnvironTag().String(),
+		OfferName:   "hosted-mysql",
+		ServiceName: "db",
+	}, {
+		EnvironTag: names.NewEnvironTag("6a6d4eb8-3be4-4cd5-8a5c-26e96b4cb3b1").String(),
+		OfferName:  "hosted-mysql",
+	}, {
+		EnvironTag: "invalid",
+		OfferName:  "hosted-mysql",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `"invalid" is not a valid tag`)
+
+	remote, err := s.State.RemoteService("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(remote.OfferName(), gc.Equals, "hosted-mysql")
+	c.Check(remote.SourceEnvironTag(), gc.Equals, s.otherState.EnvironTag())
+}
+
+func (s *crossmodelSuite) TestConsumeRequiresEnvironAccess(c *gc.C) {
+	s.offer(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	auth := apiservertesting.FakeAuthorizer{Tag: user.Tag()}
+	api, err := crossmodel.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.Consume(params.ConsumeOffers{Offers: []params.ConsumeOffer{{
+		EnvironTag: s.otherState.EnvironTag().String(),
+		OfferName:  "hosted-mysql",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "permission denied")
+}
+
+func (s *crossmodelSuite) TestEnterAndLeaveScope(c *gc.C) {
+	s.offer(c)
+	_, err := s.State.ConsumeOffer(s.otherState.EnvironTag(), "hosted-mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{{
+		RelationTag: rel.Tag().String(),
+		Unit:        "unit-mysql-0",
+		Settings:    map[string]interface{}{"user": "admin"},
+	}, {
+		RelationTag: rel.Tag().String(),
+		Unit:        "unit-wordpress-0",
+	}, {
+		RelationTag: "machine-0",
+		Unit:        "unit-mysql-0",
+	}}}
+	results, err := s.api.EnterScope(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `remote service "wordpress" not found`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, "permission denied")
+
+	ru, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+
+	results, err = s.api.LeaveScope(params.RemoteRelationUnits{Units: args.Units[:1]})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	inScope, err := ru.InScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inScope, jc.IsFalse)
+}
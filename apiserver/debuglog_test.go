@@ -8,21 +8,22 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/juju/juju/testing/factory"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"golang.org/x/net/websocket"
 	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 )
 
 type debugLogSuite struct {
 	userAuthHttpSuite
-	logFile *os.File
-	last    int
 }
 
 var _ = gc.Suite(&debugLogSuite{})
@@ -63,12 +64,6 @@ func (s *debugLogSuite) TestAgentLoginsRejected(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
-func (s *debugLogSuite) TestNoLogfile(c *gc.C) {
-	reader := s.openWebsocket(c, nil)
-	assertJSONError(c, reader, "cannot open log file: .*: "+utils.NoSuchFileErrRegexp)
-	s.assertWebsocketClosed(c, reader)
-}
-
 func (s *debugLogSuite) TestBadParams(c *gc.C) {
 	reader := s.openWebsocket(c, url.Values{"maxLines": {"foo"}})
 	assertJSONError(c, reader, `maxLines value "foo" is not a valid unsigned number`)
@@ -77,23 +72,21 @@ func (s *debugLogSuite) TestBadParams(c *gc.C) {
 
 func (s *debugLogSuite) assertLogReader(c *gc.C, reader *bufio.Reader) {
 	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
 	linesRead := s.readLogLines(c, reader, logLineCount)
 	c.Assert(linesRead, jc.DeepEquals, logLines)
 }
 
 func (s *debugLogSuite) TestServesLog(c *gc.C) {
-	s.ensureLogFile(c)
-	reader := s.openWebsocket(c, nil)
+	s.writeLogLines(c)
+	reader := s.openWebsocket(c, url.Values{"replay": {"true"}})
 	s.assertLogReader(c, reader)
 }
 
 func (s *debugLogSuite) TestReadFromTopLevelPath(c *gc.C) {
-	// Backwards compatibility check, that we can read the log file at
+	// Backwards compatibility check, that we can read the log at
 	// https://host:port/log
-	s.ensureLogFile(c)
-	reader := s.openWebsocketCustomPath(c, "/log")
+	s.writeLogLines(c)
+	reader := s.openWebsocketCustomPath(c, "/log", url.Values{"replay": {"true"}})
 	s.assertLogReader(c, reader)
 }
 
@@ -101,76 +94,56 @@ func (s *debugLogSuite) TestReadFromEnvUUIDPath(c *gc.C) {
 	// Check that we can read the log at https://host:port/ENVUUID/log
 	environ, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
-	s.ensureLogFile(c)
-	reader := s.openWebsocketCustomPath(c, fmt.Sprintf("/environment/%s/log", environ.UUID()))
+	s.writeLogLines(c)
+	reader := s.openWebsocketCustomPath(c, fmt.Sprintf("/environment/%s/log", environ.UUID()), url.Values{"replay": {"true"}})
 	s.assertLogReader(c, reader)
 }
 
 func (s *debugLogSuite) TestReadRejectsWrongEnvUUIDPath(c *gc.C) {
 	// Check that we cannot pull logs from https://host:port/BADENVUUID/log
-	s.ensureLogFile(c)
-	reader := s.openWebsocketCustomPath(c, "/environment/dead-beef-123456/log")
+	reader := s.openWebsocketCustomPath(c, "/environment/dead-beef-123456/log", nil)
 	assertJSONError(c, reader, `unknown environment: "dead-beef-123456"`)
 	s.assertWebsocketClosed(c, reader)
 }
 
-func (s *debugLogSuite) TestReadsFromEnd(c *gc.C) {
-	s.writeLogLines(c, 10)
-
-	reader := s.openWebsocket(c, nil)
-	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
-	linesRead := s.readLogLines(c, reader, logLineCount-10)
-	c.Assert(linesRead, jc.DeepEquals, logLines[10:])
-}
-
-func (s *debugLogSuite) TestReplayFromStart(c *gc.C) {
-	s.writeLogLines(c, 10)
-
-	reader := s.openWebsocket(c, url.Values{"replay": {"true"}})
-	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
-	linesRead := s.readLogLines(c, reader, logLineCount)
-	c.Assert(linesRead, jc.DeepEquals, logLines)
-}
-
 func (s *debugLogSuite) TestBacklog(c *gc.C) {
-	s.writeLogLines(c, 10)
+	s.writeLogLines(c)
 
 	reader := s.openWebsocket(c, url.Values{"backlog": {"5"}})
 	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
-	linesRead := s.readLogLines(c, reader, logLineCount-5)
-	c.Assert(linesRead, jc.DeepEquals, logLines[5:])
+	linesRead := s.readLogLines(c, reader, 5)
+	c.Assert(linesRead, jc.DeepEquals, logLines[logLineCount-5:])
 }
 
 func (s *debugLogSuite) TestMaxLines(c *gc.C) {
-	s.writeLogLines(c, 10)
+	s.writeLogLines(c)
 
-	reader := s.openWebsocket(c, url.Values{"maxLines": {"10"}})
+	reader := s.openWebsocket(c, url.Values{"replay": {"true"}, "maxLines": {"10"}})
 	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
 	linesRead := s.readLogLines(c, reader, 10)
-	c.Assert(linesRead, jc.DeepEquals, logLines[10:20])
+	c.Assert(linesRead, jc.DeepEquals, logLines[:10])
 	s.assertWebsocketClosed(c, reader)
 }
 
 func (s *debugLogSuite) TestBacklogWithMaxLines(c *gc.C) {
-	s.writeLogLines(c, 10)
+	s.writeLogLines(c)
 
-	reader := s.openWebsocket(c, url.Values{"backlog": {"5"}, "maxLines": {"10"}})
+	reader := s.openWebsocket(c, url.Values{"backlog": {"5"}, "maxLines": {"3"}})
 	s.assertLogFollowing(c, reader)
-	s.writeLogLines(c, logLineCount)
-
-	linesRead := s.readLogLines(c, reader, 10)
-	c.Assert(linesRead, jc.DeepEquals, logLines[5:15])
+	linesRead := s.readLogLines(c, reader, 3)
+	c.Assert(linesRead, jc.DeepEquals, logLines[logLineCount-5:logLineCount-2])
 	s.assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogSuite) TestLevel(c *gc.C) {
+	s.writeLogLines(c)
+
+	reader := s.openWebsocket(c, url.Values{"replay": {"true"}, "level": {"ERROR"}})
+	s.assertLogFollowing(c, reader)
+	linesRead := s.readLogLines(c, reader, 2)
+	c.Assert(linesRead, jc.DeepEquals, logLines[9:11])
+}
+
 type filterTest struct {
 	about    string
 	filter   url.Values
@@ -282,21 +255,18 @@ var filterTests []filterTest = []filterTest{
 
 // TestFilter tests that filters are processed correctly given specific debug-log configuration.
 func (s *debugLogSuite) TestFilter(c *gc.C) {
+	s.writeLogLines(c)
 	for i, test := range filterTests {
 		c.Logf("test %d: %v\n", i, test.about)
 
-		// ensures log file
-		path := filepath.Join(s.LogDir, "all-machines.log")
-		var err error
-		s.logFile, err = os.Create(path)
-		c.Assert(err, jc.ErrorIsNil)
-
-		// opens web socket
-		conn := s.dialWebsocket(c, test.filter)
+		values := url.Values{"replay": {"true"}}
+		for key, value := range test.filter {
+			values[key] = value
+		}
+		conn := s.dialWebsocket(c, values)
 		reader := bufio.NewReader(conn)
 
 		s.assertLogFollowing(c, reader)
-		s.writeLogLines(c, logLineCount)
 		/*
 			This will filter and return as many lines as filtered wanted to examine.
 			 So, if specified filter can potentially return 40 lines from sample log but filtered only wanted 2,
@@ -308,9 +278,6 @@ func (s *debugLogSuite) TestFilter(c *gc.C) {
 
 		// release resources
 		conn.Close()
-		s.logFile.Close()
-		s.logFile = nil
-		s.last = 0
 	}
 }
 
@@ -333,8 +300,8 @@ func (s *debugLogSuite) openWebsocket(c *gc.C, values url.Values) *bufio.Reader
 	return bufio.NewReader(conn)
 }
 
-func (s *debugLogSuite) openWebsocketCustomPath(c *gc.C, path string) *bufio.Reader {
-	server := s.logURL(c, "wss", nil)
+func (s *debugLogSuite) openWebsocketCustomPath(c *gc.C, path string, values url.Values) *bufio.Reader {
+	server := s.logURL(c, "wss", values)
 	server.Path = path
 	header := utils.BasicAuthHeader(s.userTag.String(), s.password)
 	conn := s.dialWebsocketFromURL(c, server.String(), header)
@@ -342,26 +309,32 @@ func (s *debugLogSuite) openWebsocketCustomPath(c *gc.C, path string) *bufio.Rea
 	return bufio.NewReader(conn)
 }
 
-func (s *debugLogSuite) ensureLogFile(c *gc.C) {
-	if s.logFile != nil {
-		return
-	}
-	path := filepath.Join(s.LogDir, "all-machines.log")
-	var err error
-	s.logFile, err = os.Create(path)
-	c.Assert(err, jc.ErrorIsNil)
-	s.AddCleanup(func(c *gc.C) {
-		s.logFile.Close()
-		s.logFile = nil
-		s.last = 0
-	})
-}
-
-func (s *debugLogSuite) writeLogLines(c *gc.C, count int) {
-	s.ensureLogFile(c)
-	for i := 0; i < count && s.last < logLineCount; i++ {
-		s.logFile.WriteString(logLines[s.last] + "\n")
-		s.last++
+// writeLogLines writes the records in logLines to the database.
+func (s *debugLogSuite) writeLogLines(c *gc.C) {
+	loggers := make(map[string]*state.DbLogger)
+	defer func() {
+		for _, dbLogger := range loggers {
+			dbLogger.Close()
+		}
+	}()
+	for _, line := range logLines {
+		// entity: date time level module location message
+		fields := strings.SplitN(line, " ", 7)
+		c.Assert(fields, gc.HasLen, 7)
+		entity := strings.TrimSuffix(fields[0], ":")
+		dbLogger, ok := loggers[entity]
+		if !ok {
+			tag, err := names.ParseTag(entity)
+			c.Assert(err, jc.ErrorIsNil)
+			dbLogger = state.NewDbLogger(s.State, tag)
+			loggers[entity] = dbLogger
+		}
+		t, err := time.Parse("2006-01-02 15:04:05", fields[1]+" "+fields[2])
+		c.Assert(err, jc.ErrorIsNil)
+		level, ok := loggo.ParseLevel(fields[3])
+		c.Assert(ok, jc.IsTrue)
+		err = dbLogger.Log(t, fields[4], fields[5], level, fields[6])
+		c.Assert(err, jc.ErrorIsNil)
 	}
 }
 
@@ -385,7 +358,7 @@ func (s *debugLogSuite) assertLogFollowing(c *gc.C, reader *bufio.Reader) {
 }
 
 var (
-	logLines = strings.Split(`
+	logLines = strings.Split(strings.TrimSpace(`
 machine-0: 2014-03-24 22:34:25 INFO juju.cmd supercommand.go:297 running juju-1.17.7.1-trusty-amd64 [gc]
 machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent machine-0 start (1.17.7.1-trusty-amd64 [gc])
 machine-0: 2014-03-24 22:34:25 DEBUG juju.agent agent.go:384 read agent config, format "1.18"
@@ -426,8 +399,8 @@ machine-1: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "upgrade-st
 machine-1: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "machiner"
 machine-1: 2014-03-24 22:36:28 INFO juju.cmd.jujud machine.go:458 upgrade to 1.17.7.1-precise-amd64 already completed.
 machine-1: 2014-03-24 22:36:28 INFO juju.cmd.jujud machine.go:445 upgrade to 1.17.7.1-precise-amd64 completed.
-unit-ubuntu-0[32423]: 2014-03-24 22:36:28 INFO juju.cmd supercommand.go:297 running juju-1.17.7.1-precise-amd64 [gc]
-unit-ubuntu-0[34543]: 2014-03-24 22:36:28 DEBUG juju.agent agent.go:384 read agent config, format "1.18"
+unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju.cmd supercommand.go:297 running juju-1.17.7.1-precise-amd64 [gc]
+unit-ubuntu-0: 2014-03-24 22:36:28 DEBUG juju.agent agent.go:384 read agent config, format "1.18"
 unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju.jujud unit.go:76 unit agent unit-ubuntu-0 start (1.17.7.1-precise-amd64 [gc])
 unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "api"
 unit-ubuntu-0: 2014-03-24 22:36:28 INFO juju apiclient.go:114 api: dialing "wss://10.0.3.1:17070/"
@@ -446,6 +419,6 @@ unit-ubuntu-1: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "uniter
 unit-ubuntu-1: 2014-03-24 22:36:28 DEBUG juju.worker.logger logger.go:60 logger setup
 unit-ubuntu-1: 2014-03-24 22:36:28 INFO juju runner.go:262 worker: start "rsyslog"
 unit-ubuntu-1: 2014-03-24 22:36:28 DEBUG juju.worker.rsyslog worker.go:76 starting rsyslog worker mode 1 for "unit-ubuntu-0" "tim-local"
-`[1:], "\n")
+`), "\n")
 	logLineCount = len(logLines)
 )
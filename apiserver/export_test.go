@@ -25,8 +25,6 @@ var (
 	NewTimer              = &newTimer
 	ResetTimer            = &resetTimer
 	NewBackups            = &newBackups
-	ParseLogLine          = parseLogLine
-	AgentMatchesFilter    = agentMatchesFilter
 )
 
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -97,6 +95,20 @@ func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
 	return newRestrictedRoot(r)
 }
 
+// TestingReadOnlyRoot returns a srvRoot as if accessed by a read-only
+// user.
+func TestingReadOnlyRoot(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newReadOnlyRoot(r)
+}
+
+// TestingScopedRoot returns a srvRoot as if accessed with a scoped
+// credential giving access to the given facades and entities.
+func TestingScopedRoot(st *state.State, facades, entities []string) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newScopedRoot(r, facades, entities)
+}
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
@@ -170,13 +182,3 @@ func TestingAboutToRestoreRoot(st *state.State) *aboutToRestoreRoot {
 	r := TestingApiRoot(st)
 	return newAboutToRestoreRoot(r)
 }
-
-// LogLineAgentTag gives tests access to an internal logLine attribute
-func (logLine *logLine) LogLineAgentTag() string {
-	return logLine.agentTag
-}
-
-// LogLineAgentName gives tests access to an internal logLine attribute
-func (logLine *logLine) LogLineAgentName() string {
-	return logLine.agentName
-}
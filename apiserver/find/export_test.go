@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package find
+
+import "github.com/juju/juju/state"
+
+type Patcher interface {
+	PatchValue(ptr, value interface{})
+}
+
+func PatchState(p Patcher, st Finder) {
+	p.PatchValue(&getState, func(*state.State) Finder {
+		return st
+	})
+}
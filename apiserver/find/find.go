@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package find implements the API facade through which clients select
+// entities across the environment by their annotations.
+package find
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Find", 1, NewFindAPI)
+}
+
+// Finder finds the entities in an environment carrying annotations.
+type Finder interface {
+	FindAnnotated(annotations map[string]string) ([]names.Tag, error)
+}
+
+// FindAPI provides access to the Find API facade.
+type FindAPI struct {
+	st Finder
+}
+
+var getState = func(st *state.State) Finder {
+	return st
+}
+
+// NewFindAPI creates a new server-side Find API facade.
+func NewFindAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*FindAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &FindAPI{st: getState(st)}, nil
+}
+
+// Find returns the environment, machines, services and units carrying
+// all the given annotations, sorted by tag.
+func (api *FindAPI) Find(args params.FindArgs) (params.FindResults, error) {
+	tags, err := api.st.FindAnnotated(args.Annotations)
+	if err != nil {
+		return params.FindResults{}, errors.Trace(err)
+	}
+	result := params.FindResults{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		result.Entities[i] = params.Entity{Tag: tag.String()}
+	}
+	return result, nil
+}
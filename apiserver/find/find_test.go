@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package find_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/find"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&FindSuite{})
+
+type FindSuite struct {
+	coretesting.BaseSuite
+	st *mockState
+}
+
+type mockState struct {
+	annotations map[string]string
+	tags        []names.Tag
+	err         error
+}
+
+func (m *mockState) FindAnnotated(annotations map[string]string) ([]names.Tag, error) {
+	m.annotations = annotations
+	return m.tags, m.err
+}
+
+func (s *FindSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &mockState{}
+	find.PatchState(s, s.st)
+}
+
+func (s *FindSuite) newAPI(c *gc.C) *find.FindAPI {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	api, err := find.NewFindAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *FindSuite) TestNewFindAPIRequiresClient(c *gc.C) {
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := find.NewFindAPI(nil, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *FindSuite) TestFind(c *gc.C) {
+	s.st.tags = []names.Tag{
+		names.NewMachineTag("0"),
+		names.NewUnitTag("mysql/0"),
+	}
+	query := map[string]string{"tag": "production"}
+	result, err := s.newAPI(c).Find(params.FindArgs{Annotations: query})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.annotations, jc.DeepEquals, query)
+	c.Assert(result, jc.DeepEquals, params.FindResults{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "unit-mysql-0"}},
+	})
+}
+
+func (s *FindSuite) TestFindError(c *gc.C) {
+	s.st.err = errors.NotValidf("empty annotation query")
+	_, err := s.newAPI(c).Find(params.FindArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
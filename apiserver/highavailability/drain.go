@@ -0,0 +1,30 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// DrainAPIServer puts the API server serving the call in drain mode,
+// before its controller is restarted for maintenance: the server stops
+// accepting connections, waits for the in-flight requests to complete
+// and closes the connections of the agents, which reconnect to the
+// other API servers of the environment, and then stops.
+func (api *HighAvailabilityAPI) DrainAPIServer() error {
+	if !api.authorizer.AuthClient() {
+		return common.ErrPerm
+	}
+	if !api.state.IsStateServer() {
+		return errors.New("unsupported with hosted environments")
+	}
+	drainer, ok := api.resources.Get("drainer").(common.DrainerResource)
+	if !ok || drainer.Drainer == nil {
+		return errors.NotSupportedf("draining the API server")
+	}
+	drainer.Drain()
+	return nil
+}
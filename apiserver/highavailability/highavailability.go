@@ -4,13 +4,16 @@
 package highavailability
 
 import (
+	"encoding/pem"
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
 	"github.com/juju/juju/state"
 )
 
@@ -21,6 +24,11 @@ func init() {
 // HighAvailability defines the methods on the highavailability API end point.
 type HighAvailability interface {
 	EnsureAvailability(args params.StateServersSpecs) (params.StateServersChangeResults, error)
+	RotateMongoCertificates(args params.RotateCertificates) (params.CertRotationResult, error)
+	ReplicaSetStatus() (params.ReplicaSetStatusResult, error)
+	MigrateStateServer(args params.MigrateStateServer) (params.MigrateStateServerResult, error)
+	RetireStateServer(args params.Entity) (params.RetireStateServerResult, error)
+	DrainAPIServer() error
 }
 
 // HighAvailabilityAPI implements the HighAvailability interface and is the concrete
@@ -123,5 +131,98 @@ func EnsureAvailabilitySingle(st *state.State, spec params.StateServersSpec) (pa
 	if err != nil {
 		return params.StateServersChanges{}, err
 	}
+	// Record the request so that it can be repeated to replace
+	// state servers that fail.
+	err = st.SetAvailabilityPolicy(state.AvailabilityPolicy{
+		AutoReplace:     spec.AutoReplace,
+		NumStateServers: spec.NumStateServers,
+		Constraints:     spec.Constraints,
+		Series:          series,
+	})
+	if err != nil {
+		return params.StateServersChanges{}, err
+	}
 	return stateServersChanges(changes), nil
 }
+
+// RotateMongoCertificates starts a rotation of the certificates served
+// by the state servers' mongo instances. The state servers restart
+// their mongo one at a time, so the replica set remains available
+// throughout.
+//
+// If a new CA is requested, it is added to the environment's CA
+// certificate, which is returned in the result; clients must trust it
+// before the rotation completes.
+func (api *HighAvailabilityAPI) RotateMongoCertificates(args params.RotateCertificates) (params.CertRotationResult, error) {
+	st := api.state
+	if !st.IsStateServer() {
+		return params.CertRotationResult{}, errors.New("unsupported with hosted environments")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.CertRotationResult{}, errors.Trace(err)
+	}
+	if rotation, err := st.CertRotation(); err == nil && !rotation.Done() {
+		return params.CertRotationResult{}, errors.Errorf("certificate rotation %d still in progress", rotation.Serial())
+	} else if err != nil && !errors.IsNotFound(err) {
+		return params.CertRotationResult{}, errors.Trace(err)
+	}
+	caCert, caKey, err := rotationCA(st, args.RotateCA)
+	if err != nil {
+		return params.CertRotationResult{}, errors.Trace(err)
+	}
+	rotation, err := st.StartCertRotation(caCert, caKey)
+	if err != nil {
+		return params.CertRotationResult{}, errors.Trace(err)
+	}
+	return params.CertRotationResult{
+		Serial:   rotation.Serial(),
+		Machines: machineIdsToTags(rotation.MachineIds()...),
+		CACert:   rotation.CACert(),
+	}, nil
+}
+
+// rotationCA returns the certificate and key of the CA that should issue
+// the certificates of a new rotation. If rotateCA is true, a new CA is
+// created and recorded as the environment's CA, along with the current
+// one so that existing certificates remain valid.
+func rotationCA(st *state.State, rotateCA bool) (caCert, caKey string, err error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	caCert, ok := cfg.CACert()
+	if !ok {
+		return "", "", errors.New("environment has no CA certificate")
+	}
+	info, err := st.StateServingInfo()
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	if info.CAPrivateKey == "" {
+		return "", "", errors.New("CA private key not available")
+	}
+	if !rotateCA {
+		return caCert, info.CAPrivateKey, nil
+	}
+	newCert, newKey, err := cert.NewCA(cfg.Name(), time.Now().UTC().AddDate(10, 0, 0))
+	if err != nil {
+		return "", "", errors.Annotate(err, "cannot create CA")
+	}
+	// The new CA comes first, as the first certificate is the one
+	// used to issue new certificates. Only the current CA is kept
+	// alongside it, so CAs retired by earlier rotations are dropped.
+	block, _ := pem.Decode([]byte(caCert))
+	if block == nil {
+		return "", "", errors.New("cannot parse CA certificate")
+	}
+	bundle := newCert + string(pem.EncodeToMemory(block))
+	if err := st.UpdateEnvironConfig(map[string]interface{}{"ca-cert": bundle}, nil, nil); err != nil {
+		return "", "", errors.Annotate(err, "cannot update CA certificate")
+	}
+	info.CAPrivateKey = newKey
+	if err := st.SetStateServingInfo(info); err != nil {
+		return "", "", errors.Annotate(err, "cannot update CA private key")
+	}
+	return bundle, newKey, nil
+}
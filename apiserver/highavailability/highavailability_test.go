@@ -4,17 +4,23 @@
 package highavailability_test
 
 import (
+	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/replicaset"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/cert"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -185,6 +191,35 @@ func (s *clientSuite) TestEnsureAvailabilityConstraints(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestEnsureAvailabilityAutoReplace(c *gc.C) {
+	arg := params.StateServersSpecs{
+		Specs: []params.StateServersSpec{{
+			NumStateServers: 3,
+			Constraints:     constraints.MustParse("mem=4G"),
+			AutoReplace:     true,
+		}}}
+	results, err := s.haServer.EnsureAvailability(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	policy, err := s.State.AvailabilityPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, state.AvailabilityPolicy{
+		AutoReplace:     true,
+		NumStateServers: 3,
+		Constraints:     constraints.MustParse("mem=4G"),
+		Series:          "quantal",
+	})
+
+	// Asking again without auto-replace turns it off.
+	_, err = s.ensureAvailability(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.State.AvailabilityPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.AutoReplace, jc.IsFalse)
+}
+
 func (s *clientSuite) TestBlockEnsureAvailability(c *gc.C) {
 	// Block all changes.
 	s.BlockAllChanges(c, "TestBlockEnsureAvailability")
@@ -316,3 +351,239 @@ func (s *clientSuite) TestEnsureAvailabilityHostedEnvErrors(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(machines, gc.HasLen, 0)
 }
+
+func (s *clientSuite) TestRotateMongoCertificates(c *gc.C) {
+	result, err := s.haServer.RotateMongoCertificates(params.RotateCertificates{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Serial, gc.Equals, int64(1))
+	c.Assert(result.Machines, jc.DeepEquals, []string{"machine-0"})
+	c.Assert(result.CACert, gc.Equals, coretesting.CACert)
+
+	rotation, err := s.State.CertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.CACert(), gc.Equals, coretesting.CACert)
+	c.Assert(rotation.CAPrivateKey(), gc.Equals, coretesting.CAKey)
+
+	_, err = s.haServer.RotateMongoCertificates(params.RotateCertificates{})
+	c.Assert(err, gc.ErrorMatches, "certificate rotation 1 still in progress")
+}
+
+func (s *clientSuite) TestRotateMongoCertificatesNewCA(c *gc.C) {
+	result, err := s.haServer.RotateMongoCertificates(params.RotateCertificates{RotateCA: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Serial, gc.Equals, int64(1))
+	c.Assert(result.CACert, gc.Not(gc.Equals), coretesting.CACert)
+	// The current CA is still trusted.
+	c.Assert(strings.HasSuffix(result.CACert, coretesting.CACert), jc.IsTrue)
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	c.Assert(caCert, gc.Equals, result.CACert)
+
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CAPrivateKey, gc.Not(gc.Equals), coretesting.CAKey)
+	_, _, err = cert.ParseCertAndKey(result.CACert, info.CAPrivateKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rotation, err := s.State.CertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.CACert(), gc.Equals, result.CACert)
+	c.Assert(rotation.CAPrivateKey(), gc.Equals, info.CAPrivateKey)
+}
+
+func (s *clientSuite) TestBlockRotateMongoCertificates(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockRotateMongoCertificates")
+	_, err := s.haServer.RotateMongoCertificates(params.RotateCertificates{})
+	s.AssertBlocked(c, err, "TestBlockRotateMongoCertificates")
+
+	_, err = s.State.CertRotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestMigrateStateServer(c *gc.C) {
+	result, err := s.haServer.MigrateStateServer(params.MigrateStateServer{
+		MachineTag:  "machine-0",
+		Constraints: constraints.MustParse("mem=8G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.MachineTag, gc.Equals, "machine-1")
+
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsTrue)
+	c.Assert(m1.Series(), gc.Equals, "quantal")
+	cons, err := m1.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=8G"))
+
+	m0, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.WantsVote(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestMigrateStateServerInvalidTag(c *gc.C) {
+	_, err := s.haServer.MigrateStateServer(params.MigrateStateServer{MachineTag: "unit-foo-0"})
+	c.Assert(err, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+}
+
+func (s *clientSuite) TestBlockMigrateStateServer(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockMigrateStateServer")
+	_, err := s.haServer.MigrateStateServer(params.MigrateStateServer{MachineTag: "machine-0"})
+	s.AssertBlocked(c, err, "TestBlockMigrateStateServer")
+}
+
+func (s *clientSuite) TestRetireStateServer(c *gc.C) {
+	_, err := s.haServer.MigrateStateServer(params.MigrateStateServer{MachineTag: "machine-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	m0, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m0.SetHasVote(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.haServer.RetireStateServer(params.Entity{Tag: "machine-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Retired, jc.IsFalse)
+
+	err = m0.SetHasVote(false)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.haServer.RetireStateServer(params.Entity{Tag: "machine-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Retired, jc.IsTrue)
+
+	err = m0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.IsManager(), jc.IsFalse)
+	c.Assert(m0.Life(), gc.Equals, state.Dying)
+}
+
+func (s *clientSuite) TestBlockRetireStateServer(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockRetireStateServer")
+	_, err := s.haServer.RetireStateServer(params.Entity{Tag: "machine-0"})
+	s.AssertBlocked(c, err, "TestBlockRetireStateServer")
+}
+
+type fakeDrainer struct {
+	drained bool
+}
+
+func (d *fakeDrainer) Drain() {
+	d.drained = true
+}
+
+func (s *clientSuite) TestDrainAPIServer(c *gc.C) {
+	drainer := &fakeDrainer{}
+	err := s.resources.RegisterNamed("drainer", common.DrainerResource{Drainer: drainer})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.haServer.DrainAPIServer()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(drainer.drained, jc.IsTrue)
+}
+
+func (s *clientSuite) TestDrainAPIServerNotSupported(c *gc.C) {
+	err := s.haServer.DrainAPIServer()
+	c.Assert(err, gc.ErrorMatches, "draining the API server not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *clientSuite) TestDrainAPIServerAgentNotAllowed(c *gc.C) {
+	drainer := &fakeDrainer{}
+	err := s.resources.RegisterNamed("drainer", common.DrainerResource{Drainer: drainer})
+	c.Assert(err, jc.ErrorIsNil)
+	authoriser := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	haServer, err := highavailability.NewHighAvailabilityAPI(s.State, s.resources, authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = haServer.DrainAPIServer()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(drainer.drained, jc.IsFalse)
+}
+
+func newInt(i int) *int {
+	return &i
+}
+
+func (s *clientSuite) TestReplicaSetStatus(c *gc.C) {
+	s.PatchValue(highavailability.CurrentStatus, func(*mgo.Session) (*replicaset.Status, error) {
+		return &replicaset.Status{
+			Name: "juju",
+			Members: []replicaset.MemberStatus{{
+				Id:      1,
+				Address: "10.0.0.1:37017",
+				Healthy: true,
+				State:   replicaset.PrimaryState,
+			}, {
+				Id:      2,
+				Address: "10.0.0.2:37017",
+				Healthy: true,
+				State:   replicaset.SecondaryState,
+			}, {
+				Id:      3,
+				Address: "10.0.0.3:37017",
+				ErrMsg:  "no route to host",
+				State:   replicaset.UnknownState,
+			}},
+		}, nil
+	})
+	s.PatchValue(highavailability.CurrentMembers, func(*mgo.Session) ([]replicaset.Member, error) {
+		return []replicaset.Member{{
+			Id:   1,
+			Tags: map[string]string{"juju-machine-id": "0"},
+		}, {
+			Id:   2,
+			Tags: map[string]string{"juju-machine-id": "1"},
+		}, {
+			Id:    3,
+			Tags:  map[string]string{"juju-machine-id": "2"},
+			Votes: newInt(0),
+		}}, nil
+	})
+	now := time.Now()
+	s.PatchValue(highavailability.MemberOptimes, func(*mgo.Session) (map[int]time.Time, error) {
+		return map[int]time.Time{
+			1: now,
+			2: now.Add(-3 * time.Second),
+		}, nil
+	})
+
+	result, err := s.haServer.ReplicaSetStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ReplicaSetStatusResult{
+		Name: "juju",
+		Members: []params.ReplicaSetMember{{
+			Id:         1,
+			Address:    "10.0.0.1:37017",
+			MachineTag: "machine-0",
+			State:      "PRIMARY",
+			Healthy:    true,
+			Votes:      1,
+		}, {
+			Id:         2,
+			Address:    "10.0.0.2:37017",
+			MachineTag: "machine-1",
+			State:      "SECONDARY",
+			Healthy:    true,
+			Votes:      1,
+			Lag:        3 * time.Second,
+		}, {
+			Id:         3,
+			Address:    "10.0.0.3:37017",
+			MachineTag: "machine-2",
+			State:      "UNKNOWN",
+			Error:      "no route to host",
+		}},
+	})
+}
+
+func (s *clientSuite) TestReplicaSetStatusError(c *gc.C) {
+	s.PatchValue(highavailability.CurrentStatus, func(*mgo.Session) (*replicaset.Status, error) {
+		return nil, errors.New("boom")
+	})
+	_, err := s.haServer.ReplicaSetStatus()
+	c.Assert(err, gc.ErrorMatches, "cannot get replica set status: boom")
+}
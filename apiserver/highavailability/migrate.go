@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// MigrateStateServer starts replacing the given state server machine
+// with a new one. The new machine joins the replica set, and takes
+// over the vote of the machine it replaces once it has caught up;
+// RetireStateServer must then be called to remove the old machine.
+func (api *HighAvailabilityAPI) MigrateStateServer(args params.MigrateStateServer) (params.MigrateStateServerResult, error) {
+	st := api.state
+	if !st.IsStateServer() {
+		return params.MigrateStateServerResult{}, errors.New("unsupported with hosted environments")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.MigrateStateServerResult{}, errors.Trace(err)
+	}
+	tag, err := names.ParseMachineTag(args.MachineTag)
+	if err != nil {
+		return params.MigrateStateServerResult{}, errors.Trace(err)
+	}
+	newId, err := st.MigrateStateServer(tag.Id(), args.Constraints, args.Series, args.Placement)
+	if err != nil {
+		return params.MigrateStateServerResult{}, errors.Trace(err)
+	}
+	return params.MigrateStateServerResult{
+		MachineTag: names.NewMachineTag(newId).String(),
+	}, nil
+}
+
+// RetireStateServer removes the given state server machine, which must
+// have been replaced by MigrateStateServer, once it no longer holds a
+// vote in the replica set. The result reports whether the machine was
+// retired; if not, the call should be repeated later.
+func (api *HighAvailabilityAPI) RetireStateServer(args params.Entity) (params.RetireStateServerResult, error) {
+	st := api.state
+	if !st.IsStateServer() {
+		return params.RetireStateServerResult{}, errors.New("unsupported with hosted environments")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.RetireStateServerResult{}, errors.Trace(err)
+	}
+	tag, err := names.ParseMachineTag(args.Tag)
+	if err != nil {
+		return params.RetireStateServerResult{}, errors.Trace(err)
+	}
+	retired, err := st.RetireStateServer(tag.Id())
+	if err != nil {
+		return params.RetireStateServerResult{}, errors.Trace(err)
+	}
+	return params.RetireStateServerResult{Retired: retired}, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/replicaset"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// jujuMachineKey is the replica set member tag that the peergrouper
+// worker uses to record the machine hosting each member.
+const jujuMachineKey = "juju-machine-id"
+
+var (
+	currentStatus  = replicaset.CurrentStatus
+	currentMembers = replicaset.CurrentMembers
+	memberOptimes  = getMemberOptimes
+)
+
+// ReplicaSetStatus returns the state, health, votes and replication lag
+// of each member of the state servers' mongo replica set.
+func (api *HighAvailabilityAPI) ReplicaSetStatus() (params.ReplicaSetStatusResult, error) {
+	if !api.state.IsStateServer() {
+		return params.ReplicaSetStatusResult{}, errors.New("unsupported with hosted environments")
+	}
+	session := api.state.MongoSession().Copy()
+	defer session.Close()
+
+	status, err := currentStatus(session)
+	if err != nil {
+		return params.ReplicaSetStatusResult{}, errors.Annotate(err, "cannot get replica set status")
+	}
+	members, err := currentMembers(session)
+	if err != nil {
+		return params.ReplicaSetStatusResult{}, errors.Annotate(err, "cannot get replica set members")
+	}
+	optimes, err := memberOptimes(session)
+	if err != nil {
+		return params.ReplicaSetStatusResult{}, errors.Annotate(err, "cannot get replica set optimes")
+	}
+	configs := make(map[int]replicaset.Member)
+	for _, m := range members {
+		configs[m.Id] = m
+	}
+	var primaryOptime time.Time
+	for _, m := range status.Members {
+		if m.State == replicaset.PrimaryState {
+			primaryOptime = optimes[m.Id]
+		}
+	}
+
+	result := params.ReplicaSetStatusResult{
+		Name:    status.Name,
+		Members: make([]params.ReplicaSetMember, len(status.Members)),
+	}
+	for i, m := range status.Members {
+		member := params.ReplicaSetMember{
+			Id:      m.Id,
+			Address: m.Address,
+			State:   m.State.String(),
+			Healthy: m.Healthy,
+			Error:   m.ErrMsg,
+			Votes:   1,
+		}
+		if config, ok := configs[m.Id]; ok {
+			if config.Votes != nil {
+				member.Votes = *config.Votes
+			}
+			if id, ok := config.Tags[jujuMachineKey]; ok && names.IsValidMachine(id) {
+				member.MachineTag = names.NewMachineTag(id).String()
+			}
+		}
+		if optime, ok := optimes[m.Id]; ok && !primaryOptime.IsZero() && primaryOptime.After(optime) {
+			member.Lag = primaryOptime.Sub(optime)
+		}
+		result.Members[i] = member
+	}
+	return result, nil
+}
+
+// getMemberOptimes returns the time of the last operation applied by
+// each member of the replica set, keyed by member id.
+func getMemberOptimes(session *mgo.Session) (map[int]time.Time, error) {
+	var status struct {
+		Members []struct {
+			Id         int       `bson:"_id"`
+			OptimeDate time.Time `bson:"optimeDate"`
+		} `bson:"members"`
+	}
+	if err := session.Run("replSetGetStatus", &status); err != nil {
+		return nil, errors.Trace(err)
+	}
+	optimes := make(map[int]time.Time)
+	for _, m := range status.Members {
+		optimes[m.Id] = m.OptimeDate
+	}
+	return optimes, nil
+}
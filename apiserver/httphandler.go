@@ -48,6 +48,16 @@ func (h *httpHandler) authError(w http.ResponseWriter, sender errorSender) {
 	sender.sendError(w, http.StatusUnauthorized, "unauthorized")
 }
 
+// userAuthError sends the error for a request from a user who could
+// not be authenticated, or who may not make the request.
+func (h *httpHandler) userAuthError(w http.ResponseWriter, sender errorSender, err error) {
+	if errors.Cause(err) == common.ErrPerm {
+		sender.sendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	h.authError(w, sender)
+}
+
 func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, error) {
 	envUUID := h.getEnvironUUID(r)
 	envState, needsClosing, err := validateEnvironUUID(validateArgs{
@@ -71,7 +81,7 @@ func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, e
 
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
-func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
+func (h *httpStateWrapper) authenticate(r *http.Request) (state.Entity, error) {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
@@ -88,38 +98,72 @@ func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 		return nil, errors.New("invalid request format")
 	}
 	// Ensure that a sensible tag was passed.
-	tag, err := names.ParseTag(tagPass[0])
-	if err != nil {
+	if _, err := names.ParseTag(tagPass[0]); err != nil {
 		return nil, common.ErrBadCreds
 	}
 	// Ensure the credentials are correct.
-	_, err = checkCreds(h.state, params.LoginRequest{
+	return checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 		Nonce:       r.Header.Get("X-Juju-Nonce"),
 	})
-	return tag, err
 }
 
+// authenticateUser authenticates the request as made by a user. Users
+// with read-only access to the environment may only make GET requests.
 func (h *httpStateWrapper) authenticateUser(r *http.Request) error {
-	tag, err := h.authenticate(r)
+	entity, err := h.authenticate(r)
 	if err != nil {
 		return err
 	}
-	switch tag.(type) {
-	case names.UserTag:
-		return nil
-	default:
+	if _, ok := entity.Tag().(names.UserTag); !ok {
+		return common.ErrBadCreds
+	}
+	if r.Method != "GET" && isReadOnlyUser(h.state, entity) {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// authenticateWriteUser authenticates the request as made by a user
+// with write access to the environment, whatever the request method.
+func (h *httpStateWrapper) authenticateWriteUser(r *http.Request) error {
+	entity, err := h.authenticate(r)
+	if err != nil {
+		return err
+	}
+	if _, ok := entity.Tag().(names.UserTag); !ok {
 		return common.ErrBadCreds
 	}
+	if isReadOnlyUser(h.state, entity) {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// authenticateStateServerAdmin authenticates the request, and checks
+// that it was made by the owner of the state server environment.
+func (h *httpStateWrapper) authenticateStateServerAdmin(r *http.Request) error {
+	entity, err := h.authenticate(r)
+	if err != nil {
+		return err
+	}
+	env, err := h.state.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if userTag, ok := entity.Tag().(names.UserTag); !ok || userTag != env.Owner() {
+		return common.ErrPerm
+	}
+	return nil
 }
 
 func (h *httpStateWrapper) authenticateAgent(r *http.Request) (names.Tag, error) {
-	tag, err := h.authenticate(r)
+	entity, err := h.authenticate(r)
 	if err != nil {
 		return nil, err
 	}
-	switch tag.(type) {
+	switch tag := entity.Tag(); tag.(type) {
 	case names.MachineTag:
 		return tag, nil
 	case names.UnitTag:
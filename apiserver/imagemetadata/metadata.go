@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package imagemetadata provides the API through which custom cloud
+// image metadata is stored on the controller, listed and deleted.
+// Providers consult the stored metadata when they start instances, so
+// private image catalogs don't need a simplestreams web server.
+package imagemetadata
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ImageMetadata", 1, NewAPI)
+}
+
+// API implements the ImageMetadata facade.
+type API struct {
+	st    *state.State
+	check *common.BlockChecker
+}
+
+// NewAPI returns a new ImageMetadata API facade, for clients only.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st, check: common.NewBlockChecker(st)}, nil
+}
+
+// List returns the stored image metadata that matches the given filter.
+func (api *API) List(filter params.ImageMetadataFilter) (params.ListCloudImageMetadataResult, error) {
+	found, err := api.st.FindCloudImageMetadata(state.CloudImageMetadataFilter{
+		Region:          filter.Region,
+		Series:          filter.Series,
+		Arches:          filter.Arches,
+		Stream:          filter.Stream,
+		VirtType:        filter.VirtType,
+		RootStorageType: filter.RootStorageType,
+	})
+	if err != nil {
+		return params.ListCloudImageMetadataResult{}, errors.Trace(err)
+	}
+	result := params.ListCloudImageMetadataResult{
+		Result: make([]params.CloudImageMetadata, len(found)),
+	}
+	for i, m := range found {
+		result.Result[i] = params.CloudImageMetadata{
+			ImageId:         m.ImageId,
+			Stream:          m.Stream,
+			Region:          m.Region,
+			Series:          m.Series,
+			Arch:            m.Arch,
+			VirtType:        m.VirtType,
+			RootStorageType: m.RootStorageType,
+		}
+	}
+	return result, nil
+}
+
+// Save stores the given image metadata, replacing the image ids of
+// any stored before with the same attributes.
+func (api *API) Save(args params.MetadataSaveParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Metadata))}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, m := range args.Metadata {
+		err := api.st.SaveCloudImageMetadata([]state.CloudImageMetadata{{
+			CloudImageMetadataAttributes: state.CloudImageMetadataAttributes{
+				Stream:          m.Stream,
+				Region:          m.Region,
+				Series:          m.Series,
+				Arch:            m.Arch,
+				VirtType:        m.VirtType,
+				RootStorageType: m.RootStorageType,
+			},
+			ImageId: m.ImageId,
+		}})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Delete removes the stored metadata of the images with the given ids.
+func (api *API) Delete(args params.MetadataImageIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Ids))}
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, imageId := range args.Ids {
+		err := api.st.DeleteCloudImageMetadata(imageId)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagemetadata_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/imagemetadata"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type imageMetadataSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	api *imagemetadata.API
+}
+
+var _ = gc.Suite(&imageMetadataSuite{})
+
+func (s *imageMetadataSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = imagemetadata.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+}
+
+func (s *imageMetadataSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := imagemetadata.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+var trustyImage = params.CloudImageMetadata{
+	ImageId:         "ami-1",
+	Stream:          "released",
+	Region:          "us-east-1",
+	Series:          "trusty",
+	Arch:            "amd64",
+	VirtType:        "hvm",
+	RootStorageType: "ebs",
+}
+
+var preciseImage = params.CloudImageMetadata{
+	ImageId: "ami-2",
+	Stream:  "released",
+	Region:  "us-west-1",
+	Series:  "precise",
+	Arch:    "i386",
+}
+
+func (s *imageMetadataSuite) TestSave(c *gc.C) {
+	results, err := s.api.Save(params.MetadataSaveParams{
+		Metadata: []params.CloudImageMetadata{
+			trustyImage,
+			{ImageId: "ami-3", Series: "trusty", Arch: "amd64"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `cannot save cloud image metadata: metadata for image "ami-3" without region not valid`)
+
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Assert(metadata[0].ImageId, gc.Equals, "ami-1")
+	c.Assert(metadata[0].VirtType, gc.Equals, "hvm")
+}
+
+func (s *imageMetadataSuite) TestBlockSave(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSave")
+	_, err := s.api.Save(params.MetadataSaveParams{
+		Metadata: []params.CloudImageMetadata{trustyImage},
+	})
+	s.AssertBlocked(c, err, "TestBlockSave")
+}
+
+func (s *imageMetadataSuite) TestList(c *gc.C) {
+	_, err := s.api.Save(params.MetadataSaveParams{
+		Metadata: []params.CloudImageMetadata{trustyImage, preciseImage},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.List(params.ImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, jc.SameContents, []params.CloudImageMetadata{trustyImage, preciseImage})
+
+	result, err = s.api.List(params.ImageMetadataFilter{
+		Series: []string{"precise"},
+		Arches: []string{"i386", "armhf"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, jc.DeepEquals, []params.CloudImageMetadata{preciseImage})
+}
+
+func (s *imageMetadataSuite) TestDelete(c *gc.C) {
+	_, err := s.api.Save(params.MetadataSaveParams{
+		Metadata: []params.CloudImageMetadata{trustyImage, preciseImage},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.Delete(params.MetadataImageIds{Ids: []string{"ami-1", "ami-9"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	result, err := s.api.List(params.ImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, jc.DeepEquals, []params.CloudImageMetadata{preciseImage})
+}
+
+func (s *imageMetadataSuite) TestBlockDelete(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockDelete")
+	_, err := s.api.Delete(params.MetadataImageIds{Ids: []string{"ami-1"}})
+	s.AssertBlocked(c, err, "TestBlockDelete")
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instanceconsole provides the API through which the console
+// output of machine instances is retrieved from the provider.
+package instanceconsole
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("InstanceConsole", 1, NewAPI)
+}
+
+// API implements the InstanceConsole facade.
+type API struct {
+	st *state.State
+}
+
+// NewAPI returns a new InstanceConsole API facade, for clients only.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// ConsoleOutput returns the console output of the instances of the
+// given machines, as captured by the provider. This is most useful for
+// machines whose agents never connected to the API server.
+func (api *API) ConsoleOutput(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{Results: make([]params.StringResult, len(args.Entities))}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	console, err := api.instanceConsole()
+	if err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		output, err := api.consoleOutput(console, entity.Tag)
+		result.Results[i].Result = output
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// instanceConsole returns the environ of the state, if it can retrieve
+// the console output of instances.
+func (api *API) instanceConsole() (environs.InstanceConsole, error) {
+	cfg, err := api.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	console, ok := environs.SupportsInstanceConsole(env)
+	if !ok {
+		return nil, errors.NotSupportedf("retrieving console output on provider %q", cfg.Type())
+	}
+	return console, nil
+}
+
+func (api *API) consoleOutput(console environs.InstanceConsole, tag string) (string, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return "", common.ErrPerm
+	}
+	machine, err := api.st.Machine(machineTag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	instId, err := machine.InstanceId()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return console.InstanceConsoleOutput(instId)
+}
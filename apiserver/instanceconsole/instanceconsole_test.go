@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instanceconsole_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/instanceconsole"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type instanceConsoleSuite struct {
+	jujutesting.JujuConnSuite
+
+	api *instanceconsole.API
+}
+
+var _ = gc.Suite(&instanceConsoleSuite{})
+
+func (s *instanceConsoleSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = instanceconsole.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *instanceConsoleSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := instanceconsole.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *instanceConsoleSuite) TestConsoleOutput(c *gc.C) {
+	provisioned, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	inst, hc, _, err := jujutesting.StartInstance(s.Environ, provisioned.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = provisioned.SetProvisioned(inst.Id(), "fake_nonce", hc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unprovisioned, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ConsoleOutput(params.Entities{Entities: []params.Entity{
+		{Tag: provisioned.Tag().String()},
+		{Tag: unprovisioned.Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{Results: []params.StringResult{
+		{Result: `console output of instance "` + string(inst.Id()) + `"` + "\n"},
+		{Error: apiservertesting.NotProvisionedError(unprovisioned.Id())},
+		{Error: apiservertesting.NotFoundError("machine 42")},
+		{Error: apiservertesting.ErrUnauthorized},
+	}})
+}
+
+func (s *instanceConsoleSuite) TestConsoleOutputNoEntities(c *gc.C) {
+	results, err := s.api.ConsoleOutput(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+}
@@ -0,0 +1,260 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// introspectionHandler serves the profiling and metrics endpoints,
+// which are only available to the administrator of the state server
+// environment.
+type introspectionHandler struct {
+	httpHandler
+	handler http.Handler
+}
+
+func (h *introspectionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(req)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+	if err := stateWrapper.authenticateStateServerAdmin(req); err != nil {
+		if errors.Cause(err) == common.ErrPerm {
+			h.sendError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.authError(w, h)
+		}
+		return
+	}
+	h.handler.ServeHTTP(w, req)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *introspectionHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	body, err := json.Marshal(&params.ErrorResult{
+		Error: common.ServerError(errors.New(message)),
+	})
+	if err != nil {
+		logger.Errorf("failed to send error: %v", err)
+		return
+	}
+	w.Write(body)
+}
+
+// newPprofHandler returns a handler serving the runtime profiling
+// data under /debug/pprof/, in the format expected by "go tool pprof".
+func newPprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	return mux
+}
+
+// serveMetrics writes the server's metrics in the Prometheus text
+// exposition format.
+func (srv *Server) serveMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	srv.metrics.write(w, srv.rateLimiter.metrics(), state.GetTxnMetrics())
+}
+
+// callKey identifies the API method of a call.
+type callKey struct {
+	facade string
+	method string
+}
+
+// callStats holds the number of calls made to an API method, and the
+// total time taken to serve them.
+type callStats struct {
+	count    int64
+	errors   int64
+	duration time.Duration
+}
+
+// apiMetrics records the activity of the API server for the metrics
+// endpoint.
+type apiMetrics struct {
+	// connections must be accessed atomically.
+	connections int64
+
+	mu        sync.Mutex
+	calls     map[callKey]*callStats
+	resources map[*common.Resources]bool
+}
+
+func newAPIMetrics() *apiMetrics {
+	return &apiMetrics{
+		calls:     make(map[callKey]*callStats),
+		resources: make(map[*common.Resources]bool),
+	}
+}
+
+// connOpened records that an API connection has been opened.
+func (m *apiMetrics) connOpened() {
+	atomic.AddInt64(&m.connections, 1)
+}
+
+// connClosed records that an API connection has been closed.
+func (m *apiMetrics) connClosed() {
+	atomic.AddInt64(&m.connections, -1)
+}
+
+// addResources records the resources of a connection, so that the
+// watchers held for it are counted.
+func (m *apiMetrics) addResources(resources *common.Resources) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resources[resources] = true
+}
+
+// removeResources stops counting the watchers in resources.
+func (m *apiMetrics) removeResources(resources *common.Resources) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.resources, resources)
+}
+
+// unknownCallKey is the key under which calls to methods that the
+// server does not serve are recorded. The facade and method names come
+// from the client, so recording them as given would let clients make
+// the server keep, and report, any number of keys.
+var unknownCallKey = callKey{"unknown", "unknown"}
+
+// recordCall records a call to the API method of the given request.
+func (m *apiMetrics) recordCall(req rpc.Request, failed bool, timeSpent time.Duration) {
+	key := callKey{req.Type, req.Action}
+	if !isServedMethod(req) {
+		key = unknownCallKey
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.calls[key]
+	if !ok {
+		stats = &callStats{}
+		m.calls[key] = stats
+	}
+	stats.count++
+	if failed {
+		stats.errors++
+	}
+	stats.duration += timeSpent
+}
+
+// isServedMethod reports whether the request is for a method of the
+// Admin facade or of a registered facade.
+func isServedMethod(req rpc.Request) bool {
+	if req.Type == "Admin" {
+		return req.Action == "Login"
+	}
+	facadeType, err := common.Facades.GetType(req.Type, req.Version)
+	if err != nil {
+		return false
+	}
+	_, err = rpcreflect.ObjTypeOf(facadeType).Method(req.Action)
+	return err == nil
+}
+
+// write writes the metrics, along with the given rate limiting and
+// transaction metrics and those of the Go runtime, to w.
+func (m *apiMetrics) write(w io.Writer, rateLimits RateLimitMetrics, txns state.TxnMetrics) {
+	m.mu.Lock()
+	keys := make([]callKey, 0, len(m.calls))
+	calls := make(map[callKey]callStats)
+	for key, stats := range m.calls {
+		keys = append(keys, key)
+		calls[key] = *stats
+	}
+	watchers := 0
+	for resources := range m.resources {
+		watchers += resources.CountWatchers()
+	}
+	m.mu.Unlock()
+	sort.Sort(callKeys(keys))
+
+	writeHeader(w, "juju_api_requests_total", "counter", "Number of API requests served.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "juju_api_requests_total%s %d\n", key.labels(), calls[key].count)
+	}
+	writeHeader(w, "juju_api_request_errors_total", "counter", "Number of API requests that failed.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "juju_api_request_errors_total%s %d\n", key.labels(), calls[key].errors)
+	}
+	writeHeader(w, "juju_api_request_duration_seconds", "summary", "Time taken to serve API requests.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "juju_api_request_duration_seconds_sum%s %g\n", key.labels(), calls[key].duration.Seconds())
+		fmt.Fprintf(w, "juju_api_request_duration_seconds_count%s %d\n", key.labels(), calls[key].count)
+	}
+	writeHeader(w, "juju_api_requests_rejected_total", "counter", "Number of API requests rejected by the rate limits.")
+	fmt.Fprintf(w, "juju_api_requests_rejected_total %d\n", rateLimits.CallsRejected)
+	writeHeader(w, "juju_api_logins_rejected_total", "counter", "Number of agent logins rejected by the rate limits.")
+	fmt.Fprintf(w, "juju_api_logins_rejected_total{reason=\"concurrency\"} %d\n", rateLimits.ConcurrentLoginsRejected)
+	fmt.Fprintf(w, "juju_api_logins_rejected_total{reason=\"rate\"} %d\n", rateLimits.LoginAttemptsRejected)
+	writeHeader(w, "juju_api_connections", "gauge", "Number of open API connections.")
+	fmt.Fprintf(w, "juju_api_connections %d\n", atomic.LoadInt64(&m.connections))
+	writeHeader(w, "juju_api_watchers", "gauge", "Number of watchers held for API connections.")
+	fmt.Fprintf(w, "juju_api_watchers %d\n", watchers)
+
+	writeHeader(w, "juju_mongo_txn_attempts_total", "counter", "Number of MongoDB transactions attempted.")
+	fmt.Fprintf(w, "juju_mongo_txn_attempts_total %d\n", txns.Attempts)
+	writeHeader(w, "juju_mongo_txn_aborted_total", "counter", "Number of MongoDB transactions aborted by failed assertions.")
+	fmt.Fprintf(w, "juju_mongo_txn_aborted_total %d\n", txns.Aborted)
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	writeHeader(w, "go_goroutines", "gauge", "Number of goroutines that currently exist.")
+	fmt.Fprintf(w, "go_goroutines %d\n", runtime.NumGoroutine())
+	writeHeader(w, "go_memstats_alloc_bytes", "gauge", "Number of bytes allocated and still in use.")
+	fmt.Fprintf(w, "go_memstats_alloc_bytes %d\n", memStats.Alloc)
+	writeHeader(w, "go_memstats_sys_bytes", "gauge", "Number of bytes obtained from the system.")
+	fmt.Fprintf(w, "go_memstats_sys_bytes %d\n", memStats.Sys)
+	writeHeader(w, "go_gc_runs_total", "counter", "Number of completed garbage collections.")
+	fmt.Fprintf(w, "go_gc_runs_total %d\n", memStats.NumGC)
+	writeHeader(w, "go_gc_pause_seconds_total", "counter", "Total time spent paused for garbage collection.")
+	fmt.Fprintf(w, "go_gc_pause_seconds_total %g\n", time.Duration(memStats.PauseTotalNs).Seconds())
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labels returns the Prometheus labels identifying the method.
+func (key callKey) labels() string {
+	return fmt.Sprintf("{facade=%q,method=%q}", key.facade, key.method)
+}
+
+type callKeys []callKey
+
+func (k callKeys) Len() int      { return len(k) }
+func (k callKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k callKeys) Less(i, j int) bool {
+	if k[i].facade != k[j].facade {
+		return k[i].facade < k[j].facade
+	}
+	return k[i].method < k[j].method
+}
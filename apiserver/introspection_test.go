@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type introspectionSuite struct {
+	userAuthHttpSuite
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+func (s *introspectionSuite) adminGet(c *gc.C, path string) (*http.Response, string) {
+	uri := s.makeURL(c, "https", path, nil).String()
+	resp, err := s.sendRequest(c, s.AdminUserTag(c).String(), "dummy-secret", "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp, string(body)
+}
+
+func (s *introspectionSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, apihttp.CTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, expError)
+}
+
+func (s *introspectionSuite) TestRequiresAuth(c *gc.C) {
+	for _, path := range []string{"/metrics", "/debug/pprof/"} {
+		uri := s.makeURL(c, "https", path, nil).String()
+		resp, err := s.sendRequest(c, "", "", "GET", uri, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+	}
+}
+
+func (s *introspectionSuite) TestRequiresStateServerAdmin(c *gc.C) {
+	for _, path := range []string{"/metrics", "/debug/pprof/"} {
+		uri := s.makeURL(c, "https", path, nil).String()
+		resp, err := s.authRequest(c, "GET", uri, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+	}
+}
+
+func (s *introspectionSuite) TestMetrics(c *gc.C) {
+	_, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, body := s.adminGet(c, "/metrics")
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "text/plain; version=0.0.4")
+	c.Assert(body, gc.Matches, `(?s).*\njuju_api_requests_total{facade="Client",method="EnvironmentGet"} [1-9]\d*\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_api_request_duration_seconds_count{facade="Client",method="EnvironmentGet"} [1-9]\d*\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_api_connections [1-9]\d*\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_mongo_txn_attempts_total \d+\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\ngo_goroutines [1-9]\d*\n.*`)
+}
+
+func (s *introspectionSuite) TestMetricsUnknownCalls(c *gc.C) {
+	err := s.APIState.APICall("NoSuchFacade", 0, "", "EnvironmentGet", nil, nil)
+	c.Assert(err, gc.NotNil)
+	err = s.APIState.APICall("Client", 0, "", "NoSuchMethod", nil, nil)
+	c.Assert(err, gc.NotNil)
+
+	resp, body := s.adminGet(c, "/metrics")
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_api_requests_total{facade="unknown",method="unknown"} 2\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_api_request_errors_total{facade="unknown",method="unknown"} 2\n.*`)
+	c.Assert(body, gc.Not(gc.Matches), `(?s).*NoSuch.*`)
+}
+
+func (s *introspectionSuite) TestPprofIndex(c *gc.C) {
+	resp, body := s.adminGet(c, "/debug/pprof/")
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Matches, `(?s).*goroutine.*`)
+}
+
+func (s *introspectionSuite) TestPprofProfile(c *gc.C) {
+	resp, body := s.adminGet(c, "/debug/pprof/goroutine?debug=1")
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Matches, `(?s)goroutine profile: total \d+\n.*`)
+}
+
+func (s *introspectionSuite) TestPprofCmdline(c *gc.C) {
+	resp, _ := s.adminGet(c, "/debug/pprof/cmdline")
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Not(gc.Equals), apihttp.CTypeJSON)
+}
@@ -36,12 +36,19 @@ type KeyManager interface {
 
 // KeyUpdaterAPI implements the KeyUpdater interface and is the concrete
 // implementation of the api end point.
+//
+// The authorised ssh keys distributed to machines are those held in the
+// authorized-keys environment setting. Keys added by users other than
+// the environment owner are also recorded against those users, so that
+// each user lists and deletes only their own keys; keys not recorded
+// against any user belong to the environment owner.
 type KeyManagerAPI struct {
 	state      *state.State
 	resources  *common.Resources
 	authorizer common.Authorizer
 	canRead    func(string) bool
 	canWrite   func(string) bool
+	isShared   func(string) bool
 	check      *common.BlockChecker
 }
 
@@ -59,39 +66,108 @@ func NewKeyManagerAPI(st *state.State, resources *common.Resources, authorizer c
 	}
 	// For gccgo interface comparisons, we need a Tag.
 	owner := names.Tag(env.Owner())
-	// TODO(wallyworld) - replace stub with real canRead function
-	// For now, only admins can read authorised ssh keys.
-	canRead := func(user string) bool {
+	ownerName := env.Owner().Name()
+	// canAccess reports whether the authenticated entity may read and
+	// write the keys of the given user. Machine agents may access the
+	// Juju system key; the environment owner may access the keys of
+	// all users; other users may only access their own keys.
+	canAccess := func(user string) bool {
 		// Are we a machine agent operating as the system identity?
 		if user == config.JujuSystemKey {
 			_, ismachinetag := authorizer.GetAuthTag().(names.MachineTag)
 			return ismachinetag
 		}
-		return authorizer.GetAuthTag() == owner
-	}
-	// TODO(wallyworld) - replace stub with real canWrite function
-	// For now, only admins can write authorised ssh keys for users.
-	// Machine agents can write the juju-system-key.
-	canWrite := func(user string) bool {
-		// Are we a machine agent writing the Juju system key.
-		if user == config.JujuSystemKey {
-			_, ismachinetag := authorizer.GetAuthTag().(names.MachineTag)
-			return ismachinetag
+		if !names.IsValidUser(user) {
+			return false
+		}
+		authTag := authorizer.GetAuthTag()
+		if userTag, ok := authTag.(names.UserTag); ok && userTag.Name() == user {
+			return true
+		}
+		if authTag != owner {
+			return false
+		}
+		if user == ownerName {
+			return true
 		}
-		// No point looking to see if the user exists as we are not
-		// yet storing keys on the user.
-		return authorizer.GetAuthTag() == owner
+		_, err := st.User(names.NewLocalUserTag(user))
+		return err == nil
+	}
+	// isShared reports whether the keys of the given user are those
+	// not recorded against any user.
+	isShared := func(user string) bool {
+		return user == ownerName || user == config.JujuSystemKey
 	}
 	return &KeyManagerAPI{
 		state:      st,
 		resources:  resources,
 		authorizer: authorizer,
-		canRead:    canRead,
-		canWrite:   canWrite,
+		canRead:    canAccess,
+		canWrite:   canAccess,
+		isShared:   isShared,
 		check:      common.NewBlockChecker(st),
 	}, nil
 }
 
+// userKeyOwners returns the names of the users against which keys are
+// recorded, keyed by key fingerprint.
+func (api *KeyManagerAPI) userKeyOwners() (map[string]string, error) {
+	userKeys, err := api.state.AllUserSSHKeys()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owners := make(map[string]string)
+	for user, keys := range userKeys {
+		for _, key := range keys {
+			fingerprint, _, err := ssh.KeyFingerprint(key)
+			if err != nil {
+				continue
+			}
+			owners[fingerprint] = user
+		}
+	}
+	return owners, nil
+}
+
+// ownsKey reports whether the key with the given fingerprint belongs
+// to the given user, according to the given key owners.
+func (api *KeyManagerAPI) ownsKey(user, fingerprint string, owners map[string]string) bool {
+	owner, recorded := owners[fingerprint]
+	if !recorded {
+		return api.isShared(user)
+	}
+	return owner == user
+}
+
+// userKeys returns those of the given keys that belong to the given user.
+func (api *KeyManagerAPI) userKeys(user string, keys []string, owners map[string]string) []string {
+	var result []string
+	for _, key := range keys {
+		fingerprint, _, err := ssh.KeyFingerprint(key)
+		if err != nil {
+			// Invalid keys cannot be recorded against users.
+			if api.isShared(user) {
+				result = append(result, key)
+			}
+			continue
+		}
+		if api.ownsKey(user, fingerprint, owners) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// recordUserKeys records the keys that the given user has added, or
+// that remain after deleting some of them. The keys of the environment
+// owner and the Juju system key are not recorded.
+func (api *KeyManagerAPI) recordUserKeys(user string, keys []string) error {
+	if api.isShared(user) {
+		return nil
+	}
+	return api.state.SetUserSSHKeys(user, keys)
+}
+
 // ListKeys returns the authorised ssh keys for the specified users.
 func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResults, error) {
 	if len(arg.Entities.Entities) == 0 {
@@ -99,12 +175,12 @@ func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResult
 	}
 	results := make([]params.StringsResult, len(arg.Entities.Entities))
 
-	// For now, authorised keys are global, common to all users.
-	var keyInfo []string
-	cfg, configErr := api.state.EnvironConfig()
-	if configErr == nil {
-		keys := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
-		keyInfo = parseKeys(keys, arg.Mode)
+	var keys []string
+	var owners map[string]string
+	cfg, err := api.state.EnvironConfig()
+	if err == nil {
+		keys = ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
+		owners, err = api.userKeyOwners()
 	}
 
 	for i, entity := range arg.Entities.Entities {
@@ -113,11 +189,10 @@ func (api *KeyManagerAPI) ListKeys(arg params.ListSSHKeys) (params.StringsResult
 			results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		// All keys are global, no need to look up the user.
-		if configErr == nil {
-			results[i].Result = keyInfo
+		if err == nil {
+			results[i].Result = parseKeys(api.userKeys(entity.Tag, keys, owners), arg.Mode)
 		}
-		results[i].Error = common.ServerError(configErr)
+		results[i].Error = common.ServerError(err)
 	}
 	return params.StringsResults{Results: results}, nil
 }
@@ -156,6 +231,15 @@ func (api *KeyManagerAPI) writeSSHKeys(sshKeys []string) error {
 	return nil
 }
 
+// writeUserSSHKeys writes out the authorised ssh keys of all users,
+// and records the keys of the given user.
+func (api *KeyManagerAPI) writeUserSSHKeys(user string, sshKeys, userKeys []string) error {
+	if err := api.writeSSHKeys(sshKeys); err != nil {
+		return err
+	}
+	return api.recordUserKeys(user, userKeys)
+}
+
 // currentKeyDataForAdd gathers data used when adding ssh keys.
 func (api *KeyManagerAPI) currentKeyDataForAdd() (keys []string, fingerprints set.Strings, err error) {
 	fingerprints = make(set.Strings)
@@ -190,11 +274,16 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
 
-	// For now, authorised keys are global, common to all users.
+	// The keys are distributed to all machines, so they must be
+	// unique across all users.
 	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
 	}
+	userKeys, err := api.state.UserSSHKeys(arg.User)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
 
 	// Ensure we are not going to add invalid or duplicate keys.
 	result.Results = make([]params.ErrorResult, len(arg.Keys))
@@ -209,9 +298,9 @@ func (api *KeyManagerAPI) AddKeys(arg params.ModifyUserSSHKeys) (params.ErrorRes
 			continue
 		}
 		sshKeys = append(sshKeys, key)
+		userKeys = append(userKeys, key)
 	}
-	err = api.writeSSHKeys(sshKeys)
-	if err != nil {
+	if err := api.writeUserSSHKeys(arg.User, sshKeys, userKeys); err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
 	}
 	return result, nil
@@ -280,11 +369,14 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
 
-	// For now, authorised keys are global, common to all users.
 	sshKeys, currentFingerprints, err := api.currentKeyDataForAdd()
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
 	}
+	userKeys, err := api.state.UserSSHKeys(arg.User)
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(err)
+	}
 
 	importedKeyInfo := runSSHKeyImport(arg.Keys)
 	// Ensure we are not going to add invalid or duplicate keys.
@@ -299,24 +391,29 @@ func (api *KeyManagerAPI) ImportKeys(arg params.ModifyUserSSHKeys) (params.Error
 			continue
 		}
 		sshKeys = append(sshKeys, keyInfo.key)
+		userKeys = append(userKeys, keyInfo.key)
 	}
-	err = api.writeSSHKeys(sshKeys)
-	if err != nil {
+	if err := api.writeUserSSHKeys(arg.User, sshKeys, userKeys); err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
 	}
 	return result, nil
 }
 
-// currentKeyDataForDelete gathers data used when deleting ssh keys.
-func (api *KeyManagerAPI) currentKeyDataForDelete() (
-	keys map[string]string, invalidKeys []string, comments map[string]string, err error) {
+// currentKeyDataForDelete gathers data used when deleting the ssh keys
+// of the given user. The keys of the user are indexed by fingerprint;
+// the keys of other users, and invalid keys, are returned as otherKeys.
+func (api *KeyManagerAPI) currentKeyDataForDelete(user string) (
+	keys map[string]string, otherKeys []string, comments map[string]string, err error) {
 
 	cfg, err := api.state.EnvironConfig()
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("reading current key data: %v", err)
 	}
-	// For now, authorised keys are global, common to all users.
 	existingSSHKeys := ssh.SplitAuthorisedKeys(cfg.AuthorizedKeys())
+	owners, err := api.userKeyOwners()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reading current key data: %v", err)
+	}
 
 	// Build up a map of keys indexed by fingerprint, and fingerprints indexed by comment
 	// so we can easily get the key represented by each keyId, which may be either a fingerprint
@@ -327,7 +424,11 @@ func (api *KeyManagerAPI) currentKeyDataForDelete() (
 		fingerprint, comment, err := ssh.KeyFingerprint(key)
 		if err != nil {
 			logger.Debugf("keeping unrecognised existing ssh key %q: %v", key, err)
-			invalidKeys = append(invalidKeys, key)
+			otherKeys = append(otherKeys, key)
+			continue
+		}
+		if !api.ownsKey(user, fingerprint, owners) {
+			otherKeys = append(otherKeys, key)
 			continue
 		}
 		keys[fingerprint] = key
@@ -335,7 +436,7 @@ func (api *KeyManagerAPI) currentKeyDataForDelete() (
 			comments[comment] = fingerprint
 		}
 	}
-	return keys, invalidKeys, comments, nil
+	return keys, otherKeys, comments, nil
 }
 
 // DeleteKeys deletes the authorised ssh keys for the specified user.
@@ -354,13 +455,13 @@ func (api *KeyManagerAPI) DeleteKeys(arg params.ModifyUserSSHKeys) (params.Error
 		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
 	}
 
-	sshKeys, invalidKeys, keyComments, err := api.currentKeyDataForDelete()
+	sshKeys, otherKeys, keyComments, err := api.currentKeyDataForDelete(arg.User)
 	if err != nil {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("reading current key data: %v", err))
 	}
 
-	// We keep all existing invalid keys.
-	keysToWrite := invalidKeys
+	// We keep all existing invalid keys, and those of other users.
+	keysToWrite := otherKeys
 
 	// Find the keys corresponding to the specified key fingerprints or comments.
 	for i, keyId := range arg.Keys {
@@ -377,15 +478,16 @@ func (api *KeyManagerAPI) DeleteKeys(arg params.ModifyUserSSHKeys) (params.Error
 		// We found the key to delete so remove it from those we wish to keep.
 		delete(sshKeys, fingerprint)
 	}
+	var userKeys []string
 	for _, key := range sshKeys {
 		keysToWrite = append(keysToWrite, key)
+		userKeys = append(userKeys, key)
 	}
 	if len(keysToWrite) == 0 {
 		return params.ErrorResults{}, common.ServerError(fmt.Errorf("cannot delete all keys"))
 	}
 
-	err = api.writeSSHKeys(keysToWrite)
-	if err != nil {
+	if err := api.writeUserSSHKeys(arg.User, keysToWrite, userKeys); err != nil {
 		return params.ErrorResults{}, common.ServerError(err)
 	}
 	return result, nil
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/utils/ssh"
 	sshtesting "github.com/juju/juju/utils/ssh/testing"
 )
@@ -107,7 +108,7 @@ func (s *keyManagerSuite) TestListKeys(c *gc.C) {
 	c.Assert(results, gc.DeepEquals, params.StringsResults{
 		Results: []params.StringsResult{
 			{Result: []string{key1, key2, "Invalid key: bad key"}},
-			{Result: []string{key1, key2, "Invalid key: bad key"}},
+			{Error: apiservertesting.ErrUnauthorized},
 		},
 	})
 }
@@ -282,7 +283,6 @@ func (s *keyManagerSuite) assertInvalidUserOperation(c *gc.C, runTestLogic func(
 }
 
 func (s *keyManagerSuite) TestAddKeysInvalidUser(c *gc.C) {
-	c.Skip("no user validation done yet")
 	s.assertInvalidUserOperation(c, func(args params.ModifyUserSSHKeys) error {
 		_, err := s.keymanager.AddKeys(args)
 		return err
@@ -290,13 +290,98 @@ func (s *keyManagerSuite) TestAddKeysInvalidUser(c *gc.C) {
 }
 
 func (s *keyManagerSuite) TestDeleteKeysInvalidUser(c *gc.C) {
-	c.Skip("no user validation done yet")
 	s.assertInvalidUserOperation(c, func(args params.ModifyUserSSHKeys) error {
 		_, err := s.keymanager.DeleteKeys(args)
 		return err
 	})
 }
 
+func (s *keyManagerSuite) userKeyManager(c *gc.C, name string) *keymanager.KeyManagerAPI {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: name})
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = user.UserTag()
+	api, err := keymanager.NewKeyManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *keyManagerSuite) listKeys(c *gc.C, api *keymanager.KeyManagerAPI, user string) []string {
+	results, err := api.ListKeys(params.ListSSHKeys{
+		Entities: params.Entities{[]params.Entity{{Tag: user}}},
+		Mode:     ssh.FullKeys,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	return results.Results[0].Result
+}
+
+func (s *keyManagerSuite) TestUserKeys(c *gc.C) {
+	adminKey := sshtesting.ValidKeyOne.Key + " admin@host"
+	s.setAuthorisedKeys(c, adminKey)
+	bob := s.userKeyManager(c, "bob")
+
+	bobKey := sshtesting.ValidKeyTwo.Key + " bob@host"
+	results, err := bob.AddKeys(params.ModifyUserSSHKeys{User: "bob", Keys: []string{bobKey, adminKey}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: apiservertesting.ServerError(fmt.Sprintf("duplicate ssh key: %s", adminKey))},
+		},
+	})
+	// All keys are distributed to machines, but each user
+	// lists only their own.
+	s.assertEnvironKeys(c, []string{adminKey, bobKey})
+	c.Assert(s.listKeys(c, bob, "bob"), jc.DeepEquals, []string{bobKey})
+	c.Assert(s.listKeys(c, s.keymanager, "bob"), jc.DeepEquals, []string{bobKey})
+	c.Assert(s.listKeys(c, s.keymanager, s.AdminUserTag(c).Name()), jc.DeepEquals, []string{adminKey})
+
+	keys, err := s.State.UserSSHKeys("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{bobKey})
+}
+
+func (s *keyManagerSuite) TestUserCannotAccessOtherUsersKeys(c *gc.C) {
+	adminKey := sshtesting.ValidKeyOne.Key + " admin@host"
+	s.setAuthorisedKeys(c, adminKey)
+	bob := s.userKeyManager(c, "bob")
+	s.userKeyManager(c, "mary")
+
+	args := params.ModifyUserSSHKeys{User: "mary", Keys: []string{sshtesting.ValidKeyTwo.Key}}
+	_, err := bob.AddKeys(args)
+	c.Assert(err, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	results, err := bob.ListKeys(params.ListSSHKeys{
+		Entities: params.Entities{[]params.Entity{{Tag: s.AdminUserTag(c).Name()}}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *keyManagerSuite) TestDeleteUserKeys(c *gc.C) {
+	adminKey := sshtesting.ValidKeyOne.Key + " admin@host"
+	s.setAuthorisedKeys(c, adminKey)
+	bob := s.userKeyManager(c, "bob")
+	bobKey := sshtesting.ValidKeyTwo.Key + " bob@host"
+	_, err := bob.AddKeys(params.ModifyUserSSHKeys{User: "bob", Keys: []string{bobKey}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Bob cannot delete the keys of the environment owner.
+	results, err := bob.DeleteKeys(params.ModifyUserSSHKeys{User: "bob", Keys: []string{"admin@host", "bob@host"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ServerError("invalid ssh key: admin@host")},
+			{Error: nil},
+		},
+	})
+	s.assertEnvironKeys(c, []string{adminKey})
+	keys, err := s.State.UserSSHKeys("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+}
+
 func (s *keyManagerSuite) TestImportKeys(c *gc.C) {
 	s.PatchValue(&keymanager.RunSSHImportId, keymanagertesting.FakeImport)
 
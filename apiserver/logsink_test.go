@@ -18,22 +18,12 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/apiserver"
-	"github.com/juju/juju/feature"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
 
-// logsinkBaseSuite has functionality that's shared between the the 2 logsink related suites
-type logsinkBaseSuite struct {
-	authHttpSuite
-}
-
-func (s *logsinkBaseSuite) logsinkURL(c *gc.C, scheme string) *url.URL {
-	return s.makeURL(c, scheme, "/environment/"+s.State.EnvironUUID()+"/logsink", nil)
-}
-
 type logsinkSuite struct {
-	logsinkBaseSuite
+	authHttpSuite
 	machineTag names.Tag
 	password   string
 	nonce      string
@@ -43,8 +33,7 @@ type logsinkSuite struct {
 var _ = gc.Suite(&logsinkSuite{})
 
 func (s *logsinkSuite) SetUpTest(c *gc.C) {
-	s.SetInitialFeatureFlags(feature.DbLog)
-	s.logsinkBaseSuite.SetUpTest(c)
+	s.authHttpSuite.SetUpTest(c)
 	s.nonce = "nonce"
 	m, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
 		Nonce: s.nonce,
@@ -180,6 +169,10 @@ func (s *logsinkSuite) dialWebsocket(c *gc.C) *websocket.Conn {
 	return s.dialWebsocketInternal(c, s.makeAuthHeader())
 }
 
+func (s *logsinkSuite) logsinkURL(c *gc.C, scheme string) *url.URL {
+	return s.makeURL(c, scheme, "/environment/"+s.State.EnvironUUID()+"/logsink", nil)
+}
+
 func (s *logsinkSuite) dialWebsocketInternal(c *gc.C, header http.Header) *websocket.Conn {
 	server := s.logsinkURL(c, "wss").String()
 	return s.dialWebsocketFromURL(c, server, header)
@@ -198,16 +191,3 @@ func (s *logsinkSuite) makeAuthHeader() http.Header {
 	header.Add("X-Juju-Nonce", s.nonce)
 	return header
 }
-
-type logsinkNoFeatureSuite struct {
-	logsinkBaseSuite
-}
-
-var _ = gc.Suite(&logsinkNoFeatureSuite{})
-
-func (s *logsinkNoFeatureSuite) TestNoApiWithoutFeatureFlag(c *gc.C) {
-	server := s.logsinkURL(c, "wss").String()
-	config := s.makeWebsocketConfigFromURL(c, server, nil)
-	_, err := websocket.DialConfig(config)
-	c.Assert(err, gc.ErrorMatches, ".+/logsink: bad status$")
-}
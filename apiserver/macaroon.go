@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/macaroon-bakery.v0/bakery"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// newMacaroonAuthenticator returns the authenticator of users logging
+// in with macaroons discharged by the environment's external identity
+// provider, or nil if there is none.
+func newMacaroonAuthenticator(st *state.State) (*authentication.ExternalMacaroonAuthenticator, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	idURL := cfg.IdentityURL()
+	if idURL == "" {
+		return nil, nil
+	}
+	var idPublicKey bakery.PublicKey
+	if err := idPublicKey.UnmarshalText([]byte(cfg.IdentityPublicKey())); err != nil {
+		return nil, errors.Annotate(err, "invalid identity public key")
+	}
+	key, err := macaroonKey(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	service, err := bakery.NewService(bakery.NewServiceParams{
+		Location: "juju environment " + st.EnvironUUID(),
+		Store:    st.MacaroonStorage(),
+		Key:      key,
+		Locator: bakery.PublicKeyLocatorMap{
+			idURL: &idPublicKey,
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &authentication.ExternalMacaroonAuthenticator{
+		Service:          service,
+		IdentityLocation: idURL,
+	}, nil
+}
+
+// macaroonKey returns the key pair of the environment's macaroon
+// service, generating and recording it in state first if need be. The
+// key pair and the macaroons' root keys are kept in state so that every
+// API server accepts the macaroons minted by the others, and across
+// restarts.
+func macaroonKey(st *state.State) (*bakery.KeyPair, error) {
+	stored, err := st.MacaroonKey()
+	if errors.IsNotFound(err) {
+		key, err := bakery.GenerateKey()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot generate macaroon key")
+		}
+		stored, err = st.EnsureMacaroonKey(state.MacaroonKey{
+			Public:  key.Public.Key[:],
+			Private: key.Private.Key[:],
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var key bakery.KeyPair
+	if len(stored.Public) != len(key.Public.Key) || len(stored.Private) != len(key.Private.Key) {
+		return nil, errors.New("invalid macaroon key in state")
+	}
+	copy(key.Public.Key[:], stored.Public)
+	copy(key.Private.Key[:], stored.Private)
+	return &key, nil
+}
+
+// externalUser is the entity of a user authenticated by the external
+// identity provider, which has no user document in state.
+type externalUser struct {
+	tag names.UserTag
+}
+
+// Tag implements state.Entity.
+func (u *externalUser) Tag() names.Tag {
+	return u.tag
+}
+
+// checkMacaroonCreds authenticates the user logging in with the
+// macaroons in the given request.
+func (a *admin) checkMacaroonCreds(req params.LoginRequest) (state.Entity, error) {
+	if a.srv.macaroonAuth == nil {
+		return nil, common.ErrBadCreds
+	}
+	tag, err := a.srv.macaroonAuth.Authenticate(req.Macaroons)
+	if err != nil {
+		return nil, err
+	}
+	// Users from the identity provider need to have been given access
+	// to the environment.
+	if _, err := a.root.state.EnvironmentUser(tag); err != nil {
+		return nil, errors.Wrap(err, common.ErrBadCreds)
+	}
+	return &externalUser{tag}, nil
+}
+
+// checkScopedCreds authenticates the owner of the scoped credential
+// that the macaroons in the given request were minted for.
+func (a *admin) checkScopedCreds(req params.LoginRequest) (state.Entity, *state.ScopedCredential, error) {
+	authenticator := &authentication.ScopedCredentialAuthenticator{
+		Credentials: a.root.state,
+	}
+	cred, err := authenticator.Authenticate(req.Macaroons)
+	if err != nil {
+		return nil, nil, err
+	}
+	owner := cred.Owner()
+	var entity state.Entity = &externalUser{owner}
+	if owner.IsLocal() {
+		user, err := a.root.state.User(owner)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, common.ErrBadCreds)
+		}
+		if user.IsDisabled() {
+			return nil, nil, common.ErrBadCreds
+		}
+		entity = user
+	}
+	// The owner must still have access to the environment.
+	if _, err := a.root.state.EnvironmentUser(owner); err != nil {
+		return nil, nil, errors.Wrap(err, common.ErrBadCreds)
+	}
+	return entity, cred, nil
+}
@@ -8,11 +8,6 @@ import (
 	"github.com/juju/testing"
 )
 
-func PatchHostAndCertPool(host string, certPool *x509.CertPool) func() {
-	restoreHost := testing.PatchValue(&metricsHost, host)
-	restoreCertsPool := testing.PatchValue(&metricsCertsPool, certPool)
-	return func() {
-		restoreHost()
-		restoreCertsPool()
-	}
+func PatchCertPool(certPool *x509.CertPool) func() {
+	return testing.PatchValue(&metricsCertsPool, certPool)
 }
@@ -15,14 +15,13 @@ import (
 	"github.com/juju/juju/apiserver/metricsender/wireformat"
 )
 
-var (
-	metricsCertsPool *x509.CertPool
-	metricsHost      string
-)
+var metricsCertsPool *x509.CertPool
 
 // DefaultSender is the default used for sending
 // metrics to the collector service.
 type DefaultSender struct {
+	// URL holds the address of the collector service.
+	URL string
 }
 
 // Send sends the given metrics to the collector service.
@@ -34,7 +33,7 @@ func (s *DefaultSender) Send(metrics []*wireformat.MetricBatch) (*wireformat.Res
 	r := bytes.NewBuffer(b)
 	t := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: metricsCertsPool}}
 	client := &http.Client{Transport: t}
-	resp, err := client.Post(metricsHost, "application/json", r)
+	resp, err := client.Post(s.URL, "application/json", r)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -27,6 +27,7 @@ type SenderSuite struct {
 	jujutesting.JujuConnSuite
 	unit           *state.Unit
 	meteredService *state.Service
+	collectorURL   string
 }
 
 var _ = gc.Suite(&SenderSuite{})
@@ -50,8 +51,9 @@ func (s *SenderSuite) SetUpTest(c *gc.C) {
 	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.meteredService, SetCharmURL: true})
 }
 
-// startServer starts a server with TLS and the specified handler, returning a
-// function that should be run at the end of the test to clean up.
+// startServer starts a server with TLS and the specified handler, recording
+// its URL in s.collectorURL, and returning a function that should be run at
+// the end of the test to clean up.
 func (s *SenderSuite) startServer(c *gc.C, handler http.Handler) func() {
 	ts := httptest.NewUnstartedServer(handler)
 	certPool, cert := createCerts(c, "127.0.0.1")
@@ -59,7 +61,8 @@ func (s *SenderSuite) startServer(c *gc.C, handler http.Handler) func() {
 		Certificates: []tls.Certificate{cert},
 	}
 	ts.StartTLS()
-	cleanup := metricsender.PatchHostAndCertPool(ts.URL, certPool)
+	cleanup := metricsender.PatchCertPool(certPool)
+	s.collectorURL = ts.URL
 	return func() {
 		ts.Close()
 		cleanup()
@@ -83,7 +86,7 @@ func (s *SenderSuite) TestDefaultSender(c *gc.C) {
 	for i := range metrics {
 		metrics[i] = s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now})
 	}
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err := metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -165,7 +168,7 @@ func (s *SenderSuite) TestErrorCodes(c *gc.C) {
 		for i := range batches {
 			batches[i] = s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now})
 		}
-		var sender metricsender.DefaultSender
+		sender := metricsender.DefaultSender{URL: s.collectorURL}
 		err := metricsender.SendMetrics(s.State, &sender, 10)
 		c.Assert(err, gc.ErrorMatches, test.expectedErr)
 		for _, batch := range batches {
@@ -194,7 +197,7 @@ func (s *SenderSuite) TestMeterStatus(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(status.Code, gc.Equals, state.MeterNotSet)
 
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err = metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -239,7 +242,7 @@ func (s *SenderSuite) TestMeterStatusInvalid(c *gc.C) {
 		c.Assert(status.Code, gc.Equals, state.MeterNotSet)
 	}
 
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err := metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -261,7 +264,7 @@ func (s *SenderSuite) TestGracePeriodResponse(c *gc.C) {
 	_ = s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false})
 	cleanup := s.startServer(c, testHandler(c, nil, nil, 47*time.Hour))
 	defer cleanup()
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err := metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 	mm, err := s.State.MetricsManager()
@@ -274,7 +277,7 @@ func (s *SenderSuite) TestNegativeGracePeriodResponse(c *gc.C) {
 
 	cleanup := s.startServer(c, testHandler(c, nil, nil, -47*time.Hour))
 	defer cleanup()
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err := metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 	mm, err := s.State.MetricsManager()
@@ -287,7 +290,7 @@ func (s *SenderSuite) TestZeroGracePeriodResponse(c *gc.C) {
 
 	cleanup := s.startServer(c, testHandler(c, nil, nil, 0))
 	defer cleanup()
-	var sender metricsender.DefaultSender
+	sender := metricsender.DefaultSender{URL: s.collectorURL}
 	err := metricsender.SendMetrics(s.State, &sender, 10)
 	c.Assert(err, jc.ErrorIsNil)
 	mm, err := s.State.MetricsManager()
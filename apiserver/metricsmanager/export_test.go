@@ -7,6 +7,8 @@ import (
 	"github.com/juju/juju/apiserver/metricsender"
 )
 
+var SenderForConfig = senderForConfig
+
 func PatchSender(s metricsender.MetricSender) {
 	sender = s
 }
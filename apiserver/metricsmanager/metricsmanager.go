@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/metricsender"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -20,7 +21,9 @@ var (
 	logger            = loggo.GetLogger("juju.apiserver.metricsmanager")
 	maxBatchesPerSend = 1000
 
-	sender metricsender.MetricSender = &metricsender.NopSender{}
+	// sender, if set, is used instead of the sender chosen by the
+	// environment configuration.
+	sender metricsender.MetricSender
 )
 
 func init() {
@@ -31,6 +34,7 @@ func init() {
 type MetricsManager interface {
 	CleanupOldMetrics(arg params.Entities) (params.ErrorResults, error)
 	SendMetrics(args params.Entities) (params.ErrorResults, error)
+	SetMeterStatus(args params.MeterStatusParams) (params.ErrorResults, error)
 }
 
 // MetricsManagerAPI implements the metrics manager interface and is the concrete
@@ -103,6 +107,27 @@ func (api *MetricsManagerAPI) CleanupOldMetrics(args params.Entities) (params.Er
 	return result, nil
 }
 
+// sender returns the sender that forwards metrics to the collector
+// service configured for the environment. Without a collector, metrics
+// are acknowledged without being sent anywhere.
+func (api *MetricsManagerAPI) sender() (metricsender.MetricSender, error) {
+	if sender != nil {
+		return sender, nil
+	}
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return senderForConfig(cfg), nil
+}
+
+func senderForConfig(cfg *config.Config) metricsender.MetricSender {
+	if collectorURL := cfg.MetricsCollectorURL(); collectorURL != "" {
+		return &metricsender.DefaultSender{URL: collectorURL}
+	}
+	return &metricsender.NopSender{}
+}
+
 // SendMetrics will send any unsent metrics onto the metric collection service.
 func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -125,7 +150,10 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		err = metricsender.SendMetrics(api.state, sender, maxBatchesPerSend)
+		metricSender, err := api.sender()
+		if err == nil {
+			err = metricsender.SendMetrics(api.state, metricSender, maxBatchesPerSend)
+		}
 		if err != nil {
 			err = errors.Annotate(err, "failed to send metrics")
 			logger.Warningf("%v", err)
@@ -135,3 +163,27 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 	}
 	return result, nil
 }
+
+// SetMeterStatus sets the meter status of the given units, which must
+// belong to the current environment. The units' charms are notified of
+// the change by the meter-status-changed hook.
+func (api *MetricsManagerAPI) SetMeterStatus(args params.MeterStatusParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Statuses)),
+	}
+	for i, arg := range args.Statuses {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		unit, err := api.state.Unit(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = unit.SetMeterStatus(arg.Code, arg.Info)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -12,12 +12,14 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/metricsender"
 	"github.com/juju/juju/apiserver/metricsender/testing"
 	"github.com/juju/juju/apiserver/metricsmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -136,6 +138,17 @@ func (s *metricsManagerSuite) TestSendMetrics(c *gc.C) {
 	c.Assert(m.Sent(), jc.IsTrue)
 }
 
+func (s *metricsManagerSuite) TestSenderForConfig(c *gc.C) {
+	sender := metricsmanager.SenderForConfig(coretesting.EnvironConfig(c))
+	c.Assert(sender, gc.FitsTypeOf, &metricsender.NopSender{})
+
+	cfg := coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"metrics-collector-url": "https://metrics.example.com/",
+	})
+	sender = metricsmanager.SenderForConfig(cfg)
+	c.Assert(sender, jc.DeepEquals, &metricsender.DefaultSender{URL: "https://metrics.example.com/"})
+}
+
 func (s *metricsManagerSuite) TestSendOldMetricsInvalidArg(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{"invalid"},
@@ -209,3 +222,24 @@ func (s *metricsManagerSuite) TestLastSuccessfulNotChangedIfNothingToSend(c *gc.
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(mm.LastSuccessfulSend().Equal(time.Time{}), jc.IsTrue)
 }
+
+func (s *metricsManagerSuite) TestSetMeterStatus(c *gc.C) {
+	args := params.MeterStatusParams{Statuses: []params.MeterStatusParam{
+		{Tag: s.unit.Tag().String(), Code: "RED", Info: "payment overdue"},
+		{Tag: s.unit.Tag().String(), Code: "PURPLE"},
+		{Tag: "unit-nope-0", Code: "GREEN"},
+		{Tag: "invalid", Code: "GREEN"},
+	}}
+	result, err := s.metricsmanager.SetMeterStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `invalid meter status "NOT AVAILABLE"`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `unit "nope/0" not found`)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `"invalid" is not a valid unit tag`)
+
+	status, err := s.unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Code, gc.Equals, state.MeterRed)
+	c.Assert(status.Info, gc.Equals, "payment overdue")
+}
@@ -30,6 +30,20 @@ const (
 	// ActionRunning is the status of an Action that has been started but
 	// not completed yet.
 	ActionRunning string = "running"
+
+	// ActionScheduled is the status reported for an Action that has
+	// been scheduled to be queued up later.
+	ActionScheduled string = "scheduled"
+)
+
+const (
+	// ActionOutputStdout identifies the output an Action writes to its
+	// standard output.
+	ActionOutputStdout string = "stdout"
+
+	// ActionOutputStderr identifies the output an Action writes to its
+	// standard error.
+	ActionOutputStderr string = "stderr"
 )
 
 // Actions is a slice of Action for bulk requests.
@@ -43,6 +57,42 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Schedule, if set, defers the queueing of the Action.
+	Schedule *ActionSchedule `json:"schedule,omitempty"`
+
+	// Parallelism, if positive, limits the number of units of the
+	// receiving service on which the Action runs at once.
+	Parallelism int `json:"parallelism,omitempty"`
+}
+
+// ServiceActionResults is a slice of ServiceActionResult for bulk
+// requests.
+type ServiceActionResults struct {
+	Results []ServiceActionResult `json:"results,omitempty"`
+}
+
+// ServiceActionResult describes an Action queued up on the units of a
+// service: those on which it has been queued up, and the tags of those
+// waiting their turn.
+type ServiceActionResult struct {
+	Actions []ActionResult `json:"actions,omitempty"`
+	Waiting []string       `json:"waiting,omitempty"`
+	Error   *Error         `json:"error,omitempty"`
+}
+
+// ActionSchedule describes when an Action is to be queued up: once,
+// at the time given by At, or each time the Cron expression falls due.
+type ActionSchedule struct {
+	Id      string    `json:"id,omitempty"`
+	At      time.Time `json:"at,omitempty"`
+	Cron    string    `json:"cron,omitempty"`
+	NextRun time.Time `json:"nextrun,omitempty"`
+}
+
+// ActionScheduleIds holds the ids of a number of ActionSchedules.
+type ActionScheduleIds struct {
+	Ids []string `json:"ids"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -103,6 +153,48 @@ type ActionExecutionResult struct {
 	Message   string                 `json:"message,omitempty"`
 }
 
+// ActionOutput holds a chunk of the output of an action.
+type ActionOutput struct {
+	Stream    string    `json:"stream"`
+	Data      string    `json:"data"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ActionOutputChunks holds chunks of actions' output for a bulk API
+// call.
+type ActionOutputChunks struct {
+	Chunks []ActionOutputChunk `json:"chunks,omitempty"`
+}
+
+// ActionOutputChunk holds a chunk of the output of a running action,
+// as written by the unit running it.
+type ActionOutputChunk struct {
+	ActionTag string `json:"actiontag"`
+	Stream    string `json:"stream"`
+	Data      string `json:"data"`
+}
+
+// ActionOutputWatchResults holds a slice of ActionOutputWatchResult for
+// a bulk API call.
+type ActionOutputWatchResults struct {
+	Results []ActionOutputWatchResult `json:"results,omitempty"`
+}
+
+// ActionOutputWatchResult holds the id of an ActionOutputWatcher, or
+// the error encountered creating it.
+type ActionOutputWatchResult struct {
+	ActionOutputWatcherId string `json:"watcherid,omitempty"`
+	Error                 *Error `json:"error,omitempty"`
+}
+
+// ActionOutputWatcherNextResult holds the output of an action written
+// since it was last reported, and the action's status. Once the action
+// is no longer pending or running, all of its output has been reported.
+type ActionOutputWatcherNextResult struct {
+	Output []ActionOutput `json:"output,omitempty"`
+	Status string         `json:"status"`
+}
+
 // ServicesCharmActionsResults holds a slice of ServiceCharmActionsResult for
 // a bulk result of charm Actions for Services.
 type ServicesCharmActionsResults struct {
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// AgentHealth holds the health metrics reported by a machine or unit
+// agent.
+type AgentHealth struct {
+	// HookQueueDepth is the number of hooks waiting to be run by the
+	// agent.
+	HookQueueDepth int `json:"hookqueuedepth"`
+
+	// LastAPICall is the time at which the agent last completed an
+	// API call successfully.
+	LastAPICall time.Time `json:"lastapicall"`
+
+	// DataDirAvailable is the space available in the agent's data
+	// directory, in MiB.
+	DataDirAvailable uint64 `json:"datadiravailable"`
+
+	// Reported is the time at which the health was recorded by the
+	// server. It is ignored when setting agent health.
+	Reported time.Time `json:"reported"`
+}
+
+// SetAgentHealth holds the health reported by the agent identified
+// by Tag.
+type SetAgentHealth struct {
+	Tag    string      `json:"tag"`
+	Health AgentHealth `json:"health"`
+}
+
+// SetAgentHealthArgs holds the arguments for recording the health of
+// a set of agents.
+type SetAgentHealthArgs struct {
+	Args []SetAgentHealth `json:"args"`
+}
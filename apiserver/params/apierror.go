@@ -15,6 +15,12 @@ import (
 type Error struct {
 	Message string
 	Code    string
+
+	// TraceId holds the server's trace id for the failed request,
+	// if the server traces requests. It is only set on errors
+	// returned by a call as a whole, not on per-entity errors in
+	// bulk call results.
+	TraceId string `json:",omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -54,6 +60,9 @@ const (
 	CodeActionNotAvailable    = "action no longer available"
 	CodeOperationBlocked      = "operation is blocked"
 	CodeLeadershipClaimDenied = "leadership claim denied"
+	CodeNotValid              = "not valid"
+	CodeConflict              = "conflict"
+	CodeProvisioningFailed    = "provisioning failed"
 )
 
 // ErrCode returns the error code associated with
@@ -81,6 +90,7 @@ func ClientError(err error) error {
 	return &Error{
 		Message: rerr.Message,
 		Code:    rerr.Code,
+		TraceId: rerr.TraceId,
 	}
 }
 
@@ -168,3 +178,15 @@ func IsCodeOperationBlocked(err error) bool {
 func IsCodeLeadershipClaimDenied(err error) bool {
 	return ErrCode(err) == CodeLeadershipClaimDenied
 }
+
+func IsCodeNotValid(err error) bool {
+	return ErrCode(err) == CodeNotValid
+}
+
+func IsCodeConflict(err error) bool {
+	return ErrCode(err) == CodeConflict
+}
+
+func IsCodeProvisioningFailed(err error) bool {
+	return ErrCode(err) == CodeProvisioningFailed
+}
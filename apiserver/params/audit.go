@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// AuditFilter restricts the audit entries returned by the Audit
+// facade's Entries method. Empty fields are not used to filter entries.
+type AuditFilter struct {
+	Caller string
+	Entity string
+	Facade string
+	Method string
+	Since  time.Time
+	Limit  int
+}
+
+// AuditEntry records a single state-changing API call.
+type AuditEntry struct {
+	Time     time.Time
+	Caller   string
+	Facade   string
+	Version  int
+	Id       string
+	Method   string
+	Entities []string
+	Args     string
+	Result   string
+}
+
+// AuditEntriesResult holds the audit entries matching an AuditFilter,
+// most recent first.
+type AuditEntriesResult struct {
+	Entries []AuditEntry
+}
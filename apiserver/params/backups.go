@@ -12,6 +12,14 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string
+
+	// Incremental requests a backup of only the changes since the
+	// most recent full backup.
+	Incremental bool
+
+	// Upload requests that the backup archive also be uploaded to
+	// the environment provider's object store.
+	Upload bool
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -66,6 +74,18 @@ type BackupsMetadataResult struct {
 	Machine     string
 	Hostname    string
 	Version     version.Number
+
+	// BaseID is the ID of the full backup on which an incremental
+	// backup is based.
+	BaseID string
+
+	// Scheduled records whether the backup was created automatically
+	// on the environment's backups schedule.
+	Scheduled bool
+
+	// Uploaded is the path in the provider's object store to which
+	// the backup archive was uploaded, if it was.
+	Uploaded string
 }
 
 // RestoreArgs Holds the backup file or id
@@ -73,3 +93,13 @@ type RestoreArgs struct {
 	// BackupId holds the id of the backup in server if any
 	BackupId string
 }
+
+// RestoreInfoResult holds the outcome of the most recent restore.
+type RestoreInfoResult struct {
+	// Status is the status of the restore.
+	Status string
+
+	// UnreachableMachines holds the ids of the machines whose agents
+	// could not be given the addresses of the restored state server.
+	UnreachableMachines []string
+}
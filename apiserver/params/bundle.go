@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// BundleChangesParams holds the parameters for making the
+// GetChanges and Deploy calls on the Bundle facade.
+type BundleChangesParams struct {
+	// BundleDataYAML is the YAML-encoded bundle data.
+	BundleDataYAML string `json:"yaml"`
+}
+
+// BundleChange describes one step of the deployment of a bundle.
+type BundleChange struct {
+	// Id uniquely identifies the step within the bundle deployment.
+	Id string `json:"id"`
+
+	// Method names the kind of change: one of "addCharm",
+	// "addMachine", "deploy", "addUnit", "addRelation" and
+	// "setAnnotations".
+	Method string `json:"method"`
+
+	// Description describes the change in human readable form.
+	Description string `json:"description"`
+
+	// Requires holds the ids of the steps that must be completed
+	// before this one.
+	Requires []string `json:"requires,omitempty"`
+}
+
+// BundleChangesResults holds the steps required to deploy a bundle,
+// or the reasons why the bundle cannot be deployed.
+type BundleChangesResults struct {
+	Changes []BundleChange `json:"changes,omitempty"`
+	Errors  []string       `json:"errors,omitempty"`
+}
+
+// BundleStepResult holds the outcome of one step of the deployment of
+// a bundle.
+type BundleStepResult struct {
+	BundleChange
+
+	// Done records whether the change has been made.
+	Done bool `json:"done"`
+
+	// Result holds the name of the entity added by the step, when it
+	// adds one: the charm URL, machine id, service name or unit name.
+	Result string `json:"result,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// BundleDeployResults holds the outcome of the deployment of a
+// bundle. If the bundle is not valid, Errors holds the reasons why and
+// no change is made to the environment.
+type BundleDeployResults struct {
+	Steps  []BundleStepResult `json:"steps,omitempty"`
+	Errors []string           `json:"errors,omitempty"`
+}
+
+// BundleExportResult holds the bundle describing the current state of
+// an environment.
+type BundleExportResult struct {
+	// BundleDataYAML is the YAML-encoded bundle data.
+	BundleDataYAML string `json:"yaml"`
+}
@@ -11,9 +11,14 @@ type CharmInfo struct {
 // CharmsList stores parameters for a charms.List call
 type CharmsList struct {
 	Names []string
+	ListPage
 }
 
 // CharmsListResult stores result from a charms.List call
 type CharmsListResult struct {
 	CharmURLs []string
+
+	// Continuation holds the token to request the next page of
+	// charm URLs with, if any are left.
+	Continuation string `json:"continuation,omitempty"`
 }
@@ -30,3 +30,14 @@ const (
 	ResolvedRetryHooks ResolvedMode = "retry-hooks"
 	ResolvedNoHooks    ResolvedMode = "no-hooks"
 )
+
+// UpgradeSeriesStatus describes the progress of a unit in the series
+// upgrade of its machine.
+type UpgradeSeriesStatus string
+
+const (
+	UpgradeSeriesPrepareStarted   UpgradeSeriesStatus = "prepare started"
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare completed"
+	UpgradeSeriesCompleteStarted  UpgradeSeriesStatus = "complete started"
+	UpgradeSeriesCompleted        UpgradeSeriesStatus = "completed"
+)
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// ServiceOffer describes endpoints of a service offered for use by
+// other environments hosted by the same controller.
+type ServiceOffer struct {
+	// EnvironTag holds the tag of the environment making the offer.
+	// It is ignored when making an offer.
+	EnvironTag string `json:"environtag,omitempty"`
+
+	// OfferName is the name of the offer, which consumers also give
+	// the service unless they choose another.
+	OfferName string `json:"offername"`
+
+	// ServiceName is the name of the offered service.
+	ServiceName string `json:"servicename"`
+
+	// Endpoints holds the names of the offered relations. When making
+	// an offer, leaving it empty offers every relation of the service
+	// that can be used across environments.
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	Description string `json:"description,omitempty"`
+}
+
+// ServiceOffers holds a number of offers.
+type ServiceOffers struct {
+	Offers []ServiceOffer `json:"offers"`
+}
+
+// OfferNames holds the names of a number of offers.
+type OfferNames struct {
+	Names []string `json:"names"`
+}
+
+// ConsumeOffer holds the parameters for consuming an offer made by
+// another environment.
+type ConsumeOffer struct {
+	// EnvironTag holds the tag of the environment making the offer.
+	EnvironTag string `json:"environtag"`
+
+	OfferName string `json:"offername"`
+
+	// ServiceName is the name given to the remote service in the
+	// consuming environment; if empty, the offer name is used.
+	ServiceName string `json:"servicename,omitempty"`
+}
+
+// ConsumeOffers holds the parameters for consuming a number of offers.
+type ConsumeOffers struct {
+	Offers []ConsumeOffer `json:"offers"`
+}
+
+// RemoteRelationUnit identifies a unit of a remote service in one of
+// its relations, along with its settings in that relation.
+type RemoteRelationUnit struct {
+	RelationTag string `json:"relationtag"`
+
+	// Unit holds the tag of the remote unit.
+	Unit string `json:"unit"`
+
+	// Settings are ignored when leaving scope.
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// RemoteRelationUnits holds a number of remote relation units.
+type RemoteRelationUnits struct {
+	Units []RemoteRelationUnit `json:"units"`
+}
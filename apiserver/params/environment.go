@@ -27,6 +27,34 @@ type EnvironmentUnset struct {
 	Keys []string
 }
 
+// EnvironmentConfigChanges contains the arguments for the
+// StageEnvironmentConfig and ApplyEnvironmentConfig client API calls.
+type EnvironmentConfigChanges struct {
+	Config map[string]interface{}
+	Unset  []string
+
+	// Revision holds the revision of the environment config returned
+	// by StageEnvironmentConfig. ApplyEnvironmentConfig only applies
+	// the changes if the config is still at that revision.
+	Revision int64
+}
+
+// EnvironmentConfigChange describes the change of a single environment
+// config attribute. OldValue is nil for added attributes, and NewValue
+// for removed ones.
+type EnvironmentConfigChange struct {
+	Key      string
+	OldValue interface{} `json:",omitempty"`
+	NewValue interface{} `json:",omitempty"`
+}
+
+// StagedEnvironmentConfig contains the result of a
+// StageEnvironmentConfig client API call.
+type StagedEnvironmentConfig struct {
+	Revision int64
+	Changes  []EnvironmentConfigChange
+}
+
 // ModifyEnvironUsers holds the parameters for making Client ShareEnvironment calls.
 type ModifyEnvironUsers struct {
 	Changes []ModifyEnvironUser
@@ -51,6 +79,42 @@ type ModifyEnvironUser struct {
 // SetEnvironAgentVersion client API call.
 type SetEnvironAgentVersion struct {
 	Version version.Number
+
+	// CanaryMachines, if not empty, holds the ids of the machines
+	// that upgrade along with the state servers. The other agents
+	// only upgrade once the upgrade is resumed.
+	CanaryMachines []string `json:",omitempty"`
+}
+
+// MachineUpgradeStatus holds the upgrade progress of a machine agent.
+type MachineUpgradeStatus struct {
+	Id string
+
+	// AgentVersion holds the version the agent is running, if known.
+	AgentVersion string
+
+	// InWave records whether the machine is told to upgrade in the
+	// current wave of the upgrade.
+	InWave bool
+
+	// Upgraded records whether the agent is running the target
+	// version.
+	Upgraded bool
+}
+
+// UpgradeStatusResult holds the result of the UpgradeStatus client
+// API call.
+type UpgradeStatusResult struct {
+	// PreviousVersion holds the version the environment is being
+	// upgraded from; it is only known for upgrades with canaries.
+	PreviousVersion *version.Number `json:",omitempty"`
+	TargetVersion   version.Number
+
+	// Wave holds the current wave of the upgrade: "canary" while an
+	// upgrade with canaries waits to be resumed, "all" otherwise.
+	Wave     string
+	Canaries []string `json:",omitempty"`
+	Machines []MachineUpgradeStatus
 }
 
 // EnvUserInfo holds information on a user.
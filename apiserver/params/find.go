@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// FindArgs holds the criteria used by the Find facade to select
+// entities. An entity is selected if it carries all the given
+// annotations.
+type FindArgs struct {
+	Annotations map[string]string
+}
+
+// FindResults holds the entities selected by the Find facade.
+type FindResults struct {
+	Entities []Entity
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// CloudImageMetadata describes a custom cloud image stored on the
+// controller.
+type CloudImageMetadata struct {
+	ImageId         string `json:"image-id"`
+	Stream          string `json:"stream,omitempty"`
+	Region          string `json:"region"`
+	Series          string `json:"series"`
+	Arch            string `json:"arch"`
+	VirtType        string `json:"virt-type,omitempty"`
+	RootStorageType string `json:"root-storage-type,omitempty"`
+}
+
+// MetadataSaveParams holds the arguments of the Save call on the
+// ImageMetadata facade.
+type MetadataSaveParams struct {
+	Metadata []CloudImageMetadata `json:"metadata"`
+}
+
+// ImageMetadataFilter holds the criteria used to select custom image
+// metadata in the List call on the ImageMetadata facade. Empty fields
+// match all metadata.
+type ImageMetadataFilter struct {
+	Region          string   `json:"region,omitempty"`
+	Series          []string `json:"series,omitempty"`
+	Arches          []string `json:"arches,omitempty"`
+	Stream          string   `json:"stream,omitempty"`
+	VirtType        string   `json:"virt-type,omitempty"`
+	RootStorageType string   `json:"root-storage-type,omitempty"`
+}
+
+// ListCloudImageMetadataResult holds the result of the List call on
+// the ImageMetadata facade.
+type ListCloudImageMetadataResult struct {
+	Result []CloudImageMetadata `json:"result"`
+}
+
+// MetadataImageIds holds the ids of the images whose metadata should
+// be deleted by the Delete call on the ImageMetadata facade.
+type MetadataImageIds struct {
+	Ids []string `json:"image-ids"`
+}
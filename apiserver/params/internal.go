@@ -189,6 +189,31 @@ type ConfigSettingsResults struct {
 	Results []ConfigSettingsResult
 }
 
+// EntityConfigSettings holds the configuration settings of an entity.
+type EntityConfigSettings struct {
+	Tag      string
+	Settings ConfigSettings
+}
+
+// EntitiesConfigSettings holds the configuration settings of multiple
+// entities.
+type EntitiesConfigSettings struct {
+	Entities []EntityConfigSettings
+}
+
+// EntityUpgradeSeriesStatus holds the progress of an entity in the
+// series upgrade of its machine.
+type EntityUpgradeSeriesStatus struct {
+	Tag    string
+	Status UpgradeSeriesStatus
+}
+
+// SetUpgradeSeriesStatus holds the progress of multiple entities in
+// the series upgrades of their machines.
+type SetUpgradeSeriesStatus struct {
+	Entities []EntityUpgradeSeriesStatus
+}
+
 // EnvironConfig holds an environment configuration.
 type EnvironConfig map[string]interface{}
 
@@ -214,6 +239,19 @@ type RelationIds struct {
 	RelationIds []int
 }
 
+// UnitEndpoint holds a unit tag and the name of one of its relation
+// endpoints.
+type UnitEndpoint struct {
+	Tag      string
+	Endpoint string
+}
+
+// UnitEndpoints holds the parameters for making API calls on
+// multiple unit endpoints.
+type UnitEndpoints struct {
+	Entities []UnitEndpoint
+}
+
 // RelationUnitPair holds a relation tag, a local and remote unit tags.
 type RelationUnitPair struct {
 	Relation   string
@@ -249,6 +287,10 @@ type RelationResult struct {
 	Id       int
 	Key      string
 	Endpoint multiwatcher.Endpoint
+
+	// DrainDeadline holds the time at which a draining relation will
+	// be broken; it is nil if the relation is not draining.
+	DrainDeadline *time.Time `json:",omitempty"`
 }
 
 // RelationResults holds the result of an API call that returns
@@ -462,6 +504,28 @@ type StringsWatchResults struct {
 	Results []StringsWatchResult
 }
 
+// MultiplexWatcherIds holds the ids of the NotifyWatchers and
+// StringsWatchers whose changes are to be returned by
+// WatcherMultiplexer.Next.
+type MultiplexWatcherIds struct {
+	NotifyWatcherIds  []string
+	StringsWatcherIds []string
+}
+
+// MultiplexedWatcherChange holds a change to a multiplexed watcher:
+// the changes of a StringsWatcher since it was last reported, or the
+// error a watcher stopped with.
+type MultiplexedWatcherChange struct {
+	WatcherId string
+	Changes   []string
+	Error     *Error
+}
+
+// MultiplexedWatcherChanges holds the result of WatcherMultiplexer.Next.
+type MultiplexedWatcherChanges struct {
+	Changes []MultiplexedWatcherChange
+}
+
 // RelationUnitsWatchResult holds a RelationUnitsWatcher id, changes
 // and an error (if any).
 type RelationUnitsWatchResult struct {
@@ -495,17 +559,30 @@ type CharmsResponse struct {
 	Error    string   `json:",omitempty"`
 	CharmURL string   `json:",omitempty"`
 	Files    []string `json:",omitempty"`
+
+	// Digests holds the digests of the files of a charm archive,
+	// keyed by file name, when requested.
+	Digests map[string]string `json:",omitempty"`
 }
 
 // RunParams is used to provide the parameters to the Run method.
 // Commands and Timeout are expected to have values, and one or more
-// values should be in the Machines, Services, or Units slices.
+// values should be in the Machines, Services, Units, Tags or Spaces
+// slices.
 type RunParams struct {
 	Commands string
 	Timeout  time.Duration
 	Machines []string
 	Services []string
 	Units    []string
+
+	// Tags selects the machines whose hardware has all of the given
+	// tags.
+	Tags []string `json:",omitempty"`
+
+	// Spaces selects the machines with an address on any of the given
+	// network spaces.
+	Spaces []string `json:",omitempty"`
 }
 
 // RunResult contains the result from an individual run call on a machine.
@@ -523,6 +600,20 @@ type RunResults struct {
 	Results []RunResult
 }
 
+// RunWatcherId holds the id of a RunWatcher, through which the results
+// of a Run are reported as each target completes.
+type RunWatcherId struct {
+	RunWatcherId string
+}
+
+// RunWatcherNextResult holds the results reported by a RunWatcher since
+// the last call to Next. Done is set once all the results have been
+// reported.
+type RunWatcherNextResult struct {
+	Results []RunResult
+	Done    bool
+}
+
 // AgentVersionResult is used to return the current version number of the
 // agent running the API server.
 type AgentVersionResult struct {
@@ -537,6 +628,10 @@ type ProvisioningInfo struct {
 	Networks    []string
 	Jobs        []multiwatcher.MachineJob
 	Volumes     []VolumeParams
+
+	// ImageMetadata holds the custom image metadata stored on the
+	// controller for the machine's series.
+	ImageMetadata []CloudImageMetadata
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -598,3 +693,54 @@ type MeterStatusResult struct {
 type MeterStatusResults struct {
 	Results []MeterStatusResult
 }
+
+// HookContextSnapshot holds the state of a unit needed to set up the
+// context a hook runs in, so that it can be fetched in one API call.
+type HookContextSnapshot struct {
+	// ConfigSettings holds the unit's charm config settings. It is
+	// nil when the unit's charm is not yet known.
+	ConfigSettings ConfigSettings
+
+	// RelationTags holds the tags of the relations whose scope the
+	// unit has entered.
+	RelationTags []string
+
+	// PublicAddress and PrivateAddress hold the unit's addresses,
+	// and are empty when not yet set.
+	PublicAddress  string
+	PrivateAddress string
+
+	// MachinePorts holds all the port ranges opened on the unit's
+	// assigned machine.
+	MachinePorts []MachinePortRange
+
+	MeterStatusCode string
+	MeterStatusInfo string
+
+	// Storage holds the unit's provisioned storage attachments.
+	Storage []StorageAttachment
+}
+
+// HookContextSnapshotResult holds a hook context snapshot or an error.
+type HookContextSnapshotResult struct {
+	Result HookContextSnapshot
+	Error  *Error
+}
+
+// HookContextSnapshotResults holds multiple hook context snapshots or
+// errors.
+type HookContextSnapshotResults struct {
+	Results []HookContextSnapshotResult
+}
+
+// MeterStatusParam holds the meter status to set for a unit.
+type MeterStatusParam struct {
+	Tag  string
+	Code string
+	Info string
+}
+
+// MeterStatusParams holds the meter statuses to set for multiple units.
+type MeterStatusParams struct {
+	Statuses []MeterStatusParam
+}
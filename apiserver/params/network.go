@@ -152,18 +152,20 @@ func (p Port) NetworkPort() network.Port {
 // requests/responses. See also network.PortRange, from/to which this is
 // transformed.
 type PortRange struct {
-	FromPort int    `json:"FromPort"`
-	ToPort   int    `json:"ToPort"`
-	Protocol string `json:"Protocol"`
+	FromPort   int    `json:"FromPort"`
+	ToPort     int    `json:"ToPort"`
+	Protocol   string `json:"Protocol"`
+	SourceCIDR string `json:"SourceCIDR,omitempty"`
 }
 
 // FromNetworkPortRange is a convenience helper to create a parameter
 // out of the network type, here for PortRange.
 func FromNetworkPortRange(pr network.PortRange) PortRange {
 	return PortRange{
-		FromPort: pr.FromPort,
-		ToPort:   pr.ToPort,
-		Protocol: pr.Protocol,
+		FromPort:   pr.FromPort,
+		ToPort:     pr.ToPort,
+		Protocol:   pr.Protocol,
+		SourceCIDR: pr.SourceCIDR,
 	}
 }
 
@@ -171,9 +173,10 @@ func FromNetworkPortRange(pr network.PortRange) PortRange {
 // as network type, here for PortRange.
 func (pr PortRange) NetworkPortRange() network.PortRange {
 	return network.PortRange{
-		FromPort: pr.FromPort,
-		ToPort:   pr.ToPort,
-		Protocol: pr.Protocol,
+		FromPort:   pr.FromPort,
+		ToPort:     pr.ToPort,
+		Protocol:   pr.Protocol,
+		SourceCIDR: pr.SourceCIDR,
 	}
 }
 
@@ -190,12 +193,15 @@ type EntitiesPorts struct {
 	Entities []EntityPort `json:"Entities"`
 }
 
-// EntityPortRange holds an entity's tag, a protocol and a port range.
+// EntityPortRange holds an entity's tag, a protocol and a port range,
+// and the CIDR of the sources the range is open to; an empty SourceCIDR
+// means the range is open to all sources.
 type EntityPortRange struct {
-	Tag      string `json:"Tag"`
-	Protocol string `json:"Protocol"`
-	FromPort int    `json:"FromPort"`
-	ToPort   int    `json:"ToPort"`
+	Tag        string `json:"Tag"`
+	Protocol   string `json:"Protocol"`
+	FromPort   int    `json:"FromPort"`
+	ToPort     int    `json:"ToPort"`
+	SourceCIDR string `json:"SourceCIDR,omitempty"`
 }
 
 // EntitiesPortRanges holds the parameters for making an OpenPorts or
@@ -0,0 +1,18 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// ListPage selects a page of the items returned by an API call that
+// lists them, so that long lists can be fetched a piece at a time
+// rather than in a single, very large, response. The zero value
+// selects all the items.
+type ListPage struct {
+	// Limit holds the greatest number of items to return. Zero
+	// means no limit.
+	Limit int `json:"limit,omitempty"`
+
+	// Continuation holds the continuation token returned with the
+	// previous page, when requesting the items that follow it.
+	Continuation string `json:"continuation,omitempty"`
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
@@ -100,6 +101,11 @@ type ErrorResult struct {
 // The endpoints specified are unordered.
 type AddRelation struct {
 	Endpoints []string
+
+	// SubordinateSelector, if not empty, holds the annotations a
+	// principal unit must carry for a container scoped relation to
+	// attach a subordinate to it.
+	SubordinateSelector map[string]string
 }
 
 // AddRelationResults holds the results of a AddRelation call. The Endpoints
@@ -109,9 +115,12 @@ type AddRelationResults struct {
 }
 
 // DestroyRelation holds the parameters for making the DestroyRelation call.
-// The endpoints specified are unordered.
+// The endpoints specified are unordered. Unless Force is set, the
+// relation drains for the environment's relation-drain-timeout before
+// it is broken.
 type DestroyRelation struct {
 	Endpoints []string
+	Force     bool
 }
 
 // AddMachineParams encapsulates the parameters used to create a new machine.
@@ -181,6 +190,19 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// UpgradeSeriesPrepare holds parameters for the UpgradeSeriesPrepare
+// call.
+type UpgradeSeriesPrepare struct {
+	MachineId string
+	Series    string
+}
+
+// UpgradeSeriesComplete holds parameters for the UpgradeSeriesComplete
+// call.
+type UpgradeSeriesComplete struct {
+	MachineId string
+}
+
 // ServiceDeploy holds the parameters for making the ServiceDeploy call.
 type ServiceDeploy struct {
 	ServiceName   string
@@ -192,6 +214,12 @@ type ServiceDeploy struct {
 	ToMachineSpec string
 	Networks      []string
 	Storage       map[string]storage.Constraints
+	SpaceBinding  string
+
+	// EndpointBindings maps the names of endpoints of the service to
+	// the names of the spaces they are bound to, overriding
+	// SpaceBinding for those endpoints.
+	EndpointBindings map[string]string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -205,6 +233,15 @@ type ServiceUpdate struct {
 	Constraints     *constraints.Value
 }
 
+// AddCharm holds the arguments for making an AddCharm API call.
+type AddCharm struct {
+	URL string
+
+	// Channel is the charm store channel from which the charm is
+	// downloaded. An empty channel means the stable channel.
+	Channel string
+}
+
 // ServiceSetCharm sets the charm for a given service.
 type ServiceSetCharm struct {
 	ServiceName string
@@ -219,9 +256,29 @@ type ServiceExpose struct {
 
 // ServiceSet holds the parameters for a ServiceSet
 // command. Options contains the configuration data.
+// If ConfigRevision is set, the configuration is only
+// changed if it is still at that revision, as returned
+// by ServiceGet.
 type ServiceSet struct {
+	ServiceName    string
+	Options        map[string]string
+	ConfigRevision *int64 `json:",omitempty"`
+}
+
+// ValidateServiceSetConfig holds the configuration values to check
+// against the charm of a service, either as option strings or, if
+// ConfigYAML is not empty, in YAML format.
+type ValidateServiceSetConfig struct {
 	ServiceName string
-	Options     map[string]string
+	Options     map[string]string `json:",omitempty"`
+	ConfigYAML  string            `json:",omitempty"`
+}
+
+// ServiceConfigValidationResult holds the result of a
+// ValidateServiceSetConfig call. Errors holds a message for each
+// invalid option, keyed by option name.
+type ServiceConfigValidationResult struct {
+	Errors map[string]string `json:",omitempty"`
 }
 
 // ServiceSetYAML holds the parameters for
@@ -248,10 +305,11 @@ type ServiceGet struct {
 
 // ServiceGetResults holds results of the ServiceGet call.
 type ServiceGetResults struct {
-	Service     string
-	Charm       string
-	Config      map[string]interface{}
-	Constraints constraints.Value
+	Service        string
+	Charm          string
+	Config         map[string]interface{}
+	ConfigRevision int64
+	Constraints    constraints.Value
 }
 
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
@@ -280,6 +338,31 @@ type ServiceMetricCredentials struct {
 	Creds []ServiceMetricCredential
 }
 
+// UnitObservedConfig holds the configuration settings last observed by
+// a unit in a config-changed hook.
+type UnitObservedConfig struct {
+	Unit string
+	// Observed is false if the unit has yet to run a config-changed
+	// hook, in which case Settings is empty.
+	Observed bool
+	Settings ConfigSettings
+}
+
+// ServiceConfigDiffResult holds the charm defaults, current settings
+// and settings observed by each unit of a service, or an error.
+type ServiceConfigDiffResult struct {
+	Error    *Error
+	Defaults ConfigSettings
+	Settings ConfigSettings
+	Units    []UnitObservedConfig
+}
+
+// ServiceConfigDiffResults holds multiple ServiceConfigDiffResult
+// results.
+type ServiceConfigDiffResults struct {
+	Results []ServiceConfigDiffResult
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string
@@ -349,6 +432,11 @@ type LoginRequest struct {
 	AuthTag     string `json:"auth-tag"`
 	Credentials string `json:"credentials"`
 	Nonce       string `json:"nonce"`
+
+	// Macaroons holds the macaroons, with their discharges, that
+	// authenticate a user to the Login v2 facade without a password.
+	// AuthTag must be empty when they are used.
+	Macaroons []macaroon.Slice `json:"macaroons,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -519,28 +607,30 @@ type StatusParams struct {
 	Patterns []string
 }
 
-// SetRsyslogCertParams holds parameters for the SetRsyslogCert call.
-type SetRsyslogCertParams struct {
-	CACert []byte
-	CAKey  []byte
-}
+// StatusHistory holds the parameters for the StatusHistory call.
+type StatusHistory struct {
+	// Tag identifies the machine or unit whose history is requested.
+	Tag string
 
-// RsyslogConfigResult holds the result of a GetRsyslogConfig call.
-type RsyslogConfigResult struct {
-	Error  *Error `json:"Error"`
-	CACert string `json:"CACert"`
-	CAKey  string `json:"CAKey"`
-	// Port is only used by state servers as the port to listen on.
-	// Clients should use HostPorts for the rsyslog addresses to forward
-	// logs to.
-	Port int `json:"Port"`
+	// Size holds the maximum number of entries to return.
+	Size int
+}
 
-	HostPorts []HostPort `json:"HostPorts"`
+// StatusHistoryEntry records a status that was set on an entity. Kind
+// holds "machine" for a machine's status, and "agent" or "workload"
+// for the statuses of a unit.
+type StatusHistoryEntry struct {
+	Kind   string
+	Status Status
+	Info   string
+	Data   map[string]interface{}
+	Since  time.Time
 }
 
-// RsyslogConfigResults is the bulk form of RyslogConfigResult
-type RsyslogConfigResults struct {
-	Results []RsyslogConfigResult
+// StatusHistoryResults holds the results of the StatusHistory call,
+// most recent first.
+type StatusHistoryResults struct {
+	Statuses []StatusHistoryEntry
 }
 
 // DistributionGroupResult contains the result of
@@ -556,6 +646,18 @@ type DistributionGroupResults struct {
 	Results []DistributionGroupResult
 }
 
+// AvailabilityZone describes an availability zone of the provider.
+type AvailabilityZone struct {
+	Name      string
+	Available bool
+}
+
+// SetAvailabilityZones holds the availability zones discovered from
+// the provider, for the SetAvailabilityZones provisioner API call.
+type SetAvailabilityZones struct {
+	Zones []AvailabilityZone
+}
+
 // FacadeVersions describes the available Facades and what versions of each one
 // are available
 type FacadeVersions struct {
@@ -615,6 +717,15 @@ type LoginResultV1 struct {
 	// ServerVersion is the string representation of the server version
 	// if the server supports it.
 	ServerVersion string `json:"server-version,omitempty"`
+
+	// DischargeRequired, if set, holds a macaroon that the client must
+	// discharge at the identity provider before logging in again with
+	// it; no other field is set.
+	DischargeRequired *macaroon.Macaroon `json:"discharge-required,omitempty"`
+
+	// DischargeRequiredReason holds the reason that the macaroons
+	// provided, if any, did not authenticate the user.
+	DischargeRequiredReason string `json:"discharge-required-error,omitempty"`
 }
 
 // StateServersSpec contains arguments for
@@ -628,6 +739,9 @@ type StateServersSpec struct {
 	Series string `json:"series,omitempty"`
 	// Placement defines specific machines to become new state server machines.
 	Placement []string `json:"placement,omitempty"`
+	// AutoReplace specifies whether state servers that become
+	// unavailable should be replaced automatically.
+	AutoReplace bool `json:"auto-replace,omitempty"`
 }
 
 // StateServersSpecs contains all the arguments
@@ -661,6 +775,99 @@ type StateServersChanges struct {
 	Demoted    []string `json:"demoted,omitempty"`
 }
 
+// ReplicaSetMember describes a member of the state servers' mongo
+// replica set.
+type ReplicaSetMember struct {
+	// Id is the member's id within the replica set.
+	Id int `json:"id"`
+
+	// Address is the member's mongo address.
+	Address string `json:"address"`
+
+	// MachineTag holds the tag of the state server machine hosting
+	// the member, if known.
+	MachineTag string `json:"machine-tag,omitempty"`
+
+	// State holds the member's replica set state, for example
+	// PRIMARY or SECONDARY.
+	State string `json:"state"`
+
+	// Healthy reports whether the member is reachable.
+	Healthy bool `json:"healthy"`
+
+	// Error holds any error reported for the member.
+	Error string `json:"error,omitempty"`
+
+	// Votes holds the number of votes the member has in elections.
+	Votes int `json:"votes"`
+
+	// Lag is how far the member's replication is behind the primary.
+	Lag time.Duration `json:"lag"`
+}
+
+// ReplicaSetStatusResult holds the result of the ReplicaSetStatus
+// API call.
+type ReplicaSetStatusResult struct {
+	Name    string             `json:"name"`
+	Members []ReplicaSetMember `json:"members"`
+}
+
+// RotateCertificates contains the arguments for the
+// RotateMongoCertificates API call.
+type RotateCertificates struct {
+	// RotateCA specifies whether a new CA should be created to
+	// issue the new certificates.
+	RotateCA bool `json:"rotate-ca,omitempty"`
+}
+
+// CertRotationResult holds the result of the
+// RotateMongoCertificates API call.
+type CertRotationResult struct {
+	// Serial identifies the rotation started.
+	Serial int64 `json:"serial"`
+
+	// Machines holds the tags of the state server machines,
+	// in the order in which they will restart.
+	Machines []string `json:"machines"`
+
+	// CACert holds the CA certificates that clients must trust
+	// once the rotation is complete.
+	CACert string `json:"ca-cert"`
+}
+
+// MigrateStateServer contains the arguments for the
+// MigrateStateServer API call.
+type MigrateStateServer struct {
+	// MachineTag identifies the state server machine to replace.
+	MachineTag string `json:"machine-tag"`
+
+	// Constraints are used to start the new state server machine.
+	Constraints constraints.Value `json:"constraints,omitempty"`
+
+	// Series is the series of the new state server machine. If
+	// this is empty, the series of the machine replaced is used.
+	Series string `json:"series,omitempty"`
+
+	// Placement, if non-empty, holds a placement directive used
+	// to start the new state server machine.
+	Placement string `json:"placement,omitempty"`
+}
+
+// MigrateStateServerResult holds the result of the
+// MigrateStateServer API call.
+type MigrateStateServerResult struct {
+	// MachineTag holds the tag of the new state server machine.
+	MachineTag string `json:"machine-tag"`
+}
+
+// RetireStateServerResult holds the result of the
+// RetireStateServer API call.
+type RetireStateServerResult struct {
+	// Retired reports whether the state server was retired; it is
+	// false if the machine still holds its replica set vote.
+	Retired bool `json:"retired"`
+}
+
 // FindToolsParams defines parameters for the FindTools method.
 type FindToolsParams struct {
 	// Number will be used to match tools versions exactly if non-zero.
@@ -712,6 +919,23 @@ type ImageMetadata struct {
 	Created time.Time `json:"created"`
 }
 
+// PruneToolsParams holds the parameters used to prune the tools
+// stored by the state server.
+type PruneToolsParams struct {
+	// Retention holds how long tools are kept after being added,
+	// whether or not they are used.
+	Retention time.Duration `json:"retention"`
+
+	// DryRun, if true, causes the tools that would be pruned to be
+	// reported without removing them.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// PruneToolsResult holds the versions of the tools pruned.
+type PruneToolsResult struct {
+	Versions []version.Binary `json:"versions"`
+}
+
 // RebootActionResults holds a list of RebootActionResult and any error.
 type RebootActionResults struct {
 	Results []RebootActionResult `json:"results,omitempty"`
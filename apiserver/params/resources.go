@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// ResourceMetadata describes a named binary resource attached to a
+// service's charm.
+type ResourceMetadata struct {
+	ServiceName string
+	Name        string
+	Revision    int
+	Size        int64
+	SHA256      string
+	Uploaded    time.Time
+}
+
+// ResourceDownloadArgs identifies the resource to download.
+type ResourceDownloadArgs struct {
+	ServiceName string
+	Name        string
+}
+
+// ResourcesResult holds the resources attached to a service, or an
+// error.
+type ResourcesResult struct {
+	Resources []ResourceMetadata
+	Error     *Error
+}
+
+// ResourcesResults holds the results of a ListResources call.
+type ResourcesResults struct {
+	Results []ResourcesResult
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+
+	"gopkg.in/macaroon.v1"
+)
+
+// CreateScopedCredentials holds the parameters for creating scoped
+// credentials.
+type CreateScopedCredentials struct {
+	Credentials []CreateScopedCredential `json:"credentials"`
+}
+
+// CreateScopedCredential holds the parameters for creating a
+// time-limited credential that gives access to some of the API facades
+// on behalf of the logged in user.
+type CreateScopedCredential struct {
+	// Facades holds the names of the facades that the credential
+	// gives access to.
+	Facades []string `json:"facades"`
+
+	// Entities holds the tags of the entities that the credential
+	// is limited to, if any.
+	Entities []string `json:"entities,omitempty"`
+
+	// Expiry holds how long the credential remains valid. If it is
+	// zero, a default is used.
+	Expiry time.Duration `json:"expiry,omitempty"`
+}
+
+// ScopedCredential holds a scoped credential. The macaroon is presented
+// to log in with it.
+type ScopedCredential struct {
+	Id       string             `json:"id"`
+	Macaroon *macaroon.Macaroon `json:"macaroon"`
+	Expires  time.Time          `json:"expires"`
+}
+
+// ScopedCredentialResult holds a scoped credential or an error.
+type ScopedCredentialResult struct {
+	Result *ScopedCredential `json:"result,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// ScopedCredentialResults holds the results of the bulk
+// CreateScopedCredentials API call.
+type ScopedCredentialResults struct {
+	Results []ScopedCredentialResult `json:"results"`
+}
+
+// ScopedCredentialIds holds the ids of scoped credentials.
+type ScopedCredentialIds struct {
+	Ids []string `json:"ids"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// CreateSpaceParams holds the name of a network space to create and
+// the CIDRs of the subnets to put in it.
+type CreateSpaceParams struct {
+	Name        string   `json:"name"`
+	SubnetCIDRs []string `json:"subnets"`
+}
+
+// CreateSpacesParams holds the arguments of the CreateSpaces call on
+// the Spaces facade.
+type CreateSpacesParams struct {
+	Spaces []CreateSpaceParams `json:"spaces"`
+}
+
+// SubnetInfo describes a subnet in a network space.
+type SubnetInfo struct {
+	CIDR             string `json:"cidr"`
+	ProviderId       string `json:"provider-id,omitempty"`
+	VLANTag          int    `json:"vlan-tag,omitempty"`
+	AvailabilityZone string `json:"zone,omitempty"`
+}
+
+// Space describes a network space and its subnets.
+type Space struct {
+	Name    string       `json:"name"`
+	Subnets []SubnetInfo `json:"subnets"`
+}
+
+// ListSpacesResults holds the result of the ListSpaces call on the
+// Spaces facade.
+type ListSpacesResults struct {
+	Results []Space `json:"results"`
+}
@@ -164,6 +164,19 @@ type Volumes struct {
 	Volumes []Volume `json:"volumes"`
 }
 
+// StorageProvisioningError records why a volume or filesystem, identified
+// by its tag, could not be provisioned.
+type StorageProvisioningError struct {
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// StorageProvisioningErrors holds the arguments for recording the errors
+// encountered while provisioning a set of volumes and filesystems.
+type StorageProvisioningErrors struct {
+	Errors []StorageProvisioningError `json:"errors"`
+}
+
 // VolumeAttachment describes a volume attachment.
 type VolumeAttachment struct {
 	VolumeTag  string `json:"volumetag"`
@@ -414,6 +427,10 @@ type StorageInfo struct {
 // StorageInfosResult holds storage details.
 type StorageInfosResult struct {
 	Results []StorageInfo `json:"results,omitempty"`
+
+	// Continuation holds the token to request the next page of
+	// results with, if any are left.
+	Continuation string `json:"continuation,omitempty"`
 }
 
 // StoragePool holds data for a pool instance.
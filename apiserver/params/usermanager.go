@@ -45,6 +45,10 @@ type AddUser struct {
 	Username    string `json:"username"`
 	DisplayName string `json:"display-name"`
 	Password    string `json:"password"`
+
+	// Access holds the user's access level, "read" or "write". If
+	// it is empty, the user has write access.
+	Access string `json:"access,omitempty"`
 }
 
 // AddUserResults holds the results of the bulk AddUser API call.
@@ -57,3 +61,25 @@ type AddUserResult struct {
 	Tag   string `json:"tag,omitempty"`
 	Error *Error `json:"error,omitempty"`
 }
+
+// ModifyEnvironAccessRequest holds the parameters for the UserManager
+// ShareEnvironment call.
+type ModifyEnvironAccessRequest struct {
+	Changes []ModifyEnvironAccess `json:"changes"`
+}
+
+// ModifyEnvironAccess holds the parameters for granting a user access
+// to an environment, or revoking it.
+type ModifyEnvironAccess struct {
+	UserTag string        `json:"user-tag"`
+	Action  EnvironAction `json:"action"`
+
+	// Access holds the access level granted to the user, "read" or
+	// "write". It is ignored when revoking access. If it is empty,
+	// the user is given write access.
+	Access string `json:"access,omitempty"`
+
+	// EnvironTag holds the tag of the environment. If it is empty,
+	// the environment the API connection is for is used.
+	EnvironTag string `json:"environ-tag,omitempty"`
+}
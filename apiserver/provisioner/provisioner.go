@@ -277,6 +277,23 @@ func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
 	return result, nil
 }
 
+// SetAvailabilityZones records the availability zones discovered from
+// the provider, replacing any recorded before. Only the environment
+// manager may record them.
+func (p *ProvisionerAPI) SetAvailabilityZones(args params.SetAvailabilityZones) error {
+	if !p.authorizer.AuthEnvironManager() {
+		return common.ErrPerm
+	}
+	zones := make([]state.AvailabilityZone, len(args.Zones))
+	for i, zone := range args.Zones {
+		zones[i] = state.AvailabilityZone{
+			Name:      zone.Name,
+			Available: zone.Available,
+		}
+	}
+	return p.st.SetAvailabilityZones(zones)
+}
+
 // MachinesWithTransientErrors returns status data for machines with provisioning
 // errors which are transient.
 func (p *ProvisionerAPI) MachinesWithTransientErrors() (params.StatusResults, error) {
@@ -391,16 +408,51 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 	for _, job := range m.Jobs() {
 		jobs = append(jobs, job.ToParams())
 	}
+	imageMetadata, err := p.machineImageMetadata(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &params.ProvisioningInfo{
-		Constraints: cons,
-		Series:      m.Series(),
-		Placement:   m.Placement(),
-		Networks:    networks,
-		Jobs:        jobs,
-		Volumes:     volumes,
+		Constraints:   cons,
+		Series:        m.Series(),
+		Placement:     m.Placement(),
+		Networks:      networks,
+		Jobs:          jobs,
+		Volumes:       volumes,
+		ImageMetadata: imageMetadata,
 	}, nil
 }
 
+// machineImageMetadata returns the custom image metadata stored for
+// the series of the given machine, in the image stream of the
+// environment.
+func (p *ProvisionerAPI) machineImageMetadata(m *state.Machine) ([]params.CloudImageMetadata, error) {
+	cfg, err := p.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stored, err := p.st.FindCloudImageMetadata(state.CloudImageMetadataFilter{
+		Series: []string{m.Series()},
+		Stream: cfg.ImageStream(),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var metadata []params.CloudImageMetadata
+	for _, image := range stored {
+		metadata = append(metadata, params.CloudImageMetadata{
+			ImageId:         image.ImageId,
+			Stream:          image.Stream,
+			Region:          image.Region,
+			Series:          image.Series,
+			Arch:            image.Arch,
+			VirtType:        image.VirtType,
+			RootStorageType: image.RootStorageType,
+		})
+	}
+	return metadata, nil
+}
+
 // DistributionGroup returns, for each given machine entity,
 // a slice of instance.Ids that belong to the same distribution
 // group as that machine. This information may be used to
@@ -380,6 +380,37 @@ func (s *withoutStateServerSuite) TestMachinesWithTransientErrors(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestSetAvailabilityZones(c *gc.C) {
+	err := s.provisioner.SetAvailabilityZones(params.SetAvailabilityZones{
+		Zones: []params.AvailabilityZone{
+			{Name: "zone-b", Available: true},
+			{Name: "zone-a", Available: false},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []state.AvailabilityZone{
+		{Name: "zone-a", Available: false},
+		{Name: "zone-b", Available: true},
+	})
+}
+
+func (s *withoutStateServerSuite) TestSetAvailabilityZonesPermission(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.EnvironManager = false
+	anAuthorizer.Tag = names.NewMachineTag("1")
+	aProvisioner, err := provisioner.NewProvisionerAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	err = aProvisioner.SetAvailabilityZones(params.SetAvailabilityZones{
+		Zones: []params.AvailabilityZone{{Name: "zone-a", Available: true}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
 func (s *withoutStateServerSuite) TestMachinesWithTransientErrorsPermission(c *gc.C) {
 	// Machines where there's permission issues are omitted.
 	anAuthorizer := s.authorizer
@@ -809,6 +840,41 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoImageMetadata(c *gc.C) {
+	image := func(imageId, series, stream string) state.CloudImageMetadata {
+		return state.CloudImageMetadata{
+			CloudImageMetadataAttributes: state.CloudImageMetadataAttributes{
+				Stream: stream,
+				Region: "dummy-region",
+				Series: series,
+				Arch:   "amd64",
+			},
+			ImageId: imageId,
+		}
+	}
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{
+		image("image-1", "quantal", "released"),
+		image("image-2", "precise", "released"),
+		image("image-3", "quantal", "daily"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.ImageMetadata, jc.DeepEquals, []params.CloudImageMetadata{{
+		ImageId: "image-1",
+		Stream:  "released",
+		Region:  "dummy-region",
+		Series:  "quantal",
+		Arch:    "amd64",
+	}})
+}
+
 func (s *withoutStateServerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	registry.RegisterProvider("dynamic", &dummy.StorageProvider{IsDynamic: true})
 	defer registry.RegisterProvider("dynamic", nil)
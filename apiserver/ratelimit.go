@@ -0,0 +1,309 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/ratelimit"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// defaultLoginAttemptsPerMinute holds the default number of times
+	// a single agent may attempt to log in each minute.
+	defaultLoginAttemptsPerMinute = 30
+
+	// defaultConnCallsPerSecond holds the default rate at which API
+	// calls may be made over a single connection.
+	defaultConnCallsPerSecond = 100
+
+	// defaultEntityCallsPerSecond holds the default rate at which API
+	// calls may be made by a single entity across all its connections.
+	defaultEntityCallsPerSecond = 200
+)
+
+// RateLimitConfig holds the limits the API server applies to protect
+// itself from runaway agents and misbehaving clients. A zero value for
+// any field selects that field's default; a negative value disables
+// the corresponding limit, except for MaxConcurrentLogins, which is
+// always enforced.
+type RateLimitConfig struct {
+	// MaxConcurrentLogins holds the number of agent logins that may
+	// be processed at once.
+	MaxConcurrentLogins int
+
+	// LoginAttemptsPerMinute holds the number of times a single
+	// agent may attempt to log in to an environment each minute.
+	LoginAttemptsPerMinute int
+
+	// ConnCallsPerSecond holds the rate at which API calls may be
+	// made over a single connection. Bursts of up to twice the rate
+	// are allowed.
+	ConnCallsPerSecond float64
+
+	// EntityCallsPerSecond holds the rate at which API calls may be
+	// made by a single entity across all of its connections. Bursts
+	// of up to twice the rate are allowed.
+	EntityCallsPerSecond float64
+}
+
+// withDefaults returns a copy of the config with any unset field set
+// to its default value.
+func (cfg RateLimitConfig) withDefaults() RateLimitConfig {
+	if cfg.MaxConcurrentLogins <= 0 {
+		cfg.MaxConcurrentLogins = loginRateLimit
+	}
+	if cfg.LoginAttemptsPerMinute == 0 {
+		cfg.LoginAttemptsPerMinute = defaultLoginAttemptsPerMinute
+	}
+	if cfg.ConnCallsPerSecond == 0 {
+		cfg.ConnCallsPerSecond = defaultConnCallsPerSecond
+	}
+	if cfg.EntityCallsPerSecond == 0 {
+		cfg.EntityCallsPerSecond = defaultEntityCallsPerSecond
+	}
+	return cfg
+}
+
+// RateLimitMetrics holds the number of requests rejected by the API
+// server's rate limits since it was started.
+type RateLimitMetrics struct {
+	// ConcurrentLoginsRejected holds the number of agent logins
+	// rejected because too many logins were already in progress.
+	ConcurrentLoginsRejected int64
+
+	// LoginAttemptsRejected holds the number of agent logins rejected
+	// because the agent had attempted to log in too often.
+	LoginAttemptsRejected int64
+
+	// CallsRejected holds the number of API calls rejected because
+	// they were made too quickly.
+	CallsRejected int64
+}
+
+// rateLimiter enforces a RateLimitConfig on behalf of a Server, and
+// records the number of requests it has rejected.
+type rateLimiter struct {
+	config        RateLimitConfig
+	loginAttempts *bucketSet
+	entityCalls   *bucketSet
+
+	// The following fields must be accessed atomically.
+	concurrentLoginsRejected int64
+	loginAttemptsRejected    int64
+	callsRejected            int64
+}
+
+// newRateLimiter returns a rateLimiter enforcing the supplied config,
+// which must already have had its defaults set.
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{config: config}
+	if n := config.LoginAttemptsPerMinute; n > 0 {
+		limiter.loginAttempts = newBucketSet(float64(n)/time.Minute.Seconds(), int64(n))
+	}
+	if rate := config.EntityCallsPerSecond; rate > 0 {
+		limiter.entityCalls = newBucketSet(rate, burstCapacity(rate))
+	}
+	return limiter
+}
+
+// allowLoginAttempt reports whether the entity identified by key may
+// attempt to log in now.
+func (l *rateLimiter) allowLoginAttempt(key string) bool {
+	if l.loginAttempts.take(key) {
+		return true
+	}
+	atomic.AddInt64(&l.loginAttemptsRejected, 1)
+	return false
+}
+
+// concurrentLoginRejected records that a login was rejected because
+// too many were already in progress.
+func (l *rateLimiter) concurrentLoginRejected() {
+	atomic.AddInt64(&l.concurrentLoginsRejected, 1)
+}
+
+// newConnBucket returns a bucket limiting the rate of calls over a
+// single connection, or nil if connections are not limited.
+func (l *rateLimiter) newConnBucket() *ratelimit.Bucket {
+	rate := l.config.ConnCallsPerSecond
+	if rate <= 0 {
+		return nil
+	}
+	return ratelimit.NewBucketWithRate(rate, burstCapacity(rate))
+}
+
+// allowCall reports whether the entity identified by key may make an
+// API call over the connection limited by connBucket.
+func (l *rateLimiter) allowCall(connBucket *ratelimit.Bucket, key string) bool {
+	if (connBucket == nil || connBucket.TakeAvailable(1) == 1) && l.entityCalls.take(key) {
+		return true
+	}
+	atomic.AddInt64(&l.callsRejected, 1)
+	return false
+}
+
+// metrics returns the number of requests rejected so far.
+func (l *rateLimiter) metrics() RateLimitMetrics {
+	return RateLimitMetrics{
+		ConcurrentLoginsRejected: atomic.LoadInt64(&l.concurrentLoginsRejected),
+		LoginAttemptsRejected:    atomic.LoadInt64(&l.loginAttemptsRejected),
+		CallsRejected:            atomic.LoadInt64(&l.callsRejected),
+	}
+}
+
+// burstCapacity returns the bucket capacity used for a call rate.
+func burstCapacity(rate float64) int64 {
+	if capacity := int64(2 * rate); capacity > 1 {
+		return capacity
+	}
+	return 1
+}
+
+// maxBucketSetKeys holds the number of keys for which a bucketSet
+// keeps buckets. Dropping a bucket only forgives the requests recently
+// made for its key, so the limit can be generous.
+const maxBucketSetKeys = 100000
+
+// bucketSet holds a token bucket for each of a set of keys. All the
+// buckets fill at the same rate and have the same capacity. The keys
+// may come from clients, so buckets are not kept forever: a bucket
+// that has been idle for long enough to fill up again is no different
+// from a new one and is dropped, and when there are too many buckets
+// the least recently used is dropped.
+type bucketSet struct {
+	rate     float64
+	capacity int64
+	maxKeys  int
+	idleTime time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// lru holds the *bucketEntry values, most recently used first.
+	lru *list.List
+}
+
+type bucketEntry struct {
+	key      string
+	bucket   *ratelimit.Bucket
+	lastUsed time.Time
+}
+
+func newBucketSet(rate float64, capacity int64) *bucketSet {
+	return &bucketSet{
+		rate:     rate,
+		capacity: capacity,
+		maxKeys:  maxBucketSetKeys,
+		idleTime: time.Duration(float64(capacity) / rate * float64(time.Second)),
+		now:      time.Now,
+		buckets:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// take reports whether a token could be taken from the bucket for the
+// given key. A nil bucketSet imposes no limit.
+func (s *bucketSet) take(key string) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.Lock()
+	now := s.now()
+	s.dropIdle(now)
+	var entry *bucketEntry
+	if elem, ok := s.buckets[key]; ok {
+		entry = elem.Value.(*bucketEntry)
+		s.lru.MoveToFront(elem)
+	} else {
+		entry = &bucketEntry{
+			key:    key,
+			bucket: ratelimit.NewBucketWithRate(s.rate, s.capacity),
+		}
+		s.buckets[key] = s.lru.PushFront(entry)
+		if s.lru.Len() > s.maxKeys {
+			s.drop(s.lru.Back())
+		}
+	}
+	entry.lastUsed = now
+	s.mu.Unlock()
+	return entry.bucket.TakeAvailable(1) == 1
+}
+
+// dropIdle drops the buckets that have not been used since long enough
+// before now to have filled up again. It must be called with mu held.
+func (s *bucketSet) dropIdle(now time.Time) {
+	for elem := s.lru.Back(); elem != nil; elem = s.lru.Back() {
+		if now.Sub(elem.Value.(*bucketEntry).lastUsed) < s.idleTime {
+			return
+		}
+		s.drop(elem)
+	}
+}
+
+func (s *bucketSet) drop(elem *list.Element) {
+	s.lru.Remove(elem)
+	delete(s.buckets, elem.Value.(*bucketEntry).key)
+}
+
+// throttledRoot rejects API calls made faster than the server's rate
+// limits allow, over a single connection or by a single entity.
+type throttledRoot struct {
+	rpc.MethodFinder
+	limiter    *rateLimiter
+	connBucket *ratelimit.Bucket
+	key        string
+}
+
+// newThrottledRoot returns a new throttledRoot limiting the calls made
+// by the entity identified by key.
+func newThrottledRoot(finder rpc.MethodFinder, limiter *rateLimiter, key string) *throttledRoot {
+	return &throttledRoot{
+		MethodFinder: finder,
+		limiter:      limiter,
+		connBucket:   limiter.newConnBucket(),
+		key:          key,
+	}
+}
+
+// FindMethod returns common.ErrTryAgain if the call would exceed the
+// rate limits. Pings are never limited, so that a throttled client
+// does not also lose its connection.
+func (r *throttledRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if rootName == "Pinger" {
+		return caller, nil
+	}
+	if !r.limiter.allowCall(r.connBucket, r.key) {
+		logger.Debugf("rate limiting %s calls, try again later", r.key)
+		return nil, common.ErrTryAgain
+	}
+	return caller, nil
+}
+
+// isEnvironManager reports whether the entity is a state server machine.
+// The state servers' own agents are not subject to call rate limits.
+func isEnvironManager(entity state.Entity) bool {
+	machine, ok := entity.(*state.Machine)
+	if !ok {
+		return false
+	}
+	for _, job := range machine.Jobs() {
+		if job == state.JobManageEnviron {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type rateLimitInternalSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&rateLimitInternalSuite{})
+
+func (s *rateLimitInternalSuite) TestWithDefaults(c *gc.C) {
+	cfg := RateLimitConfig{}.withDefaults()
+	c.Assert(cfg, jc.DeepEquals, RateLimitConfig{
+		MaxConcurrentLogins:    loginRateLimit,
+		LoginAttemptsPerMinute: defaultLoginAttemptsPerMinute,
+		ConnCallsPerSecond:     defaultConnCallsPerSecond,
+		EntityCallsPerSecond:   defaultEntityCallsPerSecond,
+	})
+
+	cfg = RateLimitConfig{
+		MaxConcurrentLogins:    3,
+		LoginAttemptsPerMinute: -1,
+		ConnCallsPerSecond:     5,
+		EntityCallsPerSecond:   -1,
+	}
+	c.Assert(cfg.withDefaults(), jc.DeepEquals, cfg)
+}
+
+func (s *rateLimitInternalSuite) TestLoginAttempts(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{LoginAttemptsPerMinute: 2}.withDefaults())
+	c.Assert(limiter.allowLoginAttempt("machine-0"), jc.IsTrue)
+	c.Assert(limiter.allowLoginAttempt("machine-0"), jc.IsTrue)
+	c.Assert(limiter.allowLoginAttempt("machine-0"), jc.IsFalse)
+	c.Assert(limiter.allowLoginAttempt("machine-1"), jc.IsTrue)
+	limiter.concurrentLoginRejected()
+	c.Assert(limiter.metrics(), jc.DeepEquals, RateLimitMetrics{
+		ConcurrentLoginsRejected: 1,
+		LoginAttemptsRejected:    1,
+	})
+}
+
+func (s *rateLimitInternalSuite) TestLoginAttemptsUnlimited(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{LoginAttemptsPerMinute: -1}.withDefaults())
+	for i := 0; i < 100; i++ {
+		c.Assert(limiter.allowLoginAttempt("machine-0"), jc.IsTrue)
+	}
+}
+
+func (s *rateLimitInternalSuite) TestCallsPerEntity(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		ConnCallsPerSecond:   -1,
+		EntityCallsPerSecond: 0.001,
+	}.withDefaults())
+	c.Assert(limiter.newConnBucket(), gc.IsNil)
+	c.Assert(limiter.allowCall(nil, "unit-mysql-0"), jc.IsTrue)
+	c.Assert(limiter.allowCall(nil, "unit-mysql-0"), jc.IsFalse)
+	c.Assert(limiter.allowCall(nil, "unit-mysql-1"), jc.IsTrue)
+	c.Assert(limiter.metrics().CallsRejected, gc.Equals, int64(1))
+}
+
+func (s *rateLimitInternalSuite) TestBucketSetDropsIdleBuckets(c *gc.C) {
+	now := time.Now()
+	set := newBucketSet(1, 2)
+	set.now = func() time.Time { return now }
+	c.Assert(set.take("machine-0"), jc.IsTrue)
+	c.Assert(set.take("machine-1"), jc.IsTrue)
+	c.Assert(set.lru.Len(), gc.Equals, 2)
+
+	// Buckets are kept until they have had time to fill up again.
+	now = now.Add(time.Second)
+	c.Assert(set.take("machine-1"), jc.IsTrue)
+	c.Assert(set.lru.Len(), gc.Equals, 2)
+	now = now.Add(time.Second)
+	c.Assert(set.take("machine-2"), jc.IsTrue)
+	c.Assert(set.lru.Len(), gc.Equals, 2)
+	_, ok := set.buckets["machine-0"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *rateLimitInternalSuite) TestBucketSetDropsLeastRecentlyUsed(c *gc.C) {
+	set := newBucketSet(0.001, 1)
+	set.maxKeys = 2
+	c.Assert(set.take("machine-0"), jc.IsTrue)
+	c.Assert(set.take("machine-1"), jc.IsTrue)
+	c.Assert(set.take("machine-0"), jc.IsFalse)
+	c.Assert(set.take("machine-2"), jc.IsTrue)
+	c.Assert(set.lru.Len(), gc.Equals, 2)
+
+	// The bucket for machine-1 was dropped, so it starts afresh;
+	// machine-0's was used more recently, and is kept.
+	c.Assert(set.take("machine-1"), jc.IsTrue)
+	_, ok := set.buckets["machine-0"]
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(set.take("machine-2"), jc.IsFalse)
+}
+
+func (s *rateLimitInternalSuite) TestRequestNotifierRemoteHost(c *gc.C) {
+	n := newRequestNotifier(nil)
+	c.Assert(n.remoteHost(), gc.Equals, "")
+	n.join(&http.Request{RemoteAddr: "10.0.0.1:54321"})
+	c.Assert(n.remoteHost(), gc.Equals, "10.0.0.1")
+}
+
+func (s *rateLimitInternalSuite) TestThrottledRoot(c *gc.C) {
+	limiter := newRateLimiter(RateLimitConfig{
+		ConnCallsPerSecond:   0.001,
+		EntityCallsPerSecond: -1,
+	}.withDefaults())
+	root := newThrottledRoot(newApiRoot(nil, false, nil, nil), limiter, "machine-1")
+
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+	caller, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, "try again")
+	c.Assert(caller, gc.IsNil)
+
+	// Pings are not limited and unknown methods are reported as such.
+	_, err = root.FindMethod("Pinger", 0, "Ping")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = root.FindMethod("Foo", 0, "Bar")
+	c.Assert(err, gc.ErrorMatches, `unknown object type "Foo"`)
+
+	// Each connection has its own limit.
+	root = newThrottledRoot(newApiRoot(nil, false, nil, nil), limiter, "machine-1")
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+}
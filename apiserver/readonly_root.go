@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// readOnlyRoot restricts the API calls made by a read-only user to
+// those listed as not changing state, such as getters and watchers;
+// any other call is refused.
+type readOnlyRoot struct {
+	rpc.MethodFinder
+}
+
+// newReadOnlyRoot returns a new readOnlyRoot.
+func newReadOnlyRoot(finder rpc.MethodFinder) *readOnlyRoot {
+	return &readOnlyRoot{finder}
+}
+
+// FindMethod returns common.ErrPerm unless the call is known not to
+// change state.
+func (r *readOnlyRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// The lookup of the name is done first to return a not found error if the
+	// user is looking for a method that we just don't have.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !isReadOnlyCall(rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
+
+// isReadOnlyUser reports whether the entity is a user with read-only
+// access, either everywhere or to the environment of st.
+func isReadOnlyUser(st *state.State, entity state.Entity) bool {
+	var userTag names.UserTag
+	switch user := entity.(type) {
+	case *state.User:
+		if user.IsReadOnly() {
+			return true
+		}
+		userTag = user.UserTag()
+	case *externalUser:
+		userTag = user.tag
+	default:
+		return false
+	}
+	envUser, err := st.EnvironmentUser(userTag)
+	if err != nil {
+		// Users without access to the environment cannot log in to
+		// it, so this should never happen; err on the side of
+		// caution.
+		logger.Warningf("cannot check %s access to environment: %v", userTag, err)
+		return true
+	}
+	return envUser.IsReadOnly()
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type readOnlyRootSuite struct {
+	testing.BaseSuite
+
+	root rpc.MethodFinder
+}
+
+var _ = gc.Suite(&readOnlyRootSuite{})
+
+func (r *readOnlyRootSuite) SetUpTest(c *gc.C) {
+	r.BaseSuite.SetUpTest(c)
+	r.root = apiserver.TestingReadOnlyRoot(nil)
+}
+
+func (r *readOnlyRootSuite) TestFindAllowedMethod(c *gc.C) {
+	for _, method := range []string{"FullStatus", "WatchAll", "EnvironmentGet", "CharmInfo"} {
+		caller, err := r.root.FindMethod("Client", 0, method)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+	caller, err := r.root.FindMethod("AllWatcher", 0, "Next")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (r *readOnlyRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	for _, method := range []string{"ServiceDeploy", "DestroyMachines", "EnvironmentSet", "AddServiceUnits"} {
+		caller, err := r.root.FindMethod("Client", 0, method)
+		c.Check(err, gc.Equals, common.ErrPerm)
+		c.Check(caller, gc.IsNil)
+	}
+}
+
+func (r *readOnlyRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	caller, err := r.root.FindMethod("Client", 0, "NoSuchMethod")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).NoSuchMethod is not implemented`)
+	c.Assert(caller, gc.IsNil)
+}
@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+)
+
+// readOnlyFacades holds the facades none of whose calls change state.
+var readOnlyFacades = set.NewStrings(
+	"ActionOutputWatcher",
+	"Admin",
+	"AllWatcher",
+	"Audit",
+	"FilesystemAttachmentsWatcher",
+	"Find",
+	"NotifyWatcher",
+	"Pinger",
+	"RelationUnitsWatcher",
+	"RunWatcher",
+	"StringsWatcher",
+	"VolumeAttachmentsWatcher",
+	"WatcherMultiplexer",
+)
+
+// readOnlyCalls holds the methods of the other facades that do not
+// change state, keyed by facade name. Facades do not declare which of
+// their methods change state, so any call not listed here is taken to
+// change state: it is recorded in the audit log, and refused to
+// read-only users. Methods that do not change state must be added here
+// as they are added to a facade.
+var readOnlyCalls = map[string]set.Strings{
+	"Action": set.NewStrings(
+		"Actions", "FindActionTagsByPrefix", "ListAll", "ListCompleted",
+		"ListPending", "ListRunning", "ListSchedules",
+		"ServicesCharmActions", "WatchActionOutput",
+	),
+	"Agent": set.NewStrings(
+		"GetEntities", "IsMaster", "StateServingInfo",
+	),
+	"Annotations": set.NewStrings(
+		"Get",
+	),
+	"Backups": set.NewStrings(
+		"Info", "List", "RestoreInfo",
+	),
+	"Block": set.NewStrings(
+		"List",
+	),
+	"Bundle": set.NewStrings(
+		"ExportBundle", "GetChanges",
+	),
+	"Charms": set.NewStrings(
+		"CharmInfo", "List",
+	),
+	"Client": set.NewStrings(
+		"APIHostPorts", "AgentVersion", "CharmInfo", "EnvUserInfo",
+		"EnvironmentGet", "EnvironmentInfo", "FindTools", "FullStatus",
+		"GetAnnotations", "GetEnvironmentConstraints",
+		"GetServiceConstraints", "PrivateAddress", "PublicAddress",
+		"ResolveCharms", "ServiceCharmRelations", "ServiceGet",
+		"ServiceGetCharmURL", "Status", "StatusHistory", "UpgradeStatus",
+		"ValidateServiceSetConfig", "WatchAll",
+	),
+	"CrossModel": set.NewStrings(
+		"ListOffers",
+	),
+	"Deployer": set.NewStrings(
+		"APIAddresses", "APIHostPorts", "CACert", "ConnectionInfo",
+		"EnvironUUID", "Life", "StateAddresses", "WatchAPIHostPorts",
+		"WatchUnits",
+	),
+	"DiskFormatter": set.NewStrings(
+		"AttachedVolumes", "VolumePreparationInfo", "WatchAttachedVolumes",
+	),
+	"Environment": set.NewStrings(
+		"EnvironConfig", "WatchForEnvironConfigChanges",
+	),
+	"EnvironmentManager": set.NewStrings(
+		"ConfigSkeleton", "ListEnvironments",
+	),
+	"Firewaller": set.NewStrings(
+		"EnvironConfig", "GetAssignedMachine", "GetExposed",
+		"GetMachineActiveNetworks", "GetMachinePorts", "InstanceId",
+		"Life", "Watch", "WatchEnvironMachines",
+		"WatchForEnvironConfigChanges", "WatchOpenedPorts", "WatchUnits",
+	),
+	"HighAvailability": set.NewStrings(
+		"ReplicaSetStatus",
+	),
+	"ImageManager": set.NewStrings(
+		"ListImages",
+	),
+	"ImageMetadata": set.NewStrings(
+		"List",
+	),
+	"InstanceConsole": set.NewStrings(
+		"ConsoleOutput",
+	),
+	"KeyManager": set.NewStrings(
+		"ListKeys",
+	),
+	"KeyUpdater": set.NewStrings(
+		"AuthorisedKeys", "WatchAuthorisedKeys",
+	),
+	"LeadershipService": set.NewStrings(
+		"BlockUntilLeadershipReleased",
+	),
+	"Logger": set.NewStrings(
+		"LoggingConfig", "WatchLoggingConfig",
+	),
+	"Machiner": set.NewStrings(
+		"APIAddresses", "APIHostPorts", "CACert", "EnvironUUID", "Life",
+		"Watch", "WatchAPIHostPorts",
+	),
+	"Networker": set.NewStrings(
+		"MachineNetworkConfig", "MachineNetworkInfo", "WatchInterfaces",
+	),
+	"Provisioner": set.NewStrings(
+		"APIAddresses", "APIHostPorts", "CACert", "Constraints",
+		"ContainerConfig", "ContainerManagerConfig", "DistributionGroup",
+		"EnvironConfig", "EnvironUUID", "FindTools", "InstanceId", "Life",
+		"MachinesWithTransientErrors", "ProvisioningInfo",
+		"RequestedNetworks", "Series", "StateAddresses", "Status",
+		"WatchAPIHostPorts", "WatchAllContainers", "WatchContainers",
+		"WatchEnvironMachines", "WatchForEnvironConfigChanges",
+		"WatchMachineErrorRetry",
+	),
+	"Reboot": set.NewStrings(
+		"GetRebootAction", "WatchForRebootEvent",
+	),
+	"Resources": set.NewStrings(
+		"ListResources",
+	),
+	"Service": set.NewStrings(
+		"ConfigDiff",
+	),
+	"Spaces": set.NewStrings(
+		"ListSpaces",
+	),
+	"Storage": set.NewStrings(
+		"List", "ListPools", "Show",
+	),
+	"StorageProvisioner": set.NewStrings(
+		"AttachmentLife", "EnvironConfig", "FilesystemAttachmentParams",
+		"FilesystemAttachments", "FilesystemParams", "Filesystems", "Life",
+		"VolumeAttachmentParams", "VolumeAttachments", "VolumeParams",
+		"Volumes", "WatchFilesystemAttachments", "WatchFilesystems",
+		"WatchForEnvironConfigChanges", "WatchVolumeAttachments",
+		"WatchVolumes",
+	),
+	"Uniter": set.NewStrings(
+		"APIAddresses", "APIHostPorts", "Actions", "AllMachinePorts",
+		"AssignedMachine", "AvailabilityZone", "CACert",
+		"CharmArchiveSha256", "CharmArchiveURLs", "CharmURL",
+		"ConfigSettings", "CurrentEnvironUUID", "CurrentEnvironment",
+		"EndpointAddresses", "EnvironConfig", "EnvironUUID",
+		"GetMeterStatus", "GetOwnerTag", "GetPrincipal", "HasSubordinates",
+		"HookContextSnapshot", "JoinedRelations", "Life", "PrivateAddress",
+		"ProviderType", "PublicAddress", "Read", "ReadRemoteSettings",
+		"ReadSettings", "Relation", "RelationById", "Resolved",
+		"ServiceOwner", "StorageAttachments", "UnitStatus",
+		"UnitStorageAttachments", "UpgradeSeriesStatus", "Watch",
+		"WatchAPIHostPorts", "WatchActionNotifications",
+		"WatchConfigSettings", "WatchForEnvironConfigChanges",
+		"WatchLeadershipSettings", "WatchMeterStatus", "WatchRelationUnits",
+		"WatchServiceRelations", "WatchStorageAttachmentInfos",
+		"WatchUnitAddresses", "WatchUnitStorageAttachments",
+		"WatchUpgradeSeriesNotifications",
+	),
+	"Upgrader": set.NewStrings(
+		"DesiredVersion", "Tools", "WatchAPIVersion",
+	),
+	"UserManager": set.NewStrings(
+		"UserInfo",
+	),
+}
+
+// isReadOnlyCall reports whether the given API call is known not to
+// change state.
+func isReadOnlyCall(rootName, methodName string) bool {
+	if readOnlyFacades.Contains(rootName) {
+		return true
+	}
+	return readOnlyCalls[rootName].Contains(methodName)
+}
+
+// isMutatingCall reports whether the given API call could change state.
+func isMutatingCall(rootName, methodName string) bool {
+	return !isReadOnlyCall(rootName, methodName)
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type readOnlyCallsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&readOnlyCallsSuite{})
+
+func (s *readOnlyCallsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.SetFeatureFlags(feature.JES, feature.Storage)
+}
+
+// registeredMethods returns the methods of all the versions of every
+// registered facade, keyed by facade name.
+func registeredMethods(c *gc.C) map[string]set.Strings {
+	methods := make(map[string]set.Strings)
+	for _, facade := range common.Facades.List() {
+		names := set.NewStrings()
+		for _, version := range facade.Versions {
+			goType, err := common.Facades.GetType(facade.Name, version)
+			c.Assert(err, jc.ErrorIsNil)
+			names = names.Union(set.NewStrings(rpcreflect.ObjTypeOf(goType).MethodNames()...))
+		}
+		methods[facade.Name] = names
+	}
+	return methods
+}
+
+func (s *readOnlyCallsSuite) TestReadOnlyCallsRegistered(c *gc.C) {
+	methods := registeredMethods(c)
+	for facade, names := range readOnlyCalls {
+		registered, ok := methods[facade]
+		if !c.Check(ok, jc.IsTrue, gc.Commentf("facade %s", facade)) {
+			continue
+		}
+		for _, name := range names.SortedValues() {
+			c.Check(registered.Contains(name), jc.IsTrue, gc.Commentf("method %s.%s", facade, name))
+		}
+	}
+}
+
+func (s *readOnlyCallsSuite) TestReadOnlyRootRefusesUnlistedCalls(c *gc.C) {
+	root := newReadOnlyRoot(TestingApiRoot(nil))
+	refused := 0
+	for _, facade := range common.Facades.List() {
+		for _, version := range facade.Versions {
+			goType, err := common.Facades.GetType(facade.Name, version)
+			c.Assert(err, jc.ErrorIsNil)
+			for _, name := range rpcreflect.ObjTypeOf(goType).MethodNames() {
+				comment := gc.Commentf("method %s(%d).%s", facade.Name, version, name)
+				caller, err := root.FindMethod(facade.Name, version, name)
+				if isReadOnlyCall(facade.Name, name) {
+					c.Check(err, jc.ErrorIsNil, comment)
+					c.Check(caller, gc.NotNil, comment)
+					continue
+				}
+				c.Check(err, gc.Equals, common.ErrPerm, comment)
+				c.Check(caller, gc.IsNil, comment)
+				refused++
+			}
+		}
+	}
+	c.Assert(refused, jc.GreaterThan, 0)
+}
+
+func (s *readOnlyCallsSuite) TestReadOnlyRootRefusesMutatingCalls(c *gc.C) {
+	root := newReadOnlyRoot(TestingApiRoot(nil))
+	for i, test := range []struct {
+		rootName   string
+		version    int
+		methodName string
+	}{
+		{"Client", 0, "InjectMachines"},
+		{"Client", 0, "ApplyEnvironmentConfig"},
+		{"Client", 0, "StageEnvironmentConfig"},
+		{"HighAvailability", 1, "DrainAPIServer"},
+		{"HighAvailability", 1, "MigrateStateServer"},
+		{"HighAvailability", 1, "RetireStateServer"},
+		{"HighAvailability", 1, "RotateMongoCertificates"},
+		{"ImageMetadata", 1, "Save"},
+		{"ToolsManager", 1, "PruneTools"},
+		{"CrossModel", 1, "Offer"},
+		{"CrossModel", 1, "Consume"},
+		{"MetricsManager", 0, "SendMetrics"},
+		{"MetricsManager", 0, "CleanupOldMetrics"},
+		{"Uniter", 2, "AppendActionsOutput"},
+	} {
+		c.Logf("test %d: %s.%s", i, test.rootName, test.methodName)
+		caller, err := root.FindMethod(test.rootName, test.version, test.methodName)
+		c.Check(err, gc.Equals, common.ErrPerm)
+		c.Check(caller, gc.IsNil)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/resourcestorage"
+)
+
+// resourcesHandler handles charm resource upload and download
+// requests.
+type resourcesHandler struct {
+	httpHandler
+}
+
+func (h *resourcesHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	// Validate before authenticate because the authentication is dependent
+	// on the state connection that is determined during the validation.
+	stateWrapper, err := h.validateEnvironUUID(req)
+	if err != nil {
+		h.sendError(resp, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	switch req.Method {
+	case "GET":
+		args, err := h.parseGETArgs(req)
+		if err != nil {
+			h.sendError(resp, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Units may download the resources of their own service.
+		entity, err := stateWrapper.authenticate(req)
+		if err != nil || !canDownloadResource(entity.Tag(), args.ServiceName) {
+			h.authError(resp, h)
+			return
+		}
+		if err := h.download(stateWrapper.state, args, resp); err != nil {
+			status := http.StatusInternalServerError
+			if errors.IsNotFound(err) {
+				status = http.StatusNotFound
+			}
+			h.sendError(resp, status, err.Error())
+			return
+		}
+	case "PUT":
+		if err := stateWrapper.authenticateUser(req); err != nil {
+			h.userAuthError(resp, h, err)
+			return
+		}
+		result, err := h.upload(stateWrapper.state, req)
+		if err != nil {
+			h.sendError(resp, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(resp, http.StatusOK, result)
+	default:
+		h.sendError(resp, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", req.Method))
+	}
+}
+
+// canDownloadResource reports whether the authenticated entity may
+// download the resources of the named service.
+func canDownloadResource(tag names.Tag, serviceName string) bool {
+	switch tag := tag.(type) {
+	case names.UserTag:
+		return true
+	case names.UnitTag:
+		unitService, err := names.UnitService(tag.Id())
+		return err == nil && unitService == serviceName
+	}
+	return false
+}
+
+func (h *resourcesHandler) download(st *state.State, args *params.ResourceDownloadArgs, resp http.ResponseWriter) error {
+	storage, err := st.ResourceStorage()
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	metadata, rc, err := storage.Resource(args.ServiceName, args.Name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// We don't set the Content-Length header, leaving it at -1.
+	resp.Header().Set("Content-Type", apihttp.CTypeRaw)
+	resp.Header().Set("Digest", fmt.Sprintf("%s=%s", apihttp.DigestSHA, metadata.SHA256))
+	resp.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(resp, rc); err != nil {
+		// The response has been started, so all we can do is log.
+		logger.Errorf("while streaming resource %q: %v", args.Name, err)
+	}
+	return nil
+}
+
+func (h *resourcesHandler) upload(st *state.State, req *http.Request) (*params.ResourceMetadata, error) {
+	// Since we want to stream the resource in we cannot simply use
+	// mime/multipart directly.
+	defer req.Body.Close()
+
+	// Check if changes are allowed and the command may proceed.
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var meta params.ResourceMetadata
+	data, err := apihttp.ExtractRequestAttachment(req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Name == "" {
+		return nil, errors.New("resource name not specified")
+	}
+	if _, err := st.Service(meta.ServiceName); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	storage, err := st.ResourceStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+
+	// The resource is hashed as it is stored, and rejected before its
+	// metadata is recorded if it does not match what the client sent.
+	r := &verifyingReader{
+		r:      data,
+		hash:   sha256.New(),
+		size:   meta.Size,
+		sha256: meta.SHA256,
+	}
+	added, err := storage.AddResource(r, resourcestorage.Metadata{
+		ServiceName: meta.ServiceName,
+		Name:        meta.Name,
+		Size:        meta.Size,
+		SHA256:      meta.SHA256,
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot attach resource %q", meta.Name)
+	}
+	logger.Infof("attached resource %q revision %d to service %q", added.Name, added.Revision, added.ServiceName)
+	return &params.ResourceMetadata{
+		ServiceName: added.ServiceName,
+		Name:        added.Name,
+		Revision:    added.Revision,
+		Size:        added.Size,
+		SHA256:      added.SHA256,
+		Uploaded:    added.Uploaded,
+	}, nil
+}
+
+// verifyingReader reads a resource, returning an error once the
+// content read does not match the expected size and SHA256 hash. The
+// hash is checked as soon as the expected size has been read, since
+// the blob store need not read on to the end of the stream.
+type verifyingReader struct {
+	r      io.Reader
+	hash   hash.Hash
+	read   int64
+	size   int64
+	sha256 string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.hash.Write(p[:n])
+	v.read += int64(n)
+	switch {
+	case v.read > v.size || (err == io.EOF && v.read < v.size):
+		return n, errors.Errorf("size mismatch: expected %d bytes, got %d", v.size, v.read)
+	case v.read == v.size && n > 0:
+		if sum := hex.EncodeToString(v.hash.Sum(nil)); sum != v.sha256 {
+			return n, errors.Errorf("hash mismatch: expected %s, got %s", v.sha256, sum)
+		}
+	}
+	return n, err
+}
+
+func (h *resourcesHandler) parseGETArgs(req *http.Request) (*params.ResourceDownloadArgs, error) {
+	defer req.Body.Close()
+
+	ctype := req.Header.Get("Content-Type")
+	if ctype != apihttp.CTypeJSON {
+		return nil, errors.Errorf("expected Content-Type %q, got %q", apihttp.CTypeJSON, ctype)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading request body")
+	}
+	var args params.ResourceDownloadArgs
+	if err := json.Unmarshal(body, &args); err != nil {
+		return nil, errors.Annotate(err, "while de-serializing args")
+	}
+	return &args, nil
+}
+
+// sendJSON sends a JSON-encoded result.
+func (h *resourcesHandler) sendJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to serialize the result (%v): %v", result, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *resourcesHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendJSON(w, statusCode, &params.Error{Message: message})
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package resources provides the API through which the binary
+// resources attached to services' charms are listed. The resources
+// themselves are uploaded and downloaded over HTTP.
+package resources
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Resources", 1, NewAPI)
+}
+
+// API implements the Resources facade.
+type API struct {
+	st        *state.State
+	canAccess common.AuthFunc
+}
+
+// NewAPI returns a new Resources API facade. Clients may list the
+// resources of any service; unit agents only those of their own.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	var canAccess common.AuthFunc
+	switch {
+	case authorizer.AuthClient():
+		canAccess = func(names.Tag) bool { return true }
+	case authorizer.AuthUnitAgent():
+		unitService, err := names.UnitService(authorizer.GetAuthTag().Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		serviceTag := names.NewServiceTag(unitService)
+		canAccess = func(tag names.Tag) bool { return tag == serviceTag }
+	default:
+		return nil, common.ErrPerm
+	}
+	return &API{st: st, canAccess: canAccess}, nil
+}
+
+// ListResources returns the resources attached to each of the given
+// services, ordered by name.
+func (api *API) ListResources(args params.Entities) (params.ResourcesResults, error) {
+	result := params.ResourcesResults{Results: make([]params.ResourcesResult, len(args.Entities))}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	storage, err := api.st.ResourceStorage()
+	if err != nil {
+		return params.ResourcesResults{}, errors.Trace(err)
+	}
+	defer storage.Close()
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil || !api.canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		resources, err := storage.ServiceResources(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		list := make([]params.ResourceMetadata, len(resources))
+		for j, r := range resources {
+			list[j] = params.ResourceMetadata{
+				ServiceName: r.ServiceName,
+				Name:        r.Name,
+				Revision:    r.Revision,
+				Size:        r.Size,
+				SHA256:      r.SHA256,
+				Uploaded:    r.Uploaded,
+			}
+		}
+		result.Results[i].Resources = list
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/resources"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state/resourcestorage"
+)
+
+type resourcesSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	for _, name := range []string{"theme", "plugins"} {
+		_, err = storage.AddResource(strings.NewReader("abc"), resourcestorage.Metadata{
+			ServiceName: "wordpress",
+			Name:        name,
+			Size:        3,
+			SHA256:      "hash(abc)",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *resourcesSuite) TestNewAPIRefusesMachineAgent(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := resources.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *resourcesSuite) TestListResources(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	api, err := resources.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ListResources(params.Entities{Entities: []params.Entity{
+		{Tag: "service-wordpress"},
+		{Tag: "service-mysql"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Resources, gc.HasLen, 2)
+	c.Check(result.Results[0].Resources[0].Name, gc.Equals, "plugins")
+	c.Check(result.Results[0].Resources[1].Name, gc.Equals, "theme")
+	c.Check(result.Results[0].Resources[1].Revision, gc.Equals, 1)
+	c.Check(result.Results[1], jc.DeepEquals, params.ResourcesResult{Resources: []params.ResourceMetadata{}})
+	c.Check(result.Results[2].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *resourcesSuite) TestListResourcesAsUnit(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("wordpress/0")}
+	api, err := resources.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ListResources(params.Entities{Entities: []params.Entity{
+		{Tag: "service-wordpress"},
+		{Tag: "service-mysql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Check(result.Results[0].Resources, gc.HasLen, 2)
+	c.Check(result.Results[1].Error, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,205 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/resourcestorage"
+	"github.com/juju/juju/testing/factory"
+)
+
+type resourcesSuite struct {
+	userAuthHttpSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.userAuthHttpSuite.SetUpTest(c)
+	s.wordpress = s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+}
+
+func (s *resourcesSuite) resourcesURL(c *gc.C) string {
+	environ, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/resources", environ.UUID())
+	return uri.String()
+}
+
+func (s *resourcesSuite) checkErrorResponse(c *gc.C, resp *http.Response, statusCode int, msg string) {
+	c.Check(resp.StatusCode, gc.Equals, statusCode)
+	c.Check(resp.Header.Get("Content-Type"), gc.Equals, apihttp.CTypeJSON)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	var failure params.Error
+	err = json.Unmarshal(body, &failure)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(&failure, gc.ErrorMatches, msg)
+}
+
+func (s *resourcesSuite) upload(c *gc.C, meta params.ResourceMetadata, content string) *http.Response {
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="metadata"`)
+	header.Set("Content-Type", apihttp.CTypeJSON)
+	part, err := writer.CreatePart(header)
+	c.Assert(err, jc.ErrorIsNil)
+	err = json.NewEncoder(part).Encode(meta)
+	c.Assert(err, jc.ErrorIsNil)
+
+	part, err = writer.CreateFormFile("attached", meta.Name)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.Copy(part, strings.NewReader(content))
+	c.Assert(err, jc.ErrorIsNil)
+	err = writer.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := s.authRequest(c, "PUT", s.resourcesURL(c), writer.FormDataContentType(), &parts)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func (s *resourcesSuite) download(c *gc.C, tag, password, name string) *http.Response {
+	body, err := json.Marshal(params.ResourceDownloadArgs{ServiceName: "wordpress", Name: name})
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.sendRequest(c, tag, password, "GET", s.resourcesURL(c), apihttp.CTypeJSON, bytes.NewReader(body))
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func resourceMetadata(name, content string) params.ResourceMetadata {
+	sum := sha256.Sum256([]byte(content))
+	return params.ResourceMetadata{
+		ServiceName: "wordpress",
+		Name:        name,
+		Size:        int64(len(content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+}
+
+func (s *resourcesSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "PUT", s.resourcesURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *resourcesSuite) TestInvalidHTTPMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.resourcesURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *resourcesSuite) TestUpload(c *gc.C) {
+	resp := s.upload(c, resourceMetadata("theme", "abc"), "abc")
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+
+	var result params.ResourceMetadata
+	err := apihttp.ExtractJSONResult(resp, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Name, gc.Equals, "theme")
+	c.Check(result.Revision, gc.Equals, 1)
+
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	metadata, err := storage.Metadata("wordpress", "theme")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(metadata.SHA256, gc.Equals, result.SHA256)
+}
+
+func (s *resourcesSuite) TestUploadHashMismatch(c *gc.C) {
+	resp := s.upload(c, resourceMetadata("theme", "abc"), "def")
+	defer resp.Body.Close()
+	s.checkErrorResponse(c, resp, http.StatusBadRequest, `cannot attach resource "theme": .*hash mismatch.*`)
+
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	resources, err := storage.ServiceResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resources, gc.HasLen, 0)
+}
+
+func (s *resourcesSuite) TestUploadUnknownService(c *gc.C) {
+	meta := resourceMetadata("theme", "abc")
+	meta.ServiceName = "mysql"
+	resp := s.upload(c, meta, "abc")
+	defer resp.Body.Close()
+	s.checkErrorResponse(c, resp, http.StatusBadRequest, `service "mysql" not found`)
+}
+
+func (s *resourcesSuite) addResource(c *gc.C, name, content string) {
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	meta := resourceMetadata(name, content)
+	_, err = storage.AddResource(strings.NewReader(content), resourcestorage.Metadata{
+		ServiceName: meta.ServiceName,
+		Name:        meta.Name,
+		Size:        meta.Size,
+		SHA256:      meta.SHA256,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *resourcesSuite) TestDownload(c *gc.C) {
+	s.addResource(c, "theme", "abc")
+	resp := s.download(c, s.userTag.String(), s.password, "theme")
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Check(resp.Header.Get("Content-Type"), gc.Equals, apihttp.CTypeRaw)
+	c.Check(resp.Header.Get("Digest"), gc.Equals, string(apihttp.DigestSHA)+"="+resourceMetadata("theme", "abc").SHA256)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(body), gc.Equals, "abc")
+}
+
+func (s *resourcesSuite) TestDownloadNotFound(c *gc.C) {
+	resp := s.download(c, s.userTag.String(), s.password, "theme")
+	defer resp.Body.Close()
+	s.checkErrorResponse(c, resp, http.StatusNotFound, `resource "theme" of service "wordpress" not found`)
+}
+
+func (s *resourcesSuite) TestDownloadAsUnit(c *gc.C) {
+	s.addResource(c, "theme", "abc")
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{
+		Service:  s.wordpress,
+		Password: "unit-password-123456",
+	})
+	resp := s.download(c, unit.Tag().String(), "unit-password-123456", "theme")
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+
+	// Units of other services cannot download the resource.
+	other := s.Factory.MakeUnit(c, &factory.UnitParams{Password: "unit-password-123456"})
+	resp = s.download(c, other.Tag().String(), "unit-password-123456", "theme")
+	defer resp.Body.Close()
+	s.checkErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
@@ -74,6 +74,12 @@ func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier
 	if err := r.resources.RegisterNamed("logDir", common.StringResource(srv.logDir)); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := r.resources.RegisterNamed("sessionPool", common.SessionPoolResource{SessionPool: srv.sessionPool}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := r.resources.RegisterNamed("drainer", common.DrainerResource{Drainer: srv}); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return r, nil
 }
 
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// scopedRoot restricts the API calls made by a user logged in with a
+// scoped credential to the facades, and the entities, that the
+// credential gives access to.
+type scopedRoot struct {
+	rpc.MethodFinder
+	facades  set.Strings
+	entities set.Strings
+}
+
+// newScopedRoot returns a new scopedRoot allowing calls to the given
+// facades. If entities is not empty, only calls on those entities are
+// allowed.
+func newScopedRoot(finder rpc.MethodFinder, facades, entities []string) *scopedRoot {
+	return &scopedRoot{
+		MethodFinder: finder,
+		facades:      set.NewStrings(facades...),
+		entities:     set.NewStrings(entities...),
+	}
+}
+
+var (
+	entitiesType = reflect.TypeOf(params.Entities{})
+	entityType   = reflect.TypeOf(params.Entity{})
+)
+
+// FindMethod returns common.ErrPerm if the call is not allowed by the
+// scoped credential. When the credential is limited to some entities,
+// only calls taking the entities to act on as params.Entities or
+// params.Entity are allowed, and the entities are checked when the call
+// is made.
+func (r *scopedRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// The lookup of the name is done first to return a not found error if the
+	// user is looking for a method that we just don't have.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	// Clients need to ping the API server, and to get the results of
+	// the watchers they were allowed to start.
+	if rootName == "Pinger" || strings.HasSuffix(rootName, "Watcher") {
+		return caller, nil
+	}
+	// Scoped credentials cannot be used to create more of them.
+	if rootName == "ScopedCredentials" || !r.facades.Contains(rootName) {
+		return nil, common.ErrPerm
+	}
+	if r.entities.IsEmpty() {
+		return caller, nil
+	}
+	switch caller.ParamsType() {
+	case entitiesType, entityType:
+		return &scopedCaller{caller, r.entities}, nil
+	}
+	return nil, common.ErrPerm
+}
+
+// scopedCaller checks that the entities named in the arguments of a
+// call are among those allowed.
+type scopedCaller struct {
+	rpcreflect.MethodCaller
+	entities set.Strings
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *scopedCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	var tags []string
+	switch arg := arg.Interface().(type) {
+	case params.Entities:
+		for _, entity := range arg.Entities {
+			tags = append(tags, entity.Tag)
+		}
+	case params.Entity:
+		tags = append(tags, arg.Tag)
+	}
+	for _, tag := range tags {
+		if !c.entities.Contains(tag) {
+			return reflect.Value{}, common.ErrPerm
+		}
+	}
+	return c.MethodCaller.Call(objId, arg)
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type scopedRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&scopedRootSuite{})
+
+func (r *scopedRootSuite) TestFindAllowedMethod(c *gc.C) {
+	root := apiserver.TestingScopedRoot(nil, []string{"Client"}, nil)
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	// Pinging and watchers are always allowed.
+	caller, err = root.FindMethod("Pinger", 0, "Ping")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+	caller, err = root.FindMethod("AllWatcher", 0, "Next")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (r *scopedRootSuite) TestFindDisallowedFacade(c *gc.C) {
+	root := apiserver.TestingScopedRoot(nil, []string{"Client", "ScopedCredentials"}, nil)
+	caller, err := root.FindMethod("UserManager", 0, "AddUser")
+	c.Check(err, gc.Equals, common.ErrPerm)
+	c.Check(caller, gc.IsNil)
+
+	caller, err = root.FindMethod("ScopedCredentials", 1, "CreateScopedCredentials")
+	c.Check(err, gc.Equals, common.ErrPerm)
+	c.Check(caller, gc.IsNil)
+}
+
+func (r *scopedRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	root := apiserver.TestingScopedRoot(nil, []string{"Client"}, nil)
+	caller, err := root.FindMethod("Client", 0, "NoSuchMethod")
+	c.Assert(err, gc.ErrorMatches, `no such request - method Client\(0\).NoSuchMethod is not implemented`)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *scopedRootSuite) TestEntitiesLimitCalls(c *gc.C) {
+	root := apiserver.TestingScopedRoot(nil, []string{"Client"}, []string{"machine-0"})
+
+	// Calls that do not name the entities they act on are refused.
+	for _, method := range []string{"FullStatus", "DestroyServiceUnits", "AgentVersion"} {
+		caller, err := root.FindMethod("Client", 0, method)
+		c.Check(err, gc.Equals, common.ErrPerm)
+		c.Check(caller, gc.IsNil)
+	}
+
+	// Calls naming other entities are refused when made.
+	caller, err := root.FindMethod("Client", 0, "RetryProvisioning")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+	_, err = caller.Call("", reflect.ValueOf(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+	}))
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package scopedcredentials provides the API used to create and revoke
+// time-limited credentials that give scripts access to part of the API
+// on behalf of a user, without the user's password.
+package scopedcredentials
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ScopedCredentials", 1, NewScopedCredentialsAPI)
+}
+
+const (
+	// DefaultExpiry is how long a scoped credential remains valid
+	// when no expiry is specified.
+	DefaultExpiry = time.Hour
+
+	// MaxExpiry is the longest that a scoped credential can remain
+	// valid.
+	MaxExpiry = 30 * 24 * time.Hour
+)
+
+// ScopedCredentials defines the methods on the scopedcredentials API
+// end point.
+type ScopedCredentials interface {
+	CreateScopedCredentials(args params.CreateScopedCredentials) (params.ScopedCredentialResults, error)
+	RevokeScopedCredentials(args params.ScopedCredentialIds) (params.ErrorResults, error)
+}
+
+// ScopedCredentialsAPI implements the ScopedCredentials interface and
+// is the concrete implementation of the api end point.
+type ScopedCredentialsAPI struct {
+	state      *state.State
+	authorizer common.Authorizer
+	user       names.UserTag
+}
+
+var _ ScopedCredentials = (*ScopedCredentialsAPI)(nil)
+
+// NewScopedCredentialsAPI returns a new ScopedCredentialsAPI. Only
+// users can create scoped credentials.
+func NewScopedCredentialsAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*ScopedCredentialsAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	user, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	return &ScopedCredentialsAPI{
+		state:      st,
+		authorizer: authorizer,
+		user:       user,
+	}, nil
+}
+
+// CreateScopedCredentials creates credentials that give access to the
+// given facades, and optionally only for the given entities, on behalf
+// of the logged in user until they expire.
+func (api *ScopedCredentialsAPI) CreateScopedCredentials(args params.CreateScopedCredentials) (params.ScopedCredentialResults, error) {
+	results := params.ScopedCredentialResults{
+		Results: make([]params.ScopedCredentialResult, len(args.Credentials)),
+	}
+	for i, arg := range args.Credentials {
+		cred, err := api.createScopedCredential(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = cred
+	}
+	return results, nil
+}
+
+func (api *ScopedCredentialsAPI) createScopedCredential(arg params.CreateScopedCredential) (*params.ScopedCredential, error) {
+	if err := validateFacades(arg.Facades); err != nil {
+		return nil, errors.Trace(err)
+	}
+	expiry := arg.Expiry
+	if expiry == 0 {
+		expiry = DefaultExpiry
+	}
+	if expiry < 0 || expiry > MaxExpiry {
+		return nil, errors.NotValidf("expiry %v", arg.Expiry)
+	}
+	cred, err := api.state.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:    api.user,
+		Facades:  arg.Facades,
+		Entities: arg.Entities,
+		Expires:  time.Now().Add(expiry),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m, err := authentication.NewScopedCredentialMacaroon(cred)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.ScopedCredential{
+		Id:       cred.Id(),
+		Macaroon: m,
+		Expires:  cred.Expires(),
+	}, nil
+}
+
+// validateFacades checks that the named facades exist, and that none
+// of them is this one: a scoped credential cannot be used to create
+// credentials that outlive it.
+func validateFacades(facades []string) error {
+	if len(facades) == 0 {
+		return errors.New("no facades specified")
+	}
+	known := make(map[string]bool)
+	for _, desc := range common.Facades.List() {
+		known[desc.Name] = true
+	}
+	for _, name := range facades {
+		if !known[name] || name == "ScopedCredentials" {
+			return errors.NotValidf("facade %q", name)
+		}
+	}
+	return nil
+}
+
+// RevokeScopedCredentials revokes the scoped credentials with the given
+// ids. Users can only revoke the credentials they created.
+func (api *ScopedCredentialsAPI) RevokeScopedCredentials(args params.ScopedCredentialIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		if err := api.revokeScopedCredential(id); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
+
+func (api *ScopedCredentialsAPI) revokeScopedCredential(id string) error {
+	cred, err := api.state.ScopedCredential(id)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if cred.Owner().Username() != api.user.Username() {
+		return common.ErrPerm
+	}
+	return api.state.RemoveScopedCredential(id)
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package scopedcredentials_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	_ "github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/scopedcredentials"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type scopedCredentialsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *scopedcredentials.ScopedCredentialsAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&scopedCredentialsSuite{})
+
+func (s *scopedCredentialsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = scopedcredentials.NewScopedCredentialsAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *scopedCredentialsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewMachineTag("1")
+	api, err := scopedcredentials.NewScopedCredentialsAPI(s.State, nil, authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *scopedCredentialsSuite) TestCreateScopedCredentials(c *gc.C) {
+	before := time.Now()
+	results, err := s.api.CreateScopedCredentials(params.CreateScopedCredentials{
+		Credentials: []params.CreateScopedCredential{{
+			Facades:  []string{"Client"},
+			Entities: []string{"unit-wordpress-0"},
+			Expiry:   time.Minute,
+		}, {
+			Facades: []string{"Client"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Macaroon.Id(), gc.Equals, result.Result.Id)
+	cred, err := s.State.ScopedCredential(result.Result.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Owner(), gc.Equals, s.AdminUserTag(c))
+	c.Assert(cred.Facades(), jc.DeepEquals, []string{"Client"})
+	c.Assert(cred.Entities(), jc.DeepEquals, []string{"unit-wordpress-0"})
+	c.Assert(cred.Expires().Before(before.Add(time.Minute)), jc.IsFalse)
+	c.Assert(cred.Expires().After(time.Now().Add(time.Minute)), jc.IsFalse)
+
+	result = results.Results[1]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Expires.After(before.Add(scopedcredentials.DefaultExpiry-time.Second)), jc.IsTrue)
+}
+
+func (s *scopedCredentialsSuite) TestCreateScopedCredentialsInvalid(c *gc.C) {
+	results, err := s.api.CreateScopedCredentials(params.CreateScopedCredentials{
+		Credentials: []params.CreateScopedCredential{{
+			Facades: nil,
+		}, {
+			Facades: []string{"NoSuchFacade"},
+		}, {
+			Facades: []string{"ScopedCredentials"},
+		}, {
+			Facades: []string{"Client"},
+			Expiry:  scopedcredentials.MaxExpiry + time.Second,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ScopedCredentialResults{
+		Results: []params.ScopedCredentialResult{{
+			Error: &params.Error{Message: "no facades specified"},
+		}, {
+			Error: &params.Error{Message: `facade "NoSuchFacade" not valid`, Code: params.CodeNotValid},
+		}, {
+			Error: &params.Error{Message: `facade "ScopedCredentials" not valid`, Code: params.CodeNotValid},
+		}, {
+			Error: &params.Error{Message: "expiry 720h0m1s not valid", Code: params.CodeNotValid},
+		}},
+	})
+}
+
+func (s *scopedCredentialsSuite) TestRevokeScopedCredentials(c *gc.C) {
+	results, err := s.api.CreateScopedCredentials(params.CreateScopedCredentials{
+		Credentials: []params.CreateScopedCredential{{Facades: []string{"Client"}}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	id := results.Results[0].Result.Id
+
+	// Another user cannot revoke the credential.
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewUserTag("mallory")
+	api, err := scopedcredentials.NewScopedCredentialsAPI(s.State, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	revokeResults, err := api.RevokeScopedCredentials(params.ScopedCredentialIds{Ids: []string{id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revokeResults.OneError(), gc.ErrorMatches, "permission denied")
+
+	revokeResults, err = s.api.RevokeScopedCredentials(params.ScopedCredentialIds{Ids: []string{id, "unknown"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revokeResults.Results[0].Error, gc.IsNil)
+	c.Assert(revokeResults.Results[1].Error, gc.ErrorMatches, "permission denied")
+	_, err = s.State.ScopedCredential(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -20,6 +20,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/highavailability"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -91,6 +92,63 @@ func (s *serverSuite) TestStop(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *serverSuite) TestDrain(c *gc.C) {
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(s.State, listener, apiserver.ServerConfig{
+		Cert: []byte(coretesting.ServerCert),
+		Key:  []byte(coretesting.ServerKey),
+		Tag:  names.NewMachineTag("0"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	agentInfo := &api.Info{
+		Tag:        machine.Tag(),
+		Password:   password,
+		Nonce:      "fake_nonce",
+		Addrs:      []string{srv.Addr()},
+		CACert:     coretesting.CACert,
+		EnvironTag: s.State.EnvironTag(),
+	}
+	agentSt, err := api.Open(agentInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer agentSt.Close()
+
+	adminInfo := s.APIInfo(c)
+	adminInfo.Addrs = []string{srv.Addr()}
+	adminSt, err := api.Open(adminInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer adminSt.Close()
+
+	// The drain request itself completes.
+	err = highavailability.NewClient(adminSt).DrainAPIServer()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The server stops without error.
+	done := make(chan error, 1)
+	go func() { done <- srv.Wait() }()
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for the API server to stop")
+	}
+
+	// The agent's connection is closed, so that it reconnects
+	// elsewhere.
+	_, err = agentSt.Machiner().Machine(machine.MachineTag())
+	if err != rpc.ErrShutdown && err != io.ErrUnexpectedEOF {
+		c.Fatalf("unexpected error from request: %v", err)
+	}
+
+	// New connections are refused.
+	_, err = api.Open(agentInfo, fastDialOpts)
+	c.Assert(err, gc.NotNil)
+}
+
 func (s *serverSuite) TestAPIServerCanListenOnBothIPv4AndIPv6(c *gc.C) {
 	err := s.State.SetAPIHostPorts(nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -273,6 +331,17 @@ func dialWebsocket(c *gc.C, addr, path string) (*websocket.Conn, error) {
 	return websocket.DialConfig(config)
 }
 
+func (s *serverSuite) TestFailedRequestsHaveTraceId(c *gc.C) {
+	err := s.APIState.APICall("NoSuchFacade", 0, "", "Frob", nil, nil)
+	c.Assert(err, gc.ErrorMatches, `unknown object type "NoSuchFacade"`)
+	c.Assert(err, gc.FitsTypeOf, &params.Error{})
+	c.Assert(err.(*params.Error).TraceId, gc.Matches, `[0-9A-F]+:[0-9]+`)
+
+	// Each request has its own trace id.
+	err2 := s.APIState.APICall("NoSuchFacade", 0, "", "Frob", nil, nil)
+	c.Assert(err2.(*params.Error).TraceId, gc.Not(gc.Equals), err.(*params.Error).TraceId)
+}
+
 func (s *serverSuite) TestNonCompatiblePathsAre404(c *gc.C) {
 	// we expose the API at '/' for compatibility, and at '/ENVUUID/api'
 	// for the correct location, but other Paths should fail.
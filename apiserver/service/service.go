@@ -6,6 +6,8 @@ package service
 
 import (
 	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -21,6 +23,7 @@ func init() {
 // Service defines the methods on the service API end point.
 type Service interface {
 	SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error)
+	ConfigDiff(args params.Entities) (params.ServiceConfigDiffResults, error)
 }
 
 // API implements the service interface and is the concrete
@@ -67,3 +70,67 @@ func (api *API) SetMetricCredentials(args params.ServiceMetricCredentials) (para
 	}
 	return result, nil
 }
+
+// ConfigDiff returns, for each given service, the defaults declared by
+// its charm, its current config settings, and the settings last
+// observed by each of its units, so that units that have yet to catch
+// up with the service's settings can be spotted.
+func (api *API) ConfigDiff(args params.Entities) (params.ServiceConfigDiffResults, error) {
+	result := params.ServiceConfigDiffResults{
+		Results: make([]params.ServiceConfigDiffResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		diff, err := api.configDiff(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i] = diff
+	}
+	return result, nil
+}
+
+func (api *API) configDiff(tag string) (params.ServiceConfigDiffResult, error) {
+	serviceTag, err := names.ParseServiceTag(tag)
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, common.ErrPerm
+	}
+	service, err := api.state.Service(serviceTag.Id())
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, err
+	}
+	ch, _, err := service.Charm()
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, err
+	}
+	settings, err := service.ConfigSettings()
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, err
+	}
+	defaults := ch.Config().DefaultSettings()
+	current := make(charm.Settings)
+	for name, value := range defaults {
+		current[name] = value
+	}
+	for name, value := range settings {
+		current[name] = value
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return params.ServiceConfigDiffResult{}, err
+	}
+	observed := make([]params.UnitObservedConfig, len(units))
+	for i, unit := range units {
+		unitSettings, ok := unit.ObservedConfigSettings()
+		observed[i] = params.UnitObservedConfig{
+			Unit:     unit.Name(),
+			Observed: ok,
+			Settings: params.ConfigSettings(unitSettings),
+		}
+	}
+	return params.ServiceConfigDiffResult{
+		Defaults: params.ConfigSettings(defaults),
+		Settings: params.ConfigSettings(current),
+		Units:    observed,
+	}, nil
+}
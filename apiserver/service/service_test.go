@@ -6,6 +6,7 @@ package service_test
 import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
@@ -88,7 +89,7 @@ func (s *serviceSuite) TestSetMetricCredentials(c *gc.C) {
 			}},
 			params.ErrorResults{[]params.ErrorResult{
 				{Error: nil},
-				{Error: &params.Error{`service "not-a-service" not found`, "not found"}},
+				{Error: &params.Error{Message: `service "not-a-service" not found`, Code: "not found"}},
 			}},
 		},
 	}
@@ -109,3 +110,41 @@ func (s *serviceSuite) TestSetMetricCredentials(c *gc.C) {
 		}
 	}
 }
+
+func (s *serviceSuite) TestConfigDiff(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"})
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: ch,
+	})
+	err := wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "New Title"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit0 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress})
+	err = unit0.SetObservedConfigSettings(charm.Settings{"blog-title": "My Title"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress})
+
+	results, err := s.serviceApi.ConfigDiff(params.Entities{Entities: []params.Entity{
+		{Tag: "service-wordpress"},
+		{Tag: "service-foo"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ServiceConfigDiffResults{
+		Results: []params.ServiceConfigDiffResult{{
+			Defaults: params.ConfigSettings{"blog-title": "My Title"},
+			Settings: params.ConfigSettings{"blog-title": "New Title"},
+			Units: []params.UnitObservedConfig{{
+				Unit:     unit0.Name(),
+				Observed: true,
+				Settings: params.ConfigSettings{"blog-title": "My Title"},
+			}, {
+				Unit: unit1.Name(),
+			}},
+		}, {
+			Error: &params.Error{Message: `service "foo" not found`, Code: "not found"},
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package spaces provides the API through which network spaces are
+// created and listed.
+package spaces
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Spaces", 1, NewAPI)
+}
+
+// API implements the Spaces facade.
+type API struct {
+	st    *state.State
+	check *common.BlockChecker
+}
+
+// NewAPI returns a new Spaces API facade, for clients only.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st, check: common.NewBlockChecker(st)}, nil
+}
+
+// CreateSpaces creates the given spaces, each holding the subnets with
+// the given CIDRs.
+func (api *API) CreateSpaces(args params.CreateSpacesParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Spaces))}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, space := range args.Spaces {
+		_, err := api.st.AddSpace(space.Name, space.SubnetCIDRs)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ListSpaces returns all the spaces of the environment and their
+// subnets.
+func (api *API) ListSpaces() (params.ListSpacesResults, error) {
+	spaces, err := api.st.AllSpaces()
+	if err != nil {
+		return params.ListSpacesResults{}, errors.Trace(err)
+	}
+	result := params.ListSpacesResults{Results: make([]params.Space, len(spaces))}
+	for i, space := range spaces {
+		subnets, err := space.Subnets()
+		if err != nil {
+			return params.ListSpacesResults{}, errors.Trace(err)
+		}
+		result.Results[i].Name = space.Name()
+		result.Results[i].Subnets = make([]params.SubnetInfo, len(subnets))
+		for j, subnet := range subnets {
+			result.Results[i].Subnets[j] = params.SubnetInfo{
+				CIDR:             subnet.CIDR(),
+				ProviderId:       subnet.ProviderId(),
+				VLANTag:          subnet.VLANTag(),
+				AvailabilityZone: subnet.AvailabilityZone(),
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/spaces"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type spacesSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	api *spaces.API
+}
+
+var _ = gc.Suite(&spacesSuite{})
+
+func (s *spacesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{Tag: s.AdminUserTag(c)}
+	var err error
+	s.api, err = spaces.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+
+	for _, info := range []state.SubnetInfo{
+		{CIDR: "10.0.0.0/24", ProviderId: "subnet-0", AvailabilityZone: "zone-a"},
+		{CIDR: "10.0.1.0/24", VLANTag: 42},
+	} {
+		_, err := s.State.AddSubnet(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *spacesSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := spaces.NewAPI(s.State, common.NewResources(), auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *spacesSuite) TestCreateSpaces(c *gc.C) {
+	results, err := s.api.CreateSpaces(params.CreateSpacesParams{
+		Spaces: []params.CreateSpaceParams{
+			{Name: "dmz", SubnetCIDRs: []string{"10.0.0.0/24"}},
+			{Name: "Bad Name"},
+			{Name: "internal", SubnetCIDRs: []string{"10.0.0.0/24"}},
+			{Name: "empty"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, `cannot add space "Bad Name": space name "Bad Name" not valid`)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `cannot add space "internal": subnet "10.0.0.0/24" already in space "dmz"`)
+	c.Check(results.Results[3].Error, gc.IsNil)
+
+	space, err := s.State.Space("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 1)
+	c.Assert(subnets[0].CIDR(), gc.Equals, "10.0.0.0/24")
+}
+
+func (s *spacesSuite) TestBlockCreateSpaces(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockCreateSpaces")
+	_, err := s.api.CreateSpaces(params.CreateSpacesParams{
+		Spaces: []params.CreateSpaceParams{{Name: "dmz"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockCreateSpaces")
+	all, err := s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *spacesSuite) TestListSpaces(c *gc.C) {
+	_, err := s.State.AddSpace("internal", []string{"10.0.1.0/24", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ListSpacesResults{
+		Results: []params.Space{{
+			Name:    "dmz",
+			Subnets: []params.SubnetInfo{},
+		}, {
+			Name: "internal",
+			Subnets: []params.SubnetInfo{
+				{CIDR: "10.0.0.0/24", ProviderId: "subnet-0", AvailabilityZone: "zone-a"},
+				{CIDR: "10.0.1.0/24", VLANTag: 42},
+			},
+		}},
+	})
+}
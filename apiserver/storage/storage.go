@@ -4,6 +4,8 @@
 package storage
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils/set"
@@ -89,13 +91,27 @@ func (api *API) Show(entities params.Entities) (params.StorageDetailsResults, er
 // List returns all currently known storage. Unlike Show(),
 // if errors encountered while retrieving a particular
 // storage, this error is treated as part of the returned storage detail.
-func (api *API) List() (params.StorageInfosResult, error) {
+// The storage instances are listed ordered by id, and the given page
+// parameters may limit the list to a page of them.
+func (api *API) List(page params.ListPage) (params.StorageInfosResult, error) {
 	stateInstances, err := api.storage.AllStorageInstances()
 	if err != nil {
 		return params.StorageInfosResult{}, common.ServerError(err)
 	}
+	ids := make([]string, len(stateInstances))
+	byId := make(map[string]state.StorageInstance)
+	for i, stateInstance := range stateInstances {
+		ids[i] = stateInstance.StorageTag().Id()
+		byId[ids[i]] = stateInstance
+	}
+	sort.Strings(ids)
+	start, end, continuation, err := common.Page(ids, page)
+	if err != nil {
+		return params.StorageInfosResult{}, common.ServerError(err)
+	}
 	var infos []params.StorageInfo
-	for _, stateInstance := range stateInstances {
+	for _, id := range ids[start:end] {
+		stateInstance := byId[id]
 		storageTag := stateInstance.StorageTag()
 		persistent, err := api.isPersistent(stateInstance)
 		if err != nil {
@@ -114,7 +130,10 @@ func (api *API) List() (params.StorageInfosResult, error) {
 			infos = append(infos, aParam)
 		}
 	}
-	return params.StorageInfosResult{Results: infos}, nil
+	return params.StorageInfosResult{
+		Results:      infos,
+		Continuation: continuation,
+	}, nil
 }
 
 func (api *API) createStorageDetailsResult(
@@ -37,14 +37,52 @@ func (s *storageSuite) TestStorageListEmpty(c *gc.C) {
 		return []state.StorageInstance{}, nil
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(found.Results, gc.HasLen, 0)
 	s.assertCalls(c, []string{allStorageInstancesCall})
 }
 
+func (s *storageSuite) TestStorageListPages(c *gc.C) {
+	var instances []state.StorageInstance
+	for _, id := range []string{"data/2", "data/0", "logs/0"} {
+		instances = append(instances, &mockStorageInstance{
+			kind:       state.StorageKindFilesystem,
+			owner:      s.unitTag,
+			storageTag: names.NewStorageTag(id),
+		})
+	}
+	s.state.allStorageInstances = func() ([]state.StorageInstance, error) {
+		return instances, nil
+	}
+	s.state.storageInstanceAttachments = func(tag names.StorageTag) ([]state.StorageAttachment, error) {
+		return nil, nil
+	}
+
+	var tags []string
+	page := params.ListPage{Limit: 2}
+	for i := 0; ; i++ {
+		c.Assert(i, gc.Not(gc.Equals), 3, gc.Commentf("too many pages"))
+		found, err := s.api.List(page)
+		c.Assert(err, jc.ErrorIsNil)
+		for _, result := range found.Results {
+			tags = append(tags, result.StorageTag)
+		}
+		if found.Continuation == "" {
+			break
+		}
+		page.Continuation = found.Continuation
+	}
+	c.Assert(tags, jc.DeepEquals, []string{"storage-data-0", "storage-data-2", "storage-logs-0"})
+}
+
+func (s *storageSuite) TestStorageListBadPage(c *gc.C) {
+	_, err := s.api.List(params.ListPage{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "limit -1 not valid")
+}
+
 func (s *storageSuite) TestStorageListFilesystem(c *gc.C) {
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -65,7 +103,7 @@ func (s *storageSuite) TestStorageListFilesystem(c *gc.C) {
 
 func (s *storageSuite) TestStorageListVolume(c *gc.C) {
 	s.storageInstance.kind = state.StorageKindBlock
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -92,7 +130,7 @@ func (s *storageSuite) TestStorageListError(c *gc.C) {
 		return []state.StorageInstance{}, errors.Errorf(msg)
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 
 	expectedCalls := []string{
@@ -110,7 +148,7 @@ func (s *storageSuite) TestStorageListInstanceError(c *gc.C) {
 		return nil, errors.Errorf(msg)
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -133,7 +171,7 @@ func (s *storageSuite) TestStorageListAttachmentError(c *gc.C) {
 		return []state.StorageAttachment{}, errors.Errorf("list test error")
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -155,7 +193,7 @@ func (s *storageSuite) TestStorageListMachineError(c *gc.C) {
 		return names.MachineTag{}, errors.Errorf(msg)
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -178,7 +216,7 @@ func (s *storageSuite) TestStorageListFilesystemError(c *gc.C) {
 		return nil, errors.Errorf(msg)
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
@@ -203,7 +241,7 @@ func (s *storageSuite) TestStorageListFilesystemAttachmentError(c *gc.C) {
 		return s.machineTag, errors.Errorf(msg)
 	}
 
-	found, err := s.api.List()
+	found, err := s.api.List(params.ListPage{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	expectedCalls := []string{
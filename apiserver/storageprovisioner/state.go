@@ -37,6 +37,9 @@ type provisionerState interface {
 	SetFilesystemAttachmentInfo(names.MachineTag, names.FilesystemTag, state.FilesystemAttachmentInfo) error
 	SetVolumeInfo(names.VolumeTag, state.VolumeInfo) error
 	SetVolumeAttachmentInfo(names.MachineTag, names.VolumeTag, state.VolumeAttachmentInfo) error
+
+	SetFilesystemProvisioningError(names.FilesystemTag, string) error
+	SetVolumeProvisioningError(names.VolumeTag, string) error
 }
 
 type stateShim struct {
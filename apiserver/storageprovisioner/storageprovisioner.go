@@ -4,6 +4,8 @@
 package storageprovisioner
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -421,6 +423,11 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 		} else if err != nil {
 			return params.VolumeParams{}, err
 		}
+		if msg := volume.ProvisioningError(); msg != "" {
+			// The volume must not be provisioned again until
+			// provisioning is retried by the user.
+			return params.VolumeParams{}, provisioningFailedError(tag, msg)
+		}
 		volumeAttachments, err := s.st.VolumeAttachments(tag)
 		if err != nil {
 			return params.VolumeParams{}, err
@@ -483,6 +490,11 @@ func (s *StorageProvisionerAPI) FilesystemParams(args params.Entities) (params.F
 		} else if err != nil {
 			return params.FilesystemParams{}, err
 		}
+		if msg := filesystem.ProvisioningError(); msg != "" {
+			// The filesystem must not be provisioned again
+			// until provisioning is retried by the user.
+			return params.FilesystemParams{}, provisioningFailedError(tag, msg)
+		}
 		filesystemParams, err := common.FilesystemParams(filesystem, poolManager)
 		if err != nil {
 			return params.FilesystemParams{}, err
@@ -804,6 +816,49 @@ func (s *StorageProvisionerAPI) SetFilesystemAttachmentInfo(
 	return results, nil
 }
 
+// SetProvisioningErrors records why the specified volumes and filesystems
+// could not be provisioned. Their parameters are withheld from storage
+// provisioners until provisioning is retried.
+func (s *StorageProvisionerAPI) SetProvisioningErrors(args params.StorageProvisioningErrors) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Errors)),
+	}
+	one := func(arg params.StorageProvisioningError) error {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		switch tag := tag.(type) {
+		case names.VolumeTag:
+			err = s.st.SetVolumeProvisioningError(tag, arg.Message)
+		case names.FilesystemTag:
+			err = s.st.SetFilesystemProvisioningError(tag, arg.Message)
+		}
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Errors {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// provisioningFailedError returns an error reporting that provisioning
+// of the storage entity with the given tag failed, for the given reason.
+func provisioningFailedError(tag names.Tag, msg string) error {
+	return &params.Error{
+		Code:    params.CodeProvisioningFailed,
+		Message: fmt.Sprintf("%s provisioning failed: %s", names.ReadableString(tag), msg),
+	}
+}
+
 // AttachmentLife returns the lifecycle state of each specified machine
 // storage attachment.
 func (s *StorageProvisionerAPI) AttachmentLife(args params.MachineStorageIds) (params.LifeResults, error) {
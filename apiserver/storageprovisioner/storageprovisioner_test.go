@@ -172,8 +172,8 @@ func (s *provisionerSuite) TestVolumesMachine(c *gc.C) {
 	c.Assert(results, gc.DeepEquals, params.VolumeResults{
 		Results: []params.VolumeResult{
 			{Result: params.Volume{VolumeTag: "volume-0-0", VolumeId: "abc", Serial: "123", Size: 1024, Persistent: true}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -193,10 +193,10 @@ func (s *provisionerSuite) TestVolumesEnviron(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.VolumeResults{
 		Results: []params.VolumeResult{
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 			{Error: common.ServerError(errors.NotProvisionedf(`volume "1"`))},
 			{Result: params.Volume{VolumeTag: "volume-2", VolumeId: "def", Serial: "456", Size: 4096}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -222,10 +222,10 @@ func (s *provisionerSuite) TestFilesystems(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.FilesystemResults{
 		Results: []params.FilesystemResult{
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 			{Error: common.ServerError(errors.NotProvisionedf(`filesystem "1"`))},
 			{Result: params.Filesystem{FilesystemTag: "filesystem-2", FilesystemId: "def", Size: 4096}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -263,7 +263,7 @@ func (s *provisionerSuite) TestVolumeAttachments(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `volume attachment "2" on "0" not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -303,7 +303,7 @@ func (s *provisionerSuite) TestFilesystemAttachments(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `filesystem attachment "2" on "0" not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -316,7 +316,7 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.VolumeParamsResults{
 		Results: []params.VolumeParamsResult{
-			{Error: &params.Error{`volume "0/0" is already provisioned`, ""}},
+			{Error: &params.Error{Message: `volume "0/0" is already provisioned`, Code: ""}},
 			{Result: params.VolumeParams{
 				VolumeTag: "volume-1",
 				Size:      2048,
@@ -328,7 +328,7 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 					InstanceId: "inst-id",
 				},
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -347,17 +347,80 @@ func (s *provisionerSuite) TestFilesystemParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.FilesystemParamsResults{
 		Results: []params.FilesystemParamsResult{
-			{Error: &params.Error{`filesystem "0/0" is already provisioned`, ""}},
+			{Error: &params.Error{Message: `filesystem "0/0" is already provisioned`, Code: ""}},
 			{Result: params.FilesystemParams{
 				FilesystemTag: "filesystem-1",
 				Size:          2048,
 				Provider:      "environscoped",
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
 
+func (s *provisionerSuite) TestSetProvisioningErrors(c *gc.C) {
+	s.setupVolumes(c)
+	results, err := s.api.SetProvisioningErrors(params.StorageProvisioningErrors{
+		Errors: []params.StorageProvisioningError{
+			{Tag: "volume-0-0", Message: "no space left"},
+			{Tag: "volume-1", Message: "no space left"},
+			{Tag: "volume-42", Message: "no space left"},
+			{Tag: "machine-0", Message: "no space left"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: `cannot set provisioning error for volume "0/0": volume is already provisioned`}},
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	volumeResults, err := s.api.VolumeParams(params.Entities{
+		Entities: []params.Entity{{"volume-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeResults.Results, jc.DeepEquals, []params.VolumeParamsResult{{
+		Error: &params.Error{
+			Message: "volume 1 provisioning failed: no space left",
+			Code:    params.CodeProvisioningFailed,
+		},
+	}})
+
+	// Once provisioning is retried, the parameters are available again.
+	err = s.State.RetryVolumeProvisioning(names.NewVolumeTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	volumeResults, err = s.api.VolumeParams(params.Entities{
+		Entities: []params.Entity{{"volume-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeResults.Results[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestSetProvisioningErrorsFilesystems(c *gc.C) {
+	s.setupFilesystems(c)
+	results, err := s.api.SetProvisioningErrors(params.StorageProvisioningErrors{
+		Errors: []params.StorageProvisioningError{
+			{Tag: "filesystem-1", Message: "quota exceeded"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+
+	filesystemResults, err := s.api.FilesystemParams(params.Entities{
+		Entities: []params.Entity{{"filesystem-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystemResults.Results, jc.DeepEquals, []params.FilesystemParamsResult{{
+		Error: &params.Error{
+			Message: "filesystem 1 provisioning failed: quota exceeded",
+			Code:    params.CodeProvisioningFailed,
+		},
+	}})
+}
+
 func (s *provisionerSuite) TestVolumeAttachmentParams(c *gc.C) {
 	s.setupVolumes(c)
 	s.authorizer.EnvironManager = true
@@ -395,7 +458,7 @@ func (s *provisionerSuite) TestVolumeAttachmentParams(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `machine 2 not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -438,7 +501,7 @@ func (s *provisionerSuite) TestFilesystemAttachmentParams(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `machine 2 not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -472,7 +535,7 @@ func (s *provisionerSuite) TestSetVolumeAttachmentInfo(c *gc.C) {
 			{},
 			{}, // TODO(axw) this should fail, since volume is not provisioned
 			{}, // TODO(axw) this should fail, since machine is not provisioned
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -506,7 +569,7 @@ func (s *provisionerSuite) TestSetFilesystemAttachmentInfo(c *gc.C) {
 			{},
 			{}, // TODO(axw) this should fail, since filesystem is not provisioned
 			{}, // TODO(axw) this should fail, since machine is not provisioned
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -767,7 +830,7 @@ func (s *provisionerSuite) TestAttachmentLife(c *gc.C) {
 			{Life: params.Alive},
 			{Life: params.Alive},
 			{Life: params.Alive},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -77,7 +77,7 @@ func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer stateWrapper.cleanup()
 
 	if err := stateWrapper.authenticateUser(r); err != nil {
-		h.authError(w, h)
+		h.userAuthError(w, h, err)
 		return
 	}
 
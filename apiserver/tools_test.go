@@ -21,6 +21,7 @@ import (
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/simplestreams"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
@@ -89,6 +90,13 @@ func (s *toolsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected binaryVersion argument")
 }
 
+func (s *toolsSuite) TestUploadRefusesReadOnlyUser(c *gc.C) {
+	s.useReadOnlyUser(c)
+	resp, err := s.authRequest(c, "POST", s.toolsURI(c, "?binaryVersion=1.18.0-quantal-amd64"), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
 func (s *toolsSuite) TestUploadRequiresVersion(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.toolsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -323,6 +331,49 @@ func (s *toolsSuite) TestDownloadFetchesAndVerifiesHash(c *gc.C) {
 	s.assertToolsNotStored(c, tools.Version)
 }
 
+func (s *toolsSuite) TestMetadataServesStoredTools(c *gc.C) {
+	vers := version.MustParseBinary("1.23.0-trusty-amd64")
+	s.storeFakeTools(c, s.State, "abc", toolstorage.Metadata{
+		Version: vers,
+		Size:    3,
+		SHA256:  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+
+	// The API server's metadata can be used as a simplestreams source,
+	// and refers to the API server's own tools download endpoint.
+	sourceURL := s.toolsURI(c, "")
+	source := simplestreams.NewURLDataSource("api server", sourceURL, utils.NoVerifySSLHostnames)
+	cons := envtools.NewGeneralToolsConstraint(1, 23, simplestreams.LookupParams{
+		Series: []string{"trusty"},
+		Arches: []string{"amd64"},
+		Stream: "released",
+	})
+	metadata, _, err := envtools.Fetch([]simplestreams.DataSource{source}, cons, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Assert(metadata[0].Version, gc.Equals, "1.23.0")
+	c.Assert(metadata[0].Size, gc.Equals, int64(3))
+	c.Assert(metadata[0].FullPath, gc.Equals, sourceURL+"/1.23.0-trusty-amd64")
+}
+
+func (s *toolsSuite) TestMetadataUnknownFile(c *gc.C) {
+	resp, err := s.metadataRequest(c, "GET", "index.sjson")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `tools metadata file "streams/v1/index.sjson" not found`)
+}
+
+func (s *toolsSuite) TestMetadataRequiresGET(c *gc.C) {
+	resp, err := s.metadataRequest(c, "PUT", "index2.json")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *toolsSuite) metadataRequest(c *gc.C, method, filename string) (*http.Response, error) {
+	url := s.toolsURL(c, "")
+	url.Path += "/streams/v1/" + filename
+	return s.sendRequest(c, "", "", method, url.String(), "", nil)
+}
+
 func (s *toolsSuite) storeFakeTools(c *gc.C, st *state.State, content string, metadata toolstorage.Metadata) *coretools.Tools {
 	storage, err := st.ToolsStorage()
 	c.Assert(err, jc.ErrorIsNil)
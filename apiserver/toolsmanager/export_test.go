@@ -0,0 +1,27 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager
+
+import (
+	"time"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+// PatchState replaces the state used by new ToolsManagerAPIs with one
+// that prunes tools using the given function.
+func PatchState(p interface {
+	PatchValue(interface{}, interface{})
+}, pruneTools func(time.Time, bool) ([]version.Binary, error)) {
+	p.PatchValue(&getState, func(*state.State) stateInterface {
+		return pruneToolsFunc(pruneTools)
+	})
+}
+
+type pruneToolsFunc func(time.Time, bool) ([]version.Binary, error)
+
+func (f pruneToolsFunc) PruneTools(minToolsTime time.Time, dryRun bool) ([]version.Binary, error) {
+	return f(minToolsTime, dryRun)
+}
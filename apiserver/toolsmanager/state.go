@@ -0,0 +1,23 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager
+
+import (
+	"time"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type stateInterface interface {
+	PruneTools(minToolsTime time.Time, dryRun bool) ([]version.Binary, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) PruneTools(minToolsTime time.Time, dryRun bool) ([]version.Binary, error) {
+	return state.PruneTools(s.State, minToolsTime, dryRun)
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ToolsManager", 1, NewToolsManagerAPI)
+}
+
+// ToolsManager defines the methods on the toolsmanager API end point.
+type ToolsManager interface {
+	PruneTools(arg params.PruneToolsParams) (params.PruneToolsResult, error)
+}
+
+// ToolsManagerAPI implements the ToolsManager interface and is the
+// concrete implementation of the api end point.
+type ToolsManagerAPI struct {
+	state      stateInterface
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+var _ ToolsManager = (*ToolsManagerAPI)(nil)
+
+var getState = func(st *state.State) stateInterface {
+	return stateShim{st}
+}
+
+// NewToolsManagerAPI creates a new server-side toolsmanager API end
+// point.
+func NewToolsManagerAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ToolsManagerAPI, error) {
+	// Only clients can access the tools manager service, and only
+	// in the state server environment, as the tools stored by the
+	// state server are shared by all environments.
+	if !authorizer.AuthClient() || !st.IsStateServer() {
+		return nil, common.ErrPerm
+	}
+	return &ToolsManagerAPI{
+		state:      getState(st),
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// PruneTools removes the tools stored by the state server that were
+// added longer ago than the retention period, and that no agent uses.
+func (api *ToolsManagerAPI) PruneTools(arg params.PruneToolsParams) (params.PruneToolsResult, error) {
+	var result params.PruneToolsResult
+	if arg.Retention < 0 {
+		return result, errors.NotValidf("negative retention %v", arg.Retention)
+	}
+	if !arg.DryRun {
+		if err := api.check.RemoveAllowed(); err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+	versions, err := api.state.PruneTools(time.Now().Add(-arg.Retention), arg.DryRun)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Versions = versions
+	return result, nil
+}
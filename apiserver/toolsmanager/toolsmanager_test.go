@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolsmanager_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/toolsmanager"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type toolsManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	resources  *common.Resources
+	authoriser apiservertesting.FakeAuthorizer
+
+	commontesting.BlockHelper
+}
+
+var _ = gc.Suite(&toolsManagerSuite{})
+
+func (s *toolsManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+}
+
+func (s *toolsManagerSuite) TestNewToolsManagerAPIAcceptsClient(c *gc.C) {
+	endPoint, err := toolsmanager.NewToolsManagerAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endPoint, gc.NotNil)
+}
+
+func (s *toolsManagerSuite) TestNewToolsManagerAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = names.NewUnitTag("mysql/0")
+	anAuthoriser.EnvironManager = false
+	endPoint, err := toolsmanager.NewToolsManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *toolsManagerSuite) TestNewToolsManagerAPIRefusesHostedEnvironment(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	endPoint, err := toolsmanager.NewToolsManagerAPI(st, s.resources, s.authoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *toolsManagerSuite) TestPruneTools(c *gc.C) {
+	pruned := []version.Binary{version.MustParseBinary("1.2.3-trusty-amd64")}
+	var minToolsTime time.Time
+	var dryRun bool
+	toolsmanager.PatchState(s, func(t time.Time, d bool) ([]version.Binary, error) {
+		minToolsTime, dryRun = t, d
+		return pruned, nil
+	})
+	api, err := toolsmanager.NewToolsManagerAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := time.Now()
+	result, err := api.PruneTools(params.PruneToolsParams{
+		Retention: 24 * time.Hour,
+		DryRun:    true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Versions, jc.DeepEquals, pruned)
+	c.Assert(dryRun, jc.IsTrue)
+	c.Assert(minToolsTime.Before(before.Add(-24*time.Hour)), jc.IsFalse)
+	c.Assert(minToolsTime.After(time.Now().Add(-24*time.Hour)), jc.IsFalse)
+}
+
+func (s *toolsManagerSuite) TestPruneToolsInvalidRetention(c *gc.C) {
+	api, err := toolsmanager.NewToolsManagerAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.PruneTools(params.PruneToolsParams{Retention: -time.Hour})
+	c.Assert(err, gc.ErrorMatches, "negative retention -1h0m0s not valid")
+}
+
+func (s *toolsManagerSuite) TestBlockPruneTools(c *gc.C) {
+	toolsmanager.PatchState(s, func(time.Time, bool) ([]version.Binary, error) {
+		return nil, nil
+	})
+	api, err := toolsmanager.NewToolsManagerAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockRemoveObject(c, "TestBlockPruneTools")
+
+	_, err = api.PruneTools(params.PruneToolsParams{})
+	s.AssertBlocked(c, err, "TestBlockPruneTools")
+
+	// Dry runs remove nothing, and so are allowed.
+	_, err = api.PruneTools(params.PruneToolsParams{DryRun: true})
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/environs/simplestreams"
+	envtools "github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/state"
+)
+
+// toolsMetadataHandler serves simplestreams metadata describing the
+// tools in the environment's tools storage. The metadata refers to
+// the tools download endpoint for the binaries, so the API server can
+// act as a tools mirror for environments without internet access.
+type toolsMetadataHandler struct {
+	toolsHandler
+}
+
+func (h *toolsMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendExistingError(w, http.StatusNotFound, err)
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	switch r.Method {
+	case "GET":
+		data, err := h.processGet(r, stateWrapper.state)
+		if errors.IsNotFound(err) {
+			h.sendExistingError(w, http.StatusNotFound, err)
+			return
+		} else if err != nil {
+			logger.Errorf("GET(%s) failed: %v", r.URL, err)
+			h.sendExistingError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.Header().Set("Content-Type", apihttp.CTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// processGet handles a tools metadata GET request, returning the
+// content of the requested simplestreams file.
+func (h *toolsMetadataHandler) processGet(r *http.Request, st *state.State) ([]byte, error) {
+	filename := path.Join("streams", envtools.StreamsVersionV1, r.URL.Query().Get(":filename"))
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	metadata, err := storedToolsMetadata(st)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read tools metadata")
+	}
+
+	// All the stored tools are offered in the released stream, and in
+	// the stream the environment's agents are configured to use.
+	streamMetadata := map[string][]*envtools.ToolsMetadata{
+		envtools.ReleasedStream: metadata,
+		cfg.AgentStream():       metadata,
+	}
+	index, legacyIndex, products, err := envtools.MarshalToolsMetadataJSON(streamMetadata, time.Now())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch filename {
+	case simplestreams.UnsignedIndex(envtools.StreamsVersionV1, envtools.IndexFileVersion):
+		return index, nil
+	case simplestreams.UnsignedIndex(envtools.StreamsVersionV1, 1):
+		if legacyIndex != nil {
+			return legacyIndex, nil
+		}
+	default:
+		for stream, data := range products {
+			if filename == envtools.ProductMetadataPath(stream) {
+				return data, nil
+			}
+		}
+	}
+	return nil, errors.NotFoundf("tools metadata file %q", filename)
+}
+
+// storedToolsMetadata returns simplestreams metadata for all the tools
+// in the environment's tools storage, with paths relative to the tools
+// endpoint.
+func storedToolsMetadata(st *state.State) ([]*envtools.ToolsMetadata, error) {
+	storage, err := st.ToolsStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+	allMetadata, err := storage.AllMetadata()
+	if err != nil {
+		return nil, err
+	}
+	metadata := make([]*envtools.ToolsMetadata, len(allMetadata))
+	for i, m := range allMetadata {
+		metadata[i] = &envtools.ToolsMetadata{
+			Release:  m.Version.Series,
+			Version:  m.Version.Number.String(),
+			Arch:     m.Version.Arch,
+			Size:     m.Size,
+			SHA256:   m.SHA256,
+			FileType: "tar.gz",
+			Path:     m.Version.String(),
+		}
+	}
+	envtools.Sort(metadata)
+	return metadata, nil
+}
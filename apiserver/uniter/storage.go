@@ -43,8 +43,13 @@ func (s *StorageAPI) UnitStorageAttachments(args params.Entities) (params.Storag
 	result := params.StorageAttachmentsResults{
 		Results: make([]params.StorageAttachmentsResult, len(args.Entities)),
 	}
-	for i, entity := range args.Entities {
-		storageAttachments, err := s.getOneUnitStorageAttachments(canAccess, entity.Tag)
+	tags, errs := common.ParseTags(common.EntityTags(args), canAccess, names.UnitTagKind)
+	for i, tag := range tags {
+		if errs[i] != nil {
+			result.Results[i].Error = common.ServerError(errs[i])
+			continue
+		}
+		storageAttachments, err := s.getOneUnitStorageAttachments(tag.(names.UnitTag))
 		if err == nil {
 			result.Results[i].Result = storageAttachments
 		}
@@ -53,11 +58,7 @@ func (s *StorageAPI) UnitStorageAttachments(args params.Entities) (params.Storag
 	return result, nil
 }
 
-func (s *StorageAPI) getOneUnitStorageAttachments(canAccess common.AuthFunc, unitTag string) ([]params.StorageAttachment, error) {
-	tag, err := names.ParseUnitTag(unitTag)
-	if err != nil || !canAccess(tag) {
-		return nil, common.ErrPerm
-	}
+func (s *StorageAPI) getOneUnitStorageAttachments(tag names.UnitTag) ([]params.StorageAttachment, error) {
 	stateStorageAttachments, err := s.st.UnitStorageAttachments(tag)
 	if errors.IsNotFound(err) {
 		return nil, common.ErrPerm
@@ -110,8 +111,13 @@ func (s *StorageAPI) StorageAttachments(args params.StorageAttachmentIds) (param
 	result := params.StorageAttachmentResults{
 		Results: make([]params.StorageAttachmentResult, len(args.Ids)),
 	}
-	for i, id := range args.Ids {
-		storageAttachment, err := s.getOneStorageAttachment(canAccess, id)
+	ids, errs := common.ParseStorageAttachmentIds(args.Ids, canAccess)
+	for i, id := range ids {
+		if errs[i] != nil {
+			result.Results[i].Error = common.ServerError(errs[i])
+			continue
+		}
+		storageAttachment, err := s.getOneStorageAttachment(id)
 		if err == nil {
 			result.Results[i].Result = storageAttachment
 		}
@@ -120,19 +126,11 @@ func (s *StorageAPI) StorageAttachments(args params.StorageAttachmentIds) (param
 	return result, nil
 }
 
-func (s *StorageAPI) getOneStorageAttachment(canAccess common.AuthFunc, id params.StorageAttachmentId) (params.StorageAttachment, error) {
-	unitTag, err := names.ParseUnitTag(id.UnitTag)
-	if err != nil || !canAccess(unitTag) {
-		return params.StorageAttachment{}, common.ErrPerm
-	}
-	storageTag, err := names.ParseStorageTag(id.StorageTag)
+func (s *StorageAPI) getOneStorageAttachment(id common.StorageAttachmentTags) (params.StorageAttachment, error) {
+	stateStorageAttachment, err := s.st.StorageAttachment(id.StorageTag, id.UnitTag)
 	if err != nil {
 		return params.StorageAttachment{}, err
 	}
-	stateStorageAttachment, err := s.st.StorageAttachment(storageTag, unitTag)
-	if errors.IsNotFound(err) {
-		return params.StorageAttachment{}, err
-	}
 	return s.fromStateStorageAttachment(stateStorageAttachment)
 }
 
@@ -147,8 +145,13 @@ func (s *StorageAPI) WatchUnitStorageAttachments(args params.Entities) (params.S
 	results := params.StringsWatchResults{
 		Results: make([]params.StringsWatchResult, len(args.Entities)),
 	}
-	for i, entity := range args.Entities {
-		result, err := s.watchOneUnitStorageAttachments(entity.Tag, canAccess)
+	tags, errs := common.ParseTags(common.EntityTags(args), canAccess, names.UnitTagKind)
+	for i, tag := range tags {
+		if errs[i] != nil {
+			results.Results[i].Error = common.ServerError(errs[i])
+			continue
+		}
+		result, err := s.watchOneUnitStorageAttachments(tag.(names.UnitTag))
 		if err == nil {
 			results.Results[i] = result
 		}
@@ -157,12 +160,8 @@ func (s *StorageAPI) WatchUnitStorageAttachments(args params.Entities) (params.S
 	return results, nil
 }
 
-func (s *StorageAPI) watchOneUnitStorageAttachments(tag string, canAccess func(names.Tag) bool) (params.StringsWatchResult, error) {
+func (s *StorageAPI) watchOneUnitStorageAttachments(unitTag names.UnitTag) (params.StringsWatchResult, error) {
 	nothing := params.StringsWatchResult{}
-	unitTag, err := names.ParseUnitTag(tag)
-	if err != nil || !canAccess(unitTag) {
-		return nothing, common.ErrPerm
-	}
 	watch := s.st.WatchStorageAttachments(unitTag)
 	if changes, ok := <-watch.Changes(); ok {
 		return params.StringsWatchResult{
@@ -184,8 +183,13 @@ func (s *StorageAPI) WatchStorageAttachmentInfos(args params.StorageAttachmentId
 	results := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Ids)),
 	}
-	for i, id := range args.Ids {
-		result, err := s.watchOneStorageAttachment(id, canAccess)
+	ids, errs := common.ParseStorageAttachmentIds(args.Ids, canAccess)
+	for i, id := range ids {
+		if errs[i] != nil {
+			results.Results[i].Error = common.ServerError(errs[i])
+			continue
+		}
+		result, err := s.watchOneStorageAttachment(id)
 		if err == nil {
 			results.Results[i] = result
 		}
@@ -194,26 +198,18 @@ func (s *StorageAPI) WatchStorageAttachmentInfos(args params.StorageAttachmentId
 	return results, nil
 }
 
-func (s *StorageAPI) watchOneStorageAttachment(id params.StorageAttachmentId, canAccess func(names.Tag) bool) (params.NotifyWatchResult, error) {
+func (s *StorageAPI) watchOneStorageAttachment(id common.StorageAttachmentTags) (params.NotifyWatchResult, error) {
 	// Watching a storage attachment is implemented as watching the
 	// underlying volume or filesystem attachment. The only thing
 	// we don't necessarily see in doing this is the lifecycle state
 	// changes, but these may be observed by using the
 	// WatchUnitStorageAttachments watcher.
 	nothing := params.NotifyWatchResult{}
-	unitTag, err := names.ParseUnitTag(id.UnitTag)
-	if err != nil || !canAccess(unitTag) {
-		return nothing, common.ErrPerm
-	}
-	storageTag, err := names.ParseStorageTag(id.StorageTag)
-	if err != nil {
-		return nothing, err
-	}
-	machineTag, err := s.st.UnitAssignedMachine(unitTag)
+	machineTag, err := s.st.UnitAssignedMachine(id.UnitTag)
 	if err != nil {
 		return nothing, err
 	}
-	watch, err := common.WatchStorageAttachmentInfo(s.st, storageTag, machineTag)
+	watch, err := common.WatchStorageAttachmentInfo(s.st, id.StorageTag, machineTag)
 	if err != nil {
 		return nothing, errors.Trace(err)
 	}
@@ -235,30 +231,17 @@ func (s *StorageAPI) EnsureStorageAttachmentsDead(args params.StorageAttachmentI
 	results := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Ids)),
 	}
-	for i, id := range args.Ids {
-		err := s.ensureOneStorageAttachmentDead(id, canAccess)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
+	ids, errs := common.ParseStorageAttachmentIds(args.Ids, canAccess)
+	for i, id := range ids {
+		err := errs[i]
+		if err == nil {
+			err = s.st.EnsureStorageAttachmentDead(id.StorageTag, id.UnitTag)
 		}
+		results.Results[i].Error = common.ServerError(err)
 	}
 	return results, nil
 }
 
-func (s *StorageAPI) ensureOneStorageAttachmentDead(id params.StorageAttachmentId, canAccess func(names.Tag) bool) error {
-	unitTag, err := names.ParseUnitTag(id.UnitTag)
-	if err != nil {
-		return err
-	}
-	if !canAccess(unitTag) {
-		return common.ErrPerm
-	}
-	storageTag, err := names.ParseStorageTag(id.StorageTag)
-	if err != nil {
-		return err
-	}
-	return s.st.EnsureStorageAttachmentDead(storageTag, unitTag)
-}
-
 // RemoveStorageAttachments removes the specified storage
 // attachments from state.
 func (s *StorageAPI) RemoveStorageAttachments(args params.StorageAttachmentIds) (params.ErrorResults, error) {
@@ -269,26 +252,13 @@ func (s *StorageAPI) RemoveStorageAttachments(args params.StorageAttachmentIds)
 	results := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Ids)),
 	}
-	for i, id := range args.Ids {
-		err := s.removeOneStorageAttachment(id, canAccess)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
+	ids, errs := common.ParseStorageAttachmentIds(args.Ids, canAccess)
+	for i, id := range ids {
+		err := errs[i]
+		if err == nil {
+			err = s.st.RemoveStorageAttachment(id.StorageTag, id.UnitTag)
 		}
+		results.Results[i].Error = common.ServerError(err)
 	}
 	return results, nil
 }
-
-func (s *StorageAPI) removeOneStorageAttachment(id params.StorageAttachmentId, canAccess func(names.Tag) bool) error {
-	unitTag, err := names.ParseUnitTag(id.UnitTag)
-	if err != nil {
-		return err
-	}
-	if !canAccess(unitTag) {
-		return common.ErrPerm
-	}
-	storageTag, err := names.ParseStorageTag(id.StorageTag)
-	if err != nil {
-		return err
-	}
-	return s.st.RemoveStorageAttachment(storageTag, unitTag)
-}
@@ -258,16 +258,66 @@ func (s *storageSuite) testEnsureDeadOrRemoveStorageAttachments(
 			{nil},
 			{&params.Error{Message: "badness"}},
 			{&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
-			{&params.Error{Message: `"unit-mysql-0" is not a valid storage tag`}},
-			{&params.Error{Message: `"storage-data-0" is not a valid unit tag`}},
+			{&params.Error{Code: params.CodeNotValid, Message: `storage tag "unit-mysql-0" not valid`}},
+			{&params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+		},
+	})
+}
+
+func (s *storageSuite) TestStorageAttachmentsPartialResults(c *gc.C) {
+	unitTag := names.NewUnitTag("mysql/0")
+	storageTag0 := names.NewStorageTag("data/0")
+	storageTag1 := names.NewStorageTag("data/1")
+
+	resources := common.NewResources()
+	getCanAccess := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			return tag == unitTag
+		}, nil
+	}
+	var calls []names.StorageTag
+	state := &mockStorageState{
+		storageAttachment: func(s names.StorageTag, u names.UnitTag) (state.StorageAttachment, error) {
+			c.Assert(u, gc.Equals, unitTag)
+			calls = append(calls, s)
+			return nil, errors.New("badness")
+		},
+	}
+
+	storage, err := uniter.NewStorageAPI(state, resources, getCanAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := storage.StorageAttachments(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag0.String(),
+			UnitTag:    "invalid",
+		}, {
+			StorageTag: "invalid",
+			UnitTag:    unitTag.String(),
+		}, {
+			StorageTag: storageTag1.String(),
+			UnitTag:    unitTag.String(),
+		}, {
+			StorageTag: storageTag0.String(),
+			UnitTag:    names.NewUnitTag("mysql/1").String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageAttachmentResults{
+		Results: []params.StorageAttachmentResult{
+			{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
+			{Error: &params.Error{Code: params.CodeNotValid, Message: `storage tag "invalid" not valid`}},
+			{Error: &params.Error{Message: "badness"}},
+			{Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"}},
 		},
 	})
+	c.Assert(calls, jc.DeepEquals, []names.StorageTag{storageTag1})
 }
 
 type mockStorageState struct {
 	uniter.StorageStateInterface
 	remove                    func(names.StorageTag, names.UnitTag) error
 	ensureDead                func(names.StorageTag, names.UnitTag) error
+	storageAttachment         func(names.StorageTag, names.UnitTag) (state.StorageAttachment, error)
 	storageInstance           func(names.StorageTag) (state.StorageInstance, error)
 	storageInstanceFilesystem func(names.StorageTag) (state.Filesystem, error)
 	storageInstanceVolume     func(names.StorageTag) (state.Volume, error)
@@ -285,6 +335,10 @@ func (m *mockStorageState) RemoveStorageAttachment(s names.StorageTag, u names.U
 	return m.remove(s, u)
 }
 
+func (m *mockStorageState) StorageAttachment(s names.StorageTag, u names.UnitTag) (state.StorageAttachment, error) {
+	return m.storageAttachment(s, u)
+}
+
 func (m *mockStorageState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
 	return m.storageInstance(s)
 }
@@ -475,7 +475,7 @@ func (u *uniterBaseAPI) SetCharmURL(args params.EntitiesCharmURL) (params.ErrorR
 }
 
 // OpenPorts sets the policy of the port range with protocol to be
-// opened, for all given units.
+// opened, to the given source CIDR if any, for all given units.
 func (u *uniterBaseAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -495,7 +495,7 @@ func (u *uniterBaseAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorR
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				err = unit.OpenPortsFrom(entity.Protocol, entity.FromPort, entity.ToPort, entity.SourceCIDR)
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -504,7 +504,7 @@ func (u *uniterBaseAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorR
 }
 
 // ClosePorts sets the policy of the port range with protocol to be
-// closed, for all given units.
+// closed, to the given source CIDR if any, for all given units.
 func (u *uniterBaseAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -524,7 +524,7 @@ func (u *uniterBaseAPI) ClosePorts(args params.EntitiesPortRanges) (params.Error
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				err = unit.ClosePortsFrom(entity.Protocol, entity.FromPort, entity.ToPort, entity.SourceCIDR)
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -688,6 +688,123 @@ func (u *uniterBaseAPI) ConfigSettings(args params.Entities) (params.ConfigSetti
 	return result, nil
 }
 
+// SetObservedConfigSettings records, for each given unit, the config
+// settings observed by its charm in a config-changed hook.
+func (u *uniterBaseAPI) SetObservedConfigSettings(args params.EntitiesConfigSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetObservedConfigSettings(charm.Settings(entity.Settings))
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UpgradeSeriesStatus returns the progress of each given unit in the
+// series upgrade of its machine.
+func (u *uniterBaseAPI) UpgradeSeriesStatus(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				var status state.UpgradeSeriesStatus
+				status, err = unit.UpgradeSeriesStatus()
+				result.Results[i].Result = string(status)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetUpgradeSeriesStatus records the progress of each given unit in
+// the series upgrade of its machine.
+func (u *uniterBaseAPI) SetUpgradeSeriesStatus(args params.SetUpgradeSeriesStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetUpgradeSeriesStatus(state.UpgradeSeriesStatus(entity.Status))
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher, for each
+// given unit, that notifies of changes to the series upgrade of the
+// unit's machine.
+func (u *uniterBaseAPI) WatchUpgradeSeriesNotifications(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneUnitUpgradeSeriesNotifications(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // WatchServiceRelations returns a StringsWatcher, for each given
 // service, that notifies of changes to the lifecycles of relations
 // involving that service.
@@ -892,6 +1009,34 @@ func (u *uniterBaseAPI) FinishActions(args params.ActionExecutionResults) (param
 	return results, nil
 }
 
+// AppendActionsOutput records chunks of the output of running Actions.
+func (u *uniterBaseAPI) AppendActionsOutput(args params.ActionOutputChunks) (params.ErrorResults, error) {
+	nothing := params.ErrorResults{}
+
+	actionFn, err := u.authAndActionFromTagFn()
+	if err != nil {
+		return nothing, err
+	}
+
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Chunks))}
+
+	for i, arg := range args.Chunks {
+		action, err := actionFn(arg.ActionTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+
+		err = action.AppendOutput(arg.Stream, arg.Data)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+	}
+
+	return results, nil
+}
+
 // paramsActionExecutionResultsToStateActionResults does exactly what
 // the name implies.
 func paramsActionExecutionResultsToStateActionResults(arg params.ActionExecutionResult) (state.ActionResults, error) {
@@ -1357,7 +1502,7 @@ func (u *uniterBaseAPI) prepareRelationResult(rel *state.Relation, unit *state.U
 		// relation.
 		return nothing, err
 	}
-	return params.RelationResult{
+	result := params.RelationResult{
 		Id:   rel.Id(),
 		Key:  rel.String(),
 		Life: params.Life(rel.Life().String()),
@@ -1365,7 +1510,11 @@ func (u *uniterBaseAPI) prepareRelationResult(rel *state.Relation, unit *state.U
 			ServiceName: ep.ServiceName,
 			Relation:    ep.Relation,
 		},
-	}, nil
+	}
+	if deadline, draining := rel.DrainDeadline(); draining {
+		result.DrainDeadline = &deadline
+	}
+	return result, nil
 }
 
 func (u *uniterBaseAPI) getOneRelation(canAccess common.AuthFunc, relTag, unitTag string) (params.RelationResult, error) {
@@ -1431,6 +1580,22 @@ func (u *uniterBaseAPI) watchOneUnitConfigSettings(tag names.UnitTag) (string, e
 	return "", watcher.EnsureErr(watch)
 }
 
+func (u *uniterBaseAPI) watchOneUnitUpgradeSeriesNotifications(tag names.UnitTag) (string, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return "", err
+	}
+	watch, err := unit.WatchUpgradeSeriesNotifications()
+	if err != nil {
+		return "", err
+	}
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
 func (u *uniterBaseAPI) watchOneUnitActionNotifications(tag names.UnitTag) (params.StringsWatchResult, error) {
 	nothing := params.StringsWatchResult{}
 	unit, err := u.getUnit(tag)
@@ -835,6 +835,7 @@ func (s *uniterBaseSuite) testOpenPorts(
 	args := params.EntitiesPortRanges{Entities: []params.EntityPortRange{
 		{Tag: "unit-mysql-0", Protocol: "tcp", FromPort: 1234, ToPort: 1400},
 		{Tag: "unit-wordpress-0", Protocol: "udp", FromPort: 4321, ToPort: 5000},
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 443, ToPort: 443, SourceCIDR: "10.0.0.0/8"},
 		{Tag: "unit-foo-42", Protocol: "tcp", FromPort: 42, ToPort: 42},
 	}}
 	result, err := facade.OpenPorts(args)
@@ -843,14 +844,16 @@ func (s *uniterBaseSuite) testOpenPorts(
 		Results: []params.ErrorResult{
 			{apiservertesting.ErrUnauthorized},
 			{nil},
+			{nil},
 			{apiservertesting.ErrUnauthorized},
 		},
 	})
 
-	// Verify the wordpressUnit's port is opened.
+	// Verify the wordpressUnit's ports are opened.
 	openedPorts, err = s.wordpressUnit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(openedPorts, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 443, ToPort: 443, SourceCIDR: "10.0.0.0/8"},
 		{Protocol: "udp", FromPort: 4321, ToPort: 5000},
 	})
 }
@@ -1145,6 +1148,73 @@ func (s *uniterBaseSuite) testConfigSettings(
 	})
 }
 
+func (s *uniterBaseSuite) testSetObservedConfigSettings(
+	c *gc.C,
+	facade interface {
+		SetObservedConfigSettings(args params.EntitiesConfigSettings) (params.ErrorResults, error)
+	},
+) {
+	args := params.EntitiesConfigSettings{Entities: []params.EntityConfigSettings{
+		{Tag: "unit-mysql-0", Settings: params.ConfigSettings{"blog-title": "foo"}},
+		{Tag: "unit-wordpress-0", Settings: params.ConfigSettings{"blog-title": "My Title"}},
+		{Tag: "unit-foo-42", Settings: params.ConfigSettings{"blog-title": "foo"}},
+	}}
+	result, err := facade.SetObservedConfigSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.wordpressUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, observed := s.wordpressUnit.ObservedConfigSettings()
+	c.Assert(observed, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+}
+
+func (s *uniterBaseSuite) testUpgradeSeriesStatus(
+	c *gc.C,
+	facade interface {
+		UpgradeSeriesStatus(args params.Entities) (params.StringResults, error)
+		SetUpgradeSeriesStatus(args params.SetUpgradeSeriesStatus) (params.ErrorResults, error)
+	},
+) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := facade.UpgradeSeriesStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError("series upgrade of machine 0")},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	setResult, err := facade.SetUpgradeSeriesStatus(params.SetUpgradeSeriesStatus{
+		Entities: []params.EntityUpgradeSeriesStatus{
+			{Tag: "unit-mysql-0", Status: params.UpgradeSeriesPrepareCompleted},
+			{Tag: "unit-wordpress-0", Status: params.UpgradeSeriesPrepareCompleted},
+			{Tag: "unit-foo-42", Status: params.UpgradeSeriesPrepareCompleted},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(setResult, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.NotFoundError(`cannot set series upgrade status of unit "wordpress/0": series upgrade of machine 0`)},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterBaseSuite) testWatchServiceRelations(
 	c *gc.C,
 	facade interface {
@@ -1517,6 +1587,35 @@ func (s *uniterBaseSuite) testBeginActions(c *gc.C, facade beginActions) {
 	c.Assert(started.After(enqueued) || started.Equal(enqueued), jc.IsTrue, gc.Commentf("started should be after or equal to enqueued time"))
 }
 
+type appendActionsOutput interface {
+	AppendActionsOutput(args params.ActionOutputChunks) (params.ErrorResults, error)
+}
+
+func (s *uniterBaseSuite) testAppendActionsOutput(c *gc.C, facade appendActionsOutput) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ActionOutputChunks{Chunks: []params.ActionOutputChunk{
+		{ActionTag: good.ActionTag().String(), Stream: "stdout", Data: "some output\n"},
+		{ActionTag: bad.ActionTag().String(), Stream: "stdout", Data: "not mine\n"},
+	}}
+	res, err := facade.AppendActionsOutput(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, common.ErrPerm.Error())
+
+	output, err := good.Output(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 1)
+	c.Assert(output[0].Stream, gc.Equals, state.ActionOutputStdout)
+	c.Assert(output[0].Data, gc.Equals, "some output\n")
+}
+
 func (s *uniterBaseSuite) testRelation(
 	c *gc.C,
 	facade interface {
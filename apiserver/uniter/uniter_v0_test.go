@@ -232,6 +232,10 @@ func (s *uniterV0Suite) TestBeginActions(c *gc.C) {
 	s.testBeginActions(c, s.uniter)
 }
 
+func (s *uniterV0Suite) TestAppendActionsOutput(c *gc.C) {
+	s.testAppendActionsOutput(c, s.uniter)
+}
+
 func (s *uniterV0Suite) TestRelation(c *gc.C) {
 	s.testRelation(c, s.uniter)
 }
@@ -155,10 +155,22 @@ func (u *UniterAPIV1) getOneMachinePorts(canAccess common.AuthFunc, machineTag s
 	if err != nil {
 		return params.MachinePortsResult{Error: common.ServerError(err)}
 	}
-	allPorts, err := machine.AllPorts()
+	resultPorts, err := machinePortRanges(machine)
 	if err != nil {
 		return params.MachinePortsResult{Error: common.ServerError(err)}
 	}
+	return params.MachinePortsResult{
+		Ports: resultPorts,
+	}
+}
+
+// machinePortRanges returns all the port ranges opened on the given
+// machine, ordered by network and port range.
+func machinePortRanges(machine *state.Machine) ([]params.MachinePortRange, error) {
+	allPorts, err := machine.AllPorts()
+	if err != nil {
+		return nil, err
+	}
 	var resultPorts []params.MachinePortRange
 	for _, ports := range allPorts {
 		// AllPortRanges gives a map, but apis require a stable order
@@ -177,7 +189,5 @@ func (u *UniterAPIV1) getOneMachinePorts(canAccess common.AuthFunc, machineTag s
 			})
 		}
 	}
-	return params.MachinePortsResult{
-		Ports: resultPorts,
-	}
+	return resultPorts, nil
 }
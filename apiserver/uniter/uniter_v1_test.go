@@ -177,6 +177,14 @@ func (s *uniterV1Suite) TestConfigSettings(c *gc.C) {
 	s.testConfigSettings(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestSetObservedConfigSettings(c *gc.C) {
+	s.testSetObservedConfigSettings(c, s.uniter)
+}
+
+func (s *uniterV1Suite) TestUpgradeSeriesStatus(c *gc.C) {
+	s.testUpgradeSeriesStatus(c, s.uniter)
+}
+
 func (s *uniterV1Suite) TestWatchServiceRelations(c *gc.C) {
 	s.testWatchServiceRelations(c, s.uniter)
 }
@@ -236,6 +244,10 @@ func (s *uniterV1Suite) TestBeginActions(c *gc.C) {
 	s.testBeginActions(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestAppendActionsOutput(c *gc.C) {
+	s.testAppendActionsOutput(c, s.uniter)
+}
+
 func (s *uniterV1Suite) TestRelation(c *gc.C) {
 	s.testRelation(c, s.uniter)
 }
@@ -412,10 +424,10 @@ func (s *uniterV1Suite) TestAllMachinePorts(c *gc.C) {
 		{Tag: "service-wordpress"},
 	}}
 	expectPorts := []params.MachinePortRange{
-		{UnitTag: "unit-wordpress-0", PortRange: params.PortRange{100, 200, "tcp"}},
-		{UnitTag: "unit-mysql-1", PortRange: params.PortRange{201, 250, "tcp"}},
-		{UnitTag: "unit-mysql-1", PortRange: params.PortRange{1, 8, "udp"}},
-		{UnitTag: "unit-wordpress-0", PortRange: params.PortRange{10, 20, "udp"}},
+		{UnitTag: "unit-wordpress-0", PortRange: params.PortRange{100, 200, "tcp", ""}},
+		{UnitTag: "unit-mysql-1", PortRange: params.PortRange{201, 250, "tcp", ""}},
+		{UnitTag: "unit-mysql-1", PortRange: params.PortRange{1, 8, "udp", ""}},
+		{UnitTag: "unit-wordpress-0", PortRange: params.PortRange{10, 20, "udp", ""}},
 	}
 	result, err := s.uniter.AllMachinePorts(args)
 	c.Assert(err, jc.ErrorIsNil)
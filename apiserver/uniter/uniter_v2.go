@@ -7,7 +7,10 @@
 package uniter
 
 import (
+	"github.com/juju/names"
+
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -36,3 +39,32 @@ func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer com
 		StorageAPI:  *storageAPI,
 	}, nil
 }
+
+// EndpointAddresses returns, for each given unit endpoint, the
+// addresses on which the unit should serve that endpoint.
+func (u *UniterAPIV2) EndpointAddresses(args params.UnitEndpoints) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = unit.EndpointAddresses(entity.Endpoint)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
 
@@ -34,6 +35,39 @@ func (s *uniterV2Suite) SetUpTest(c *gc.C) {
 	s.uniter = uniterAPIV2
 }
 
+func (s *uniterV2Suite) TestSetObservedConfigSettings(c *gc.C) {
+	s.testSetObservedConfigSettings(c, s.uniter)
+}
+
+func (s *uniterV2Suite) TestUpgradeSeriesStatus(c *gc.C) {
+	s.testUpgradeSeriesStatus(c, s.uniter)
+}
+
+func (s *uniterV2Suite) TestEndpointAddresses(c *gc.C) {
+	err := s.machine0.SetAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UnitEndpoints{Entities: []params.UnitEndpoint{
+		{Tag: "unit-mysql-0", Endpoint: "server"},
+		{Tag: "unit-wordpress-0", Endpoint: "db"},
+		{Tag: "unit-wordpress-0", Endpoint: "missing"},
+		{Tag: "unit-foo-42", Endpoint: "db"},
+	}}
+	result, err := s.uniter.EndpointAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: []string{"10.0.0.1"}},
+			{Error: apiservertesting.NotFoundError(`endpoint "missing" of service "wordpress"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterV2Suite) TestStorageAttachments(c *gc.C) {
 	// We need to set up a unit that has storage metadata defined.
 	ch := s.AddTestingCharm(c, "storage-block")
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+type UniterAPIV3 struct {
+	UniterAPIV2
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+	}, nil
+}
+
+// HookContextSnapshot returns, for each given unit, the unit state that
+// the uniter needs to set up a hook context, saving it from making a
+// separate call for each piece of it.
+func (u *UniterAPIV3) HookContextSnapshot(args params.Entities) (params.HookContextSnapshotResults, error) {
+	result := params.HookContextSnapshotResults{
+		Results: make([]params.HookContextSnapshotResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.HookContextSnapshotResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			result.Results[i].Result, err = u.getOneHookContextSnapshot(tag)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) getOneHookContextSnapshot(tag names.UnitTag) (params.HookContextSnapshot, error) {
+	var snapshot params.HookContextSnapshot
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return snapshot, err
+	}
+	if _, ok := unit.CharmURL(); ok {
+		settings, err := unit.ConfigSettings()
+		if err != nil {
+			return snapshot, errors.Annotate(err, "cannot get config settings")
+		}
+		snapshot.ConfigSettings = params.ConfigSettings(settings)
+	}
+	snapshot.RelationTags, err = relationsInScopeTags(unit)
+	if err != nil {
+		return snapshot, errors.Annotate(err, "cannot get relations in scope")
+	}
+	snapshot.PublicAddress, _ = unit.PublicAddress()
+	snapshot.PrivateAddress, _ = unit.PrivateAddress()
+
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return snapshot, errors.Trace(err)
+	}
+	machine, err := u.uniterBaseAPI.st.Machine(machineId)
+	if err != nil {
+		return snapshot, errors.Trace(err)
+	}
+	snapshot.MachinePorts, err = machinePortRanges(machine)
+	if err != nil {
+		return snapshot, errors.Annotate(err, "cannot get machine ports")
+	}
+
+	status, err := unit.GetMeterStatus()
+	if err != nil {
+		return snapshot, errors.Annotate(err, "cannot get meter status")
+	}
+	snapshot.MeterStatusCode = status.Code.String()
+	snapshot.MeterStatusInfo = status.Info
+
+	snapshot.Storage, err = u.getOneUnitStorageAttachments(tag)
+	if err != nil {
+		return snapshot, errors.Annotate(err, "cannot get storage attachments")
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestEndpointAddresses(c *gc.C) {
+	err := s.machine0.SetAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UnitEndpoints{Entities: []params.UnitEndpoint{
+		{Tag: "unit-wordpress-0", Endpoint: "db"},
+	}}
+	result, err := s.uniter.EndpointAddresses(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"10.0.0.1"}},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestHookContextSnapshot(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine0.SetAddresses(
+		network.NewScopedAddress("1.2.3.4", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressUnit.OpenPorts("tcp", 80, 81)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.wordpressUnit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.HookContextSnapshot(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.HookContextSnapshotResults{
+		Results: []params.HookContextSnapshotResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: params.HookContextSnapshot{
+				ConfigSettings: params.ConfigSettings{"blog-title": "My Title"},
+				PublicAddress:  "1.2.3.4",
+				PrivateAddress: "10.0.0.1",
+				MachinePorts: []params.MachinePortRange{{
+					UnitTag:   "unit-wordpress-0",
+					PortRange: params.PortRange{FromPort: 80, ToPort: 81, Protocol: "tcp"},
+				}},
+				MeterStatusCode: status.Code.String(),
+				MeterStatusInfo: status.Info,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestHookContextSnapshotWithoutCharm(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{{Tag: "unit-wordpress-0"}}}
+	result, err := s.uniter.HookContextSnapshot(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.ConfigSettings, gc.IsNil)
+}
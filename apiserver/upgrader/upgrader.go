@@ -103,7 +103,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchForUpgradeChanges()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -149,6 +149,25 @@ func (u *UpgraderAPI) entityIsManager(tag names.Tag) bool {
 	}
 }
 
+// waveAgentVersion returns the version the machine agent with the
+// given tag should run given the environment's agent version, taking
+// into account any upgrade with canaries in progress.
+func (u *UpgraderAPI) waveAgentVersion(tag names.Tag, agentVersion version.Number) (*version.Number, error) {
+	plan, err := u.st.UpgradePlan()
+	if errors.IsNotFound(err) {
+		return &agentVersion, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineTag, ok := tag.(names.MachineTag)
+	if !ok || plan.TargetVersion() != agentVersion || plan.MachineInWave(machineTag.Id()) {
+		return &agentVersion, nil
+	}
+	logger.Debugf("desired version is %s, but %s is not in the %s wave of the upgrade", agentVersion, tag, plan.Wave())
+	previousVersion := plan.PreviousVersion()
+	return &previousVersion, nil
+}
+
 // DesiredVersion reports the Agent Version that we want that agent to be running
 func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
 	results := make([]params.VersionResult, len(args.Entities))
@@ -179,8 +198,18 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
+			//
+			// During an upgrade with canaries, other agents are
+			// only told to upgrade once their machine is in the
+			// current wave of the upgrade.
+			if u.entityIsManager(tag) {
 				results[i].Version = &agentVersion
+			} else if !isNewerVersion {
+				results[i].Version, err = u.waveAgentVersion(tag, agentVersion)
+				if err != nil {
+					results[i].Error = common.ServerError(err)
+					continue
+				}
 			} else {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
 				results[i].Version = &version.Current.Number
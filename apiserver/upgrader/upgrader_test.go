@@ -326,3 +326,60 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) bumpDesiredAgentVersionWithCanaries(c *gc.C, canaries ...string) version.Number {
+	s.apiMachine.SetAgentVersion(version.Current)
+	s.rawMachine.SetAgentVersion(version.Current)
+	newer := version.Current
+	newer.Patch++
+	err := s.State.SetEnvironAgentVersionWithCanaries(newer.Number, canaries)
+	c.Assert(err, jc.ErrorIsNil)
+	// The state servers have already upgraded.
+	s.PatchValue(&version.Current, newer)
+	return newer.Number
+}
+
+func (s *upgraderSuite) assertDesiredVersion(c *gc.C, expected version.Number) {
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	agentVersion := results.Results[0].Version
+	c.Assert(agentVersion, gc.NotNil)
+	c.Check(*agentVersion, gc.DeepEquals, expected)
+}
+
+func (s *upgraderSuite) TestDesiredVersionForCanary(c *gc.C) {
+	newVersion := s.bumpDesiredAgentVersionWithCanaries(c, s.rawMachine.Id())
+	s.assertDesiredVersion(c, newVersion)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldBeforeResume(c *gc.C) {
+	previousVersion := version.Current.Number
+	newVersion := s.bumpDesiredAgentVersionWithCanaries(c, s.apiMachine.Id())
+	s.assertDesiredVersion(c, previousVersion)
+
+	err := s.State.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDesiredVersion(c, newVersion)
+}
+
+func (s *upgraderSuite) TestWatchAPIVersionNoticesResume(c *gc.C) {
+	s.bumpDesiredAgentVersionWithCanaries(c, s.apiMachine.Id())
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err = s.State.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
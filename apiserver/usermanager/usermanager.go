@@ -17,6 +17,7 @@ var logger = loggo.GetLogger("juju.apiserver.usermanager")
 
 func init() {
 	common.RegisterStandardFacade("UserManager", 0, NewUserManagerAPI)
+	common.RegisterStandardFacade("UserManager", 1, NewUserManagerAPIV1)
 }
 
 // UserManager defines the methods on the usermanager API end point.
@@ -28,6 +29,13 @@ type UserManager interface {
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
 }
 
+// UserManagerV1 defines the methods on version 1 of the usermanager
+// API end point.
+type UserManagerV1 interface {
+	UserManager
+	ShareEnvironment(args params.ModifyEnvironAccessRequest) (params.ErrorResults, error)
+}
+
 // UserManagerAPI implements the user manager interface and is the concrete
 // implementation of the api end point.
 type UserManagerAPI struct {
@@ -38,6 +46,15 @@ type UserManagerAPI struct {
 
 var _ UserManager = (*UserManagerAPI)(nil)
 
+// UserManagerAPIV1 implements version 1 of the usermanager API end
+// point, which adds ShareEnvironment. Earlier API servers share
+// environments through the Client facade.
+type UserManagerAPIV1 struct {
+	*UserManagerAPI
+}
+
+var _ UserManagerV1 = (*UserManagerAPIV1)(nil)
+
 func NewUserManagerAPI(
 	st *state.State,
 	resources *common.Resources,
@@ -54,6 +71,20 @@ func NewUserManagerAPI(
 	}, nil
 }
 
+// NewUserManagerAPIV1 returns version 1 of the usermanager API end
+// point.
+func NewUserManagerAPIV1(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*UserManagerAPIV1, error) {
+	apiV0, err := NewUserManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV1{apiV0}, nil
+}
+
 func (api *UserManagerAPI) permissionCheck(user names.UserTag) error {
 	// TODO(thumper): PERMISSIONS Change this permission check when we have
 	// real permissions. For now, only the owner of the initial environment is
@@ -91,7 +122,11 @@ func (api *UserManagerAPI) AddUser(args params.AddUsers) (params.AddUserResults,
 		return result, errors.Trace(err)
 	}
 	for i, arg := range args.Users {
-		user, err := api.state.AddUser(arg.Username, arg.DisplayName, arg.Password, loggedInUser.Id())
+		access := state.UserAccess(arg.Access)
+		if access == "" {
+			access = state.UserAccessWrite
+		}
+		user, err := api.state.AddUserWithAccess(arg.Username, arg.DisplayName, arg.Password, loggedInUser.Id(), access)
 		if err != nil {
 			err = errors.Annotate(err, "failed to create user")
 			result.Results[i].Error = common.ServerError(err)
@@ -248,6 +283,83 @@ func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.Erro
 	return result, nil
 }
 
+// ShareEnvironment grants users access to environments, or revokes it.
+// Only the owner of an environment, or of the state server environment,
+// may change who has access to it.
+func (api *UserManagerAPIV1) ShareEnvironment(args params.ModifyEnvironAccessRequest) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	serverAdmin := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Changes {
+		if err := api.shareEnvironment(loggedInUser, serverAdmin, arg); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) shareEnvironment(loggedInUser names.UserTag, serverAdmin bool, arg params.ModifyEnvironAccess) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Annotate(err, "could not modify environment access")
+	}
+	st := api.state
+	if arg.EnvironTag != "" {
+		envTag, err := names.ParseEnvironTag(arg.EnvironTag)
+		if err != nil {
+			return errors.Annotate(err, "could not modify environment access")
+		}
+		if envTag != st.EnvironTag() {
+			if st, err = api.state.ForEnviron(envTag); err != nil {
+				return errors.Trace(err)
+			}
+			defer st.Close()
+		}
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !serverAdmin && env.Owner() != loggedInUser {
+		return common.ErrPerm
+	}
+
+	switch arg.Action {
+	case params.AddEnvUser:
+		access := state.UserAccess(arg.Access)
+		if access == "" {
+			access = state.UserAccessWrite
+		}
+		_, err := st.AddEnvironmentUserWithAccess(user, loggedInUser, "", access)
+		if errors.IsAlreadyExists(err) {
+			// Sharing the environment with an existing user changes
+			// their access level.
+			var envUser *state.EnvironmentUser
+			if envUser, err = st.EnvironmentUser(user); err == nil {
+				err = envUser.SetAccess(access)
+			}
+		}
+		return errors.Annotate(err, "could not share environment")
+	case params.RemoveEnvUser:
+		if user == env.Owner() {
+			return errors.New("could not unshare environment: cannot remove the environment owner")
+		}
+		return errors.Annotate(st.RemoveEnvironmentUser(user), "could not unshare environment")
+	}
+	return errors.Errorf("unknown action %q", arg.Action)
+}
+
 func (api *UserManagerAPI) getLoggedInUser() (names.UserTag, error) {
 	switch tag := api.authorizer.GetAuthTag().(type) {
 	case names.UserTag:
@@ -20,7 +20,7 @@ import (
 type userManagerSuite struct {
 	jujutesting.JujuConnSuite
 
-	usermanager *usermanager.UserManagerAPI
+	usermanager *usermanager.UserManagerAPIV1
 	authorizer  apiservertesting.FakeAuthorizer
 	adminName   string
 
@@ -38,7 +38,7 @@ func (s *userManagerSuite) SetUpTest(c *gc.C) {
 		Tag: adminTag,
 	}
 	var err error
-	s.usermanager, err = usermanager.NewUserManagerAPI(s.State, nil, s.authorizer)
+	s.usermanager, err = usermanager.NewUserManagerAPIV1(s.State, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
@@ -76,6 +76,29 @@ func (s *userManagerSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.DisplayName(), gc.Equals, "Foo Bar")
 }
 
+func (s *userManagerSuite) TestAddReadOnlyUser(c *gc.C) {
+	args := params.AddUsers{
+		Users: []params.AddUser{{
+			Username: "auditor",
+			Password: "password",
+			Access:   "read",
+		}, {
+			Username: "bogus",
+			Password: "password",
+			Access:   "superuser",
+		}}}
+
+	result, err := s.usermanager.AddUser(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `failed to create user: user access "superuser" not valid`)
+
+	user, err := s.State.User(names.NewLocalUserTag("auditor"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsReadOnly(), jc.IsTrue)
+}
+
 func (s *userManagerSuite) TestBlockAddUser(c *gc.C) {
 	args := params.AddUsers{
 		Users: []params.AddUser{{
@@ -481,3 +504,119 @@ func (s *userManagerSuite) TestSetPasswordForOther(c *gc.C) {
 
 	c.Assert(barb.PasswordValid("new-password"), jc.IsFalse)
 }
+
+func (s *userManagerSuite) TestShareEnvironment(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoEnvUser: true})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb", NoEnvUser: true})
+
+	args := params.ModifyEnvironAccessRequest{
+		Changes: []params.ModifyEnvironAccess{{
+			UserTag: alex.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "read",
+		}, {
+			UserTag: barb.Tag().String(),
+			Action:  params.AddEnvUser,
+		}, {
+			UserTag: "not-a-tag",
+			Action:  params.AddEnvUser,
+		}}}
+	results, err := s.usermanager.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `could not modify environment access: "not-a-tag" is not a valid .*tag`)
+
+	envUser, err := s.State.EnvironmentUser(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+	envUser, err = s.State.EnvironmentUser(barb.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsFalse)
+
+	// Sharing again changes the access level.
+	args = params.ModifyEnvironAccessRequest{
+		Changes: []params.ModifyEnvironAccess{{
+			UserTag: alex.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "write",
+		}}}
+	results, err = s.usermanager.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	envUser, err = s.State.EnvironmentUser(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestUnshareEnvironment(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	args := params.ModifyEnvironAccessRequest{
+		Changes: []params.ModifyEnvironAccess{{
+			UserTag: alex.Tag().String(),
+			Action:  params.RemoveEnvUser,
+		}, {
+			UserTag: s.AdminUserTag(c).String(),
+			Action:  params.RemoveEnvUser,
+		}}}
+	results, err := s.usermanager.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "could not unshare environment: cannot remove the environment owner")
+
+	_, err = s.State.EnvironmentUser(alex.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestShareHostedEnvironment(c *gc.C) {
+	owner := s.Factory.MakeUser(c, &factory.UserParams{Name: "owner"})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner.Tag()})
+	defer st.Close()
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoEnvUser: true})
+
+	// The owner of the hosted environment may share it, even though
+	// they do not own the state server environment.
+	usermanager, err := usermanager.NewUserManagerAPIV1(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: owner.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.ModifyEnvironAccessRequest{
+		Changes: []params.ModifyEnvironAccess{{
+			UserTag:    alex.Tag().String(),
+			Action:     params.AddEnvUser,
+			Access:     "read",
+			EnvironTag: st.EnvironTag().String(),
+		}}}
+	results, err := usermanager.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	envUser, err := st.EnvironmentUser(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+	_, err = s.State.EnvironmentUser(alex.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestShareEnvironmentAsNonOwner(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb", NoEnvUser: true})
+	usermanager, err := usermanager.NewUserManagerAPIV1(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ModifyEnvironAccessRequest{
+		Changes: []params.ModifyEnvironAccess{{
+			UserTag: barb.Tag().String(),
+			Action:  params.AddEnvUser,
+		}}}
+	results, err := usermanager.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0], gc.DeepEquals, params.ErrorResult{
+		Error: &params.Error{
+			Message: "permission denied",
+			Code:    params.CodeUnauthorized,
+		}})
+}
@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -60,10 +61,28 @@ type srvClientAllWatcher struct {
 	watcher   *state.Multiwatcher
 	id        string
 	resources *common.Resources
+
+	// lastNext holds the time at which Next last returned.
+	lastNext time.Time
 }
 
+// allWatcherBatchInterval holds the minimum time between the replies
+// to successive AllWatcher.Next calls.
+var allWatcherBatchInterval = 250 * time.Millisecond
+
 func (aw *srvClientAllWatcher) Next() (params.AllWatcherNextResults, error) {
+	// Deltas are batched by leaving at least allWatcherBatchInterval
+	// between replies: the multiwatcher accumulates the changes made
+	// in the meantime, collapsing several changes to an entity into a
+	// single delta. In large environments this saves sending the same
+	// entity many times over while it settles.
+	if !aw.lastNext.IsZero() {
+		if wait := allWatcherBatchInterval - time.Since(aw.lastNext); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
 	deltas, err := aw.watcher.Next()
+	aw.lastNext = time.Now()
 	return params.AllWatcherNextResults{
 		Deltas: deltas,
 	}, err
@@ -0,0 +1,257 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("WatcherMultiplexer", 1, newWatcherMultiplexerAPI)
+}
+
+// WatcherMultiplexerAPI lets an agent receive the changes reported by
+// many of its NotifyWatchers and StringsWatchers through a single
+// outstanding Next call, rather than one per watcher.
+type WatcherMultiplexerAPI struct {
+	mux       *watcherMultiplexer
+	resources *common.Resources
+}
+
+// watcherMultiplexerResource holds the name under which a connection's
+// watcherMultiplexer is registered in its resources.
+const watcherMultiplexerResource = "watcherMultiplexer"
+
+func newWatcherMultiplexerAPI(st *state.State, resources *common.Resources, auth common.Authorizer) (*WatcherMultiplexerAPI, error) {
+	if !isAgent(auth) {
+		return nil, common.ErrPerm
+	}
+	// Each connection has a single multiplexer, which is created when
+	// it is first used.
+	mux, ok := resources.Get(watcherMultiplexerResource).(*watcherMultiplexer)
+	if !ok {
+		mux = newWatcherMultiplexer()
+		if err := resources.RegisterNamed(watcherMultiplexerResource, mux); err != nil {
+			// Another call registered one first.
+			mux.Stop()
+			if mux, ok = resources.Get(watcherMultiplexerResource).(*watcherMultiplexer); !ok {
+				return nil, err
+			}
+		}
+	}
+	return &WatcherMultiplexerAPI{
+		mux:       mux,
+		resources: resources,
+	}, nil
+}
+
+// Add starts multiplexing the changes of the given watchers, which
+// must have been returned by earlier calls on the same connection.
+// Their changes are no longer returned by NotifyWatcher.Next or
+// StringsWatcher.Next, but they are still stopped as usual.
+func (api *WatcherMultiplexerAPI) Add(args params.MultiplexWatcherIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.NotifyWatcherIds)+len(args.StringsWatcherIds)),
+	}
+	for i, id := range args.NotifyWatcherIds {
+		w, ok := api.resources.Get(id).(state.NotifyWatcher)
+		if !ok {
+			result.Results[i].Error = common.ServerError(common.ErrUnknownWatcher)
+			continue
+		}
+		result.Results[i].Error = common.ServerError(api.mux.addNotifyWatcher(id, w))
+	}
+	offset := len(args.NotifyWatcherIds)
+	for i, id := range args.StringsWatcherIds {
+		w, ok := api.resources.Get(id).(state.StringsWatcher)
+		if !ok {
+			result.Results[offset+i].Error = common.ServerError(common.ErrUnknownWatcher)
+			continue
+		}
+		result.Results[offset+i].Error = common.ServerError(api.mux.addStringsWatcher(id, w))
+	}
+	return result, nil
+}
+
+// Next returns when any of the multiplexed watchers has changed since
+// the most recent call to Next, or the Add call that added it. The
+// changes are returned in the order they happened; a StringsWatcher
+// may appear once for each of its events, while successive changes to
+// a NotifyWatcher are reported once. A watcher that has stopped
+// reports the error it stopped with.
+func (api *WatcherMultiplexerAPI) Next() (params.MultiplexedWatcherChanges, error) {
+	changes, err := api.mux.next()
+	return params.MultiplexedWatcherChanges{Changes: changes}, err
+}
+
+// watcherMultiplexer collects the changes of a connection's multiplexed
+// watchers until they are returned by WatcherMultiplexerAPI.Next.
+type watcherMultiplexer struct {
+	tomb tomb.Tomb
+	wg   sync.WaitGroup
+
+	// ready receives a value when a change is recorded.
+	ready chan struct{}
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// watching holds the ids of the watchers being forwarded.
+	watching map[string]bool
+
+	// pending holds the changes not yet returned by next.
+	pending []params.MultiplexedWatcherChange
+
+	// notifyPending holds the ids of the NotifyWatchers with a change
+	// in pending.
+	notifyPending map[string]bool
+}
+
+func newWatcherMultiplexer() *watcherMultiplexer {
+	m := &watcherMultiplexer{
+		ready:         make(chan struct{}, 1),
+		watching:      make(map[string]bool),
+		notifyPending: make(map[string]bool),
+	}
+	go func() {
+		defer m.tomb.Done()
+		<-m.tomb.Dying()
+		m.wg.Wait()
+	}()
+	return m
+}
+
+// Stop is part of the common.Resource interface.
+func (m *watcherMultiplexer) Stop() error {
+	m.tomb.Kill(nil)
+	return m.tomb.Wait()
+}
+
+// startForwarding records that the watcher with the given id is being
+// forwarded, reporting whether a forwarder should be started for it.
+func (m *watcherMultiplexer) startForwarding(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case <-m.tomb.Dying():
+		return false, common.ErrStoppedWatcher
+	default:
+	}
+	if m.watching[id] {
+		return false, nil
+	}
+	m.watching[id] = true
+	m.wg.Add(1)
+	return true, nil
+}
+
+func (m *watcherMultiplexer) addNotifyWatcher(id string, w state.NotifyWatcher) error {
+	if start, err := m.startForwarding(id); !start {
+		return err
+	}
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case <-m.tomb.Dying():
+				return
+			case _, ok := <-w.Changes():
+				if !ok {
+					m.stopped(id, w)
+					return
+				}
+				m.recordNotify(id)
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *watcherMultiplexer) addStringsWatcher(id string, w state.StringsWatcher) error {
+	if start, err := m.startForwarding(id); !start {
+		return err
+	}
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case <-m.tomb.Dying():
+				return
+			case changes, ok := <-w.Changes():
+				if !ok {
+					m.stopped(id, w)
+					return
+				}
+				m.recordStrings(id, changes)
+			}
+		}
+	}()
+	return nil
+}
+
+// recordNotify records a change to the NotifyWatcher with the given id.
+func (m *watcherMultiplexer) recordNotify(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.notifyPending[id] {
+		m.notifyPending[id] = true
+		m.record(params.MultiplexedWatcherChange{WatcherId: id})
+	}
+}
+
+// recordStrings records an event of the StringsWatcher with the given id.
+func (m *watcherMultiplexer) recordStrings(id string, changes []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.record(params.MultiplexedWatcherChange{WatcherId: id, Changes: changes})
+}
+
+// stopped records that the watcher with the given id has stopped.
+func (m *watcherMultiplexer) stopped(id string, w watcher.Errer) {
+	err := w.Err()
+	if err == nil {
+		err = common.ErrStoppedWatcher
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.watching, id)
+	m.record(params.MultiplexedWatcherChange{WatcherId: id, Error: common.ServerError(err)})
+}
+
+// record adds the given change to those pending, and wakes a waiting
+// call to next. It must be called with mu held.
+func (m *watcherMultiplexer) record(change params.MultiplexedWatcherChange) {
+	m.pending = append(m.pending, change)
+	select {
+	case m.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for any changes to be recorded, and returns them.
+func (m *watcherMultiplexer) next() ([]params.MultiplexedWatcherChange, error) {
+	for {
+		m.mu.Lock()
+		if len(m.pending) > 0 {
+			changes := m.pending
+			m.pending = nil
+			m.notifyPending = make(map[string]bool)
+			m.mu.Unlock()
+			return changes, nil
+		}
+		m.mu.Unlock()
+		select {
+		case <-m.ready:
+		case <-m.tomb.Dying():
+			return nil, common.ErrStoppedWatcher
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type watcherMultiplexerSuite struct {
+	testing.JujuConnSuite
+
+	st      *api.State
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&watcherMultiplexerSuite{})
+
+func (s *watcherMultiplexerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.st, s.machine = s.OpenAPIAsNewMachine(c)
+}
+
+func (s *watcherMultiplexerSuite) watchMachine(c *gc.C) string {
+	var results params.NotifyWatchResults
+	args := params.Entities{Entities: []params.Entity{{Tag: s.machine.Tag().String()}}}
+	err := s.st.APICall("Machiner", s.st.BestFacadeVersion("Machiner"), "", "Watch", args, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	return results.Results[0].NotifyWatcherId
+}
+
+func (s *watcherMultiplexerSuite) call(method string, args, result interface{}) error {
+	return s.st.APICall("WatcherMultiplexer", s.st.BestFacadeVersion("WatcherMultiplexer"), "", method, args, result)
+}
+
+func (s *watcherMultiplexerSuite) add(c *gc.C, args params.MultiplexWatcherIds) params.ErrorResults {
+	var results params.ErrorResults
+	err := s.call("Add", args, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	return results
+}
+
+// next calls Next, failing the test if it does not return in time.
+func (s *watcherMultiplexerSuite) next(c *gc.C) []params.MultiplexedWatcherChange {
+	type nextResult struct {
+		result params.MultiplexedWatcherChanges
+		err    error
+	}
+	done := make(chan nextResult, 1)
+	go func() {
+		var result params.MultiplexedWatcherChanges
+		err := s.call("Next", nil, &result)
+		done <- nextResult{result, err}
+	}()
+	select {
+	case r := <-done:
+		c.Assert(r.err, jc.ErrorIsNil)
+		return r.result.Changes
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for watcher changes")
+	}
+	panic("unreachable")
+}
+
+func (s *watcherMultiplexerSuite) TestRequiresAgent(c *gc.C) {
+	var results params.ErrorResults
+	err := s.APIState.APICall("WatcherMultiplexer", s.APIState.BestFacadeVersion("WatcherMultiplexer"), "", "Add", params.MultiplexWatcherIds{}, &results)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *watcherMultiplexerSuite) TestAddUnknownWatchers(c *gc.C) {
+	id := s.watchMachine(c)
+	results := s.add(c, params.MultiplexWatcherIds{
+		NotifyWatcherIds:  []string{"42", id},
+		StringsWatcherIds: []string{id},
+	})
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: "unknown watcher id", Code: params.CodeNotFound}},
+			{},
+			{Error: &params.Error{Message: "unknown watcher id", Code: params.CodeNotFound}},
+		},
+	})
+}
+
+func (s *watcherMultiplexerSuite) TestNextReturnsChanges(c *gc.C) {
+	id := s.watchMachine(c)
+	results := s.add(c, params.MultiplexWatcherIds{NotifyWatcherIds: []string{id}})
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	err := s.machine.SetProvisioned(instance.Id("i-manager"), "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	changes := s.next(c)
+	c.Assert(changes, gc.DeepEquals, []params.MultiplexedWatcherChange{{WatcherId: id}})
+}
+
+func (s *watcherMultiplexerSuite) TestNextReportsStoppedWatchers(c *gc.C) {
+	id := s.watchMachine(c)
+	results := s.add(c, params.MultiplexWatcherIds{NotifyWatcherIds: []string{id}})
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	err := s.st.APICall("NotifyWatcher", s.st.BestFacadeVersion("NotifyWatcher"), id, "Stop", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	changes := s.next(c)
+	c.Assert(changes, gc.HasLen, 1)
+	c.Assert(changes[0].WatcherId, gc.Equals, id)
+	c.Assert(changes[0].Error, jc.Satisfies, params.IsCodeStopped)
+}
@@ -85,6 +85,37 @@ func GetDefaultEnvironment() (string, error) {
 	return envs.Default, nil
 }
 
+// ControllerName returns the name of the controller that runs the named
+// environment, as recorded in the given store. An environment whose
+// controller is not recorded, such as one only described by a JENV file,
+// is taken to be its own controller.
+func ControllerName(store configstore.Storage, envName string) (string, error) {
+	controllers, ok := store.(configstore.ControllerStore)
+	if !ok {
+		return envName, nil
+	}
+	env, err := controllers.EnvironmentByName(envName)
+	if errors.IsNotFound(err) {
+		return envName, nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return env.Controller, nil
+}
+
+// CurrentController returns the name of the controller that runs the
+// current environment, as returned by GetDefaultEnvironment.
+func CurrentController(store configstore.Storage) (string, error) {
+	envName, err := GetDefaultEnvironment()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if envName == "" {
+		return "", errors.Trace(ErrNoEnvironmentSpecified)
+	}
+	return ControllerName(store, envName)
+}
+
 // EnvironCommand extends cmd.Command with a SetEnvName method.
 type EnvironCommand interface {
 	cmd.Command
@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -82,6 +83,31 @@ func (s *EnvironmentCommandSuite) TestGetDefaultEnvironmentBothSet(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *EnvironmentCommandSuite) TestCurrentController(c *gc.C) {
+	s.SetFeatureFlags(feature.EnvironmentsCacheFile)
+	store, err := configstore.Default()
+	c.Assert(err, jc.ErrorIsNil)
+	info := store.CreateInfo("hosted")
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		EnvironUUID: "hosted-uuid",
+		ServerUUID:  "server-uuid",
+	})
+	err = info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An environment not recorded in the controllers files is its own
+	// controller.
+	controller, err := envcmd.CurrentController(store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controller, gc.Equals, "erewhemos")
+
+	err = envcmd.WriteCurrentEnvironment("hosted")
+	c.Assert(err, jc.ErrorIsNil)
+	controller, err = envcmd.CurrentController(store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controller, gc.Equals, "server-uuid")
+}
+
 func (s *EnvironmentCommandSuite) TestWriteAddsNewline(c *gc.C) {
 	err := envcmd.WriteCurrentEnvironment("fubar")
 	c.Assert(err, jc.ErrorIsNil)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/api/action"
@@ -35,7 +36,9 @@ func NewSuperCommand() cmd.Command {
 	actionCmd.Register(envcmd.Wrap(&DefinedCommand{}))
 	actionCmd.Register(envcmd.Wrap(&DoCommand{}))
 	actionCmd.Register(envcmd.Wrap(&FetchCommand{}))
+	actionCmd.Register(envcmd.Wrap(&SchedulesCommand{}))
 	actionCmd.Register(envcmd.Wrap(&StatusCommand{}))
+	actionCmd.Register(envcmd.Wrap(&UnscheduleCommand{}))
 	return actionCmd
 }
 
@@ -49,6 +52,11 @@ type APIClient interface {
 	// Action.
 	Enqueue(params.Actions) (params.ActionResults, error)
 
+	// EnqueueAll takes a list of Actions whose receivers are services,
+	// and queues each up on all the units of its service, on no more
+	// than the Action's Parallelism of them at once.
+	EnqueueAll(params.Actions) (params.ServiceActionResults, error)
+
 	// ListAll takes a list of Tags representing ActionReceivers and returns
 	// all of the Actions that have been queued or run by each of those
 	// Entities.
@@ -78,6 +86,17 @@ type APIClient interface {
 	// FindActionTagsByPrefix takes a list of string prefixes and finds
 	// corresponding ActionTags that match that prefix.
 	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
+
+	// WatchActionOutput returns a watcher that reports the output of
+	// the action as it is written.
+	WatchActionOutput(names.ActionTag) (action.OutputWatcher, error)
+
+	// ListSchedules returns the Actions that are scheduled to be
+	// queued up later.
+	ListSchedules() (params.ActionResults, error)
+
+	// RemoveSchedules cancels the ActionSchedules with the given ids.
+	RemoveSchedules(params.ActionScheduleIds) (params.ErrorResults, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
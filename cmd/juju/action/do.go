@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -24,9 +25,15 @@ var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
 type DoCommand struct {
 	ActionCommandBase
 	unitTag      names.UnitTag
+	serviceTag   names.ServiceTag
+	all          bool
+	parallel     int
 	actionName   string
 	paramsYAML   cmd.FileVar
 	parseStrings bool
+	at           string
+	atTime       time.Time
+	schedule     string
 	out          cmd.Output
 	args         [][]string
 }
@@ -47,6 +54,16 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+The Action may instead be scheduled, to be queued up once at the time
+given with --at, or repeatedly on the cron schedule given with --schedule.
+Scheduled Actions can be seen with "juju action schedules" and cancelled
+with "juju action unschedule".
+
+With --all, the first argument names a service rather than a unit, and the
+Action is queued up on all of the service's units. With --parallel as well,
+it runs on no more than the given number of units at once, and on each of
+the remaining units in turn as those finish.
+
 Examples:
 
 $ juju action do mysql/3 backup 
@@ -97,6 +114,18 @@ $ juju action do sleeper/0 pause time=1000
 $ juju action do sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju action do mysql/3 backup --at 2015-06-01T02:00:00Z
+...
+The Action will be queued up at 02:00 UTC on the 1st of June 2015.
+
+$ juju action do mysql/3 backup --schedule "30 2 * * *"
+...
+The Action will be queued up at 02:30 every day.
+
+$ juju action do --all --parallel 2 mysql backup
+...
+The Action will run on two of the mysql units at a time.
 `
 
 // actionNameRule describes the format an action name must match to be valid.
@@ -107,12 +136,16 @@ func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.StringVar(&c.at, "at", "", "queue the action once, at the given RFC 3339 time")
+	f.StringVar(&c.schedule, "schedule", "", "queue the action repeatedly, on the given cron schedule")
+	f.BoolVar(&c.all, "all", false, "queue the action on all units of the given service")
+	f.IntVar(&c.parallel, "parallel", 0, "with --all, the number of units on which the action may run at once")
 }
 
 func (c *DoCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "do",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "<unit>|--all <service> <action name> [key.key.key...=value]",
 		Purpose: "queue an action for execution",
 		Doc:     doDoc,
 	}
@@ -120,22 +153,52 @@ func (c *DoCommand) Info() *cmd.Info {
 
 // Init gets the unit tag, and checks for other correct args.
 func (c *DoCommand) Init(args []string) error {
+	if c.at != "" && c.schedule != "" {
+		return errors.New("--at and --schedule cannot be combined")
+	}
+	if c.all && (c.at != "" || c.schedule != "") {
+		return errors.New("--all cannot be combined with --at or --schedule")
+	}
+	if c.parallel != 0 && !c.all {
+		return errors.New("--parallel requires --all")
+	}
+	if c.parallel < 0 {
+		return errors.Errorf("invalid --parallel %d: must not be negative", c.parallel)
+	}
+	if c.at != "" {
+		at, err := time.Parse(time.RFC3339, c.at)
+		if err != nil {
+			return errors.Errorf("invalid time %q: expected RFC 3339 format", c.at)
+		}
+		c.atTime = at
+	}
 	switch len(args) {
 	case 0:
+		if c.all {
+			return errors.New("no service specified")
+		}
 		return errors.New("no unit specified")
 	case 1:
 		return errors.New("no action specified")
 	default:
-		// Grab and verify the unit and action names.
-		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
-			return errors.Errorf("invalid unit name %q", unitName)
+		// Grab and verify the unit (or service) and action names.
+		if c.all {
+			serviceName := args[0]
+			if !names.IsValidService(serviceName) {
+				return errors.Errorf("invalid service name %q", serviceName)
+			}
+			c.serviceTag = names.NewServiceTag(serviceName)
+		} else {
+			unitName := args[0]
+			if !names.IsValidUnit(unitName) {
+				return errors.Errorf("invalid unit name %q", unitName)
+			}
+			c.unitTag = names.NewUnitTag(unitName)
 		}
 		actionName := args[1]
 		if valid := actionNameRule.MatchString(actionName); !valid {
 			return fmt.Errorf("invalid action name %q", actionName)
 		}
-		c.unitTag = names.NewUnitTag(unitName)
 		c.actionName = actionName
 		if len(args) == 2 {
 			return nil
@@ -228,6 +291,17 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 			Parameters: actionParams,
 		}},
 	}
+	if c.at != "" || c.schedule != "" {
+		actionParam.Actions[0].Schedule = &params.ActionSchedule{
+			At:   c.atTime,
+			Cron: c.schedule,
+		}
+	}
+	if c.all {
+		actionParam.Actions[0].Receiver = c.serviceTag.String()
+		actionParam.Actions[0].Parallelism = c.parallel
+		return c.enqueueAll(ctx, api, actionParam)
+	}
 
 	results, err := api.Enqueue(actionParam)
 	if err != nil {
@@ -247,6 +321,14 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 		return errors.New("action failed to enqueue")
 	}
 
+	if sched := result.Action.Schedule; sched != nil {
+		output := map[string]string{
+			"Action scheduled with id": sched.Id,
+			"Next run":                 sched.NextRun.Format(time.RFC3339),
+		}
+		return c.out.Write(ctx, output)
+	}
+
 	tag, err := names.ParseActionTag(result.Action.Tag)
 	if err != nil {
 		return err
@@ -255,3 +337,48 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 	output := map[string]string{"Action queued with id": tag.Id()}
 	return c.out.Write(ctx, output)
 }
+
+// enqueueAll queues up the Action on all the units of the service,
+// reporting the ids of the Actions queued up and the units that are
+// waiting their turn.
+func (c *DoCommand) enqueueAll(ctx *cmd.Context, api APIClient, actionParam params.Actions) error {
+	results, err := api.EnqueueAll(actionParam)
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	queued := make(map[string]string)
+	for _, actionResult := range result.Actions {
+		if actionResult.Action == nil {
+			return errors.New("action failed to enqueue")
+		}
+		actionTag, err := names.ParseActionTag(actionResult.Action.Tag)
+		if err != nil {
+			return err
+		}
+		unitTag, err := names.ParseUnitTag(actionResult.Action.Receiver)
+		if err != nil {
+			return err
+		}
+		queued[unitTag.Id()] = actionTag.Id()
+	}
+	output := map[string]interface{}{"Actions queued with ids": queued}
+	if len(result.Waiting) > 0 {
+		var waiting []string
+		for _, tag := range result.Waiting {
+			unitTag, err := names.ParseUnitTag(tag)
+			if err != nil {
+				return err
+			}
+			waiting = append(waiting, unitTag.Id())
+		}
+		output["Units waiting"] = waiting
+	}
+	return c.out.Write(ctx, output)
+}
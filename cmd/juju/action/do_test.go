@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/names"
@@ -398,3 +399,116 @@ func (s *DoSuite) TestRun(c *gc.C) {
 		}()
 	}
 }
+
+func (s *DoSuite) TestInitSchedule(c *gc.C) {
+	subcommand := &action.DoCommand{}
+	err := testing.InitCommand(subcommand, []string{validUnitId, "backup", "--at", "2015-06-01T02:00:00Z"})
+	c.Assert(err, jc.ErrorIsNil)
+	at, cron := subcommand.Schedule()
+	c.Check(at, gc.DeepEquals, time.Date(2015, time.June, 1, 2, 0, 0, 0, time.UTC))
+	c.Check(cron, gc.Equals, "")
+
+	subcommand = &action.DoCommand{}
+	err = testing.InitCommand(subcommand, []string{validUnitId, "backup", "--schedule", "@daily"})
+	c.Assert(err, jc.ErrorIsNil)
+	at, cron = subcommand.Schedule()
+	c.Check(at.IsZero(), jc.IsTrue)
+	c.Check(cron, gc.Equals, "@daily")
+
+	err = testing.InitCommand(&action.DoCommand{}, []string{validUnitId, "backup", "--at", "tomorrow"})
+	c.Check(err, gc.ErrorMatches, `invalid time "tomorrow": expected RFC 3339 format`)
+
+	err = testing.InitCommand(&action.DoCommand{}, []string{validUnitId, "backup", "--at", "2015-06-01T02:00:00Z", "--schedule", "@daily"})
+	c.Check(err, gc.ErrorMatches, "--at and --schedule cannot be combined")
+}
+
+func (s *DoSuite) TestRunScheduled(c *gc.C) {
+	nextRun := time.Date(2015, time.June, 2, 0, 0, 0, 0, time.UTC)
+	fakeClient := &fakeAPIClient{
+		actionResults: []params.ActionResult{{
+			Action: &params.Action{
+				Schedule: &params.ActionSchedule{Id: "some-id", Cron: "@daily", NextRun: nextRun},
+			},
+			Status: params.ActionScheduled,
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.DoCommand{}, validUnitId, "some-action", "--schedule", "@daily")
+	c.Assert(err, jc.ErrorIsNil)
+	resultMap := make(map[string]string)
+	err = yaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &resultMap)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resultMap, jc.DeepEquals, map[string]string{
+		"Action scheduled with id": "some-id",
+		"Next run":                 "2015-06-02T00:00:00Z",
+	})
+	enqueued := fakeClient.EnqueuedActions()
+	c.Assert(enqueued.Actions, gc.HasLen, 1)
+	c.Check(enqueued.Actions[0].Schedule, jc.DeepEquals, &params.ActionSchedule{Cron: "@daily"})
+}
+
+func (s *DoSuite) TestInitAll(c *gc.C) {
+	subcommand := &action.DoCommand{}
+	err := testing.InitCommand(subcommand, []string{"--all", "--parallel", "2", "mysql", "backup"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subcommand.ServiceTag(), gc.Equals, names.NewServiceTag("mysql"))
+	c.Check(subcommand.Parallel(), gc.Equals, 2)
+	c.Check(subcommand.ActionName(), gc.Equals, "backup")
+
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--all"},
+		err:  "no service specified",
+	}, {
+		args: []string{"--all", "mysql/0", "backup"},
+		err:  `invalid service name "mysql/0"`,
+	}, {
+		args: []string{"--parallel", "2", validUnitId, "backup"},
+		err:  "--parallel requires --all",
+	}, {
+		args: []string{"--all", "--parallel", "-1", "mysql", "backup"},
+		err:  "invalid --parallel -1: must not be negative",
+	}, {
+		args: []string{"--all", "--schedule", "@daily", "mysql", "backup"},
+		err:  "--all cannot be combined with --at or --schedule",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&action.DoCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *DoSuite) TestRunAll(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		serviceResults: []params.ServiceActionResult{{
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}},
+			Waiting: []string{"unit-mysql-1", "unit-mysql-2"},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.DoCommand{}, "--all", "--parallel", "1", "mysql", "some-action")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+Actions queued with ids:
+  mysql/0: `+validActionId+`
+Units waiting:
+- mysql/1
+- mysql/2
+`[1:])
+	enqueued := fakeClient.EnqueuedActions()
+	c.Assert(enqueued.Actions, gc.HasLen, 1)
+	c.Check(enqueued.Actions[0], jc.DeepEquals, params.Action{
+		Receiver:    "service-mysql",
+		Name:        "some-action",
+		Parameters:  map[string]interface{}{},
+		Parallelism: 1,
+	})
+}
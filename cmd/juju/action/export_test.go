@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/params"
@@ -41,6 +43,22 @@ func (c *DoCommand) ParseStrings() bool {
 	return c.parseStrings
 }
 
+func (c *DoCommand) ServiceTag() names.ServiceTag {
+	return c.serviceTag
+}
+
+func (c *DoCommand) Parallel() int {
+	return c.parallel
+}
+
+func (c *DoCommand) Schedule() (time.Time, string) {
+	return c.atTime, c.schedule
+}
+
+func (c *UnscheduleCommand) ScheduleIds() []string {
+	return c.ids
+}
+
 func ActionResultsToMap(results []params.ActionResult) map[string]interface{} {
 	return resultsToMap(results)
 }
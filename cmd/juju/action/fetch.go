@@ -4,6 +4,7 @@
 package action
 
 import (
+	"io"
 	"regexp"
 	"time"
 
@@ -21,6 +22,7 @@ type FetchCommand struct {
 	requestedId string
 	fullSchema  bool
 	wait        string
+	watch       bool
 }
 
 const fetchDoc = `
@@ -32,12 +34,17 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+To follow the output of a running action as it is written, use the --watch
+flag.  The action's standard output and standard error are copied to those of
+the command until the action finishes, after which its results are shown.
 `
 
 // Set up the output.
 func (c *FetchCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.StringVar(&c.wait, "wait", "-1s", "wait for results")
+	f.BoolVar(&c.watch, "watch", false, "show the action's output as it runs")
 }
 
 func (c *FetchCommand) Info() *cmd.Info {
@@ -56,6 +63,9 @@ func (c *FetchCommand) Init(args []string) error {
 		return errors.New("no action ID specified")
 	case 1:
 		c.requestedId = args[0]
+		if c.watch && c.wait != "-1s" {
+			return errors.New("--watch and --wait cannot be combined")
+		}
 		return nil
 	default:
 		return cmd.CheckEmpty(args[1:])
@@ -83,6 +93,14 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.watch {
+		result, err := watchOutput(ctx, api, c.requestedId)
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, formatActionResult(result))
+	}
+
 	// tick every two seconds, to delay the loop timer.
 	tick := time.NewTimer(2 * time.Second)
 	wait := time.NewTimer(0 * time.Second)
@@ -143,6 +161,41 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 	}
 }
 
+// watchOutput copies the output of the action to the context's stdout
+// and stderr as it is written, and returns the action's result once it
+// has finished.
+func watchOutput(ctx *cmd.Context, api APIClient, requestedId string) (params.ActionResult, error) {
+	actionTag, err := getActionTagByPrefix(api, requestedId)
+	if err != nil {
+		return params.ActionResult{}, err
+	}
+	w, err := api.WatchActionOutput(actionTag)
+	if err != nil {
+		return params.ActionResult{}, err
+	}
+	defer w.Stop()
+	for {
+		next, err := w.Next()
+		if err != nil {
+			return params.ActionResult{}, errors.Annotate(err, "cannot watch action output")
+		}
+		for _, chunk := range next.Output {
+			out := ctx.Stdout
+			if chunk.Stream == params.ActionOutputStderr {
+				out = ctx.Stderr
+			}
+			if _, err := io.WriteString(out, chunk.Data); err != nil {
+				return params.ActionResult{}, errors.Trace(err)
+			}
+		}
+		switch next.Status {
+		case params.ActionRunning, params.ActionPending:
+		default:
+			return fetchResult(api, requestedId)
+		}
+	}
+}
+
 // fetchResult queries the given API for the given Action ID prefix, and
 // makes sure the results are acceptable, returning an error if they are not.
 func fetchResult(api APIClient, requestedId string) (params.ActionResult, error) {
@@ -287,6 +287,49 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunWatch(c *gc.C) {
+	client := makeFakeClient(
+		0*time.Second,
+		5*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		[]params.ActionResult{{
+			Status: params.ActionCompleted,
+			Output: map[string]interface{}{"foo": "bar"},
+		}},
+		"",
+	)
+	client.actionOutput = []params.ActionOutputWatcherNextResult{{
+		Status: params.ActionRunning,
+		Output: []params.ActionOutput{
+			{Stream: params.ActionOutputStdout, Data: "working\n"},
+			{Stream: params.ActionOutputStderr, Data: "warning\n"},
+		},
+	}, {
+		Status: params.ActionCompleted,
+		Output: []params.ActionOutput{
+			{Stream: params.ActionOutputStdout, Data: "done\n"},
+		},
+	}}
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+
+	ctx, err := testing.RunCommand(c, &action.FetchCommand{}, validActionId, "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+working
+done
+results:
+  foo: bar
+status: completed
+`[1:])
+	c.Check(testing.Stderr(ctx), gc.Equals, "warning\n")
+}
+
+func (s *FetchSuite) TestInitWatchWithWait(c *gc.C) {
+	err := testing.InitCommand(&action.FetchCommand{}, []string{"--watch", "--wait", "5s", validActionId})
+	c.Check(err, gc.ErrorMatches, "--watch and --wait cannot be combined")
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
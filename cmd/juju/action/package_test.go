@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5-unstable"
 
+	apiaction "github.com/juju/juju/api/action"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/action"
@@ -136,6 +138,10 @@ type fakeAPIClient struct {
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
+	actionOutput       []params.ActionOutputWatcherNextResult
+	serviceResults     []params.ServiceActionResult
+	removedSchedules   []string
+	removeResults      []params.ErrorResult
 	apiErr             error
 }
 
@@ -156,6 +162,11 @@ func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, erro
 	return params.ActionResults{Results: c.actionResults}, c.apiErr
 }
 
+func (c *fakeAPIClient) EnqueueAll(args params.Actions) (params.ServiceActionResults, error) {
+	c.enqueuedActions = args
+	return params.ServiceActionResults{Results: c.serviceResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) ListAll(args params.Entities) (params.ActionsByReceivers, error) {
 	return params.ActionsByReceivers{
 		Actions: c.actionsByReceivers,
@@ -180,6 +191,19 @@ func (c *fakeAPIClient) Cancel(args params.Actions) (params.ActionResults, error
 	}, c.apiErr
 }
 
+func (c *fakeAPIClient) ListSchedules() (params.ActionResults, error) {
+	return params.ActionResults{
+		Results: c.actionResults,
+	}, c.apiErr
+}
+
+func (c *fakeAPIClient) RemoveSchedules(args params.ActionScheduleIds) (params.ErrorResults, error) {
+	c.removedSchedules = args.Ids
+	return params.ErrorResults{
+		Results: c.removeResults,
+	}, c.apiErr
+}
+
 func (c *fakeAPIClient) ServiceCharmActions(params.Entity) (*charm.Actions, error) {
 	return c.charmActions, c.apiErr
 }
@@ -214,3 +238,27 @@ func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, err
 func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsResults, error) {
 	return c.actionTagMatches, c.apiErr
 }
+
+func (c *fakeAPIClient) WatchActionOutput(names.ActionTag) (apiaction.OutputWatcher, error) {
+	if c.apiErr != nil {
+		return nil, c.apiErr
+	}
+	return &fakeOutputWatcher{results: c.actionOutput}, nil
+}
+
+type fakeOutputWatcher struct {
+	results []params.ActionOutputWatcherNextResult
+}
+
+func (w *fakeOutputWatcher) Next() (params.ActionOutputWatcherNextResult, error) {
+	if len(w.results) == 0 {
+		return params.ActionOutputWatcherNextResult{}, errors.New("no more output")
+	}
+	next := w.results[0]
+	w.results = w.results[1:]
+	return next, nil
+}
+
+func (w *fakeOutputWatcher) Stop() error {
+	return nil
+}
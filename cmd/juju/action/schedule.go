@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// SchedulesCommand lists the Actions that are scheduled to be queued up
+// later.
+type SchedulesCommand struct {
+	ActionCommandBase
+	out cmd.Output
+}
+
+const schedulesDoc = `
+Show the Actions scheduled with "juju action do --at" or "juju action do
+--schedule", in the order in which they will next be queued up.
+`
+
+// SetFlags offers an option for YAML output.
+func (c *SchedulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *SchedulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "schedules",
+		Purpose: "show the scheduled actions",
+		Doc:     schedulesDoc,
+	}
+}
+
+func (c *SchedulesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *SchedulesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ListSchedules()
+	if err != nil {
+		return err
+	}
+	items := []map[string]interface{}{}
+	for _, result := range results.Results {
+		if result.Action == nil || result.Action.Schedule == nil {
+			continue
+		}
+		items = append(items, scheduleToMap(result.Action))
+	}
+	return c.out.Write(ctx, map[string]interface{}{"schedules": items})
+}
+
+func scheduleToMap(action *params.Action) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":       action.Schedule.Id,
+		"action":   action.Name,
+		"next-run": action.Schedule.NextRun.Format(time.RFC3339),
+	}
+	if tag, err := names.ParseUnitTag(action.Receiver); err == nil {
+		item["unit"] = tag.Id()
+	} else {
+		item["unit"] = action.Receiver
+	}
+	if action.Schedule.Cron != "" {
+		item["schedule"] = action.Schedule.Cron
+	}
+	if len(action.Parameters) > 0 {
+		item["params"] = action.Parameters
+	}
+	return item
+}
+
+// UnscheduleCommand cancels scheduled Actions.
+type UnscheduleCommand struct {
+	ActionCommandBase
+	ids []string
+}
+
+const unscheduleDoc = `
+Cancel the scheduled Actions with the given ids, as shown by "juju action
+schedules". Actions already queued up by the schedules are not affected.
+`
+
+func (c *UnscheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "unschedule",
+		Args:    "<schedule ID> ...",
+		Purpose: "cancel scheduled actions",
+		Doc:     unscheduleDoc,
+	}
+}
+
+func (c *UnscheduleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no schedule id specified")
+	}
+	c.ids = args
+	return nil
+}
+
+func (c *UnscheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RemoveSchedules(params.ActionScheduleIds{Ids: c.ids})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != len(c.ids) {
+		return errors.Errorf("expected %d results, got %d", len(c.ids), len(results.Results))
+	}
+	failed := false
+	for i, result := range results.Results {
+		if result.Error != nil {
+			ctx.Infof("cannot cancel schedule %q: %v", c.ids[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type SchedulesSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&SchedulesSuite{})
+
+func (s *SchedulesSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, &action.SchedulesCommand{})
+}
+
+func (s *SchedulesSuite) TestRun(c *gc.C) {
+	nextRun := time.Date(2015, time.June, 1, 2, 30, 0, 0, time.UTC)
+	fakeClient := &fakeAPIClient{
+		actionResults: []params.ActionResult{{
+			Action: &params.Action{
+				Receiver: "unit-mysql-3",
+				Name:     "backup",
+				Schedule: &params.ActionSchedule{Id: "id-1", Cron: "30 2 * * *", NextRun: nextRun},
+			},
+			Status: params.ActionScheduled,
+		}, {
+			Action: &params.Action{
+				Receiver:   "unit-mysql-3",
+				Name:       "compact",
+				Parameters: map[string]interface{}{"level": 2},
+				Schedule:   &params.ActionSchedule{Id: "id-2", NextRun: nextRun.Add(time.Hour)},
+			},
+			Status: params.ActionScheduled,
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.SchedulesCommand{}, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `{"schedules":[`+
+		`{"action":"backup","id":"id-1","next-run":"2015-06-01T02:30:00Z","schedule":"30 2 * * *","unit":"mysql/3"},`+
+		`{"action":"compact","id":"id-2","next-run":"2015-06-01T03:30:00Z","params":{"level":2},"unit":"mysql/3"}]}`+"\n")
+}
+
+type UnscheduleSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&UnscheduleSuite{})
+
+func (s *UnscheduleSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, &action.UnscheduleCommand{})
+}
+
+func (s *UnscheduleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&action.UnscheduleCommand{}, nil)
+	c.Check(err, gc.ErrorMatches, "no schedule id specified")
+
+	subcommand := &action.UnscheduleCommand{}
+	err = testing.InitCommand(subcommand, []string{"id-1", "id-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subcommand.ScheduleIds(), jc.DeepEquals, []string{"id-1", "id-2"})
+}
+
+func (s *UnscheduleSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		removeResults: []params.ErrorResult{{}, {}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, &action.UnscheduleCommand{}, "id-1", "id-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.removedSchedules, jc.DeepEquals, []string{"id-1", "id-2"})
+}
+
+func (s *UnscheduleSuite) TestRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		removeResults: []params.ErrorResult{
+			{Error: common.ServerError(errors.New(`action schedule "id-1" not found`))},
+		},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.UnscheduleCommand{}, "id-1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(testing.Stderr(ctx), gc.Equals, `cannot cancel schedule "id-1": action schedule "id-1" not found`+"\n")
+}
@@ -5,8 +5,10 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -16,21 +18,54 @@ import (
 type AddRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+
+	// Selector holds the annotations a principal unit must carry for
+	// a subordinate to be attached to it.
+	Selector map[string]string
+	selector string
 }
 
+const addRelationDoc = `
+A relation between a subordinate service and a principal service
+attaches a unit of the subordinate to every unit of the principal.
+With --select the subordinate is only attached to the principal units
+carrying all the given annotations, either on the unit or on its
+machine. Annotations are set with the Annotations API, for example by
+the GUI, and units carrying them are listed by "juju find".
+
+Example:
+   juju add-relation nrpe mysql --select "tier=prod zone=east"
+   (attach nrpe only to the mysql units annotated tier=prod and zone=east)
+`
+
 func (c *AddRelationCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     addRelationDoc,
 	}
 }
 
+func (c *AddRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.selector, "select", "", "annotations selecting the principal units a subordinate is attached to")
+}
+
 func (c *AddRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
 	}
 	c.Endpoints = args
+	for _, field := range strings.Fields(c.selector) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf(`invalid --select annotation %q: expected "key=value"`, field)
+		}
+		if c.Selector == nil {
+			c.Selector = make(map[string]string)
+		}
+		c.Selector[parts[0]] = parts[1]
+	}
 	return nil
 }
 
@@ -40,6 +75,6 @@ func (c *AddRelationCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	_, err = client.AddRelation(c.Endpoints...)
+	_, err = client.AddRelationWithSelector(c.Selector, c.Endpoints...)
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
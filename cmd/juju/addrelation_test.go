@@ -161,6 +161,31 @@ func (s *AddRelationSuite) TestAddRelation(c *gc.C) {
 	}
 }
 
+func (s *AddRelationSuite) TestAddRelationWithSelector(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:mysql", "ms")
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
+	err = runDeploy(c, "local:logging", "lg")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runAddRelation(c, "ms", "lg", "--select", "tier=prod zone=east")
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.State.InferEndpoints("ms", "lg")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.EndpointsRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.SubordinateSelector(), jc.DeepEquals, map[string]string{
+		"tier": "prod",
+		"zone": "east",
+	})
+}
+
+func (s *AddRelationSuite) TestAddRelationInvalidSelector(c *gc.C) {
+	err := runAddRelation(c, "ms", "lg", "--select", "tier")
+	c.Assert(err, gc.ErrorMatches, `invalid --select annotation "tier": expected "key=value"`)
+}
+
 func (s *AddRelationSuite) TestBlockAddRelation(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
 	err := runDeploy(c, "local:wordpress", "wp")
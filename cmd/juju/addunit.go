@@ -82,7 +82,8 @@ have already been deployed via juju deploy.
 
 By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
-argument.
+argument. The machine may already host other units, but it must be of the
+service's series. The placement overrides the service's constraints.
 
 Examples:
  juju add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/keyvalues"
+
+	"github.com/juju/juju/api/resources"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const attachDoc = `
+Attach binary resources to a service, for its charm to fetch with the
+resource-get hook tool. Each resource is given as <name>=<file>; uploading
+a resource that is already attached replaces it with a new revision.
+
+Resources are removed along with the service.
+
+Examples:
+
+  # Attach a theme to wordpress.
+  juju attach wordpress theme=./theme.tar.gz
+`
+
+// AttachCommand uploads charm resources for a service.
+type AttachCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Resources   map[string]string
+}
+
+func (c *AttachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "attach",
+		Args:    "<service> <name>=<file> ...",
+		Purpose: "attach resources to a service",
+		Doc:     attachDoc,
+	}
+}
+
+func (c *AttachCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	if len(args) == 1 {
+		return errors.New("no resources specified")
+	}
+	resources, err := keyvalues.Parse(args[1:], false)
+	if err != nil {
+		return err
+	}
+	c.Resources = resources
+	return nil
+}
+
+// AttachAPI defines the API methods used by the attach command.
+type AttachAPI interface {
+	Upload(serviceName, name string, r io.ReadSeeker) (params.ResourceMetadata, error)
+	Close() error
+}
+
+var getAttachAPI = func(c *AttachCommand) (AttachAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return resources.NewClient(root), nil
+}
+
+// Run uploads each of the resources in name order.
+func (c *AttachCommand) Run(ctx *cmd.Context) error {
+	client, err := getAttachAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var resourceNames []string
+	for name := range c.Resources {
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Strings(resourceNames)
+	for _, name := range resourceNames {
+		meta, err := c.upload(ctx, client, name)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		fmt.Fprintf(ctx.Stdout, "%s: revision %d\n", name, meta.Revision)
+	}
+	return nil
+}
+
+func (c *AttachCommand) upload(ctx *cmd.Context, client AttachAPI, name string) (params.ResourceMetadata, error) {
+	f, err := os.Open(ctx.AbsPath(c.Resources[name]))
+	if err != nil {
+		return params.ResourceMetadata{}, errors.Trace(err)
+	}
+	defer f.Close()
+	meta, err := client.Upload(c.ServiceName, name, f)
+	if err != nil {
+		return params.ResourceMetadata{}, errors.Annotatef(err, "cannot attach resource %q", name)
+	}
+	return meta, nil
+}
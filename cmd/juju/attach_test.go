@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AttachSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAttachAPI
+}
+
+var _ = gc.Suite(&AttachSuite{})
+
+func (s *AttachSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAttachAPI{uploaded: make(map[string]string)}
+	s.PatchValue(&getAttachAPI, func(_ *AttachCommand) (AttachAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *AttachSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected map[string]string
+		errMatch string
+	}{{
+		errMatch: "no service name specified",
+	}, {
+		args:     []string{"wordpress"},
+		errMatch: "no resources specified",
+	}, {
+		args:     []string{"Wordpress", "theme=theme.tgz"},
+		errMatch: `invalid service name "Wordpress"`,
+	}, {
+		args:     []string{"wordpress", "theme"},
+		errMatch: `expected "key=value", got "theme"`,
+	}, {
+		args:     []string{"wordpress", "theme=theme.tgz", "plugins=plugins.tgz"},
+		expected: map[string]string{"theme": "theme.tgz", "plugins": "plugins.tgz"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AttachCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.ServiceName, gc.Equals, "wordpress")
+			c.Check(command.Resources, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AttachSuite) TestRun(c *gc.C) {
+	dir := c.MkDir()
+	for name, content := range map[string]string{"theme.tgz": "abc", "plugins.tgz": "def"} {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AttachCommand{}), "wordpress",
+		"theme="+filepath.Join(dir, "theme.tgz"),
+		"plugins="+filepath.Join(dir, "plugins.tgz"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.uploaded, jc.DeepEquals, map[string]string{
+		"wordpress/theme":   "abc",
+		"wordpress/plugins": "def",
+	})
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "plugins: revision 1\ntheme: revision 1\n")
+}
+
+func (s *AttachSuite) TestRunMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AttachCommand{}), "wordpress", "theme=/no/such/file")
+	c.Assert(err, gc.ErrorMatches, "open /no/such/file: .*")
+}
+
+func (s *AttachSuite) TestRunError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	path := filepath.Join(c.MkDir(), "theme.tgz")
+	err := ioutil.WriteFile(path, []byte("abc"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&AttachCommand{}), "wordpress", "theme="+path)
+	c.Assert(err, gc.ErrorMatches, `cannot attach resource "theme": boom`)
+}
+
+type fakeAttachAPI struct {
+	uploaded map[string]string
+	err      error
+	closed   bool
+}
+
+func (f *fakeAttachAPI) Upload(serviceName, name string, r io.ReadSeeker) (params.ResourceMetadata, error) {
+	if f.err != nil {
+		return params.ResourceMetadata{}, f.err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return params.ResourceMetadata{}, err
+	}
+	f.uploaded[serviceName+"/"+name] = string(data)
+	return params.ResourceMetadata{ServiceName: serviceName, Name: name, Revision: 1}, nil
+}
+
+func (f *fakeAttachAPI) Close() error {
+	f.closed = true
+	return nil
+}
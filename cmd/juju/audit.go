@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/audit"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// defaultAuditLimit is the default number of audit entries shown.
+const defaultAuditLimit = 20
+
+const auditDoc = `
+Show the most recent state-changing API calls made in the environment,
+most recent first. Each entry records the entity that made the call,
+the call itself, the entities it named, and its outcome.
+
+Entries can be filtered on:
+  --caller   the tag of the entity that made the call, eg "user-admin"
+  --entity   the tag of an entity named by the call, eg "unit-mysql-0"
+  --facade   the API facade called, eg "Client"
+  --method   the API method called, eg "ServiceDeploy"
+  --since    how far back to look, eg "30m" or "24h"
+
+Examples:
+
+  # Show the 20 most recent calls.
+  juju audit
+
+  # Show all the calls made by the admin user in the last hour.
+  juju audit --caller user-admin --since 1h -n 0
+`
+
+// AuditCommand shows entries from the environment's audit log.
+type AuditCommand struct {
+	envcmd.EnvCommandBase
+	out    cmd.Output
+	filter params.AuditFilter
+	since  time.Duration
+}
+
+func (c *AuditCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit",
+		Purpose: "show state-changing API calls made in the environment",
+		Doc:     auditDoc,
+	}
+}
+
+func (c *AuditCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.filter.Caller, "caller", "", "only show calls made by this entity")
+	f.StringVar(&c.filter.Entity, "entity", "", "only show calls naming this entity")
+	f.StringVar(&c.filter.Facade, "facade", "", "only show calls to this API facade")
+	f.StringVar(&c.filter.Method, "method", "", "only show calls to this API method")
+	f.DurationVar(&c.since, "since", 0, "only show calls made within this duration")
+	f.IntVar(&c.filter.Limit, "n", defaultAuditLimit, "show at most this many calls; 0 shows all")
+	f.IntVar(&c.filter.Limit, "limit", defaultAuditLimit, "")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditTabular,
+	})
+}
+
+func (c *AuditCommand) Init(args []string) error {
+	for flag, tag := range map[string]string{
+		"caller": c.filter.Caller,
+		"entity": c.filter.Entity,
+	} {
+		if tag == "" {
+			continue
+		}
+		if _, err := names.ParseTag(tag); err != nil {
+			return errors.Errorf("invalid --%s: %v", flag, err)
+		}
+	}
+	if c.since < 0 {
+		return errors.New("--since must not be negative")
+	}
+	if c.filter.Limit < 0 {
+		return errors.New("-n must not be negative")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// AuditAPI defines the API methods used by the audit command.
+type AuditAPI interface {
+	Entries(filter params.AuditFilter) ([]params.AuditEntry, error)
+	Close() error
+}
+
+var getAuditAPI = func(c *AuditCommand) (AuditAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewClient(root), nil
+}
+
+// auditEntry defines the serialization of an audit log entry.
+type auditEntry struct {
+	Time     string   `yaml:"time" json:"time"`
+	Caller   string   `yaml:"caller" json:"caller"`
+	Call     string   `yaml:"call" json:"call"`
+	Entities []string `yaml:"entities,omitempty" json:"entities,omitempty"`
+	Args     string   `yaml:"args,omitempty" json:"args,omitempty"`
+	Result   string   `yaml:"result,omitempty" json:"result,omitempty"`
+}
+
+// Run shows the matching audit log entries.
+func (c *AuditCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	filter := c.filter
+	if c.since > 0 {
+		filter.Since = time.Now().Add(-c.since)
+	}
+	entries, err := client.Entries(filter)
+	if err != nil {
+		return err
+	}
+	output := make([]auditEntry, len(entries))
+	for i, entry := range entries {
+		call := fmt.Sprintf("%s(%d).%s", entry.Facade, entry.Version, entry.Method)
+		if entry.Id != "" {
+			call = fmt.Sprintf("%s(%d)[%q].%s", entry.Facade, entry.Version, entry.Id, entry.Method)
+		}
+		output[i] = auditEntry{
+			Time:     entry.Time.UTC().Format(time.RFC3339),
+			Caller:   entry.Caller,
+			Call:     call,
+			Entities: entry.Entities,
+			Args:     entry.Args,
+			Result:   entry.Result,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatAuditTabular returns a tabular summary of audit entries. The
+// arguments are omitted, as they are usually too long to be useful in
+// a table.
+func formatAuditTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]auditEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "TIME\tCALLER\tCALL\tENTITIES\tRESULT\n")
+	for _, entry := range entries {
+		result := entry.Result
+		if result == "" {
+			result = "ok"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Time, entry.Caller, entry.Call, strings.Join(entry.Entities, ","), result)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
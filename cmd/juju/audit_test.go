@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAuditAPI
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func (s *AuditSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAuditAPI{
+		entries: []params.AuditEntry{{
+			Time:     time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+			Caller:   "user-admin",
+			Facade:   "Client",
+			Method:   "DestroyServiceUnits",
+			Entities: []string{"unit-wordpress-0", "unit-wordpress-1"},
+			Args:     `{"UnitNames":["wordpress/0","wordpress/1"]}`,
+			Result:   "unit is already dying",
+		}, {
+			Time:     time.Date(2015, 5, 1, 11, 0, 0, 0, time.UTC),
+			Caller:   "machine-0",
+			Facade:   "Machiner",
+			Version:  1,
+			Method:   "SetStatus",
+			Entities: []string{"machine-0"},
+		}},
+	}
+	s.PatchValue(&getAuditAPI, func(_ *AuditCommand) (AuditAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *AuditSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected params.AuditFilter
+		errMatch string
+	}{{
+		expected: params.AuditFilter{Limit: 20},
+	}, {
+		args: []string{"--caller", "user-admin", "--entity", "unit-mysql-0", "--facade", "Client", "--method", "ServiceDeploy", "-n", "5"},
+		expected: params.AuditFilter{
+			Caller: "user-admin",
+			Entity: "unit-mysql-0",
+			Facade: "Client",
+			Method: "ServiceDeploy",
+			Limit:  5,
+		},
+	}, {
+		args:     []string{"--caller", "admin"},
+		errMatch: `invalid --caller: "admin" is not a valid tag`,
+	}, {
+		args:     []string{"--since", "-1h"},
+		errMatch: "--since must not be negative",
+	}, {
+		args:     []string{"--limit", "-1"},
+		errMatch: "-n must not be negative",
+	}, {
+		args:     []string{"extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AuditCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.filter, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AuditSuite) TestRunSince(c *gc.C) {
+	before := time.Now()
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AuditCommand{}), "--since", "1h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.filter.Since.Before(before.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(s.fake.filter.Since.After(time.Now().Add(-time.Hour)), jc.IsFalse)
+	c.Assert(s.fake.closed, jc.IsTrue)
+}
+
+func (s *AuditSuite) TestRunTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  CALLER      CALL                           ENTITIES                           RESULT\n"+
+		"2015-05-01T12:00:00Z  user-admin  Client(0).DestroyServiceUnits  unit-wordpress-0,unit-wordpress-1  unit is already dying\n"+
+		"2015-05-01T11:00:00Z  machine-0   Machiner(1).SetStatus          machine-0                          ok\n")
+}
+
+func (s *AuditSuite) TestRunJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[`+
+		`{"time":"2015-05-01T12:00:00Z","caller":"user-admin","call":"Client(0).DestroyServiceUnits",`+
+		`"entities":["unit-wordpress-0","unit-wordpress-1"],`+
+		`"args":"{\"UnitNames\":[\"wordpress/0\",\"wordpress/1\"]}","result":"unit is already dying"},`+
+		`{"time":"2015-05-01T11:00:00Z","caller":"machine-0","call":"Machiner(1).SetStatus","entities":["machine-0"]}`+
+		`]`+"\n")
+}
+
+type fakeAuditAPI struct {
+	filter  params.AuditFilter
+	entries []params.AuditEntry
+	closed  bool
+}
+
+func (f *fakeAuditAPI) Entries(filter params.AuditFilter) ([]params.AuditEntry, error) {
+	f.filter = filter
+	return f.entries, nil
+}
+
+func (f *fakeAuditAPI) Close() error {
+	f.closed = true
+	return nil
+}
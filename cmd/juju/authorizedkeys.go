@@ -15,6 +15,10 @@ var authKeysDoc = `
 "juju authorized-keys" is used to manage the ssh keys allowed to log on to
 nodes in the Juju environment.
 
+Keys are tracked per Juju user: each user lists and deletes only the keys
+they added, while the environment owner may manage the keys of any user.
+The keys of all users are allowed to log on to every node, and are updated
+on running nodes as soon as they change.
 `
 
 type AuthorizedKeysCommand struct {
@@ -150,12 +150,14 @@ func (s *ListKeysSuite) TestListKeysNonDefaultUser(c *gc.C) {
 	key2 := sshtesting.ValidKeyTwo.Key + " another@host"
 	s.setAuthorizedKeys(c, key1, key2)
 	s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	err := s.State.SetUserSSHKeys("fred", []string{key2})
+	c.Assert(err, jc.ErrorIsNil)
 
 	context, err := coretesting.RunCommand(c, envcmd.Wrap(&ListKeysCommand{}), "--user", "fred")
 	c.Assert(err, jc.ErrorIsNil)
 	output := strings.TrimSpace(coretesting.Stdout(context))
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(output, gc.Matches, "Keys for user fred:\n.*\\(user@host\\)\n.*\\(another@host\\)")
+	c.Assert(output, gc.Matches, "Keys for user fred:\n.*\\(another@host\\)")
 }
 
 func (s *ListKeysSuite) TestTooManyArgs(c *gc.C) {
@@ -201,6 +203,9 @@ func (s *AddKeySuite) TestAddKeyNonDefaultUser(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(coretesting.Stderr(context), gc.Equals, "")
 	s.assertEnvironKeys(c, key1, key2)
+	keys, err := s.State.UserSSHKeys("fred")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{key2})
 }
 
 type DeleteKeySuite struct {
@@ -238,11 +243,14 @@ func (s *DeleteKeySuite) TestDeleteKeyNonDefaultUser(c *gc.C) {
 	key2 := sshtesting.ValidKeyTwo.Key + " another@host"
 	s.setAuthorizedKeys(c, key1, key2)
 	s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	err := s.State.SetUserSSHKeys("fred", []string{key2})
+	c.Assert(err, jc.ErrorIsNil)
 
+	// Fred can only delete his own keys.
 	context, err := coretesting.RunCommand(c, envcmd.Wrap(&DeleteKeysCommand{}),
-		"--user", "fred", sshtesting.ValidKeyTwo.Fingerprint)
+		"--user", "fred", sshtesting.ValidKeyOne.Fingerprint, sshtesting.ValidKeyTwo.Fingerprint)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(coretesting.Stderr(context), gc.Equals, "")
+	c.Assert(coretesting.Stderr(context), gc.Matches, `cannot delete key id "`+sshtesting.ValidKeyOne.Fingerprint+`".*\n`)
 	s.assertEnvironKeys(c, key1)
 }
 
@@ -56,7 +56,7 @@ func NewCommand() cmd.Command {
 type APIClient interface {
 	io.Closer
 	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	Create(notes string, incremental, upload bool) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
@@ -107,6 +107,15 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
 	fmt.Fprintf(ctx.Stdout, "created on host: %q\n", result.Hostname)
 	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", result.Version)
+	if result.Scheduled {
+		fmt.Fprintf(ctx.Stdout, "scheduled:       %v\n", result.Scheduled)
+	}
+	if result.BaseID != "" {
+		fmt.Fprintf(ctx.Stdout, "incremental on:  %q\n", result.BaseID)
+	}
+	if result.Uploaded != "" {
+		fmt.Fprintf(ctx.Stdout, "uploaded to:     %q\n", result.Uploaded)
+	}
 }
 
 func getArchive(filename string) (rc io.ReadCloser, metaResult *params.BackupsMetadataResult, err error) {
@@ -28,6 +28,14 @@ backup's unique ID.  You may provide a note to associate with the backup.
 
 The backup archive and associated metadata are stored remotely by juju.
 
+The --incremental option creates a backup holding only the changes to
+juju's state since the most recent full backup.  Restoring it restores
+that full backup first.
+
+The --upload option also uploads the backup archive to the environment
+provider's object store (for example S3 or Swift), where it is kept
+even if the state servers are lost.
+
 The --download option may be used without the --filename option.  In
 that case, the backup archive will be stored in the current working
 directory with a name matching juju-backup-<date>-<time>.tar.gz.
@@ -53,6 +61,12 @@ type CreateCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// Incremental means only the changes since the last full backup
+	// should be backed up.
+	Incremental bool
+	// Upload means the backup archive should also be uploaded to the
+	// provider's object store.
+	Upload bool
 }
 
 // Info implements Command.Info.
@@ -70,6 +84,8 @@ func (c *CreateCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the metadata")
 	f.BoolVar(&c.NoDownload, "no-download", false, "do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "download to this file")
+	f.BoolVar(&c.Incremental, "incremental", false, "back up only the changes since the last full backup")
+	f.BoolVar(&c.Upload, "upload", false, "also upload the archive to the provider's object store")
 }
 
 // Init implements Command.Init.
@@ -98,7 +114,7 @@ func (c *CreateCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	result, err := client.Create(c.Notes, c.Incremental, c.Upload)
 	if err != nil {
 		return errors.Trace(err)
 	}
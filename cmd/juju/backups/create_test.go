@@ -122,6 +122,16 @@ func (s *createSuite) TestNotes(c *gc.C) {
 	client.Check(c, s.metaresult.ID, "spam", "Create", "Download")
 }
 
+func (s *createSuite) TestIncrementalUpload(c *gc.C) {
+	client := s.BaseBackupsSuite.setDownload()
+	_, err := testing.RunCommand(c, s.command, "create", "--incremental", "--upload")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, s.metaresult.ID, "", "Create", "Download")
+	c.Check(client.incremental, jc.IsTrue)
+	c.Check(client.upload, jc.IsTrue)
+}
+
 func (s *createSuite) TestFilename(c *gc.C) {
 	client := s.setDownload()
 	s.subcommand.Filename = "backup.tgz"
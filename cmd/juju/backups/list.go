@@ -13,6 +13,9 @@ import (
 
 const listDoc = `
 "list" provides the metadata associated with all backups.
+
+Backups created automatically on the environment's backups-schedule
+are marked as scheduled.
 `
 
 // ListCommand is the sub-command for listing all available backups.
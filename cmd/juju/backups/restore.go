@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/instance"
 )
 
 // RestoreCommand is a subcommand of backups that implement the restore behaior
@@ -29,6 +30,7 @@ type RestoreCommand struct {
 	filename    string
 	backupId    string
 	bootstrap   bool
+	controller  bool
 }
 
 var restoreDoc = `
@@ -47,6 +49,14 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+With --controller the state server is restored in one step: a new
+state server is bootstrapped only if none of the previous ones is still
+running, the backup given by --file is restored onto it and the API
+addresses held in state and by every machine agent are replaced with
+those of the new state server, so that workloads reconnect to it
+without manual changes. Any machine whose agent could not be updated is
+listed, so that it can be updated by hand.
 `
 
 // Info returns the content for --help.
@@ -65,6 +75,7 @@ func (c *RestoreCommand) SetFlags(f *gnuflag.FlagSet) {
 		"constraints", "set environment constraints")
 
 	f.BoolVar(&c.bootstrap, "b", false, "bootstrap a new state machine")
+	f.BoolVar(&c.controller, "controller", false, "bootstrap a new state server if needed, restore and reconnect all agents to it")
 	f.StringVar(&c.filename, "file", "", "provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "provide the name of the backup to be restored.")
 }
@@ -80,6 +91,12 @@ func (c *RestoreCommand) Init(args []string) error {
 	if c.backupId != "" && c.bootstrap {
 		return errors.Errorf("it is not possible to rebootstrap and restore from an id.")
 	}
+	if c.controller && c.bootstrap {
+		return errors.Errorf("--controller bootstraps when needed and cannot be combined with -b.")
+	}
+	if c.controller && c.backupId != "" {
+		return errors.Errorf("it is not possible to restore the controller from an id.")
+	}
 	var err error
 	if c.filename != "" {
 		c.filename, err = filepath.Abs(c.filename)
@@ -124,47 +141,124 @@ func (c *RestoreCommand) runRestore(ctx *cmd.Context) error {
 	}
 
 	fmt.Fprintf(ctx.Stdout, "restore from %q completed\n", target)
+	return c.reportUnreachable(ctx)
+}
+
+// reportUnreachable lists the machines whose agents the restore could
+// not point at the new state server.
+func (c *RestoreCommand) reportUnreachable(ctx *cmd.Context) error {
+	client, closer, err := c.newClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer closer()
+	info, err := client.RestoreInfo()
+	if params.IsCodeNotImplemented(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Annotate(err, "cannot determine which agents were updated")
+	}
+	if len(info.UnreachableMachines) == 0 {
+		return nil
+	}
+	fmt.Fprintf(ctx.Stderr, "the agents of these machines could not be updated with the new state server addresses: %s\n",
+		strings.Join(info.UnreachableMachines, ", "))
 	return nil
 }
 
 // rebootstrap will bootstrap a new server in safe-mode (not killing any other agent)
 // if there is no current server available to restore to.
 func (c *RestoreCommand) rebootstrap(ctx *cmd.Context) error {
-	store, err := configstore.Default()
+	env, err := c.safeModeEnviron()
 	if err != nil {
 		return errors.Trace(err)
 	}
-	cfg, err := c.Config(store)
+	inst, err := stateServerInstances(env)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(inst) > 0 {
+		return errors.Errorf("old bootstrap instance %q still seems to exist; will not replace", inst)
+	}
+	return c.bootstrapEnviron(ctx, env)
+}
+
+// restoreController bootstraps a new state server, unless one of the
+// previous ones is still running, in which case the backup is restored
+// onto that instead.
+func (c *RestoreCommand) restoreController(ctx *cmd.Context) error {
+	env, err := c.safeModeEnviron()
 	if err != nil {
 		return errors.Trace(err)
 	}
+	inst, err := stateServerInstances(env)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(inst) > 0 {
+		ctx.Infof("state server instance %q is still running; restoring onto it", inst[0].Id())
+		return nil
+	}
+	return c.bootstrapEnviron(ctx, env)
+}
+
+// safeModeEnviron returns the environment with provisioner-safe-mode
+// enabled.
+func (c *RestoreCommand) safeModeEnviron() (environs.Environ, error) {
+	store, err := configstore.Default()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := c.Config(store)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Turn on safe mode so that the newly bootstrapped instance
 	// will not destroy all the instances it does not know about.
 	cfg, err = cfg.Apply(map[string]interface{}{
 		"provisioner-safe-mode": true,
 	})
 	if err != nil {
-		return errors.Annotatef(err, "cannot enable provisioner-safe-mode")
+		return nil, errors.Annotatef(err, "cannot enable provisioner-safe-mode")
 	}
 	env, err := environs.New(cfg)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
+	return env, nil
+}
+
+// stateServerInstances returns the environment's state server instances
+// that are still running.
+func stateServerInstances(env environs.Environ) ([]instance.Instance, error) {
 	instanceIds, err := env.StateServerInstances()
 	if err != nil {
-		return errors.Annotatef(err, "cannot determine state server instances")
+		return nil, errors.Annotatef(err, "cannot determine state server instances")
 	}
 	if len(instanceIds) == 0 {
-		return errors.Errorf("no instances found; perhaps the environment was not bootstrapped")
-	}
-	inst, err := env.Instances(instanceIds)
-	if err == nil {
-		return errors.Errorf("old bootstrap instance %q still seems to exist; will not replace", inst)
+		return nil, errors.Errorf("no instances found; perhaps the environment was not bootstrapped")
 	}
-	if err != environs.ErrNoInstances {
-		return errors.Annotatef(err, "cannot detect whether old instance is still running")
+	insts, err := env.Instances(instanceIds)
+	switch err {
+	case nil:
+		return insts, nil
+	case environs.ErrPartialInstances:
+		var running []instance.Instance
+		for _, inst := range insts {
+			if inst != nil {
+				running = append(running, inst)
+			}
+		}
+		return running, nil
+	case environs.ErrNoInstances:
+		return nil, nil
 	}
+	return nil, errors.Annotatef(err, "cannot detect whether old instance is still running")
+}
 
+// bootstrapEnviron bootstraps a new state server for env.
+func (c *RestoreCommand) bootstrapEnviron(ctx *cmd.Context, env environs.Environ) error {
 	cons := c.constraints
 	args := bootstrap.BootstrapParams{Constraints: cons}
 	if err := bootstrap.Bootstrap(envcmd.BootstrapContext(ctx), env, args); err != nil {
@@ -192,5 +286,10 @@ func (c *RestoreCommand) Run(ctx *cmd.Context) error {
 			return errors.Trace(err)
 		}
 	}
+	if c.controller {
+		if err := c.restoreController(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return c.runRestore(ctx)
 }
@@ -32,4 +32,10 @@ func (s *restoreSuite) TestRestoreArgs(c *gc.C) {
 
 	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "-b")
 	c.Assert(err, gc.ErrorMatches, "it is not possible to rebootstrap and restore from an id.")
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--file", "afile", "--controller", "-b")
+	c.Assert(err, gc.ErrorMatches, "--controller bootstraps when needed and cannot be combined with -b.")
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "--controller")
+	c.Assert(err, gc.ErrorMatches, "it is not possible to restore the controller from an id.")
 }
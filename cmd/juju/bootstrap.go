@@ -5,10 +5,13 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 	"gopkg.in/juju/charm.v5-unstable"
 	"launchpad.net/gnuflag"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/network"
@@ -39,7 +43,9 @@ algorithm by using the "--to" flag. The value associated with "--to" is a
 For more information on placement directives, see "juju help placement".
 
 Bootstrap initializes the cloud environment synchronously and displays information
-about the current installation steps.  The time for bootstrap to complete varies
+about the current installation steps; run with --verbose to see each phase of the
+bootstrap process as it starts and finishes. Phases that fail because of transient
+cloud provider errors, such as exceeded rate limits, are retried automatically.  The time for bootstrap to complete varies
 across cloud providers from a few seconds to several minutes.  Once bootstrap has
 completed, you can run other juju commands against your environment. You can change
 the default timeout and retry delays used during the bootstrap by changing the
@@ -52,6 +58,13 @@ following settings in your environments.yaml (all values represent number of sec
     # How often to refresh state server addresses from the API server.
     bootstrap-addresses-delay: 10 # default: 10 seconds
 
+The state server may also be installed on an existing machine instead of a new
+instance, by specifying the machine's address with the "--bootstrap-to" flag.
+The machine must be running Ubuntu and be reachable over SSH as the "ubuntu"
+user, with passwordless sudo. It is enlisted as machine 0 in the same way as by
+the manual provider; all subsequent machines are started by the provider as
+usual.
+
 Private clouds may need to specify their own custom image metadata, and possibly upload
 Juju tools to cloud storage if no outgoing Internet access is available. In this case,
 use the --metadata-source paramater to tell bootstrap a local directory from which to
@@ -74,6 +87,7 @@ type BootstrapCommand struct {
 	seriesOld             []string
 	MetadataSource        string
 	Placement             string
+	BootstrapHost         string
 	KeepBrokenEnvironment bool
 }
 
@@ -92,6 +106,7 @@ func (c *BootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(newSeriesValue(nil, &c.seriesOld), "series", "see --upload-series (OBSOLETE)")
 	f.StringVar(&c.MetadataSource, "metadata-source", "", "local path to use as tools and/or metadata source")
 	f.StringVar(&c.Placement, "to", "", "a placement directive indicating an instance to bootstrap")
+	f.StringVar(&c.BootstrapHost, "bootstrap-to", "", "the address of an existing machine to bootstrap over SSH")
 	f.BoolVar(&c.KeepBrokenEnvironment, "keep-broken", false, "do not destroy the environment if bootstrap fails")
 }
 
@@ -115,6 +130,14 @@ func (c *BootstrapCommand) Init(args []string) (err error) {
 			return fmt.Errorf("unsupported bootstrap placement directive %q", c.Placement)
 		}
 	}
+	if c.BootstrapHost != "" {
+		if c.Placement != "" {
+			return fmt.Errorf("--to and --bootstrap-to can't be used together")
+		}
+		if strings.Contains(c.BootstrapHost, "@") {
+			return fmt.Errorf("invalid bootstrap host %q: the ubuntu user is always used", c.BootstrapHost)
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -243,16 +266,77 @@ func (c *BootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 		c.UploadTools = true
 	}
 
+	if c.BootstrapHost != "" {
+		if envType := environ.Config().Type(); envType == provider.Local || provider.IsManual(envType) {
+			return fmt.Errorf("--bootstrap-to is not supported by the %q provider", envType)
+		}
+	}
+
+	progress := bootstrapProgress(ctx)
 	err = bootstrapFuncs.Bootstrap(envcmd.BootstrapContext(ctx), environ, bootstrap.BootstrapParams{
-		Constraints: c.Constraints,
-		Placement:   c.Placement,
-		UploadTools: c.UploadTools,
-		MetadataDir: metadataDir,
+		Constraints:   c.Constraints,
+		Placement:     c.Placement,
+		UploadTools:   c.UploadTools,
+		MetadataDir:   metadataDir,
+		BootstrapHost: c.BootstrapHost,
+		Progress:      progress,
 	})
 	if err != nil {
 		return errors.Annotate(err, "failed to bootstrap environment")
 	}
-	return c.SetBootstrapEndpointAddress(environ)
+	if err := c.SetBootstrapEndpointAddress(environ); err != nil {
+		return err
+	}
+	return c.waitForAPIServer(environ, progress)
+}
+
+// bootstrapProgress returns a function that reports the progress of
+// the bootstrap phases to the user. Retries are always reported; the
+// other events are only shown in verbose mode, as a failure is
+// reported by the returned error anyway.
+func bootstrapProgress(ctx *cmd.Context) bootstrap.ProgressFunc {
+	return func(event bootstrap.ProgressEvent) {
+		if event.Status == bootstrap.PhaseRetrying {
+			ctx.Infof("Bootstrap phase %s", event)
+		} else {
+			ctx.Verbosef("Bootstrap phase %s", event)
+		}
+	}
+}
+
+// waitForAPIServer waits for the API server on the bootstrap machine
+// to accept connections.
+func (c *BootstrapCommand) waitForAPIServer(environ environs.Environ, progress bootstrap.ProgressFunc) error {
+	netAddrs, err := c.bootstrapAddresses(environ)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg := environ.Config()
+	hostPorts := network.AddressesWithPort(netAddrs, cfg.APIPort())
+	return bootstrap.RunPhase(progress, bootstrap.PhaseAPIUp, func() error {
+		return waitForAPI(hostPorts, cfg.BootstrapSSHOpts())
+	})
+}
+
+// waitForAPI waits until one of the given API server addresses
+// accepts TCP connections, or the bootstrap timeout is reached.
+var waitForAPI = func(hostPorts []network.HostPort, opts config.SSHTimeoutOpts) error {
+	attempt := utils.AttemptStrategy{
+		Total: opts.Timeout,
+		Delay: opts.RetryDelay,
+	}
+	for a := attempt.Start(); a.Next(); {
+		for _, hp := range hostPorts {
+			conn, err := net.DialTimeout("tcp", hp.NetAddr(), opts.RetryDelay)
+			if err != nil {
+				logger.Debugf("cannot connect to API server at %v: %v", hp, err)
+				continue
+			}
+			conn.Close()
+			return nil
+		}
+	}
+	return errors.Errorf("timed out waiting for API server at %v", hostPorts)
 }
 
 // handleBootstrapError is called to clean up if bootstrap fails.
@@ -278,20 +362,13 @@ var prepareEndpointsForCaching = juju.PrepareEndpointsForCaching
 // SetBootstrapEndpointAddress writes the API endpoint address of the
 // bootstrap server into the connection information. This should only be run
 // once directly after Bootstrap. It assumes that there is just one instance
-// in the environment - the bootstrap instance.
+// in the environment - the bootstrap instance - unless an existing machine
+// was bootstrapped.
 func (c *BootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ) error {
-	instances, err := allInstances(environ)
+	netAddrs, err := c.bootstrapAddresses(environ)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	length := len(instances)
-	if length == 0 {
-		return errors.Errorf("found no instances, expected at least one")
-	}
-	if length > 1 {
-		logger.Warningf("expected one instance, got %d", length)
-	}
-	bootstrapInstance := instances[0]
 	cfg := environ.Config()
 	info, err := envcmd.ConnectionInfoForName(c.ConnectionName())
 	if err != nil {
@@ -301,10 +378,6 @@ func (c *BootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ)
 	// Don't use c.ConnectionEndpoint as it attempts to contact the state
 	// server if no addresses are found in connection info.
 	endpoint := info.APIEndpoint()
-	netAddrs, err := bootstrapInstance.Addresses()
-	if err != nil {
-		return errors.Annotate(err, "failed to get bootstrap instance addresses")
-	}
 	apiPort := cfg.APIPort()
 	apiHostPorts := network.AddressesWithPort(netAddrs, apiPort)
 	addrs, hosts, addrsChanged := prepareEndpointsForCaching(
@@ -327,3 +400,28 @@ func (c *BootstrapCommand) SetBootstrapEndpointAddress(environ environs.Environ)
 	}
 	return nil
 }
+
+// bootstrapAddresses returns the addresses of the bootstrap machine.
+// An existing machine that was bootstrapped is not one of the
+// provider's instances, so its address is the one it was given by.
+func (c *BootstrapCommand) bootstrapAddresses(environ environs.Environ) ([]network.Address, error) {
+	if c.BootstrapHost != "" {
+		return []network.Address{network.NewAddress(c.BootstrapHost)}, nil
+	}
+	instances, err := allInstances(environ)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	length := len(instances)
+	if length == 0 {
+		return nil, errors.Errorf("found no instances, expected at least one")
+	}
+	if length > 1 {
+		logger.Warningf("expected one instance, got %d", length)
+	}
+	netAddrs, err := instances[0].Addresses()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to get bootstrap instance addresses")
+	}
+	return netAddrs, nil
+}
@@ -5,8 +5,10 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -68,6 +70,12 @@ func (s *BootstrapSuite) SetUpTest(c *gc.C) {
 	s.PatchValue(&envtools.DefaultBaseURL, sourceDir)
 
 	s.PatchValue(&envtools.BundleTools, toolstesting.GetMockBundleTools(c))
+
+	// The dummy provider's API server does not listen on the
+	// addresses of its instances.
+	s.PatchValue(&waitForAPI, func([]network.HostPort, config.SSHTimeoutOpts) error {
+		return nil
+	})
 }
 
 func (s *BootstrapSuite) TearDownSuite(c *gc.C) {
@@ -99,11 +107,12 @@ type bootstrapTest struct {
 	err     string
 	// binary version string for expected tools; if set, no default tools
 	// will be uploaded before running the test.
-	upload      string
-	constraints constraints.Value
-	placement   string
-	hostArch    string
-	keepBroken  bool
+	upload        string
+	constraints   constraints.Value
+	placement     string
+	bootstrapHost string
+	hostArch      string
+	keepBroken    bool
 }
 
 func (s *BootstrapSuite) run(c *gc.C, test bootstrapTest) (restore gitjujutesting.Restorer) {
@@ -164,6 +173,7 @@ func (s *BootstrapSuite) run(c *gc.C, test bootstrapTest) (restore gitjujutestin
 	c.Check(opBootstrap.Env, gc.Equals, "peckham")
 	c.Check(opBootstrap.Args.Constraints, gc.DeepEquals, test.constraints)
 	c.Check(opBootstrap.Args.Placement, gc.Equals, test.placement)
+	c.Check(opBootstrap.Args.BootstrapHost, gc.Equals, test.bootstrapHost)
 
 	opFinalizeBootstrap := (<-opc).(dummy.OpFinalizeBootstrap)
 	c.Check(opFinalizeBootstrap.Env, gc.Equals, "peckham")
@@ -185,6 +195,9 @@ func (s *BootstrapSuite) run(c *gc.C, test bootstrapTest) (restore gitjujutestin
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(info, gc.NotNil)
 	c.Assert(prepareCalled, jc.IsTrue)
+	if test.bootstrapHost != "" {
+		addrConnectedTo = net.JoinHostPort(test.bootstrapHost, strconv.Itoa(env.Config().APIPort()))
+	}
 	c.Assert(info.APIEndpoint().Addresses, gc.DeepEquals, []string{addrConnectedTo})
 	return restore
 }
@@ -256,6 +269,18 @@ var bootstrapTests = []bootstrapTest{{
 	info:      "placement",
 	args:      []string{"--to", "something"},
 	placement: "something",
+}, {
+	info:          "bootstrap to existing host",
+	args:          []string{"--bootstrap-to", "10.0.0.1"},
+	bootstrapHost: "10.0.0.1",
+}, {
+	info: "bootstrap to existing host with placement",
+	args: []string{"--bootstrap-to", "10.0.0.1", "--to", "something"},
+	err:  `--to and --bootstrap-to can't be used together`,
+}, {
+	info: "bootstrap to existing host with user",
+	args: []string{"--bootstrap-to", "admin@10.0.0.1"},
+	err:  `invalid bootstrap host "admin@10.0.0.1": the ubuntu user is always used`,
 }, {
 	info:       "keep broken",
 	args:       []string{"--keep-broken"},
@@ -319,6 +344,64 @@ func (s *BootstrapSuite) checkSeriesArg(c *gc.C, argVariant string) *cmd.Context
 	return ctx
 }
 
+func (s *BootstrapSuite) TestBootstrapWaitsForAPIServer(c *gc.C) {
+	_bootstrap := &fakeBootstrapFuncs{}
+	s.PatchValue(&getBootstrapFuncs, func() BootstrapInterface {
+		return _bootstrap
+	})
+	env := resetJujuHome(c, "devenv")
+	s.PatchValue(&allInstances, func(environ environs.Environ) ([]instance.Instance, error) {
+		return []instance.Instance{&mockBootstrapInstance{}}, nil
+	})
+	var waitedFor []network.HostPort
+	s.PatchValue(&waitForAPI, func(hostPorts []network.HostPort, opts config.SSHTimeoutOpts) error {
+		waitedFor = hostPorts
+		return nil
+	})
+
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&BootstrapCommand{}), "-e", "devenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(_bootstrap.args.Progress, gc.NotNil)
+	c.Assert(waitedFor, jc.DeepEquals, network.AddressesWithPort(
+		[]network.Address{{Value: "localhost"}}, env.Config().APIPort(),
+	))
+}
+
+func (s *BootstrapSuite) TestBootstrapReportsAPIServerFailure(c *gc.C) {
+	_bootstrap := &fakeBootstrapFuncs{}
+	s.PatchValue(&getBootstrapFuncs, func() BootstrapInterface {
+		return _bootstrap
+	})
+	resetJujuHome(c, "devenv")
+	s.PatchValue(&allInstances, func(environ environs.Environ) ([]instance.Instance, error) {
+		return []instance.Instance{&mockBootstrapInstance{}}, nil
+	})
+	s.PatchValue(&waitForAPI, func([]network.HostPort, config.SSHTimeoutOpts) error {
+		return errors.New("timed out")
+	})
+
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&BootstrapCommand{}), "-e", "devenv")
+	c.Assert(err, gc.ErrorMatches, "timed out")
+}
+
+func (s *BootstrapSuite) TestBootstrapProgressReportsRetries(c *gc.C) {
+	ctx := coretesting.Context(c)
+	progress := bootstrapProgress(ctx)
+	progress(bootstrap.ProgressEvent{
+		Phase:   bootstrap.PhaseStartInstance,
+		Status:  bootstrap.PhaseStarted,
+		Attempt: 1,
+	})
+	progress(bootstrap.ProgressEvent{
+		Phase:   bootstrap.PhaseStartInstance,
+		Status:  bootstrap.PhaseRetrying,
+		Attempt: 2,
+		Err:     errors.New("rate limit exceeded"),
+	})
+	c.Assert(coretesting.Stderr(ctx), gc.Equals,
+		"Bootstrap phase start-instance retrying (attempt 2): rate limit exceeded\n")
+}
+
 // In the case where we cannot examine an environment, we want the
 // error to propagate back up to the user.
 func (s *BootstrapSuite) TestBootstrapPropagatesEnvErrors(c *gc.C) {
@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.controller")
+
+const controllerCommandDoc = `
+"juju controller" provides commands to manage the state server machines
+of the Juju environment.
+`
+
+const controllerCommandPurpose = "manage state server machines"
+
+// NewSuperCommand creates the controller supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	controllerCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "controller",
+		Doc:         controllerCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     controllerCommandPurpose,
+	})
+	controllerCmd.Register(envcmd.Wrap(&MigrateMachineCommand{}))
+	return controllerCmd
+}
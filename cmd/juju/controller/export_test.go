@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+var RetireDelay = &retireDelay
+
+// NewMigrateMachineCommand returns a MigrateMachineCommand with the api
+// provided as specified.
+func NewMigrateMachineCommand(api MigrateMachineAPI) *MigrateMachineCommand {
+	return &MigrateMachineCommand{
+		api: api,
+	}
+}
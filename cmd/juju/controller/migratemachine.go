@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/constraints"
+)
+
+// MigrateMachineCommand replaces a state server machine with a new
+// one, so that state servers can be moved to larger instances.
+type MigrateMachineCommand struct {
+	envcmd.EnvCommandBase
+	api         MigrateMachineAPI
+	MachineId   string
+	Constraints constraints.Value
+	Series      string
+	Placement   string
+	Timeout     time.Duration
+}
+
+const migrateMachineDoc = `
+migrate-machine replaces a state server machine with a new machine, started
+with the given constraints, series and placement directive. This allows state
+servers to be moved to larger (or smaller) instances without a backup and
+restore.
+
+The new machine joins the state servers' mongo replica set, and takes over the
+vote of the machine it replaces once its database has caught up. The API server
+addresses known to the agents are updated automatically, and the old machine is
+then removed from the environment.
+
+The command waits until the old machine has been removed, or the timeout is
+reached. If it is interrupted, running it again for the same machine resumes
+waiting. Use "juju controller-health" to follow the progress of the database
+replication.
+
+Examples:
+
+  juju controller migrate-machine 0 --constraints mem=16G
+  juju controller migrate-machine 2 --to zone=us-east-1c
+`
+
+func (c *MigrateMachineCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "migrate-machine",
+		Args:    "<machine>",
+		Purpose: "replace a state server machine with a new one",
+		Doc:     migrateMachineDoc,
+	}
+}
+
+func (c *MigrateMachineCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "constraints for the new machine")
+	f.StringVar(&c.Series, "series", "", "the series of the new machine; defaults to that of the old machine")
+	f.StringVar(&c.Placement, "to", "", "a placement directive for the new machine")
+	f.DurationVar(&c.Timeout, "timeout", 30*time.Minute, "how long to wait for the old machine to be retired")
+}
+
+func (c *MigrateMachineCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine specified")
+	}
+	c.MachineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.MachineId) {
+		return errors.Errorf("invalid machine id %q", c.MachineId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// MigrateMachineAPI defines the API methods used by the migrate-machine
+// command.
+type MigrateMachineAPI interface {
+	MigrateStateServer(machineId string, cons constraints.Value, series, placement string) (string, error)
+	RetireStateServer(machineId string) (bool, error)
+	Close() error
+}
+
+func (c *MigrateMachineCommand) getAPI() (MigrateMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return highavailability.NewClient(root), nil
+}
+
+// retireDelay is how long to wait between attempts to retire the
+// old state server machine.
+var retireDelay = 10 * time.Second
+
+func (c *MigrateMachineCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	newId, err := api.MigrateStateServer(c.MachineId, c.Constraints, c.Series, c.Placement)
+	api.Close()
+	switch {
+	case params.IsCodeAlreadyExists(err):
+		// An earlier run was interrupted; resume waiting.
+		logger.Debugf("%v", err)
+	case err != nil:
+		return block.ProcessBlockedError(err, block.BlockChange)
+	default:
+		ctx.Infof("adding machine %s to replace state server machine %s", newId, c.MachineId)
+	}
+	return c.waitForRetirement(ctx)
+}
+
+// waitForRetirement retires the old state server machine, once its
+// replacement has taken over its vote. A new API connection is made
+// on every attempt, so the cached API addresses are kept up to date
+// as the state servers change.
+func (c *MigrateMachineCommand) waitForRetirement(ctx *cmd.Context) error {
+	ctx.Infof("waiting for the new machine to take over the vote of machine %s", c.MachineId)
+	attempt := utils.AttemptStrategy{
+		Total: c.Timeout,
+		Delay: retireDelay,
+	}
+	for a := attempt.Start(); a.Next(); {
+		retired, err := c.retire()
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockRemove)
+		}
+		if retired {
+			ctx.Infof("state server machine %s retired", c.MachineId)
+			return nil
+		}
+		logger.Debugf("machine %s still holds its vote", c.MachineId)
+	}
+	return errors.Errorf("timed out waiting for machine %s to be retired", c.MachineId)
+}
+
+func (c *MigrateMachineCommand) retire() (bool, error) {
+	api, err := c.getAPI()
+	if err != nil {
+		return false, err
+	}
+	defer api.Close()
+	return api.RetireStateServer(c.MachineId)
+}
@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type MigrateMachineSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeMigrateMachineAPI
+}
+
+var _ = gc.Suite(&MigrateMachineSuite{})
+
+func (s *MigrateMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeMigrateMachineAPI{newId: "3"}
+	s.PatchValue(controller.RetireDelay, time.Duration(0))
+}
+
+func (s *MigrateMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	migrate := controller.NewMigrateMachineCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(migrate), args...)
+}
+
+func (s *MigrateMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		machineId string
+		timeout   time.Duration
+		err       string
+	}{{
+		err: "no machine specified",
+	}, {
+		args:      []string{"0"},
+		machineId: "0",
+		timeout:   30 * time.Minute,
+	}, {
+		args:      []string{"2", "--timeout", "1h"},
+		machineId: "2",
+		timeout:   time.Hour,
+	}, {
+		args: []string{"lxc"},
+		err:  `invalid machine id "lxc"`,
+	}, {
+		args: []string{"0", "1"},
+		err:  `unrecognized args: \["1"\]`,
+	}} {
+		c.Logf("test %d", i)
+		migrateCmd := &controller.MigrateMachineCommand{}
+		err := testing.InitCommand(migrateCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(migrateCmd.MachineId, gc.Equals, test.machineId)
+		c.Check(migrateCmd.Timeout, gc.Equals, test.timeout)
+	}
+}
+
+func (s *MigrateMachineSuite) TestMigrate(c *gc.C) {
+	s.fake.votingAttempts = 2
+	ctx, err := s.run(c, "0", "--constraints", "mem=16G", "--series", "trusty", "--to", "zone=a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machineId, gc.Equals, "0")
+	c.Assert(s.fake.cons, gc.DeepEquals, constraints.MustParse("mem=16G"))
+	c.Assert(s.fake.series, gc.Equals, "trusty")
+	c.Assert(s.fake.placement, gc.Equals, "zone=a")
+	c.Assert(s.fake.retireCalls, gc.Equals, 3)
+	c.Assert(testing.Stderr(ctx), gc.Equals, `
+adding machine 3 to replace state server machine 0
+waiting for the new machine to take over the vote of machine 0
+state server machine 0 retired
+`[1:])
+}
+
+func (s *MigrateMachineSuite) TestMigrateResumes(c *gc.C) {
+	s.fake.migrateErr = &params.Error{
+		Message: "machine 0 is already being retired",
+		Code:    params.CodeAlreadyExists,
+	}
+	_, err := s.run(c, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.retireCalls, gc.Equals, 1)
+}
+
+func (s *MigrateMachineSuite) TestMigrateError(c *gc.C) {
+	s.fake.migrateErr = errors.New("machine 1 is not a state server")
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "machine 1 is not a state server")
+	c.Assert(s.fake.retireCalls, gc.Equals, 0)
+}
+
+func (s *MigrateMachineSuite) TestMigrateTimeout(c *gc.C) {
+	s.fake.votingAttempts = 1 << 30
+	_, err := s.run(c, "0", "--timeout", "1ms")
+	c.Assert(err, gc.ErrorMatches, "timed out waiting for machine 0 to be retired")
+}
+
+func (s *MigrateMachineSuite) TestRetireError(c *gc.C) {
+	s.fake.retireErr = errors.New("boom")
+	_, err := s.run(c, "0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeMigrateMachineAPI struct {
+	newId      string
+	migrateErr error
+	retireErr  error
+
+	// votingAttempts holds the number of calls to RetireStateServer
+	// for which the machine still holds its vote.
+	votingAttempts int
+
+	machineId   string
+	cons        constraints.Value
+	series      string
+	placement   string
+	retireCalls int
+}
+
+func (f *fakeMigrateMachineAPI) MigrateStateServer(machineId string, cons constraints.Value, series, placement string) (string, error) {
+	f.machineId = machineId
+	f.cons = cons
+	f.series = series
+	f.placement = placement
+	if f.migrateErr != nil {
+		return "", f.migrateErr
+	}
+	return f.newId, nil
+}
+
+func (f *fakeMigrateMachineAPI) RetireStateServer(machineId string) (bool, error) {
+	f.retireCalls++
+	if f.retireErr != nil {
+		return false, f.retireErr
+	}
+	return f.retireCalls > f.votingAttempts, nil
+}
+
+func (f *fakeMigrateMachineAPI) Close() error {
+	return nil
+}
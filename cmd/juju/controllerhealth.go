@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const controllerHealthDoc = `
+Show the members of the state servers' mongo replica set, with the
+state, health, votes and replication lag of each. This is the place
+to start when diagnosing problems with a highly available
+environment.
+
+Examples:
+
+  juju controller-health
+  juju controller-health --format yaml
+`
+
+// ControllerHealthCommand shows the health of the state servers'
+// replica set.
+type ControllerHealthCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *ControllerHealthCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "controller-health",
+		Purpose: "show the health of the state servers' replica set",
+		Doc:     controllerHealthDoc,
+	}
+}
+
+func (c *ControllerHealthCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatControllerHealthTabular,
+	})
+}
+
+func (c *ControllerHealthCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// ControllerHealthAPI defines the API methods used by the
+// controller-health command.
+type ControllerHealthAPI interface {
+	ReplicaSetStatus() (params.ReplicaSetStatusResult, error)
+	Close() error
+}
+
+var getControllerHealthAPI = func(c *ControllerHealthCommand) (ControllerHealthAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return highavailability.NewClient(root), nil
+}
+
+// replicaSetMember defines the serialization of a replica set member.
+type replicaSetMember struct {
+	Id      int    `yaml:"id" json:"id"`
+	Machine string `yaml:"machine,omitempty" json:"machine,omitempty"`
+	Address string `yaml:"address" json:"address"`
+	State   string `yaml:"state" json:"state"`
+	Healthy bool   `yaml:"healthy" json:"healthy"`
+	Votes   int    `yaml:"votes" json:"votes"`
+	Lag     string `yaml:"lag" json:"lag"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// Run shows the health of the replica set.
+func (c *ControllerHealthCommand) Run(ctx *cmd.Context) error {
+	client, err := getControllerHealthAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	status, err := client.ReplicaSetStatus()
+	if err != nil {
+		return err
+	}
+	output := make([]replicaSetMember, len(status.Members))
+	for i, m := range status.Members {
+		output[i] = replicaSetMember{
+			Id:      m.Id,
+			Address: m.Address,
+			State:   m.State,
+			Healthy: m.Healthy,
+			Votes:   m.Votes,
+			Lag:     fmt.Sprintf("%.1fs", m.Lag.Seconds()),
+			Error:   m.Error,
+		}
+		if tag, err := names.ParseMachineTag(m.MachineTag); err == nil {
+			output[i].Machine = tag.Id()
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatControllerHealthTabular returns a tabular summary of the
+// replica set members.
+func formatControllerHealthTabular(value interface{}) ([]byte, error) {
+	members, ok := value.([]replicaSetMember)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", members, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "ID\tMACHINE\tADDRESS\tSTATE\tHEALTHY\tVOTES\tLAG\tERROR\n")
+	for _, m := range members {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%t\t%d\t%s\t%s\n", m.Id, m.Machine, m.Address, m.State, m.Healthy, m.Votes, m.Lag, m.Error)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerHealthSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeControllerHealthAPI
+}
+
+var _ = gc.Suite(&ControllerHealthSuite{})
+
+func (s *ControllerHealthSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeControllerHealthAPI{
+		status: params.ReplicaSetStatusResult{
+			Name: "juju",
+			Members: []params.ReplicaSetMember{{
+				Id:         1,
+				Address:    "10.0.0.1:37017",
+				MachineTag: "machine-0",
+				State:      "PRIMARY",
+				Healthy:    true,
+				Votes:      1,
+			}, {
+				Id:         2,
+				Address:    "10.0.0.2:37017",
+				MachineTag: "machine-1",
+				State:      "SECONDARY",
+				Healthy:    true,
+				Votes:      1,
+				Lag:        3 * time.Second,
+			}, {
+				Id:      3,
+				Address: "10.0.0.3:37017",
+				State:   "UNKNOWN",
+				Error:   "no route to host",
+			}},
+		},
+	}
+	s.PatchValue(&getControllerHealthAPI, func(_ *ControllerHealthCommand) (ControllerHealthAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *ControllerHealthSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ControllerHealthCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ControllerHealthSuite) TestRunTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ControllerHealthCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID  MACHINE  ADDRESS         STATE      HEALTHY  VOTES  LAG   ERROR\n"+
+		"1   0        10.0.0.1:37017  PRIMARY    true     1      0.0s  \n"+
+		"2   1        10.0.0.2:37017  SECONDARY  true     1      3.0s  \n"+
+		"3            10.0.0.3:37017  UNKNOWN    false    0      0.0s  no route to host\n")
+}
+
+func (s *ControllerHealthSuite) TestRunJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ControllerHealthCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[`+
+		`{"id":1,"machine":"0","address":"10.0.0.1:37017","state":"PRIMARY","healthy":true,"votes":1,"lag":"0.0s"},`+
+		`{"id":2,"machine":"1","address":"10.0.0.2:37017","state":"SECONDARY","healthy":true,"votes":1,"lag":"3.0s"},`+
+		`{"id":3,"address":"10.0.0.3:37017","state":"UNKNOWN","healthy":false,"votes":0,"lag":"0.0s","error":"no route to host"}`+
+		`]`+"\n")
+}
+
+type fakeControllerHealthAPI struct {
+	status params.ReplicaSetStatusResult
+	closed bool
+}
+
+func (f *fakeControllerHealthAPI) ReplicaSetStatus() (params.ReplicaSetStatusResult, error) {
+	return f.status, nil
+}
+
+func (f *fakeControllerHealthAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -20,10 +20,12 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 )
 
@@ -33,11 +35,40 @@ type DeployCommand struct {
 	CharmName    string
 	ServiceName  string
 	Config       cmd.FileVar
+	ExpandEnv    bool
 	Constraints  constraints.Value
 	Networks     string
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
 
+	// Channel is the charm store channel from which the charm is
+	// deployed; the stable channel is used if it is empty.
+	Channel string
+
+	// CharmPath is the path of the charm directory or archive to
+	// deploy, when one is given in place of a charm name.
+	CharmPath string
+
+	// BundlePath is the path of the bundle file to deploy, when one
+	// is given in place of a charm name.
+	BundlePath string
+
+	// Watch causes the charm at CharmPath to be watched after it is
+	// deployed, and the service upgraded whenever it changes.
+	Watch bool
+
+	// Bind holds the value of the --bind flag, from which SpaceBinding
+	// and EndpointBindings are parsed.
+	Bind string
+
+	// SpaceBinding is the network space to which all the endpoints of
+	// the service are bound, if any.
+	SpaceBinding string
+
+	// EndpointBindings maps the names of endpoints to the network
+	// spaces they are bound to instead of SpaceBinding.
+	EndpointBindings map[string]string
+
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
 	//
@@ -63,10 +94,27 @@ setting, followed by the preferred series for the charm in the charm store.
 In these cases, a versioned charm URL will be expanded as expected (for example,
 mysql-33 becomes cs:precise/mysql-33).
 
+Charm store charms are deployed from the stable channel of the charm store,
+unless another channel is chosen with --channel: the candidate channel holds
+charms being readied for release, and the edge channel the latest
+development versions.
+
 However, for local charms, when the default-series is not specified in the
 environment, one must specify the series. For example:
   local:precise/mysql
 
+A charm directory or archive can also be deployed directly by giving its
+path, starting with "." or "/", in place of the charm name; it is deployed
+for the environment's default-series. With --watch, the charm directory is
+then watched until deploy is interrupted, and every change to it is
+uploaded and the service upgraded to it, to shorten the charm development
+loop.
+
+A bundle can be deployed by giving the path of its YAML file, ending in
+".yaml", in place of the charm name. The API server deploys the bundle's
+services, machines, units and relations, and every change it makes is
+reported; no service name can be given with a bundle.
+
 <service name>, if omitted, will be derived from <charm name>.
 
 Constraints can be specified when using deploy by specifying the --constraints
@@ -75,6 +123,8 @@ machines provisioned with add-unit will use the same constraints (unless changed
 by set-constraints).
 
 Charms can be deployed to a specific machine using the --to argument.
+An existing machine or container must be of the charm's series and, once
+provisioned, satisfy any constraints given with --constraints.
 If the destination is an LXC container the default is to use lxc-clone
 to create the container where possible. For Ubuntu deployments, lxc-clone
 is supported for the trusty OS series and later. A 'template' container is
@@ -101,11 +151,28 @@ Examples:
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
 
+   juju deploy mysql --channel edge
+   (deploy the latest development version of mysql from the charm store)
+
+   juju deploy ./wiki.yaml
+   (deploy the services, machines and relations of the bundle in wiki.yaml)
+
+   juju deploy --watch ./mycharm
+   (deploy the charm in ./mycharm, and upgrade it whenever it changes)
+
    juju deploy mysql --networks=storage,mynet --constraints networks=^logging,db
    (deploy mysql on machines with "storage", "mynet" and "db" networks,
     but not on machines with "logging" network, also configure "storage" and
     "mynet" networks)
 
+   juju deploy mysql --bind internal
+   (deploy mysql with all its endpoints bound to the "internal" space;
+    see "juju help space")
+
+   juju deploy mysql --bind "db=internal admin=dmz"
+   (deploy mysql with its "db" endpoint bound to the "internal" space
+    and its "admin" endpoint bound to the "dmz" space)
+
 Like constraints, service-specific network requirements can be
 specified with the --networks argument, which takes a comma-delimited
 list of juju-specific network names. Networks can also be specified with
@@ -114,6 +181,13 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+The file given with --config holds service settings keyed by service
+name; YAML anchors may be used to share settings between services. A
+top-level "include" key names another config file, or a list of them,
+relative to the including file; the included settings are overridden by
+those of the including file. With --expand-env, ${NAME} in a setting
+value is replaced by the value of the environment variable NAME.
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -135,9 +209,13 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.BumpRevision, "u", false, "increment local charm directory revision (DEPRECATED)")
 	f.BoolVar(&c.BumpRevision, "upgrade", false, "")
 	f.Var(&c.Config, "config", "path to yaml-formatted service config")
+	f.BoolVar(&c.ExpandEnv, "expand-env", false, "expand ${NAME} environment variable references in the service config")
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set service constraints")
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
+	f.StringVar(&c.Bind, "bind", "", "bind the service's endpoints to network spaces")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
+	f.StringVar(&c.Channel, "channel", "", "charm store channel to deploy from: stable, candidate or edge")
+	f.BoolVar(&c.Watch, "watch", false, "upgrade the service whenever the local charm directory changes")
 	if featureflag.Enabled(feature.Storage) {
 		// NOTE: if/when the feature flag is removed, bump the client
 		// facade and check that the ServiceDeployWithNetworks facade
@@ -155,7 +233,14 @@ func (c *DeployCommand) Init(args []string) error {
 		c.ServiceName = args[1]
 		fallthrough
 	case 1:
-		if _, err := charm.InferURL(args[0], "fake"); err != nil {
+		if isBundlePath(args[0]) {
+			if c.ServiceName != "" {
+				return errors.New("cannot give a service name when deploying a bundle")
+			}
+			c.BundlePath = args[0]
+		} else if isCharmPath(args[0]) {
+			c.CharmPath = args[0]
+		} else if _, err := charm.InferURL(args[0], "fake"); err != nil {
 			return fmt.Errorf("invalid charm name %q", args[0])
 		}
 		c.CharmName = args[0]
@@ -164,10 +249,63 @@ func (c *DeployCommand) Init(args []string) error {
 	default:
 		return cmd.CheckEmpty(args[2:])
 	}
+	if c.Watch && c.CharmPath == "" {
+		return errors.New("--watch requires the path of a charm directory")
+	}
+	if c.Channel != "" && (c.CharmPath != "" || c.BundlePath != "") {
+		return errors.New("--channel cannot be used with the path of a charm")
+	}
+	if err := config.ValidateCharmChannel(c.Channel); err != nil {
+		return errors.Errorf("invalid --channel: %v", err)
+	}
+	if c.Bind != "" {
+		if c.BundlePath != "" {
+			return errors.New("--bind cannot be used when deploying a bundle")
+		}
+		if err := c.parseBind(); err != nil {
+			return err
+		}
+	}
 	return c.UnitCommandBase.Init(args)
 }
 
+// parseBind parses the value of the --bind flag: space-separated
+// "endpoint=space" pairs binding individual endpoints, and at most one
+// space name to which all the other endpoints are bound.
+func (c *DeployCommand) parseBind() error {
+	for _, field := range strings.Fields(c.Bind) {
+		endpoint, spaceName := "", field
+		if i := strings.Index(field, "="); i >= 0 {
+			endpoint, spaceName = field[:i], field[i+1:]
+			if endpoint == "" {
+				return errors.Errorf("invalid --bind endpoint binding %q", field)
+			}
+		}
+		if !network.IsValidSpaceName(spaceName) {
+			return errors.Errorf("invalid --bind space name %q", spaceName)
+		}
+		if endpoint == "" {
+			if c.SpaceBinding != "" {
+				return errors.New("--bind can only name one space for all endpoints")
+			}
+			c.SpaceBinding = spaceName
+			continue
+		}
+		if _, ok := c.EndpointBindings[endpoint]; ok {
+			return errors.Errorf("--bind binds endpoint %q more than once", endpoint)
+		}
+		if c.EndpointBindings == nil {
+			c.EndpointBindings = make(map[string]string)
+		}
+		c.EndpointBindings[endpoint] = spaceName
+	}
+	return nil
+}
+
 func (c *DeployCommand) Run(ctx *cmd.Context) error {
+	if c.BundlePath != "" {
+		return c.deployBundle(ctx)
+	}
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
@@ -183,18 +321,12 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	curl, err := resolveCharmURL(c.CharmName, client, conf)
-	if err != nil {
-		return err
-	}
-
-	repo, err := charmrepo.LegacyInferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
-	if err != nil {
-		return err
+	var curl *charm.URL
+	if c.CharmPath != "" {
+		curl, err = addCharmPathViaAPI(client, ctx, ctx.AbsPath(c.CharmPath), conf, nil)
+	} else {
+		curl, err = c.addCharm(client, ctx, conf)
 	}
-	repo = config.SpecializeCharmRepo(repo, conf)
-
-	curl, err = addCharmViaAPI(client, ctx, curl, repo)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
@@ -241,11 +373,32 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 
 	var configYAML []byte
 	if c.Config.Path != "" {
-		configYAML, err = c.Config.Read(ctx)
+		configYAML, err = service.ReadConfigFile(ctx, c.Config, c.ExpandEnv)
 		if err != nil {
 			return err
 		}
 	}
+	if c.SpaceBinding != "" || len(c.EndpointBindings) > 0 {
+		err = client.ServiceDeployWithBindings(params.ServiceDeploy{
+			ServiceName:      serviceName,
+			CharmUrl:         curl.String(),
+			NumUnits:         numUnits,
+			ConfigYAML:       string(configYAML),
+			Constraints:      c.Constraints,
+			ToMachineSpec:    c.ToMachineSpec,
+			Networks:         requestedNetworks,
+			Storage:          c.Storage,
+			SpaceBinding:     c.SpaceBinding,
+			EndpointBindings: c.EndpointBindings,
+		})
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("cannot use --bind: not supported by the API server")
+		}
+		if err := block.ProcessBlockedError(err, block.BlockChange); err != nil || !c.Watch {
+			return err
+		}
+		return c.watchCharmDir(ctx, client, serviceName, conf)
+	}
 	// TODO(axw) rename ServiceDeployWithNetworks to ServiceDeploy,
 	// and ServiceDeploy to ServiceDeployLegacy or some such.
 	err = client.ServiceDeployWithNetworks(
@@ -270,13 +423,36 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Constraints,
 			c.ToMachineSpec)
 	}
-	return block.ProcessBlockedError(err, block.BlockChange)
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil || !c.Watch {
+		return err
+	}
+	return c.watchCharmDir(ctx, client, serviceName, conf)
+}
+
+// addCharm resolves the charm named on the command line, and adds it
+// to the environment.
+func (c *DeployCommand) addCharm(client *api.Client, ctx *cmd.Context, conf *config.Config) (*charm.URL, error) {
+	curl, err := resolveCharmURL(c.CharmName, client, conf)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := charmrepo.LegacyInferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
+	if err != nil {
+		return nil, err
+	}
+	repo, err = config.CharmRepoForChannel(repo, c.Channel)
+	if err != nil {
+		return nil, err
+	}
+	repo = config.SpecializeCharmRepo(repo, conf)
+	return addCharmViaAPI(client, ctx, curl, repo, c.Channel, nil)
 }
 
 // addCharmViaAPI calls the appropriate client API calls to add the
-// given charm URL to state. Also displays the charm URL of the added
-// charm on stdout.
-func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo charmrepo.Interface) (*charm.URL, error) {
+// given charm URL to state, taking charm store charms from the given
+// channel. A local charm is uploaded as a delta from base, if that is
+// not nil. Also displays the charm URL of the added charm on stdout.
+func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo charmrepo.Interface, channel string, base *charm.URL) (*charm.URL, error) {
 	if curl.Revision < 0 {
 		latest, err := charmrepo.Latest(repo, curl)
 		if err != nil {
@@ -290,13 +466,13 @@ func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo
 		if err != nil {
 			return nil, err
 		}
-		stateCurl, err := client.AddLocalCharm(curl, ch)
+		stateCurl, err := addLocalCharm(client, curl, ch, base)
 		if err != nil {
 			return nil, err
 		}
 		curl = stateCurl
 	case "cs":
-		err := client.AddCharm(curl)
+		err := client.AddCharmWithChannel(curl, channel)
 		if err != nil {
 			return nil, err
 		}
@@ -307,6 +483,16 @@ func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo
 	return curl, nil
 }
 
+// addLocalCharm adds the given local charm to the environment. When
+// base is the URL of a local charm, such as the charm of the service
+// being upgraded, only the files that differ from it are uploaded.
+func addLocalCharm(client *api.Client, curl *charm.URL, ch charm.Charm, base *charm.URL) (*charm.URL, error) {
+	if base != nil && base.Schema == "local" {
+		return client.AddLocalCharmDelta(curl, ch, base)
+	}
+	return client.AddLocalCharm(curl, ch)
+}
+
 // parseNetworks returns a list of network names by parsing the
 // comma-delimited string value of --networks argument.
 func parseNetworks(networksValue string) []string {
@@ -5,7 +5,10 @@ package main
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -15,6 +18,7 @@ import (
 	"gopkg.in/juju/charm.v5-unstable"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
@@ -76,6 +80,36 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "--watch"},
+		err:  `--watch requires the path of a charm directory`,
+	}, {
+		args: []string{"craziness", "--channel", "beta"},
+		err:  `invalid --channel: charm channel "beta" not valid`,
+	}, {
+		args: []string{"./craziness", "--channel", "edge"},
+		err:  `--channel cannot be used with the path of a charm`,
+	}, {
+		args: []string{"./bundle.yaml", "service"},
+		err:  `cannot give a service name when deploying a bundle`,
+	}, {
+		args: []string{"craziness", "--bind", "Bad_Space"},
+		err:  `invalid --bind space name "Bad_Space"`,
+	}, {
+		args: []string{"craziness", "--bind", "db=Bad_Space"},
+		err:  `invalid --bind space name "Bad_Space"`,
+	}, {
+		args: []string{"craziness", "--bind", "=dmz"},
+		err:  `invalid --bind endpoint binding "=dmz"`,
+	}, {
+		args: []string{"craziness", "--bind", "dmz internal"},
+		err:  `--bind can only name one space for all endpoints`,
+	}, {
+		args: []string{"craziness", "--bind", "db=dmz db=internal"},
+		err:  `--bind binds endpoint "db" more than once`,
+	}, {
+		args: []string{"./bundle.yaml", "--bind", "dmz"},
+		err:  `--bind cannot be used when deploying a bundle`,
 	},
 }
 
@@ -108,6 +142,104 @@ func (s *DeploySuite) TestCharmDir(c *gc.C) {
 	s.AssertService(c, "dummy", curl, 1, 0)
 }
 
+func (s *DeploySuite) TestChannelWithLocalCharm(c *gc.C) {
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "--channel", "edge")
+	c.Assert(err, gc.ErrorMatches, `charm channel "edge" not supported`)
+	err = runDeploy(c, "local:dummy", "--channel", "stable")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DeploySuite) TestCharmPath(c *gc.C) {
+	dirPath := testcharms.Repo.ClonedDirPath(c.MkDir(), "dummy")
+	err := runDeploy(c, dirPath, "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	s.AssertService(c, "some-service-name", curl, 1, 0)
+}
+
+func (s *DeploySuite) TestCharmPathNotFound(c *gc.C) {
+	err := runDeploy(c, "./no-such-charm")
+	c.Assert(err, gc.ErrorMatches, `cannot read charm at ".*no-such-charm": .*`)
+}
+
+type fakeBundleAPI struct {
+	bundleYAML string
+	steps      []params.BundleStepResult
+	err        error
+}
+
+func (f *fakeBundleAPI) Deploy(bundleYAML string) ([]params.BundleStepResult, error) {
+	f.bundleYAML = bundleYAML
+	return f.steps, f.err
+}
+
+func (f *fakeBundleAPI) Close() error {
+	return nil
+}
+
+func (s *DeploySuite) TestBundle(c *gc.C) {
+	fake := &fakeBundleAPI{
+		steps: []params.BundleStepResult{{
+			BundleChange: params.BundleChange{Description: "add charm cs:trusty/mysql-42"},
+			Done:         true,
+		}, {
+			BundleChange: params.BundleChange{Description: "deploy service mysql using cs:trusty/mysql-42"},
+			Error:        &params.Error{Message: "boom"},
+		}},
+		err: errors.New("cannot deploy service mysql using cs:trusty/mysql-42: boom"),
+	}
+	s.PatchValue(&getBundleAPI, func(*DeployCommand) (BundleAPI, error) {
+		return fake, nil
+	})
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte("services: {}"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path)
+	c.Assert(err, gc.ErrorMatches, "cannot deploy service mysql using cs:trusty/mysql-42: boom")
+	c.Assert(fake.bundleYAML, gc.Equals, "services: {}")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "add charm cs:trusty/mysql-42\n")
+}
+
+func (s *DeploySuite) TestWatchCharmDir(c *gc.C) {
+	s.PatchValue(&charmWatchInterval, 10*time.Millisecond)
+	dirPath := testcharms.Repo.ClonedDirPath(c.MkDir(), "dummy")
+	changed := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- watchCharmDir(dirPath, stop, func() {
+			changed <- struct{}{}
+		})
+	}()
+
+	// Changes in hidden directories are ignored.
+	err := os.Mkdir(filepath.Join(dirPath, ".git"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-changed:
+		c.Fatalf("unexpected change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dirPath, "README"), []byte("changed"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-changed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for change")
+	}
+
+	close(stop)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for watch to stop")
+	}
+}
+
 func (s *DeploySuite) TestUpgradeReportsDeprecated(c *gc.C) {
 	testcharms.Repo.ClonedDirPath(s.SeriesPath, "dummy")
 	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "local:dummy", "-u")
@@ -209,6 +341,40 @@ func (s *DeploySuite) TestNetworks(c *gc.C) {
 	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2 networks=net1,net0,^net3,^net4"))
 }
 
+func (s *DeploySuite) TestSpaceBinding(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err = runDeploy(c, "local:dummy", "--bind", "internal")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	service, _ := s.AssertService(c, "dummy", curl, 1, 0)
+	c.Assert(service.SpaceBinding(), gc.Equals, "internal")
+}
+
+func (s *DeploySuite) TestEndpointBindings(c *gc.C) {
+	for _, name := range []string{"internal", "dmz"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err := runDeploy(c, "local:wordpress", "--bind", "db=internal url=dmz internal")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/wordpress-3")
+	service, _ := s.AssertService(c, "wordpress", curl, 1, 0)
+	c.Assert(service.SpaceBinding(), gc.Equals, "internal")
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{
+		"db":  "internal",
+		"url": "dmz",
+	})
+}
+
+func (s *DeploySuite) TestSpaceBindingUnknownSpace(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "--bind", "internal")
+	c.Assert(err, gc.ErrorMatches, `cannot bind service "dummy" to space "internal": .*`)
+}
+
 func (s *DeploySuite) TestStorageWithoutFeatureFlag(c *gc.C) {
 	err := runDeploy(c, "local:storage-block", "--storage", "data=1G")
 	c.Assert(err, gc.ErrorMatches, "flag provided but not defined: --storage")
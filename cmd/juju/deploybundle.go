@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// BundleAPI defines the API methods used to deploy bundles.
+type BundleAPI interface {
+	Deploy(bundleYAML string) ([]params.BundleStepResult, error)
+	Close() error
+}
+
+var getBundleAPI = func(c *DeployCommand) (BundleAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return bundle.NewClient(root), nil
+}
+
+// isBundlePath reports whether the name given in place of a charm name
+// is the path of a bundle file.
+func isBundlePath(name string) bool {
+	return isCharmPath(name) && strings.HasSuffix(name, ".yaml")
+}
+
+// deployBundle has the API server deploy the bundle at BundlePath,
+// and reports each change made in doing so.
+func (c *DeployCommand) deployBundle(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return err
+	}
+	client, err := getBundleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	steps, err := client.Deploy(string(data))
+	for _, step := range steps {
+		if step.Done {
+			ctx.Infof("%s", step.Description)
+		}
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
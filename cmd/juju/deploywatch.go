@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5-unstable"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/config"
+)
+
+// charmWatchInterval is how often a watched charm directory is
+// checked for changes.
+var charmWatchInterval = 2 * time.Second
+
+// isCharmPath reports whether the deploy argument names a charm on
+// the local filesystem rather than a charm URL.
+func isCharmPath(name string) bool {
+	return strings.HasPrefix(name, ".") || filepath.IsAbs(name)
+}
+
+// addCharmPathViaAPI adds the charm directory or archive at the given
+// path to the environment, for the environment's default series. The
+// charm is uploaded as a delta from base, if that is not nil.
+func addCharmPathViaAPI(client *api.Client, ctx *cmd.Context, path string, conf *config.Config, base *charm.URL) (*charm.URL, error) {
+	series, ok := conf.DefaultSeries()
+	if !ok {
+		return nil, errors.New("cannot deploy a charm from a path: no default-series set in the environment")
+	}
+	ch, err := charm.ReadCharm(path)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read charm at %q", path)
+	}
+	curl, err := charm.ParseURL(fmt.Sprintf("local:%s/%s-%d", series, ch.Meta().Name, ch.Revision()))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	curl, err = addLocalCharm(client, curl, ch, base)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Infof("Added charm %q to the environment.", curl)
+	return curl, nil
+}
+
+// watchCharmDir watches the charm directory deployed for the service
+// until the command is interrupted, uploading the charm and upgrading
+// the service to it whenever it changes. Failures to upgrade, such as
+// those caused by a half-edited metadata.yaml, are reported and the
+// watch continues.
+func (c *DeployCommand) watchCharmDir(ctx *cmd.Context, client *api.Client, serviceName string, conf *config.Config) error {
+	path := ctx.AbsPath(c.CharmPath)
+	if info, err := os.Stat(path); err != nil {
+		return errors.Trace(err)
+	} else if !info.IsDir() {
+		return errors.Errorf("cannot watch %q: not a charm directory", path)
+	}
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	stop := make(chan struct{})
+	go func() {
+		<-interrupted
+		close(stop)
+	}()
+
+	ctx.Infof("Watching %q for changes; interrupt to stop.", path)
+	upgrade := func() error {
+		base, err := client.ServiceGetCharmURL(serviceName)
+		if err != nil {
+			return err
+		}
+		curl, err := addCharmPathViaAPI(client, ctx, path, conf, base)
+		if err != nil {
+			return err
+		}
+		if err := client.ServiceSetCharm(serviceName, curl.String(), false); err != nil {
+			return err
+		}
+		ctx.Infof("Upgraded service %q to charm %q.", serviceName, curl)
+		return nil
+	}
+	return watchCharmDir(path, stop, func() {
+		if err := block.ProcessBlockedError(upgrade(), block.BlockChange); err != nil {
+			ctx.Infof("cannot upgrade service %q: %v", serviceName, err)
+		}
+	})
+}
+
+// watchCharmDir calls changed each time the contents of the charm
+// directory at path change, until stop is closed.
+func watchCharmDir(path string, stop <-chan struct{}, changed func()) error {
+	last, err := charmDirFingerprint(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(charmWatchInterval):
+		}
+		current, err := charmDirFingerprint(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if current != last {
+			last = current
+			changed()
+		}
+	}
+}
+
+// charmDirFingerprint returns a digest of the names, sizes and
+// modification times of the files in the charm directory. Hidden
+// directories, such as those of version control systems, are skipped,
+// as they are not part of the charm; so are the modification times of
+// directories, which change along with their hidden subdirectories.
+func charmDirFingerprint(path string) (string, error) {
+	hash := sha256.New()
+	err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && name != path && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(path, name)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			fmt.Fprintf(hash, "%s/\n", rel)
+			return nil
+		}
+		fmt.Fprintf(hash, "%s %d %d %v\n", rel, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return nil
+	})
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot read charm directory %q", path)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -42,6 +42,9 @@ type EnsureAvailabilityCommand struct {
 	Placement []string
 	// PlacementSpec holds the unparsed placement directives argument (--to).
 	PlacementSpec string
+	// AutoReplace specifies whether state servers that become
+	// unavailable should be replaced automatically.
+	AutoReplace bool
 }
 
 const ensureAvailabilityDoc = `
@@ -68,6 +71,12 @@ Examples:
      Ensure that 7 state servers are available, with machines server1 and
      server2 used first, and if necessary, newly created state server
      machines having the default series, and at least 8GB RAM.
+ juju ensure-availability --auto-replace
+     Ensure that the system is still in highly available mode, and
+     keep it so: state servers that become unavailable are replaced
+     automatically, as if ensure-availability had been run again.
+     Running ensure-availability without --auto-replace turns this
+     off.
 `
 
 // formatSimple marshals value to a yaml-formatted []byte, unless value is nil.
@@ -129,6 +138,7 @@ func (c *EnsureAvailabilityCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Series, "series", "", "the charm series")
 	f.StringVar(&c.PlacementSpec, "to", "", "the machine(s) to become state servers, bypasses constraints")
 	f.Var(constraints.ConstraintsValue{&c.Constraints}, "constraints", "additional machine constraints")
+	f.BoolVar(&c.AutoReplace, "auto-replace", false, "automatically replace state servers that become unavailable")
 	c.out.AddFlags(f, "simple", map[string]cmd.Formatter{
 		"yaml":   cmd.FormatYaml,
 		"json":   cmd.FormatJson,
@@ -171,7 +181,7 @@ type EnsureAvailabilityClient interface {
 	Close() error
 	EnsureAvailability(
 		numStateServers int, cons constraints.Value, series string,
-		placement []string) (params.StateServersChanges, error)
+		placement []string, autoReplace bool) (params.StateServersChanges, error)
 }
 
 func (c *EnsureAvailabilityCommand) getHAClient() (EnsureAvailabilityClient, error) {
@@ -202,6 +212,7 @@ func (c *EnsureAvailabilityCommand) Run(ctx *cmd.Context) error {
 		c.Constraints,
 		c.Series,
 		c.Placement,
+		c.AutoReplace,
 	)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
@@ -51,6 +51,7 @@ type fakeHAClient struct {
 	err             error
 	series          string
 	placement       []string
+	autoReplace     bool
 	result          params.StateServersChanges
 }
 
@@ -59,12 +60,13 @@ func (f *fakeHAClient) Close() error {
 }
 
 func (f *fakeHAClient) EnsureAvailability(numStateServers int, cons constraints.Value,
-	series string, placement []string) (params.StateServersChanges, error) {
+	series string, placement []string, autoReplace bool) (params.StateServersChanges, error) {
 
 	f.numStateServers = numStateServers
 	f.cons = cons
 	f.series = series
 	f.placement = placement
+	f.autoReplace = autoReplace
 
 	if f.err != nil {
 		return f.result, f.err
@@ -204,6 +206,17 @@ func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityWithPlacement(c *gc.C) {
 	c.Assert(s.fake.placement, gc.DeepEquals, expectedPlacement)
 }
 
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityWithAutoReplace(c *gc.C) {
+	_, err := s.runEnsureAvailability(c, "--auto-replace")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numStateServers, gc.Equals, 0)
+	c.Assert(s.fake.autoReplace, jc.IsTrue)
+
+	_, err = s.runEnsureAvailability(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.autoReplace, jc.IsFalse)
+}
+
 func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityErrors(c *gc.C) {
 	for _, n := range []int{-1, 2} {
 		_, err := s.runEnsureAvailability(c, "-n", fmt.Sprint(n))
@@ -31,17 +31,19 @@ func NewRetryProvisioningCommand(api RetryProvisioningAPI) *RetryProvisioningCom
 	}
 }
 
-// NewShareCommand returns a ShareCommand with the api provided as specified.
-func NewShareCommand(api ShareEnvironmentAPI) *ShareCommand {
+// NewShareCommand returns a ShareCommand with the apis provided as specified.
+func NewShareCommand(api ShareEnvironmentAPI, clientAPI ShareEnvironmentClientAPI) *ShareCommand {
 	return &ShareCommand{
-		api: api,
+		api:       api,
+		clientAPI: clientAPI,
 	}
 }
 
-// NewUnshareCommand returns an unshareCommand with the api provided as specified.
-func NewUnshareCommand(api UnshareEnvironmentAPI) *UnshareCommand {
+// NewUnshareCommand returns an unshareCommand with the apis provided as specified.
+func NewUnshareCommand(api UnshareEnvironmentAPI, clientAPI UnshareEnvironmentClientAPI) *UnshareCommand {
 	return &UnshareCommand{
-		api: api,
+		api:       api,
+		clientAPI: clientAPI,
 	}
 }
 
@@ -7,12 +7,14 @@ import (
 	"github.com/juju/names"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 )
 
 type fakeEnvSuite struct {
 	testing.FakeJujuHomeSuite
-	fake *fakeEnvAPI
+	fake       *fakeEnvAPI
+	fakeClient *fakeEnvClientAPI
 }
 
 func (s *fakeEnvSuite) SetUpTest(c *gc.C) {
@@ -23,7 +25,9 @@ func (s *fakeEnvSuite) SetUpTest(c *gc.C) {
 			"special": "special value",
 			"running": true,
 		},
+		version: 1,
 	}
+	s.fakeClient = &fakeEnvClientAPI{}
 }
 
 type fakeEnvAPI struct {
@@ -31,13 +35,21 @@ type fakeEnvAPI struct {
 	err         error
 	keys        []string
 	addUsers    []names.UserTag
+	access      string
 	removeUsers []names.UserTag
+	staged      params.StagedEnvironmentConfig
+	revision    int64
+	version     int
 }
 
 func (f *fakeEnvAPI) Close() error {
 	return nil
 }
 
+func (f *fakeEnvAPI) BestAPIVersion() int {
+	return f.version
+}
+
 func (f *fakeEnvAPI) EnvironmentGet() (map[string]interface{}, error) {
 	return f.values, nil
 }
@@ -47,13 +59,26 @@ func (f *fakeEnvAPI) EnvironmentSet(config map[string]interface{}) error {
 	return f.err
 }
 
+func (f *fakeEnvAPI) StageEnvironmentConfig(config map[string]interface{}, unset []string) (params.StagedEnvironmentConfig, error) {
+	f.keys = unset
+	return f.staged, f.err
+}
+
+func (f *fakeEnvAPI) ApplyEnvironmentConfig(config map[string]interface{}, unset []string, revision int64) error {
+	f.values = config
+	f.keys = unset
+	f.revision = revision
+	return f.err
+}
+
 func (f *fakeEnvAPI) EnvironmentUnset(keys ...string) error {
 	f.keys = keys
 	return f.err
 }
 
-func (f *fakeEnvAPI) ShareEnvironment(users ...names.UserTag) error {
+func (f *fakeEnvAPI) ShareEnvironment(access string, users ...names.UserTag) error {
 	f.addUsers = users
+	f.access = access
 	return f.err
 }
 
@@ -61,3 +86,25 @@ func (f *fakeEnvAPI) UnshareEnvironment(users ...names.UserTag) error {
 	f.removeUsers = users
 	return f.err
 }
+
+// fakeEnvClientAPI implements the Client API used to share environments
+// by API servers whose UserManager facade cannot.
+type fakeEnvClientAPI struct {
+	err         error
+	addUsers    []names.UserTag
+	removeUsers []names.UserTag
+}
+
+func (f *fakeEnvClientAPI) Close() error {
+	return nil
+}
+
+func (f *fakeEnvClientAPI) ShareEnvironment(users ...names.UserTag) error {
+	f.addUsers = users
+	return f.err
+}
+
+func (f *fakeEnvClientAPI) UnshareEnvironment(users ...names.UserTag) error {
+	f.removeUsers = users
+	return f.err
+}
@@ -14,11 +14,12 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 )
 
-// RetryProvisioningCommand updates machines' error status to tell
-// the provisoner that it should try to re-provision the machine.
+// RetryProvisioningCommand tells the provisioners that they should try
+// to re-provision the machines, units, volumes and filesystems whose
+// provisioning failed.
 type RetryProvisioningCommand struct {
 	envcmd.EnvCommandBase
-	Machines []names.MachineTag
+	Entities []names.Tag
 	api      RetryProvisioningAPI
 }
 
@@ -26,31 +27,67 @@ type RetryProvisioningCommand struct {
 // that the retry-provisioning command calls.
 type RetryProvisioningAPI interface {
 	Close() error
-	RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error)
+	RetryProvisioning(entities ...names.Tag) ([]params.ErrorResult, error)
 }
 
+const retryProvisioningDoc = `
+Retry provisioning the given machines, units, volumes and filesystems
+after a failure.
+
+Machines are identified by their ids and units by their names. Volumes
+and filesystems are identified by their tags, such as "volume-0" or
+"filesystem-0-1", since their ids may be mistaken for those of machines.
+
+A unit is retried by resolving its error and re-running its failed hook.
+
+Example:
+
+  juju retry-provisioning 0 wordpress/1 volume-2
+`
+
 func (c *RetryProvisioningCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "retry-provisioning",
-		Args:    "<machine> [...]",
-		Purpose: "retries provisioning for failed machines",
+		Args:    "<machine>|<unit>|<volume>|<filesystem> [...]",
+		Purpose: "retries provisioning for failed machines, units and storage",
+		Doc:     retryProvisioningDoc,
 	}
 }
 
 func (c *RetryProvisioningCommand) Init(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("no machine specified")
+		return fmt.Errorf("no machine, unit, volume or filesystem specified")
 	}
-	c.Machines = make([]names.MachineTag, len(args))
+	c.Entities = make([]names.Tag, len(args))
 	for i, arg := range args {
-		if !names.IsValidMachine(arg) {
-			return fmt.Errorf("invalid machine %q", arg)
+		tag, err := retryProvisioningTag(arg)
+		if err != nil {
+			return err
 		}
-		c.Machines[i] = names.NewMachineTag(arg)
+		c.Entities[i] = tag
 	}
 	return nil
 }
 
+// retryProvisioningTag returns the tag of the entity identified by the
+// given argument.
+func retryProvisioningTag(arg string) (names.Tag, error) {
+	switch {
+	case names.IsValidMachine(arg):
+		return names.NewMachineTag(arg), nil
+	case names.IsValidUnit(arg):
+		return names.NewUnitTag(arg), nil
+	}
+	tag, err := names.ParseTag(arg)
+	if err == nil {
+		switch tag.(type) {
+		case names.VolumeTag, names.FilesystemTag:
+			return tag, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid machine, unit, volume or filesystem %q", arg)
+}
+
 func (c *RetryProvisioningCommand) getAPI() (RetryProvisioningAPI, error) {
 	if c.api != nil {
 		return c.api, nil
@@ -65,7 +102,7 @@ func (c *RetryProvisioningCommand) Run(context *cmd.Context) error {
 	}
 	defer client.Close()
 
-	results, err := client.RetryProvisioning(c.Machines...)
+	results, err := client.RetryProvisioning(c.Entities...)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
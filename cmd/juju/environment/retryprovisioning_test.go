@@ -31,8 +31,9 @@ var _ = gc.Suite(&retryProvisioningSuite{})
 // about machines in the environment to mock out the behavior
 // of the real RetryProvisioning command.
 type fakeRetryProvisioningClient struct {
-	m   map[string]fakeMachine
-	err error
+	m        map[string]fakeMachine
+	err      error
+	entities []names.Tag
 }
 
 type fakeMachine struct {
@@ -44,18 +45,24 @@ func (f *fakeRetryProvisioningClient) Close() error {
 	return nil
 }
 
-func (f *fakeRetryProvisioningClient) RetryProvisioning(machines ...names.MachineTag) (
+func (f *fakeRetryProvisioningClient) RetryProvisioning(entities ...names.Tag) (
 	[]params.ErrorResult, error) {
 
 	if f.err != nil {
 		return nil, f.err
 	}
+	f.entities = entities
 
-	results := make([]params.ErrorResult, len(machines))
+	results := make([]params.ErrorResult, len(entities))
 
 	// For each of the machines passed in, verify that we have the
-	// id and that the info string is "broken".
-	for i, machine := range machines {
+	// id and that the info string is "broken". Other entities are
+	// always retried successfully.
+	for i, entity := range entities {
+		machine, ok := entity.(names.MachineTag)
+		if !ok {
+			continue
+		}
 		m, ok := f.m[machine.Id()]
 		if ok {
 			if m.info == "broken" {
@@ -97,10 +104,13 @@ var resolvedMachineTests = []struct {
 	stdErr string
 }{
 	{
-		err: `no machine specified`,
+		err: `no machine, unit, volume or filesystem specified`,
 	}, {
 		args: []string{"jeremy-fisher"},
-		err:  `invalid machine "jeremy-fisher"`,
+		err:  `invalid machine, unit, volume or filesystem "jeremy-fisher"`,
+	}, {
+		args: []string{"service-wordpress"},
+		err:  `invalid machine, unit, volume or filesystem "service-wordpress"`,
 	}, {
 		args:   []string{"42"},
 		stdErr: `machine 42 not found`,
@@ -140,6 +150,20 @@ func (s *retryProvisioningSuite) TestRetryProvisioning(c *gc.C) {
 	}
 }
 
+func (s *retryProvisioningSuite) TestRetryProvisioningEntities(c *gc.C) {
+	command := environment.NewRetryProvisioningCommand(s.fake)
+	_, err := testing.RunCommand(c, envcmd.Wrap(command),
+		"0", "wordpress/1", "volume-2", "filesystem-0-1",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.entities, jc.DeepEquals, []names.Tag{
+		names.NewMachineTag("0"),
+		names.NewUnitTag("wordpress/1"),
+		names.NewVolumeTag("2"),
+		names.NewFilesystemTag("0/1"),
+	})
+}
+
 func (s *retryProvisioningSuite) TestBlockRetryProvisioning(c *gc.C) {
 	s.fake.err = common.ErrOperationBlocked("TestBlockRetryProvisioning")
 	command := environment.NewRetryProvisioningCommand(s.fake)
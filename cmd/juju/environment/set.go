@@ -6,10 +6,13 @@ package environment
 import (
 	"fmt"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/juju/cmd"
 	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -18,13 +21,22 @@ type attributes map[string]interface{}
 
 type SetCommand struct {
 	envcmd.EnvCommandBase
-	api    SetEnvironmentAPI
-	values attributes
+	api      SetEnvironmentAPI
+	values   attributes
+	dryRun   bool
+	revision int64
 }
 
 const setEnvHelpDoc = `
 Updates the environment of a running Juju instance.  Multiple key/value pairs
 can be passed on as command line arguments.
+
+With --dry-run, the values are validated against the provider and the
+changes they would make to the environment are shown, along with the
+revision of the environment configuration they were validated against,
+but nothing is changed. Running the command again with the same values
+and --revision instead of --dry-run applies the changes, provided the
+configuration has not changed since that revision.
 `
 
 func (c *SetCommand) Info() *cmd.Info {
@@ -36,10 +48,21 @@ func (c *SetCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.dryRun, "dry-run", false, "validate the values and show the changes they would make, without applying them")
+	f.Int64Var(&c.revision, "revision", 0, "only set the values if the environment config is still at this revision")
+}
+
 func (c *SetCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return fmt.Errorf("no key, value pairs specified")
 	}
+	if c.dryRun && c.revision != 0 {
+		return fmt.Errorf("cannot specify both --dry-run and --revision")
+	}
+	if c.revision < 0 {
+		return fmt.Errorf("--revision must be positive")
+	}
 
 	options, err := keyvalues.Parse(args, true)
 	if err != nil {
@@ -61,6 +84,8 @@ type SetEnvironmentAPI interface {
 	Close() error
 	EnvironmentGet() (map[string]interface{}, error)
 	EnvironmentSet(config map[string]interface{}) error
+	StageEnvironmentConfig(config map[string]interface{}, unset []string) (params.StagedEnvironmentConfig, error)
+	ApplyEnvironmentConfig(config map[string]interface{}, unset []string, revision int64) error
 }
 
 func (c *SetCommand) getAPI() (SetEnvironmentAPI, error) {
@@ -91,5 +116,43 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 		}
 
 	}
+	switch {
+	case c.dryRun:
+		staged, err := client.StageEnvironmentConfig(c.values, nil)
+		if err != nil {
+			return err
+		}
+		if len(staged.Changes) == 0 {
+			ctx.Infof("No changes.")
+			return nil
+		}
+		writeEnvironmentConfigChanges(ctx, staged.Changes)
+		ctx.Infof("To apply these changes, run the command again with --revision %d instead of --dry-run.", staged.Revision)
+		return nil
+	case c.revision != 0:
+		err := client.ApplyEnvironmentConfig(c.values, nil, c.revision)
+		if params.IsCodeConflict(err) {
+			return fmt.Errorf("environment configuration has changed since revision %d; see juju environment set --dry-run", c.revision)
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.EnvironmentSet(c.values), block.BlockChange)
 }
+
+// writeEnvironmentConfigChanges writes a table of the given environment
+// config changes to the context's stdout.
+func writeEnvironmentConfigChanges(ctx *cmd.Context, changes []params.EnvironmentConfigChange) {
+	tw := tabwriter.NewWriter(ctx.Stdout, 0, 1, 2, ' ', 0)
+	fmt.Fprintf(tw, "KEY\tOLD\tNEW\n")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", change.Key, formatConfigValue(change.OldValue), formatConfigValue(change.NewValue))
+	}
+	tw.Flush()
+}
+
+func formatConfigValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprint(value)
+}
@@ -9,6 +9,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/testing"
@@ -41,6 +42,12 @@ func (s *SetSuite) TestInit(c *gc.C) {
 		}, {
 			args:       []string{"agent-version=2.0.0"},
 			errorMatch: "agent-version must be set via upgrade-juju",
+		}, {
+			args:       []string{"--dry-run", "--revision", "3", "special=extra"},
+			errorMatch: "cannot specify both --dry-run and --revision",
+		}, {
+			args:       []string{"--revision", "-1", "special=extra"},
+			errorMatch: "--revision must be positive",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -68,6 +75,49 @@ func (s *SetSuite) TestSettingKnownValue(c *gc.C) {
 	c.Check(c.GetTestLog(), jc.Contains, expected)
 }
 
+func (s *SetSuite) TestDryRun(c *gc.C) {
+	s.fake.staged = params.StagedEnvironmentConfig{
+		Revision: 42,
+		Changes: []params.EnvironmentConfigChange{
+			{Key: "special", OldValue: "special value", NewValue: "extra"},
+			{Key: "unknown", NewValue: "foo"},
+		},
+	}
+	ctx, err := s.run(c, "--dry-run", "special=extra", "unknown=foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"KEY      OLD            NEW\n"+
+		"special  special value  extra\n"+
+		"unknown  -              foo\n",
+	)
+	c.Assert(testing.Stderr(ctx), gc.Equals,
+		"To apply these changes, run the command again with --revision 42 instead of --dry-run.\n",
+	)
+	// Nothing was set.
+	c.Assert(s.fake.values["special"], gc.Equals, "special value")
+}
+
+func (s *SetSuite) TestDryRunNoChanges(c *gc.C) {
+	s.fake.staged = params.StagedEnvironmentConfig{Revision: 42}
+	ctx, err := s.run(c, "--dry-run", "special=special value")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No changes.\n")
+}
+
+func (s *SetSuite) TestRevision(c *gc.C) {
+	_, err := s.run(c, "--revision", "42", "special=extra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.values, jc.DeepEquals, map[string]interface{}{"special": "extra"})
+	c.Assert(s.fake.revision, gc.Equals, int64(42))
+}
+
+func (s *SetSuite) TestRevisionConflict(c *gc.C) {
+	s.fake.err = &params.Error{Code: params.CodeConflict, Message: "settings changed"}
+	_, err := s.run(c, "--revision", "42", "special=extra")
+	c.Assert(err, gc.ErrorMatches, `environment configuration has changed since revision 42; see juju environment set --dry-run`)
+}
+
 func (s *SetSuite) TestBlockedError(c *gc.C) {
 	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
 	_, err := s.run(c, "special=extra")
@@ -9,7 +9,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -26,16 +28,26 @@ Examples:
 
  juju environment share sam --environment myenv
      Give local user "sam" access to the environment named "myenv"
+
+ juju environment share auditor --acl=read
+     Give local user "auditor" read-only access to the current environment
+
+Sharing the environment with a user who already has access to it changes
+their access level.
  `
 
 // ShareCommand represents the command to share an environment with a user(s).
 type ShareCommand struct {
 	envcmd.EnvCommandBase
-	envName string
-	api     ShareEnvironmentAPI
+	envName   string
+	api       ShareEnvironmentAPI
+	clientAPI ShareEnvironmentClientAPI
 
 	// Users to share the environment with.
 	Users []names.UserTag
+
+	// Access is the access level given to the users.
+	Access string
 }
 
 // Info implements Command.Info.
@@ -48,10 +60,18 @@ func (c *ShareCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *ShareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Access, "acl", "write", `the users' access level, "read" or "write"`)
+}
+
 func (c *ShareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return errors.New("no users specified")
 	}
+	if c.Access != "read" && c.Access != "write" {
+		return errors.Errorf(`invalid --acl %q, expected "read" or "write"`, c.Access)
+	}
 
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
@@ -67,13 +87,33 @@ func (c *ShareCommand) getAPI() (ShareEnvironmentAPI, error) {
 	if c.api != nil {
 		return c.api, nil
 	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return usermanager.NewClient(root), nil
+}
+
+func (c *ShareCommand) getClientAPI() (ShareEnvironmentClientAPI, error) {
+	if c.clientAPI != nil {
+		return c.clientAPI, nil
+	}
 	return c.NewAPIClient()
 }
 
 // ShareEnvironmentAPI defines the API functions used by the environment share command.
 type ShareEnvironmentAPI interface {
 	Close() error
-	ShareEnvironment(...names.UserTag) error
+	BestAPIVersion() int
+	ShareEnvironment(access string, users ...names.UserTag) error
+}
+
+// ShareEnvironmentClientAPI defines the Client API functions used by
+// the environment share command when the API server's UserManager
+// facade cannot share environments.
+type ShareEnvironmentClientAPI interface {
+	Close() error
+	ShareEnvironment(users ...names.UserTag) error
 }
 
 func (c *ShareCommand) Run(ctx *cmd.Context) error {
@@ -83,5 +123,24 @@ func (c *ShareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if client.BestAPIVersion() < 1 {
+		return c.shareWithClientAPI()
+	}
+	return block.ProcessBlockedError(client.ShareEnvironment(c.Access, c.Users...), block.BlockChange)
+}
+
+// shareWithClientAPI shares the environment through the Client facade,
+// as API servers did before the UserManager facade could. Users are
+// always given write access.
+func (c *ShareCommand) shareWithClientAPI() error {
+	if c.Access != "write" {
+		return errors.Errorf("cannot use --acl %q: not supported by the API server", c.Access)
+	}
+	client, err := c.getClientAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
 	return block.ProcessBlockedError(client.ShareEnvironment(c.Users...), block.BlockChange)
 }
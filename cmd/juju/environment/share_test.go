@@ -23,7 +23,7 @@ type shareSuite struct {
 var _ = gc.Suite(&shareSuite{})
 
 func (s *shareSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
-	command := environment.NewShareCommand(s.fake)
+	command := environment.NewShareCommand(s.fake, s.fakeClient)
 	return testing.RunCommand(c, envcmd.Wrap(command), args...)
 }
 
@@ -40,6 +40,14 @@ func (s *shareSuite) TestInit(c *gc.C) {
 
 	err = testing.InitCommand(shareCmd, []string{"not valid/0"})
 	c.Assert(err, gc.ErrorMatches, `invalid username: "not valid/0"`)
+
+	shareCmd = &environment.ShareCommand{}
+	err = testing.InitCommand(shareCmd, []string{"--acl", "read", "sam"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(shareCmd.Access, gc.Equals, "read")
+
+	err = testing.InitCommand(&environment.ShareCommand{}, []string{"--acl", "admin", "sam"})
+	c.Assert(err, gc.ErrorMatches, `invalid --acl "admin", expected "read" or "write"`)
 }
 
 func (s *shareSuite) TestPassesValues(c *gc.C) {
@@ -49,6 +57,14 @@ func (s *shareSuite) TestPassesValues(c *gc.C) {
 	_, err := s.run(c, "sam", "ralph")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{sam, ralph})
+	c.Assert(s.fake.access, gc.Equals, "write")
+}
+
+func (s *shareSuite) TestPassesAccess(c *gc.C) {
+	_, err := s.run(c, "--acl", "read", "sam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addUsers, jc.DeepEquals, []names.UserTag{names.NewUserTag("sam")})
+	c.Assert(s.fake.access, gc.Equals, "read")
 }
 
 func (s *shareSuite) TestBlockShare(c *gc.C) {
@@ -57,3 +73,29 @@ func (s *shareSuite) TestBlockShare(c *gc.C) {
 	c.Assert(err, gc.Equals, cmd.ErrSilent)
 	c.Check(c.GetTestLog(), jc.Contains, "To unblock changes")
 }
+
+func (s *shareSuite) TestFallsBackToClientAPI(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c, "sam", "ralph")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.addUsers, gc.HasLen, 0)
+	c.Assert(s.fakeClient.addUsers, jc.DeepEquals, []names.UserTag{
+		names.NewUserTag("sam"), names.NewUserTag("ralph"),
+	})
+}
+
+func (s *shareSuite) TestClientAPIFallbackRefusesReadAccess(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c, "--acl", "read", "sam")
+	c.Assert(err, gc.ErrorMatches, `cannot use --acl "read": not supported by the API server`)
+	c.Assert(s.fake.addUsers, gc.HasLen, 0)
+	c.Assert(s.fakeClient.addUsers, gc.HasLen, 0)
+}
+
+func (s *shareSuite) TestBlockShareWithClientAPI(c *gc.C) {
+	s.fake.version = 0
+	s.fakeClient.err = &params.Error{Code: params.CodeOperationBlocked}
+	_, err := s.run(c, "sam")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(c.GetTestLog(), jc.Contains, "To unblock changes")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -32,8 +33,9 @@ Examples:
 type UnshareCommand struct {
 	envcmd.EnvCommandBase
 	cmd.CommandBase
-	envName string
-	api     UnshareEnvironmentAPI
+	envName   string
+	api       UnshareEnvironmentAPI
+	clientAPI UnshareEnvironmentClientAPI
 
 	// Users to unshare the environment with.
 	Users []names.UserTag
@@ -67,12 +69,31 @@ func (c *UnshareCommand) getAPI() (UnshareEnvironmentAPI, error) {
 	if c.api != nil {
 		return c.api, nil
 	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return usermanager.NewClient(root), nil
+}
+
+func (c *UnshareCommand) getClientAPI() (UnshareEnvironmentClientAPI, error) {
+	if c.clientAPI != nil {
+		return c.clientAPI, nil
+	}
 	return c.NewAPIClient()
 }
 
 // UnshareEnvironmentAPI defines the API functions used by the environment
 // unshare command.
 type UnshareEnvironmentAPI interface {
+	UnshareEnvironmentClientAPI
+	BestAPIVersion() int
+}
+
+// UnshareEnvironmentClientAPI defines the Client API functions used by
+// the environment unshare command when the API server's UserManager
+// facade cannot unshare environments.
+type UnshareEnvironmentClientAPI interface {
 	Close() error
 	UnshareEnvironment(...names.UserTag) error
 }
@@ -84,5 +105,20 @@ func (c *UnshareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if client.BestAPIVersion() < 1 {
+		return c.unshareWithClientAPI()
+	}
+	return block.ProcessBlockedError(client.UnshareEnvironment(c.Users...), block.BlockChange)
+}
+
+// unshareWithClientAPI unshares the environment through the Client
+// facade, as API servers did before the UserManager facade could.
+func (c *UnshareCommand) unshareWithClientAPI() error {
+	client, err := c.getClientAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
 	return block.ProcessBlockedError(client.UnshareEnvironment(c.Users...), block.BlockChange)
 }
@@ -22,7 +22,7 @@ type unshareSuite struct {
 var _ = gc.Suite(&unshareSuite{})
 
 func (s *unshareSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
-	command := environment.NewUnshareCommand(s.fake)
+	command := environment.NewUnshareCommand(s.fake, s.fakeClient)
 	return testing.RunCommand(c, envcmd.Wrap(command), args...)
 }
 
@@ -56,3 +56,13 @@ func (s *unshareSuite) TestBlockUnShare(c *gc.C) {
 	c.Assert(err, gc.Equals, cmd.ErrSilent)
 	c.Check(c.GetTestLog(), jc.Contains, "To unblock changes")
 }
+
+func (s *unshareSuite) TestFallsBackToClientAPI(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c, "sam", "ralph")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.removeUsers, gc.HasLen, 0)
+	c.Assert(s.fakeClient.removeUsers, jc.DeepEquals, []names.UserTag{
+		names.NewUserTag("sam"), names.NewUserTag("ralph"),
+	})
+}
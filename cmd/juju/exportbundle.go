@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ExportBundleCommand writes out a bundle recreating the environment.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	Filename string
+}
+
+const exportBundleDoc = `
+Write out a bundle that recreates the services of the environment, with
+their charms, config, constraints and annotations, the relations between
+them and the machines their units are placed on. The bundle is written
+to standard output unless --output is given, and can be deployed to
+another environment with "juju deploy".
+
+Units in nested containers are placed in a container of the same type
+directly on a machine. Services using local charms refer to them by URL,
+so the charms must also be available to the environment the bundle is
+deployed to.
+
+Examples:
+   juju export-bundle
+   juju export-bundle --output mybundle.yaml
+`
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "write out a bundle recreating the environment",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "o", "", "write the bundle to the named file")
+	f.StringVar(&c.Filename, "output", "", "")
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// ExportBundleAPI defines the API methods used to export bundles.
+type ExportBundleAPI interface {
+	ExportBundle() (string, error)
+	Close() error
+}
+
+var getExportBundleAPI = func(c *ExportBundleCommand) (ExportBundleAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return bundle.NewClient(root), nil
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := getExportBundleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	data, err := client.ExportBundle()
+	if err != nil {
+		return err
+	}
+	if c.Filename == "" {
+		_, err = ctx.Stdout.Write([]byte(data))
+		return err
+	}
+	return ioutil.WriteFile(ctx.AbsPath(c.Filename), []byte(data), 0644)
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeExportBundleAPI
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func (s *ExportBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeExportBundleAPI{
+		bundleYAML: "services:\n  mysql:\n    charm: cs:trusty/mysql-42\n",
+	}
+	s.PatchValue(&getExportBundleAPI, func(_ *ExportBundleCommand) (ExportBundleAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *ExportBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ExportBundleSuite) TestRunStdout(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, s.fake.bundleYAML)
+}
+
+func (s *ExportBundleSuite) TestRunOutputFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), "--output", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, s.fake.bundleYAML)
+}
+
+func (s *ExportBundleSuite) TestRunError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.fake.closed, jc.IsTrue)
+}
+
+type fakeExportBundleAPI struct {
+	bundleYAML string
+	err        error
+	closed     bool
+}
+
+func (f *fakeExportBundleAPI) ExportBundle() (string, error) {
+	return f.bundleYAML, f.err
+}
+
+func (f *fakeExportBundleAPI) Close() error {
+	f.closed = true
+	return nil
+}
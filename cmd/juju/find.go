@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/find"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const findDoc = `
+Find the environment, machines, services and units carrying all of the
+given annotations, and print their tags, one per line by default.
+
+Annotations are set with the Annotations API, for example by the GUI.
+
+Examples:
+
+  # Find everything tagged for production.
+  juju find tag=production
+
+  # Find the production database machines.
+  juju find tag=production role=db
+`
+
+// FindCommand finds entities by their annotations.
+type FindCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	annotations map[string]string
+}
+
+func (c *FindCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "find",
+		Args:    "<key>=<value> ...",
+		Purpose: "find entities by their annotations",
+		Doc:     findDoc,
+	}
+}
+
+func (c *FindCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *FindCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no annotations specified")
+	}
+	annotations, err := keyvalues.Parse(args, false)
+	if err != nil {
+		return err
+	}
+	c.annotations = annotations
+	return nil
+}
+
+// FindAPI defines the API methods used by the find command.
+type FindAPI interface {
+	Find(annotations map[string]string) ([]string, error)
+	Close() error
+}
+
+var getFindAPI = func(c *FindCommand) (FindAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return find.NewClient(root), nil
+}
+
+// Run prints the tags of the entities carrying the annotations.
+func (c *FindCommand) Run(ctx *cmd.Context) error {
+	client, err := getFindAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	tags, err := client.Find(c.annotations)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, tags)
+}
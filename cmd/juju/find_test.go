@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type FindSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeFindAPI
+}
+
+var _ = gc.Suite(&FindSuite{})
+
+func (s *FindSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeFindAPI{
+		tags: []string{"machine-0", "unit-mysql-0"},
+	}
+	s.PatchValue(&getFindAPI, func(_ *FindCommand) (FindAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *FindSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected map[string]string
+		errMatch string
+	}{{
+		errMatch: "no annotations specified",
+	}, {
+		args:     []string{"tag=production", "role=db"},
+		expected: map[string]string{"tag": "production", "role": "db"},
+	}, {
+		args:     []string{"tag"},
+		errMatch: `expected "key=value", got "tag"`,
+	}, {
+		args:     []string{"tag=a", "tag=b"},
+		errMatch: `key "tag" specified more than once`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &FindCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.annotations, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *FindSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&FindCommand{}), "tag=production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.annotations, jc.DeepEquals, map[string]string{"tag": "production"})
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "machine-0\nunit-mysql-0\n")
+}
+
+func (s *FindSuite) TestRunJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&FindCommand{}), "--format", "json", "tag=production")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `["machine-0","unit-mysql-0"]`+"\n")
+}
+
+type fakeFindAPI struct {
+	annotations map[string]string
+	tags        []string
+	closed      bool
+}
+
+func (f *fakeFindAPI) Find(annotations map[string]string) ([]string, error) {
+	f.annotations = annotations
+	return f.tags, nil
+}
+
+func (f *fakeFindAPI) Close() error {
+	f.closed = true
+	return nil
+}
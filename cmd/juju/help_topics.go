@@ -400,6 +400,23 @@ instance-type
    conflict with other constraints depending on the provider (since the instance
    type my determine things like memory size etc.)
 
+virt-type
+   Virt-type defines the kind of virtualisation the machine itself must use,
+   as opposed to container, which asks juju to create a container for it.
+   Recognized values are:
+      kvm - a kvm virtual machine
+      lxc - an lxc container
+      metal - a bare metal machine
+   Not supported on all providers; MaaS only provides metal machines, and the
+   local provider only the kind of container it is configured to use.
+
+zones
+   Zones defines the list of availability zones the machine may be started in.
+   Multiple zones must be delimited by a comma. On providers with availability
+   zones, units of a service are spread across the allowed zones only. Zones
+   are currently only supported by the Amazon EC2 environment.
+   Example: zones=us-east-1a,us-east-1c
+
 Example:
 
    juju add-machine --constraints "arch=amd64 mem=8G tags=foo,^bar"
@@ -551,9 +568,8 @@ Service Unit Agent
 
 const helpLogging = `
 Juju has logging available for both client and server components. Most
-users' exposure to the logging mechanism is through either the 'debug-log'
-command, or through the log file stored on the bootstrap node at
-/var/log/juju/all-machines.log.
+users' exposure to the logging mechanism is through the 'debug-log'
+command, which shows the logs collected by the state servers.
 
 All the agents have their own log files on the individual machines. So
 for the bootstrap node, there is the machine agent log file at
@@ -562,10 +578,10 @@ a unit agent is started. This agent also logs to /var/log/juju and the
 name of the log file is based on the id of the unit, so for wordpress/0
 the log file is unit-wordpress-0.log.
 
-Juju uses rsyslog to forward the content of all the log files on the machine
-back to the bootstrap node, and they are accumulated into the all-machines.log
-file.  Each line is prefixed with the source agent tag (also the same as
-the filename without the extension).
+The agents also send their logs over a secure connection to the state
+servers, where they are stored in the database and can be viewed with
+"juju debug-log".  Each line is prefixed with the source agent tag (also
+the same as the filename without the extension).
 
 Juju has a hierarchical logging system internally, and as a user you can
 control how much information is logged out.
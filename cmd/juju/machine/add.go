@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils/featureflag"
+	"github.com/juju/utils/readpass"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -57,6 +58,11 @@ and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
 the same network as the API server.
 
+Windows machines can be manually provisioned with "winrm:[user@]host"; the
+machine must have a WinRM HTTPS listener with basic authentication enabled,
+and must have Cloudbase-Init installed. The password of the user, which
+defaults to Administrator, is prompted for.
+
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" with "--to"; these give the provider additional
 information about how to allocate the machine. For example, one can direct the
@@ -71,6 +77,7 @@ Examples:
    juju machine add lxc:4                (starts a new lxc container on machine 4)
    juju machine add --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju machine add ssh:user@10.10.0.3   (manually provisions a machine with ssh)
+   juju machine add winrm:10.10.0.4      (manually provisions a Windows machine with WinRM)
    juju machine add zone=us-east-1a
 
 See Also:
@@ -110,7 +117,7 @@ type AddCommand struct {
 func (c *AddCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add",
-		Args:    "[<container>:machine | <container> | ssh:[user@]host | placement]",
+		Args:    "[<container>:machine | <container> | ssh:[user@]host | winrm:[user@]host | placement]",
 		Purpose: "start a new, empty machine and optionally a container, or add a container to a machine",
 		Doc:     addMachineDoc,
 	}
@@ -160,7 +167,11 @@ type AddMachineAPI interface {
 	ProvisioningScript(params.ProvisioningScriptParams) (script string, err error)
 }
 
-var manualProvisioner = manual.ProvisionMachine
+var (
+	manualProvisioner        = manual.ProvisionMachine
+	manualWindowsProvisioner = manual.ProvisionWindowsMachine
+	readPassword             = readpass.ReadPassword
+)
 
 func (c *AddCommand) getAddMachineAPI() (AddMachineAPI, error) {
 	if c.api != nil {
@@ -204,6 +215,28 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.Placement != nil && c.Placement.Scope == "winrm" {
+		logger.Infof("manual provisioning over WinRM")
+		fmt.Fprintln(ctx.Stdout, "password:")
+		password, err := readPassword()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		args := manual.ProvisionMachineArgs{
+			Host:     c.Placement.Directive,
+			Password: password,
+			Client:   client,
+			Stdin:    ctx.Stdin,
+			Stdout:   ctx.Stdout,
+			Stderr:   ctx.Stderr,
+		}
+		machineId, err := manualWindowsProvisioner(args)
+		if err == nil {
+			ctx.Infof("created machine %v", machineId)
+		}
+		return err
+	}
+
 	logger.Infof("environment provisioning")
 	if c.Placement != nil && c.Placement.Scope == "env-uuid" {
 		c.Placement.Scope = client.EnvironmentUUID()
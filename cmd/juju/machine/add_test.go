@@ -79,6 +79,10 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"ssh:user@10.10.0.3"},
 			count:     1,
 			placement: "ssh:user@10.10.0.3",
+		}, {
+			args:      []string{"winrm:Administrator@10.10.0.4"},
+			count:     1,
+			placement: "winrm:Administrator@10.10.0.4",
 		}, {
 			args:      []string{"zone=us-east-1a"},
 			count:     1,
@@ -151,6 +155,35 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(testing.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) TestWinRMPlacement(c *gc.C) {
+	s.PatchValue(machine.ReadPassword, func() (string, error) {
+		return "secret", nil
+	})
+	var provisionArgs manual.ProvisionMachineArgs
+	s.PatchValue(machine.ManualWindowsProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		provisionArgs = args
+		return "42", nil
+	})
+	context, err := s.run(c, "winrm:juju@10.1.2.4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "password:\n")
+	c.Assert(testing.Stderr(context), gc.Equals, "created machine 42\n")
+	c.Assert(provisionArgs.Host, gc.Equals, "juju@10.1.2.4")
+	c.Assert(provisionArgs.Password, gc.Equals, "secret")
+}
+
+func (s *AddMachineSuite) TestWinRMPlacementError(c *gc.C) {
+	s.PatchValue(machine.ReadPassword, func() (string, error) {
+		return "secret", nil
+	})
+	s.PatchValue(machine.ManualWindowsProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		return "", errors.New("failed to initialize warp core")
+	})
+	context, err := s.run(c, "winrm:10.1.2.4")
+	c.Assert(err, gc.ErrorMatches, "failed to initialize warp core")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
@@ -252,7 +285,7 @@ func (f *fakeAddMachineAPI) AddMachines(args []params.AddMachineParams) ([]param
 		} else {
 			results = append(results, params.AddMachinesResult{
 				Machine: string(i),
-				Error:   &params.Error{"something went wrong", "1"},
+				Error:   &params.Error{Message: "something went wrong", Code: "1"},
 			})
 		}
 		f.currentOp++
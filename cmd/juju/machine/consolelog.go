@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/instanceconsole"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ConsoleLogCommand shows the console output of a machine's instance.
+type ConsoleLogCommand struct {
+	envcmd.EnvCommandBase
+	api       ConsoleLogAPI
+	MachineId string
+}
+
+const consoleLogDoc = `
+Show the console output of the instance of a machine, as captured by the
+provider. This is useful for diagnosing machines that were started by the
+provider, but whose agents never connected to the state server.
+
+The console output is only as recent as the provider's last capture of it,
+and not all providers support retrieving it.
+
+Example:
+	$ juju machine console-log 5
+`
+
+func (c *ConsoleLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "console-log",
+		Args:    "<machine>",
+		Purpose: "show the console output of a machine's instance",
+		Doc:     consoleLogDoc,
+	}
+}
+
+func (c *ConsoleLogCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machine specified")
+	}
+	c.MachineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+type ConsoleLogAPI interface {
+	ConsoleOutput(machineId string) (string, error)
+	Close() error
+}
+
+func (c *ConsoleLogCommand) getConsoleLogAPI() (ConsoleLogAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return instanceconsole.NewClient(root), nil
+}
+
+func (c *ConsoleLogCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getConsoleLogAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	output, err := client.ConsoleOutput(c.MachineId)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(ctx.Stdout, output)
+	return err
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type ConsoleLogSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeConsoleLogAPI
+}
+
+var _ = gc.Suite(&ConsoleLogSuite{})
+
+func (s *ConsoleLogSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeConsoleLogAPI{output: "Cloud-init finished\n"}
+}
+
+func (s *ConsoleLogSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	consoleLog := machine.NewConsoleLogCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(consoleLog), args...)
+}
+
+func (s *ConsoleLogSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		errorString: "no machine specified",
+	}, {
+		args:        []string{"lxc"},
+		errorString: `invalid machine id "lxc"`,
+	}, {
+		args:        []string{"1", "2"},
+		errorString: `unrecognized args: \["2"\]`,
+	}, {
+		args: []string{"1/lxc/0"},
+	}} {
+		c.Logf("test %d", i)
+		err := testing.InitCommand(&machine.ConsoleLogCommand{}, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *ConsoleLogSuite) TestConsoleLog(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "Cloud-init finished\n")
+	c.Assert(s.fake.machineId, gc.Equals, "1")
+	c.Assert(s.fake.closed, jc.IsTrue)
+}
+
+func (s *ConsoleLogSuite) TestConsoleLogError(c *gc.C) {
+	s.fake.err = errors.New("retrieving console output on provider \"local\" not supported")
+	ctx, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, `retrieving console output on provider "local" not supported`)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+}
+
+type fakeConsoleLogAPI struct {
+	machineId string
+	output    string
+	err       error
+	closed    bool
+}
+
+func (f *fakeConsoleLogAPI) ConsoleOutput(machineId string) (string, error) {
+	f.machineId = machineId
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.output, nil
+}
+
+func (f *fakeConsoleLogAPI) Close() error {
+	f.closed = true
+	return nil
+}
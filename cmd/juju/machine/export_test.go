@@ -6,7 +6,9 @@ package machine
 import "github.com/juju/juju/storage"
 
 var (
-	ManualProvisioner = &manualProvisioner
+	ManualProvisioner        = &manualProvisioner
+	ManualWindowsProvisioner = &manualWindowsProvisioner
+	ReadPassword             = &readPassword
 )
 
 // NewAddCommand returns an AddCommand with the api provided as specified.
@@ -23,6 +25,13 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 	}
 }
 
+// NewConsoleLogCommand returns a ConsoleLogCommand with the api provided as specified.
+func NewConsoleLogCommand(api ConsoleLogAPI) *ConsoleLogCommand {
+	return &ConsoleLogCommand{
+		api: api,
+	}
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -13,7 +13,8 @@ import (
 var logger = loggo.GetLogger("juju.cmd.juju.machine")
 
 const machineCommandDoc = `
-"juju machine" provides commands to add and remove machines in the Juju environment.
+"juju machine" provides commands to add and remove machines in the Juju environment,
+and to show the console output of their instances.
 `
 
 const machineCommandPurpose = "manage machines"
@@ -29,5 +30,6 @@ func NewSuperCommand() cmd.Command {
 	})
 	machineCmd.Register(envcmd.Wrap(&AddCommand{}))
 	machineCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	machineCmd.Register(envcmd.Wrap(&ConsoleLogCommand{}))
 	return machineCmd
 }
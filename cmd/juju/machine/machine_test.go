@@ -21,6 +21,7 @@ var _ = gc.Suite(&MachineCommandSuite{})
 
 var expectedCommmandNames = []string{
 	"add",
+	"console-log",
 	"help",
 	"remove",
 }
@@ -18,9 +18,11 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/cachedimages"
 	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/environs"
@@ -120,6 +122,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&DeployCommand{}))
 	r.Register(wrapEnvCommand(&AddRelationCommand{}))
 	r.Register(wrapEnvCommand(&AddUnitCommand{}))
+	r.Register(wrapEnvCommand(&AttachCommand{}))
 
 	// Destruction commands.
 	r.Register(wrapEnvCommand(&RemoveRelationCommand{}))
@@ -129,9 +132,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Reporting commands.
 	r.Register(wrapEnvCommand(&StatusCommand{}))
+	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&WaitCommand{}))
 	r.Register(&SwitchCommand{})
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
+	r.Register(wrapEnvCommand(&FindCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -140,6 +146,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&ResolvedCommand{}))
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
+	r.Register(wrapEnvCommand(&AuditCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
@@ -148,9 +155,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterDeprecated(wrapEnvCommand(&common.SetConstraintsCommand{}),
 		twoDotOhDeprecation("environment set-constraints or service set-constraints"))
 	r.Register(wrapEnvCommand(&ExposeCommand{}))
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
 	r.Register(wrapEnvCommand(&SyncToolsCommand{}))
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
+	r.Register(wrapEnvCommand(&UpgradeStatusCommand{}))
+	r.Register(wrapEnvCommand(&UpgradeSeriesCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeCharmCommand{}))
 
 	// Charm publishing commands.
@@ -193,17 +203,23 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage state server availability
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
+	r.Register(wrapEnvCommand(&ControllerHealthCommand{}))
+	r.Register(controller.NewSuperCommand())
 
 	// Manage and control services
 	r.Register(service.NewSuperCommand())
 	r.RegisterSuperAlias("get", "service", "get", twoDotOhDeprecation("service get"))
 	r.RegisterSuperAlias("set", "service", "set", twoDotOhDeprecation("service set"))
 	r.RegisterSuperAlias("unset", "service", "unset", twoDotOhDeprecation("service unset"))
+	r.RegisterSuperAlias("diff-config", "service", "diff-config", nil)
 
 	// Operation protection commands
 	r.Register(block.NewSuperBlockCommand())
 	r.Register(wrapEnvCommand(&block.UnblockCommand{}))
 
+	// Manage network spaces
+	r.Register(space.NewSuperCommand())
+
 	// Manage storage
 	if featureflag.Enabled(feature.Storage) {
 		r.Register(storage.NewSuperCommand())
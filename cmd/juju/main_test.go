@@ -195,12 +195,16 @@ var commandNames = []string{
 	"add-unit",
 	"api-endpoints",
 	"api-info",
+	"attach",
+	"audit",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
 	"block",
 	"bootstrap",
 	"cached-images",
+	"controller",
+	"controller-health",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -209,10 +213,13 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"diff-config", // alias for service diff-config
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
+	"export-bundle",
 	"expose",
+	"find",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
@@ -236,9 +243,11 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"space",
 	"ssh",
 	"stat", // alias for status
 	"status",
+	"status-history",
 	"storage",
 	"switch",
 	"sync-tools",
@@ -250,8 +259,11 @@ var commandNames = []string{
 	"unset-environment",
 	"upgrade-charm",
 	"upgrade-juju",
+	"upgrade-series",
+	"upgrade-status",
 	"user",
 	"version",
+	"wait",
 }
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
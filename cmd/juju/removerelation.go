@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -16,17 +17,30 @@ import (
 type RemoveRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+	Force     bool
 }
 
+const removeRelationDoc = `
+If the environment's relation-drain-timeout is set, a removed relation
+drains for that many seconds before it is broken. The charms of its units
+can check whether it is draining with the relation-draining hook tool.
+Use --force to break the relation immediately.
+`
+
 func (c *RemoveRelationCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "remove-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "remove a relation between two services",
+		Doc:     removeRelationDoc,
 		Aliases: []string{"destroy-relation"},
 	}
 }
 
+func (c *RemoveRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Force, "force", false, "break the relation without draining it")
+}
+
 func (c *RemoveRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
@@ -41,5 +55,9 @@ func (c *RemoveRelationCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.DestroyRelation(c.Endpoints...), block.BlockRemove)
+	destroy := client.DestroyRelation
+	if c.Force {
+		destroy = client.ForceDestroyRelation
+	}
+	return block.ProcessBlockedError(destroy(c.Endpoints...), block.BlockRemove)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing"
 )
@@ -60,6 +61,38 @@ func (s *RemoveRelationSuite) TestRemoveRelation(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `a relation must involve two services`)
 }
 
+func (s *RemoveRelationSuite) TestRemoveRelationDrains(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"relation-drain-timeout": 300}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runRemoveRelation(c, "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Life(), gc.Equals, state.Alive)
+	_, draining := rel.DrainDeadline()
+	c.Assert(draining, jc.IsTrue)
+
+	// A draining relation can still be broken immediately.
+	err = runRemoveRelation(c, "--force", "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Life(), gc.Equals, state.Dying)
+}
+
 func (s *RemoveRelationSuite) TestBlockRemoveRelation(c *gc.C) {
 	s.setupRelationForRemove(c)
 
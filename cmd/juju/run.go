@@ -14,6 +14,7 @@ import (
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -28,6 +29,9 @@ type RunCommand struct {
 	machines []string
 	services []string
 	units    []string
+	tags     []string
+	spaces   []string
+	stream   bool
 	commands string
 }
 
@@ -53,6 +57,15 @@ is equivalent to
 Commands run for services or units are executed in a 'hook context' for
 the unit.
 
+Machines can also be selected by the tags of their hardware, or by the
+network spaces on which they have addresses. The command is run on each
+machine that has all the tags given with --tag, and on each machine with
+an address on any of the spaces given with --space.
+
+By default the results are reported once the commands have completed on
+every target. With --stream, the result for each target is reported as
+soon as it completes.
+
 --all is provided as a simple way to run the command on all the machines
 in the environment.  If you specify --all you cannot provide additional
 targets.
@@ -75,6 +88,9 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
+	f.Var(cmd.NewStringsValue(nil, &c.tags), "tag", "run on the machines with all of these hardware tags")
+	f.Var(cmd.NewStringsValue(nil, &c.spaces), "space", "run on the machines with addresses on any of these network spaces")
+	f.BoolVar(&c.stream, "stream", false, "report the result for each target as it completes")
 }
 
 func (c *RunCommand) Init(args []string) error {
@@ -93,9 +109,15 @@ func (c *RunCommand) Init(args []string) error {
 		if len(c.units) != 0 {
 			return fmt.Errorf("You cannot specify --all and individual units")
 		}
+		if len(c.tags) != 0 || len(c.spaces) != 0 {
+			return fmt.Errorf("You cannot specify --all and --tag or --space")
+		}
+		if c.stream {
+			return fmt.Errorf("You cannot specify --all and --stream")
+		}
 	} else {
-		if len(c.machines) == 0 && len(c.services) == 0 && len(c.units) == 0 {
-			return fmt.Errorf("You must specify a target, either through --all, --machine, --service or --unit")
+		if len(c.machines) == 0 && len(c.services) == 0 && len(c.units) == 0 && len(c.tags) == 0 && len(c.spaces) == 0 {
+			return fmt.Errorf("You must specify a target, either through --all, --machine, --service, --unit, --tag or --space")
 		}
 	}
 
@@ -179,18 +201,23 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	runParams := params.RunParams{
+		Commands: c.commands,
+		Timeout:  c.timeout,
+		Machines: c.machines,
+		Services: c.services,
+		Units:    c.units,
+		Tags:     c.tags,
+		Spaces:   c.spaces,
+	}
+	if c.stream {
+		return c.runStreamed(ctx, client, runParams)
+	}
 	var runResults []params.RunResult
 	if c.all {
 		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
 	} else {
-		params := params.RunParams{
-			Commands: c.commands,
-			Timeout:  c.timeout,
-			Machines: c.machines,
-			Services: c.services,
-			Units:    c.units,
-		}
-		runResults, err = client.Run(params)
+		runResults, err = client.Run(runParams)
 	}
 
 	if err != nil {
@@ -217,6 +244,30 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// runStreamed writes out the results of the commands as they are
+// reported, in batches of those that completed together.
+func (c *RunCommand) runStreamed(ctx *cmd.Context, client RunClient, runParams params.RunParams) error {
+	w, err := client.RunStreamed(runParams)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	defer w.Stop()
+	for {
+		result, err := w.Next()
+		if err != nil {
+			return err
+		}
+		if len(result.Results) > 0 {
+			if err := c.out.Write(ctx, ConvertRunResults(result.Results)); err != nil {
+				return err
+			}
+		}
+		if result.Done {
+			return nil
+		}
+	}
+}
+
 // In order to be able to easily mock out the API side for testing,
 // the API client is got using a function.
 
@@ -224,6 +275,7 @@ type RunClient interface {
 	Close() error
 	RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
 	Run(run params.RunParams) ([]params.RunResult, error)
+	RunStreamed(run params.RunParams) (api.RunWatcher, error)
 }
 
 // Here we need the signature to be correct for the interface.
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/utils/exec"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
@@ -34,6 +35,8 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines []string
 		units    []string
 		services []string
+		tags     []string
+		spaces   []string
 		commands string
 		errMatch string
 	}{{
@@ -42,7 +45,7 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 	}, {
 		message:  "no target",
 		args:     []string{"sudo reboot"},
-		errMatch: "You must specify a target, either through --all, --machine, --service or --unit",
+		errMatch: "You must specify a target, either through --all, --machine, --service, --unit, --tag or --space",
 	}, {
 		message:  "too many args",
 		args:     []string{"--all", "sudo reboot", "oops"},
@@ -107,6 +110,20 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines: []string{"0"},
 		services: []string{"mysql"},
 		units:    []string{"wordpress/0", "wordpress/1"},
+	}, {
+		message:  "command to machines by tag and space",
+		args:     []string{"--tag=ssd,gpu", "--space=public", "sudo reboot"},
+		commands: "sudo reboot",
+		tags:     []string{"ssd", "gpu"},
+		spaces:   []string{"public"},
+	}, {
+		message:  "all and tags",
+		args:     []string{"--all", "--tag=ssd", "sudo reboot"},
+		errMatch: `You cannot specify --all and --tag or --space`,
+	}, {
+		message:  "all and stream",
+		args:     []string{"--all", "--stream", "sudo reboot"},
+		errMatch: `You cannot specify --all and --stream`,
 	}} {
 		c.Log(fmt.Sprintf("%v: %s", i, test.message))
 		runCmd := &RunCommand{}
@@ -116,6 +133,8 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 			c.Check(runCmd.machines, gc.DeepEquals, test.machines)
 			c.Check(runCmd.services, gc.DeepEquals, test.services)
 			c.Check(runCmd.units, gc.DeepEquals, test.units)
+			c.Check(runCmd.tags, gc.DeepEquals, test.tags)
+			c.Check(runCmd.spaces, gc.DeepEquals, test.spaces)
 			c.Check(runCmd.commands, gc.Equals, test.commands)
 		}
 	}
@@ -269,6 +288,36 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 	c.Check(testing.Stdout(context), gc.Equals, string(jsonFormatted)+"\n")
 }
 
+func (s *RunSuite) TestRunStreamed(c *gc.C) {
+	mock := s.setupMockAPI()
+	machineResponse := mockResponse{
+		stdout:    "megatron\n",
+		machineId: "0",
+	}
+	unitResponse := mockResponse{
+		stdout:    "bumblebee",
+		machineId: "1",
+		unitId:    "unit/0",
+	}
+	mock.setResponse("0", machineResponse)
+	mock.setResponse("unit/0", unitResponse)
+
+	// Each result is written out as it is reported.
+	var expected string
+	for _, response := range []mockResponse{machineResponse, unitResponse} {
+		formatted, err := cmd.FormatJson(ConvertRunResults([]params.RunResult{makeRunResult(response)}))
+		c.Assert(err, jc.ErrorIsNil)
+		expected += string(formatted) + "\n"
+	}
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}),
+		"--format=json", "--stream", "--machine=0", "--unit=unit/0", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(context), gc.Equals, expected)
+	c.Check(mock.stopped, jc.IsTrue)
+}
+
 func (s *RunSuite) TestBlockRunForMachineAndUnit(c *gc.C) {
 	mock := s.setupMockAPI()
 	// Block operation
@@ -391,6 +440,7 @@ type mockRunAPI struct {
 	machines  map[string]bool
 	responses map[string]params.RunResult
 	block     bool
+	stopped   bool
 }
 
 type mockResponse struct {
@@ -484,3 +534,31 @@ func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error)
 
 	return result, nil
 }
+
+func (m *mockRunAPI) RunStreamed(runParams params.RunParams) (api.RunWatcher, error) {
+	results, err := m.Run(runParams)
+	if err != nil {
+		return nil, err
+	}
+	return &mockRunWatcher{api: m, results: results}, nil
+}
+
+// mockRunWatcher reports the results of a mockRunAPI one at a time.
+type mockRunWatcher struct {
+	api     *mockRunAPI
+	results []params.RunResult
+}
+
+func (w *mockRunWatcher) Next() (params.RunWatcherNextResult, error) {
+	if len(w.results) == 0 {
+		return params.RunWatcherNextResult{Done: true}, nil
+	}
+	result := params.RunWatcherNextResult{Results: w.results[:1]}
+	w.results = w.results[1:]
+	return result, nil
+}
+
+func (w *mockRunWatcher) Stop() error {
+	w.api.stopped = true
+	return nil
+}
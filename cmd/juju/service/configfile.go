@@ -0,0 +1,199 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v1"
+)
+
+// includeKey is the top-level key of a service config file that names
+// the config files it includes.
+const includeKey = "include"
+
+// envVarRef matches a reference to an environment variable in a service
+// config value.
+var envVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// serviceSettings holds the settings of services, keyed by service name
+// and then by option name.
+type serviceSettings map[string]map[string]interface{}
+
+// ReadConfigFile reads the service config file named by the given
+// flag, along with the files it includes, and returns the resulting
+// settings of all services as YAML, in the format expected by the API
+// server. If expandEnv is true, references to environment variables
+// in the setting values are expanded.
+func ReadConfigFile(ctx *cmd.Context, file cmd.FileVar, expandEnv bool) ([]byte, error) {
+	data, err := file.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !expandEnv {
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			// Leave it to the API server to report.
+			return data, nil
+		}
+		if includes, err := includedFiles(raw); err == nil && includes == nil {
+			// There is nothing to include or expand.
+			return data, nil
+		}
+	}
+	r := &configReader{
+		expandEnv: expandEnv,
+		reading:   make(map[string]bool),
+	}
+	settings, err := r.parse(ctx.AbsPath(file.Path), data)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(settings)
+}
+
+// configReader reads service config files.
+type configReader struct {
+	expandEnv bool
+
+	// reading holds the paths of the files being read, so that
+	// include cycles are detected.
+	reading map[string]bool
+}
+
+func (r *configReader) read(path string) (serviceSettings, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read included config file")
+	}
+	return r.parse(path, data)
+}
+
+func (r *configReader) parse(path string, data []byte) (serviceSettings, error) {
+	if r.reading[path] {
+		return nil, errors.Errorf("config file %q includes itself", path)
+	}
+	r.reading[path] = true
+	defer delete(r.reading, path)
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse config file %q", path)
+	}
+	includes, err := includedFiles(raw)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse config file %q", path)
+	}
+	settings := make(serviceSettings)
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		included, err := r.read(include)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings.merge(included)
+	}
+	own := make(serviceSettings)
+	for serviceName, value := range raw {
+		if serviceName == includeKey && includes != nil {
+			continue
+		}
+		options, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.Errorf("config file %q: expected settings of service %q, got %#v", path, serviceName, value)
+		}
+		own[serviceName] = make(map[string]interface{})
+		for name, value := range options {
+			if r.expandEnv {
+				if value, err = expandEnvVars(value); err != nil {
+					return nil, errors.Annotatef(err, "config file %q: option %q of service %q", path, name, serviceName)
+				}
+			}
+			own[serviceName][fmt.Sprint(name)] = value
+		}
+	}
+	settings.merge(own)
+	return settings, nil
+}
+
+// includedFiles returns the names of the files named by the include
+// key of a service config file. It returns nil if the key is not
+// present or holds the settings of a service named "include".
+func includedFiles(raw map[string]interface{}) ([]string, error) {
+	switch value := raw[includeKey].(type) {
+	case nil, map[interface{}]interface{}:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		includes := make([]string, len(value))
+		for i, include := range value {
+			name, ok := include.(string)
+			if !ok {
+				return nil, errors.Errorf("expected file name to include, got %#v", include)
+			}
+			includes[i] = name
+		}
+		return includes, nil
+	default:
+		return nil, errors.Errorf("expected file names to include, got %#v", value)
+	}
+}
+
+// merge adds the given settings to s, overriding the settings already
+// in s.
+func (s serviceSettings) merge(settings serviceSettings) {
+	for serviceName, options := range settings {
+		if s[serviceName] == nil {
+			s[serviceName] = make(map[string]interface{})
+		}
+		for name, value := range options {
+			s[serviceName][name] = value
+		}
+	}
+}
+
+// expandEnvVars replaces the references to environment variables in
+// the given value, if it is a string. It is an error to refer to an
+// environment variable that is not set.
+func expandEnvVars(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	var err error
+	expanded := envVarRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVarRef.FindStringSubmatch(ref)[1]
+		value, ok := lookupEnv(name)
+		if !ok && err == nil {
+			err = errors.Errorf("environment variable %q not set", name)
+		}
+		return value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// lookupEnv returns the value of the named environment variable, and
+// whether it is set.
+func lookupEnv(name string) (string, bool) {
+	prefix := name + "="
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, prefix) {
+			return kv[len(prefix):], true
+		}
+	}
+	return "", false
+}
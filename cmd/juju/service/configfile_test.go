@@ -0,0 +1,160 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/cmd/juju/service"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigFileSuite struct {
+	coretesting.FakeJujuHomeSuite
+	dir string
+}
+
+var _ = gc.Suite(&ConfigFileSuite{})
+
+func (s *ConfigFileSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *ConfigFileSuite) writeFile(c *gc.C, name, content string) {
+	path := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigFileSuite) readConfigFile(c *gc.C, name string, expandEnv bool) (map[string]interface{}, error) {
+	ctx := coretesting.ContextForDir(c, s.dir)
+	data, err := service.ReadConfigFile(ctx, cmd.FileVar{Path: name}, expandEnv)
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]interface{}
+	err = yaml.Unmarshal(data, &settings)
+	c.Assert(err, jc.ErrorIsNil)
+	return settings, nil
+}
+
+func (s *ConfigFileSuite) TestPlainFileUnchanged(c *gc.C) {
+	content := "# settings\nwordpress:\n  title: blog\n\n"
+	s.writeFile(c, "config.yaml", content)
+	ctx := coretesting.ContextForDir(c, s.dir)
+	data, err := service.ReadConfigFile(ctx, cmd.FileVar{Path: "config.yaml"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+}
+
+func (s *ConfigFileSuite) TestAnchors(c *gc.C) {
+	s.writeFile(c, "config.yaml", `
+wordpress: &wordpress
+  title: blog
+  debug: false
+mysql:
+  <<: *wordpress
+  title: db
+`)
+	settings, err := s.readConfigFile(c, "config.yaml", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["mysql"], jc.DeepEquals, map[interface{}]interface{}{
+		"title": "db",
+		"debug": false,
+	})
+}
+
+func (s *ConfigFileSuite) TestIncludes(c *gc.C) {
+	s.writeFile(c, "common.yaml", `
+wordpress:
+  title: common
+  tuning: optimized
+mysql:
+  dataset-size: 80%
+`)
+	s.writeFile(c, "more.yaml", "include: common.yaml\nmysql:\n  dataset-size: 50%\n")
+	s.writeFile(c, "config.yaml", `
+include: [more.yaml]
+wordpress:
+  title: blog
+`)
+	settings, err := s.readConfigFile(c, "config.yaml", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{
+		"wordpress": map[interface{}]interface{}{
+			"title":  "blog",
+			"tuning": "optimized",
+		},
+		"mysql": map[interface{}]interface{}{
+			"dataset-size": "50%",
+		},
+	})
+}
+
+func (s *ConfigFileSuite) TestServiceNamedInclude(c *gc.C) {
+	s.writeFile(c, "config.yaml", "include:\n  title: blog\n")
+	settings, err := s.readConfigFile(c, "config.yaml", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{
+		"include": map[interface{}]interface{}{"title": "blog"},
+	})
+}
+
+func (s *ConfigFileSuite) TestIncludeCycle(c *gc.C) {
+	s.writeFile(c, "a.yaml", "include: b.yaml\n")
+	s.writeFile(c, "b.yaml", "include: a.yaml\n")
+	_, err := s.readConfigFile(c, "a.yaml", false)
+	c.Assert(err, gc.ErrorMatches, `config file ".*a.yaml" includes itself`)
+}
+
+func (s *ConfigFileSuite) TestIncludeMissing(c *gc.C) {
+	s.writeFile(c, "config.yaml", "include: missing.yaml\n")
+	_, err := s.readConfigFile(c, "config.yaml", false)
+	c.Assert(err, gc.ErrorMatches, `cannot read included config file: .*`)
+}
+
+func (s *ConfigFileSuite) TestInvalidInclude(c *gc.C) {
+	s.writeFile(c, "config.yaml", "include: [1]\n")
+	_, err := s.readConfigFile(c, "config.yaml", false)
+	c.Assert(err, gc.ErrorMatches, `cannot parse config file ".*config.yaml": expected file name to include, got 1`)
+}
+
+func (s *ConfigFileSuite) TestExpandEnv(c *gc.C) {
+	s.PatchEnvironment("BLOG_TITLE", "my blog")
+	s.writeFile(c, "config.yaml", `
+wordpress:
+  title: ${BLOG_TITLE}
+  tagline: "${BLOG_TITLE}, and $NOT_EXPANDED"
+  port: 80
+`)
+	settings, err := s.readConfigFile(c, "config.yaml", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["wordpress"], jc.DeepEquals, map[interface{}]interface{}{
+		"title":   "my blog",
+		"tagline": "my blog, and $NOT_EXPANDED",
+		"port":    80,
+	})
+
+	// References are left alone without expandEnv.
+	settings, err = s.readConfigFile(c, "config.yaml", false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["wordpress"], jc.DeepEquals, map[interface{}]interface{}{
+		"title":   "${BLOG_TITLE}",
+		"tagline": "${BLOG_TITLE}, and $NOT_EXPANDED",
+		"port":    80,
+	})
+}
+
+func (s *ConfigFileSuite) TestExpandEnvNotSet(c *gc.C) {
+	s.writeFile(c, "config.yaml", "wordpress:\n  title: ${NO_SUCH_VARIABLE_SET}\n")
+	_, err := s.readConfigFile(c, "config.yaml", true)
+	c.Assert(err, gc.ErrorMatches, `config file ".*config.yaml": option "title" of service "wordpress": environment variable "NO_SUCH_VARIABLE_SET" not set`)
+}
@@ -0,0 +1,200 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// DiffConfigCommand shows how the configuration of a service differs
+// from its charm's defaults, and from what its units have observed.
+type DiffConfigCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	all         bool
+	out         cmd.Output
+	api         DiffConfigAPI
+}
+
+const diffConfigDoc = `
+Show the config settings of a service that are not at their charm
+defaults, along with the units whose charms have not yet observed the
+current value in a config-changed hook. Units that have yet to run a
+config-changed hook at all are shown as "-".
+
+Example:
+
+$ juju service diff-config wordpress
+
+OPTION      DEFAULT   CURRENT    UNIT         OBSERVED
+blog-title  My Title  New Title  wordpress/0  My Title
+                                 wordpress/1  -
+`
+
+func (c *DiffConfigCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-config",
+		Args:    "<service>",
+		Purpose: "show pending and non-default service config settings",
+		Doc:     diffConfigDoc,
+	}
+}
+
+func (c *DiffConfigCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.all, "all", false, "show all options, including those at their defaults and observed by all units")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatConfigDiffTabular,
+	})
+}
+
+func (c *DiffConfigCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// DiffConfigAPI defines the methods on the service API that the
+// diff-config command calls.
+type DiffConfigAPI interface {
+	Close() error
+	ConfigDiff(service string) (params.ServiceConfigDiffResult, error)
+}
+
+func (c *DiffConfigCommand) getAPI() (DiffConfigAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return apiservice.NewClient(root), nil
+}
+
+// configOptionDiff holds the defaults, current value and the values
+// observed by units that are out of date, of a service config option.
+type configOptionDiff struct {
+	Default interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+	Current interface{} `yaml:"current,omitempty" json:"current,omitempty"`
+	// Pending maps the names of the units that have not observed the
+	// current value to the value they last observed, which is nil if
+	// they have not run a config-changed hook at all.
+	Pending map[string]interface{} `yaml:"pending,omitempty" json:"pending,omitempty"`
+}
+
+// Run shows the config settings of the service that differ from the
+// charm defaults or from those observed by its units.
+func (c *DiffConfigCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := client.ConfigDiff(c.ServiceName)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, configDiff(result, c.all))
+}
+
+// configDiff returns the options in the given result that differ from
+// their defaults or from what the units have observed, or all options
+// if all is true.
+func configDiff(result params.ServiceConfigDiffResult, all bool) map[string]configOptionDiff {
+	diff := make(map[string]configOptionDiff)
+	for name, current := range result.Settings {
+		option := configOptionDiff{
+			Default: result.Defaults[name],
+			Current: current,
+		}
+		for _, unit := range result.Units {
+			observed, ok := unit.Settings[name]
+			if !unit.Observed || !ok || !reflect.DeepEqual(observed, current) {
+				if option.Pending == nil {
+					option.Pending = make(map[string]interface{})
+				}
+				option.Pending[unit.Unit] = observed
+			}
+		}
+		if all || option.Pending != nil || !reflect.DeepEqual(option.Default, current) {
+			diff[name] = option
+		}
+	}
+	return diff
+}
+
+// formatConfigDiffTabular returns a tabular summary of config option
+// differences, with a row for each out of date unit.
+func formatConfigDiffTabular(value interface{}) ([]byte, error) {
+	diff, ok := value.(map[string]configOptionDiff)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", diff, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "OPTION\tDEFAULT\tCURRENT\tUNIT\tOBSERVED\n")
+	for _, name := range sortedConfigOptions(diff) {
+		option := diff[name]
+		units := make([]string, 0, len(option.Pending))
+		for unit := range option.Pending {
+			units = append(units, unit)
+		}
+		sort.Strings(units)
+		prefix := fmt.Sprintf("%s\t%s\t%s", name, formatConfigValue(option.Default), formatConfigValue(option.Current))
+		if len(units) == 0 {
+			fmt.Fprintf(tw, "%s\t\t\n", prefix)
+		}
+		for _, unit := range units {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", prefix, unit, formatConfigValue(option.Pending[unit]))
+			prefix = "\t\t"
+		}
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+func sortedConfigOptions(diff map[string]configOptionDiff) []string {
+	options := make([]string, 0, len(diff))
+	for name := range diff {
+		options = append(options, name)
+	}
+	sort.Strings(options)
+	return options
+}
+
+func formatConfigValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DiffConfigSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeDiffConfigAPI
+}
+
+var _ = gc.Suite(&DiffConfigSuite{})
+
+func (s *DiffConfigSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeDiffConfigAPI{
+		result: params.ServiceConfigDiffResult{
+			Defaults: params.ConfigSettings{
+				"title":    "My Title",
+				"username": "admin001",
+				"outlook":  nil,
+			},
+			Settings: params.ConfigSettings{
+				"title":    "New Title",
+				"username": "admin001",
+				"outlook":  "sunny",
+			},
+			Units: []params.UnitObservedConfig{{
+				Unit:     "dummy-service/0",
+				Observed: true,
+				Settings: params.ConfigSettings{
+					"title":    "My Title",
+					"username": "admin001",
+					"outlook":  "sunny",
+				},
+			}, {
+				Unit: "dummy-service/1",
+			}},
+		},
+	}
+}
+
+func (s *DiffConfigSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(service.NewDiffConfigCommand(s.fake)), args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *DiffConfigSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+	_, err = s.run(c, "dummy-service/0")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "dummy-service/0"`)
+	_, err = s.run(c, "dummy-service", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *DiffConfigSuite) TestRunTabular(c *gc.C) {
+	out, err := s.run(c, "dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "dummy-service")
+	c.Assert(out, gc.Equals, ""+
+		"OPTION    DEFAULT   CURRENT    UNIT             OBSERVED\n"+
+		"outlook   -         sunny      dummy-service/1  -\n"+
+		"title     My Title  New Title  dummy-service/0  My Title\n"+
+		"                               dummy-service/1  -\n"+
+		"username  admin001  admin001   dummy-service/1  -\n")
+}
+
+func (s *DiffConfigSuite) TestRunAllObserved(c *gc.C) {
+	s.fake.result.Units = s.fake.result.Units[:1]
+	out, err := s.run(c, "dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"OPTION   DEFAULT   CURRENT    UNIT             OBSERVED\n"+
+		"outlook  -         sunny                       \n"+
+		"title    My Title  New Title  dummy-service/0  My Title\n")
+}
+
+func (s *DiffConfigSuite) TestRunAllOptionsYAML(c *gc.C) {
+	s.fake.result.Units = s.fake.result.Units[:1]
+	out, err := s.run(c, "--all", "--format", "yaml", "dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"outlook:\n"+
+		"  current: sunny\n"+
+		"title:\n"+
+		"  default: My Title\n"+
+		"  current: New Title\n"+
+		"  pending:\n"+
+		"    dummy-service/0: My Title\n"+
+		"username:\n"+
+		"  default: admin001\n"+
+		"  current: admin001\n")
+}
+
+func (s *DiffConfigSuite) TestRunError(c *gc.C) {
+	s.fake.err = errors.NotFoundf(`service "dummy-service"`)
+	_, err := s.run(c, "dummy-service")
+	c.Assert(err, gc.ErrorMatches, `service "dummy-service" not found`)
+}
+
+type fakeDiffConfigAPI struct {
+	service string
+	result  params.ServiceConfigDiffResult
+	err     error
+}
+
+func (f *fakeDiffConfigAPI) Close() error {
+	return nil
+}
+
+func (f *fakeDiffConfigAPI) ConfigDiff(service string) (params.ServiceConfigDiffResult, error) {
+	f.service = service
+	return f.result, f.err
+}
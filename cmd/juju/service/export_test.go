@@ -23,3 +23,10 @@ func NewGetCommand(api GetServiceAPI) *GetCommand {
 		api: api,
 	}
 }
+
+// NewDiffConfigCommand returns a DiffConfigCommand with the api provided as specified.
+func NewDiffConfigCommand(api DiffConfigAPI) *DiffConfigCommand {
+	return &DiffConfigCommand{
+		api: api,
+	}
+}
@@ -19,7 +19,12 @@ type fakeServiceAPI struct {
 	servName  string
 	charmName string
 	config    string
+	revision  int64
 	err       error
+
+	// invalid holds the invalid options reported by
+	// ValidateServiceSetConfig and ValidateServiceSetConfigYAML.
+	invalid map[string]string
 }
 
 func (f *fakeServiceAPI) Close() error {
@@ -54,9 +59,10 @@ func (f *fakeServiceAPI) ServiceGet(service string) (*params.ServiceGetResults,
 	}
 
 	return &params.ServiceGetResults{
-		Service: f.servName,
-		Charm:   f.charmName,
-		Config:  configInfo,
+		Service:        f.servName,
+		Charm:          f.charmName,
+		Config:         configInfo,
+		ConfigRevision: f.revision,
 	}, nil
 }
 
@@ -72,10 +78,35 @@ func (f *fakeServiceAPI) ServiceSet(service string, options map[string]string) e
 	for k, v := range options {
 		f.values[k] = v
 	}
+	f.revision++
 
 	return nil
 }
 
+func (f *fakeServiceAPI) ServiceSetAtRevision(service string, options map[string]string, revision int64) error {
+	if f.err == nil && service == f.servName && revision != f.revision {
+		return &params.Error{
+			Message: "settings changed since they were read",
+			Code:    params.CodeConflict,
+		}
+	}
+	return f.ServiceSet(service, options)
+}
+
+func (f *fakeServiceAPI) ValidateServiceSetConfig(service string, options map[string]string) (map[string]string, error) {
+	if service != f.servName {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+	return f.invalid, nil
+}
+
+func (f *fakeServiceAPI) ValidateServiceSetConfigYAML(service string, yaml string) (map[string]string, error) {
+	if service != f.servName {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+	return f.invalid, nil
+}
+
 func (f *fakeServiceAPI) ServiceUnset(service string, options []string) error {
 	if f.err != nil {
 		return f.err
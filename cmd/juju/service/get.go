@@ -29,6 +29,7 @@ or not ("default: true"), description (if set), type, and current value. Example
 $ juju service get wordpress
 
 charm: wordpress
+revision: 4
 service: wordpress
 settings:
   engine:
@@ -44,6 +45,10 @@ settings:
 NOTE: In the example above the descriptions and most other settings were omitted for
 brevity. The "engine" setting was left at its default value ("nginx"), while the
 "tuning" setting was set to "optimized" (the default value is "single").
+
+The revision changes every time the service's configuration is changed. It
+can be passed to the set command to make sure that the configuration has not
+been changed by someone else in the meantime.
 `
 
 func (c *GetCommand) Info() *cmd.Info {
@@ -102,6 +107,7 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 	resultsMap := map[string]interface{}{
 		"service":  results.Service,
 		"charm":    results.Charm,
+		"revision": results.ConfigRevision,
 		"settings": results.Config,
 	}
 	return c.out.Write(ctx, resultsMap)
@@ -30,8 +30,9 @@ var getTests = []struct {
 	{
 		"dummy-service",
 		map[string]interface{}{
-			"service": "dummy-service",
-			"charm":   "dummy",
+			"service":  "dummy-service",
+			"charm":    "dummy",
+			"revision": 3,
 			"settings": map[string]interface{}{
 				"title": map[string]interface{}{
 					"description": "Specifies title",
@@ -63,7 +64,7 @@ var getTests = []struct {
 
 func (s *GetSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuHomeSuite.SetUpTest(c)
-	s.fake = &fakeServiceAPI{servName: "dummy-service", charmName: "dummy",
+	s.fake = &fakeServiceAPI{servName: "dummy-service", charmName: "dummy", revision: 3,
 		values: map[string]interface{}{
 			"title":       "Nearly There",
 			"skill-level": 100,
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
@@ -26,6 +27,9 @@ type SetCommand struct {
 	ServiceName     string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	Revision        int64
+	DryRun          bool
+	ExpandEnv       bool
 	api             SetServiceAPI
 }
 
@@ -40,6 +44,20 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+The --revision option takes the configuration revision reported by the get
+command. If it is given, the options are only set if the service's
+configuration has not been changed since that revision, so that changes made
+by someone else in the meantime are not silently overwritten.
+
+The file given with --config may include other config files, named by
+its top-level "include" key relative to its own directory, whose
+settings it overrides; YAML anchors may be used to share settings. With
+--expand-env, ${NAME} in a setting value is replaced by the value of the
+environment variable NAME.
+
+The --dry-run option checks the options against those declared by the
+service's charm, reporting every invalid option, without setting them.
 `
 
 const maxValueSize = 5242880
@@ -55,6 +73,9 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.Int64Var(&c.Revision, "revision", 0, "only set options if the service config is still at this revision")
+	f.BoolVar(&c.DryRun, "dry-run", false, "check the options against the charm without setting them")
+	f.BoolVar(&c.ExpandEnv, "expand-env", false, "expand ${NAME} environment variable references in the --config file")
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -64,6 +85,12 @@ func (c *SetCommand) Init(args []string) error {
 	if c.SettingsYAML.Path != "" && len(args) > 1 {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
+	if c.SettingsYAML.Path != "" && c.Revision != 0 {
+		return errors.New("cannot specify --revision with --config")
+	}
+	if c.Revision < 0 {
+		return errors.New("--revision must be positive")
+	}
 	c.ServiceName = args[0]
 	settings, err := keyvalues.Parse(args[1:], true)
 	if err != nil {
@@ -80,6 +107,9 @@ type SetServiceAPI interface {
 	ServiceSetYAML(service string, yaml string) error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
 	ServiceSet(service string, options map[string]string) error
+	ServiceSetAtRevision(service string, options map[string]string, revision int64) error
+	ValidateServiceSetConfig(service string, options map[string]string) (map[string]string, error)
+	ValidateServiceSetConfigYAML(service string, yaml string) (map[string]string, error)
 }
 
 func (c *SetCommand) getAPI() (SetServiceAPI, error) {
@@ -98,10 +128,17 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	defer api.Close()
 
 	if c.SettingsYAML.Path != "" {
-		b, err := c.SettingsYAML.Read(ctx)
+		b, err := ReadConfigFile(ctx, c.SettingsYAML, c.ExpandEnv)
 		if err != nil {
 			return err
 		}
+		if c.DryRun {
+			invalid, err := api.ValidateServiceSetConfigYAML(c.ServiceName, string(b))
+			if err != nil {
+				return err
+			}
+			return c.reportInvalidOptions(ctx, invalid)
+		}
 		return block.ProcessBlockedError(api.ServiceSetYAML(c.ServiceName, string(b)), block.BlockChange)
 	} else if len(c.SettingsStrings) == 0 {
 		return nil
@@ -148,7 +185,39 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	return block.ProcessBlockedError(api.ServiceSet(c.ServiceName, settings), block.BlockChange)
+	if c.DryRun {
+		invalid, err := api.ValidateServiceSetConfig(c.ServiceName, settings)
+		if err != nil {
+			return err
+		}
+		return c.reportInvalidOptions(ctx, invalid)
+	}
+	if c.Revision == 0 {
+		return block.ProcessBlockedError(api.ServiceSet(c.ServiceName, settings), block.BlockChange)
+	}
+	err = api.ServiceSetAtRevision(c.ServiceName, settings, c.Revision)
+	if params.IsCodeConflict(err) {
+		return fmt.Errorf("configuration of service %q has changed since revision %d; see juju get", c.ServiceName, c.Revision)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// reportInvalidOptions reports the given invalid options, keyed by
+// option name, found by validating the service configuration.
+func (c *SetCommand) reportInvalidOptions(ctx *cmd.Context, invalid map[string]string) error {
+	if len(invalid) == 0 {
+		ctx.Infof("configuration options for service %q are valid", c.ServiceName)
+		return nil
+	}
+	names := make([]string, 0, len(invalid))
+	for name := range invalid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ctx.Infof("invalid option %q: %s", name, invalid[name])
+	}
+	return cmd.ErrSilent
 }
 
 // readValue reads the value of an option out of the named file.
@@ -64,6 +64,14 @@ func (*SetSuite) TestSetCommandInit(c *gc.C) {
 	// --config and options specified
 	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--config", "testconfig.yaml", "bees="})
 	c.Assert(err, gc.ErrorMatches, "cannot specify --config when using key=value arguments")
+
+	// --config and --revision specified
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--config", "testconfig.yaml", "--revision", "2"})
+	c.Assert(err, gc.ErrorMatches, "cannot specify --revision with --config")
+
+	// negative --revision
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--revision", "-1", "bees="})
+	c.Assert(err, gc.ErrorMatches, "--revision must be positive")
 }
 
 func (s *SetSuite) TestSetOptionSuccess(c *gc.C) {
@@ -111,6 +119,23 @@ func (s *SetSuite) TestSetSameValue(c *gc.C) {
 
 }
 
+func (s *SetSuite) TestSetAtRevision(c *gc.C) {
+	s.fake.revision = 5
+	s.assertSetSuccess(c, s.dir, []string{
+		"--revision", "5",
+		"username=hello",
+	}, map[string]interface{}{
+		"username": "hello",
+	})
+	s.assertSetFail(c, s.dir, []string{
+		"--revision", "5",
+		"username=bye",
+	}, `error: configuration of service "dummy-service" has changed since revision 5; see juju get\n`)
+	c.Assert(s.fake.values, gc.DeepEquals, map[string]interface{}{
+		"username": "hello",
+	})
+}
+
 func (s *SetSuite) TestSetOptionFail(c *gc.C) {
 	s.assertSetFail(c, s.dir, []string{"foo", "bar"}, "error: expected \"key=value\", got \"foo\"\n")
 	s.assertSetFail(c, s.dir, []string{"=bar"}, "error: expected \"key=value\", got \"=bar\"\n")
@@ -141,6 +166,47 @@ func (s *SetSuite) TestSetConfig(c *gc.C) {
 	c.Check(s.fake.config, gc.Equals, yamlConfigValue)
 }
 
+func (s *SetSuite) TestSetDryRun(c *gc.C) {
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service", "--dry-run", "username=hello"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(coretesting.Stderr(ctx), gc.Equals, "configuration options for service \"dummy-service\" are valid\n")
+
+	// Nothing was set.
+	c.Assert(s.fake.values, gc.HasLen, 0)
+}
+
+func (s *SetSuite) TestSetDryRunInvalid(c *gc.C) {
+	s.fake.invalid = map[string]string{
+		"skill-level": `option "skill-level" expected int, got "lots"`,
+		"outlook":     `unknown option "outlook"`,
+	}
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service", "--dry-run", "skill-level=lots", "outlook=sunny"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(coretesting.Stderr(ctx), gc.Equals, ""+
+		"invalid option \"outlook\": unknown option \"outlook\"\n"+
+		"invalid option \"skill-level\": option \"skill-level\" expected int, got \"lots\"\n")
+	c.Assert(s.fake.values, gc.HasLen, 0)
+}
+
+func (s *SetSuite) TestSetConfigDryRun(c *gc.C) {
+	s.fake.invalid = map[string]string{
+		"skill-level": `option "skill-level" expected int, got "lots"`,
+	}
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service", "--dry-run", "--config", "testconfig.yaml"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(coretesting.Stderr(ctx), gc.Equals,
+		"invalid option \"skill-level\": option \"skill-level\" expected int, got \"lots\"\n")
+
+	// Nothing was set.
+	c.Check(s.fake.config, gc.Equals, "")
+}
+
 func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fake.err = common.ErrOperationBlocked("TestBlockSetConfig")
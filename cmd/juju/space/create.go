@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+const createCommandDoc = `
+Create a network space, holding the subnets with the given CIDRs.
+
+A subnet can be in at most one space. Spaces can be created without
+subnets, and the subnets added to them later.
+
+Space names must consist of lower case letters, digits and hyphens,
+and must neither start nor end with a hyphen.
+
+Examples:
+
+   juju space create dmz 10.0.1.0/24 10.0.2.0/24
+   juju space create internal
+`
+
+// CreateCommand creates a network space.
+type CreateCommand struct {
+	envcmd.EnvCommandBase
+	Name  string
+	CIDRs []string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> [<CIDR> ...]",
+		Purpose: "create a network space",
+		Doc:     createCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no space name specified")
+	}
+	c.Name, c.CIDRs = args[0], args[1:]
+	if !network.IsValidSpaceName(c.Name) {
+		return errors.Errorf("invalid space name %q", c.Name)
+	}
+	seen := make(map[string]bool)
+	for _, cidr := range c.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("invalid subnet CIDR %q", cidr)
+		}
+		if seen[cidr] {
+			return errors.Errorf("duplicate subnet CIDR %q", cidr)
+		}
+		seen[cidr] = true
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	api, err := getSpaceAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.CreateSpace(c.Name, c.CIDRs); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("created space %q", c.Name)
+	return nil
+}
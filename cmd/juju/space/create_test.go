@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type createSuite struct {
+	baseSpaceSuite
+}
+
+var _ = gc.Suite(&createSuite{})
+
+func (s *createSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args  []string
+		err   string
+		name  string
+		cidrs []string
+	}{{
+		err: "no space name specified",
+	}, {
+		args: []string{"Bad_Name"},
+		err:  `invalid space name "Bad_Name"`,
+	}, {
+		args: []string{"dmz", "10.0.0.0"},
+		err:  `invalid subnet CIDR "10.0.0.0"`,
+	}, {
+		args: []string{"dmz", "10.0.0.0/24", "10.0.0.0/24"},
+		err:  `duplicate subnet CIDR "10.0.0.0/24"`,
+	}, {
+		args: []string{"dmz"},
+		name: "dmz",
+	}, {
+		args:  []string{"dmz", "10.0.0.0/24", "10.0.1.0/24"},
+		name:  "dmz",
+		cidrs: []string{"10.0.0.0/24", "10.0.1.0/24"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &space.CreateCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.Name, gc.Equals, test.name)
+		c.Check(command.CIDRs, jc.DeepEquals, test.cidrs)
+	}
+}
+
+func (s *createSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&space.CreateCommand{}), "dmz", "10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.name, gc.Equals, "dmz")
+	c.Assert(s.api.subnets, jc.DeepEquals, []string{"10.0.0.0/24"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "created space \"dmz\"\n")
+}
+
+func (s *createSuite) TestRunError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&space.CreateCommand{}), "dmz")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
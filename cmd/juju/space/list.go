@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCommandDoc = `
+List the network spaces of the environment, and the subnets in each of
+them.
+`
+
+// ListCommand lists network spaces.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list network spaces",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.EnvCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	api, err := getSpaceAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	spaces, err := api.ListSpaces()
+	if err != nil {
+		return err
+	}
+	if len(spaces) == 0 {
+		ctx.Infof("no spaces to display")
+		return nil
+	}
+	return c.out.Write(ctx, formatSpaces(spaces))
+}
+
+// SubnetInfo defines the serialization behaviour of the subnets of a
+// space.
+type SubnetInfo struct {
+	ProviderId       string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+	VLANTag          int    `yaml:"vlan-tag,omitempty" json:"vlan-tag,omitempty"`
+	AvailabilityZone string `yaml:"zone,omitempty" json:"zone,omitempty"`
+}
+
+// formatSpaces maps each space name to its subnets, keyed on CIDR.
+func formatSpaces(spaces []params.Space) map[string]map[string]SubnetInfo {
+	output := make(map[string]map[string]SubnetInfo, len(spaces))
+	for _, space := range spaces {
+		subnets := make(map[string]SubnetInfo, len(space.Subnets))
+		for _, subnet := range space.Subnets {
+			subnets[subnet.CIDR] = SubnetInfo{
+				ProviderId:       subnet.ProviderId,
+				VLANTag:          subnet.VLANTag,
+				AvailabilityZone: subnet.AvailabilityZone,
+			}
+		}
+		output[space.Name] = subnets
+	}
+	return output
+}
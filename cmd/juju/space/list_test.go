@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type listSuite struct {
+	baseSpaceSuite
+}
+
+var _ = gc.Suite(&listSuite{})
+
+func (s *listSuite) SetUpTest(c *gc.C) {
+	s.baseSpaceSuite.SetUpTest(c)
+	s.api.spaces = []params.Space{{
+		Name:    "dmz",
+		Subnets: []params.SubnetInfo{{CIDR: "10.0.0.0/24", AvailabilityZone: "zone-a"}},
+	}, {
+		Name: "empty",
+	}}
+}
+
+func (s *listSuite) TestList(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+dmz:
+  10.0.0.0/24:
+    zone: zone-a
+empty: {}
+`[1:])
+}
+
+func (s *listSuite) TestListJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`{"dmz":{"10.0.0.0/24":{"zone":"zone-a"}},"empty":{}}`+"\n")
+}
+
+func (s *listSuite) TestListEmpty(c *gc.C) {
+	s.api.spaces = nil
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no spaces to display\n")
+}
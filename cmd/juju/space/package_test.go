@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+func TestAll(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type baseSpaceSuite struct {
+	testing.FakeJujuHomeSuite
+	api *mockSpaceAPI
+}
+
+func (s *baseSpaceSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &mockSpaceAPI{}
+	s.PatchValue(space.GetSpaceAPI, func(*envcmd.EnvCommandBase) (space.SpaceAPI, error) {
+		return s.api, nil
+	})
+}
+
+type mockSpaceAPI struct {
+	name    string
+	subnets []string
+	spaces  []params.Space
+	err     error
+}
+
+func (m *mockSpaceAPI) Close() error {
+	return nil
+}
+
+func (m *mockSpaceAPI) CreateSpace(name string, subnets []string) error {
+	m.name, m.subnets = name, subnets
+	return errors.Trace(m.err)
+}
+
+func (m *mockSpaceAPI) ListSpaces() ([]params.Space, error) {
+	return m.spaces, errors.Trace(m.err)
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const spaceCmdDoc = `
+"juju space" is used to manage network spaces in the Juju environment.
+
+A space is a named set of subnets with the same ingress and egress
+rules. Services can be bound to a space when they are deployed, so that
+their units are placed on machines connected to the subnets of that
+space.
+`
+
+const spaceCmdPurpose = "manage network spaces"
+
+// Command is the top-level command wrapping all space functionality.
+type Command struct {
+	cmd.SuperCommand
+}
+
+// NewSuperCommand creates the space supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	spacecmd := Command{
+		SuperCommand: *cmd.NewSuperCommand(
+			cmd.SuperCommandParams{
+				Name:        "space",
+				Doc:         spaceCmdDoc,
+				UsagePrefix: "juju",
+				Purpose:     spaceCmdPurpose,
+			})}
+	spacecmd.Register(envcmd.Wrap(&CreateCommand{}))
+	spacecmd.Register(envcmd.Wrap(&ListCommand{}))
+	return &spacecmd
+}
+
+// SpaceAPI defines the API methods that the space commands use.
+type SpaceAPI interface {
+	Close() error
+	CreateSpace(name string, subnets []string) error
+	ListSpaces() ([]params.Space, error)
+}
+
+var getSpaceAPI = func(c *envcmd.EnvCommandBase) (SpaceAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return spaces.NewClient(root), nil
+}
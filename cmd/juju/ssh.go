@@ -30,6 +30,7 @@ type SSHCommand struct {
 type SSHCommon struct {
 	envcmd.EnvCommandBase
 	proxy     bool
+	proxyHost string
 	pty       bool
 	Target    string
 	Args      []string
@@ -42,17 +43,23 @@ func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.pty, "pty", true, "enable pseudo-tty allocation")
 }
 
-// setProxyCommand sets the proxy command option.
+// setProxyCommand sets the proxy command option. Connections are
+// proxied through the ssh-proxy-host of the environment if it is set,
+// and through the API server otherwise.
 func (c *SSHCommon) setProxyCommand(options *ssh.Options) error {
-	apiServerHost, _, err := net.SplitHostPort(c.apiAddr)
-	if err != nil {
-		return fmt.Errorf("failed to get proxy address: %v", err)
+	proxyHost := c.proxyHost
+	if proxyHost == "" {
+		apiServerHost, _, err := net.SplitHostPort(c.apiAddr)
+		if err != nil {
+			return fmt.Errorf("failed to get proxy address: %v", err)
+		}
+		proxyHost = apiServerHost
 	}
 	juju, err := getJujuExecutable()
 	if err != nil {
 		return fmt.Errorf("failed to get juju executable path: %v", err)
 	}
-	options.SetProxyCommand(juju, "ssh", "--proxy=false", "--pty=false", apiServerHost, "nc", "-q0", "%h", "%p")
+	options.SetProxyCommand(juju, "ssh", "--proxy=false", "--pty=false", proxyHost, "nc", "-q0", "%h", "%p")
 	return nil
 }
 
@@ -83,6 +90,13 @@ Connect to the first mysql unit and run 'ls -la /var/log/juju':
 Connect to the first jenkins unit as the user jenkins:
 
     juju ssh jenkins@jenkins/0
+
+Unless --proxy=false is given or proxy-ssh is false in the environment,
+the connection is proxied through the API server, and the private address
+of the target is used, so that machines without public addresses can be
+reached. If ssh-proxy-host is set in the environment, connections are
+proxied through that machine id or [user@]host instead, such as a jump
+host running alongside the state servers.
 `
 
 func (c *SSHCommand) Info() *cmd.Info {
@@ -160,7 +174,8 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 
 // proxySSH returns true iff both c.proxy and
 // the proxy-ssh environment configuration
-// are true.
+// are true. It also records the ssh-proxy-host
+// of the environment in c.proxyHost.
 func (c *SSHCommon) proxySSH() (bool, error) {
 	if !c.proxy {
 		return false, nil
@@ -177,6 +192,7 @@ func (c *SSHCommon) proxySSH() (bool, error) {
 		return false, err
 	}
 	logger.Debugf("proxy-ssh is %v", cfg.ProxySSH())
+	c.proxyHost = cfg.SSHProxyHost()
 	return cfg.ProxySSH(), nil
 }
 
@@ -143,6 +143,20 @@ func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns")
 }
 
+func (s *SSHSuite) TestSSHCommandEnvironProxyHost(c *gc.C) {
+	s.makeMachines(1, c, true)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"ssh-proxy-host": "ubuntu@bastion"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := coretesting.Context(c)
+	jujucmd := cmd.NewSuperCommand(cmd.SuperCommandParams{})
+	jujucmd.Register(envcmd.Wrap(&SSHCommand{}))
+	code := cmd.Main(jujucmd, ctx, []string{"ssh", "0"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	proxyArgs := strings.Replace(sshArgs, " localhost ", " ubuntu@bastion ", 1)
+	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, proxyArgs+"ubuntu@dummyenv-0.internal")
+}
+
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	// Check the API client interface used by "juju ssh" against what
 	// the API server will allow during upgrades. Ensure that the API
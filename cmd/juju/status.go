@@ -6,6 +6,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -23,6 +24,7 @@ type StatusCommand struct {
 	envcmd.EnvCommandBase
 	out      cmd.Output
 	patterns []string
+	watch    bool
 }
 
 var statusDoc = `
@@ -54,6 +56,11 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+With --watch, the command does not exit: it writes the current state of
+every entity in the environment, then each change to them as it happens,
+as one JSON array per line of the form [kind, "change"|"remove", entity].
+Patterns and --format cannot be used with --watch.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -76,9 +83,13 @@ func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 		"tabular": FormatTabular,
 		"summary": FormatSummary,
 	})
+	f.BoolVar(&c.watch, "watch", false, "stream changes to the environment as JSON deltas")
 }
 
 func (c *StatusCommand) Init(args []string) error {
+	if c.watch && len(args) > 0 {
+		return errors.New("cannot filter status with --watch")
+	}
 	c.patterns = args
 	return nil
 }
@@ -100,6 +111,9 @@ var newApiClientForStatus = func(c *StatusCommand) (statusAPI, error) {
 }
 
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
+	if c.watch {
+		return c.watchStatus(ctx)
+	}
 
 	apiclient, err := newApiClientForStatus(c)
 	if err != nil {
@@ -148,6 +162,7 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	AgentHealth    *agentHealthStatus       `json:"agent-health,omitempty" yaml:"agent-health,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -217,6 +232,14 @@ type unitStatus struct {
 	OpenedPorts    []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress  string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
 	Subordinates   map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	AgentHealth    *agentHealthStatus    `json:"agent-health,omitempty" yaml:"agent-health,omitempty"`
+}
+
+type agentHealthStatus struct {
+	HookQueueDepth   int    `json:"hook-queue-depth" yaml:"hook-queue-depth"`
+	LastAPICall      string `json:"last-api-call,omitempty" yaml:"last-api-call,omitempty"`
+	DataDirAvailable string `json:"data-dir-available" yaml:"data-dir-available"`
+	Reported         string `json:"reported,omitempty" yaml:"reported,omitempty"`
 }
 
 type statusInfoContents struct {
@@ -374,9 +397,28 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 			break
 		}
 	}
+	out.AgentHealth = formatAgentHealth(machine.AgentHealth)
 	return out
 }
 
+func formatAgentHealth(health *params.AgentHealth) *agentHealthStatus {
+	if health == nil {
+		return nil
+	}
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return &agentHealthStatus{
+		HookQueueDepth:   health.HookQueueDepth,
+		LastAPICall:      formatTime(health.LastAPICall),
+		DataDirAvailable: fmt.Sprintf("%dM", health.DataDirAvailable),
+		Reported:         formatTime(health.Reported),
+	}
+}
+
 func (sf *statusFormatter) formatService(name string, service api.ServiceStatus) serviceStatus {
 	out := serviceStatus{
 		Err:           service.Err,
@@ -415,6 +457,7 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		PublicAddress:      unit.PublicAddress,
 		Charm:              unit.Charm,
 		Subordinates:       make(map[string]unitStatus),
+		AgentHealth:        formatAgentHealth(unit.AgentHealth),
 	}
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(m, serviceName)
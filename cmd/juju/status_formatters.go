@@ -93,18 +93,21 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	tw.Flush()
 
 	pUnit := func(name string, u unitStatus, level int) {
+		workloadState, agentState, message := unitStates(u)
 		p(
 			indent("", level*2, name),
-			u.AgentState,
+			workloadState,
+			agentState,
 			u.AgentVersion,
 			u.Machine,
 			strings.Join(u.OpenedPorts, ","),
 			u.PublicAddress,
+			message,
 		)
 	}
 
 	p("\n[Units]")
-	p("ID\tSTATE\tVERSION\tMACHINE\tPORTS\tPUBLIC-ADDRESS")
+	p("ID\tWORKLOAD-STATE\tAGENT-STATE\tVERSION\tMACHINE\tPORTS\tPUBLIC-ADDRESS\tMESSAGE")
 	for _, name := range sortStringsNaturally(stringKeysFromMap(units)) {
 		u := units[name]
 		pUnit(name, u, 0)
@@ -116,6 +119,17 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	return out.Bytes(), nil
 }
 
+// unitStates returns the workload and agent states of the given unit,
+// and the message of its workload. Servers that predate workload status
+// only report the legacy agent state, which is returned as the agent
+// state along with its info.
+func unitStates(u unitStatus) (workloadState, agentState params.Status, message string) {
+	if u.WorkloadStatusInfo.Current == "" {
+		return "", u.AgentState, u.AgentStateInfo
+	}
+	return u.WorkloadStatusInfo.Current, u.AgentStatusInfo.Current, u.WorkloadStatusInfo.Message
+}
+
 // FormatSummary returns a summary of the current environment
 // including the following information:
 // - Headers:
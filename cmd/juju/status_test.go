@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
@@ -16,6 +17,7 @@ import (
 	goyaml "gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -2527,15 +2529,48 @@ func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 			"wordpress  true    cs:quantal/wordpress-3 \n"+
 			"\n"+
 			"[Units]     \n"+
-			"ID          STATE   VERSION MACHINE PORTS PUBLIC-ADDRESS \n"+
-			"mysql/0     started         2             dummyenv-2.dns \n"+
-			"  logging/1 error                         dummyenv-2.dns \n"+
-			"wordpress/0 started         1             dummyenv-1.dns \n"+
-			"  logging/0 started                       dummyenv-1.dns \n"+
+			"ID          WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                        \n"+
+			"mysql/0     active         allocating          2             dummyenv-2.dns                                \n"+
+			"  logging/1 error          allocating                        dummyenv-2.dns somehow lost in all those logs \n"+
+			"wordpress/0 active         allocating          1             dummyenv-1.dns                                \n"+
+			"  logging/0 active         allocating                        dummyenv-1.dns                                \n"+
 			"\n",
 	)
 }
 
+func (s *StatusSuite) TestFormatTabularLegacyUnitStatus(c *gc.C) {
+	// Servers that predate workload status only report agent-state.
+	status := formattedStatus{
+		Services: map[string]serviceStatus{
+			"mysql": {
+				Charm: "cs:quantal/mysql-1",
+				Units: map[string]unitStatus{
+					"mysql/0": {
+						AgentState:     params.StatusError,
+						AgentStateInfo: `hook failed: "install"`,
+						AgentVersion:   "1.23.0",
+						Machine:        "0",
+					},
+				},
+			},
+		},
+	}
+	out, err := FormatTabular(status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"[Machines] \n"+
+		"ID         STATE VERSION DNS INS-ID SERIES HARDWARE \n"+
+		"\n"+
+		"[Services] \n"+
+		"NAME       EXPOSED CHARM              \n"+
+		"mysql      false   cs:quantal/mysql-1 \n"+
+		"\n"+
+		"[Units] \n"+
+		"ID      WORKLOAD-STATE AGENT-STATE VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                \n"+
+		"mysql/0                error       1.23.0  0                            hook failed: \"install\" \n",
+	)
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -2866,3 +2901,36 @@ func (s *StatusSuite) TestSummaryStatusWithUnresolvableDns(c *gc.C) {
 	formatter.resolveAndTrackIp("invalidDns")
 	// Test should not panic.
 }
+
+func (s *StatusSuite) TestStatusAgentHealth(c *gc.C) {
+	when := time.Date(2015, 5, 1, 12, 30, 0, 0, time.UTC)
+	formatter := newStatusFormatter(&api.Status{})
+	out := formatter.formatMachine(api.MachineStatus{
+		Id: "0",
+		AgentHealth: &params.AgentHealth{
+			HookQueueDepth:   4,
+			LastAPICall:      when,
+			DataDirAvailable: 1536,
+			Reported:         when.Add(time.Minute),
+		},
+	})
+	c.Assert(out.AgentHealth, jc.DeepEquals, &agentHealthStatus{
+		HookQueueDepth:   4,
+		LastAPICall:      "2015-05-01T12:30:00Z",
+		DataDirAvailable: "1536M",
+		Reported:         "2015-05-01T12:31:00Z",
+	})
+
+	data, err := goyaml.Marshal(out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Matches, `(?s).*
+agent-health:
+  hook-queue-depth: 4
+  last-api-call: "?2015-05-01T12:30:00Z"?
+  data-dir-available: 1536M
+  reported: "?2015-05-01T12:31:00Z"?
+`)
+
+	out = formatter.formatMachine(api.MachineStatus{Id: "1"})
+	c.Assert(out.AgentHealth, gc.IsNil)
+}
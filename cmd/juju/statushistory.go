@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// defaultStatusHistorySize is the default number of statuses shown.
+const defaultStatusHistorySize = 20
+
+const statusHistoryDoc = `
+Show the most recent statuses set on a unit or machine, most recent
+first. The history of a unit includes the statuses of both its agent
+and its workload, so it can be used to see what happened before the
+unit went into an error state.
+
+Examples:
+
+  # Show the 20 most recent statuses of a unit.
+  juju status-history mysql/0
+
+  # Show the 5 most recent statuses of machine 1.
+  juju status-history -n 5 1
+`
+
+// StatusHistoryCommand shows the status history of a unit or machine.
+type StatusHistoryCommand struct {
+	envcmd.EnvCommandBase
+	out  cmd.Output
+	size int
+	tag  names.Tag
+}
+
+func (c *StatusHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status-history",
+		Args:    "<unit> | <machine>",
+		Purpose: "show the status history of a unit or machine",
+		Doc:     statusHistoryDoc,
+	}
+}
+
+func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.size, "n", defaultStatusHistorySize, "show at most this many statuses")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStatusHistoryTabular,
+	})
+}
+
+func (c *StatusHistoryCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit or machine specified")
+	}
+	switch entity := args[0]; {
+	case names.IsValidUnit(entity):
+		c.tag = names.NewUnitTag(entity)
+	case names.IsValidMachine(entity):
+		c.tag = names.NewMachineTag(entity)
+	default:
+		return errors.Errorf("invalid unit or machine %q", entity)
+	}
+	if c.size <= 0 {
+		return errors.New("-n must be positive")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// StatusHistoryAPI defines the API methods used by the status-history
+// command.
+type StatusHistoryAPI interface {
+	StatusHistory(tag names.Tag, size int) ([]params.StatusHistoryEntry, error)
+	Close() error
+}
+
+var getStatusHistoryAPI = func(c *StatusHistoryCommand) (StatusHistoryAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// statusHistoryEntry defines the serialization of a status history
+// entry.
+type statusHistoryEntry struct {
+	Since  string                 `yaml:"since" json:"since"`
+	Kind   string                 `yaml:"kind" json:"kind"`
+	Status string                 `yaml:"status" json:"status"`
+	Info   string                 `yaml:"info,omitempty" json:"info,omitempty"`
+	Data   map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+// Run shows the status history.
+func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := getStatusHistoryAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	entries, err := client.StatusHistory(c.tag, c.size)
+	if err != nil {
+		return err
+	}
+	output := make([]statusHistoryEntry, len(entries))
+	for i, entry := range entries {
+		output[i] = statusHistoryEntry{
+			Since:  entry.Since.UTC().Format(time.RFC3339),
+			Kind:   entry.Kind,
+			Status: string(entry.Status),
+			Info:   entry.Info,
+			Data:   entry.Data,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatStatusHistoryTabular returns a tabular summary of status
+// history entries. The status data is omitted.
+func formatStatusHistoryTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]statusHistoryEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "SINCE\tKIND\tSTATUS\tINFO\n")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Since, entry.Kind, entry.Status, entry.Info)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
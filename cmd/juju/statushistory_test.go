@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeStatusHistoryAPI
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeStatusHistoryAPI{
+		entries: []params.StatusHistoryEntry{{
+			Kind:   "agent",
+			Status: params.StatusError,
+			Info:   "hook failed: install",
+			Data:   map[string]interface{}{"hook": "install"},
+			Since:  time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+		}, {
+			Kind:   "workload",
+			Status: params.Status("maintenance"),
+			Info:   "installing",
+			Since:  time.Date(2015, 5, 1, 11, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.PatchValue(&getStatusHistoryAPI, func(_ *StatusHistoryCommand) (StatusHistoryAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		tag      names.Tag
+		size     int
+		errMatch string
+	}{{
+		args: []string{"mysql/0"},
+		tag:  names.NewUnitTag("mysql/0"),
+		size: 20,
+	}, {
+		args: []string{"-n", "5", "1/lxc/0"},
+		tag:  names.NewMachineTag("1/lxc/0"),
+		size: 5,
+	}, {
+		errMatch: "no unit or machine specified",
+	}, {
+		args:     []string{"mysql"},
+		errMatch: `invalid unit or machine "mysql"`,
+	}, {
+		args:     []string{"-n", "0", "mysql/0"},
+		errMatch: "-n must be positive",
+	}, {
+		args:     []string{"mysql/0", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &StatusHistoryCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.tag, gc.Equals, test.tag)
+			c.Check(command.size, gc.Equals, test.size)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *StatusHistorySuite) TestRunTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(s.fake.size, gc.Equals, 20)
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"SINCE                 KIND      STATUS       INFO\n"+
+		"2015-05-01T12:00:00Z  agent     error        hook failed: install\n"+
+		"2015-05-01T11:00:00Z  workload  maintenance  installing\n")
+}
+
+func (s *StatusHistorySuite) TestRunJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--format", "json", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[`+
+		`{"since":"2015-05-01T12:00:00Z","kind":"agent","status":"error","info":"hook failed: install","data":{"hook":"install"}},`+
+		`{"since":"2015-05-01T11:00:00Z","kind":"workload","status":"maintenance","info":"installing"}`+
+		`]`+"\n")
+}
+
+type fakeStatusHistoryAPI struct {
+	tag     names.Tag
+	size    int
+	entries []params.StatusHistoryEntry
+	closed  bool
+}
+
+func (f *fakeStatusHistoryAPI) StatusHistory(tag names.Tag, size int) ([]params.StatusHistoryEntry, error) {
+	f.tag = tag
+	f.size = size
+	return f.entries, nil
+}
+
+func (f *fakeStatusHistoryAPI) Close() error {
+	f.closed = true
+	return nil
+}
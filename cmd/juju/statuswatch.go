@@ -0,0 +1,78 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// statusWatchAPI defines the API methods used by status --watch.
+type statusWatchAPI interface {
+	WatchAll() (AllWatcher, error)
+	Close() error
+}
+
+var newAPIClientForStatusWatch = func(c *StatusCommand) (statusWatchAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return allWatcherClient{client}, nil
+}
+
+// watchStatus writes the deltas of an all watcher to stdout, one JSON
+// array per line, until the command is interrupted.
+func (c *StatusCommand) watchStatus(ctx *cmd.Context) error {
+	client, err := newAPIClientForStatusWatch(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return err
+	}
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupted:
+			// Stopping the watcher makes any pending Next call
+			// return.
+			close(stopped)
+			watcher.Stop()
+		case <-done:
+			watcher.Stop()
+		}
+	}()
+
+	encoder := json.NewEncoder(ctx.Stdout)
+	for {
+		deltas, err := watcher.Next()
+		select {
+		case <-stopped:
+			return nil
+		default:
+		}
+		if err != nil {
+			return errors.Annotate(err, "cannot watch environment")
+		}
+		for _, delta := range deltas {
+			if err := encoder.Encode(&delta); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/testing"
+)
+
+type StatusWatchSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeWaitAPI
+}
+
+var _ = gc.Suite(&StatusWatchSuite{})
+
+func (s *StatusWatchSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeWaitAPI{
+		deltas: make(chan []multiwatcher.Delta, 10),
+	}
+	s.PatchValue(&newAPIClientForStatusWatch, func(_ *StatusCommand) (statusWatchAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *StatusWatchSuite) TestWatchWithPatterns(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--watch", "mysql")
+	c.Assert(err, gc.ErrorMatches, "cannot filter status with --watch")
+}
+
+func (s *StatusWatchSuite) TestWatch(c *gc.C) {
+	s.fake.deltas <- []multiwatcher.Delta{
+		{Entity: &multiwatcher.ServiceInfo{Name: "mysql"}},
+		{Entity: &multiwatcher.UnitInfo{Name: "mysql/0", Service: "mysql"}},
+	}
+	s.fake.deltas <- []multiwatcher.Delta{
+		{Removed: true, Entity: &multiwatcher.UnitInfo{Name: "mysql/0", Service: "mysql"}},
+	}
+	s.fake.onNext = func() {
+		if s.fake.nextCalls == 3 {
+			s.fake.err = errors.New("connection lost")
+		}
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--watch")
+	c.Assert(err, gc.ErrorMatches, "cannot watch environment: connection lost")
+	lines := testing.Stdout(ctx)
+	c.Assert(lines, gc.Matches, ``+
+		`\["service","change",\{"Name":"mysql",[^\n]*\}\]\n`+
+		`\["unit","change",\{"Name":"mysql/0","Service":"mysql",[^\n]*\}\]\n`+
+		`\["unit","remove",\{"Name":"mysql/0","Service":"mysql",[^\n]*\}\]\n`)
+	c.Assert(s.fake.closed, gc.Equals, true)
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -32,12 +33,17 @@ environment as defined by the file $JUJU_HOME/current-environment.
 If a command line parameter is passed in, that value will is stored in the
 current environment file if it represents a valid environment name as
 specified in the environments.yaml file.
+
+An environment hosted by a Juju Environment Server may also be given as
+<controller>:<environment>, in which case the environment must be known
+to run in the named controller. The --list option shows the environments
+in the same way, naming the controller of each hosted environment.
 `
 
 func (c *SwitchCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "switch",
-		Args:    "[environment name]",
+		Args:    "[[<controller>:]<environment name>]",
 		Purpose: "show or change the default juju environment name",
 		Doc:     switchDoc,
 		Aliases: []string{"env"},
@@ -54,11 +60,7 @@ func (c *SwitchCommand) Init(args []string) (err error) {
 	return
 }
 
-func getConfigstoreEnvironments() (set.Strings, error) {
-	store, err := configstore.Default()
-	if err != nil {
-		return nil, errors.Annotate(err, "failed to get config store")
-	}
+func getConfigstoreEnvironments(store configstore.Storage) (set.Strings, error) {
 	other, err := store.List()
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to list environments in config store")
@@ -66,6 +68,38 @@ func getConfigstoreEnvironments() (set.Strings, error) {
 	return set.NewStrings(other...), nil
 }
 
+// displayName returns the environment name qualified with the name of
+// its controller when the environment is hosted by a controller.
+func displayName(store configstore.Storage, envName string) (string, error) {
+	controllerName, err := envcmd.ControllerName(store, envName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if controllerName == envName {
+		return envName, nil
+	}
+	return controllerName + ":" + envName, nil
+}
+
+// resolveEnvName returns the name of the environment given on the
+// command line, checking that an environment given as
+// <controller>:<environment> runs in that controller.
+func resolveEnvName(store configstore.Storage, name string) (string, error) {
+	i := strings.Index(name, ":")
+	if i < 0 {
+		return name, nil
+	}
+	controllerName, envName := name[:i], name[i+1:]
+	actual, err := envcmd.ControllerName(store, envName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if actual != controllerName {
+		return "", errors.Errorf("environment %q is not hosted by controller %q", envName, controllerName)
+	}
+	return envName, nil
+}
+
 func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 	// Switch is an alternative way of dealing with environments than using
 	// the JUJU_ENV environment setting, and as such, doesn't play too well.
@@ -78,8 +112,12 @@ func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("couldn't read the environment")
 	}
 
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Annotate(err, "failed to get config store")
+	}
 	names := set.NewStrings(environments.Names()...)
-	configEnvirons, err := getConfigstoreEnvironments()
+	configEnvirons, err := getConfigstoreEnvironments(store)
 	if err != nil {
 		return err
 	}
@@ -91,6 +129,10 @@ func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 			return errors.New("cannot switch and list at the same time")
 		}
 		for _, name := range names.SortedValues() {
+			name, err := displayName(store, name)
+			if err != nil {
+				return err
+			}
 			fmt.Fprintf(ctx.Stdout, "%s\n", name)
 		}
 		return nil
@@ -121,16 +163,20 @@ func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 		fmt.Fprintf(ctx.Stdout, "%s\n", currentEnv)
 	default:
 		// Switch the environment.
-		if !names.Contains(c.EnvName) {
+		envName, err := resolveEnvName(store, c.EnvName)
+		if err != nil {
+			return err
+		}
+		if !names.Contains(envName) {
 			return errors.Errorf("%q is not a name of an existing defined environment", c.EnvName)
 		}
-		if err := envcmd.WriteCurrentEnvironment(c.EnvName); err != nil {
+		if err := envcmd.WriteCurrentEnvironment(envName); err != nil {
 			return err
 		}
 		if currentEnv == "" {
-			fmt.Fprintf(ctx.Stdout, "-> %s\n", c.EnvName)
+			fmt.Fprintf(ctx.Stdout, "-> %s\n", envName)
 		} else {
-			fmt.Fprintf(ctx.Stdout, "%s -> %s\n", currentEnv, c.EnvName)
+			fmt.Fprintf(ctx.Stdout, "%s -> %s\n", currentEnv, envName)
 		}
 	}
 	return nil
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/feature"
 	_ "github.com/juju/juju/juju"
 	"github.com/juju/juju/testing"
 )
@@ -114,6 +115,56 @@ func (s *SwitchSimpleSuite) TestListEnvironmentsWithConfigstore(c *gc.C) {
 	c.Assert(testing.Stdout(context), gc.Equals, expected)
 }
 
+// writeHostedEnvironments records a controller environment and an
+// environment hosted by it in the controllers files.
+func (s *SwitchSimpleSuite) writeHostedEnvironments(c *gc.C) {
+	s.SetFeatureFlags(feature.EnvironmentsCacheFile)
+	store, err := configstore.Default()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, env := range []struct{ name, envUUID string }{
+		{"ctrl", "server-uuid"},
+		{"hosted", "hosted-uuid"},
+	} {
+		info := store.CreateInfo(env.name)
+		info.SetAPIEndpoint(configstore.APIEndpoint{
+			EnvironUUID: env.envUUID,
+			ServerUUID:  "server-uuid",
+		})
+		err := info.Write()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *SwitchSimpleSuite) TestListHostedEnvironments(c *gc.C) {
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	s.writeHostedEnvironments(c)
+	context, err := testing.RunCommand(c, &SwitchCommand{}, "--list")
+	c.Assert(err, jc.ErrorIsNil)
+	expected := "ctrl\n" + expectedEnvironments + "ctrl:hosted\n"
+	c.Assert(testing.Stdout(context), gc.Equals, expected)
+}
+
+func (s *SwitchSimpleSuite) TestSettingHostedEnvironment(c *gc.C) {
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	s.writeHostedEnvironments(c)
+	context, err := testing.RunCommand(c, &SwitchCommand{}, "ctrl:hosted")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "erewhemos -> hosted\n")
+	c.Assert(envcmd.ReadCurrentEnvironment(), gc.Equals, "hosted")
+
+	context, err = testing.RunCommand(c, &SwitchCommand{}, "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "hosted -> ctrl\n")
+}
+
+func (s *SwitchSimpleSuite) TestSettingHostedEnvironmentWrongController(c *gc.C) {
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	s.writeHostedEnvironments(c)
+	_, err := testing.RunCommand(c, &SwitchCommand{}, "erewhemos:hosted")
+	c.Assert(err, gc.ErrorMatches, `environment "hosted" is not hosted by controller "erewhemos"`)
+	c.Assert(envcmd.ReadCurrentEnvironment(), gc.Equals, "")
+}
+
 func (*SwitchSimpleSuite) TestListEnvironmentsOSJujuEnvSet(c *gc.C) {
 	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
 	os.Setenv("JUJU_ENV", "using-env")
@@ -25,7 +25,8 @@ type UpgradeCharmCommand struct {
 	Force       bool
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
-	Revision    int // defaults to -1 (latest)
+	Revision    int    // defaults to -1 (latest)
+	Channel     string // defaults to the stable channel
 }
 
 const upgradeCharmDoc = `
@@ -57,6 +58,9 @@ have the same types.
 
 The new charm may add new relations and configuration settings.
 
+Charm store charms are upgraded from the stable channel of the charm store,
+unless another channel (candidate or edge) is chosen with the --channel flag.
+
 --switch and --revision are mutually exclusive. To specify a given revision
 number with --switch, give it in the charm URL, for instance "cs:wordpress-5"
 would specify revision number 5 of the wordpress charm.
@@ -80,6 +84,7 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.StringVar(&c.Channel, "channel", "", "charm store channel to upgrade from: stable, candidate or edge")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -97,6 +102,9 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if err := config.ValidateCharmChannel(c.Channel); err != nil {
+		return fmt.Errorf("invalid --channel: %v", err)
+	}
 	return nil
 }
 
@@ -137,6 +145,10 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	repo, err = config.CharmRepoForChannel(repo, c.Channel)
+	if err != nil {
+		return err
+	}
 	repo = config.SpecializeCharmRepo(repo, conf)
 
 	// If no explicit revision was set with either SwitchURL
@@ -161,7 +173,7 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	addedURL, err := addCharmViaAPI(client, ctx, newURL, repo)
+	addedURL, err := addCharmViaAPI(client, ctx, newURL, repo, c.Channel, oldURL)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
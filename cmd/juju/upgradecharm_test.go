@@ -97,6 +97,17 @@ func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid value "blah" for flag --revision: strconv.ParseInt: parsing "blah": invalid syntax`)
 }
 
+func (s *UpgradeCharmErrorsSuite) TestInvalidChannel(c *gc.C) {
+	err := runUpgradeCharm(c, "riak", "--channel=beta")
+	c.Assert(err, gc.ErrorMatches, `invalid --channel: charm channel "beta" not valid`)
+}
+
+func (s *UpgradeCharmErrorsSuite) TestChannelWithLocalCharm(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--channel=edge")
+	c.Assert(err, gc.ErrorMatches, `charm channel "edge" not supported`)
+}
+
 type UpgradeCharmSuccessSuite struct {
 	jujutesting.RepoSuite
 	CmdBlockHelper
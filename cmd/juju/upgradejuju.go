@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -35,6 +36,8 @@ type UpgradeJujuCommand struct {
 	ResetPrevious bool
 	AssumeYes     bool
 	Series        []string
+	Canaries      []string
+	Resume        bool
 }
 
 var upgradeJujuDoc = `
@@ -74,7 +77,14 @@ progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
 availability environment failed to upgrade. If a failed upgrade has
 been resolved, the --reset-previous-upgrade flag can be used to reset
-the environment's upgrade tracking state, allowing further upgrades.`
+the environment's upgrade tracking state, allowing further upgrades.
+
+The state servers always upgrade first. With the --canary flag, only the
+state servers and the given machines (with their containers and units)
+upgrade; the other agents keep running the previous version until the
+upgrade is resumed with the --resume flag, once the canaries have been
+checked. The progress of an upgrade is shown by the upgrade-status
+command.`
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -92,9 +102,22 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.AssumeYes, "y", false, "answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list (OBSOLETE)")
+	f.Var(cmd.NewStringsValue(nil, &c.Canaries), "canary", "upgrade only the supplied comma-separated machines until the upgrade is resumed")
+	f.BoolVar(&c.Resume, "resume", false, "resume an upgrade paused after upgrading its canary machines")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
+	if c.Resume {
+		if c.vers != "" || c.UploadTools || c.DryRun || c.ResetPrevious || len(c.Canaries) > 0 || len(c.Series) > 0 {
+			return fmt.Errorf("--resume cannot be combined with other flags")
+		}
+		return cmd.CheckEmpty(args)
+	}
+	for _, id := range c.Canaries {
+		if !names.IsValidMachine(id) {
+			return fmt.Errorf("invalid canary machine id %q", id)
+		}
+	}
 	if c.vers != "" {
 		vers, err := version.Parse(c.vers)
 		if err != nil {
@@ -136,6 +159,8 @@ type upgradeJujuAPI interface {
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
+	SetEnvironAgentVersionWithCanaries(version version.Number, canaries []string) error
+	ResumeUpgrade() error
 	Close() error
 }
 
@@ -154,6 +179,13 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 		return err
 	}
 	defer client.Close()
+	if c.Resume {
+		if err := client.ResumeUpgrade(); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		logger.Infof("resumed upgrade")
+		return nil
+	}
 	defer func() {
 		if err == errUpToDate {
 			ctx.Infof(err.Error())
@@ -200,7 +232,7 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
-		if err := client.SetEnvironAgentVersion(context.chosen); err != nil {
+		if err := c.setAgentVersion(client, context.chosen); err != nil {
 			if params.IsCodeUpgradeInProgress(err) {
 				return errors.Errorf("%s\n\n"+
 					"Please wait for the upgrade to complete or if there was a problem with\n"+
@@ -212,10 +244,22 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 			}
 		}
 		logger.Infof("started upgrade to %s", context.chosen)
+		if len(c.Canaries) > 0 {
+			ctx.Infof("only the state servers and machines %s will upgrade until the upgrade is resumed with\n    juju upgrade-juju --resume", strings.Join(c.Canaries, ", "))
+		}
 	}
 	return nil
 }
 
+// setAgentVersion sets the environment's agent version, upgrading the
+// canary machines first if any were specified.
+func (c *UpgradeJujuCommand) setAgentVersion(client upgradeJujuAPI, vers version.Number) error {
+	if len(c.Canaries) > 0 {
+		return client.SetEnvironAgentVersionWithCanaries(vers, c.Canaries)
+	}
+	return client.SetEnvironAgentVersion(vers)
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
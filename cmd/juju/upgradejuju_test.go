@@ -635,6 +635,48 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeWithCanaries(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--canary", "1,2/lxc/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.canariesCalledWith, jc.DeepEquals, []string{"1", "2/lxc/0"})
+	c.Assert(coretesting.Stderr(ctx), jc.Contains, "juju upgrade-juju --resume")
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeInvalidCanary(c *gc.C) {
+	err := coretesting.InitCommand(envcmd.Wrap(&UpgradeJujuCommand{}), []string{"--canary", "foo"})
+	c.Assert(err, gc.ErrorMatches, `invalid canary machine id "foo"`)
+}
+
+func (s *UpgradeJujuSuite) TestResumeUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd), []string{"--resume"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmd.Run(coretesting.Context(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.resumeUpgradeCalled, jc.IsTrue)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+}
+
+func (s *UpgradeJujuSuite) TestResumeUpgradeWithOtherFlags(c *gc.C) {
+	for _, args := range [][]string{
+		{"--resume", "--version", "1.2.3"},
+		{"--resume", "--canary", "1"},
+		{"--resume", "--dry-run"},
+	} {
+		err := coretesting.InitCommand(envcmd.Wrap(&UpgradeJujuCommand{}), args)
+		c.Check(err, gc.ErrorMatches, "--resume cannot be combined with other flags")
+	}
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Current
 	nextVersion.Minor++
@@ -652,12 +694,16 @@ type fakeUpgradeJujuAPI struct {
 	setVersionErr             error
 	abortCurrentUpgradeCalled bool
 	setVersionCalledWith      version.Number
+	canariesCalledWith        []string
+	resumeUpgradeCalled       bool
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
 	a.setVersionErr = nil
 	a.abortCurrentUpgradeCalled = false
 	a.setVersionCalledWith = version.Number{}
+	a.canariesCalledWith = nil
+	a.resumeUpgradeCalled = false
 }
 
 func (a *fakeUpgradeJujuAPI) patch(s *UpgradeJujuSuite) {
@@ -700,6 +746,17 @@ func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersionWithCanaries(v version.Number, canaries []string) error {
+	a.setVersionCalledWith = v
+	a.canariesCalledWith = canaries
+	return a.setVersionErr
+}
+
+func (a *fakeUpgradeJujuAPI) ResumeUpgrade() error {
+	a.resumeUpgradeCalled = true
+	return nil
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const upgradeSeriesDoc = `
+Upgrade the operating system series of a machine in two steps.
+
+"juju upgrade-series <machine> prepare <series>" has each unit on the
+machine run its pre-series-upgrade hook, after which the unit agents
+pause. Once they have all paused, upgrade the operating system of the
+machine by hand, for example with do-release-upgrade.
+
+"juju upgrade-series <machine> complete" then resumes the unit agents.
+Each unit runs its post-series-upgrade hook, and once they all have the
+series of the machine is updated.
+
+Only the units assigned to the machine when the upgrade is prepared
+take part in it. State server machines and containers cannot be
+upgraded this way.
+
+Examples:
+
+  juju upgrade-series 1 prepare trusty
+  juju upgrade-series 1 complete
+`
+
+const (
+	upgradeSeriesPrepare  = "prepare"
+	upgradeSeriesComplete = "complete"
+)
+
+// UpgradeSeriesCommand prepares or completes the upgrade of the
+// operating system series of a machine.
+type UpgradeSeriesCommand struct {
+	envcmd.EnvCommandBase
+	MachineId string
+	Step      string
+	Series    string
+}
+
+func (c *UpgradeSeriesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-series",
+		Args:    "<machine> prepare <series> | <machine> complete",
+		Purpose: "upgrade the operating system series of a machine",
+		Doc:     upgradeSeriesDoc,
+	}
+}
+
+func (c *UpgradeSeriesCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("no machine and step specified")
+	}
+	c.MachineId, c.Step, args = args[0], args[1], args[2:]
+	if !names.IsValidMachine(c.MachineId) {
+		return errors.Errorf("invalid machine id %q", c.MachineId)
+	}
+	switch c.Step {
+	case upgradeSeriesPrepare:
+		if len(args) == 0 {
+			return errors.New("no series specified")
+		}
+		c.Series, args = args[0], args[1:]
+	case upgradeSeriesComplete:
+	default:
+		return errors.Errorf("unknown step %q; expected %q or %q",
+			c.Step, upgradeSeriesPrepare, upgradeSeriesComplete)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// UpgradeSeriesAPI defines the API methods used by the upgrade-series
+// command.
+type UpgradeSeriesAPI interface {
+	UpgradeSeriesPrepare(machine, series string) error
+	UpgradeSeriesComplete(machine string) error
+	Close() error
+}
+
+var getUpgradeSeriesAPI = func(c *UpgradeSeriesCommand) (UpgradeSeriesAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return client, nil
+}
+
+// Run prepares or completes the series upgrade of the machine.
+func (c *UpgradeSeriesCommand) Run(ctx *cmd.Context) error {
+	client, err := getUpgradeSeriesAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.Step == upgradeSeriesComplete {
+		if err := client.UpgradeSeriesComplete(c.MachineId); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		ctx.Infof("completing series upgrade of machine %s", c.MachineId)
+		return nil
+	}
+	if err := client.UpgradeSeriesPrepare(c.MachineId, c.Series); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("preparing series upgrade of machine %s to %s", c.MachineId, c.Series)
+	ctx.Infof("once its units have paused, upgrade the operating system and run:\n"+
+		"  juju upgrade-series %s complete", c.MachineId)
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeUpgradeSeriesAPI
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeUpgradeSeriesAPI{}
+	s.PatchValue(&getUpgradeSeriesAPI, func(_ *UpgradeSeriesCommand) (UpgradeSeriesAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *UpgradeSeriesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machine and step specified",
+	}, {
+		args: []string{"1"},
+		err:  "no machine and step specified",
+	}, {
+		args: []string{"foo", "complete"},
+		err:  `invalid machine id "foo"`,
+	}, {
+		args: []string{"1", "upgrade"},
+		err:  `unknown step "upgrade"; expected "prepare" or "complete"`,
+	}, {
+		args: []string{"1", "prepare"},
+		err:  "no series specified",
+	}, {
+		args: []string{"1", "prepare", "trusty", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"1", "complete", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&UpgradeSeriesCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *UpgradeSeriesSuite) TestPrepare(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeSeriesCommand{}), "1", "prepare", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"prepare 1 trusty"})
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"preparing series upgrade of machine 1 to trusty\n"+
+		"once its units have paused, upgrade the operating system and run:\n"+
+		"  juju upgrade-series 1 complete\n")
+}
+
+func (s *UpgradeSeriesSuite) TestComplete(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeSeriesCommand{}), "1", "complete")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"complete 1"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "completing series upgrade of machine 1\n")
+}
+
+func (s *UpgradeSeriesSuite) TestError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeSeriesCommand{}), "1", "complete")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeUpgradeSeriesAPI struct {
+	calls  []string
+	err    error
+	closed bool
+}
+
+func (f *fakeUpgradeSeriesAPI) UpgradeSeriesPrepare(machine, series string) error {
+	f.calls = append(f.calls, "prepare "+machine+" "+series)
+	return f.err
+}
+
+func (f *fakeUpgradeSeriesAPI) UpgradeSeriesComplete(machine string) error {
+	f.calls = append(f.calls, "complete "+machine)
+	return f.err
+}
+
+func (f *fakeUpgradeSeriesAPI) Close() error {
+	f.closed = true
+	return nil
+}
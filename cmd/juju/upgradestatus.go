@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const upgradeStatusDoc = `
+Show the progress of the environment's agents towards the environment's
+agent version: the version each machine agent is running, and whether
+the machine is told to upgrade in the current wave of the upgrade.
+
+An upgrade started with "juju upgrade-juju --canary" stays in the canary
+wave, in which only the state servers and the canary machines upgrade,
+until it is resumed with "juju upgrade-juju --resume".
+
+Examples:
+
+  juju upgrade-status
+  juju upgrade-status --format json
+`
+
+// UpgradeStatusCommand shows the progress of an upgrade of the
+// environment's agents.
+type UpgradeStatusCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *UpgradeStatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-status",
+		Purpose: "show the progress of an upgrade of the juju agents",
+		Doc:     upgradeStatusDoc,
+	}
+}
+
+func (c *UpgradeStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *UpgradeStatusCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// UpgradeStatusAPI defines the API methods used by the upgrade-status
+// command.
+type UpgradeStatusAPI interface {
+	UpgradeStatus() (params.UpgradeStatusResult, error)
+	Close() error
+}
+
+var getUpgradeStatusAPI = func(c *UpgradeStatusCommand) (UpgradeStatusAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return client, nil
+}
+
+// upgradeStatus defines the serialization of the progress of an
+// upgrade.
+type upgradeStatus struct {
+	PreviousVersion string                          `yaml:"previous-version,omitempty" json:"previous-version,omitempty"`
+	TargetVersion   string                          `yaml:"target-version" json:"target-version"`
+	Wave            string                          `yaml:"wave" json:"wave"`
+	Canaries        []string                        `yaml:"canaries,omitempty" json:"canaries,omitempty"`
+	Progress        string                          `yaml:"progress" json:"progress"`
+	Machines        map[string]machineUpgradeStatus `yaml:"machines,omitempty" json:"machines,omitempty"`
+}
+
+// machineUpgradeStatus defines the serialization of the upgrade
+// progress of a machine agent.
+type machineUpgradeStatus struct {
+	Version  string `yaml:"version,omitempty" json:"version,omitempty"`
+	InWave   bool   `yaml:"in-wave" json:"in-wave"`
+	Upgraded bool   `yaml:"upgraded" json:"upgraded"`
+}
+
+// Run shows the progress of the upgrade.
+func (c *UpgradeStatusCommand) Run(ctx *cmd.Context) error {
+	client, err := getUpgradeStatusAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := client.UpgradeStatus()
+	if err != nil {
+		return err
+	}
+	output := upgradeStatus{
+		TargetVersion: result.TargetVersion.String(),
+		Wave:          result.Wave,
+		Canaries:      result.Canaries,
+	}
+	if result.PreviousVersion != nil {
+		output.PreviousVersion = result.PreviousVersion.String()
+	}
+	upgraded := 0
+	if len(result.Machines) > 0 {
+		output.Machines = make(map[string]machineUpgradeStatus)
+	}
+	for _, m := range result.Machines {
+		output.Machines[m.Id] = machineUpgradeStatus{
+			Version:  m.AgentVersion,
+			InWave:   m.InWave,
+			Upgraded: m.Upgraded,
+		}
+		if m.Upgraded {
+			upgraded++
+		}
+	}
+	output.Progress = fmt.Sprintf("%d of %d machines upgraded", upgraded, len(result.Machines))
+	return c.out.Write(ctx, output)
+}
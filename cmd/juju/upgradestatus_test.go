@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type UpgradeStatusSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeUpgradeStatusAPI
+}
+
+var _ = gc.Suite(&UpgradeStatusSuite{})
+
+func (s *UpgradeStatusSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	previous := version.MustParse("1.24.0")
+	s.fake = &fakeUpgradeStatusAPI{
+		result: params.UpgradeStatusResult{
+			PreviousVersion: &previous,
+			TargetVersion:   version.MustParse("1.24.1"),
+			Wave:            "canary",
+			Canaries:        []string{"1"},
+			Machines: []params.MachineUpgradeStatus{{
+				Id:           "0",
+				AgentVersion: "1.24.1",
+				InWave:       true,
+				Upgraded:     true,
+			}, {
+				Id:           "1",
+				AgentVersion: "1.24.0",
+				InWave:       true,
+			}, {
+				Id:           "2",
+				AgentVersion: "1.24.0",
+			}},
+		},
+	}
+	s.PatchValue(&getUpgradeStatusAPI, func(_ *UpgradeStatusCommand) (UpgradeStatusAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *UpgradeStatusSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&UpgradeStatusCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *UpgradeStatusSuite) TestRunYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeStatusCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"previous-version: 1.24.0\n"+
+		"target-version: 1.24.1\n"+
+		"wave: canary\n"+
+		"canaries:\n"+
+		"- \"1\"\n"+
+		"progress: 1 of 3 machines upgraded\n"+
+		"machines:\n"+
+		"  \"0\":\n"+
+		"    version: 1.24.1\n"+
+		"    in-wave: true\n"+
+		"    upgraded: true\n"+
+		"  \"1\":\n"+
+		"    version: 1.24.0\n"+
+		"    in-wave: true\n"+
+		"    upgraded: false\n"+
+		"  \"2\":\n"+
+		"    version: 1.24.0\n"+
+		"    in-wave: false\n"+
+		"    upgraded: false\n")
+}
+
+func (s *UpgradeStatusSuite) TestRunJSONWithoutMachines(c *gc.C) {
+	s.fake.result = params.UpgradeStatusResult{
+		TargetVersion: version.MustParse("1.24.1"),
+		Wave:          "all",
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeStatusCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`{"target-version":"1.24.1","wave":"all","progress":"0 of 0 machines upgraded"}`+"\n")
+}
+
+type fakeUpgradeStatusAPI struct {
+	result params.UpgradeStatusResult
+	closed bool
+}
+
+func (f *fakeUpgradeStatusAPI) UpgradeStatus() (params.UpgradeStatusResult, error) {
+	return f.result, nil
+}
+
+func (f *fakeUpgradeStatusAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -32,6 +32,9 @@ Examples:
   # Add user "foobar" with a strong random password is generated.
   juju user add foobar --generate
 
+  # Add user "auditor", who may look at but not change the environment.
+  juju user add auditor --acl=read
+
 
 See Also:
   juju user change-password
@@ -45,6 +48,7 @@ type AddCommand struct {
 	Password    string
 	OutPath     string
 	Generate    bool
+	Access      string
 }
 
 // Info implements Command.Info.
@@ -62,6 +66,7 @@ func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Generate, "generate", false, "generate a new strong password")
 	f.StringVar(&c.OutPath, "o", "", "specify the environment file for new user")
 	f.StringVar(&c.OutPath, "output", "", "")
+	f.StringVar(&c.Access, "acl", "write", `the user's access level, "read" or "write"`)
 }
 
 // Init implements Command.Init.
@@ -73,12 +78,15 @@ func (c *AddCommand) Init(args []string) error {
 	if len(args) > 0 {
 		c.DisplayName, args = args[0], args[1:]
 	}
+	if c.Access != "read" && c.Access != "write" {
+		return errors.Errorf(`invalid --acl %q, expected "read" or "write"`, c.Access)
+	}
 	return cmd.CheckEmpty(args)
 }
 
 // AddUserAPI defines the usermanager API methods that the add command uses.
 type AddUserAPI interface {
-	AddUser(username, displayName, password string) (names.UserTag, error)
+	AddUserWithAccess(username, displayName, password, access string) (names.UserTag, error)
 	Close() error
 }
 
@@ -124,7 +132,7 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	tag, err := client.AddUser(c.User, c.DisplayName, c.Password)
+	tag, err := client.AddUserWithAccess(c.User, c.DisplayName, c.Password, c.Access)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
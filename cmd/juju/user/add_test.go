@@ -51,6 +51,7 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 		displayname string
 		outPath     string
 		generate    bool
+		access      string
 		errorString string
 	}{
 		{
@@ -77,6 +78,13 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 			args:    []string{"foobar", "-o", "somefile"},
 			user:    "foobar",
 			outPath: "somefile",
+		}, {
+			args:   []string{"foobar", "--acl", "read"},
+			user:   "foobar",
+			access: "read",
+		}, {
+			args:        []string{"foobar", "--acl", "admin"},
+			errorString: `invalid --acl "admin", expected "read" or "write"`,
 		},
 	} {
 		c.Logf("test %d", i)
@@ -87,6 +95,10 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 			c.Check(addUserCmd.DisplayName, gc.Equals, test.displayname)
 			c.Check(addUserCmd.OutPath, gc.Equals, test.outPath)
 			c.Check(addUserCmd.Generate, gc.Equals, test.generate)
+			if test.access == "" {
+				test.access = "write"
+			}
+			c.Check(addUserCmd.Access, gc.Equals, test.access)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errorString)
 		}
@@ -125,6 +137,7 @@ func (s *UserAddCommandSuite) TestAddUserJustUsername(c *gc.C) {
 	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
 	c.Assert(s.mockAPI.displayname, gc.Equals, "")
 	c.Assert(s.mockAPI.password, gc.Equals, "sekrit")
+	c.Assert(s.mockAPI.access, gc.Equals, "write")
 	expected := `
 password:
 type password again:
@@ -201,13 +214,14 @@ type mockAddUserAPI struct {
 	username    string
 	displayname string
 	password    string
+	access      string
 
 	shareFailMsg string
 	sharedUsers  []names.UserTag
 	blocked      bool
 }
 
-func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.UserTag, error) {
+func (m *mockAddUserAPI) AddUserWithAccess(username, displayname, password, access string) (names.UserTag, error) {
 	if m.blocked {
 		return names.UserTag{}, common.ErrOperationBlocked("The operation has been blocked.")
 	}
@@ -215,6 +229,7 @@ func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.
 	m.username = username
 	m.displayname = displayname
 	m.password = password
+	m.access = access
 	if m.failMessage == "" {
 		return names.NewLocalUserTag(username), nil
 	}
@@ -0,0 +1,206 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/scopedcredentials"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const createCredentialDoc = `
+Create a time-limited credential that gives access to some of the Juju
+API facades on your behalf, so that scripts and CI systems can use the
+API without your password. The credential can be limited further to
+calls acting on the given entities, identified by their tags.
+
+The output holds what is needed to connect to the API: the addresses and
+CA certificate of the API servers, the environment UUID, and the
+credential's macaroon, which is presented instead of a user name and
+password when logging in.
+
+Examples:
+  juju user create-credential --facades Client,Service --expiry 2h
+  juju user create-credential --facades Client --entities unit-wordpress-0
+
+See Also:
+  juju user revoke-credential
+`
+
+const revokeCredentialDoc = `
+Revoke a credential created with "juju user create-credential", so that
+it can no longer be used to log in. Connections already made with it are
+not affected.
+
+Examples:
+  juju user revoke-credential 5e4c1f3a-...
+
+See Also:
+  juju user create-credential
+`
+
+// ScopedCredentialsAPI defines the API methods that the create-credential
+// and revoke-credential commands use.
+type ScopedCredentialsAPI interface {
+	CreateScopedCredential(facades, entities []string, expiry time.Duration) (params.ScopedCredential, error)
+	RevokeScopedCredential(id string) error
+	Close() error
+}
+
+// ScopedCredentialCommandBase is the base of the create-credential and
+// revoke-credential commands.
+type ScopedCredentialCommandBase struct {
+	UserCommandBase
+}
+
+func (c *ScopedCredentialCommandBase) getScopedCredentialsAPI() (ScopedCredentialsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return scopedcredentials.NewClient(root), nil
+}
+
+var getScopedCredentialsAPI = (*ScopedCredentialCommandBase).getScopedCredentialsAPI
+
+// CreateCredentialCommand creates scoped credentials.
+type CreateCredentialCommand struct {
+	ScopedCredentialCommandBase
+	out      cmd.Output
+	facades  string
+	entities string
+	expiry   time.Duration
+}
+
+// Info implements Command.Info.
+func (c *CreateCredentialCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-credential",
+		Purpose: "create a time-limited credential for scripts to use the API",
+		Doc:     createCredentialDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *CreateCredentialCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.facades, "facades", "", "comma-separated names of the API facades that the credential gives access to")
+	f.StringVar(&c.entities, "entities", "", "comma-separated tags of the entities that the credential is limited to")
+	f.DurationVar(&c.expiry, "expiry", 0, "how long the credential remains valid (default 1h)")
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *CreateCredentialCommand) Init(args []string) error {
+	if c.facades == "" {
+		return errors.New("no facades specified")
+	}
+	for _, tag := range splitList(c.entities) {
+		if _, err := names.ParseTag(tag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if c.expiry < 0 {
+		return errors.New("expiry must not be negative")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// scopedCredentialOutput is the output of the create-credential
+// command.
+type scopedCredentialOutput struct {
+	Id           string    `yaml:"id" json:"id"`
+	Expires      time.Time `yaml:"expires" json:"expires"`
+	EnvironUUID  string    `yaml:"environ-uuid" json:"environ-uuid"`
+	APIEndpoints []string  `yaml:"api-endpoints" json:"api-endpoints"`
+	CACert       string    `yaml:"ca-cert" json:"ca-cert"`
+	// Macaroon holds the JSON encoding of the credential's macaroon.
+	Macaroon string `yaml:"macaroon" json:"macaroon"`
+}
+
+// Run implements Command.Run.
+func (c *CreateCredentialCommand) Run(ctx *cmd.Context) error {
+	client, err := getScopedCredentialsAPI(&c.ScopedCredentialCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	cred, err := client.CreateScopedCredential(splitList(c.facades), splitList(c.entities), c.expiry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := json.Marshal(cred.Macaroon)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal macaroon")
+	}
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, scopedCredentialOutput{
+		Id:           cred.Id,
+		Expires:      cred.Expires,
+		EnvironUUID:  endpoint.EnvironUUID,
+		APIEndpoints: endpoint.Addresses,
+		CACert:       endpoint.CACert,
+		Macaroon:     string(data),
+	})
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// RevokeCredentialCommand revokes scoped credentials.
+type RevokeCredentialCommand struct {
+	ScopedCredentialCommandBase
+	id string
+}
+
+// Info implements Command.Info.
+func (c *RevokeCredentialCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-credential",
+		Args:    "<credential id>",
+		Purpose: "revoke a credential created with create-credential",
+		Doc:     revokeCredentialDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RevokeCredentialCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no credential id supplied")
+	}
+	c.id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *RevokeCredentialCommand) Run(ctx *cmd.Context) error {
+	client, err := getScopedCredentialsAPI(&c.ScopedCredentialCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if err := client.RevokeScopedCredential(c.id); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Credential %q revoked", c.id)
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type ScopedCredentialSuite struct {
+	BaseSuite
+	mock *mockScopedCredentialsAPI
+}
+
+var _ = gc.Suite(&ScopedCredentialSuite{})
+
+func (s *ScopedCredentialSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	m, err := macaroon.New([]byte("root-key"), "cred-id", "juju scoped credential")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mock = &mockScopedCredentialsAPI{macaroon: m}
+	s.PatchValue(user.GetScopedCredentialsAPI, func(*user.ScopedCredentialCommandBase) (user.ScopedCredentialsAPI, error) {
+		return s.mock, nil
+	})
+}
+
+func (s *ScopedCredentialSuite) TestCreateInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no facades specified",
+	}, {
+		args:     []string{"--facades", "Client", "--entities", "foo"},
+		errMatch: `"foo" is not a valid tag`,
+	}, {
+		args:     []string{"--facades", "Client", "--expiry", "-1h"},
+		errMatch: "expiry must not be negative",
+	}, {
+		args:     []string{"--facades", "Client", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--facades", "Client,Service", "--entities", "unit-foo-0", "--expiry", "2h"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := testing.InitCommand(&user.CreateCredentialCommand{}, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, jc.ErrorIsNil)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *ScopedCredentialSuite) TestCreate(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&user.CreateCredentialCommand{}),
+		"--facades", "Client, Service", "--entities", "unit-foo-0", "--expiry", "2h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.facades, gc.DeepEquals, []string{"Client", "Service"})
+	c.Assert(s.mock.entities, gc.DeepEquals, []string{"unit-foo-0"})
+	c.Assert(s.mock.expiry, gc.Equals, 2*time.Hour)
+	c.Assert(s.mock.closed, jc.IsTrue)
+
+	output := testing.Stdout(context)
+	c.Assert(output, jc.Contains, "id: cred-id\n")
+	c.Assert(output, jc.Contains, "environ-uuid: env-uuid\n")
+	c.Assert(output, jc.Contains, "api-endpoints:\n- 127.0.0.1:12345\n")
+	c.Assert(output, jc.Contains, "macaroon: '{")
+}
+
+func (s *ScopedCredentialSuite) TestCreateFails(c *gc.C) {
+	s.mock.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&user.CreateCredentialCommand{}), "--facades", "Client")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ScopedCredentialSuite) TestRevokeInit(c *gc.C) {
+	err := testing.InitCommand(&user.RevokeCredentialCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no credential id supplied")
+	err = testing.InitCommand(&user.RevokeCredentialCommand{}, []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ScopedCredentialSuite) TestRevoke(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&user.RevokeCredentialCommand{}), "cred-id")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.revoked, gc.Equals, "cred-id")
+	c.Assert(s.mock.closed, jc.IsTrue)
+	c.Assert(strings.TrimSpace(testing.Stderr(context)), gc.Equals, `Credential "cred-id" revoked`)
+}
+
+type mockScopedCredentialsAPI struct {
+	macaroon *macaroon.Macaroon
+	err      error
+	facades  []string
+	entities []string
+	expiry   time.Duration
+	revoked  string
+	closed   bool
+}
+
+func (m *mockScopedCredentialsAPI) CreateScopedCredential(facades, entities []string, expiry time.Duration) (params.ScopedCredential, error) {
+	m.facades, m.entities, m.expiry = facades, entities, expiry
+	if m.err != nil {
+		return params.ScopedCredential{}, m.err
+	}
+	return params.ScopedCredential{
+		Id:       "cred-id",
+		Macaroon: m.macaroon,
+		Expires:  time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *mockScopedCredentialsAPI) RevokeScopedCredential(id string) error {
+	m.revoked = id
+	return m.err
+}
+
+func (m *mockScopedCredentialsAPI) Close() error {
+	m.closed = true
+	return nil
+}
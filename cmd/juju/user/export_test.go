@@ -18,6 +18,8 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// scoped credentials
+	GetScopedCredentialsAPI = &getScopedCredentialsAPI
 
 	UserFriendlyDuration = userFriendlyDuration
 )
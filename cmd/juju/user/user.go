@@ -40,6 +40,8 @@ func NewSuperCommand() cmd.Command {
 	usercmd.Register(envcmd.Wrap(&DisableCommand{}))
 	usercmd.Register(envcmd.Wrap(&EnableCommand{}))
 	usercmd.Register(envcmd.Wrap(&ListCommand{}))
+	usercmd.Register(envcmd.Wrap(&CreateCredentialCommand{}))
+	usercmd.Register(envcmd.Wrap(&RevokeCredentialCommand{}))
 	return usercmd
 }
 
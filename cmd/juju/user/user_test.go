@@ -24,11 +24,13 @@ var _ = gc.Suite(&UserCommandSuite{})
 var expectedUserCommmandNames = []string{
 	"add",
 	"change-password",
+	"create-credential",
 	"disable",
 	"enable",
 	"help",
 	"info",
 	"list",
+	"revoke-credential",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
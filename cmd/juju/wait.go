@@ -0,0 +1,312 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+const waitDoc = `
+Wait until all the units of the given services, or of the whole
+environment if no services are given, have settled: their agents are
+idle and their workloads are active (or, for charms that do not report
+their workload status, unknown).
+
+The command fails as soon as a unit or its machine is in an error
+state, or when the timeout expires, so it can be used to script
+deployments without polling juju status.
+
+Examples:
+
+  # Wait for everything in the environment to settle.
+  juju wait
+
+  # Wait at most 10 minutes for mysql and wordpress to settle.
+  juju wait --timeout 10m mysql wordpress
+`
+
+// WaitCommand waits for the units of services to settle.
+type WaitCommand struct {
+	envcmd.EnvCommandBase
+	services []string
+	timeout  time.Duration
+}
+
+func (c *WaitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "wait",
+		Args:    "[<service> ...]",
+		Purpose: "wait for the units of services to settle",
+		Doc:     waitDoc,
+	}
+}
+
+func (c *WaitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.timeout, "timeout", 0, "how long to wait before failing; 0 waits forever")
+}
+
+func (c *WaitCommand) Init(args []string) error {
+	for _, service := range args {
+		if !names.IsValidService(service) {
+			return errors.Errorf("invalid service name %q", service)
+		}
+	}
+	if c.timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	c.services = args
+	return nil
+}
+
+// AllWatcher defines the all watcher methods used by the commands that
+// follow changes to the environment.
+type AllWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// WaitAPI defines the API methods used by the wait command.
+type WaitAPI interface {
+	WatchAll() (AllWatcher, error)
+	Close() error
+}
+
+// allWatcherClient adapts the API client to the interfaces of the
+// commands that use an AllWatcher.
+type allWatcherClient struct {
+	*api.Client
+}
+
+// WatchAll implements WaitAPI.
+func (c allWatcherClient) WatchAll() (AllWatcher, error) {
+	return c.Client.WatchAll()
+}
+
+var getWaitAPI = func(c *WaitCommand) (WaitAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return allWatcherClient{client}, nil
+}
+
+// waitTimeout returns a channel that receives a value when the timeout
+// expires; it is a variable so tests can expire it on demand.
+var waitTimeout = func(d time.Duration) <-chan time.Time {
+	if d == 0 {
+		return nil
+	}
+	return time.After(d)
+}
+
+// Run waits until the units have settled, one of them fails, or the
+// timeout expires.
+func (c *WaitCommand) Run(ctx *cmd.Context) error {
+	client, err := getWaitAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	type nextResult struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	results := make(chan nextResult)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			select {
+			case results <- nextResult{deltas, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	env := newWaitEnvironment()
+	timeout := waitTimeout(c.timeout)
+	first := true
+	lastReport := ""
+	for {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				return errors.Annotate(result.err, "cannot watch environment")
+			}
+			env.apply(result.deltas)
+		case <-timeout:
+			return errors.Errorf("timed out after %v waiting for %s", c.timeout, env.pending(c.services))
+		}
+		if first {
+			// The first batch of deltas holds the whole environment,
+			// so any service not in it does not exist.
+			for _, service := range c.services {
+				if _, ok := env.services[service]; !ok {
+					return errors.NotFoundf("service %q", service)
+				}
+			}
+			first = false
+		}
+		settled, err := env.settled(c.services)
+		if err != nil {
+			return err
+		}
+		if settled {
+			ctx.Infof("All units have settled.")
+			return nil
+		}
+		if report := env.pending(c.services); report != lastReport {
+			ctx.Verbosef("Waiting for %s.", report)
+			lastReport = report
+		}
+	}
+}
+
+// waitEnvironment holds the entities of the environment relevant to
+// the wait command, as reported by the all watcher.
+type waitEnvironment struct {
+	services map[string]bool
+	units    map[string]*multiwatcher.UnitInfo
+	machines map[string]*multiwatcher.MachineInfo
+}
+
+func newWaitEnvironment() *waitEnvironment {
+	return &waitEnvironment{
+		services: make(map[string]bool),
+		units:    make(map[string]*multiwatcher.UnitInfo),
+		machines: make(map[string]*multiwatcher.MachineInfo),
+	}
+}
+
+// apply updates the environment with the given deltas.
+func (env *waitEnvironment) apply(deltas []multiwatcher.Delta) {
+	for _, delta := range deltas {
+		switch entity := delta.Entity.(type) {
+		case *multiwatcher.ServiceInfo:
+			if delta.Removed {
+				delete(env.services, entity.Name)
+			} else {
+				env.services[entity.Name] = true
+			}
+		case *multiwatcher.UnitInfo:
+			if delta.Removed {
+				delete(env.units, entity.Name)
+			} else {
+				env.units[entity.Name] = entity
+			}
+		case *multiwatcher.MachineInfo:
+			if delta.Removed {
+				delete(env.machines, entity.Id)
+			} else {
+				env.machines[entity.Id] = entity
+			}
+		}
+	}
+}
+
+// selectedUnits returns the units of the given services, or all units
+// if no services are given, sorted by name.
+func (env *waitEnvironment) selectedUnits(services []string) []*multiwatcher.UnitInfo {
+	selected := set.NewStrings(services...)
+	var units []*multiwatcher.UnitInfo
+	for _, unit := range env.units {
+		if selected.IsEmpty() || selected.Contains(unit.Service) {
+			units = append(units, unit)
+		}
+	}
+	sort.Sort(unitInfosByName(units))
+	return units
+}
+
+// settled reports whether all the selected units have settled. It
+// returns an error if any of them, or any of their machines, is in an
+// error state.
+func (env *waitEnvironment) settled(services []string) (bool, error) {
+	settled := true
+	for _, unit := range env.selectedUnits(services) {
+		if err := env.unitError(unit); err != nil {
+			return false, err
+		}
+		if !unitSettled(unit) {
+			settled = false
+		}
+	}
+	return settled, nil
+}
+
+// unitError returns an error if the given unit or its machine is in an
+// error state.
+func (env *waitEnvironment) unitError(unit *multiwatcher.UnitInfo) error {
+	if unit.Status == multiwatcher.Status(params.StatusError) {
+		return errors.Errorf("unit %q is in error: %s", unit.Name, unit.StatusInfo)
+	}
+	if unit.WorkloadStatus == multiwatcher.Status(params.StatusError) {
+		return errors.Errorf("unit %q workload is in error: %s", unit.Name, unit.WorkloadStatusInfo)
+	}
+	if machine, ok := env.machines[unit.MachineId]; ok && machine.Status == multiwatcher.Status(params.StatusError) {
+		return errors.Errorf("machine %q of unit %q is in error: %s", machine.Id, unit.Name, machine.StatusInfo)
+	}
+	return nil
+}
+
+// unitSettled reports whether the agent of the given unit is idle and
+// its workload active. The workload status of charms that do not set
+// it remains unknown, which is also accepted.
+func unitSettled(unit *multiwatcher.UnitInfo) bool {
+	if unit.Status != multiwatcher.Status(params.StatusIdle) {
+		return false
+	}
+	switch unit.WorkloadStatus {
+	case multiwatcher.Status(params.StatusActive), multiwatcher.Status(params.StatusUnknown):
+		return true
+	}
+	return false
+}
+
+// pending returns a description of the selected units that have not
+// yet settled.
+func (env *waitEnvironment) pending(services []string) string {
+	var pending []string
+	for _, unit := range env.selectedUnits(services) {
+		if !unitSettled(unit) {
+			pending = append(pending, fmt.Sprintf("%s (%s/%s)", unit.Name, unit.Status, unit.WorkloadStatus))
+		}
+	}
+	if len(pending) == 0 {
+		return "units to settle"
+	}
+	return strings.Join(pending, ", ")
+}
+
+type unitInfosByName []*multiwatcher.UnitInfo
+
+func (u unitInfosByName) Len() int           { return len(u) }
+func (u unitInfosByName) Less(i, j int) bool { return u[i].Name < u[j].Name }
+func (u unitInfosByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/testing"
+)
+
+type WaitSuite struct {
+	testing.FakeJujuHomeSuite
+	fake    *fakeWaitAPI
+	timeout chan time.Time
+}
+
+var _ = gc.Suite(&WaitSuite{})
+
+func (s *WaitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeWaitAPI{
+		deltas: make(chan []multiwatcher.Delta, 10),
+	}
+	s.PatchValue(&getWaitAPI, func(_ *WaitCommand) (WaitAPI, error) {
+		return s.fake, nil
+	})
+	s.timeout = make(chan time.Time, 1)
+	s.PatchValue(&waitTimeout, func(time.Duration) <-chan time.Time {
+		return s.timeout
+	})
+}
+
+func (s *WaitSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		services []string
+		timeout  time.Duration
+		errMatch string
+	}{{
+		args: nil,
+	}, {
+		args:     []string{"--timeout", "5m", "mysql", "wordpress"},
+		services: []string{"mysql", "wordpress"},
+		timeout:  5 * time.Minute,
+	}, {
+		args:     []string{"mysql/0"},
+		errMatch: `invalid service name "mysql/0"`,
+	}, {
+		args:     []string{"--timeout", "-1s"},
+		errMatch: "--timeout must not be negative",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &WaitCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.services, jc.DeepEquals, test.services)
+			c.Check(command.timeout, gc.Equals, test.timeout)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func unitDelta(name, service, agentStatus, workloadStatus string) multiwatcher.Delta {
+	return multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{
+		Name:           name,
+		Service:        service,
+		MachineId:      "0",
+		Status:         multiwatcher.Status(agentStatus),
+		WorkloadStatus: multiwatcher.Status(workloadStatus),
+	}}
+}
+
+var waitInitialDeltas = []multiwatcher.Delta{
+	{Entity: &multiwatcher.MachineInfo{Id: "0", Status: "started"}},
+	{Entity: &multiwatcher.ServiceInfo{Name: "mysql"}},
+	{Entity: &multiwatcher.ServiceInfo{Name: "wordpress"}},
+	unitDelta("mysql/0", "mysql", "executing", "maintenance"),
+	unitDelta("wordpress/0", "wordpress", "idle", "active"),
+}
+
+func (s *WaitSuite) TestWaitEnvironment(c *gc.C) {
+	s.fake.deltas <- waitInitialDeltas
+	s.fake.deltas <- []multiwatcher.Delta{unitDelta("mysql/0", "mysql", "idle", "maintenance")}
+	s.fake.deltas <- []multiwatcher.Delta{unitDelta("mysql/0", "mysql", "idle", "active")}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "All units have settled.\n")
+	c.Assert(s.fake.stopped, jc.IsTrue)
+	c.Assert(s.fake.closed, jc.IsTrue)
+}
+
+func (s *WaitSuite) TestWaitService(c *gc.C) {
+	// Only the units of wordpress are waited for.
+	s.fake.deltas <- waitInitialDeltas
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "All units have settled.\n")
+}
+
+func (s *WaitSuite) TestWaitUnknownWorkloadStatus(c *gc.C) {
+	s.fake.deltas <- []multiwatcher.Delta{
+		{Entity: &multiwatcher.ServiceInfo{Name: "mysql"}},
+		unitDelta("mysql/0", "mysql", "idle", "unknown"),
+	}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestWaitServiceNotFound(c *gc.C) {
+	s.fake.deltas <- waitInitialDeltas
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "mediawiki")
+	c.Assert(err, gc.ErrorMatches, `service "mediawiki" not found`)
+}
+
+func (s *WaitSuite) TestWaitUnitError(c *gc.C) {
+	s.fake.deltas <- waitInitialDeltas
+	s.fake.deltas <- []multiwatcher.Delta{{Entity: &multiwatcher.UnitInfo{
+		Name:       "mysql/0",
+		Service:    "mysql",
+		Status:     "error",
+		StatusInfo: `hook failed: "install"`,
+	}}}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}))
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" is in error: hook failed: "install"`)
+}
+
+func (s *WaitSuite) TestWaitMachineError(c *gc.C) {
+	s.fake.deltas <- waitInitialDeltas
+	s.fake.deltas <- []multiwatcher.Delta{{Entity: &multiwatcher.MachineInfo{
+		Id:         "0",
+		Status:     "error",
+		StatusInfo: "no matching instance type",
+	}}}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "mysql")
+	c.Assert(err, gc.ErrorMatches, `machine "0" of unit "mysql/0" is in error: no matching instance type`)
+}
+
+func (s *WaitSuite) TestWaitTimeout(c *gc.C) {
+	s.fake.deltas <- waitInitialDeltas
+	s.fake.onNext = func() {
+		if s.fake.nextCalls == 2 {
+			s.timeout <- time.Now()
+		}
+	}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "--timeout", "1m")
+	c.Assert(err, gc.ErrorMatches, `timed out after 1m0s waiting for mysql/0 \(executing/maintenance\)`)
+}
+
+func (s *WaitSuite) TestWaitWatcherError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot watch environment: boom")
+}
+
+type fakeWaitAPI struct {
+	deltas    chan []multiwatcher.Delta
+	onNext    func()
+	err       error
+	nextCalls int
+	stopped   bool
+	closed    bool
+}
+
+func (f *fakeWaitAPI) WatchAll() (AllWatcher, error) {
+	return f, nil
+}
+
+func (f *fakeWaitAPI) Next() ([]multiwatcher.Delta, error) {
+	f.nextCalls++
+	if f.onNext != nil {
+		f.onNext()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return <-f.deltas, nil
+}
+
+func (f *fakeWaitAPI) Stop() error {
+	f.stopped = true
+	return nil
+}
+
+func (f *fakeWaitAPI) Close() error {
+	f.closed = true
+	return nil
+}
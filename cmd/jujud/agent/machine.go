@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -55,13 +56,19 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	statestorage "github.com/juju/juju/state/storage"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
+	"github.com/juju/juju/worker/addresser"
+	"github.com/juju/juju/worker/agenthealth"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/certrotator"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
@@ -69,11 +76,15 @@ import (
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskformatter"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/diskspace"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
+	"github.com/juju/juju/worker/hareplacer"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
+	"github.com/juju/juju/worker/logforwarder"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
@@ -82,11 +93,14 @@ import (
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
+	"github.com/juju/juju/worker/relationdrainer"
 	"github.com/juju/juju/worker/resumer"
-	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
+	"github.com/juju/juju/worker/toolspruner"
+	"github.com/juju/juju/worker/txnpruner"
 	"github.com/juju/juju/worker/upgrader"
 )
 
@@ -243,19 +257,27 @@ func (a *machineAgentCmd) Info() *cmd.Info {
 }
 
 // MachineAgentFactoryFn returns a function which instantiates a
-// MachineAgent given a machineId.
+// MachineAgent given a machineId. If newUnitWorker is not nil, the
+// agents of the units deployed to the machine are run by the machine
+// agent as the workers it returns; otherwise they are run as separate
+// processes.
 func MachineAgentFactoryFn(
 	agentConfWriter AgentConfigWriter,
 	apiAddressSetter apiaddressupdater.APIAddressSetter,
+	bufferedLogs *logsender.BufferedLogWriter,
+	newUnitWorker deployer.UnitWorkerFunc,
 ) func(string) *MachineAgent {
 	return func(machineId string) *MachineAgent {
-		return NewMachineAgent(
+		a := NewMachineAgent(
 			machineId,
 			agentConfWriter,
 			apiAddressSetter,
+			bufferedLogs,
 			NewUpgradeWorkerContext(),
 			worker.NewRunner(cmdutil.IsFatal, cmdutil.MoreImportant),
 		)
+		a.newUnitWorker = newUnitWorker
+		return a
 	}
 }
 
@@ -264,6 +286,7 @@ func NewMachineAgent(
 	machineId string,
 	agentConfWriter AgentConfigWriter,
 	apiAddressSetter apiaddressupdater.APIAddressSetter,
+	bufferedLogs *logsender.BufferedLogWriter,
 	upgradeWorkerContext *upgradeWorkerContext,
 	runner worker.Runner,
 ) *MachineAgent {
@@ -272,6 +295,7 @@ func NewMachineAgent(
 		machineId:            machineId,
 		AgentConfigWriter:    agentConfWriter,
 		apiAddressSetter:     apiAddressSetter,
+		bufferedLogs:         bufferedLogs,
 		workersStarted:       make(chan struct{}),
 		upgradeWorkerContext: upgradeWorkerContext,
 		runner:               runner,
@@ -287,6 +311,7 @@ type MachineAgent struct {
 	machineId            string
 	previousAgentVersion version.Number
 	apiAddressSetter     apiaddressupdater.APIAddressSetter
+	bufferedLogs         *logsender.BufferedLogWriter
 	runner               worker.Runner
 	configChangedVal     voyeur.Value
 	upgradeWorkerContext *upgradeWorkerContext
@@ -294,6 +319,10 @@ type MachineAgent struct {
 	restoring            bool
 	workersStarted       chan struct{}
 
+	// newUnitWorker, if not nil, runs the agents of the units
+	// deployed to the machine inside the machine agent process.
+	newUnitWorker deployer.UnitWorkerFunc
+
 	mongoInitMutex   sync.Mutex
 	mongoInitialized bool
 }
@@ -661,11 +690,6 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 	}
 
-	rsyslogMode := rsyslog.RsyslogModeForwarding
-	if isEnvironManager {
-		rsyslogMode = rsyslog.RsyslogModeAccumulate
-	}
-
 	runner := newConnRunner(st)
 	// TODO(fwereade): this is *still* a hideous layering violation, but at least
 	// it's confined to jujud rather than extending into the worker itself.
@@ -696,9 +720,30 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 	runner.StartWorker("logger", func() (worker.Worker, error) {
 		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
 	})
+	if st.BestFacadeVersion("AgentHealth") > 0 {
+		runner.StartWorker("agenthealth", func() (worker.Worker, error) {
+			return agenthealth.NewWorker(agenthealth.Config{
+				Setter:      st.AgentHealth(),
+				DataDir:     agentConfig.DataDir(),
+				LastAPICall: st.LastSuccessfulCall,
+			})
+		})
+	}
 
-	runner.StartWorker("rsyslog", func() (worker.Worker, error) {
-		return cmdutil.NewRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslogMode)
+	runner.StartWorker("logsender", func() (worker.Worker, error) {
+		return cmdutil.NewLogSender(a.bufferedLogs, agentConfig), nil
+	})
+	runner.StartWorker("diskspace", func() (worker.Worker, error) {
+		machine, err := st.Machiner().Machine(agentConfig.Tag().(names.MachineTag))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		config, err := diskSpaceConfig(agentConfig, isEnvironManager)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		config.StatusSetter = machine
+		return diskspace.NewWorker(config)
 	})
 	if featureflag.Enabled(feature.Storage) {
 		runner.StartWorker("diskmanager", func() (worker.Worker, error) {
@@ -773,7 +818,7 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		case multiwatcher.JobHostUnits:
 			runner.StartWorker("deployer", func() (worker.Worker, error) {
 				apiDeployer := st.Deployer()
-				context := newDeployContext(apiDeployer, agentConfig)
+				context := newDeployContext(apiDeployer, agentConfig, a.newUnitWorker)
 				return deployer.NewDeployer(apiDeployer, context), nil
 			})
 		case multiwatcher.JobManageEnviron:
@@ -795,6 +840,41 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 	return cmdutil.NewCloseWorker(logger, runner, st), nil // Note: a worker.Runner is itself a worker.Worker.
 }
 
+// diskSpaceConfig returns the configuration of the diskspace worker,
+// which monitors the data and log directories, and mongo's data
+// directory on state servers.
+func diskSpaceConfig(agentConfig agent.Config, isEnvironManager bool) (diskspace.Config, error) {
+	dataDir := agentConfig.DataDir()
+	logDir := agentConfig.LogDir()
+	config := diskspace.Config{
+		Paths:     []string{dataDir, logDir},
+		Threshold: diskspace.DefaultThreshold,
+	}
+	if isEnvironManager {
+		config.Paths = append(config.Paths, filepath.Join(dataDir, "db"))
+	}
+	if value := agentConfig.Value(agent.DiskSpaceThreshold); value != "" {
+		threshold, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || threshold == 0 {
+			return diskspace.Config{}, errors.Errorf("invalid %s value %q", agent.DiskSpaceThreshold, value)
+		}
+		config.Threshold = threshold
+	}
+	if value := agentConfig.Value(agent.DiskSpaceCleanups); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			switch strings.TrimSpace(name) {
+			case "tools":
+				config.Cleanups = append(config.Cleanups, diskspace.OldToolsCleanup(dataDir))
+			case "logs":
+				config.Cleanups = append(config.Cleanups, diskspace.RotatedLogsCleanup(logDir))
+			default:
+				return diskspace.Config{}, errors.Errorf("invalid %s value %q", agent.DiskSpaceCleanups, value)
+			}
+		}
+	}
+	return config, nil
+}
+
 func (a *MachineAgent) upgradeStepsWorkerStarter(
 	st *api.State,
 	jobs []multiwatcher.MachineJob,
@@ -970,12 +1050,48 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(runner, "certupdater", func() (worker.Worker, error) {
 				return newCertificateUpdater(m, agentConfig, st, stateServingSetter, certChangedChan), nil
 			})
+			a.startWorkerAfterUpgrade(runner, "certrotator", func() (worker.Worker, error) {
+				changeConfig := func(mutate func(agent.ConfigSetter) error) error {
+					return a.ChangeConfig(mutate)
+				}
+				return certrotator.NewCertificateRotator(m.Id(), st, a.CurrentConfig, changeConfig, stateServingSetter), nil
+			})
 
-			if featureflag.Enabled(feature.DbLog) {
-				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
-					return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
-				})
-			}
+			a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
+				return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "logforwarder", func() (worker.Worker, error) {
+				return logforwarder.New(st), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				paths := backups.Paths{
+					DataDir: agentConfig.DataDir(),
+					LogsDir: agentConfig.LogDir(),
+				}
+				return backupscheduler.New(st, backupscheduler.NewStateBackups(st, paths, m.Id())), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "actionscheduler", func() (worker.Worker, error) {
+				return actionscheduler.New(st), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "relationdrainer", func() (worker.Worker, error) {
+				return relationdrainer.New(st), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "hareplacer", func() (worker.Worker, error) {
+				return hareplacer.New(st, hareplacer.NewReplaceParams()), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
+				envConfig, err := st.EnvironConfig()
+				if err != nil {
+					return nil, errors.Annotate(err, "cannot read environment config")
+				}
+				return txnpruner.New(st, txnpruner.NewTxnPruneParamsFromConfig(envConfig)), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "statushistorypruner", func() (worker.Worker, error) {
+				return statushistorypruner.New(st, statushistorypruner.NewHistoryPruneParams()), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "toolspruner", func() (worker.Worker, error) {
+				return toolspruner.New(st, toolspruner.NewToolsPruneParams()), nil
+			})
 			a.startWorkerAfterUpgrade(singularRunner, "resumer", func() (worker.Worker, error) {
 				// The action of resumer is so subtle that it is not tested,
 				// because we can't figure out how to do so without brutalising
@@ -1056,6 +1172,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("addresser", func() (worker.Worker, error) {
+		return addresser.NewWorker(st)
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1128,22 +1247,117 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	dataDir := agentConfig.DataDir()
 	logDir := agentConfig.LogDir()
 
+	rateLimit, err := apiserverRateLimitConfig(agentConfig)
+	if err != nil {
+		return nil, &cmdutil.FatalError{err.Error()}
+	}
+	readPrefs, err := apiserverReadPreferences(agentConfig)
+	if err != nil {
+		return nil, &cmdutil.FatalError{err.Error()}
+	}
+
 	endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
 	listener, err := net.Listen("tcp", endpoint)
 	if err != nil {
 		return nil, err
 	}
+	registerStateServerToolsDataSource(info.APIPort, st.EnvironUUID())
 	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
-		Cert:        cert,
-		Key:         key,
-		Tag:         tag,
-		DataDir:     dataDir,
-		LogDir:      logDir,
-		Validator:   a.limitLogins,
-		CertChanged: certChanged,
+		Cert:            cert,
+		Key:             key,
+		Tag:             tag,
+		DataDir:         dataDir,
+		LogDir:          logDir,
+		Validator:       a.limitLogins,
+		CertChanged:     certChanged,
+		RateLimit:       rateLimit,
+		AuditLogPath:    agentConfig.Value(agent.AuditLogFile),
+		ReadPreferences: readPrefs,
 	})
 }
 
+// apiserverReadPreferences returns the mongo read preferences of the API
+// server's categories of read-only calls recorded in the agent
+// configuration.
+func apiserverReadPreferences(agentConfig agent.Config) (map[string]mongo.ReadPreference, error) {
+	prefs := make(map[string]mongo.ReadPreference)
+	value := agentConfig.Value(agent.MongoReadPreferences)
+	if value == "" {
+		return prefs, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid %s: %q", agent.MongoReadPreferences, value)
+		}
+		pref, err := mongo.ParseReadPreference(parts[1])
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid %s", agent.MongoReadPreferences)
+		}
+		prefs[parts[0]] = pref
+	}
+	return prefs, nil
+}
+
+// apiserverRateLimitConfig returns the API server rate limits recorded in
+// the agent configuration. Limits that are not recorded are left unset,
+// so that the API server uses its defaults.
+func apiserverRateLimitConfig(agentConfig agent.Config) (apiserver.RateLimitConfig, error) {
+	var cfg apiserver.RateLimitConfig
+	for key, value := range map[string]*int{
+		agent.APIMaxConcurrentLogins:    &cfg.MaxConcurrentLogins,
+		agent.APILoginAttemptsPerMinute: &cfg.LoginAttemptsPerMinute,
+	} {
+		if s := agentConfig.Value(key); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return apiserver.RateLimitConfig{}, errors.Errorf("invalid %s: %q", key, s)
+			}
+			*value = n
+		}
+	}
+	for key, value := range map[string]*float64{
+		agent.APIConnCallsPerSecond:   &cfg.ConnCallsPerSecond,
+		agent.APIEntityCallsPerSecond: &cfg.EntityCallsPerSecond,
+	} {
+		if s := agentConfig.Value(key); s != "" {
+			rate, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return apiserver.RateLimitConfig{}, errors.Errorf("invalid %s: %q", key, s)
+			}
+			*value = rate
+		}
+	}
+	return cfg, nil
+}
+
+// stateWatcherCoalescing returns the coalescing of the state watchers
+// recorded in the agent configuration, or the default if none is.
+func stateWatcherCoalescing(agentConfig agent.Config) (state.WatcherCoalescing, error) {
+	coalescing := state.DefaultWatcherCoalescing
+	window := agentConfig.Value(agent.WatcherCoalesceWindow)
+	maxDelay := agentConfig.Value(agent.WatcherCoalesceMaxDelay)
+	if window == "" && maxDelay == "" {
+		return coalescing, nil
+	}
+	if window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			return state.WatcherCoalescing{}, errors.Errorf("invalid %s: %q", agent.WatcherCoalesceWindow, window)
+		}
+		coalescing.Window = d
+		coalescing.MaxDelay = 10 * d
+	}
+	if maxDelay != "" {
+		d, err := time.ParseDuration(maxDelay)
+		if err != nil {
+			return state.WatcherCoalescing{}, errors.Errorf("invalid %s: %q", agent.WatcherCoalesceMaxDelay, maxDelay)
+		}
+		coalescing.MaxDelay = d
+	}
+	return coalescing, nil
+}
+
 // limitLogins is called by the API server for each login attempt.
 // it returns an error if upgrads or restore are running.
 func (a *MachineAgent) limitLogins(req params.LoginRequest) error {
@@ -1164,6 +1378,11 @@ func (a *MachineAgent) limitLoginsDuringRestore(req params.LoginRequest) error {
 		err = apiserver.AboutToRestoreError
 	}
 	if err != nil {
+		if req.AuthTag == "" {
+			// Users authenticated by macaroons log in without a
+			// tag; use a restricted API mode.
+			return err
+		}
 		authTag, parseErr := names.ParseTag(req.AuthTag)
 		if parseErr != nil {
 			return errors.Annotate(err, "could not parse auth tag")
@@ -1188,6 +1407,11 @@ func (a *MachineAgent) limitLoginsDuringRestore(req params.LoginRequest) error {
 // login is for a user (i.e. a client) or the local machine.
 func (a *MachineAgent) limitLoginsDuringUpgrade(req params.LoginRequest) error {
 	if a.upgradeWorkerContext.IsUpgradeRunning() {
+		if req.AuthTag == "" {
+			// Users authenticated by macaroons log in without a
+			// tag; use a restricted API mode.
+			return apiserver.UpgradeInProgressError
+		}
 		authTag, err := names.ParseTag(req.AuthTag)
 		if err != nil {
 			return errors.Annotate(err, "could not parse auth tag")
@@ -1353,6 +1577,13 @@ func openState(agentConfig agent.Config, dialOpts mongo.DialOpts) (_ *state.Stat
 			st.Close()
 		}
 	}()
+	coalescing, err := stateWatcherCoalescing(agentConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := st.SetWatcherCoalescing(coalescing); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot set watcher coalescing")
+	}
 	m0, err := st.FindEntity(agentConfig.Tag())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -1517,6 +1748,9 @@ func metricAPI(st *api.State) metricsmanager.MetricsManagerClient {
 // running the tests and (2) get access to the *State used internally, so that
 // tests can be run without waiting for the 5s watcher refresh time to which we would
 // otherwise be restricted.
-var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) deployer.Context {
+var newDeployContext = func(st *apideployer.State, agentConfig agent.Config, newUnitWorker deployer.UnitWorkerFunc) deployer.Context {
+	if newUnitWorker != nil {
+		return deployer.NewNestedContext(agentConfig, st, newUnitWorker)
+	}
 	return deployer.NewSimpleContext(agentConfig, st)
 }
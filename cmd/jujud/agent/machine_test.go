@@ -34,7 +34,7 @@ import (
 	apifirewaller "github.com/juju/juju/api/firewaller"
 	apimetricsmanager "github.com/juju/juju/api/metricsmanager"
 	apinetworker "github.com/juju/juju/api/networker"
-	apirsyslog "github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/apiserver"
 	charmtesting "github.com/juju/juju/apiserver/charmrevisionupdater/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -43,6 +43,7 @@ import (
 	lxctesting "github.com/juju/juju/container/lxc/testing"
 	"github.com/juju/juju/environs/config"
 	envtesting "github.com/juju/juju/environs/testing"
+	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
@@ -66,11 +67,12 @@ import (
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/diskspace"
 	"github.com/juju/juju/worker/instancepoller"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/networker"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/proxyupdater"
-	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/upgrader"
@@ -213,7 +215,7 @@ func (s *commonMachineSuite) configureMachine(c *gc.C, machineId string, vers ve
 func (s *commonMachineSuite) newAgent(c *gc.C, m *state.Machine) *MachineAgent {
 	agentConf := AgentConf{DataDir: s.DataDir()}
 	agentConf.ReadConfig(names.NewMachineTag(m.Id()).String())
-	machineAgentFactory := MachineAgentFactoryFn(&agentConf, &agentConf)
+	machineAgentFactory := MachineAgentFactoryFn(&agentConf, &agentConf, logsender.NewBufferedLogWriter(1024), nil)
 	return machineAgentFactory(m.Id())
 }
 
@@ -221,7 +223,7 @@ func (s *MachineSuite) TestParseSuccess(c *gc.C) {
 	create := func() (cmd.Command, *AgentConf) {
 		agentConf := AgentConf{DataDir: s.DataDir()}
 		a := NewMachineAgentCmd(
-			MachineAgentFactoryFn(&agentConf, &agentConf),
+			MachineAgentFactoryFn(&agentConf, &agentConf, nil, nil),
 			&agentConf,
 			&agentConf,
 		)
@@ -241,6 +243,7 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"addresser",
 	"environ-provisioner",
 	"charm-revision-updater",
 	"firewaller",
@@ -409,7 +412,7 @@ func patchDeployContext(c *gc.C, st *state.State) (*fakeContext, func()) {
 		deployed: make(set.Strings),
 	}
 	orig := newDeployContext
-	newDeployContext = func(dst *apideployer.State, agentConfig agent.Config) deployer.Context {
+	newDeployContext = func(dst *apideployer.State, agentConfig agent.Config, _ deployer.UnitWorkerFunc) deployer.Context {
 		ctx.st = st
 		ctx.agentConfig = agentConfig
 		close(ctx.inited)
@@ -595,9 +598,7 @@ func (s *MachineSuite) TestManageEnvironRunsPeergrouper(c *gc.C) {
 	}
 }
 
-func (s *MachineSuite) TestManageEnvironRunsDbLogPrunerIfFeatureFlagEnabled(c *gc.C) {
-	s.SetFeatureFlags(feature.DbLog)
-
+func (s *MachineSuite) TestManageEnvironRunsDbLogPruner(c *gc.C) {
 	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
 	a := s.newAgent(c, m)
 	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
@@ -605,19 +606,77 @@ func (s *MachineSuite) TestManageEnvironRunsDbLogPrunerIfFeatureFlagEnabled(c *g
 
 	runner := s.singularRecord.nextRunner(c)
 	runner.waitForWorker(c, "dblogpruner")
+	runner.waitForWorker(c, "logforwarder")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsTxnPruner(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "txnpruner")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsBackupScheduler(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "backupscheduler")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsActionScheduler(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "actionscheduler")
 }
 
-func (s *MachineSuite) TestManageEnvironDoesntRunDbLogPrunerByDefault(c *gc.C) {
+func (s *MachineSuite) TestManageEnvironRunsRelationDrainer(c *gc.C) {
 	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
 	a := s.newAgent(c, m)
 	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
 	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
 
-	// Wait for the resumer to be started. This is started just after
-	// dblogpruner would be started.
 	runner := s.singularRecord.nextRunner(c)
-	started := set.NewStrings(runner.waitForWorker(c, "resumer")...)
-	c.Assert(started.Contains("dblogpruner"), jc.IsFalse)
+	runner.waitForWorker(c, "relationdrainer")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsHAReplacer(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "hareplacer")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsStatusHistoryPruner(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "statushistorypruner")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsToolsPruner(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "toolspruner")
 }
 
 func (s *MachineSuite) TestManageEnvironCallsUseMultipleCPUs(c *gc.C) {
@@ -1093,26 +1152,18 @@ func (s *MachineSuite) TestMachineAgentUninstall(c *gc.C) {
 	c.Assert(err, jc.Satisfies, os.IsNotExist)
 }
 
-func (s *MachineSuite) TestMachineAgentRsyslogManageEnviron(c *gc.C) {
-	s.testMachineAgentRsyslogConfigWorker(c, state.JobManageEnviron, rsyslog.RsyslogModeAccumulate)
-}
-
-func (s *MachineSuite) TestMachineAgentRsyslogHostUnits(c *gc.C) {
-	s.testMachineAgentRsyslogConfigWorker(c, state.JobHostUnits, rsyslog.RsyslogModeForwarding)
-}
-
-func (s *MachineSuite) testMachineAgentRsyslogConfigWorker(c *gc.C, job state.MachineJob, expectedMode rsyslog.RsyslogMode) {
-	created := make(chan rsyslog.RsyslogMode, 1)
-	s.AgentSuite.PatchValue(&cmdutil.NewRsyslogConfigWorker, func(_ *apirsyslog.State, _ agent.Config, mode rsyslog.RsyslogMode) (worker.Worker, error) {
-		created <- mode
-		return newDummyWorker(), nil
+func (s *MachineSuite) TestMachineAgentRunsLogSender(c *gc.C) {
+	created := make(chan agent.Config, 1)
+	s.AgentSuite.PatchValue(&cmdutil.NewLogSender, func(_ *logsender.BufferedLogWriter, agentConfig agent.Config) worker.Worker {
+		created <- agentConfig
+		return newDummyWorker()
 	})
-	s.assertJobWithAPI(c, job, func(conf agent.Config, st *api.State) {
+	s.assertJobWithAPI(c, state.JobHostUnits, func(conf agent.Config, st *api.State) {
 		select {
 		case <-time.After(coretesting.LongWait):
-			c.Fatalf("timeout while waiting for rsyslog worker to be created")
-		case mode := <-created:
-			c.Assert(mode, gc.Equals, expectedMode)
+			c.Fatalf("timeout while waiting for logsender worker to be created")
+		case agentConfig := <-created:
+			c.Assert(agentConfig.Tag(), gc.Equals, conf.Tag())
 		}
 	})
 }
@@ -1588,6 +1639,39 @@ func (s *MachineSuite) TestMachineAgentRestoreRequiresPrepare(c *gc.C) {
 	c.Assert(a.IsRestoreRunning(), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestMachineAgentRestoreLimitsMacaroonLogins(c *gc.C) {
+	// Start the machine agent.
+	m, _, _ := s.primeAgent(c, version.Current, state.JobHostUnits)
+	a := s.newAgent(c, m)
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	err := a.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Users logging in with macaroons give no tag, and get the
+	// restricted API rather than an error.
+	err = a.limitLogins(params.LoginRequest{})
+	c.Assert(err, gc.Equals, apiserver.AboutToRestoreError)
+
+	err = a.BeginRestore()
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.limitLogins(params.LoginRequest{})
+	c.Assert(err, gc.Equals, apiserver.RestoreInProgressError)
+}
+
+func (s *MachineSuite) TestStateServerToolsDataSourceSearchedFirst(c *gc.C) {
+	registerStateServerToolsDataSource(17070, s.State.EnvironUUID())
+	defer envtools.UnregisterPreferredToolsDataSourceFunc("state server")
+
+	sources, err := envtools.GetMetadataSources(s.Environ)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sources, gc.Not(gc.HasLen), 0)
+	c.Assert(sources[0].Description(), gc.Equals, "state server")
+	url, err := sources[0].URL("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(url, gc.Equals, "https://localhost:17070/environment/"+s.State.EnvironUUID()+"/tools/")
+}
+
 func (s *MachineSuite) TestNewEnvironmentStartsNewWorkers(c *gc.C) {
 	s.PatchValue(&watcher.Period, 100*time.Millisecond)
 
@@ -1773,6 +1857,65 @@ func (s *shouldWriteProxyFilesSuite) TestAll(c *gc.C) {
 	}
 }
 
+type diskSpaceConfigSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&diskSpaceConfigSuite{})
+
+type diskSpaceAgentConfig struct {
+	agent.Config
+	values map[string]string
+}
+
+func (*diskSpaceAgentConfig) DataDir() string {
+	return "/var/lib/juju"
+}
+
+func (*diskSpaceAgentConfig) LogDir() string {
+	return "/var/log/juju"
+}
+
+func (m *diskSpaceAgentConfig) Value(key string) string {
+	return m.values[key]
+}
+
+func (s *diskSpaceConfigSuite) TestDefaults(c *gc.C) {
+	config, err := diskSpaceConfig(&diskSpaceAgentConfig{}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Paths, jc.DeepEquals, []string{"/var/lib/juju", "/var/log/juju"})
+	c.Assert(config.Threshold, gc.Equals, uint64(diskspace.DefaultThreshold))
+	c.Assert(config.Cleanups, gc.HasLen, 0)
+
+	config, err = diskSpaceConfig(&diskSpaceAgentConfig{}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Paths, jc.DeepEquals, []string{"/var/lib/juju", "/var/log/juju", "/var/lib/juju/db"})
+}
+
+func (s *diskSpaceConfigSuite) TestConfigured(c *gc.C) {
+	config, err := diskSpaceConfig(&diskSpaceAgentConfig{values: map[string]string{
+		agent.DiskSpaceThreshold: "2048",
+		agent.DiskSpaceCleanups:  "logs, tools",
+	}}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Threshold, gc.Equals, uint64(2048))
+	c.Assert(config.Cleanups, gc.HasLen, 2)
+	c.Assert(config.Cleanups[0].Name, gc.Equals, "rotated logs")
+	c.Assert(config.Cleanups[1].Name, gc.Equals, "old tools")
+}
+
+func (s *diskSpaceConfigSuite) TestInvalid(c *gc.C) {
+	_, err := diskSpaceConfig(&diskSpaceAgentConfig{values: map[string]string{
+		agent.DiskSpaceThreshold: "lots",
+	}}, false)
+	c.Assert(err, gc.ErrorMatches, `invalid DISK_SPACE_THRESHOLD value "lots"`)
+
+	_, err = diskSpaceConfig(&diskSpaceAgentConfig{values: map[string]string{
+		agent.DiskSpaceCleanups: "tools,charms",
+	}}, false)
+	c.Assert(err, gc.ErrorMatches, `invalid DISK_SPACE_CLEANUPS value "tools,charms"`)
+}
+
 type mockAgentConfig struct {
 	agent.Config
 	providerType string
@@ -1896,6 +2039,10 @@ func (m *mockMetricAPI) SendMetrics() error {
 	return nil
 }
 
+func (m *mockMetricAPI) SetMeterStatus(unit names.UnitTag, code, info string) error {
+	return nil
+}
+
 func (m *mockMetricAPI) SendCalled() <-chan struct{} {
 	return m.sendCalled
 }
@@ -10,9 +10,12 @@ import (
 	"io/ioutil"
 	"path"
 
+	"github.com/juju/utils"
+
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/simplestreams"
 	envstorage "github.com/juju/juju/environs/storage"
+	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/state/storage"
 )
 
@@ -67,3 +70,22 @@ func registerSimplestreamsDataSource(stor storage.Storage) {
 		return ds, nil
 	})
 }
+
+// stateServerToolsDataSource returns a datasource for the tools metadata
+// that the API server listening on the given port serves for the tools
+// in the storage of the given environment.
+func stateServerToolsDataSource(apiPort int, envUUID string) simplestreams.DataSource {
+	url := fmt.Sprintf("https://localhost:%d/environment/%s/tools", apiPort, envUUID)
+	return simplestreams.NewURLDataSource("state server", url, utils.NoVerifySSLHostnames)
+}
+
+// registerStateServerToolsDataSource registers the tools metadata served
+// by the local API server as the first place to look for tools, so that
+// agents can be deployed and upgraded without access to the public
+// tools mirrors.
+func registerStateServerToolsDataSource(apiPort int, envUUID string) {
+	ds := stateServerToolsDataSource(apiPort, envUUID)
+	envtools.RegisterPreferredToolsDataSourceFunc(ds.Description(), func(environs.Environ) (simplestreams.DataSource, error) {
+		return ds, nil
+	})
+}
@@ -32,7 +32,8 @@ type upgradingMachineAgent interface {
 }
 
 var (
-	upgradesPerformUpgrade = upgrades.PerformUpgrade // Allow patching
+	upgradesPerformUpgrade   = upgrades.PerformUpgrade   // Allow patching
+	upgradesPerformDowngrade = upgrades.PerformDowngrade // Allow patching
 
 	// The maximum time a master state server will wait for other
 	// state servers to come up and indicate they are ready to begin
@@ -82,7 +83,8 @@ func (c *upgradeWorkerContext) InitializeUsingAgent(a upgradingMachineAgent) err
 		return nil
 	}
 	return a.ChangeConfig(func(agentConfig agent.ConfigSetter) error {
-		if !upgrades.AreUpgradesDefined(agentConfig.UpgradedToVersion()) {
+		from := agentConfig.UpgradedToVersion()
+		if !upgrades.AreUpgradesDefined(from) && !upgrades.AreDowngradesDefined(from) {
 			logger.Infof("no upgrade steps required or upgrade steps for %v "+
 				"have already been run.", version.Current.Number)
 			close(c.UpgradeComplete)
@@ -323,15 +325,24 @@ func (c *upgradeWorkerContext) waitForOtherStateServers(info *state.UpgradeInfo)
 func (c *upgradeWorkerContext) runUpgradeSteps(agentConfig agent.ConfigSetter) error {
 	var upgradeErr error
 	a := c.agent
-	a.setMachineStatus(c.apiState, params.StatusStarted, fmt.Sprintf("upgrading to %v", c.toVersion))
+	perform := upgradesPerformUpgrade
+	if c.isRollback() {
+		// The agent is being rolled back to an earlier version, so
+		// revert the upgrade steps that were run to get to the
+		// version it is rolling back from.
+		perform = upgradesPerformDowngrade
+		a.setMachineStatus(c.apiState, params.StatusStarted, fmt.Sprintf("rolling back to %v", c.toVersion))
+		logger.Infof("starting rollback from %v to %v for %q", c.fromVersion, c.toVersion, c.tag)
+	} else {
+		a.setMachineStatus(c.apiState, params.StatusStarted, fmt.Sprintf("upgrading to %v", c.toVersion))
+		logger.Infof("starting upgrade from %v to %v for %q", c.fromVersion, c.toVersion, c.tag)
+	}
 
 	context := upgrades.NewContext(agentConfig, c.apiState, c.st)
-	logger.Infof("starting upgrade from %v to %v for %q", c.fromVersion, c.toVersion, c.tag)
-
 	targets := jobsToTargets(c.jobs, c.isMaster)
 	attempts := getUpgradeRetryStrategy()
 	for attempt := attempts.Start(); attempt.Next(); {
-		upgradeErr = upgradesPerformUpgrade(c.fromVersion, targets, context)
+		upgradeErr = perform(c.fromVersion, targets, context)
 		if upgradeErr == nil {
 			break
 		}
@@ -350,6 +361,12 @@ func (c *upgradeWorkerContext) runUpgradeSteps(agentConfig agent.ConfigSetter) e
 	return nil
 }
 
+// isRollback reports whether the agent is moving to an earlier
+// version than the one it last upgraded to.
+func (c *upgradeWorkerContext) isRollback() bool {
+	return c.toVersion.Compare(c.fromVersion) < 0
+}
+
 func (c *upgradeWorkerContext) reportUpgradeFailure(err error, willRetry bool) {
 	retryText := "will retry"
 	if !willRetry {
@@ -260,6 +260,30 @@ func (s *UpgradeSuite) TestUpgradeStepsRetries(c *gc.C) {
 	assertUpgradeComplete(c, context)
 }
 
+func (s *UpgradeSuite) TestRollbackRevertsUpgradeSteps(c *gc.C) {
+	upgradeAttemptsP := s.countUpgradeAttempts(nil)
+	var downgradedFrom []version.Number
+	s.PatchValue(&upgradesPerformDowngrade, func(from version.Number, _ []upgrades.Target, _ upgrades.Context) error {
+		downgradedFrom = append(downgradedFrom, from)
+		return nil
+	})
+	// The agent last upgraded to a later version than it is running.
+	s.oldVersion = version.Current
+	s.oldVersion.Patch++
+
+	workerErr, config, agent, context := s.runUpgradeWorker(c, multiwatcher.JobHostUnits)
+
+	c.Check(workerErr, gc.IsNil)
+	c.Check(*upgradeAttemptsP, gc.Equals, 0)
+	c.Check(downgradedFrom, jc.DeepEquals, []version.Number{s.oldVersion.Number})
+	c.Check(config.Version, gc.Equals, version.Current.Number)
+	c.Assert(agent.MachineStatusCalls, jc.DeepEquals, []MachineStatusCall{
+		{params.StatusStarted, fmt.Sprintf("rolling back to %s", version.Current.Number)},
+		{params.StatusStarted, ""},
+	})
+	assertUpgradeComplete(c, context)
+}
+
 func (s *UpgradeSuite) TestOtherUpgradeRunFailure(c *gc.C) {
 	// This test checks what happens something other than the upgrade
 	// steps themselves fails, ensuring the something is logged and
@@ -19,11 +19,14 @@ import (
 
 	jujucmd "github.com/juju/juju/cmd"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/juju/sockets"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
+	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -48,6 +51,10 @@ const (
 	exit_err = 2
 	// exit_panic is the value that is returned when we exit due to an unhandled panic.
 	exit_panic = 3
+
+	// logBufferSize holds how many log records an agent keeps while it
+	// cannot send them to the state servers.
+	logBufferSize = 100000
 )
 
 func getenv(name string) (string, error) {
@@ -121,15 +128,33 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	// TODO(katco-): AgentConf type is doing too much. The
 	// MachineAgent type has called out the seperate concerns; the
 	// AgentConf should be split up to follow suite.
+	// The agents send their logs to the state servers, which store
+	// them in the database.
+	bufferedLogs, err := logsender.InstallBufferedLogWriter(logBufferSize)
+	if err != nil {
+		return 1, err
+	}
+
 	var agentConf agentcmd.AgentConf
-	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf)
+	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf, bufferedLogs, unitWorkerFunc())
 	jujud.Register(agentcmd.NewMachineAgentCmd(machineAgentFactory, &agentConf, &agentConf))
 
-	jujud.Register(&UnitAgent{})
+	jujud.Register(NewUnitAgent(bufferedLogs))
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
 }
 
+// unitWorkerFunc returns the function with which the machine agent runs
+// the agents of its units inside its own process, or nil if they are to
+// run as separate processes, as they do unless the nested-unit-agents
+// feature flag is set.
+func unitWorkerFunc() deployer.UnitWorkerFunc {
+	if featureflag.Enabled(feature.NestedUnitAgents) {
+		return NewNestedUnitAgent
+	}
+	return nil
+}
+
 // Main is not redundant with main(), because it provides an entry point
 // for testing with arbitrary command line arguments.
 func Main(args []string) {
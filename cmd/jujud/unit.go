@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -24,10 +25,12 @@ import (
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agenthealth"
 	"github.com/juju/juju/worker/apiaddressupdater"
+	"github.com/juju/juju/worker/deployer"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/proxyupdater"
-	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -41,8 +44,56 @@ type UnitAgent struct {
 	agentcmd.AgentConf
 	UnitName     string
 	runner       worker.Runner
+	bufferedLogs *logsender.BufferedLogWriter
 	setupLogging func(agent.Config) error
 	logToStdErr  bool
+
+	// nested is true when the agent runs inside the machine agent
+	// process, which then writes and sends the agent's logs.
+	nested bool
+}
+
+// NewUnitAgent creates a new UnitAgent value, which sends the log
+// records buffered by the given writer to the state servers.
+func NewUnitAgent(bufferedLogs *logsender.BufferedLogWriter) *UnitAgent {
+	return &UnitAgent{bufferedLogs: bufferedLogs}
+}
+
+// NewNestedUnitAgent runs the agent of the named unit as a worker
+// inside the machine agent process, reading the unit agent's
+// configuration from the given data directory. It is a
+// deployer.UnitWorkerFunc.
+func NewNestedUnitAgent(dataDir, unitName string) (worker.Worker, error) {
+	a := &UnitAgent{
+		AgentConf: agentcmd.AgentConf{DataDir: dataDir},
+		UnitName:  unitName,
+		runner:    worker.NewRunner(cmdutil.IsFatal, cmdutil.MoreImportant),
+		nested:    true,
+	}
+	w := &nestedUnitAgent{agent: a}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(a.Run(nil))
+	}()
+	return w, nil
+}
+
+var _ deployer.UnitWorkerFunc = NewNestedUnitAgent
+
+// nestedUnitAgent is a worker that runs a unit agent.
+type nestedUnitAgent struct {
+	tomb  tomb.Tomb
+	agent *UnitAgent
+}
+
+// Kill implements worker.Worker.
+func (w *nestedUnitAgent) Kill() {
+	w.agent.runner.Kill()
+}
+
+// Wait implements worker.Worker.
+func (w *nestedUnitAgent) Wait() error {
+	return w.tomb.Wait()
 }
 
 // Info returns usage information for the command.
@@ -88,7 +139,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 	}
 	agentConfig := a.CurrentConfig()
 
-	if !a.logToStdErr {
+	if !a.logToStdErr && !a.nested {
 		filename := filepath.Join(agentConfig.LogDir(), agentConfig.Tag().String()+".log")
 
 		log := &lumberjack.Logger{
@@ -170,13 +221,38 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 	runner.StartWorker("logger", func() (worker.Worker, error) {
 		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
 	})
+	// The uniter may be restarted at any time, so the agent health worker
+	// reports the hook queue depth of whichever one is currently running.
+	var uniterMu sync.Mutex
+	var currentUniter *uniter.Uniter
 	runner.StartWorker("uniter", func() (worker.Worker, error) {
 		uniterFacade, err := st.Uniter()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return uniter.NewUniter(uniterFacade, unitTag, st.LeadershipManager(), dataDir, hookLock), nil
+		u := uniter.NewUniter(uniterFacade, unitTag, st.LeadershipManager(), dataDir, hookLock)
+		uniterMu.Lock()
+		currentUniter = u
+		uniterMu.Unlock()
+		return u, nil
 	})
+	if st.BestFacadeVersion("AgentHealth") > 0 {
+		runner.StartWorker("agenthealth", func() (worker.Worker, error) {
+			return agenthealth.NewWorker(agenthealth.Config{
+				Setter:      st.AgentHealth(),
+				DataDir:     dataDir,
+				LastAPICall: st.LastSuccessfulCall,
+				HookQueueDepth: func() int {
+					uniterMu.Lock()
+					defer uniterMu.Unlock()
+					if currentUniter == nil {
+						return 0
+					}
+					return currentUniter.HookQueueDepth()
+				},
+			})
+		})
+	}
 
 	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
 		uniterFacade, err := st.Uniter()
@@ -185,9 +261,13 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		}
 		return apiaddressupdater.NewAPIAddressUpdater(uniterFacade, a), nil
 	})
-	runner.StartWorker("rsyslog", func() (worker.Worker, error) {
-		return cmdutil.NewRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslog.RsyslogModeForwarding)
-	})
+	// The logs of a nested unit agent are sent by the machine agent
+	// running it, as they share the process's log writer.
+	if !a.nested {
+		runner.StartWorker("logsender", func() (worker.Worker, error) {
+			return cmdutil.NewLogSender(a.bufferedLogs, agentConfig), nil
+		})
+	}
 	return cmdutil.NewCloseWorker(logger, runner, st), nil
 }
 
@@ -19,11 +19,11 @@ import (
 
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
-	apirsyslog "github.com/juju/juju/api/rsyslog"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	agenttesting "github.com/juju/juju/cmd/jujud/agent/testing"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/feature"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/lease"
 	"github.com/juju/juju/network"
@@ -32,7 +32,7 @@ import (
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
-	"github.com/juju/juju/worker/rsyslog"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/upgrader"
 )
 
@@ -97,7 +97,7 @@ func (s *UnitSuite) primeAgent(c *gc.C) (*state.Machine, *state.Unit, agent.Conf
 }
 
 func (s *UnitSuite) newAgent(c *gc.C, unit *state.Unit) *UnitAgent {
-	a := &UnitAgent{}
+	a := NewUnitAgent(logsender.NewBufferedLogWriter(1024))
 	s.InitAgent(c, a, "--unit-name", unit.Name(), "--log-to-stderr=true")
 	err := a.ReadConfig(unit.Tag().String())
 	c.Assert(err, jc.ErrorIsNil)
@@ -316,11 +316,11 @@ func (s *UnitSuite) TestOpenStateFails(c *gc.C) {
 	s.AssertCannotOpenState(c, conf.Tag(), conf.DataDir())
 }
 
-func (s *UnitSuite) TestRsyslogConfigWorker(c *gc.C) {
-	created := make(chan rsyslog.RsyslogMode, 1)
-	s.PatchValue(&cmdutil.NewRsyslogConfigWorker, func(_ *apirsyslog.State, _ agent.Config, mode rsyslog.RsyslogMode) (worker.Worker, error) {
-		created <- mode
-		return newDummyWorker(), nil
+func (s *UnitSuite) TestLogSenderWorker(c *gc.C) {
+	created := make(chan agent.Config, 1)
+	s.PatchValue(&cmdutil.NewLogSender, func(_ *logsender.BufferedLogWriter, agentConfig agent.Config) worker.Worker {
+		created <- agentConfig
+		return newDummyWorker()
 	})
 
 	_, unit, _, _ := s.primeAgent(c)
@@ -330,12 +330,71 @@ func (s *UnitSuite) TestRsyslogConfigWorker(c *gc.C) {
 
 	select {
 	case <-time.After(coretesting.LongWait):
-		c.Fatalf("timeout while waiting for rsyslog worker to be created")
-	case mode := <-created:
-		c.Assert(mode, gc.Equals, rsyslog.RsyslogModeForwarding)
+		c.Fatalf("timeout while waiting for logsender worker to be created")
+	case agentConfig := <-created:
+		c.Assert(agentConfig.Tag(), gc.Equals, unit.Tag())
+	}
+}
+
+func (s *UnitSuite) TestNestedUnitAgent(c *gc.C) {
+	created := make(chan agent.Config, 1)
+	s.PatchValue(&cmdutil.NewLogSender, func(_ *logsender.BufferedLogWriter, agentConfig agent.Config) worker.Worker {
+		created <- agentConfig
+		return newDummyWorker()
+	})
+
+	_, unit, _, _ := s.primeAgent(c)
+	w, err := NewNestedUnitAgent(s.DataDir(), unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Check(worker.Stop(w), jc.ErrorIsNil) }()
+	waitForUnitActive(s.State, unit, c)
+
+	// The machine agent running the unit agent sends its logs.
+	select {
+	case <-created:
+		c.Fatalf("nested unit agent started a logsender worker")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *UnitSuite) TestNestedUnitAgentSetsToolsVersion(c *gc.C) {
+	// The nested unit agent still runs its own upgrader.
+	_, unit, _, _ := s.primeAgent(c)
+	vers := version.Current
+	vers.Minor = version.Current.Minor + 1
+	err := unit.SetAgentVersion(vers)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := NewNestedUnitAgent(s.DataDir(), unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Check(worker.Stop(w), jc.ErrorIsNil) }()
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timeout while waiting for agent version to be set")
+		case <-time.After(coretesting.ShortWait):
+			err := unit.Refresh()
+			c.Assert(err, jc.ErrorIsNil)
+			agentTools, err := unit.AgentTools()
+			c.Assert(err, jc.ErrorIsNil)
+			if agentTools.Version.Minor == version.Current.Minor {
+				c.Assert(agentTools.Version, gc.DeepEquals, version.Current)
+				return
+			}
+		}
 	}
 }
 
+func (s *UnitSuite) TestUnitWorkerFunc(c *gc.C) {
+	// Unit agents run as separate processes by default.
+	c.Assert(unitWorkerFunc(), gc.IsNil)
+
+	s.SetFeatureFlags(feature.NestedUnitAgents)
+	c.Assert(unitWorkerFunc(), gc.NotNil)
+}
+
 func (s *UnitSuite) TestAgentSetsToolsVersion(c *gc.C) {
 	_, unit, _, _ := s.primeAgent(c)
 	vers := version.Current
@@ -8,6 +8,7 @@ import (
 	"io"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -15,14 +16,13 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/juju/juju/agent"
-	apirsyslog "github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
-	"github.com/juju/juju/worker/rsyslog"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/upgrader"
 )
 
@@ -165,6 +165,25 @@ func NewEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		}
 	}
 
+	// Likewise for the mongo cache size.
+	var cacheSize int
+	if cacheSizeString := agentConfig.Value(agent.MongoCacheSize); cacheSizeString != "" {
+		var err error
+		if cacheSize, err = strconv.Atoi(cacheSizeString); err != nil {
+			return mongo.EnsureServerParams{}, fmt.Errorf("invalid mongo cache size: %q", cacheSizeString)
+		}
+	}
+
+	// If a journal commit interval is specified in the agent
+	// configuration, use that. Otherwise mongo's default is used.
+	var journalCommit time.Duration
+	if journalCommitString := agentConfig.Value(agent.MongoJournalCommit); journalCommitString != "" {
+		var err error
+		if journalCommit, err = time.ParseDuration(journalCommitString); err != nil {
+			return mongo.EnsureServerParams{}, fmt.Errorf("invalid mongo journal commit interval: %q", journalCommitString)
+		}
+	}
+
 	// If numa ctl preference is specified in the agent configuration, use that.
 	// Otherwise leave the default false value to indicate to EnsureServer
 	// that numactl should not be used.
@@ -190,10 +209,12 @@ func NewEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		SharedSecret:   si.SharedSecret,
 		SystemIdentity: si.SystemIdentity,
 
-		DataDir:              agentConfig.DataDir(),
-		Namespace:            agentConfig.Value(agent.Namespace),
-		OplogSize:            oplogSize,
-		SetNumaControlPolicy: numaCtlPolicy,
+		DataDir:               agentConfig.DataDir(),
+		Namespace:             agentConfig.Value(agent.Namespace),
+		OplogSize:             oplogSize,
+		CacheSizeGB:           cacheSize,
+		JournalCommitInterval: journalCommit,
+		SetNumaControlPolicy:  numaCtlPolicy,
 	}
 	return params, nil
 }
@@ -236,17 +257,11 @@ func HookExecutionLock(dataDir string) (*fslock.Lock, error) {
 	return fslock.NewLock(lockDir, "uniter-hook-execution")
 }
 
-// NewRsyslogConfigWorker creates and returns a new
-// RsyslogConfigWorker based on the specified configuration
-// parameters.
-var NewRsyslogConfigWorker = func(st *apirsyslog.State, agentConfig agent.Config, mode rsyslog.RsyslogMode) (worker.Worker, error) {
-	tag := agentConfig.Tag()
-	namespace := agentConfig.Value(agent.Namespace)
-	addrs, err := agentConfig.APIAddresses()
-	if err != nil {
-		return nil, err
-	}
-	return rsyslog.NewRsyslogConfigWorker(st, mode, tag, namespace, addrs)
+// NewLogSender creates and returns a new logsender worker, which sends
+// the log records buffered by an agent to the state servers using the
+// API connection details in the agent's configuration.
+var NewLogSender = func(bufferedLogs *logsender.BufferedLogWriter, agentConfig agent.Config) worker.Worker {
+	return logsender.New(bufferedLogs.Logs(), agentConfig.APIInfo())
 }
 
 // ParamsStateServingInfoToStateStateServingInfo converts a
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/imagemetadata"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/version"
+)
+
+// MetadataAPI defines the API methods that the commands managing the
+// custom image metadata stored on the controller use.
+type MetadataAPI interface {
+	List(filter params.ImageMetadataFilter) ([]params.CloudImageMetadata, error)
+	Save(metadata []params.CloudImageMetadata) error
+	Delete(imageId string) error
+	Close() error
+}
+
+// cloudImageMetadataCommandBase is the base of the commands that
+// manage the custom image metadata stored on the controller.
+type cloudImageMetadataCommandBase struct {
+	envcmd.EnvCommandBase
+	api MetadataAPI
+}
+
+func (c *cloudImageMetadataCommandBase) getMetadataAPI() (MetadataAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return imagemetadata.NewClient(root), nil
+}
+
+// AddImageMetadataCommand stores custom image metadata on the
+// controller.
+type AddImageMetadataCommand struct {
+	cloudImageMetadataCommandBase
+	ImageId         string
+	Region          string
+	Series          string
+	Arch            string
+	Stream          string
+	VirtType        string
+	RootStorageType string
+}
+
+var addImageMetadataDoc = `
+add-image stores the metadata of a custom image on the controller of the
+current Juju environment. When starting instances, providers prefer the
+stored images to those published by simplestreams, so private images can
+be used without serving simplestreams metadata from a web server.
+
+The region and series of the image must be specified. By default, the
+image is recorded as an "amd64" image in the "released" stream.
+
+Example:
+   juju metadata add-image ami-8c6f2dec --region us-east-1 --series trusty
+`
+
+func (c *AddImageMetadataCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-image",
+		Args:    "<image id>",
+		Purpose: "store custom image metadata on the controller",
+		Doc:     addImageMetadataDoc,
+	}
+}
+
+func (c *AddImageMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Region, "region", "", "the cloud region of the image")
+	f.StringVar(&c.Series, "series", "", "the series of the image")
+	f.StringVar(&c.Arch, "arch", arch.AMD64, "the architecture of the image")
+	f.StringVar(&c.Stream, "stream", "released", "the image stream")
+	f.StringVar(&c.VirtType, "virt-type", "", "the virtualisation type of the image")
+	f.StringVar(&c.RootStorageType, "storage-type", "", "the root storage type of the image")
+}
+
+func (c *AddImageMetadataCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no image id specified")
+	}
+	c.ImageId, args = args[0], args[1:]
+	if c.Region == "" {
+		return errors.New("no region specified")
+	}
+	if c.Series == "" {
+		return errors.New("no series specified")
+	}
+	if _, err := version.SeriesVersion(c.Series); err != nil {
+		return errors.Trace(err)
+	}
+	if !arch.IsSupportedArch(c.Arch) {
+		return errors.NotSupportedf("architecture %q", c.Arch)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *AddImageMetadataCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getMetadataAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.Save([]params.CloudImageMetadata{{
+		ImageId:         c.ImageId,
+		Region:          c.Region,
+		Series:          c.Series,
+		Arch:            c.Arch,
+		Stream:          c.Stream,
+		VirtType:        c.VirtType,
+		RootStorageType: c.RootStorageType,
+	}})
+}
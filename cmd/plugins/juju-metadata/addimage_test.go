@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AddImageSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *fakeMetadataAPI
+}
+
+var _ = gc.Suite(&AddImageSuite{})
+
+func (s *AddImageSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeMetadataAPI{}
+}
+
+func (s *AddImageSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &AddImageMetadataCommand{}
+	command.api = s.api
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *AddImageSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no image id specified",
+	}, {
+		args: []string{"ami-1", "--series", "trusty"},
+		err:  "no region specified",
+	}, {
+		args: []string{"ami-1", "--region", "us-east-1"},
+		err:  "no series specified",
+	}, {
+		args: []string{"ami-1", "--region", "us-east-1", "--series", "bad"},
+		err:  `invalid series "bad"`,
+	}, {
+		args: []string{"ami-1", "--region", "us-east-1", "--series", "trusty", "--arch", "sparc"},
+		err:  `architecture "sparc" not supported`,
+	}, {
+		args: []string{"ami-1", "ami-2", "--region", "us-east-1", "--series", "trusty"},
+		err:  `unrecognized args: \["ami-2"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	c.Assert(s.api.saved, gc.HasLen, 0)
+}
+
+func (s *AddImageSuite) TestAddImage(c *gc.C) {
+	_, err := s.run(c, "ami-1", "--region", "us-east-1", "--series", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.saved, jc.DeepEquals, []params.CloudImageMetadata{{
+		ImageId: "ami-1",
+		Region:  "us-east-1",
+		Series:  "trusty",
+		Arch:    "amd64",
+		Stream:  "released",
+	}})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *AddImageSuite) TestAddImageAllAttributes(c *gc.C) {
+	_, err := s.run(c, "ami-1",
+		"--region", "us-east-1",
+		"--series", "precise",
+		"--arch", "i386",
+		"--stream", "daily",
+		"--virt-type", "hvm",
+		"--storage-type", "ebs",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.saved, jc.DeepEquals, []params.CloudImageMetadata{{
+		ImageId:         "ami-1",
+		Region:          "us-east-1",
+		Series:          "precise",
+		Arch:            "i386",
+		Stream:          "daily",
+		VirtType:        "hvm",
+		RootStorageType: "ebs",
+	}})
+}
+
+// fakeMetadataAPI is a MetadataAPI that records the calls made to it.
+type fakeMetadataAPI struct {
+	filter  params.ImageMetadataFilter
+	found   []params.CloudImageMetadata
+	saved   []params.CloudImageMetadata
+	deleted []string
+	err     error
+	closed  bool
+}
+
+func (f *fakeMetadataAPI) List(filter params.ImageMetadataFilter) ([]params.CloudImageMetadata, error) {
+	f.filter = filter
+	return f.found, f.err
+}
+
+func (f *fakeMetadataAPI) Save(metadata []params.CloudImageMetadata) error {
+	f.saved = append(f.saved, metadata...)
+	return f.err
+}
+
+func (f *fakeMetadataAPI) Delete(imageId string) error {
+	f.deleted = append(f.deleted, imageId)
+	return f.err
+}
+
+func (f *fakeMetadataAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// DeleteImageMetadataCommand deletes custom image metadata stored on
+// the controller.
+type DeleteImageMetadataCommand struct {
+	cloudImageMetadataCommandBase
+	ImageId string
+}
+
+var deleteImageMetadataDoc = `
+delete-image deletes the metadata of a custom image, stored on the
+controller of the current Juju environment with add-image. New instances
+will no longer be started with the image.
+
+Example:
+   juju metadata delete-image ami-8c6f2dec
+`
+
+func (c *DeleteImageMetadataCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "delete-image",
+		Args:    "<image id>",
+		Purpose: "delete custom image metadata stored on the controller",
+		Doc:     deleteImageMetadataDoc,
+	}
+}
+
+func (c *DeleteImageMetadataCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no image id specified")
+	}
+	c.ImageId, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *DeleteImageMetadataCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getMetadataAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.Delete(c.ImageId)
+}
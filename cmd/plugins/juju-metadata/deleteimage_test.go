@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DeleteImageSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *fakeMetadataAPI
+}
+
+var _ = gc.Suite(&DeleteImageSuite{})
+
+func (s *DeleteImageSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeMetadataAPI{}
+}
+
+func (s *DeleteImageSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &DeleteImageMetadataCommand{}
+	command.api = s.api
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *DeleteImageSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no image id specified")
+	_, err = s.run(c, "ami-1", "ami-2")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["ami-2"\]`)
+	c.Assert(s.api.deleted, gc.HasLen, 0)
+}
+
+func (s *DeleteImageSuite) TestDeleteImage(c *gc.C) {
+	_, err := s.run(c, "ami-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.deleted, jc.DeepEquals, []string{"ami-1"})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *DeleteImageSuite) TestDeleteImageError(c *gc.C) {
+	s.api.err = errors.New(`cannot delete cloud image metadata for image "ami-1": image metadata not found`)
+	_, err := s.run(c, "ami-1")
+	c.Assert(err, gc.ErrorMatches, `cannot delete cloud image metadata for image "ami-1": image metadata not found`)
+}
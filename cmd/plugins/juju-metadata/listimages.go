@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ListImageMetadataCommand lists the custom image metadata stored on
+// the controller.
+type ListImageMetadataCommand struct {
+	cloudImageMetadataCommandBase
+	out             cmd.Output
+	Region          string
+	Series          []string
+	Arches          []string
+	Stream          string
+	VirtType        string
+	RootStorageType string
+}
+
+var listImageMetadataDoc = `
+list-images lists the custom image metadata stored on the controller of
+the current Juju environment. The results may be narrowed down using the
+filtering options.
+
+Example:
+   juju metadata list-images --region us-east-1 --series trusty --series precise
+`
+
+func (c *ListImageMetadataCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-images",
+		Purpose: "list custom image metadata stored on the controller",
+		Doc:     listImageMetadataDoc,
+	}
+}
+
+func (c *ListImageMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Region, "region", "", "only show images in this region")
+	f.Var(cmd.NewAppendStringsValue(&c.Series), "series", "only show images of these series")
+	f.Var(cmd.NewAppendStringsValue(&c.Arches), "arch", "only show images of these architectures")
+	f.StringVar(&c.Stream, "stream", "", "only show images in this stream")
+	f.StringVar(&c.VirtType, "virt-type", "", "only show images of this virtualisation type")
+	f.StringVar(&c.RootStorageType, "storage-type", "", "only show images of this root storage type")
+
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatImageMetadataTabular,
+	})
+}
+
+func (c *ListImageMetadataCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// MetadataInfo describes a custom image in the output of list-images.
+type MetadataInfo struct {
+	ImageId         string `yaml:"image-id" json:"image-id"`
+	Region          string `yaml:"region" json:"region"`
+	Series          string `yaml:"series" json:"series"`
+	Arch            string `yaml:"arch" json:"arch"`
+	Stream          string `yaml:"stream" json:"stream"`
+	VirtType        string `yaml:"virt-type,omitempty" json:"virt-type,omitempty"`
+	RootStorageType string `yaml:"storage-type,omitempty" json:"storage-type,omitempty"`
+}
+
+func (c *ListImageMetadataCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getMetadataAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	found, err := api.List(params.ImageMetadataFilter{
+		Region:          c.Region,
+		Series:          c.Series,
+		Arches:          c.Arches,
+		Stream:          c.Stream,
+		VirtType:        c.VirtType,
+		RootStorageType: c.RootStorageType,
+	})
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		ctx.Infof("no image metadata found")
+		return nil
+	}
+	info := make([]MetadataInfo, len(found))
+	for i, m := range found {
+		info[i] = MetadataInfo{
+			ImageId:         m.ImageId,
+			Region:          m.Region,
+			Series:          m.Series,
+			Arch:            m.Arch,
+			Stream:          m.Stream,
+			VirtType:        m.VirtType,
+			RootStorageType: m.RootStorageType,
+		}
+	}
+	return c.out.Write(ctx, info)
+}
+
+// formatImageMetadataTabular returns a tabular summary of the given
+// image metadata.
+func formatImageMetadataTabular(value interface{}) ([]byte, error) {
+	metadata, ok := value.([]MetadataInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", metadata, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("IMAGE ID", "REGION", "SERIES", "ARCH", "STREAM", "VIRT TYPE", "STORAGE TYPE")
+	for _, m := range metadata {
+		print(m.ImageId, m.Region, m.Series, m.Arch, m.Stream, m.VirtType, m.RootStorageType)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
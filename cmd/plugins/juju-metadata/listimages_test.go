@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ListImagesSuite struct {
+	coretesting.FakeJujuHomeSuite
+	api *fakeMetadataAPI
+}
+
+var _ = gc.Suite(&ListImagesSuite{})
+
+func (s *ListImagesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeMetadataAPI{
+		found: []params.CloudImageMetadata{{
+			ImageId:         "ami-1",
+			Region:          "us-east-1",
+			Series:          "trusty",
+			Arch:            "amd64",
+			Stream:          "released",
+			VirtType:        "hvm",
+			RootStorageType: "ebs",
+		}, {
+			ImageId: "ami-2",
+			Region:  "us-east-1",
+			Series:  "precise",
+			Arch:    "i386",
+			Stream:  "released",
+		}},
+	}
+}
+
+func (s *ListImagesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &ListImageMetadataCommand{}
+	command.api = s.api
+	return coretesting.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ListImagesSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"IMAGE ID  REGION     SERIES   ARCH   STREAM    VIRT TYPE  STORAGE TYPE\n"+
+		"ami-1     us-east-1  trusty   amd64  released  hvm        ebs\n"+
+		"ami-2     us-east-1  precise  i386   released             \n")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ListImagesSuite) TestListYAML(c *gc.C) {
+	s.api.found = s.api.found[1:]
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"- image-id: ami-2\n"+
+		"  region: us-east-1\n"+
+		"  series: precise\n"+
+		"  arch: i386\n"+
+		"  stream: released\n")
+}
+
+func (s *ListImagesSuite) TestListFilter(c *gc.C) {
+	_, err := s.run(c,
+		"--region", "us-east-1",
+		"--series", "trusty", "--series", "precise",
+		"--arch", "amd64",
+		"--stream", "daily",
+		"--virt-type", "hvm",
+		"--storage-type", "ebs",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, params.ImageMetadataFilter{
+		Region:          "us-east-1",
+		Series:          []string{"trusty", "precise"},
+		Arches:          []string{"amd64"},
+		Stream:          "daily",
+		VirtType:        "hvm",
+		RootStorageType: "ebs",
+	})
+}
+
+func (s *ListImagesSuite) TestListNone(c *gc.C) {
+	s.api.found = nil
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "no image metadata found\n")
+}
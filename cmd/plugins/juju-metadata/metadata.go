@@ -20,6 +20,9 @@ var logger = loggo.GetLogger("juju.plugins.metadata")
 var metadataDoc = `
 Juju metadata is used to find the correct image and tools when bootstrapping a
 Juju environment.
+
+Custom image metadata may also be stored on the controller of a running
+environment, and is then used when starting new instances.
 `
 
 // Main registers subcommands for the juju-metadata executable, and hands over control
@@ -39,7 +42,7 @@ func Main(args []string) {
 		Name:        "metadata",
 		UsagePrefix: "juju",
 		Doc:         metadataDoc,
-		Purpose:     "tools for generating and validating image and tools metadata",
+		Purpose:     "tools for generating, validating and storing image and tools metadata",
 		Log:         &cmd.Log{}})
 
 	metadatacmd.Register(envcmd.Wrap(&ValidateImageMetadataCommand{}))
@@ -47,6 +50,9 @@ func Main(args []string) {
 	metadatacmd.Register(envcmd.Wrap(&ToolsMetadataCommand{}))
 	metadatacmd.Register(envcmd.Wrap(&ValidateToolsMetadataCommand{}))
 	metadatacmd.Register(&SignMetadataCommand{})
+	metadatacmd.Register(envcmd.Wrap(&AddImageMetadataCommand{}))
+	metadatacmd.Register(envcmd.Wrap(&ListImageMetadataCommand{}))
+	metadatacmd.Register(envcmd.Wrap(&DeleteImageMetadataCommand{}))
 
 	os.Exit(cmd.Main(metadatacmd, ctx, args[1:]))
 }
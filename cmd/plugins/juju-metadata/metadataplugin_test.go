@@ -28,9 +28,12 @@ type MetadataSuite struct {
 var _ = gc.Suite(&MetadataSuite{})
 
 var metadataCommandNames = []string{
+	"add-image",
+	"delete-image",
 	"generate-image",
 	"generate-tools",
 	"help",
+	"list-images",
 	"sign",
 	"validate-images",
 	"validate-tools",
@@ -7,6 +7,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -24,6 +25,7 @@ import (
 	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
@@ -436,16 +438,21 @@ func extractConfig(backupFile string) (agentConfig, error) {
 		return agentConfig{}, err
 	}
 	// TODO(ericsnowcurrently) This should come from an authoritative source.
-	const confFilename = "var/lib/juju/agents/%s/agent.conf"
-	agentConf, err := findFileInTar(outerTar, fmt.Sprintf(confFilename, tag))
+	const (
+		confFilename = "var/lib/juju/agents/%s/agent.conf"
+		keyFilename  = "var/lib/juju/agents/%s/agent.key"
+	)
+	confPath := fmt.Sprintf(confFilename, tag)
+	keyPath := fmt.Sprintf(keyFilename, tag)
+	files, err := readFilesFromTar(outerTar, confPath, keyPath)
 	if err != nil {
 		return agentConfig{}, err
 	}
 
 	// Extract the config data.
-	data, err := ioutil.ReadAll(agentConf)
-	if err != nil {
-		return agentConfig{}, errors.Annotate(err, "failed to read agent config file")
+	data, ok := files[confPath]
+	if !ok {
+		return agentConfig{}, errors.NotFoundf(confPath)
 	}
 	var conf interface{}
 	if err := goyaml.Unmarshal(data, &conf); err != nil {
@@ -455,6 +462,17 @@ func extractConfig(backupFile string) (agentConfig, error) {
 	if !ok {
 		return agentConfig{}, fmt.Errorf("config file unmarshalled to %T not %T", conf, m)
 	}
+	// Agent configs from 1.24 hold the passwords sealed with the key
+	// in the agent directory, which is backed up alongside them.
+	if sealed, ok := m["secrets"].(string); ok && sealed != "" {
+		key, ok := files[keyPath]
+		if !ok {
+			return agentConfig{}, errors.NotFoundf(keyPath)
+		}
+		if err := unsealSecrets(m, sealed, key); err != nil {
+			return agentConfig{}, errors.Annotate(err, "cannot read agent secrets")
+		}
+	}
 	password, ok := m["statepassword"].(string)
 	if !ok || password == "" {
 		return agentConfig{}, fmt.Errorf("agent password not found in configuration")
@@ -502,6 +520,61 @@ func findFileInTar(r io.Reader, name string) (io.Reader, error) {
 	}
 }
 
+// readFilesFromTar returns the contents of the named files in the tar
+// archive read from r. Files not found in the archive are omitted.
+func readFilesFromTar(r io.Reader, names ...string) (map[string][]byte, error) {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	files := make(map[string][]byte)
+	tarr := tar.NewReader(r)
+	for len(files) < len(wanted) {
+		hdr, err := tarr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Annotate(err, "while reading tar archive")
+		}
+		name := path.Clean(hdr.Name)
+		if !wanted[name] {
+			continue
+		}
+		data, err := ioutil.ReadAll(tarr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to read %q", name)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// unsealSecrets opens the secrets sealed in an agent config with the
+// given agent key, and adds them to the config's map.
+func unsealSecrets(m map[interface{}]interface{}, sealed string, key []byte) error {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sealer, err := agent.NewKeySealer(key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err = sealer.Unseal(data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var secrets map[interface{}]interface{}
+	if err := goyaml.Unmarshal(data, &secrets); err != nil {
+		return errors.Trace(err)
+	}
+	for k, v := range secrets {
+		m[k] = v
+	}
+	return nil
+}
+
 var agentAddressTemplate = mustParseTemplate(`
 set -exu
 cd /var/lib/juju/agents
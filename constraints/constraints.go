@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/arch"
@@ -29,8 +30,25 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+	VirtType     = "virt-type"
+	Zones        = "zones"
 )
 
+// The values accepted for the virt-type constraint.
+const (
+	// VirtTypeKVM requires a KVM virtual machine.
+	VirtTypeKVM = "kvm"
+
+	// VirtTypeLXC requires an LXC container.
+	VirtTypeLXC = "lxc"
+
+	// VirtTypeMetal requires a machine that is not virtualised.
+	VirtTypeMetal = "metal"
+)
+
+// virtTypes lists the values accepted for the virt-type constraint.
+var virtTypes = []string{VirtTypeKVM, VirtTypeLXC, VirtTypeMetal}
+
 // Value describes a user's requirements of the hardware on which units
 // of a service will run. Constraints are used to choose an existing machine
 // onto which a unit will be deployed, or to provision a new machine if no
@@ -78,6 +96,16 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// VirtType, if not nil or empty, indicates that a machine must be
+	// virtualised in the named way: "kvm", "lxc" or "metal" for none.
+	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zones; a machine
+	// must be started in one of them, and the machines of a service
+	// are spread across them. An empty list is treated the same as a
+	// nil list, except that it overrides any default zones.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -151,6 +179,32 @@ func (v *Value) HaveNetworks() bool {
 	return v.Networks != nil && len(*v.Networks) > 0
 }
 
+// HasVirtType returns true if the constraints.Value specifies a
+// virtualisation type.
+func (v *Value) HasVirtType() bool {
+	return v.VirtType != nil && *v.VirtType != ""
+}
+
+// HasZones returns true if the constraints.Value restricts machines to
+// some availability zones.
+func (v *Value) HasZones() bool {
+	return v.Zones != nil && len(*v.Zones) > 0
+}
+
+// AllowsZone reports whether a machine may be started in the named
+// availability zone.
+func (v *Value) AllowsZone(zone string) bool {
+	if !v.HasZones() {
+		return true
+	}
+	for _, z := range *v.Zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -191,6 +245,13 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.VirtType != nil {
+		strs = append(strs, "virt-type="+*v.VirtType)
+	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -330,6 +391,10 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case VirtType:
+		err = v.setVirtType(str)
+	case Zones:
+		err = v.setZones(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -376,6 +441,10 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			if err == nil {
 				err = v.validateNetworks(networks)
 			}
+		case VirtType:
+			v.VirtType = &vstr
+		case Zones:
+			v.Zones, err = parseYamlStrings("zones", val)
 		default:
 			return false
 		}
@@ -478,6 +547,25 @@ func (v *Value) setNetworks(str string) error {
 	return nil
 }
 
+func (v *Value) setVirtType(str string) error {
+	if v.VirtType != nil {
+		return fmt.Errorf("already set")
+	}
+	if str != "" && !set.NewStrings(virtTypes...).Contains(str) {
+		return fmt.Errorf("%q not recognized", str)
+	}
+	v.VirtType = &str
+	return nil
+}
+
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Zones = parseCommaDelimited(str)
+	return nil
+}
+
 func (v *Value) validateNetworks(networks *[]string) error {
 	if networks == nil {
 		return nil
@@ -525,7 +613,8 @@ func parseSize(str string) (*uint64, error) {
 }
 
 // parseCommaDelimited returns the items in the value s. We expect the
-// tags to be comma delimited strings. It is used for tags and networks.
+// tags to be comma delimited strings. It is used for tags, networks
+// and zones.
 func parseCommaDelimited(s string) *[]string {
 	if s == "" {
 		return &[]string{}
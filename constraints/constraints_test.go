@@ -280,6 +280,42 @@ var parseConstraintsTests = []struct {
 		args:    []string{"networks="},
 	},
 
+	// virt-type
+	{
+		summary: "set virt-type kvm",
+		args:    []string{"virt-type=kvm"},
+	}, {
+		summary: "set virt-type metal",
+		args:    []string{"virt-type=metal"},
+	}, {
+		summary: "virt-type empty",
+		args:    []string{"virt-type="},
+	}, {
+		summary: "set unknown virt-type",
+		args:    []string{"virt-type=xen"},
+		err:     `bad "virt-type" constraint: "xen" not recognized`,
+	}, {
+		summary: "double set virt-type together",
+		args:    []string{"virt-type=lxc virt-type=kvm"},
+		err:     `bad "virt-type" constraint: already set`,
+	},
+
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=us-east-1a"},
+	}, {
+		summary: "multiple zones",
+		args:    []string{"zones=us-east-1a,us-east-1b"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	}, {
+		summary: "double set zones separately",
+		args:    []string{"zones=us-east-1a", "zones=us-east-1b"},
+		err:     `bad "zones" constraint: already set`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -299,7 +335,8 @@ var parseConstraintsTests = []struct {
 		summary: "kitchen sink separately",
 		args: []string{
 			"root-disk=8G", "mem=2T", "cpu-cores=4096", "cpu-power=9001", "arch=armhf",
-			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo"},
+			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo",
+			"virt-type=kvm", "zones=az1,az2"},
 	},
 }
 
@@ -373,6 +410,27 @@ func (s *ConstraintsSuite) TestIncludeExcludeAndHaveNetworks(c *gc.C) {
 	c.Check(con.HaveNetworks(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasVirtType(c *gc.C) {
+	con := constraints.MustParse("virt-type=lxc")
+	c.Check(con.HasVirtType(), jc.IsTrue)
+	con = constraints.MustParse("virt-type=")
+	c.Check(con.HasVirtType(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HasVirtType(), jc.IsFalse)
+}
+
+func (s *ConstraintsSuite) TestZones(c *gc.C) {
+	con := constraints.MustParse("zones=az1,az2")
+	c.Check(con.HasZones(), jc.IsTrue)
+	c.Check(con.AllowsZone("az1"), jc.IsTrue)
+	c.Check(con.AllowsZone("az3"), jc.IsFalse)
+	for _, s := range []string{"zones=", "mem=4G"} {
+		con = constraints.MustParse(s)
+		c.Check(con.HasZones(), jc.IsFalse)
+		c.Check(con.AllowsZone("az3"), jc.IsTrue)
+	}
+}
+
 func (s *ConstraintsSuite) TestInvalidNetworks(c *gc.C) {
 	invalidNames := []string{
 		"%ne$t", "^net#2", "_", "tcp:ip",
@@ -461,6 +519,11 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"VirtType1", constraints.Value{VirtType: strp("")}},
+	{"VirtType2", constraints.Value{VirtType: strp("kvm")}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxc"),
@@ -471,6 +534,8 @@ var constraintsRoundtripTests = []roundTrip{
 		Tags:         &[]string{"foo", "bar"},
 		Networks:     &[]string{"net1", "^net2"},
 		InstanceType: strp("foo"),
+		VirtType:     strp("metal"),
+		Zones:        &[]string{"az1", "az2"},
 	}},
 }
 
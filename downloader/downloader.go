@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
@@ -41,7 +42,27 @@ func New(url, dir string, hostnameVerification utils.SSLHostnameVerification) *D
 		done:                 make(chan Status),
 		hostnameVerification: hostnameVerification,
 	}
-	go d.run(url, dir)
+	go d.run(url, func() (*os.File, error) {
+		return download(url, dir, hostnameVerification)
+	}, cleanTempFile)
+	return d
+}
+
+// NewResumable returns a new Download instance downloading from the
+// given URL to the file at the given path. If the file already holds
+// the start of the data, left by an earlier download that failed, only
+// the rest of the data is requested. The file is not removed when the
+// download fails or is stopped, so that a later download can resume it;
+// it is the caller's responsibility to remove it when it is no longer
+// wanted.
+func NewResumable(url, path string, hostnameVerification utils.SSLHostnameVerification) *Download {
+	d := &Download{
+		done:                 make(chan Status),
+		hostnameVerification: hostnameVerification,
+	}
+	go d.run(url, func() (*os.File, error) {
+		return resumeDownload(url, path, hostnameVerification)
+	}, closeFile)
 	return d
 }
 
@@ -58,12 +79,15 @@ func (d *Download) Done() <-chan Status {
 	return d.done
 }
 
-func (d *Download) run(url, dir string) {
+// run calls fetch to download from the given url, and sends the result
+// on the done channel. If the download is stopped before the result is
+// received, the downloaded file is passed to cleanup.
+func (d *Download) run(url string, fetch func() (*os.File, error), cleanup func(*os.File)) {
 	defer d.tomb.Done()
 	// TODO(dimitern) 2013-10-03 bug #1234715
 	// Add a testing HTTPS storage to verify the
 	// disableSSLHostnameVerification behavior here.
-	file, err := download(url, dir, d.hostnameVerification)
+	file, err := fetch()
 	if err != nil {
 		err = fmt.Errorf("cannot download %q: %v", url, err)
 	}
@@ -74,7 +98,7 @@ func (d *Download) run(url, dir string) {
 	select {
 	case d.done <- status:
 	case <-d.tomb.Dying():
-		cleanTempFile(status.File)
+		cleanup(status.File)
 	}
 }
 
@@ -111,6 +135,75 @@ func download(url, dir string, hostnameVerification utils.SSLHostnameVerificatio
 	return tempFile, nil
 }
 
+// resumeDownload downloads from the given url to the file at the given
+// path, requesting only the data that follows what the file already
+// holds.
+func resumeDownload(url, path string, hostnameVerification utils.SSLHostnameVerification) (file *os.File, err error) {
+	file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+	offset, err := file.Seek(0, 2)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	client := utils.GetHTTPClient(hostnameVerification)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		contentRange := resp.Header.Get("Content-Range")
+		if !strings.HasPrefix(contentRange, fmt.Sprintf("bytes %d-", offset)) {
+			return nil, fmt.Errorf("unexpected content range %q", contentRange)
+		}
+		logger.Infof("resuming download of %q from byte %d", url, offset)
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The file already holds all the data.
+		if _, err := file.Seek(0, 0); err != nil {
+			return nil, err
+		}
+		return file, nil
+	case resp.StatusCode == http.StatusOK:
+		// The server sent all the data, so start again.
+		if err := file.Truncate(0); err != nil {
+			return nil, err
+		}
+		if _, err := file.Seek(0, 0); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("bad http response: %v", resp.Status)
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func closeFile(f *os.File) {
+	if f != nil {
+		f.Close()
+	}
+}
+
 func cleanTempFile(f *os.File) {
 	if f != nil {
 		f.Close()
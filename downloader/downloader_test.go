@@ -95,6 +95,59 @@ func (s *suite) TestStopDownload(c *gc.C) {
 	c.Assert(infos, gc.HasLen, 0)
 }
 
+func (s *suite) TestResumableDownload(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "archive.partial")
+	err := ioutil.WriteFile(path, []byte("arch"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	gitjujutesting.Server.Response(206, map[string]string{"Content-Range": "bytes 4-6/7"}, []byte("ive"))
+	d := downloader.NewResumable(s.URL("/archive.tgz"), path, utils.VerifySSLHostnames)
+	status := <-d.Done()
+	c.Assert(status.Err, gc.IsNil)
+	defer status.File.Close()
+	c.Assert(status.File.Name(), gc.Equals, path)
+	assertFileContents(c, status.File, "archive")
+	req := gitjujutesting.Server.WaitRequest()
+	c.Assert(req.Header.Get("Range"), gc.Equals, "bytes=4-")
+}
+
+func (s *suite) TestResumableDownloadRestarts(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "archive.partial")
+	err := ioutil.WriteFile(path, []byte("stale"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	gitjujutesting.Server.Response(200, nil, []byte("archive"))
+	d := downloader.NewResumable(s.URL("/archive.tgz"), path, utils.VerifySSLHostnames)
+	status := <-d.Done()
+	c.Assert(status.Err, gc.IsNil)
+	defer status.File.Close()
+	assertFileContents(c, status.File, "archive")
+}
+
+func (s *suite) TestResumableDownloadComplete(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "archive.partial")
+	err := ioutil.WriteFile(path, []byte("archive"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	gitjujutesting.Server.Response(416, nil, nil)
+	d := downloader.NewResumable(s.URL("/archive.tgz"), path, utils.VerifySSLHostnames)
+	status := <-d.Done()
+	c.Assert(status.Err, gc.IsNil)
+	defer status.File.Close()
+	assertFileContents(c, status.File, "archive")
+}
+
+func (s *suite) TestResumableDownloadErrorKeepsFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "archive.partial")
+	err := ioutil.WriteFile(path, []byte("arch"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	gitjujutesting.Server.Response(404, nil, nil)
+	d := downloader.NewResumable(s.URL("/archive.tgz"), path, utils.VerifySSLHostnames)
+	status := <-d.Done()
+	c.Assert(status.File, gc.IsNil)
+	c.Assert(status.Err, gc.ErrorMatches, `cannot download ".*": bad http response: 404 Not Found`)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "arch")
+}
+
 func assertFileContents(c *gc.C, f *os.File, expect string) {
 	got, err := ioutil.ReadAll(f)
 	c.Assert(err, jc.ErrorIsNil)
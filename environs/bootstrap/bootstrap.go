@@ -50,6 +50,15 @@ type BootstrapParams struct {
 	// MetadataDir is an optional path to a local directory containing
 	// tools and/or image metadata.
 	MetadataDir string
+
+	// BootstrapHost, if non-empty, holds the address of an existing
+	// machine to install the state server on over SSH, instead of
+	// starting a new instance.
+	BootstrapHost string
+
+	// Progress, if non-nil, is called as each phase of the bootstrap
+	// process starts, is retried, finishes or fails.
+	Progress ProgressFunc
 }
 
 // Bootstrap bootstraps the given environment. The supplied constraints are
@@ -80,14 +89,16 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	// then verify constraints. Providers may rely on image metadata
 	// for constraint validation.
 	var imageMetadata []*imagemetadata.ImageMetadata
-	if args.MetadataDir != "" {
-		var err error
-		imageMetadata, err = setPrivateMetadataSources(environ, args.MetadataDir)
-		if err != nil {
-			return err
+	if err := RunPhase(args.Progress, PhaseImageSelection, func() error {
+		if args.MetadataDir != "" {
+			var err error
+			imageMetadata, err = setPrivateMetadataSources(environ, args.MetadataDir)
+			if err != nil {
+				return err
+			}
 		}
-	}
-	if err := validateConstraints(environ, args.Constraints); err != nil {
+		return validateConstraints(environ, args.Constraints)
+	}); err != nil {
 		return err
 	}
 
@@ -97,7 +108,12 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 	logger.Debugf("environment %q supports service/machine networks: %v", cfg.Name(), supportsNetworking)
 	disableNetworkManagement, _ := cfg.DisableNetworkManagement()
 	logger.Debugf("network management by juju enabled: %v", !disableNetworkManagement)
-	availableTools, err := findAvailableTools(environ, args.Constraints.Arch, args.UploadTools)
+	var availableTools coretools.List
+	err := RunPhase(args.Progress, PhaseToolsSelection, func() error {
+		var err error
+		availableTools, err = findAvailableTools(environ, args.Constraints.Arch, args.UploadTools)
+		return err
+	})
 	if errors.IsNotFound(err) {
 		return errors.New(noToolsMessage)
 	} else if err != nil {
@@ -118,28 +134,48 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		return err
 	}
 
-	ctx.Infof("Starting new instance for initial state server")
-	arch, series, finalizer, err := environ.Bootstrap(ctx, environs.BootstrapParams{
-		Constraints:    args.Constraints,
-		Placement:      args.Placement,
-		AvailableTools: availableTools,
-	})
-	if err != nil {
-		return err
+	if args.BootstrapHost != "" {
+		ctx.Infof("Using existing machine %s for initial state server", args.BootstrapHost)
+	} else {
+		ctx.Infof("Starting new instance for initial state server")
 	}
-
-	matchingTools, err := availableTools.Match(coretools.Filter{
-		Arch:   arch,
-		Series: series,
-	})
-	if err != nil {
+	var arch, series string
+	var finalizer environs.BootstrapFinalizer
+	if err := RunPhase(args.Progress, PhaseStartInstance, func() error {
+		var err error
+		arch, series, finalizer, err = environ.Bootstrap(ctx, environs.BootstrapParams{
+			Constraints:    args.Constraints,
+			Placement:      args.Placement,
+			AvailableTools: availableTools,
+			BootstrapHost:  args.BootstrapHost,
+		})
 		return err
-	}
-	selectedTools, err := setBootstrapTools(environ, matchingTools)
-	if err != nil {
+	}); err != nil {
 		return err
 	}
-	if selectedTools.URL == "" {
+
+	var selectedTools *coretools.Tools
+	var builtToolsDir string
+	defer func() {
+		if builtToolsDir != "" {
+			os.RemoveAll(builtToolsDir)
+		}
+	}()
+	if err := RunPhase(args.Progress, PhaseToolsUpload, func() error {
+		matchingTools, err := availableTools.Match(coretools.Filter{
+			Arch:   arch,
+			Series: series,
+		})
+		if err != nil {
+			return err
+		}
+		selectedTools, err = setBootstrapTools(environ, matchingTools)
+		if err != nil {
+			return err
+		}
+		if selectedTools.URL != "" {
+			return nil
+		}
 		if !args.UploadTools {
 			logger.Warningf("no prepackaged tools available")
 		}
@@ -148,21 +184,26 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		if err != nil {
 			return errors.Annotate(err, "cannot upload bootstrap tools")
 		}
-		defer os.RemoveAll(builtTools.Dir)
+		builtToolsDir = builtTools.Dir
 		filename := filepath.Join(builtTools.Dir, builtTools.StorageName)
 		selectedTools.URL = fmt.Sprintf("file://%s", filename)
 		selectedTools.Size = builtTools.Size
 		selectedTools.SHA256 = builtTools.Sha256Hash
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	ctx.Infof("Installing Juju agent on bootstrap instance")
-	machineConfig, err := environs.NewBootstrapMachineConfig(args.Constraints, series)
-	if err != nil {
-		return err
-	}
-	machineConfig.Tools = selectedTools
-	machineConfig.CustomImageMetadata = imageMetadata
-	if err := finalizer(ctx, machineConfig); err != nil {
+	if err := RunPhase(args.Progress, PhaseMongoInit, func() error {
+		machineConfig, err := environs.NewBootstrapMachineConfig(args.Constraints, series)
+		if err != nil {
+			return err
+		}
+		machineConfig.Tools = selectedTools
+		machineConfig.CustomImageMetadata = imageMetadata
+		return finalizer(ctx, machineConfig)
+	}); err != nil {
 		return err
 	}
 	ctx.Infof("Bootstrap complete")
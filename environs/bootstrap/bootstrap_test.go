@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
@@ -284,6 +285,95 @@ func (s *bootstrapSuite) TestBootstrapMetadataImagesMissing(c *gc.C) {
 	c.Assert(datasources[0].Description(), gc.Equals, "default cloud images")
 }
 
+func (s *bootstrapSuite) bootstrapWithProgress(c *gc.C, env *bootstrapEnviron) ([]bootstrap.ProgressEvent, error) {
+	var events []bootstrap.ProgressEvent
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		Progress: func(event bootstrap.ProgressEvent) {
+			events = append(events, event)
+		},
+	})
+	return events, err
+}
+
+func (s *bootstrapSuite) TestBootstrapReportsProgress(c *gc.C) {
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	events, err := s.bootstrapWithProgress(c, env)
+	c.Assert(err, jc.ErrorIsNil)
+	var expected []bootstrap.ProgressEvent
+	for _, phase := range []bootstrap.Phase{
+		bootstrap.PhaseImageSelection,
+		bootstrap.PhaseToolsSelection,
+		bootstrap.PhaseStartInstance,
+		bootstrap.PhaseToolsUpload,
+		bootstrap.PhaseMongoInit,
+	} {
+		expected = append(expected,
+			bootstrap.ProgressEvent{Phase: phase, Status: bootstrap.PhaseStarted, Attempt: 1},
+			bootstrap.ProgressEvent{Phase: phase, Status: bootstrap.PhaseFinished, Attempt: 1},
+		)
+	}
+	c.Assert(events, jc.DeepEquals, expected)
+}
+
+func (s *bootstrapSuite) TestBootstrapRetriesTransientErrors(c *gc.C) {
+	s.PatchValue(bootstrap.PhaseRetryStrategy, utils.AttemptStrategy{Min: 3})
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	transientErr := environs.NewStartInstanceError(environs.StartInstanceTransient, errors.New("try again"))
+	env.bootstrapErrors = []error{transientErr}
+	events, err := s.bootstrapWithProgress(c, env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.bootstrapCount, gc.Equals, 2)
+	c.Assert(env.finalizerCount, gc.Equals, 1)
+	c.Assert(events[4:7], jc.DeepEquals, []bootstrap.ProgressEvent{
+		{Phase: bootstrap.PhaseStartInstance, Status: bootstrap.PhaseStarted, Attempt: 1},
+		{Phase: bootstrap.PhaseStartInstance, Status: bootstrap.PhaseRetrying, Attempt: 2, Err: transientErr},
+		{Phase: bootstrap.PhaseStartInstance, Status: bootstrap.PhaseFinished, Attempt: 2},
+	})
+}
+
+func (s *bootstrapSuite) TestBootstrapGivesUpRetrying(c *gc.C) {
+	s.PatchValue(bootstrap.PhaseRetryStrategy, utils.AttemptStrategy{Min: 2})
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	transientErr := environs.NewStartInstanceError(environs.StartInstanceTransient, errors.New("try again"))
+	env.bootstrapErrors = []error{transientErr, transientErr, transientErr}
+	events, err := s.bootstrapWithProgress(c, env)
+	c.Assert(err, gc.ErrorMatches, "try again")
+	c.Assert(env.bootstrapCount, gc.Equals, 2)
+	c.Assert(env.finalizerCount, gc.Equals, 0)
+	c.Assert(events[len(events)-1], jc.DeepEquals, bootstrap.ProgressEvent{
+		Phase:   bootstrap.PhaseStartInstance,
+		Status:  bootstrap.PhaseFailed,
+		Attempt: 2,
+		Err:     transientErr,
+	})
+}
+
+func (s *bootstrapSuite) TestBootstrapDoesNotRetryPermanentErrors(c *gc.C) {
+	s.PatchValue(bootstrap.PhaseRetryStrategy, utils.AttemptStrategy{Min: 3})
+	env := newEnviron("foo", useDefaultKeys, nil)
+	s.setDummyStorage(c, env)
+	env.bootstrapErrors = []error{errors.New("no way")}
+	events, err := s.bootstrapWithProgress(c, env)
+	c.Assert(err, gc.ErrorMatches, "no way")
+	c.Assert(env.bootstrapCount, gc.Equals, 1)
+	c.Assert(events[len(events)-1].Status, gc.Equals, bootstrap.PhaseFailed)
+}
+
+func (s *bootstrapSuite) TestProgressEventString(c *gc.C) {
+	event := bootstrap.ProgressEvent{
+		Phase:   bootstrap.PhaseStartInstance,
+		Status:  bootstrap.PhaseRetrying,
+		Attempt: 2,
+		Err:     errors.New("boom"),
+	}
+	c.Assert(event.String(), gc.Equals, "start-instance retrying (attempt 2): boom")
+	event = bootstrap.ProgressEvent{Phase: bootstrap.PhaseAPIUp, Status: bootstrap.PhaseFinished, Attempt: 1}
+	c.Assert(event.String(), gc.Equals, "api-up finished")
+}
+
 type bootstrapEnviron struct {
 	cfg              *config.Config
 	environs.Environ // stub out all methods we don't care about.
@@ -295,6 +385,10 @@ type bootstrapEnviron struct {
 	args                        environs.BootstrapParams
 	machineConfig               *cloudinit.MachineConfig
 	storage                     storage.Storage
+
+	// bootstrapErrors holds errors returned by successive calls
+	// to Bootstrap.
+	bootstrapErrors []error
 }
 
 func newEnviron(name string, defaultKeys bool, extraAttrs map[string]interface{}) *bootstrapEnviron {
@@ -328,6 +422,13 @@ func (s *bootstrapSuite) setDummyStorage(c *gc.C, env *bootstrapEnviron) {
 func (e *bootstrapEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
 	e.bootstrapCount++
 	e.args = args
+	if len(e.bootstrapErrors) > 0 {
+		err := e.bootstrapErrors[0]
+		e.bootstrapErrors = e.bootstrapErrors[1:]
+		if err != nil {
+			return "", "", nil, err
+		}
+	}
 	finalizer := func(_ environs.BootstrapContext, mcfg *cloudinit.MachineConfig) error {
 		e.finalizerCount++
 		e.machineConfig = mcfg
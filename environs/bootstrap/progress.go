@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs"
+)
+
+// Phase identifies a step of the bootstrap process.
+type Phase string
+
+const (
+	// PhaseImageSelection is the phase in which image metadata
+	// sources are set up and the constraints are validated.
+	PhaseImageSelection Phase = "image-selection"
+
+	// PhaseToolsSelection is the phase in which the tools that the
+	// bootstrap instance may run are found.
+	PhaseToolsSelection Phase = "tools-selection"
+
+	// PhaseStartInstance is the phase in which the bootstrap
+	// instance is started, or the existing bootstrap host is
+	// prepared.
+	PhaseStartInstance Phase = "start-instance"
+
+	// PhaseToolsUpload is the phase in which the tools for the
+	// bootstrap instance are chosen, and built for upload if
+	// necessary.
+	PhaseToolsUpload Phase = "tools-upload"
+
+	// PhaseMongoInit is the phase in which the Juju agent and
+	// the state database are installed on the bootstrap instance.
+	PhaseMongoInit Phase = "mongo-init"
+
+	// PhaseAPIUp is the phase in which the client waits for the
+	// API server on the bootstrap instance to become reachable.
+	PhaseAPIUp Phase = "api-up"
+)
+
+// PhaseStatus describes the progress of a bootstrap phase.
+type PhaseStatus string
+
+const (
+	PhaseStarted  PhaseStatus = "started"
+	PhaseFinished PhaseStatus = "finished"
+	PhaseRetrying PhaseStatus = "retrying"
+	PhaseFailed   PhaseStatus = "failed"
+)
+
+// ProgressEvent is reported to a ProgressFunc whenever a bootstrap
+// phase changes status.
+type ProgressEvent struct {
+	Phase  Phase
+	Status PhaseStatus
+
+	// Attempt holds the number of the attempt at running the
+	// phase, starting at 1.
+	Attempt int
+
+	// Err holds the error that caused the phase to be retried or
+	// to fail; it is nil otherwise.
+	Err error
+}
+
+// String returns a human readable description of the event.
+func (e ProgressEvent) String() string {
+	s := fmt.Sprintf("%s %s", e.Phase, e.Status)
+	if e.Attempt > 1 {
+		s += fmt.Sprintf(" (attempt %d)", e.Attempt)
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// ProgressFunc is called with the events reported while
+// bootstrapping.
+type ProgressFunc func(ProgressEvent)
+
+// phaseRetryStrategy defines how long a phase failing with a
+// transient provider error is retried for.
+var phaseRetryStrategy = utils.AttemptStrategy{
+	Total: time.Minute,
+	Delay: 10 * time.Second,
+}
+
+// isTransientError reports whether the given error is a provider
+// failure that may succeed if the operation is retried.
+func isTransientError(err error) bool {
+	switch environs.StartInstanceErrorKindOf(err) {
+	case environs.StartInstanceTransient, environs.StartInstanceQuotaExceeded:
+		return true
+	}
+	return false
+}
+
+// RunPhase runs f as the given bootstrap phase, reporting its
+// progress to the supplied function, which may be nil. The phase is
+// retried while f fails with a transient provider error.
+func RunPhase(progress ProgressFunc, phase Phase, f func() error) error {
+	report := func(status PhaseStatus, attempt int, err error) {
+		logger.Debugf("bootstrap phase %s %s (attempt %d)", phase, status, attempt)
+		if progress != nil {
+			progress(ProgressEvent{
+				Phase:   phase,
+				Status:  status,
+				Attempt: attempt,
+				Err:     err,
+			})
+		}
+	}
+	var err error
+	attempt := 0
+	for a := phaseRetryStrategy.Start(); a.Next(); {
+		attempt++
+		if attempt == 1 {
+			report(PhaseStarted, attempt, nil)
+		} else {
+			report(PhaseRetrying, attempt, err)
+		}
+		if err = f(); err == nil {
+			report(PhaseFinished, attempt, nil)
+			return nil
+		}
+		if !isTransientError(err) || !a.HasNext() {
+			break
+		}
+		logger.Warningf("bootstrap phase %s failed, retrying: %v", phase, err)
+	}
+	report(PhaseFailed, attempt, err)
+	return err
+}
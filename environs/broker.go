@@ -6,6 +6,7 @@ package environs
 import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
@@ -39,6 +40,14 @@ type StartInstanceParams struct {
 	// high availability.
 	DistributionGroup func() ([]instance.Id, error)
 
+	// AvailabilityZones, if non-empty, holds the names of the
+	// availability zones in which the instance may be started, in
+	// order of preference, as chosen by the provisioner to spread
+	// the distribution group across zones. It is ignored when a
+	// placement directive names a zone; if it is empty, a
+	// ZonedEnviron chooses the zones itself.
+	AvailabilityZones []string
+
 	// Volumes is a set of parameters for volumes that should be created.
 	//
 	// StartInstance need not check the value of the Attachment field,
@@ -49,6 +58,11 @@ type StartInstanceParams struct {
 	// NetworkInfo is an optional list of network interface details,
 	// necessary to configure on the instance.
 	NetworkInfo []network.InterfaceInfo
+
+	// ImageMetadata is a collection of custom image metadata stored
+	// on the controller, which providers may consider along with the
+	// metadata found in simplestreams when choosing an image.
+	ImageMetadata []*imagemetadata.ImageMetadata
 }
 
 // StartInstanceResult holds the result of an
@@ -95,3 +109,26 @@ type InstanceBroker interface {
 	// AllInstances returns all instances currently known to the broker.
 	AllInstances() ([]instance.Instance, error)
 }
+
+// StartInstancesResult holds the outcome of starting one of the
+// instances requested in a BulkInstanceBroker.StartInstances call.
+type StartInstancesResult struct {
+	// Result holds the started instance, if Error is nil.
+	Result *StartInstanceResult
+
+	// Error holds the reason why the instance was not started.
+	Error error
+}
+
+// BulkInstanceBroker is implemented by instance brokers that can start
+// several instances more quickly than by starting them one at a time.
+type BulkInstanceBroker interface {
+	InstanceBroker
+
+	// StartInstances starts an instance for each of the given params,
+	// all of which have the same series and constraints. It returns
+	// the outcome for each instance, in the same order as args;
+	// failing to start one instance does not prevent the others from
+	// being started.
+	StartInstances(args []StartInstanceParams) []StartInstancesResult
+}
@@ -161,6 +161,10 @@ func FinishMachineConfig(mcfg *cloudinit.MachineConfig, cfg *config.Config) (err
 		return errors.Trace(err)
 	}
 
+	if srv := cfg.APIAddressesSRV(); srv != "" {
+		mcfg.AgentEnvironment[agent.APIAddressesSRV] = srv
+	}
+
 	if isStateMachineConfig(mcfg) {
 		// Add NUMACTL preference. Needed to work for both bootstrap and high availability
 		// Only makes sense for state server
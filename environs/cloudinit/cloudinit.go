@@ -230,7 +230,6 @@ func AddAptCommands(
 			// Don't install bridge-utils in cloud-init;
 			// leave it to the networker worker.
 			"bridge-utils",
-			"rsyslog-gnutls",
 			"cloud-utils",
 			"cloud-image-utils",
 		}
@@ -827,7 +827,7 @@ rm "$binDir\tools.tar*"
 Set-Content $binDir\downloaded-tools.txt '{"version":"1.2.3-win8-amd64","url":"http://foo.com/tools/released/juju1.2.3-win8-amd64.tgz","sha256":"1234","size":10}'
 mkdir 'C:\Juju\lib\juju\agents\machine-10'
 Set-Content 'C:/Juju/lib/juju/agents/machine-10/agent.conf' @"
-# format 1.18
+# format 1.24
 tag: machine-10
 datadir: C:/Juju/lib/juju
 logdir: C:/Juju/log/juju
@@ -131,6 +131,27 @@ func (s *CloudInitSuite) TestFinishMachineConfigNonDefault(c *gc.C) {
 	})
 }
 
+func (s *CloudInitSuite) TestFinishMachineConfigAPIAddressesSRV(c *gc.C) {
+	userTag := names.NewLocalUserTag("not-touched")
+	attrs := dummySampleConfig().Merge(testing.Attrs{
+		"authorized-keys":   "we-are-the-keys",
+		"api-addresses-srv": "_juju-api._tcp.example.com",
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	mcfg := &cloudinit.MachineConfig{
+		MongoInfo: &mongo.MongoInfo{Tag: userTag},
+		APIInfo:   &api.Info{Tag: userTag},
+	}
+	err = environs.FinishMachineConfig(mcfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mcfg.AgentEnvironment, jc.DeepEquals, map[string]string{
+		agent.ProviderType:    "dummy",
+		agent.ContainerType:   "",
+		agent.APIAddressesSRV: "_juju-api._tcp.example.com",
+	})
+}
+
 func (s *CloudInitSuite) TestFinishBootstrapConfig(c *gc.C) {
 	attrs := dummySampleConfig().Merge(testing.Attrs{
 		"authorized-keys": "we-are-the-keys",
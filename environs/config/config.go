@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/utils/schedule"
 	"github.com/juju/juju/version"
 )
 
@@ -88,6 +90,33 @@ const (
 	// Only prevent all-changes from running
 	// if user specifically requests it. Otherwise, let them run.
 	DefaultPreventAllChanges = false
+
+	// DefaultHookRetryDelay is the initial amount of time the uniter
+	// waits before automatically retrying a failed hook, in seconds.
+	DefaultHookRetryDelay int = 5
+
+	// DefaultBackupsRetention is the number of scheduled backups
+	// kept when backups-retention is not set.
+	DefaultBackupsRetention int = 7
+
+	// DefaultProvisionerRetryAttempts is the number of times the
+	// provisioner retries starting an instance that failed to start
+	// for a transient reason, when provisioner-retry-attempts is not
+	// set.
+	DefaultProvisionerRetryAttempts int = 3
+
+	// DefaultTxnPruneInterval is the number of seconds between runs of
+	// the transaction pruner when txn-prune-interval is not set.
+	DefaultTxnPruneInterval int = 60 * 60 // 1 hour
+
+	// DefaultTxnPruneMaxAge is the number of seconds for which completed
+	// transactions are kept when txn-prune-max-age is not set.
+	DefaultTxnPruneMaxAge int = 24 * 60 * 60 // 1 day
+
+	// DefaultTxnPruneMaxCollectionMB is the size in megabytes the
+	// transactions collection is pruned down to when
+	// txn-prune-max-collection-mb is not set.
+	DefaultTxnPruneMaxCollectionMB int = 1024 // 1 GB
 )
 
 // TODO(katco-): Please grow this over time.
@@ -155,6 +184,97 @@ const (
 	// allowed by the user.
 	AllowLXCLoopMounts = "allow-lxc-loop-mounts"
 
+	// HookTimeoutKey stores the maximum number of seconds a single
+	// hook execution may take before it is killed.
+	HookTimeoutKey = "hook-timeout"
+
+	// HookRetryAttemptsKey stores the number of times a failed hook
+	// is automatically retried before waiting for user resolution.
+	HookRetryAttemptsKey = "hook-retry-attempts"
+
+	// HookRetryDelayKey stores the initial delay, in seconds, before
+	// a failed hook is automatically retried.
+	HookRetryDelayKey = "hook-retry-delay"
+
+	// RelationDrainTimeoutKey stores the number of seconds for which a
+	// removed relation drains, giving its units' charms a chance to
+	// move traffic elsewhere, before it is broken.
+	RelationDrainTimeoutKey = "relation-drain-timeout"
+
+	// InstancePollShortIntervalKey stores the initial number of seconds
+	// between polls of the provider for the addresses and status of a
+	// machine that is not yet started.
+	InstancePollShortIntervalKey = "instance-poll-short-interval"
+
+	// InstancePollLongIntervalKey stores the number of seconds between
+	// polls of the provider for the addresses and status of a started
+	// machine.
+	InstancePollLongIntervalKey = "instance-poll-long-interval"
+
+	// ProvisionerRetryAttemptsKey stores the number of times the
+	// provisioner retries starting an instance after the provider
+	// fails to start it for a transient reason, such as an exceeded
+	// quota or an API error.
+	ProvisionerRetryAttemptsKey = "provisioner-retry-attempts"
+
+	// BackupsScheduleKey stores the cron-like schedule on which the
+	// state server creates backups automatically.
+	BackupsScheduleKey = "backups-schedule"
+
+	// BackupsRetentionKey stores the number of scheduled backups
+	// kept; older ones are removed.
+	BackupsRetentionKey = "backups-retention"
+
+	// TxnPruneIntervalKey stores the number of seconds between runs of
+	// the transaction pruner.
+	TxnPruneIntervalKey = "txn-prune-interval"
+
+	// TxnPruneMaxAgeKey stores the number of seconds for which
+	// completed transactions are kept before they are pruned.
+	TxnPruneMaxAgeKey = "txn-prune-max-age"
+
+	// TxnPruneMaxCollectionMBKey stores the size in megabytes above
+	// which the oldest completed transactions are pruned, whatever
+	// their age.
+	TxnPruneMaxCollectionMBKey = "txn-prune-max-collection-mb"
+
+	// APIAddressesSRVKey stores the name of the DNS SRV records from
+	// which agents resolve the API server addresses, rather than using
+	// only the addresses held in their configuration.
+	APIAddressesSRVKey = "api-addresses-srv"
+
+	// IdentityURLKey stores the URL of the external identity provider
+	// that authenticates users logging in with macaroons.
+	IdentityURLKey = "identity-url"
+
+	// IdentityPublicKeyKey stores the base64 encoded public key of the
+	// external identity provider.
+	IdentityPublicKeyKey = "identity-public-key"
+
+	// MetricsCollectorURLKey stores the URL of the service to which
+	// the state server forwards the metrics collected from charms.
+	MetricsCollectorURLKey = "metrics-collector-url"
+
+	// SuspendHooksOnRedMeterKey stores whether units whose meter status
+	// is RED stop running hooks, other than meter-status-changed, until
+	// their meter status improves.
+	SuspendHooksOnRedMeterKey = "suspend-hooks-on-red-meter"
+
+	// LogForwardTargetsKey stores a comma-separated list of URLs of
+	// external services to which the state servers forward the logs
+	// of the environment; see ParseLogForwardTargets.
+	LogForwardTargetsKey = "log-forward-targets"
+
+	// LogForwardCACertKey stores the certificate of the CA that signed
+	// the certificates of the log forwarding targets, in PEM format.
+	// If it is not set, the system's root CAs are used.
+	LogForwardCACertKey = "log-forward-ca-cert"
+
+	// SSHProxyHostKey stores the host through which SSH connections to
+	// machines are proxied when proxy-ssh is true, instead of the API
+	// server. It is either a machine id or a [user@]host.
+	SSHProxyHostKey = "ssh-proxy-host"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -589,6 +709,69 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Ensure that the hook timeout and retry settings are sane.
+	for _, attr := range []string{HookTimeoutKey, HookRetryAttemptsKey, HookRetryDelayKey, RelationDrainTimeoutKey, ProvisionerRetryAttemptsKey} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("invalid %s in environment configuration: %d", attr, v)
+		}
+	}
+
+	// Ensure that the instance poll intervals are sane.
+	for _, attr := range []string{InstancePollShortIntervalKey, InstancePollLongIntervalKey} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("invalid %s in environment configuration: %d", attr, v)
+		}
+	}
+	if pollOpts := cfg.InstancePollOpts(); pollOpts.ShortInterval > 0 && pollOpts.LongInterval > 0 &&
+		pollOpts.ShortInterval > pollOpts.LongInterval {
+		return fmt.Errorf("%s must not be greater than %s in environment configuration",
+			InstancePollShortIntervalKey, InstancePollLongIntervalKey)
+	}
+
+	if spec, ok := cfg.defined[BackupsScheduleKey].(string); ok && spec != "" {
+		if _, err := schedule.Parse(spec); err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", BackupsScheduleKey)
+		}
+	}
+	if v, ok := cfg.defined[BackupsRetentionKey].(int); ok && v < 1 {
+		return fmt.Errorf("invalid %s in environment configuration: %d", BackupsRetentionKey, v)
+	}
+	for _, attr := range []string{TxnPruneIntervalKey, TxnPruneMaxAgeKey, TxnPruneMaxCollectionMBKey} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 1 {
+			return fmt.Errorf("invalid %s in environment configuration: %d", attr, v)
+		}
+	}
+	if proxyHost := cfg.SSHProxyHost(); strings.ContainsAny(proxyHost, " \t\n") {
+		return fmt.Errorf("invalid %s in environment configuration: %q", SSHProxyHostKey, proxyHost)
+	}
+	if idURL := cfg.IdentityURL(); idURL != "" {
+		if _, err := url.Parse(idURL); err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", IdentityURLKey)
+		}
+		if cfg.IdentityPublicKey() == "" {
+			return fmt.Errorf("%s must be set with %s in environment configuration", IdentityPublicKeyKey, IdentityURLKey)
+		}
+	}
+
+	if collectorURL := cfg.MetricsCollectorURL(); collectorURL != "" {
+		u, err := url.Parse(collectorURL)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", MetricsCollectorURLKey)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid %s in environment configuration: %q is not an http or https URL", MetricsCollectorURLKey, collectorURL)
+		}
+	}
+
+	if _, err := ParseLogForwardTargets(cfg.asString(LogForwardTargetsKey)); err != nil {
+		return errors.Annotatef(err, "invalid %s in environment configuration", LogForwardTargetsKey)
+	}
+	if caCert := cfg.LogForwardCACert(); caCert != "" {
+		if _, err := cert.ParseCert(caCert); err != nil {
+			return errors.Annotatef(err, "invalid %s in environment configuration", LogForwardCACertKey)
+		}
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -827,7 +1010,8 @@ func (c *Config) AuthorizedKeys() string {
 }
 
 // ProxySSH returns a flag indicating whether SSH commands
-// should be proxied through the API server.
+// should be proxied through the API server, or the host
+// returned by SSHProxyHost if it is set.
 func (c *Config) ProxySSH() bool {
 	value, _ := c.defined["proxy-ssh"].(bool)
 	return value
@@ -932,6 +1116,146 @@ func (c *Config) BootstrapSSHOpts() SSHTimeoutOpts {
 	return opts
 }
 
+// HookRetryOpts returns the options controlling how the uniter times
+// out and automatically retries hooks.
+func (c *Config) HookRetryOpts() HookRetryOpts {
+	opts := HookRetryOpts{
+		Delay: time.Duration(DefaultHookRetryDelay) * time.Second,
+	}
+	if v, ok := c.defined[HookTimeoutKey].(int); ok {
+		opts.Timeout = time.Duration(v) * time.Second
+	}
+	if v, ok := c.defined[HookRetryAttemptsKey].(int); ok {
+		opts.Attempts = v
+	}
+	if v, ok := c.defined[HookRetryDelayKey].(int); ok && v != 0 {
+		opts.Delay = time.Duration(v) * time.Second
+	}
+	return opts
+}
+
+// RelationDrainTimeout returns how long a removed relation drains
+// before it is broken. Relations are broken immediately if it is zero.
+func (c *Config) RelationDrainTimeout() time.Duration {
+	if v, ok := c.defined[RelationDrainTimeoutKey].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return 0
+}
+
+// InstancePollOpts returns the intervals at which the instance poller
+// polls the provider for the addresses and status of machines. A zero
+// interval means the poller's default is used.
+func (c *Config) InstancePollOpts() InstancePollOpts {
+	var opts InstancePollOpts
+	if v, ok := c.defined[InstancePollShortIntervalKey].(int); ok {
+		opts.ShortInterval = time.Duration(v) * time.Second
+	}
+	if v, ok := c.defined[InstancePollLongIntervalKey].(int); ok {
+		opts.LongInterval = time.Duration(v) * time.Second
+	}
+	return opts
+}
+
+// ProvisionerRetryAttempts returns the number of times the provisioner
+// retries starting an instance that failed to start for a transient
+// reason before leaving the machine in an error state.
+func (c *Config) ProvisionerRetryAttempts() int {
+	if v, ok := c.defined[ProvisionerRetryAttemptsKey].(int); ok {
+		return v
+	}
+	return DefaultProvisionerRetryAttempts
+}
+
+// BackupsSchedule returns the cron-like schedule on which backups are
+// created automatically. It is empty if they are not.
+func (c *Config) BackupsSchedule() string {
+	return c.asString(BackupsScheduleKey)
+}
+
+// BackupsRetention returns the number of scheduled backups to keep.
+func (c *Config) BackupsRetention() int {
+	if v, ok := c.defined[BackupsRetentionKey].(int); ok {
+		return v
+	}
+	return DefaultBackupsRetention
+}
+
+// TxnPruneOpts returns how often completed transactions are pruned,
+// and how many of them are kept.
+func (c *Config) TxnPruneOpts() TxnPruneOpts {
+	opts := TxnPruneOpts{
+		Interval:        time.Duration(DefaultTxnPruneInterval) * time.Second,
+		MaxAge:          time.Duration(DefaultTxnPruneMaxAge) * time.Second,
+		MaxCollectionMB: DefaultTxnPruneMaxCollectionMB,
+	}
+	if v, ok := c.defined[TxnPruneIntervalKey].(int); ok {
+		opts.Interval = time.Duration(v) * time.Second
+	}
+	if v, ok := c.defined[TxnPruneMaxAgeKey].(int); ok {
+		opts.MaxAge = time.Duration(v) * time.Second
+	}
+	if v, ok := c.defined[TxnPruneMaxCollectionMBKey].(int); ok {
+		opts.MaxCollectionMB = v
+	}
+	return opts
+}
+
+// APIAddressesSRV returns the name of the DNS SRV records from which
+// agents resolve the API server addresses, or "" if they only use the
+// addresses in their configuration.
+func (c *Config) APIAddressesSRV() string {
+	return c.asString(APIAddressesSRVKey)
+}
+
+// SSHProxyHost returns the machine id or [user@]host through which SSH
+// connections to machines are proxied, or "" if they are proxied
+// through the API server.
+func (c *Config) SSHProxyHost() string {
+	return c.asString(SSHProxyHostKey)
+}
+
+// IdentityURL returns the URL of the external identity provider that
+// authenticates users logging in with macaroons, or "" if users only
+// log in with passwords.
+func (c *Config) IdentityURL() string {
+	return c.asString(IdentityURLKey)
+}
+
+// IdentityPublicKey returns the base64 encoded public key of the
+// external identity provider.
+func (c *Config) IdentityPublicKey() string {
+	return c.asString(IdentityPublicKeyKey)
+}
+
+// MetricsCollectorURL returns the URL of the service to which charm
+// metrics are forwarded, or "" if they are not forwarded.
+func (c *Config) MetricsCollectorURL() string {
+	return c.asString(MetricsCollectorURLKey)
+}
+
+// SuspendHooksOnRedMeter reports whether units whose meter status is
+// RED should stop running hooks other than meter-status-changed.
+func (c *Config) SuspendHooksOnRedMeter() bool {
+	v, _ := c.defined[SuspendHooksOnRedMeterKey].(bool)
+	return v
+}
+
+// LogForwardTargets returns the external services to which the logs of
+// the environment are forwarded.
+func (c *Config) LogForwardTargets() []LogForwardTarget {
+	// The targets are checked by Validate.
+	targets, _ := ParseLogForwardTargets(c.asString(LogForwardTargetsKey))
+	return targets
+}
+
+// LogForwardCACert returns the certificate of the CA that signed the
+// certificates of the log forwarding targets, in PEM format, or "" if
+// the system's root CAs are used.
+func (c *Config) LogForwardCACert() string {
+	return c.asString(LogForwardCACertKey)
+}
+
 // CACert returns the certificate of the CA that signed the state server
 // certificate, in PEM format, and whether the setting is available.
 func (c *Config) CACert() (string, bool) {
@@ -1212,6 +1536,26 @@ var fields = schema.Fields{
 	PreventAllChangesKey:         schema.Bool(),
 	StorageDefaultBlockSourceKey: schema.String(),
 	AllowLXCLoopMounts:           schema.Bool(),
+	HookTimeoutKey:               schema.ForceInt(),
+	HookRetryAttemptsKey:         schema.ForceInt(),
+	HookRetryDelayKey:            schema.ForceInt(),
+	RelationDrainTimeoutKey:      schema.ForceInt(),
+	InstancePollShortIntervalKey: schema.ForceInt(),
+	InstancePollLongIntervalKey:  schema.ForceInt(),
+	ProvisionerRetryAttemptsKey:  schema.ForceInt(),
+	BackupsScheduleKey:           schema.String(),
+	BackupsRetentionKey:          schema.ForceInt(),
+	TxnPruneIntervalKey:          schema.ForceInt(),
+	TxnPruneMaxAgeKey:            schema.ForceInt(),
+	TxnPruneMaxCollectionMBKey:   schema.ForceInt(),
+	APIAddressesSRVKey:           schema.String(),
+	SSHProxyHostKey:              schema.String(),
+	IdentityURLKey:               schema.String(),
+	IdentityPublicKeyKey:         schema.String(),
+	MetricsCollectorURLKey:       schema.String(),
+	SuspendHooksOnRedMeterKey:    schema.Bool(),
+	LogForwardTargetsKey:         schema.String(),
+	LogForwardCACertKey:          schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1255,6 +1599,26 @@ var alwaysOptional = schema.Defaults{
 	AgentStreamKey:               schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
+	HookTimeoutKey:               schema.Omit,
+	HookRetryAttemptsKey:         schema.Omit,
+	HookRetryDelayKey:            schema.Omit,
+	RelationDrainTimeoutKey:      schema.Omit,
+	InstancePollShortIntervalKey: schema.Omit,
+	InstancePollLongIntervalKey:  schema.Omit,
+	ProvisionerRetryAttemptsKey:  schema.Omit,
+	BackupsScheduleKey:           schema.Omit,
+	BackupsRetentionKey:          schema.Omit,
+	TxnPruneIntervalKey:          schema.Omit,
+	TxnPruneMaxAgeKey:            schema.Omit,
+	TxnPruneMaxCollectionMBKey:   schema.Omit,
+	APIAddressesSRVKey:           schema.Omit,
+	SSHProxyHostKey:              schema.Omit,
+	IdentityURLKey:               schema.Omit,
+	IdentityPublicKeyKey:         schema.Omit,
+	MetricsCollectorURLKey:       schema.Omit,
+	SuspendHooksOnRedMeterKey:    schema.Omit,
+	LogForwardTargetsKey:         schema.Omit,
+	LogForwardCACertKey:          schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1427,6 +1791,52 @@ func SpecializeCharmRepo(repo charmrepo.Interface, cfg *Config) charmrepo.Interf
 	return repo
 }
 
+// The charm store channels from which charms can be deployed. Charms
+// in the stable channel are those published for general use; the
+// candidate and edge channels hold charms that are progressively less
+// well tested.
+const (
+	StableChannel    = "stable"
+	CandidateChannel = "candidate"
+	EdgeChannel      = "edge"
+)
+
+// ValidateCharmChannel returns an error if the given charm store
+// channel is not known. An empty channel is taken to mean the stable
+// channel.
+func ValidateCharmChannel(channel string) error {
+	switch channel {
+	case "", StableChannel, CandidateChannel, EdgeChannel:
+		return nil
+	}
+	return errors.NotValidf("charm channel %q", channel)
+}
+
+// CharmRepoForChannel returns a charm repository that serves charms
+// from the given channel of the given charm store. The stable channel
+// is served by the store itself; other channels are only available
+// from stores that support them.
+func CharmRepoForChannel(repo charmrepo.Interface, channel string) (charmrepo.Interface, error) {
+	if err := ValidateCharmChannel(channel); err != nil {
+		return nil, err
+	}
+	if channel == "" || channel == StableChannel {
+		return repo, nil
+	}
+	type specializer interface {
+		WithChannel(channel string) charmrepo.Interface
+	}
+	switch store := repo.(type) {
+	case specializer:
+		return store.WithChannel(channel), nil
+	case *charmrepo.LegacyCharmStore:
+		// The legacy charm store serves each channel other
+		// than stable below its own base URL.
+		return &charmrepo.LegacyCharmStore{BaseURL: store.BaseURL + "/" + channel}, nil
+	}
+	return nil, errors.NotSupportedf("charm channel %q", channel)
+}
+
 // SSHTimeoutOpts lists the amount of time we will wait for various
 // parts of the SSH connection to complete. This is similar to
 // DialOpts, see http://pad.lv/1258889 about possibly deduplicating
@@ -1445,6 +1855,49 @@ type SSHTimeoutOpts struct {
 	AddressesDelay time.Duration
 }
 
+// HookRetryOpts lists the settings the uniter uses to bound hook
+// execution time and to automatically retry failed hooks.
+type HookRetryOpts struct {
+	// Timeout is the amount of time a single hook execution may
+	// take before it is killed. Zero means hooks never time out.
+	Timeout time.Duration
+
+	// Attempts is the number of times a failed hook will be retried
+	// before the unit waits for the error to be resolved manually.
+	Attempts int
+
+	// Delay is the amount of time to wait before the first retry;
+	// it doubles with each subsequent attempt.
+	Delay time.Duration
+}
+
+// InstancePollOpts lists the intervals at which the instance poller
+// polls the provider for the addresses and status of machines.
+type InstancePollOpts struct {
+	// ShortInterval is the initial interval between polls of a
+	// machine that is not started or has no addresses; it backs off
+	// exponentially until it reaches LongInterval.
+	ShortInterval time.Duration
+
+	// LongInterval is the interval between polls of a started
+	// machine with addresses.
+	LongInterval time.Duration
+}
+
+// TxnPruneOpts lists how the transaction pruner prunes completed
+// transactions.
+type TxnPruneOpts struct {
+	// Interval is the time between runs of the pruner.
+	Interval time.Duration
+
+	// MaxAge is how long completed transactions are kept.
+	MaxAge time.Duration
+
+	// MaxCollectionMB is the size in megabytes the transactions
+	// collection is pruned down to, oldest transactions first.
+	MaxCollectionMB int
+}
+
 func addIfNotEmpty(settings map[string]interface{}, key, value string) {
 	if value != "" {
 		settings[key] = value
@@ -11,6 +11,7 @@ import (
 	stdtesting "testing"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/schema"
 	gitjujutesting "github.com/juju/testing"
@@ -763,6 +764,255 @@ var configTests = []configTest{
 			"bootstrap-addresses-delay": "illegal",
 		},
 		err: `bootstrap-addresses-delay: expected number, got string\("illegal"\)`,
+	}, {
+		about:       "Explicit hook retry settings",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"hook-timeout":        1800,
+			"hook-retry-attempts": 3,
+			"hook-retry-delay":    10,
+		},
+	}, {
+		about:       "Invalid hook timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"hook-timeout": "illegal",
+		},
+		err: `hook-timeout: expected number, got string\("illegal"\)`,
+	}, {
+		about:       "Negative hook retry attempts",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"hook-retry-attempts": -1,
+		},
+		err: `invalid hook-retry-attempts in environment configuration: -1`,
+	}, {
+		about:       "Explicit relation drain timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"relation-drain-timeout": 300,
+		},
+	}, {
+		about:       "Negative relation drain timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"relation-drain-timeout": -1,
+		},
+		err: `invalid relation-drain-timeout in environment configuration: -1`,
+	}, {
+		about:       "Explicit instance poll intervals",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                         "my-type",
+			"name":                         "my-name",
+			"instance-poll-short-interval": 5,
+			"instance-poll-long-interval":  3600,
+		},
+	}, {
+		about:       "Negative instance poll interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"instance-poll-long-interval": -1,
+		},
+		err: `invalid instance-poll-long-interval in environment configuration: -1`,
+	}, {
+		about:       "Explicit provisioner retry attempts",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-retry-attempts": 0,
+		},
+	}, {
+		about:       "Negative provisioner retry attempts",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-retry-attempts": -1,
+		},
+		err: `invalid provisioner-retry-attempts in environment configuration: -1`,
+	}, {
+		about:       "Short instance poll interval longer than long interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                         "my-type",
+			"name":                         "my-name",
+			"instance-poll-short-interval": 600,
+			"instance-poll-long-interval":  60,
+		},
+		err: `instance-poll-short-interval must not be greater than instance-poll-long-interval in environment configuration`,
+	}, {
+		about:       "Explicit backups schedule",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"backups-schedule":  "@daily",
+			"backups-retention": 3,
+		},
+	}, {
+		about:       "Explicit API addresses SRV name",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"api-addresses-srv": "_juju-api._tcp.example.com",
+		},
+	}, {
+		about:       "Explicit SSH proxy host",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"ssh-proxy-host": "ubuntu@10.0.0.5",
+		},
+	}, {
+		about:       "Invalid SSH proxy host",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"ssh-proxy-host": "bastion host",
+		},
+		err: `invalid ssh-proxy-host in environment configuration: "bastion host"`,
+	}, {
+		about:       "Explicit identity provider",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "https://identity.example.com",
+			"identity-public-key": "CIdWcEUN+0OZnKW9KwruRQnQDY/qqzVdD30CijwiWCk=",
+		},
+	}, {
+		about:       "Identity provider without public key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"identity-url": "https://identity.example.com",
+		},
+		err: `identity-public-key must be set with identity-url in environment configuration`,
+	}, {
+		about:       "Explicit metrics collector",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"metrics-collector-url": "https://metrics.example.com/v1/metrics",
+		},
+	}, {
+		about:       "Suspend hooks on red meter status",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"suspend-hooks-on-red-meter": true,
+		},
+	}, {
+		about:       "Invalid metrics collector",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"metrics-collector-url": "metrics.example.com",
+		},
+		err: `invalid metrics-collector-url in environment configuration: "metrics.example.com" is not an http or https URL`,
+	}, {
+		about:       "Explicit log forwarding targets",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-targets": "syslog+tls://logs.example.com?level=WARNING,https://elk.example.com/juju",
+			"log-forward-ca-cert": testing.CACert,
+		},
+	}, {
+		about:       "Invalid log forwarding target",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-targets": "udp://logs.example.com",
+		},
+		err: `invalid log-forward-targets in environment configuration: invalid log forwarding target "udp://logs.example.com": unsupported scheme "udp"`,
+	}, {
+		about:       "Invalid log forwarding CA certificate",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"log-forward-ca-cert": "foo",
+		},
+		err: `invalid log-forward-ca-cert in environment configuration: .*`,
+	}, {
+		about:       "Invalid backups schedule",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"backups-schedule": "daily",
+		},
+		err: `invalid backups-schedule in environment configuration: invalid schedule "daily": expected 5 fields, got 1`,
+	}, {
+		about:       "Invalid backups retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"backups-retention": 0,
+		},
+		err: `invalid backups-retention in environment configuration: 0`,
+	}, {
+		about:       "Explicit transaction pruning",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"txn-prune-interval":          600,
+			"txn-prune-max-age":           3600,
+			"txn-prune-max-collection-mb": 256,
+		},
+	}, {
+		about:       "Invalid transaction pruning interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"txn-prune-interval": 0,
+		},
+		err: `invalid txn-prune-interval in environment configuration: 0`,
+	}, {
+		about:       "Negative transaction pruning max age",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"txn-prune-max-age": -1,
+		},
+		err: `invalid txn-prune-max-age in environment configuration: -1`,
+	}, {
+		about:       "Invalid transaction pruning collection size",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"txn-prune-max-collection-mb": 0,
+		},
+		err: `invalid txn-prune-max-collection-mb in environment configuration: 0`,
 	}, {
 		about:       "Invalid logging configuration",
 		useDefaults: config.UseDefaults,
@@ -1215,6 +1465,80 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		config.DefaultBootstrapSSHAddressesDelay,
 	)
 
+	hookOpts := cfg.HookRetryOpts()
+	test.assertDuration(c, "hook-timeout", hookOpts.Timeout, 0)
+	test.assertDuration(c, "hook-retry-delay", hookOpts.Delay, config.DefaultHookRetryDelay)
+	if v, ok := test.attrs["hook-retry-attempts"]; ok {
+		c.Assert(hookOpts.Attempts, gc.Equals, v)
+	} else {
+		c.Assert(hookOpts.Attempts, gc.Equals, 0)
+	}
+
+	test.assertDuration(c, "relation-drain-timeout", cfg.RelationDrainTimeout(), 0)
+
+	pollOpts := cfg.InstancePollOpts()
+	test.assertDuration(c, "instance-poll-short-interval", pollOpts.ShortInterval, 0)
+	test.assertDuration(c, "instance-poll-long-interval", pollOpts.LongInterval, 0)
+
+	if v, ok := test.attrs["provisioner-retry-attempts"]; ok {
+		c.Assert(cfg.ProvisionerRetryAttempts(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.ProvisionerRetryAttempts(), gc.Equals, config.DefaultProvisionerRetryAttempts)
+	}
+
+	if v, ok := test.attrs["backups-schedule"]; ok {
+		c.Assert(cfg.BackupsSchedule(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.BackupsSchedule(), gc.Equals, "")
+	}
+	if v, ok := test.attrs["backups-retention"]; ok {
+		c.Assert(cfg.BackupsRetention(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.BackupsRetention(), gc.Equals, config.DefaultBackupsRetention)
+	}
+	txnPruneOpts := cfg.TxnPruneOpts()
+	test.assertDuration(c, "txn-prune-interval", txnPruneOpts.Interval, config.DefaultTxnPruneInterval)
+	test.assertDuration(c, "txn-prune-max-age", txnPruneOpts.MaxAge, config.DefaultTxnPruneMaxAge)
+	if v, ok := test.attrs["txn-prune-max-collection-mb"]; ok {
+		c.Assert(txnPruneOpts.MaxCollectionMB, gc.Equals, v)
+	} else {
+		c.Assert(txnPruneOpts.MaxCollectionMB, gc.Equals, config.DefaultTxnPruneMaxCollectionMB)
+	}
+	if v, ok := test.attrs["identity-url"]; ok {
+		c.Assert(cfg.IdentityURL(), gc.Equals, v)
+		c.Assert(cfg.IdentityPublicKey(), gc.Equals, test.attrs["identity-public-key"])
+	} else {
+		c.Assert(cfg.IdentityURL(), gc.Equals, "")
+	}
+	if v, ok := test.attrs["suspend-hooks-on-red-meter"]; ok {
+		c.Assert(cfg.SuspendHooksOnRedMeter(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.SuspendHooksOnRedMeter(), jc.IsFalse)
+	}
+	if v, ok := test.attrs["log-forward-targets"]; ok {
+		targets, err := config.ParseLogForwardTargets(v.(string))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(cfg.LogForwardTargets(), jc.DeepEquals, targets)
+		c.Assert(cfg.LogForwardCACert(), gc.Equals, test.attrs["log-forward-ca-cert"])
+	} else {
+		c.Assert(cfg.LogForwardTargets(), gc.HasLen, 0)
+	}
+	if v, ok := test.attrs["metrics-collector-url"]; ok {
+		c.Assert(cfg.MetricsCollectorURL(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MetricsCollectorURL(), gc.Equals, "")
+	}
+	if v, ok := test.attrs["api-addresses-srv"]; ok {
+		c.Assert(cfg.APIAddressesSRV(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.APIAddressesSRV(), gc.Equals, "")
+	}
+	if v, ok := test.attrs["ssh-proxy-host"]; ok {
+		c.Assert(cfg.SSHProxyHost(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.SSHProxyHost(), gc.Equals, "")
+	}
+
 	if v, ok := test.attrs["image-stream"]; ok {
 		c.Assert(cfg.ImageStream(), gc.Equals, v)
 	} else {
@@ -1814,6 +2138,52 @@ func (s *specializedCharmRepo) WithTestMode(testMode bool) charmrepo.Interface {
 	return s
 }
 
+func (s *specializedCharmRepo) WithChannel(channel string) charmrepo.Interface {
+	return &channelCharmRepo{s.LegacyCharmStore, channel}
+}
+
+type channelCharmRepo struct {
+	*charmrepo.LegacyCharmStore
+	channel string
+}
+
+func (s *ConfigSuite) TestValidateCharmChannel(c *gc.C) {
+	for _, channel := range []string{"", "stable", "candidate", "edge"} {
+		c.Check(config.ValidateCharmChannel(channel), jc.ErrorIsNil)
+	}
+	err := config.ValidateCharmChannel("beta")
+	c.Assert(err, gc.ErrorMatches, `charm channel "beta" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ConfigSuite) TestCharmRepoForChannel(c *gc.C) {
+	store := &specializedCharmRepo{}
+	for _, channel := range []string{"", "stable"} {
+		repo, err := config.CharmRepoForChannel(store, channel)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(repo, gc.Equals, store)
+	}
+	repo, err := config.CharmRepoForChannel(store, "edge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(repo.(*channelCharmRepo).channel, gc.Equals, "edge")
+
+	_, err = config.CharmRepoForChannel(store, "beta")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ConfigSuite) TestCharmRepoForChannelLegacyStore(c *gc.C) {
+	store := &charmrepo.LegacyCharmStore{BaseURL: "https://store.example.com"}
+	repo, err := config.CharmRepoForChannel(store, "candidate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(repo.(*charmrepo.LegacyCharmStore).BaseURL, gc.Equals, "https://store.example.com/candidate")
+}
+
+func (s *ConfigSuite) TestCharmRepoForChannelNotSupported(c *gc.C) {
+	_, err := config.CharmRepoForChannel(&charmrepo.LocalRepository{}, "edge")
+	c.Assert(err, gc.ErrorMatches, `charm channel "edge" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *ConfigSuite) TestLastestLtsSeriesFallback(c *gc.C) {
 	config.ResetCachedLtsSeries()
 	s.PatchValue(config.DistroLtsSeries, func() (string, error) {
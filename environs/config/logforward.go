@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+const (
+	// LogForwardSyslogScheme is the URL scheme of log forwarding
+	// targets that receive syslog messages over TLS.
+	LogForwardSyslogScheme = "syslog+tls"
+
+	// DefaultLogForwardSyslogPort is the port of syslog log forwarding
+	// targets whose URL does not specify one.
+	DefaultLogForwardSyslogPort = "6514"
+)
+
+// LogForwardTarget describes an external service to which the state
+// servers forward the logs of the environment.
+type LogForwardTarget struct {
+	// URL holds the URL of the target, without the filter parameters.
+	// Its scheme is "syslog+tls" for a syslog server, which receives
+	// RFC 5424 messages over TLS, or "http" or "https" for an
+	// endpoint to which batches of records are posted as
+	// newline-delimited JSON documents.
+	URL *url.URL
+
+	// MinLevel holds the lowest level of the records forwarded,
+	// taken from the "level" parameter.
+	MinLevel loggo.Level
+
+	// IncludeEntity and ExcludeEntity hold the entity tags whose
+	// records are, or are not, forwarded, taken from the "entity" and
+	// "exclude-entity" parameters. Tags may contain '*' wildcards.
+	IncludeEntity []string
+	ExcludeEntity []string
+
+	// IncludeModule and ExcludeModule hold the logging modules whose
+	// records are, or are not, forwarded, taken from the "module" and
+	// "exclude-module" parameters.
+	IncludeModule []string
+	ExcludeModule []string
+}
+
+// ParseLogForwardTargets parses a comma-separated list of log
+// forwarding target URLs, such as
+//
+//	syslog+tls://logs.example.com:6514?level=WARNING,https://elk.example.com/juju?entity=unit-mysql-*
+//
+// Filter parameters may be repeated; any other parameters of http
+// and https URLs are left in place.
+func ParseLogForwardTargets(value string) ([]LogForwardTarget, error) {
+	var targets []LogForwardTarget
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		target, err := parseLogForwardTarget(s)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid log forwarding target %q", s)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func parseLogForwardTarget(s string) (LogForwardTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return LogForwardTarget{}, errors.Trace(err)
+	}
+	if u.Host == "" {
+		return LogForwardTarget{}, errors.New("no host specified")
+	}
+	query := u.Query()
+	target := LogForwardTarget{
+		IncludeEntity: query["entity"],
+		ExcludeEntity: query["exclude-entity"],
+		IncludeModule: query["module"],
+		ExcludeModule: query["exclude-module"],
+	}
+	if level := query.Get("level"); level != "" {
+		var ok bool
+		if target.MinLevel, ok = loggo.ParseLevel(level); !ok {
+			return LogForwardTarget{}, errors.Errorf("unknown level %q", level)
+		}
+	}
+	for _, key := range []string{"level", "entity", "exclude-entity", "module", "exclude-module"} {
+		delete(query, key)
+	}
+	switch u.Scheme {
+	case LogForwardSyslogScheme:
+		if len(query) > 0 || u.Path != "" {
+			return LogForwardTarget{}, errors.New("syslog targets take no path or parameters other than filters")
+		}
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			u.Host = net.JoinHostPort(u.Host, DefaultLogForwardSyslogPort)
+		}
+	case "http", "https":
+	default:
+		return LogForwardTarget{}, errors.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	u.RawQuery = query.Encode()
+	target.URL = u
+	return target, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type LogForwardSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&LogForwardSuite{})
+
+func (*LogForwardSuite) TestParseLogForwardTargets(c *gc.C) {
+	targets, err := config.ParseLogForwardTargets(
+		"syslog+tls://logs.example.com?level=WARNING&entity=unit-mysql-*&entity=machine-0, " +
+			"https://elk.example.com:9200/juju?pipeline=juju&module=juju.worker&exclude-module=juju.worker.uniter&exclude-entity=unit-mysql-1,",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(targets, gc.HasLen, 2)
+
+	c.Check(targets[0].URL.String(), gc.Equals, "syslog+tls://logs.example.com:6514")
+	c.Check(targets[0].MinLevel, gc.Equals, loggo.WARNING)
+	c.Check(targets[0].IncludeEntity, jc.DeepEquals, []string{"unit-mysql-*", "machine-0"})
+	c.Check(targets[0].ExcludeEntity, gc.HasLen, 0)
+
+	c.Check(targets[1].URL.String(), gc.Equals, "https://elk.example.com:9200/juju?pipeline=juju")
+	c.Check(targets[1].MinLevel, gc.Equals, loggo.UNSPECIFIED)
+	c.Check(targets[1].IncludeModule, jc.DeepEquals, []string{"juju.worker"})
+	c.Check(targets[1].ExcludeModule, jc.DeepEquals, []string{"juju.worker.uniter"})
+	c.Check(targets[1].ExcludeEntity, jc.DeepEquals, []string{"unit-mysql-1"})
+}
+
+func (*LogForwardSuite) TestParseLogForwardTargetsEmpty(c *gc.C) {
+	targets, err := config.ParseLogForwardTargets("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(targets, gc.HasLen, 0)
+}
+
+func (*LogForwardSuite) TestParseLogForwardTargetsErrors(c *gc.C) {
+	for i, test := range []struct {
+		value    string
+		errMatch string
+	}{{
+		value:    "udp://logs.example.com",
+		errMatch: `invalid log forwarding target "udp://logs.example.com": unsupported scheme "udp"`,
+	}, {
+		value:    "https:///juju",
+		errMatch: `invalid log forwarding target "https:///juju": no host specified`,
+	}, {
+		value:    "syslog+tls://logs.example.com?level=LOUD",
+		errMatch: `invalid log forwarding target ".*": unknown level "LOUD"`,
+	}, {
+		value:    "syslog+tls://logs.example.com/juju",
+		errMatch: `invalid log forwarding target ".*": syslog targets take no path or parameters other than filters`,
+	}} {
+		c.Logf("test %d: %s", i, test.value)
+		_, err := config.ParseLogForwardTargets(test.value)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// ControllersFile represents the YAML structure of the file
+// $JUJU_HOME/environments/controllers.yaml.
+type ControllersFile struct {
+	// Controllers maps the local name of each controller to its details.
+	Controllers map[string]ControllerDetails `yaml:"controllers"`
+}
+
+// ControllerDetails holds the details needed to connect to the API
+// servers running in a controller, the state server environment of a
+// Juju Environment Server.
+type ControllerDetails struct {
+	// ServerUUID is the UUID of the controller's state server
+	// environment.
+	ServerUUID      string                 `yaml:"uuid"`
+	APIEndpoints    []string               `yaml:"api-endpoints"`
+	ServerHostnames []string               `yaml:"server-hostnames,omitempty"`
+	CACert          string                 `yaml:"ca-cert"`
+	BootstrapConfig map[string]interface{} `yaml:"bootstrap-config,omitempty"`
+}
+
+// AccountsFile represents the YAML structure of the file
+// $JUJU_HOME/environments/accounts.yaml.
+type AccountsFile struct {
+	// Accounts maps the local name of each controller to the accounts
+	// of its users, keyed by full user name.
+	Accounts map[string]map[string]AccountDetails `yaml:"accounts"`
+}
+
+// AccountDetails holds the credentials of a user on a controller.
+type AccountDetails struct {
+	Password string `yaml:"password"`
+}
+
+// EnvironmentsFile represents the YAML structure of the file
+// $JUJU_HOME/environments/environments.yaml.
+type EnvironmentsFile struct {
+	// Environments maps the local name of each environment to its
+	// details.
+	Environments map[string]EnvironmentDetails `yaml:"environments"`
+}
+
+// EnvironmentDetails holds the details of a single environment running
+// in a controller.
+type EnvironmentDetails struct {
+	// Controller is the local name of the controller running the
+	// environment.
+	Controller  string `yaml:"controller"`
+	EnvironUUID string `yaml:"uuid"`
+	// User is the full name of the user the environment is accessed as.
+	User string `yaml:"user"`
+}
+
+const (
+	controllersFile  = "controllers.yaml"
+	accountsFile     = "accounts.yaml"
+	environmentsFile = "environments.yaml"
+
+	// legacyCacheFile is the single file that held the controllers,
+	// accounts and environments before they were split apart.
+	legacyCacheFile = "cache.yaml"
+)
+
+func controllersFilename(dir string) string {
+	return filepath.Join(dir, controllersFile)
+}
+
+func accountsFilename(dir string) string {
+	return filepath.Join(dir, accountsFile)
+}
+
+func environmentsFilename(dir string) string {
+	return filepath.Join(dir, environmentsFile)
+}
+
+// clientFiles holds the contents of the controllers, accounts and
+// environments files. All synchronisation locking is expected to be
+// done outside the read and write methods.
+type clientFiles struct {
+	controllers  ControllersFile
+	accounts     AccountsFile
+	environments EnvironmentsFile
+}
+
+// readClientFiles reads the controllers, accounts and environments
+// files found in the given directory. If none of them exists yet but a
+// legacy cache file does, its contents are returned instead; they are
+// moved to the new files on the next write.
+func readClientFiles(dir string) (*clientFiles, error) {
+	files := &clientFiles{}
+	found, err := readYAMLFile(controllersFilename(dir), &files.controllers)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !found {
+		legacy, err := readLegacyCacheFile(filepath.Join(dir, legacyCacheFile))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if legacy != nil {
+			return legacy.clientFiles(), nil
+		}
+	}
+	if _, err := readYAMLFile(accountsFilename(dir), &files.accounts); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := readYAMLFile(environmentsFilename(dir), &files.environments); err != nil {
+		return nil, errors.Trace(err)
+	}
+	files.init()
+	return files, nil
+}
+
+// init makes sure that all the maps are usable.
+func (files *clientFiles) init() {
+	if files.controllers.Controllers == nil {
+		files.controllers.Controllers = make(map[string]ControllerDetails)
+	}
+	if files.accounts.Accounts == nil {
+		files.accounts.Accounts = make(map[string]map[string]AccountDetails)
+	}
+	if files.environments.Environments == nil {
+		files.environments.Environments = make(map[string]EnvironmentDetails)
+	}
+}
+
+// write writes the controllers, accounts and environments files to the
+// given directory, removing any legacy cache file.
+func (files *clientFiles) write(dir string) error {
+	if err := writeYAMLFile(controllersFilename(dir), files.controllers); err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeYAMLFile(accountsFilename(dir), files.accounts); err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeYAMLFile(environmentsFilename(dir), files.environments); err != nil {
+		return errors.Trace(err)
+	}
+	err := os.Remove(filepath.Join(dir, legacyCacheFile))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// readYAMLFile unmarshals the contents of the named file into content,
+// reporting whether the file exists.
+func readYAMLFile(filename string, content interface{}) (bool, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err := goyaml.Unmarshal(data, content); err != nil {
+		return false, errors.Annotatef(err, "error unmarshalling %q", filename)
+	}
+	return true, nil
+}
+
+func writeYAMLFile(filename string, content interface{}) error {
+	data, err := goyaml.Marshal(content)
+	if err != nil {
+		return errors.Annotatef(err, "cannot marshal %q", filepath.Base(filename))
+	}
+	err = ioutil.WriteFile(filename, data, 0600)
+	return errors.Annotate(err, "cannot write file")
+}
+
+// controllerNames returns the sorted names of the controllers.
+func (files *clientFiles) controllerNames() []string {
+	var names []string
+	for name := range files.controllers.Controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// controllerByUUID returns the name of a controller with the given
+// server UUID.
+func (files *clientFiles) controllerByUUID(serverUUID string) (string, bool) {
+	for _, name := range files.controllerNames() {
+		if files.controllers.Controllers[name].ServerUUID == serverUUID {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// environmentNames returns the sorted names of the environments running
+// in the named controller.
+func (files *clientFiles) environmentNames(controllerName string) []string {
+	var names []string
+	for name, env := range files.environments.Environments {
+		if env.Controller == controllerName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (files *clientFiles) readInfo(envName string) (*environInfo, error) {
+	envDetails, ok := files.environments.Environments[envName]
+	if !ok {
+		return nil, errors.NotFoundf("environment %q", envName)
+	}
+	controller, ok := files.controllers.Controllers[envDetails.Controller]
+	if !ok {
+		return nil, errors.Errorf("missing controller %q for environment %q", envDetails.Controller, envName)
+	}
+	info := &environInfo{
+		name:            envName,
+		source:          sourceControllers,
+		user:            envDetails.User,
+		credentials:     files.accounts.Accounts[envDetails.Controller][envDetails.User].Password,
+		environmentUUID: envDetails.EnvironUUID,
+		serverUUID:      controller.ServerUUID,
+		caCert:          controller.CACert,
+		apiEndpoints:    controller.APIEndpoints,
+		apiHostnames:    controller.ServerHostnames,
+	}
+	if info.serverUUID == info.environmentUUID {
+		info.bootstrapConfig = controller.BootstrapConfig
+	}
+	return info, nil
+}
+
+func (files *clientFiles) updateInfo(info *environInfo) error {
+	// If the info is new, then check for name clashes.
+	envDetails, found := files.environments.Environments[info.name]
+	if info.source == sourceCreated && found {
+		return ErrEnvironInfoAlreadyExists
+	}
+
+	// Find the controller the environment runs in. A new controller is
+	// named after its state server environment; when the state server
+	// environment isn't known locally, the server UUID is used instead.
+	controllerName := envDetails.Controller
+	controller, found := files.controllers.Controllers[controllerName]
+	if !found || controller.ServerUUID != info.serverUUID {
+		var ok bool
+		controllerName, ok = files.controllerByUUID(info.serverUUID)
+		if !ok {
+			controllerName = info.serverUUID
+			if info.environmentUUID == info.serverUUID {
+				controllerName = info.name
+			}
+		}
+		controller = files.controllers.Controllers[controllerName]
+	}
+	controller.ServerUUID = info.serverUUID
+	controller.APIEndpoints = info.apiEndpoints
+	controller.ServerHostnames = info.apiHostnames
+	controller.CACert = info.caCert
+	if info.bootstrapConfig != nil {
+		controller.BootstrapConfig = info.bootstrapConfig
+	}
+	files.controllers.Controllers[controllerName] = controller
+
+	accounts := files.accounts.Accounts[controllerName]
+	if accounts == nil {
+		accounts = make(map[string]AccountDetails)
+		files.accounts.Accounts[controllerName] = accounts
+	}
+	accounts[info.user] = AccountDetails{Password: info.credentials}
+
+	oldController := envDetails.Controller
+	files.environments.Environments[info.name] = EnvironmentDetails{
+		Controller:  controllerName,
+		EnvironUUID: info.environmentUUID,
+		User:        info.user,
+	}
+	if oldController != "" && oldController != controllerName {
+		files.removeUnusedController(oldController)
+	}
+	return nil
+}
+
+func (files *clientFiles) removeInfo(info *environInfo) error {
+	envDetails, found := files.environments.Environments[info.name]
+	if !found {
+		return errors.New("environment info has already been removed")
+	}
+	delete(files.environments.Environments, info.name)
+	files.removeUnusedController(envDetails.Controller)
+	return nil
+}
+
+// removeUnusedController removes the details and accounts of the named
+// controller when no environment refers to it any more.
+func (files *clientFiles) removeUnusedController(controllerName string) {
+	if len(files.environmentNames(controllerName)) > 0 {
+		return
+	}
+	delete(files.controllers.Controllers, controllerName)
+	delete(files.accounts.Accounts, controllerName)
+}
+
+// legacyCache represents the YAML structure of the legacy file
+// $JUJU_HOME/environments/cache.yaml.
+type legacyCache struct {
+	Server map[string]struct {
+		ServerUUID string `yaml:"server-uuid"`
+		User       string `yaml:"user"`
+	} `yaml:"server-user"`
+	ServerData map[string]struct {
+		APIEndpoints    []string               `yaml:"api-endpoints"`
+		ServerHostnames []string               `yaml:"server-hostnames,omitempty"`
+		CACert          string                 `yaml:"ca-cert"`
+		Identities      map[string]string      `yaml:"identities"`
+		BootstrapConfig map[string]interface{} `yaml:"bootstrap-config,omitempty"`
+	} `yaml:"server-data"`
+	Environment map[string]struct {
+		User            string `yaml:"user"`
+		EnvironmentUUID string `yaml:"env-uuid"`
+		ServerUUID      string `yaml:"server-uuid"`
+	} `yaml:"environment"`
+}
+
+// readLegacyCacheFile returns the contents of the legacy cache file,
+// or nil if it does not exist.
+func readLegacyCacheFile(filename string) (*legacyCache, error) {
+	var cache legacyCache
+	found, err := readYAMLFile(filename, &cache)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// clientFiles converts the legacy cache to controllers, accounts and
+// environments. Each server is named after its state server
+// environment, or after its UUID if that is not known.
+func (cache *legacyCache) clientFiles() *clientFiles {
+	files := &clientFiles{}
+	files.init()
+	serverNames := make(map[string]string)
+	var names []string
+	for name := range cache.Server {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		serverUUID := cache.Server[name].ServerUUID
+		if _, ok := serverNames[serverUUID]; !ok {
+			serverNames[serverUUID] = name
+		}
+	}
+	for serverUUID, data := range cache.ServerData {
+		name, ok := serverNames[serverUUID]
+		if !ok {
+			name = serverUUID
+			serverNames[serverUUID] = name
+		}
+		files.controllers.Controllers[name] = ControllerDetails{
+			ServerUUID:      serverUUID,
+			APIEndpoints:    data.APIEndpoints,
+			ServerHostnames: data.ServerHostnames,
+			CACert:          data.CACert,
+			BootstrapConfig: data.BootstrapConfig,
+		}
+		accounts := make(map[string]AccountDetails)
+		for user, password := range data.Identities {
+			accounts[user] = AccountDetails{Password: password}
+		}
+		files.accounts.Accounts[name] = accounts
+	}
+	for name, env := range cache.Environment {
+		controllerName, ok := serverNames[env.ServerUUID]
+		if !ok {
+			continue
+		}
+		files.environments.Environments[name] = EnvironmentDetails{
+			Controller:  controllerName,
+			EnvironUUID: env.EnvironmentUUID,
+			User:        env.User,
+		}
+	}
+	return files
+}
@@ -0,0 +1,360 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configstore_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/feature"
+)
+
+var _ = gc.Suite(&controllersFileInterfaceSuite{})
+
+type controllersFileInterfaceSuite struct {
+	interfaceSuite
+	dir   string
+	store configstore.Storage
+}
+
+func (s *controllersFileInterfaceSuite) SetUpTest(c *gc.C) {
+	s.interfaceSuite.SetUpTest(c)
+	s.SetFeatureFlags(feature.EnvironmentsCacheFile)
+	s.dir = c.MkDir()
+	s.NewStore = func(c *gc.C) configstore.Storage {
+		store, err := configstore.NewDisk(s.dir)
+		c.Assert(err, jc.ErrorIsNil)
+		return store
+	}
+	s.store = s.NewStore(c)
+}
+
+func (s *controllersFileInterfaceSuite) writeEnv(c *gc.C, name, envUUID, srvUUID, user, password string) configstore.EnvironInfo {
+	info := s.store.CreateInfo(name)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"address1", "address2"},
+		Hostnames:   []string{"hostname1", "hostname2"},
+		CACert:      testing.CACert,
+		EnvironUUID: envUUID,
+		ServerUUID:  srvUUID,
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     user,
+		Password: password,
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	return info
+}
+
+func (s *controllersFileInterfaceSuite) TestServerUUIDWrite(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	info := s.writeEnv(c, "testing", envUUID, envUUID, "tester", "secret")
+
+	// Now make sure the controllers files exist and the jenv doesn't
+	envDir := filepath.Join(s.dir, "environments")
+	filename := configstore.EnvironmentsFilename(envDir)
+	c.Assert(info.Location(), gc.Equals, fmt.Sprintf("file %q", filename))
+	c.Assert(configstore.JENVFilename(envDir, "testing"), jc.DoesNotExist)
+
+	controllers, accounts, environments := s.readFiles(c)
+	c.Assert(controllers.Controllers, jc.DeepEquals, map[string]configstore.ControllerDetails{
+		"testing": {
+			ServerUUID:      envUUID,
+			APIEndpoints:    []string{"address1", "address2"},
+			ServerHostnames: []string{"hostname1", "hostname2"},
+			CACert:          testing.CACert,
+		},
+	})
+	c.Assert(accounts.Accounts, jc.DeepEquals, map[string]map[string]configstore.AccountDetails{
+		"testing": {"tester": {Password: "secret"}},
+	})
+	c.Assert(environments.Environments, jc.DeepEquals, map[string]configstore.EnvironmentDetails{
+		"testing": {Controller: "testing", EnvironUUID: envUUID, User: "tester"},
+	})
+}
+
+func (s *controllersFileInterfaceSuite) TestServerEnvNameExists(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	s.writeEnv(c, "testing", envUUID, envUUID, "tester", "secret")
+
+	info := s.store.CreateInfo("testing")
+	// In order to trigger the writing to the controllers files, we need to store
+	// a server uuid.
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		EnvironUUID: envUUID,
+		ServerUUID:  envUUID,
+	})
+	err := info.Write()
+	c.Assert(err, gc.ErrorMatches, "environment info already exists")
+}
+
+func (s *controllersFileInterfaceSuite) TestServerUUIDRead(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	s.writeEnv(c, "testing", envUUID, envUUID, "tester", "secret")
+
+	info, err := s.store.ReadInfo("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.APICredentials(), jc.DeepEquals, configstore.APICredentials{
+		User:     "tester",
+		Password: "secret",
+	})
+	c.Assert(info.APIEndpoint(), jc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"address1", "address2"},
+		Hostnames:   []string{"hostname1", "hostname2"},
+		CACert:      testing.CACert,
+		EnvironUUID: envUUID,
+		ServerUUID:  envUUID,
+	})
+}
+
+func (s *controllersFileInterfaceSuite) TestServerDetailsShared(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	s.writeEnv(c, "testing", envUUID, envUUID, "tester", "secret")
+	info := s.writeEnv(c, "second", "fake-uuid", envUUID, "tester", "new-secret")
+	endpoint := info.APIEndpoint()
+	endpoint.Addresses = []string{"address2", "address3"}
+	endpoint.Hostnames = []string{"hostname2", "hostname3"}
+	info.SetAPIEndpoint(endpoint)
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err = s.store.ReadInfo("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.APICredentials(), jc.DeepEquals, configstore.APICredentials{
+		User:     "tester",
+		Password: "new-secret",
+	})
+	c.Assert(info.APIEndpoint(), jc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"address2", "address3"},
+		Hostnames:   []string{"hostname2", "hostname3"},
+		CACert:      testing.CACert,
+		EnvironUUID: envUUID,
+		ServerUUID:  envUUID,
+	})
+
+	controllers, accounts, environments := s.readFiles(c)
+	c.Assert(controllers.Controllers, gc.HasLen, 1)
+	c.Assert(accounts.Accounts["testing"], gc.HasLen, 1)
+	c.Assert(environments.Environments, jc.DeepEquals, map[string]configstore.EnvironmentDetails{
+		"testing": {Controller: "testing", EnvironUUID: envUUID, User: "tester"},
+		"second":  {Controller: "testing", EnvironUUID: "fake-uuid", User: "tester"},
+	})
+}
+
+func (s *controllersFileInterfaceSuite) TestMigrateJENV(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	info := s.writeEnv(c, "testing", envUUID, "", "tester", "secret")
+	envDir := filepath.Join(s.dir, "environments")
+	jenvFilename := configstore.JENVFilename(envDir, "testing")
+	c.Assert(info.Location(), gc.Equals, fmt.Sprintf("file %q", jenvFilename))
+
+	// Add server details and write again will migrate the info to the
+	// controllers files.
+	endpoint := info.APIEndpoint()
+	endpoint.ServerUUID = envUUID
+	info.SetAPIEndpoint(endpoint)
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(jenvFilename, jc.DoesNotExist)
+	controllers, _, environments := s.readFiles(c)
+
+	envDetails, ok := environments.Environments["testing"]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(envDetails, jc.DeepEquals, configstore.EnvironmentDetails{
+		Controller:  "testing",
+		EnvironUUID: envUUID,
+		User:        "tester",
+	})
+	// Controller entry also written.
+	controller, ok := controllers.Controllers["testing"]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(controller.ServerUUID, gc.Equals, envUUID)
+
+	readInfo, err := s.store.ReadInfo("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readInfo.APIEndpoint(), jc.DeepEquals, info.APIEndpoint())
+}
+
+func (s *controllersFileInterfaceSuite) readFiles(c *gc.C) (
+	controllers configstore.ControllersFile,
+	accounts configstore.AccountsFile,
+	environments configstore.EnvironmentsFile,
+) {
+	envDir := filepath.Join(s.dir, "environments")
+	_, err := configstore.ReadYAMLFile(configstore.ControllersFilename(envDir), &controllers)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = configstore.ReadYAMLFile(configstore.AccountsFilename(envDir), &accounts)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = configstore.ReadYAMLFile(configstore.EnvironmentsFilename(envDir), &environments)
+	c.Assert(err, jc.ErrorIsNil)
+	return controllers, accounts, environments
+}
+
+func (s *controllersFileInterfaceSuite) TestExistingJENVBlocksNew(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	info := s.writeEnv(c, "testing", envUUID, "", "tester", "secret")
+	envDir := filepath.Join(s.dir, "environments")
+	jenvFilename := configstore.JENVFilename(envDir, "testing")
+	c.Assert(info.Location(), gc.Equals, fmt.Sprintf("file %q", jenvFilename))
+
+	info = s.store.CreateInfo("testing")
+	// In order to trigger the writing to the controllers files, we need to store
+	// a server uuid.
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		EnvironUUID: envUUID,
+		ServerUUID:  envUUID,
+	})
+	err := info.Write()
+	c.Assert(err, gc.ErrorMatches, "environment info already exists")
+}
+
+func (s *controllersFileInterfaceSuite) TestList(c *gc.C) {
+	// List returns both JENV environments and the environments file entries.
+	s.writeEnv(c, "jenv-1", "fake-uuid1", "", "tester", "secret")
+	s.writeEnv(c, "jenv-2", "fake-uuid2", "", "tester", "secret")
+	s.writeEnv(c, "cache-1", "fake-uuid3", "fake-server", "tester", "secret")
+	s.writeEnv(c, "cache-2", "fake-uuid4", "fake-server", "tester", "secret")
+
+	environments, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(environments, jc.SameContents, []string{"jenv-1", "jenv-2", "cache-1", "cache-2"})
+
+	// Confirm that the sources are from where we'd expect.
+	envDir := filepath.Join(s.dir, "environments")
+	c.Assert(configstore.JENVFilename(envDir, "jenv-1"), jc.IsNonEmptyFile)
+	c.Assert(configstore.JENVFilename(envDir, "jenv-2"), jc.IsNonEmptyFile)
+	_, _, envFiles := s.readFiles(c)
+	names := make([]string, 0)
+	for name := range envFiles.Environments {
+		names = append(names, name)
+	}
+	c.Assert(names, jc.SameContents, []string{"cache-1", "cache-2"})
+}
+
+func (s *controllersFileInterfaceSuite) TestDestroy(c *gc.C) {
+	info := s.writeEnv(c, "cache-1", "fake-uuid", "fake-server", "tester", "secret")
+
+	err := info.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	controllers, accounts, environments := s.readFiles(c)
+	c.Assert(controllers.Controllers, gc.HasLen, 0)
+	c.Assert(accounts.Accounts, gc.HasLen, 0)
+	c.Assert(environments.Environments, gc.HasLen, 0)
+}
+
+func (s *controllersFileInterfaceSuite) TestDestroyTwice(c *gc.C) {
+	info := s.writeEnv(c, "cache-1", "fake-uuid", "fake-server", "tester", "secret")
+
+	err := info.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = info.Destroy()
+	c.Assert(err, gc.ErrorMatches, "environment info has already been removed")
+}
+
+func (s *controllersFileInterfaceSuite) TestDestroyKeepsSharedData(c *gc.C) {
+	info := s.writeEnv(c, "cache-1", "fake-uuid1", "fake-server", "tester", "secret")
+	s.writeEnv(c, "cache-2", "fake-uuid2", "fake-server", "tester", "secret")
+
+	err := info.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	controllers, accounts, environments := s.readFiles(c)
+	c.Assert(controllers.Controllers, gc.HasLen, 1)
+	c.Assert(accounts.Accounts, gc.HasLen, 1)
+	c.Assert(environments.Environments, gc.HasLen, 1)
+}
+
+func (s *controllersFileInterfaceSuite) TestControllerStore(c *gc.C) {
+	envUUID := testing.EnvironmentTag.Id()
+	s.writeEnv(c, "testing", envUUID, envUUID, "tester", "secret")
+	s.writeEnv(c, "hosted", "fake-uuid1", envUUID, "tester", "secret")
+	s.writeEnv(c, "other", "fake-uuid2", "fake-server", "tester", "secret")
+	s.writeEnv(c, "jenv", "fake-uuid3", "", "tester", "secret")
+
+	store := s.store.(configstore.ControllerStore)
+	controllers, err := store.ListControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controllers, jc.DeepEquals, []string{"fake-server", "testing"})
+
+	controller, err := store.ControllerByName("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(controller.ServerUUID, gc.Equals, envUUID)
+	_, err = store.ControllerByName("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	environments, err := store.ListEnvironments("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(environments, jc.DeepEquals, []string{"hosted", "testing"})
+
+	env, err := store.EnvironmentByName("hosted")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env, jc.DeepEquals, configstore.EnvironmentDetails{
+		Controller:  "testing",
+		EnvironUUID: "fake-uuid1",
+		User:        "tester",
+	})
+	_, err = store.EnvironmentByName("jenv")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *controllersFileInterfaceSuite) TestMigrateLegacyCacheFile(c *gc.C) {
+	envDir := filepath.Join(s.dir, "environments")
+	legacy := fmt.Sprintf(`
+server-user:
+  testing:
+    server-uuid: server-uuid
+    user: tester
+server-data:
+  server-uuid:
+    api-endpoints: [address1]
+    ca-cert: %q
+    identities:
+      tester: secret
+environment:
+  testing:
+    user: tester
+    env-uuid: server-uuid
+    server-uuid: server-uuid
+  hosted:
+    user: tester
+    env-uuid: hosted-uuid
+    server-uuid: server-uuid
+`[1:], testing.CACert)
+	legacyFilename := filepath.Join(envDir, "cache.yaml")
+	err := ioutil.WriteFile(legacyFilename, []byte(legacy), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.store.ReadInfo("hosted")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.APICredentials(), jc.DeepEquals, configstore.APICredentials{
+		User:     "tester",
+		Password: "secret",
+	})
+	c.Assert(info.APIEndpoint(), jc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"address1"},
+		CACert:      testing.CACert,
+		EnvironUUID: "hosted-uuid",
+		ServerUUID:  "server-uuid",
+	})
+
+	// Writing the info moves everything to the new files.
+	err = info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(legacyFilename, jc.DoesNotExist)
+	_, _, environments := s.readFiles(c)
+	c.Assert(environments.Environments, jc.DeepEquals, map[string]configstore.EnvironmentDetails{
+		"testing": {Controller: "testing", EnvironUUID: "server-uuid", User: "tester"},
+		"hosted":  {Controller: "testing", EnvironUUID: "hosted-uuid", User: "tester"},
+	})
+}
@@ -28,10 +28,10 @@ type configSource string
 const (
 	lockName = "env.lock"
 
-	sourceCreated configSource = "created"
-	sourceJenv    configSource = "jenv"
-	sourceCache   configSource = "cache"
-	sourceMem     configSource = "mem"
+	sourceCreated     configSource = "created"
+	sourceJenv        configSource = "jenv"
+	sourceControllers configSource = "controllers"
+	sourceMem         configSource = "mem"
 )
 
 // A second should be way more than enough to write or read any files.
@@ -47,6 +47,8 @@ type diskStore struct {
 	dir string
 }
 
+var _ ControllerStore = (*diskStore)(nil)
+
 // EnvironInfoData is the serialisation structure for the original JENV file.
 type EnvironInfoData struct {
 	User            string
@@ -123,20 +125,20 @@ func (d *diskStore) CreateInfo(envName string) EnvironInfo {
 func (d *diskStore) List() ([]string, error) {
 	var envs []string
 
-	// Awkward -  list both jenv files and cache entries.
-	cache, err := readCacheFile(cacheFilename(d.dir))
+	// Awkward -  list both jenv files and the environments file entries.
+	files, err := readClientFiles(d.dir)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	for name := range cache.Environment {
+	for name := range files.environments.Environments {
 		envs = append(envs, name)
 	}
 
-	files, err := filepath.Glob(d.dir + "/*" + jenvExtension)
+	jenvFiles, err := filepath.Glob(d.dir + "/*" + jenvExtension)
 	if err != nil {
 		return nil, err
 	}
-	for _, file := range files {
+	for _, file := range jenvFiles {
 		fName := filepath.Base(file)
 		name := fName[:len(fName)-len(jenvExtension)]
 		envs = append(envs, name)
@@ -155,7 +157,7 @@ func (d *diskStore) ReadInfo(envName string) (EnvironInfo, error) {
 	}
 	defer lock.Unlock()
 
-	info, err := d.readCacheFile(envName)
+	info, err := d.readClientFiles(envName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			info, err = d.readJENVFile(envName)
@@ -168,20 +170,72 @@ func (d *diskStore) ReadInfo(envName string) (EnvironInfo, error) {
 	return info, nil
 }
 
-func cacheFilename(dir string) string {
-	return filepath.Join(dir, "cache.yaml")
+func (d *diskStore) readClientFiles(envName string) (*environInfo, error) {
+	files, err := readClientFiles(d.dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := files.readInfo(envName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info.path = environmentsFilename(d.dir)
+	return info, nil
+}
+
+// readLockedClientFiles reads the controllers, accounts and environments
+// files while holding the environment lock.
+func (d *diskStore) readLockedClientFiles() (*clientFiles, error) {
+	lock, err := acquireEnvironmentLock(d.dir, "reading")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read controllers")
+	}
+	defer lock.Unlock()
+	return readClientFiles(d.dir)
 }
 
-func (d *diskStore) readCacheFile(envName string) (*environInfo, error) {
-	cache, err := readCacheFile(cacheFilename(d.dir))
+// ListControllers implements ControllerStore.ListControllers.
+func (d *diskStore) ListControllers() ([]string, error) {
+	files, err := d.readLockedClientFiles()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	info, err := cache.readInfo(envName)
+	return files.controllerNames(), nil
+}
+
+// ControllerByName implements ControllerStore.ControllerByName.
+func (d *diskStore) ControllerByName(controllerName string) (ControllerDetails, error) {
+	files, err := d.readLockedClientFiles()
+	if err != nil {
+		return ControllerDetails{}, errors.Trace(err)
+	}
+	controller, ok := files.controllers.Controllers[controllerName]
+	if !ok {
+		return ControllerDetails{}, errors.NotFoundf("controller %q", controllerName)
+	}
+	return controller, nil
+}
+
+// ListEnvironments implements ControllerStore.ListEnvironments.
+func (d *diskStore) ListEnvironments(controllerName string) ([]string, error) {
+	files, err := d.readLockedClientFiles()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return info, nil
+	return files.environmentNames(controllerName), nil
+}
+
+// EnvironmentByName implements ControllerStore.EnvironmentByName.
+func (d *diskStore) EnvironmentByName(envName string) (EnvironmentDetails, error) {
+	files, err := d.readLockedClientFiles()
+	if err != nil {
+		return EnvironmentDetails{}, errors.Trace(err)
+	}
+	env, ok := files.environments.Environments[envName]
+	if !ok {
+		return EnvironmentDetails{}, errors.NotFoundf("environment %q", envName)
+	}
+	return env, nil
 }
 
 // Initialized implements EnvironInfo.Initialized.
@@ -271,31 +325,30 @@ func (info *environInfo) Write() error {
 	}
 	defer lock.Unlock()
 
-	// In order to write out the environment info to the cache
-	// file we need to make sure the server UUID is set. Sufficiently
+	// In order to write out the environment info to the controllers
+	// files we need to make sure the server UUID is set. Sufficiently
 	// up to date servers will write the server UUID to the JENV
 	// file as connections are made to the API server. It is possible
 	// that for an old JENV file, the first update (on API connection)
 	// may write a JENV file, and the subsequent update will create the
-	// entry in the cache file.
+	// entries in the controllers files.
 	if featureflag.Enabled(feature.EnvironmentsCacheFile) && info.serverUUID != "" {
 		if err := info.ensureNoJENV(); info.source == sourceCreated && err != nil {
 			return errors.Trace(err)
 		}
-		logger.Debugf("writing cache file")
-		filename := cacheFilename(info.environmentDir)
-		cache, err := readCacheFile(filename)
+		logger.Debugf("writing controllers files")
+		files, err := readClientFiles(info.environmentDir)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		if err := cache.updateInfo(info); err != nil {
+		if err := files.updateInfo(info); err != nil {
 			return errors.Trace(err)
 		}
-		if err := writeCacheFile(filename, cache); err != nil {
+		if err := files.write(info.environmentDir); err != nil {
 			return errors.Trace(err)
 		}
 		oldPath := info.path
-		info.path = filename
+		info.path = environmentsFilename(info.environmentDir)
 		// If source was jenv file, delete the jenv.
 		if info.source == sourceJenv {
 			err := os.Remove(oldPath)
@@ -303,7 +356,7 @@ func (info *environInfo) Write() error {
 				return errors.Trace(err)
 			}
 		}
-		info.source = sourceCache
+		info.source = sourceControllers
 	} else {
 		logger.Debugf("writing jenv file")
 		if err := info.writeJENVFile(); err != nil {
@@ -332,16 +385,15 @@ func (info *environInfo) Destroy() error {
 			}
 			return err
 		}
-		if info.source == sourceCache {
-			filename := cacheFilename(info.environmentDir)
-			cache, err := readCacheFile(filename)
+		if info.source == sourceControllers {
+			files, err := readClientFiles(info.environmentDir)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if err := cache.removeInfo(info); err != nil {
+			if err := files.removeInfo(info); err != nil {
 				return errors.Trace(err)
 			}
-			if err := writeCacheFile(filename, cache); err != nil {
+			if err := files.write(info.environmentDir); err != nil {
 				return errors.Trace(err)
 			}
 			return nil
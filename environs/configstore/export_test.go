@@ -4,7 +4,9 @@
 package configstore
 
 var (
-	CacheFilename = cacheFilename
-	JENVFilename  = jenvFilename
-	ReadCacheFile = readCacheFile
+	ControllersFilename  = controllersFilename
+	AccountsFilename     = accountsFilename
+	EnvironmentsFilename = environmentsFilename
+	JENVFilename         = jenvFilename
+	ReadYAMLFile         = readYAMLFile
 )
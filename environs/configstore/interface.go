@@ -65,6 +65,30 @@ type Storage interface {
 	List() ([]string, error)
 }
 
+// ControllerStore is implemented by Storage implementations that record
+// which controller each environment runs in, so that the environments
+// hosted by a Juju Environment Server can be told apart from the
+// environments that are their own controller.
+type ControllerStore interface {
+	// ListControllers returns the sorted names of the controllers
+	// known to the store.
+	ListControllers() ([]string, error)
+
+	// ControllerByName returns the details of the named controller.
+	// If there is no such controller, it will return an
+	// errors.NotFound error.
+	ControllerByName(controllerName string) (ControllerDetails, error)
+
+	// ListEnvironments returns the sorted names of the environments
+	// known to run in the named controller.
+	ListEnvironments(controllerName string) ([]string, error)
+
+	// EnvironmentByName returns the details of the named environment.
+	// If the store does not know the controller of the environment,
+	// it will return an errors.NotFound error.
+	EnvironmentByName(envName string) (EnvironmentDetails, error)
+}
+
 // EnvironInfo holds information associated with an environment.
 type EnvironInfo interface {
 	// Initialized returns whether the environment information has
@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/instance"
+)
+
+// InstanceConsole defines the method of environments that can retrieve
+// the console output of their instances.
+type InstanceConsole interface {
+	// InstanceConsoleOutput returns the console output of the instance
+	// with the given id, as most recently captured by the provider.
+	// The output may lag behind that of the instance, and may be empty
+	// if the instance has only just started.
+	InstanceConsoleOutput(id instance.Id) (string, error)
+}
+
+// SupportsInstanceConsole is a convenience helper to check if an
+// environment supports retrieving the console output of its instances.
+func SupportsInstanceConsole(environ Environ) (InstanceConsole, bool) {
+	ic, ok := environ.(InstanceConsole)
+	return ic, ok
+}
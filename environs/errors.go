@@ -4,7 +4,9 @@
 package environs
 
 import (
-	"errors"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
 )
 
 var (
@@ -18,3 +20,57 @@ var (
 	ErrIPAddressesExhausted = errors.New("can't allocate a new IP address")
 	ErrIPAddressUnavailable = errors.New("the requested IP address is unavailable")
 )
+
+// StartInstanceErrorKind classifies the errors returned by
+// InstanceBroker.StartInstance, so that the provisioner knows whether
+// starting the instance again may succeed.
+type StartInstanceErrorKind string
+
+const (
+	// StartInstanceQuotaExceeded indicates that the provider refused
+	// to start the instance because a quota or rate limit has been
+	// reached; starting it later may succeed.
+	StartInstanceQuotaExceeded StartInstanceErrorKind = "quota-exceeded"
+
+	// StartInstanceTransient indicates that the provider failed to
+	// start the instance for a reason that is expected to go away,
+	// such as an API error or timeout.
+	StartInstanceTransient StartInstanceErrorKind = "transient"
+
+	// StartInstanceInvalidConstraints indicates that the provider
+	// cannot start an instance matching the machine's constraints;
+	// starting it again will not succeed.
+	StartInstanceInvalidConstraints StartInstanceErrorKind = "invalid-constraints"
+)
+
+// startInstanceError is an error returned by StartInstance and
+// classified by its kind.
+type startInstanceError struct {
+	kind StartInstanceErrorKind
+	err  error
+}
+
+// Error is part of the error interface.
+func (e *startInstanceError) Error() string {
+	return e.err.Error()
+}
+
+// NewStartInstanceError returns an error that wraps err, classified
+// as being of the given kind.
+func NewStartInstanceError(kind StartInstanceErrorKind, err error) error {
+	return &startInstanceError{kind, err}
+}
+
+// StartInstanceErrorKindOf returns the kind of the given error
+// returned by StartInstance, or the empty string if the error has not
+// been classified.
+func StartInstanceErrorKindOf(err error) StartInstanceErrorKind {
+	cause := errors.Cause(err)
+	if err, ok := cause.(*startInstanceError); ok {
+		return err.kind
+	}
+	if instance.IsRetryableCreationError(cause) {
+		return StartInstanceTransient
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type StartInstanceErrorSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&StartInstanceErrorSuite{})
+
+func (s *StartInstanceErrorSuite) TestKindOf(c *gc.C) {
+	err := environs.NewStartInstanceError(environs.StartInstanceQuotaExceeded, errors.New("too many instances"))
+	c.Assert(err, gc.ErrorMatches, "too many instances")
+	c.Assert(environs.StartInstanceErrorKindOf(err), gc.Equals, environs.StartInstanceQuotaExceeded)
+
+	wrapped := errors.Annotate(err, "cannot run instance")
+	c.Assert(wrapped, gc.ErrorMatches, "cannot run instance: too many instances")
+	c.Assert(environs.StartInstanceErrorKindOf(wrapped), gc.Equals, environs.StartInstanceQuotaExceeded)
+}
+
+func (s *StartInstanceErrorSuite) TestKindOfRetryableCreationError(c *gc.C) {
+	err := errors.Trace(instance.NewRetryableCreationError("container failed to start"))
+	c.Assert(environs.StartInstanceErrorKindOf(err), gc.Equals, environs.StartInstanceTransient)
+}
+
+func (s *StartInstanceErrorSuite) TestKindOfUnclassified(c *gc.C) {
+	c.Assert(environs.StartInstanceErrorKindOf(errors.New("boom")), gc.Equals, environs.StartInstanceErrorKind(""))
+}
@@ -95,6 +95,11 @@ type BootstrapParams struct {
 	// ContainerBridgeName, if non-empty, overrides the default
 	// network bridge device to use for LXC and KVM containers.
 	ContainerBridgeName string
+
+	// BootstrapHost, if non-empty, holds the address of an existing
+	// machine that the state server should be installed on over SSH,
+	// instead of on a new instance started by the provider.
+	BootstrapHost string
 }
 
 // BootstrapFinalizer is a function returned from Environ.Bootstrap.
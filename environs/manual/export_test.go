@@ -3,11 +3,39 @@
 
 package manual
 
+import (
+	"io"
+)
+
 var (
 	NetLookupHost         = &netLookupHost
 	ProvisionMachineAgent = &provisionMachineAgent
 )
 
 const (
-	DetectionScript = detectionScript
+	DetectionScript               = detectionScript
+	WindowsDetectionScript        = windowsDetectionScript
+	WindowsCheckProvisionedScript = windowsCheckProvisionedScript
 )
+
+type patcher interface {
+	PatchValue(interface{}, interface{})
+}
+
+// PowerShellRunFunc runs a PowerShell script in place of a WinRM client.
+type PowerShellRunFunc func(host, user, password, script string, stdout, stderr io.Writer) error
+
+type fakePowerShellRunner struct {
+	host, user, password string
+	run                  PowerShellRunFunc
+}
+
+func (r *fakePowerShellRunner) RunPowerShell(script string, stdout, stderr io.Writer) error {
+	return r.run(r.host, r.user, r.password, script, stdout, stderr)
+}
+
+func PatchNewWinRMClient(patcher patcher, run PowerShellRunFunc) {
+	patcher.PatchValue(&newWinRMClient, func(host, user, password string) powerShellRunner {
+		return &fakePowerShellRunner{host, user, password, run}
+	})
+}
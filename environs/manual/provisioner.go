@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/version"
 )
 
 const manualInstancePrefix = "manual:"
@@ -41,6 +42,10 @@ type ProvisionMachineArgs struct {
 	// Host is the SSH host: [user@]host
 	Host string
 
+	// Password is the password of the user logging in to the host.
+	// It is only used when provisioning Windows hosts over WinRM.
+	Password string
+
 	// DataDir is the root directory for juju data.
 	// If left blank, the default location "/var/lib/juju" will be used.
 	DataDir string
@@ -150,20 +155,6 @@ func recordMachineInState(client ProvisioningClientAPI, machineParams params.Add
 // If we can, we will reverse lookup the hostname by its IP address, and use
 // the DNS resolved name, rather than the name that was supplied
 func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
-
-	// Generate a unique nonce for the machine.
-	uuid, err := utils.NewUUID()
-	if err != nil {
-		return nil, err
-	}
-
-	var addrs []network.Address
-	if addr, err := HostAddress(hostname); err != nil {
-		logger.Warningf("failed to compute public address for %q: %v", hostname, err)
-	} else {
-		addrs = append(addrs, addr)
-	}
-
 	provisioned, err := checkProvisioned(hostname)
 	if err != nil {
 		err = fmt.Errorf("error checking if provisioned: %v", err)
@@ -178,6 +169,25 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		err = fmt.Errorf("error detecting hardware characteristics: %v", err)
 		return nil, err
 	}
+	return newMachineParams(hostname, series, hc)
+}
+
+// newMachineParams returns the parameters with which to record the
+// existing machine with the given hostname, series and hardware
+// characteristics in state.
+func newMachineParams(hostname, series string, hc instance.HardwareCharacteristics) (*params.AddMachineParams, error) {
+	// Generate a unique nonce for the machine.
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []network.Address
+	if addr, err := HostAddress(hostname); err != nil {
+		logger.Warningf("failed to compute public address for %q: %v", hostname, err)
+	} else {
+		addrs = append(addrs, addr)
+	}
 
 	// There will never be a corresponding "instance" that any provider
 	// knows about. This is fine, and works well with the provisioner
@@ -212,13 +222,17 @@ var provisionMachineAgent = func(host string, mcfg *cloudinit.MachineConfig, pro
 
 // ProvisioningScript generates a bash script that can be
 // executed on a remote host to carry out the cloud-init
-// configuration.
+// configuration. For Windows series, a PowerShell script
+// equivalent to the cloudbase-init userdata is generated.
 func ProvisioningScript(mcfg *cloudinit.MachineConfig) (string, error) {
 
 	cloudcfg, err := coreCloudinit.New(mcfg.Series)
 	if err != nil {
 		return "", errors.Annotate(err, "error generating cloud-config")
 	}
+	if os, err := version.GetOSFromSeries(mcfg.Series); err == nil && os == version.Windows {
+		return windowsProvisioningScript(mcfg, cloudcfg)
+	}
 	cloudcfg.SetAptUpdate(mcfg.EnableOSRefreshUpdate)
 	cloudcfg.SetAptUpgrade(mcfg.EnableOSUpgrade)
 
@@ -244,6 +258,23 @@ func ProvisioningScript(mcfg *cloudinit.MachineConfig) (string, error) {
 	return buf.String(), nil
 }
 
+// windowsProvisioningScript generates the PowerShell script that
+// initialises a Windows machine and installs its machine agent.
+func windowsProvisioningScript(mcfg *cloudinit.MachineConfig, cloudcfg *coreCloudinit.Config) (string, error) {
+	udata, err := cloudinit.NewUserdataConfig(mcfg, cloudcfg)
+	if err != nil {
+		return "", errors.Annotate(err, "error generating userdata")
+	}
+	if err := udata.Configure(); err != nil {
+		return "", errors.Annotate(err, "error generating userdata")
+	}
+	script, err := udata.Render()
+	if err != nil {
+		return "", errors.Annotate(err, "error rendering userdata")
+	}
+	return string(script), nil
+}
+
 func runProvisionScript(script, host string, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
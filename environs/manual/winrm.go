@@ -0,0 +1,199 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/utils/winrm"
+	"github.com/juju/juju/version"
+)
+
+// defaultWindowsUser is the user that logs in to Windows hosts if
+// none is specified.
+const defaultWindowsUser = "Administrator"
+
+// windowsDetectionScript is the PowerShell script to run on the
+// remote machine to detect the OS series and hardware characteristics.
+const windowsDetectionScript = `$ErrorActionPreference = "Stop"
+(Get-ItemProperty "HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion").ProductName
+$env:PROCESSOR_ARCHITECTURE
+(Get-WmiObject Win32_ComputerSystem).TotalPhysicalMemory
+(Get-WmiObject Win32_Processor | Measure-Object -Property NumberOfLogicalProcessors -Sum).Sum`
+
+// windowsCheckProvisionedScript lists the juju services installed on
+// the remote machine.
+const windowsCheckProvisionedScript = `Get-Service | Where-Object { $_.Name -like "jujud-*" } | ForEach-Object { $_.Name }`
+
+// powerShellRunner runs PowerShell scripts on a remote Windows host.
+type powerShellRunner interface {
+	RunPowerShell(script string, stdout, stderr io.Writer) error
+}
+
+// newWinRMClient returns a powerShellRunner that connects to the
+// HTTPS WinRM listener of the given host. The certificates of WinRM
+// listeners are usually self-signed, so they are not verified.
+var newWinRMClient = func(host, user, password string) powerShellRunner {
+	return winrm.NewClient(winrm.ClientParams{
+		Host:     host,
+		User:     user,
+		Password: password,
+		Secure:   true,
+		Insecure: true,
+	})
+}
+
+// ProvisionWindowsMachine provisions a machine agent to an existing
+// Windows host, via a WinRM connection to the specified host. The host
+// may optionally be preceded with a login username, as in [user@]host;
+// if it is not, the Administrator user is used. The password of the
+// user is taken from args.Password.
+//
+// The host must have Cloudbase-Init installed, as the provisioning
+// script relies on its Python interpreter to unpack the tools.
+//
+// On successful completion, this function will return the id of the state.Machine
+// that was entered into state.
+func ProvisionWindowsMachine(args ProvisionMachineArgs) (machineId string, err error) {
+	defer func() {
+		if machineId != "" && err != nil {
+			logger.Errorf("provisioning failed, removing machine %v: %v", machineId, err)
+			if cleanupErr := args.Client.ForceDestroyMachines(machineId); cleanupErr != nil {
+				logger.Warningf("error cleaning up machine: %s", cleanupErr)
+			}
+			machineId = ""
+		}
+	}()
+
+	user, hostname := splitUserHost(args.Host)
+	if user == "" {
+		user = defaultWindowsUser
+	}
+	client := newWinRMClient(hostname, user, args.Password)
+
+	machineParams, err := gatherWindowsMachineParams(client, hostname)
+	if err != nil {
+		return "", err
+	}
+
+	// Inform Juju that the machine exists.
+	machineId, err = recordMachineInState(args.Client, *machineParams)
+	if err != nil {
+		return "", err
+	}
+
+	provisioningScript, err := args.Client.ProvisioningScript(params.ProvisioningScriptParams{
+		MachineId: machineId,
+		Nonce:     machineParams.Nonce,
+	})
+	if err != nil {
+		logger.Errorf("cannot obtain provisioning script")
+		return "", err
+	}
+
+	// Finally, provision the machine agent.
+	logger.Infof("Provisioning machine agent on %s", hostname)
+	if err := client.RunPowerShell(provisioningScript, args.Stderr, args.Stderr); err != nil {
+		return machineId, errors.Annotate(err, "cannot run provisioning script")
+	}
+
+	logger.Infof("Provisioned machine %v", machineId)
+	return machineId, nil
+}
+
+// gatherWindowsMachineParams collects all the information we know
+// about the Windows machine we are about to provision, using the given
+// client to run scripts on it.
+func gatherWindowsMachineParams(client powerShellRunner, hostname string) (*params.AddMachineParams, error) {
+	provisioned, err := checkWindowsProvisioned(client, hostname)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if provisioned: %v", err)
+	}
+	if provisioned {
+		return nil, ErrProvisioned
+	}
+
+	hc, series, err := detectWindowsSeriesAndHardwareCharacteristics(client, hostname)
+	if err != nil {
+		return nil, fmt.Errorf("error detecting hardware characteristics: %v", err)
+	}
+	return newMachineParams(hostname, series, hc)
+}
+
+func checkWindowsProvisioned(client powerShellRunner, host string) (bool, error) {
+	logger.Infof("Checking if %s is already provisioned", host)
+	output, err := runWindowsScript(client, windowsCheckProvisionedScript)
+	if err != nil {
+		return false, err
+	}
+	provisioned := output != ""
+	if provisioned {
+		logger.Infof("%s is already provisioned [%q]", host, output)
+	} else {
+		logger.Infof("%s is not provisioned", host)
+	}
+	return provisioned, nil
+}
+
+func detectWindowsSeriesAndHardwareCharacteristics(client powerShellRunner, host string) (hc instance.HardwareCharacteristics, series string, err error) {
+	logger.Infof("Detecting series and characteristics on %s", host)
+	output, err := runWindowsScript(client, windowsDetectionScript)
+	if err != nil {
+		return hc, "", err
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) != 4 {
+		return hc, "", errors.Errorf("unexpected output %q", output)
+	}
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+
+	if series, err = version.WindowsSeries(lines[0]); err != nil {
+		return hc, "", err
+	}
+
+	arch := arch.NormaliseArch(strings.ToLower(lines[1]))
+	hc.Arch = &arch
+
+	// HardwareCharacteristics wants memory in megabytes,
+	// Windows reports it in bytes.
+	hc.Mem = new(uint64)
+	if *hc.Mem, err = strconv.ParseUint(lines[2], 10, 0); err != nil {
+		return hc, "", err
+	}
+	*hc.Mem /= 1024 * 1024
+
+	hc.CpuCores = new(uint64)
+	if *hc.CpuCores, err = strconv.ParseUint(lines[3], 10, 0); err != nil {
+		return hc, "", err
+	}
+
+	logger.Infof("series: %s, characteristics: %s", series, hc)
+	return hc, series, nil
+}
+
+// runWindowsScript runs the given PowerShell script with the client,
+// and returns its output with line endings normalised and surrounding
+// whitespace removed.
+func runWindowsScript(client powerShellRunner, script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := client.RunPowerShell(script, &stdout, &stderr); err != nil {
+		if stderr.Len() != 0 {
+			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	output := strings.Replace(stdout.String(), "\r\n", "\n", -1)
+	return strings.TrimSpace(output), nil
+}
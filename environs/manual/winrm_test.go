@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type winrmProvisionerSuite struct {
+	testing.BaseSuite
+	client   *fakeProvisioningClient
+	scripts  []string
+	logins   []string
+	services string
+	runErr   error
+}
+
+var _ = gc.Suite(&winrmProvisionerSuite{})
+
+func (s *winrmProvisionerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.client = &fakeProvisioningClient{}
+	s.scripts = nil
+	s.logins = nil
+	s.services = ""
+	s.runErr = nil
+	s.PatchValue(manual.NetLookupHost, func(host string) ([]string, error) {
+		return []string{"10.0.0.4"}, nil
+	})
+	manual.PatchNewWinRMClient(s, func(host, user, password, script string, stdout, stderr io.Writer) error {
+		s.logins = append(s.logins, fmt.Sprintf("%s:%s@%s", user, password, host))
+		s.scripts = append(s.scripts, script)
+		switch script {
+		case manual.WindowsCheckProvisionedScript:
+			fmt.Fprint(stdout, s.services)
+		case manual.WindowsDetectionScript:
+			fmt.Fprint(stdout, "Windows Server 2012 R2 Standard\r\nAMD64\r\n4294967296\r\n4\r\n")
+		default:
+			return s.runErr
+		}
+		return nil
+	})
+}
+
+func (s *winrmProvisionerSuite) args() manual.ProvisionMachineArgs {
+	return manual.ProvisionMachineArgs{
+		Host:     "winhost",
+		Password: "secret",
+		Client:   s.client,
+		Stdout:   ioutil.Discard,
+		Stderr:   ioutil.Discard,
+	}
+}
+
+func (s *winrmProvisionerSuite) TestProvisionWindowsMachine(c *gc.C) {
+	machineId, err := manual.ProvisionWindowsMachine(s.args())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, "1")
+
+	c.Assert(s.logins, gc.HasLen, 3)
+	c.Assert(s.logins[0], gc.Equals, "Administrator:secret@winhost")
+	c.Assert(s.scripts[2], gc.Equals, "provisioning script for 1")
+
+	c.Assert(s.client.added, gc.HasLen, 1)
+	added := s.client.added[0]
+	c.Assert(added.Series, gc.Equals, "win2012r2")
+	c.Assert(added.InstanceId, gc.Equals, instance.Id("manual:winhost"))
+	c.Assert(added.Nonce, gc.Matches, "manual:winhost:.*")
+	hc := added.HardwareCharacteristics
+	c.Assert(*hc.Arch, gc.Equals, "amd64")
+	c.Assert(*hc.Mem, gc.Equals, uint64(4096))
+	c.Assert(*hc.CpuCores, gc.Equals, uint64(4))
+	c.Assert(s.client.destroyed, gc.HasLen, 0)
+}
+
+func (s *winrmProvisionerSuite) TestProvisionWindowsMachineUser(c *gc.C) {
+	args := s.args()
+	args.Host = "juju@winhost"
+	_, err := manual.ProvisionWindowsMachine(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.logins[0], gc.Equals, "juju:secret@winhost")
+}
+
+func (s *winrmProvisionerSuite) TestProvisionWindowsMachineProvisioned(c *gc.C) {
+	s.services = "jujud-machine-0\r\n"
+	_, err := manual.ProvisionWindowsMachine(s.args())
+	c.Assert(err, gc.Equals, manual.ErrProvisioned)
+	c.Assert(s.client.added, gc.HasLen, 0)
+}
+
+func (s *winrmProvisionerSuite) TestProvisionWindowsMachineScriptFails(c *gc.C) {
+	s.runErr = errors.New("command failed with exit code 1")
+	machineId, err := manual.ProvisionWindowsMachine(s.args())
+	c.Assert(err, gc.ErrorMatches, "cannot run provisioning script: command failed with exit code 1")
+	c.Assert(machineId, gc.Equals, "")
+	c.Assert(s.client.destroyed, jc.DeepEquals, []string{"1"})
+}
+
+type fakeProvisioningClient struct {
+	added     []params.AddMachineParams
+	destroyed []string
+}
+
+func (f *fakeProvisioningClient) AddMachines(args []params.AddMachineParams) ([]params.AddMachinesResult, error) {
+	f.added = append(f.added, args...)
+	return []params.AddMachinesResult{{Machine: fmt.Sprint(len(f.added))}}, nil
+}
+
+func (f *fakeProvisioningClient) ForceDestroyMachines(machines ...string) error {
+	f.destroyed = append(f.destroyed, machines...)
+	return nil
+}
+
+func (f *fakeProvisioningClient) ProvisioningScript(args params.ProvisioningScriptParams) (string, error) {
+	return "provisioning script for " + args.MachineId, nil
+}
@@ -22,8 +22,9 @@ type toolsDatasourceFuncId struct {
 }
 
 var (
-	toolsDatasourceFuncsMu sync.RWMutex
-	toolsDatasourceFuncs   []toolsDatasourceFuncId
+	toolsDatasourceFuncsMu       sync.RWMutex
+	toolsDatasourceFuncs         []toolsDatasourceFuncId
+	preferredToolsDatasourceFunc *toolsDatasourceFuncId
 )
 
 // ToolsDataSourceFunc is a function type that takes an environment and
@@ -64,13 +65,38 @@ func UnregisterToolsDataSourceFunc(id string) {
 	}
 }
 
+// RegisterPreferredToolsDataSourceFunc registers a ToolsDataSourceFunc
+// whose datasource is searched before all others, including the one
+// configured with agent-metadata-url, replacing any function previously
+// registered with this function. The state servers use it to look for
+// tools in their own storage before going to external mirrors.
+func RegisterPreferredToolsDataSourceFunc(id string, f ToolsDataSourceFunc) {
+	toolsDatasourceFuncsMu.Lock()
+	defer toolsDatasourceFuncsMu.Unlock()
+	preferredToolsDatasourceFunc = &toolsDatasourceFuncId{id, f}
+}
+
+// UnregisterPreferredToolsDataSourceFunc unregisters the ToolsDataSourceFunc
+// registered with RegisterPreferredToolsDataSourceFunc, if it has the
+// specified id.
+func UnregisterPreferredToolsDataSourceFunc(id string) {
+	toolsDatasourceFuncsMu.Lock()
+	defer toolsDatasourceFuncsMu.Unlock()
+	if preferredToolsDatasourceFunc != nil && preferredToolsDatasourceFunc.id == id {
+		preferredToolsDatasourceFunc = nil
+	}
+}
+
 // GetMetadataSources returns the sources to use when looking for
 // simplestreams tools metadata for the given stream.
 func GetMetadataSources(env environs.Environ) ([]simplestreams.DataSource, error) {
 	config := env.Config()
 
-	// Add configured and environment-specific datasources.
-	var sources []simplestreams.DataSource
+	// Add the preferred, configured and environment-specific datasources.
+	sources, err := preferredDataSources(env)
+	if err != nil {
+		return nil, err
+	}
 	if userURL, ok := config.AgentMetadataURL(); ok {
 		verify := utils.VerifySSLHostnames
 		if !config.SSLHostnameVerification() {
@@ -97,6 +123,24 @@ func GetMetadataSources(env environs.Environ) ([]simplestreams.DataSource, error
 	return sources, nil
 }
 
+// preferredDataSources returns the datasource of the function registered
+// with RegisterPreferredToolsDataSourceFunc, if any.
+func preferredDataSources(env environs.Environ) ([]simplestreams.DataSource, error) {
+	toolsDatasourceFuncsMu.RLock()
+	defer toolsDatasourceFuncsMu.RUnlock()
+	if preferredToolsDatasourceFunc == nil {
+		return nil, nil
+	}
+	logger.Debugf("trying datasource %q", preferredToolsDatasourceFunc.id)
+	datasource, err := preferredToolsDatasourceFunc.f(env)
+	if errors.IsNotSupported(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return []simplestreams.DataSource{datasource}, nil
+}
+
 // environmentDataSources returns simplestreams datasources for the environment
 // by calling the functions registered in RegisterToolsDataSourceFunc.
 // The datasources returned will be in the same order the functions were registered.
@@ -91,6 +91,37 @@ func (s *URLsSuite) TestToolsMetadataURLsRegisteredFuncs(c *gc.C) {
 	})
 }
 
+func (s *URLsSuite) TestToolsMetadataURLsPreferredFunc(c *gc.C) {
+	tools.RegisterToolsDataSourceFunc("id0", func(environs.Environ) (simplestreams.DataSource, error) {
+		return simplestreams.NewURLDataSource("id0", "betwixt/releases", utils.NoVerifySSLHostnames), nil
+	})
+	defer tools.UnregisterToolsDataSourceFunc("id0")
+	tools.RegisterPreferredToolsDataSourceFunc("state server", func(environs.Environ) (simplestreams.DataSource, error) {
+		return simplestreams.NewURLDataSource("state server", "https://localhost:17070/tools", utils.NoVerifySSLHostnames), nil
+	})
+	defer tools.UnregisterPreferredToolsDataSourceFunc("state server")
+
+	// The preferred datasource comes before even the configured one.
+	env := s.env(c, "config-tools-metadata-url")
+	sources, err := tools.GetMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []string{
+		"https://localhost:17070/tools/",
+		"config-tools-metadata-url/",
+		"betwixt/releases/",
+		"https://streams.canonical.com/juju/tools/",
+	})
+
+	tools.UnregisterPreferredToolsDataSourceFunc("state server")
+	sources, err = tools.GetMetadataSources(env)
+	c.Assert(err, jc.ErrorIsNil)
+	sstesting.AssertExpectedSources(c, sources, []string{
+		"config-tools-metadata-url/",
+		"betwixt/releases/",
+		"https://streams.canonical.com/juju/tools/",
+	})
+}
+
 func (s *URLsSuite) TestToolsMetadataURLsRegisteredFuncsError(c *gc.C) {
 	tools.RegisterToolsDataSourceFunc("id0", func(environs.Environ) (simplestreams.DataSource, error) {
 		// Non-NotSupported errors cause GetMetadataSources to fail.
@@ -0,0 +1,30 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/instance"
+)
+
+// AvailabilityZone describes a provider availability zone.
+type AvailabilityZone interface {
+	// Name returns the name of the availability zone.
+	Name() string
+
+	// Available reports whether the availability zone is currently available.
+	Available() bool
+}
+
+// ZonedEnviron is an Environ that has support for availability zones.
+type ZonedEnviron interface {
+	Environ
+
+	// AvailabilityZones returns all availability zones in the environment.
+	AvailabilityZones() ([]AvailabilityZone, error)
+
+	// InstanceAvailabilityZoneNames returns the names of the availability
+	// zones for the specified instances. The error returned follows the same
+	// rules as Environ.Instances.
+	InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error)
+}
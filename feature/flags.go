@@ -31,10 +31,10 @@ const LeaderElection = "leader-election"
 const LogErrorStack = "log-error-stack"
 
 // EnvironmentsCacheFile is the feature flag that guards the writing of the
-// new $JUJU_HOME/environments/cache.yaml file.  If this flag is set, new
-// environments will be written to the cache file rather than a JENV file.
-// As JENV files are updated, they are migrated to the cache file and the
-// JENV file removed.
+// new controllers.yaml, accounts.yaml and environments.yaml files in
+// $JUJU_HOME/environments.  If this flag is set, new environments will be
+// written to those files rather than a JENV file.  As JENV files are
+// updated, they are migrated to those files and the JENV file removed.
 const EnvironmentsCacheFile = "env-cache"
 
 // LegacyUpstart is used to indicate that the version-based init system
@@ -42,6 +42,7 @@ const EnvironmentsCacheFile = "env-cache"
 // instead of systemd for vivid and newer.
 const LegacyUpstart = "legacy-upstart"
 
-// DbLog is the the feature which has Juju's logs go to
-// MongoDB instead of to all-machines.log using rsyslog.
-const DbLog = "db-log"
+// NestedUnitAgents is the name of the feature to run the agents of the
+// units deployed to a machine inside the machine agent process, rather
+// than as separate processes.
+const NestedUnitAgents = "nested-unit-agents"
@@ -51,6 +51,15 @@ func (s *cmdEnvironmentSuite) TestEnvironmentShareCmdStack(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *cmdEnvironmentSuite) TestEnvironmentShareReadOnly(c *gc.C) {
+	username := "bar@ubuntuone"
+	runShare(c, []string{"--acl", "read", username})
+
+	envUser, err := s.State.EnvironmentUser(names.NewUserTag(username))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+}
+
 func runUnshare(c *gc.C, args []string) *cmd.Context {
 	context, err := testing.RunCommand(c, envcmd.Wrap(&cmdenvironment.UnshareCommand{}), args...)
 	c.Assert(err, jc.ErrorIsNil)
@@ -29,6 +29,7 @@ import (
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/logsender"
 )
 
 type leadershipSuite struct {
@@ -100,7 +101,7 @@ func (s *leadershipSuite) SetUpTest(c *gc.C) {
 
 	// Create & start a machine agent so the tests have something to call into.
 	agentConf := agentcmd.AgentConf{DataDir: s.DataDir()}
-	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf)
+	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf, logsender.NewBufferedLogWriter(1024), nil)
 	s.machineAgent = machineAgentFactory(stateServer.Id())
 
 	// See comment in createMockJujudExecutable
@@ -363,7 +364,7 @@ func (s *uniterLeadershipSuite) SetUpTest(c *gc.C) {
 
 	// Create & start a machine agent so the tests have something to call into.
 	agentConf := agentcmd.AgentConf{DataDir: s.DataDir()}
-	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf)
+	machineAgentFactory := agentcmd.MachineAgentFactoryFn(&agentConf, &agentConf, logsender.NewBufferedLogWriter(1024), nil)
 	s.machineAgent = machineAgentFactory(stateServer.Id())
 
 	// See comment in createMockJujudExecutable
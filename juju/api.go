@@ -6,6 +6,7 @@ package juju
 import (
 	"fmt"
 	"io"
+	"os"
 	"time"
 
 	"github.com/juju/errors"
@@ -286,14 +287,23 @@ func apiInfoConnect(store configstore.Storage, info configstore.EnvironInfo, api
 		// with an empty UUID. Login will work for the same reasons.
 		logger.Warningf("ignoring invalid API endpoint environment UUID %v", endpoint.EnvironUUID)
 	}
+	userTag := environInfoUserTag(info)
 	apiInfo := &api.Info{
 		Addrs:      endpoint.Addresses,
 		CACert:     endpoint.CACert,
-		Tag:        environInfoUserTag(info),
+		Tag:        userTag,
 		Password:   info.APICredentials().Password,
 		EnvironTag: environTag,
 	}
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	dialOpts := api.DefaultDialOpts()
+	if !userTag.IsLocal() && apiInfo.Password == "" {
+		// Users from an external identity provider have no password,
+		// and log in with macaroons that the provider discharges.
+		apiInfo.Tag = nil
+		apiInfo.UseMacaroons = true
+		dialOpts.Discharger = newHTTPDischarger(os.Stderr)
+	}
+	st, err := apiOpen(apiInfo, dialOpts)
 	if err != nil {
 		return nil, &infoConnectError{err}
 	}
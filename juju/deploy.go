@@ -35,6 +35,12 @@ type DeployServiceParams struct {
 	// Networks holds a list of networks to required to start on boot.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// SpaceBinding, if not empty, names the network space to bind
+	// the service's endpoints to.
+	SpaceBinding string
+	// EndpointBindings maps the names of individual endpoints to the
+	// network spaces they are bound to instead of SpaceBinding.
+	EndpointBindings map[string]string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -91,6 +97,16 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, err
 		}
 	}
+	if args.SpaceBinding != "" {
+		if err := service.SetSpaceBinding(args.SpaceBinding); err != nil {
+			return nil, err
+		}
+	}
+	if len(args.EndpointBindings) > 0 {
+		if err := service.SetEndpointBindings(args.EndpointBindings); err != nil {
+			return nil, err
+		}
+	}
 	if args.Charm.Meta().Subordinate {
 		return service, nil
 	}
@@ -100,7 +116,7 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 		}
 	}
 	if args.NumUnits > 0 {
-		if _, err := AddUnits(st, service, args.NumUnits, args.ToMachineSpec); err != nil {
+		if _, err := addUnits(st, service, args.NumUnits, args.ToMachineSpec, &args.Constraints); err != nil {
 			return nil, err
 		}
 	}
@@ -110,6 +126,14 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 // AddUnits starts n units of the given service and allocates machines
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
+	return addUnits(st, svc, n, machineIdSpec, nil)
+}
+
+// addUnits is AddUnits, but refuses to place the units on an existing
+// machine that does not satisfy the requested constraints, if any. An
+// explicit placement still overrides the constraints the service and
+// environment already had, as it always has.
+func addUnits(st *state.State, svc *state.Service, n int, machineIdSpec string, requested *constraints.Value) ([]*state.Unit, error) {
 	units := make([]*state.Unit, n)
 	// Hard code for now till we implement a different approach.
 	policy := state.AssignCleanEmpty
@@ -165,10 +189,14 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 					RequestedNetworks: networks,
 				}
 				m, err = st.AddMachineInsideMachine(template, mid, containerType)
-			} else {
-				m, err = st.Machine(mid)
+			} else if m, err = st.Machine(mid); err == nil {
+				err = validatePlacement(unit, m, requested, unitCons)
 			}
 			if err != nil {
+				// Don't leave behind a unit that was never placed.
+				if err := unit.Destroy(); err != nil {
+					logger.Warningf("cannot destroy unplaced unit %q: %v", unit.Name(), err)
+				}
 				return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unit.Name(), err)
 			}
 			err = unit.AssignToMachine(m)
@@ -184,6 +212,93 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 	return units, nil
 }
 
+// validatePlacement returns an error if the unit cannot be placed on
+// the existing machine m: the machine must be alive, of the unit's
+// series, and it must satisfy the constraints requested for the
+// placement, if any. The unit's effective constraints only produce a
+// warning when the machine does not satisfy them.
+func validatePlacement(unit *state.Unit, m *state.Machine, requested, effective *constraints.Value) error {
+	if m.Life() != state.Alive {
+		return fmt.Errorf("machine %s is not alive", m.Id())
+	}
+	if unit.Series() != m.Series() {
+		return fmt.Errorf(
+			"series of unit (%s) does not match series of machine %s (%s)",
+			unit.Series(), m.Id(), m.Series(),
+		)
+	}
+	if requested != nil {
+		if err := checkMachineConstraints(m, requested); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := checkMachineConstraints(m, effective); err != nil {
+		logger.Warningf("placing unit %q on machine %s anyway: %v", unit.Name(), m.Id(), err)
+	}
+	return nil
+}
+
+// checkMachineConstraints returns an error if the machine m does not
+// satisfy the given constraints. Hardware constraints are only checked
+// once the machine is provisioned, since its hardware is not known
+// before then.
+func checkMachineConstraints(m *state.Machine, cons *constraints.Value) error {
+	if cons.Container != nil && *cons.Container != "" {
+		containerType := m.ContainerType()
+		if containerType == "" {
+			containerType = instance.NONE
+		}
+		if *cons.Container != containerType {
+			return fmt.Errorf(
+				"machine %s does not satisfy constraint container=%s", m.Id(), *cons.Container,
+			)
+		}
+	}
+	hc, err := m.HardwareCharacteristics()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	var unsatisfied []string
+	if cons.Arch != nil && *cons.Arch != "" && (hc.Arch == nil || *hc.Arch != *cons.Arch) {
+		unsatisfied = append(unsatisfied, "arch="+*cons.Arch)
+	}
+	if cons.CpuCores != nil && (hc.CpuCores == nil || *hc.CpuCores < *cons.CpuCores) {
+		unsatisfied = append(unsatisfied, fmt.Sprintf("cpu-cores=%d", *cons.CpuCores))
+	}
+	if cons.CpuPower != nil && (hc.CpuPower == nil || *hc.CpuPower < *cons.CpuPower) {
+		unsatisfied = append(unsatisfied, fmt.Sprintf("cpu-power=%d", *cons.CpuPower))
+	}
+	if cons.Mem != nil && (hc.Mem == nil || *hc.Mem < *cons.Mem) {
+		unsatisfied = append(unsatisfied, fmt.Sprintf("mem=%dM", *cons.Mem))
+	}
+	if cons.RootDisk != nil && (hc.RootDisk == nil || *hc.RootDisk < *cons.RootDisk) {
+		unsatisfied = append(unsatisfied, fmt.Sprintf("root-disk=%dM", *cons.RootDisk))
+	}
+	if cons.Tags != nil {
+		have := make(map[string]bool)
+		if hc.Tags != nil {
+			for _, tag := range *hc.Tags {
+				have[tag] = true
+			}
+		}
+		for _, tag := range *cons.Tags {
+			if !have[tag] {
+				unsatisfied = append(unsatisfied, "tags="+strings.Join(*cons.Tags, ","))
+				break
+			}
+		}
+	}
+	if len(unsatisfied) > 0 {
+		return fmt.Errorf(
+			"machine %s (%s) does not satisfy constraints %s",
+			m.Id(), hc, strings.Join(unsatisfied, " "),
+		)
+	}
+	return nil
+}
+
 func stateStorageConstraints(cons map[string]storage.Constraints) map[string]state.StorageConstraints {
 	result := make(map[string]state.StorageConstraints)
 	for name, cons := range cons {
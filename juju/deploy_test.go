@@ -102,6 +102,40 @@ func (s *DeployLocalSuite) TestDeploySettings(c *gc.C) {
 	})
 }
 
+func (s *DeployLocalSuite) TestDeploySpaceBinding(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:  "bob",
+			Charm:        s.charm,
+			SpaceBinding: "dmz",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.SpaceBinding(), gc.Equals, "dmz")
+}
+
+func (s *DeployLocalSuite) TestDeployEndpointBindings(c *gc.C) {
+	for _, name := range []string{"dmz", "internal"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			SpaceBinding:     "internal",
+			EndpointBindings: map[string]string{"juju-info": "dmz"},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.SpaceBinding(), gc.Equals, "internal")
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"juju-info": "dmz"})
+}
+
 func (s *DeployLocalSuite) TestDeploySettingsError(c *gc.C) {
 	_, err := juju.DeployService(s.State,
 		juju.DeployServiceParams{
@@ -214,6 +248,112 @@ func (s *DeployLocalSuite) TestDeployForceMachineIdWithContainer(c *gc.C) {
 	c.Assert(machineCons, gc.DeepEquals, *unitCons)
 }
 
+func (s *DeployLocalSuite) TestDeployForceMachineIdExistingContainer(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	container, err := s.State.AddMachineInsideMachine(template, "0", instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Id(), gc.Equals, "0/lxc/0")
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "0/lxc/0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachines(c, service, constraints.Value{}, "0/lxc/0")
+}
+
+func (s *DeployLocalSuite) TestAddUnitsToMachineHostingUnits(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.AddUnits(s.State, service, 1, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachines(c, service, constraints.Value{}, "0", "0")
+}
+
+func (s *DeployLocalSuite) assertPlacementRejected(c *gc.C, serviceCons constraints.Value, expectErr string) {
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			Constraints:   serviceCons,
+			NumUnits:      1,
+			ToMachineSpec: "0",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine: `+expectErr)
+	c.Assert(service, gc.IsNil)
+
+	// The unit is not left behind unassigned.
+	service, err = s.State.Service("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *DeployLocalSuite) TestDeployForceMachineIdSeriesMismatch(c *gc.C) {
+	_, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlacementRejected(c, constraints.Value{},
+		`series of unit \(quantal\) does not match series of machine 0 \(precise\)`,
+	)
+}
+
+func (s *DeployLocalSuite) TestDeployForceMachineIdContainerMismatch(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlacementRejected(c, constraints.MustParse("container=lxc"),
+		`machine 0 does not satisfy constraint container=lxc`,
+	)
+}
+
+func (s *DeployLocalSuite) TestDeployForceMachineIdUnsatisfiedConstraints(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64 mem=1G cpu-cores=4")
+	err = machine.SetProvisioned("i-0", "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlacementRejected(c, constraints.MustParse("mem=2G cpu-cores=2"),
+		`machine 0 \(arch=amd64 cpu-cores=4 mem=1024M\) does not satisfy constraints mem=2048M`,
+	)
+}
+
+func (s *DeployLocalSuite) TestAddUnitsOverridesServiceConstraints(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	hc := instance.MustParseHardware("arch=amd64 mem=1G")
+	err = machine.SetProvisioned("i-0", "fake_nonce", &hc)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			Constraints: constraints.MustParse("mem=2G"),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only constraints given along with the placement are enforced;
+	// the service's constraints are overridden by it.
+	_, err = juju.AddUnits(s.State, service, 1, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachines(c, service, constraints.Value{}, "0")
+	c.Assert(c.GetTestLog(), jc.Contains, `placing unit "bob/0" on machine 0 anyway`)
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, service *state.Service, expect *charm.URL) {
 	curl, force := service.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
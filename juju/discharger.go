@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package juju
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"gopkg.in/macaroon-bakery.v0/httpbakery"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/api"
+)
+
+// httpDischarger is an api.MacaroonDischarger that acquires the
+// discharges of third-party caveats from their locations over HTTP.
+type httpDischarger struct {
+	client *http.Client
+	out    io.Writer
+}
+
+var _ api.MacaroonDischarger = (*httpDischarger)(nil)
+
+// newHTTPDischarger returns a discharger that writes to out the URLs
+// of the pages that the user needs to visit to log in to the identity
+// provider.
+func newHTTPDischarger(out io.Writer) *httpDischarger {
+	return &httpDischarger{
+		client: httpbakery.NewHTTPClient(),
+		out:    out,
+	}
+}
+
+// DischargeAll implements api.MacaroonDischarger.
+func (d *httpDischarger) DischargeAll(m *macaroon.Macaroon) (macaroon.Slice, error) {
+	return httpbakery.DischargeAll(m, d.client, d.visitWebPage)
+}
+
+// visitWebPage asks the user to visit the given URL to log in; the
+// discharge completes once they have.
+func (d *httpDischarger) visitWebPage(u *url.URL) error {
+	fmt.Fprintf(d.out, "Please visit this web page to log in:\n%s\n", u)
+	return nil
+}
@@ -155,11 +155,29 @@ func StartInstanceWithParams(
 	networks []string,
 ) (
 	*environs.StartInstanceResult, error,
+) {
+	params, err := CompleteStartInstanceParams(env, machineId, params, networks)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return env.StartInstance(params)
+}
+
+// CompleteStartInstanceParams is a test helper function that returns
+// the given parameters for starting an instance completed with tools
+// and a plausible but invalid machine configuration. The provided
+// params's MachineConfig and Tools field values will be ignored.
+func CompleteStartInstanceParams(
+	env environs.Environ, machineId string,
+	params environs.StartInstanceParams,
+	networks []string,
+) (
+	environs.StartInstanceParams, error,
 ) {
 	series := config.PreferredSeries(env.Config())
 	agentVersion, ok := env.Config().AgentVersion()
 	if !ok {
-		return nil, errors.New("missing agent version in environment config")
+		return params, errors.New("missing agent version in environment config")
 	}
 	filter := coretools.Filter{
 		Number: agentVersion,
@@ -170,7 +188,7 @@ func StartInstanceWithParams(
 	}
 	possibleTools, err := tools.FindTools(env, -1, -1, filter)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return params, errors.Trace(err)
 	}
 	machineNonce := "fake_nonce"
 	stateInfo := FakeStateInfo(machineId)
@@ -186,9 +204,9 @@ func StartInstanceWithParams(
 		apiInfo,
 	)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return params, errors.Trace(err)
 	}
 	params.Tools = possibleTools
 	params.MachineConfig = machineConfig
-	return env.StartInstance(params)
+	return params, nil
 }
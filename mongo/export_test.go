@@ -4,6 +4,8 @@
 package mongo
 
 import (
+	"time"
+
 	"github.com/juju/juju/service/common"
 	svctesting "github.com/juju/juju/service/common/testing"
 )
@@ -17,8 +19,6 @@ var (
 	SharedSecretPath = sharedSecretPath
 	SSLKeyPath       = sslKeyPath
 
-	NewConf = newConf
-
 	HostWordSize   = &hostWordSize
 	RuntimeGOOS    = &runtimeGOOS
 	AvailSpace     = &availSpace
@@ -26,12 +26,32 @@ var (
 	MaxOplogSizeMB = &maxOplogSizeMB
 	PreallocFile   = &preallocFile
 
+	MemTotal      = &memTotal
+	MongodVersion = &mongodVersion
+
 	DefaultOplogSize  = defaultOplogSize
+	DefaultCacheSize  = defaultCacheSize
 	FsAvailSpace      = fsAvailSpace
 	PreallocFileSizes = preallocFileSizes
 	PreallocFiles     = preallocFiles
 )
 
+// NewConf returns the mongo service config for the given oplog size,
+// using mongod's defaults for the other tuned settings.
+func NewConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCtl bool) common.Conf {
+	return newConf(dataDir, dbDir, mongoPath, port, tuning{oplogSizeMB: oplogSizeMB}, wantNumaCtl)
+}
+
+// NewTunedConf returns the mongo service config for the given tuned
+// settings.
+func NewTunedConf(dataDir string, oplogSizeMB, cacheSizeGB int, journalCommitInterval time.Duration) common.Conf {
+	return newConf(dataDir, dataDir, JujuMongodPath, 1234, tuning{
+		oplogSizeMB:           oplogSizeMB,
+		cacheSizeGB:           cacheSizeGB,
+		journalCommitInterval: journalCommitInterval,
+	}, false)
+}
+
 func PatchService(patchValue func(interface{}, interface{}), data *svctesting.FakeServiceData) {
 	patchValue(&discoverService, func(name string) (mongoService, error) {
 		svc := svctesting.NewFakeService(name, common.Conf{})
@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -151,6 +152,18 @@ type EnsureServerParams struct {
 	// algorithm defined in Mongo.
 	OplogSize int
 
+	// CacheSizeGB is the size of the WiredTiger cache used by
+	// mongod 3.0 and later. If this is zero, then EnsureServer
+	// will calculate a size from the machine's memory. Earlier
+	// versions of mongod have no configurable cache, and the
+	// size is ignored.
+	CacheSizeGB int
+
+	// JournalCommitInterval is the maximum time mongod waits
+	// between journal commits, which must be between 2ms and
+	// 300ms. If this is zero, mongod's default is used.
+	JournalCommitInterval time.Duration
+
 	// SetNumaControlPolicy preference - whether the user
 	// wants to set the numa control policy when starting mongo.
 	SetNumaControlPolicy bool
@@ -171,15 +184,22 @@ func EnsureServer(args EnsureServerParams) error {
 		args.DataDir, args.StatePort,
 	)
 
+	if err := validateJournalCommitInterval(args.JournalCommitInterval); err != nil {
+		return errors.Trace(err)
+	}
 	dbDir := filepath.Join(args.DataDir, "db")
 	if err := os.MkdirAll(dbDir, 0700); err != nil {
 		return fmt.Errorf("cannot create mongo database directory: %v", err)
 	}
 
-	oplogSizeMB := args.OplogSize
-	if oplogSizeMB == 0 {
+	tune := tuning{
+		oplogSizeMB:           args.OplogSize,
+		cacheSizeGB:           args.CacheSizeGB,
+		journalCommitInterval: args.JournalCommitInterval,
+	}
+	if tune.oplogSizeMB == 0 {
 		var err error
-		if oplogSizeMB, err = defaultOplogSize(dbDir); err != nil {
+		if tune.oplogSizeMB, err = defaultOplogSize(dbDir); err != nil {
 			return err
 		}
 	}
@@ -192,8 +212,15 @@ func EnsureServer(args EnsureServerParams) error {
 		return err
 	}
 	logVersion(mongoPath)
+	if tune.cacheSizeGB == 0 {
+		if tune.cacheSizeGB, err = defaultCacheSize(mongoPath); err != nil {
+			// mongod chooses its own cache size.
+			logger.Warningf("cannot calculate mongo cache size: %v", err)
+		}
+	}
+	logger.Infof("mongo oplog size %dMB, cache size %dGB", tune.oplogSizeMB, tune.cacheSizeGB)
 
-	svcConf := newConf(args.DataDir, dbDir, mongoPath, args.StatePort, oplogSizeMB, args.SetNumaControlPolicy)
+	svcConf := newConf(args.DataDir, dbDir, mongoPath, args.StatePort, tune, args.SetNumaControlPolicy)
 	svc, err := newService(ServiceName(args.Namespace), svcConf)
 	if err != nil {
 		return err
@@ -249,7 +276,7 @@ func EnsureServer(args EnsureServerParams) error {
 	if err := makeJournalDirs(dbDir); err != nil {
 		return fmt.Errorf("error creating journal directories: %v", err)
 	}
-	if err := preallocOplog(dbDir, oplogSizeMB); err != nil {
+	if err := preallocOplog(dbDir, tune.oplogSizeMB); err != nil {
 		return fmt.Errorf("error creating oplog files: %v", err)
 	}
 	if err := service.InstallAndStart(svc); err != nil {
@@ -265,6 +292,25 @@ func UpdateSSLKey(dataDir, cert, privateKey string) error {
 	return errors.Annotate(err, "cannot write SSL key")
 }
 
+// RotateSSLKey writes a new SSL key used by mongo and restarts the mongo
+// service in the given namespace so that the key takes effect.
+func RotateSSLKey(dataDir, namespace, cert, privateKey string) error {
+	if err := UpdateSSLKey(dataDir, cert, privateKey); err != nil {
+		return errors.Trace(err)
+	}
+	svc, err := discoverService(ServiceName(namespace))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := svc.Stop(); err != nil {
+		return errors.Annotate(err, "cannot stop mongo")
+	}
+	if err := svc.Start(); err != nil {
+		return errors.Annotate(err, "cannot start mongo")
+	}
+	return nil
+}
+
 func makeJournalDirs(dataDir string) error {
 	journalDir := path.Join(dataDir, "journal")
 	if err := os.MkdirAll(journalDir, 0700); err != nil {
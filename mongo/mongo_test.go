@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -312,6 +313,14 @@ func (s *MongoSuite) TestInstallMongodServiceExists(c *gc.C) {
 	c.Check(cmds, gc.HasLen, 1)
 }
 
+func (s *MongoSuite) TestEnsureServerInvalidJournalCommitInterval(c *gc.C) {
+	params := makeEnsureServerParams(c.MkDir(), "")
+	params.JournalCommitInterval = time.Second
+	err := mongo.EnsureServer(params)
+	c.Assert(err, gc.ErrorMatches, `journal commit interval 1s \(must be between 2ms and 300ms\) not valid`)
+	c.Assert(s.data.Installed, gc.HasLen, 0)
+}
+
 func (s *MongoSuite) TestNewServiceWithReplSet(c *gc.C) {
 	dataDir := c.MkDir()
 
@@ -368,6 +377,20 @@ func (s *MongoSuite) TestRemoveService(c *gc.C) {
 	s.data.CheckCallNames(c, "Stop", "Remove")
 }
 
+func (s *MongoSuite) TestRotateSSLKey(c *gc.C) {
+	dataDir := c.MkDir()
+	namespace := "namespace"
+	s.data.SetStatus(mongo.ServiceName(namespace), "running")
+
+	err := mongo.RotateSSLKey(dataDir, namespace, "cert", "key")
+	c.Assert(err, jc.ErrorIsNil)
+
+	contents, err := ioutil.ReadFile(mongo.SSLKeyPath(dataDir))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(contents), gc.Equals, "cert\nkey")
+	s.data.CheckCallNames(c, "Stop", "Start")
+}
+
 func (s *MongoSuite) TestQuantalAptAddRepo(c *gc.C) {
 	dir := c.MkDir()
 	s.PatchEnvPathPrepend(dir)
@@ -101,12 +101,15 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 	if len(info.CACert) == 0 {
 		return nil, stderrors.New("missing CA certificate")
 	}
-	xcert, err := cert.ParseCert(info.CACert)
-	if err != nil {
+	// The CA certificate may hold several certificates while the
+	// state servers' certificates are being rotated to a new CA.
+	if _, err := cert.ParseCert(info.CACert); err != nil {
 		return nil, fmt.Errorf("cannot parse CA certificate: %v", err)
 	}
 	pool := x509.NewCertPool()
-	pool.AddCert(xcert)
+	if !pool.AppendCertsFromPEM([]byte(info.CACert)) {
+		return nil, stderrors.New("cannot parse CA certificate: no certificates found")
+	}
 	tlsConfig := &tls.Config{
 		RootCAs:    pool,
 		ServerName: "juju-mongodb",
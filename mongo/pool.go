@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+)
+
+// ReadPreference describes which members of a replica set may serve
+// the reads made with a session.
+type ReadPreference string
+
+const (
+	// Primary reads are only served by the primary. This is the
+	// only preference that guarantees reads see all preceding
+	// writes.
+	Primary ReadPreference = "primary"
+
+	// PrimaryPreferred reads are served by the primary if it can be
+	// reached, and by a secondary otherwise.
+	PrimaryPreferred ReadPreference = "primaryPreferred"
+
+	// Nearest reads are served by the member with the lowest
+	// network latency, whether it is the primary or a secondary.
+	Nearest ReadPreference = "nearest"
+)
+
+// ParseReadPreference returns the read preference with the given name.
+// The empty string is taken to mean Primary.
+func ParseReadPreference(s string) (ReadPreference, error) {
+	switch pref := ReadPreference(s); pref {
+	case "":
+		return Primary, nil
+	case Primary, PrimaryPreferred, Nearest:
+		return pref, nil
+	}
+	return "", errors.NotValidf("read preference %q", s)
+}
+
+// primaryPingTimeout is the time a PrimaryPreferred session will wait
+// for the primary before falling back to a secondary.
+var primaryPingTimeout = 5 * time.Second
+
+// SessionPool hands out sessions copied from a single base session,
+// with a read preference chosen by the category of the caller. This
+// allows heavy read-only work, such as reporting status, to be served
+// by the secondaries of a replica set while everything else continues
+// to use the primary.
+type SessionPool struct {
+	mu       sync.Mutex
+	session  *mgo.Session
+	prefs    map[string]ReadPreference
+	sessions map[mgo.Mode]*mgo.Session
+}
+
+// NewSessionPool returns a pool handing out copies of session. The read
+// preference used for each category is taken from prefs; categories
+// not found there use Primary. The pool owns its own copy of session,
+// which is closed when the pool is closed.
+func NewSessionPool(session *mgo.Session, prefs map[string]ReadPreference) *SessionPool {
+	p := &SessionPool{
+		session:  session.Copy(),
+		prefs:    make(map[string]ReadPreference),
+		sessions: make(map[mgo.Mode]*mgo.Session),
+	}
+	for category, pref := range prefs {
+		p.prefs[category] = pref
+	}
+	return p
+}
+
+// SetReadPreference sets the read preference used for sessions of the
+// given category.
+func (p *SessionPool) SetReadPreference(category string, pref ReadPreference) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prefs[category] = pref
+}
+
+// ReadPreference returns the read preference used for sessions of the
+// given category.
+func (p *SessionPool) ReadPreference(category string) ReadPreference {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pref, ok := p.prefs[category]; ok {
+		return pref
+	}
+	return Primary
+}
+
+// Session returns a new session for the given category, which must be
+// closed by the caller when it is no longer needed.
+func (p *SessionPool) Session(category string) (*mgo.Session, error) {
+	switch pref := p.ReadPreference(category); pref {
+	case Primary:
+		return p.copy(mgo.Strong)
+	case Nearest:
+		// mgo sends eventually consistent reads to the
+		// reachable member with the lowest ping time.
+		return p.copy(mgo.Eventual)
+	case PrimaryPreferred:
+		session, err := p.copy(mgo.Strong)
+		if err != nil {
+			return nil, err
+		}
+		session.SetSyncTimeout(primaryPingTimeout)
+		err = session.Ping()
+		session.Close()
+		if err == nil {
+			return p.copy(mgo.Strong)
+		}
+		logger.Debugf("primary not reachable, reading from secondary: %v", err)
+		return p.copy(mgo.Monotonic)
+	default:
+		return nil, errors.NotValidf("read preference %q", pref)
+	}
+}
+
+// copy returns a copy of the pool's base session for the given mode.
+func (p *SessionPool) copy(mode mgo.Mode) (*mgo.Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.session == nil {
+		return nil, errors.New("session pool closed")
+	}
+	base, ok := p.sessions[mode]
+	if !ok {
+		base = p.session.Copy()
+		base.SetMode(mode, true)
+		p.sessions[mode] = base
+	}
+	return base.Copy(), nil
+}
+
+// Close closes the pool's sessions. Sessions already handed out by the
+// pool are not affected, and must still be closed by their users.
+func (p *SessionPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for mode, session := range p.sessions {
+		session.Close()
+		delete(p.sessions, mode)
+	}
+	if p.session != nil {
+		p.session.Close()
+		p.session = nil
+	}
+}
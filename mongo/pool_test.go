@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo_test
+
+import (
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type poolSuite struct {
+	coretesting.BaseSuite
+	inst    *gitjujutesting.MgoInstance
+	session *mgo.Session
+}
+
+var _ = gc.Suite(&poolSuite{})
+
+func (s *poolSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.inst = &gitjujutesting.MgoInstance{}
+	err := s.inst.Start(coretesting.Certs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.session = s.inst.MustDial()
+}
+
+func (s *poolSuite) TearDownTest(c *gc.C) {
+	s.session.Close()
+	s.inst.DestroyWithLog()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *poolSuite) TestParseReadPreference(c *gc.C) {
+	for _, t := range []struct {
+		in     string
+		expect mongo.ReadPreference
+	}{
+		{"", mongo.Primary},
+		{"primary", mongo.Primary},
+		{"primaryPreferred", mongo.PrimaryPreferred},
+		{"nearest", mongo.Nearest},
+	} {
+		pref, err := mongo.ParseReadPreference(t.in)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(pref, gc.Equals, t.expect)
+	}
+	_, err := mongo.ParseReadPreference("secondary")
+	c.Assert(err, gc.ErrorMatches, `read preference "secondary" not valid`)
+}
+
+func (s *poolSuite) TestSessionModes(c *gc.C) {
+	pool := mongo.NewSessionPool(s.session, map[string]mongo.ReadPreference{
+		"status":  mongo.Nearest,
+		"backups": mongo.PrimaryPreferred,
+	})
+	defer pool.Close()
+
+	for _, t := range []struct {
+		category string
+		pref     mongo.ReadPreference
+		mode     mgo.Mode
+	}{
+		{"", mongo.Primary, mgo.Strong},
+		{"status", mongo.Nearest, mgo.Eventual},
+		// The primary is reachable.
+		{"backups", mongo.PrimaryPreferred, mgo.Strong},
+	} {
+		c.Logf("category %q", t.category)
+		c.Check(pool.ReadPreference(t.category), gc.Equals, t.pref)
+		session, err := pool.Session(t.category)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(session.Mode(), gc.Equals, t.mode)
+		c.Check(session.Ping(), jc.ErrorIsNil)
+		session.Close()
+	}
+}
+
+func (s *poolSuite) TestSetReadPreference(c *gc.C) {
+	pool := mongo.NewSessionPool(s.session, nil)
+	defer pool.Close()
+	c.Assert(pool.ReadPreference("status"), gc.Equals, mongo.Primary)
+
+	pool.SetReadPreference("status", mongo.Nearest)
+	c.Assert(pool.ReadPreference("status"), gc.Equals, mongo.Nearest)
+	session, err := pool.Session("status")
+	c.Assert(err, jc.ErrorIsNil)
+	defer session.Close()
+	c.Assert(session.Mode(), gc.Equals, mgo.Eventual)
+}
+
+func (s *poolSuite) TestClose(c *gc.C) {
+	pool := mongo.NewSessionPool(s.session, nil)
+	session, err := pool.Session("")
+	c.Assert(err, jc.ErrorIsNil)
+	defer session.Close()
+	pool.Close()
+
+	// Sessions handed out remain usable.
+	c.Assert(session.Ping(), jc.ErrorIsNil)
+	_, err = pool.Session("")
+	c.Assert(err, gc.ErrorMatches, "session pool closed")
+}
@@ -30,7 +30,12 @@ var (
 	zeroes = make([]byte, 64*1024)
 
 	minOplogSizeMB = 512
-	maxOplogSizeMB = 1024
+	maxOplogSizeMB = 8192
+
+	// maxOplogFraction is the largest fraction of the available
+	// disk space that the oplog may take, even if that makes it
+	// smaller than minOplogSizeMB.
+	maxOplogFraction = 0.1
 
 	availSpace   = fsAvailSpace
 	preallocFile = doPreallocFile
@@ -54,7 +59,11 @@ func preallocOplog(dir string, oplogSizeMB int) error {
 //
 // NOTE: we deviate from the specified minimum and maximum
 //       sizes. Mongo suggests a minimum of 1GB and maximum
-//       of 50GB; we set these to 512MB and 1GB respectively.
+//       of 50GB; we set these to 512MB and 8GB respectively,
+//       as the whole oplog is preallocated when mongo is
+//       installed. The oplog never takes more than a tenth
+//       of the available space, so that small disks are not
+//       exhausted.
 func defaultOplogSize(dir string) (int, error) {
 	if hostWordSize == 32 {
 		// "For 32-bit systems, MongoDB allocates about 48 megabytes
@@ -83,6 +92,10 @@ func defaultOplogSize(dir string) (int, error) {
 	} else if size > maxOplogSizeMB {
 		size = maxOplogSizeMB
 	}
+	if limit := int(avail * maxOplogFraction); limit > 0 && size > limit {
+		logger.Warningf("only %dMB available for the mongo database; limiting oplog size to %dMB", int(avail), limit)
+		size = limit
+	}
 	return size, nil
 }
 
@@ -49,17 +49,27 @@ func (s *preallocSuite) TestOplogSize(c *gc.C) {
 		hostWordSize: 64,
 		runtimeGOOS:  "linux",
 		availSpace:   1024,
+		expected:     102,
+	}, {
+		hostWordSize: 64,
+		runtimeGOOS:  "linux",
+		availSpace:   8 * 1024,
 		expected:     512,
+	}, {
+		hostWordSize: 64,
+		runtimeGOOS:  "linux",
+		availSpace:   40 * 1024,
+		expected:     2048,
 	}, {
 		hostWordSize: 64,
 		runtimeGOOS:  "linux",
 		availSpace:   420 * 1024,
-		expected:     1024,
+		expected:     8192,
 	}, {
 		hostWordSize: 64,
 		runtimeGOOS:  "linux",
 		availSpace:   1024 * 1024,
-		expected:     1024,
+		expected:     8192,
 	}}
 	var availSpace int
 	getAvailSpace := func(dir string) (float64, error) {
@@ -77,6 +87,49 @@ func (s *preallocSuite) TestOplogSize(c *gc.C) {
 	}
 }
 
+func (s *preallocSuite) TestCacheSize(c *gc.C) {
+	for i, test := range []struct {
+		major    int
+		memMB    int
+		expected int
+	}{
+		{major: 2, memMB: 16 * 1024, expected: 0},
+		{major: 3, memMB: 2 * 1024, expected: 1},
+		{major: 3, memMB: 16 * 1024, expected: 4},
+	} {
+		c.Logf("test %d: %+v", i, test)
+		major := test.major
+		s.PatchValue(mongo.MongodVersion, func(string) (int, int, error) {
+			return major, 0, nil
+		})
+		memMB := test.memMB
+		s.PatchValue(mongo.MemTotal, func() (int, error) {
+			return memMB, nil
+		})
+		size, err := mongo.DefaultCacheSize("mongod")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(size, gc.Equals, test.expected)
+	}
+}
+
+func (s *preallocSuite) TestCacheSizeMongodVersion(c *gc.C) {
+	dir := c.MkDir()
+	mongod := filepath.Join(dir, "mongod")
+	err := ioutil.WriteFile(mongod, []byte("#!/bin/sh\necho 'db version v3.0.4'\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(mongo.MemTotal, func() (int, error) {
+		return 8 * 1024, nil
+	})
+	size, err := mongo.DefaultCacheSize(mongod)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, 2)
+
+	err = ioutil.WriteFile(mongod, []byte("#!/bin/sh\necho 'nonsense'\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = mongo.DefaultCacheSize(mongod)
+	c.Assert(err, gc.ErrorMatches, `cannot determine mongod version: unexpected output from .*`)
+}
+
 func (s *preallocSuite) TestFsAvailSpace(c *gc.C) {
 	output := `Filesystem     1K-blocks    Used Available Use% Mounted on
     /dev/vda1        8124856 1365292     12345  18% /`
@@ -6,7 +6,6 @@ package mongo
 import (
 	"fmt"
 	"path/filepath"
-	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -100,7 +99,7 @@ func sharedSecretPath(dataDir string) string {
 }
 
 // newConf returns the init system config for the mongo state service.
-func newConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCtl bool) common.Conf {
+func newConf(dataDir, dbDir, mongoPath string, port int, tune tuning, wantNumaCtl bool) common.Conf {
 	mongoCmd := mongoPath +
 		" --auth" +
 		" --dbpath " + utils.ShQuote(dbDir) +
@@ -115,7 +114,7 @@ func newConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCt
 		" --keyFile " + utils.ShQuote(sharedSecretPath(dataDir)) +
 		" --replSet " + ReplicaSetName +
 		" --ipv6" +
-		" --oplogSize " + strconv.Itoa(oplogSizeMB)
+		tune.args()
 	extraScript := ""
 	if wantNumaCtl {
 		extraScript = fmt.Sprintf(detectMultiNodeScript, multinodeVarName, multinodeVarName)
@@ -5,6 +5,7 @@ package mongo_test
 
 import (
 	"strings"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -54,3 +55,11 @@ func (s *serviceSuite) TestNewConf(c *gc.C) {
 	c.Check(conf, jc.DeepEquals, expected)
 	c.Check(strings.Fields(conf.ExecStart), jc.DeepEquals, strings.Fields(expected.ExecStart))
 }
+
+func (s *serviceSuite) TestNewConfTuned(c *gc.C) {
+	conf := mongo.NewTunedConf("/var/lib/juju", 2048, 4, 50*time.Millisecond)
+	c.Check(conf.ExecStart, gc.Matches, `.* --oplogSize 2048 --wiredTigerCacheSizeGB 4 --journalCommitInterval 50$`)
+
+	conf = mongo.NewTunedConf("/var/lib/juju", 2048, 0, 0)
+	c.Check(conf.ExecStart, gc.Matches, `.* --oplogSize 2048$`)
+}
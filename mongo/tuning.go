@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+var (
+	memTotal      = procMemTotal
+	mongodVersion = getMongodVersion
+
+	mongodVersionRE = regexp.MustCompile(`db version v(\d+)\.(\d+)`)
+)
+
+// tuning holds the mongod settings that EnsureServer chooses
+// according to the machine it is running on.
+type tuning struct {
+	// oplogSizeMB is the size of the oplog.
+	oplogSizeMB int
+
+	// cacheSizeGB is the size of the WiredTiger cache. If zero,
+	// mongod's default is used.
+	cacheSizeGB int
+
+	// journalCommitInterval is the maximum time between journal
+	// commits. If zero, mongod's default is used.
+	journalCommitInterval time.Duration
+}
+
+// args returns the mongod arguments for the settings.
+func (t tuning) args() string {
+	args := " --oplogSize " + strconv.Itoa(t.oplogSizeMB)
+	if t.cacheSizeGB > 0 {
+		args += " --wiredTigerCacheSizeGB " + strconv.Itoa(t.cacheSizeGB)
+	}
+	if t.journalCommitInterval > 0 {
+		ms := int(t.journalCommitInterval / time.Millisecond)
+		args += " --journalCommitInterval " + strconv.Itoa(ms)
+	}
+	return args
+}
+
+// validateJournalCommitInterval returns an error if mongod will not
+// accept the given journal commit interval.
+func validateJournalCommitInterval(interval time.Duration) error {
+	if interval == 0 {
+		return nil
+	}
+	if interval < 2*time.Millisecond || interval > 300*time.Millisecond {
+		return errors.NotValidf("journal commit interval %v (must be between 2ms and 300ms)", interval)
+	}
+	return nil
+}
+
+// defaultCacheSize returns the size in GB of the WiredTiger cache to
+// use with the given mongod, or zero if the mongod predates WiredTiger
+// and so has no configurable cache.
+//
+// Mongo's own default is half of the machine's memory less 1GB; the
+// state server also runs the API server, so we leave it a larger share
+// and use a quarter of the memory, with a minimum of 1GB.
+func defaultCacheSize(mongoPath string) (int, error) {
+	major, _, err := mongodVersion(mongoPath)
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot determine mongod version")
+	}
+	if major < 3 {
+		return 0, nil
+	}
+	memMB, err := memTotal()
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot determine memory size")
+	}
+	size := memMB / 4 / 1024
+	if size < 1 {
+		size = 1
+	}
+	return size, nil
+}
+
+// getMongodVersion returns the major and minor version of the mongod
+// at the given path.
+func getMongodVersion(mongoPath string) (major, minor int, err error) {
+	output, err := exec.Command(mongoPath, "--version").CombinedOutput()
+	if err != nil {
+		return 0, 0, errors.Annotatef(err, "cannot run %s --version", mongoPath)
+	}
+	matches := mongodVersionRE.FindStringSubmatch(string(output))
+	if matches == nil {
+		return 0, 0, errors.Errorf("unexpected output from %s --version: %q", mongoPath, output)
+	}
+	major, _ = strconv.Atoi(matches[1])
+	minor, _ = strconv.Atoi(matches[2])
+	return major, minor, nil
+}
+
+// procMemTotal returns the total memory of the machine in MB, as
+// reported by /proc/meminfo.
+func procMemTotal() (int, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, errors.Annotatef(err, "cannot parse MemTotal %q", fields[1])
+		}
+		return kb / 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return 0, errors.New("MemTotal not found in /proc/meminfo")
+}
@@ -3,7 +3,10 @@
 
 package network
 
-var NetLookupIP = &netLookupIP
+var (
+	NetLookupIP  = &netLookupIP
+	NetLookupSRV = &netLookupSRV
+)
 
 func SetPreferIPv6(value bool) {
 	globalPreferIPv6 = value
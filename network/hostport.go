@@ -7,6 +7,7 @@ import (
 	"net"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -176,6 +177,34 @@ func ResolveOrDropHostnames(hps []HostPort) []HostPort {
 	return result
 }
 
+var netLookupSRV = net.LookupSRV
+
+// LookupSRVHostPorts returns the host-ports of the targets of the DNS
+// SRV records with the given name (for example
+// "_juju-api._tcp.example.com"), ordered by priority and randomized by
+// weight within each priority, as described in RFC 2782.
+func LookupSRVHostPorts(name string) ([]HostPort, error) {
+	_, records, err := netLookupSRV("", "", name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hps := make([]HostPort, 0, len(records))
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		if target == "" {
+			continue
+		}
+		hps = append(hps, HostPort{
+			Address: NewAddress(target),
+			Port:    int(record.Port),
+		})
+	}
+	if len(hps) == 0 {
+		return nil, errors.NotFoundf("SRV records for %q", name)
+	}
+	return hps, nil
+}
+
 // FilterUnusableHostPorts returns a copy of the given HostPorts after
 // removing any addresses unlikely to be usable (ScopeMachineLocal or
 // ScopeLinkLocal).
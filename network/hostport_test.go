@@ -144,6 +144,37 @@ func (s *HostPortSuite) TestResolveOrDropHostnames(c *gc.C) {
 	))
 }
 
+func (s *HostPortSuite) TestLookupSRVHostPorts(c *gc.C) {
+	s.PatchValue(network.NetLookupSRV, func(service, proto, name string) (string, []*net.SRV, error) {
+		c.Assert(name, gc.Equals, "_juju-api._tcp.example.com")
+		return "", []*net.SRV{
+			{Target: "api-1.example.com.", Port: 17070, Priority: 10},
+			{Target: ".", Port: 17070, Priority: 10},
+			{Target: "10.0.0.2.", Port: 17071, Priority: 20},
+		}, nil
+	})
+	hps, err := network.LookupSRVHostPorts("_juju-api._tcp.example.com")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(network.HostPortsToStrings(hps), jc.DeepEquals, []string{
+		"api-1.example.com:17070",
+		"10.0.0.2:17071",
+	})
+}
+
+func (s *HostPortSuite) TestLookupSRVHostPortsErrors(c *gc.C) {
+	s.PatchValue(network.NetLookupSRV, func(service, proto, name string) (string, []*net.SRV, error) {
+		return "", nil, errors.New("lookup failed")
+	})
+	_, err := network.LookupSRVHostPorts("_juju-api._tcp.example.com")
+	c.Assert(err, gc.ErrorMatches, "lookup failed")
+
+	s.PatchValue(network.NetLookupSRV, func(service, proto, name string) (string, []*net.SRV, error) {
+		return "", []*net.SRV{{Target: ".", Port: 17070}}, nil
+	})
+	_, err = network.LookupSRVHostPorts("_juju-api._tcp.example.com")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *HostPortSuite) TestFilterUnusableHostPorts(c *gc.C) {
 	// The order is preserved, but machine- and link-local addresses
 	// are dropped.
@@ -5,6 +5,7 @@ package network
 
 import (
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,6 +18,11 @@ type PortRange struct {
 	FromPort int
 	ToPort   int
 	Protocol string
+
+	// SourceCIDR, if not empty, restricts the port range to
+	// connections from addresses in the given CIDR. An empty
+	// SourceCIDR means connections from anywhere.
+	SourceCIDR string
 }
 
 // IsValid determines if the port range is valid.
@@ -39,6 +45,11 @@ func (p PortRange) Validate() error {
 	case p.ToPort < 1 || p.ToPort > 65535:
 		return err
 	}
+	if p.SourceCIDR != "" {
+		if _, _, err := net.ParseCIDR(p.SourceCIDR); err != nil {
+			return errors.Errorf("invalid source CIDR %q", p.SourceCIDR)
+		}
+	}
 	return nil
 }
 
@@ -50,11 +61,24 @@ func (a PortRange) ConflictsWith(b PortRange) bool {
 	return a.ToPort >= b.FromPort && b.ToPort >= a.FromPort
 }
 
+// SamePorts determines if the two port ranges cover exactly the same
+// ports, whatever sources they are open to. A unit can open the same
+// ports to several sources.
+func (a PortRange) SamePorts(b PortRange) bool {
+	return a.Protocol == b.Protocol && a.FromPort == b.FromPort && a.ToPort == b.ToPort
+}
+
 func (p PortRange) String() string {
+	var s string
 	if p.FromPort == p.ToPort {
-		return fmt.Sprintf("%d/%s", p.FromPort, strings.ToLower(p.Protocol))
+		s = fmt.Sprintf("%d/%s", p.FromPort, strings.ToLower(p.Protocol))
+	} else {
+		s = fmt.Sprintf("%d-%d/%s", p.FromPort, p.ToPort, strings.ToLower(p.Protocol))
 	}
-	return fmt.Sprintf("%d-%d/%s", p.FromPort, p.ToPort, strings.ToLower(p.Protocol))
+	if p.SourceCIDR != "" {
+		s += " from " + p.SourceCIDR
+	}
+	return s
 }
 
 func (p PortRange) GoString() string {
@@ -74,7 +98,10 @@ func (p portRangeSlice) Less(i, j int) bool {
 	if p1.FromPort != p2.FromPort {
 		return p1.FromPort < p2.FromPort
 	}
-	return p1.ToPort < p2.ToPort
+	if p1.ToPort != p2.ToPort {
+		return p1.ToPort < p2.ToPort
+	}
+	return p1.SourceCIDR < p2.SourceCIDR
 }
 
 // SortPortRanges sorts the given ports, first by protocol, then by number.
@@ -91,7 +118,7 @@ func CollapsePorts(ports []Port) (result []PortRange) {
 	// First, convert ports to ranges, then sort them.
 	var portRanges []PortRange
 	for _, p := range ports {
-		portRanges = append(portRanges, PortRange{p.Number, p.Number, p.Protocol})
+		portRanges = append(portRanges, PortRange{FromPort: p.Number, ToPort: p.Number, Protocol: p.Protocol})
 	}
 	SortPortRanges(portRanges)
 	fromPort := 0
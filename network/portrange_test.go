@@ -25,43 +25,43 @@ func (*PortRangeSuite) TestConflictsWith(c *gc.C) {
 		expectConflict bool
 	}{{
 		"identical ports",
-		network.PortRange{80, 80, "TCP"},
-		network.PortRange{80, 80, "TCP"},
+		network.PortRange{80, 80, "TCP", ""},
+		network.PortRange{80, 80, "TCP", ""},
 		true,
 	}, {
 		"different ports",
-		network.PortRange{80, 80, "TCP"},
-		network.PortRange{90, 90, "TCP"},
+		network.PortRange{80, 80, "TCP", ""},
+		network.PortRange{90, 90, "TCP", ""},
 		false,
 	}, {
 		"touching ranges",
-		network.PortRange{100, 200, "TCP"},
-		network.PortRange{201, 240, "TCP"},
+		network.PortRange{100, 200, "TCP", ""},
+		network.PortRange{201, 240, "TCP", ""},
 		false,
 	}, {
 		"touching ranges with overlap",
-		network.PortRange{100, 200, "TCP"},
-		network.PortRange{200, 240, "TCP"},
+		network.PortRange{100, 200, "TCP", ""},
+		network.PortRange{200, 240, "TCP", ""},
 		true,
 	}, {
 		"different protocols",
-		network.PortRange{80, 80, "UDP"},
-		network.PortRange{80, 80, "TCP"},
+		network.PortRange{80, 80, "UDP", ""},
+		network.PortRange{80, 80, "TCP", ""},
 		false,
 	}, {
 		"outside range",
-		network.PortRange{100, 200, "TCP"},
-		network.PortRange{80, 80, "TCP"},
+		network.PortRange{100, 200, "TCP", ""},
+		network.PortRange{80, 80, "TCP", ""},
 		false,
 	}, {
 		"overlap end",
-		network.PortRange{100, 200, "TCP"},
-		network.PortRange{80, 120, "TCP"},
+		network.PortRange{100, 200, "TCP", ""},
+		network.PortRange{80, 120, "TCP", ""},
 		true,
 	}, {
 		"complete overlap",
-		network.PortRange{100, 200, "TCP"},
-		network.PortRange{120, 140, "TCP"},
+		network.PortRange{100, 200, "TCP", ""},
+		network.PortRange{120, 140, "TCP", ""},
 		true,
 	}}
 
@@ -74,25 +74,38 @@ func (*PortRangeSuite) TestConflictsWith(c *gc.C) {
 
 func (*PortRangeSuite) TestStrings(c *gc.C) {
 	c.Assert(
-		network.PortRange{80, 80, "TCP"}.String(),
+		network.PortRange{80, 80, "TCP", ""}.String(),
 		gc.Equals,
 		"80/tcp",
 	)
 	c.Assert(
-		network.PortRange{80, 80, "TCP"}.GoString(),
+		network.PortRange{80, 80, "TCP", ""}.GoString(),
 		gc.Equals,
 		"80/tcp",
 	)
 	c.Assert(
-		network.PortRange{80, 100, "TCP"}.String(),
+		network.PortRange{80, 100, "TCP", ""}.String(),
 		gc.Equals,
 		"80-100/tcp",
 	)
 	c.Assert(
-		network.PortRange{80, 100, "TCP"}.GoString(),
+		network.PortRange{80, 100, "TCP", ""}.GoString(),
 		gc.Equals,
 		"80-100/tcp",
 	)
+	c.Assert(
+		network.PortRange{443, 443, "tcp", "10.0.0.0/8"}.String(),
+		gc.Equals,
+		"443/tcp from 10.0.0.0/8",
+	)
+}
+
+func (*PortRangeSuite) TestSamePorts(c *gc.C) {
+	ports := network.PortRange{80, 90, "tcp", ""}
+	c.Check(ports.SamePorts(network.PortRange{80, 90, "tcp", "10.0.0.0/8"}), jc.IsTrue)
+	c.Check(ports.SamePorts(network.PortRange{80, 90, "tcp", ""}), jc.IsTrue)
+	c.Check(ports.SamePorts(network.PortRange{80, 91, "tcp", ""}), jc.IsFalse)
+	c.Check(ports.SamePorts(network.PortRange{80, 90, "udp", ""}), jc.IsFalse)
 }
 
 func (*PortRangeSuite) TestValidate(c *gc.C) {
@@ -102,44 +115,52 @@ func (*PortRangeSuite) TestValidate(c *gc.C) {
 		expected string
 	}{{
 		"single valid port",
-		network.PortRange{80, 80, "tcp"},
+		network.PortRange{80, 80, "tcp", ""},
 		"",
 	}, {
 		"valid port range",
-		network.PortRange{80, 90, "tcp"},
+		network.PortRange{80, 90, "tcp", ""},
 		"",
 	}, {
 		"valid udp port range",
-		network.PortRange{80, 90, "UDP"},
+		network.PortRange{80, 90, "UDP", ""},
 		"",
 	}, {
 		"invalid port range boundaries",
-		network.PortRange{90, 80, "tcp"},
+		network.PortRange{90, 80, "tcp", ""},
 		"invalid port range 90-80/tcp",
 	}, {
 		"both FromPort and ToPort too large",
-		network.PortRange{88888, 99999, "tcp"},
+		network.PortRange{88888, 99999, "tcp", ""},
 		"invalid port range 88888-99999/tcp",
 	}, {
 		"FromPort too large",
-		network.PortRange{88888, 65535, "tcp"},
+		network.PortRange{88888, 65535, "tcp", ""},
 		"invalid port range 88888-65535/tcp",
 	}, {
 		"FromPort too small",
-		network.PortRange{0, 80, "tcp"},
+		network.PortRange{0, 80, "tcp", ""},
 		"invalid port range 0-80/tcp",
 	}, {
 		"ToPort too large",
-		network.PortRange{1, 99999, "tcp"},
+		network.PortRange{1, 99999, "tcp", ""},
 		"invalid port range 1-99999/tcp",
 	}, {
 		"both ports 0",
-		network.PortRange{0, 0, "tcp"},
+		network.PortRange{0, 0, "tcp", ""},
 		"invalid port range 0-0/tcp",
 	}, {
 		"invalid protocol",
-		network.PortRange{80, 80, "some protocol"},
+		network.PortRange{80, 80, "some protocol", ""},
 		`invalid protocol "some protocol", expected "tcp" or "udp"`,
+	}, {
+		"valid source CIDR",
+		network.PortRange{80, 80, "tcp", "10.0.0.0/8"},
+		"",
+	}, {
+		"invalid source CIDR",
+		network.PortRange{80, 80, "tcp", "10.0.0.0"},
+		`invalid source CIDR "10.0.0.0"`,
 	}}
 
 	for i, t := range testCases {
@@ -154,14 +175,16 @@ func (*PortRangeSuite) TestValidate(c *gc.C) {
 
 func (*PortRangeSuite) TestSortPortRanges(c *gc.C) {
 	ranges := []network.PortRange{
-		{10, 100, "udp"},
-		{80, 90, "tcp"},
-		{80, 80, "tcp"},
+		{10, 100, "udp", ""},
+		{80, 90, "tcp", "10.0.0.0/8"},
+		{80, 90, "tcp", ""},
+		{80, 80, "tcp", ""},
 	}
 	expected := []network.PortRange{
-		{80, 80, "tcp"},
-		{80, 90, "tcp"},
-		{10, 100, "udp"},
+		{80, 80, "tcp", ""},
+		{80, 90, "tcp", ""},
+		{80, 90, "tcp", "10.0.0.0/8"},
+		{10, 100, "udp", ""},
 	}
 	network.SortPortRanges(ranges)
 	c.Assert(ranges, gc.DeepEquals, expected)
@@ -175,39 +198,39 @@ func (*PortRangeSuite) TestCollapsePorts(c *gc.C) {
 	}{{
 		"single port",
 		[]network.Port{{"tcp", 80}},
-		[]network.PortRange{{80, 80, "tcp"}},
+		[]network.PortRange{{80, 80, "tcp", ""}},
 	}, {
 		"continuous port range (increasing)",
 		[]network.Port{{"tcp", 80}, {"tcp", 81}, {"tcp", 82}, {"tcp", 83}},
-		[]network.PortRange{{80, 83, "tcp"}},
+		[]network.PortRange{{80, 83, "tcp", ""}},
 	}, {
 		"continuous port range (decreasing)",
 		[]network.Port{{"tcp", 83}, {"tcp", 82}, {"tcp", 81}, {"tcp", 80}},
-		[]network.PortRange{{80, 83, "tcp"}},
+		[]network.PortRange{{80, 83, "tcp", ""}},
 	}, {
 		"non-continuous port range (increasing)",
 		[]network.Port{{"tcp", 80}, {"tcp", 81}, {"tcp", 82}, {"tcp", 84}, {"tcp", 85}},
-		[]network.PortRange{{80, 82, "tcp"}, {84, 85, "tcp"}},
+		[]network.PortRange{{80, 82, "tcp", ""}, {84, 85, "tcp", ""}},
 	}, {
 		"non-continuous port range (decreasing)",
 		[]network.Port{{"tcp", 85}, {"tcp", 84}, {"tcp", 82}, {"tcp", 81}, {"tcp", 80}},
-		[]network.PortRange{{80, 82, "tcp"}, {84, 85, "tcp"}},
+		[]network.PortRange{{80, 82, "tcp", ""}, {84, 85, "tcp", ""}},
 	}, {
 		"alternating tcp / udp ports (increasing)",
 		[]network.Port{{"tcp", 80}, {"udp", 81}, {"tcp", 82}, {"udp", 83}, {"tcp", 84}},
-		[]network.PortRange{{80, 80, "tcp"}, {82, 82, "tcp"}, {84, 84, "tcp"}, {81, 81, "udp"}, {83, 83, "udp"}},
+		[]network.PortRange{{80, 80, "tcp", ""}, {82, 82, "tcp", ""}, {84, 84, "tcp", ""}, {81, 81, "udp", ""}, {83, 83, "udp", ""}},
 	}, {
 		"alternating tcp / udp ports (decreasing)",
 		[]network.Port{{"tcp", 84}, {"udp", 83}, {"tcp", 82}, {"udp", 81}, {"tcp", 80}},
-		[]network.PortRange{{80, 80, "tcp"}, {82, 82, "tcp"}, {84, 84, "tcp"}, {81, 81, "udp"}, {83, 83, "udp"}},
+		[]network.PortRange{{80, 80, "tcp", ""}, {82, 82, "tcp", ""}, {84, 84, "tcp", ""}, {81, 81, "udp", ""}, {83, 83, "udp", ""}},
 	}, {
 		"non-continuous port range (udp vs tcp - increasing)",
 		[]network.Port{{"tcp", 80}, {"tcp", 81}, {"tcp", 82}, {"udp", 84}, {"tcp", 83}},
-		[]network.PortRange{{80, 83, "tcp"}, {84, 84, "udp"}},
+		[]network.PortRange{{80, 83, "tcp", ""}, {84, 84, "udp", ""}},
 	}, {
 		"non-continuous port range (udp vs tcp - decreasing)",
 		[]network.Port{{"tcp", 83}, {"udp", 84}, {"tcp", 82}, {"tcp", 81}, {"tcp", 80}},
-		[]network.PortRange{{80, 83, "tcp"}, {84, 84, "udp"}},
+		[]network.PortRange{{80, 83, "tcp", ""}, {84, 84, "udp", ""}},
 	}}
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
@@ -167,9 +167,9 @@ func (s *PortSetSuite) TestPortSetRemoveRanges(c *gc.C) {
 	portSet := network.NewPortSet(s.portRange1)
 	portSet.RemoveRanges(
 		s.portRange2,
-		network.PortRange{7000, 8049, "tcp"},
-		network.PortRange{8051, 8074, "tcp"},
-		network.PortRange{8080, 9000, "tcp"},
+		network.PortRange{7000, 8049, "tcp", ""},
+		network.PortRange{8051, 8074, "tcp", ""},
+		network.PortRange{8080, 9000, "tcp", ""},
 	)
 
 	s.checkPortSetTCP(c, portSet, 8050, 8075, 8076, 8077, 8078, 8079)
@@ -191,7 +191,7 @@ func (s *PortSetSuite) TestPortSetContainsNotFound(c *gc.C) {
 
 func (s *PortSetSuite) TestPortSetContainsRangesSingleMatch(c *gc.C) {
 	portSet := network.NewPortSet(s.portRange1)
-	isfound := portSet.ContainsRanges(network.PortRange{8080, 8080, "tcp"})
+	isfound := portSet.ContainsRanges(network.PortRange{8080, 8080, "tcp", ""})
 
 	c.Assert(isfound, jc.IsTrue)
 }
@@ -205,7 +205,7 @@ func (s *PortSetSuite) TestPortSetContainsRangesSingleNoMatch(c *gc.C) {
 
 func (s *PortSetSuite) TestPortSetContainsRangesOverlapping(c *gc.C) {
 	portSet := network.NewPortSet(s.portRange1)
-	isfound := portSet.ContainsRanges(network.PortRange{7000, 8049, "tcp"})
+	isfound := portSet.ContainsRanges(network.PortRange{7000, 8049, "tcp", ""})
 
 	c.Assert(isfound, jc.IsFalse)
 }
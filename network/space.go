@@ -0,0 +1,17 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"regexp"
+)
+
+var validSpaceName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidSpaceName reports whether name is a valid network space name:
+// lower case letters and digits, optionally separated by single
+// hyphens.
+func IsValidSpaceName(name string) bool {
+	return validSpaceName.MatchString(name)
+}
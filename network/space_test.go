@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type SpaceSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SpaceSuite{})
+
+func (s *SpaceSuite) TestIsValidSpaceName(c *gc.C) {
+	for i, test := range []struct {
+		name  string
+		valid bool
+	}{
+		{"dmz", true},
+		{"db-2", true},
+		{"a-b-c", true},
+		{"42", true},
+		{"", false},
+		{"DMZ", false},
+		{"-dmz", false},
+		{"dmz-", false},
+		{"d--mz", false},
+		{"d_mz", false},
+		{"d mz", false},
+	} {
+		c.Logf("test %d: %q", i, test.name)
+		c.Check(network.IsValidSpaceName(test.name), gc.Equals, test.valid)
+	}
+}
@@ -172,7 +172,7 @@ func (s *instanceSuite) TestOpenPorts(c *gc.C) {
 	responses := preparePortChangeConversation(c, s.role)
 	record := gwacl.PatchManagementAPIResponses(responses)
 	err := s.instance.OpenPorts("machine-id", []network.PortRange{
-		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
+		{79, 79, "tcp", ""}, {587, 587, "tcp", ""}, {9, 9, "udp", ""},
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -202,7 +202,7 @@ func (s *instanceSuite) TestOpenPortsFailsWhenUnableToGetRole(c *gc.C) {
 	failPortChangeConversationAt(1, responses) // 1st request, GetRole
 	record := gwacl.PatchManagementAPIResponses(responses)
 	err := s.instance.OpenPorts("machine-id", []network.PortRange{
-		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
+		{79, 79, "tcp", ""}, {587, 587, "tcp", ""}, {9, 9, "udp", ""},
 	})
 	c.Check(err, gc.ErrorMatches, "GET request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 1)
@@ -213,7 +213,7 @@ func (s *instanceSuite) TestOpenPortsFailsWhenUnableToUpdateRole(c *gc.C) {
 	failPortChangeConversationAt(2, responses) // 2nd request, UpdateRole
 	record := gwacl.PatchManagementAPIResponses(responses)
 	err := s.instance.OpenPorts("machine-id", []network.PortRange{
-		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
+		{79, 79, "tcp", ""}, {587, 587, "tcp", ""}, {9, 9, "udp", ""},
 	})
 	c.Check(err, gc.ErrorMatches, "PUT request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 2)
@@ -227,21 +227,21 @@ func (s *instanceSuite) TestClosePorts(c *gc.C) {
 	}
 
 	tests := []test{{
-		inputPorts:  []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
+		inputPorts:  []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
 		removePorts: nil,
-		outputPorts: []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
+		outputPorts: []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
 	}, {
-		inputPorts:  []network.PortRange{{1, 1, "tcp"}},
-		removePorts: []network.PortRange{{1, 1, "udp"}},
-		outputPorts: []network.PortRange{{1, 1, "tcp"}},
+		inputPorts:  []network.PortRange{{1, 1, "tcp", ""}},
+		removePorts: []network.PortRange{{1, 1, "udp", ""}},
+		outputPorts: []network.PortRange{{1, 1, "tcp", ""}},
 	}, {
-		inputPorts:  []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
-		removePorts: []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
+		inputPorts:  []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
+		removePorts: []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
 		outputPorts: []network.PortRange{},
 	}, {
-		inputPorts:  []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
-		removePorts: []network.PortRange{{99, 99, "tcp"}},
-		outputPorts: []network.PortRange{{1, 1, "tcp"}, {2, 2, "tcp"}, {3, 3, "udp"}},
+		inputPorts:  []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
+		removePorts: []network.PortRange{{99, 99, "tcp", ""}},
+		outputPorts: []network.PortRange{{1, 1, "tcp", ""}, {2, 2, "tcp", ""}, {3, 3, "udp", ""}},
 	}}
 
 	for i, test := range tests {
@@ -282,7 +282,7 @@ func (s *instanceSuite) TestClosePortsFailsWhenUnableToGetRole(c *gc.C) {
 	failPortChangeConversationAt(1, responses) // 1st request, GetRole
 	record := gwacl.PatchManagementAPIResponses(responses)
 	err := s.instance.ClosePorts("machine-id", []network.PortRange{
-		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
+		{79, 79, "tcp", ""}, {587, 587, "tcp", ""}, {9, 9, "udp", ""},
 	})
 	c.Check(err, gc.ErrorMatches, "GET request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 1)
@@ -293,7 +293,7 @@ func (s *instanceSuite) TestClosePortsFailsWhenUnableToUpdateRole(c *gc.C) {
 	failPortChangeConversationAt(2, responses) // 2nd request, UpdateRole
 	record := gwacl.PatchManagementAPIResponses(responses)
 	err := s.instance.ClosePorts("machine-id", []network.PortRange{
-		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
+		{79, 79, "tcp", ""}, {587, 587, "tcp", ""}, {9, 9, "udp", ""},
 	})
 	c.Check(err, gc.ErrorMatches, "PUT request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 2)
@@ -315,8 +315,8 @@ func (s *instanceSuite) TestConvertAndFilterEndpoints(c *gc.C) {
 		}}
 	endpoints = append(endpoints, s.env.getInitialEndpoints(true)...)
 	expectedPorts := []network.PortRange{
-		{1123, 1123, "udp"},
-		{44, 44, "tcp"}}
+		{1123, 1123, "udp", ""},
+		{44, 44, "tcp", ""}}
 	network.SortPortRanges(expectedPorts)
 	c.Check(convertAndFilterEndpoints(endpoints, s.env, true), gc.DeepEquals, expectedPorts)
 }
@@ -365,12 +365,12 @@ func (s *instanceSuite) testPorts(c *gc.C, maskStateServerPorts bool) {
 	})
 
 	expected := []network.PortRange{
-		{4456, 4456, "tcp"},
-		{1123, 1123, "udp"},
-		{2123, 2123, "udp"},
+		{4456, 4456, "tcp", ""},
+		{1123, 1123, "udp", ""},
+		{2123, 2123, "udp", ""},
 	}
 	if !maskStateServerPorts {
-		expected = append(expected, network.PortRange{s.env.Config().APIPort(), s.env.Config().APIPort(), "tcp"})
+		expected = append(expected, network.PortRange{s.env.Config().APIPort(), s.env.Config().APIPort(), "tcp", ""})
 		network.SortPortRanges(expected)
 	}
 	c.Check(ports, gc.DeepEquals, expected)
@@ -10,29 +10,6 @@ import (
 	"github.com/juju/juju/instance"
 )
 
-// AvailabilityZone describes a provider availability zone.
-type AvailabilityZone interface {
-	// Name returns the name of the availability zone.
-	Name() string
-
-	// Available reports whether the availability zone is currently available.
-	Available() bool
-}
-
-// ZonedEnviron is an environs.Environ that has support for
-// availability zones.
-type ZonedEnviron interface {
-	environs.Environ
-
-	// AvailabilityZones returns all availability zones in the environment.
-	AvailabilityZones() ([]AvailabilityZone, error)
-
-	// InstanceAvailabilityZoneNames returns the names of the availability
-	// zones for the specified instances. The error returned follows the same
-	// rules as Environ.Instances.
-	InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error)
-}
-
 // AvailabilityZoneInstances describes an availability zone and
 // a set of instances in that zone.
 type AvailabilityZoneInstances struct {
@@ -70,7 +47,7 @@ func (b byPopulationThenName) Swap(i, j int) {
 //
 // If the specified group is empty, then it will behave as if the result of
 // AllInstances were provided.
-func AvailabilityZoneAllocations(env ZonedEnviron, group []instance.Id) ([]AvailabilityZoneInstances, error) {
+func AvailabilityZoneAllocations(env environs.ZonedEnviron, group []instance.Id) ([]AvailabilityZoneInstances, error) {
 	if len(group) == 0 {
 		instances, err := env.AllInstances()
 		if err != nil {
@@ -136,7 +113,7 @@ var internalAvailabilityZoneAllocations = AvailabilityZoneAllocations
 // DistributeInstances is a common function for implement the
 // state.InstanceDistributor policy based on availability zone
 // spread.
-func DistributeInstances(env ZonedEnviron, candidates, group []instance.Id) ([]instance.Id, error) {
+func DistributeInstances(env environs.ZonedEnviron, candidates, group []instance.Id) ([]instance.Id, error) {
 	// Determine the best availability zones for the group.
 	zoneInstances, err := internalAvailabilityZoneAllocations(env, group)
 	if err != nil || len(zoneInstances) == 0 {
@@ -33,14 +33,14 @@ func (s *AvailabilityZoneSuite) SetUpSuite(c *gc.C) {
 		return allInstances, nil
 	}
 
-	availabilityZones := make([]common.AvailabilityZone, 3)
+	availabilityZones := make([]environs.AvailabilityZone, 3)
 	for i := range availabilityZones {
 		availabilityZones[i] = &mockAvailabilityZone{
 			name:      fmt.Sprintf("az%d", i),
 			available: i > 0,
 		}
 	}
-	s.env.availabilityZones = func() ([]common.AvailabilityZone, error) {
+	s.env.availabilityZones = func() ([]environs.AvailabilityZone, error) {
 		return availabilityZones, nil
 	}
 }
@@ -127,9 +127,9 @@ func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsNoZones(c *gc.C)
 		calls = append(calls, "InstanceAvailabilityZoneNames")
 		return []string{"", "", ""}, nil
 	})
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
+	s.PatchValue(&s.env.availabilityZones, func() ([]environs.AvailabilityZone, error) {
 		calls = append(calls, "AvailabilityZones")
-		return []common.AvailabilityZone{}, nil
+		return []environs.AvailabilityZone{}, nil
 	})
 	zoneInstances, err := common.AvailabilityZoneAllocations(&s.env, nil)
 	c.Assert(calls, gc.DeepEquals, []string{"InstanceAvailabilityZoneNames", "AvailabilityZones"})
@@ -145,7 +145,7 @@ func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsErrors(c *gc.C) {
 		return []string{"", "", ""}, nil
 	})
 	resultErr := fmt.Errorf("u can haz no az")
-	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
+	s.PatchValue(&s.env.availabilityZones, func() ([]environs.AvailabilityZone, error) {
 		calls = append(calls, "AvailabilityZones")
 		return nil, resultErr
 	})
@@ -158,7 +158,7 @@ func (s *AvailabilityZoneSuite) TestAvailabilityZoneAllocationsErrors(c *gc.C) {
 func (s *AvailabilityZoneSuite) TestDistributeInstancesGroup(c *gc.C) {
 	expectedGroup := []instance.Id{"0", "1", "2"}
 	var called bool
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
 		c.Assert(group, gc.DeepEquals, expectedGroup)
 		called = true
 		return nil, nil
@@ -169,7 +169,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstancesGroup(c *gc.C) {
 
 func (s *AvailabilityZoneSuite) TestDistributeInstancesGroupErrors(c *gc.C) {
 	resultErr := fmt.Errorf("whatever")
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
 		return nil, resultErr
 	})
 	_, err := common.DistributeInstances(&s.env, nil, nil)
@@ -178,7 +178,7 @@ func (s *AvailabilityZoneSuite) TestDistributeInstancesGroupErrors(c *gc.C) {
 
 func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 	var zoneInstances []common.AvailabilityZoneInstances
-	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+	s.PatchValue(common.InternalAvailabilityZoneAllocations, func(_ environs.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
 		return zoneInstances, nil
 	})
 
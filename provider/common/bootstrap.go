@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	coretools "github.com/juju/juju/tools"
@@ -37,6 +38,9 @@ var logger = loggo.GetLogger("juju.provider.common")
 // when writing a new provider.
 func Bootstrap(ctx environs.BootstrapContext, env environs.Environ, args environs.BootstrapParams,
 ) (arch, series string, _ environs.BootstrapFinalizer, err error) {
+	if args.BootstrapHost != "" {
+		return BootstrapExistingHost(ctx, env, args)
+	}
 	if result, series, finalizer, err := BootstrapInstance(ctx, env, args); err == nil {
 		return *result.Hardware.Arch, series, finalizer, nil
 	} else {
@@ -117,6 +121,70 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	return result, series, finalize, nil
 }
 
+// manualInstancePrefix is the prefix of the instance ids of machines
+// that were not started by the provider, but enlisted over SSH.
+const manualInstancePrefix = "manual:"
+
+// manualCheckProvisioned and manualDetectSeriesAndHardwareCharacteristics
+// are defined here to allow tests to override them.
+var (
+	manualCheckProvisioned                       = manual.CheckProvisioned
+	manualDetectSeriesAndHardwareCharacteristics = manual.DetectSeriesAndHardwareCharacteristics
+)
+
+// BootstrapExistingHost bootstraps the environment onto the existing
+// machine named by args.BootstrapHost, instead of starting a new
+// instance. The machine is enlisted over SSH as machine 0 in the same
+// way as by the manual provider, while subsequent machines are
+// provisioned by the environment's provider as usual.
+func BootstrapExistingHost(ctx environs.BootstrapContext, env environs.Environ, args environs.BootstrapParams,
+) (arch, series string, _ environs.BootstrapFinalizer, err error) {
+	host := args.BootstrapHost
+	if args.Placement != "" {
+		return "", "", nil, errors.New("cannot specify both a placement directive and an existing host to bootstrap")
+	}
+	client := ssh.DefaultClient
+	if client == nil {
+		return "", "", nil, fmt.Errorf("no SSH client available")
+	}
+	provisioned, err := manualCheckProvisioned(host)
+	if err != nil {
+		return "", "", nil, errors.Annotate(err, "failed to check provisioned status")
+	}
+	if provisioned {
+		return "", "", nil, manual.ErrProvisioned
+	}
+	hc, series, err := manualDetectSeriesAndHardwareCharacteristics(host)
+	if err != nil {
+		return "", "", nil, errors.Annotatef(err, "error detecting hardware characteristics of %s", host)
+	}
+	if _, err := args.AvailableTools.Match(coretools.Filter{Series: series, Arch: *hc.Arch}); err != nil {
+		return "", "", nil, errors.Annotatef(err, "no tools available for %s (%s/%s)", host, series, *hc.Arch)
+	}
+	fmt.Fprintf(ctx.GetStderr(), "Bootstrapping existing machine %s\n", host)
+
+	instanceId := instance.Id(manualInstancePrefix + host)
+	finalize := func(ctx environs.BootstrapContext, mcfg *cloudinit.MachineConfig) error {
+		mcfg.InstanceId = instanceId
+		mcfg.HardwareCharacteristics = &hc
+		if err := environs.FinishMachineConfig(mcfg, env.Config()); err != nil {
+			return err
+		}
+		if args.ContainerBridgeName != "" {
+			mcfg.AgentEnvironment[agent.LxcBridge] = args.ContainerBridgeName
+		}
+		// Record the machine as the state server, so that the
+		// environment is known to be bootstrapped.
+		if envStorage, ok := env.(environs.EnvironStorage); ok {
+			if err := AddStateInstance(envStorage.Storage(), instanceId); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return ConfigureMachine(ctx, client, host, mcfg)
+	}
+	return *hc.Arch, series, finalize, nil
+}
+
 // FinishBootstrap completes the bootstrap process by connecting
 // to the instance via SSH and carrying out the cloud-config.
 //
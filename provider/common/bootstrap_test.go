@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/environs/storage"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/instance"
@@ -78,6 +79,88 @@ func configGetter(c *gc.C) configFunc {
 	return func() *config.Config { return cfg }
 }
 
+func (s *BootstrapSuite) patchExistingHost(c *gc.C, provisioned bool) {
+	s.PatchValue(common.ManualCheckProvisioned, func(host string) (bool, error) {
+		c.Check(host, gc.Equals, "10.0.0.1")
+		return provisioned, nil
+	})
+	s.PatchValue(common.ManualDetectSeriesAndHardwareCharacteristics, func(host string) (instance.HardwareCharacteristics, string, error) {
+		c.Check(host, gc.Equals, "10.0.0.1")
+		arch := version.Current.Arch
+		return instance.HardwareCharacteristics{Arch: &arch}, version.Current.Series, nil
+	})
+}
+
+func (s *BootstrapSuite) TestBootstrapExistingHost(c *gc.C) {
+	s.patchExistingHost(c, false)
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+	}
+	env.startInstance = func(
+		string, constraints.Value, []string, tools.List, *cloudinit.MachineConfig,
+	) (instance.Instance, *instance.HardwareCharacteristics, []network.InterfaceInfo, error) {
+		c.Fatalf("StartInstance called")
+		return nil, nil, nil, nil
+	}
+
+	ctx := envtesting.BootstrapContext(c)
+	arch, series, finalizer, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		AvailableTools: tools.List{&tools.Tools{Version: version.Current}},
+		BootstrapHost:  "10.0.0.1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arch, gc.Equals, version.Current.Arch)
+	c.Assert(series, gc.Equals, version.Current.Series)
+	c.Assert(finalizer, gc.NotNil)
+}
+
+func (s *BootstrapSuite) TestBootstrapExistingHostProvisioned(c *gc.C) {
+	s.patchExistingHost(c, true)
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+	}
+	ctx := envtesting.BootstrapContext(c)
+	_, _, _, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		AvailableTools: tools.List{&tools.Tools{Version: version.Current}},
+		BootstrapHost:  "10.0.0.1",
+	})
+	c.Assert(err, gc.Equals, manual.ErrProvisioned)
+}
+
+func (s *BootstrapSuite) TestBootstrapExistingHostNoTools(c *gc.C) {
+	s.patchExistingHost(c, false)
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+	}
+	otherVersion := version.Current
+	otherVersion.Series = "precise"
+	if otherVersion.Series == version.Current.Series {
+		otherVersion.Series = "trusty"
+	}
+	ctx := envtesting.BootstrapContext(c)
+	_, _, _, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		AvailableTools: tools.List{&tools.Tools{Version: otherVersion}},
+		BootstrapHost:  "10.0.0.1",
+	})
+	c.Assert(err, gc.ErrorMatches, "no tools available for 10.0.0.1 .*")
+}
+
+func (s *BootstrapSuite) TestBootstrapExistingHostWithPlacement(c *gc.C) {
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+	}
+	ctx := envtesting.BootstrapContext(c)
+	_, _, _, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		Placement:     "zone=a",
+		BootstrapHost: "10.0.0.1",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot specify both a placement directive and an existing host to bootstrap")
+}
+
 func (s *BootstrapSuite) TestCannotStartInstance(c *gc.C) {
 	checkPlacement := "directive"
 	checkCons := constraints.MustParse("mem=8G")
@@ -7,4 +7,7 @@ var (
 	ConnectSSH                          = &connectSSH
 	WaitSSH                             = waitSSH
 	InternalAvailabilityZoneAllocations = &internalAvailabilityZoneAllocations
+
+	ManualCheckProvisioned                       = &manualCheckProvisioned
+	ManualDetectSeriesAndHardwareCharacteristics = &manualDetectSeriesAndHardwareCharacteristics
 )
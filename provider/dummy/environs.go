@@ -1035,6 +1035,25 @@ func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err e
 	return
 }
 
+// InstanceConsoleOutput is specified in the environs.InstanceConsole
+// interface.
+func (e *environ) InstanceConsoleOutput(id instance.Id) (string, error) {
+	defer delay()
+	if err := e.checkBroken("InstanceConsoleOutput"); err != nil {
+		return "", err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return "", err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	if estate.insts[id] == nil {
+		return "", errors.NotFoundf("instance %q", id)
+	}
+	return fmt.Sprintf("console output of instance %q\n", id), nil
+}
+
 // SupportsAddressAllocation is specified on environs.Networking.
 func (env *environ) SupportsAddressAllocation(subnetId network.Id) (bool, error) {
 	if err := env.checkBroken("SupportsAddressAllocation"); err != nil {
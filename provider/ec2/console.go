@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+var _ environs.InstanceConsole = (*environ)(nil)
+
+// ec2ConsoleOutput returns the base64-encoded console output of the
+// instance with the given id.
+var ec2ConsoleOutput = func(e *ec2.EC2, instId string) (string, error) {
+	resp, err := e.GetConsoleOutput(instId)
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// InstanceConsoleOutput is specified in the environs.InstanceConsole
+// interface. EC2 captures the console output of an instance shortly
+// after it boots, and again shortly before it is terminated.
+func (e *environ) InstanceConsoleOutput(id instance.Id) (string, error) {
+	encoded, err := ec2ConsoleOutput(e.ec2(), string(id))
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot get console output of instance %q", id)
+	}
+	output, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot decode console output of instance %q", id)
+	}
+	return string(output), nil
+}
@@ -102,17 +102,86 @@ var validConfigOptions = set.NewStrings(
 	EBS_AvailabilityZone,
 )
 
+// validVolumeTypes holds the AWS names of the EBS volume types.
+var validVolumeTypes = set.NewStrings("standard", "gp2", "io1")
+
 // ValidateConfig is defined on the Provider interface.
 func (e *ebsProvider) ValidateConfig(providerConfig *storage.Config) error {
-	// TODO - check valid values as well as attr names
 	for attr := range providerConfig.Attrs() {
 		if !validConfigOptions.Contains(attr) {
 			return errors.Errorf("unknown provider config option %q", attr)
 		}
 	}
+	cfg, err := newEBSConfig(providerConfig.Attrs())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.iops > 0 && cfg.volumeType != "io1" {
+		return errors.Errorf("IOPS specified, but volume type is %q", cfg.volumeType)
+	}
 	return nil
 }
 
+// ebsConfig holds the EBS specific attributes of a storage pool.
+type ebsConfig struct {
+	volumeType string
+	iops       int64
+	encrypted  bool
+}
+
+// newEBSConfig parses and checks the EBS specific attributes of a
+// storage pool. Attributes given on the command line are strings,
+// while those read from YAML have their natural types, so both are
+// accepted.
+func newEBSConfig(attrs map[string]interface{}) (*ebsConfig, error) {
+	// TODO(wallyworld) - remove type switches when juju/schema is used
+	var cfg ebsConfig
+	options := TranslateUserEBSOptions(attrs)
+	if v, ok := options[EBS_VolumeType]; ok && v != "" {
+		volumeType, _ := v.(string)
+		if !validVolumeTypes.Contains(volumeType) {
+			return nil, errors.Errorf(
+				"invalid volume type %v, expected one of magnetic, ssd or provisioned-iops", v,
+			)
+		}
+		cfg.volumeType = volumeType
+	}
+	if v, ok := options[EBS_IOPS]; ok && v != "" {
+		switch v := v.(type) {
+		case int:
+			cfg.iops = int64(v)
+		case int64:
+			cfg.iops = v
+		case string:
+			var err error
+			cfg.iops, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid iops value %v, expected integer", v)
+			}
+		default:
+			return nil, errors.Errorf("invalid iops value %v, expected integer", v)
+		}
+		if cfg.iops < 0 {
+			return nil, errors.Errorf("invalid iops value %v, expected positive integer", v)
+		}
+	}
+	if v, ok := options[EBS_Encrypted]; ok && v != "" {
+		switch v := v.(type) {
+		case bool:
+			cfg.encrypted = v
+		case string:
+			var err error
+			cfg.encrypted, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Errorf("invalid encrypted value %v, expected boolean", v)
+			}
+		default:
+			return nil, errors.Errorf("invalid encrypted value %v, expected boolean", v)
+		}
+	}
+	return &cfg, nil
+}
+
 // Supports is defined on the Provider interface.
 func (e *ebsProvider) Supports(k storage.StorageKind) bool {
 	return k == storage.StorageKindBlock
@@ -184,21 +253,13 @@ func parseVolumeOptions(size uint64, attr map[string]interface{}) (_ ec2.CreateV
 	}
 	vol.AvailZone = availabilityZone
 
-	// TODO(wallyworld) - remove type assertions when juju/schema is used
-	options := TranslateUserEBSOptions(attr)
-	if v, ok := options[EBS_VolumeType]; ok && v != "" {
-		vol.VolumeType = v.(string)
-	}
-	if v, ok := options[EBS_IOPS]; ok && v != "" {
-		var err error
-		vol.IOPS, err = strconv.ParseInt(v.(string), 10, 64)
-		if err != nil {
-			return vol, false, errors.Annotatef(err, "invalid iops value %v, expected integer", v)
-		}
-	}
-	if v, ok := options[EBS_Encrypted].(bool); ok {
-		vol.Encrypted = v
+	cfg, err := newEBSConfig(attr)
+	if err != nil {
+		return vol, false, errors.Trace(err)
 	}
+	vol.VolumeType = cfg.volumeType
+	vol.IOPS = cfg.iops
+	vol.Encrypted = cfg.encrypted
 
 	return vol, persistent, nil
 }
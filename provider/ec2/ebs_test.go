@@ -41,6 +41,47 @@ func (*storageSuite) TestValidateConfigInvalidConfig(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown provider config option "invalid"`)
 }
 
+func (*storageSuite) TestValidateConfig(c *gc.C) {
+	p := ec2.EBSProvider()
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"volume-type": "ssd", "encrypted": true},
+	}, {
+		attrs: map[string]interface{}{"volume-type": "gp2", "encrypted": "true"},
+	}, {
+		attrs: map[string]interface{}{"volume-type": "provisioned-iops", "iops": "1000"},
+	}, {
+		attrs: map[string]interface{}{"volume-type": "io1", "iops": 1000},
+	}, {
+		attrs: map[string]interface{}{"volume-type": "floppy"},
+		err:   "invalid volume type floppy, expected one of magnetic, ssd or provisioned-iops",
+	}, {
+		attrs: map[string]interface{}{"volume-type": "io1", "iops": "lots"},
+		err:   `invalid iops value lots, expected integer: .*`,
+	}, {
+		attrs: map[string]interface{}{"volume-type": "io1", "iops": -1},
+		err:   "invalid iops value -1, expected positive integer",
+	}, {
+		attrs: map[string]interface{}{"volume-type": "magnetic", "iops": "1000"},
+		err:   `IOPS specified, but volume type is "standard"`,
+	}, {
+		attrs: map[string]interface{}{"encrypted": "maybe"},
+		err:   "invalid encrypted value maybe, expected boolean",
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("foo", ec2.EBS_ProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
 func (s *storageSuite) TestSupports(c *gc.C) {
 	p := ec2.EBSProvider()
 	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
@@ -65,7 +65,7 @@ type environ struct {
 	storageUnlocked envstorage.Storage
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 
 	// cachedDefaultVpc caches the id of the ec2 default vpc
 	cachedDefaultVpc *defaultVpc
@@ -235,6 +235,7 @@ func (e *environ) SupportsAddressAllocation(_ network.Id) (bool, error) {
 
 var unsupportedConstraints = []string{
 	constraints.Tags,
+	constraints.VirtType,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -285,7 +286,7 @@ func (z *ec2AvailabilityZone) Available() bool {
 
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -296,7 +297,7 @@ func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 			return nil, err
 		}
 		logger.Debugf("availability zones: %+v", resp)
-		e.availabilityZones = make([]common.AvailabilityZone, len(resp.Zones))
+		e.availabilityZones = make([]environs.AvailabilityZone, len(resp.Zones))
 		for i, z := range resp.Zones {
 			e.availabilityZones[i] = &ec2AvailabilityZone{z}
 		}
@@ -352,9 +353,13 @@ func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (e *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" {
-		if _, err := e.parsePlacement(placement); err != nil {
+		placement, err := e.parsePlacement(placement)
+		if err != nil {
 			return err
 		}
+		if !cons.AllowsZone(placement.availabilityZone.Name) {
+			return fmt.Errorf("availability zone %q not allowed by zones constraint", placement.availabilityZone.Name)
+		}
 	}
 	if !cons.HasInstanceType() {
 		return nil
@@ -419,6 +424,33 @@ func (e *environ) DistributeInstances(candidates, distributionGroup []instance.I
 
 var availabilityZoneAllocations = common.AvailabilityZoneAllocations
 
+// maxConcurrentStarts limits the number of instances StartInstances
+// starts at the same time, to stay within the EC2 API request rate.
+const maxConcurrentStarts = 10
+
+var _ environs.BulkInstanceBroker = (*environ)(nil)
+
+// StartInstances is specified in the BulkInstanceBroker interface.
+// The user data of each instance identifies its machine, so EC2 cannot
+// start them all with a single RunInstances request with a count;
+// instead their RunInstances requests are made concurrently.
+func (e *environ) StartInstances(args []environs.StartInstanceParams) []environs.StartInstancesResult {
+	results := make([]environs.StartInstancesResult, len(args))
+	sem := make(chan struct{}, maxConcurrentStarts)
+	var wg sync.WaitGroup
+	for i := range args {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i].Result, results[i].Error = e.StartInstance(args[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
 // StartInstance is specified in the InstanceBroker interface.
 func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
 	var availabilityZones []string
@@ -430,12 +462,26 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if placement.availabilityZone.State != "available" {
 			return nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
 		}
+		if !args.Constraints.AllowsZone(placement.availabilityZone.Name) {
+			return nil, errors.Errorf("availability zone %q not allowed by zones constraint", placement.availabilityZone.Name)
+		}
 		availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
 	}
 
-	// If no availability zone is specified, then automatically spread across
-	// the known zones for optimal spread across the instance distribution
-	// group.
+	// If no availability zone is specified, use the zones chosen by the
+	// provisioner, or else automatically spread across the known zones
+	// for optimal spread across the instance distribution group; either
+	// way, limited to those allowed by any zones constraint.
+	if len(availabilityZones) == 0 && len(args.AvailabilityZones) > 0 {
+		for _, zone := range args.AvailabilityZones {
+			if args.Constraints.AllowsZone(zone) {
+				availabilityZones = append(availabilityZones, zone)
+			}
+		}
+		if len(availabilityZones) == 0 {
+			return nil, errors.Errorf("no available zones match zones constraint %q", strings.Join(*args.Constraints.Zones, ","))
+		}
+	}
 	if len(availabilityZones) == 0 {
 		var group []instance.Id
 		var err error
@@ -450,9 +496,14 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			return nil, err
 		}
 		for _, z := range zoneInstances {
-			availabilityZones = append(availabilityZones, z.ZoneName)
+			if args.Constraints.AllowsZone(z.ZoneName) {
+				availabilityZones = append(availabilityZones, z.ZoneName)
+			}
 		}
 		if len(availabilityZones) == 0 {
+			if args.Constraints.HasZones() {
+				return nil, errors.Errorf("no available zones match zones constraint %q", strings.Join(*args.Constraints.Zones, ","))
+			}
 			return nil, errors.New("failed to determine availability zones")
 		}
 	}
@@ -467,7 +518,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 
 	series := args.Tools.OneSeries()
-	spec, err := findInstanceSpec(sources, e.Config().ImageStream(), &instances.InstanceConstraint{
+	spec, err := findInstanceSpec(args.ImageMetadata, sources, e.Config().ImageStream(), &instances.InstanceConstraint{
 		Region:      e.ecfg().region(),
 		Series:      series,
 		Arches:      arches,
@@ -523,7 +574,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		}
 	}
 	if err != nil {
-		return nil, errors.Annotate(err, "cannot run instances")
+		return nil, errors.Annotate(classifyRunInstancesError(err), "cannot run instances")
 	}
 	if len(instResp.Instances) != 1 {
 		return nil, errors.Errorf("expected 1 started instance, got %d", len(instResp.Instances))
@@ -959,14 +1010,22 @@ func (e *environ) Destroy() error {
 	return e.Storage().RemoveAll()
 }
 
+// anySourceCIDR is the source of the IP permissions granted for port
+// ranges open to all sources.
+const anySourceCIDR = "0.0.0.0/0"
+
 func portsToIPPerms(ports []network.PortRange) []ec2.IPPerm {
 	ipPerms := make([]ec2.IPPerm, len(ports))
 	for i, p := range ports {
+		sourceCIDR := p.SourceCIDR
+		if sourceCIDR == "" {
+			sourceCIDR = anySourceCIDR
+		}
 		ipPerms[i] = ec2.IPPerm{
 			Protocol:  p.Protocol,
 			FromPort:  p.FromPort,
 			ToPort:    p.ToPort,
-			SourceIPs: []string{"0.0.0.0/0"},
+			SourceIPs: []string{sourceCIDR},
 		}
 	}
 	return ipPerms
@@ -976,7 +1035,8 @@ func (e *environ) openPortsInGroup(name string, ports []network.PortRange) error
 	if len(ports) == 0 {
 		return nil
 	}
-	// Give permissions for anyone to access the given ports.
+	// Give permissions for the given sources, or anyone, to access
+	// the given ports.
 	g, err := e.groupByName(name)
 	if err != nil {
 		return err
@@ -1029,15 +1089,24 @@ func (e *environ) portsInGroup(name string) (ports []network.PortRange, err erro
 		return nil, err
 	}
 	for _, p := range group.IPPerms {
-		if len(p.SourceIPs) != 1 {
+		if len(p.SourceIPs) == 0 {
 			logger.Warningf("unexpected IP permission found: %v", p)
 			continue
 		}
-		ports = append(ports, network.PortRange{
-			Protocol: p.Protocol,
-			FromPort: p.FromPort,
-			ToPort:   p.ToPort,
-		})
+		// EC2 reports the permissions granted for the same ports to
+		// several sources as a single permission.
+		for _, sourceIP := range p.SourceIPs {
+			sourceCIDR := sourceIP
+			if sourceCIDR == anySourceCIDR {
+				sourceCIDR = ""
+			}
+			ports = append(ports, network.PortRange{
+				Protocol:   p.Protocol,
+				FromPort:   p.FromPort,
+				ToPort:     p.ToPort,
+				SourceCIDR: sourceCIDR,
+			})
+		}
 	}
 	network.SortPortRanges(ports)
 	return ports, nil
@@ -1325,6 +1394,21 @@ func isZoneConstrainedError(err error) bool {
 	return false
 }
 
+// classifyRunInstancesError returns the given RunInstances error
+// classified according to whether starting the instance again may
+// succeed, so the provisioner knows whether to retry it.
+func classifyRunInstancesError(err error) error {
+	switch ec2ErrCode(err) {
+	case "InstanceLimitExceeded", "InsufficientInstanceCapacity", "RequestLimitExceeded":
+		return environs.NewStartInstanceError(environs.StartInstanceQuotaExceeded, err)
+	case "InternalError", "Unavailable":
+		return environs.NewStartInstanceError(environs.StartInstanceTransient, err)
+	case "InvalidParameterCombination":
+		return environs.NewStartInstanceError(environs.StartInstanceInvalidConstraints, err)
+	}
+	return err
+}
+
 // If the err is of type *ec2.Error, ec2ErrCode returns
 // its code, otherwise it returns the empty string.
 func ec2ErrCode(err error) string {
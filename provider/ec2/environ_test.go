@@ -9,6 +9,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
 )
 
@@ -132,6 +133,20 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 			ToPort:    120,
 			SourceIPs: []string{"0.0.0.0/0"},
 		}},
+	}, {
+		about: "port range restricted to a source CIDR",
+		ports: []network.PortRange{{
+			FromPort:   443,
+			ToPort:     443,
+			Protocol:   "tcp",
+			SourceCIDR: "10.0.0.0/8",
+		}},
+		expected: []amzec2.IPPerm{{
+			Protocol:  "tcp",
+			FromPort:  443,
+			ToPort:    443,
+			SourceIPs: []string{"10.0.0.0/8"},
+		}},
 	}}
 
 	for i, t := range testCases {
@@ -140,3 +155,22 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
+
+func (*Suite) TestClassifyRunInstancesError(c *gc.C) {
+	for i, t := range []struct {
+		code string
+		kind environs.StartInstanceErrorKind
+	}{
+		{"InstanceLimitExceeded", environs.StartInstanceQuotaExceeded},
+		{"RequestLimitExceeded", environs.StartInstanceQuotaExceeded},
+		{"Unavailable", environs.StartInstanceTransient},
+		{"InsufficientInstanceCapacity", environs.StartInstanceQuotaExceeded},
+		{"InvalidParameterCombination", environs.StartInstanceInvalidConstraints},
+		{"AuthFailure", ""},
+	} {
+		c.Logf("test %d: %s", i, t.code)
+		err := classifyRunInstancesError(&amzec2.Error{Code: t.code, Message: "boom"})
+		c.Check(environs.StartInstanceErrorKindOf(err), gc.Equals, t.kind)
+		c.Check(err, gc.ErrorMatches, ".*boom.*")
+	}
+}
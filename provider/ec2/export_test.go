@@ -52,6 +52,7 @@ func InstanceEC2(inst instance.Instance) *ec2.Instance {
 
 var (
 	EC2AvailabilityZones        = &ec2AvailabilityZones
+	EC2ConsoleOutput            = &ec2ConsoleOutput
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 	RunInstances                = &runInstances
 	BlockDeviceNamer            = blockDeviceNamer
@@ -6,9 +6,12 @@ package ec2
 import (
 	"fmt"
 
+	"github.com/juju/utils/set"
+
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/version"
 )
 
 // signedImageDataOnly is defined here to allow tests to override the content.
@@ -43,23 +46,64 @@ func filterImages(images []*imagemetadata.ImageMetadata, ic *instances.InstanceC
 	return nil
 }
 
+// matchingCustomImages returns those of the given custom images that
+// are in the region, series and architectures of the supplied
+// instanceConstraint, and in the given stream.
+func matchingCustomImages(
+	customImages []*imagemetadata.ImageMetadata, stream string, ic *instances.InstanceConstraint) []*imagemetadata.ImageMetadata {
+
+	if len(customImages) == 0 {
+		return nil
+	}
+	seriesVersion, err := version.SeriesVersion(ic.Series)
+	if err != nil {
+		return nil
+	}
+	if stream == "" {
+		stream = "released"
+	}
+	arches := set.NewStrings(ic.Arches...)
+	var matching []*imagemetadata.ImageMetadata
+	for _, image := range customImages {
+		switch {
+		case image.RegionName != ic.Region:
+		case image.Version != seriesVersion:
+		case image.Stream != "" && image.Stream != stream:
+		case !arches.Contains(image.Arch):
+		default:
+			matching = append(matching, image)
+		}
+	}
+	return matching
+}
+
 // findInstanceSpec returns an InstanceSpec satisfying the supplied instanceConstraint.
+// Custom images stored on the controller are preferred to those found in
+// simplestreams; the sources are only searched if none of them match.
 func findInstanceSpec(
-	sources []simplestreams.DataSource, stream string, ic *instances.InstanceConstraint) (*instances.InstanceSpec, error) {
+	customImages []*imagemetadata.ImageMetadata,
+	sources []simplestreams.DataSource,
+	stream string,
+	ic *instances.InstanceConstraint,
+) (*instances.InstanceSpec, error) {
 
 	if ic.Constraints.CpuPower == nil {
 		ic.Constraints.CpuPower = instances.CpuPower(defaultCpuPower)
 	}
-	ec2Region := allRegions[ic.Region]
-	imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
-		CloudSpec: simplestreams.CloudSpec{ic.Region, ec2Region.EC2Endpoint},
-		Series:    []string{ic.Series},
-		Arches:    ic.Arches,
-		Stream:    stream,
-	})
-	matchingImages, _, err := imagemetadata.Fetch(sources, imageConstraint, signedImageDataOnly)
-	if err != nil {
-		return nil, err
+	matchingImages := matchingCustomImages(customImages, stream, ic)
+	if len(matchingImages) == 0 {
+		ec2Region := allRegions[ic.Region]
+		imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
+			CloudSpec: simplestreams.CloudSpec{ic.Region, ec2Region.EC2Endpoint},
+			Series:    []string{ic.Series},
+			Arches:    ic.Arches,
+			Stream:    stream,
+		})
+		var err error
+		matchingImages, _, err = imagemetadata.Fetch(sources, imageConstraint, signedImageDataOnly)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if len(matchingImages) == 0 {
 		logger.Warningf("no matching image meta data for constraints: %v", ic)
@@ -157,6 +157,7 @@ func (s *specSuite) TestFindInstanceSpec(c *gc.C) {
 			stor = []string{ssdStorage, ebsStorage}
 		}
 		spec, err := findInstanceSpec(
+			nil,
 			[]simplestreams.DataSource{
 				simplestreams.NewURLDataSource("test", "test:", utils.VerifySSLHostnames)},
 			"released",
@@ -199,6 +200,7 @@ func (s *specSuite) TestFindInstanceSpecErrors(c *gc.C) {
 	for i, t := range findInstanceSpecErrorTests {
 		c.Logf("test %d", i)
 		_, err := findInstanceSpec(
+			nil,
 			[]simplestreams.DataSource{
 				simplestreams.NewURLDataSource("test", "test:", utils.VerifySSLHostnames)},
 			"released",
@@ -212,6 +214,47 @@ func (s *specSuite) TestFindInstanceSpecErrors(c *gc.C) {
 	}
 }
 
+func (s *specSuite) TestFindInstanceSpecCustomImages(c *gc.C) {
+	customImages := []*imagemetadata.ImageMetadata{{
+		Id:         "ami-custom-other-region",
+		Storage:    "ebs",
+		VirtType:   "pv",
+		Arch:       "amd64",
+		Version:    "14.04",
+		RegionName: "other",
+	}, {
+		Id:         "ami-custom",
+		Storage:    "ebs",
+		VirtType:   "pv",
+		Arch:       "amd64",
+		Version:    "14.04",
+		RegionName: "test",
+		Stream:     "released",
+	}}
+	spec, err := findInstanceSpec(
+		customImages,
+		[]simplestreams.DataSource{
+			simplestreams.NewURLDataSource("test", "test:", utils.VerifySSLHostnames)},
+		"released",
+		&instances.InstanceConstraint{
+			Region:  "test",
+			Series:  testing.FakeDefaultSeries,
+			Arches:  both,
+			Storage: []string{ebsStorage},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.InstanceType.Name, gc.Equals, "m1.small")
+	c.Check(spec.Image.Id, gc.Equals, "ami-custom")
+
+	// Custom images of other streams are not used.
+	matching := matchingCustomImages(customImages, "daily", &instances.InstanceConstraint{
+		Region: "test",
+		Series: testing.FakeDefaultSeries,
+		Arches: both,
+	})
+	c.Check(matching, gc.HasLen, 0)
+}
+
 func (*specSuite) TestFilterImagesAcceptsNil(c *gc.C) {
 	c.Check(filterImages(nil, nil), gc.HasLen, 0)
 }
@@ -4,6 +4,7 @@
 package ec2_test
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net"
 	"regexp"
@@ -310,6 +311,32 @@ func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	c.Assert(*hc.CpuPower, gc.Equals, uint64(100))
 }
 
+func (t *localServerSuite) TestStartInstances(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var args []environs.StartInstanceParams
+	for _, machineId := range []string{"1", "2", "3"} {
+		params, err := testing.CompleteStartInstanceParams(env, machineId, environs.StartInstanceParams{}, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		args = append(args, params)
+	}
+	results := env.(environs.BulkInstanceBroker).StartInstances(args)
+	c.Assert(results, gc.HasLen, 3)
+	ids := set.NewStrings()
+	for i, result := range results {
+		c.Logf("result %d", i)
+		c.Assert(result.Error, jc.ErrorIsNil)
+		ids.Add(string(result.Result.Instance.Id()))
+	}
+	c.Assert(ids.Size(), gc.Equals, 3)
+
+	insts, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 4)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZone(c *gc.C) {
 	inst, err := t.testStartInstanceAvailZone(c, "test-available")
 	c.Assert(err, jc.ErrorIsNil)
@@ -348,7 +375,7 @@ func (t *localServerSuite) TestGetAvailabilityZones(c *gc.C) {
 		}
 		return resp, resultErr
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 
 	resultErr = fmt.Errorf("failed to get availability zones")
 	zones, err := env.AvailabilityZones()
@@ -382,7 +409,7 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 		}
 		return resp, nil
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 	resultZones = make([]amzec2.AvailabilityZoneInfo, 2)
 	resultZones[0].Name = "az1"
 	resultZones[1].Name = "az2"
@@ -397,6 +424,30 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 	c.Assert(zones[1].Available(), jc.IsFalse)
 }
 
+func (t *localServerSuite) TestInstanceConsoleOutput(c *gc.C) {
+	var requested []string
+	t.PatchValue(ec2.EC2ConsoleOutput, func(e *amzec2.EC2, instId string) (string, error) {
+		requested = append(requested, instId)
+		return base64.StdEncoding.EncodeToString([]byte("Cloud-init finished\n")), nil
+	})
+	env := t.Prepare(c)
+	console, ok := environs.SupportsInstanceConsole(env)
+	c.Assert(ok, jc.IsTrue)
+	output, err := console.InstanceConsoleOutput("i-123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.Equals, "Cloud-init finished\n")
+	c.Assert(requested, jc.DeepEquals, []string{"i-123"})
+}
+
+func (t *localServerSuite) TestInstanceConsoleOutputError(c *gc.C) {
+	t.PatchValue(ec2.EC2ConsoleOutput, func(e *amzec2.EC2, instId string) (string, error) {
+		return "", fmt.Errorf("instance not found")
+	})
+	env := t.Prepare(c).(environs.InstanceConsole)
+	_, err := env.InstanceConsoleOutput("i-123")
+	c.Assert(err, gc.ErrorMatches, `cannot get console output of instance "i-123": instance not found`)
+}
+
 type mockAvailabilityZoneAllocations struct {
 	group  []instance.Id // input param
 	result []common.AvailabilityZoneInstances
@@ -404,7 +455,7 @@ type mockAvailabilityZoneAllocations struct {
 }
 
 func (t *mockAvailabilityZoneAllocations) AvailabilityZoneAllocations(
-	e common.ZonedEnviron, group []instance.Id,
+	e environs.ZonedEnviron, group []instance.Id,
 ) ([]common.AvailabilityZoneInstances, error) {
 	t.group = group
 	return t.result, t.err
@@ -536,6 +587,72 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 	c.Assert(azArgs, gc.DeepEquals, []string{"az1", "az2"})
 }
 
+func (t *localServerSuite) TestStartInstanceZonesConstraint(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{
+			{ZoneName: "az1"}, {ZoneName: "az2"}, {ZoneName: "az3"},
+		},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+
+	var azArgs []string
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		azArgs = append(azArgs, ri.AvailZone)
+		return nil, azConstrainedErr
+	})
+	params := environs.StartInstanceParams{Constraints: constraints.MustParse("zones=az3,az2")}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot run instances: .*")
+	c.Assert(azArgs, gc.DeepEquals, []string{"az2", "az3"})
+
+	params = environs.StartInstanceParams{Constraints: constraints.MustParse("zones=az4")}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `no available zones match zones constraint "az4"`)
+}
+
+func (t *localServerSuite) TestStartInstanceProvisionerZones(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The zones chosen by the provisioner are used instead of the
+	// provider's own allocations.
+	mock := mockAvailabilityZoneAllocations{
+		err: fmt.Errorf("AvailabilityZoneAllocations should not be called"),
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+
+	var azArgs []string
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		azArgs = append(azArgs, ri.AvailZone)
+		return nil, azConstrainedErr
+	})
+	params := environs.StartInstanceParams{
+		AvailabilityZones: []string{"az3", "az1", "az2"},
+		Constraints:       constraints.MustParse("zones=az1,az2"),
+	}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot run instances: .*")
+	c.Assert(azArgs, gc.DeepEquals, []string{"az1", "az2"})
+}
+
+func (t *localServerSuite) TestStartInstanceAvailZoneNotInZonesConstraint(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := environs.StartInstanceParams{
+		Placement:   "zone=test-available",
+		Constraints: constraints.MustParse("zones=az1"),
+	}
+	_, err = testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `availability zone "test-available" not allowed by zones constraint`)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZoneOneConstrained(c *gc.C) {
 	t.testStartInstanceAvailZoneOneConstrained(c, azConstrainedErr)
 }
@@ -608,10 +725,10 @@ func (t *localServerSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	env := t.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=foo")
+	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm zones=az1")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, gc.DeepEquals, []string{"tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type"})
 }
 
 func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -675,6 +792,14 @@ func (t *localServerSuite) TestPrecheckInstanceAvailZoneUnavailable(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceAvailZoneNotInZonesConstraint(c *gc.C) {
+	env := t.Prepare(c)
+	placement := "zone=test-available"
+	cons := constraints.MustParse("zones=test-impaired")
+	err := env.PrecheckInstance(coretesting.FakeDefaultSeries, cons, placement)
+	c.Assert(err, gc.ErrorMatches, `availability zone "test-available" not allowed by zones constraint`)
+}
+
 func (t *localServerSuite) TestPrecheckInstanceAvailZoneUnknown(c *gc.C) {
 	env := t.Prepare(c)
 	placement := "zone=test-unknown"
@@ -13,13 +13,13 @@ import (
 )
 
 // AvailabilityZones returns all availability zones in the environment.
-func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (env *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	zones, err := env.gce.AvailabilityZones(env.ecfg.region())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var result []common.AvailabilityZone
+	var result []environs.AvailabilityZone
 	for _, zone := range zones {
 		zoneCopy := zone
 		result = append(result, &zoneCopy)
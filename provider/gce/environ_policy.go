@@ -67,6 +67,8 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.VirtType,
+	constraints.Zones,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
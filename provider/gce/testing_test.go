@@ -327,7 +327,7 @@ func (fc *fakeCommon) Destroy(env environs.Environ) error {
 	return fc.err()
 }
 
-func (fc *fakeCommon) AvailabilityZoneAllocations(env common.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
+func (fc *fakeCommon) AvailabilityZoneAllocations(env environs.ZonedEnviron, group []instance.Id) ([]common.AvailabilityZoneInstances, error) {
 	fc.addCall("AvailabilityZoneAllocations", FakeCallArgs{
 		"env":   env,
 		"group": group,
@@ -64,11 +64,11 @@ func (s *FirewallSuite) TestRuleCreation(c *gc.C) {
 		expected string
 	}{{
 		"single port firewall rule",
-		network.PortRange{80, 80, "tcp"},
+		network.PortRange{80, 80, "tcp", ""},
 		"FROM tag env TO tag juju ALLOW tcp PORT 80",
 	}, {
 		"multiple port firewall rule",
-		network.PortRange{80, 81, "tcp"},
+		network.PortRange{80, 81, "tcp", ""},
 		"FROM tag env TO tag juju ALLOW tcp ( PORT 80 AND PORT 81 )",
 	}}
 
@@ -344,6 +344,7 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -355,6 +356,7 @@ func (env *localEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		return nil, err
 	}
 	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	validator.RegisterVocabulary(constraints.VirtType, []string{string(env.config.container())})
 	return validator, nil
 }
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2/bson"
@@ -95,7 +96,7 @@ type maasEnviron struct {
 	storageUnlocked    storage.Storage
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 }
 
 var _ environs.Environ = (*maasEnviron)(nil)
@@ -113,6 +114,9 @@ func NewEnviron(cfg *config.Config) (*maasEnviron, error) {
 
 // Bootstrap is specified in the Environ interface.
 func (env *maasEnviron) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
+	if args.BootstrapHost != "" {
+		return common.BootstrapExistingHost(ctx, env, args)
+	}
 	result, series, finalizer, err := common.BootstrapInstance(ctx, env, args)
 	if err != nil {
 		return "", "", nil, err
@@ -404,7 +408,7 @@ func (z maasAvailabilityZone) Available() bool {
 
 // AvailabilityZones returns a slice of availability zones
 // for the configured region.
-func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *maasEnviron) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -421,7 +425,7 @@ func (e *maasEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
 			return nil, err
 		}
 		logger.Debugf("availability zones: %+v", list)
-		availabilityZones := make([]common.AvailabilityZone, len(list))
+		availabilityZones := make([]environs.AvailabilityZone, len(list))
 		for i, obj := range list {
 			zone, err := obj.GetMap()
 			if err != nil {
@@ -575,10 +579,8 @@ func convertConstraints(cons constraints.Value) url.Values {
 			params.Add("not_tags", strings.Join(notTags, ","))
 		}
 	}
-	// TODO(bug 1212689): ignore root-disk constraint for now.
-	if cons.RootDisk != nil {
-		logger.Warningf("ignoring unsupported constraint 'root-disk'")
-	}
+	// The root-disk constraint is passed to MAAS as part of the
+	// storage parameter; see buildMAASVolumeParameters.
 	if cons.CpuPower != nil {
 		logger.Warningf("ignoring unsupported constraint 'cpu-power'")
 	}
@@ -621,9 +623,15 @@ func addNetworks(params url.Values, includeNetworks, excludeNetworks []string) {
 }
 
 // acquireNode allocates a node from the MAAS.
-func (environ *maasEnviron) acquireNode(nodeName, zoneName string, cons constraints.Value, includeNetworks, excludeNetworks []string) (gomaasapi.MAASObject, error) {
+func (environ *maasEnviron) acquireNode(
+	nodeName, zoneName string,
+	cons constraints.Value,
+	includeNetworks, excludeNetworks []string,
+	volumes []volumeInfo,
+) (gomaasapi.MAASObject, error) {
 	acquireParams := convertConstraints(cons)
 	addNetworks(acquireParams, includeNetworks, excludeNetworks)
+	addVolumes(acquireParams, volumes)
 	acquireParams.Add("agent_name", environ.ecfg().maasAgentName())
 	if zoneName != "" {
 		acquireParams.Add("zone", zoneName)
@@ -687,6 +695,7 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -698,6 +707,7 @@ func (environ *maasEnviron) ConstraintsValidator() (constraints.Validator, error
 		return nil, err
 	}
 	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	validator.RegisterVocabulary(constraints.VirtType, []string{constraints.VirtTypeMetal})
 	return validator, nil
 }
 
@@ -821,12 +831,19 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 	includeNetworks := append(args.Constraints.IncludeNetworks(), requestedNetworks...)
 	excludeNetworks := args.Constraints.ExcludeNetworks()
 
+	// Storage.
+	volumes, err := buildMAASVolumeParameters(args.Volumes, args.Constraints)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid volume parameters")
+	}
+
 	snArgs := selectNodeArgs{
 		Constraints:       args.Constraints,
 		AvailabilityZones: availabilityZones,
 		NodeName:          nodeName,
 		IncludeNetworks:   includeNetworks,
 		ExcludeNetworks:   excludeNetworks,
+		Volumes:           volumes,
 	}
 	node, err := environ.selectNode(snArgs)
 	if err != nil {
@@ -886,6 +903,22 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 	}
 	logger.Debugf("started instance %q", inst.Id())
 
+	requestedVolumes := make([]names.VolumeTag, len(args.Volumes))
+	for i, v := range args.Volumes {
+		requestedVolumes[i] = v.Tag
+	}
+	resultVolumes, resultAttachments, err := inst.volumes(
+		names.NewMachineTag(args.MachineConfig.MachineId),
+		requestedVolumes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(resultVolumes) != len(requestedVolumes) {
+		err = errors.Errorf("requested %v storage volumes. %v returned.", len(requestedVolumes), len(resultVolumes))
+		return nil, err
+	}
+
 	if multiwatcher.AnyJobNeedsState(args.MachineConfig.Jobs...) {
 		if err := common.AddStateInstance(environ.Storage(), inst.Id()); err != nil {
 			logger.Errorf("could not record instance in provider-state: %v", err)
@@ -893,9 +926,11 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 	}
 
 	return &environs.StartInstanceResult{
-		Instance:    inst,
-		Hardware:    hc,
-		NetworkInfo: networkInfo,
+		Instance:          inst,
+		Hardware:          hc,
+		NetworkInfo:       networkInfo,
+		Volumes:           resultVolumes,
+		VolumeAttachments: resultAttachments,
 	}, nil
 }
 
@@ -965,6 +1000,7 @@ type selectNodeArgs struct {
 	Constraints       constraints.Value
 	IncludeNetworks   []string
 	ExcludeNetworks   []string
+	Volumes           []volumeInfo
 }
 
 func (environ *maasEnviron) selectNode(args selectNodeArgs) (*gomaasapi.MAASObject, error) {
@@ -978,6 +1014,7 @@ func (environ *maasEnviron) selectNode(args selectNodeArgs) (*gomaasapi.MAASObje
 			args.Constraints,
 			args.IncludeNetworks,
 			args.ExcludeNetworks,
+			args.Volumes,
 		)
 
 		if err, ok := err.(gomaasapi.ServerError); ok && err.StatusCode == http.StatusConflict {
@@ -15,5 +15,9 @@ const (
 func init() {
 	environs.RegisterProvider(providerType, maasEnvironProvider{})
 
-	registry.RegisterEnvironStorageProviders(providerType)
+	// Register the MAAS specific providers.
+	registry.RegisterProvider(maasStorageProviderType, &maasStorageProvider{})
+
+	// Inform the storage provider registry about the MAAS providers.
+	registry.RegisterEnvironStorageProviders(providerType, maasStorageProviderType)
 }
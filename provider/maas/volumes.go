@@ -0,0 +1,273 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// maasStorageProviderType is the name of the storage provider
+	// used to specify storage when acquiring MAAS nodes.
+	maasStorageProviderType = storage.ProviderType("maas")
+
+	// rootDiskLabel is the label recognised by MAAS as being for
+	// the root disk.
+	rootDiskLabel = "root"
+
+	// tagsAttribute is the name of the pool attribute used
+	// to specify tag values for requested volumes.
+	tagsAttribute = "tags"
+)
+
+// maasStorageProvider allocates the physical disks of MAAS nodes as
+// volumes. The disks are selected when the node is acquired, so
+// volumes cannot be created or attached dynamically.
+type maasStorageProvider struct{}
+
+var _ storage.Provider = (*maasStorageProvider)(nil)
+
+var validConfigOptions = set.NewStrings(
+	tagsAttribute,
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (*maasStorageProvider) ValidateConfig(providerConfig *storage.Config) error {
+	for attr, value := range providerConfig.Attrs() {
+		if !validConfigOptions.Contains(attr) {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if _, err := parseVolumeTags(value); err != nil {
+			return errors.Annotatef(err, "invalid %s value", attr)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (*maasStorageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*maasStorageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (*maasStorageProvider) Dynamic() bool {
+	return false
+}
+
+// VolumeSource is defined on the Provider interface.
+func (*maasStorageProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	// Dynamic volumes are not supported.
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*maasStorageProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// parseVolumeTags parses the tags attribute of a pool, which may be
+// given as a comma separated string or as a list of strings.
+func parseVolumeTags(value interface{}) ([]string, error) {
+	var tags []string
+	switch value := value.(type) {
+	case string:
+		tags = strings.Split(value, ",")
+	case []string:
+		tags = value
+	case []interface{}:
+		for _, tag := range value {
+			tag, ok := tag.(string)
+			if !ok {
+				return nil, errors.Errorf("expected string tags, got %T", tag)
+			}
+			tags = append(tags, tag)
+		}
+	default:
+		return nil, errors.Errorf("expected string or list of strings, got %T", value)
+	}
+	var result []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// volumeInfo describes a disk to be selected when acquiring a node.
+type volumeInfo struct {
+	name     string
+	sizeInGB uint64
+	tags     []string
+}
+
+// mibToGb converts the size in MiB to the nearest GB (base-10),
+// rounding up, which is what MAAS uses for storage constraints.
+func mibToGb(m uint64) uint64 {
+	const mib, gb = 1024 * 1024, 1000 * 1000 * 1000
+	return (m*mib + gb - 1) / gb
+}
+
+// buildMAASVolumeParameters creates the volumeInfo values to be passed
+// to MAAS when acquiring a node, for the given volume parameters and
+// the machine's root-disk constraint. The root disk is always first, so
+// that MAAS does not choose one of the requested volumes as the root
+// disk.
+func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Value) ([]volumeInfo, error) {
+	if len(args) == 0 && cons.RootDisk == nil {
+		return nil, nil
+	}
+	volumes := make([]volumeInfo, len(args)+1)
+	rootVolume := volumeInfo{name: rootDiskLabel}
+	if cons.RootDisk != nil {
+		rootVolume.sizeInGB = mibToGb(*cons.RootDisk)
+	}
+	volumes[0] = rootVolume
+	for i, v := range args {
+		info := volumeInfo{
+			name:     v.Tag.Id(),
+			sizeInGB: mibToGb(v.Size),
+		}
+		if value, ok := v.Attributes[tagsAttribute]; ok {
+			tags, err := parseVolumeTags(value)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid tags for volume %v", v.Tag.Id())
+			}
+			info.tags = tags
+		}
+		volumes[i+1] = info
+	}
+	return volumes, nil
+}
+
+// addVolumes converts the volumes to the "storage" parameter of the
+// MAAS acquire call, of the form "label:size(tag,tag),...".
+func addVolumes(params url.Values, volumes []volumeInfo) {
+	if len(volumes) == 0 {
+		return
+	}
+	volParams := make([]string, len(volumes))
+	for i, v := range volumes {
+		var volStr string
+		if v.name != "" {
+			volStr = v.name + ":"
+		}
+		volStr += fmt.Sprintf("%d", v.sizeInGB)
+		if len(v.tags) > 0 {
+			volStr += "(" + strings.Join(v.tags, ",") + ")"
+		}
+		volParams[i] = volStr
+	}
+	params.Add("storage", strings.Join(volParams, ","))
+}
+
+// volumes returns the volumes and volume attachments of the node for
+// the requested volumes, which are matched to the node's physical block
+// devices by the labels MAAS reports in the node's constraint map.
+func (mi *maasInstance) volumes(mTag names.MachineTag, requestedVolumes []names.VolumeTag) ([]storage.Volume, []storage.VolumeAttachment, error) {
+	fields := mi.getMaasObject().GetMap()
+	deviceInfo, ok := fields["physicalblockdevice_set"]
+	// Older MAAS servers don't support storage.
+	if !ok || deviceInfo.IsNil() {
+		return nil, nil, nil
+	}
+	labelsMap, ok := fields["constraint_map"]
+	if !ok || labelsMap.IsNil() {
+		return nil, nil, errors.NotFoundf("constraint map field")
+	}
+	devices, err := deviceInfo.GetArray()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	// The device labels are the volume labels passed to MAAS in
+	// the storage parameter of the acquire call, keyed by the ids
+	// of the devices selected for them.
+	deviceLabels, err := labelsMap.GetMap()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	// Only report the volumes that were asked for; the root disk,
+	// in particular, is not a volume managed by Juju.
+	validVolumes := set.NewStrings()
+	for _, v := range requestedVolumes {
+		validVolumes.Add(v.Id())
+	}
+
+	var volumes []storage.Volume
+	var attachments []storage.VolumeAttachment
+	for _, d := range devices {
+		deviceAttrs, err := d.GetMap()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		// The ids of devices are numeric, but the keys
+		// of the constraint map are strings.
+		id, err := deviceAttrs["id"].GetFloat64()
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "invalid device id")
+		}
+		label, ok := deviceLabels[strconv.Itoa(int(id))]
+		if !ok {
+			continue
+		}
+		volumeLabel, err := label.GetString()
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid label for device %d", int(id))
+		}
+		if !validVolumes.Contains(volumeLabel) {
+			continue
+		}
+		volumeTag := names.NewVolumeTag(volumeLabel)
+
+		size, err := deviceAttrs["size"].GetFloat64()
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid size for volume %v", volumeLabel)
+		}
+		deviceName, err := deviceAttrs["name"].GetString()
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid device name for volume %v", volumeLabel)
+		}
+		// Not all disks have a serial number.
+		var serial string
+		if value, ok := deviceAttrs["serial"]; ok && !value.IsNil() {
+			if serial, err = value.GetString(); err != nil {
+				return nil, nil, errors.Annotatef(err, "invalid serial for volume %v", volumeLabel)
+			}
+		}
+
+		volumes = append(volumes, storage.Volume{
+			Tag:      volumeTag,
+			VolumeId: volumeTag.String(),
+			Serial:   serial,
+			// MAAS reports the size in bytes.
+			Size: uint64(size) / (1024 * 1024),
+			// The disk is part of the node, and so goes
+			// when the node is released.
+			Persistent: false,
+		})
+		attachments = append(attachments, storage.VolumeAttachment{
+			Volume:     volumeTag,
+			Machine:    mTag,
+			DeviceName: deviceName,
+		})
+	}
+	return volumes, attachments, nil
+}
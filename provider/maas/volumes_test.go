@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maas
+
+import (
+	"net/url"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/storage"
+)
+
+type volumeSuite struct {
+	providerSuite
+}
+
+var _ = gc.Suite(&volumeSuite{})
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersNoVolumes(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters(nil, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, gc.HasLen, 0)
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersJustRootDisk(c *gc.C) {
+	var cons constraints.Value
+	rootSize := uint64(20000)
+	cons.RootDisk = &rootSize
+	vInfo, err := buildMAASVolumeParameters(nil, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 21, nil},
+	})
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersNoTags(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000},
+	}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, nil},
+		{"1", 2098, nil},
+	})
+}
+
+func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("1"),
+		Size:       2000000,
+		Attributes: map[string]interface{}{"tags": "tag1, tag2"},
+	}, {
+		Tag:        names.NewVolumeTag("2"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"tags": []interface{}{"ssd"}},
+	}}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, nil},
+		{"1", 2098, []string{"tag1", "tag2"}},
+		{"2", 2, []string{"ssd"}},
+	})
+}
+
+func (s *volumeSuite) TestAddVolumes(c *gc.C) {
+	params := url.Values{}
+	addVolumes(params, []volumeInfo{
+		{"root", 0, nil},
+		{"1", 2098, []string{"tag1", "tag2"}},
+		{"", 10, nil},
+	})
+	c.Assert(params.Get("storage"), gc.Equals, "root:0,1:2098(tag1,tag2),10")
+}
+
+func (s *volumeSuite) TestValidateConfig(c *gc.C) {
+	p := &maasStorageProvider{}
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"tags": "ssd,fast"},
+	}, {
+		attrs: map[string]interface{}{"tags": []interface{}{"ssd"}},
+	}, {
+		attrs: map[string]interface{}{"invalid": "config"},
+		err:   `unknown provider config option "invalid"`,
+	}, {
+		attrs: map[string]interface{}{"tags": 123},
+		err:   `invalid tags value: expected string or list of strings, got int`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("foo", maasStorageProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *volumeSuite) TestSupports(c *gc.C) {
+	p := &maasStorageProvider{}
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Dynamic(), jc.IsFalse)
+}
+
+func (s *volumeSuite) TestInstanceVolumes(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
+	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	mTag := names.NewMachineTag("1")
+	volumes, attachments, err := instance.volumes(mTag, []names.VolumeTag{
+		names.NewVolumeTag("1"),
+		names.NewVolumeTag("2"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// Expect 2 volumes - root volume is ignored.
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("1"),
+		VolumeId: "volume-1",
+		Serial:   "WD-WMAP9A9",
+		Size:     238472,
+	}, {
+		Tag:      names.NewVolumeTag("2"),
+		VolumeId: "volume-2",
+		Size:     238472,
+	}})
+	c.Assert(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:     names.NewVolumeTag("1"),
+		Machine:    mTag,
+		DeviceName: "sdb",
+	}, {
+		Volume:     names.NewVolumeTag("2"),
+		Machine:    mTag,
+		DeviceName: "sdc",
+	}})
+}
+
+func (s *volumeSuite) TestInstanceVolumesOldMAAS(c *gc.C) {
+	obj := s.testMAASObject.TestServer.NewNode(`{"system_id": "node0"}`)
+	instance := maasInstance{maasObject: &obj, environ: s.makeEnviron()}
+	volumes, attachments, err := instance.volumes(names.NewMachineTag("1"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 0)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+var validVolumeJson = `
+{
+    "system_id": "node0",
+    "physicalblockdevice_set": [
+        {
+            "name": "sda",
+            "tags": ["ssd"],
+            "id_path": "/dev/disk/by-id/id_for_sda",
+            "path": "/dev/sda",
+            "model": "Samsung_SSD_850_EVO_250GB",
+            "block_size": 4096,
+            "serial": "S21NNSAFC38075L",
+            "id": 1,
+            "size": 250057060352
+        },
+        {
+            "name": "sdb",
+            "tags": ["rotary"],
+            "id_path": "/dev/disk/by-id/id_for_sdb",
+            "path": "/dev/sdb",
+            "model": "WD",
+            "block_size": 4096,
+            "serial": "WD-WMAP9A9",
+            "id": 2,
+            "size": 250057060352
+        },
+        {
+            "name": "sdc",
+            "tags": [],
+            "path": "/dev/sdc",
+            "block_size": 4096,
+            "serial": null,
+            "id": 3,
+            "size": 250057060352
+        }
+    ],
+    "constraint_map": {
+        "1": "root",
+        "2": "1",
+        "3": "2"
+    }
+}
+`[1:]
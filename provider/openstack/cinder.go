@@ -0,0 +1,317 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	CinderProviderType = storage.ProviderType("cinder")
+
+	// Config attributes
+
+	// The Cinder volume type to create volumes with, which selects
+	// the storage backend on clouds with more than one.
+	CinderVolumeType = "volume-type"
+
+	// The availability zone in which volumes will be created.
+	CinderAvailabilityZone = "availability-zone"
+)
+
+const (
+	// volumeStatusAvailable is the status of Cinder volumes that are
+	// ready to be attached.
+	volumeStatusAvailable = "available"
+
+	// volumeStatusError is the status of Cinder volumes that could
+	// not be created.
+	volumeStatusError = "error"
+)
+
+// cinderAttempt is used to poll the status of volumes being created.
+var cinderAttempt = utils.AttemptStrategy{
+	Total: 1 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// cinderProvider creates volume sources which use OpenStack Cinder
+// volumes, attached to instances through Nova.
+type cinderProvider struct {
+	// newStorage returns the interface to the OpenStack services
+	// for the given environment configuration.
+	newStorage func(*config.Config) (openstackStorage, error)
+}
+
+var _ storage.Provider = (*cinderProvider)(nil)
+
+var validCinderConfigOptions = set.NewStrings(
+	CinderVolumeType,
+	CinderAvailabilityZone,
+)
+
+// newOpenstackStorage returns the interface to the Cinder and Nova
+// services of the OpenStack cloud of the given environment.
+func newOpenstackStorage(cfg *config.Config) (openstackStorage, error) {
+	env, err := providerInstance.Open(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e := env.(*environ)
+	if err := authenticateClient(e); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &cinderClient{e.client}, nil
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (p *cinderProvider) ValidateConfig(providerConfig *storage.Config) error {
+	for attr, value := range providerConfig.Attrs() {
+		if !validCinderConfigOptions.Contains(attr) {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if _, ok := value.(string); !ok {
+			return errors.Errorf("invalid %s value %v, expected string", attr, value)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (p *cinderProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (p *cinderProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (p *cinderProvider) Dynamic() bool {
+	return true
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *cinderProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(providerConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	storageAdapter, err := p.newStorage(environConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "connecting to OpenStack")
+	}
+	return &cinderVolumeSource{storageAdapter}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *cinderProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+type cinderVolumeSource struct {
+	storageAdapter openstackStorage
+}
+
+var _ storage.VolumeSource = (*cinderVolumeSource)(nil)
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+//
+// Cinder volumes exist independently of the instances they are
+// attached to, so the volumes are always persistent, and are attached
+// separately by AttachVolumes.
+func (s *cinderVolumeSource) CreateVolumes(args []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(args))
+
+	// If there's an error, we delete any ones that are created.
+	defer func() {
+		if err != nil && len(volumes) > 0 {
+			volIds := make([]string, len(volumes))
+			for i, v := range volumes {
+				volIds[i] = v.VolumeId
+			}
+			for i, volErr := range s.DestroyVolumes(volIds) {
+				if volErr != nil {
+					logger.Warningf("error cleaning up volume %v: %v", volumes[i].Tag, volErr)
+				}
+			}
+		}
+	}()
+
+	for _, arg := range args {
+		if err := s.ValidateVolumeParams(arg); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	for _, arg := range args {
+		volumeType, _ := arg.Attributes[CinderVolumeType].(string)
+		availabilityZone, _ := arg.Attributes[CinderAvailabilityZone].(string)
+		cinderVolume, err := s.storageAdapter.CreateVolume(createCinderVolume{
+			// Juju size is MiB, Cinder size is GiB.
+			Size:             int(mibToGib(arg.Size)),
+			Name:             arg.Tag.String(),
+			VolumeType:       volumeType,
+			AvailabilityZone: availabilityZone,
+		})
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %v", arg.Tag.Id())
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:        arg.Tag,
+			VolumeId:   cinderVolume.Id,
+			Size:       gibToMib(uint64(cinderVolume.Size)),
+			Persistent: true,
+		})
+		if err := s.waitVolumeAvailable(cinderVolume.Id); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return volumes, nil, nil
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	volumes := make([]storage.Volume, len(volIds))
+	for i, volumeId := range volIds {
+		cinderVolume, err := s.storageAdapter.GetVolume(volumeId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		volumes[i] = storage.Volume{
+			VolumeId:   cinderVolume.Id,
+			Size:       gibToMib(uint64(cinderVolume.Size)),
+			Persistent: true,
+		}
+	}
+	return volumes, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volumeId := range volIds {
+		err := s.storageAdapter.DeleteVolume(volumeId)
+		if err != nil && !errors.IsNotFound(err) {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	for _, attr := range []string{CinderVolumeType, CinderAvailabilityZone} {
+		if value, ok := params.Attributes[attr]; ok {
+			if _, ok := value.(string); !ok {
+				return errors.Errorf("invalid %s value %v, expected string", attr, value)
+			}
+		}
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(args))
+	for i, arg := range args {
+		device, err := s.attachVolume(string(arg.InstanceId), arg.VolumeId)
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching %v to %v", arg.Volume.Id(), arg.Machine.Id())
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:     arg.Volume,
+			Machine:    arg.Machine,
+			DeviceName: strings.TrimPrefix(device, "/dev/"),
+		}
+	}
+	return attachments, nil
+}
+
+// attachVolume attaches the volume to the server, if it is not already
+// attached, and returns the device name of the attachment.
+func (s *cinderVolumeSource) attachVolume(serverId, volumeId string) (string, error) {
+	attachment, err := s.volumeAttachment(serverId, volumeId)
+	if err == nil {
+		return attachment.Device, nil
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	// Leave the choice of device name to Nova.
+	attachment, err = s.storageAdapter.AttachVolume(serverId, volumeId, "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return attachment.Device, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) error {
+	for _, arg := range args {
+		serverId := string(arg.InstanceId)
+		attachment, err := s.volumeAttachment(serverId, arg.VolumeId)
+		if errors.IsNotFound(err) {
+			// The volume is already detached.
+			continue
+		} else if err != nil {
+			return errors.Annotatef(err, "detaching %v from %v", arg.Volume.Id(), arg.Machine.Id())
+		}
+		err = s.storageAdapter.DetachVolume(serverId, attachment.Id)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "detaching %v from %v", arg.Volume.Id(), arg.Machine.Id())
+		}
+	}
+	return nil
+}
+
+// volumeAttachment returns the attachment of the volume to the server,
+// or an error satisfying errors.IsNotFound if there is none.
+func (s *cinderVolumeSource) volumeAttachment(serverId, volumeId string) (*novaVolumeAttachment, error) {
+	attachments, err := s.storageAdapter.ListVolumeAttachments(serverId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, attachment := range attachments {
+		if attachment.VolumeId == volumeId {
+			return &attachment, nil
+		}
+	}
+	return nil, errors.NotFoundf("attachment of volume %q to server %q", volumeId, serverId)
+}
+
+// waitVolumeAvailable waits for the newly created volume to become
+// available for attachment.
+func (s *cinderVolumeSource) waitVolumeAvailable(volumeId string) error {
+	for a := cinderAttempt.Start(); a.Next(); {
+		volume, err := s.storageAdapter.GetVolume(volumeId)
+		if err != nil {
+			return errors.Annotatef(err, "querying volume %q", volumeId)
+		}
+		switch volume.Status {
+		case volumeStatusAvailable:
+			return nil
+		case volumeStatusError:
+			return errors.Errorf("volume %q failed to be created", volumeId)
+		}
+	}
+	return errors.Errorf("timed out waiting for volume %q to become available", volumeId)
+}
+
+// mibToGib converts mebibytes to gibibytes, rounding up, as Cinder
+// volume sizes are given in GiB.
+func mibToGib(m uint64) uint64 {
+	return (m + 1023) / 1024
+}
+
+// gibToMib converts gibibytes to mebibytes.
+func gibToMib(g uint64) uint64 {
+	return g * 1024
+}
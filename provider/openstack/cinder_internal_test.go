@@ -0,0 +1,274 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type cinderVolumeSourceSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&cinderVolumeSourceSuite{})
+
+func (s *cinderVolumeSourceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&cinderAttempt, utils.AttemptStrategy{Min: 3})
+}
+
+func (s *cinderVolumeSourceSuite) TestValidateConfig(c *gc.C) {
+	p := &cinderProvider{}
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"volume-type": "ssd", "availability-zone": "nova"},
+	}, {
+		attrs: map[string]interface{}{"invalid": "config"},
+		err:   `unknown provider config option "invalid"`,
+	}, {
+		attrs: map[string]interface{}{"volume-type": 123},
+		err:   `invalid volume-type value 123, expected string`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("foo", CinderProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *cinderVolumeSourceSuite) TestSupports(c *gc.C) {
+	p := &cinderProvider{}
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *cinderVolumeSourceSuite) TestCreateVolumes(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		createVolume: func(args createCinderVolume) (*cinderVolume, error) {
+			c.Check(args, jc.DeepEquals, createCinderVolume{
+				Size:       2,
+				Name:       "volume-123",
+				VolumeType: "ssd",
+			})
+			return &cinderVolume{Id: "vol-123", Size: args.Size, Status: "creating"}, nil
+		},
+		getVolume: func(volumeId string) (*cinderVolume, error) {
+			c.Check(volumeId, gc.Equals, "vol-123")
+			return &cinderVolume{Id: volumeId, Size: 2, Status: volumeStatusAvailable}, nil
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	volumes, attachments, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("123"),
+		Size:       1025,
+		Provider:   CinderProviderType,
+		Attributes: map[string]interface{}{CinderVolumeType: "ssd"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:        names.NewVolumeTag("123"),
+		VolumeId:   "vol-123",
+		Size:       2048,
+		Persistent: true,
+	}})
+	mockAdapter.CheckCallNames(c, "CreateVolume", "GetVolume")
+}
+
+func (s *cinderVolumeSourceSuite) TestCreateVolumesErrorCleansUp(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		createVolume: func(args createCinderVolume) (*cinderVolume, error) {
+			return &cinderVolume{Id: "vol-123", Size: args.Size}, nil
+		},
+		getVolume: func(volumeId string) (*cinderVolume, error) {
+			return &cinderVolume{Id: volumeId, Size: 1, Status: volumeStatusError}, nil
+		},
+		deleteVolume: func(volumeId string) error {
+			c.Check(volumeId, gc.Equals, "vol-123")
+			return nil
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("123"),
+		Size: 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, `volume "vol-123" failed to be created`)
+	mockAdapter.CheckCallNames(c, "CreateVolume", "GetVolume", "DeleteVolume")
+}
+
+func (s *cinderVolumeSourceSuite) TestDestroyVolumesNotFound(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		deleteVolume: func(volumeId string) error {
+			if volumeId == "vol-456" {
+				return errors.New("boom")
+			}
+			return errors.NotFoundf("volume %q", volumeId)
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	errs := source.DestroyVolumes([]string{"vol-123", "vol-456"})
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `destroying "vol-456": boom`)
+}
+
+func (s *cinderVolumeSourceSuite) TestAttachVolumes(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		attachVolume: func(serverId, volumeId, device string) (*novaVolumeAttachment, error) {
+			c.Check(serverId, gc.Equals, "inst-0")
+			c.Check(volumeId, gc.Equals, "vol-123")
+			return &novaVolumeAttachment{
+				Id:       "att-0",
+				VolumeId: volumeId,
+				ServerId: serverId,
+				Device:   "/dev/vdb",
+			}, nil
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	attachments, err := source.AttachVolumes([]storage.VolumeAttachmentParams{
+		volumeAttachmentParams("0", "123", "inst-0", "vol-123"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:     names.NewVolumeTag("123"),
+		Machine:    names.NewMachineTag("0"),
+		DeviceName: "vdb",
+	}})
+	mockAdapter.CheckCallNames(c, "ListVolumeAttachments", "AttachVolume")
+}
+
+func (s *cinderVolumeSourceSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		listVolumeAttachments: func(serverId string) ([]novaVolumeAttachment, error) {
+			return []novaVolumeAttachment{{
+				Id:       "att-0",
+				VolumeId: "vol-123",
+				ServerId: serverId,
+				Device:   "/dev/vdc",
+			}}, nil
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	attachments, err := source.AttachVolumes([]storage.VolumeAttachmentParams{
+		volumeAttachmentParams("0", "123", "inst-0", "vol-123"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].DeviceName, gc.Equals, "vdc")
+	mockAdapter.CheckCallNames(c, "ListVolumeAttachments")
+}
+
+func (s *cinderVolumeSourceSuite) TestDetachVolumes(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		listVolumeAttachments: func(serverId string) ([]novaVolumeAttachment, error) {
+			return []novaVolumeAttachment{{
+				Id:       "att-0",
+				VolumeId: "vol-123",
+				ServerId: serverId,
+			}}, nil
+		},
+		detachVolume: func(serverId, attachmentId string) error {
+			c.Check(serverId, gc.Equals, "inst-0")
+			c.Check(attachmentId, gc.Equals, "att-0")
+			return nil
+		},
+	}
+	source := &cinderVolumeSource{mockAdapter}
+	err := source.DetachVolumes([]storage.VolumeAttachmentParams{
+		volumeAttachmentParams("0", "123", "inst-0", "vol-123"),
+		volumeAttachmentParams("0", "456", "inst-0", "vol-456"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	mockAdapter.CheckCallNames(c, "ListVolumeAttachments", "DetachVolume", "ListVolumeAttachments")
+}
+
+func volumeAttachmentParams(machineId, volumeId, instanceId, providerVolumeId string) storage.VolumeAttachmentParams {
+	return storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   CinderProviderType,
+			Machine:    names.NewMachineTag(machineId),
+			InstanceId: instance.Id(instanceId),
+		},
+		Volume:   names.NewVolumeTag(volumeId),
+		VolumeId: providerVolumeId,
+	}
+}
+
+type mockAdapter struct {
+	gitjujutesting.Stub
+	createVolume          func(createCinderVolume) (*cinderVolume, error)
+	getVolume             func(string) (*cinderVolume, error)
+	deleteVolume          func(string) error
+	listVolumeAttachments func(string) ([]novaVolumeAttachment, error)
+	attachVolume          func(string, string, string) (*novaVolumeAttachment, error)
+	detachVolume          func(string, string) error
+}
+
+func (ma *mockAdapter) CreateVolume(args createCinderVolume) (*cinderVolume, error) {
+	ma.MethodCall(ma, "CreateVolume", args)
+	if ma.createVolume != nil {
+		return ma.createVolume(args)
+	}
+	return nil, errors.NotImplementedf("CreateVolume")
+}
+
+func (ma *mockAdapter) GetVolume(volumeId string) (*cinderVolume, error) {
+	ma.MethodCall(ma, "GetVolume", volumeId)
+	if ma.getVolume != nil {
+		return ma.getVolume(volumeId)
+	}
+	return nil, errors.NotFoundf("volume %q", volumeId)
+}
+
+func (ma *mockAdapter) DeleteVolume(volumeId string) error {
+	ma.MethodCall(ma, "DeleteVolume", volumeId)
+	if ma.deleteVolume != nil {
+		return ma.deleteVolume(volumeId)
+	}
+	return nil
+}
+
+func (ma *mockAdapter) ListVolumeAttachments(serverId string) ([]novaVolumeAttachment, error) {
+	ma.MethodCall(ma, "ListVolumeAttachments", serverId)
+	if ma.listVolumeAttachments != nil {
+		return ma.listVolumeAttachments(serverId)
+	}
+	return nil, nil
+}
+
+func (ma *mockAdapter) AttachVolume(serverId, volumeId, device string) (*novaVolumeAttachment, error) {
+	ma.MethodCall(ma, "AttachVolume", serverId, volumeId, device)
+	if ma.attachVolume != nil {
+		return ma.attachVolume(serverId, volumeId, device)
+	}
+	return nil, errors.NotImplementedf("AttachVolume")
+}
+
+func (ma *mockAdapter) DetachVolume(serverId, attachmentId string) error {
+	ma.MethodCall(ma, "DetachVolume", serverId, attachmentId)
+	if ma.detachVolume != nil {
+		return ma.detachVolume(serverId, attachmentId)
+	}
+	return nil
+}
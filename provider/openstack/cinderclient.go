@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/juju/errors"
+	"launchpad.net/goose/client"
+	gooseerrors "launchpad.net/goose/errors"
+	goosehttp "launchpad.net/goose/http"
+)
+
+// cinderServiceType is the keystone catalog type of the Cinder
+// block storage service.
+const cinderServiceType = "volume"
+
+// cinderVolume holds the details of a Cinder volume.
+type cinderVolume struct {
+	Id          string                   `json:"id"`
+	Size        int                      `json:"size"`
+	Status      string                   `json:"status"`
+	Attachments []cinderVolumeAttachment `json:"attachments"`
+}
+
+// cinderVolumeAttachment holds the details of the attachment of a
+// Cinder volume to a Nova server.
+type cinderVolumeAttachment struct {
+	Id       string `json:"id"`
+	ServerId string `json:"server_id"`
+	Device   string `json:"device"`
+}
+
+// novaVolumeAttachment holds the details of a volume attachment, as
+// reported by Nova.
+type novaVolumeAttachment struct {
+	Id       string `json:"id,omitempty"`
+	VolumeId string `json:"volumeId"`
+	ServerId string `json:"serverId,omitempty"`
+	Device   string `json:"device,omitempty"`
+}
+
+// createCinderVolume holds the parameters for creating a Cinder volume.
+type createCinderVolume struct {
+	// Size holds the size of the volume in GiB.
+	Size             int               `json:"size"`
+	Name             string            `json:"display_name,omitempty"`
+	VolumeType       string            `json:"volume_type,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// openstackStorage is the interface to the OpenStack services that
+// the Cinder volume source uses.
+type openstackStorage interface {
+	CreateVolume(args createCinderVolume) (*cinderVolume, error)
+	GetVolume(volumeId string) (*cinderVolume, error)
+	DeleteVolume(volumeId string) error
+	ListVolumeAttachments(serverId string) ([]novaVolumeAttachment, error)
+	AttachVolume(serverId, volumeId, device string) (*novaVolumeAttachment, error)
+	DetachVolume(serverId, attachmentId string) error
+}
+
+// cinderClient implements openstackStorage using the Cinder and Nova
+// REST APIs, through an authenticated goose client.
+type cinderClient struct {
+	client client.Client
+}
+
+var _ openstackStorage = (*cinderClient)(nil)
+
+// CreateVolume is part of the openstackStorage interface.
+func (c *cinderClient) CreateVolume(args createCinderVolume) (*cinderVolume, error) {
+	var req struct {
+		Volume createCinderVolume `json:"volume"`
+	}
+	req.Volume = args
+	var resp struct {
+		Volume cinderVolume `json:"volume"`
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       &req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK, http.StatusAccepted},
+	}
+	if err := c.client.SendRequest(client.POST, cinderServiceType, "volumes", &requestData); err != nil {
+		return nil, errors.Annotate(err, "creating volume")
+	}
+	return &resp.Volume, nil
+}
+
+// GetVolume is part of the openstackStorage interface.
+func (c *cinderClient) GetVolume(volumeId string) (*cinderVolume, error) {
+	var resp struct {
+		Volume cinderVolume `json:"volume"`
+	}
+	requestData := goosehttp.RequestData{
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	}
+	url := fmt.Sprintf("volumes/%s", volumeId)
+	if err := c.client.SendRequest(client.GET, cinderServiceType, url, &requestData); err != nil {
+		return nil, cinderError(err, "volume %q", volumeId)
+	}
+	return &resp.Volume, nil
+}
+
+// DeleteVolume is part of the openstackStorage interface.
+func (c *cinderClient) DeleteVolume(volumeId string) error {
+	requestData := goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusAccepted, http.StatusNoContent},
+	}
+	url := fmt.Sprintf("volumes/%s", volumeId)
+	if err := c.client.SendRequest(client.DELETE, cinderServiceType, url, &requestData); err != nil {
+		return cinderError(err, "volume %q", volumeId)
+	}
+	return nil
+}
+
+// ListVolumeAttachments is part of the openstackStorage interface.
+func (c *cinderClient) ListVolumeAttachments(serverId string) ([]novaVolumeAttachment, error) {
+	var resp struct {
+		VolumeAttachments []novaVolumeAttachment `json:"volumeAttachments"`
+	}
+	requestData := goosehttp.RequestData{
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	}
+	url := fmt.Sprintf("servers/%s/os-volume_attachments", serverId)
+	if err := c.client.SendRequest(client.GET, "compute", url, &requestData); err != nil {
+		return nil, cinderError(err, "server %q", serverId)
+	}
+	return resp.VolumeAttachments, nil
+}
+
+// AttachVolume is part of the openstackStorage interface. If device is
+// empty, Nova chooses the device name.
+func (c *cinderClient) AttachVolume(serverId, volumeId, device string) (*novaVolumeAttachment, error) {
+	var req struct {
+		VolumeAttachment novaVolumeAttachment `json:"volumeAttachment"`
+	}
+	req.VolumeAttachment = novaVolumeAttachment{
+		VolumeId: volumeId,
+		Device:   device,
+	}
+	var resp struct {
+		VolumeAttachment novaVolumeAttachment `json:"volumeAttachment"`
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       &req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	}
+	url := fmt.Sprintf("servers/%s/os-volume_attachments", serverId)
+	if err := c.client.SendRequest(client.POST, "compute", url, &requestData); err != nil {
+		return nil, errors.Annotatef(err, "attaching volume %q to server %q", volumeId, serverId)
+	}
+	return &resp.VolumeAttachment, nil
+}
+
+// DetachVolume is part of the openstackStorage interface.
+func (c *cinderClient) DetachVolume(serverId, attachmentId string) error {
+	requestData := goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusAccepted},
+	}
+	url := fmt.Sprintf("servers/%s/os-volume_attachments/%s", serverId, attachmentId)
+	if err := c.client.SendRequest(client.DELETE, "compute", url, &requestData); err != nil {
+		return cinderError(err, "attachment %q of server %q", attachmentId, serverId)
+	}
+	return nil
+}
+
+// cinderError returns an error satisfying errors.IsNotFound if the
+// given goose error means that the described entity was not found,
+// and otherwise annotates the error with the description.
+func cinderError(err error, format string, args ...interface{}) error {
+	if gooseerrors.IsNotFound(err) {
+		return errors.NewNotFound(err, fmt.Sprintf(format+" not found", args...))
+	}
+	return errors.Annotatef(err, format, args...)
+}
@@ -18,5 +18,9 @@ func init() {
 	environs.RegisterImageDataSourceFunc("keystone catalog", getKeystoneImageSource)
 	tools.RegisterToolsDataSourceFunc("keystone catalog", getKeystoneToolsSource)
 
-	registry.RegisterEnvironStorageProviders(providerType)
+	// Register the OpenStack specific providers.
+	registry.RegisterProvider(CinderProviderType, &cinderProvider{newOpenstackStorage})
+
+	// Inform the storage provider registry about the OpenStack providers.
+	registry.RegisterEnvironStorageProviders(providerType, CinderProviderType)
 }
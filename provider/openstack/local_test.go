@@ -1375,7 +1375,7 @@ func (t *localServerSuite) TestGetAvailabilityZones(c *gc.C) {
 	t.PatchValue(openstack.NovaListAvailabilityZones, func(c *nova.Client) ([]nova.AvailabilityZone, error) {
 		return append([]nova.AvailabilityZone{}, resultZones...), resultErr
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 
 	resultErr = fmt.Errorf("failed to get availability zones")
 	zones, err := env.AvailabilityZones()
@@ -1406,7 +1406,7 @@ func (t *localServerSuite) TestGetAvailabilityZonesCommon(c *gc.C) {
 	t.PatchValue(openstack.NovaListAvailabilityZones, func(c *nova.Client) ([]nova.AvailabilityZone, error) {
 		return append([]nova.AvailabilityZone{}, resultZones...), nil
 	})
-	env := t.Prepare(c).(common.ZonedEnviron)
+	env := t.Prepare(c).(environs.ZonedEnviron)
 	resultZones = make([]nova.AvailabilityZone, 2)
 	resultZones[0].Name = "az1"
 	resultZones[1].Name = "az2"
@@ -1428,7 +1428,7 @@ type mockAvailabilityZoneAllocations struct {
 }
 
 func (t *mockAvailabilityZoneAllocations) AvailabilityZoneAllocations(
-	e common.ZonedEnviron, group []instance.Id,
+	e environs.ZonedEnviron, group []instance.Id,
 ) ([]common.AvailabilityZoneInstances, error) {
 	t.group = group
 	return t.result, t.err
@@ -357,7 +357,7 @@ type environ struct {
 	keystoneToolsDataSource      simplestreams.DataSource
 
 	availabilityZonesMutex sync.Mutex
-	availabilityZones      []common.AvailabilityZone
+	availabilityZones      []environs.AvailabilityZone
 }
 
 var _ environs.Environ = (*environ)(nil)
@@ -573,6 +573,8 @@ func (e *environ) SupportedArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.VirtType,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -615,7 +617,7 @@ func (z *openstackAvailabilityZone) Available() bool {
 }
 
 // AvailabilityZones returns a slice of availability zones.
-func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+func (e *environ) AvailabilityZones() ([]environs.AvailabilityZone, error) {
 	e.availabilityZonesMutex.Lock()
 	defer e.availabilityZonesMutex.Unlock()
 	if e.availabilityZones == nil {
@@ -626,7 +628,7 @@ func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.availabilityZones = make([]common.AvailabilityZone, len(zones))
+		e.availabilityZones = make([]environs.AvailabilityZone, len(zones))
 		for i, z := range zones {
 			e.availabilityZones[i] = &openstackAvailabilityZone{z}
 		}
@@ -1307,21 +1309,40 @@ func (e *environ) machinesFilter() *nova.Filter {
 	return filter
 }
 
+// anySourceCIDR is the CIDR of the rules created for port ranges open
+// to all sources.
+const anySourceCIDR = "0.0.0.0/0"
+
 // portsToRuleInfo maps port ranges to nova rules
 func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
 	rules := make([]nova.RuleInfo, len(ports))
 	for i, portRange := range ports {
+		cidr := portRange.SourceCIDR
+		if cidr == "" {
+			cidr = anySourceCIDR
+		}
 		rules[i] = nova.RuleInfo{
 			ParentGroupId: groupId,
 			FromPort:      portRange.FromPort,
 			ToPort:        portRange.ToPort,
 			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+			Cidr:          cidr,
 		}
 	}
 	return rules
 }
 
+// ruleSourceCIDR returns the source CIDR of the port range allowed by
+// the supplied nova security group rule; it is empty for rules that
+// allow all sources.
+func ruleSourceCIDR(rule nova.SecurityGroupRule) string {
+	cidr := rule.IPRange["cidr"]
+	if cidr == anySourceCIDR {
+		return ""
+	}
+	return cidr
+}
+
 func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
@@ -1346,7 +1367,8 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 	}
 	return *rule.IPProtocol == portRange.Protocol &&
 		*rule.FromPort == portRange.FromPort &&
-		*rule.ToPort == portRange.ToPort
+		*rule.ToPort == portRange.ToPort &&
+		ruleSourceCIDR(rule) == portRange.SourceCIDR
 }
 
 func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange) error {
@@ -1381,9 +1403,10 @@ func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err
 	}
 	for _, p := range (*group).Rules {
 		portRanges = append(portRanges, network.PortRange{
-			Protocol: *p.IPProtocol,
-			FromPort: *p.FromPort,
-			ToPort:   *p.ToPort,
+			Protocol:   *p.IPProtocol,
+			FromPort:   *p.FromPort,
+			ToPort:     *p.ToPort,
+			SourceCIDR: ruleSourceCIDR(p),
 		})
 	}
 	network.SortPortRanges(portRanges)
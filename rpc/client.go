@@ -23,6 +23,7 @@ type Call struct {
 type RequestError struct {
 	Message string
 	Code    string
+	TraceId string
 }
 
 func (e *RequestError) Error() string {
@@ -111,6 +112,7 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		call.Error = &RequestError{
 			Message: hdr.Error,
 			Code:    hdr.ErrorCode,
+			TraceId: hdr.TraceId,
 		}
 		err = conn.readBody(nil, false)
 		if conn.notifier != nil {
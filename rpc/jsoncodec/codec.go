@@ -70,6 +70,7 @@ type inMsg struct {
 	Params    json.RawMessage
 	Error     string
 	ErrorCode string
+	TraceId   string
 	Response  json.RawMessage
 }
 
@@ -83,6 +84,7 @@ type outMsg struct {
 	Params    interface{} `json:",omitempty"`
 	Error     string      `json:",omitempty"`
 	ErrorCode string      `json:",omitempty"`
+	TraceId   string      `json:",omitempty"`
 	Response  interface{} `json:",omitempty"`
 }
 
@@ -131,6 +133,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.TraceId = c.msg.TraceId
 	return nil
 }
 
@@ -191,6 +194,7 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.TraceId = hdr.TraceId
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
@@ -47,11 +47,12 @@ var readTests = []struct {
 	},
 	expectBody: &value{X: "param"},
 }, {
-	msg: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
+	msg: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "TraceId": "A1:2"}`,
 	expectHdr: rpc.Header{
 		RequestId: 2,
 		Error:     "an error",
 		ErrorCode: "a code",
+		TraceId:   "A1:2",
 	},
 	expectBody: new(map[string]interface{}),
 }, {
@@ -235,8 +236,9 @@ var writeTests = []struct {
 		RequestId: 2,
 		Error:     "an error",
 		ErrorCode: "a code",
+		TraceId:   "A1:2",
 	},
-	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
+	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "TraceId": "A1:2"}`,
 }, {
 	hdr: &rpc.Header{
 		RequestId: 3,
@@ -1,28 +1,104 @@
 package jsoncodec
 
 import (
+	"bytes"
+	"compress/flate"
 	"encoding/json"
+	"io/ioutil"
 	"net"
 
 	"golang.org/x/net/websocket"
 )
 
+const (
+	// CompressionHeader holds the name of the HTTP header a client
+	// sets when opening a websocket connection to ask for the
+	// messages sent to it to be compressed.
+	CompressionHeader = "X-Juju-Rpc-Compression"
+
+	// CompressionDeflate is the only supported value of
+	// CompressionHeader.
+	CompressionDeflate = "deflate"
+)
+
+// compressionThreshold holds the size, in bytes, above which messages
+// sent on a compressed websocket connection are compressed. Smaller
+// messages gain little from compression, so are sent as they are.
+var compressionThreshold = 1024
+
 // NewWebsocket returns an rpc codec that uses the given websocket
-// connection to send and receive messages.
+// connection to send and receive messages. Messages are sent
+// uncompressed; messages received may be compressed or not.
 func NewWebsocket(conn *websocket.Conn) *Codec {
-	return New(wsJSONConn{conn})
+	return New(wsJSONConn{conn: conn, codec: jsonCodec})
+}
+
+// NewCompressedWebsocket is like NewWebsocket except that messages
+// larger than a small threshold are sent compressed with deflate, in
+// binary frames. It must only be used when the peer is known to accept
+// compressed messages (see CompressionHeader).
+//
+// The websocket package does not support the permessage-deflate
+// extension, so compression is implemented at the message level
+// instead.
+func NewCompressedWebsocket(conn *websocket.Conn) *Codec {
+	return New(wsJSONConn{conn: conn, codec: compressedJSONCodec})
+}
+
+var (
+	jsonCodec           = websocket.Codec{Marshal: marshalJSON, Unmarshal: unmarshalJSON}
+	compressedJSONCodec = websocket.Codec{Marshal: marshalCompressedJSON, Unmarshal: unmarshalJSON}
+)
+
+func marshalJSON(v interface{}) ([]byte, byte, error) {
+	data, err := json.Marshal(v)
+	return data, websocket.TextFrame, err
+}
+
+func marshalCompressedJSON(v interface{}) ([]byte, byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(data) <= compressionThreshold {
+		return data, websocket.TextFrame, err
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, 0, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), websocket.BinaryFrame, nil
+}
+
+// unmarshalJSON decodes a message sent in a text frame as JSON, and a
+// message sent in a binary frame as deflate-compressed JSON.
+func unmarshalJSON(data []byte, payloadType byte, v interface{}) error {
+	if payloadType == websocket.BinaryFrame {
+		r := flate.NewReader(bytes.NewReader(data))
+		defer r.Close()
+		var err error
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
 }
 
 type wsJSONConn struct {
-	conn *websocket.Conn
+	conn  *websocket.Conn
+	codec websocket.Codec
 }
 
 func (conn wsJSONConn) Send(msg interface{}) error {
-	return websocket.JSON.Send(conn.conn, msg)
+	return conn.codec.Send(conn.conn, msg)
 }
 
 func (conn wsJSONConn) Receive(msg interface{}) error {
-	return websocket.JSON.Receive(conn.conn, msg)
+	return conn.codec.Receive(conn.conn, msg)
 }
 
 func (conn wsJSONConn) Close() error {
@@ -0,0 +1,63 @@
+package jsoncodec_test
+
+import (
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/websocket"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc/jsoncodec"
+)
+
+type connSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&connSuite{})
+
+func (s *connSuite) TestMarshalJSON(c *gc.C) {
+	msg := value{X: strings.Repeat("x", 4096)}
+	data, payloadType, err := jsoncodec.MarshalJSON(msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloadType, gc.Equals, byte(websocket.TextFrame))
+	c.Assert(string(data), gc.Equals, `{"X":"`+msg.X+`"}`)
+}
+
+func (s *connSuite) TestMarshalCompressedJSONSmallMessage(c *gc.C) {
+	data, payloadType, err := jsoncodec.MarshalCompressedJSON(value{X: "small"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloadType, gc.Equals, byte(websocket.TextFrame))
+	c.Assert(string(data), gc.Equals, `{"X":"small"}`)
+}
+
+func (s *connSuite) TestMarshalCompressedJSONLargeMessage(c *gc.C) {
+	msg := value{X: strings.Repeat("x", 4096)}
+	data, payloadType, err := jsoncodec.MarshalCompressedJSON(msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloadType, gc.Equals, byte(websocket.BinaryFrame))
+	c.Assert(len(data) < len(msg.X), jc.IsTrue)
+
+	var got value
+	err = jsoncodec.UnmarshalJSON(data, payloadType, &got)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, msg)
+}
+
+func (s *connSuite) TestCompressionThreshold(c *gc.C) {
+	s.PatchValue(jsoncodec.CompressionThreshold, 0)
+	_, payloadType, err := jsoncodec.MarshalCompressedJSON(value{X: "small"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(payloadType, gc.Equals, byte(websocket.BinaryFrame))
+}
+
+func (s *connSuite) TestUnmarshalJSON(c *gc.C) {
+	var got value
+	err := jsoncodec.UnmarshalJSON([]byte(`{"X":"plain"}`), websocket.TextFrame, &got)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, gc.Equals, value{X: "plain"})
+
+	err = jsoncodec.UnmarshalJSON([]byte(`not deflate`), websocket.BinaryFrame, &got)
+	c.Assert(err, gc.NotNil)
+}
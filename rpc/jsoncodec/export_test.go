@@ -0,0 +1,8 @@
+package jsoncodec
+
+var (
+	CompressionThreshold  = &compressionThreshold
+	MarshalJSON           = marshalJSON
+	MarshalCompressedJSON = marshalCompressedJSON
+	UnmarshalJSON         = unmarshalJSON
+)
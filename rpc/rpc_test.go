@@ -773,6 +773,35 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(err.(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+func (*rpcSuite) TestTraceId(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{&codedError{"message", "code"}},
+	}
+	srvPipe, clientPipe := net.Pipe()
+	srv := rpc.NewConn(NewJSONCodec(srvPipe, roleServer), nil)
+	srv.SetTracePrefix("A1")
+	srv.Serve(root, nil)
+	srv.Start()
+	defer srv.Close()
+	client := rpc.NewConn(NewJSONCodec(clientPipe, roleClient), nil)
+	client.Start()
+	defer client.Close()
+
+	err := client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, gc.DeepEquals, &rpc.RequestError{
+		Message: "message",
+		Code:    "code",
+		TraceId: "A1:1",
+	})
+
+	// Only error replies carry the trace id.
+	root.errorInst.err = nil
+	err = client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(rpc.TraceId("", 1), gc.Equals, "")
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
@@ -62,6 +62,12 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// TraceId holds an identifier for the request that is unique
+	// within the server, so that a failed request can be correlated
+	// with the server's logs. It is only set in error replies from
+	// servers that trace their requests.
+	TraceId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -124,6 +130,11 @@ type Conn struct {
 	// transformErrors is used to transform returned errors.
 	transformErrors func(error) error
 
+	// tracePrefix holds the prefix of the trace ids of the
+	// requests served by the connection. If it is empty, requests
+	// are not traced.
+	tracePrefix string
+
 	// reqId holds the latest client request id.
 	reqId uint64
 
@@ -198,6 +209,27 @@ func NewConn(codec Codec, notifier RequestNotifier) *Conn {
 	}
 }
 
+// SetTracePrefix causes every server request on the connection to be
+// given a trace id made from the given prefix and the request's id. The
+// trace id is returned to the client in error replies, so that it can be
+// correlated with the server's logs; the prefix should therefore identify
+// the connection uniquely within the server. SetTracePrefix must be
+// called before Start.
+func (conn *Conn) SetTracePrefix(prefix string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.tracePrefix = prefix
+}
+
+// TraceId returns the trace id of the request with the given id served
+// on a connection whose trace prefix is prefix.
+func TraceId(prefix string, reqId uint64) string {
+	if prefix == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", prefix, reqId)
+}
+
 // Start starts the RPC connection running.  It must be called at least
 // once for any RPC connection (client or server side) It has no effect
 // if it has already been called.  By default, a connection serves no
@@ -493,6 +525,7 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.T
 	defer conn.sending.Unlock()
 	hdr := &Header{
 		RequestId: reqHdr.RequestId,
+		TraceId:   TraceId(conn.tracePrefix, reqHdr.RequestId),
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
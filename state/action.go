@@ -99,6 +99,14 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// OutputCount is the number of chunks of output recorded while
+	// the action was running.
+	OutputCount int `bson:"outputcount"`
+
+	// Queue holds the id of the ActionQueue the action was started
+	// from, if any.
+	Queue string `bson:"queue,omitempty"`
 }
 
 // Action represents an instruction to do some "action" and is expected
@@ -236,6 +244,13 @@ func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]inter
 	if err != nil {
 		return nil, err
 	}
+	if a.doc.Queue != "" {
+		// The action is finished whether or not the next one can be
+		// started, so a failure to do so is only logged.
+		if err := a.st.advanceActionQueue(a.doc.Queue); err != nil {
+			actionLogger.Errorf("cannot advance action queue %q: %v", a.doc.Queue, err)
+		}
+	}
 	return a.st.Action(a.Id())
 }
 
@@ -330,6 +345,12 @@ func (st *State) FindActionTagsByPrefix(prefix string) []names.ActionTag {
 
 // EnqueueAction
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	return st.enqueueAction(receiver, actionName, payload, "")
+}
+
+// enqueueAction enqueues an Action, recording the ActionQueue it is
+// started from, if any.
+func (st *State) enqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}, queue string) (*Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
@@ -343,6 +364,7 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	doc.Queue = queue
 
 	ops := []txn.Op{{
 		C:      receiverCollectionName,
@@ -463,6 +463,62 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestAppendOutput(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.AppendOutput(state.ActionOutputStdout, "too early")
+	c.Assert(err, gc.ErrorMatches, `cannot append output of action ".*": action is not running`)
+
+	a, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.AppendOutput(state.ActionOutputStdout, "one\n")
+	c.Assert(err, jc.ErrorIsNil)
+	// The stale action is refreshed when the transaction fails.
+	err = a.AppendOutput(state.ActionOutputStderr, "two\n")
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.AppendOutput("stdin", "three\n")
+	c.Assert(err, gc.ErrorMatches, `action output stream "stdin" not valid`)
+
+	output, err := a.Output(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 2)
+	c.Check(output[0].Stream, gc.Equals, state.ActionOutputStdout)
+	c.Check(output[0].Data, gc.Equals, "one\n")
+	c.Check(output[1].Stream, gc.Equals, state.ActionOutputStderr)
+	c.Check(output[1].Data, gc.Equals, "two\n")
+
+	output, err = a.Output(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 1)
+	c.Check(output[0].Data, gc.Equals, "two\n")
+
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.AppendOutput(state.ActionOutputStdout, "too late")
+	c.Assert(err, gc.ErrorMatches, `cannot append output of action ".*": action is not running`)
+}
+
+func (s *ActionSuite) TestWatchAction(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	w := a.Watch()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	a, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = a.AppendOutput(state.ActionOutputStdout, "output")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const (
+	// ActionOutputStdout identifies output written by an action to
+	// its standard output.
+	ActionOutputStdout = "stdout"
+
+	// ActionOutputStderr identifies output written by an action to
+	// its standard error.
+	ActionOutputStderr = "stderr"
+)
+
+// ActionOutput is a chunk of the output of an action.
+type ActionOutput struct {
+	Stream    string
+	Data      string
+	Timestamp time.Time
+}
+
+// actionOutputDoc holds a chunk of the output of an action. The chunks
+// of an action are numbered from zero in the order they were written.
+type actionOutputDoc struct {
+	DocId     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	ActionID  string    `bson:"actionid"`
+	Seq       int       `bson:"seq"`
+	Stream    string    `bson:"stream"`
+	Data      string    `bson:"data"`
+	Timestamp time.Time `bson:"timestamp"`
+}
+
+// AppendOutput records a chunk of the output of the running action.
+// The action's document is updated along with it, so that the output
+// can be followed with Watch.
+func (a *Action) AppendOutput(stream, data string) error {
+	switch stream {
+	case ActionOutputStdout, ActionOutputStderr:
+	default:
+		return errors.NotValidf("action output stream %q", stream)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		action := a
+		if attempt > 0 {
+			var err error
+			if action, err = a.st.Action(a.Id()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if action.doc.Status != ActionRunning {
+			return nil, errors.Errorf("action is not running")
+		}
+		seq := action.doc.OutputCount
+		docId := a.st.docID(actionOutputId(a.Id(), seq))
+		return []txn.Op{{
+			C:  actionsC,
+			Id: a.doc.DocId,
+			Assert: bson.D{
+				{"status", ActionRunning},
+				{"outputcount", seq},
+			},
+			Update: bson.D{{"$set", bson.D{{"outputcount", seq + 1}}}},
+		}, {
+			C:      actionOutputC,
+			Id:     docId,
+			Assert: txn.DocMissing,
+			Insert: &actionOutputDoc{
+				DocId:     docId,
+				EnvUUID:   a.st.EnvironUUID(),
+				ActionID:  a.Id(),
+				Seq:       seq,
+				Stream:    stream,
+				Data:      data,
+				Timestamp: time.Now().UTC(),
+			},
+		}}, nil
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot append output of action %q", a.Id())
+	}
+	return nil
+}
+
+// Output returns the chunks of the action's output from the start'th,
+// counting from zero, in the order they were written.
+func (a *Action) Output(start int) ([]ActionOutput, error) {
+	outputs, closer := a.st.getCollection(actionOutputC)
+	defer closer()
+
+	var docs []actionOutputDoc
+	query := outputs.Find(bson.D{
+		{"actionid", a.Id()},
+		{"seq", bson.D{{"$gte", start}}},
+	})
+	if err := query.Sort("seq").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get output of action %q", a.Id())
+	}
+	output := make([]ActionOutput, len(docs))
+	for i, doc := range docs {
+		output[i] = ActionOutput{
+			Stream:    doc.Stream,
+			Data:      doc.Data,
+			Timestamp: doc.Timestamp,
+		}
+	}
+	return output, nil
+}
+
+// Watch returns a watcher that notifies of changes to the action,
+// including the recording of its output and its completion.
+func (a *Action) Watch() NotifyWatcher {
+	return newEntityWatcher(a.st, actionsC, a.doc.DocId)
+}
+
+func actionOutputId(actionId string, seq int) string {
+	return fmt.Sprintf("%s#%d", actionId, seq)
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// actionQueueDoc records the units of a service on which an Action is
+// yet to be enqueued. Whenever an Action started from the queue
+// finishes, the Action is enqueued on the next waiting unit, so that no
+// more than the queue's limit of them run at once.
+type actionQueueDoc struct {
+	DocId      string                 `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	Service    string                 `bson:"service"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+
+	// Waiting holds the names of the units on which the Action is yet
+	// to be enqueued, in the order in which it will be.
+	Waiting []string `bson:"waiting"`
+}
+
+// EnqueueActions enqueues the named Action on every alive unit of the
+// service. If limit is positive, the Action is enqueued on no more than
+// limit units at once, and on each of the remaining units in turn as
+// those Actions finish. It returns the Actions enqueued immediately and
+// the names of the units that are waiting their turn.
+func (s *Service) EnqueueActions(name string, payload map[string]interface{}, limit int) (_ []*Action, waiting []string, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot enqueue action %q on service %q", name, s.doc.Name)
+	if len(name) == 0 {
+		return nil, nil, errors.New("no action name given")
+	}
+	all, err := s.AllUnits()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var units []*Unit
+	for _, unit := range all {
+		if unit.Life() == Alive {
+			units = append(units, unit)
+		}
+	}
+	if len(units) == 0 {
+		return nil, nil, errors.New("service has no units")
+	}
+	sort.Sort(unitsByNumber(units))
+	// The Action is checked against the first unit before anything is
+	// enqueued, so that a mistake does not leave a queue behind.
+	if _, err := units[0].actionPayload(name, payload); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	queue := ""
+	start := units
+	if limit > 0 && limit < len(units) {
+		id, err := NewUUID()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		queue = id.String()
+		start = units[:limit]
+		for _, unit := range units[limit:] {
+			waiting = append(waiting, unit.Name())
+		}
+		doc := &actionQueueDoc{
+			DocId:      s.st.docID(queue),
+			EnvUUID:    s.st.EnvironUUID(),
+			Service:    s.doc.Name,
+			Name:       name,
+			Parameters: payload,
+			Waiting:    waiting,
+		}
+		ops := []txn.Op{{
+			C:      actionQueuesC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}
+		if err := s.st.runTransaction(ops); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	var actions []*Action
+	for _, unit := range start {
+		action, err := unit.addQueuedAction(name, payload, queue)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "unit %q", unit.Name())
+		}
+		actions = append(actions, action)
+	}
+	return actions, waiting, nil
+}
+
+// advanceActionQueue enqueues the Action of the queue with the given id
+// on the next waiting unit that is still alive, removing the queue once
+// no units are left waiting.
+func (st *State) advanceActionQueue(id string) error {
+	queues, closer := st.getCollection(actionQueuesC)
+	defer closer()
+
+	for {
+		var unitName string
+		var doc actionQueueDoc
+		buildTxn := func(attempt int) ([]txn.Op, error) {
+			err := queues.FindId(id).One(&doc)
+			if err == mgo.ErrNotFound {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			op := txn.Op{
+				C:      actionQueuesC,
+				Id:     doc.DocId,
+				Assert: bson.D{{"waiting", doc.Waiting}},
+			}
+			unitName = ""
+			if len(doc.Waiting) > 0 {
+				unitName = doc.Waiting[0]
+			}
+			if len(doc.Waiting) > 1 {
+				op.Update = bson.D{{"$set", bson.D{{"waiting", doc.Waiting[1:]}}}}
+			} else {
+				op.Remove = true
+			}
+			return []txn.Op{op}, nil
+		}
+		if err := st.run(buildTxn); err != nil {
+			return errors.Trace(err)
+		}
+		if unitName == "" {
+			return nil
+		}
+		// Units that have gone away since the Action was queued up
+		// are skipped.
+		unit, err := st.Unit(unitName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if unit.Life() != Alive {
+			continue
+		}
+		_, err = unit.addQueuedAction(doc.Name, doc.Parameters, id)
+		if err == ErrDead {
+			continue
+		}
+		return errors.Annotatef(err, "cannot enqueue action %q on unit %q", doc.Name, unitName)
+	}
+}
+
+type unitsByNumber []*Unit
+
+func (u unitsByNumber) Len() int      { return len(u) }
+func (u unitsByNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+// unitNumber returns the number of the unit with the given name; unit
+// names are validated on creation, so it cannot fail.
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+func (s *ActionSuite) TestEnqueueActionsWithoutLimit(c *gc.C) {
+	actions, waiting, err := s.service.EnqueueActions("snapshot", nil, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(waiting, gc.HasLen, 0)
+	c.Assert(actions, gc.HasLen, 3)
+	for i, unit := range []*state.Unit{s.unit, s.unit2, s.charmlessUnit} {
+		c.Check(actions[i].Receiver(), gc.Equals, unit.Name())
+	}
+}
+
+func (s *ActionSuite) TestEnqueueActionsWithLimit(c *gc.C) {
+	actions, waiting, err := s.service.EnqueueActions("snapshot", nil, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Check(actions[0].Receiver(), gc.Equals, s.unit.Name())
+	c.Check(waiting, jc.DeepEquals, []string{s.unit2.Name(), s.charmlessUnit.Name()})
+	s.assertPending(c, s.unit2, 0)
+
+	// The next unit has its turn once the running action finishes.
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	pending := s.assertPending(c, s.unit2, 1)
+	s.assertPending(c, s.charmlessUnit, 0)
+
+	// Units that go away are skipped.
+	err = s.charmlessUnit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = pending[0].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, s.charmlessUnit, 0)
+}
+
+func (s *ActionSuite) TestEnqueueActionsCancelAdvancesQueue(c *gc.C) {
+	actions, _, err := s.service.EnqueueActions("snapshot", nil, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+
+	_, err = s.unit2.CancelAction(actions[1])
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPending(c, s.charmlessUnit, 1)
+}
+
+func (s *ActionSuite) TestEnqueueActionsErrors(c *gc.C) {
+	_, _, err := s.service.EnqueueActions("", nil, 1)
+	c.Check(err, gc.ErrorMatches, `cannot enqueue action "" on service "dummy": no action name given`)
+
+	_, _, err = s.service.EnqueueActions("nonsense", nil, 1)
+	c.Check(err, gc.ErrorMatches, `cannot enqueue action "nonsense" on service "dummy": action "nonsense" not defined on unit "dummy/0"`)
+
+	svc := s.AddTestingService(c, "unitless", s.charm)
+	_, _, err = svc.EnqueueActions("snapshot", nil, 1)
+	c.Check(err, gc.ErrorMatches, `cannot enqueue action "snapshot" on service "unitless": service has no units`)
+}
+
+func (s *ActionSuite) assertPending(c *gc.C, unit *state.Unit, count int) []*state.Action {
+	pending, err := unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, count)
+	return pending
+}
@@ -0,0 +1,279 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/utils/schedule"
+)
+
+// actionScheduleDoc records an Action that is to be enqueued at a later
+// time. An Action scheduled with a cron expression is enqueued each time
+// the expression falls due; otherwise it is enqueued just once.
+type actionScheduleDoc struct {
+	DocId   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+
+	// Receiver is the tag of the ActionReceiver on which the Action
+	// is enqueued.
+	Receiver string `bson:"receiver"`
+
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters"`
+
+	// Cron holds the schedule of a recurring Action.
+	Cron string `bson:"cron,omitempty"`
+
+	// NextRun is the time at which the Action is next enqueued.
+	NextRun time.Time `bson:"nextrun"`
+}
+
+// ActionSchedule represents an Action that is to be enqueued at a later
+// time, once or repeatedly.
+type ActionSchedule struct {
+	st  *State
+	doc actionScheduleDoc
+}
+
+// Id returns the id of the schedule.
+func (s *ActionSchedule) Id() string {
+	return s.st.localID(s.doc.DocId)
+}
+
+// Receiver returns the tag of the entity on which the Action is
+// enqueued.
+func (s *ActionSchedule) Receiver() string {
+	return s.doc.Receiver
+}
+
+// Name returns the name of the Action.
+func (s *ActionSchedule) Name() string {
+	return s.doc.Name
+}
+
+// Parameters returns the parameters with which the Action is enqueued.
+func (s *ActionSchedule) Parameters() map[string]interface{} {
+	return s.doc.Parameters
+}
+
+// Cron returns the schedule on which the Action recurs, or "" if it is
+// enqueued just once.
+func (s *ActionSchedule) Cron() string {
+	return s.doc.Cron
+}
+
+// NextRun returns the time at which the Action is next enqueued.
+func (s *ActionSchedule) NextRun() time.Time {
+	return s.doc.NextRun.UTC()
+}
+
+// AddActionSchedule arranges for the named Action to be enqueued on the
+// receiver at a later time. If cron is empty the Action is enqueued
+// once, at the given time; otherwise at is ignored, and the Action is
+// enqueued each time the cron expression falls due.
+func (st *State) AddActionSchedule(receiver names.Tag, name string, payload map[string]interface{}, at time.Time, cron string) (_ *ActionSchedule, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot schedule action %q on %s", name, names.ReadableString(receiver))
+	if len(name) == 0 {
+		return nil, errors.New("no action name given")
+	}
+	// Times are held to the second, as they are in the database.
+	nextRun := at.UTC().Truncate(time.Second)
+	if cron != "" {
+		sched, err := schedule.Parse(cron)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		nextRun = sched.Next(time.Now().UTC())
+		if nextRun.IsZero() {
+			return nil, errors.Errorf("schedule %q never falls due", cron)
+		}
+	} else if at.IsZero() {
+		return nil, errors.New("no time or schedule given")
+	}
+	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entity, err := st.FindEntity(receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := entity.(ActionReceiver); !ok {
+		return nil, errors.NotValidf("action receiver %s", names.ReadableString(receiver))
+	}
+	// The parameters are checked now so that mistakes are reported to
+	// the user, but defaults are only filled in when the Action is
+	// enqueued, in case the charm has been upgraded in the meantime.
+	if unit, ok := entity.(*Unit); ok {
+		specs, err := unit.ActionSpecs()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec, ok := specs[name]
+		if !ok {
+			return nil, errors.Errorf("action %q not defined on unit %q", name, unit.Name())
+		}
+		if err := spec.ValidateParams(payload); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	id, err := NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := actionScheduleDoc{
+		DocId:      st.docID(id.String()),
+		EnvUUID:    st.EnvironUUID(),
+		Receiver:   receiver.String(),
+		Name:       name,
+		Parameters: payload,
+		Cron:       cron,
+		NextRun:    nextRun,
+	}
+	ops := []txn.Op{{
+		C:      receiverCollectionName,
+		Id:     receiverId,
+		Assert: notDeadDoc,
+	}, {
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, ErrDead
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// ActionSchedule returns the schedule with the given id.
+func (st *State) ActionSchedule(id string) (*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action schedule %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action schedule %q", id)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// ActionSchedules returns all the scheduled Actions in the environment,
+// in the order in which they are next enqueued.
+func (st *State) ActionSchedules() ([]*ActionSchedule, error) {
+	schedules, closer := st.getCollection(actionSchedulesC)
+	defer closer()
+
+	var docs []actionScheduleDoc
+	if err := schedules.Find(nil).Sort("nextrun").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get action schedules")
+	}
+	result := make([]*ActionSchedule, len(docs))
+	for i, doc := range docs {
+		result[i] = &ActionSchedule{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// WatchActionSchedules returns a watcher that notifies of the addition,
+// removal and dispatch of scheduled Actions.
+func (st *State) WatchActionSchedules() NotifyWatcher {
+	return newCollectionWatcher(st, actionSchedulesC)
+}
+
+// Remove cancels the schedule. It is not an error to remove a schedule
+// that has already been removed.
+func (s *ActionSchedule) Remove() error {
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Remove: true,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove action schedule %q", s.Id())
+	}
+	return nil
+}
+
+// Dispatch enqueues the scheduled Action, and then moves the schedule
+// on to the next time its cron expression falls due after now, or
+// removes it if the Action is not recurring. The schedule is moved on
+// even if the Action cannot be enqueued, so a failed run is not
+// retried until the schedule next falls due; a schedule whose receiver
+// no longer exists is removed.
+func (s *ActionSchedule) Dispatch(now time.Time) (*Action, error) {
+	action, err := s.enqueue()
+	if errors.IsNotFound(err) || err == ErrDead {
+		if err := s.Remove(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.Annotatef(err, "cannot dispatch action schedule %q", s.Id())
+	}
+	if err := s.advance(now); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot dispatch action schedule %q", s.Id())
+	}
+	return action, nil
+}
+
+// advance moves the schedule on to the next time it falls due after
+// now, removing it if it never does.
+func (s *ActionSchedule) advance(now time.Time) error {
+	var nextRun time.Time
+	if s.doc.Cron != "" {
+		sched, err := schedule.Parse(s.doc.Cron)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		nextRun = sched.Next(now.UTC())
+	}
+	op := txn.Op{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"nextrun", s.doc.NextRun}},
+	}
+	if nextRun.IsZero() {
+		op.Remove = true
+	} else {
+		op.Update = bson.D{{"$set", bson.D{{"nextrun", nextRun}}}}
+	}
+	// The schedule may have been removed while the Action was being
+	// enqueued, in which case there is nothing more to do.
+	if err := s.st.runTransaction([]txn.Op{op}); err != nil && err != txn.ErrAborted {
+		return errors.Annotatef(err, "cannot update action schedule %q", s.Id())
+	}
+	s.doc.NextRun = nextRun
+	return nil
+}
+
+func (s *ActionSchedule) enqueue() (*Action, error) {
+	tag, err := names.ParseTag(s.doc.Receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entity, err := s.st.FindEntity(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	receiver, ok := entity.(ActionReceiver)
+	if !ok {
+		return nil, errors.NotValidf("action receiver %s", names.ReadableString(tag))
+	}
+	return receiver.AddAction(s.doc.Name, s.doc.Parameters)
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+func (s *ActionSuite) TestAddActionScheduleOnce(c *gc.C) {
+	at := time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC)
+	sched, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", map[string]interface{}{"outfile": "out.bz2"}, at, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sched.Receiver(), gc.Equals, s.unit.Tag().String())
+	c.Check(sched.Name(), gc.Equals, "snapshot")
+	c.Check(sched.Cron(), gc.Equals, "")
+	c.Check(sched.NextRun(), gc.DeepEquals, at)
+
+	action, err := sched.Dispatch(at)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(action.Name(), gc.Equals, "snapshot")
+	c.Check(action.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.bz2"})
+	pending, err := s.unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pending, gc.HasLen, 1)
+
+	// A schedule that is not recurring is removed once dispatched.
+	_, err = s.State.ActionSchedule(sched.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestAddActionScheduleRecurring(c *gc.C) {
+	sched, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, time.Time{}, "@daily")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sched.Cron(), gc.Equals, "@daily")
+	c.Check(sched.NextRun().After(time.Now()), jc.IsTrue)
+
+	now := time.Date(2015, time.June, 1, 12, 0, 0, 0, time.UTC)
+	action, err := sched.Dispatch(now)
+	c.Assert(err, jc.ErrorIsNil)
+	// Defaults are filled in when the Action is enqueued.
+	c.Check(action.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+
+	sched, err = s.State.ActionSchedule(sched.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sched.NextRun(), gc.DeepEquals, time.Date(2015, time.June, 2, 0, 0, 0, 0, time.UTC))
+
+	schedules, err := s.State.ActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules, gc.HasLen, 1)
+	c.Check(schedules[0].Id(), gc.Equals, sched.Id())
+
+	err = sched.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	schedules, err = s.State.ActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schedules, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestAddActionScheduleErrors(c *gc.C) {
+	at := time.Now()
+	for i, test := range []struct {
+		name   string
+		params map[string]interface{}
+		at     time.Time
+		cron   string
+		err    string
+	}{{
+		name: "",
+		at:   at,
+		err:  `cannot schedule action "" on unit "dummy/0": no action name given`,
+	}, {
+		name: "snapshot",
+		err:  `cannot schedule action "snapshot" on unit "dummy/0": no time or schedule given`,
+	}, {
+		name: "snapshot",
+		cron: "* *",
+		err:  `cannot schedule action "snapshot" on unit "dummy/0": invalid schedule "\* \*": expected 5 fields, got 2`,
+	}, {
+		name: "snapshot",
+		cron: "0 0 31 2 *",
+		err:  `cannot schedule action "snapshot" on unit "dummy/0": schedule "0 0 31 2 \*" never falls due`,
+	}, {
+		name: "nonsense",
+		at:   at,
+		err:  `cannot schedule action "nonsense" on unit "dummy/0": action "nonsense" not defined on unit "dummy/0"`,
+	}, {
+		name:   "snapshot",
+		params: map[string]interface{}{"outfile": 5},
+		at:     at,
+		err:    `cannot schedule action "snapshot" on unit "dummy/0": validation failed: .*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddActionSchedule(s.unit.Tag(), test.name, test.params, test.at, test.cron)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ActionSuite) TestDispatchRemovesScheduleOfRemovedReceiver(c *gc.C) {
+	sched, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, time.Time{}, "@daily")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sched.Dispatch(time.Now())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ActionSchedule(sched.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestWatchActionSchedules(c *gc.C) {
+	w := s.State.WatchActionSchedules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	sched, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, time.Time{}, "@hourly")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = sched.Dispatch(time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = sched.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AgentHealth holds the health metrics most recently reported by a
+// machine or unit agent.
+type AgentHealth struct {
+	// HookQueueDepth holds the number of hooks waiting to be run by
+	// the agent.
+	HookQueueDepth int
+
+	// LastAPICall holds the time at which the agent last completed
+	// an API call successfully.
+	LastAPICall time.Time
+
+	// DataDirAvailable holds the space available in the agent's data
+	// directory, in MiB.
+	DataDirAvailable uint64
+
+	// Reported holds the time at which the health was recorded. It is
+	// set by state, and ignored when setting agent health.
+	Reported time.Time
+}
+
+// AgentHealthSetter is implemented by entities whose agents can report
+// their health.
+type AgentHealthSetter interface {
+	SetAgentHealth(health AgentHealth) error
+}
+
+// AgentHealthGetter is implemented by entities whose agents can report
+// their health.
+type AgentHealthGetter interface {
+	AgentHealth() (AgentHealth, error)
+}
+
+var (
+	_ AgentHealthSetter = (*Machine)(nil)
+	_ AgentHealthSetter = (*Unit)(nil)
+	_ AgentHealthGetter = (*Machine)(nil)
+	_ AgentHealthGetter = (*Unit)(nil)
+)
+
+// agentHealthDoc records the health reported by an agent.
+type agentHealthDoc struct {
+	DocID            string    `bson:"_id"`
+	EnvUUID          string    `bson:"env-uuid"`
+	HookQueueDepth   int       `bson:"hookqueuedepth"`
+	LastAPICall      time.Time `bson:"lastapicall"`
+	DataDirAvailable uint64    `bson:"datadiravailable"`
+	Reported         time.Time `bson:"reported"`
+}
+
+// SetAgentHealth records the health reported by the machine's agent.
+func (m *Machine) SetAgentHealth(health AgentHealth) error {
+	err := setAgentHealth(m.st, m.globalKey(), machinesC, m.doc.DocID, health)
+	return errors.Annotatef(err, "cannot set agent health for machine %s", m.Id())
+}
+
+// AgentHealth returns the health most recently reported by the machine's
+// agent. It returns an error satisfying errors.IsNotFound if none has
+// been reported.
+func (m *Machine) AgentHealth() (AgentHealth, error) {
+	return getAgentHealth(m.st, m.globalKey())
+}
+
+// SetAgentHealth records the health reported by the unit's agent.
+func (u *Unit) SetAgentHealth(health AgentHealth) error {
+	err := setAgentHealth(u.st, u.globalAgentKey(), unitsC, u.doc.DocID, health)
+	return errors.Annotatef(err, "cannot set agent health for unit %q", u.Name())
+}
+
+// AgentHealth returns the health most recently reported by the unit's
+// agent. It returns an error satisfying errors.IsNotFound if none has
+// been reported.
+func (u *Unit) AgentHealth() (AgentHealth, error) {
+	return getAgentHealth(u.st, u.globalAgentKey())
+}
+
+// setAgentHealth records the supplied health against globalKey, as long
+// as the entity document identified by collection and docID is not dead.
+func setAgentHealth(st *State, globalKey, collection, docID string, health AgentHealth) error {
+	doc := agentHealthDoc{
+		DocID:            st.docID(globalKey),
+		EnvUUID:          st.EnvironUUID(),
+		HookQueueDepth:   health.HookQueueDepth,
+		LastAPICall:      health.LastAPICall.UTC(),
+		DataDirAvailable: health.DataDirAvailable,
+		Reported:         time.Now().UTC(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if notDead, err := isNotDead(st, collection, docID); err != nil {
+				return nil, errors.Trace(err)
+			} else if !notDead {
+				return nil, ErrDead
+			}
+		}
+		ops := []txn.Op{{
+			C:      collection,
+			Id:     docID,
+			Assert: notDeadDoc,
+		}}
+		_, err := getAgentHealth(st, globalKey)
+		switch {
+		case errors.IsNotFound(err):
+			ops = append(ops, txn.Op{
+				C:      agentHealthC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		default:
+			ops = append(ops, txn.Op{
+				C:      agentHealthC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"hookqueuedepth", doc.HookQueueDepth},
+					{"lastapicall", doc.LastAPICall},
+					{"datadiravailable", doc.DataDirAvailable},
+					{"reported", doc.Reported},
+				}}},
+			})
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+func getAgentHealth(st *State, globalKey string) (AgentHealth, error) {
+	agentHealth, closer := st.getCollection(agentHealthC)
+	defer closer()
+
+	var doc agentHealthDoc
+	err := agentHealth.FindId(globalKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AgentHealth{}, errors.NotFoundf("agent health for %q", globalKey)
+	} else if err != nil {
+		return AgentHealth{}, errors.Annotatef(err, "cannot get agent health for %q", globalKey)
+	}
+	return AgentHealth{
+		HookQueueDepth:   doc.HookQueueDepth,
+		LastAPICall:      doc.LastAPICall,
+		DataDirAvailable: doc.DataDirAvailable,
+		Reported:         doc.Reported,
+	}, nil
+}
+
+// removeAgentHealthOp returns the operation needed to remove the agent
+// health document associated with the given globalKey.
+func removeAgentHealthOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      agentHealthC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
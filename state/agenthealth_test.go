@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type AgentHealthSuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&AgentHealthSuite{})
+
+func (s *AgentHealthSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	f := factory.NewFactory(s.State)
+	s.machine = f.MakeMachine(c, nil)
+	s.unit = f.MakeUnit(c, nil)
+}
+
+func (s *AgentHealthSuite) TestAgentHealthNotFound(c *gc.C) {
+	_, err := s.machine.AgentHealth()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.unit.AgentHealth()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *AgentHealthSuite) assertSetAgentHealth(c *gc.C, entity interface {
+	state.AgentHealthSetter
+	state.AgentHealthGetter
+}) {
+	lastCall := time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, depth := range []int{3, 0} {
+		c.Logf("report %d", i)
+		before := time.Now().Add(-time.Second)
+		err := entity.SetAgentHealth(state.AgentHealth{
+			HookQueueDepth:   depth,
+			LastAPICall:      lastCall,
+			DataDirAvailable: 1024,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+
+		health, err := entity.AgentHealth()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(health.HookQueueDepth, gc.Equals, depth)
+		c.Check(health.LastAPICall.Equal(lastCall), jc.IsTrue)
+		c.Check(health.DataDirAvailable, gc.Equals, uint64(1024))
+		c.Check(health.Reported.Before(before), jc.IsFalse)
+	}
+}
+
+func (s *AgentHealthSuite) TestSetMachineAgentHealth(c *gc.C) {
+	s.assertSetAgentHealth(c, s.machine)
+}
+
+func (s *AgentHealthSuite) TestSetUnitAgentHealth(c *gc.C) {
+	s.assertSetAgentHealth(c, s.unit)
+}
+
+func (s *AgentHealthSuite) TestSetAgentHealthDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetAgentHealth(state.AgentHealth{HookQueueDepth: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set agent health for machine \d+: not found or dead`)
+}
+
+func (s *AgentHealthSuite) TestRemoveMachineRemovesAgentHealth(c *gc.C) {
+	err := s.machine.SetAgentHealth(state.AgentHealth{HookQueueDepth: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.machine.AgentHealth()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
@@ -19,11 +20,32 @@ import (
 // due to the fact that it is not accessed directly, but through
 // Annotations/Annotation below.
 // Note also the correspondence with AnnotationInfo in apiserver/params.
+//
+// Tags holds each annotation as a "key=value" string. It duplicates
+// the annotations map so that entities can be found by their
+// annotations using an index.
 type annotatorDoc struct {
 	EnvUUID     string            `bson:"env-uuid"`
 	GlobalKey   string            `bson:"globalkey"`
 	Tag         string            `bson:"tag"`
 	Annotations map[string]string `bson:"annotations"`
+	Tags        []string          `bson:"tags"`
+	TxnRevno    int64             `bson:"txn-revno"`
+}
+
+// annotationTags returns the tags recording the given annotations.
+func annotationTags(annotations map[string]string) []string {
+	tags := make([]string, 0, len(annotations))
+	for key, value := range annotations {
+		tags = append(tags, annotationTag(key, value))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// annotationTag returns the tag recording a single annotation.
+func annotationTag(key, value string) string {
+	return key + "=" + value
 }
 
 // SetAnnotations adds key/value pairs to annotations in MongoDB.
@@ -56,16 +78,17 @@ func (st *State) SetAnnotations(entity GlobalEntity, annotations map[string]stri
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		annotations, closer := st.getCollection(annotationsC)
 		defer closer()
-		if count, err := annotations.FindId(entity.globalKey()).Count(); err != nil {
-			return nil, err
-		} else if count == 0 {
+		var doc annotatorDoc
+		if err := annotations.FindId(entity.globalKey()).One(&doc); err == mgo.ErrNotFound {
 			// Check that the annotator entity was not previously destroyed.
 			if attempt != 0 {
 				return nil, fmt.Errorf("%s no longer exists", entity.Tag())
 			}
 			return insertAnnotationsOps(st, entity, toInsert)
+		} else if err != nil {
+			return nil, err
 		}
-		return updateAnnotations(st, entity, toUpdate, toRemove), nil
+		return updateAnnotations(st, entity, &doc, toUpdate, toRemove), nil
 	}
 	return st.run(buildTxn)
 }
@@ -107,6 +130,7 @@ func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]st
 			GlobalKey:   entity.globalKey(),
 			Tag:         tag.String(),
 			Annotations: toInsert,
+			Tags:        annotationTags(toInsert),
 		},
 	}}
 
@@ -137,16 +161,94 @@ func insertAnnotationsOps(st *State, entity GlobalEntity, toInsert map[string]st
 	}), nil
 }
 
-// updateAnnotations returns the operations required to update or remove annotations in MongoDB.
-func updateAnnotations(st *State, entity GlobalEntity, toUpdate, toRemove bson.M) []txn.Op {
+// updateAnnotations returns the operations required to update or remove
+// annotations in MongoDB. The entity's tags are rewritten to match its
+// updated annotations, so the operations assert that its existing
+// annotations, as held in doc, are unchanged.
+func updateAnnotations(st *State, entity GlobalEntity, doc *annotatorDoc, toUpdate, toRemove bson.M) []txn.Op {
+	annotations := make(map[string]string)
+	for key, value := range doc.Annotations {
+		annotations[key] = value
+	}
+	set := make(bson.M)
+	for field, value := range toUpdate {
+		annotations[strings.TrimPrefix(field, "annotations.")] = value.(string)
+		set[field] = value
+	}
+	for field := range toRemove {
+		delete(annotations, strings.TrimPrefix(field, "annotations."))
+	}
+	set["tags"] = annotationTags(annotations)
 	return []txn.Op{{
 		C:      annotationsC,
 		Id:     st.docID(entity.globalKey()),
-		Assert: txn.DocExists,
-		Update: setUnsetUpdate(toUpdate, toRemove),
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+		Update: setUnsetUpdate(set, toRemove),
 	}}
 }
 
+// findAnnotatedKinds holds the kinds of entity that can be found by
+// their annotations.
+var findAnnotatedKinds = []string{
+	names.EnvironTagKind,
+	names.MachineTagKind,
+	names.ServiceTagKind,
+	names.UnitTagKind,
+}
+
+// FindAnnotated returns the tags of the environment, machines, services
+// and units in the environment that carry all the given annotations,
+// sorted by tag. At least one annotation must be given, and none may
+// have an empty value.
+func (st *State) FindAnnotated(annotations map[string]string) ([]names.Tag, error) {
+	if len(annotations) == 0 {
+		return nil, errors.NotValidf("empty annotation query")
+	}
+	want := make([]string, 0, len(annotations))
+	for key, value := range annotations {
+		if key == "" || strings.ContainsAny(key, ".=") {
+			return nil, errors.NotValidf("annotation key %q", key)
+		}
+		if value == "" {
+			return nil, errors.NotValidf("empty value for annotation %q", key)
+		}
+		want = append(want, annotationTag(key, value))
+	}
+	coll, closer := st.getCollection(annotationsC)
+	defer closer()
+
+	var docs []annotatorDoc
+	err := coll.Find(bson.D{{"tags", bson.D{{"$all", want}}}}).Select(bson.D{{"tag", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot find annotated entities")
+	}
+	found := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		found = append(found, doc.Tag)
+	}
+	sort.Strings(found)
+	tags := make([]names.Tag, 0, len(found))
+	for _, tagString := range found {
+		tag, err := names.ParseTag(tagString)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if isFindAnnotatedKind(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func isFindAnnotatedKind(tag names.Tag) bool {
+	for _, kind := range findAnnotatedKinds {
+		if tag.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
 // annotationRemoveOp returns an operation to remove a given annotation
 // document from MongoDB.
 func annotationRemoveOp(st *State, id string) txn.Op {
@@ -133,6 +133,74 @@ func (s *AnnotationsSuite) TestSetAnnotationsConcurrently(c *gc.C) {
 	assertAnnotation(c, s.State, s.testEntity, key, last)
 }
 
+func (s *AnnotationsSuite) TestFindAnnotated(c *gc.C) {
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(s.testEntity, map[string]string{"tag": "production", "role": "db"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(other, map[string]string{"tag": "production", "role": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(env, map[string]string{"tag": "production"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	tags, err := s.State.FindAnnotated(map[string]string{"tag": "production"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{env.Tag(), s.testEntity.Tag(), other.Tag()})
+
+	tags, err = s.State.FindAnnotated(map[string]string{"tag": "production", "role": "db"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{s.testEntity.Tag()})
+
+	tags, err = s.State.FindAnnotated(map[string]string{"tag": "staging"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+}
+
+func (s *AnnotationsSuite) TestFindAnnotatedFollowsUpdates(c *gc.C) {
+	query := map[string]string{"tag": "production"}
+	s.assertSetAnnotation(c, "tag", "production")
+	tags, err := s.State.FindAnnotated(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{s.testEntity.Tag()})
+
+	s.assertSetAnnotation(c, "tag", "staging")
+	tags, err = s.State.FindAnnotated(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+
+	s.assertSetAnnotation(c, "tag", "production")
+	s.assertSetAnnotation(c, "tag", "")
+	tags, err = s.State.FindAnnotated(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+}
+
+func (s *AnnotationsSuite) TestFindAnnotatedInvalidQuery(c *gc.C) {
+	for i, test := range []struct {
+		query map[string]string
+		err   string
+	}{{
+		query: nil,
+		err:   "empty annotation query not valid",
+	}, {
+		query: map[string]string{"a=b": "c"},
+		err:   `annotation key "a=b" not valid`,
+	}, {
+		query: map[string]string{"a.b": "c"},
+		err:   `annotation key "a.b" not valid`,
+	}, {
+		query: map[string]string{"tag": ""},
+		err:   `empty value for annotation "tag" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.query)
+		_, err := s.State.FindAnnotated(test.query)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 type AnnotationsEnvSuite struct {
 	ConnSuite
 }
@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// auditC is the capped collection holding the audit log. As documents
+// cannot be removed from capped collections, it is not filtered by
+// environment automatically and must be accessed as a raw collection.
+const auditC = "audit"
+
+// The capped collection used for the audit log defaults to 10MB. It's
+// tweaked in export_test.go to 1MB to avoid the overhead of creating and
+// deleting the large file repeatedly in tests.
+var (
+	auditLogSize      = 10000000
+	auditLogSizeTests = 1000000
+)
+
+// AuditEntry records a single state-changing API call.
+type AuditEntry struct {
+	// Time holds the time at which the call completed.
+	Time time.Time
+
+	// Caller holds the tag of the entity that made the call.
+	Caller string
+
+	// Facade, Version, Id and Method identify the call that was made.
+	Facade  string
+	Version int
+	Id      string
+	Method  string
+
+	// Entities holds the tags of the entities named in the call's
+	// arguments.
+	Entities []string
+
+	// Args holds a summary of the call's arguments.
+	Args string
+
+	// Result summarises the outcome of the call. It is empty if the
+	// call succeeded completely.
+	Result string
+}
+
+// AuditFilter restricts the audit entries returned by AuditEntries.
+// Empty fields are not used to filter entries.
+type AuditFilter struct {
+	// Caller holds the tag of the entity that made the call.
+	Caller string
+
+	// Entity holds a tag that must be named in the call's arguments.
+	Entity string
+
+	// Facade and Method identify the call that was made.
+	Facade string
+	Method string
+
+	// Since holds the earliest time of any entry returned.
+	Since time.Time
+
+	// Limit holds the maximum number of entries returned.
+	Limit int
+}
+
+// auditDoc records a single state-changing API call.
+type auditDoc struct {
+	Id       bson.ObjectId `bson:"_id"`
+	EnvUUID  string        `bson:"env-uuid"`
+	Time     time.Time     `bson:"time"`
+	Caller   string        `bson:"caller"`
+	Facade   string        `bson:"facade"`
+	Version  int           `bson:"version"`
+	ObjectId string        `bson:"objectid"`
+	Method   string        `bson:"method"`
+	Entities []string      `bson:"entities,omitempty"`
+	Args     string        `bson:"args"`
+	Result   string        `bson:"result"`
+}
+
+// initAuditLog creates the capped audit collection in db, if it does
+// not already exist.
+func initAuditLog(db *mgo.Database) error {
+	info := mgo.CollectionInfo{Capped: true, MaxBytes: auditLogSize}
+	err := db.C(auditC).Create(&info)
+	if isCollectionExistsError(err) {
+		return maybeUnauthorized(err, "cannot create audit log collection")
+	}
+	return nil
+}
+
+// AddAuditEntry records the supplied entry in the environment's audit
+// log. The oldest entries are discarded once the log is full.
+func (st *State) AddAuditEntry(entry AuditEntry) error {
+	audit, closer := st.getRawCollection(auditC)
+	defer closer()
+
+	err := audit.Insert(&auditDoc{
+		Id:       bson.NewObjectId(),
+		EnvUUID:  st.EnvironUUID(),
+		Time:     entry.Time.UTC(),
+		Caller:   entry.Caller,
+		Facade:   entry.Facade,
+		Version:  entry.Version,
+		ObjectId: entry.Id,
+		Method:   entry.Method,
+		Entities: entry.Entities,
+		Args:     entry.Args,
+		Result:   entry.Result,
+	})
+	return errors.Annotate(err, "cannot add audit entry")
+}
+
+// AuditEntries returns the entries in the environment's audit log that
+// match the supplied filter, most recent first.
+func (st *State) AuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	audit, closer := st.getRawCollection(auditC)
+	defer closer()
+
+	sel := bson.D{{"env-uuid", st.EnvironUUID()}}
+	if filter.Caller != "" {
+		sel = append(sel, bson.DocElem{"caller", filter.Caller})
+	}
+	if filter.Entity != "" {
+		sel = append(sel, bson.DocElem{"entities", filter.Entity})
+	}
+	if filter.Facade != "" {
+		sel = append(sel, bson.DocElem{"facade", filter.Facade})
+	}
+	if filter.Method != "" {
+		sel = append(sel, bson.DocElem{"method", filter.Method})
+	}
+	if !filter.Since.IsZero() {
+		sel = append(sel, bson.DocElem{"time", bson.D{{"$gte", filter.Since.UTC()}}})
+	}
+	// Capped collections preserve insertion order, so the most recent
+	// entries are found by sorting in reverse natural order.
+	query := audit.Find(sel).Sort("-$natural")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	entries := make([]AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = AuditEntry{
+			Time:     doc.Time.UTC(),
+			Caller:   doc.Caller,
+			Facade:   doc.Facade,
+			Version:  doc.Version,
+			Id:       doc.ObjectId,
+			Method:   doc.Method,
+			Entities: doc.Entities,
+			Args:     doc.Args,
+			Result:   doc.Result,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+var (
+	auditEntry0 = state.AuditEntry{
+		Time:     time.Date(2015, 5, 1, 10, 0, 0, 0, time.UTC),
+		Caller:   "user-admin",
+		Facade:   "Client",
+		Method:   "ServiceDeploy",
+		Entities: []string{"service-wordpress"},
+		Args:     `{"ServiceName":"wordpress"}`,
+	}
+	auditEntry1 = state.AuditEntry{
+		Time:     time.Date(2015, 5, 1, 11, 0, 0, 0, time.UTC),
+		Caller:   "machine-0",
+		Facade:   "Machiner",
+		Version:  1,
+		Method:   "SetStatus",
+		Entities: []string{"machine-0"},
+		Args:     `{"Entities":[{"Tag":"machine-0","Status":"started"}]}`,
+	}
+	auditEntry2 = state.AuditEntry{
+		Time:     time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC),
+		Caller:   "user-admin",
+		Facade:   "Client",
+		Method:   "DestroyServiceUnits",
+		Entities: []string{"unit-wordpress-0"},
+		Args:     `{"UnitNames":["wordpress/0"]}`,
+		Result:   "unit is already dying",
+	}
+)
+
+func (s *AuditSuite) addEntries(c *gc.C) {
+	for _, entry := range []state.AuditEntry{auditEntry0, auditEntry1, auditEntry2} {
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *AuditSuite) TestAuditEntriesEmpty(c *gc.C) {
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *AuditSuite) TestAuditEntries(c *gc.C) {
+	s.addEntries(c)
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []state.AuditEntry{auditEntry2, auditEntry1, auditEntry0})
+}
+
+func (s *AuditSuite) TestAuditEntriesFiltered(c *gc.C) {
+	s.addEntries(c)
+	for i, test := range []struct {
+		filter   state.AuditFilter
+		expected []state.AuditEntry
+	}{{
+		filter:   state.AuditFilter{Caller: "user-admin"},
+		expected: []state.AuditEntry{auditEntry2, auditEntry0},
+	}, {
+		filter:   state.AuditFilter{Entity: "machine-0"},
+		expected: []state.AuditEntry{auditEntry1},
+	}, {
+		filter:   state.AuditFilter{Facade: "Client", Method: "ServiceDeploy"},
+		expected: []state.AuditEntry{auditEntry0},
+	}, {
+		filter:   state.AuditFilter{Since: auditEntry1.Time},
+		expected: []state.AuditEntry{auditEntry2, auditEntry1},
+	}, {
+		filter:   state.AuditFilter{Limit: 1},
+		expected: []state.AuditEntry{auditEntry2},
+	}, {
+		filter: state.AuditFilter{Caller: "unit-mysql-0"},
+	}} {
+		c.Logf("test %d: %+v", i, test.filter)
+		entries, err := s.State.AuditEntries(test.filter)
+		c.Assert(err, jc.ErrorIsNil)
+		if test.expected == nil {
+			c.Assert(entries, gc.HasLen, 0)
+		} else {
+			c.Assert(entries, jc.DeepEquals, test.expected)
+		}
+	}
+}
+
+func (s *AuditSuite) TestAuditEntriesPerEnvironment(c *gc.C) {
+	s.addEntries(c)
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	entries, err := st.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
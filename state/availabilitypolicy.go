@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+const availabilityPolicyKey = "availabilityPolicy"
+
+// AvailabilityPolicy records how the state servers were last asked to
+// be made available, so that the request can be repeated when a state
+// server fails.
+type AvailabilityPolicy struct {
+	// AutoReplace specifies whether unavailable state servers should
+	// be replaced automatically.
+	AutoReplace bool
+
+	// NumStateServers, Constraints and Series hold the arguments
+	// with which EnsureAvailability should be called to replace
+	// unavailable state servers.
+	NumStateServers int
+	Constraints     constraints.Value
+	Series          string
+}
+
+// availabilityPolicyDoc is the persistent form of AvailabilityPolicy.
+type availabilityPolicyDoc struct {
+	AutoReplace     bool   `bson:"autoreplace"`
+	NumStateServers int    `bson:"numstateservers"`
+	Constraints     string `bson:"constraints"`
+	Series          string `bson:"series"`
+}
+
+// AvailabilityPolicy returns the policy last recorded with
+// SetAvailabilityPolicy. If none has been recorded, unavailable state
+// servers are not replaced automatically.
+func (st *State) AvailabilityPolicy() (AvailabilityPolicy, error) {
+	stateServers, closer := st.getCollection(stateServersC)
+	defer closer()
+
+	var doc availabilityPolicyDoc
+	err := stateServers.FindId(availabilityPolicyKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AvailabilityPolicy{}, nil
+	} else if err != nil {
+		return AvailabilityPolicy{}, errors.Annotate(err, "cannot get availability policy")
+	}
+	cons, err := constraints.Parse(doc.Constraints)
+	if err != nil {
+		return AvailabilityPolicy{}, errors.Annotate(err, "cannot parse availability policy constraints")
+	}
+	return AvailabilityPolicy{
+		AutoReplace:     doc.AutoReplace,
+		NumStateServers: doc.NumStateServers,
+		Constraints:     cons,
+		Series:          doc.Series,
+	}, nil
+}
+
+// SetAvailabilityPolicy records the policy by which state servers are
+// made available.
+func (st *State) SetAvailabilityPolicy(policy AvailabilityPolicy) error {
+	doc := availabilityPolicyDoc{
+		AutoReplace:     policy.AutoReplace,
+		NumStateServers: policy.NumStateServers,
+		Constraints:     policy.Constraints.String(),
+		Series:          policy.Series,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		stateServers, closer := st.getCollection(stateServersC)
+		defer closer()
+		count, err := stateServers.FindId(availabilityPolicyKey).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return []txn.Op{{
+				C:      stateServersC,
+				Id:     availabilityPolicyKey,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      stateServersC,
+			Id:     availabilityPolicyKey,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", &doc}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot set availability policy")
+	}
+	return nil
+}
+
+// UnavailableStateServers returns the ids of the state server machines
+// that want a vote in peer election but are not available, and so
+// would be replaced by a call to EnsureAvailability.
+func (st *State) UnavailableStateServers() ([]string, error) {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var unavailable []string
+	for _, id := range info.VotingMachineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.WantsVote() {
+			continue
+		}
+		available, err := stateServerAvailable(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !available {
+			unavailable = append(unavailable, id)
+		}
+	}
+	return unavailable, nil
+}
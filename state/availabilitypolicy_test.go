@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type AvailabilityPolicySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AvailabilityPolicySuite{})
+
+func (s *AvailabilityPolicySuite) TestDefaultPolicy(c *gc.C) {
+	policy, err := s.State.AvailabilityPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, state.AvailabilityPolicy{})
+}
+
+func (s *AvailabilityPolicySuite) TestSetAvailabilityPolicy(c *gc.C) {
+	policy := state.AvailabilityPolicy{
+		AutoReplace:     true,
+		NumStateServers: 5,
+		Constraints:     constraints.MustParse("mem=4G"),
+		Series:          "trusty",
+	}
+	err := s.State.SetAvailabilityPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := s.State.AvailabilityPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, policy)
+
+	policy = state.AvailabilityPolicy{NumStateServers: 3}
+	err = s.State.SetAvailabilityPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err = s.State.AvailabilityPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, policy)
+}
+
+func (s *AvailabilityPolicySuite) TestUnavailableStateServers(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	_, err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ids, err := s.State.UnavailableStateServers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 0)
+
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return m.Id() != "1", nil
+	})
+	ids, err = s.State.UnavailableStateServers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{"1"})
+
+	// Once replaced, the machine no longer wants a vote.
+	changes, err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Demoted, jc.DeepEquals, []string{"1"})
+	ids, err = s.State.UnavailableStateServers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 0)
+}
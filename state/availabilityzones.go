@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AvailabilityZone describes an availability zone of the environment's
+// provider, as last discovered by the provisioner.
+type AvailabilityZone struct {
+	// Name is the provider's name for the zone.
+	Name string
+
+	// Available reports whether instances may be started in the zone.
+	Available bool
+}
+
+type availabilityZoneDoc struct {
+	DocID     string `bson:"_id"`
+	EnvUUID   string `bson:"env-uuid"`
+	Name      string `bson:"name"`
+	Available bool   `bson:"available"`
+}
+
+// AvailabilityZones returns the availability zones recorded for the
+// environment, ordered by name.
+func (st *State) AvailabilityZones() ([]AvailabilityZone, error) {
+	docs, err := st.availabilityZoneDocs()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get availability zones")
+	}
+	zones := make([]AvailabilityZone, len(docs))
+	for i, doc := range docs {
+		zones[i] = AvailabilityZone{Name: doc.Name, Available: doc.Available}
+	}
+	return zones, nil
+}
+
+func (st *State) availabilityZoneDocs() ([]availabilityZoneDoc, error) {
+	coll, closer := st.getCollection(availabilityZonesC)
+	defer closer()
+
+	var docs []availabilityZoneDoc
+	if err := coll.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return docs, nil
+}
+
+// SetAvailabilityZones records the availability zones of the
+// environment's provider, replacing any recorded before.
+func (st *State) SetAvailabilityZones(zones []AvailabilityZone) error {
+	wanted := make(map[string]AvailabilityZone)
+	for _, zone := range zones {
+		if zone.Name == "" {
+			return errors.New("cannot set availability zones: empty zone name")
+		}
+		if _, ok := wanted[zone.Name]; ok {
+			return errors.Errorf("cannot set availability zones: duplicate zone %q", zone.Name)
+		}
+		wanted[zone.Name] = zone
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		docs, err := st.availabilityZoneDocs()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		existing := make(map[string]bool)
+		for _, doc := range docs {
+			existing[doc.Name] = true
+			zone, ok := wanted[doc.Name]
+			switch {
+			case !ok:
+				ops = append(ops, txn.Op{
+					C:      availabilityZonesC,
+					Id:     doc.DocID,
+					Assert: txn.DocExists,
+					Remove: true,
+				})
+			case zone.Available != doc.Available:
+				ops = append(ops, txn.Op{
+					C:      availabilityZonesC,
+					Id:     doc.DocID,
+					Assert: txn.DocExists,
+					Update: bson.D{{"$set", bson.D{{"available", zone.Available}}}},
+				})
+			}
+		}
+		for _, zone := range zones {
+			if existing[zone.Name] {
+				continue
+			}
+			ops = append(ops, txn.Op{
+				C:      availabilityZonesC,
+				Id:     st.docID(zone.Name),
+				Assert: txn.DocMissing,
+				Insert: &availabilityZoneDoc{
+					EnvUUID:   st.EnvironUUID(),
+					Name:      zone.Name,
+					Available: zone.Available,
+				},
+			})
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	return errors.Annotate(st.run(buildTxn), "cannot set availability zones")
+}
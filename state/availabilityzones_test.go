@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type AvailabilityZonesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AvailabilityZonesSuite{})
+
+func (s *AvailabilityZonesSuite) TestNoZones(c *gc.C) {
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
+func (s *AvailabilityZonesSuite) TestSetAvailabilityZones(c *gc.C) {
+	err := s.State.SetAvailabilityZones([]state.AvailabilityZone{
+		{Name: "zone-b", Available: true},
+		{Name: "zone-a", Available: false},
+		{Name: "zone-c", Available: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []state.AvailabilityZone{
+		{Name: "zone-a", Available: false},
+		{Name: "zone-b", Available: true},
+		{Name: "zone-c", Available: true},
+	})
+
+	// The zones recorded before are replaced.
+	err = s.State.SetAvailabilityZones([]state.AvailabilityZone{
+		{Name: "zone-a", Available: true},
+		{Name: "zone-d", Available: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	zones, err = s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []state.AvailabilityZone{
+		{Name: "zone-a", Available: true},
+		{Name: "zone-d", Available: true},
+	})
+
+	// Setting the same zones again is not an error.
+	err = s.State.SetAvailabilityZones([]state.AvailabilityZone{
+		{Name: "zone-a", Available: true},
+		{Name: "zone-d", Available: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AvailabilityZonesSuite) TestSetAvailabilityZonesInvalid(c *gc.C) {
+	err := s.State.SetAvailabilityZones([]state.AvailabilityZone{{Name: ""}})
+	c.Assert(err, gc.ErrorMatches, "cannot set availability zones: empty zone name")
+	err = s.State.SetAvailabilityZones([]state.AvailabilityZone{{Name: "a"}, {Name: "a"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set availability zones: duplicate zone "a"`)
+}
+
+func (s *AvailabilityZonesSuite) TestZonesPerEnvironment(c *gc.C) {
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	err := st.SetAvailabilityZones([]state.AvailabilityZone{{Name: "zone-a", Available: true}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
@@ -19,7 +19,9 @@ import (
 
 // Restore handles either returning or creating a state server to a backed up status:
 // * extracts the content of the given backup file and:
-// * runs mongorestore with the backed up mongo dump
+// * runs mongorestore with the backed up mongo dump, first restoring
+// the full backup of an incremental backup and then replaying the
+// incremental backup's oplog on top of it
 // * updates and writes configuration files
 // * updates existing db entries to make sure they hold no references to
 // old instances
@@ -38,6 +40,16 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 	}
 	defer workspace.Close()
 
+	dbWorkspaces := []*ArchiveWorkspace{workspace}
+	if meta.Incremental() {
+		baseWorkspace, err := b.baseWorkspace(meta.BaseID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer baseWorkspace.Close()
+		dbWorkspaces = []*ArchiveWorkspace{baseWorkspace, workspace}
+	}
+
 	// TODO(perrito666) Create a compatibility table of sorts.
 	version := meta.Origin.Version
 	backupMachine := names.NewMachineTag(meta.Origin.Machine)
@@ -81,8 +93,10 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 	}
 
 	// Restore mongodb from backup
-	if err := placeNewMongo(workspace.DBDumpDir, version); err != nil {
-		return errors.Annotate(err, "error restoring state from backup")
+	for _, ws := range dbWorkspaces {
+		if err := placeNewMongo(ws.DBDumpDir, version); err != nil {
+			return errors.Annotate(err, "error restoring state from backup")
+		}
 	}
 
 	// Re-start replicaset with the new value for server address
@@ -124,23 +138,32 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 		return errors.Annotate(err, "cannot update api server machine addresses")
 	}
 
-	// update all agents known to the new state server.
-	// TODO(perrito666): We should never stop process because of this.
-	// updateAllMachines will not return errors for individual
-	// agent update failures
-	machines, err := st.AllMachines()
-	if err != nil {
-		return errors.Trace(err)
+	// The API addresses in state still refer to the old state
+	// servers; replace them so that agents which reconnect to the
+	// new one, and clients logging in to it, learn the new addresses.
+	apiHostPorts = [][]network.HostPort{
+		network.NewHostPorts(ssi.APIPort, args.PrivateAddress, args.PublicAddress),
 	}
-	if err = updateAllMachines(args.PrivateAddress, machines); err != nil {
-		return errors.Annotate(err, "cannot update agents")
+	if err := st.SetAPIHostPorts(apiHostPorts); err != nil {
+		return errors.Annotate(err, "cannot update api server addresses")
 	}
 
 	info, err := st.RestoreInfoSetter()
+	if err != nil {
+		return errors.Trace(err)
+	}
 
+	// update all agents known to the new state server. Failures to
+	// update individual agents do not stop the restore; they are
+	// recorded so the client can report them.
+	machines, err := st.AllMachines()
 	if err != nil {
 		return errors.Trace(err)
 	}
+	unreachable := updateAllMachines(args.PrivateAddress, machines)
+	if err := info.SetUnreachableMachines(unreachable); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Mark restoreInfo as Finished so upon restart of the apiserver
 	// the client can reconnect and determine if we where succesful.
@@ -148,3 +171,21 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 
 	return errors.Annotate(err, "failed to set status to finished")
 }
+
+// baseWorkspace unpacks the full backup on which an incremental
+// backup is based.
+func (b *backups) baseWorkspace(baseID string) (*ArchiveWorkspace, error) {
+	baseMeta, baseReader, err := b.Get(baseID)
+	if err != nil {
+		return nil, errors.Annotatef(err, "could not fetch full backup %q", baseID)
+	}
+	defer baseReader.Close()
+	if baseMeta.Incremental() {
+		return nil, errors.Errorf("backup %q is not a full backup", baseID)
+	}
+	workspace, err := NewArchiveWorkspaceReader(baseReader)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot unpack full backup file")
+	}
+	return workspace, nil
+}
@@ -4,6 +4,7 @@
 package backups
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/juju/paths"
@@ -42,6 +45,10 @@ type DBInfo struct {
 	Password string
 	// Targets is a list of databases to dump.
 	Targets set.Strings
+	// Since, if non-zero, is the oplog position after which the
+	// oplog is dumped. Only those oplog entries are dumped, making
+	// the backup incremental.
+	Since int64
 }
 
 // ignoredDatabases is the list of databases that should not be
@@ -88,6 +95,26 @@ func getBackupTargetDatabases(session DBSession) (set.Strings, error) {
 	return targets, nil
 }
 
+// OplogRange returns the positions of the oldest and the most recent
+// entries in the replication oplog. Both are zero if there is no oplog.
+func OplogRange(session *mgo.Session) (oldest, newest int64, err error) {
+	oplog := session.DB("local").C("oplog.rs")
+	var doc struct {
+		Timestamp bson.MongoTimestamp `bson:"ts"`
+	}
+	err = oplog.Find(nil).Sort("$natural").One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, errors.Annotate(err, "cannot read oplog")
+	}
+	oldest = int64(doc.Timestamp)
+	if err := oplog.Find(nil).Sort("-$natural").One(&doc); err != nil {
+		return 0, 0, errors.Annotate(err, "cannot read oplog")
+	}
+	return oldest, int64(doc.Timestamp), nil
+}
+
 const dumpName = "mongodump"
 
 // DBDumper is any type that dumps something to a dump dir.
@@ -122,7 +149,8 @@ type mongoDumper struct {
 }
 
 // NewDBDumper returns a new value with a Dump method for dumping the
-// juju state database.
+// juju state database. If info.Since is set, only the oplog entries
+// after that position are dumped.
 func NewDBDumper(info *DBInfo) (DBDumper, error) {
 	mongodumpPath, err := getMongodumpPath()
 	if err != nil {
@@ -145,9 +173,20 @@ func (md *mongoDumper) options(dumpDir string) []string {
 		"--username", md.Username,
 		"--password", md.Password,
 		"--out", dumpDir,
-		"--oplog",
 	}
-	return options
+	if md.Since == 0 {
+		return append(options, "--oplog")
+	}
+	// A mongo timestamp holds the seconds since the epoch in its
+	// high 32 bits and an ordinal in its low 32 bits.
+	query := fmt.Sprintf(`{"ts": {"$gt": {"$timestamp": {"t": %d, "i": %d}}}}`,
+		uint64(md.Since)>>32, uint64(md.Since)&0xffffffff,
+	)
+	return append(options,
+		"--db", "local",
+		"--collection", "oplog.rs",
+		"--query", query,
+	)
 }
 
 func (md *mongoDumper) dump(dumpDir string) error {
@@ -164,6 +203,9 @@ func (md *mongoDumper) Dump(baseDumpDir string) error {
 	if err := md.dump(baseDumpDir); err != nil {
 		return errors.Trace(err)
 	}
+	if md.Since != 0 {
+		return errors.Trace(placeOplog(baseDumpDir))
+	}
 
 	found, err := listDatabases(baseDumpDir)
 	if err != nil {
@@ -196,6 +238,18 @@ func stripIgnored(ignored set.Strings, dumpDir string) error {
 	return nil
 }
 
+// placeOplog moves the oplog entries dumped for an incremental backup
+// to where "mongorestore --oplogReplay" expects to find them, leaving
+// no databases in the dump dir.
+func placeOplog(dumpDir string) error {
+	localDir := filepath.Join(dumpDir, "local")
+	oplogFile := filepath.Join(localDir, "oplog.rs.bson")
+	if err := os.Rename(oplogFile, filepath.Join(dumpDir, "oplog.bson")); err != nil {
+		return errors.Annotate(err, "while placing oplog dump")
+	}
+	return errors.Trace(os.RemoveAll(localDir))
+}
+
 // listDatabases returns the name of each sub-directory of the dump
 // directory.  Each corresponds to a database dump generated by
 // mongodump.  Note that, while mongodump is unlikely to change behavior
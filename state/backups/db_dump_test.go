@@ -4,6 +4,7 @@
 package backups_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -30,7 +31,12 @@ func (s *dumpSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 
 	targets := set.NewStrings("juju", "admin")
-	s.dbInfo = &backups.DBInfo{"a", "b", "c", targets}
+	s.dbInfo = &backups.DBInfo{
+		Address:  "a",
+		Username: "b",
+		Password: "c",
+		Targets:  targets,
+	}
 	s.targets = targets
 	s.dumpDir = c.MkDir()
 }
@@ -125,3 +131,30 @@ func (s *dumpSuite) TestDumpNothingIgnored(c *gc.C) {
 
 	s.checkDBs(c, "juju", "admin")
 }
+
+func (s *dumpSuite) TestDumpIncremental(c *gc.C) {
+	s.PatchValue(backups.GetMongodumpPath, func() (string, error) {
+		return "bogusmongodump", nil
+	})
+	var ranArgs []string
+	s.PatchValue(backups.RunCommand, func(cmd string, args ...string) error {
+		ranArgs = args
+		dirName := s.prepDB(c, "local")
+		return ioutil.WriteFile(filepath.Join(dirName, "oplog.rs.bson"), []byte("oplog"), 0644)
+	})
+	s.dbInfo.Since = 5<<32 | 2
+	dumper := s.prep(c)
+
+	err := dumper.Dump(s.dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ranArgs[len(ranArgs)-6:], jc.DeepEquals, []string{
+		"--db", "local",
+		"--collection", "oplog.rs",
+		"--query", `{"ts": {"$gt": {"$timestamp": {"t": 5, "i": 2}}}}`,
+	})
+	data, err := ioutil.ReadFile(filepath.Join(s.dumpDir, "oplog.bson"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "oplog")
+	s.checkStripped(c, "local")
+}
@@ -63,6 +63,8 @@ func GetFilesToBackUp(rootDir string, paths *Paths, oldmachine string) ([]string
 		return nil, errors.Annotate(err, "failed to fetch startup conf files")
 	}
 
+	// The agent directories are backed up whole: besides agent.conf,
+	// restore needs the agent.key with which its secrets are sealed.
 	glob = filepath.Join(rootDir, paths.DataDir, agentsDir, agentsConfs)
 	agentConfs, err := filepath.Glob(glob)
 	if err != nil {
@@ -6,6 +6,7 @@
 package backups_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -130,6 +131,28 @@ func (s *filesSuite) TestGetFilesToBackUpMachine0(c *gc.C) {
 	s.checkSameStrings(c, files, expected)
 }
 
+func (s *filesSuite) TestGetFilesToBackUpAgentDir(c *gc.C) {
+	paths := backups.Paths{
+		DataDir: "/var/lib/juju",
+		LogsDir: "/var/log/juju",
+	}
+	s.createFiles(c, paths, s.root, "0")
+	agentDir := filepath.Join(s.root, "/var/lib/juju/agents/machine-0")
+	err := os.MkdirAll(agentDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"agent.conf", "agent.key"} {
+		err := ioutil.WriteFile(filepath.Join(agentDir, name), nil, 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	files, err := backups.GetFilesToBackUp(s.root, &paths, "0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The whole agent directory, including the agent.key that seals
+	// the secrets in agent.conf, is backed up.
+	c.Check(set.NewStrings(files...).Contains(agentDir), jc.IsTrue)
+}
+
 func (s *filesSuite) TestDirectoriesCleaned(c *gc.C) {
 	recreatableFolder := filepath.Join(s.root, "recreate_me")
 	os.MkdirAll(recreatableFolder, os.FileMode(0755))
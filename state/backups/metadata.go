@@ -62,6 +62,16 @@ type Metadata struct {
 	Origin Origin
 	// Notes is an optional user-supplied annotation.
 	Notes string
+	// BaseID is the ID of the full backup on which an incremental
+	// backup is based. It is empty for full backups.
+	BaseID string
+	// OplogPosition is the position in the replication oplog at which
+	// the backup was started. An incremental backup holds the oplog
+	// entries after the position of its full backup.
+	OplogPosition int64
+	// Scheduled records whether the backup was created automatically
+	// on the environment's backups schedule.
+	Scheduled bool
 }
 
 // NewMetadata returns a new Metadata for a state backup archive.  Only
@@ -95,6 +105,31 @@ func NewMetadataState(db DB, machine string) (*Metadata, error) {
 	return meta, nil
 }
 
+// Incremental reports whether the backup holds only the oplog
+// entries since its full backup.
+func (m *Metadata) Incremental() bool {
+	return m.BaseID != ""
+}
+
+// LatestFullBackup returns the most recently started of the listed
+// full backups on which incremental backups may be based. If there is
+// none, an error satisfying errors.IsNotFound is returned.
+func LatestFullBackup(list []*Metadata) (*Metadata, error) {
+	var latest *Metadata
+	for _, meta := range list {
+		if meta.Incremental() || meta.OplogPosition == 0 {
+			continue
+		}
+		if latest == nil || meta.Started.After(latest.Started) {
+			latest = meta
+		}
+	}
+	if latest == nil {
+		return nil, errors.NotFoundf("full backup")
+	}
+	return latest, nil
+}
+
 // MarkComplete populates the remaining metadata values.  The default
 // checksum format is used.
 func (m *Metadata) MarkComplete(size int64, checksum string) error {
@@ -134,6 +169,12 @@ type flatMetadata struct {
 	Machine     string
 	Hostname    string
 	Version     version.Number
+
+	// incremental
+
+	BaseID        string `json:",omitempty"`
+	OplogPosition int64  `json:",omitempty"`
+	Scheduled     bool   `json:",omitempty"`
 }
 
 // TODO(ericsnow) Move AsJSONBuffer to filestorage.Metadata.
@@ -153,6 +194,10 @@ func (m *Metadata) AsJSONBuffer() (io.Reader, error) {
 		Machine:     m.Origin.Machine,
 		Hostname:    m.Origin.Hostname,
 		Version:     m.Origin.Version,
+
+		BaseID:        m.BaseID,
+		OplogPosition: m.OplogPosition,
+		Scheduled:     m.Scheduled,
 	}
 
 	stored := m.Stored()
@@ -195,6 +240,9 @@ func NewMetadataJSONReader(in io.Reader) (*Metadata, error) {
 		meta.Finished = &flat.Finished
 	}
 	meta.Notes = flat.Notes
+	meta.BaseID = flat.BaseID
+	meta.OplogPosition = flat.OplogPosition
+	meta.Scheduled = flat.Scheduled
 	meta.Origin = Origin{
 		Environment: flat.Environment,
 		Machine:     flat.Machine,
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -117,3 +118,43 @@ func (s *metadataSuite) TestBuildMetadata(c *gc.C) {
 	c.Check(meta.Origin.Hostname, gc.Equals, backups.UnknownString)
 	c.Check(meta.Origin.Version.String(), gc.Equals, backups.UnknownVersion.String())
 }
+
+func (s *metadataSuite) TestIncrementalJSONRoundTrip(c *gc.C) {
+	meta := backups.NewMetadata()
+	meta.BaseID = "20140909-115934.asdf-zxcv-qwe"
+	meta.OplogPosition = 5<<32 | 2
+
+	buf, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(buf.(*bytes.Buffer).String(), jc.Contains, `"BaseID":"20140909-115934.asdf-zxcv-qwe"`)
+
+	read, err := backups.NewMetadataJSONReader(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(read.Incremental(), jc.IsTrue)
+	c.Check(read.BaseID, gc.Equals, meta.BaseID)
+	c.Check(read.OplogPosition, gc.Equals, meta.OplogPosition)
+}
+
+func (s *metadataSuite) TestLatestFullBackup(c *gc.C) {
+	newMeta := func(id string, started time.Time, position int64, baseID string) *backups.Metadata {
+		meta := backups.NewMetadata()
+		meta.SetID(id)
+		meta.Started = started
+		meta.OplogPosition = position
+		meta.BaseID = baseID
+		return meta
+	}
+	t0 := time.Date(2015, time.May, 1, 0, 0, 0, 0, time.UTC)
+	list := []*backups.Metadata{
+		newMeta("old", t0, 1, ""),
+		newMeta("full", t0.Add(time.Hour), 2, ""),
+		newMeta("incremental", t0.Add(2*time.Hour), 3, "full"),
+		newMeta("legacy", t0.Add(3*time.Hour), 0, ""),
+	}
+	latest, err := backups.LatestFullBackup(list)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(latest.ID(), gc.Equals, "full")
+
+	_, err = backups.LatestFullBackup(list[2:])
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
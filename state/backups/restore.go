@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"text/template"
@@ -147,9 +148,14 @@ func newStateConnection(info *mongo.MongoInfo) (*state.State, error) {
 // It is too late to go back and errors in a couple of agents have
 // better chance of being fixed by the user, if we were to fail
 // we risk an inconsistent state server because of one unresponsive
-// agent, we should nevertheless return the err info to the user.
-func updateAllMachines(privateAddress string, machines []*state.Machine) error {
-	var machineUpdating sync.WaitGroup
+// agent, so the ids of the machines that could not be updated are
+// returned instead, to be reported to the user.
+func updateAllMachines(privateAddress string, machines []*state.Machine) []string {
+	var (
+		machineUpdating sync.WaitGroup
+		mu              sync.Mutex
+		unreachable     []string
+	)
 	for key := range machines {
 		// key is used to have machine be scope bound to the loop iteration.
 		machine := machines[key]
@@ -162,18 +168,24 @@ func updateAllMachines(privateAddress string, machines []*state.Machine) error {
 		go func() {
 			defer machineUpdating.Done()
 			err := runMachineUpdate(machine.Addresses(), setAgentAddressScript(privateAddress))
-			logger.Errorf("failed updating machine: %v", err)
+			if err == nil {
+				return
+			}
+			logger.Errorf("failed updating machine %s: %v", machine.Id(), err)
+			mu.Lock()
+			unreachable = append(unreachable, machine.Id())
+			mu.Unlock()
 		}()
 	}
 	machineUpdating.Wait()
-
-	// We should return errors encapsulated in a digest.
-	return nil
+	sort.Strings(unreachable)
+	return unreachable
 }
 
 // agentAddressAndRelationsTemplate is the template used to replace the api server data
 // in the agents for the new ones if the machine has been rebootstraped it will also reset
-// the relations so hooks will re-fire.
+// the relations so hooks will re-fire. The first address of each list is replaced and
+// the rest, which refer to the state servers that no longer exist, are dropped.
 var agentAddressAndRelationsTemplate = template.Must(template.New("").Parse(`
 set -xu
 cd /var/lib/juju/agents
@@ -186,6 +198,12 @@ do
 	sed -i.old -r "/^(stateaddresses|apiaddresses):/{
 		n
 		s/- .*(:[0-9]+)/- {{.Address}}\1/
+		:more
+		N
+		s/\n- .*$//
+		t more
+		P
+		D
 	}" $agent/agent.conf
 
 	# If we're processing a unit agent's directly
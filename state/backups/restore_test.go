@@ -147,6 +147,8 @@ func (r *RestoreSuite) TestSetAgentAddressScript(c *gc.C) {
 		expectedString := fmt.Sprintf("\t\ts/- .*(:[0-9]+)/- %s\\1/\n", address)
 		logger.Infof(fmt.Sprintf("Testing with address %q", address))
 		c.Assert(strings.Contains(template, expectedString), gc.Equals, true)
+		// The addresses of the other, lost, state servers are dropped.
+		c.Assert(strings.Contains(template, "\t\ts/\\n- .*$//\n"), gc.Equals, true)
 	}
 }
 
@@ -45,6 +45,13 @@ type storageMetaDoc struct {
 	Finished int64  `bson:"finished,minsize"`
 	Notes    string `bson:"notes,omitempty"`
 
+	Scheduled bool `bson:"scheduled,omitempty"`
+
+	// incremental
+
+	BaseID        string `bson:"baseid,omitempty"`
+	OplogPosition int64  `bson:"oplogposition,omitempty"`
+
 	// origin
 
 	Environment string         `bson:"environment"`
@@ -120,6 +127,9 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.BaseID = doc.BaseID
+	meta.OplogPosition = doc.OplogPosition
+	meta.Scheduled = doc.Scheduled
 
 	meta.Origin.Environment = doc.Environment
 	meta.Origin.Machine = doc.Machine
@@ -170,6 +180,9 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.BaseID = meta.BaseID
+	doc.OplogPosition = meta.OplogPosition
+	doc.Scheduled = meta.Scheduled
 
 	doc.Environment = meta.Origin.Environment
 	doc.Machine = meta.Origin.Machine
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"path"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/storage"
+)
+
+// providerStorageRoot is the directory in provider storage to which
+// backup archives are uploaded.
+const providerStorageRoot = "juju-backups"
+
+// ProviderStoragePath returns the path in provider storage to which
+// the identified backup archive is uploaded.
+func ProviderStoragePath(id string) string {
+	return path.Join(providerStorageRoot, id+".tar.gz")
+}
+
+// UploadArchive copies the identified backup archive from backups
+// storage to the environment provider's object store (for example S3
+// or Swift), so that it outlives the state servers. It returns the
+// path of the archive in provider storage.
+func UploadArchive(b Backups, stor storage.StorageWriter, id string) (string, error) {
+	meta, archive, err := b.Get(id)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer archive.Close()
+
+	name := ProviderStoragePath(meta.ID())
+	if err := stor.Put(name, archive, meta.Size()); err != nil {
+		return "", errors.Annotatef(err, "cannot upload backup %q", id)
+	}
+	return name, nil
+}
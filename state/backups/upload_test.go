@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type uploadSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&uploadSuite{})
+
+func (s *uploadSuite) TestUploadArchive(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	meta.SetID("spam")
+	err := meta.MarkComplete(int64(len("<archive>")), "<checksum>")
+	c.Assert(err, jc.ErrorIsNil)
+	fake := &backupstesting.FakeBackups{
+		Meta:    meta,
+		Archive: ioutil.NopCloser(bytes.NewBufferString("<archive>")),
+	}
+	stor, err := filestorage.NewFileStorageWriter(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+
+	name, err := backups.UploadArchive(fake, stor, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(name, gc.Equals, "juju-backups/spam.tar.gz")
+	c.Check(fake.IDArg, gc.Equals, "spam")
+
+	r, err := stor.Get(name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<archive>")
+}
@@ -125,13 +125,45 @@ func (b *block) Type() BlockType {
 // SwitchBlockOn enables block of specified type for the
 // current environment.
 func (st *State) SwitchBlockOn(t BlockType, msg string) error {
-	return setEnvironmentBlock(st, t, msg)
+	return setEnvironmentBlock(st, t, st.EnvironTag(), msg)
 }
 
 // SwitchBlockOff disables block of specified type for the
 // current environment.
 func (st *State) SwitchBlockOff(t BlockType) error {
-	return removeEnvironmentBlock(st, t)
+	return removeEnvironmentBlock(st, t, st.EnvironTag())
+}
+
+// SwitchBlockOnForEntity enables block of specified type for the
+// service or machine with the given tag, so that only operations
+// on that entity are blocked.
+func (st *State) SwitchBlockOnForEntity(t BlockType, tag names.Tag, msg string) error {
+	if err := st.checkBlockableEntity(tag); err != nil {
+		return errors.Trace(err)
+	}
+	return setEnvironmentBlock(st, t, tag, msg)
+}
+
+// SwitchBlockOffForEntity disables block of specified type for the
+// service or machine with the given tag. Environment blocks of the
+// same type are left in place.
+func (st *State) SwitchBlockOffForEntity(t BlockType, tag names.Tag) error {
+	if err := st.checkBlockableEntity(tag); err != nil {
+		return errors.Trace(err)
+	}
+	return removeEnvironmentBlock(st, t, tag)
+}
+
+// checkBlockableEntity returns an error if the entity with the given
+// tag cannot be blocked on its own, or does not exist.
+func (st *State) checkBlockableEntity(tag names.Tag) error {
+	switch tag.(type) {
+	case names.ServiceTag, names.MachineTag:
+	default:
+		return errors.NotValidf("blocking %s", names.ReadableString(tag))
+	}
+	_, err := st.FindEntity(tag)
+	return err
 }
 
 // GetBlockForType returns the Block of the specified type for the current environment
@@ -140,11 +172,19 @@ func (st *State) SwitchBlockOff(t BlockType) error {
 //     found -> block, true, nil
 //     error -> nil, false, err
 func (st *State) GetBlockForType(t BlockType) (Block, bool, error) {
+	return st.GetBlockForEntity(t, st.EnvironTag())
+}
+
+// GetBlockForEntity returns the Block of the specified type for the
+// entity with the given tag, as GetBlockForType does for the current
+// environment. Blocks of the environment itself are not returned for
+// other entities.
+func (st *State) GetBlockForEntity(t BlockType, tag names.Tag) (Block, bool, error) {
 	all, closer := st.getCollection(blocksC)
 	defer closer()
 
 	doc := blockDoc{}
-	err := all.Find(bson.D{{"type", t}}).One(&doc)
+	err := all.Find(bson.D{{"type", t}, {"tag", tag.String()}}).One(&doc)
 
 	switch err {
 	case nil:
@@ -174,24 +214,33 @@ func (st *State) AllBlocks() ([]Block, error) {
 }
 
 // setEnvironmentBlock updates the blocks collection with the
-// specified block.
-// Only one instance of each block type can exist in environment.
-func setEnvironmentBlock(st *State, t BlockType, msg string) error {
+// specified block of the entity with the given tag.
+// Only one instance of each block type can exist for an entity.
+func setEnvironmentBlock(st *State, t BlockType, tag names.Tag, msg string) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		_, exists, err := st.GetBlockForType(t)
+		_, exists, err := st.GetBlockForEntity(t, tag)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		// Cannot create blocks of the same type more than once per environment.
+		// Cannot create blocks of the same type more than once per entity.
 		// Cannot update current blocks.
 		if exists {
-			return nil, errors.Errorf("block %v is already ON", t.String())
+			return nil, errors.Errorf("block %v is already ON", blockDescription(st, t, tag))
 		}
-		return createEnvironmentBlockOps(st, t, msg)
+		return createEnvironmentBlockOps(st, t, tag, msg)
 	}
 	return st.run(buildTxn)
 }
 
+// blockDescription returns a description of the block of the given
+// type for the entity with the given tag, for use in error messages.
+func blockDescription(st *State, t BlockType, tag names.Tag) string {
+	if tag == st.EnvironTag() {
+		return t.String()
+	}
+	return fmt.Sprintf("%v for %s", t.String(), names.ReadableString(tag))
+}
+
 // newBlockId returns a sequential block id for this environment.
 func newBlockId(st *State) (string, error) {
 	seq, err := st.sequence("block")
@@ -201,7 +250,7 @@ func newBlockId(st *State) (string, error) {
 	return fmt.Sprint(seq), nil
 }
 
-func createEnvironmentBlockOps(st *State, t BlockType, msg string) ([]txn.Op, error) {
+func createEnvironmentBlockOps(st *State, t BlockType, tag names.Tag, msg string) ([]txn.Op, error) {
 	id, err := newBlockId(st)
 	if err != nil {
 		return nil, errors.Annotatef(err, "getting new block id")
@@ -209,7 +258,7 @@ func createEnvironmentBlockOps(st *State, t BlockType, msg string) ([]txn.Op, er
 	newDoc := blockDoc{
 		DocID:   st.docID(id),
 		EnvUUID: st.EnvironUUID(),
-		Tag:     st.EnvironTag().String(),
+		Tag:     tag.String(),
 		Type:    t,
 		Message: msg,
 	}
@@ -222,17 +271,17 @@ func createEnvironmentBlockOps(st *State, t BlockType, msg string) ([]txn.Op, er
 	return []txn.Op{insertOp}, nil
 }
 
-func removeEnvironmentBlock(st *State, t BlockType) error {
+func removeEnvironmentBlock(st *State, t BlockType, tag names.Tag) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		return removeEnvironmentBlockOps(st, t)
+		return removeEnvironmentBlockOps(st, t, tag)
 	}
 	return st.run(buildTxn)
 }
 
-func removeEnvironmentBlockOps(st *State, t BlockType) ([]txn.Op, error) {
-	tBlock, exists, err := st.GetBlockForType(t)
+func removeEnvironmentBlockOps(st *State, t BlockType, tag names.Tag) ([]txn.Op, error) {
+	tBlock, exists, err := st.GetBlockForEntity(t, tag)
 	if err != nil {
-		return nil, errors.Annotatef(err, "removing block %v", t.String())
+		return nil, errors.Annotatef(err, "removing block %v", blockDescription(st, t, tag))
 	}
 	if exists {
 		return []txn.Op{txn.Op{
@@ -241,5 +290,42 @@ func removeEnvironmentBlockOps(st *State, t BlockType) ([]txn.Op, error) {
 			Remove: true,
 		}}, nil
 	}
-	return nil, errors.Errorf("block %v is already OFF", t.String())
+	return nil, errors.Errorf("block %v is already OFF", blockDescription(st, t, tag))
+}
+
+// hasEntityBlocks reports whether any blocks are in place for the
+// entity with the given tag.
+func hasEntityBlocks(st *State, tag names.Tag) bool {
+	blocks, closer := st.getCollection(blocksC)
+	defer closer()
+	n, err := blocks.Find(bson.D{{"tag", tag.String()}}).Count()
+	if err != nil {
+		logger.Warningf("cannot count blocks of %s: %v", names.ReadableString(tag), err)
+		return true
+	}
+	return n > 0
+}
+
+// cleanupBlocksForRemovedEntity removes the blocks of the removed
+// service or machine with the given tag.
+func (st *State) cleanupBlocksForRemovedEntity(tagString string) error {
+	blocks, closer := st.getCollection(blocksC)
+	defer closer()
+
+	var docs []blockDoc
+	if err := blocks.Find(bson.D{{"tag", tagString}}).All(&docs); err != nil {
+		return errors.Annotatef(err, "cannot get blocks of %q", tagString)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      blocksC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return st.runTransaction(ops)
 }
@@ -159,3 +159,62 @@ func (s *blockSuite) TestConcurrentBlocked(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	assertEnvHasBlock(c, s.State, t, msg)
 }
+
+func (s *blockSuite) TestEntityBlocked(c *gc.C) {
+	service := s.factory.MakeService(c, nil)
+	t := state.ChangeBlock
+	err := s.State.SwitchBlockOnForEntity(t, service.Tag(), "protect the service")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The environment itself is not blocked.
+	s.assertNoTypedBlock(c, t)
+	block, found, err := s.State.GetBlockForEntity(t, service.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsTrue)
+	c.Assert(block.Type(), gc.Equals, t)
+	c.Assert(block.Message(), gc.Equals, "protect the service")
+	tag, err := block.Tag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, service.Tag())
+
+	err = s.State.SwitchBlockOnForEntity(t, service.Tag(), "")
+	c.Assert(err, gc.ErrorMatches, `.*block BlockChange for service mysql is already ON`)
+
+	// Environment blocks of the same type are independent.
+	s.assertSwitchedOn(c, t)
+	err = s.State.SwitchBlockOffForEntity(t, service.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	assertEnvHasBlock(c, s.State, t, "")
+	_, found, err = s.State.GetBlockForEntity(t, service.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+
+	err = s.State.SwitchBlockOffForEntity(t, service.Tag())
+	c.Assert(err, gc.ErrorMatches, `.*block BlockChange for service mysql is already OFF`)
+}
+
+func (s *blockSuite) TestEntityBlockInvalid(c *gc.C) {
+	err := s.State.SwitchBlockOnForEntity(state.ChangeBlock, names.NewUnitTag("mysql/0"), "")
+	c.Assert(err, gc.ErrorMatches, `blocking unit mysql/0 not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.SwitchBlockOnForEntity(state.ChangeBlock, names.NewMachineTag("42"), "")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	assertNoEnvBlock(c, s.State)
+}
+
+func (s *blockSuite) TestEntityBlocksRemovedWithEntity(c *gc.C) {
+	machine := s.factory.MakeMachine(c, nil)
+	err := s.State.SwitchBlockOnForEntity(state.RemoveBlock, machine.Tag(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnForEntity(state.ChangeBlock, machine.Tag(), "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoEnvBlock(c, s.State)
+}
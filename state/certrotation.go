@@ -0,0 +1,249 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const certRotationKey = "certRotation"
+
+// certRotationDoc records the progress of a rotation of the
+// certificates served by the state servers' mongo instances.
+type certRotationDoc struct {
+	Serial       int64    `bson:"serial"`
+	CACert       string   `bson:"cacert"`
+	CAPrivateKey string   `bson:"caprivatekey"`
+	MachineIds   []string `bson:"machineids"`
+	Trusted      []string `bson:"trusted"`
+	Restarted    []string `bson:"restarted"`
+}
+
+// CertRotation describes a rotation of the certificates served by the
+// state servers' mongo instances. Every state server first comes to
+// trust the rotation's CA; then each in turn writes a new certificate
+// issued by that CA and restarts its mongo, so that the replica set
+// keeps a majority of its members throughout.
+type CertRotation struct {
+	doc certRotationDoc
+}
+
+// Serial returns the serial number of the rotation, which increases
+// with every rotation started.
+func (r *CertRotation) Serial() int64 {
+	return r.doc.Serial
+}
+
+// CACert returns the PEM-encoded certificate of the CA that issues the
+// rotated certificates.
+func (r *CertRotation) CACert() string {
+	return r.doc.CACert
+}
+
+// CAPrivateKey returns the PEM-encoded private key of the CA that
+// issues the rotated certificates.
+func (r *CertRotation) CAPrivateKey() string {
+	return r.doc.CAPrivateKey
+}
+
+// MachineIds returns the ids of the state server machines taking part
+// in the rotation, in the order in which they restart.
+func (r *CertRotation) MachineIds() []string {
+	return r.doc.MachineIds
+}
+
+// Trusted returns the ids of the machines that trust the rotation's CA.
+func (r *CertRotation) Trusted() []string {
+	return r.doc.Trusted
+}
+
+// Ready returns whether all the machines taking part in the rotation
+// trust its CA, so that they may start to restart.
+func (r *CertRotation) Ready() bool {
+	trusted := set.NewStrings(r.doc.Trusted...)
+	for _, id := range r.doc.MachineIds {
+		if !trusted.Contains(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Restarted returns the ids of the machines that have restarted with
+// their rotated certificates.
+func (r *CertRotation) Restarted() []string {
+	return r.doc.Restarted
+}
+
+// NextMachineId returns the id of the machine that should restart next,
+// or the empty string if the rotation is complete.
+func (r *CertRotation) NextMachineId() string {
+	restarted := set.NewStrings(r.doc.Restarted...)
+	for _, id := range r.doc.MachineIds {
+		if !restarted.Contains(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+// Done returns whether all the machines taking part in the rotation
+// have restarted.
+func (r *CertRotation) Done() bool {
+	return r.NextMachineId() == ""
+}
+
+// CertRotation returns the most recently started certificate rotation.
+// It returns a NotFound error if no rotation has been started.
+func (st *State) CertRotation() (*CertRotation, error) {
+	stateServers, closer := st.getCollection(stateServersC)
+	defer closer()
+
+	var doc certRotationDoc
+	err := stateServers.FindId(certRotationKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("certificate rotation")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get certificate rotation")
+	}
+	return &CertRotation{doc}, nil
+}
+
+// StartCertRotation starts a rotation of the certificates served by the
+// state servers' mongo instances, to certificates issued by the given
+// CA. It fails if an earlier rotation has not completed.
+func (st *State) StartCertRotation(caCert, caPrivateKey string) (*CertRotation, error) {
+	if caCert == "" || caPrivateKey == "" {
+		return nil, errors.New("CA certificate and private key must be specified")
+	}
+	var doc certRotationDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		info, err := st.StateServerInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineIds := append([]string(nil), info.MachineIds...)
+		sort.Strings(machineIds)
+		doc = certRotationDoc{
+			Serial:       1,
+			CACert:       caCert,
+			CAPrivateKey: caPrivateKey,
+			MachineIds:   machineIds,
+			Trusted:      []string{},
+			Restarted:    []string{},
+		}
+		ops := []txn.Op{{
+			C:      stateServersC,
+			Id:     environGlobalKey,
+			Assert: bson.D{{"machineids", info.MachineIds}},
+		}}
+		existing, err := st.CertRotation()
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      stateServersC,
+				Id:     certRotationKey,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !existing.Done() {
+			return nil, errors.Errorf("certificate rotation %d still in progress", existing.Serial())
+		}
+		doc.Serial = existing.Serial() + 1
+		return append(ops, txn.Op{
+			C:      stateServersC,
+			Id:     certRotationKey,
+			Assert: bson.D{{"serial", existing.Serial()}},
+			Update: bson.D{{"$set", &doc}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Annotate(err, "cannot start certificate rotation")
+	}
+	return &CertRotation{doc}, nil
+}
+
+// SetCertRotationTrusted records that the given machine trusts the CA
+// of the rotation with the given serial.
+func (st *State) SetCertRotationTrusted(serial int64, machineId string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		rotation, err := st.CertRotation()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if rotation.Serial() != serial {
+			return nil, errors.Errorf("certificate rotation %d superseded by %d", serial, rotation.Serial())
+		}
+		if !set.NewStrings(rotation.MachineIds()...).Contains(machineId) {
+			return nil, errors.Errorf("machine %s is not part of certificate rotation %d", machineId, serial)
+		}
+		if set.NewStrings(rotation.Trusted()...).Contains(machineId) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      stateServersC,
+			Id:     certRotationKey,
+			Assert: bson.D{{"serial", serial}},
+			Update: bson.D{{"$addToSet", bson.D{{"trusted", machineId}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot record certificate trust of machine %s", machineId)
+	}
+	return nil
+}
+
+// SetCertRotationRestarted records that the given machine has restarted
+// its mongo with the certificate of the rotation with the given serial.
+// It fails if any machine does not yet trust the rotation's CA, or if
+// the machine is not the next to restart.
+func (st *State) SetCertRotationRestarted(serial int64, machineId string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		rotation, err := st.CertRotation()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if rotation.Serial() != serial {
+			return nil, errors.Errorf("certificate rotation %d superseded by %d", serial, rotation.Serial())
+		}
+		if set.NewStrings(rotation.Restarted()...).Contains(machineId) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if !rotation.Ready() {
+			return nil, errors.Errorf("certificate rotation %d not trusted by all machines", serial)
+		}
+		if next := rotation.NextMachineId(); next != machineId {
+			return nil, errors.Errorf("machine %s is not next to restart (waiting for %q)", machineId, next)
+		}
+		return []txn.Op{{
+			C:  stateServersC,
+			Id: certRotationKey,
+			Assert: bson.D{
+				{"serial", serial},
+				{"trusted", rotation.Trusted()},
+				{"restarted", rotation.Restarted()},
+			},
+			Update: bson.D{{"$push", bson.D{{"restarted", machineId}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot record certificate rotation of machine %s", machineId)
+	}
+	return nil
+}
+
+// WatchCertRotation returns a watcher that notifies of changes to the
+// certificate rotation.
+func (st *State) WatchCertRotation() NotifyWatcher {
+	return newEntityWatcher(st, stateServersC, certRotationKey)
+}
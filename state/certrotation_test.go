@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type CertRotationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CertRotationSuite{})
+
+func (s *CertRotationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	_, err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CertRotationSuite) TestNoRotation(c *gc.C) {
+	_, err := s.State.CertRotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CertRotationSuite) TestStartCertRotation(c *gc.C) {
+	rotation, err := s.State.StartCertRotation("ca-cert", "ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Serial(), gc.Equals, int64(1))
+	c.Assert(rotation.CACert(), gc.Equals, "ca-cert")
+	c.Assert(rotation.CAPrivateKey(), gc.Equals, "ca-key")
+	c.Assert(rotation.MachineIds(), jc.DeepEquals, []string{"0", "1", "2"})
+	c.Assert(rotation.Ready(), jc.IsFalse)
+	c.Assert(rotation.NextMachineId(), gc.Equals, "0")
+
+	rotation, err = s.State.CertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Serial(), gc.Equals, int64(1))
+	c.Assert(rotation.MachineIds(), jc.DeepEquals, []string{"0", "1", "2"})
+	c.Assert(rotation.Trusted(), gc.HasLen, 0)
+	c.Assert(rotation.Restarted(), gc.HasLen, 0)
+}
+
+func (s *CertRotationSuite) TestStartCertRotationInProgress(c *gc.C) {
+	_, err := s.State.StartCertRotation("ca-cert", "ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartCertRotation("ca-cert", "ca-key")
+	c.Assert(err, gc.ErrorMatches, "cannot start certificate rotation: certificate rotation 1 still in progress")
+}
+
+func (s *CertRotationSuite) TestStartCertRotationMissingCA(c *gc.C) {
+	_, err := s.State.StartCertRotation("ca-cert", "")
+	c.Assert(err, gc.ErrorMatches, "CA certificate and private key must be specified")
+}
+
+func (s *CertRotationSuite) TestRollingRestart(c *gc.C) {
+	rotation, err := s.State.StartCertRotation("ca-cert", "ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	serial := rotation.Serial()
+
+	// No machine may restart until all trust the new CA.
+	err = s.State.SetCertRotationTrusted(serial, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetCertRotationRestarted(serial, "0")
+	c.Assert(err, gc.ErrorMatches, `cannot record certificate rotation of machine 0: certificate rotation 1 not trusted by all machines`)
+	for _, id := range []string{"0", "1", "2"} {
+		err = s.State.SetCertRotationTrusted(serial, id)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = s.State.SetCertRotationTrusted(serial, "3")
+	c.Assert(err, gc.ErrorMatches, `cannot record certificate trust of machine 3: machine 3 is not part of certificate rotation 1`)
+
+	// Machines restart one at a time, in order.
+	err = s.State.SetCertRotationRestarted(serial, "1")
+	c.Assert(err, gc.ErrorMatches, `cannot record certificate rotation of machine 1: machine 1 is not next to restart \(waiting for "0"\)`)
+	for _, id := range []string{"0", "1", "2"} {
+		rotation, err = s.State.CertRotation()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(rotation.Done(), jc.IsFalse)
+		c.Assert(rotation.NextMachineId(), gc.Equals, id)
+		err = s.State.SetCertRotationRestarted(serial, id)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// Recording a restart again is not an error.
+	err = s.State.SetCertRotationRestarted(serial, "0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	rotation, err = s.State.CertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Done(), jc.IsTrue)
+	c.Assert(rotation.Restarted(), jc.DeepEquals, []string{"0", "1", "2"})
+
+	// Once done, a new rotation may be started.
+	rotation, err = s.State.StartCertRotation("new-ca-cert", "new-ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Serial(), gc.Equals, int64(2))
+	c.Assert(rotation.Trusted(), gc.HasLen, 0)
+	c.Assert(rotation.Restarted(), gc.HasLen, 0)
+
+	err = s.State.SetCertRotationTrusted(serial, "0")
+	c.Assert(err, gc.ErrorMatches, `cannot record certificate trust of machine 0: certificate rotation 1 superseded by 2`)
+}
+
+func (s *CertRotationSuite) TestWatchCertRotation(c *gc.C) {
+	w := s.State.WatchCertRotation()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	rotation, err := s.State.StartCertRotation("ca-cert", "ca-key")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.SetCertRotationTrusted(rotation.Serial(), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
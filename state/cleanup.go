@@ -24,6 +24,8 @@ const (
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
 	cleanupAttachmentsForDyingStorage  cleanupKind = "storageAttachments"
+	cleanupResourcesForRemovedService  cleanupKind = "resources"
+	cleanupBlocksForRemovedEntity      cleanupKind = "blocks"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -88,6 +90,10 @@ func (st *State) Cleanup() error {
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
 		case cleanupAttachmentsForDyingStorage:
 			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
+		case cleanupResourcesForRemovedService:
+			err = st.cleanupResourcesForRemovedService(doc.Prefix)
+		case cleanupBlocksForRemovedEntity:
+			err = st.cleanupBlocksForRemovedEntity(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -0,0 +1,246 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// CloudImageMetadataAttributes describes the kind of cloud image
+// that a piece of custom image metadata applies to.
+type CloudImageMetadataAttributes struct {
+	// Stream is the image stream, e.g. "released" or "daily".
+	Stream string
+
+	// Region is the name of the cloud region the image lives in.
+	Region string
+
+	// Series is the OS series of the image, e.g. "trusty".
+	Series string
+
+	// Arch is the architecture of the image, e.g. "amd64".
+	Arch string
+
+	// VirtType is the virtualisation type of the image, e.g. "hvm".
+	VirtType string
+
+	// RootStorageType is the type of root storage of the image,
+	// e.g. "ebs".
+	RootStorageType string
+}
+
+// key returns a string that identifies the attributes uniquely, for
+// use in the ids of documents.
+func (a CloudImageMetadataAttributes) key() string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s:%s",
+		a.Stream, a.Region, a.Series, a.Arch, a.VirtType, a.RootStorageType,
+	)
+}
+
+// CloudImageMetadata holds the id of a cloud image along with the
+// attributes that describe it.
+type CloudImageMetadata struct {
+	CloudImageMetadataAttributes
+
+	// ImageId is the provider-specific id of the image.
+	ImageId string
+}
+
+// CloudImageMetadataFilter holds the criteria used to select custom
+// image metadata. Empty fields match all metadata.
+type CloudImageMetadataFilter struct {
+	Region          string
+	Series          []string
+	Arches          []string
+	Stream          string
+	VirtType        string
+	RootStorageType string
+}
+
+// query returns the mongo query that selects the metadata matching
+// the filter.
+func (f CloudImageMetadataFilter) query() bson.D {
+	var query bson.D
+	if f.Region != "" {
+		query = append(query, bson.DocElem{"region", f.Region})
+	}
+	if len(f.Series) > 0 {
+		query = append(query, bson.DocElem{"series", bson.D{{"$in", f.Series}}})
+	}
+	if len(f.Arches) > 0 {
+		query = append(query, bson.DocElem{"arch", bson.D{{"$in", f.Arches}}})
+	}
+	if f.Stream != "" {
+		query = append(query, bson.DocElem{"stream", f.Stream})
+	}
+	if f.VirtType != "" {
+		query = append(query, bson.DocElem{"virt-type", f.VirtType})
+	}
+	if f.RootStorageType != "" {
+		query = append(query, bson.DocElem{"root-storage-type", f.RootStorageType})
+	}
+	return query
+}
+
+// cloudImageMetadataDoc records a piece of custom image metadata. The
+// id of the document is derived from the attributes of the image, so
+// that there is at most one image for each kind.
+type cloudImageMetadataDoc struct {
+	DocID           string `bson:"_id"`
+	EnvUUID         string `bson:"env-uuid"`
+	Stream          string `bson:"stream"`
+	Region          string `bson:"region"`
+	Series          string `bson:"series"`
+	Arch            string `bson:"arch"`
+	VirtType        string `bson:"virt-type,omitempty"`
+	RootStorageType string `bson:"root-storage-type,omitempty"`
+	ImageId         string `bson:"image-id"`
+}
+
+func (doc *cloudImageMetadataDoc) metadata() CloudImageMetadata {
+	return CloudImageMetadata{
+		CloudImageMetadataAttributes: CloudImageMetadataAttributes{
+			Stream:          doc.Stream,
+			Region:          doc.Region,
+			Series:          doc.Series,
+			Arch:            doc.Arch,
+			VirtType:        doc.VirtType,
+			RootStorageType: doc.RootStorageType,
+		},
+		ImageId: doc.ImageId,
+	}
+}
+
+func validateCloudImageMetadata(m CloudImageMetadata) error {
+	switch {
+	case m.ImageId == "":
+		return errors.NotValidf("metadata without image id")
+	case m.Region == "":
+		return errors.NotValidf("metadata for image %q without region", m.ImageId)
+	case m.Series == "":
+		return errors.NotValidf("metadata for image %q without series", m.ImageId)
+	case m.Arch == "":
+		return errors.NotValidf("metadata for image %q without architecture", m.ImageId)
+	}
+	return nil
+}
+
+// SaveCloudImageMetadata records the given custom image metadata. If
+// metadata was recorded before for an image with the same attributes,
+// its image id is replaced. Metadata without a stream is recorded for
+// the "released" stream.
+func (st *State) SaveCloudImageMetadata(metadata []CloudImageMetadata) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot save cloud image metadata")
+	metadata = append([]CloudImageMetadata(nil), metadata...)
+	for i, m := range metadata {
+		if err := validateCloudImageMetadata(m); err != nil {
+			return errors.Trace(err)
+		}
+		if m.Stream == "" {
+			metadata[i].Stream = "released"
+		}
+	}
+	images, closer := st.getCollection(cloudimagemetadataC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var ops []txn.Op
+		for _, m := range metadata {
+			key := m.key()
+			var existing cloudImageMetadataDoc
+			err := images.FindId(key).One(&existing)
+			switch {
+			case err == nil && existing.ImageId == m.ImageId:
+				continue
+			case err == nil:
+				ops = append(ops, txn.Op{
+					C:      cloudimagemetadataC,
+					Id:     st.docID(key),
+					Assert: bson.D{{"image-id", existing.ImageId}},
+					Update: bson.D{{"$set", bson.D{{"image-id", m.ImageId}}}},
+				})
+				continue
+			case err != mgo.ErrNotFound:
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, txn.Op{
+				C:      cloudimagemetadataC,
+				Id:     st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: &cloudImageMetadataDoc{
+					DocID:           st.docID(key),
+					EnvUUID:         st.EnvironUUID(),
+					Stream:          m.Stream,
+					Region:          m.Region,
+					Series:          m.Series,
+					Arch:            m.Arch,
+					VirtType:        m.VirtType,
+					RootStorageType: m.RootStorageType,
+					ImageId:         m.ImageId,
+				},
+			})
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// FindCloudImageMetadata returns the custom image metadata that
+// matches the given filter.
+func (st *State) FindCloudImageMetadata(filter CloudImageMetadataFilter) ([]CloudImageMetadata, error) {
+	images, closer := st.getCollection(cloudimagemetadataC)
+	defer closer()
+
+	var docs []cloudImageMetadataDoc
+	if err := images.Find(filter.query()).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot find cloud image metadata")
+	}
+	metadata := make([]CloudImageMetadata, len(docs))
+	for i, doc := range docs {
+		metadata[i] = doc.metadata()
+	}
+	return metadata, nil
+}
+
+// DeleteCloudImageMetadata removes all the custom image metadata
+// recorded for the image with the given id. It returns an error
+// satisfying errors.IsNotFound if there is none.
+func (st *State) DeleteCloudImageMetadata(imageId string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot delete cloud image metadata for image %q", imageId)
+	images, closer := st.getCollection(cloudimagemetadataC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var docs []cloudImageMetadataDoc
+		if err := images.Find(bson.D{{"image-id", imageId}}).All(&docs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(docs) == 0 {
+			if attempt == 0 {
+				return nil, errors.NotFoundf("image metadata")
+			}
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := make([]txn.Op, len(docs))
+		for i, doc := range docs {
+			ops[i] = txn.Op{
+				C:      cloudimagemetadataC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Remove: true,
+			}
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type CloudImageMetadataSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CloudImageMetadataSuite{})
+
+func cloudImageMetadata(imageId, region, series, arch string) state.CloudImageMetadata {
+	return state.CloudImageMetadata{
+		CloudImageMetadataAttributes: state.CloudImageMetadataAttributes{
+			Stream:          "released",
+			Region:          region,
+			Series:          series,
+			Arch:            arch,
+			VirtType:        "hvm",
+			RootStorageType: "ebs",
+		},
+		ImageId: imageId,
+	}
+}
+
+func (s *CloudImageMetadataSuite) TestFindCloudImageMetadataNone(c *gc.C) {
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 0)
+}
+
+func (s *CloudImageMetadataSuite) TestSaveCloudImageMetadata(c *gc.C) {
+	image1 := cloudImageMetadata("ami-1", "us-east-1", "trusty", "amd64")
+	image2 := cloudImageMetadata("ami-2", "us-east-1", "precise", "amd64")
+	image3 := cloudImageMetadata("ami-3", "eu-west-1", "trusty", "i386")
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image1, image2, image3})
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.SameContents, []state.CloudImageMetadata{image1, image2, image3})
+
+	metadata, err = s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{
+		Region: "us-east-1",
+		Series: []string{"trusty", "precise"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.SameContents, []state.CloudImageMetadata{image1, image2})
+
+	metadata, err = s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{
+		Arches: []string{"i386"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, []state.CloudImageMetadata{image3})
+}
+
+func (s *CloudImageMetadataSuite) TestSaveCloudImageMetadataReplacesImage(c *gc.C) {
+	image := cloudImageMetadata("ami-1", "us-east-1", "trusty", "amd64")
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Saving the same metadata again is a no-op.
+	err = s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, jc.ErrorIsNil)
+
+	image.ImageId = "ami-2"
+	err = s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, jc.ErrorIsNil)
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, []state.CloudImageMetadata{image})
+}
+
+func (s *CloudImageMetadataSuite) TestSaveCloudImageMetadataDefaultStream(c *gc.C) {
+	image := cloudImageMetadata("ami-1", "us-east-1", "trusty", "amd64")
+	image.Stream = ""
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, jc.ErrorIsNil)
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{
+		Stream: "released",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Assert(metadata[0].ImageId, gc.Equals, "ami-1")
+}
+
+func (s *CloudImageMetadataSuite) TestSaveCloudImageMetadataInvalid(c *gc.C) {
+	image := cloudImageMetadata("ami-1", "", "trusty", "amd64")
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, gc.ErrorMatches, `cannot save cloud image metadata: metadata for image "ami-1" without region not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	image = cloudImageMetadata("", "us-east-1", "trusty", "amd64")
+	err = s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image})
+	c.Assert(err, gc.ErrorMatches, `cannot save cloud image metadata: metadata without image id not valid`)
+}
+
+func (s *CloudImageMetadataSuite) TestDeleteCloudImageMetadata(c *gc.C) {
+	image1 := cloudImageMetadata("ami-1", "us-east-1", "trusty", "amd64")
+	image2 := cloudImageMetadata("ami-1", "us-east-1", "trusty", "i386")
+	image3 := cloudImageMetadata("ami-2", "us-east-1", "precise", "amd64")
+	err := s.State.SaveCloudImageMetadata([]state.CloudImageMetadata{image1, image2, image3})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.DeleteCloudImageMetadata("ami-1")
+	c.Assert(err, jc.ErrorIsNil)
+	metadata, err := s.State.FindCloudImageMetadata(state.CloudImageMetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, []state.CloudImageMetadata{image3})
+
+	err = s.State.DeleteCloudImageMetadata("ami-1")
+	c.Assert(err, gc.ErrorMatches, `cannot delete cloud image metadata for image "ami-1": image metadata not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -60,12 +60,18 @@ type stateCollection interface {
 // these collections.
 var multiEnvCollections = set.NewStrings(
 	actionNotificationsC,
+	actionOutputC,
+	actionQueuesC,
+	actionSchedulesC,
 	actionsC,
+	agentHealthC,
 	annotationsC,
+	availabilityZonesC,
 	blockDevicesC,
 	blocksC,
 	charmsC,
 	cleanupsC,
+	cloudimagemetadataC,
 	constraintsC,
 	containerRefsC,
 	envUsersC,
@@ -74,6 +80,7 @@ var multiEnvCollections = set.NewStrings(
 	instanceDataC,
 	ipaddressesC,
 	machinesC,
+	macaroonsC,
 	meterStatusC,
 	minUnitsC,
 	networkInterfacesC,
@@ -82,17 +89,25 @@ var multiEnvCollections = set.NewStrings(
 	rebootC,
 	relationScopesC,
 	relationsC,
+	remoteServicesC,
 	requestedNetworksC,
+	scopedCredentialsC,
 	sequenceC,
+	serviceOffersC,
 	servicesC,
 	settingsC,
 	settingsrefsC,
+	spacesC,
 	statusesC,
+	statusesHistoryC,
 	storageAttachmentsC,
 	storageConstraintsC,
 	storageInstancesC,
 	subnetsC,
 	unitsC,
+	upgradePlansC,
+	upgradeSeriesLocksC,
+	userSSHKeysC,
 	volumesC,
 	volumeAttachmentsC,
 )
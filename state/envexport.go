@@ -0,0 +1,365 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+)
+
+// envDescriptionVersion is the version of the environment description
+// format written by Export and understood by Import.
+const envDescriptionVersion = 1
+
+// EnvironmentDescription is a complete description of an environment,
+// as produced by Environment.Export and consumed by State.Import. It
+// can be serialized with bson.Marshal.
+//
+// Charm archives are held outside the environment's documents and are
+// not included; they must be copied to the importing state server
+// separately.
+type EnvironmentDescription struct {
+	// Version holds the version of the description format.
+	Version int `bson:"version"`
+
+	// Owner holds the tag of the user that owns the environment.
+	Owner string `bson:"owner"`
+
+	// Config holds the environment's configuration.
+	Config map[string]interface{} `bson:"config"`
+
+	// Constraints holds the environment's constraints.
+	Constraints constraints.Value `bson:"constraints"`
+
+	// Collections holds the environment's documents, keyed by the
+	// name of the collection holding them. The documents' ids are
+	// local to the environment, and their environment UUID and
+	// transaction fields are omitted.
+	Collections map[string][]bson.M `bson:"collections"`
+}
+
+// exportedCollections holds the collections whose documents make up an
+// environment's description. Every environment collection must be in
+// either exportedCollections or unexportedCollections.
+var exportedCollections = set.NewStrings(
+	annotationsC,
+	blockDevicesC,
+	blocksC,
+	charmsC,
+	cloudimagemetadataC,
+	constraintsC,
+	containerRefsC,
+	envUsersC,
+	filesystemAttachmentsC,
+	filesystemsC,
+	instanceDataC,
+	ipaddressesC,
+	machinesC,
+	meterStatusC,
+	minUnitsC,
+	networkInterfacesC,
+	networksC,
+	openedPortsC,
+	relationScopesC,
+	relationsC,
+	remoteServicesC,
+	requestedNetworksC,
+	sequenceC,
+	serviceOffersC,
+	servicesC,
+	settingsC,
+	settingsrefsC,
+	spacesC,
+	statusesC,
+	storageAttachmentsC,
+	storageConstraintsC,
+	storageInstancesC,
+	subnetsC,
+	unitsC,
+	userSSHKeysC,
+	volumeAttachmentsC,
+	volumesC,
+)
+
+// unexportedCollections holds the environment collections that are not
+// moved with the environment. They hold transient data, such as pending
+// actions and cleanups, or data that is local to the state server, such
+// as its macaroon keys and the credentials it hands out.
+var unexportedCollections = set.NewStrings(
+	actionNotificationsC,
+	actionOutputC,
+	actionQueuesC,
+	actionSchedulesC,
+	actionsC,
+	agentHealthC,
+	availabilityZonesC,
+	cleanupsC,
+	macaroonsC,
+	rebootC,
+	scopedCredentialsC,
+	statusesHistoryC,
+	upgradePlansC,
+	upgradeSeriesLocksC,
+)
+
+// Export returns a complete description of the environment: its
+// machines, services, units, relations, settings and storage.
+//
+// The environment should not be changing while it is exported, as the
+// description is not read in a single transaction.
+func (e *Environment) Export() (*EnvironmentDescription, error) {
+	st := e.st
+	if st.EnvironUUID() != e.UUID() {
+		var err error
+		st, err = e.st.ForEnviron(e.EnvironTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer st.Close()
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot export environment config")
+	}
+	cons, err := st.EnvironConstraints()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot export environment constraints")
+	}
+	desc := &EnvironmentDescription{
+		Version:     envDescriptionVersion,
+		Owner:       e.Owner().String(),
+		Config:      cfg.AllAttrs(),
+		Constraints: cons,
+		Collections: make(map[string][]bson.M),
+	}
+	ownerID := strings.ToLower(e.Owner().Username())
+	for _, name := range exportedCollections.SortedValues() {
+		docs, err := exportCollection(st, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot export %s", name)
+		}
+		var exported []bson.M
+		for _, doc := range docs {
+			id := doc["_id"]
+			switch {
+			case (name == settingsC || name == constraintsC) && id == environGlobalKey:
+				// The environment's config and constraints are
+				// described separately.
+				continue
+			case name == envUsersC && id == ownerID:
+				// The owner is added when the environment is
+				// created.
+				continue
+			}
+			exported = append(exported, doc)
+		}
+		if len(exported) > 0 {
+			desc.Collections[name] = exported
+		}
+	}
+	return desc, nil
+}
+
+// exportCollection returns the documents of the state's environment held
+// in the named collection, with local ids and without their environment
+// UUID and transaction fields.
+func exportCollection(st *State, name string) ([]bson.M, error) {
+	coll, closer := st.getCollection(name)
+	defer closer()
+
+	var docs []bson.M
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range docs {
+		delete(doc, "env-uuid")
+		delete(doc, "txn-revno")
+		delete(doc, "txn-queue")
+		if id, ok := doc["_id"].(string); ok {
+			doc["_id"] = st.localID(id)
+		}
+	}
+	return docs, nil
+}
+
+// importBatchOps and importBatchBytes bound the number of documents,
+// and their total size, inserted by each transaction of an import, so
+// that no transaction exceeds Mongo's document size limit.
+var (
+	importBatchOps   = 500
+	importBatchBytes = 4 * 1024 * 1024
+)
+
+// Import creates an environment on the state server from a description
+// produced by Environment.Export, typically on another state server, and
+// returns it along with a State for it. The environment keeps the UUID
+// recorded in its config; the import fails if an environment with that
+// UUID, or with the same name and owner, already exists.
+//
+// The environment is created first, marked as importing, and its
+// documents are then inserted in batches; the mark is only removed once
+// all of them are in place. If the import fails after the environment
+// is created, its documents are removed again.
+func (st *State) Import(desc *EnvironmentDescription) (_ *Environment, _ *State, err error) {
+	if desc.Version != envDescriptionVersion {
+		return nil, nil, errors.NotSupportedf("environment description version %d", desc.Version)
+	}
+	owner, err := names.ParseUserTag(desc.Owner)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+	if owner.IsLocal() {
+		if _, err := st.User(owner); err != nil {
+			return nil, nil, errors.Annotate(err, "cannot import environment")
+		}
+	}
+	cfg, err := config.New(config.NoDefaults, desc.Config)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment config")
+	}
+	uuid, ok := cfg.UUID()
+	if !ok {
+		return nil, nil, errors.Errorf("environment uuid was not supplied")
+	}
+	ssEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "could not load state server environment")
+	}
+	batches, err := importDocsBatches(desc.Collections)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+
+	newState, err := st.ForEnviron(names.NewEnvironTag(uuid))
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "could not create state for imported environment")
+	}
+	defer func() {
+		if err != nil {
+			newState.Close()
+		}
+	}()
+
+	ops, err := newState.envSetupOps(cfg, uuid, ssEnv.UUID(), owner)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+	for i, op := range ops {
+		switch {
+		case op.C == constraintsC && op.Id == environGlobalKey:
+			ops[i] = createConstraintsOp(newState, environGlobalKey, desc.Constraints)
+		case op.C == environmentsC:
+			if doc, ok := op.Insert.(*environmentDoc); ok {
+				doc.Importing = true
+			}
+		}
+	}
+	if err := newState.runTransactionNoEnvAliveAssert(ops); err == txn.ErrAborted {
+		return nil, nil, errors.AlreadyExistsf("environment %q (%s) for %s", cfg.Name(), uuid, owner.Username())
+	} else if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+
+	if err := newState.importDocs(batches); err != nil {
+		if cleanupErr := newState.RemoveAllEnvironDocs(); cleanupErr != nil {
+			logger.Errorf("cannot remove partially imported environment %s: %v", uuid, cleanupErr)
+		}
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+
+	newEnv, err := newState.Environment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return newEnv, newState, nil
+}
+
+// importDocs inserts the given batches of documents into the state's
+// environment, one transaction per batch, and then marks the
+// environment as imported.
+func (st *State) importDocs(batches [][]txn.Op) error {
+	for i, ops := range batches {
+		if err := st.runTransaction(ops); err != nil {
+			return errors.Annotatef(err, "cannot insert batch %d of %d", i+1, len(batches))
+		}
+	}
+	ops := []txn.Op{{
+		C:      environmentsC,
+		Id:     st.EnvironUUID(),
+		Assert: bson.D{{"importing", true}},
+		Update: bson.D{{"$unset", bson.D{{"importing", nil}}}},
+	}}
+	return errors.Annotate(st.runTransaction(ops), "cannot complete import")
+}
+
+// importDocsBatches returns the operations that insert the described
+// documents into an environment, split into batches small enough to be
+// run in a single transaction each.
+func importDocsBatches(collections map[string][]bson.M) ([][]txn.Op, error) {
+	ops, err := importDocsOps(collections)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var batches [][]txn.Op
+	var batch []txn.Op
+	size := 0
+	for _, op := range ops {
+		data, err := bson.Marshal(op.Insert)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot marshal %s document %v", op.C, op.Id)
+		}
+		if len(batch) > 0 && (len(batch) >= importBatchOps || size+len(data) > importBatchBytes) {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+		batch = append(batch, op)
+		size += len(data)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// importDocsOps returns the operations that insert the described
+// documents into the environment of the running transaction.
+func importDocsOps(collections map[string][]bson.M) ([]txn.Op, error) {
+	collNames := make([]string, 0, len(collections))
+	for name := range collections {
+		if !exportedCollections.Contains(name) {
+			return nil, errors.NotValidf("collection %q", name)
+		}
+		collNames = append(collNames, name)
+	}
+	sort.Strings(collNames)
+	var ops []txn.Op
+	for _, name := range collNames {
+		for _, doc := range collections[name] {
+			id, ok := doc["_id"]
+			if !ok {
+				return nil, errors.NotValidf("%s document without id", name)
+			}
+			insert := make(bson.M, len(doc))
+			for key, value := range doc {
+				insert[key] = value
+			}
+			ops = append(ops, txn.Op{
+				C:      name,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: insert,
+			})
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type EnvExportSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&EnvExportSuite{})
+
+func (s *EnvExportSuite) makeEnvironment(c *gc.C) {
+	rel := s.factory.MakeRelation(c, nil)
+	for _, ep := range rel.Endpoints() {
+		svc, err := s.State.Service(ep.ServiceName)
+		c.Assert(err, jc.ErrorIsNil)
+		s.factory.MakeUnit(c, &factory.UnitParams{Service: svc})
+	}
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "exported"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EnvExportSuite) export(c *gc.C) *state.EnvironmentDescription {
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	desc, err := env.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	return desc
+}
+
+func (s *EnvExportSuite) TestExport(c *gc.C) {
+	s.makeEnvironment(c)
+	desc := s.export(c)
+
+	c.Assert(desc.Owner, gc.Equals, s.Owner.String())
+	c.Assert(desc.Config["uuid"], gc.Equals, s.State.EnvironUUID())
+	c.Assert(desc.Constraints, jc.DeepEquals, constraints.MustParse("mem=4G"))
+	c.Assert(desc.Collections["machines"], gc.HasLen, 2)
+	c.Assert(desc.Collections["services"], gc.HasLen, 2)
+	c.Assert(desc.Collections["units"], gc.HasLen, 2)
+	c.Assert(desc.Collections["relations"], gc.HasLen, 1)
+	c.Assert(desc.Collections["spaces"], gc.HasLen, 1)
+	for name, docs := range desc.Collections {
+		for _, doc := range docs {
+			c.Check(doc["env-uuid"], gc.IsNil, gc.Commentf("%s %v", name, doc["_id"]))
+			c.Check(doc["txn-revno"], gc.IsNil, gc.Commentf("%s %v", name, doc["_id"]))
+			if id, ok := doc["_id"].(string); ok {
+				c.Check(strings.Contains(id, s.State.EnvironUUID()), jc.IsFalse)
+			}
+		}
+	}
+	// The owner is described by the Owner field alone; the service
+	// creators made by the factory are ordinary environment users.
+	c.Assert(desc.Collections["envusers"], gc.HasLen, 2)
+	for _, doc := range desc.Collections["envusers"] {
+		c.Check(doc["user"], gc.Not(gc.Equals), s.Owner.Username())
+	}
+}
+
+func (s *EnvExportSuite) TestAllCollectionsClassified(c *gc.C) {
+	// Collections added to the environment must be either exported
+	// or explicitly left out.
+	for _, name := range state.MultiEnvCollections.SortedValues() {
+		exported := state.ExportedCollections.Contains(name)
+		unexported := state.UnexportedCollections.Contains(name)
+		c.Check(exported || unexported, jc.IsTrue, gc.Commentf("collection %q is neither exported nor excluded", name))
+		c.Check(exported && unexported, jc.IsFalse, gc.Commentf("collection %q is both exported and excluded", name))
+	}
+	c.Check(state.ExportedCollections.Difference(state.MultiEnvCollections).SortedValues(), gc.HasLen, 0)
+	c.Check(state.UnexportedCollections.Difference(state.MultiEnvCollections).SortedValues(), gc.HasLen, 0)
+}
+
+func (s *EnvExportSuite) TestImport(c *gc.C) {
+	s.makeEnvironment(c)
+	desc := s.export(c)
+
+	// Importing under a new UUID and name allows the description to
+	// be imported alongside the original environment.
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+
+	// Round trip the description through its serialized form.
+	data, err := bson.Marshal(desc)
+	c.Assert(err, jc.ErrorIsNil)
+	var imported state.EnvironmentDescription
+	err = bson.Unmarshal(data, &imported)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env, st, err := s.State.Import(&imported)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	c.Assert(env.UUID(), gc.Equals, uuid.String())
+	c.Assert(env.Name(), gc.Equals, "imported")
+	c.Assert(env.Owner(), gc.Equals, s.Owner)
+
+	cons, err := st.EnvironConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	origMachines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err := st.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, len(origMachines))
+	for i, m := range machines {
+		c.Check(m.Id(), gc.Equals, origMachines[i].Id())
+		c.Check(m.Series(), gc.Equals, origMachines[i].Series())
+	}
+
+	wordpress, err := st.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := wordpress.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "exported")
+	units, err := wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	machineId, err := units[0].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	relations, err := st.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 1)
+
+	// Sequences are carried over, so new entities do not reuse ids.
+	m, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, orig := range origMachines {
+		c.Check(m.Id(), gc.Not(gc.Equals), orig.Id())
+	}
+}
+
+func (s *EnvExportSuite) TestImportInBatches(c *gc.C) {
+	s.makeEnvironment(c)
+	for i := 0; i < 10; i++ {
+		s.factory.MakeMachine(c, nil)
+	}
+	desc := s.export(c)
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+	s.PatchValue(state.ImportBatchOps, 3)
+
+	env, st, err := s.State.Import(desc)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	c.Assert(env.Importing(), jc.IsFalse)
+
+	origMachines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err := st.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, len(origMachines))
+	wordpress, err := st.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *EnvExportSuite) TestImportFailureRemovesEnvironment(c *gc.C) {
+	s.makeEnvironment(c)
+	desc := s.export(c)
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+	// The last batch inserts a unit already inserted by an earlier one.
+	units := desc.Collections["units"]
+	desc.Collections["units"] = append(units, units[0])
+	s.PatchValue(state.ImportBatchOps, 1)
+
+	_, _, err = s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment: cannot insert batch \d+ of \d+: .*`)
+	_, err = s.State.GetEnvironment(names.NewEnvironTag(uuid.String()))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvExportSuite) TestImportExistingEnvironment(c *gc.C) {
+	desc := s.export(c)
+	_, _, err := s.State.Import(desc)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *EnvExportSuite) TestImportUnknownCollection(c *gc.C) {
+	desc := s.export(c)
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	desc.Config["uuid"] = uuid.String()
+	desc.Config["name"] = "imported"
+	desc.Collections["actions"] = []bson.M{{"_id": "foo"}}
+
+	_, _, err = s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment: collection "actions" not valid`)
+	_, err = s.State.GetEnvironment(names.NewEnvironTag(uuid.String()))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvExportSuite) TestImportUnsupportedVersion(c *gc.C) {
+	desc := s.export(c)
+	desc.Version = 99
+	_, _, err := s.State.Import(desc)
+	c.Assert(err, gc.ErrorMatches, "environment description version 99 not supported")
+}
@@ -32,6 +32,10 @@ type environmentDoc struct {
 	Life       Life
 	Owner      string `bson:"owner"`
 	ServerUUID string `bson:"server-uuid"`
+
+	// Importing is set while the environment's documents are being
+	// imported by State.Import.
+	Importing bool `bson:"importing,omitempty"`
 }
 
 // StateServerEnvironment returns the environment that was bootstrapped.
@@ -192,6 +196,12 @@ func (e *Environment) Life() Life {
 	return e.doc.Life
 }
 
+// Importing returns whether the environment is still being imported,
+// in which case its documents are not all in place.
+func (e *Environment) Importing() bool {
+	return e.doc.Importing
+}
+
 // Owner returns tag representing the owner of the environment.
 // The owner is the user that created the environment.
 func (e *Environment) Owner() names.UserTag {
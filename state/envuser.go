@@ -32,6 +32,9 @@ type envUserDoc struct {
 	CreatedBy      string     `bson:"createdby"`
 	DateCreated    time.Time  `bson:"datecreated"`
 	LastConnection *time.Time `bson:"lastconnection"`
+	// Access is empty for environment users created before access
+	// levels were introduced; they have write access.
+	Access UserAccess `bson:"access,omitempty"`
 }
 
 // ID returns the ID of the environment user.
@@ -80,6 +83,40 @@ func (e *EnvironmentUser) LastConnection() *time.Time {
 	return &result
 }
 
+// Access returns the environment user's access level for the
+// environment.
+func (e *EnvironmentUser) Access() UserAccess {
+	if e.doc.Access == "" {
+		return UserAccessWrite
+	}
+	return e.doc.Access
+}
+
+// IsReadOnly returns whether the environment user may only make API
+// calls that do not change the environment.
+func (e *EnvironmentUser) IsReadOnly() bool {
+	return e.Access() == UserAccessRead
+}
+
+// SetAccess changes the environment user's access level for the
+// environment.
+func (e *EnvironmentUser) SetAccess(access UserAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.ID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	if err := e.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set access for envuser %q", e.ID())
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // UpdateLastConnection updates the last connection time of the environment user.
 func (e *EnvironmentUser) UpdateLastConnection() error {
 	timestamp := nowToTheSecond()
@@ -116,6 +153,15 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 
 // AddEnvironmentUser adds a new user to the database.
 func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+	return st.AddEnvironmentUserWithAccess(user, createdBy, displayName, UserAccessWrite)
+}
+
+// AddEnvironmentUserWithAccess adds a new user with the given access
+// level for the environment to the database.
+func (st *State) AddEnvironmentUserWithAccess(user, createdBy names.UserTag, displayName string, access UserAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -136,6 +182,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 
 	envuuid := st.EnvironUUID()
 	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName)
+	doc.Access = access
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Username())
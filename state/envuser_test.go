@@ -50,6 +50,38 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *EnvUserSuite) TestAddEnvironmentUserWithAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "auditor", NoEnvUser: true})
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	envUser, err := s.State.AddEnvironmentUserWithAccess(user.UserTag(), createdBy.UserTag(), "", state.UserAccessRead)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.UserAccessRead)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+
+	_, err = s.State.AddEnvironmentUserWithAccess(createdBy.UserTag(), createdBy.UserTag(), "", "admin")
+	c.Assert(err, gc.ErrorMatches, `user access "admin" not valid`)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.factory.MakeEnvUser(c, nil)
+	c.Assert(envUser.Access(), gc.Equals, state.UserAccessWrite)
+
+	err := envUser.SetAccess(state.UserAccessRead)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.IsReadOnly(), jc.IsTrue)
+
+	err = envUser.SetAccess("admin")
+	c.Assert(err, gc.ErrorMatches, `user access "admin" not valid`)
+}
+
 func (s *EnvUserSuite) TestCaseSensitiveEnvUserErrors(c *gc.C) {
 	env, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
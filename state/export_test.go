@@ -46,8 +46,12 @@ var (
 	NowToTheSecond         = nowToTheSecond
 	MultiEnvCollections    = multiEnvCollections
 	PickAddress            = &pickAddress
+	LogTailerPollInterval  = &logTailerPollInterval
 	AddVolumeOp            = (*State).addVolumeOp
 	CombineMeterStatus     = combineMeterStatus
+	ImportBatchOps         = &importBatchOps
+	ExportedCollections    = exportedCollections
+	UnexportedCollections  = unexportedCollections
 )
 
 type (
@@ -196,6 +200,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	auditLogSize = auditLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
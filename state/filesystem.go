@@ -53,6 +53,11 @@ type Filesystem interface {
 	// if it needs to be provisioned. Params returns true if the returned
 	// parameters are usable for provisioning, otherwise false.
 	Params() (FilesystemParams, bool)
+
+	// ProvisioningError returns the reason the storage provisioner
+	// last failed to provision the filesystem, or the empty string if
+	// the filesystem is not in an error state.
+	ProvisioningError() string
 }
 
 // FilesystemAttachment describes an attachment of a filesystem to a machine.
@@ -99,6 +104,10 @@ type filesystemDoc struct {
 	VolumeId     string            `bson:"volumeid,omitempty"`
 	Info         *FilesystemInfo   `bson:"info,omitempty"`
 	Params       *FilesystemParams `bson:"params,omitempty"`
+
+	// ProvisioningError records why the filesystem could not be
+	// provisioned, until provisioning is retried.
+	ProvisioningError string `bson:"provisioningerror,omitempty"`
 }
 
 // filesystemAttachmentDoc records information about a filesystem attachment.
@@ -197,6 +206,11 @@ func (f *filesystem) Params() (FilesystemParams, bool) {
 	return *f.doc.Params, true
 }
 
+// ProvisioningError is required to implement Filesystem.
+func (f *filesystem) ProvisioningError() string {
+	return f.doc.ProvisioningError
+}
+
 // Filesystem is required to implement FilesystemAttachment.
 func (f *filesystemAttachment) Filesystem() names.FilesystemTag {
 	return names.NewFilesystemTag(f.doc.Filesystem)
@@ -486,6 +500,57 @@ func (st *State) SetFilesystemInfo(tag names.FilesystemTag, info FilesystemInfo)
 	return st.run(buildTxn)
 }
 
+// SetFilesystemProvisioningError records that the specified filesystem
+// could not be provisioned, and why. The filesystem will not be
+// provisioned until RetryFilesystemProvisioning is called.
+func (st *State) SetFilesystemProvisioningError(tag names.FilesystemTag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set provisioning error for filesystem %q", tag.Id())
+	if message == "" {
+		return errors.New("empty error message")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fs, err := st.Filesystem(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fs.Life() != Alive {
+			return nil, errNotAlive
+		}
+		if _, err := fs.Info(); err == nil {
+			return nil, errors.New("filesystem is already provisioned")
+		}
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", false}}}),
+			Update: bson.D{{"$set", bson.D{{"provisioningerror", message}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// RetryFilesystemProvisioning clears the provisioning error recorded
+// for the specified filesystem, so that the storage provisioner will
+// try to provision it again.
+func (st *State) RetryFilesystemProvisioning(tag names.FilesystemTag) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fs, err := st.Filesystem(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fs.ProvisioningError() == "" {
+			return nil, errors.Errorf("filesystem %q is not in an error state", tag.Id())
+		}
+		return []txn.Op{{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"provisioningerror", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$unset", bson.D{{"provisioningerror", nil}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
 func validateFilesystemInfoChange(newInfo, oldInfo FilesystemInfo) error {
 	if newInfo.Pool != oldInfo.Pool {
 		return errors.Errorf(
@@ -104,6 +104,38 @@ func (s *FilesystemStateSuite) TestSetFilesystemInfoImmutable(c *gc.C) {
 	s.assertFilesystemInfo(c, filesystemTag, filesystemInfoSet)
 }
 
+func (s *FilesystemStateSuite) TestSetFilesystemProvisioningError(c *gc.C) {
+	filesystemAttachment := s.addUnitWithFilesystem(c, "rootfs", false)
+	filesystemTag := filesystemAttachment.Filesystem()
+
+	err := s.State.SetFilesystemProvisioningError(filesystemTag, "no space left")
+	c.Assert(err, jc.ErrorIsNil)
+	filesystem, err := s.State.Filesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystem.ProvisioningError(), gc.Equals, "no space left")
+
+	err = s.State.RetryFilesystemProvisioning(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystem, err = s.State.Filesystem(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystem.ProvisioningError(), gc.Equals, "")
+	_, err = filesystem.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	err = s.State.RetryFilesystemProvisioning(filesystemTag)
+	c.Assert(err, gc.ErrorMatches, `filesystem "0/0" is not in an error state`)
+}
+
+func (s *FilesystemStateSuite) TestSetFilesystemProvisioningErrorProvisioned(c *gc.C) {
+	filesystemAttachment := s.addUnitWithFilesystem(c, "rootfs", false)
+	filesystemTag := filesystemAttachment.Filesystem()
+	err := s.State.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{Size: 123})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetFilesystemProvisioningError(filesystemTag, "no space left")
+	c.Assert(err, gc.ErrorMatches, `cannot set provisioning error for filesystem "0/0": filesystem is already provisioned`)
+}
+
 func (s *FilesystemStateSuite) TestVolumeFilesystem(c *gc.C) {
 	filesystemAttachment := s.addUnitWithFilesystem(c, "loop", true)
 	filesystem, err := s.State.Filesystem(filesystemAttachment.Filesystem())
@@ -6,6 +6,8 @@
 package state
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/names"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
+	"launchpad.net/tomb"
 )
 
 const logsDB = "logs"
@@ -86,6 +89,279 @@ func (logger *DbLogger) Close() {
 	}
 }
 
+// LogRecord defines a single Juju log message as returned by
+// LogTailer.
+type LogRecord struct {
+	Time     time.Time
+	Entity   string
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// LogTailerParams specifies the filtering a LogTailer should apply to
+// log records in order to decide which to return.
+type LogTailerParams struct {
+	// MinLevel holds the lowest level of the records returned.
+	MinLevel loggo.Level
+
+	// InitialLines holds how many of the most recent matching
+	// records are returned before new ones. It has no meaning if
+	// Replay is true.
+	InitialLines int
+
+	// Replay causes all matching records to be returned, starting
+	// with the oldest.
+	Replay bool
+
+	// IncludeEntity lists the entity tags whose records are returned;
+	// if it is empty, records of all entities are. A tag may contain
+	// '*' wildcards, e.g. "unit-mysql-*".
+	IncludeEntity []string
+
+	// ExcludeEntity lists the entity tags whose records are not
+	// returned. As with IncludeEntity, a tag may contain wildcards.
+	ExcludeEntity []string
+
+	// IncludeModule lists the logging modules whose records are
+	// returned, along with those of their submodules; if it is empty,
+	// records of all modules are.
+	IncludeModule []string
+
+	// ExcludeModule lists the logging modules whose records, and
+	// those of their submodules, are not returned.
+	ExcludeModule []string
+
+	// Filter, if not nil, is called with each record at or above
+	// MinLevel; only records for which it returns true are returned.
+	Filter func(*LogRecord) bool
+}
+
+// logTailerPollInterval holds how often a LogTailer checks for new
+// log records.
+var logTailerPollInterval = time.Second
+
+// LogTailer allows for retrieval of Juju's logs from MongoDB. It
+// first returns any matching already recorded logs and then waits
+// for additional matching logs as they appear.
+type LogTailer struct {
+	tomb     tomb.Tomb
+	session  *mgo.Session
+	logsColl *mgo.Collection
+	envUUID  string
+	params   LogTailerParams
+	logCh    chan *LogRecord
+}
+
+// NewLogTailer returns a LogTailer which returns the logs of the
+// environment of the given State that match the given parameters.
+func NewLogTailer(st *State, params LogTailerParams) *LogTailer {
+	session, logsColl := initLogsSession(st)
+	t := &LogTailer{
+		session:  session,
+		logsColl: logsColl,
+		envUUID:  st.EnvironUUID(),
+		params:   params,
+		logCh:    make(chan *LogRecord),
+	}
+	go func() {
+		defer t.tomb.Done()
+		defer close(t.logCh)
+		defer session.Close()
+		err := t.loop()
+		t.tomb.Kill(errors.Cause(err))
+	}()
+	return t
+}
+
+// Logs returns the channel through which the LogTailer returns log
+// records. It is closed when the LogTailer stops.
+func (t *LogTailer) Logs() <-chan *LogRecord {
+	return t.logCh
+}
+
+// Dead returns a channel that is closed when the LogTailer has
+// stopped.
+func (t *LogTailer) Dead() <-chan struct{} {
+	return t.tomb.Dead()
+}
+
+// Stop shuts down the LogTailer.
+func (t *LogTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+// Err returns the error that caused the LogTailer to stop. If it
+// hasn't stopped or stopped without error nil will be returned.
+func (t *LogTailer) Err() error {
+	return t.tomb.Err()
+}
+
+func (t *LogTailer) loop() error {
+	var lastId bson.ObjectId
+	var err error
+	if t.params.Replay {
+		lastId, err = t.processFrom("")
+	} else {
+		lastId, err = t.processInitial()
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-t.tomb.Dying():
+			return tomb.ErrDying
+		case <-time.After(logTailerPollInterval):
+		}
+		if lastId, err = t.processFrom(lastId); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// processInitial sends the most recent InitialLines matching records,
+// oldest first, and returns the id of the newest record in the
+// collection, from which tailing continues.
+func (t *LogTailer) processInitial() (bson.ObjectId, error) {
+	iter := t.logsColl.Find(t.query("")).Sort("-_id").Iter()
+	var lastId bson.ObjectId
+	var records []*LogRecord
+	var doc logDoc
+	for len(records) < t.params.InitialLines && iter.Next(&doc) {
+		if lastId == "" {
+			lastId = doc.Id
+		}
+		if rec := newLogRecord(&doc); t.match(rec) {
+			records = append(records, rec)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return "", errors.Annotate(err, "cannot read initial log records")
+	}
+	if lastId == "" {
+		// Either no lines were asked for, or there are none; start
+		// after the newest record of any level.
+		if err := t.logsColl.Find(bson.M{"e": t.envUUID}).Sort("-_id").One(&doc); err == nil {
+			lastId = doc.Id
+		} else if err != mgo.ErrNotFound {
+			return "", errors.Annotate(err, "cannot read newest log record")
+		}
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if err := t.send(records[i]); err != nil {
+			return "", err
+		}
+	}
+	return lastId, nil
+}
+
+// processFrom sends the matching records newer than the one with the
+// given id, or all matching records if no id is given, and returns
+// the id of the newest record seen.
+func (t *LogTailer) processFrom(lastId bson.ObjectId) (bson.ObjectId, error) {
+	iter := t.logsColl.Find(t.query(lastId)).Sort("_id").Iter()
+	var doc logDoc
+	for iter.Next(&doc) {
+		lastId = doc.Id
+		if rec := newLogRecord(&doc); t.match(rec) {
+			if err := t.send(rec); err != nil {
+				iter.Close()
+				return "", err
+			}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return "", errors.Annotate(err, "cannot read log records")
+	}
+	return lastId, nil
+}
+
+// query returns the query selecting the environment's records that
+// match the tailer's level, entity and module filters, with ids after
+// the given one, if any. Filtering on the server means records that
+// do not match are never sent to the API server.
+func (t *LogTailer) query(afterId bson.ObjectId) bson.M {
+	query := bson.M{
+		"e": t.envUUID,
+		"v": bson.M{"$gte": t.params.MinLevel},
+	}
+	if afterId != "" {
+		query["_id"] = bson.M{"$gt": afterId}
+	}
+	if entity := filterQuery(t.params.IncludeEntity, t.params.ExcludeEntity, entityRegex); entity != nil {
+		query["n"] = entity
+	}
+	if module := filterQuery(t.params.IncludeModule, t.params.ExcludeModule, moduleRegex); module != nil {
+		query["m"] = module
+	}
+	return query
+}
+
+// filterQuery returns the query for a field matching any of the
+// include filters, if there are any, and none of the exclude filters.
+// It returns nil if there are no filters.
+func filterQuery(include, exclude []string, toRegex func(string) bson.RegEx) bson.M {
+	query := bson.M{}
+	if len(include) > 0 {
+		query["$in"] = filterRegexes(include, toRegex)
+	}
+	if len(exclude) > 0 {
+		query["$nin"] = filterRegexes(exclude, toRegex)
+	}
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
+
+func filterRegexes(filters []string, toRegex func(string) bson.RegEx) []bson.RegEx {
+	regexes := make([]bson.RegEx, len(filters))
+	for i, filter := range filters {
+		regexes[i] = toRegex(filter)
+	}
+	return regexes
+}
+
+// entityRegex returns a regular expression matching the entity tags
+// matched by the given filter, in which '*' matches anything.
+func entityRegex(filter string) bson.RegEx {
+	pattern := strings.Replace(regexp.QuoteMeta(filter), `\*`, ".*", -1)
+	return bson.RegEx{Pattern: "^" + pattern + "$"}
+}
+
+// moduleRegex returns a regular expression matching the given logging
+// module and its submodules.
+func moduleRegex(module string) bson.RegEx {
+	return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(module) + `(\.|$)`}
+}
+
+func (t *LogTailer) match(rec *LogRecord) bool {
+	return t.params.Filter == nil || t.params.Filter(rec)
+}
+
+func (t *LogTailer) send(rec *LogRecord) error {
+	select {
+	case <-t.tomb.Dying():
+		return tomb.ErrDying
+	case t.logCh <- rec:
+	}
+	return nil
+}
+
+func newLogRecord(doc *logDoc) *LogRecord {
+	return &LogRecord{
+		Time:     doc.Time,
+		Entity:   doc.Entity,
+		Module:   doc.Module,
+		Location: doc.Location,
+		Level:    doc.Level,
+		Message:  doc.Message,
+	}
+}
+
 // PruneLogs removes old log documents in order to control the size of
 // logs collection. All logs older than minLogTime are
 // removed. Further removal is also performed if the logs collection
@@ -15,6 +15,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
 )
 
 type LogsSuite struct {
@@ -152,6 +153,111 @@ func (s *LogsSuite) TestPruneLogsBySize(c *gc.C) {
 	assertLatestTs(s2)
 }
 
+func (s *LogsSuite) TestLogTailerInitialLines(c *gc.C) {
+	s.PatchValue(state.LogTailerPollInterval, 10*time.Millisecond)
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	now := time.Now().Truncate(time.Millisecond)
+	for i, level := range []loggo.Level{loggo.INFO, loggo.DEBUG, loggo.WARNING, loggo.ERROR} {
+		err := dbLogger.Log(now.Add(time.Duration(i)*time.Second), "some.module", "foo.go:1", level, level.String())
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	tailer := state.NewLogTailer(s.State, state.LogTailerParams{
+		MinLevel:     loggo.INFO,
+		InitialLines: 2,
+	})
+	defer tailer.Stop()
+	s.assertTailed(c, tailer, "WARNING", "ERROR")
+
+	// New records are returned once they are written.
+	err := dbLogger.Log(now.Add(time.Minute), "some.module", "foo.go:1", loggo.DEBUG, "DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	err = dbLogger.Log(now.Add(time.Minute), "some.module", "foo.go:1", loggo.INFO, "INFO")
+	c.Assert(err, jc.ErrorIsNil)
+	rec := s.assertTailed(c, tailer, "INFO")[0]
+	c.Assert(rec.Time, gc.Equals, now.Add(time.Minute))
+	c.Assert(rec.Entity, gc.Equals, "machine-0")
+	c.Assert(rec.Module, gc.Equals, "some.module")
+	c.Assert(rec.Location, gc.Equals, "foo.go:1")
+	c.Assert(rec.Level, gc.Equals, loggo.INFO)
+}
+
+func (s *LogsSuite) TestLogTailerReplayWithFilter(c *gc.C) {
+	s.PatchValue(state.LogTailerPollInterval, 10*time.Millisecond)
+	for _, id := range []string{"0", "1", "0"} {
+		dbLogger := state.NewDbLogger(s.State, names.NewMachineTag(id))
+		err := dbLogger.Log(time.Now(), "some.module", "foo.go:1", loggo.INFO, "machine-"+id)
+		c.Assert(err, jc.ErrorIsNil)
+		dbLogger.Close()
+	}
+
+	tailer := state.NewLogTailer(s.State, state.LogTailerParams{
+		Replay: true,
+		Filter: func(rec *state.LogRecord) bool {
+			return rec.Entity == "machine-0"
+		},
+	})
+	defer tailer.Stop()
+	s.assertTailed(c, tailer, "machine-0", "machine-0")
+}
+
+func (s *LogsSuite) TestLogTailerEntityAndModuleFilters(c *gc.C) {
+	s.PatchValue(state.LogTailerPollInterval, 10*time.Millisecond)
+	for _, entity := range []names.Tag{
+		names.NewMachineTag("0"),
+		names.NewUnitTag("mysql/0"),
+		names.NewUnitTag("mysql/1"),
+		names.NewUnitTag("wordpress/0"),
+	} {
+		dbLogger := state.NewDbLogger(s.State, entity)
+		for _, module := range []string{"juju.worker", "juju.worker.uniter", "juju.workers", "juju.state"} {
+			err := dbLogger.Log(time.Now(), module, "foo.go:1", loggo.INFO, entity.String()+" "+module)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		dbLogger.Close()
+	}
+
+	tailer := state.NewLogTailer(s.State, state.LogTailerParams{
+		Replay:        true,
+		IncludeEntity: []string{"unit-mysql-*", "machine-0"},
+		ExcludeEntity: []string{"unit-mysql-1"},
+		IncludeModule: []string{"juju.worker"},
+		ExcludeModule: []string{"juju.worker.uniter"},
+	})
+	defer tailer.Stop()
+	s.assertTailed(c, tailer, "machine-0 juju.worker", "unit-mysql-0 juju.worker")
+}
+
+func (s *LogsSuite) TestLogTailerStop(c *gc.C) {
+	tailer := state.NewLogTailer(s.State, state.LogTailerParams{})
+	c.Assert(tailer.Stop(), jc.ErrorIsNil)
+	_, ok := <-tailer.Logs()
+	c.Assert(ok, jc.IsFalse)
+}
+
+// assertTailed checks that the tailer returns records with the given
+// messages, and no more.
+func (s *LogsSuite) assertTailed(c *gc.C, tailer *state.LogTailer, messages ...string) []*state.LogRecord {
+	var records []*state.LogRecord
+	for _, message := range messages {
+		select {
+		case rec, ok := <-tailer.Logs():
+			c.Assert(ok, jc.IsTrue)
+			c.Assert(rec.Message, gc.Equals, message)
+			records = append(records, rec)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for log record %q", message)
+		}
+	}
+	select {
+	case rec := <-tailer.Logs():
+		c.Fatalf("unexpected log record %q", rec.Message)
+	case <-time.After(testing.ShortWait):
+	}
+	return records
+}
+
 func (s *LogsSuite) generateLogs(c *gc.C, st *state.State, now time.Time, count int) {
 	dbLogger := state.NewDbLogger(st, names.NewMachineTag("0"))
 	defer dbLogger.Close()
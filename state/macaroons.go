@@ -0,0 +1,157 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// macaroonKeyId is the id of the document holding the environment's
+// macaroon key pair. The ids of the macaroons' root keys are random hex
+// strings, so they cannot clash with it.
+const macaroonKeyId = "macaroon-key"
+
+// macaroonKeyDoc records the key pair with which the API servers
+// encrypt the third-party caveats of the macaroons they mint.
+type macaroonKeyDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Public  []byte `bson:"public"`
+	Private []byte `bson:"private"`
+}
+
+// macaroonItemDoc records an item stored by the macaroon service, such
+// as the root key of a macaroon that it minted.
+type macaroonItemDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Item    string `bson:"item"`
+}
+
+// MacaroonKey holds the key pair of the environment's macaroon service.
+type MacaroonKey struct {
+	Public  []byte
+	Private []byte
+}
+
+// MacaroonKey returns the key pair recorded for the environment's
+// macaroon service. It returns a NotFound error if none was recorded.
+func (st *State) MacaroonKey() (MacaroonKey, error) {
+	macaroons, closer := st.getCollection(macaroonsC)
+	defer closer()
+
+	var doc macaroonKeyDoc
+	err := macaroons.FindId(macaroonKeyId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return MacaroonKey{}, errors.NotFoundf("macaroon key")
+	} else if err != nil {
+		return MacaroonKey{}, errors.Annotate(err, "cannot get macaroon key")
+	}
+	return MacaroonKey{Public: doc.Public, Private: doc.Private}, nil
+}
+
+// EnsureMacaroonKey records the given key pair for the environment's
+// macaroon service, unless one was recorded already, and returns the
+// recorded key pair. All the API servers use the same key pair, so that
+// the macaroons minted by one of them are accepted by the others.
+func (st *State) EnsureMacaroonKey(key MacaroonKey) (MacaroonKey, error) {
+	ops := []txn.Op{{
+		C:      macaroonsC,
+		Id:     st.docID(macaroonKeyId),
+		Assert: txn.DocMissing,
+		Insert: &macaroonKeyDoc{
+			DocID:   st.docID(macaroonKeyId),
+			EnvUUID: st.EnvironUUID(),
+			Public:  key.Public,
+			Private: key.Private,
+		},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		// Another API server got there first.
+		return st.MacaroonKey()
+	} else if err != nil {
+		return MacaroonKey{}, errors.Annotate(err, "cannot record macaroon key")
+	}
+	return key, nil
+}
+
+// MacaroonStorage returns the storage of the environment's macaroon
+// service. Its items are kept in state, so that the macaroons minted by
+// one API server can be checked by the others, and survive restarts.
+func (st *State) MacaroonStorage() *MacaroonStorage {
+	return &MacaroonStorage{st: st}
+}
+
+// MacaroonStorage stores the items of a macaroon service in state. It
+// implements the Storage interface of the macaroon bakery.
+type MacaroonStorage struct {
+	st *State
+}
+
+// Put stores the given item at the given location, replacing any item
+// stored there already.
+func (s *MacaroonStorage) Put(location, item string) error {
+	docID := s.st.docID(location)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := s.Get(location); errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      macaroonsC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &macaroonItemDoc{
+					DocID:   docID,
+					EnvUUID: s.st.EnvironUUID(),
+					Item:    item,
+				},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      macaroonsC,
+			Id:     docID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"item", item}}}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot store macaroon item %q", location)
+	}
+	return nil
+}
+
+// Get returns the item stored at the given location. It returns a
+// NotFound error if there is none.
+func (s *MacaroonStorage) Get(location string) (string, error) {
+	macaroons, closer := s.st.getCollection(macaroonsC)
+	defer closer()
+
+	var doc macaroonItemDoc
+	err := macaroons.FindId(location).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("macaroon item %q", location)
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get macaroon item %q", location)
+	}
+	return doc.Item, nil
+}
+
+// Del removes the item stored at the given location, if any.
+func (s *MacaroonStorage) Del(location string) error {
+	ops := []txn.Op{{
+		C:      macaroonsC,
+		Id:     s.st.docID(location),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := s.st.runTransaction(ops)
+	if err != nil && err != txn.ErrAborted {
+		return errors.Annotatef(err, "cannot remove macaroon item %q", location)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type MacaroonSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MacaroonSuite{})
+
+func (s *MacaroonSuite) TestEnsureMacaroonKey(c *gc.C) {
+	_, err := s.State.MacaroonKey()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	key := state.MacaroonKey{Public: []byte("public"), Private: []byte("private")}
+	got, err := s.State.EnsureMacaroonKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, key)
+
+	// Once recorded, the key pair is not replaced.
+	got, err = s.State.EnsureMacaroonKey(state.MacaroonKey{
+		Public:  []byte("other public"),
+		Private: []byte("other private"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, key)
+
+	got, err = s.State.MacaroonKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, key)
+}
+
+func (s *MacaroonSuite) TestMacaroonKeyPerEnvironment(c *gc.C) {
+	key := state.MacaroonKey{Public: []byte("public"), Private: []byte("private")}
+	_, err := s.State.EnsureMacaroonKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	_, err = otherState.MacaroonKey()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MacaroonSuite) TestMacaroonStorage(c *gc.C) {
+	store := s.State.MacaroonStorage()
+	_, err := store.Get("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = store.Put("foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+	item, err := store.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(item, gc.Equals, "bar")
+
+	err = store.Put("foo", "baz")
+	c.Assert(err, jc.ErrorIsNil)
+	item, err = store.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(item, gc.Equals, "baz")
+
+	err = store.Del("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = store.Get("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing a missing item is not an error.
+	err = store.Del("foo")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MacaroonSuite) TestMacaroonStorageSharedBetweenStates(c *gc.C) {
+	err := s.State.MacaroonStorage().Put("foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := state.Open(statetesting.NewMongoInfo(), statetesting.NewDialOpts(), state.Policy(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	item, err := st.MacaroonStorage().Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(item, gc.Equals, "bar")
+}
@@ -288,7 +288,19 @@ func (m *Machine) IsManual() (bool, error) {
 			return false, err
 		}
 		t := cfg.Type()
-		return t == "null" || t == "manual", nil
+		if t == "null" || t == "manual" {
+			return true, nil
+		}
+		// Other providers may have bootstrapped onto an existing
+		// machine, which is then given an instance id prefixed
+		// with "manual:".
+		instId, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return strings.HasPrefix(string(instId), manualMachinePrefix), nil
 	}
 	return false, nil
 }
@@ -632,6 +644,30 @@ func (m *Machine) removeNetworkInterfacesOps() ([]txn.Op, error) {
 	return ops, iter.Close()
 }
 
+// killIPAddressesOps returns the operations that set the addresses
+// allocated to the machine to Dead, so they will be released by the
+// addresser worker.
+func (m *Machine) killIPAddressesOps() ([]txn.Op, error) {
+	if m.doc.Life != Dead {
+		return nil, errors.Errorf("machine is not dead")
+	}
+	sel := bson.D{{"machineid", m.doc.Id}, {"life", Alive}}
+	ipAddresses, closer := m.st.getCollection(ipaddressesC)
+	defer closer()
+
+	var ops []txn.Op
+	iter := ipAddresses.Find(sel).Select(bson.D{{"_id", 1}}).Iter()
+	var doc ipaddressDoc
+	for iter.Next(&doc) {
+		ops = append(ops, txn.Op{
+			C:      ipaddressesC,
+			Id:     doc.DocID,
+			Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		})
+	}
+	return ops, iter.Close()
+}
+
 // Remove removes the machine from state. It will fail if the machine
 // is not Dead.
 func (m *Machine) Remove() (err error) {
@@ -661,7 +697,9 @@ func (m *Machine) Remove() (err error) {
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
+		removeUpgradeSeriesLockOp(m.st, m.Id()),
 		removeMachineBlockDevicesOp(m.Id()),
+		removeAgentHealthOp(m.st, m.globalKey()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -671,9 +709,17 @@ func (m *Machine) Remove() (err error) {
 	if err != nil {
 		return err
 	}
+	ipAddrsOps, err := m.killIPAddressesOps()
+	if err != nil {
+		return err
+	}
 	ops = append(ops, ifacesOps...)
 	ops = append(ops, portsOps...)
+	ops = append(ops, ipAddrsOps...)
 	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
+	if hasEntityBlocks(m.st, m.Tag()) {
+		ops = append(ops, m.st.newCleanupOp(cleanupBlocksForRemovedEntity, m.Tag().String()))
+	}
 	// The only abort conditions in play indicate that the machine has already
 	// been removed.
 	return onAbort(m.st.runTransaction(ops), nil)
@@ -1298,6 +1344,7 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err = m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+	recordStatusHistory(m.st, m.globalKey(), doc.statusDoc)
 	return nil
 }
 
@@ -6,6 +6,7 @@ package state_test
 import (
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -22,6 +23,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/testing"
 	"github.com/juju/juju/storage/poolmanager"
 	"github.com/juju/juju/storage/provider"
@@ -202,6 +204,14 @@ func (s *MachineSuite) TestMachineIsManualBootstrap(c *gc.C) {
 	c.Assert(manual, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestMachineIsManualBootstrapExistingHost(c *gc.C) {
+	err := s.machine0.SetProvisioned("manual:10.0.0.1", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	manual, err := s.machine0.IsManual()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(manual, jc.IsTrue)
+}
+
 func (s *MachineSuite) TestMachineIsManual(c *gc.C) {
 	tests := []struct {
 		instanceId instance.Id
@@ -386,6 +396,27 @@ func (s *MachineSuite) TestRemove(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *MachineSuite) TestRemoveKillsAllocatedIPAddresses(c *gc.C) {
+	addr, err := s.State.AddIPAddress(network.NewAddress("0.1.2.3"), "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr.AllocateTo(s.machine.Id(), "wobble")
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddIPAddress(network.NewAddress("0.1.2.4"), "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = addr.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Life(), gc.Equals, state.Dead)
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other.Life(), gc.Equals, state.Alive)
+}
+
 func (s *MachineSuite) TestHasVote(c *gc.C) {
 	c.Assert(s.machine.HasVote(), jc.IsFalse)
 
@@ -563,6 +594,38 @@ func (s *MachineSuite) TestMachineWaitAgentPresence(c *gc.C) {
 	c.Assert(alive, jc.IsFalse)
 }
 
+func (s *MachineSuite) TestWatchAgentPresence(c *gc.C) {
+	ch := make(chan presence.Change)
+	err := s.State.WatchAgentPresence(s.machine.Tag(), ch)
+	c.Assert(err, jc.ErrorIsNil)
+	defer s.State.UnwatchAgentPresence(s.machine.Tag(), ch)
+
+	assertChange := func(alive bool) {
+		s.State.StartSync()
+		select {
+		case change := <-ch:
+			c.Assert(change.Alive, gc.Equals, alive)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("no presence change")
+		}
+	}
+	assertChange(false)
+
+	pinger, err := s.machine.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	assertChange(true)
+
+	err = pinger.Kill()
+	c.Assert(err, jc.ErrorIsNil)
+	assertChange(false)
+}
+
+func (s *MachineSuite) TestWatchAgentPresenceInvalidTag(c *gc.C) {
+	ch := make(chan presence.Change)
+	err := s.State.WatchAgentPresence(names.NewServiceTag("wordpress"), ch)
+	c.Assert(err, gc.ErrorMatches, `agent tag "service-wordpress" not valid`)
+}
+
 func (s *MachineSuite) TestRequestedNetworks(c *gc.C) {
 	// s.machine is created without requested networks, so check
 	// they're empty when we read them.
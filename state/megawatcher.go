@@ -161,6 +161,12 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id interface{
 		info.Status = multiwatcher.Status(sdoc.Status)
 		info.Status = translateLegacyUnitAgentStatus(info.Status)
 		info.StatusInfo = sdoc.StatusInfo
+		wdoc, err := getStatus(st, unitGlobalKey(u.Name))
+		if err != nil {
+			return err
+		}
+		info.WorkloadStatus = multiwatcher.Status(wdoc.Status)
+		info.WorkloadStatusInfo = wdoc.StatusInfo
 		portRanges, compatiblePorts, err := getUnitPortRangesAndPorts(st, u.Name)
 		if err != nil {
 			return errors.Trace(err)
@@ -173,6 +179,8 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id interface{
 		oldInfo := oldInfo.(*multiwatcher.UnitInfo)
 		info.Status = oldInfo.Status
 		info.StatusInfo = oldInfo.StatusInfo
+		info.WorkloadStatus = oldInfo.WorkloadStatus
+		info.WorkloadStatusInfo = oldInfo.WorkloadStatusInfo
 		info.Ports = oldInfo.Ports
 		info.PortRanges = oldInfo.PortRanges
 	}
@@ -402,7 +410,8 @@ func (a *backingBlock) mongoId() interface{} {
 type backingStatus statusDoc
 
 func (s *backingStatus) updated(st *State, store *multiwatcherStore, id interface{}) error {
-	parentID, ok := backingEntityIdForGlobalKey(st.localID(id.(string)))
+	localID := st.localID(id.(string))
+	parentID, ok := backingEntityIdForGlobalKey(localID)
 	if !ok {
 		return nil
 	}
@@ -413,6 +422,14 @@ func (s *backingStatus) updated(st *State, store *multiwatcherStore, id interfac
 		return nil
 	case *multiwatcher.UnitInfo:
 		newInfo := *info
+		if strings.HasSuffix(localID, "#charm") {
+			// The status of the unit's workload, rather than
+			// of its agent.
+			newInfo.WorkloadStatus = multiwatcher.Status(s.Status)
+			newInfo.WorkloadStatusInfo = s.StatusInfo
+			info0 = &newInfo
+			break
+		}
 		newInfo.Status = multiwatcher.Status(s.Status)
 		newInfo.Status = translateLegacyUnitAgentStatus(newInfo.Status)
 		newInfo.StatusInfo = s.StatusInfo
@@ -244,13 +244,14 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C, st *State, units int) (e
 		c.Assert(m.Tag().String(), gc.Equals, fmt.Sprintf("machine-%d", i+1))
 
 		add(&multiwatcher.UnitInfo{
-			Name:        fmt.Sprintf("wordpress/%d", i),
-			Service:     wordpress.Name(),
-			Series:      m.Series(),
-			MachineId:   m.Id(),
-			Ports:       []network.Port{},
-			Status:      multiwatcher.Status("allocating"),
-			Subordinate: false,
+			Name:           fmt.Sprintf("wordpress/%d", i),
+			Service:        wordpress.Name(),
+			Series:         m.Series(),
+			MachineId:      m.Id(),
+			Ports:          []network.Port{},
+			Status:         multiwatcher.Status("allocating"),
+			WorkloadStatus: multiwatcher.Status("maintenance"),
+			Subordinate:    false,
 		})
 		pairs := map[string]string{"name": fmt.Sprintf("bar %d", i)}
 		err = st.SetAnnotations(wu, pairs)
@@ -301,12 +302,13 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C, st *State, units int) (e
 		c.Assert(ok, jc.IsTrue)
 		c.Assert(deployer, gc.Equals, names.NewUnitTag(fmt.Sprintf("wordpress/%d", i)))
 		add(&multiwatcher.UnitInfo{
-			Name:        fmt.Sprintf("logging/%d", i),
-			Service:     "logging",
-			Series:      "quantal",
-			Ports:       []network.Port{},
-			Status:      multiwatcher.Status("allocating"),
-			Subordinate: true,
+			Name:           fmt.Sprintf("logging/%d", i),
+			Service:        "logging",
+			Series:         "quantal",
+			Ports:          []network.Port{},
+			Status:         multiwatcher.Status("allocating"),
+			WorkloadStatus: multiwatcher.Status("maintenance"),
+			Subordinate:    true,
 		})
 	}
 	return
@@ -1070,12 +1072,13 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 							{"udp", 54321},
 						},
 						PortRanges: []network.PortRange{
-							{5555, 5558, "tcp"},
-							{12345, 12345, "tcp"},
-							{54321, 54321, "udp"},
+							{5555, 5558, "tcp", ""},
+							{12345, 12345, "tcp", ""},
+							{54321, 54321, "udp", ""},
 						},
-						Status:     multiwatcher.Status("error"),
-						StatusInfo: "failure",
+						Status:         multiwatcher.Status("error"),
+						StatusInfo:     "failure",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 		func(c *gc.C, st *State) changeTestCase {
@@ -1096,7 +1099,7 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 					Status:     multiwatcher.Status("error"),
 					StatusInfo: "another failure",
 					Ports:      []network.Port{{"udp", 17070}},
-					PortRanges: []network.PortRange{{17070, 17070, "udp"}},
+					PortRanges: []network.PortRange{{17070, 17070, "udp", ""}},
 				}},
 				change: watcher.Change{
 					C:  "units",
@@ -1109,7 +1112,7 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 						Series:     "quantal",
 						MachineId:  "0",
 						Ports:      []network.Port{{"udp", 17070}},
-						PortRanges: []network.PortRange{{17070, 17070, "udp"}},
+						PortRanges: []network.PortRange{{17070, 17070, "udp", ""}},
 						Status:     multiwatcher.Status("error"),
 						StatusInfo: "another failure",
 					}}}
@@ -1143,7 +1146,7 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 					&multiwatcher.UnitInfo{
 						Name:       "wordpress/0",
 						Ports:      []network.Port{{"tcp", 4242}},
-						PortRanges: []network.PortRange{{4242, 4242, "tcp"}},
+						PortRanges: []network.PortRange{{4242, 4242, "tcp", ""}},
 					},
 					&multiwatcher.MachineInfo{
 						Id: "0",
@@ -1174,13 +1177,14 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
-						Name:       "wordpress/0",
-						Service:    "wordpress",
-						Series:     "quantal",
-						MachineId:  "0",
-						Status:     "allocating",
-						Ports:      []network.Port{{"tcp", 21}, {"tcp", 22}},
-						PortRanges: []network.PortRange{{21, 22, "tcp"}},
+						Name:           "wordpress/0",
+						Service:        "wordpress",
+						Series:         "quantal",
+						MachineId:      "0",
+						Status:         "allocating",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
+						Ports:          []network.Port{{"tcp", 21}, {"tcp", 22}},
+						PortRanges:     []network.PortRange{{21, 22, "tcp", ""}},
 					},
 					&multiwatcher.MachineInfo{
 						Id: "0",
@@ -1219,9 +1223,10 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 						PrivateAddress: "private",
 						MachineId:      "0",
 						Ports:          []network.Port{{"tcp", 12345}},
-						PortRanges:     []network.PortRange{{12345, 12345, "tcp"}},
+						PortRanges:     []network.PortRange{{12345, 12345, "tcp", ""}},
 						Status:         multiwatcher.Status("error"),
 						StatusInfo:     "failure",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 		func(c *gc.C, st *State) changeTestCase {
@@ -1276,6 +1281,32 @@ func (s *storeManagerStateSuite) TestChangeUnits(c *gc.C) {
 						StatusData: make(map[string]interface{}),
 					}}}
 		},
+		func(c *gc.C, st *State) changeTestCase {
+			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), s.owner)
+			u, err := wordpress.AddUnit()
+			c.Assert(err, jc.ErrorIsNil)
+			err = u.SetStatus(StatusActive, "serving", nil)
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "workload status is changed without changing the agent status",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.UnitInfo{
+					Name:           "wordpress/0",
+					Status:         multiwatcher.Status("idle"),
+					WorkloadStatus: multiwatcher.Status("maintenance"),
+				}},
+				change: watcher.Change{
+					C:  "statuses",
+					Id: st.docID("u#wordpress/0#charm"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.UnitInfo{
+						Name:               "wordpress/0",
+						Status:             multiwatcher.Status("idle"),
+						WorkloadStatus:     multiwatcher.Status("active"),
+						WorkloadStatusInfo: "serving",
+					}}}
+		},
 		func(c *gc.C, st *State) changeTestCase {
 			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), s.owner)
 			u, err := wordpress.AddUnit()
@@ -1368,13 +1399,14 @@ func (s *storeManagerStateSuite) TestChangeUnitsNonNilPorts(c *gc.C) {
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
-						Name:       "wordpress/0",
-						Service:    "wordpress",
-						Series:     "quantal",
-						MachineId:  "0",
-						Ports:      []network.Port{},
-						PortRanges: []network.PortRange{},
-						Status:     "allocating",
+						Name:           "wordpress/0",
+						Service:        "wordpress",
+						Series:         "quantal",
+						MachineId:      "0",
+						Ports:          []network.Port{},
+						PortRanges:     []network.PortRange{},
+						Status:         "allocating",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 		func(c *gc.C, st *State) changeTestCase {
@@ -1395,8 +1427,9 @@ func (s *storeManagerStateSuite) TestChangeUnitsNonNilPorts(c *gc.C) {
 						PublicAddress:  "1.2.3.4",
 						PrivateAddress: "4.3.2.1",
 						Ports:          []network.Port{{"tcp", 12345}},
-						PortRanges:     []network.PortRange{{12345, 12345, "tcp"}},
+						PortRanges:     []network.PortRange{{12345, 12345, "tcp", ""}},
 						Status:         "allocating",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 		func(c *gc.C, st *State) changeTestCase {
@@ -1419,6 +1452,7 @@ func (s *storeManagerStateSuite) TestChangeUnitsNonNilPorts(c *gc.C) {
 						Ports:          []network.Port{},
 						PortRanges:     []network.PortRange{},
 						Status:         "allocating",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 		func(c *gc.C, st *State) changeTestCase {
@@ -1432,12 +1466,13 @@ func (s *storeManagerStateSuite) TestChangeUnitsNonNilPorts(c *gc.C) {
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
-						Name:       "wordpress/0",
-						Service:    "wordpress",
-						Series:     "quantal",
-						Ports:      []network.Port{},
-						PortRanges: []network.PortRange{},
-						Status:     "allocating",
+						Name:           "wordpress/0",
+						Service:        "wordpress",
+						Series:         "quantal",
+						Ports:          []network.Port{},
+						PortRanges:     []network.PortRange{},
+						Status:         "allocating",
+						WorkloadStatus: multiwatcher.Status("maintenance"),
 					}}}
 		},
 	}
@@ -1479,8 +1514,9 @@ func (s *storeManagerStateSuite) TestClosingPorts(c *gc.C) {
 			PublicAddress:  "1.2.3.4",
 			PrivateAddress: "4.3.2.1",
 			Ports:          []network.Port{{"tcp", 12345}},
-			PortRanges:     []network.PortRange{{12345, 12345, "tcp"}},
+			PortRanges:     []network.PortRange{{12345, 12345, "tcp", ""}},
 			Status:         "allocating",
+			WorkloadStatus: multiwatcher.Status("maintenance"),
 		},
 	})
 	// Close the ports.
@@ -1502,6 +1538,7 @@ func (s *storeManagerStateSuite) TestClosingPorts(c *gc.C) {
 			Ports:          []network.Port{},
 			PortRanges:     []network.PortRange{},
 			Status:         "allocating",
+			WorkloadStatus: multiwatcher.Status("maintenance"),
 		},
 	})
 	// Try closing and updating with an invalid unit.
@@ -1669,11 +1706,12 @@ func (s *storeManagerStateSuite) TestStateWatcher(c *gc.C) {
 		},
 	}, {
 		Entity: &multiwatcher.UnitInfo{
-			Name:      "wordpress/0",
-			Service:   "wordpress",
-			Series:    "quantal",
-			MachineId: "2",
-			Status:    "allocating",
+			Name:           "wordpress/0",
+			Service:        "wordpress",
+			Series:         "quantal",
+			MachineId:      "2",
+			Status:         "allocating",
+			WorkloadStatus: multiwatcher.Status("maintenance"),
 		},
 	}})
 }
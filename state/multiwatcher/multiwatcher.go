@@ -177,6 +177,12 @@ type UnitInfo struct {
 	StatusInfo     string
 	StatusData     map[string]interface{}
 	Subordinate    bool
+
+	// WorkloadStatus and WorkloadStatusInfo hold the status of the
+	// unit's workload, as reported by its charm; Status above holds
+	// the status of the unit's agent.
+	WorkloadStatus     Status
+	WorkloadStatusInfo string
 }
 
 func (i *UnitInfo) EntityId() EntityId {
@@ -181,6 +181,12 @@ var indexes = []struct {
 	{storageAttachmentsC, []string{"env-uuid", "unitid"}, false, false},
 	{volumesC, []string{"env-uuid", "storageid"}, false, false},
 	{filesystemsC, []string{"env-uuid", "storageid"}, false, false},
+	{annotationsC, []string{"env-uuid", "tags"}, false, false},
+	{statusesHistoryC, []string{"env-uuid", "globalkey", "updated"}, false, false},
+	{actionOutputC, []string{"env-uuid", "actionid", "seq"}, false, false},
+	{actionSchedulesC, []string{"env-uuid", "nextrun"}, false, false},
+	{serviceOffersC, []string{"env-uuid", "service"}, false, false},
+	{resourcesC, []string{"env-uuid", "service"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -245,13 +251,17 @@ func newState(session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (
 	if isCollectionExistsError(err) {
 		return nil, maybeUnauthorized(err, "cannot create transaction collection")
 	}
+	if err := initAuditLog(db); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Create and set up State.
 	st := &State{
-		mongoInfo: mongoInfo,
-		policy:    policy,
-		db:        db,
-		watcher:   watcher.New(txnLog),
+		mongoInfo:  mongoInfo,
+		policy:     policy,
+		db:         db,
+		watcher:    watcher.New(txnLog),
+		coalescing: DefaultWatcherCoalescing,
 	}
 	defer func() {
 		if resultErr != nil {
@@ -294,6 +304,9 @@ func (st *State) CACert() string {
 }
 
 func (st *State) Close() (err error) {
+	if st.borrowed {
+		return nil
+	}
 	defer errors.DeferredAnnotatef(&err, "closing state failed")
 	err1 := st.watcher.Stop()
 	var err2 error
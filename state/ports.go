@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 
@@ -40,15 +41,32 @@ type PortRange struct {
 	FromPort int
 	ToPort   int
 	Protocol string
+
+	// SourceCIDR, if not empty, restricts the port range to
+	// connections from addresses in the given CIDR.
+	SourceCIDR string `bson:"sourcecidr,omitempty"`
 }
 
 // NewPortRange create a new port range and validate it.
 func NewPortRange(unitName string, fromPort, toPort int, protocol string) (PortRange, error) {
+	return newSourcePortRange(unitName, fromPort, toPort, protocol, "")
+}
+
+// PortRangeFromNetworkPortRange constructs a state.PortRange from the
+// given unitName and network.PortRange.
+func PortRangeFromNetworkPortRange(unitName string, portRange network.PortRange) (PortRange, error) {
+	return newSourcePortRange(unitName, portRange.FromPort, portRange.ToPort, portRange.Protocol, portRange.SourceCIDR)
+}
+
+// newSourcePortRange creates a new port range open to connections from
+// the given source CIDR, and validates it.
+func newSourcePortRange(unitName string, fromPort, toPort int, protocol, sourceCIDR string) (PortRange, error) {
 	p := PortRange{
-		UnitName: unitName,
-		FromPort: fromPort,
-		ToPort:   toPort,
-		Protocol: strings.ToLower(protocol),
+		UnitName:   unitName,
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		Protocol:   strings.ToLower(protocol),
+		SourceCIDR: sourceCIDR,
 	}
 	if err := p.Validate(); err != nil {
 		return PortRange{}, err
@@ -56,12 +74,6 @@ func NewPortRange(unitName string, fromPort, toPort int, protocol string) (PortR
 	return p, nil
 }
 
-// PortRangeFromNetworkPortRange constructs a state.PortRange from the
-// given unitName and network.PortRange.
-func PortRangeFromNetworkPortRange(unitName string, portRange network.PortRange) (PortRange, error) {
-	return NewPortRange(unitName, portRange.FromPort, portRange.ToPort, portRange.Protocol)
-}
-
 // Validate checks if the port range is valid.
 func (p PortRange) Validate() error {
 	proto := strings.ToLower(p.Protocol)
@@ -78,6 +90,11 @@ func (p PortRange) Validate() error {
 		p.ToPort <= 0 || p.ToPort > 65535 {
 		return errors.Errorf("port range bounds must be between 1 and 65535, got %d-%d", p.FromPort, p.ToPort)
 	}
+	if p.SourceCIDR != "" {
+		if _, _, err := net.ParseCIDR(p.SourceCIDR); err != nil {
+			return errors.Errorf("invalid source CIDR %q", p.SourceCIDR)
+		}
+	}
 	return nil
 }
 
@@ -125,6 +142,10 @@ func (prA PortRange) CheckConflicts(prB PortRange) error {
 	if prA == prB {
 		return nil
 	}
+	// A unit can open the same port range to several sources.
+	if prA.UnitName == prB.UnitName && prA.NetworkPortRange().SamePorts(prB.NetworkPortRange()) {
+		return nil
+	}
 	if prA.Protocol != prB.Protocol {
 		return nil
 	}
@@ -136,9 +157,23 @@ func (prA PortRange) CheckConflicts(prB PortRange) error {
 
 // Strings returns the port range as a string.
 func (p PortRange) String() string {
+	if p.SourceCIDR != "" {
+		return fmt.Sprintf("%d-%d/%s from %s (%q)", p.FromPort, p.ToPort, strings.ToLower(p.Protocol), p.SourceCIDR, p.UnitName)
+	}
 	return fmt.Sprintf("%d-%d/%s (%q)", p.FromPort, p.ToPort, strings.ToLower(p.Protocol), p.UnitName)
 }
 
+// NetworkPortRange returns the port range without the unit that
+// opened it.
+func (p PortRange) NetworkPortRange() network.PortRange {
+	return network.PortRange{
+		FromPort:   p.FromPort,
+		ToPort:     p.ToPort,
+		Protocol:   p.Protocol,
+		SourceCIDR: p.SourceCIDR,
+	}
+}
+
 // portsDoc represents the state of ports opened on machines for networks
 type portsDoc struct {
 	DocID       string      `bson:"_id"`
@@ -329,12 +364,7 @@ func (p *Ports) Refresh() error {
 func (p *Ports) AllPortRanges() map[network.PortRange]string {
 	result := make(map[network.PortRange]string)
 	for _, portRange := range p.doc.Ports {
-		rawRange := network.PortRange{
-			FromPort: portRange.FromPort,
-			ToPort:   portRange.ToPort,
-			Protocol: portRange.Protocol,
-		}
-		result[rawRange] = portRange.UnitName
+		result[portRange.NetworkPortRange()] = portRange.UnitName
 	}
 	return result
 }
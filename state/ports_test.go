@@ -279,7 +279,7 @@ func (s *PortsDocSuite) TestAllPortRanges(c *gc.C) {
 	ranges := s.ports.AllPortRanges()
 	c.Assert(ranges, gc.HasLen, 1)
 
-	c.Assert(ranges[network.PortRange{100, 200, "TCP"}], gc.Equals, s.unit1.Name())
+	c.Assert(ranges[network.PortRange{100, 200, "TCP", ""}], gc.Equals, s.unit1.Name())
 }
 
 func (s *PortsDocSuite) TestOpenInvalidRange(c *gc.C) {
@@ -439,6 +439,19 @@ func MustPortRange(unitName string, fromPort, toPort int, protocol string) state
 	return portRange
 }
 
+func MustSourcePortRange(unitName string, fromPort, toPort int, protocol, sourceCIDR string) state.PortRange {
+	portRange, err := state.PortRangeFromNetworkPortRange(unitName, network.PortRange{
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		Protocol:   protocol,
+		SourceCIDR: sourceCIDR,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return portRange
+}
+
 func (p *PortRangeSuite) TestPortRangeConflicts(c *gc.C) {
 	var testCases = []struct {
 		about    string
@@ -507,7 +520,7 @@ func (p *PortRangeSuite) TestPortRangeConflicts(c *gc.C) {
 		"port ranges .* conflict",
 	}, {
 		"invalid port range",
-		state.PortRange{"wordpress/0", 100, 80, "TCP"},
+		state.PortRange{"wordpress/0", 100, 80, "TCP", ""},
 		MustPortRange("wordpress/0", 80, 80, "TCP"),
 		"invalid port range 100-80",
 	}, {
@@ -525,6 +538,21 @@ func (p *PortRangeSuite) TestPortRangeConflicts(c *gc.C) {
 		MustPortRange("mysql/0", 80, 100, "TCP"),
 		MustPortRange("wordpress/0", 90, 280, "TCP"),
 		"port ranges .* conflict",
+	}, {
+		"same unit, same port range, different sources",
+		MustSourcePortRange("mysql/0", 80, 100, "TCP", "10.0.0.0/8"),
+		MustPortRange("mysql/0", 80, 100, "TCP"),
+		nil,
+	}, {
+		"same unit, overlapping port ranges, different sources",
+		MustSourcePortRange("mysql/0", 80, 100, "TCP", "10.0.0.0/8"),
+		MustSourcePortRange("mysql/0", 90, 110, "TCP", "192.168.0.0/16"),
+		"port ranges .* conflict",
+	}, {
+		"different units, same port range, different sources",
+		MustSourcePortRange("mysql/0", 80, 100, "TCP", "10.0.0.0/8"),
+		MustSourcePortRange("wordpress/0", 80, 100, "TCP", "192.168.0.0/16"),
+		"port ranges .* conflict",
 	}}
 
 	for i, t := range testCases {
@@ -563,14 +591,18 @@ func swapProtocol(protocol string) string {
 }
 
 func (p *PortRangeSuite) TestPortRangeString(c *gc.C) {
-	c.Assert(state.PortRange{"wordpress/42", 80, 80, "TCP"}.String(),
+	c.Assert(state.PortRange{"wordpress/42", 80, 80, "TCP", ""}.String(),
 		gc.Equals,
 		`80-80/tcp ("wordpress/42")`,
 	)
-	c.Assert(state.PortRange{"wordpress/0", 80, 100, "TCP"}.String(),
+	c.Assert(state.PortRange{"wordpress/0", 80, 100, "TCP", ""}.String(),
 		gc.Equals,
 		`80-100/tcp ("wordpress/0")`,
 	)
+	c.Assert(state.PortRange{"wordpress/0", 443, 443, "TCP", "10.0.0.0/8"}.String(),
+		gc.Equals,
+		`443-443/tcp from 10.0.0.0/8 ("wordpress/0")`,
+	)
 }
 
 func (p *PortRangeSuite) TestPortRangeValidityAndLength(c *gc.C) {
@@ -581,67 +613,77 @@ func (p *PortRangeSuite) TestPortRangeValidityAndLength(c *gc.C) {
 		expectedErr  string
 	}{{
 		"single valid port",
-		state.PortRange{"wordpress/0", 80, 80, "tcp"},
+		state.PortRange{"wordpress/0", 80, 80, "tcp", ""},
 		1,
 		"",
 	}, {
 		"valid tcp port range",
-		state.PortRange{"wordpress/0", 80, 90, "tcp"},
+		state.PortRange{"wordpress/0", 80, 90, "tcp", ""},
 		11,
 		"",
 	}, {
 		"valid udp port range",
-		state.PortRange{"wordpress/0", 80, 90, "UDP"},
+		state.PortRange{"wordpress/0", 80, 90, "UDP", ""},
 		11,
 		"",
 	}, {
 		"invalid port range boundaries",
-		state.PortRange{"wordpress/0", 90, 80, "tcp"},
+		state.PortRange{"wordpress/0", 90, 80, "tcp", ""},
 		0,
 		"invalid port range.*",
 	}, {
 		"invalid protocol",
-		state.PortRange{"wordpress/0", 80, 80, "some protocol"},
+		state.PortRange{"wordpress/0", 80, 80, "some protocol", ""},
 		0,
 		"invalid protocol.*",
 	}, {
 		"invalid unit",
-		state.PortRange{"invalid unit", 80, 80, "tcp"},
+		state.PortRange{"invalid unit", 80, 80, "tcp", ""},
 		0,
 		"invalid unit.*",
+	}, {
+		"valid source CIDR",
+		state.PortRange{"wordpress/0", 80, 80, "tcp", "10.0.0.0/8"},
+		1,
+		"",
+	}, {
+		"invalid source CIDR",
+		state.PortRange{"wordpress/0", 80, 80, "tcp", "10.0.0.0"},
+		0,
+		`invalid source CIDR "10.0.0.0".*`,
 	}, {
 		"negative lower bound",
-		state.PortRange{"wordpress/0", -10, 10, "tcp"},
+		state.PortRange{"wordpress/0", -10, 10, "tcp", ""},
 		0,
 		"port range bounds must be between 1 and 65535.*",
 	}, {
 		"zero lower bound",
-		state.PortRange{"wordpress/0", 0, 10, "tcp"},
+		state.PortRange{"wordpress/0", 0, 10, "tcp", ""},
 		0,
 		"port range bounds must be between 1 and 65535.*",
 	}, {
 		"negative upper bound",
-		state.PortRange{"wordpress/0", 10, -10, "tcp"},
+		state.PortRange{"wordpress/0", 10, -10, "tcp", ""},
 		0,
 		"invalid port range.*",
 	}, {
 		"zero upper bound",
-		state.PortRange{"wordpress/0", 10, 0, "tcp"},
+		state.PortRange{"wordpress/0", 10, 0, "tcp", ""},
 		0,
 		"invalid port range.*",
 	}, {
 		"too large lower bound",
-		state.PortRange{"wordpress/0", 65540, 99999, "tcp"},
+		state.PortRange{"wordpress/0", 65540, 99999, "tcp", ""},
 		0,
 		"port range bounds must be between 1 and 65535.*",
 	}, {
 		"too large upper bound",
-		state.PortRange{"wordpress/0", 10, 99999, "tcp"},
+		state.PortRange{"wordpress/0", 10, 99999, "tcp", ""},
 		0,
 		"port range bounds must be between 1 and 65535.*",
 	}, {
 		"longest valid range",
-		state.PortRange{"wordpress/0", 1, 65535, "tcp"},
+		state.PortRange{"wordpress/0", 1, 65535, "tcp", ""},
 		65535,
 		"",
 	}}
@@ -664,56 +706,56 @@ func (p *PortRangeSuite) TestSanitizeBounds(c *gc.C) {
 		output state.PortRange
 	}{{
 		"valid range",
-		state.PortRange{"", 100, 200, ""},
-		state.PortRange{"", 100, 200, ""},
+		state.PortRange{"", 100, 200, "", ""},
+		state.PortRange{"", 100, 200, "", ""},
 	}, {
 		"negative lower bound",
-		state.PortRange{"", -10, 10, ""},
-		state.PortRange{"", 1, 10, ""},
+		state.PortRange{"", -10, 10, "", ""},
+		state.PortRange{"", 1, 10, "", ""},
 	}, {
 		"zero lower bound",
-		state.PortRange{"", 0, 10, ""},
-		state.PortRange{"", 1, 10, ""},
+		state.PortRange{"", 0, 10, "", ""},
+		state.PortRange{"", 1, 10, "", ""},
 	}, {
 		"negative upper bound",
-		state.PortRange{"", 42, -20, ""},
-		state.PortRange{"", 1, 42, ""},
+		state.PortRange{"", 42, -20, "", ""},
+		state.PortRange{"", 1, 42, "", ""},
 	}, {
 		"zero upper bound",
-		state.PortRange{"", 42, 0, ""},
-		state.PortRange{"", 1, 42, ""},
+		state.PortRange{"", 42, 0, "", ""},
+		state.PortRange{"", 1, 42, "", ""},
 	}, {
 		"both bounds negative",
-		state.PortRange{"", -10, -20, ""},
-		state.PortRange{"", 1, 1, ""},
+		state.PortRange{"", -10, -20, "", ""},
+		state.PortRange{"", 1, 1, "", ""},
 	}, {
 		"both bounds zero",
-		state.PortRange{"", 0, 0, ""},
-		state.PortRange{"", 1, 1, ""},
+		state.PortRange{"", 0, 0, "", ""},
+		state.PortRange{"", 1, 1, "", ""},
 	}, {
 		"swapped bounds",
-		state.PortRange{"", 20, 10, ""},
-		state.PortRange{"", 10, 20, ""},
+		state.PortRange{"", 20, 10, "", ""},
+		state.PortRange{"", 10, 20, "", ""},
 	}, {
 		"too large upper bound",
-		state.PortRange{"", 20, 99999, ""},
-		state.PortRange{"", 20, 65535, ""},
+		state.PortRange{"", 20, 99999, "", ""},
+		state.PortRange{"", 20, 65535, "", ""},
 	}, {
 		"too large lower bound",
-		state.PortRange{"", 99999, 10, ""},
-		state.PortRange{"", 10, 65535, ""},
+		state.PortRange{"", 99999, 10, "", ""},
+		state.PortRange{"", 10, 65535, "", ""},
 	}, {
 		"both bounds too large",
-		state.PortRange{"", 88888, 99999, ""},
-		state.PortRange{"", 65535, 65535, ""},
+		state.PortRange{"", 88888, 99999, "", ""},
+		state.PortRange{"", 65535, 65535, "", ""},
 	}, {
 		"lower negative, upper too large",
-		state.PortRange{"", -10, 99999, ""},
-		state.PortRange{"", 1, 65535, ""},
+		state.PortRange{"", -10, 99999, "", ""},
+		state.PortRange{"", 1, 65535, "", ""},
 	}, {
 		"lower zero, upper too large",
-		state.PortRange{"", 0, 99999, ""},
-		state.PortRange{"", 1, 65535, ""},
+		state.PortRange{"", 0, 99999, "", ""},
+		state.PortRange{"", 1, 65535, "", ""},
 	}}
 	for i, t := range tests {
 		c.Logf("test %d: %s", i, t.about)
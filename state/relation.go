@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -44,6 +45,15 @@ type relationDoc struct {
 	Endpoints []Endpoint
 	Life      Life
 	UnitCount int
+
+	// DrainDeadline is set while the relation drains, and holds the
+	// time at which it will be destroyed.
+	DrainDeadline time.Time `bson:"draindeadline,omitempty"`
+
+	// SubordinateSelector holds the annotations a principal unit must
+	// carry for a subordinate to be attached to it by a container
+	// scoped relation.
+	SubordinateSelector map[string]string `bson:"subordinateselector,omitempty"`
 }
 
 // Relation represents a relation between one or two service endpoints.
@@ -93,6 +103,18 @@ func (r *Relation) Refresh() error {
 	return nil
 }
 
+// SubordinateSelector returns the annotations a principal unit must
+// carry, either itself or on its machine, for the relation to attach a
+// subordinate to it. If the result is empty, subordinates are attached
+// to every principal unit.
+func (r *Relation) SubordinateSelector() map[string]string {
+	selector := make(map[string]string)
+	for key, value := range r.doc.SubordinateSelector {
+		selector[key] = value
+	}
+	return selector
+}
+
 // Life returns the relation's current life state.
 func (r *Relation) Life() Life {
 	return r.doc.Life
@@ -147,7 +169,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 		return nil, false, errAlreadyDying
 	}
 	if r.doc.UnitCount == 0 {
-		removeOps, err := r.removeOps(ignoreService, nil)
+		removeOps, err := r.removeOps(ignoreService, "")
 		if err != nil {
 			return nil, false, err
 		}
@@ -163,16 +185,17 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 
 // removeOps returns the operations necessary to remove the relation. If
 // ignoreService is not empty, no operations affecting that service will be
-// included; if departingUnit is not nil, this implies that the relation's
+// included; if departingService is not empty, a unit of that service is
+// leaving the relation's last scope, which implies that the relation's
 // services may be Dying and otherwise unreferenced, and may thus require
 // removal themselves.
-func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.Op, error) {
+func (r *Relation) removeOps(ignoreService string, departingService string) ([]txn.Op, error) {
 	relOp := txn.Op{
 		C:      relationsC,
 		Id:     r.doc.DocID,
 		Remove: true,
 	}
-	if departingUnit != nil {
+	if departingService != "" {
 		relOp.Assert = bson.D{{"life", Dying}, {"unitcount", 1}}
 	} else {
 		relOp.Assert = bson.D{{"life", Alive}, {"unitcount", 0}}
@@ -182,14 +205,21 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 		if ep.ServiceName == ignoreService {
 			continue
 		}
+		// Remote services have no units of their own to consider.
+		if remoteOps, isRemote, err := remoteServiceDecrefOps(r.st, ep.ServiceName); err != nil {
+			return nil, err
+		} else if isRemote {
+			ops = append(ops, remoteOps...)
+			continue
+		}
 		var asserts bson.D
 		hasRelation := bson.D{{"relationcount", bson.D{{"$gt", 0}}}}
-		if departingUnit == nil {
+		if departingService == "" {
 			// We're constructing a destroy operation, either of the relation
 			// or one of its services, and can therefore be assured that both
 			// services are Alive.
 			asserts = append(hasRelation, isAliveDoc...)
-		} else if ep.ServiceName == departingUnit.ServiceName() {
+		} else if ep.ServiceName == departingService {
 			// This service must have at least one unit -- the one that's
 			// departing the relation -- so it cannot be ready for removal.
 			cannotDieYet := bson.D{{"unitcount", bson.D{{"$gt", 0}}}}
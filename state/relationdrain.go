@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Drain starts the relation draining. A draining relation stays alive,
+// and its units stay in scope, until the given timeout has passed; this
+// gives the charms of its units the chance to move traffic elsewhere
+// before the relation is destroyed by the state server and broken. A
+// relation with no units in scope, or drained with no timeout, is
+// destroyed immediately. It is not an error to drain a relation that is
+// already draining, dying or removed.
+func (r *Relation) Drain(timeout time.Duration) (err error) {
+	if timeout <= 0 {
+		return r.Destroy()
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot drain relation %q", r)
+	if len(r.doc.Endpoints) == 1 && r.doc.Endpoints[0].Role == charm.RolePeer {
+		return fmt.Errorf("is a peer relation")
+	}
+	deadline := nowToTheSecond().Add(timeout)
+	rel := &Relation{r.st, r.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := rel.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if rel.doc.Life != Alive || !rel.doc.DrainDeadline.IsZero() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if rel.doc.UnitCount == 0 {
+			ops, _, err := rel.destroyOps("")
+			return ops, err
+		}
+		return []txn.Op{{
+			C:  relationsC,
+			Id: rel.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"unitcount", bson.D{{"$gt", 0}}},
+				{"draindeadline", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", bson.D{{"draindeadline", deadline}}}},
+		}}, nil
+	}
+	return r.st.run(buildTxn)
+}
+
+// DrainDeadline returns the time at which the draining relation will be
+// destroyed, and whether it is draining at all.
+func (r *Relation) DrainDeadline() (time.Time, bool) {
+	return r.doc.DrainDeadline, !r.doc.DrainDeadline.IsZero()
+}
+
+// DrainingRelations returns the alive relations that are draining,
+// ordered by the time at which they are due to be destroyed.
+func (st *State) DrainingRelations() ([]*Relation, error) {
+	relations, closer := st.getCollection(relationsC)
+	defer closer()
+
+	var docs []relationDoc
+	query := bson.D{
+		{"life", Alive},
+		{"draindeadline", bson.D{{"$exists", true}}},
+	}
+	if err := relations.Find(query).Sort("draindeadline").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get draining relations")
+	}
+	result := make([]*Relation, len(docs))
+	for i := range docs {
+		result[i] = newRelation(st, &docs[i])
+	}
+	return result, nil
+}
+
+// WatchDrainingRelations returns a watcher that notifies of changes to
+// the environment's relations, including those that start them
+// draining.
+func (st *State) WatchDrainingRelations() NotifyWatcher {
+	return newCollectionWatcher(st, relationsC)
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type RelationDrainSuite struct {
+	ConnSuite
+	rel *state.Relation
+	ru  *state.RelationUnit
+}
+
+var _ = gc.Suite(&RelationDrainSuite{})
+
+func (s *RelationDrainSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.rel, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.ru, err = s.rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RelationDrainSuite) TestDrain(c *gc.C) {
+	_, draining := s.rel.DrainDeadline()
+	c.Assert(draining, jc.IsFalse)
+
+	before := time.Now()
+	err := s.rel.Drain(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rel.Life(), gc.Equals, state.Alive)
+	deadline, draining := s.rel.DrainDeadline()
+	c.Assert(draining, jc.IsTrue)
+	c.Assert(deadline.After(before.Add(time.Minute-time.Second)), jc.IsTrue)
+	c.Assert(deadline.Before(time.Now().Add(time.Minute+time.Second)), jc.IsTrue)
+
+	// Draining again does not move the deadline.
+	err = s.rel.Drain(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	again, _ := s.rel.DrainDeadline()
+	c.Assert(again, gc.Equals, deadline)
+
+	relations, err := s.State.DrainingRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 1)
+	c.Assert(relations[0].Id(), gc.Equals, s.rel.Id())
+
+	// Destroying a draining relation breaks it as usual.
+	err = s.rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rel.Life(), gc.Equals, state.Dying)
+	relations, err = s.State.DrainingRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations, gc.HasLen, 0)
+}
+
+func (s *RelationDrainSuite) TestDrainWithoutTimeout(c *gc.C) {
+	err := s.rel.Drain(0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.rel.Life(), gc.Equals, state.Dying)
+	_, draining := s.rel.DrainDeadline()
+	c.Assert(draining, jc.IsFalse)
+}
+
+func (s *RelationDrainSuite) TestDrainWithoutUnits(c *gc.C) {
+	err := s.ru.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Drain(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RelationDrainSuite) TestDrainPeerRelation(c *gc.C) {
+	riak := s.AddTestingService(c, "riak", s.AddTestingCharm(c, "riak"))
+	rels, err := riak.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	err = rels[0].Drain(time.Minute)
+	c.Assert(err, gc.ErrorMatches, `cannot drain relation "riak:ring": is a peer relation`)
+}
+
+func (s *RelationDrainSuite) TestWatchDrainingRelations(c *gc.C) {
+	w := s.State.WatchDrainingRelations()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.rel.Drain(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
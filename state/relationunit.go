@@ -222,6 +222,11 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 	selSubordinate := bson.D{{"service", serviceName}, {"principal", unitName}}
 	var lDoc lifeDoc
 	if err := units.Find(selSubordinate).One(&lDoc); err == mgo.ErrNotFound {
+		if selected, err := ru.selectedPrincipal(); err != nil {
+			return nil, "", err
+		} else if !selected {
+			return nil, "", nil
+		}
 		service, err := ru.st.Service(serviceName)
 		if err != nil {
 			return nil, "", err
@@ -240,6 +245,39 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 	}}, lDoc.Id, nil
 }
 
+// selectedPrincipal returns whether the relation's subordinate selector
+// allows a subordinate to be attached to the principal unit. Each of
+// the selector's annotations must be carried by the unit or its machine.
+func (ru *RelationUnit) selectedPrincipal() (bool, error) {
+	selector := ru.relation.doc.SubordinateSelector
+	if len(selector) == 0 {
+		return true, nil
+	}
+	unitAnnotations, err := ru.st.Annotations(ru.unit)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	var machineAnnotations map[string]string
+	if machineId, err := ru.unit.AssignedMachineId(); err == nil {
+		machine, err := ru.st.Machine(machineId)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if machineAnnotations, err = ru.st.Annotations(machine); err != nil {
+			return false, errors.Trace(err)
+		}
+	} else if !errors.IsNotAssigned(err) {
+		return false, errors.Trace(err)
+	}
+	for key, value := range selector {
+		if unitAnnotations[key] != value && machineAnnotations[key] != value {
+			logger.Debugf("not attaching %q subordinate to %q: %s=%s not matched", ru.relation, ru.unit, key, value)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // PrepareLeaveScope causes the unit to be reported as departed by watchers,
 // but does not *actually* leave the scope, to avoid triggering relation
 // cleanup.
@@ -270,13 +308,20 @@ func (ru *RelationUnit) PrepareLeaveScope() error {
 // leaves, it is removed immediately. It is not an error to leave a scope
 // that the unit is not, or never was, a member of.
 func (ru *RelationUnit) LeaveScope() error {
-	relationScopes, closer := ru.st.getCollection(relationScopesC)
-	defer closer()
-
 	key, err := ru.key(ru.unit.Name())
 	if err != nil {
 		return err
 	}
+	desc := fmt.Sprintf("unit %q in relation %q", ru.unit, ru.relation)
+	return leaveScope(ru.st, ru.relation, key, ru.unit.ServiceName(), desc)
+}
+
+// leaveScope removes the scope document with the given key, which
+// belongs to a unit of the named service, from the relation.
+func leaveScope(st *State, relation *Relation, key, serviceName, desc string) error {
+	relationScopes, closer := st.getCollection(relationScopesC)
+	defer closer()
+
 	// The logic below is involved because we remove a dying relation
 	// with the last unit that leaves a scope in it. It handles three
 	// possible cases:
@@ -299,10 +344,9 @@ func (ru *RelationUnit) LeaveScope() error {
 	// to have a Dying relation with a smaller-than-real unit count, because
 	// Destroy changes the Life attribute in memory (units could join before
 	// the database is actually changed).
-	desc := fmt.Sprintf("unit %q in relation %q", ru.unit, ru.relation)
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
-			if err := ru.relation.Refresh(); errors.IsNotFound(err) {
+			if err := relation.Refresh(); errors.IsNotFound(err) {
 				return nil, jujutxn.ErrNoOperations
 			} else if err != nil {
 				return nil, err
@@ -316,26 +360,26 @@ func (ru *RelationUnit) LeaveScope() error {
 		}
 		ops := []txn.Op{{
 			C:      relationScopesC,
-			Id:     ru.st.docID(key),
+			Id:     st.docID(key),
 			Assert: txn.DocExists,
 			Remove: true,
 		}}
-		if ru.relation.doc.Life == Alive {
+		if relation.doc.Life == Alive {
 			ops = append(ops, txn.Op{
 				C:      relationsC,
-				Id:     ru.relation.doc.DocID,
+				Id:     relation.doc.DocID,
 				Assert: bson.D{{"life", Alive}},
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
-		} else if ru.relation.doc.UnitCount > 1 {
+		} else if relation.doc.UnitCount > 1 {
 			ops = append(ops, txn.Op{
 				C:      relationsC,
-				Id:     ru.relation.doc.DocID,
+				Id:     relation.doc.DocID,
 				Assert: bson.D{{"unitcount", bson.D{{"$gt", 1}}}},
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
 		} else {
-			relOps, err := ru.relation.removeOps("", ru.unit)
+			relOps, err := relation.removeOps("", serviceName)
 			if err != nil {
 				return nil, err
 			}
@@ -343,7 +387,7 @@ func (ru *RelationUnit) LeaveScope() error {
 		}
 		return ops, nil
 	}
-	if err = ru.st.run(buildTxn); err != nil {
+	if err := st.run(buildTxn); err != nil {
 		return fmt.Errorf("cannot leave scope for %s: %v", desc, err)
 	}
 	return nil
@@ -293,6 +293,67 @@ func (s *RelationUnitSuite) TestContainerCreateSubordinate(c *gc.C) {
 	assertJoined(c, pru)
 }
 
+func (s *RelationUnitSuite) TestContainerSubordinateSelector(c *gc.C) {
+	psvc := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	rsvc := s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelationWithSelector(map[string]string{"tier": "prod", "zone": "east"}, eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.SubordinateSelector(), jc.DeepEquals, map[string]string{"tier": "prod", "zone": "east"})
+
+	// The first principal carries both annotations, one of them on its
+	// machine; the second carries only one.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(machine, map[string]string{"zone": "east"})
+	c.Assert(err, jc.ErrorIsNil)
+	selected, err := psvc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = selected.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(selected, map[string]string{"tier": "prod"})
+	c.Assert(err, jc.ErrorIsNil)
+	unselected, err := psvc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAnnotations(unselected, map[string]string{"tier": "prod"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both principals enter scope, but only the selected one gets a
+	// subordinate.
+	for _, punit := range []*state.Unit{unselected, selected} {
+		pru, err := rel.Unit(punit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = pru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		assertJoined(c, pru)
+	}
+	runits, err := rsvc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(runits, gc.HasLen, 1)
+	principal, ok := runits[0].PrincipalName()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(principal, gc.Equals, selected.Name())
+}
+
+func (s *RelationUnitSuite) TestAddRelationWithSelectorErrors(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelationWithSelector(map[string]string{"tier": "prod"}, eps...)
+	c.Assert(err, gc.ErrorMatches, `cannot add relation ".*": only container scoped relations can select principal units`)
+
+	eps, err = s.State.InferEndpoints("mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelationWithSelector(map[string]string{"a.b": "prod"}, eps...)
+	c.Assert(err, gc.ErrorMatches, `cannot add relation ".*": subordinate selector key "a.b" not valid`)
+	_, err = s.State.AddRelationWithSelector(map[string]string{"tier": ""}, eps...)
+	c.Assert(err, gc.ErrorMatches, `cannot add relation ".*": empty value for subordinate selector key "tier" not valid`)
+}
+
 func (s *RelationUnitSuite) TestDestroyRelationWithUnitsInScope(c *gc.C) {
 	pr := NewPeerRelation(c, s.State, s.Owner)
 	rel := pr.ru0.Relation()
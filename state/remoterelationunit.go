@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RemoteRelationUnit holds information about a unit of a remote service
+// in a relation. Remote units are not otherwise represented in the
+// environment; they enter and leave the relation's scope on behalf of
+// the units of the offering environment, and local units see them just
+// as they would local ones.
+type RemoteRelationUnit struct {
+	st       *State
+	relation *Relation
+	unitName string
+	endpoint Endpoint
+	scope    string
+}
+
+// RemoteUnit returns a RemoteRelationUnit for the named unit of a
+// remote service in the relation.
+func (r *Relation) RemoteUnit(unitName string) (*RemoteRelationUnit, error) {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return nil, errors.Errorf("%q is not a valid unit name", unitName)
+	}
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.st.RemoteService(serviceName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Remote services only take part in relations of global scope.
+	return &RemoteRelationUnit{
+		st:       r.st,
+		relation: r,
+		unitName: unitName,
+		endpoint: ep,
+		scope:    strings.Join([]string{"r", strconv.Itoa(r.doc.Id)}, "#"),
+	}, nil
+}
+
+// UnitName returns the name of the remote unit.
+func (ru *RemoteRelationUnit) UnitName() string {
+	return ru.unitName
+}
+
+// Relation returns the relation associated with the unit.
+func (ru *RemoteRelationUnit) Relation() *Relation {
+	return ru.relation
+}
+
+// Endpoint returns the relation endpoint that defines the unit's
+// participation in the relation.
+func (ru *RemoteRelationUnit) Endpoint() Endpoint {
+	return ru.endpoint
+}
+
+func (ru *RemoteRelationUnit) key() string {
+	return strings.Join([]string{ru.scope, string(ru.endpoint.Role), ru.unitName}, "#")
+}
+
+// EnterScope ensures that the remote unit has entered its scope in the
+// relation, with the supplied settings. When the unit is already in
+// scope, its settings are replaced.
+func (ru *RemoteRelationUnit) EnterScope(settings map[string]interface{}) error {
+	db, closer := ru.st.newDB()
+	defer closer()
+	envUUID := ru.st.EnvironUUID()
+	relationScopes := getCollectionFromDB(db, relationScopesC, envUUID)
+	settingsColl := getCollectionFromDB(db, settingsC, envUUID)
+
+	ruKey := ru.key()
+	prefix := fmt.Sprintf("cannot enter scope for remote unit %q in relation %q: ", ru.unitName, ru.relation)
+	if count, err := relationScopes.FindId(ruKey).Count(); err != nil {
+		return err
+	} else if count != 0 {
+		// The settings of a remote unit change without it leaving
+		// scope.
+		op, _, err := replaceSettingsOp(ru.st, ruKey, settings)
+		if err != nil {
+			return err
+		}
+		if err := ru.st.runTransaction([]txn.Op{op}); err == txn.ErrAborted {
+			return fmt.Errorf(prefix + "concurrent settings change detected")
+		} else if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	ops := []txn.Op{{
+		C:      remoteServicesC,
+		Id:     ru.st.docID(ru.endpoint.ServiceName),
+		Assert: isAliveDoc,
+	}, {
+		C:      relationsC,
+		Id:     ru.relation.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"unitcount", 1}}}},
+	}}
+	// Settings may be left behind by a previous membership of the scope.
+	settingsChanged := func() (bool, error) { return false, nil }
+	if count, err := settingsColl.FindId(ruKey).Count(); err != nil {
+		return err
+	} else if count == 0 {
+		ops = append(ops, createSettingsOp(ru.st, ruKey, settings))
+	} else {
+		var rop txn.Op
+		rop, settingsChanged, err = replaceSettingsOp(ru.st, ruKey, settings)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, rop)
+	}
+	rsDocID := ru.st.docID(ruKey)
+	ops = append(ops, txn.Op{
+		C:      relationScopesC,
+		Id:     rsDocID,
+		Assert: txn.DocMissing,
+		Insert: relationScopeDoc{
+			DocID:   rsDocID,
+			Key:     ruKey,
+			EnvUUID: envUUID,
+		},
+	})
+	if err := ru.st.runTransaction(ops); err != txn.ErrAborted {
+		return err
+	}
+	if count, err := relationScopes.FindId(rsDocID).Count(); err != nil {
+		return err
+	} else if count != 0 {
+		return nil
+	}
+	remoteServices := getCollectionFromDB(db, remoteServicesC, envUUID)
+	relations := getCollectionFromDB(db, relationsC, envUUID)
+	if alive, err := isAliveWithSession(remoteServices, ru.st.docID(ru.endpoint.ServiceName)); err != nil {
+		return err
+	} else if !alive {
+		return ErrCannotEnterScope
+	}
+	if alive, err := isAliveWithSession(relations, ru.relation.doc.DocID); err != nil {
+		return err
+	} else if !alive {
+		return ErrCannotEnterScope
+	}
+	if changed, err := settingsChanged(); err != nil {
+		return err
+	} else if changed {
+		return fmt.Errorf(prefix + "concurrent settings change detected")
+	}
+	return fmt.Errorf(prefix + "inconsistent state in EnterScope")
+}
+
+// LeaveScope signals that the remote unit has left its scope in the
+// relation. If the relation is dying when its last member unit leaves,
+// it is removed immediately. It is not an error to leave a scope that
+// the unit is not, or never was, a member of.
+func (ru *RemoteRelationUnit) LeaveScope() error {
+	desc := fmt.Sprintf("remote unit %q in relation %q", ru.unitName, ru.relation)
+	return leaveScope(ru.st, ru.relation, ru.key(), ru.endpoint.ServiceName, desc)
+}
+
+// InScope returns whether the remote unit has entered scope and not
+// left it.
+func (ru *RemoteRelationUnit) InScope() (bool, error) {
+	relationScopes, closer := ru.st.getCollection(relationScopesC)
+	defer closer()
+
+	count, err := relationScopes.FindId(ru.key()).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Settings returns the settings of the remote unit within the relation.
+func (ru *RemoteRelationUnit) Settings() (map[string]interface{}, error) {
+	node, err := readSettings(ru.st, ru.key())
+	if err != nil {
+		return nil, err
+	}
+	return node.Map(), nil
+}
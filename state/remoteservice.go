@@ -0,0 +1,412 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// remoteServiceDoc records a service of another environment, consumed
+// through one of that environment's offers, with which local services
+// may be related.
+type remoteServiceDoc struct {
+	DocID   string `bson:"_id"`
+	Name    string `bson:"name"`
+	EnvUUID string `bson:"env-uuid"`
+
+	// SourceEnvUUID and OfferName identify the consumed offer.
+	SourceEnvUUID string `bson:"source-env-uuid"`
+	OfferName     string `bson:"offer"`
+
+	Endpoints     []charm.Relation `bson:"endpoints"`
+	Life          Life             `bson:"life"`
+	RelationCount int              `bson:"relationcount"`
+}
+
+// RemoteService represents the service of another environment behind a
+// consumed offer. Its units are not modelled in this environment; they
+// enter and leave the scopes of its relations as RemoteRelationUnits.
+type RemoteService struct {
+	st  *State
+	doc remoteServiceDoc
+}
+
+// Name returns the name by which the service is known in this
+// environment.
+func (s *RemoteService) Name() string {
+	return s.doc.Name
+}
+
+// String returns the service name.
+func (s *RemoteService) String() string {
+	return s.doc.Name
+}
+
+// SourceEnvironTag returns the tag of the environment making the offer.
+func (s *RemoteService) SourceEnvironTag() names.EnvironTag {
+	return names.NewEnvironTag(s.doc.SourceEnvUUID)
+}
+
+// OfferName returns the name of the consumed offer.
+func (s *RemoteService) OfferName() string {
+	return s.doc.OfferName
+}
+
+// Life returns whether the remote service is Alive or Dying.
+func (s *RemoteService) Life() Life {
+	return s.doc.Life
+}
+
+// Endpoints returns the offered endpoints of the service.
+func (s *RemoteService) Endpoints() []Endpoint {
+	eps := make([]Endpoint, len(s.doc.Endpoints))
+	for i, rel := range s.doc.Endpoints {
+		eps[i] = Endpoint{
+			ServiceName: s.doc.Name,
+			Relation:    rel,
+		}
+	}
+	sort.Sort(epSlice(eps))
+	return eps
+}
+
+// Endpoint returns the offered endpoint with the supplied name.
+func (s *RemoteService) Endpoint(relationName string) (Endpoint, error) {
+	for _, ep := range s.Endpoints() {
+		if ep.Name == relationName {
+			return ep, nil
+		}
+	}
+	return Endpoint{}, errors.Errorf("remote service %q has no %q relation", s, relationName)
+}
+
+// Relations returns the relations in which the remote service takes
+// part.
+func (s *RemoteService) Relations() ([]*Relation, error) {
+	return serviceRelations(s.st, s.doc.Name)
+}
+
+// Refresh refreshes the contents of the RemoteService from the
+// underlying state. It returns an error that satisfies
+// errors.IsNotFound if the remote service has been removed.
+func (s *RemoteService) Refresh() error {
+	remoteServices, closer := s.st.getCollection(remoteServicesC)
+	defer closer()
+
+	err := remoteServices.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("remote service %q", s)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh remote service %q", s)
+	}
+	return nil
+}
+
+// Destroy ensures that the remote service and all its relations will be
+// removed at some point; if no relation involving the service has any
+// units in scope, they are all removed immediately.
+func (s *RemoteService) Destroy() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy remote service %q", s)
+	defer func() {
+		if err == nil {
+			// This is a white lie; the document might actually be removed.
+			s.doc.Life = Dying
+		}
+	}()
+	svc := &RemoteService{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := svc.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		switch ops, err := svc.destroyOps(); err {
+		case errRefresh:
+		case errAlreadyDying:
+			return nil, jujutxn.ErrNoOperations
+		case nil:
+			return ops, nil
+		default:
+			return nil, err
+		}
+		return nil, jujutxn.ErrTransientFailure
+	}
+	return s.st.run(buildTxn)
+}
+
+// destroyOps returns the operations required to destroy the remote
+// service. If it returns errRefresh, the service should be refreshed
+// and the destruction operations recalculated.
+func (s *RemoteService) destroyOps() ([]txn.Op, error) {
+	if s.doc.Life == Dying {
+		return nil, errAlreadyDying
+	}
+	rels, err := s.Relations()
+	if err != nil {
+		return nil, err
+	}
+	if len(rels) != s.doc.RelationCount {
+		return nil, errRefresh
+	}
+	var ops []txn.Op
+	removeCount := 0
+	for _, rel := range rels {
+		relOps, isRemove, err := rel.destroyOps(s.doc.Name)
+		if err == errAlreadyDying {
+			relOps = []txn.Op{{
+				C:      relationsC,
+				Id:     rel.doc.DocID,
+				Assert: bson.D{{"life", Dying}},
+			}}
+		} else if err != nil {
+			return nil, err
+		}
+		if isRemove {
+			removeCount++
+		}
+		ops = append(ops, relOps...)
+	}
+	// If all its known relations will be removed, the remote service
+	// can also be removed; otherwise it is removed along with the last
+	// relation referencing it.
+	if s.doc.RelationCount == removeCount {
+		return append(ops, txn.Op{
+			C:      remoteServicesC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"relationcount", removeCount}},
+			Remove: true,
+		}), nil
+	}
+	update := bson.D{{"$set", bson.D{{"life", Dying}}}}
+	if removeCount != 0 {
+		decref := bson.D{{"$inc", bson.D{{"relationcount", -removeCount}}}}
+		update = append(update, decref...)
+	}
+	return append(ops, txn.Op{
+		C:      remoteServicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"relationcount", s.doc.RelationCount}},
+		Update: update,
+	}), nil
+}
+
+// remoteServiceDecrefOps returns the operations that release a reference
+// from a relation being removed to the named remote service, removing
+// the service if it is dying and this is its last relation. If there is
+// no such remote service, it returns false.
+func remoteServiceDecrefOps(st *State, name string) ([]txn.Op, bool, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	var doc remoteServiceDoc
+	err := remoteServices.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Annotatef(err, "cannot get remote service %q", name)
+	}
+	if doc.Life == Dying && doc.RelationCount == 1 {
+		return []txn.Op{{
+			C:      remoteServicesC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"life", Dying}, {"relationcount", 1}},
+			Remove: true,
+		}}, true, nil
+	}
+	return []txn.Op{{
+		C:  remoteServicesC,
+		Id: doc.DocID,
+		Assert: bson.D{{"$or", []bson.D{
+			{{"life", Alive}},
+			{{"relationcount", bson.D{{"$gt", 1}}}},
+		}}},
+		Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+	}}, true, nil
+}
+
+// addRemoteRelationOps returns the operations that add a reference from
+// a new relation to the remote service of the endpoint, checking that
+// the service offers the endpoint.
+func (st *State) addRemoteRelationOps(ep Endpoint) ([]txn.Op, error) {
+	svc, err := st.RemoteService(ep.ServiceName)
+	if errors.IsNotFound(err) {
+		return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	} else if svc.doc.Life != Alive {
+		return nil, errors.Errorf("service %q is not alive", ep.ServiceName)
+	}
+	offered, err := svc.Endpoint(ep.Name)
+	if err != nil || offered.Role != ep.Role || offered.Interface != ep.Interface {
+		return nil, errors.Errorf("%q does not implement %q", ep.ServiceName, ep)
+	}
+	return []txn.Op{{
+		C:      remoteServicesC,
+		Id:     svc.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
+	}}, nil
+}
+
+// AddRemoteServiceArgs holds the parameters of a new remote service.
+type AddRemoteServiceArgs struct {
+	// Name is the name by which the service is known in this
+	// environment. It must not be used by any local service.
+	Name string
+
+	// SourceEnvironTag and OfferName identify the consumed offer.
+	SourceEnvironTag names.EnvironTag
+	OfferName        string
+
+	// Endpoints holds the offered relations.
+	Endpoints []charm.Relation
+}
+
+// AddRemoteService records a service of another environment with which
+// local services may be related.
+func (st *State) AddRemoteService(args AddRemoteServiceArgs) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add remote service %q", args.Name)
+	if !names.IsValidService(args.Name) {
+		return nil, errors.Errorf("invalid name")
+	}
+	if args.SourceEnvironTag.Id() == st.EnvironUUID() {
+		return nil, errors.Errorf("offer %q is made by this environment", args.OfferName)
+	}
+	if len(args.Endpoints) == 0 {
+		return nil, errors.Errorf("no endpoints given")
+	}
+	for _, rel := range args.Endpoints {
+		if rel.Role == charm.RolePeer || rel.Scope != charm.ScopeGlobal {
+			return nil, errors.Errorf("endpoint %q cannot be related across environments", rel.Name)
+		}
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	} else if env.Life() != Alive {
+		return nil, errors.Errorf("environment is no longer alive")
+	}
+	doc := remoteServiceDoc{
+		DocID:         st.docID(args.Name),
+		Name:          args.Name,
+		EnvUUID:       st.EnvironUUID(),
+		SourceEnvUUID: args.SourceEnvironTag.Id(),
+		OfferName:     args.OfferName,
+		Endpoints:     args.Endpoints,
+		Life:          Alive,
+	}
+	ops := []txn.Op{
+		env.assertAliveOp(),
+		{
+			// Remote and local services share a namespace.
+			C:      servicesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+		}, {
+			C:      remoteServicesC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		},
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if err := env.Refresh(); err != nil || env.Life() != Alive {
+			return nil, errors.Errorf("environment is no longer alive")
+		}
+		return nil, errors.Errorf("service already exists")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &RemoteService{st: st, doc: doc}, nil
+}
+
+// RemoteService returns the remote service with the given name.
+func (st *State) RemoteService(name string) (*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	if !names.IsValidService(name) {
+		return nil, errors.Errorf("%q is not a valid service name", name)
+	}
+	var doc remoteServiceDoc
+	err := remoteServices.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("remote service %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote service %q", name)
+	}
+	return &RemoteService{st: st, doc: doc}, nil
+}
+
+// AllRemoteServices returns all the remote services in the environment.
+func (st *State) AllRemoteServices() ([]*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	var docs []remoteServiceDoc
+	if err := remoteServices.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get remote services")
+	}
+	result := make([]*RemoteService, len(docs))
+	for i, doc := range docs {
+		result[i] = &RemoteService{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// ConsumeOffer adds a remote service for the named offer of another
+// environment hosted by the same controller. The service is given the
+// name of the offer unless serviceName is not empty.
+func (st *State) ConsumeOffer(sourceEnv names.EnvironTag, offerName, serviceName string) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot consume offer %q", offerName)
+	if sourceEnv.Id() == st.EnvironUUID() {
+		return nil, errors.Errorf("offer is made by this environment")
+	}
+	if serviceName == "" {
+		serviceName = offerName
+	}
+	if _, err := st.GetEnvironment(sourceEnv); err != nil {
+		return nil, errors.Trace(err)
+	}
+	sourceSt, err := st.ForEnviron(sourceEnv)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer sourceSt.Close()
+
+	offer, err := sourceSt.ServiceOffer(offerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	svc, err := sourceSt.Service(offer.ServiceName())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var endpoints []charm.Relation
+	for _, name := range offer.Endpoints() {
+		ep, err := svc.Endpoint(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		endpoints = append(endpoints, ep.Relation)
+	}
+	return st.AddRemoteService(AddRemoteServiceArgs{
+		Name:             serviceName,
+		SourceEnvironTag: sourceEnv,
+		OfferName:        offerName,
+		Endpoints:        endpoints,
+	})
+}
@@ -0,0 +1,263 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RemoteServiceSuite struct {
+	ConnSuite
+
+	// otherState is the environment that offers mysql.
+	otherState *state.State
+	offer      *state.ServiceOffer
+}
+
+var _ = gc.Suite(&RemoteServiceSuite{})
+
+func (s *RemoteServiceSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.otherState = s.factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.otherState.Close() })
+	ch := state.AddTestingCharm(c, s.otherState, "mysql")
+	state.AddTestingService(c, s.otherState, "mysql", ch, s.Owner)
+	var err error
+	s.offer, err = s.otherState.AddServiceOffer(state.AddServiceOfferArgs{
+		Name:        "hosted-mysql",
+		ServiceName: "mysql",
+		Description: "a database",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RemoteServiceSuite) TestAddServiceOffer(c *gc.C) {
+	c.Check(s.offer.Name(), gc.Equals, "hosted-mysql")
+	c.Check(s.offer.ServiceName(), gc.Equals, "mysql")
+	c.Check(s.offer.Endpoints(), jc.DeepEquals, []string{"juju-info", "server"})
+	c.Check(s.offer.Description(), gc.Equals, "a database")
+	c.Check(s.offer.EnvironTag(), gc.Equals, s.otherState.EnvironTag())
+
+	offers, err := s.otherState.ServiceOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, gc.HasLen, 1)
+	c.Check(offers[0].Name(), gc.Equals, "hosted-mysql")
+
+	_, err = s.otherState.AddServiceOffer(state.AddServiceOfferArgs{
+		Name:        "hosted-mysql",
+		ServiceName: "mysql",
+	})
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	err = offers[0].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.otherState.ServiceOffer("hosted-mysql")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestAddServiceOfferErrors(c *gc.C) {
+	riak := state.AddTestingCharm(c, s.otherState, "riak")
+	state.AddTestingService(c, s.otherState, "riak", riak, s.Owner)
+	for i, test := range []struct {
+		args state.AddServiceOfferArgs
+		err  string
+	}{{
+		args: state.AddServiceOfferArgs{Name: "bad name", ServiceName: "mysql"},
+		err:  `cannot add offer "bad name": invalid name`,
+	}, {
+		args: state.AddServiceOfferArgs{Name: "db", ServiceName: "postgresql"},
+		err:  `cannot add offer "db": service "postgresql" not found`,
+	}, {
+		args: state.AddServiceOfferArgs{Name: "db", ServiceName: "mysql", Endpoints: []string{"nonsense"}},
+		err:  `cannot add offer "db": service "mysql" has no "nonsense" relation`,
+	}, {
+		args: state.AddServiceOfferArgs{Name: "db", ServiceName: "riak", Endpoints: []string{"ring"}},
+		err:  `cannot add offer "db": endpoint "riak:ring" cannot be offered`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.otherState.AddServiceOffer(test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RemoteServiceSuite) TestDestroyServiceRemovesOffers(c *gc.C) {
+	svc, err := s.otherState.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.otherState.ServiceOffer("hosted-mysql")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestConsumeOffer(c *gc.C) {
+	remote, err := s.State.ConsumeOffer(s.otherState.EnvironTag(), "hosted-mysql", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(remote.Name(), gc.Equals, "hosted-mysql")
+	c.Check(remote.SourceEnvironTag(), gc.Equals, s.otherState.EnvironTag())
+	c.Check(remote.OfferName(), gc.Equals, "hosted-mysql")
+	c.Check(remote.Life(), gc.Equals, state.Alive)
+	ep, err := remote.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ep, gc.DeepEquals, state.Endpoint{
+		ServiceName: "hosted-mysql",
+		Relation: charm.Relation{
+			Name:      "server",
+			Role:      charm.RoleProvider,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		},
+	})
+
+	remotes, err := s.State.AllRemoteServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remotes, gc.HasLen, 1)
+	c.Check(remotes[0].Name(), gc.Equals, "hosted-mysql")
+
+	// Remote and local services share a namespace.
+	_, err = s.State.ConsumeOffer(s.otherState.EnvironTag(), "hosted-mysql", "")
+	c.Check(err, gc.ErrorMatches, `cannot consume offer "hosted-mysql": cannot add remote service "hosted-mysql": service already exists`)
+	_, err = s.State.AddService("hosted-mysql", s.Owner.String(), s.AddTestingCharm(c, "mysql"), nil, nil)
+	c.Check(err, gc.ErrorMatches, `cannot add service "hosted-mysql": service already exists`)
+}
+
+func (s *RemoteServiceSuite) TestConsumeOfferErrors(c *gc.C) {
+	_, err := s.State.ConsumeOffer(s.otherState.EnvironTag(), "nonsense", "")
+	c.Check(err, gc.ErrorMatches, `cannot consume offer "nonsense": offer "nonsense" not found`)
+	_, err = s.State.ConsumeOffer(s.State.EnvironTag(), "hosted-mysql", "")
+	c.Check(err, gc.ErrorMatches, `cannot consume offer "hosted-mysql": offer is made by this environment`)
+}
+
+func (s *RemoteServiceSuite) addRelation(c *gc.C) (*state.Relation, *state.RemoteService) {
+	remote, err := s.State.ConsumeOffer(s.otherState.EnvironTag(), "hosted-mysql", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel, remote
+}
+
+func (s *RemoteServiceSuite) TestAddRelation(c *gc.C) {
+	rel, remote := s.addRelation(c)
+	c.Check(rel.String(), gc.Equals, "wordpress:db mysql:server")
+	rels, err := remote.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	c.Check(rels[0].Id(), gc.Equals, rel.Id())
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitEnterScope(c *gc.C) {
+	rel, _ := s.addRelation(c)
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	w := ru.WatchScope()
+	defer testing.AssertStop(c, w)
+	s.assertScopeChange(c, w, nil, nil)
+
+	remoteRU, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoteRU.EnterScope(map[string]interface{}{"user": "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	inScope, err := remoteRU.InScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inScope, jc.IsTrue)
+	s.assertScopeChange(c, w, []string{"mysql/0"}, nil)
+
+	// Local units read the settings of remote units just as they do
+	// those of local ones.
+	settings, err := ru.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+
+	// Entering scope again replaces the settings.
+	err = remoteRU.EnterScope(map[string]interface{}{"password": "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = remoteRU.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(settings, jc.DeepEquals, map[string]interface{}{"password": "sekrit"})
+
+	err = remoteRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertScopeChange(c, w, nil, []string{"mysql/0"})
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitErrors(c *gc.C) {
+	rel, _ := s.addRelation(c)
+	_, err := rel.RemoteUnit("mysql")
+	c.Check(err, gc.ErrorMatches, `"mysql" is not a valid unit name`)
+	_, err = rel.RemoteUnit("riak/0")
+	c.Check(err, gc.ErrorMatches, `service "riak" is not a member of "wordpress:db mysql:server"`)
+	_, err = rel.RemoteUnit("wordpress/0")
+	c.Check(err, gc.ErrorMatches, `remote service "wordpress" not found`)
+}
+
+func (s *RemoteServiceSuite) TestDestroyRemoteService(c *gc.C) {
+	rel, remote := s.addRelation(c)
+	remoteRU, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = remoteRU.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The relation has a unit in scope, so both it and the remote
+	// service are kept until the unit leaves.
+	err = remote.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = remote.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(remote.Life(), gc.Equals, state.Dying)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rel.Life(), gc.Equals, state.Dying)
+
+	err = remoteRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	err = remote.Refresh()
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestDestroyRelationWithRemoteService(c *gc.C) {
+	rel, remote := s.addRelation(c)
+	err := rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	rels, err := remote.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rels, gc.HasLen, 0)
+
+	// With no relations left, the remote service is removed at once.
+	err = remote.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RemoteService("mysql")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) assertScopeChange(c *gc.C, w *state.RelationScopeWatcher, entered, left []string) {
+	s.State.StartSync()
+	select {
+	case ch, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		sort.Strings(ch.Entered)
+		c.Assert(ch.Entered, gc.DeepEquals, entered)
+		sort.Strings(ch.Left)
+		c.Assert(ch.Left, gc.DeepEquals, left)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("no change")
+	}
+}
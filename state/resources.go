@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/blobstore"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state/resourcestorage"
+)
+
+var (
+	resourcestorageNewStorage = resourcestorage.NewStorage
+)
+
+// ResourceStorage returns a new resourcestorage.StorageCloser
+// that stores charm resource metadata in the "juju" database's
+// "resources" collection.
+func (st *State) ResourceStorage() (resourcestorage.StorageCloser, error) {
+	uuid := st.EnvironUUID()
+	session := st.db.Session.Copy()
+	txnRunner := st.txnRunner(session)
+	rs := blobstore.NewGridFS(blobstoreDB, uuid, session)
+	db := st.db.With(session)
+	managedStorage := blobstore.NewManagedStorage(db, rs)
+	metadataCollection := db.C(resourcesC)
+	storage := resourcestorageNewStorage(uuid, managedStorage, metadataCollection, txnRunner)
+	return &resourceStorageCloser{storage, session}, nil
+}
+
+type resourceStorageCloser struct {
+	resourcestorage.Storage
+	session *mgo.Session
+}
+
+func (r *resourceStorageCloser) Close() error {
+	r.session.Close()
+	return nil
+}
+
+// cleanupResourcesForRemovedService removes the charm resources
+// attached to the named service, which has been removed.
+func (st *State) cleanupResourcesForRemovedService(serviceName string) error {
+	storage, err := st.ResourceStorage()
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+	return storage.RemoveServiceResources(serviceName)
+}
+
+// hasResources reports whether any charm resources are attached to
+// the service. Errors are logged and treated as there being resources,
+// so that a cleanup is scheduled regardless.
+func (s *Service) hasResources() bool {
+	resources, closer := s.st.getCollection(resourcesC)
+	defer closer()
+	n, err := resources.Find(bson.D{
+		{"env-uuid", s.st.EnvironUUID()},
+		{"service", s.doc.Name},
+	}).Count()
+	if err != nil {
+		logger.Warningf("cannot count resources of service %q: %v", s.doc.Name, err)
+		return true
+	}
+	return n > 0
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/resourcestorage"
+)
+
+type ResourcesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func (s *ResourcesSuite) TestResourceStorage(c *gc.C) {
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	metadata, err := storage.AddResource(strings.NewReader("abc"), resourcestorage.Metadata{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Size:        3,
+		SHA256:      "hash(abc)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.Revision, gc.Equals, 1)
+
+	resources, err := storage.ServiceResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, []resourcestorage.Metadata{metadata})
+}
+
+func (s *ResourcesSuite) TestResourcesRemovedWithService(c *gc.C) {
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = storage.AddResource(strings.NewReader("abc"), resourcestorage.Metadata{
+		ServiceName: "wordpress",
+		Name:        "theme",
+		Size:        3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	dirty, err := s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dirty, jc.IsTrue)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = storage.Metadata("wordpress", "theme")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	dirty, err = s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dirty, jc.IsFalse)
+}
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcestorage
+
+import (
+	"io"
+	"time"
+)
+
+// Metadata describes a resource blob attached to a service.
+type Metadata struct {
+	ServiceName string
+	Name        string
+	Revision    int
+	Size        int64
+	SHA256      string
+	Uploaded    time.Time
+}
+
+// Storage provides methods for storing and retrieving the resources
+// attached to services.
+type Storage interface {
+	// AddResource adds the resource blob and metadata into state,
+	// replacing any existing resource with the same service and name.
+	// The revision and upload time of the supplied metadata are
+	// ignored; the stored metadata, with the resource's new revision,
+	// is returned.
+	AddResource(io.Reader, Metadata) (Metadata, error)
+
+	// Resource returns the Metadata and blob contents of the named
+	// resource of the service if it exists, else an error satisfying
+	// errors.IsNotFound.
+	Resource(serviceName, name string) (Metadata, io.ReadCloser, error)
+
+	// Metadata returns the Metadata of the named resource of the
+	// service if it exists, else an error satisfying errors.IsNotFound.
+	Metadata(serviceName, name string) (Metadata, error)
+
+	// ServiceResources returns the metadata of all the resources
+	// attached to the service, ordered by name.
+	ServiceResources(serviceName string) ([]Metadata, error)
+
+	// RemoveServiceResources removes all the resources attached to the
+	// service.
+	RemoveServiceResources(serviceName string) error
+}
+
+// StorageCloser extends the Storage interface with a Close method.
+type StorageCloser interface {
+	Storage
+	Close() error
+}
@@ -0,0 +1,232 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcestorage
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+var logger = loggo.GetLogger("juju.state.resourcestorage")
+
+type resourceStorage struct {
+	envUUID            string
+	managedStorage     blobstore.ManagedStorage
+	metadataCollection *mgo.Collection
+	txnRunner          jujutxn.Runner
+}
+
+var _ Storage = (*resourceStorage)(nil)
+
+// NewStorage constructs a new Storage that stores resource blobs
+// in the provided ManagedStorage, and resource metadata in the provided
+// collection using the provided transaction runner.
+func NewStorage(
+	envUUID string,
+	managedStorage blobstore.ManagedStorage,
+	metadataCollection *mgo.Collection,
+	runner jujutxn.Runner,
+) Storage {
+	return &resourceStorage{
+		envUUID:            envUUID,
+		managedStorage:     managedStorage,
+		metadataCollection: metadataCollection,
+		txnRunner:          runner,
+	}
+}
+
+type resourceMetadataDoc struct {
+	Id          string    `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	ServiceName string    `bson:"service"`
+	Name        string    `bson:"name"`
+	Revision    int       `bson:"revision"`
+	Size        int64     `bson:"size"`
+	SHA256      string    `bson:"sha256"`
+	Uploaded    time.Time `bson:"uploaded"`
+	Path        string    `bson:"path"`
+}
+
+func (doc resourceMetadataDoc) metadata() Metadata {
+	return Metadata{
+		ServiceName: doc.ServiceName,
+		Name:        doc.Name,
+		Revision:    doc.Revision,
+		Size:        doc.Size,
+		SHA256:      doc.SHA256,
+		Uploaded:    doc.Uploaded,
+	}
+}
+
+func (s *resourceStorage) AddResource(r io.Reader, metadata Metadata) (_ Metadata, resultErr error) {
+	// Each upload is stored at a path of its own, so that a failure
+	// to record it never removes the blob of the current revision.
+	path := resourcePath(metadata.ServiceName, metadata.Name, bson.NewObjectId().Hex())
+	if err := s.managedStorage.PutForEnvironment(s.envUUID, path, r, metadata.Size); err != nil {
+		return Metadata{}, errors.Annotate(err, "cannot store resource")
+	}
+	defer func() {
+		if resultErr == nil {
+			return
+		}
+		err := s.managedStorage.RemoveForEnvironment(s.envUUID, path)
+		if err != nil {
+			logger.Errorf("failed to remove resource blob: %v", err)
+		}
+	}()
+
+	newDoc := resourceMetadataDoc{
+		Id:          s.docId(metadata.ServiceName, metadata.Name),
+		EnvUUID:     s.envUUID,
+		ServiceName: metadata.ServiceName,
+		Name:        metadata.Name,
+		Revision:    1,
+		Size:        metadata.Size,
+		SHA256:      metadata.SHA256,
+		Uploaded:    time.Now().UTC().Round(time.Second),
+		Path:        path,
+	}
+
+	// Add or replace metadata. If replacing, record the
+	// existing path so we can remove it later.
+	var oldPath string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		op := txn.Op{
+			C:  s.metadataCollection.Name,
+			Id: newDoc.Id,
+		}
+		oldDoc, err := s.resourceMetadata(metadata.ServiceName, metadata.Name)
+		if errors.IsNotFound(err) {
+			oldPath = ""
+			newDoc.Revision = 1
+			op.Assert = txn.DocMissing
+			op.Insert = &newDoc
+			return []txn.Op{op}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		oldPath = oldDoc.Path
+		newDoc.Revision = oldDoc.Revision + 1
+		op.Assert = bson.D{{"revision", oldDoc.Revision}}
+		op.Update = bson.D{{
+			"$set", bson.D{
+				{"revision", newDoc.Revision},
+				{"size", newDoc.Size},
+				{"sha256", newDoc.SHA256},
+				{"uploaded", newDoc.Uploaded},
+				{"path", newDoc.Path},
+			},
+		}}
+		return []txn.Op{op}, nil
+	}
+	if err := s.txnRunner.Run(buildTxn); err != nil {
+		return Metadata{}, errors.Annotate(err, "cannot store resource metadata")
+	}
+
+	if oldPath != "" {
+		// Attempt to remove the old path. Failure is non-fatal.
+		err := s.managedStorage.RemoveForEnvironment(s.envUUID, oldPath)
+		if err != nil {
+			logger.Errorf("failed to remove old resource blob: %v", err)
+		} else {
+			logger.Debugf("removed old resource blob")
+		}
+	}
+	return newDoc.metadata(), nil
+}
+
+func (s *resourceStorage) Resource(serviceName, name string) (Metadata, io.ReadCloser, error) {
+	doc, err := s.resourceMetadata(serviceName, name)
+	if err != nil {
+		return Metadata{}, nil, err
+	}
+	r, _, err := s.managedStorage.GetForEnvironment(s.envUUID, doc.Path)
+	if err != nil {
+		return Metadata{}, nil, err
+	}
+	return doc.metadata(), r, nil
+}
+
+func (s *resourceStorage) Metadata(serviceName, name string) (Metadata, error) {
+	doc, err := s.resourceMetadata(serviceName, name)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return doc.metadata(), nil
+}
+
+func (s *resourceStorage) ServiceResources(serviceName string) ([]Metadata, error) {
+	docs, err := s.serviceResourceDocs(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Metadata, len(docs))
+	for i, doc := range docs {
+		list[i] = doc.metadata()
+	}
+	return list, nil
+}
+
+func (s *resourceStorage) RemoveServiceResources(serviceName string) error {
+	docs, err := s.serviceResourceDocs(serviceName)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		buildTxn := func(attempt int) ([]txn.Op, error) {
+			return []txn.Op{{
+				C:      s.metadataCollection.Name,
+				Id:     doc.Id,
+				Remove: true,
+			}}, nil
+		}
+		if err := s.txnRunner.Run(buildTxn); err != nil {
+			return errors.Annotatef(err, "cannot remove resource %q", doc.Name)
+		}
+		err := s.managedStorage.RemoveForEnvironment(s.envUUID, doc.Path)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "cannot remove resource %q", doc.Name)
+		}
+	}
+	return nil
+}
+
+func (s *resourceStorage) docId(serviceName, name string) string {
+	return fmt.Sprintf("%s:%s/%s", s.envUUID, serviceName, name)
+}
+
+func (s *resourceStorage) resourceMetadata(serviceName, name string) (resourceMetadataDoc, error) {
+	var doc resourceMetadataDoc
+	err := s.metadataCollection.FindId(s.docId(serviceName, name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return doc, errors.NotFoundf("resource %q of service %q", name, serviceName)
+	} else if err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+func (s *resourceStorage) serviceResourceDocs(serviceName string) ([]resourceMetadataDoc, error) {
+	var docs []resourceMetadataDoc
+	query := bson.D{{"env-uuid", s.envUUID}, {"service", serviceName}}
+	if err := s.metadataCollection.Find(query).Sort("name").All(&docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// resourcePath returns the storage path for an upload of the specified
+// resource.
+func resourcePath(serviceName, name, uploadId string) string {
+	return fmt.Sprintf("resources/%s/%s/%s", serviceName, name, uploadId)
+}
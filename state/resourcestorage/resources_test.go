@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcestorage_test
+
+import (
+	"io/ioutil"
+	"strings"
+	stdtesting "testing"
+
+	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	jujutxn "github.com/juju/txn"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/state/resourcestorage"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type ResourcesSuite struct {
+	testing.BaseSuite
+	mongo          *gitjujutesting.MgoInstance
+	session        *mgo.Session
+	storage        resourcestorage.Storage
+	managedStorage blobstore.ManagedStorage
+}
+
+func (s *ResourcesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mongo = &gitjujutesting.MgoInstance{}
+	s.mongo.Start(nil)
+
+	var err error
+	s.session, err = s.mongo.Dial()
+	c.Assert(err, jc.ErrorIsNil)
+	rs := blobstore.NewGridFS("blobstore", "my-uuid", s.session)
+	catalogue := s.session.DB("catalogue")
+	s.managedStorage = blobstore.NewManagedStorage(catalogue, rs)
+	runner := jujutxn.NewRunner(jujutxn.RunnerParams{Database: catalogue})
+	s.storage = resourcestorage.NewStorage("my-uuid", s.managedStorage, catalogue.C("resources"), runner)
+}
+
+func (s *ResourcesSuite) TearDownTest(c *gc.C) {
+	s.session.Close()
+	s.mongo.DestroyWithLog()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *ResourcesSuite) addResource(c *gc.C, service, name, content string) resourcestorage.Metadata {
+	metadata, err := s.storage.AddResource(strings.NewReader(content), resourcestorage.Metadata{
+		ServiceName: service,
+		Name:        name,
+		Size:        int64(len(content)),
+		SHA256:      "hash(" + content + ")",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return metadata
+}
+
+func (s *ResourcesSuite) assertResource(c *gc.C, service, name, content string, revision int) {
+	metadata, rc, err := s.storage.Resource(service, name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer rc.Close()
+	c.Assert(metadata.Revision, gc.Equals, revision)
+	c.Assert(metadata.SHA256, gc.Equals, "hash("+content+")")
+	data, err := ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+}
+
+func (s *ResourcesSuite) TestAddResource(c *gc.C) {
+	metadata := s.addResource(c, "wordpress", "theme", "abc")
+	c.Assert(metadata.ServiceName, gc.Equals, "wordpress")
+	c.Assert(metadata.Name, gc.Equals, "theme")
+	c.Assert(metadata.Revision, gc.Equals, 1)
+	c.Assert(metadata.Size, gc.Equals, int64(3))
+	c.Assert(metadata.Uploaded.IsZero(), jc.IsFalse)
+	s.assertResource(c, "wordpress", "theme", "abc", 1)
+}
+
+func (s *ResourcesSuite) TestAddResourceReplaces(c *gc.C) {
+	s.addResource(c, "wordpress", "theme", "abc")
+	metadata := s.addResource(c, "wordpress", "theme", "def")
+	c.Assert(metadata.Revision, gc.Equals, 2)
+	s.assertResource(c, "wordpress", "theme", "def", 2)
+
+	// Uploading the same content again still makes a new revision.
+	metadata = s.addResource(c, "wordpress", "theme", "def")
+	c.Assert(metadata.Revision, gc.Equals, 3)
+	s.assertResource(c, "wordpress", "theme", "def", 3)
+}
+
+func (s *ResourcesSuite) TestResourceNotFound(c *gc.C) {
+	_, _, err := s.storage.Resource("wordpress", "theme")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `resource "theme" of service "wordpress" not found`)
+	_, err = s.storage.Metadata("wordpress", "theme")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ResourcesSuite) TestServiceResources(c *gc.C) {
+	s.addResource(c, "wordpress", "theme", "abc")
+	s.addResource(c, "wordpress", "plugins", "def")
+	s.addResource(c, "mysql", "dump", "ghi")
+
+	resources, err := s.storage.ServiceResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+	c.Assert(resources[0].Name, gc.Equals, "plugins")
+	c.Assert(resources[1].Name, gc.Equals, "theme")
+
+	resources, err = s.storage.ServiceResources("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 0)
+}
+
+func (s *ResourcesSuite) TestRemoveServiceResources(c *gc.C) {
+	s.addResource(c, "wordpress", "theme", "abc")
+	s.addResource(c, "mysql", "dump", "ghi")
+
+	err := s.storage.RemoveServiceResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storage.Metadata("wordpress", "theme")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertResource(c, "mysql", "dump", "ghi", 1)
+
+	// Removing again is not an error.
+	err = s.storage.RemoveServiceResources("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -34,6 +34,10 @@ const (
 type restoreInfoDoc struct {
 	Id     string        `bson:"_id"`
 	Status RestoreStatus `bson:"status"`
+
+	// Unreachable holds the ids of the machines whose agents
+	// could not be given the restored state server's addresses.
+	Unreachable []string `bson:"unreachable,omitempty"`
 }
 
 // RestoreInfo its used to syncronize Restore and machine agent
@@ -47,6 +51,29 @@ func (info *RestoreInfo) Status() RestoreStatus {
 	return info.doc.Status
 }
 
+// UnreachableMachines returns the ids of the machines whose agents
+// could not be given the addresses of the restored state server, and
+// so must be updated manually.
+func (info *RestoreInfo) UnreachableMachines() []string {
+	return info.doc.Unreachable
+}
+
+// SetUnreachableMachines records the ids of the machines whose agents
+// could not be given the addresses of the restored state server.
+func (info *RestoreInfo) SetUnreachableMachines(ids []string) error {
+	ops := []txn.Op{{
+		C:      restoreInfoC,
+		Id:     currentRestoreId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"unreachable", ids}}}},
+	}}
+	if err := info.st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot record unreachable machines")
+	}
+	info.doc.Unreachable = ids
+	return nil
+}
+
 // SetStatus sets the status of the current restore. Checks are made
 // to ensure that status changes are performed in the correct order.
 func (info *RestoreInfo) SetStatus(status RestoreStatus) error {
@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// scopedCredentialDoc records a time-limited credential that gives
+// access to part of the API on behalf of a user. The root key is the
+// secret used to mint and check the macaroon handed out for the
+// credential.
+type scopedCredentialDoc struct {
+	DocID    string    `bson:"_id"`
+	EnvUUID  string    `bson:"env-uuid"`
+	Id       string    `bson:"id"`
+	Owner    string    `bson:"owner"`
+	RootKey  []byte    `bson:"rootkey"`
+	Facades  []string  `bson:"facades"`
+	Entities []string  `bson:"entities"`
+	Expires  time.Time `bson:"expires"`
+}
+
+// ScopedCredential represents a time-limited credential that gives
+// access to some of the API facades, possibly only for some entities,
+// on behalf of the user that created it.
+type ScopedCredential struct {
+	doc scopedCredentialDoc
+}
+
+// Id returns the unique id of the credential.
+func (c *ScopedCredential) Id() string {
+	return c.doc.Id
+}
+
+// Owner returns the tag of the user that the credential acts for.
+func (c *ScopedCredential) Owner() names.UserTag {
+	return names.NewUserTag(c.doc.Owner)
+}
+
+// RootKey returns the secret key of the credential.
+func (c *ScopedCredential) RootKey() []byte {
+	return c.doc.RootKey
+}
+
+// Facades returns the names of the API facades that the credential
+// gives access to.
+func (c *ScopedCredential) Facades() []string {
+	return c.doc.Facades
+}
+
+// Entities returns the tags of the entities that the credential is
+// limited to. If it is empty, the credential is not limited to
+// particular entities.
+func (c *ScopedCredential) Entities() []string {
+	return c.doc.Entities
+}
+
+// Expires returns the time when the credential stops being valid.
+func (c *ScopedCredential) Expires() time.Time {
+	return c.doc.Expires
+}
+
+// ScopedCredentialParams holds the parameters for creating a scoped
+// credential.
+type ScopedCredentialParams struct {
+	// Owner is the user that the credential acts for.
+	Owner names.UserTag
+
+	// Facades holds the names of the API facades that the credential
+	// gives access to; there must be at least one.
+	Facades []string
+
+	// Entities holds the tags of the entities that the credential is
+	// limited to, if any.
+	Entities []string
+
+	// Expires holds the time when the credential stops being valid.
+	Expires time.Time
+}
+
+// scopedCredentialRootKeySize is the size in bytes of the random root
+// keys of scoped credentials.
+const scopedCredentialRootKeySize = 24
+
+// AddScopedCredential records a new scoped credential with a random
+// id and root key.
+func (st *State) AddScopedCredential(args ScopedCredentialParams) (_ *ScopedCredential, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add scoped credential for %s", args.Owner.Username())
+	if len(args.Facades) == 0 {
+		return nil, errors.New("no facades specified")
+	}
+	for _, tag := range args.Entities {
+		if _, err := names.ParseTag(tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rootKey := make([]byte, scopedCredentialRootKeySize)
+	if _, err := rand.Read(rootKey); err != nil {
+		return nil, errors.Annotate(err, "cannot generate root key")
+	}
+	id := uuid.String()
+	doc := scopedCredentialDoc{
+		DocID:    st.docID(id),
+		EnvUUID:  st.EnvironUUID(),
+		Id:       id,
+		Owner:    args.Owner.Username(),
+		RootKey:  rootKey,
+		Facades:  args.Facades,
+		Entities: args.Entities,
+		Expires:  args.Expires.UTC(),
+	}
+	ops := []txn.Op{{
+		C:      scopedCredentialsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ScopedCredential{doc}, nil
+}
+
+// ScopedCredential returns the scoped credential with the given id.
+func (st *State) ScopedCredential(id string) (*ScopedCredential, error) {
+	credentials, closer := st.getCollection(scopedCredentialsC)
+	defer closer()
+
+	var doc scopedCredentialDoc
+	err := credentials.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("scoped credential %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get scoped credential %q", id)
+	}
+	return &ScopedCredential{doc}, nil
+}
+
+// RemoveScopedCredential revokes the scoped credential with the given
+// id.
+func (st *State) RemoveScopedCredential(id string) error {
+	ops := []txn.Op{{
+		C:      scopedCredentialsC,
+		Id:     st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("scoped credential %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove scoped credential %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ScopedCredentialSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ScopedCredentialSuite{})
+
+func (s *ScopedCredentialSuite) TestAddScopedCredential(c *gc.C) {
+	expires := time.Now().Add(time.Hour)
+	cred, err := s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:    names.NewUserTag("bob"),
+		Facades:  []string{"Client"},
+		Entities: []string{"unit-wordpress-0"},
+		Expires:  expires,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Id(), gc.Not(gc.Equals), "")
+	c.Assert(cred.RootKey(), gc.HasLen, 24)
+
+	got, err := s.State.ScopedCredential(cred.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Owner(), gc.Equals, names.NewUserTag("bob@local"))
+	c.Assert(got.RootKey(), jc.DeepEquals, cred.RootKey())
+	c.Assert(got.Facades(), jc.DeepEquals, []string{"Client"})
+	c.Assert(got.Entities(), jc.DeepEquals, []string{"unit-wordpress-0"})
+	c.Assert(got.Expires().Unix(), gc.Equals, expires.Unix())
+}
+
+func (s *ScopedCredentialSuite) TestAddScopedCredentialDistinctKeys(c *gc.C) {
+	args := state.ScopedCredentialParams{
+		Owner:   names.NewUserTag("bob"),
+		Facades: []string{"Client"},
+		Expires: time.Now().Add(time.Hour),
+	}
+	cred0, err := s.State.AddScopedCredential(args)
+	c.Assert(err, jc.ErrorIsNil)
+	cred1, err := s.State.AddScopedCredential(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred0.Id(), gc.Not(gc.Equals), cred1.Id())
+	c.Assert(cred0.RootKey(), gc.Not(jc.DeepEquals), cred1.RootKey())
+}
+
+func (s *ScopedCredentialSuite) TestAddScopedCredentialInvalid(c *gc.C) {
+	_, err := s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:   names.NewUserTag("bob"),
+		Expires: time.Now().Add(time.Hour),
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot add scoped credential for bob@local: no facades specified")
+
+	_, err = s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:    names.NewUserTag("bob"),
+		Facades:  []string{"Client"},
+		Entities: []string{"wordpress"},
+		Expires:  time.Now().Add(time.Hour),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add scoped credential for bob@local: "wordpress" is not a valid tag`)
+}
+
+func (s *ScopedCredentialSuite) TestRemoveScopedCredential(c *gc.C) {
+	cred, err := s.State.AddScopedCredential(state.ScopedCredentialParams{
+		Owner:   names.NewUserTag("bob"),
+		Facades: []string{"Client"},
+		Expires: time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveScopedCredential(cred.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ScopedCredential(cred.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveScopedCredential(cred.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -30,22 +30,24 @@ type Service struct {
 // serviceDoc represents the internal state of a service in MongoDB.
 // Note the correspondence with ServiceInfo in apiserver/params.
 type serviceDoc struct {
-	DocID             string     `bson:"_id"`
-	Name              string     `bson:"name"`
-	EnvUUID           string     `bson:"env-uuid"`
-	Series            string     `bson:"series"`
-	Subordinate       bool       `bson:"subordinate"`
-	CharmURL          *charm.URL `bson:"charmurl"`
-	ForceCharm        bool       `bson:forcecharm"`
-	Life              Life       `bson:"life"`
-	UnitSeq           int        `bson:"unitseq"`
-	UnitCount         int        `bson:"unitcount"`
-	RelationCount     int        `bson:"relationcount"`
-	Exposed           bool       `bson:"exposed"`
-	MinUnits          int        `bson:"minunits"`
-	OwnerTag          string     `bson:"ownertag"`
-	TxnRevno          int64      `bson:"txn-revno"`
-	MetricCredentials []byte     `bson:"metric-credentials"`
+	DocID             string            `bson:"_id"`
+	Name              string            `bson:"name"`
+	EnvUUID           string            `bson:"env-uuid"`
+	Series            string            `bson:"series"`
+	Subordinate       bool              `bson:"subordinate"`
+	CharmURL          *charm.URL        `bson:"charmurl"`
+	ForceCharm        bool              `bson:forcecharm"`
+	Life              Life              `bson:"life"`
+	UnitSeq           int               `bson:"unitseq"`
+	UnitCount         int               `bson:"unitcount"`
+	RelationCount     int               `bson:"relationcount"`
+	Exposed           bool              `bson:"exposed"`
+	MinUnits          int               `bson:"minunits"`
+	OwnerTag          string            `bson:"ownertag"`
+	TxnRevno          int64             `bson:"txn-revno"`
+	MetricCredentials []byte            `bson:"metric-credentials"`
+	SpaceBinding      string            `bson:"spacebinding,omitempty"`
+	EndpointBindings  map[string]string `bson:"endpointbindings,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -148,6 +150,13 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 		return nil, errRefresh
 	}
 	ops := []txn.Op{minUnitsRemoveOp(s.st, s.doc.Name)}
+	// A service that is going away can no longer be consumed by other
+	// environments.
+	offerOps, err := removeServiceOffersOps(s.st, s.doc.Name)
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops, offerOps...)
 	removeCount := 0
 	for _, rel := range rels {
 		relOps, isRemove, err := rel.destroyOps(s.doc.Name)
@@ -230,6 +239,12 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
 	}
+	if s.hasResources() {
+		ops = append(ops, s.st.newCleanupOp(cleanupResourcesForRemovedService, s.doc.Name))
+	}
+	if hasEntityBlocks(s.st, s.Tag()) {
+		ops = append(ops, s.st.newCleanupOp(cleanupBlocksForRemovedEntity, s.Tag().String()))
+	}
 	return ops
 }
 
@@ -266,6 +281,106 @@ func (s *Service) setExposed(exposed bool) (err error) {
 	return nil
 }
 
+// SpaceBinding returns the name of the network space the service's
+// endpoints are bound to, or the empty string if they are not bound.
+func (s *Service) SpaceBinding() string {
+	return s.doc.SpaceBinding
+}
+
+// SetSpaceBinding binds all the service's endpoints to the named space,
+// which must exist, so that its units are given addresses in the
+// space's subnets.
+func (s *Service) SetSpaceBinding(spaceName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot bind service %q to space %q", s, spaceName)
+	space, err := s.st.Space(spaceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      spacesC,
+		Id:     space.doc.DocID,
+		Assert: txn.DocExists,
+	}, {
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"spacebinding", spaceName}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	s.doc.SpaceBinding = spaceName
+	return nil
+}
+
+// EndpointBindings returns the names of the network spaces individual
+// endpoints of the service are bound to, keyed on the endpoint names.
+// Endpoints not in the result are bound to the space returned by
+// SpaceBinding, if any.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string)
+	for endpoint, spaceName := range s.doc.EndpointBindings {
+		bindings[endpoint] = spaceName
+	}
+	return bindings
+}
+
+// EndpointSpace returns the name of the network space the service's
+// endpoint with the given name is bound to, or the empty string if it
+// is not bound.
+func (s *Service) EndpointSpace(endpoint string) string {
+	if spaceName, ok := s.doc.EndpointBindings[endpoint]; ok {
+		return spaceName
+	}
+	return s.doc.SpaceBinding
+}
+
+// SetEndpointBindings binds the service's endpoints named in bindings
+// to the spaces they map to, which must exist, replacing any earlier
+// endpoint bindings. Endpoints not named are bound to the space set
+// with SetSpaceBinding, if any.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot bind endpoints of service %q", s)
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{"charmurl", s.doc.CharmURL}),
+		Update: bson.D{{"$set", bson.D{{"endpointbindings", bindings}}}},
+	}}
+	seenSpaces := make(map[string]bool)
+	for endpoint, spaceName := range bindings {
+		if _, err := s.Endpoint(endpoint); err != nil {
+			return errors.NotFoundf("endpoint %q", endpoint)
+		}
+		if seenSpaces[spaceName] {
+			continue
+		}
+		space, err := s.st.Space(spaceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		seenSpaces[spaceName] = true
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     space.doc.DocID,
+			Assert: txn.DocExists,
+		})
+	}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return errNotAlive
+		}
+		return errors.New("service or spaces changed while binding endpoints")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.EndpointBindings = bindings
+	return nil
+}
+
 // Charm returns the service's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (s *Service) Charm() (ch *Charm, force bool, err error) {
@@ -738,6 +853,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeStatusOp(s.st, u.globalAgentKey()),
 		removeStatusOp(s.st, u.globalKey()),
 		removeMeterStatusOp(s.st, u.globalKey()),
+		removeAgentHealthOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
@@ -828,9 +944,34 @@ func (s *Service) ConfigSettings() (charm.Settings, error) {
 	return settings.Map(), nil
 }
 
+// ConfigSettingsRevision returns the raw user configuration for the
+// service's charm, as ConfigSettings does, along with its revision. The
+// revision changes every time the configuration is written; it can be
+// passed to UpdateConfigSettingsAtRevision to ensure that changes are
+// not made on top of configuration the caller has not seen.
+func (s *Service) ConfigSettingsRevision() (charm.Settings, int64, error) {
+	settings, err := readSettings(s.st, s.settingsKey())
+	if err != nil {
+		return nil, 0, err
+	}
+	return settings.Map(), settings.Revision(), nil
+}
+
 // UpdateConfigSettings changes a service's charm config settings. Values set
 // to nil will be deleted; unknown and invalid values will return an error.
 func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
+	return s.updateConfigSettings(changes, nil)
+}
+
+// UpdateConfigSettingsAtRevision changes a service's charm config
+// settings, as UpdateConfigSettings does, but only if they are still at
+// the given revision, as returned by ConfigSettingsRevision. If they are
+// not, ErrSettingsChanged is returned and nothing is changed.
+func (s *Service) UpdateConfigSettingsAtRevision(changes charm.Settings, revision int64) error {
+	return s.updateConfigSettings(changes, &revision)
+}
+
+func (s *Service) updateConfigSettings(changes charm.Settings, revision *int64) error {
 	charm, _, err := s.Charm()
 	if err != nil {
 		return err
@@ -847,6 +988,9 @@ func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
 	if err != nil {
 		return err
 	}
+	if revision != nil && node.Revision() != *revision {
+		return ErrSettingsChanged
+	}
 	for name, value := range changes {
 		if value == nil {
 			node.Delete(name)
@@ -854,7 +998,11 @@ func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
 			node.Set(name, value)
 		}
 	}
-	_, err = node.Write()
+	if revision != nil {
+		_, err = node.WriteIfUnchanged()
+	} else {
+		_, err = node.Write()
+	}
 	return err
 }
 
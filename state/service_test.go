@@ -628,6 +628,47 @@ func (s *ServiceSuite) TestUpdateConfigSettings(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestUpdateConfigSettingsAtRevision(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "dummy-service", sch)
+	_, revision, err := svc.ConfigSettingsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = svc.UpdateConfigSettingsAtRevision(charm.Settings{"title": "one"}, revision)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The settings have changed since the revision was read.
+	err = svc.UpdateConfigSettingsAtRevision(charm.Settings{"title": "two"}, revision)
+	c.Assert(err, gc.Equals, state.ErrSettingsChanged)
+
+	settings, revision, err := svc.ConfigSettingsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "one"})
+	err = svc.UpdateConfigSettingsAtRevision(charm.Settings{"title": "two"}, revision)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "two"})
+}
+
+func (s *ServiceSuite) TestUpdateConfigSettingsAtRevisionConcurrentChange(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "dummy-service", sch)
+	_, revision, err := svc.ConfigSettingsRevision()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := svc.UpdateConfigSettings(charm.Settings{"title": "racing"})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = svc.UpdateConfigSettingsAtRevision(charm.Settings{"title": "mine"}, revision)
+	c.Assert(err, gc.Equals, state.ErrSettingsChanged)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "racing"})
+}
+
 func assertNoSettingsRef(c *gc.C, st *state.State, svcName string, sch *state.Charm) {
 	_, err := state.ServiceSettingsRefCount(st, svcName, sch.URL())
 	c.Assert(err, gc.Equals, mgo.ErrNotFound)
@@ -0,0 +1,222 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5-unstable"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// serviceOfferDoc records endpoints of a service that are offered for
+// use by services in other environments hosted by the same controller.
+type serviceOfferDoc struct {
+	DocId       string   `bson:"_id"`
+	EnvUUID     string   `bson:"env-uuid"`
+	Name        string   `bson:"name"`
+	ServiceName string   `bson:"service"`
+	Endpoints   []string `bson:"endpoints"`
+	Description string   `bson:"description,omitempty"`
+}
+
+// ServiceOffer represents endpoints of a service that may be consumed
+// from other environments.
+type ServiceOffer struct {
+	st  *State
+	doc serviceOfferDoc
+}
+
+// Name returns the name under which the endpoints are offered.
+func (o *ServiceOffer) Name() string {
+	return o.doc.Name
+}
+
+// ServiceName returns the name of the offered service.
+func (o *ServiceOffer) ServiceName() string {
+	return o.doc.ServiceName
+}
+
+// Endpoints returns the names of the offered relations.
+func (o *ServiceOffer) Endpoints() []string {
+	return o.doc.Endpoints
+}
+
+// Description returns the description of the offer.
+func (o *ServiceOffer) Description() string {
+	return o.doc.Description
+}
+
+// EnvironTag returns the tag of the environment making the offer.
+func (o *ServiceOffer) EnvironTag() names.EnvironTag {
+	return names.NewEnvironTag(o.doc.EnvUUID)
+}
+
+// AddServiceOfferArgs holds the parameters of a new offer.
+type AddServiceOfferArgs struct {
+	// Name is the name of the offer, which must be unique within the
+	// environment. It is also the name consumers give the service
+	// unless they choose another.
+	Name string
+
+	// ServiceName is the name of the offered service.
+	ServiceName string
+
+	// Endpoints holds the names of the offered relations. If it is
+	// empty, every relation of the service that could be used across
+	// environments is offered.
+	Endpoints []string
+
+	Description string
+}
+
+// offerableEndpoint reports whether the endpoint can be used by a
+// service in another environment. Peer relations only ever involve the
+// service's own units, and container scoped ones require colocation.
+func offerableEndpoint(ep Endpoint) bool {
+	return ep.Role != charm.RolePeer && ep.Scope == charm.ScopeGlobal
+}
+
+// AddServiceOffer offers endpoints of a service for use by other
+// environments.
+func (st *State) AddServiceOffer(args AddServiceOfferArgs) (_ *ServiceOffer, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add offer %q", args.Name)
+	if !names.IsValidService(args.Name) {
+		return nil, errors.Errorf("invalid name")
+	}
+	svc, err := st.Service(args.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if svc.Life() != Alive {
+		return nil, errors.Errorf("service %q is not alive", args.ServiceName)
+	}
+	eps, err := svc.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var endpoints []string
+	if len(args.Endpoints) == 0 {
+		for _, ep := range eps {
+			if offerableEndpoint(ep) {
+				endpoints = append(endpoints, ep.Name)
+			}
+		}
+		if len(endpoints) == 0 {
+			return nil, errors.Errorf("service %q has no endpoints that can be offered", args.ServiceName)
+		}
+	} else {
+		for _, name := range args.Endpoints {
+			ep, err := svc.Endpoint(name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !offerableEndpoint(ep) {
+				return nil, errors.Errorf("endpoint %q cannot be offered", ep)
+			}
+			endpoints = append(endpoints, name)
+		}
+	}
+	sort.Strings(endpoints)
+	doc := serviceOfferDoc{
+		DocId:       st.docID(args.Name),
+		EnvUUID:     st.EnvironUUID(),
+		Name:        args.Name,
+		ServiceName: args.ServiceName,
+		Endpoints:   endpoints,
+		Description: args.Description,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     st.docID(args.ServiceName),
+		Assert: isAliveDoc,
+	}, {
+		C:      serviceOffersC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.ServiceOffer(args.Name); err == nil {
+			return nil, errors.AlreadyExistsf("offer %q", args.Name)
+		}
+		return nil, errors.Errorf("service %q is not alive", args.ServiceName)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ServiceOffer{st: st, doc: doc}, nil
+}
+
+// ServiceOffer returns the offer with the given name.
+func (st *State) ServiceOffer(name string) (*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var doc serviceOfferDoc
+	err := offers.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("offer %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get offer %q", name)
+	}
+	return &ServiceOffer{st: st, doc: doc}, nil
+}
+
+// ServiceOffers returns all the offers made by the environment, ordered
+// by name.
+func (st *State) ServiceOffers() ([]*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := offers.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get offers")
+	}
+	result := make([]*ServiceOffer, len(docs))
+	for i, doc := range docs {
+		result[i] = &ServiceOffer{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// Remove withdraws the offer. Services already consuming the offer are
+// not affected. It is not an error to remove an offer that has already
+// been removed.
+func (o *ServiceOffer) Remove() error {
+	ops := []txn.Op{{
+		C:      serviceOffersC,
+		Id:     o.doc.DocId,
+		Remove: true,
+	}}
+	if err := o.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove offer %q", o.doc.Name)
+	}
+	return nil
+}
+
+// removeServiceOffersOps returns the operations that remove the offers
+// of the named service.
+func removeServiceOffersOps(st *State, serviceName string) ([]txn.Op, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := offers.Find(bson.D{{"service", serviceName}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get offers of service %q", serviceName)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      serviceOffersC,
+			Id:     doc.DocId,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
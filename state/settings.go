@@ -118,6 +118,12 @@ func (c *Settings) Delete(key string) {
 	delete(c.core, key)
 }
 
+// Revision returns the revision of the node when it was last read. The
+// revision changes every time the node is written.
+func (c *Settings) Revision() int64 {
+	return c.txnRevno
+}
+
 // cacheKeys returns the keys of all caches as a key=>true map.
 func cacheKeys(caches ...map[string]interface{}) map[string]bool {
 	keys := make(map[string]bool)
@@ -133,9 +139,23 @@ func cacheKeys(caches ...map[string]interface{}) map[string]bool {
 // as a delta applied on top of the latest version of the node, to prevent
 // overwriting unrelated changes made to the node since it was last read.
 func (c *Settings) Write() ([]ItemChange, error) {
-	changes := []ItemChange{}
-	updates := bson.M{}
-	deletions := bson.M{}
+	return c.write(false)
+}
+
+// WriteIfUnchanged writes changes made to c back onto its node, as Write
+// does, but only if the node has not been written since it was last
+// read. If it has, ErrSettingsChanged is returned and nothing is written.
+func (c *Settings) WriteIfUnchanged() ([]ItemChange, error) {
+	return c.write(true)
+}
+
+// delta returns the changes made to c since it was last read or written,
+// sorted by key, along with the corresponding updates and deletions of
+// the escaped keys of its node.
+func (c *Settings) delta() (changes []ItemChange, updates, deletions bson.M) {
+	changes = []ItemChange{}
+	updates = bson.M{}
+	deletions = bson.M{}
 	for key := range cacheKeys(c.disk, c.core) {
 		old, ondisk := c.disk[key]
 		new, incore := c.core[key]
@@ -159,18 +179,32 @@ func (c *Settings) Write() ([]ItemChange, error) {
 		}
 		changes = append(changes, change)
 	}
+	sort.Sort(itemChangeSlice(changes))
+	return changes, updates, deletions
+}
+
+func (c *Settings) write(checkRevision bool) ([]ItemChange, error) {
+	changes, updates, deletions := c.delta()
 	if len(changes) == 0 {
 		return []ItemChange{}, nil
 	}
-	sort.Sort(itemChangeSlice(changes))
+	var assert interface{} = txn.DocExists
+	if checkRevision {
+		assert = bson.D{{"txn-revno", c.txnRevno}}
+	}
 	ops := []txn.Op{{
 		C:      settingsC,
 		Id:     c.st.docID(c.key),
-		Assert: txn.DocExists,
+		Assert: assert,
 		Update: setUnsetUpdate(updates, deletions),
 	}}
 	err := c.st.runTransaction(ops)
 	if err == txn.ErrAborted {
+		if checkRevision {
+			if _, _, err := readSettingsDoc(c.st, c.key); err == nil {
+				return nil, ErrSettingsChanged
+			}
+		}
 		return nil, errors.NotFoundf("settings")
 	}
 	if err != nil {
@@ -286,6 +320,11 @@ func readSettings(st *State, key string) (*Settings, error) {
 
 var errSettingsExist = fmt.Errorf("cannot overwrite existing settings")
 
+// ErrSettingsChanged is returned when settings are written on the
+// understanding that they have not changed since a given revision, and
+// they have.
+var ErrSettingsChanged = fmt.Errorf("settings changed since they were read")
+
 func createSettingsOp(st *State, key string, values map[string]interface{}) txn.Op {
 	newValues := copyMap(values, escapeReplacer.Replace)
 	newValues["env-uuid"] = st.EnvironUUID()
@@ -451,6 +451,48 @@ func (s *SettingsSuite) TestWriteTwice(c *gc.C) {
 	c.Assert(nodeOne.core, gc.DeepEquals, nodeTwo.core)
 }
 
+func (s *SettingsSuite) TestWriteIfUnchanged(c *gc.C) {
+	nodeOne, err := createSettings(s.state, s.key, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	nodeTwo, err := readSettings(s.state, s.key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodeTwo.Revision(), gc.Equals, nodeOne.Revision())
+
+	nodeOne.Set("a", "foo")
+	changes, err := nodeOne.WriteIfUnchanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.DeepEquals, []ItemChange{
+		{ItemAdded, "a", nil, "foo"},
+	})
+
+	// The node has been written since nodeTwo read it.
+	nodeTwo.Set("a", "bar")
+	_, err = nodeTwo.WriteIfUnchanged()
+	c.Assert(err, gc.Equals, ErrSettingsChanged)
+
+	err = nodeTwo.Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodeTwo.Revision(), gc.Not(gc.Equals), nodeOne.Revision())
+	c.Assert(nodeTwo.Map(), gc.DeepEquals, map[string]interface{}{"a": "foo"})
+	nodeTwo.Set("a", "bar")
+	changes, err = nodeTwo.WriteIfUnchanged()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.DeepEquals, []ItemChange{
+		{ItemModified, "a", "foo", "bar"},
+	})
+}
+
+func (s *SettingsSuite) TestWriteIfUnchangedMissing(c *gc.C) {
+	node, err := createSettings(s.state, s.key, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = removeSettings(s.state, s.key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	node.Set("a", "foo")
+	_, err = node.WriteIfUnchanged()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *SettingsSuite) TestList(c *gc.C) {
 	_, err := createSettings(s.state, "key#1", map[string]interface{}{"foo1": "bar1"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// Space represents a network space: a set of subnets with the same
+// ingress and egress rules, in which units bound to the space are
+// given addresses.
+type Space struct {
+	st  *State
+	doc spaceDoc
+}
+
+type spaceDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Name    string `bson:"name"`
+}
+
+// Name returns the name of the space.
+func (s *Space) Name() string {
+	return s.doc.Name
+}
+
+// String implements fmt.Stringer.
+func (s *Space) String() string {
+	return s.doc.Name
+}
+
+// Subnets returns the subnets in the space, ordered by CIDR.
+func (s *Space) Subnets() ([]*Subnet, error) {
+	subnets, closer := s.st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	err := subnets.Find(bson.D{{"spacename", s.doc.Name}}).Sort("cidr").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets of space %q", s)
+	}
+	result := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		result[i] = &Subnet{s.st, doc}
+	}
+	return result, nil
+}
+
+// AddSpace creates and returns a new space holding the subnets with the
+// given CIDRs, which must already exist and not be in another space.
+func (st *State) AddSpace(name string, subnets []string) (space *Space, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add space %q", name)
+
+	if !network.IsValidSpaceName(name) {
+		return nil, errors.NotValidf("space name %q", name)
+	}
+	ops := []txn.Op{{
+		C:      spacesC,
+		Id:     st.docID(name),
+		Assert: txn.DocMissing,
+		Insert: &spaceDoc{
+			EnvUUID: st.EnvironUUID(),
+			Name:    name,
+		},
+	}}
+	for _, cidr := range subnets {
+		subnet, err := st.Subnet(cidr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if subnet.doc.SpaceName != "" {
+			return nil, errors.Errorf("subnet %q already in space %q", cidr, subnet.doc.SpaceName)
+		}
+		ops = append(ops, txn.Op{
+			C:  subnetsC,
+			Id: subnet.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"spacename", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", bson.D{{"spacename", name}}}},
+		})
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.Space(name); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", name)
+		}
+		return nil, errors.New("subnets changed while adding the space")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.Space(name)
+}
+
+// Space returns the space with the given name.
+func (st *State) Space(name string) (*Space, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var doc spaceDoc
+	err := spaces.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("space %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get space %q", name)
+	}
+	return &Space{st, doc}, nil
+}
+
+// AllSpaces returns all the spaces of the environment, ordered by name.
+func (st *State) AllSpaces() ([]*Space, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var docs []spaceDoc
+	if err := spaces.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all spaces")
+	}
+	result := make([]*Space, len(docs))
+	for i, doc := range docs {
+		result[i] = &Space{st, doc}
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SpacesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SpacesSuite{})
+
+func (s *SpacesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	for _, cidr := range []string{"10.0.1.0/24", "10.0.0.0/24", "10.0.2.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *SpacesSuite) assertSubnets(c *gc.C, space *state.Space, expected ...string) {
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	var cidrs []string
+	for _, subnet := range subnets {
+		c.Check(subnet.SpaceName(), gc.Equals, space.Name())
+		cidrs = append(cidrs, subnet.CIDR())
+	}
+	c.Assert(cidrs, jc.DeepEquals, expected)
+}
+
+func (s *SpacesSuite) TestAddSpace(c *gc.C) {
+	space, err := s.State.AddSpace("dmz", []string{"10.0.1.0/24", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "dmz")
+	s.assertSubnets(c, space, "10.0.0.0/24", "10.0.1.0/24")
+
+	space, err = s.State.Space("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubnets(c, space, "10.0.0.0/24", "10.0.1.0/24")
+
+	subnet, err := s.State.Subnet("10.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SpacesSuite) TestAddSpaceWithoutSubnets(c *gc.C) {
+	space, err := s.State.AddSpace("empty", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubnets(c, space)
+}
+
+func (s *SpacesSuite) TestAddSpaceInvalidName(c *gc.C) {
+	_, err := s.State.AddSpace("Not Valid", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "Not Valid": space name "Not Valid" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *SpacesSuite) TestAddSpaceAlreadyExists(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"10.0.2.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "dmz": space "dmz" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// The subnet was not moved.
+	subnet, err := s.State.Subnet("10.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SpacesSuite) TestAddSpaceUnknownSubnet(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", []string{"10.0.9.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "dmz": subnet "10.0.9.0/24" not found`)
+	_, err = s.State.Space("dmz")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpacesSuite) TestAddSpaceSubnetInOtherSpace(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("db", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "db": subnet "10.0.0.0/24" already in space "dmz"`)
+}
+
+func (s *SpacesSuite) TestAllSpaces(c *gc.C) {
+	spaces, err := s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+
+	for _, name := range []string{"public", "dmz", "db"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	spaces, err = s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, space := range spaces {
+		names = append(names, space.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"db", "dmz", "public"})
+}
+
+func (s *SpacesSuite) TestServiceSpaceBinding(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	c.Assert(service.SpaceBinding(), gc.Equals, "")
+
+	err = service.SetSpaceBinding("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.SpaceBinding(), gc.Equals, "dmz")
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.SpaceBinding(), gc.Equals, "dmz")
+
+	err = service.SetSpaceBinding("public")
+	c.Assert(err, gc.ErrorMatches, `cannot bind service "wordpress" to space "public": space "public" not found`)
+	c.Assert(service.SpaceBinding(), gc.Equals, "dmz")
+}
+
+func (s *SpacesSuite) TestServiceEndpointBindings(c *gc.C) {
+	for _, name := range []string{"dmz", "internal"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := service.SetSpaceBinding("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), gc.HasLen, 0)
+
+	err = service.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"db": "internal"})
+	c.Assert(service.EndpointSpace("db"), gc.Equals, "internal")
+	c.Assert(service.EndpointSpace("url"), gc.Equals, "dmz")
+}
+
+func (s *SpacesSuite) TestServiceEndpointBindingsErrors(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	err = service.SetEndpointBindings(map[string]string{"missing": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot bind endpoints of service "wordpress": endpoint "missing" not found`)
+	err = service.SetEndpointBindings(map[string]string{"db": "public"})
+	c.Assert(err, gc.ErrorMatches, `cannot bind endpoints of service "wordpress": space "public" not found`)
+	c.Assert(service.EndpointBindings(), gc.HasLen, 0)
+}
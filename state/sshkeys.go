@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// userSSHKeysDoc records the authorised ssh keys that a user has added
+// to the environment. The keys distributed to machines remain those
+// in the authorized-keys environment setting; these documents record
+// which of them belong to which user.
+type userSSHKeysDoc struct {
+	DocID   string   `bson:"_id"`
+	EnvUUID string   `bson:"env-uuid"`
+	User    string   `bson:"user"`
+	Keys    []string `bson:"keys"`
+}
+
+// UserSSHKeys returns the authorised ssh keys recorded for the user
+// with the given name.
+func (st *State) UserSSHKeys(user string) ([]string, error) {
+	sshKeys, closer := st.getCollection(userSSHKeysC)
+	defer closer()
+
+	var doc userSSHKeysDoc
+	err := sshKeys.FindId(user).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get ssh keys of user %q", user)
+	}
+	return doc.Keys, nil
+}
+
+// AllUserSSHKeys returns the authorised ssh keys recorded for all the
+// users of the environment, keyed by user name.
+func (st *State) AllUserSSHKeys() (map[string][]string, error) {
+	sshKeys, closer := st.getCollection(userSSHKeysC)
+	defer closer()
+
+	var docs []userSSHKeysDoc
+	if err := sshKeys.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get ssh keys of users")
+	}
+	result := make(map[string][]string)
+	for _, doc := range docs {
+		result[doc.User] = doc.Keys
+	}
+	return result, nil
+}
+
+// SetUserSSHKeys records the given authorised ssh keys as those of the
+// user with the given name, replacing any recorded before. Recording no
+// keys removes the user's record.
+func (st *State) SetUserSSHKeys(user string, keys []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set ssh keys of user %q", user)
+	sshKeys, closer := st.getCollection(userSSHKeysC)
+	defer closer()
+
+	docID := st.docID(user)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		count, err := sshKeys.FindId(user).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		exists := count > 0
+		switch {
+		case len(keys) == 0 && !exists:
+			return nil, jujutxn.ErrNoOperations
+		case len(keys) == 0:
+			return []txn.Op{{
+				C:      userSSHKeysC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Remove: true,
+			}}, nil
+		case exists:
+			return []txn.Op{{
+				C:      userSSHKeysC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"keys", keys}}}},
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      userSSHKeysC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &userSSHKeysDoc{
+				DocID:   docID,
+				EnvUUID: st.EnvironUUID(),
+				User:    user,
+				Keys:    keys,
+			},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type SSHKeysSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SSHKeysSuite{})
+
+func (s *SSHKeysSuite) TestUserSSHKeysNone(c *gc.C) {
+	keys, err := s.State.UserSSHKeys("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+	all, err := s.State.AllUserSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *SSHKeysSuite) TestSetUserSSHKeys(c *gc.C) {
+	err := s.State.SetUserSSHKeys("bob", []string{"key-1", "key-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserSSHKeys("mary", []string{"key-3"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	keys, err := s.State.UserSSHKeys("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"key-1", "key-2"})
+
+	err = s.State.SetUserSSHKeys("bob", []string{"key-2"})
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllUserSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string][]string{
+		"bob":  {"key-2"},
+		"mary": {"key-3"},
+	})
+}
+
+func (s *SSHKeysSuite) TestSetUserSSHKeysEmptyRemoves(c *gc.C) {
+	err := s.State.SetUserSSHKeys("bob", []string{"key-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserSSHKeys("bob", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllUserSSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+
+	// Removing the keys of a user without any is a no-op.
+	err = s.State.SetUserSSHKeys("bob", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
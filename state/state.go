@@ -57,6 +57,12 @@ const (
 	subnetsC           = "subnets"
 	ipaddressesC       = "ipaddresses"
 
+	// serviceOffersC holds the service endpoints offered for use by
+	// other environments, and remoteServicesC the offers consumed
+	// from other environments.
+	serviceOffersC  = "serviceoffers"
+	remoteServicesC = "remoteservices"
+
 	// actionsC and related collections store state of Actions that
 	// have been enqueued.
 	actionsC = "actions"
@@ -66,6 +72,15 @@ const (
 	// actionResultsC is deprecated and will soon be folded into
 	// actionsC.
 	actionresultsC = "actionresults"
+	// actionOutputC holds the output of Actions, streamed while
+	// they run.
+	actionOutputC = "actionoutput"
+	// actionSchedulesC holds the Actions that are to be enqueued at
+	// a later time, once or repeatedly.
+	actionSchedulesC = "actionschedules"
+	// actionQueuesC holds the units of services on which Actions are
+	// waiting to be enqueued.
+	actionQueuesC = "actionqueues"
 
 	usersC                 = "users"
 	envUsersC              = "envusers"
@@ -73,6 +88,7 @@ const (
 	cleanupsC              = "cleanups"
 	annotationsC           = "annotations"
 	statusesC              = "statuses"
+	statusesHistoryC       = "statuseshistory"
 	stateServersC          = "stateServers"
 	openedPortsC           = "openedPorts"
 	metricsC               = "metrics"
@@ -87,6 +103,7 @@ const (
 	volumeAttachmentsC     = "volumeattachments"
 	filesystemsC           = "filesystems"
 	filesystemAttachmentsC = "filesystemAttachments"
+	agentHealthC           = "agenthealth"
 
 	// leaseC is used to store lease tokens
 	leaseC = "lease"
@@ -100,6 +117,40 @@ const (
 	// toolsmetadataC is the collection used to store tools metadata.
 	toolsmetadataC = "toolsmetadata"
 
+	// resourcesC is the collection used to store charm resource metadata.
+	resourcesC = "resources"
+
+	// availabilityZonesC is the collection used to record the
+	// availability zones discovered from the provider.
+	availabilityZonesC = "availabilityzones"
+
+	// spacesC is the collection used to store network spaces.
+	spacesC = "spaces"
+
+	// cloudimagemetadataC is the collection used to store custom
+	// cloud image metadata uploaded to the controller.
+	cloudimagemetadataC = "cloudimagemetadata"
+
+	// upgradePlansC is the collection used to record the plan of an
+	// upgrade that proceeds in waves.
+	upgradePlansC = "upgradeplans"
+
+	// upgradeSeriesLocksC is the collection used to record the
+	// progress of series upgrades of machines.
+	upgradeSeriesLocksC = "upgradeserieslocks"
+
+	// userSSHKeysC is the collection used to record which authorised
+	// ssh keys belong to which users.
+	userSSHKeysC = "usersshkeys"
+
+	// scopedCredentialsC is the collection used to record the
+	// time-limited credentials that give access to part of the API.
+	scopedCredentialsC = "scopedcredentials"
+
+	// macaroonsC is the collection used to record the key pair and
+	// the root keys of the macaroons minted by the API servers.
+	macaroonsC = "macaroons"
+
 	// These collections are used by the mgo transaction runner.
 	txnLogC = "txns.log"
 	txnsC   = "txns"
@@ -134,11 +185,15 @@ type State struct {
 	db                *mgo.Database
 	watcher           *watcher.Watcher
 	pwatcher          *presence.Watcher
-	// mu guards allManager.
+	// mu guards allManager and coalescing.
 	mu         sync.Mutex
 	allManager *storeManager
+	coalescing WatcherCoalescing
 	environTag names.EnvironTag
 	serverTag  names.EnvironTag
+	// borrowed is true if the state shares its watchers with
+	// another, and so must not stop them when closed.
+	borrowed bool
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -213,6 +268,7 @@ func (st *State) ForEnviron(env names.EnvironTag) (*State, error) {
 	}
 	newState.environTag = env
 	newState.serverTag = st.serverTag
+	newState.coalescing = st.watcherCoalescing()
 	newState.startPresenceWatcher()
 	return newState, nil
 }
@@ -279,6 +335,43 @@ func (st *State) startPresenceWatcher() {
 	st.pwatcher = presence.NewWatcher(pdb.C(presenceC), st.environTag)
 }
 
+// agentPresenceKey returns the presence key of the agent of the machine
+// or unit with the given tag.
+func agentPresenceKey(tag names.Tag) (string, error) {
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		return machineGlobalKey(tag.Id()), nil
+	case names.UnitTag:
+		return unitAgentGlobalKey(tag.Id()), nil
+	}
+	return "", errors.NotValidf("agent tag %q", tag)
+}
+
+// WatchAgentPresence starts watching the presence of the agent of the
+// machine or unit with the given tag. As with presence.Watcher.Watch,
+// the agent's current presence is sent on ch, followed by every change
+// to it, and the changes must be consumed or the presence of every
+// agent stops being watched.
+func (st *State) WatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error {
+	key, err := agentPresenceKey(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st.pwatcher.Watch(key, ch)
+	return nil
+}
+
+// UnwatchAgentPresence stops watching the presence of the agent of the
+// machine or unit with the given tag via ch.
+func (st *State) UnwatchAgentPresence(tag names.Tag, ch chan<- presence.Change) error {
+	key, err := agentPresenceKey(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st.pwatcher.Unwatch(key, ch)
+	return nil
+}
+
 // newDB returns a database connection using a new session, along with
 // a closer function for the session. This is useful where you need to work
 // with various collections in a single session, so don't want to call
@@ -302,6 +395,29 @@ func (st *State) MongoSession() *mgo.Session {
 	return st.db.Session
 }
 
+// WithSession returns a State for the same environment as st, sharing
+// its watchers and policy, whose database operations use the given
+// session. This allows reads to be made with a session whose read
+// preference differs from st's.
+//
+// The session remains owned by the caller, who must not close it while
+// the returned State is in use. Closing the returned State does nothing.
+func (st *State) WithSession(session *mgo.Session) *State {
+	return &State{
+		LeasePersistor:    st.LeasePersistor,
+		transactionRunner: st.transactionRunner,
+		mongoInfo:         st.mongoInfo,
+		policy:            st.policy,
+		db:                st.db.With(session),
+		watcher:           st.watcher,
+		pwatcher:          st.pwatcher,
+		environTag:        st.environTag,
+		serverTag:         st.serverTag,
+		coalescing:        st.watcherCoalescing(),
+		borrowed:          true,
+	}
+}
+
 type closeFunc func()
 
 func (st *State) Watch() *Multiwatcher {
@@ -418,8 +534,16 @@ func IsUpgradeInProgressError(err error) bool {
 
 // SetEnvironAgentVersion changes the agent version for the
 // environment to the given version, only if the environment is in a
-// stable state (all agents are running the current version).
+// stable state (all agents are running the current version). Any plan
+// of a previous upgrade with canaries is discarded, so all agents
+// upgrade.
 func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
+	return st.setEnvironAgentVersion(newVersion, nil)
+}
+
+// setEnvironAgentVersion changes the agent version for the environment
+// and records the plan for upgrading the given canary machines first.
+func (st *State) setEnvironAgentVersion(newVersion version.Number, canaries []string) (err error) {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		settings, err := readSettings(st, environGlobalKey)
 		if err != nil {
@@ -455,7 +579,15 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 				Update: bson.D{{"$set", bson.D{{"agent-version", newVersion.String()}}}},
 			},
 		}
-		return ops, nil
+		previousVersion, err := version.Parse(currentVersion)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		planOps, err := st.upgradePlanOps(previousVersion, newVersion, canaries)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, planOps...), nil
 	}
 	if err = st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		// Although there is a small chance of a race here, try to
@@ -503,25 +635,81 @@ func (st *State) UpdateEnvironConfig(updateAttrs map[string]interface{}, removeA
 	// applied as a delta to what's on disk; if there has
 	// been a concurrent update, the change may not be what
 	// the user asked for.
-	settings, err := readSettings(st, environGlobalKey)
+	settings, err := st.updatedEnvironSettings(updateAttrs, removeAttrs, additionalValidation)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = settings.Write()
+	return errors.Trace(err)
+}
+
+// EnvironConfigUpdate describes the changes that an update of the
+// environment config would make.
+type EnvironConfigUpdate struct {
+	// Revision holds the revision of the environment settings that
+	// the update was validated against.
+	Revision int64
+
+	// Changes holds the changes to the environment settings, sorted
+	// by key.
+	Changes []ItemChange
+}
+
+// ValidateEnvironConfigUpdate validates the given changes to the
+// environment config as UpdateEnvironConfig does, and returns the
+// changes they would make without applying them. The changes may then
+// be applied with UpdateEnvironConfigAtRevision.
+func (st *State) ValidateEnvironConfigUpdate(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc) (EnvironConfigUpdate, error) {
+	settings, err := st.updatedEnvironSettings(updateAttrs, removeAttrs, additionalValidation)
+	if err != nil {
+		return EnvironConfigUpdate{}, errors.Trace(err)
+	}
+	changes, _, _ := settings.delta()
+	return EnvironConfigUpdate{
+		Revision: settings.Revision(),
+		Changes:  changes,
+	}, nil
+}
+
+// UpdateEnvironConfigAtRevision updates the environment config as
+// UpdateEnvironConfig does, but only if the environment settings are
+// still at the given revision, as returned by ValidateEnvironConfigUpdate.
+// If they are not, ErrSettingsChanged is returned and nothing is changed.
+func (st *State) UpdateEnvironConfigAtRevision(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc, revision int64) error {
+	settings, err := st.updatedEnvironSettings(updateAttrs, removeAttrs, additionalValidation)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if settings.Revision() != revision {
+		return ErrSettingsChanged
+	}
+	_, err = settings.WriteIfUnchanged()
+	return err
+}
+
+// updatedEnvironSettings reads the environment settings and applies the
+// given changes to them, after validating the resulting config. The
+// settings are not written.
+func (st *State) updatedEnvironSettings(updateAttrs map[string]interface{}, removeAttrs []string, additionalValidation ValidateConfigFunc) (*Settings, error) {
+	settings, err := readSettings(st, environGlobalKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Get the existing environment config from state.
 	oldConfig, err := config.New(config.NoDefaults, settings.Map())
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if additionalValidation != nil {
 		err = additionalValidation(updateAttrs, removeAttrs, oldConfig)
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 	}
 	validCfg, err := st.buildAndValidateEnvironConfig(updateAttrs, removeAttrs, oldConfig)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	validAttrs := validCfg.AllAttrs()
@@ -531,8 +719,7 @@ func (st *State) UpdateEnvironConfig(updateAttrs map[string]interface{}, removeA
 		}
 	}
 	settings.Update(validAttrs)
-	_, err = settings.Write()
-	return errors.Trace(err)
+	return settings, nil
 }
 
 // EnvironConstraints returns the current environment constraints.
@@ -1243,6 +1430,11 @@ func (st *State) AddService(
 	} else if exists {
 		return nil, errors.Errorf("service already exists")
 	}
+	if _, err := st.RemoteService(name); err == nil {
+		return nil, errors.Errorf("service already exists")
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
 	env, err := st.Environment()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1292,6 +1484,12 @@ func (st *State) AddService(
 				RefCount: 1,
 				EnvUUID:  st.EnvironUUID()},
 		},
+		{
+			// Remote and local services share a namespace.
+			C:      remoteServicesC,
+			Id:     serviceID,
+			Assert: txn.DocMissing,
+		},
 		{
 			C:      servicesC,
 			Id:     serviceID,
@@ -1433,6 +1631,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		AllocatableIPHigh: args.AllocatableIPHigh,
 		AllocatableIPLow:  args.AllocatableIPLow,
 		AvailabilityZone:  args.AvailabilityZone,
+		SpaceName:         args.SpaceName,
 	}
 	subnet = &Subnet{doc: subDoc, st: st}
 	err = subnet.Validate()
@@ -1735,7 +1934,16 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 		return nil, errors.Errorf("invalid endpoint %q", name)
 	}
 	svc, err := st.Service(svcName)
-	if err != nil {
+	if errors.IsNotFound(err) {
+		// The service may be one of another environment.
+		remote, remoteErr := st.RemoteService(svcName)
+		if errors.IsNotFound(remoteErr) {
+			return nil, errors.Trace(err)
+		} else if remoteErr != nil {
+			return nil, errors.Trace(remoteErr)
+		}
+		return remoteEndpoints(remote, relName, filter)
+	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
 	eps := []Endpoint{}
@@ -1760,8 +1968,36 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 	return final, nil
 }
 
+// remoteEndpoints is the equivalent of endpoints for a remote service.
+func remoteEndpoints(svc *RemoteService, relName string, filter func(ep Endpoint) bool) ([]Endpoint, error) {
+	eps := svc.Endpoints()
+	if relName != "" {
+		ep, err := svc.Endpoint(relName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		eps = []Endpoint{ep}
+	}
+	final := []Endpoint{}
+	for _, ep := range eps {
+		if filter(ep) {
+			final = append(final, ep)
+		}
+	}
+	return final, nil
+}
+
 // AddRelation creates a new relation with the given endpoints.
 func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
+	return st.AddRelationWithSelector(nil, eps...)
+}
+
+// AddRelationWithSelector creates a new relation with the given
+// endpoints. If selector is not empty, the relation must be container
+// scoped, and subordinates are only attached to the principal units
+// that carry all the annotations in selector, either themselves or on
+// their machines.
+func (st *State) AddRelationWithSelector(selector map[string]string, eps ...Endpoint) (r *Relation, err error) {
 	key := relationKey(eps)
 	defer errors.DeferredAnnotatef(&err, "cannot add relation %q", key)
 	// Enforce basic endpoint sanity. The epCount restrictions may be relaxed
@@ -1772,6 +2008,14 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 	if !eps[0].CanRelateTo(eps[1]) {
 		return nil, errors.Errorf("endpoints do not relate")
 	}
+	for label, value := range selector {
+		if label == "" || strings.ContainsAny(label, ".=") {
+			return nil, errors.NotValidf("subordinate selector key %q", label)
+		}
+		if value == "" {
+			return nil, errors.NotValidf("empty value for subordinate selector key %q", label)
+		}
+	}
 	// If either endpoint has container scope, so must the other; and the
 	// services's series must also match, because they'll be deployed to
 	// the same machines.
@@ -1799,12 +2043,18 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		}
 		// Collect per-service operations, checking sanity as we go.
 		var ops []txn.Op
-		var subordinateCount int
+		var subordinateCount, remoteCount int
 		series := map[string]bool{}
 		for _, ep := range eps {
 			svc, err := st.Service(ep.ServiceName)
 			if errors.IsNotFound(err) {
-				return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				remoteOps, err := st.addRemoteRelationOps(ep)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				remoteCount++
+				ops = append(ops, remoteOps...)
+				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
 			} else if svc.doc.Life != Alive {
@@ -1828,12 +2078,21 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 				Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
 			})
 		}
+		if remoteCount == len(eps) {
+			return nil, errors.Errorf("cannot relate remote services to each other")
+		}
+		if remoteCount > 0 && eps[0].Scope == charm.ScopeContainer {
+			return nil, errors.Errorf("remote services cannot take part in container scoped relations")
+		}
 		if matchSeries && len(series) != 1 {
 			return nil, errors.Errorf("principal and subordinate services' series must match")
 		}
 		if eps[0].Scope == charm.ScopeContainer && subordinateCount < 1 {
 			return nil, errors.Errorf("container scoped relation requires at least one subordinate service")
 		}
+		if len(selector) > 0 && eps[0].Scope != charm.ScopeContainer {
+			return nil, errors.Errorf("only container scoped relations can select principal units")
+		}
 
 		// Create a new unique id if that has not already been done, and add
 		// an operation to create the relation document.
@@ -1852,6 +2111,9 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 			Endpoints: eps,
 			Life:      Alive,
 		}
+		if len(selector) > 0 {
+			doc.SubordinateSelector = selector
+		}
 		ops = append(ops, txn.Op{
 			C:      relationsC,
 			Id:     docID,
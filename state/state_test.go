@@ -152,6 +152,29 @@ func (s *StateSuite) TestMongoSession(c *gc.C) {
 	c.Assert(session.Ping(), gc.IsNil)
 }
 
+func (s *StateSuite) TestWithSession(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	session := s.State.MongoSession().Copy()
+	defer session.Close()
+	session.SetMode(mgo.Eventual, true)
+	st := s.State.WithSession(session)
+	c.Assert(st.MongoSession(), gc.Equals, session)
+	c.Assert(st.EnvironUUID(), gc.Equals, s.State.EnvironUUID())
+
+	m, err := st.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, machine.Id())
+
+	// Closing the borrowing state leaves the original intact.
+	err = st.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.Ping(), jc.ErrorIsNil)
+	_, err = s.State.Machine(machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type MultiEnvStateSuite struct {
 	ConnSuite
 	OtherState *state.State
@@ -2088,6 +2111,59 @@ func (s *StateSuite) TestEnvironConfig(c *gc.C) {
 	c.Assert(oldCfg, gc.DeepEquals, cfg)
 }
 
+func (s *StateSuite) TestValidateEnvironConfigUpdate(c *gc.C) {
+	attrs := map[string]interface{}{
+		"authorized-keys": "different-keys",
+		"arbitrary-key":   "shazam!",
+	}
+	oldCfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	update, err := s.State.ValidateEnvironConfigUpdate(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	changes := make(map[string]state.ItemChange)
+	for _, change := range update.Changes {
+		changes[change.Key] = change
+	}
+	c.Assert(changes["arbitrary-key"], gc.DeepEquals, state.ItemChange{
+		Type:     state.ItemAdded,
+		Key:      "arbitrary-key",
+		NewValue: "shazam!",
+	})
+	c.Assert(changes["authorized-keys"], gc.DeepEquals, state.ItemChange{
+		Type:     state.ItemModified,
+		Key:      "authorized-keys",
+		OldValue: oldCfg.AuthorizedKeys(),
+		NewValue: "different-keys",
+	})
+
+	// The changes are not applied until asked to.
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs(), jc.DeepEquals, oldCfg.AllAttrs())
+	err = s.State.UpdateEnvironConfigAtRevision(attrs, nil, nil, update.Revision)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuthorizedKeys(), gc.Equals, "different-keys")
+	c.Assert(cfg.AllAttrs()["arbitrary-key"], gc.Equals, "shazam!")
+
+	// The config is no longer at the validated revision.
+	err = s.State.UpdateEnvironConfigAtRevision(
+		map[string]interface{}{"arbitrary-key": "kazam!"}, nil, nil, update.Revision,
+	)
+	c.Assert(err, gc.Equals, state.ErrSettingsChanged)
+	cfg, err = s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["arbitrary-key"], gc.Equals, "shazam!")
+}
+
+func (s *StateSuite) TestValidateEnvironConfigUpdateInvalid(c *gc.C) {
+	_, err := s.State.ValidateEnvironConfigUpdate(
+		map[string]interface{}{"firewall-mode": "wrong"}, nil, nil,
+	)
+	c.Assert(err, gc.ErrorMatches, `invalid firewall mode in environment configuration: "wrong"`)
+}
+
 func (s *StateSuite) TestEnvironConstraints(c *gc.C) {
 	// Environ constraints start out empty (for now).
 	cons, err := s.State.EnvironConstraints()
@@ -2136,6 +2212,25 @@ func (s *StateSuite) TestSetUnsupportedConstraintsWarning(c *gc.C) {
 	c.Assert(econs, gc.DeepEquals, cons)
 }
 
+func (s *StateSuite) TestSetWatcherCoalescingValidates(c *gc.C) {
+	err := s.State.SetWatcherCoalescing(state.WatcherCoalescing{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, "coalescing window 0s? not valid")
+
+	err = s.State.SetWatcherCoalescing(state.WatcherCoalescing{
+		Window:   time.Second,
+		MaxDelay: time.Millisecond,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, "coalescing max delay 1ms shorter than window 1s not valid")
+
+	err = s.State.SetWatcherCoalescing(state.WatcherCoalescing{
+		Window:   time.Second,
+		MaxDelay: time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *StateSuite) TestWatchIPAddresses(c *gc.C) {
 	w := s.State.WatchIPAddresses()
 	defer statetesting.AssertStop(c, w)
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+// MigrateStateServer starts replacing the state server running on the
+// machine with the given id by a new machine, started according to the
+// given constraints, series and placement directive; if series is empty,
+// that of the existing machine is used. The existing machine is demoted
+// in the same transaction, so the peergrouper worker moves its vote to
+// the new machine once the new machine's mongo has caught up with the
+// replica set. The existing machine can then be removed with
+// RetireStateServer. The id of the new machine is returned. If the
+// machine is already being retired, an error satisfying
+// errors.IsAlreadyExists is returned.
+func (st *State) MigrateStateServer(machineId string, cons constraints.Value, series, placement string) (string, error) {
+	var newMachineId string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		currentInfo, err := st.StateServerInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m, err := st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.IsManager() {
+			return nil, errors.Errorf("machine %s is not a state server", machineId)
+		}
+		if m.Life() != Alive {
+			return nil, errors.Errorf("machine %s is not alive", machineId)
+		}
+		if !m.WantsVote() {
+			return nil, errors.NewAlreadyExists(nil, fmt.Sprintf("machine %s is already being retired", machineId))
+		}
+		if series == "" {
+			series = m.Series()
+		}
+		template := MachineTemplate{
+			Series: series,
+			Jobs: []MachineJob{
+				JobHostUnits,
+				JobManageEnviron,
+			},
+			Constraints: cons,
+			Placement:   placement,
+		}
+		mdoc, ops, err := st.addMachineOps(template)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ssOps, err := st.maintainStateServersOps([]*machineDoc{mdoc}, currentInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, ssOps...)
+		ops = append(ops, demoteStateServerOps(m)...)
+		newMachineId = mdoc.Id
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return "", errors.Annotatef(err, "cannot migrate state server on machine %s", machineId)
+	}
+	return newMachineId, nil
+}
+
+// RetireStateServer removes the state server job from the machine with
+// the given id, which must have been demoted by MigrateStateServer, and
+// then destroys the machine. It returns false, without changing
+// anything, if the machine still holds its vote in the replica set;
+// the caller should try again later.
+func (st *State) RetireStateServer(machineId string) (bool, error) {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		m, err := st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.IsManager() {
+			if attempt > 0 {
+				// Retired by a concurrent call.
+				return nil, jujutxn.ErrNoOperations
+			}
+			return nil, errors.Errorf("machine %s is not a state server", machineId)
+		}
+		if m.WantsVote() {
+			return nil, errors.Errorf("machine %s is not being retired", machineId)
+		}
+		if m.HasVote() {
+			return nil, errStateServerStillVoting
+		}
+		return removeStateServerOps(m), nil
+	}
+	err := st.run(buildTxn)
+	if err == errStateServerStillVoting {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot retire state server on machine %s", machineId)
+	}
+	m, err := st.Machine(machineId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if err := m.Destroy(); err != nil {
+		return false, errors.Annotatef(err, "cannot destroy machine %s", machineId)
+	}
+	return true, nil
+}
+
+var errStateServerStillVoting = errors.New("state server still has a vote")
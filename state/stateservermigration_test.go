@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type StateServerMigrationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&StateServerMigrationSuite{})
+
+func (s *StateServerMigrationSuite) addStateServer(c *gc.C) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *StateServerMigrationSuite) assertStateServerInfo(c *gc.C, machineIds, votingMachineIds []string) {
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.MachineIds, jc.SameContents, machineIds)
+	c.Assert(info.VotingMachineIds, jc.SameContents, votingMachineIds)
+}
+
+func (s *StateServerMigrationSuite) TestMigrateStateServer(c *gc.C) {
+	m0 := s.addStateServer(c)
+	cons := constraints.MustParse("mem=8G")
+
+	newId, err := s.State.MigrateStateServer(m0.Id(), cons, "", "zone=a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newId, gc.Equals, "1")
+
+	m1, err := s.State.Machine(newId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.Series(), gc.Equals, "quantal")
+	c.Assert(m1.Jobs(), gc.DeepEquals, []state.MachineJob{
+		state.JobHostUnits,
+		state.JobManageEnviron,
+	})
+	c.Assert(m1.Placement(), gc.Equals, "zone=a")
+	c.Assert(m1.WantsVote(), jc.IsTrue)
+	gotCons, err := m1.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotCons, gc.DeepEquals, cons)
+
+	err = m0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.IsManager(), jc.IsTrue)
+	c.Assert(m0.WantsVote(), jc.IsFalse)
+	s.assertStateServerInfo(c, []string{"0", "1"}, []string{"1"})
+}
+
+func (s *StateServerMigrationSuite) TestMigrateStateServerSeries(c *gc.C) {
+	m0 := s.addStateServer(c)
+	newId, err := s.State.MigrateStateServer(m0.Id(), constraints.Value{}, "trusty", "")
+	c.Assert(err, jc.ErrorIsNil)
+	m1, err := s.State.Machine(newId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.Series(), gc.Equals, "trusty")
+}
+
+func (s *StateServerMigrationSuite) TestMigrateStateServerNotStateServer(c *gc.C) {
+	s.addStateServer(c)
+	m1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateStateServer(m1.Id(), constraints.Value{}, "", "")
+	c.Assert(err, gc.ErrorMatches, "cannot migrate state server on machine 1: machine 1 is not a state server")
+}
+
+func (s *StateServerMigrationSuite) TestMigrateStateServerAlreadyRetiring(c *gc.C) {
+	m0 := s.addStateServer(c)
+	_, err := s.State.MigrateStateServer(m0.Id(), constraints.Value{}, "", "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateStateServer(m0.Id(), constraints.Value{}, "", "")
+	c.Assert(err, gc.ErrorMatches, "cannot migrate state server on machine 0: machine 0 is already being retired")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	s.assertStateServerInfo(c, []string{"0", "1"}, []string{"1"})
+}
+
+func (s *StateServerMigrationSuite) TestRetireStateServerStillVoting(c *gc.C) {
+	m0 := s.addStateServer(c)
+	err := m0.SetHasVote(true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MigrateStateServer(m0.Id(), constraints.Value{}, "", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	retired, err := s.State.RetireStateServer(m0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retired, jc.IsFalse)
+	err = m0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.IsManager(), jc.IsTrue)
+	c.Assert(m0.Life(), gc.Equals, state.Alive)
+	s.assertStateServerInfo(c, []string{"0", "1"}, []string{"1"})
+}
+
+func (s *StateServerMigrationSuite) TestRetireStateServer(c *gc.C) {
+	m0 := s.addStateServer(c)
+	_, err := s.State.MigrateStateServer(m0.Id(), constraints.Value{}, "", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	retired, err := s.State.RetireStateServer(m0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retired, jc.IsTrue)
+	err = m0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m0.IsManager(), jc.IsFalse)
+	c.Assert(m0.Life(), gc.Equals, state.Dying)
+	s.assertStateServerInfo(c, []string{"1"}, []string{"1"})
+}
+
+func (s *StateServerMigrationSuite) TestRetireStateServerNotRetiring(c *gc.C) {
+	m0 := s.addStateServer(c)
+	_, err := s.State.RetireStateServer(m0.Id())
+	c.Assert(err, gc.ErrorMatches, "cannot retire state server on machine 0: machine 0 is not being retired")
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// StatusHistoryKind identifies which of an entity's statuses a status
+// history entry records.
+type StatusHistoryKind string
+
+const (
+	// StatusHistoryMachine records the status of a machine.
+	StatusHistoryMachine StatusHistoryKind = "machine"
+
+	// StatusHistoryAgent records the status of a unit agent.
+	StatusHistoryAgent StatusHistoryKind = "agent"
+
+	// StatusHistoryWorkload records the status of a unit's workload.
+	StatusHistoryWorkload StatusHistoryKind = "workload"
+)
+
+// StatusHistoryEntry records a status that was set on an entity.
+type StatusHistoryEntry struct {
+	Kind   StatusHistoryKind
+	Status Status
+	Info   string
+	Data   map[string]interface{}
+	Since  time.Time
+}
+
+// statusHistoryDoc represents a status that was set on an entity, as
+// stored in MongoDB. The history is written outside of transactions, as
+// it is only ever appended to and pruned.
+type statusHistoryDoc struct {
+	Id         bson.ObjectId          `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	GlobalKey  string                 `bson:"globalkey"`
+	Status     Status                 `bson:"status"`
+	StatusInfo string                 `bson:"statusinfo"`
+	StatusData map[string]interface{} `bson:"statusdata"`
+	Updated    time.Time              `bson:"updated"`
+}
+
+// recordStatusHistory adds the status just set on the entity with the
+// given global key to its history. The status has already been set, so
+// a failure to record it is logged rather than returned.
+func recordStatusHistory(st *State, globalKey string, doc statusDoc) {
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+	err := history.Insert(&statusHistoryDoc{
+		Id:         bson.NewObjectId(),
+		EnvUUID:    st.EnvironUUID(),
+		GlobalKey:  globalKey,
+		Status:     doc.Status,
+		StatusInfo: doc.StatusInfo,
+		StatusData: doc.StatusData,
+		Updated:    time.Now().UTC(),
+	})
+	if err != nil {
+		logger.Errorf("cannot record status history of %q: %v", globalKey, err)
+	}
+}
+
+// StatusHistory returns up to count of the most recent statuses set on
+// the machine or unit with the given tag, most recent first. The
+// history of a unit includes the statuses of both its agent and its
+// workload.
+func (st *State) StatusHistory(tag names.Tag, count int) ([]StatusHistoryEntry, error) {
+	if count <= 0 {
+		return nil, errors.NotValidf("status history count %d", count)
+	}
+	kinds := make(map[string]StatusHistoryKind)
+	switch tag := tag.(type) {
+	case names.MachineTag:
+		kinds[machineGlobalKey(tag.Id())] = StatusHistoryMachine
+	case names.UnitTag:
+		kinds[unitAgentGlobalKey(tag.Id())] = StatusHistoryAgent
+		kinds[unitGlobalKey(tag.Id())] = StatusHistoryWorkload
+	default:
+		return nil, errors.NotSupportedf("status history for %s", names.ReadableString(tag))
+	}
+	keys := make([]string, 0, len(kinds))
+	for key := range kinds {
+		keys = append(keys, key)
+	}
+
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+	var docs []statusHistoryDoc
+	query := history.Find(bson.D{{"globalkey", bson.D{{"$in", keys}}}})
+	if err := query.Sort("-updated", "-_id").Limit(count).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get status history of %s", names.ReadableString(tag))
+	}
+	entries := make([]StatusHistoryEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = StatusHistoryEntry{
+			Kind:   kinds[doc.GlobalKey],
+			Status: doc.Status,
+			Info:   doc.StatusInfo,
+			Data:   doc.StatusData,
+			Since:  doc.Updated,
+		}
+	}
+	return entries, nil
+}
+
+// PruneStatusHistory removes the status history entries of all
+// environments that were recorded before minHistoryTime, and then all
+// but the most recent maxEntriesPerEntity entries of each entity.
+//
+// The history of removed entities is not removed with them, and so
+// is left to be pruned by age.
+func PruneStatusHistory(st *State, minHistoryTime time.Time, maxEntriesPerEntity int) error {
+	history, closer := st.getRawCollection(statusesHistoryC)
+	defer closer()
+
+	var envUUIDs []string
+	if err := history.Find(nil).Distinct("env-uuid", &envUUIDs); err != nil {
+		return errors.Annotate(err, "cannot get environments with status history")
+	}
+	removed := 0
+	for _, envUUID := range envUUIDs {
+		info, err := history.RemoveAll(bson.D{
+			{"env-uuid", envUUID},
+			{"updated", bson.D{{"$lt", minHistoryTime}}},
+		})
+		if err != nil {
+			return errors.Annotate(err, "cannot prune status history by time")
+		}
+		removed += info.Removed
+
+		var keys []string
+		err = history.Find(bson.D{{"env-uuid", envUUID}}).Distinct("globalkey", &keys)
+		if err != nil {
+			return errors.Annotate(err, "cannot get entities with status history")
+		}
+		for _, key := range keys {
+			n, err := pruneEntityStatusHistory(history, envUUID, key, maxEntriesPerEntity)
+			if err != nil {
+				return errors.Annotatef(err, "cannot prune status history of %q", key)
+			}
+			removed += n
+		}
+	}
+	if removed > 0 {
+		logger.Debugf("pruned %d status history entries", removed)
+	}
+	return nil
+}
+
+// pruneEntityStatusHistory removes all but the most recent maxEntries
+// of an entity's status history entries, returning how many were
+// removed.
+func pruneEntityStatusHistory(history *mgo.Collection, envUUID, globalKey string, maxEntries int) (int, error) {
+	sel := bson.D{{"env-uuid", envUUID}, {"globalkey", globalKey}}
+	var docs []struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	query := history.Find(sel).Sort("-updated", "-_id").Skip(maxEntries).Select(bson.D{{"_id", 1}})
+	if err := query.All(&docs); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	ids := make([]bson.ObjectId, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Id
+	}
+	info, err := history.RemoveAll(bson.D{{"_id", bson.D{{"$in", ids}}}})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return info.Removed, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type StatusHistorySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) addUnit(c *gc.C) *state.Unit {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *StatusHistorySuite) TestMachineStatusHistory(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(state.StatusError, "disk full", map[string]interface{}{"disk": "sda"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.StatusHistory(m.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Kind, gc.Equals, state.StatusHistoryMachine)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+	c.Assert(history[0].Info, gc.Equals, "disk full")
+	c.Assert(history[0].Data, jc.DeepEquals, map[string]interface{}{"disk": "sda"})
+	c.Assert(history[1].Status, gc.Equals, state.StatusStarted)
+	c.Assert(history[0].Since.Before(history[1].Since), jc.IsFalse)
+
+	history, err = s.State.StatusHistory(m.Tag(), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+}
+
+func (s *StatusHistorySuite) TestUnitStatusHistory(c *gc.C) {
+	unit := s.addUnit(c)
+	agent := unit.Agent().(*state.UnitAgent)
+	err := agent.SetStatus(state.StatusExecuting, "running install hook", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusMaintenance, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = agent.SetStatus(state.StatusError, "hook failed: install", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.StatusHistory(unit.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Check(history[0].Kind, gc.Equals, state.StatusHistoryAgent)
+	c.Check(history[0].Status, gc.Equals, state.StatusError)
+	c.Check(history[1].Kind, gc.Equals, state.StatusHistoryWorkload)
+	c.Check(history[1].Status, gc.Equals, state.StatusMaintenance)
+	c.Check(history[2].Kind, gc.Equals, state.StatusHistoryAgent)
+	c.Check(history[2].Status, gc.Equals, state.StatusExecuting)
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryInvalid(c *gc.C) {
+	unit := s.addUnit(c)
+	_, err := s.State.StatusHistory(unit.Tag(), 0)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	svc, err := unit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StatusHistory(svc.Tag(), 10)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByCount(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 5; i++ {
+		err = m.SetStatus(state.StatusStarted, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err = m.SetStatus(state.StatusError, "oops", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.PruneStatusHistory(s.State, time.Now().Add(-time.Hour), 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.StatusHistory(m.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+}
+
+func (s *StatusHistorySuite) TestPruneStatusHistoryByTime(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.PruneStatusHistory(s.State, time.Now().Add(time.Hour), 100)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.State.StatusHistory(m.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
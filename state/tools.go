@@ -4,10 +4,16 @@
 package state
 
 import (
+	"sort"
+	"time"
+
 	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/state/toolstorage"
+	"github.com/juju/juju/version"
 )
 
 var (
@@ -40,3 +46,83 @@ func (t *toolsStorageCloser) Close() error {
 	t.session.Close()
 	return nil
 }
+
+// PruneTools removes the tools added to the tools storage before
+// minToolsTime that are neither used by any agent nor of the agent
+// version of any environment, and returns the versions of the tools
+// removed. The agents and agent versions of all environments are
+// considered, as the tools catalogue is shared between them. If
+// dryRun is true, the versions are returned but nothing is removed.
+func PruneTools(st *State, minToolsTime time.Time, dryRun bool) ([]version.Binary, error) {
+	inUse, agentVersions, err := toolsInUse(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	storage, err := st.ToolsStorage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer storage.Close()
+	metadata, err := storage.MetadataAddedBefore(minToolsTime)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get tools metadata")
+	}
+	sort.Sort(toolsMetadataByVersion(metadata))
+
+	var removed []version.Binary
+	for _, m := range metadata {
+		if inUse.Contains(m.Version.String()) || agentVersions.Contains(m.Version.Number.String()) {
+			continue
+		}
+		if !dryRun {
+			err := storage.RemoveTools(m.Version)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return removed, errors.Annotatef(err, "cannot remove %v tools", m.Version)
+			}
+			logger.Infof("pruned unused %v tools", m.Version)
+		}
+		removed = append(removed, m.Version)
+	}
+	return removed, nil
+}
+
+// toolsInUse returns the versions of the tools used by the agents of
+// all environments, and the agent versions of all environments.
+func toolsInUse(st *State) (inUse, agentVersions set.Strings, err error) {
+	inUse = set.NewStrings()
+	for _, name := range []string{machinesC, unitsC} {
+		collection, closer := st.getRawCollection(name)
+		var versions []string
+		err := collection.Find(nil).Distinct("tools.version", &versions)
+		closer()
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "cannot get agent tools versions")
+		}
+		for _, v := range versions {
+			inUse.Add(v)
+		}
+	}
+	settings, closer := st.getRawCollection(settingsC)
+	defer closer()
+	var versions []string
+	if err := settings.Find(nil).Distinct("agent-version", &versions); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot get environment agent versions")
+	}
+	return inUse, set.NewStrings(versions...), nil
+}
+
+type toolsMetadataByVersion []toolstorage.Metadata
+
+func (m toolsMetadataByVersion) Len() int {
+	return len(m)
+}
+
+func (m toolsMetadataByVersion) Less(i, j int) bool {
+	return m[i].Version.String() < m[j].Version.String()
+}
+
+func (m toolsMetadataByVersion) Swap(i, j int) {
+	m[i], m[j] = m[j], m[i]
+}
@@ -6,6 +6,7 @@ package state_test
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/blobstore"
 	"github.com/juju/errors"
@@ -116,3 +117,60 @@ func (s *ToolsSuite) TestStorageParams(c *gc.C) {
 	storage.Close()
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *ToolsSuite) addTools(c *gc.C, storage toolstorage.Storage, vers string) {
+	err := storage.AddTools(strings.NewReader(vers), toolstorage.Metadata{
+		Version: version.MustParseBinary(vers),
+		Size:    int64(len(vers)),
+		SHA256:  "hash(" + vers + ")",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ToolsSuite) TestPruneTools(c *gc.C) {
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	current := version.Binary{Number: agentVersion, Series: "trusty", Arch: "armhf"}
+	s.addTools(c, storage, current.String())
+	s.addTools(c, storage, "1.2.3-trusty-amd64")
+	s.addTools(c, storage, "1.2.4-trusty-amd64")
+	s.addTools(c, storage, "1.2.5-trusty-amd64")
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.MustParseBinary("1.2.4-trusty-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Tools added after the retention window are kept.
+	removed, err := state.PruneTools(s.State, time.Now().Add(-time.Hour), false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+
+	expected := []version.Binary{
+		version.MustParseBinary("1.2.3-trusty-amd64"),
+		version.MustParseBinary("1.2.5-trusty-amd64"),
+	}
+	removed, err = state.PruneTools(s.State, time.Now().Add(time.Hour), true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, expected)
+	metadata, err := storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 4)
+
+	removed, err = state.PruneTools(s.State, time.Now().Add(time.Hour), false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, expected)
+	metadata, err = storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	var remaining []string
+	for _, m := range metadata {
+		remaining = append(remaining, m.Version.String())
+	}
+	c.Assert(remaining, jc.SameContents, []string{current.String(), "1.2.4-trusty-amd64"})
+}
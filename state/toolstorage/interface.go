@@ -5,6 +5,7 @@ package toolstorage
 
 import (
 	"io"
+	"time"
 
 	"github.com/juju/juju/version"
 )
@@ -35,6 +36,17 @@ type Storage interface {
 	// Metadata returns the Metadata for the specified version
 	// if it exists, else an error satisfying errors.IsNotFound.
 	Metadata(v version.Binary) (Metadata, error)
+
+	// MetadataAddedBefore returns metadata for the tools added to the
+	// catalogue before the specified time. Tools added before the
+	// time of addition was recorded are always included.
+	MetadataAddedBefore(time.Time) ([]Metadata, error)
+
+	// RemoveTools removes the metadata for the specified version, and
+	// the tools tarball if no other version refers to it. If there is
+	// no such version, an error satisfying errors.IsNotFound is
+	// returned.
+	RemoveTools(version.Binary) error
 }
 
 // StorageCloser extends the Storage interface with a Close method.
@@ -6,6 +6,7 @@ package toolstorage
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/juju/blobstore"
 	"github.com/juju/errors"
@@ -68,6 +69,7 @@ func (s *toolsStorage) AddTools(r io.Reader, metadata Metadata) (resultErr error
 		Size:    metadata.Size,
 		SHA256:  metadata.SHA256,
 		Path:    path,
+		Created: time.Now(),
 	}
 
 	// Add or replace metadata. If replacing, record the
@@ -99,6 +101,7 @@ func (s *toolsStorage) AddTools(r io.Reader, metadata Metadata) (resultErr error
 						{"size", metadata.Size},
 						{"sha256", metadata.SHA256},
 						{"path", path},
+						{"created", newDoc.Created},
 					},
 				}}
 			}
@@ -169,12 +172,66 @@ func (s *toolsStorage) AllMetadata() ([]Metadata, error) {
 	return list, nil
 }
 
+func (s *toolsStorage) MetadataAddedBefore(t time.Time) ([]Metadata, error) {
+	var docs []toolsMetadataDoc
+	query := bson.D{{"$or", []bson.D{
+		{{"created", bson.D{{"$lt", t}}}},
+		{{"created", bson.D{{"$exists", false}}}},
+	}}}
+	if err := s.metadataCollection.Find(query).All(&docs); err != nil {
+		return nil, err
+	}
+	list := make([]Metadata, len(docs))
+	for i, doc := range docs {
+		list[i] = Metadata{
+			Version: doc.Version,
+			Size:    doc.Size,
+			SHA256:  doc.SHA256,
+		}
+	}
+	return list, nil
+}
+
+func (s *toolsStorage) RemoveTools(v version.Binary) error {
+	metadataDoc, err := s.toolsMetadata(v)
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      s.metadataCollection.Name,
+		Id:     metadataDoc.Id,
+		Assert: bson.D{{"path", metadataDoc.Path}},
+		Remove: true,
+	}}
+	if err := s.txnRunner.RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot remove %v tools metadata: tools changed", v)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove %v tools metadata", v)
+	}
+
+	// Other versions may be aliases for the same tarball,
+	// in which case it must be kept.
+	n, err := s.metadataCollection.Find(bson.D{{"path", metadataDoc.Path}}).Count()
+	if err != nil {
+		return errors.Annotate(err, "cannot count tools referring to tarball")
+	}
+	if n > 0 {
+		return nil
+	}
+	if err := s.managedStorage.RemoveForEnvironment(s.envUUID, metadataDoc.Path); err != nil {
+		// The metadata is gone, so failure is non-fatal.
+		logger.Errorf("failed to remove tools blob: %v", err)
+	}
+	return nil
+}
+
 type toolsMetadataDoc struct {
 	Id      string         `bson:"_id"`
 	Version version.Binary `bson:"version"`
 	Size    int64          `bson:"size"`
 	SHA256  string         `bson:"sha256,omitempty"`
 	Path    string         `bson:"path"`
+	Created time.Time      `bson:"created,omitempty"`
 }
 
 func (s *toolsStorage) toolsMetadata(v version.Binary) (toolsMetadataDoc, error) {
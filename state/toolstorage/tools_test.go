@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/blobstore"
 	"github.com/juju/errors"
@@ -325,6 +326,70 @@ func (s *ToolsSuite) TestAddToolsExcessiveContention(c *gc.C) {
 	s.assertTools(c, metadata[3], "3")
 }
 
+func (s *ToolsSuite) TestMetadataAddedBefore(c *gc.C) {
+	// Tools whose time of addition is unknown are always included.
+	legacy := bumpVersion(version.Current)
+	s.addMetadataDoc(c, legacy, 3, "hash(abc)", "path")
+	err := s.storage.AddTools(strings.NewReader("def"), toolstorage.Metadata{
+		Version: version.Current,
+		Size:    3,
+		SHA256:  "hash(def)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	metadata, err := s.storage.MetadataAddedBefore(time.Now().Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, []toolstorage.Metadata{{
+		Version: legacy,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	}})
+
+	metadata, err = s.storage.MetadataAddedBefore(time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 2)
+}
+
+func (s *ToolsSuite) TestRemoveTools(c *gc.C) {
+	err := s.storage.AddTools(strings.NewReader("abc"), toolstorage.Metadata{
+		Version: version.Current,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storage.RemoveTools(version.Current)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storage.Metadata(version.Current)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	path := fmt.Sprintf("tools/%s-%s", version.Current, "hash(abc)")
+	_, _, err = s.managedStorage.GetForEnvironment("my-uuid", path)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.storage.RemoveTools(version.Current)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ToolsSuite) TestRemoveToolsKeepsAliasedTarball(c *gc.C) {
+	err := s.storage.AddTools(strings.NewReader("abc"), toolstorage.Metadata{
+		Version: version.Current,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	path := fmt.Sprintf("tools/%s-%s", version.Current, "hash(abc)")
+	alias := bumpVersion(version.Current)
+	s.addMetadataDoc(c, alias, 3, "hash(abc)", path)
+
+	err = s.storage.RemoveTools(version.Current)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTools(c, toolstorage.Metadata{
+		Version: alias,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	}, "abc")
+}
+
 func (s *ToolsSuite) addMetadataDoc(c *gc.C, v version.Binary, size int64, hash, path string) {
 	doc := struct {
 		Id      string         `bson:"_id"`
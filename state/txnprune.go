@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// txnPruneBatchSize is the number of transactions removed at a time.
+const txnPruneBatchSize = 1000
+
+// completedTxnStates holds the states of transactions that will not
+// be touched again by the txn package.
+var completedTxnStates = []int{5, 6} // aborted, applied
+
+// PruneTxns removes completed transactions that started before
+// minTxnTime from the txns collection. If the collection is still
+// larger than maxTxnsMB, all completed transactions are removed.
+//
+// Transactions still named in any document's txn-queue are never
+// removed, as the txn package may need them to work out the state of
+// the document. The txns.log collection is capped, and so is already
+// bounded in size.
+func PruneTxns(st *State, minTxnTime time.Time, maxTxnsMB int) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	db := st.db.With(session)
+	txns := db.C(txnsC)
+
+	// Transactions started after the referenced tokens are collected
+	// may not yet be visible in the txn-queues read, so they must not
+	// be considered for removal.
+	started := bson.NewObjectIdWithTime(time.Now())
+	referenced, err := referencedTxnIds(db)
+	if err != nil {
+		return errors.Annotate(err, "cannot find referenced transactions")
+	}
+
+	cutoff := started
+	if minTxnTime.Before(started.Time()) {
+		cutoff = bson.NewObjectIdWithTime(minTxnTime)
+	}
+	removed, err := removeCompletedTxns(txns, cutoff, referenced)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	collMB, err := getCollectionMB(txns)
+	if err != nil {
+		return errors.Annotate(err, "cannot get transaction collection size")
+	}
+	if collMB > maxTxnsMB && cutoff != started {
+		n, err := removeCompletedTxns(txns, started, referenced)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		removed += n
+	}
+	if removed > 0 {
+		logger.Debugf("pruned %d transactions", removed)
+	}
+	return nil
+}
+
+// referencedTxnIds returns the ids of all the transactions named in the
+// txn-queue of any document in the database, including the documents
+// stashed by the txn package.
+func referencedTxnIds(db *mgo.Database) (map[bson.ObjectId]bool, error) {
+	names, err := db.CollectionNames()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	referenced := make(map[bson.ObjectId]bool)
+	for _, name := range names {
+		if name == txnsC || name == txnLogC || strings.HasPrefix(name, "system.") {
+			continue
+		}
+		iter := db.C(name).Find(bson.D{
+			{"txn-queue.0", bson.D{{"$exists", true}}},
+		}).Select(bson.D{{"txn-queue", 1}}).Iter()
+		var doc struct {
+			Queue []string `bson:"txn-queue"`
+		}
+		for iter.Next(&doc) {
+			for _, token := range doc.Queue {
+				// Tokens are of the form "<txn id>_<nonce>".
+				if i := strings.Index(token, "_"); i > 0 && bson.IsObjectIdHex(token[:i]) {
+					referenced[bson.ObjectIdHex(token[:i])] = true
+				}
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return nil, errors.Annotatef(err, "cannot read %s", name)
+		}
+	}
+	return referenced, nil
+}
+
+// removeCompletedTxns removes the completed transactions that started
+// before the cutoff and are not referenced, returning how many were
+// removed.
+func removeCompletedTxns(txns *mgo.Collection, cutoff bson.ObjectId, referenced map[bson.ObjectId]bool) (int, error) {
+	iter := txns.Find(bson.D{
+		{"_id", bson.D{{"$lt", cutoff}}},
+		{"s", bson.D{{"$in", completedTxnStates}}},
+	}).Select(bson.D{{"_id", 1}}).Iter()
+	var doc struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	var batch []bson.ObjectId
+	removed := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		info, err := txns.RemoveAll(bson.D{{"_id", bson.D{{"$in", batch}}}})
+		if err != nil {
+			return errors.Annotate(err, "cannot remove transactions")
+		}
+		removed += info.Removed
+		batch = batch[:0]
+		return nil
+	}
+	for iter.Next(&doc) {
+		if referenced[doc.Id] {
+			continue
+		}
+		batch = append(batch, doc.Id)
+		if len(batch) == txnPruneBatchSize {
+			if err := flush(); err != nil {
+				iter.Close()
+				return removed, err
+			}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return removed, errors.Annotate(err, "cannot read transactions")
+	}
+	return removed, flush()
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type TxnPruneSuite struct {
+	ConnSuite
+	txnsColl *mgo.Collection
+}
+
+var _ = gc.Suite(&TxnPruneSuite{})
+
+func (s *TxnPruneSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.txnsColl = s.State.MongoSession().DB("juju").C("txns")
+}
+
+func (s *TxnPruneSuite) addTxn(c *gc.C, t time.Time, state int) bson.ObjectId {
+	id := bson.NewObjectIdWithTime(t)
+	err := s.txnsColl.Insert(bson.M{"_id": id, "s": state})
+	c.Assert(err, jc.ErrorIsNil)
+	return id
+}
+
+func (s *TxnPruneSuite) txnExists(c *gc.C, id bson.ObjectId) bool {
+	n, err := s.txnsColl.FindId(id).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	return n == 1
+}
+
+func (s *TxnPruneSuite) TestPruneTxnsByTime(c *gc.C) {
+	// Ids made from a time alone are identical, so each transaction
+	// starts a second after the last.
+	old := time.Now().Add(-48 * time.Hour)
+	applied := s.addTxn(c, old, 6)
+	aborted := s.addTxn(c, old.Add(time.Second), 5)
+	pending := s.addTxn(c, old.Add(2*time.Second), 2)
+	referenced := s.addTxn(c, old.Add(3*time.Second), 6)
+	recent := s.addTxn(c, time.Now().Add(-time.Minute), 6)
+
+	// A transaction still named in a document's txn-queue must be kept.
+	refs := s.State.MongoSession().DB("juju").C("txnprunetest")
+	err := refs.Insert(bson.M{
+		"_id":       "doc",
+		"txn-queue": []string{fmt.Sprintf("%s_12345678", referenced.Hex())},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	noPruneMB := int(1e9)
+	err = state.PruneTxns(s.State, time.Now().Add(-24*time.Hour), noPruneMB)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.txnExists(c, applied), jc.IsFalse)
+	c.Check(s.txnExists(c, aborted), jc.IsFalse)
+	c.Check(s.txnExists(c, pending), jc.IsTrue)
+	c.Check(s.txnExists(c, referenced), jc.IsTrue)
+	c.Check(s.txnExists(c, recent), jc.IsTrue)
+}
+
+func (s *TxnPruneSuite) TestPruneTxnsKeepsStateUsable(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.PruneTxns(s.State, time.Now().Add(time.Hour), int(1e9))
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+}
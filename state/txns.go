@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"reflect"
+	"sync/atomic"
 
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/set"
@@ -19,6 +20,29 @@ const (
 	txnAssertEnvIsNotAlive = false
 )
 
+// TxnMetrics holds the number of transactions run by all the State
+// instances in the process since it started.
+type TxnMetrics struct {
+	// Attempts holds the number of transactions attempted, including
+	// retries of transactions that were aborted.
+	Attempts int64
+
+	// Aborted holds the number of attempted transactions that were
+	// aborted because their assertions failed.
+	Aborted int64
+}
+
+// The following variables must be accessed atomically.
+var txnAttempts, txnAborted int64
+
+// GetTxnMetrics returns the number of transactions run so far.
+func GetTxnMetrics() TxnMetrics {
+	return TxnMetrics{
+		Attempts: atomic.LoadInt64(&txnAttempts),
+		Aborted:  atomic.LoadInt64(&txnAborted),
+	}
+}
+
 // txnRunner returns a jujutxn.Runner instance.
 //
 // If st.transactionRunner is non-nil, then that will be
@@ -94,7 +118,12 @@ type multiEnvRunner struct {
 // to ensure correct interaction with these collections.
 func (r *multiEnvRunner) RunTransaction(ops []txn.Op) error {
 	ops = r.updateOps(ops)
-	return r.rawRunner.RunTransaction(ops)
+	atomic.AddInt64(&txnAttempts, 1)
+	err := r.rawRunner.RunTransaction(ops)
+	if err == txn.ErrAborted {
+		atomic.AddInt64(&txnAborted, 1)
+	}
+	return err
 }
 
 // Run is part of the jujutxn.Run interface. Operations returned by
@@ -103,6 +132,10 @@ func (r *multiEnvRunner) RunTransaction(ops []txn.Op) error {
 // with these collections.
 func (r *multiEnvRunner) Run(transactions jujutxn.TransactionSource) error {
 	return r.rawRunner.Run(func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			// The runner only retries aborted transactions.
+			atomic.AddInt64(&txnAborted, 1)
+		}
 		ops, err := transactions(attempt)
 		if err != nil {
 			// Don't use Trace here as jujutxn doens't use juju/errors
@@ -110,6 +143,7 @@ func (r *multiEnvRunner) Run(transactions jujutxn.TransactionSource) error {
 			return nil, err
 		}
 		ops = r.updateOps(ops)
+		atomic.AddInt64(&txnAttempts, 1)
 		return ops, nil
 	})
 }
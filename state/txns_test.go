@@ -368,6 +368,21 @@ func (s *MultiEnvRunnerSuite) TestResumeTransactionsWithError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *MultiEnvRunnerSuite) TestTxnMetrics(c *gc.C) {
+	before := GetTxnMetrics()
+	err := s.multiEnvRunner.RunTransaction([]txn.Op{{C: "other", Id: "foo"}})
+	c.Assert(err, jc.ErrorIsNil)
+	// The recording runner makes a single attempt, numbered
+	// testTxnAttempt, which is counted as a retry.
+	err = s.multiEnvRunner.Run(func(attempt int) ([]txn.Op, error) {
+		return []txn.Op{{C: "other", Id: "foo"}}, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	after := GetTxnMetrics()
+	c.Assert(after.Attempts-before.Attempts, gc.Equals, int64(2))
+	c.Assert(after.Aborted-before.Aborted, gc.Equals, int64(1))
+}
+
 // recordingRunner is fake transaction running that implements the
 // jujutxn.Runner interface. Instead of doing anything with a database
 // it simply records the transaction operations passed to it for later
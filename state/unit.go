@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/juju/errors"
@@ -99,6 +100,10 @@ type unitDoc struct {
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
 
+	// ObservedConfig holds the config settings last observed by the
+	// unit's charm in a config-changed hook, with escaped keys.
+	ObservedConfig map[string]interface{} `bson:"observedconfig,omitempty"`
+
 	// TODO(mue) No longer actively used, only in upgrades.go.
 	// To be removed later.
 	Ports          []port `bson:"ports"`
@@ -149,6 +154,34 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// ObservedConfigSettings returns the config settings last observed by
+// the unit's charm in a config-changed hook, and whether it has run one
+// at all.
+func (u *Unit) ObservedConfigSettings() (charm.Settings, bool) {
+	if u.doc.ObservedConfig == nil {
+		return nil, false
+	}
+	return charm.Settings(copyMap(u.doc.ObservedConfig, unescapeReplacer.Replace)), true
+}
+
+// SetObservedConfigSettings records the config settings observed by the
+// unit's charm in a config-changed hook, so that they can be compared
+// with the service's current settings.
+func (u *Unit) SetObservedConfigSettings(settings charm.Settings) error {
+	observed := copyMap(settings, escapeReplacer.Replace)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"observedconfig", observed}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set observed config settings of unit %q", u)
+	}
+	u.doc.ObservedConfig = observed
+	return nil
+}
+
 // ServiceName returns the service name.
 func (u *Unit) ServiceName() string {
 	return u.doc.Service
@@ -742,6 +775,63 @@ func (u *Unit) PrivateAddress() (string, bool) {
 	return privateAddress, privateAddress != ""
 }
 
+// EndpointAddresses returns the addresses on which the unit should
+// serve the relation endpoint with the given name. An endpoint bound
+// to a space is served on the addresses of the unit's machine in the
+// space's subnets; any other endpoint is served on the unit's private
+// address.
+func (u *Unit) EndpointAddresses(endpoint string) ([]string, error) {
+	svc, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := svc.Endpoint(endpoint); err != nil {
+		return nil, errors.NotFoundf("endpoint %q of service %q", endpoint, svc)
+	}
+	spaceName := svc.EndpointSpace(endpoint)
+	if spaceName == "" {
+		address, ok := u.PrivateAddress()
+		if !ok {
+			return nil, errors.NotFoundf("private address of unit %q", u)
+		}
+		return []string{address}, nil
+	}
+	space, err := u.st.Space(spaceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var networks []*net.IPNet
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		networks = append(networks, ipNet)
+	}
+	m, err := u.machine()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addresses []string
+	for _, address := range m.Addresses() {
+		ip := net.ParseIP(address.Value)
+		for _, ipNet := range networks {
+			if ip != nil && ipNet.Contains(ip) {
+				addresses = append(addresses, address.Value)
+				break
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, errors.NotFoundf("address of unit %q in space %q", u, spaceName)
+	}
+	return addresses, nil
+}
+
 // AvailabilityZone returns the name of the availability zone into which
 // the unit's machine instance was provisioned.
 func (u *Unit) AvailabilityZone() (string, error) {
@@ -826,7 +916,7 @@ func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}
 	if err != nil {
 		return fmt.Errorf("cannot set status of unit %q: %v", u, onAbort(err, ErrDead))
 	}
-
+	recordStatusHistory(u.st, u.globalKey(), doc.statusDoc)
 	return nil
 }
 
@@ -834,7 +924,14 @@ func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}
 // it does not conflict with another already opened range on the
 // unit's assigned machine.
 func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) (err error) {
-	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
+	return u.OpenPortsFrom(protocol, fromPort, toPort, "")
+}
+
+// OpenPortsFrom opens the given port range and protocol for the unit,
+// to connections from the given source CIDR only. An empty sourceCIDR
+// opens the range to connections from anywhere.
+func (u *Unit) OpenPortsFrom(protocol string, fromPort, toPort int, sourceCIDR string) (err error) {
+	ports, err := newSourcePortRange(u.Name(), fromPort, toPort, protocol, sourceCIDR)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
@@ -857,7 +954,13 @@ func (u *Unit) OpenPorts(protocol string, fromPort, toPort int) (err error) {
 
 // ClosePorts closes the given port range and protocol for the unit.
 func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) (err error) {
-	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
+	return u.ClosePortsFrom(protocol, fromPort, toPort, "")
+}
+
+// ClosePortsFrom closes the given port range and protocol, previously
+// opened with OpenPortsFrom to the given source CIDR, for the unit.
+func (u *Unit) ClosePortsFrom(protocol string, fromPort, toPort int, sourceCIDR string) (err error) {
+	ports, err := newSourcePortRange(u.Name(), fromPort, toPort, protocol, sourceCIDR)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
@@ -903,11 +1006,7 @@ func (u *Unit) OpenedPorts() ([]network.PortRange, error) {
 	if err == nil {
 		ports := machinePorts.PortsForUnit(u.Name())
 		for _, port := range ports {
-			result = append(result, network.PortRange{
-				Protocol: port.Protocol,
-				FromPort: port.FromPort,
-				ToPort:   port.ToPort,
-			})
+			result = append(result, port.NetworkPortRange())
 		}
 	} else {
 		if !errors.IsNotFound(err) {
@@ -1771,6 +1870,26 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+}
+
+// addQueuedAction is like AddAction, but records that the Action was
+// started from the given ActionQueue.
+func (u *Unit) addQueuedAction(name string, payload map[string]interface{}, queue string) (*Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.enqueueAction(u.Tag(), name, payloadWithDefaults, queue)
+}
+
+// actionPayload validates the payload of the named Action against the
+// unit's charm, and returns it with defaults inserted.
+func (u *Unit) actionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -1787,11 +1906,7 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if err != nil {
 		return nil, err
 	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
-	if err != nil {
-		return nil, err
-	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return spec.InsertDefaults(payload)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -4,7 +4,9 @@
 package state_test
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -84,6 +86,31 @@ func (s *UnitSuite) TestConfigSettingsReflectService(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "ironic title"})
 }
 
+func (s *UnitSuite) TestObservedConfigSettings(c *gc.C) {
+	_, observed := s.unit.ObservedConfigSettings()
+	c.Assert(observed, jc.IsFalse)
+
+	err := s.unit.SetObservedConfigSettings(charm.Settings{"blog-title": "My Title", "dotted.key": 42})
+	c.Assert(err, jc.ErrorIsNil)
+	expected := charm.Settings{"blog-title": "My Title", "dotted.key": 42}
+	settings, observed := s.unit.ObservedConfigSettings()
+	c.Assert(observed, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, expected)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	settings, observed = unit.ObservedConfigSettings()
+	c.Assert(observed, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, expected)
+}
+
+func (s *UnitSuite) TestSetObservedConfigSettingsDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetObservedConfigSettings(charm.Settings{"blog-title": "My Title"})
+	c.Assert(err, gc.ErrorMatches, `cannot set observed config settings of unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestConfigSettingsReflectCharm(c *gc.C) {
 	err := s.unit.SetCharmURL(s.charm.URL())
 	c.Assert(err, jc.ErrorIsNil)
@@ -109,6 +136,32 @@ func (s *UnitSuite) TestWatchConfigSettingsNeedsCharmURL(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "unit charm not set")
 }
 
+func (s *UnitSuite) TestWatchConfigSettingsCoalescesBursts(c *gc.C) {
+	err := s.State.SetWatcherCoalescing(state.WatcherCoalescing{
+		Window:   time.Second,
+		MaxDelay: coretesting.LongWait,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := s.unit.WatchConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changes made while the watcher is being read are not reported
+	// until they stop for the coalescing window.
+	for i := 0; i < 3; i++ {
+		err = s.service.UpdateConfigSettings(charm.Settings{
+			"blog-title": fmt.Sprintf("title %d", i),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		wc.AssertNoChange()
+	}
+	wc.AssertOneChange()
+}
+
 func (s *UnitSuite) TestWatchConfigSettings(c *gc.C) {
 	err := s.unit.SetCharmURL(s.charm.URL())
 	c.Assert(err, jc.ErrorIsNil)
@@ -283,6 +336,67 @@ func (s *UnitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 }
 
+func (s *UnitSuite) TestEndpointAddresses(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.unit.EndpointAddresses("db")
+	c.Assert(err, gc.ErrorMatches, `private address of unit "wordpress/0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	public := network.NewScopedAddress("8.8.8.8", network.ScopePublic)
+	private := network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal)
+	err = machine.SetAddresses(public, private)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses, err := s.unit.EndpointAddresses("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"10.0.0.1"})
+
+	_, err = s.unit.EndpointAddresses("missing")
+	c.Assert(err, gc.ErrorMatches, `endpoint "missing" of service "wordpress" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UnitSuite) TestEndpointAddressesBoundToSpace(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, cidr := range []string{"10.0.0.0/24", "192.168.1.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", []string{"192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetEndpointBindings(map[string]string{"db": "dmz"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetSpaceBinding("internal")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.unit.EndpointAddresses("db")
+	c.Assert(err, gc.ErrorMatches, `address of unit "wordpress/0" in space "dmz" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = machine.SetAddresses(
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.1.5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses, err := s.unit.EndpointAddresses("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"192.168.1.5"})
+	addresses, err = s.unit.EndpointAddresses("url")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"10.0.0.1"})
+}
+
 type destroyMachineTestCase struct {
 	target    *state.Unit
 	host      *state.Machine
@@ -1105,8 +1219,8 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{80, 80, "tcp"},
-		{100, 200, "udp"},
+		{80, 80, "tcp", ""},
+		{100, 200, "udp", ""},
 	})
 
 	err = s.unit.OpenPort("udp", 53)
@@ -1114,9 +1228,9 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{80, 80, "tcp"},
-		{53, 53, "udp"},
-		{100, 200, "udp"},
+		{80, 80, "tcp", ""},
+		{53, 53, "udp", ""},
+		{100, 200, "udp", ""},
 	})
 
 	err = s.unit.OpenPorts("tcp", 53, 55)
@@ -1124,10 +1238,10 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{53, 55, "tcp"},
-		{80, 80, "tcp"},
-		{53, 53, "udp"},
-		{100, 200, "udp"},
+		{53, 55, "tcp", ""},
+		{80, 80, "tcp", ""},
+		{53, 53, "udp", ""},
+		{100, 200, "udp", ""},
 	})
 
 	err = s.unit.OpenPort("tcp", 443)
@@ -1135,11 +1249,11 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{53, 55, "tcp"},
-		{80, 80, "tcp"},
-		{443, 443, "tcp"},
-		{53, 53, "udp"},
-		{100, 200, "udp"},
+		{53, 55, "tcp", ""},
+		{80, 80, "tcp", ""},
+		{443, 443, "tcp", ""},
+		{53, 53, "udp", ""},
+		{100, 200, "udp", ""},
 	})
 
 	err = s.unit.ClosePort("tcp", 80)
@@ -1147,10 +1261,10 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{53, 55, "tcp"},
-		{443, 443, "tcp"},
-		{53, 53, "udp"},
-		{100, 200, "udp"},
+		{53, 55, "tcp", ""},
+		{443, 443, "tcp", ""},
+		{53, 53, "udp", ""},
+		{100, 200, "udp", ""},
 	})
 
 	err = s.unit.ClosePorts("udp", 100, 200)
@@ -1158,10 +1272,52 @@ func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	open, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(open, gc.DeepEquals, []network.PortRange{
-		{53, 55, "tcp"},
-		{443, 443, "tcp"},
-		{53, 53, "udp"},
+		{53, 55, "tcp", ""},
+		{443, 443, "tcp", ""},
+		{53, 53, "udp", ""},
+	})
+}
+
+func (s *UnitSuite) TestOpenedPortsFrom(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.OpenPortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPortsFrom("tcp", 443, 443, "192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	open, err := s.unit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(open, gc.DeepEquals, []network.PortRange{
+		{80, 80, "tcp", ""},
+		{443, 443, "tcp", "10.0.0.0/8"},
+		{443, 443, "tcp", "192.168.0.0/16"},
+	})
+
+	// The same ports opened by another unit on the machine conflict,
+	// whatever their sources.
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPortsFrom("tcp", 443, 443, "172.16.0.0/12")
+	c.Assert(err, gc.ErrorMatches, `cannot open ports 443-443/tcp from 172.16.0.0/12 \("wordpress/1"\) for unit "wordpress/1": .*conflict.*`)
+
+	err = s.unit.ClosePortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	open, err = s.unit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(open, gc.DeepEquals, []network.PortRange{
+		{80, 80, "tcp", ""},
+		{443, 443, "tcp", "192.168.0.0/16"},
 	})
+
+	err = s.unit.OpenPortsFrom("tcp", 8080, 8080, "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `invalid port range 8080-8080/tcp: invalid source CIDR "10.0.0.0"`)
 }
 
 func (s *UnitSuite) TestOpenClosePortWhenDying(c *gc.C) {
@@ -1209,7 +1365,7 @@ func (s *UnitSuite) TestRemoveLastUnitOnMachineRemovesAllPorts(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
-		{s.unit.Name(), 100, 200, "tcp"},
+		{s.unit.Name(), 100, 200, "tcp", ""},
 	})
 
 	// Now remove the unit and check again.
@@ -1251,10 +1407,10 @@ func (s *UnitSuite) TestRemoveUnitRemovesItsPortsOnly(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
-		{s.unit.Name(), 100, 200, "tcp"},
+		{s.unit.Name(), 100, 200, "tcp", ""},
 	})
 	c.Assert(ports[0].PortsForUnit(otherUnit.Name()), jc.DeepEquals, []state.PortRange{
-		{otherUnit.Name(), 300, 400, "udp"},
+		{otherUnit.Name(), 300, 400, "udp", ""},
 	})
 
 	// Now remove the first unit and check again.
@@ -1271,7 +1427,7 @@ func (s *UnitSuite) TestRemoveUnitRemovesItsPortsOnly(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), gc.HasLen, 0)
 	c.Assert(ports[0].PortsForUnit(otherUnit.Name()), jc.DeepEquals, []state.PortRange{
-		{otherUnit.Name(), 300, 400, "udp"},
+		{otherUnit.Name(), 300, 400, "udp", ""},
 	})
 }
 
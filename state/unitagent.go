@@ -57,6 +57,7 @@ func (u *UnitAgent) SetStatus(status Status, info string, data map[string]interf
 	if err != nil {
 		return errors.Errorf("cannot set status of unit agent %q: %v", u, onAbort(err, ErrDead))
 	}
+	recordStatusHistory(u.st, u.globalKey(), doc.statusDoc)
 	return nil
 }
 
@@ -493,8 +493,14 @@ func currentUpgradeInfoDoc(st *State) (*upgradeInfoDoc, error) {
 }
 
 func checkUpgradeInfoSanity(st *State, machineId string, previousVersion, targetVersion version.Number) (bson.D, error) {
-	if previousVersion.Compare(targetVersion) != -1 {
+	switch previousVersion.Compare(targetVersion) {
+	case 0:
 		return nil, errors.Errorf("cannot sanely upgrade from %s to %s", previousVersion, targetVersion)
+	case 1:
+		// Rolling back is only supported within a minor series.
+		if previousVersion.Major != targetVersion.Major || previousVersion.Minor != targetVersion.Minor {
+			return nil, errors.Errorf("cannot roll back from %s to %s: not in the same minor series", previousVersion, targetVersion)
+		}
 	}
 	stateServerInfo, err := st.StateServerInfo()
 	if err != nil {
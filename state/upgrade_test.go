@@ -131,7 +131,7 @@ func (s *UpgradeSuite) TestEnsureUpgradeInfoDowngrade(c *gc.C) {
 	v111 := vers("1.1.1")
 
 	info, err := s.State.EnsureUpgradeInfo(s.serverIdA, v123, v111)
-	c.Assert(err, gc.ErrorMatches, "cannot sanely upgrade from 1.2.3 to 1.1.1")
+	c.Assert(err, gc.ErrorMatches, "cannot roll back from 1.2.3 to 1.1.1: not in the same minor series")
 	c.Assert(info, gc.IsNil)
 
 	info, err = s.State.EnsureUpgradeInfo(s.serverIdA, v123, v123)
@@ -139,6 +139,13 @@ func (s *UpgradeSuite) TestEnsureUpgradeInfoDowngrade(c *gc.C) {
 	c.Assert(info, gc.IsNil)
 }
 
+func (s *UpgradeSuite) TestEnsureUpgradeInfoRollback(c *gc.C) {
+	info, err := s.State.EnsureUpgradeInfo(s.serverIdA, vers("1.2.3"), vers("1.2.1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.PreviousVersion(), gc.Equals, vers("1.2.3"))
+	c.Assert(info.TargetVersion(), gc.Equals, vers("1.2.1"))
+}
+
 func (s *UpgradeSuite) TestEnsureUpgradeInfoNonStateServer(c *gc.C) {
 	info, err := s.State.EnsureUpgradeInfo("2345678", vers("1.2.3"), vers("2.3.4"))
 	c.Assert(err, gc.ErrorMatches, `machine "2345678" is not a state server`)
@@ -0,0 +1,183 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// currentUpgradePlanId is the id of the upgrade plan document of an
+// environment; there is at most one.
+const currentUpgradePlanId = "current"
+
+// UpgradeWave identifies the agents that are told to run the
+// environment's agent version during an upgrade with canaries.
+type UpgradeWave string
+
+const (
+	// UpgradeWaveCanary means that only the state servers and the
+	// canary machines, with their containers and units, upgrade. The
+	// upgrade pauses in this wave until the operator resumes it.
+	UpgradeWaveCanary UpgradeWave = "canary"
+
+	// UpgradeWaveAll means that all agents upgrade.
+	UpgradeWaveAll UpgradeWave = "all"
+)
+
+// UpgradePlan describes an upgrade of the environment's agents that
+// proceeds in waves: the state servers and a set of canary machines
+// upgrade first, and the rest of the agents only once the operator
+// resumes the upgrade.
+type UpgradePlan struct {
+	st  *State
+	doc upgradePlanDoc
+}
+
+type upgradePlanDoc struct {
+	DocID           string         `bson:"_id"`
+	EnvUUID         string         `bson:"env-uuid"`
+	PreviousVersion version.Number `bson:"previousversion"`
+	TargetVersion   version.Number `bson:"targetversion"`
+	Canaries        []string       `bson:"canaries"`
+	Wave            UpgradeWave    `bson:"wave"`
+}
+
+// PreviousVersion returns the agent version the environment ran before
+// the upgrade.
+func (p *UpgradePlan) PreviousVersion() version.Number {
+	return p.doc.PreviousVersion
+}
+
+// TargetVersion returns the agent version the environment is being
+// upgraded to.
+func (p *UpgradePlan) TargetVersion() version.Number {
+	return p.doc.TargetVersion
+}
+
+// Canaries returns the ids of the machines that upgrade in the canary
+// wave.
+func (p *UpgradePlan) Canaries() []string {
+	return p.doc.Canaries
+}
+
+// Wave returns the current wave of the upgrade.
+func (p *UpgradePlan) Wave() UpgradeWave {
+	return p.doc.Wave
+}
+
+// MachineInWave reports whether the agents of the machine with the
+// given id, and of the units assigned to it, are told to upgrade in
+// the current wave. Containers upgrade along with their host machines.
+func (p *UpgradePlan) MachineInWave(machineId string) bool {
+	if p.doc.Wave == UpgradeWaveAll {
+		return true
+	}
+	for _, canary := range p.doc.Canaries {
+		if machineId == canary || strings.HasPrefix(machineId, canary+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// UpgradePlan returns the plan of the environment's current upgrade
+// with canaries, or an error satisfying errors.IsNotFound if there is
+// none.
+func (st *State) UpgradePlan() (*UpgradePlan, error) {
+	plans, closer := st.getCollection(upgradePlansC)
+	defer closer()
+
+	var doc upgradePlanDoc
+	err := plans.FindId(currentUpgradePlanId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("upgrade plan")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get upgrade plan")
+	}
+	return &UpgradePlan{st, doc}, nil
+}
+
+// SetEnvironAgentVersionWithCanaries sets the agent version for the
+// environment, like SetEnvironAgentVersion, but only the state servers
+// and the given canary machines upgrade until ResumeUpgrade is called.
+func (st *State) SetEnvironAgentVersionWithCanaries(newVersion version.Number, canaries []string) (err error) {
+	if len(canaries) == 0 {
+		return errors.New("no canary machines specified")
+	}
+	for _, id := range canaries {
+		if !names.IsValidMachine(id) {
+			return errors.NotValidf("machine id %q", id)
+		}
+		if _, err := st.Machine(id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return st.setEnvironAgentVersion(newVersion, canaries)
+}
+
+// upgradePlanOps returns the operations that record the plan for
+// upgrading from currentVersion to newVersion with the given canaries,
+// replacing any previous plan. If there are no canaries, the previous
+// plan is just removed.
+func (st *State) upgradePlanOps(currentVersion, newVersion version.Number, canaries []string) ([]txn.Op, error) {
+	plans, closer := st.getCollection(upgradePlansC)
+	defer closer()
+
+	count, err := plans.FindId(currentUpgradePlanId).Count()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	if count > 0 {
+		ops = append(ops, txn.Op{
+			C:      upgradePlansC,
+			Id:     st.docID(currentUpgradePlanId),
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+	}
+	if len(canaries) > 0 {
+		ops = append(ops, txn.Op{
+			C:  upgradePlansC,
+			Id: st.docID(currentUpgradePlanId),
+			Insert: &upgradePlanDoc{
+				EnvUUID:         st.EnvironUUID(),
+				PreviousVersion: currentVersion,
+				TargetVersion:   newVersion,
+				Canaries:        canaries,
+				Wave:            UpgradeWaveCanary,
+			},
+		})
+	}
+	return ops, nil
+}
+
+// ResumeUpgrade moves the environment's current upgrade with canaries
+// on to upgrading all agents. It is not an error to resume an upgrade
+// that is already upgrading all agents.
+func (st *State) ResumeUpgrade() error {
+	if _, err := st.UpgradePlan(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      upgradePlansC,
+		Id:     st.docID(currentUpgradePlanId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"wave", UpgradeWaveAll}}}},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("upgrade plan")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot resume upgrade")
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type UpgradePlanSuite struct {
+	ConnSuite
+	currentVersion version.Number
+}
+
+var _ = gc.Suite(&UpgradePlanSuite{})
+
+func (s *UpgradePlanSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, ok := envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	s.currentVersion = agentVersion
+
+	// Add two machines running the current version.
+	for i := 0; i < 2; i++ {
+		machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = machine.SetAgentVersion(version.Binary{
+			Number: agentVersion,
+			Series: "quantal",
+			Arch:   "amd64",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *UpgradePlanSuite) assertNoPlan(c *gc.C) {
+	_, err := s.State.UpgradePlan()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "upgrade plan not found")
+}
+
+func (s *UpgradePlanSuite) TestNoPlan(c *gc.C) {
+	s.assertNoPlan(c)
+}
+
+func (s *UpgradePlanSuite) TestSetEnvironAgentVersionWithCanaries(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, _ := envConfig.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, version.MustParse("4.5.6"))
+
+	plan, err := s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.PreviousVersion(), gc.Equals, s.currentVersion)
+	c.Assert(plan.TargetVersion(), gc.Equals, version.MustParse("4.5.6"))
+	c.Assert(plan.Canaries(), jc.DeepEquals, []string{"1"})
+	c.Assert(plan.Wave(), gc.Equals, state.UpgradeWaveCanary)
+}
+
+func (s *UpgradePlanSuite) TestSetEnvironAgentVersionWithCanariesErrors(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), nil)
+	c.Assert(err, gc.ErrorMatches, "no canary machines specified")
+
+	err = s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+
+	err = s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"42"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.assertNoPlan(c)
+}
+
+func (s *UpgradePlanSuite) TestSetEnvironAgentVersionRemovesPlan(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Bring the agents up to date, so the environment can be upgraded
+	// again.
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, machine := range machines {
+		err = machine.SetAgentVersion(version.MustParseBinary("4.5.6-quantal-amd64"))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err = s.State.SetEnvironAgentVersion(version.MustParse("4.5.7"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoPlan(c)
+}
+
+func (s *UpgradePlanSuite) TestResumeUpgrade(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Wave(), gc.Equals, state.UpgradeWaveAll)
+
+	// Resuming again is a no-op.
+	err = s.State.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradePlanSuite) TestResumeUpgradeWithoutPlan(c *gc.C) {
+	err := s.State.ResumeUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradePlanSuite) TestMachineInWave(c *gc.C) {
+	err := s.State.SetEnvironAgentVersionWithCanaries(version.MustParse("4.5.6"), []string{"1"})
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(plan.MachineInWave("1"), jc.IsTrue)
+	c.Check(plan.MachineInWave("1/lxc/0"), jc.IsTrue)
+	c.Check(plan.MachineInWave("0"), jc.IsFalse)
+	c.Check(plan.MachineInWave("10"), jc.IsFalse)
+
+	err = s.State.ResumeUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err = s.State.UpgradePlan()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(plan.MachineInWave("0"), jc.IsTrue)
+}
@@ -1148,6 +1148,38 @@ func FixSequenceFields(st *State) error {
 	return st.runRawTransaction(ops)
 }
 
+// AddTagsToAnnotations sets the tags field of all annotation documents
+// that lack it, so that the entities they annotate can be found by
+// their annotations.
+func AddTagsToAnnotations(st *State) error {
+	annotations, closer := st.getRawCollection(annotationsC)
+	defer closer()
+
+	sel := bson.D{{"tags", bson.D{{"$exists", false}}}}
+	iter := annotations.Find(sel).Select(bson.D{{"annotations", 1}}).Iter()
+	defer iter.Close()
+
+	ops := []txn.Op{}
+	var doc struct {
+		DocID       string            `bson:"_id"`
+		Annotations map[string]string `bson:"annotations"`
+	}
+	for iter.Next(&doc) {
+		ops = append(ops, txn.Op{
+			C:      annotationsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"tags", annotationTags(doc.Annotations)}}}},
+		})
+		// Unmarshalling into a map adds to it rather than replacing it.
+		doc.Annotations = nil
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	return st.runRawTransaction(ops)
+}
+
 // DropOldIndexesv123 drops old mongo indexes.
 func DropOldIndexesv123(st *State) error {
 	for collName, indexes := range oldIndexesv123 {
@@ -1593,7 +1593,7 @@ func (s *upgradesSuite) setUpPortsMigration(c *gc.C) ([]*Machine, map[int][]*Uni
 }
 
 func (s *upgradesSuite) newRange(from, to int, proto string) network.PortRange {
-	return network.PortRange{from, to, proto}
+	return network.PortRange{from, to, proto, ""}
 }
 
 func (s *upgradesSuite) assertInitialMachinePorts(c *gc.C, machines []*Machine, units map[int][]*Unit) {
@@ -2503,3 +2503,42 @@ func (s *upgradesSuite) TestIPAddressLifeIdempotent(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(doc.Life, gc.Equals, Alive)
 }
+
+func (s *upgradesSuite) TestAddTagsToAnnotations(c *gc.C) {
+	annotations, closer := s.state.getRawCollection(annotationsC)
+	defer closer()
+
+	uuid := s.state.EnvironUUID()
+	err := annotations.Insert(
+		// This record should have its tags set.
+		bson.D{
+			{"_id", uuid + ":m#0"},
+			{"env-uuid", uuid},
+			{"globalkey", "m#0"},
+			{"tag", "machine-0"},
+			{"annotations", bson.M{"role": "db", "tier": "production"}},
+		},
+		// This record should be left untouched.
+		bson.D{
+			{"_id", uuid + ":m#1"},
+			{"env-uuid", uuid},
+			{"globalkey", "m#1"},
+			{"tag", "machine-1"},
+			{"annotations", bson.M{"role": "web"}},
+			{"tags", []string{"role=web"}},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = AddTagsToAnnotations(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	err = AddTagsToAnnotations(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []annotatorDoc
+	err = annotations.Find(nil).Sort("_id").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	c.Assert(docs[0].Tags, jc.DeepEquals, []string{"role=db", "tier=production"})
+	c.Assert(docs[1].Tags, jc.DeepEquals, []string{"role=web"})
+}
@@ -0,0 +1,365 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// UpgradeSeriesStatus describes the progress of a series upgrade, both
+// of a machine and of each of its units.
+type UpgradeSeriesStatus string
+
+const (
+	// UpgradeSeriesPrepareStarted means that the units are running
+	// their pre-series-upgrade hooks.
+	UpgradeSeriesPrepareStarted UpgradeSeriesStatus = "prepare started"
+
+	// UpgradeSeriesPrepareCompleted means that the units have run
+	// their pre-series-upgrade hooks, and their agents are paused
+	// while the operator upgrades the machine's operating system.
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare completed"
+
+	// UpgradeSeriesCompleteStarted means that the operating system
+	// has been upgraded, and the units are running their
+	// post-series-upgrade hooks.
+	UpgradeSeriesCompleteStarted UpgradeSeriesStatus = "complete started"
+
+	// UpgradeSeriesCompleted means that a unit has run its
+	// post-series-upgrade hook. Once all the units have done so, the
+	// machine's series is updated and the upgrade is over.
+	UpgradeSeriesCompleted UpgradeSeriesStatus = "completed"
+)
+
+// UpgradeSeriesLock records the progress of the series upgrade of a
+// machine, from the time it is prepared until all of the machine's
+// units have completed it.
+type UpgradeSeriesLock struct {
+	doc upgradeSeriesLockDoc
+}
+
+type upgradeSeriesLockDoc struct {
+	DocID      string                         `bson:"_id"`
+	EnvUUID    string                         `bson:"env-uuid"`
+	MachineId  string                         `bson:"machineid"`
+	FromSeries string                         `bson:"fromseries"`
+	ToSeries   string                         `bson:"toseries"`
+	Status     UpgradeSeriesStatus            `bson:"status"`
+	Units      map[string]UpgradeSeriesStatus `bson:"units"`
+}
+
+// FromSeries returns the series the machine ran before the upgrade.
+func (l *UpgradeSeriesLock) FromSeries() string {
+	return l.doc.FromSeries
+}
+
+// ToSeries returns the series the machine is being upgraded to.
+func (l *UpgradeSeriesLock) ToSeries() string {
+	return l.doc.ToSeries
+}
+
+// Status returns the progress of the upgrade of the machine.
+func (l *UpgradeSeriesLock) Status() UpgradeSeriesStatus {
+	return l.doc.Status
+}
+
+// UnitStatuses returns the progress of the upgrade of each of the
+// units that were assigned to the machine when it was prepared, keyed
+// by unit name.
+func (l *UpgradeSeriesLock) UnitStatuses() map[string]UpgradeSeriesStatus {
+	statuses := make(map[string]UpgradeSeriesStatus)
+	for name, status := range l.doc.Units {
+		statuses[name] = status
+	}
+	return statuses
+}
+
+// unitsNotAt returns the sorted names of the units whose status is not
+// the given one.
+func (l *UpgradeSeriesLock) unitsNotAt(status UpgradeSeriesStatus) []string {
+	var names []string
+	for name, unitStatus := range l.doc.Units {
+		if unitStatus != status {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getUpgradeSeriesLock returns the series upgrade lock of the machine
+// with the given id, or an error satisfying errors.IsNotFound if the
+// machine's series is not being upgraded.
+func (st *State) getUpgradeSeriesLock(machineId string) (*UpgradeSeriesLock, error) {
+	locks, closer := st.getCollection(upgradeSeriesLocksC)
+	defer closer()
+
+	var doc upgradeSeriesLockDoc
+	err := locks.FindId(machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("series upgrade of machine %s", machineId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get series upgrade of machine %s", machineId)
+	}
+	return &UpgradeSeriesLock{doc}, nil
+}
+
+// removeUpgradeSeriesLockOp returns the operation that removes the
+// series upgrade lock of the machine with the given id, if any.
+func removeUpgradeSeriesLockOp(st *State, machineId string) txn.Op {
+	return txn.Op{
+		C:      upgradeSeriesLocksC,
+		Id:     st.docID(machineId),
+		Remove: true,
+	}
+}
+
+// finishUpgradeSeriesOps returns the operations that record the new
+// series of the machine, and remove its series upgrade lock.
+func finishUpgradeSeriesOps(st *State, lock *UpgradeSeriesLock) []txn.Op {
+	return []txn.Op{{
+		C:      machinesC,
+		Id:     st.docID(lock.doc.MachineId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"series", lock.doc.ToSeries}}}},
+	}, {
+		C:      upgradeSeriesLocksC,
+		Id:     lock.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+}
+
+// UpgradeSeriesLock returns the progress of the machine's series
+// upgrade, or an error satisfying errors.IsNotFound if the machine's
+// series is not being upgraded.
+func (m *Machine) UpgradeSeriesLock() (*UpgradeSeriesLock, error) {
+	return m.st.getUpgradeSeriesLock(m.doc.Id)
+}
+
+// PrepareUpgradeSeries starts upgrading the machine's series to the
+// given one. The units assigned to the machine run their
+// pre-series-upgrade hooks and then pause, so that the operator can
+// upgrade the operating system of the machine before calling
+// CompleteUpgradeSeries. Units assigned to the machine later take no
+// part in the upgrade.
+func (m *Machine) PrepareUpgradeSeries(toSeries string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot prepare series upgrade of machine %s", m)
+	if m.IsManager() {
+		return errors.New("machine is a state server")
+	}
+	toOS, err := version.GetOSFromSeries(toSeries)
+	if err != nil {
+		return errors.NotValidf("series %q", toSeries)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, errors.New("machine is not alive")
+		}
+		if m.doc.ContainerType != "" && m.doc.ContainerType != "none" {
+			return nil, errors.New("machine is a container")
+		}
+		if m.doc.Series == toSeries {
+			return nil, errors.Errorf("machine is already running series %q", toSeries)
+		}
+		if fromOS, err := version.GetOSFromSeries(m.doc.Series); err != nil || fromOS != toOS {
+			return nil, errors.Errorf("cannot upgrade from series %q to %q", m.doc.Series, toSeries)
+		}
+		if _, err := m.UpgradeSeriesLock(); err == nil {
+			return nil, errors.New("series upgrade already in progress")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		units, err := m.Units()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		lock := &UpgradeSeriesLock{upgradeSeriesLockDoc{
+			DocID:      m.st.docID(m.doc.Id),
+			EnvUUID:    m.st.EnvironUUID(),
+			MachineId:  m.doc.Id,
+			FromSeries: m.doc.Series,
+			ToSeries:   toSeries,
+			Status:     UpgradeSeriesPrepareStarted,
+			Units:      make(map[string]UpgradeSeriesStatus),
+		}}
+		for _, unit := range units {
+			lock.doc.Units[unit.Name()] = UpgradeSeriesPrepareStarted
+		}
+		if len(units) == 0 {
+			// There is nothing to prepare.
+			lock.doc.Status = UpgradeSeriesPrepareCompleted
+		}
+		return []txn.Op{{
+			C:  machinesC,
+			Id: m.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"series", m.doc.Series},
+			},
+		}, {
+			C:      upgradeSeriesLocksC,
+			Id:     lock.doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &lock.doc,
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// CompleteUpgradeSeries resumes the agents of the machine's units
+// once the operator has upgraded the operating system of the machine.
+// The units run their post-series-upgrade hooks, after which the
+// machine's series is updated. It fails if any of the units has not
+// yet run its pre-series-upgrade hook.
+func (m *Machine) CompleteUpgradeSeries() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot complete series upgrade of machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		lock, err := m.UpgradeSeriesLock()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch lock.doc.Status {
+		case UpgradeSeriesPrepareStarted:
+			return nil, errors.Errorf(
+				"units have not finished preparing: %v",
+				lock.unitsNotAt(UpgradeSeriesPrepareCompleted),
+			)
+		case UpgradeSeriesCompleteStarted:
+			return nil, jujutxn.ErrNoOperations
+		}
+		if len(lock.doc.Units) == 0 {
+			return finishUpgradeSeriesOps(m.st, lock), nil
+		}
+		units := make(bson.D, 0, len(lock.doc.Units))
+		for name := range lock.doc.Units {
+			units = append(units, bson.DocElem{"units." + name, UpgradeSeriesCompleteStarted})
+		}
+		return []txn.Op{{
+			C:      upgradeSeriesLocksC,
+			Id:     lock.doc.DocID,
+			Assert: bson.D{{"status", UpgradeSeriesPrepareCompleted}},
+			Update: bson.D{{"$set", append(units, bson.DocElem{"status", UpgradeSeriesCompleteStarted})}},
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// upgradeSeriesLock returns the series upgrade lock of the unit's
+// machine, or an error satisfying errors.IsNotFound if the unit is not
+// taking part in a series upgrade.
+func (u *Unit) upgradeSeriesLock() (*UpgradeSeriesLock, error) {
+	machineId, err := u.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := u.st.getUpgradeSeriesLock(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := lock.doc.Units[u.doc.Name]; !ok {
+		return nil, errors.NotFoundf("series upgrade of unit %q", u.doc.Name)
+	}
+	return lock, nil
+}
+
+// UpgradeSeriesStatus returns the progress of the unit in the series
+// upgrade of its machine, or an error satisfying errors.IsNotFound if
+// the unit is not taking part in a series upgrade.
+func (u *Unit) UpgradeSeriesStatus() (UpgradeSeriesStatus, error) {
+	lock, err := u.upgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return lock.doc.Units[u.doc.Name], nil
+}
+
+// SetUpgradeSeriesStatus records the unit's progress in the series
+// upgrade of its machine. A unit moves to UpgradeSeriesPrepareCompleted
+// once it has run its pre-series-upgrade hook, and to
+// UpgradeSeriesCompleted once it has run its post-series-upgrade hook;
+// the machine moves on with the last of its units.
+func (u *Unit) SetUpgradeSeriesStatus(status UpgradeSeriesStatus) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set series upgrade status of unit %q", u)
+	var from UpgradeSeriesStatus
+	switch status {
+	case UpgradeSeriesPrepareCompleted:
+		from = UpgradeSeriesPrepareStarted
+	case UpgradeSeriesCompleted:
+		from = UpgradeSeriesCompleteStarted
+	default:
+		return errors.NotValidf("status %q", status)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		lock, err := u.upgradeSeriesLock()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		current := lock.doc.Units[u.doc.Name]
+		if current == status {
+			return nil, jujutxn.ErrNoOperations
+		} else if current != from {
+			return nil, fmt.Errorf("unit is %s, not %s", current, from)
+		}
+		lock.doc.Units[u.doc.Name] = status
+		unitKey := "units." + u.doc.Name
+		if len(lock.unitsNotAt(status)) > 0 {
+			return []txn.Op{{
+				C:      upgradeSeriesLocksC,
+				Id:     lock.doc.DocID,
+				Assert: bson.D{{unitKey, from}},
+				Update: bson.D{{"$set", bson.D{{unitKey, status}}}},
+			}}, nil
+		}
+		// This is the last unit to make progress, so the machine
+		// moves on too; the assertions ensure that the other units
+		// have not moved since they were read.
+		assert := make(bson.D, 0, len(lock.doc.Units))
+		for name, unitStatus := range lock.doc.Units {
+			if name == u.doc.Name {
+				unitStatus = from
+			}
+			assert = append(assert, bson.DocElem{"units." + name, unitStatus})
+		}
+		if status == UpgradeSeriesCompleted {
+			ops := finishUpgradeSeriesOps(u.st, lock)
+			ops[1].Assert = assert
+			return ops, nil
+		}
+		return []txn.Op{{
+			C:      upgradeSeriesLocksC,
+			Id:     lock.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{
+				{unitKey, status},
+				{"status", UpgradeSeriesPrepareCompleted},
+			}}},
+		}}, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher that reports
+// changes to the series upgrade of the unit's machine.
+func (u *Unit) WatchUpgradeSeriesNotifications() (NotifyWatcher, error) {
+	machineId, err := u.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newEntityWatcher(u.st, upgradeSeriesLocksC, u.st.docID(machineId)), nil
+}
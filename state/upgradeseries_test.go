@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	ConnSuite
+	machine *state.Machine
+	units   []*state.Unit
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.units = nil
+	for i := 0; i < 2; i++ {
+		unit, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(s.machine)
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *UpgradeSeriesSuite) assertUnitStatus(c *gc.C, unit *state.Unit, expect state.UpgradeSeriesStatus) {
+	status, err := unit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, expect)
+}
+
+func (s *UpgradeSeriesSuite) assertMachineStatus(c *gc.C, expect state.UpgradeSeriesStatus) {
+	lock, err := s.machine.UpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lock.Status(), gc.Equals, expect)
+}
+
+func (s *UpgradeSeriesSuite) TestNoUpgrade(c *gc.C) {
+	_, err := s.machine.UpgradeSeriesLock()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "series upgrade of machine 0 not found")
+	_, err = s.units[0].UpgradeSeriesStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeries(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	lock, err := s.machine.UpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lock.FromSeries(), gc.Equals, "quantal")
+	c.Assert(lock.ToSeries(), gc.Equals, "trusty")
+	c.Assert(lock.Status(), gc.Equals, state.UpgradeSeriesPrepareStarted)
+	c.Assert(lock.UnitStatuses(), jc.DeepEquals, map[string]state.UpgradeSeriesStatus{
+		"wordpress/0": state.UpgradeSeriesPrepareStarted,
+		"wordpress/1": state.UpgradeSeriesPrepareStarted,
+	})
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, gc.ErrorMatches, "cannot prepare series upgrade of machine 0: series upgrade already in progress")
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesInvalid(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("quantal")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine 0: machine is already running series "quantal"`)
+	err = s.machine.PrepareUpgradeSeries("win2012r2")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine 0: cannot upgrade from series "quantal" to "win2012r2"`)
+	err = s.machine.PrepareUpgradeSeries("nonsense")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade of machine 0: series "nonsense" not valid`)
+
+	manager, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = manager.PrepareUpgradeSeries("trusty")
+	c.Assert(err, gc.ErrorMatches, "cannot prepare series upgrade of machine 1: machine is a state server")
+}
+
+func (s *UpgradeSeriesSuite) TestCompleteUpgradeSeriesNotPrepared(c *gc.C) {
+	err := s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, gc.ErrorMatches, `cannot complete series upgrade of machine 0: units have not finished preparing: \[wordpress/1\]`)
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeries(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, unit := range s.units {
+		s.assertMachineStatus(c, state.UpgradeSeriesPrepareStarted)
+		err = unit.SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertUnitStatus(c, unit, state.UpgradeSeriesPrepareCompleted)
+	}
+	s.assertMachineStatus(c, state.UpgradeSeriesPrepareCompleted)
+
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachineStatus(c, state.UpgradeSeriesCompleteStarted)
+	for _, unit := range s.units {
+		s.assertUnitStatus(c, unit, state.UpgradeSeriesCompleteStarted)
+	}
+
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachineStatus(c, state.UpgradeSeriesCompleteStarted)
+
+	err = s.units[1].SetUpgradeSeriesStatus(state.UpgradeSeriesCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.UpgradeSeriesLock()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Series(), gc.Equals, "trusty")
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeriesNoUnits(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Series(), gc.Equals, "trusty")
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesStatusOutOfOrder(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesCompleted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit "wordpress/0": unit is prepare started, not complete started`)
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesCompleteStarted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit "wordpress/0": status "complete started" not valid`)
+
+	// Setting the current status again is not an error.
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitStatus(c, s.units[0], state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w, err := s.units[0].WatchUpgradeSeriesNotifications()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.units[1].SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -38,11 +38,42 @@ func (st *State) checkUserExists(name string) (bool, error) {
 	return count > 0, nil
 }
 
+// UserAccess describes what a user may do through the API.
+type UserAccess string
+
+const (
+	// UserAccessWrite allows a user to make any API call their
+	// other permissions allow.
+	UserAccessWrite UserAccess = "write"
+
+	// UserAccessRead allows a user only to make API calls that do
+	// not change state, such as getters and watchers.
+	UserAccessRead UserAccess = "read"
+)
+
+// Validate returns an error if the access level is not known.
+func (access UserAccess) Validate() error {
+	switch access {
+	case UserAccessWrite, UserAccessRead:
+		return nil
+	}
+	return errors.NotValidf("user access %q", string(access))
+}
+
 // AddUser adds a user to the database.
 func (st *State) AddUser(name, displayName, password, creator string) (*User, error) {
+	return st.AddUserWithAccess(name, displayName, password, creator, UserAccessWrite)
+}
+
+// AddUserWithAccess adds a user with the given access level to the
+// database.
+func (st *State) AddUserWithAccess(name, displayName, password, creator string, access UserAccess) (*User, error) {
 	if !names.IsValidUserName(name) {
 		return nil, errors.Errorf("invalid user name %q", name)
 	}
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	salt, err := utils.RandomSalt()
 	if err != nil {
 		return nil, err
@@ -58,6 +89,7 @@ func (st *State) AddUser(name, displayName, password, creator string) (*User, er
 			PasswordSalt: salt,
 			CreatedBy:    creator,
 			DateCreated:  nowToTheSecond(),
+			Access:       access,
 		},
 	}
 	ops := []txn.Op{{
@@ -167,6 +199,9 @@ type userDoc struct {
 	CreatedBy    string     `bson:"createdby"`
 	DateCreated  time.Time  `bson:"datecreated"`
 	LastLogin    *time.Time `bson:"lastlogin"`
+	// Access is empty for users created before access levels were
+	// introduced; they have write access.
+	Access UserAccess `bson:"access,omitempty"`
 }
 
 // String returns "<name>@local" where <name> is the Name of the user.
@@ -210,6 +245,20 @@ func (u *User) UserTag() names.UserTag {
 	return names.NewLocalUserTag(name)
 }
 
+// Access returns the User's access level.
+func (u *User) Access() UserAccess {
+	if u.doc.Access == "" {
+		return UserAccessWrite
+	}
+	return u.doc.Access
+}
+
+// IsReadOnly returns whether the User may only make API calls that do
+// not change state.
+func (u *User) IsReadOnly() bool {
+	return u.Access() == UserAccessRead
+}
+
 // LastLogin returns when this User last connected through the API in UTC.
 // The resulting time will be nil if the user has never logged in.  In the
 // normal case, the LastLogin is the last time that the user connected through
@@ -71,6 +71,28 @@ func (s *UserSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.LastLogin(), gc.IsNil)
 }
 
+func (s *UserSuite) TestAddUserAccess(c *gc.C) {
+	user, err := s.State.AddUser("bob", "", "password", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessWrite)
+	c.Assert(user.IsReadOnly(), jc.IsFalse)
+
+	user, err = s.State.AddUserWithAccess("auditor", "", "password", "admin", state.UserAccessRead)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.Access(), gc.Equals, state.UserAccessRead)
+	c.Assert(user.IsReadOnly(), jc.IsTrue)
+
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsReadOnly(), jc.IsTrue)
+}
+
+func (s *UserSuite) TestAddUserInvalidAccess(c *gc.C) {
+	_, err := s.State.AddUserWithAccess("bob", "", "password", "admin", "superuser")
+	c.Assert(err, gc.ErrorMatches, `user access "superuser" not valid`)
+	c.Assert(errors.IsNotValid(err), jc.IsTrue)
+}
+
 func (s *UserSuite) TestCheckUserExists(c *gc.C) {
 	user := s.factory.MakeUser(c, nil)
 	exists, err := state.CheckUserExists(s.State, user.Name())
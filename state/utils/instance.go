@@ -6,8 +6,8 @@ package utils
 import (
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
-	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 )
 
@@ -21,7 +21,7 @@ func AvailabilityZone(st *state.State, instID instance.Id) (string, error) {
 	if err != nil {
 		return "", errors.Trace(err)
 	}
-	zenv, ok := env.(common.ZonedEnviron)
+	zenv, ok := env.(environs.ZonedEnviron)
 	if !ok {
 		return "", errors.NotSupportedf(`zones for provider "%T"`, env)
 	}
@@ -43,6 +43,11 @@ type Volume interface {
 	// if it has not already been provisioned. Params returns true if the
 	// returned parameters are usable for provisioning, otherwise false.
 	Params() (VolumeParams, bool)
+
+	// ProvisioningError returns the reason the storage provisioner
+	// last failed to provision the volume, or the empty string if the
+	// volume is not in an error state.
+	ProvisioningError() string
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -86,6 +91,10 @@ type volumeDoc struct {
 	StorageId string        `bson:"storageid,omitempty"`
 	Info      *VolumeInfo   `bson:"info,omitempty"`
 	Params    *VolumeParams `bson:"params,omitempty"`
+
+	// ProvisioningError records why the volume could not be
+	// provisioned, until provisioning is retried.
+	ProvisioningError string `bson:"provisioningerror,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -171,6 +180,11 @@ func (v *volume) Params() (VolumeParams, bool) {
 	return *v.doc.Params, true
 }
 
+// ProvisioningError is required to implement Volume.
+func (v *volume) ProvisioningError() string {
+	return v.doc.ProvisioningError
+}
+
 // Volume is required to implement VolumeAttachment.
 func (v *volumeAttachment) Volume() names.VolumeTag {
 	return names.NewVolumeTag(v.doc.Volume)
@@ -544,6 +558,57 @@ func (st *State) SetVolumeInfo(tag names.VolumeTag, info VolumeInfo) (err error)
 	return st.run(buildTxn)
 }
 
+// SetVolumeProvisioningError records that the specified volume could
+// not be provisioned, and why. The volume will not be provisioned until
+// RetryVolumeProvisioning is called.
+func (st *State) SetVolumeProvisioningError(tag names.VolumeTag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set provisioning error for volume %q", tag.Id())
+	if message == "" {
+		return errors.New("empty error message")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errNotAlive
+		}
+		if _, err := v.Info(); err == nil {
+			return nil, errors.New("volume is already provisioned")
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", false}}}),
+			Update: bson.D{{"$set", bson.D{{"provisioningerror", message}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// RetryVolumeProvisioning clears the provisioning error recorded for
+// the specified volume, so that the storage provisioner will try to
+// provision it again.
+func (st *State) RetryVolumeProvisioning(tag names.VolumeTag) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.ProvisioningError() == "" {
+			return nil, errors.Errorf("volume %q is not in an error state", tag.Id())
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"provisioningerror", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$unset", bson.D{{"provisioningerror", nil}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
 func validateVolumeInfoChange(newInfo, oldInfo VolumeInfo) error {
 	if newInfo.Pool != oldInfo.Pool {
 		return errors.Errorf(
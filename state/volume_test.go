@@ -202,6 +202,53 @@ func (s *VolumeStateSuite) TestSetVolumeInfoImmutable(c *gc.C) {
 	s.assertVolumeInfo(c, volumeTag, volumeInfoSet)
 }
 
+func (s *VolumeStateSuite) TestSetVolumeProvisioningError(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := volume.VolumeTag()
+	c.Assert(volume.ProvisioningError(), gc.Equals, "")
+
+	err = s.State.SetVolumeProvisioningError(volumeTag, "no space left")
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.ProvisioningError(), gc.Equals, "no space left")
+
+	err = s.State.RetryVolumeProvisioning(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.ProvisioningError(), gc.Equals, "")
+	s.assertVolumeUnprovisioned(c, volumeTag)
+
+	err = s.State.RetryVolumeProvisioning(volumeTag)
+	c.Assert(err, gc.ErrorMatches, `volume "0/0" is not in an error state`)
+}
+
+func (s *VolumeStateSuite) TestSetVolumeProvisioningErrorProvisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{Size: 123})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetVolumeProvisioningError(volume.VolumeTag(), "no space left")
+	c.Assert(err, gc.ErrorMatches, `cannot set provisioning error for volume "0/0": volume is already provisioned`)
+	err = s.State.SetVolumeProvisioningError(volume.VolumeTag(), "")
+	c.Assert(err, gc.ErrorMatches, `cannot set provisioning error for volume "0/0": empty error message`)
+}
+
+func (s *VolumeStateSuite) TestRetryVolumeProvisioningNotFound(c *gc.C) {
+	err := s.State.RetryVolumeProvisioning(names.NewVolumeTag("42"))
+	c.Assert(err, gc.ErrorMatches, `volume "42" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *VolumeStateSuite) TestWatchVolumeAttachment(c *gc.C) {
 	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
 	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
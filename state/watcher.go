@@ -94,11 +94,100 @@ func (w *commonWatcher) Err() error {
 	return w.tomb.Err()
 }
 
-// collect combines the effects of the one change, and any further changes read
-// from more in the next 10ms. The result map describes the existence, or not,
-// of every id observed to have changed. If a value is read from the supplied
-// stop chan, collect returns false immediately.
-func collect(one watcher.Change, more <-chan watcher.Change, stop <-chan struct{}) (map[interface{}]bool, bool) {
+// WatcherCoalescing holds how watchers coalesce a burst of changes into
+// a single event.
+type WatcherCoalescing struct {
+	// Window holds how long a watcher waits after a change for further
+	// changes to be made, before reporting them together. The wait
+	// begins again with each further change.
+	Window time.Duration
+
+	// MaxDelay holds the longest a watcher delays reporting a change
+	// while further changes keep arriving within Window. It must not be
+	// less than Window.
+	MaxDelay time.Duration
+}
+
+// DefaultWatcherCoalescing holds the coalescing of watchers of a State
+// whose coalescing has not been set. It reports changes 10ms after the
+// first one, however many more are made in the meantime.
+var DefaultWatcherCoalescing = WatcherCoalescing{
+	Window:   10 * time.Millisecond,
+	MaxDelay: 10 * time.Millisecond,
+}
+
+// Validate returns an error if the coalescing is not valid.
+func (c WatcherCoalescing) Validate() error {
+	if c.Window <= 0 {
+		return errors.NotValidf("coalescing window %v", c.Window)
+	}
+	if c.MaxDelay < c.Window {
+		return errors.NotValidf("coalescing max delay %v shorter than window %v", c.MaxDelay, c.Window)
+	}
+	return nil
+}
+
+// SetWatcherCoalescing sets the coalescing of the watchers
+// subsequently started by st. Raising Window above the default makes
+// watchers wait for a burst of changes, such as rapid settings writes,
+// to quiesce before reporting them, at the cost of reporting each
+// change later.
+func (st *State) SetWatcherCoalescing(c WatcherCoalescing) error {
+	if err := c.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.coalescing = c
+	return nil
+}
+
+func (st *State) watcherCoalescing() WatcherCoalescing {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.coalescing
+}
+
+// coalescer decides when a watcher reports a burst of changes.
+type coalescer struct {
+	config WatcherCoalescing
+
+	// deadline holds the time by which the changes recorded since
+	// done was last called must be reported.
+	deadline time.Time
+}
+
+// newCoalescer returns a coalescer configured for the watcher's State.
+func (w *commonWatcher) newCoalescer() *coalescer {
+	return &coalescer{config: w.st.watcherCoalescing()}
+}
+
+// changed records a change, and returns a channel that receives a value
+// when the changes recorded since done was last called are to be
+// reported. The channels returned by earlier calls should be discarded.
+func (c *coalescer) changed() <-chan time.Time {
+	now := time.Now()
+	if c.deadline.IsZero() {
+		c.deadline = now.Add(c.config.MaxDelay)
+	}
+	wait := c.config.Window
+	if remaining := c.deadline.Sub(now); remaining < wait {
+		wait = remaining
+	}
+	return time.After(wait)
+}
+
+// done records that the changes have been reported.
+func (c *coalescer) done() {
+	c.deadline = time.Time{}
+}
+
+// collect combines the effects of the one change, and any further changes
+// read from more until they are coalesced, as configured for the watcher's
+// State. The result map describes the existence, or not, of every id
+// observed to have changed. If the watcher is killed, collect returns false
+// immediately.
+func (w *commonWatcher) collect(one watcher.Change, more <-chan watcher.Change) (map[interface{}]bool, bool) {
 	var count int
 	result := map[interface{}]bool{}
 	handle := func(ch watcher.Change) {
@@ -106,14 +195,16 @@ func collect(one watcher.Change, more <-chan watcher.Change, stop <-chan struct{
 		result[ch.Id] = ch.Revno != -1
 	}
 	handle(one)
-	timeout := time.After(10 * time.Millisecond)
+	c := w.newCoalescer()
+	ready := c.changed()
 	for done := false; !done; {
 		select {
-		case <-stop:
+		case <-w.tomb.Dying():
 			return nil, false
 		case another := <-more:
 			handle(another)
-		case <-timeout:
+			ready = c.changed()
+		case <-ready:
 			done = true
 		}
 	}
@@ -533,7 +624,7 @@ func (w *lifecycleWatcher) loop() error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			updates, ok := collect(ch, in, w.tomb.Dying())
+			updates, ok := w.collect(ch, in)
 			if !ok {
 				return tomb.ErrDying
 			}
@@ -832,7 +923,7 @@ func (w *RelationScopeWatcher) loop() error {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
 		case ch := <-in:
-			latest, ok := collect(ch, in, w.tomb.Dying())
+			latest, ok := w.collect(ch, in)
 			if !ok {
 				return tomb.ErrDying
 			}
@@ -974,7 +1065,11 @@ func (w *relationUnitsWatcher) loop() (err error) {
 		sentInitial bool
 		changes     multiwatcher.RelationUnitsChange
 		out         chan<- multiwatcher.RelationUnitsChange
+		ready       <-chan time.Time
 	)
+	// Settings changes are reported once a burst of writes has been
+	// coalesced; changes of scope are reported immediately.
+	coalesce := w.newCoalescer()
 	for {
 		select {
 		case <-w.st.watcher.Dead():
@@ -999,11 +1094,19 @@ func (w *relationUnitsWatcher) loop() (err error) {
 				logger.Warningf("ignoring bad relation scope id: %#v", c.Id)
 			}
 			setRelationUnitChangeVersion(&changes, id, c.Revno)
-			out = w.out
+			ready = coalesce.changed()
+		case <-ready:
+			ready = nil
+			coalesce.done()
+			if !emptyRelationUnitsChanges(&changes) {
+				out = w.out
+			}
 		case out <- changes:
 			sentInitial = true
 			changes = multiwatcher.RelationUnitsChange{}
 			out = nil
+			ready = nil
+			coalesce.done()
 		}
 	}
 }
@@ -1337,6 +1440,10 @@ func (w *settingsWatcher) loop(key string) (err error) {
 	if revno == -1 {
 		out = nil
 	}
+	// The settings are read once a burst of writes has been
+	// coalesced, rather than after each of them.
+	c := w.newCoalescer()
+	var ready <-chan time.Time
 	for {
 		select {
 		case <-w.st.watcher.Dead():
@@ -1344,6 +1451,10 @@ func (w *settingsWatcher) loop(key string) (err error) {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
 		case <-ch:
+			ready = c.changed()
+		case <-ready:
+			ready = nil
+			c.done()
 			settings, err = readSettings(w.st, key)
 			if err != nil {
 				return err
@@ -1412,6 +1523,21 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
+// WatchForUpgradeChanges returns a NotifyWatcher that notifies when
+// the environment config or the plan of an upgrade with canaries
+// changes; either may change the version an agent should run.
+func (st *State) WatchForUpgradeChanges() NotifyWatcher {
+	return newDocWatcher(st, []docKey{
+		{
+			settingsC,
+			st.docID(environGlobalKey),
+		}, {
+			upgradePlansC,
+			st.docID(currentUpgradePlanId),
+		},
+	})
+}
+
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
 // when the set of API addresses changes.
 func (st *State) WatchAPIHostPorts() NotifyWatcher {
@@ -1532,7 +1658,7 @@ func (w *docWatcher) loop(docKeys []docKey) error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+			if _, ok := w.collect(ch, in); !ok {
 				return tomb.ErrDying
 			}
 			out = w.out
@@ -1771,22 +1897,25 @@ func (w *machineAddressesWatcher) loop() error {
 	}
 }
 
-// cleanupWatcher notifies of changes in the cleanups collection.
-type cleanupWatcher struct {
+// collectionWatcher notifies of any change to the documents of a
+// collection in the environment.
+type collectionWatcher struct {
 	commonWatcher
-	out chan struct{}
+	collName string
+	out      chan struct{}
 }
 
-var _ Watcher = (*cleanupWatcher)(nil)
+var _ Watcher = (*collectionWatcher)(nil)
 
 // WatchCleanups starts and returns a CleanupWatcher.
 func (st *State) WatchCleanups() NotifyWatcher {
-	return newCleanupWatcher(st)
+	return newCollectionWatcher(st, cleanupsC)
 }
 
-func newCleanupWatcher(st *State) NotifyWatcher {
-	w := &cleanupWatcher{
+func newCollectionWatcher(st *State, collName string) NotifyWatcher {
+	w := &collectionWatcher{
 		commonWatcher: commonWatcher{st: st},
+		collName:      collName,
 		out:           make(chan struct{}),
 	}
 	go func() {
@@ -1798,14 +1927,14 @@ func newCleanupWatcher(st *State) NotifyWatcher {
 }
 
 // Changes returns the event channel for w.
-func (w *cleanupWatcher) Changes() <-chan struct{} {
+func (w *collectionWatcher) Changes() <-chan struct{} {
 	return w.out
 }
 
-func (w *cleanupWatcher) loop() (err error) {
+func (w *collectionWatcher) loop() (err error) {
 	in := make(chan watcher.Change)
-	w.st.watcher.WatchCollectionWithFilter(cleanupsC, in, w.st.isForStateEnv)
-	defer w.st.watcher.UnwatchCollection(cleanupsC, in)
+	w.st.watcher.WatchCollectionWithFilter(w.collName, in, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(w.collName, in)
 
 	out := w.out
 	for {
@@ -1815,7 +1944,7 @@ func (w *cleanupWatcher) loop() (err error) {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+			if _, ok := w.collect(ch, in); !ok {
 				return tomb.ErrDying
 			}
 			out = w.out
@@ -1893,7 +2022,7 @@ func (w *actionStatusWatcher) loop() error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			updates, ok := collect(ch, in, w.tomb.Dying())
+			updates, ok := w.collect(ch, in)
 			if !ok {
 				return tomb.ErrDying
 			}
@@ -2085,7 +2214,7 @@ func (w *idPrefixWatcher) loop() error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			updates, ok := collect(ch, in, w.tomb.Dying())
+			updates, ok := w.collect(ch, in)
 			if !ok {
 				return tomb.ErrDying
 			}
@@ -2348,7 +2477,7 @@ func (w *machineInterfacesWatcher) loop() error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			updates, ok := collect(ch, in, w.tomb.Dying())
+			updates, ok := w.collect(ch, in)
 			if !ok {
 				return tomb.ErrDying
 			}
@@ -2565,7 +2694,7 @@ func (w *rebootWatcher) loop() error {
 		case <-w.st.watcher.Dead():
 			return stateWatcherDeadError(w.st.watcher.Err())
 		case ch := <-in:
-			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+			if _, ok := w.collect(ch, in); !ok {
 				return tomb.ErrDying
 			}
 			out = w.out
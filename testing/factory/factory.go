@@ -45,6 +45,7 @@ type UserParams struct {
 	Creator     names.Tag
 	NoEnvUser   bool
 	Disabled    bool
+	Access      state.UserAccess
 }
 
 // EnvUserParams defines the parameters for creating an environment user.
@@ -52,6 +53,7 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	Access      state.UserAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -152,9 +154,12 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 		c.Assert(err, jc.ErrorIsNil)
 		params.Creator = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.UserAccessWrite
+	}
 	creatorUserTag := params.Creator.(names.UserTag)
-	user, err := factory.st.AddUser(
-		params.Name, params.DisplayName, params.Password, creatorUserTag.Name())
+	user, err := factory.st.AddUserWithAccess(
+		params.Name, params.DisplayName, params.Password, creatorUserTag.Name(), params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	if !params.NoEnvUser {
 		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()), params.DisplayName)
@@ -187,8 +192,12 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		c.Assert(err, jc.ErrorIsNil)
 		params.CreatedBy = env.Owner()
 	}
+	if params.Access == "" {
+		params.Access = state.UserAccessWrite
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.DisplayName)
+	envUser, err := factory.st.AddEnvironmentUserWithAccess(
+		names.NewUserTag(params.User), createdByUserTag, params.DisplayName, params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
@@ -258,7 +258,7 @@ func (s *migrateAgentEnvUUIDSuite) removeEnvUUIDFromAgentConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Write the yaml back out remembering to add the format prefix.
-	data = append([]byte("# format 1.18\n"), data...)
+	data = append([]byte("# format 1.24\n"), data...)
 	c.Logf("Data out:\n\n%s\n", data)
 	err = ioutil.WriteFile(filename, data, 0644)
 	c.Assert(err, jc.ErrorIsNil)
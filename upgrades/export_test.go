@@ -42,4 +42,9 @@ var (
 	AddEnvironmentUUIDToAgentConfig = addEnvironmentUUIDToAgentConfig
 	AddDefaultStoragePools          = addDefaultStoragePools
 	MoveBlocksFromEnvironToState    = moveBlocksFromEnvironToState
+
+	// 124 upgrade functions
+	RsyslogConfDir      = &rsyslogConfDir
+	RestartRsyslog      = &restartRsyslog
+	RemoveRsyslogConfig = removeRsyslogConfig
 )
@@ -31,6 +31,10 @@ var stateUpgradeOperations = func() []Operation {
 			version.MustParse("1.23.0"),
 			stateStepsFor123(),
 		},
+		upgradeToVersion{
+			version.MustParse("1.24.0"),
+			stateStepsFor124(),
+		},
 	}
 	return steps
 }
@@ -52,6 +56,10 @@ var upgradeOperations = func() []Operation {
 			version.MustParse("1.23.0"),
 			stepsFor123(),
 		},
+		upgradeToVersion{
+			version.MustParse("1.24.0"),
+			stepsFor124(),
+		},
 	}
 	return steps
 }
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/utils/syslog"
+)
+
+// These are patched out during tests.
+var (
+	rsyslogConfDir = "/etc/rsyslog.d"
+	restartRsyslog = syslog.Restart
+)
+
+// removeRsyslogConfig removes the rsyslog configuration that earlier
+// versions wrote to forward the agents' logs to the state servers, which
+// now receive them over the API, and restarts rsyslog so that it stops
+// using it.
+func removeRsyslogConfig(context Context) error {
+	paths, err := filepath.Glob(filepath.Join(rsyslogConfDir, "*juju*"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	for _, path := range paths {
+		logger.Debugf("removing rsyslog config %q", path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Annotate(err, "cannot remove rsyslog config")
+		}
+	}
+	return restartRsyslog()
+}
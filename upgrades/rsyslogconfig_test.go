@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type rsyslogConfigSuite struct {
+	testing.BaseSuite
+	confDir   string
+	restarted int
+}
+
+var _ = gc.Suite(&rsyslogConfigSuite{})
+
+func (s *rsyslogConfigSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.confDir = c.MkDir()
+	s.restarted = 0
+	s.PatchValue(upgrades.RsyslogConfDir, s.confDir)
+	s.PatchValue(upgrades.RestartRsyslog, func() error {
+		s.restarted++
+		return nil
+	})
+}
+
+func (s *rsyslogConfigSuite) writeConf(c *gc.C, name string) string {
+	path := filepath.Join(s.confDir, name)
+	err := ioutil.WriteFile(path, []byte("# config"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *rsyslogConfigSuite) TestRemovesJujuConfig(c *gc.C) {
+	forwarding := s.writeConf(c, "25-juju.conf")
+	accepting := s.writeConf(c, "25-juju-local.conf")
+	other := s.writeConf(c, "50-default.conf")
+
+	err := upgrades.RemoveRsyslogConfig(&mockContext{})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, path := range []string{forwarding, accepting} {
+		_, err := os.Stat(path)
+		c.Check(err, jc.Satisfies, os.IsNotExist)
+	}
+	_, err = os.Stat(other)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.restarted, gc.Equals, 1)
+}
+
+func (s *rsyslogConfigSuite) TestIdempotent(c *gc.C) {
+	s.writeConf(c, "25-juju.conf")
+	err := upgrades.RemoveRsyslogConfig(&mockContext{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Without any juju config left, rsyslog is not restarted again.
+	err = upgrades.RemoveRsyslogConfig(&mockContext{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.restarted, gc.Equals, 1)
+}
@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/juju/state"
+)
+
+// stepsFor124 returns upgrade steps for Juju 1.24 that only need the API.
+func stepsFor124() []Step {
+	return []Step{
+		&upgradeStep{
+			description: "remove rsyslog configuration",
+			targets:     []Target{AllMachines},
+			run:         removeRsyslogConfig,
+		},
+	}
+}
+
+// stateStepsFor124 returns upgrade steps for Juju 1.24 that manipulate state directly.
+func stateStepsFor124() []Step {
+	return []Step{
+		&upgradeStep{
+			description: "add tags to annotations",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return state.AddTagsToAnnotations(context.State())
+			},
+		},
+	}
+}
@@ -26,6 +26,16 @@ type Step interface {
 	Run(Context) error
 }
 
+// ReversibleStep defines an upgrade step that can undo its changes, so
+// that an agent can be rolled back to the version it upgraded from.
+type ReversibleStep interface {
+	Step
+
+	// Revert undoes the changes made by Run. Like Run, it must be
+	// idempotent.
+	Revert(Context) error
+}
+
 // Operation defines what steps to perform to upgrade to a target version.
 type Operation interface {
 	// The Juju version for which this operation is applicable.
@@ -110,6 +120,84 @@ func PerformUpgrade(from version.Number, targets []Target, context Context) erro
 	return nil
 }
 
+// AreDowngradesDefined returns true if there are reversible upgrade
+// steps to revert when rolling back from the version supplied to the
+// running software version.
+func AreDowngradesDefined(from version.Number) bool {
+	for _, ops := range [][]Operation{upgradeOperations(), stateUpgradeOperations()} {
+		for _, op := range downgradeOps(from, version.Current.Number, ops) {
+			for _, step := range op.Steps() {
+				if _, ok := step.(ReversibleStep); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// PerformDowngrade reverts the reversible upgrade steps that were run
+// to upgrade this version of Juju to the "from" version on the "target"
+// type of machine. The steps are reverted in the opposite order to
+// that in which they were run; steps that are not reversible are
+// skipped.
+func PerformDowngrade(from version.Number, targets []Target, context Context) error {
+	to := version.Current.Number
+	ops := downgradeOps(from, to, upgradeOperations())
+	if err := revertUpgradeSteps(ops, targets, context.APIContext()); err != nil {
+		return err
+	}
+
+	if hasStateTarget(targets) {
+		ops := downgradeOps(from, to, stateUpgradeOperations())
+		if err := revertUpgradeSteps(ops, targets, context.StateContext()); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("All downgrade steps completed successfully")
+	return nil
+}
+
+// downgradeOps returns, most recent first, the operations that were
+// run when upgrading from the "to" version to the "from" version.
+func downgradeOps(from, to version.Number, allOps []Operation) []Operation {
+	var ops []Operation
+	for i := len(allOps) - 1; i >= 0; i-- {
+		targetVersion := allOps[i].TargetVersion()
+		if targetVersion.Compare(to) > 0 && targetVersion.Compare(from) <= 0 {
+			ops = append(ops, allOps[i])
+		}
+	}
+	return ops
+}
+
+// revertUpgradeSteps reverts the reversible upgrade steps of the given
+// operations that are relevant to the targets given.
+//
+// As with runUpgradeSteps, the downgrade is aborted as soon as any
+// error is encountered.
+func revertUpgradeSteps(ops []Operation, targets []Target, context Context) error {
+	for _, op := range ops {
+		steps := op.Steps()
+		for i := len(steps) - 1; i >= 0; i-- {
+			step, ok := steps[i].(ReversibleStep)
+			if !ok || !targetsMatch(targets, step.Targets()) {
+				continue
+			}
+			logger.Infof("reverting upgrade step: %v", step.Description())
+			if err := step.Revert(context); err != nil {
+				logger.Errorf("reverting upgrade step %q failed: %v", step.Description(), err)
+				return &upgradeError{
+					description: step.Description(),
+					err:         err,
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func hasStateTarget(targets []Target) bool {
 	for _, target := range targets {
 		if target == StateServer || target == DatabaseMaster {
@@ -558,6 +558,141 @@ func (s *upgradeSuite) TestPerformUpgrade(c *gc.C) {
 	}
 }
 
+type mockReversibleStep struct {
+	mockUpgradeStep
+}
+
+func (u *mockReversibleStep) Revert(ctx upgrades.Context) error {
+	if strings.HasSuffix(u.msg, "error") {
+		return errors.New("revert error occurred")
+	}
+	context := ctx.(*mockContext)
+	context.messages = append(context.messages, "revert "+u.msg)
+	return nil
+}
+
+func newReversibleStep(msg string, targets ...upgrades.Target) *mockReversibleStep {
+	return &mockReversibleStep{*newUpgradeStep(msg, targets...)}
+}
+
+func (s *upgradeSuite) patchDowngradeOperations() {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps: []upgrades.Step{
+					newReversibleStep("state step 1 - 1.22.0", upgrades.DatabaseMaster),
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newReversibleStep("step 1 - 1.21.0", upgrades.AllMachines),
+					newUpgradeStep("step 2 - 1.21.0", upgrades.AllMachines),
+				},
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps: []upgrades.Step{
+					newReversibleStep("step 1 - 1.22.0", upgrades.HostMachine),
+					newReversibleStep("step 2 - 1.22.0", upgrades.HostMachine),
+					newReversibleStep("step 3 - 1.22.0", upgrades.StateServer),
+				},
+			},
+		}
+	})
+}
+
+var downgradeTests = []struct {
+	about         string
+	fromVersion   string
+	toVersion     string
+	targets       []upgrades.Target
+	expectedSteps []string
+}{{
+	about:         "steps are reverted most recent first",
+	fromVersion:   "1.22.0",
+	toVersion:     "1.20.0",
+	targets:       targets(upgrades.HostMachine),
+	expectedSteps: []string{"revert step 2 - 1.22.0", "revert step 1 - 1.22.0", "revert step 1 - 1.21.0"},
+}, {
+	about:         "only steps run since the version rolled back to are reverted",
+	fromVersion:   "1.22.0",
+	toVersion:     "1.21.0",
+	targets:       targets(upgrades.HostMachine),
+	expectedSteps: []string{"revert step 2 - 1.22.0", "revert step 1 - 1.22.0"},
+}, {
+	about:         "state steps are reverted after API steps",
+	fromVersion:   "1.22.0",
+	toVersion:     "1.20.0",
+	targets:       targets(upgrades.DatabaseMaster),
+	expectedSteps: []string{"revert step 1 - 1.21.0", "revert state step 1 - 1.22.0"},
+}, {
+	about:       "nothing to revert within a release",
+	fromVersion: "1.22.1",
+	toVersion:   "1.22.0",
+	targets:     targets(upgrades.HostMachine),
+}}
+
+func (s *upgradeSuite) TestPerformDowngrade(c *gc.C) {
+	s.patchDowngradeOperations()
+	for i, test := range downgradeTests {
+		c.Logf("%d: %s", i, test.about)
+		ctx := &mockContext{}
+		vers := version.Current
+		vers.Number = version.MustParse(test.toVersion)
+		s.PatchValue(&version.Current, vers)
+		err := upgrades.PerformDowngrade(version.MustParse(test.fromVersion), test.targets, ctx)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ctx.messages, jc.DeepEquals, test.expectedSteps)
+	}
+}
+
+func (s *upgradeSuite) TestPerformDowngradeStopsAtError(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation { return nil })
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.22.0"),
+				steps: []upgrades.Step{
+					newReversibleStep("step 1 - 1.22.0", upgrades.AllMachines),
+					newReversibleStep("step 2 error", upgrades.AllMachines),
+				},
+			},
+		}
+	})
+	vers := version.Current
+	vers.Number = version.MustParse("1.21.0")
+	s.PatchValue(&version.Current, vers)
+	ctx := &mockContext{}
+	err := upgrades.PerformDowngrade(version.MustParse("1.22.0"), targets(upgrades.HostMachine), ctx)
+	c.Assert(err, gc.ErrorMatches, "step 2 error: revert error occurred")
+	c.Assert(ctx.messages, gc.HasLen, 0)
+}
+
+func (s *upgradeSuite) TestAreDowngradesDefined(c *gc.C) {
+	s.patchDowngradeOperations()
+	for i, test := range []struct {
+		from, to string
+		expected bool
+	}{
+		{"1.22.0", "1.21.0", true},
+		{"1.21.0", "1.20.0", true},
+		{"1.22.1", "1.22.0", false},
+		{"1.22.0", "1.22.0", false},
+	} {
+		c.Logf("%d: %s -> %s", i, test.from, test.to)
+		vers := version.Current
+		vers.Number = version.MustParse(test.to)
+		s.PatchValue(&version.Current, vers)
+		c.Check(upgrades.AreDowngradesDefined(version.MustParse(test.from)), gc.Equals, test.expected)
+	}
+}
+
 type contextStep struct {
 	useAPI bool
 }
@@ -666,12 +801,12 @@ func (s *upgradeSuite) TestUpgradeOperationsOrdered(c *gc.C) {
 
 func (s *upgradeSuite) TestStateUpgradeOperationsVersions(c *gc.C) {
 	versions := extractUpgradeVersions(c, (*upgrades.StateUpgradeOperations)())
-	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.21.0", "1.22.0", "1.23.0"})
+	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.21.0", "1.22.0", "1.23.0", "1.24.0"})
 }
 
 func (s *upgradeSuite) TestUpgradeOperationsVersions(c *gc.C) {
 	versions := extractUpgradeVersions(c, (*upgrades.UpgradeOperations)())
-	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.22.0", "1.23.0"})
+	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.22.0", "1.23.0", "1.24.0"})
 }
 
 func extractUpgradeVersions(c *gc.C, ops []upgrades.Operation) []string {
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+// Package du reports the disk space available on a machine's
+// filesystems.
+package du
+
+import (
+	"syscall"
+)
+
+// AvailableSpace returns the number of bytes available to unprivileged
+// users on the filesystem containing path.
+func AvailableSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package du_test
+
+import (
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/du"
+)
+
+type duSuite struct{}
+
+var _ = gc.Suite(&duSuite{})
+
+func (*duSuite) TestAvailableSpace(c *gc.C) {
+	available, err := du.AvailableSpace(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(available > 0, jc.IsTrue)
+}
+
+func (*duSuite) TestAvailableSpaceMissingPath(c *gc.C) {
+	_, err := du.AvailableSpace(filepath.Join(c.MkDir(), "missing"))
+	c.Assert(err, gc.NotNil)
+}
@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package du reports the disk space available on a machine's
+// filesystems.
+package du
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// AvailableSpace returns the number of bytes available to the current
+// user on the volume containing path.
+func AvailableSpace(path string) (uint64, error) {
+	pathp, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available uint64
+	ret, _, err := procGetDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(pathp)),
+		uintptr(unsafe.Pointer(&available)),
+		0, 0,
+	)
+	if ret == 0 {
+		return 0, err
+	}
+	return available, nil
+}
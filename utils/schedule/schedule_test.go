@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package schedule_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/schedule"
+)
+
+type scheduleSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+// start is a Friday.
+var start = time.Date(2015, time.May, 1, 10, 30, 20, 0, time.UTC)
+
+var nextTests = []struct {
+	spec string
+	next time.Time
+}{{
+	spec: "* * * * *",
+	next: time.Date(2015, time.May, 1, 10, 31, 0, 0, time.UTC),
+}, {
+	spec: "@hourly",
+	next: time.Date(2015, time.May, 1, 11, 0, 0, 0, time.UTC),
+}, {
+	spec: "@daily",
+	next: time.Date(2015, time.May, 2, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "@weekly",
+	next: time.Date(2015, time.May, 3, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "@monthly",
+	next: time.Date(2015, time.June, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "*/20 * * * *",
+	next: time.Date(2015, time.May, 1, 10, 40, 0, 0, time.UTC),
+}, {
+	spec: "15 2,14 * * *",
+	next: time.Date(2015, time.May, 1, 14, 15, 0, 0, time.UTC),
+}, {
+	spec: "0 3 * * 1-5",
+	next: time.Date(2015, time.May, 4, 3, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 * * 7",
+	next: time.Date(2015, time.May, 3, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 1 1 *",
+	next: time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+}, {
+	// Both day fields restricted: either matches.
+	spec: "0 0 15 * 6",
+	next: time.Date(2015, time.May, 2, 0, 0, 0, 0, time.UTC),
+}, {
+	spec: "0 0 30 2 *",
+	next: time.Time{},
+}}
+
+func (s *scheduleSuite) TestNext(c *gc.C) {
+	for i, test := range nextTests {
+		c.Logf("test %d: %q", i, test.spec)
+		sched, err := schedule.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(sched.Next(start), gc.Equals, test.next)
+	}
+}
+
+var parseErrorTests = []struct {
+	spec string
+	err  string
+}{{
+	spec: "",
+	err:  `invalid schedule "": expected 5 fields, got 0`,
+}, {
+	spec: "* * * *",
+	err:  `invalid schedule "\* \* \* \*": expected 5 fields, got 4`,
+}, {
+	spec: "60 * * * *",
+	err:  `invalid schedule "60 \* \* \* \*": invalid minute "60": expected 0 to 59`,
+}, {
+	spec: "* * 0 * *",
+	err:  `invalid schedule "\* \* 0 \* \*": invalid day of month "0": expected 1 to 31`,
+}, {
+	spec: "*/0 * * * *",
+	err:  `invalid schedule "\*/0 \* \* \* \*": invalid step in minute "\*/0"`,
+}, {
+	spec: "* 5-2 * * *",
+	err:  `invalid schedule "\* 5-2 \* \* \*": invalid range in hour "5-2"`,
+}, {
+	spec: "@yearly",
+	err:  `invalid schedule "@yearly": expected 5 fields, got 1`,
+}}
+
+func (s *scheduleSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := schedule.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
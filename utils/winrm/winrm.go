@@ -0,0 +1,398 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package winrm contains a minimal client for the Windows Remote
+// Management (WS-Management) protocol, sufficient to run commands and
+// PowerShell scripts on Windows hosts. All WinRM-based command
+// executions in Juju should use the Client in this package.
+package winrm
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf16"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+)
+
+var logger = loggo.GetLogger("juju.utils.winrm")
+
+const (
+	// DefaultPort is the port of the HTTP WinRM listener.
+	DefaultPort = 5985
+
+	// DefaultSecurePort is the port of the HTTPS WinRM listener.
+	DefaultSecurePort = 5986
+)
+
+const (
+	actionCreate  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Create"
+	actionDelete  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Delete"
+	actionCommand = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Command"
+	actionReceive = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Receive"
+	actionSignal  = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Signal"
+
+	commandStateDone = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done"
+	signalTerminate  = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/signal/terminate"
+
+	// timedOutCode is the WS-Management fault code reported when a
+	// Receive request times out before the command produces output.
+	timedOutCode = "2150858793"
+)
+
+// scriptChunkSize is the number of base64 characters of a script that
+// are uploaded in each command, so that command lines stay well within
+// the limits of the Windows command interpreter.
+const scriptChunkSize = 2000
+
+// ClientParams holds the parameters of a WinRM client.
+type ClientParams struct {
+	// Host is the name or address of the Windows host.
+	Host string
+
+	// Port is the port of the WinRM listener. If zero, DefaultPort or
+	// DefaultSecurePort is used, according to Secure.
+	Port int
+
+	// User and Password are used for basic authentication.
+	User     string
+	Password string
+
+	// Secure specifies whether the HTTPS listener is used.
+	Secure bool
+
+	// Insecure specifies whether the certificate of the HTTPS listener
+	// is accepted without verification. WinRM listeners commonly use
+	// self-signed certificates.
+	Insecure bool
+}
+
+// Client runs commands on a Windows host over WinRM.
+type Client struct {
+	params   ClientParams
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a client that runs commands on the host described
+// by the given parameters.
+func NewClient(params ClientParams) *Client {
+	scheme, port := "http", DefaultPort
+	if params.Secure {
+		scheme, port = "https", DefaultSecurePort
+	}
+	if params.Port != 0 {
+		port = params.Port
+	}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: params.Insecure},
+	}
+	return &Client{
+		params:   params,
+		endpoint: fmt.Sprintf("%s://%s/wsman", scheme, net.JoinHostPort(params.Host, strconv.Itoa(port))),
+		http:     &http.Client{Transport: transport},
+	}
+}
+
+// Run runs the given command line on the host in a new shell, copying
+// its output to stdout and stderr. An error is returned if the command
+// exits with a non-zero status.
+func (c *Client) Run(command string, stdout, stderr io.Writer) error {
+	shellId, err := c.createShell()
+	if err != nil {
+		return errors.Annotate(err, "cannot create shell")
+	}
+	defer func() {
+		if deleteErr := c.deleteShell(shellId); deleteErr != nil {
+			logger.Warningf("cannot delete shell %s: %v", shellId, deleteErr)
+		}
+	}()
+	commandId, err := c.startCommand(shellId, command)
+	if err != nil {
+		return errors.Annotate(err, "cannot start command")
+	}
+	exitCode, err := c.receiveOutput(shellId, commandId, stdout, stderr)
+	if err != nil {
+		if signalErr := c.terminateCommand(shellId, commandId); signalErr != nil {
+			logger.Warningf("cannot terminate command %s: %v", commandId, signalErr)
+		}
+		return errors.Annotate(err, "cannot receive command output")
+	}
+	if exitCode != 0 {
+		return errors.Errorf("command failed with exit code %d", exitCode)
+	}
+	return nil
+}
+
+// RunPowerShell runs the given PowerShell script on the host, copying
+// its output to stdout and stderr. Short scripts are passed on the
+// command line; longer ones are first uploaded to a temporary file.
+func (c *Client) RunPowerShell(script string, stdout, stderr io.Writer) error {
+	command := PowerShellCommand(script)
+	if len(command) <= scriptChunkSize {
+		return c.Run(command, stdout, stderr)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	path := fmt.Sprintf(`$env:TEMP\juju-%s`, uuid)
+	encoded := base64.StdEncoding.EncodeToString([]byte(script))
+	for len(encoded) > 0 {
+		n := scriptChunkSize
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		chunk := fmt.Sprintf(`Add-Content -Path "%s.b64" -Value '%s'`, path, encoded[:n])
+		if err := c.Run(PowerShellCommand(chunk), ioutil.Discard, stderr); err != nil {
+			return errors.Annotate(err, "cannot upload script")
+		}
+		encoded = encoded[n:]
+	}
+	run := fmt.Sprintf(`$ErrorActionPreference = "Stop"
+$encoded = (Get-Content -Path "%[1]s.b64") -join ""
+[IO.File]::WriteAllBytes("%[1]s.ps1", [Convert]::FromBase64String($encoded))
+Remove-Item -Path "%[1]s.b64"
+try {
+    & "%[1]s.ps1"
+    if ($LASTEXITCODE) {
+        exit $LASTEXITCODE
+    }
+} finally {
+    Remove-Item -Path "%[1]s.ps1"
+}`, path)
+	return c.Run(PowerShellCommand(run), stdout, stderr)
+}
+
+// PowerShellCommand returns the command line that runs the given
+// PowerShell script.
+func PowerShellCommand(script string) string {
+	runes := utf16.Encode([]rune(script))
+	encoded := make([]byte, 2*len(runes))
+	for i, r := range runes {
+		encoded[2*i] = byte(r)
+		encoded[2*i+1] = byte(r >> 8)
+	}
+	return "powershell.exe -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand " +
+		base64.StdEncoding.EncodeToString(encoded)
+}
+
+func (c *Client) createShell() (string, error) {
+	var response struct {
+		ShellId string `xml:"Body>Shell>ShellId"`
+	}
+	err := c.call(actionCreate, "", shellOptions, createShellBody, nil, &response)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return response.ShellId, nil
+}
+
+func (c *Client) deleteShell(shellId string) error {
+	return c.call(actionDelete, shellId, nil, "", nil, nil)
+}
+
+func (c *Client) startCommand(shellId, command string) (string, error) {
+	var body bytes.Buffer
+	body.WriteString("<rsp:CommandLine><rsp:Command>")
+	if err := xml.EscapeText(&body, []byte(command)); err != nil {
+		return "", errors.Trace(err)
+	}
+	body.WriteString("</rsp:Command></rsp:CommandLine>")
+	var response struct {
+		CommandId string `xml:"Body>CommandResponse>CommandId"`
+	}
+	err := c.call(actionCommand, shellId, commandOptions, body.String(), nil, &response)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return response.CommandId, nil
+}
+
+func (c *Client) terminateCommand(shellId, commandId string) error {
+	body := fmt.Sprintf(`<rsp:Signal CommandId="%s"><rsp:Code>%s</rsp:Code></rsp:Signal>`,
+		commandId, signalTerminate)
+	return c.call(actionSignal, shellId, nil, body, nil, nil)
+}
+
+// receiveResponse holds the parts of a Receive response that are used.
+type receiveResponse struct {
+	Streams []struct {
+		Name    string `xml:"Name,attr"`
+		Content string `xml:",chardata"`
+	} `xml:"Body>ReceiveResponse>Stream"`
+	CommandState struct {
+		State    string `xml:"State,attr"`
+		ExitCode int    `xml:"ExitCode"`
+	} `xml:"Body>ReceiveResponse>CommandState"`
+}
+
+// receiveOutput copies the output of the command until it is done, and
+// returns its exit code.
+func (c *Client) receiveOutput(shellId, commandId string, stdout, stderr io.Writer) (int, error) {
+	body := fmt.Sprintf(`<rsp:Receive><rsp:DesiredStream CommandId="%s">stdout stderr</rsp:DesiredStream></rsp:Receive>`, commandId)
+	for {
+		var response receiveResponse
+		var fault soapFault
+		err := c.call(actionReceive, shellId, nil, body, &fault, &response)
+		if err != nil {
+			if fault.Code() == timedOutCode {
+				// The command has not produced any output yet.
+				continue
+			}
+			return 0, errors.Trace(err)
+		}
+		for _, stream := range response.Streams {
+			if stream.Content == "" {
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(stream.Content)
+			if err != nil {
+				return 0, errors.Annotate(err, "cannot decode output")
+			}
+			w := stdout
+			if stream.Name == "stderr" {
+				w = stderr
+			}
+			if _, err := w.Write(data); err != nil {
+				return 0, errors.Trace(err)
+			}
+		}
+		if response.CommandState.State == commandStateDone {
+			return response.CommandState.ExitCode, nil
+		}
+	}
+}
+
+// soapFault holds the parts of a SOAP fault that are used.
+type soapFault struct {
+	WSManFault struct {
+		Code    string `xml:"Code,attr"`
+		Message string `xml:"Message"`
+	} `xml:"Body>Fault>Detail>WSManFault"`
+	Reason string `xml:"Body>Fault>Reason>Text"`
+}
+
+// Code returns the WS-Management code of the fault.
+func (f *soapFault) Code() string {
+	return f.WSManFault.Code
+}
+
+// Error implements error.
+func (f *soapFault) Error() string {
+	message := strings.TrimSpace(f.WSManFault.Message)
+	if message == "" {
+		message = strings.TrimSpace(f.Reason)
+	}
+	return fmt.Sprintf("WinRM fault %s: %s", f.WSManFault.Code, message)
+}
+
+// call sends a request with the given action and body to the host,
+// and decodes the response into result. If the host responds with a
+// SOAP fault and fault is not nil, the fault is decoded into it.
+func (c *Client) call(action, shellId string, options map[string]string, body string, fault *soapFault, result interface{}) error {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var request bytes.Buffer
+	err = envelopeTemplate.Execute(&request, envelopeParams{
+		Endpoint:  c.endpoint,
+		Action:    action,
+		MessageId: uuid.String(),
+		ShellId:   shellId,
+		Options:   options,
+		Body:      body,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", c.endpoint, &request)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.SetBasicAuth(c.params.User, c.params.Password)
+	req.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return errors.Unauthorizedf("WinRM authentication as %q failed", c.params.User)
+	case resp.StatusCode != http.StatusOK:
+		if fault == nil {
+			fault = &soapFault{}
+		}
+		if err := xml.Unmarshal(data, fault); err != nil || fault.Code() == "" {
+			return errors.Errorf("WinRM request failed: %s", resp.Status)
+		}
+		return fault
+	}
+	if result == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(data, result); err != nil {
+		return errors.Annotate(err, "cannot decode WinRM response")
+	}
+	return nil
+}
+
+var (
+	shellOptions = map[string]string{
+		"WINRS_NOPROFILE": "FALSE",
+		"WINRS_CODEPAGE":  "65001",
+	}
+	commandOptions = map[string]string{
+		"WINRS_CONSOLEMODE_STDIN": "TRUE",
+		"WINRS_SKIP_CMD_SHELL":    "FALSE",
+	}
+)
+
+const createShellBody = `<rsp:Shell><rsp:InputStreams>stdin</rsp:InputStreams><rsp:OutputStreams>stdout stderr</rsp:OutputStreams></rsp:Shell>`
+
+type envelopeParams struct {
+	Endpoint  string
+	Action    string
+	MessageId string
+	ShellId   string
+	Options   map[string]string
+	Body      string
+}
+
+var envelopeTemplate = template.Must(template.New("envelope").Parse(`<s:Envelope` +
+	` xmlns:s="http://www.w3.org/2003/05/soap-envelope"` +
+	` xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing"` +
+	` xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd"` +
+	` xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell">` +
+	`<s:Header>` +
+	`<a:To>{{.Endpoint}}</a:To>` +
+	`<w:ResourceURI s:mustUnderstand="true">http://schemas.microsoft.com/wbem/wsman/1/windows/shell/cmd</w:ResourceURI>` +
+	`<a:ReplyTo><a:Address s:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>` +
+	`<a:Action s:mustUnderstand="true">{{.Action}}</a:Action>` +
+	`<w:MaxEnvelopeSize s:mustUnderstand="true">153600</w:MaxEnvelopeSize>` +
+	`<a:MessageID>uuid:{{.MessageId}}</a:MessageID>` +
+	`<w:Locale xml:lang="en-US" s:mustUnderstand="false"/>` +
+	`<w:OperationTimeout>PT60S</w:OperationTimeout>` +
+	`{{if .ShellId}}<w:SelectorSet><w:Selector Name="ShellId">{{.ShellId}}</w:Selector></w:SelectorSet>{{end}}` +
+	`{{if .Options}}<w:OptionSet>{{range $name, $value := .Options}}<w:Option Name="{{$name}}">{{$value}}</w:Option>{{end}}</w:OptionSet>{{end}}` +
+	`</s:Header>` +
+	`<s:Body>{{.Body}}</s:Body>` +
+	`</s:Envelope>`))
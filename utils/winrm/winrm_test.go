@@ -0,0 +1,193 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package winrm_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/winrm"
+)
+
+type WinRMSuite struct {
+	testing.IsolationSuite
+	server *httptest.Server
+	fake   *fakeWinRM
+	client *winrm.Client
+}
+
+var _ = gc.Suite(&WinRMSuite{})
+
+func (s *WinRMSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.fake = &fakeWinRM{}
+	s.server = httptest.NewServer(s.fake)
+	host, port, err := net.SplitHostPort(s.server.Listener.Addr().String())
+	c.Assert(err, jc.ErrorIsNil)
+	portNum, err := strconv.Atoi(port)
+	c.Assert(err, jc.ErrorIsNil)
+	s.client = winrm.NewClient(winrm.ClientParams{
+		Host:     host,
+		Port:     portNum,
+		User:     "Administrator",
+		Password: "secret",
+	})
+}
+
+func (s *WinRMSuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+	s.IsolationSuite.TearDownTest(c)
+}
+
+func (s *WinRMSuite) TestRun(c *gc.C) {
+	s.fake.stdout = "hello\r\n"
+	s.fake.stderr = "warning\r\n"
+	var stdout, stderr bytes.Buffer
+	err := s.client.Run("echo hello", &stdout, &stderr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout.String(), gc.Equals, "hello\r\n")
+	c.Assert(stderr.String(), gc.Equals, "warning\r\n")
+	c.Assert(s.fake.commands, jc.DeepEquals, []string{"echo hello"})
+	c.Assert(s.fake.actions, jc.DeepEquals, []string{
+		"Create", "Command", "Receive", "Receive", "Delete",
+	})
+}
+
+func (s *WinRMSuite) TestRunExitCode(c *gc.C) {
+	s.fake.exitCode = 3
+	err := s.client.Run("exit 3", &bytes.Buffer{}, &bytes.Buffer{})
+	c.Assert(err, gc.ErrorMatches, "command failed with exit code 3")
+	c.Assert(s.fake.actions[len(s.fake.actions)-1], gc.Equals, "Delete")
+}
+
+func (s *WinRMSuite) TestRunUnauthorized(c *gc.C) {
+	s.fake.password = "other"
+	err := s.client.Run("echo hello", &bytes.Buffer{}, &bytes.Buffer{})
+	c.Assert(err, gc.ErrorMatches, `cannot create shell: WinRM authentication as "Administrator" failed`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *WinRMSuite) TestRunPowerShellShort(c *gc.C) {
+	err := s.client.RunPowerShell("Write-Host hello", &bytes.Buffer{}, &bytes.Buffer{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.commands, jc.DeepEquals, []string{
+		winrm.PowerShellCommand("Write-Host hello"),
+	})
+}
+
+func (s *WinRMSuite) TestRunPowerShellLong(c *gc.C) {
+	script := string(bytes.Repeat([]byte("Write-Host hello\r\n"), 300))
+	err := s.client.RunPowerShell(script, &bytes.Buffer{}, &bytes.Buffer{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The script is uploaded in chunks, and then run.
+	c.Assert(len(s.fake.commands) > 2, jc.IsTrue)
+	uploadRE := regexp.MustCompile(`^Add-Content -Path "\$env:TEMP\\juju-[0-9a-f-]+\.b64" -Value '([A-Za-z0-9+/=]+)'$`)
+	var uploaded string
+	for _, command := range s.fake.commands[:len(s.fake.commands)-1] {
+		match := uploadRE.FindStringSubmatch(decodePowerShell(c, command))
+		c.Assert(match, gc.NotNil)
+		uploaded += match[1]
+	}
+	data, err := base64.StdEncoding.DecodeString(uploaded)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, script)
+	c.Assert(decodePowerShell(c, s.fake.commands[len(s.fake.commands)-1]), gc.Matches, `(?s).*& ".*\.ps1".*`)
+}
+
+// decodePowerShell returns the script run by the given PowerShell
+// command line.
+func decodePowerShell(c *gc.C, command string) string {
+	const prefix = "powershell.exe -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand "
+	c.Assert(command[:len(prefix)], gc.Equals, prefix)
+	data, err := base64.StdEncoding.DecodeString(command[len(prefix):])
+	c.Assert(err, jc.ErrorIsNil)
+	runes := make([]uint16, len(data)/2)
+	for i := range runes {
+		runes[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+	}
+	return string(utf16.Decode(runes))
+}
+
+// fakeWinRM is an http.Handler that responds to WinRM requests for a
+// single command at a time.
+type fakeWinRM struct {
+	password string
+	stdout   string
+	stderr   string
+	exitCode int
+
+	actions  []string
+	commands []string
+	received bool
+}
+
+var actionRE = regexp.MustCompile(`<a:Action s:mustUnderstand="true">[^<]*/([A-Za-z]+)</a:Action>`)
+
+func (f *fakeWinRM) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	password := f.password
+	if password == "" {
+		password = "secret"
+	}
+	if user, pass, ok := req.BasicAuth(); !ok || user != "Administrator" || pass != password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	action := actionRE.FindStringSubmatch(string(body))[1]
+	f.actions = append(f.actions, action)
+	var response string
+	switch action {
+	case "Create":
+		response = `<rsp:Shell><rsp:ShellId>shell-1</rsp:ShellId></rsp:Shell>`
+	case "Command":
+		var request struct {
+			Command string `xml:"Body>CommandLine>Command"`
+		}
+		xml.Unmarshal(body, &request)
+		f.commands = append(f.commands, request.Command)
+		f.received = false
+		response = `<rsp:CommandResponse><rsp:CommandId>command-1</rsp:CommandId></rsp:CommandResponse>`
+	case "Receive":
+		if !f.received {
+			// The first Receive times out, as if the command
+			// had not produced any output yet.
+			f.received = true
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, envelope(`<s:Fault><s:Detail><f:WSManFault xmlns:f="http://schemas.microsoft.com/wbem/wsman/1/wsmanfault" Code="2150858793"><f:Message>timed out</f:Message></f:WSManFault></s:Detail></s:Fault>`))
+			return
+		}
+		response = fmt.Sprintf(`<rsp:ReceiveResponse>`+
+			`<rsp:Stream Name="stdout" CommandId="command-1">%s</rsp:Stream>`+
+			`<rsp:Stream Name="stderr" CommandId="command-1">%s</rsp:Stream>`+
+			`<rsp:CommandState CommandId="command-1" State="http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done">`+
+			`<rsp:ExitCode>%d</rsp:ExitCode></rsp:CommandState>`+
+			`</rsp:ReceiveResponse>`,
+			base64.StdEncoding.EncodeToString([]byte(f.stdout)),
+			base64.StdEncoding.EncodeToString([]byte(f.stderr)),
+			f.exitCode,
+		)
+	}
+	fmt.Fprint(w, envelope(response))
+}
+
+func envelope(body string) string {
+	return `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"` +
+		` xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell">` +
+		`<s:Body>` + body + `</s:Body></s:Envelope>`
+}
@@ -4,11 +4,8 @@
 package version
 
 import (
-	"strings"
 	"syscall"
 	"unsafe"
-
-	"github.com/juju/errors"
 )
 
 func readRegString(h syscall.Handle, key string) (value string, err error) {
@@ -50,13 +47,9 @@ func osVersion() (string, error) {
 	if err != nil {
 		return "unknown", err
 	}
-	if val, ok := windowsVersions[ver]; ok {
-		return val, nil
-	}
-	for key, value := range windowsVersions {
-		if strings.HasPrefix(ver, key) {
-			return value, nil
-		}
+	series, err := WindowsSeries(ver)
+	if err != nil {
+		return "unknown", err
 	}
-	return "unknown", errors.Errorf("unknown series %q", ver)
+	return series, nil
 }
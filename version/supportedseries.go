@@ -93,6 +93,24 @@ var windowsVersions = map[string]string{
 	"Windows 8.1":                    "win81",
 }
 
+// WindowsSeries returns the series of the Windows version with the
+// given product name, as held in the registry of a Windows host. The
+// longest known name that prefixes the product name is matched, so
+// that the flavor of the version is ignored.
+func WindowsSeries(productName string) (string, error) {
+	productName = strings.TrimSpace(productName)
+	var series, matched string
+	for name, value := range windowsVersions {
+		if strings.HasPrefix(productName, name) && len(name) > len(matched) {
+			series, matched = value, name
+		}
+	}
+	if series == "" {
+		return "", fmt.Errorf("unknown series %q", productName)
+	}
+	return series, nil
+}
+
 var distroInfo = "/usr/share/distro-info/ubuntu.csv"
 
 // GetOSFromSeries will return the operating system based
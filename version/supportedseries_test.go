@@ -71,3 +71,32 @@ func (s *supportedSeriesSuite) TestOSSupportedSeries(c *gc.C) {
 	series = version.OSSupportedSeries(version.CentOS)
 	c.Assert(series, jc.SameContents, []string{"centos7"})
 }
+
+func (s *supportedSeriesSuite) TestWindowsSeries(c *gc.C) {
+	for _, t := range []struct {
+		productName string
+		series      string
+		err         string
+	}{{
+		productName: "Windows Server 2012 R2 Standard",
+		series:      "win2012r2",
+	}, {
+		productName: "Windows Server 2012 Datacenter",
+		series:      "win2012",
+	}, {
+		productName: "Windows 8.1 Pro\r\n",
+		series:      "win81",
+	}, {
+		productName: "Windows 95",
+		err:         `unknown series "Windows 95"`,
+	}} {
+		c.Logf("product name %q", t.productName)
+		series, err := version.WindowsSeries(t.productName)
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(series, gc.Equals, t.series)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.actionscheduler")
+
+// State defines the state methods used by the worker.
+type State interface {
+	ActionSchedules() ([]*state.ActionSchedule, error)
+	WatchActionSchedules() state.NotifyWatcher
+}
+
+// New returns a worker which queues up scheduled Actions when they fall
+// due. This worker is intended to run just once, on the MongoDB master.
+func New(st State) worker.Worker {
+	w := &scheduleWorker{st: st}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type scheduleWorker struct {
+	tomb tomb.Tomb
+	st   State
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *scheduleWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *scheduleWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *scheduleWorker) loop() error {
+	schedulesWatcher := w.st.WatchActionSchedules()
+	defer watcher.Stop(schedulesWatcher, &w.tomb)
+
+	var due <-chan time.Time
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-schedulesWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(schedulesWatcher)
+			}
+		case <-due:
+		}
+		next, err := w.dispatch(time.Now().UTC())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		due = nil
+		if !next.IsZero() {
+			due = time.After(next.Sub(time.Now()))
+		}
+	}
+}
+
+// dispatch queues up the Actions whose schedules have fallen due by
+// now, returning the time at which the next schedule falls due, or the
+// zero time if there is none. A schedule that fails to queue up its
+// Action is moved on regardless, so the failure is only logged.
+func (w *scheduleWorker) dispatch(now time.Time) (time.Time, error) {
+	schedules, err := w.st.ActionSchedules()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	for _, sched := range schedules {
+		if sched.NextRun().After(now) {
+			// Schedules are ordered by the time they next fall due.
+			return sched.NextRun(), nil
+		}
+		action, err := sched.Dispatch(now)
+		if err != nil {
+			logger.Errorf("scheduled action %q on %s failed: %v", sched.Name(), sched.Receiver(), err)
+			continue
+		}
+		logger.Infof("queued scheduled action %q on %s as %s", sched.Name(), sched.Receiver(), action.Id())
+	}
+	return time.Time{}, nil
+}
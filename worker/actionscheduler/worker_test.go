@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type suite struct {
+	testing.JujuConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&suite{})
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "dummy", ch)
+	var err error
+	s.unit, err = svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmURL(ch.URL())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *suite) waitForActions(c *gc.C, count int) []*state.Action {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		actions, err := s.unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(actions) == count {
+			return actions
+		}
+	}
+	c.Fatalf("timed out waiting for %d scheduled actions", count)
+	return nil
+}
+
+func (s *suite) TestDispatchesDueActions(c *gc.C) {
+	past := time.Now().Add(-time.Minute)
+	_, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, past, "")
+	c.Assert(err, jc.ErrorIsNil)
+	future, err := s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, time.Now().Add(time.Hour), "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := actionscheduler.New(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	actions := s.waitForActions(c, 1)
+	c.Check(actions[0].Name(), gc.Equals, "snapshot")
+
+	// A schedule added while the worker is running is noticed.
+	_, err = s.State.AddActionSchedule(s.unit.Tag(), "snapshot", nil, past, "")
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitForActions(c, 2)
+
+	// Only the schedule that is not yet due remains.
+	schedules, err := s.State.ActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules, gc.HasLen, 1)
+	c.Check(schedules[0].Id(), gc.Equals, future.Id())
+}
@@ -41,6 +41,8 @@ type addresserHandler struct {
 
 // NewWorker returns a worker that keeps track of
 // IP address lifecycles, releaseing and removing Dead addresses.
+// If the environment does not support networking, the returned
+// worker does nothing.
 func NewWorker(st stateAddresser) (worker.Worker, error) {
 	config, err := st.EnvironConfig()
 	if err != nil {
@@ -52,7 +54,8 @@ func NewWorker(st stateAddresser) (worker.Worker, error) {
 	}
 	netEnviron, ok := environs.SupportsNetworking(environ)
 	if !ok {
-		return nil, errors.New("environment does not support networking")
+		logger.Infof("environment does not support networking; not releasing addresses")
+		return worker.NewNoOpWorker(), nil
 	}
 	a := newWorkerWithReleaser(st, netEnviron)
 	return a, nil
@@ -101,15 +104,18 @@ func (a *addresserHandler) releaseIPAddress(addr *state.IPAddress) (err error) {
 	defer errors.DeferredAnnotatef(&err, "failed to release address %v", addr.Value())
 	var machine *state.Machine
 	logger.Debugf("attempting to release dead address %#v", addr.Value())
-	machine, err = a.st.Machine(addr.MachineId())
+	// Addresses allocated to a container are assigned to the host
+	// instance, and the container itself may already be removed.
+	hostId := state.TopParentId(addr.MachineId())
+	machine, err = a.st.Machine(hostId)
 	if err != nil {
-		return errors.Annotatef(err, "cannot get allocated machine %q", addr.MachineId())
+		return errors.Annotatef(err, "cannot get allocated machine %q", hostId)
 	}
 
 	var instId instance.Id
 	instId, err = machine.InstanceId()
 	if err != nil {
-		return errors.Annotatef(err, "cannot get machine %q instance ID", addr.MachineId())
+		return errors.Annotatef(err, "cannot get machine %q instance ID", hostId)
 	}
 
 	subnetId := network.Id(addr.SubnetId())
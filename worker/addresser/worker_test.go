@@ -11,6 +11,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -174,6 +175,35 @@ func (s *workerSuite) TestWorkerRemovesDeadAddress(c *gc.C) {
 	}
 }
 
+func (s *workerSuite) TestWorkerReleasesRemovedContainerAddress(c *gc.C) {
+	w, err := addresser.NewWorker(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	defer s.assertStop(c, w)
+	s.waitForInitialDead(c)
+	opsChan := dummyListen()
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	container, err := s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := s.State.AddIPAddress(network.NewAddress("0.1.2.7"), "foobar")
+	c.Assert(err, jc.ErrorIsNil)
+	err = addr.AllocateTo(container.Id(), "eth0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Removing the container kills its address, which is released
+	// from the host instance.
+	err = container.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	op := waitForReleaseOp(c, opsChan)
+	c.Assert(op, jc.DeepEquals, makeReleaseOp(7))
+}
+
 func (s *workerSuite) TestErrorKillsWorker(c *gc.C) {
 	s.AssertConfigParameterUpdated(c, "broken", "ReleaseAddress")
 	w, err := addresser.NewWorker(s.State)
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agenthealth defines a worker that periodically reports the
+// health of the agent it runs in to the state server, so that sick
+// agents can be identified before they fail.
+package agenthealth
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/du"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.agenthealth")
+
+// reportPeriod is the time period between health reports.
+var reportPeriod = 5 * time.Minute
+
+// availableSpace returns the number of bytes available on the
+// filesystem containing path. It is a variable so tests can patch it.
+var availableSpace = du.AvailableSpace
+
+// bytesInMiB is the number of bytes in a MiB.
+const bytesInMiB = 1024 * 1024
+
+// HealthSetter is an interface that is supplied to NewWorker for
+// recording the agent's health.
+type HealthSetter interface {
+	SetAgentHealth(params.AgentHealth) error
+}
+
+// Config holds the information needed by the agenthealth worker.
+type Config struct {
+	// Setter records the health of the agent.
+	Setter HealthSetter
+
+	// DataDir is the agent's data directory, the available space in
+	// which is reported.
+	DataDir string
+
+	// LastAPICall returns the time at which the agent last completed
+	// an API call successfully.
+	LastAPICall func() time.Time
+
+	// HookQueueDepth, if not nil, returns the number of hooks waiting
+	// to be run by the agent.
+	HookQueueDepth func() int
+}
+
+// Validate returns an error if the config cannot be used to start a
+// worker.
+func (config Config) Validate() error {
+	if config.Setter == nil {
+		return errors.NotValidf("missing Setter")
+	}
+	if config.DataDir == "" {
+		return errors.NotValidf("missing DataDir")
+	}
+	if config.LastAPICall == nil {
+		return errors.NotValidf("missing LastAPICall")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically reports the health of
+// the agent described by config.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	f := func(stop <-chan struct{}) error {
+		return report(config)
+	}
+	return worker.NewPeriodicWorker(f, reportPeriod), nil
+}
+
+// report collects and records the agent's current health.
+func report(config Config) error {
+	var health params.AgentHealth
+	available, err := availableSpace(config.DataDir)
+	if err != nil {
+		return errors.Annotatef(err, "cannot determine space available in %q", config.DataDir)
+	}
+	health.DataDirAvailable = available / bytesInMiB
+	health.LastAPICall = config.LastAPICall()
+	if config.HookQueueDepth != nil {
+		health.HookQueueDepth = config.HookQueueDepth()
+	}
+	logger.Debugf("reporting agent health: %+v", health)
+	return errors.Trace(config.Setter.SetAgentHealth(health))
+}
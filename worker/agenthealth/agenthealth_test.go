@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agenthealth_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/agenthealth"
+)
+
+var _ = gc.Suite(&AgentHealthWorkerSuite{})
+
+type AgentHealthWorkerSuite struct {
+	coretesting.BaseSuite
+	lastCall time.Time
+}
+
+func (s *AgentHealthWorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.lastCall = time.Date(2015, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(agenthealth.AvailableSpace, func(path string) (uint64, error) {
+		c.Check(path, gc.Equals, "/var/lib/juju")
+		return 3 * 1024 * 1024 * 1024, nil
+	})
+}
+
+type healthSetterFunc func(params.AgentHealth) error
+
+func (f healthSetterFunc) SetAgentHealth(health params.AgentHealth) error {
+	return f(health)
+}
+
+func (s *AgentHealthWorkerSuite) config(setter agenthealth.HealthSetter) agenthealth.Config {
+	return agenthealth.Config{
+		Setter:      setter,
+		DataDir:     "/var/lib/juju",
+		LastAPICall: func() time.Time { return s.lastCall },
+	}
+}
+
+func (s *AgentHealthWorkerSuite) TestWorker(c *gc.C) {
+	s.PatchValue(agenthealth.ReportPeriod, time.Millisecond)
+	reports := make(chan params.AgentHealth, 10)
+	setter := healthSetterFunc(func(health params.AgentHealth) error {
+		select {
+		case reports <- health:
+		default:
+		}
+		return nil
+	})
+
+	w, err := agenthealth.NewWorker(s.config(setter))
+	c.Assert(err, jc.ErrorIsNil)
+	defer w.Wait()
+	defer w.Kill()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case health := <-reports:
+			c.Check(health, jc.DeepEquals, params.AgentHealth{
+				LastAPICall:      s.lastCall,
+				DataDirAvailable: 3 * 1024,
+			})
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for agent health report")
+		}
+	}
+}
+
+func (s *AgentHealthWorkerSuite) TestReportHookQueueDepth(c *gc.C) {
+	var reported params.AgentHealth
+	setter := healthSetterFunc(func(health params.AgentHealth) error {
+		reported = health
+		return nil
+	})
+	config := s.config(setter)
+	config.HookQueueDepth = func() int { return 7 }
+
+	err := agenthealth.Report(config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reported, jc.DeepEquals, params.AgentHealth{
+		HookQueueDepth:   7,
+		LastAPICall:      s.lastCall,
+		DataDirAvailable: 3 * 1024,
+	})
+}
+
+func (s *AgentHealthWorkerSuite) TestReportErrors(c *gc.C) {
+	setter := healthSetterFunc(func(params.AgentHealth) error {
+		return errors.New("splat")
+	})
+	err := agenthealth.Report(s.config(setter))
+	c.Assert(err, gc.ErrorMatches, "splat")
+
+	s.PatchValue(agenthealth.AvailableSpace, func(string) (uint64, error) {
+		return 0, errors.New("no disk")
+	})
+	err = agenthealth.Report(s.config(setter))
+	c.Assert(err, gc.ErrorMatches, `cannot determine space available in "/var/lib/juju": no disk`)
+}
+
+func (s *AgentHealthWorkerSuite) TestNewWorkerValidatesConfig(c *gc.C) {
+	config := s.config(nil)
+	_, err := agenthealth.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "missing Setter not valid")
+
+	config = s.config(healthSetterFunc(nil))
+	config.LastAPICall = nil
+	_, err = agenthealth.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "missing LastAPICall not valid")
+}
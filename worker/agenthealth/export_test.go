@@ -0,0 +1,10 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agenthealth
+
+var (
+	ReportPeriod   = &reportPeriod
+	AvailableSpace = &availableSpace
+	Report         = report
+)
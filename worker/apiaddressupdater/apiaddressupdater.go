@@ -19,6 +19,9 @@ var logger = loggo.GetLogger("juju.worker.apiaddressupdater")
 //
 // In practice, APIAddressUpdater is used by a machine agent to watch
 // API addresses in state and write the changes to the agent's config file.
+// Agents that resolve the API addresses from DNS SRV records (see
+// agent.APIAddressesSRV) fall back to the addresses written here when
+// the lookup fails.
 type APIAddressUpdater struct {
 	addresser APIAddresser
 	setter    APIAddressSetter
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+// scheduledNotes annotates the backups created by the worker.
+const scheduledNotes = "scheduled backup"
+
+// NewStateBackups returns a Backups that stores the backups of the
+// state server machine in state.
+func NewStateBackups(st *state.State, paths backups.Paths, machineID string) Backups {
+	return &stateBackups{
+		st:        st,
+		paths:     paths,
+		machineID: machineID,
+	}
+}
+
+type stateBackups struct {
+	st        *state.State
+	paths     backups.Paths
+	machineID string
+}
+
+// Create is part of the Backups interface.
+func (b *stateBackups) Create() (*backups.Metadata, error) {
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+
+	session := b.st.MongoSession().Copy()
+	defer session.Close()
+
+	dbInfo, err := backups.NewDBInfo(b.st.MongoConnectionInfo(), session)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(b.st, b.machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Notes = scheduledNotes
+	meta.Scheduled = true
+	if _, meta.OplogPosition, err = backups.OplogRange(session); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := backups.NewBackups(stor).Create(meta, &b.paths, dbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// List is part of the Backups interface.
+func (b *stateBackups) List() ([]*backups.Metadata, error) {
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+	return backups.NewBackups(stor).List()
+}
+
+// Remove is part of the Backups interface.
+func (b *stateBackups) Remove(id string) error {
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+	return backups.NewBackups(stor).Remove(id)
+}
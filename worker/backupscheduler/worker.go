@@ -0,0 +1,178 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/utils/schedule"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// State defines the state methods used by the worker.
+type State interface {
+	EnvironConfig() (*config.Config, error)
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+}
+
+// Backups defines the backups operations used by the worker.
+type Backups interface {
+	// Create creates and stores a new scheduled backup.
+	Create() (*backups.Metadata, error)
+
+	// List returns the metadata for all stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Remove deletes the backup from storage.
+	Remove(id string) error
+}
+
+// after returns a channel on which the time is sent when the schedule
+// next falls due.
+var after = func(sched *schedule.Schedule) <-chan time.Time {
+	now := time.Now().UTC()
+	next := sched.Next(now)
+	if next.IsZero() {
+		return nil
+	}
+	return time.After(next.Sub(now))
+}
+
+// New returns a worker which creates backups on the schedule given by
+// the environment's backups-schedule setting, keeping only the most
+// recent backups-retention of them. Each scheduled backup is marked as
+// such in its metadata, so it is listed alongside those requested by
+// users. This worker is intended to run just once, on the MongoDB
+// master.
+func New(st State, b Backups) worker.Worker {
+	w := &scheduleWorker{
+		st:      st,
+		backups: b,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type scheduleWorker struct {
+	tomb    tomb.Tomb
+	st      State
+	backups Backups
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *scheduleWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *scheduleWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *scheduleWorker) loop() error {
+	configWatcher := w.st.WatchForEnvironConfigChanges()
+	defer watcher.Stop(configWatcher, &w.tomb)
+
+	var spec string
+	var sched *schedule.Schedule
+	var retention int
+	var due <-chan time.Time
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(configWatcher)
+			}
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			retention = cfg.BackupsRetention()
+			if cfg.BackupsSchedule() == spec {
+				continue
+			}
+			spec = cfg.BackupsSchedule()
+			if spec == "" {
+				logger.Infof("scheduled backups disabled")
+				sched, due = nil, nil
+				continue
+			}
+			if sched, err = schedule.Parse(spec); err != nil {
+				return errors.Trace(err)
+			}
+			logger.Infof("scheduling backups %q", spec)
+			due = after(sched)
+		case <-due:
+			// A failed backup is retried when the schedule next
+			// falls due.
+			if err := w.backUp(retention); err != nil {
+				logger.Errorf("scheduled backup failed: %v", err)
+			}
+			due = after(sched)
+		}
+	}
+}
+
+// backUp creates a scheduled backup and then removes the oldest
+// scheduled backups beyond the retention count.
+func (w *scheduleWorker) backUp(retention int) error {
+	meta, err := w.backups.Create()
+	if err != nil {
+		return errors.Annotate(err, "cannot create backup")
+	}
+	logger.Infof("created scheduled backup %q", meta.ID())
+
+	list, err := w.backups.List()
+	if err != nil {
+		return errors.Annotate(err, "cannot list backups")
+	}
+	// Full backups on which others are based are kept, so that the
+	// incremental backups can still be restored.
+	bases := make(map[string]bool)
+	var scheduled []*backups.Metadata
+	for _, m := range list {
+		if m.Incremental() {
+			bases[m.BaseID] = true
+		}
+		if m.Scheduled {
+			scheduled = append(scheduled, m)
+		}
+	}
+	if len(scheduled) <= retention {
+		return nil
+	}
+	sort.Sort(byStarted(scheduled))
+	for _, m := range scheduled[:len(scheduled)-retention] {
+		if bases[m.ID()] {
+			continue
+		}
+		if err := w.backups.Remove(m.ID()); err != nil {
+			return errors.Annotatef(err, "cannot remove expired backup %q", m.ID())
+		}
+		logger.Infof("removed expired scheduled backup %q", m.ID())
+	}
+	return nil
+}
+
+type byStarted []*backups.Metadata
+
+func (b byStarted) Len() int           { return len(b) }
+func (b byStarted) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byStarted) Less(i, j int) bool { return b[i].Started.Before(b[j].Started) }
@@ -0,0 +1,194 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"fmt"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/schedule"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type suite struct {
+	coretesting.BaseSuite
+	due       chan time.Time
+	scheduled chan struct{}
+}
+
+var _ = gc.Suite(&suite{})
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.due = make(chan time.Time)
+	s.scheduled = make(chan struct{}, 10)
+	s.PatchValue(backupscheduler.After, func(*schedule.Schedule) <-chan time.Time {
+		s.scheduled <- struct{}{}
+		return s.due
+	})
+}
+
+type mockWatcher struct {
+	changes chan struct{}
+}
+
+func (w *mockWatcher) Changes() <-chan struct{} { return w.changes }
+func (w *mockWatcher) Stop() error              { return nil }
+func (w *mockWatcher) Kill()                    {}
+func (w *mockWatcher) Wait() error              { return nil }
+func (w *mockWatcher) Err() error               { return nil }
+
+type mockState struct {
+	cfg     *config.Config
+	watcher *mockWatcher
+}
+
+func (st *mockState) EnvironConfig() (*config.Config, error) {
+	return st.cfg, nil
+}
+
+func (st *mockState) WatchForEnvironConfigChanges() state.NotifyWatcher {
+	return st.watcher
+}
+
+type mockBackups struct {
+	mu      sync.Mutex
+	list    []*backups.Metadata
+	count   int
+	removed []string
+}
+
+func newMockBackups() *mockBackups {
+	b := &mockBackups{}
+	t0 := time.Date(2015, time.May, 1, 0, 0, 0, 0, time.UTC)
+	b.add("old", t0, true, "")
+	b.add("older", t0.Add(-time.Hour), true, "")
+	b.add("manual", t0.Add(-2*time.Hour), false, "")
+	return b
+}
+
+func (b *mockBackups) add(id string, started time.Time, scheduled bool, baseID string) {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Scheduled = scheduled
+	meta.BaseID = baseID
+	b.list = append(b.list, meta)
+}
+
+func (b *mockBackups) Create() (*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.count++
+	id := fmt.Sprintf("new-%d", b.count)
+	b.add(id, time.Now(), true, "")
+	return b.list[len(b.list)-1], nil
+}
+
+func (b *mockBackups) List() ([]*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.list, nil
+}
+
+func (b *mockBackups) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removed = append(b.removed, id)
+	for i, meta := range b.list {
+		if meta.ID() == id {
+			b.list = append(b.list[:i], b.list[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (b *mockBackups) removedIds() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.removed
+}
+
+func (s *suite) startWorker(c *gc.C, attrs coretesting.Attrs, b *mockBackups) {
+	st := &mockState{
+		cfg:     coretesting.CustomEnvironConfig(c, attrs),
+		watcher: &mockWatcher{changes: make(chan struct{}, 1)},
+	}
+	st.watcher.changes <- struct{}{}
+	w := backupscheduler.New(st, b)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) waitScheduled(c *gc.C) {
+	select {
+	case <-s.scheduled:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for backup to be scheduled")
+	}
+}
+
+// run lets the given number of scheduled backups run, returning once
+// the last has been completed.
+func (s *suite) run(c *gc.C, runs int) {
+	for i := 0; i < runs; i++ {
+		s.waitScheduled(c)
+		select {
+		case s.due <- time.Now():
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for backup to run")
+		}
+	}
+	s.waitScheduled(c)
+}
+
+func (s *suite) TestRemovesExpiredBackups(c *gc.C) {
+	b := newMockBackups()
+	s.startWorker(c, coretesting.Attrs{
+		"backups-schedule":  "@daily",
+		"backups-retention": 2,
+	}, b)
+	s.run(c, 2)
+	// The oldest scheduled backup is removed after each run; the
+	// manual backup is never removed.
+	c.Check(b.removedIds(), jc.DeepEquals, []string{"older", "old"})
+}
+
+func (s *suite) TestKeepsIncrementalBases(c *gc.C) {
+	b := newMockBackups()
+	b.add("incremental", time.Now(), false, "older")
+	s.startWorker(c, coretesting.Attrs{
+		"backups-schedule":  "@daily",
+		"backups-retention": 2,
+	}, b)
+	s.run(c, 2)
+	c.Check(b.removedIds(), jc.DeepEquals, []string{"old"})
+}
+
+func (s *suite) TestNoSchedule(c *gc.C) {
+	b := newMockBackups()
+	s.startWorker(c, nil, b)
+	select {
+	case <-s.scheduled:
+		c.Fatalf("unexpected backup scheduled")
+	case <-time.After(coretesting.ShortWait):
+	}
+	c.Check(b.count, gc.Equals, 0)
+}
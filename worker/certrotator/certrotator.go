@@ -0,0 +1,189 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package certrotator
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/certupdater"
+)
+
+var logger = loggo.GetLogger("juju.worker.certrotator")
+
+var (
+	rotateSSLKey = mongo.RotateSSLKey
+
+	// restartedAttempt governs how long the rotator waits for state
+	// to be reachable again after restarting mongo.
+	restartedAttempt = utils.AttemptStrategy{
+		Total: 5 * time.Minute,
+		Delay: 5 * time.Second,
+	}
+)
+
+// State defines the state methods used by the certificate rotator.
+type State interface {
+	WatchCertRotation() state.NotifyWatcher
+	CertRotation() (*state.CertRotation, error)
+	SetCertRotationTrusted(serial int64, machineId string) error
+	SetCertRotationRestarted(serial int64, machineId string) error
+}
+
+// ConfigChanger defines a function that is called to change the
+// agent's configuration.
+type ConfigChanger func(mutate func(agent.ConfigSetter) error) error
+
+// CertificateRotator takes part in rotations of the certificates served
+// by the state servers' mongo instances.
+//
+// When a rotation is started, the rotator on every state server adds
+// the rotation's CA to those trusted by its agent. Once all of them
+// trust it, each rotator in turn issues a new server certificate from
+// that CA, restarts its mongo with it, and records that it has done so,
+// allowing the next state server to restart.
+type CertificateRotator struct {
+	machineId     string
+	st            State
+	currentConfig func() agent.Config
+	changeConfig  ConfigChanger
+	setter        certupdater.StateServingInfoSetter
+}
+
+// NewCertificateRotator returns a worker.Worker that takes part in mongo
+// certificate rotations on behalf of the given state server machine.
+// The new server certificate is passed to setter, which must record it
+// in the agent's configuration.
+func NewCertificateRotator(
+	machineId string, st State, currentConfig func() agent.Config,
+	changeConfig ConfigChanger, setter certupdater.StateServingInfoSetter,
+) worker.Worker {
+	return worker.NewNotifyWorker(&CertificateRotator{
+		machineId:     machineId,
+		st:            st,
+		currentConfig: currentConfig,
+		changeConfig:  changeConfig,
+		setter:        setter,
+	})
+}
+
+// SetUp is defined on the NotifyWatchHandler interface.
+func (r *CertificateRotator) SetUp() (watcher.NotifyWatcher, error) {
+	return r.st.WatchCertRotation(), nil
+}
+
+// Handle is defined on the NotifyWatchHandler interface.
+func (r *CertificateRotator) Handle() error {
+	rotation, err := r.st.CertRotation()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !set.NewStrings(rotation.MachineIds()...).Contains(r.machineId) {
+		return nil
+	}
+	if !set.NewStrings(rotation.Trusted()...).Contains(r.machineId) {
+		return r.trust(rotation)
+	}
+	if rotation.Ready() && rotation.NextMachineId() == r.machineId {
+		return r.restart(rotation)
+	}
+	return nil
+}
+
+// trust adds the rotation's CA to those trusted by the agent.
+func (r *CertificateRotator) trust(rotation *state.CertRotation) error {
+	err := r.changeConfig(func(config agent.ConfigSetter) error {
+		info, ok := config.StateServingInfo()
+		if !ok {
+			return errors.New("no state serving info")
+		}
+		info.CAPrivateKey = rotation.CAPrivateKey()
+		config.SetStateServingInfo(info)
+		config.SetCACert(rotation.CACert())
+		return nil
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot write agent config")
+	}
+	logger.Infof("trusting CA of certificate rotation %d", rotation.Serial())
+	return r.st.SetCertRotationTrusted(rotation.Serial(), r.machineId)
+}
+
+// restart restarts mongo with a new certificate issued by the
+// rotation's CA. The serial of the rotation is recorded in the agent's
+// configuration so that mongo is only restarted once per rotation,
+// even if the rotator is itself restarted along with mongo.
+func (r *CertificateRotator) restart(rotation *state.CertRotation) error {
+	serial := strconv.FormatInt(rotation.Serial(), 10)
+	config := r.currentConfig()
+	if config.Value(agent.MongoCertRotation) != serial {
+		info, ok := config.StateServingInfo()
+		if !ok {
+			return errors.New("no state serving info")
+		}
+		hostnames, err := certHostnames(info.Cert)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse current server certificate")
+		}
+		info.Cert, info.PrivateKey, err = cert.NewDefaultServer(rotation.CACert(), rotation.CAPrivateKey(), hostnames)
+		if err != nil {
+			return errors.Annotate(err, "cannot generate server certificate")
+		}
+		logger.Infof("restarting mongo for certificate rotation %d", rotation.Serial())
+		if err := rotateSSLKey(config.DataDir(), config.Value(agent.Namespace), info.Cert, info.PrivateKey); err != nil {
+			return errors.Annotate(err, "cannot restart mongo")
+		}
+		if err := r.setter(info); err != nil {
+			return errors.Annotate(err, "cannot write agent config")
+		}
+		err = r.changeConfig(func(config agent.ConfigSetter) error {
+			config.SetValue(agent.MongoCertRotation, serial)
+			return nil
+		})
+		if err != nil {
+			return errors.Annotate(err, "cannot write agent config")
+		}
+	}
+	// Mongo takes a while to come back, and the replica set may
+	// elect a new primary in the meantime.
+	for a := restartedAttempt.Start(); ; {
+		err := r.st.SetCertRotationRestarted(rotation.Serial(), r.machineId)
+		if err == nil || !a.Next() {
+			return errors.Trace(err)
+		}
+		logger.Debugf("cannot record restart yet: %v", err)
+	}
+}
+
+// certHostnames returns the DNS names and IP addresses of the given
+// certificate, so that its replacement may be issued for the same.
+func certHostnames(certPEM string) ([]string, error) {
+	serverCert, err := cert.ParseCert(certPEM)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostnames := append([]string(nil), serverCert.DNSNames...)
+	for _, ip := range serverCert.IPAddresses {
+		hostnames = append(hostnames, ip.String())
+	}
+	return hostnames, nil
+}
+
+// TearDown is defined on the NotifyWatchHandler interface.
+func (r *CertificateRotator) TearDown() error {
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package certrotator_test
+
+import (
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/constraints"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/certrotator"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type CertRotatorSuite struct {
+	statetesting.StateSuite
+
+	mu        sync.Mutex
+	restarted []string
+}
+
+var _ = gc.Suite(&CertRotatorSuite{})
+
+func (s *CertRotatorSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.restarted = nil
+	s.PatchValue(certrotator.RotateSSLKey, func(dataDir, namespace, certPEM, keyPEM string) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// Each machine's config uses its id as its data dir.
+		s.restarted = append(s.restarted, dataDir)
+		return nil
+	})
+	s.PatchValue(certrotator.RestartedAttempt, utils.AttemptStrategy{})
+	_, err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// mockConfig implements the parts of agent.ConfigSetter used by the
+// certificate rotator.
+type mockConfig struct {
+	agent.ConfigSetter
+
+	mu      sync.Mutex
+	dataDir string
+	caCert  string
+	info    params.StateServingInfo
+	values  map[string]string
+}
+
+func newMockConfig(machineId string) *mockConfig {
+	return &mockConfig{
+		dataDir: machineId,
+		caCert:  coretesting.CACert,
+		info: params.StateServingInfo{
+			Cert:         coretesting.ServerCert,
+			PrivateKey:   coretesting.ServerKey,
+			CAPrivateKey: coretesting.CAKey,
+		},
+		values: make(map[string]string),
+	}
+}
+
+func (m *mockConfig) DataDir() string {
+	return m.dataDir
+}
+
+func (m *mockConfig) CACert() string {
+	return m.caCert
+}
+
+func (m *mockConfig) SetCACert(caCert string) {
+	m.caCert = caCert
+}
+
+func (m *mockConfig) StateServingInfo() (params.StateServingInfo, bool) {
+	return m.info, true
+}
+
+func (m *mockConfig) SetStateServingInfo(info params.StateServingInfo) {
+	m.info = info
+}
+
+func (m *mockConfig) Value(key string) string {
+	return m.values[key]
+}
+
+func (m *mockConfig) SetValue(key, value string) {
+	m.values[key] = value
+}
+
+func (m *mockConfig) current() agent.Config {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clone := &mockConfig{
+		dataDir: m.dataDir,
+		caCert:  m.caCert,
+		info:    m.info,
+		values:  make(map[string]string),
+	}
+	for k, v := range m.values {
+		clone.values[k] = v
+	}
+	return clone
+}
+
+func (m *mockConfig) change(mutate func(agent.ConfigSetter) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return mutate(m)
+}
+
+func (m *mockConfig) setStateServingInfo(info params.StateServingInfo) error {
+	return m.change(func(config agent.ConfigSetter) error {
+		config.SetStateServingInfo(info)
+		return nil
+	})
+}
+
+func (s *CertRotatorSuite) startRotator(c *gc.C, machineId string) *mockConfig {
+	config := newMockConfig(machineId)
+	w := certrotator.NewCertificateRotator(machineId, s.State, config.current, config.change, config.setStateServingInfo)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(w), jc.ErrorIsNil)
+	})
+	return config
+}
+
+func (s *CertRotatorSuite) waitDone(c *gc.C) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		rotation, err := s.State.CertRotation()
+		c.Assert(err, jc.ErrorIsNil)
+		if rotation.Done() {
+			return
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for rotation; restarted %v", rotation.Restarted())
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+}
+
+func (s *CertRotatorSuite) TestRollingRestart(c *gc.C) {
+	caCert, caKey, err := cert.NewCA("rotated", time.Now().AddDate(1, 0, 0))
+	c.Assert(err, jc.ErrorIsNil)
+	configs := make(map[string]*mockConfig)
+	for _, id := range []string{"0", "1", "2"} {
+		configs[id] = s.startRotator(c, id)
+	}
+	_, err = s.State.StartCertRotation(caCert, caKey)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitDone(c)
+
+	s.mu.Lock()
+	c.Check(s.restarted, jc.DeepEquals, []string{"0", "1", "2"})
+	s.mu.Unlock()
+	for id, config := range configs {
+		c.Logf("machine %s", id)
+		current := config.current()
+		c.Check(current.CACert(), gc.Equals, caCert)
+		c.Check(current.Value(agent.MongoCertRotation), gc.Equals, "1")
+		info, _ := current.StateServingInfo()
+		c.Check(info.CAPrivateKey, gc.Equals, caKey)
+		c.Check(cert.Verify(info.Cert, caCert, time.Now()), jc.ErrorIsNil)
+		// The new certificate is issued for the same names.
+		newCert, err := cert.ParseCert(info.Cert)
+		c.Assert(err, jc.ErrorIsNil)
+		oldCert, err := cert.ParseCert(coretesting.ServerCert)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(newCert.DNSNames, jc.SameContents, oldCert.DNSNames)
+	}
+}
+
+func (s *CertRotatorSuite) TestAlreadyRestarted(c *gc.C) {
+	rotation, err := s.State.StartCertRotation(coretesting.CACert, coretesting.CAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, id := range []string{"0", "1", "2"} {
+		err := s.State.SetCertRotationTrusted(rotation.Serial(), id)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// A rotator restarted along with mongo does not restart it again.
+	config := newMockConfig("0")
+	config.values[agent.MongoCertRotation] = "1"
+	w := certrotator.NewCertificateRotator("0", s.State, config.current, config.change, config.setStateServingInfo)
+	defer func() { c.Check(worker.Stop(w), jc.ErrorIsNil) }()
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		rotation, err := s.State.CertRotation()
+		c.Assert(err, jc.ErrorIsNil)
+		if rotation.NextMachineId() == "1" {
+			break
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for restart to be recorded")
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Assert(s.restarted, gc.HasLen, 0)
+}
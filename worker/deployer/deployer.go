@@ -188,6 +188,10 @@ func (d *Deployer) remove(unit *apideployer.Unit) error {
 }
 
 func (d *Deployer) TearDown() error {
-	// Nothing to do here.
+	// Contexts running the unit agents themselves must stop them
+	// along with the deployer.
+	if w, ok := d.ctx.(worker.Worker); ok {
+		return worker.Stop(w)
+	}
 	return nil
 }
@@ -33,3 +33,8 @@ func NewTestSimpleContext(agentConfig agent.Config, logDir string, data *svctest
 		},
 	}
 }
+
+func NewTestNestedContext(agentConfig agent.Config, data *svctesting.FakeServiceData, newUnitWorker UnitWorkerFunc) *NestedContext {
+	legacy := NewTestSimpleContext(agentConfig, agentConfig.LogDir(), data)
+	return newNestedContext(agentConfig, &fakeAPI{}, newUnitWorker, legacy)
+}
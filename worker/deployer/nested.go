@@ -0,0 +1,215 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/worker"
+)
+
+// UnitWorkerFunc returns a worker that runs the agent of the named
+// unit, using the agent configuration written in the given data
+// directory.
+type UnitWorkerFunc func(dataDir, unitName string) (worker.Worker, error)
+
+// NestedContext is a Context that runs unit agents as workers inside
+// the machine agent process, rather than as separate processes managed
+// by the init system.
+type NestedContext struct {
+	api         APICalls
+	agentConfig agent.Config
+
+	// newUnitWorker starts the agent of a unit.
+	newUnitWorker UnitWorkerFunc
+
+	// runner runs the unit agents, restarting them when they fail.
+	runner worker.Runner
+
+	// legacy is used to take over the units whose agents were
+	// deployed as separate processes by a SimpleContext.
+	legacy *SimpleContext
+
+	mu       sync.Mutex
+	deployed map[string]bool
+}
+
+var _ Context = (*NestedContext)(nil)
+var _ worker.Worker = (*NestedContext)(nil)
+
+// NewNestedContext returns a new NestedContext that runs the agents of
+// the units deployed on behalf of the given machine agent as workers
+// returned by newUnitWorker. Paths to which the agents' configuration
+// and tools are installed are relative to the machine agent's data
+// directory.
+func NewNestedContext(agentConfig agent.Config, api APICalls, newUnitWorker UnitWorkerFunc) *NestedContext {
+	return newNestedContext(agentConfig, api, newUnitWorker, NewSimpleContext(agentConfig, api))
+}
+
+func newNestedContext(agentConfig agent.Config, api APICalls, newUnitWorker UnitWorkerFunc, legacy *SimpleContext) *NestedContext {
+	return &NestedContext{
+		api:           api,
+		agentConfig:   agentConfig,
+		newUnitWorker: newUnitWorker,
+		runner:        worker.NewRunner(neverFatal, moreImportant),
+		legacy:        legacy,
+		deployed:      make(map[string]bool),
+	}
+}
+
+func neverFatal(error) bool {
+	return false
+}
+
+func moreImportant(err0, err1 error) bool {
+	return true
+}
+
+func (ctx *NestedContext) AgentConfig() agent.Config {
+	return ctx.agentConfig
+}
+
+func (ctx *NestedContext) DeployUnit(unitName, initialPassword string) (err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	tag := names.NewUnitTag(unitName)
+	agentDir := agent.Dir(ctx.agentConfig.DataDir(), tag)
+	if _, err := os.Stat(agentDir); err == nil {
+		return fmt.Errorf("unit %q is already deployed", unitName)
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+
+	toolsDir, agentDir, err := writeUnitAgentConfig(ctx.agentConfig, ctx.api, unitName, initialPassword)
+	if err != nil {
+		return err
+	}
+	defer removeOnErr(&err, toolsDir)
+	defer removeOnErr(&err, agentDir)
+
+	if err := ctx.startUnitWorker(unitName); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// startUnitWorker starts running the agent of the named unit.
+func (ctx *NestedContext) startUnitWorker(unitName string) error {
+	logger.Infof("starting agent of unit %q", unitName)
+	err := ctx.runner.StartWorker(unitName, func() (worker.Worker, error) {
+		return ctx.newUnitWorker(ctx.agentConfig.DataDir(), unitName)
+	})
+	if err != nil {
+		return err
+	}
+	ctx.deployed[unitName] = true
+	return nil
+}
+
+func (ctx *NestedContext) RecallUnit(unitName string) error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if !ctx.deployed[unitName] {
+		return fmt.Errorf("unit %q is not deployed", unitName)
+	}
+	if err := ctx.runner.StopWorker(unitName); err != nil {
+		return err
+	}
+	delete(ctx.deployed, unitName)
+	return removeUnitAgentFiles(ctx.agentConfig.DataDir(), unitName)
+}
+
+// DeployedUnits returns the names of the units whose agent
+// configuration is found in the data directory, making sure that the
+// agents of all of them are running. The agents of units that were
+// deployed as separate processes are taken over: their init system
+// services are removed, and the agents are run in-process instead.
+func (ctx *NestedContext) DeployedUnits() ([]string, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if err := ctx.takeOverLegacyUnits(); err != nil {
+		return nil, err
+	}
+	unitNames, err := ctx.unitAgentDirs()
+	if err != nil {
+		return nil, err
+	}
+	for _, unitName := range unitNames {
+		if ctx.deployed[unitName] {
+			continue
+		}
+		if err := ctx.startUnitWorker(unitName); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return unitNames, nil
+}
+
+// takeOverLegacyUnits stops and removes the init system services
+// running the agents of units deployed by a SimpleContext, leaving
+// their agent configuration in place.
+func (ctx *NestedContext) takeOverLegacyUnits() error {
+	if ctx.legacy == nil {
+		return nil
+	}
+	unitsAndJobs, err := ctx.legacy.deployedUnitsInitSystemJobs()
+	if err != nil {
+		return errors.Annotate(err, "cannot list unit agent services")
+	}
+	for unitName, job := range unitsAndJobs {
+		logger.Infof("taking over agent of unit %q from service %q", unitName, job)
+		svc := ctx.legacy.discoverService(job, common.Conf{})
+		if err := svc.Stop(); err != nil {
+			return errors.Annotatef(err, "cannot stop agent of unit %q", unitName)
+		}
+		if err := svc.Remove(); err != nil {
+			return errors.Annotatef(err, "cannot remove agent service of unit %q", unitName)
+		}
+	}
+	return nil
+}
+
+// unitAgentDirs returns the names of the units whose agent directory
+// is found in the data directory.
+func (ctx *NestedContext) unitAgentDirs() ([]string, error) {
+	agentsDir := filepath.Join(ctx.agentConfig.DataDir(), "agents")
+	fis, err := ioutil.ReadDir(agentsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var unitNames []string
+	for _, fi := range fis {
+		if !fi.IsDir() || !strings.HasPrefix(fi.Name(), names.UnitTagKind+"-") {
+			continue
+		}
+		tag, err := names.ParseUnitTag(fi.Name())
+		if err != nil {
+			continue
+		}
+		unitNames = append(unitNames, tag.Id())
+	}
+	return unitNames, nil
+}
+
+// Kill stops the agents of all deployed units.
+func (ctx *NestedContext) Kill() {
+	ctx.runner.Kill()
+}
+
+// Wait waits for the agents of all deployed units to stop.
+func (ctx *NestedContext) Wait() error {
+	return ctx.runner.Wait()
+}
@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type NestedContextSuite struct {
+	SimpleToolsFixture
+	started chan string
+	stopped chan string
+}
+
+var _ = gc.Suite(&NestedContextSuite{})
+
+func (s *NestedContextSuite) SetUpTest(c *gc.C) {
+	s.SimpleToolsFixture.SetUp(c, c.MkDir())
+	s.started = make(chan string, 10)
+	s.stopped = make(chan string, 10)
+}
+
+func (s *NestedContextSuite) TearDownTest(c *gc.C) {
+	s.SimpleToolsFixture.TearDown(c)
+}
+
+func (s *NestedContextSuite) newUnitWorker(dataDir, unitName string) (worker.Worker, error) {
+	if dataDir != s.dataDir {
+		panic("unexpected data dir " + dataDir)
+	}
+	s.started <- unitName
+	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+		<-stop
+		s.stopped <- unitName
+		return nil
+	}), nil
+}
+
+func (s *NestedContextSuite) getContext(c *gc.C) *deployer.NestedContext {
+	config := agentConfig(names.NewMachineTag("99"), s.dataDir, s.logDir)
+	return deployer.NewTestNestedContext(config, s.data, s.newUnitWorker)
+}
+
+func (s *NestedContextSuite) assertUnitEvent(c *gc.C, ch chan string, unitName string) {
+	select {
+	case got := <-ch:
+		c.Assert(got, gc.Equals, unitName)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for unit %q", unitName)
+	}
+}
+
+func (s *NestedContextSuite) assertNoUnitEvent(c *gc.C, ch chan string) {
+	select {
+	case got := <-ch:
+		c.Fatalf("unexpected event for unit %q", got)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *NestedContextSuite) TestDeployRecall(c *gc.C) {
+	ctx := s.getContext(c)
+	defer func() { c.Check(worker.Stop(ctx), jc.ErrorIsNil) }()
+
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	err = ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitEvent(c, s.started, "foo/123")
+	s.assertUpstartCount(c, 0)
+
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.dataDir, names.NewUnitTag("foo/123")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conf.OldPassword(), gc.Equals, "some-password")
+	c.Assert(conf.DataDir(), gc.Equals, s.dataDir)
+
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123"})
+	s.assertNoUnitEvent(c, s.started)
+
+	err = ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is already deployed`)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitEvent(c, s.stopped, "foo/123")
+	s.checkUnitRemoved(c, "foo/123")
+
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
+func (s *NestedContextSuite) TestDeployedUnitsRestartsAgents(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/1", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.DeployUnit("bar/2", "other-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitEvent(c, s.started, "foo/1")
+	s.assertUnitEvent(c, s.started, "bar/2")
+
+	// Stopping the context stops all the agents, as happens when
+	// the machine agent restarts.
+	err = worker.Stop(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	var stopped []string
+	for i := 0; i < 2; i++ {
+		select {
+		case unitName := <-s.stopped:
+			stopped = append(stopped, unitName)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for agents to stop")
+		}
+	}
+	c.Assert(stopped, jc.SameContents, []string{"foo/1", "bar/2"})
+
+	ctx = s.getContext(c)
+	defer func() { c.Check(worker.Stop(ctx), jc.ErrorIsNil) }()
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Strings(units)
+	c.Assert(units, gc.DeepEquals, []string{"bar/2", "foo/1"})
+	var started []string
+	for i := 0; i < 2; i++ {
+		select {
+		case unitName := <-s.started:
+			started = append(started, unitName)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for agents to start")
+		}
+	}
+	c.Assert(started, jc.SameContents, []string{"foo/1", "bar/2"})
+}
+
+func (s *NestedContextSuite) TestDeployedUnitsTakesOverSeparateAgents(c *gc.C) {
+	// Deploy a unit agent as a separate process, as done before
+	// the agents were run by the machine agent.
+	simple := s.SimpleToolsFixture.getContext(c)
+	err := simple.DeployUnit("foo/1", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpstartCount(c, 1)
+
+	ctx := s.getContext(c)
+	defer func() { c.Check(worker.Stop(ctx), jc.ErrorIsNil) }()
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/1"})
+	s.assertUpstartCount(c, 0)
+	s.assertUnitEvent(c, s.started, "foo/1")
+
+	// The unit agent's configuration is kept.
+	_, err = agent.ReadConfig(agent.ConfigPath(s.dataDir, names.NewUnitTag("foo/1")))
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -99,24 +99,60 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 		return fmt.Errorf("unit %q is already deployed", unitName)
 	}
 
-	// Link the current tools for use by the new agent.
+	toolsDir, agentDir, err := writeUnitAgentConfig(ctx.agentConfig, ctx.api, unitName, initialPassword)
+	if err != nil {
+		return err
+	}
+	defer removeOnErr(&err, toolsDir)
+	defer removeOnErr(&err, agentDir)
+
+	// Install an init service that runs the unit agent.
+	info := service.NewAgentInfo(
+		service.AgentKindUnit,
+		unitName,
+		ctx.agentConfig.DataDir(),
+		ctx.agentConfig.LogDir(),
+	)
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// TODO(thumper): 2013-09-02 bug 1219630
+	// As much as I'd like to remove JujuContainerType now, it is still
+	// needed as MAAS still needs it at this stage, and we can't fix
+	// everything at once.
+	containerType := ctx.agentConfig.Value(agent.ContainerType)
+	sconf := service.ContainerAgentConf(info, renderer, containerType)
+
+	svc.UpdateConfig(sconf)
+	if err := service.InstallAndStart(svc); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// writeUnitAgentConfig links the current tools for use by the agent of
+// the named unit, and writes the agent's configuration. The tools and
+// agent directories are returned, so that the caller can remove them if
+// it then fails to start the agent.
+func writeUnitAgentConfig(agentConfig agent.Config, api APICalls, unitName, initialPassword string) (toolsDir, agentDir string, err error) {
 	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
-	logDir := ctx.agentConfig.LogDir()
+	dataDir := agentConfig.DataDir()
+	logDir := agentConfig.LogDir()
 	// TODO(dfc)
 	_, err = tools.ChangeAgentTools(dataDir, tag.String(), version.Current)
 	// TODO(dfc)
-	toolsDir := tools.ToolsDir(dataDir, tag.String())
+	toolsDir = tools.ToolsDir(dataDir, tag.String())
 	defer removeOnErr(&err, toolsDir)
 
-	result, err := ctx.api.ConnectionInfo()
+	result, err := api.ConnectionInfo()
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	logger.Debugf("state addresses: %q", result.StateAddresses)
 	logger.Debugf("API addresses: %q", result.APIAddresses)
-	containerType := ctx.agentConfig.Value(agent.ContainerType)
-	namespace := ctx.agentConfig.Value(agent.Namespace)
+	containerType := agentConfig.Value(agent.ContainerType)
+	namespace := agentConfig.Value(agent.Namespace)
 	conf, err := agent.NewAgentConfig(
 		agent.AgentConfigParams{
 			DataDir:           dataDir,
@@ -125,46 +161,23 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 			Tag:               tag,
 			Password:          initialPassword,
 			Nonce:             "unused",
-			Environment:       ctx.agentConfig.Environment(),
+			Environment:       agentConfig.Environment(),
 			// TODO: remove the state addresses here and test when api only.
 			StateAddresses: result.StateAddresses,
 			APIAddresses:   result.APIAddresses,
-			CACert:         ctx.agentConfig.CACert(),
+			CACert:         agentConfig.CACert(),
 			Values: map[string]string{
 				agent.ContainerType: containerType,
 				agent.Namespace:     namespace,
 			},
 		})
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	if err := conf.Write(); err != nil {
-		return err
+		return "", "", err
 	}
-	defer removeOnErr(&err, conf.Dir())
-
-	// Install an init service that runs the unit agent.
-	info := service.NewAgentInfo(
-		service.AgentKindUnit,
-		unitName,
-		dataDir,
-		logDir,
-	)
-	renderer, err := shell.NewRenderer("")
-	if err != nil {
-		return errors.Trace(err)
-	}
-	// TODO(thumper): 2013-09-02 bug 1219630
-	// As much as I'd like to remove JujuContainerType now, it is still
-	// needed as MAAS still needs it at this stage, and we can't fix
-	// everything at once.
-	sconf := service.ContainerAgentConf(info, renderer, containerType)
-
-	svc.UpdateConfig(sconf)
-	if err := service.InstallAndStart(svc); err != nil {
-		return errors.Trace(err)
-	}
-	return nil
+	return toolsDir, conf.Dir(), nil
 }
 
 type deployerService interface {
@@ -199,6 +212,9 @@ func (ctx *SimpleContext) RecallUnit(unitName string) error {
 		return fmt.Errorf("unit %q is not deployed", unitName)
 	}
 	installed, err := svc.Installed()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	if !installed {
 		return fmt.Errorf("unit %q is not deployed", unitName)
 	}
@@ -208,12 +224,17 @@ func (ctx *SimpleContext) RecallUnit(unitName string) error {
 	if err := svc.Remove(); err != nil {
 		return err
 	}
+	return removeUnitAgentFiles(ctx.agentConfig.DataDir(), unitName)
+}
+
+// removeUnitAgentFiles removes the agent directory and the tools link
+// of the named unit.
+func removeUnitAgentFiles(dataDir, unitName string) error {
 	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
 	agentDir := agent.Dir(dataDir, tag)
 	// Recursivley change mode to 777 on windows to avoid
 	// Operation not permitted errors when deleting the agentDir
-	err = recursiveChmod(agentDir, os.FileMode(0777))
+	err := recursiveChmod(agentDir, os.FileMode(0777))
 	if err != nil {
 		return err
 	}
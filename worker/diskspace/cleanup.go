@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/version"
+)
+
+// OldToolsCleanup returns a Cleanup that removes the tools unpacked in
+// the data directory that are neither used by any agent on the machine
+// nor of the running version.
+func OldToolsCleanup(dataDir string) Cleanup {
+	return Cleanup{
+		Name: "old tools",
+		Run: func() error {
+			return removeOldTools(filepath.Join(dataDir, "tools"))
+		},
+	}
+}
+
+func removeOldTools(toolsDir string) error {
+	fis, err := ioutil.ReadDir(toolsDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	// The tools used by the agents are the targets of the symlinks
+	// named after them.
+	inUse := map[string]bool{
+		version.Current.String(): true,
+	}
+	for _, fi := range fis {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(toolsDir, fi.Name()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		inUse[filepath.Base(target)] = true
+	}
+	for _, fi := range fis {
+		if !fi.IsDir() || inUse[fi.Name()] {
+			continue
+		}
+		if _, err := version.ParseBinary(fi.Name()); err != nil {
+			continue
+		}
+		logger.Infof("removing unused tools %s", fi.Name())
+		if err := os.RemoveAll(filepath.Join(toolsDir, fi.Name())); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// rotatedLogPattern matches the names of the log files rotated by the
+// agents, such as "machine-0-2015-06-01T10-00-00.000.log", and by
+// logrotate, such as "all-machines.log.1" or "all-machines.log.2.gz".
+var rotatedLogPattern = regexp.MustCompile(
+	`(-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log|\.log\.\d+)(\.gz)?$`,
+)
+
+// RotatedLogsCleanup returns a Cleanup that removes the rotated log
+// files in the log directory.
+func RotatedLogsCleanup(logDir string) Cleanup {
+	return Cleanup{
+		Name: "rotated logs",
+		Run: func() error {
+			return removeRotatedLogs(logDir)
+		},
+	}
+}
+
+func removeRotatedLogs(logDir string) error {
+	fis, err := ioutil.ReadDir(logDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, fi := range fis {
+		if !fi.Mode().IsRegular() || !rotatedLogPattern.MatchString(fi.Name()) {
+			continue
+		}
+		logger.Infof("removing rotated log %s", fi.Name())
+		if err := os.Remove(filepath.Join(logDir, fi.Name())); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspace_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/diskspace"
+)
+
+var _ = gc.Suite(&CleanupSuite{})
+
+type CleanupSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *CleanupSuite) TestOldToolsCleanup(c *gc.C) {
+	s.PatchValue(&version.Current, version.MustParseBinary("1.25.0-trusty-amd64"))
+	dataDir := c.MkDir()
+	toolsDir := filepath.Join(dataDir, "tools")
+	for _, dir := range []string{
+		"1.24.0-trusty-amd64",
+		"1.24.5-trusty-amd64",
+		"1.25.0-trusty-amd64",
+		"1.25.1-trusty-amd64",
+		"not-tools",
+	} {
+		err := os.MkdirAll(filepath.Join(toolsDir, dir), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := os.Symlink(filepath.Join(toolsDir, "1.24.5-trusty-amd64"), filepath.Join(toolsDir, "unit-mysql-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Symlink(filepath.Join(toolsDir, "1.25.1-trusty-amd64"), filepath.Join(toolsDir, "machine-0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = diskspace.OldToolsCleanup(dataDir).Run()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dirNames(c, toolsDir), jc.DeepEquals, []string{
+		"1.24.5-trusty-amd64",
+		"1.25.0-trusty-amd64",
+		"1.25.1-trusty-amd64",
+		"machine-0",
+		"not-tools",
+		"unit-mysql-0",
+	})
+}
+
+func (s *CleanupSuite) TestRotatedLogsCleanup(c *gc.C) {
+	logDir := c.MkDir()
+	for _, name := range []string{
+		"machine-0.log",
+		"machine-0-2015-06-01T10-00-00.000.log",
+		"unit-mysql-0-2015-06-01T10-00-00.000.log.gz",
+		"all-machines.log",
+		"all-machines.log.1",
+		"all-machines.log.2.gz",
+	} {
+		err := ioutil.WriteFile(filepath.Join(logDir, name), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err := diskspace.RotatedLogsCleanup(logDir).Run()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dirNames(c, logDir), jc.DeepEquals, []string{
+		"all-machines.log",
+		"machine-0.log",
+	})
+}
+
+func (s *CleanupSuite) TestCleanupsIgnoreMissingDirs(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "missing")
+	c.Assert(diskspace.OldToolsCleanup(dir).Run(), jc.ErrorIsNil)
+	c.Assert(diskspace.RotatedLogsCleanup(dir).Run(), jc.ErrorIsNil)
+}
+
+func dirNames(c *gc.C, dir string) []string {
+	fis, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	names := make([]string, len(fis))
+	for i, fi := range fis {
+		names[i] = fi.Name()
+	}
+	sort.Strings(names)
+	return names
+}
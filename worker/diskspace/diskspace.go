@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package diskspace defines a worker that monitors the space available
+// on the filesystems a machine agent depends on, reports it in the
+// machine's status, and runs cleanups to free space before it runs
+// out and takes the agent or mongo down.
+package diskspace
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/du"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.diskspace")
+
+// checkPeriod is the time period between checks of the available space.
+var checkPeriod = time.Minute
+
+// availableSpace returns the number of bytes available on the
+// filesystem containing path. It is a variable so tests can patch it.
+var availableSpace = du.AvailableSpace
+
+// bytesInMiB is the number of bytes in a MiB.
+const bytesInMiB = 1024 * 1024
+
+// DefaultThreshold is the available space, in MiB, below which a
+// filesystem is considered low on space if no threshold is configured.
+const DefaultThreshold = 512
+
+// StatusSetter is an interface that is supplied to NewWorker for
+// reporting the available space in the machine's status.
+type StatusSetter interface {
+	SetStatus(status params.Status, info string, data map[string]interface{}) error
+}
+
+// Cleanup is an action that frees space when a filesystem is low on
+// space.
+type Cleanup struct {
+	// Name identifies the cleanup in logs.
+	Name string
+
+	// Run performs the cleanup.
+	Run func() error
+}
+
+// Config holds the information needed by the diskspace worker.
+type Config struct {
+	// StatusSetter records the available space in the machine's status.
+	StatusSetter StatusSetter
+
+	// Paths holds the directories the space available to which is
+	// monitored. Paths that do not exist are ignored.
+	Paths []string
+
+	// Threshold is the available space, in MiB, below which a
+	// filesystem is considered low on space.
+	Threshold uint64
+
+	// Cleanups are run, in order, when any of the paths is low on
+	// space.
+	Cleanups []Cleanup
+}
+
+// Validate returns an error if the config cannot be used to start a
+// worker.
+func (config Config) Validate() error {
+	if config.StatusSetter == nil {
+		return errors.NotValidf("missing StatusSetter")
+	}
+	if len(config.Paths) == 0 {
+		return errors.NotValidf("missing Paths")
+	}
+	if config.Threshold == 0 {
+		return errors.NotValidf("zero Threshold")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically checks the space
+// available to the paths in config, running the cleanups when it is
+// low. The machine's status is set when the worker starts, and whenever
+// the paths that are low on space change.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &diskSpaceWorker{config: config}
+	f := func(stop <-chan struct{}) error {
+		return w.check()
+	}
+	return worker.NewPeriodicWorker(f, checkPeriod), nil
+}
+
+type diskSpaceWorker struct {
+	config Config
+
+	// reported records whether the status has been set, and lastInfo
+	// the status info it was last set with.
+	reported bool
+	lastInfo string
+}
+
+// check measures the available space, runs the cleanups if any path is
+// low on space, and reports the result if it changed.
+func (w *diskSpaceWorker) check() error {
+	available, low, err := w.measure()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(low) > 0 && len(w.config.Cleanups) > 0 {
+		logger.Warningf("low disk space on %s, running cleanups", strings.Join(low, ", "))
+		w.cleanup()
+		if available, low, err = w.measure(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	var info string
+	if len(low) > 0 {
+		lowInfo := make([]string, len(low))
+		for i, path := range low {
+			lowInfo[i] = fmt.Sprintf("%s (%dMiB available)", path, available[path])
+		}
+		info = "low disk space: " + strings.Join(lowInfo, ", ")
+		logger.Warningf("%s", info)
+	}
+	if w.reported && info == w.lastInfo {
+		return nil
+	}
+	data := make(map[string]interface{})
+	for path, mib := range available {
+		data[path] = mib
+	}
+	err = w.config.StatusSetter.SetStatus(params.StatusStarted, info, map[string]interface{}{
+		"disk-space-available": data,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot report disk space")
+	}
+	w.reported = true
+	w.lastInfo = info
+	return nil
+}
+
+// measure returns the space available to each path, in MiB, and the
+// sorted paths that are low on space.
+func (w *diskSpaceWorker) measure() (map[string]uint64, []string, error) {
+	available := make(map[string]uint64)
+	var low []string
+	for _, path := range w.config.Paths {
+		bytes, err := availableSpace(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot determine space available in %q", path)
+		}
+		available[path] = bytes / bytesInMiB
+		if available[path] < w.config.Threshold {
+			low = append(low, path)
+		}
+	}
+	sort.Strings(low)
+	return available, low, nil
+}
+
+// cleanup runs the cleanups. Failures are logged rather than returned,
+// so that one failing cleanup does not prevent the others from running.
+func (w *diskSpaceWorker) cleanup() {
+	for _, cleanup := range w.config.Cleanups {
+		logger.Infof("running %s cleanup", cleanup.Name)
+		if err := cleanup.Run(); err != nil {
+			logger.Errorf("%s cleanup failed: %v", cleanup.Name, err)
+		}
+	}
+}
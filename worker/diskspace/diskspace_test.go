@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspace_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskspace"
+)
+
+var _ = gc.Suite(&DiskSpaceWorkerSuite{})
+
+type DiskSpaceWorkerSuite struct {
+	coretesting.BaseSuite
+	available map[string]uint64
+}
+
+func (s *DiskSpaceWorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.available = map[string]uint64{
+		"/var/lib/juju": 3 * 1024 * 1024 * 1024,
+		"/var/log/juju": 2 * 1024 * 1024 * 1024,
+	}
+	s.PatchValue(diskspace.AvailableSpace, func(path string) (uint64, error) {
+		available, ok := s.available[path]
+		if !ok {
+			return 0, os.ErrNotExist
+		}
+		return available, nil
+	})
+}
+
+type statusSetterFunc func(params.Status, string, map[string]interface{}) error
+
+func (f statusSetterFunc) SetStatus(status params.Status, info string, data map[string]interface{}) error {
+	return f(status, info, data)
+}
+
+type statusReport struct {
+	status params.Status
+	info   string
+	data   map[string]interface{}
+}
+
+func recordingSetter(reports *[]statusReport) diskspace.StatusSetter {
+	return statusSetterFunc(func(status params.Status, info string, data map[string]interface{}) error {
+		*reports = append(*reports, statusReport{status, info, data})
+		return nil
+	})
+}
+
+func (s *DiskSpaceWorkerSuite) config(setter diskspace.StatusSetter) diskspace.Config {
+	return diskspace.Config{
+		StatusSetter: setter,
+		Paths:        []string{"/var/lib/juju", "/var/log/juju", "/var/lib/juju/db"},
+		Threshold:    1024,
+	}
+}
+
+func (s *DiskSpaceWorkerSuite) TestWorker(c *gc.C) {
+	s.PatchValue(diskspace.CheckPeriod, time.Millisecond)
+	reports := make(chan string, 10)
+	setter := statusSetterFunc(func(status params.Status, info string, data map[string]interface{}) error {
+		reports <- info
+		return nil
+	})
+
+	w, err := diskspace.NewWorker(s.config(setter))
+	c.Assert(err, jc.ErrorIsNil)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case info := <-reports:
+		c.Check(info, gc.Equals, "")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for disk space report")
+	}
+	// The status is not set again while nothing changes.
+	select {
+	case info := <-reports:
+		c.Fatalf("unexpected disk space report %q", info)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *DiskSpaceWorkerSuite) TestCheckReportsChanges(c *gc.C) {
+	var reports []statusReport
+	check := diskspace.NewCheck(s.config(recordingSetter(&reports)))
+
+	err := check()
+	c.Assert(err, jc.ErrorIsNil)
+	s.available["/var/log/juju"] = 100 * 1024 * 1024
+	err = check()
+	c.Assert(err, jc.ErrorIsNil)
+	err = check()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(reports, jc.DeepEquals, []statusReport{{
+		status: params.StatusStarted,
+		data: map[string]interface{}{
+			"disk-space-available": map[string]interface{}{
+				"/var/lib/juju": uint64(3072),
+				"/var/log/juju": uint64(2048),
+			},
+		},
+	}, {
+		status: params.StatusStarted,
+		info:   "low disk space: /var/log/juju (100MiB available)",
+		data: map[string]interface{}{
+			"disk-space-available": map[string]interface{}{
+				"/var/lib/juju": uint64(3072),
+				"/var/log/juju": uint64(100),
+			},
+		},
+	}})
+}
+
+func (s *DiskSpaceWorkerSuite) TestCheckRunsCleanups(c *gc.C) {
+	s.available["/var/lib/juju"] = 100 * 1024 * 1024
+	var ran []string
+	var reports []statusReport
+	config := s.config(recordingSetter(&reports))
+	config.Cleanups = []diskspace.Cleanup{{
+		Name: "failing",
+		Run: func() error {
+			ran = append(ran, "failing")
+			return errors.New("splat")
+		},
+	}, {
+		Name: "freeing",
+		Run: func() error {
+			ran = append(ran, "freeing")
+			s.available["/var/lib/juju"] = 2 * 1024 * 1024 * 1024
+			return nil
+		},
+	}}
+
+	err := diskspace.NewCheck(config)()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ran, jc.DeepEquals, []string{"failing", "freeing"})
+	c.Assert(reports, gc.HasLen, 1)
+	c.Assert(reports[0].info, gc.Equals, "")
+}
+
+func (s *DiskSpaceWorkerSuite) TestCheckErrors(c *gc.C) {
+	setter := statusSetterFunc(func(params.Status, string, map[string]interface{}) error {
+		return errors.New("splat")
+	})
+	err := diskspace.NewCheck(s.config(setter))()
+	c.Assert(err, gc.ErrorMatches, "cannot report disk space: splat")
+
+	s.PatchValue(diskspace.AvailableSpace, func(string) (uint64, error) {
+		return 0, errors.New("no disk")
+	})
+	err = diskspace.NewCheck(s.config(setter))()
+	c.Assert(err, gc.ErrorMatches, `cannot determine space available in "/var/lib/juju": no disk`)
+}
+
+func (s *DiskSpaceWorkerSuite) TestNewWorkerValidatesConfig(c *gc.C) {
+	config := s.config(nil)
+	_, err := diskspace.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "missing StatusSetter not valid")
+
+	config = s.config(statusSetterFunc(nil))
+	config.Paths = nil
+	_, err = diskspace.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "missing Paths not valid")
+
+	config = s.config(statusSetterFunc(nil))
+	config.Threshold = 0
+	_, err = diskspace.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "zero Threshold not valid")
+}
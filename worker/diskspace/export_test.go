@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskspace
+
+var (
+	CheckPeriod    = &checkPeriod
+	AvailableSpace = &availableSpace
+)
+
+// NewCheck returns a function that runs the checks of a worker with
+// the given config, one at a time.
+func NewCheck(config Config) func() error {
+	w := &diskSpaceWorker{config: config}
+	return w.check
+}
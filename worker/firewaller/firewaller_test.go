@@ -191,12 +191,40 @@ func (s *InstanceModeSuite) TestExposedService(c *gc.C) {
 	err = u.OpenPort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 90, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 90, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	err = u.ClosePorts("tcp", 80, 90)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{8080, 8080, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{8080, 8080, "tcp", ""}})
+}
+
+func (s *InstanceModeSuite) TestExposedServiceWithSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+
+	err = u.OpenPortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPortsFrom("tcp", 443, 443, "192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{
+		{443, 443, "tcp", "10.0.0.0/8"},
+		{443, 443, "tcp", "192.168.0.0/16"},
+	})
+
+	err = u.ClosePortsFrom("tcp", 443, 443, "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{443, 443, "tcp", "192.168.0.0/16"}})
 }
 
 func (s *InstanceModeSuite) TestMultipleExposedServices(c *gc.C) {
@@ -225,15 +253,15 @@ func (s *InstanceModeSuite) TestMultipleExposedServices(c *gc.C) {
 	err = u2.OpenPort("tcp", 3306)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{3306, 3306, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{3306, 3306, "tcp", ""}})
 
 	err = u1.ClosePort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 	err = u2.ClosePort("tcp", 3306)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{8080, 8080, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{8080, 8080, "tcp", ""}})
 	s.assertPorts(c, inst2, m2.Id(), nil)
 }
 
@@ -254,12 +282,12 @@ func (s *InstanceModeSuite) TestMachineWithoutInstanceId(c *gc.C) {
 	inst2 := s.startInstance(c, m2)
 	err = u2.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	inst1 := s.startInstance(c, m1)
 	err = u1.OpenPort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{8080, 8080, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{8080, 8080, "tcp", ""}})
 }
 
 func (s *InstanceModeSuite) TestMultipleUnits(c *gc.C) {
@@ -281,8 +309,8 @@ func (s *InstanceModeSuite) TestMultipleUnits(c *gc.C) {
 	err = u2.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp"}})
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp", ""}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	err = u1.ClosePort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
@@ -313,7 +341,7 @@ func (s *InstanceModeSuite) TestStartWithState(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertKillAndWait(c, fw)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	err = svc.SetExposed()
 	c.Assert(err, jc.ErrorIsNil)
@@ -343,7 +371,7 @@ func (s *InstanceModeSuite) TestStartWithPartialState(c *gc.C) {
 	err = u.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 }
 
 func (s *InstanceModeSuite) TestStartWithUnexposedService(c *gc.C) {
@@ -369,7 +397,7 @@ func (s *InstanceModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	// Expose service.
 	err = svc.SetExposed()
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 }
 
 func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
@@ -393,7 +421,7 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	err = svc.SetExposed()
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// ClearExposed closes the ports again.
 	err = svc.ClearExposed()
@@ -421,8 +449,8 @@ func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	err = u2.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp"}})
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp", ""}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	// Remove unit.
 	err = u1.EnsureDead()
@@ -431,7 +459,7 @@ func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.assertPorts(c, inst1, m1.Id(), nil)
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 }
 
 func (s *InstanceModeSuite) TestRemoveService(c *gc.C) {
@@ -448,7 +476,7 @@ func (s *InstanceModeSuite) TestRemoveService(c *gc.C) {
 	err = u.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	// Remove service.
 	err = u.EnsureDead()
@@ -483,8 +511,8 @@ func (s *InstanceModeSuite) TestRemoveMultipleServices(c *gc.C) {
 	err = u2.OpenPort("tcp", 3306)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp"}})
-	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{3306, 3306, "tcp"}})
+	s.assertPorts(c, inst1, m1.Id(), []network.PortRange{{80, 80, "tcp", ""}})
+	s.assertPorts(c, inst2, m2.Id(), []network.PortRange{{3306, 3306, "tcp", ""}})
 
 	// Remove services.
 	err = u2.EnsureDead()
@@ -519,7 +547,7 @@ func (s *InstanceModeSuite) TestDeadMachine(c *gc.C) {
 	err = u.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	// Remove unit and service, also tested without. Has no effect.
 	err = u.EnsureDead()
@@ -552,7 +580,7 @@ func (s *InstanceModeSuite) TestRemoveMachine(c *gc.C) {
 	err = u.OpenPort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp", ""}})
 
 	// Remove unit.
 	err = u.EnsureDead()
@@ -618,17 +646,17 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	err = u2.OpenPorts("tcp", 80, 90)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Closing a port opened by a different unit won't touch the environment.
 	err = u1.ClosePorts("tcp", 80, 90)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Closing a port used just once changes the environment.
 	err = u1.ClosePort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp", ""}})
 
 	// Closing the last port also modifies the environment.
 	err = u2.ClosePorts("tcp", 80, 90)
@@ -659,7 +687,7 @@ func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	// Expose service.
 	err = svc.SetExposed()
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}})
 }
 
 func (s *GlobalModeSuite) TestRestart(c *gc.C) {
@@ -678,7 +706,7 @@ func (s *GlobalModeSuite) TestRestart(c *gc.C) {
 	err = u.OpenPort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Stop firewaller and close one and open a different port.
 	err = worker.Stop(fw)
@@ -694,7 +722,7 @@ func (s *GlobalModeSuite) TestRestart(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertKillAndWait(c, fw)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}, {8888, 8888, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp", ""}, {8888, 8888, "tcp", ""}})
 }
 
 func (s *GlobalModeSuite) TestRestartUnexposedService(c *gc.C) {
@@ -713,7 +741,7 @@ func (s *GlobalModeSuite) TestRestartUnexposedService(c *gc.C) {
 	err = u.OpenPort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Stop firewaller and clear exposed flag on service.
 	err = worker.Stop(fw)
@@ -746,7 +774,7 @@ func (s *GlobalModeSuite) TestRestartPortCount(c *gc.C) {
 	err = u1.OpenPort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Stop firewaller and add another service using the port.
 	err = worker.Stop(fw)
@@ -766,17 +794,17 @@ func (s *GlobalModeSuite) TestRestartPortCount(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertKillAndWait(c, fw)
 
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Closing a port opened by a different unit won't touch the environment.
 	err = u1.ClosePort("tcp", 80)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}, {8080, 8080, "tcp", ""}})
 
 	// Closing a port used just once changes the environment.
 	err = u1.ClosePort("tcp", 8080)
 	c.Assert(err, jc.ErrorIsNil)
-	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}})
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp", ""}})
 
 	// Closing the last port also modifies the environment.
 	err = u2.ClosePort("tcp", 80)
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hareplacer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.hareplacer")
+
+// ReplaceParams specifies how unavailable state servers are replaced.
+type ReplaceParams struct {
+	// CheckInterval is the time between checks of the state
+	// servers' availability.
+	CheckInterval time.Duration
+
+	// GracePeriod is the time a state server must have been
+	// unavailable before it is replaced.
+	GracePeriod time.Duration
+}
+
+const DefaultCheckInterval = time.Minute
+const DefaultGracePeriod = 5 * time.Minute
+
+// NewReplaceParams returns a ReplaceParams initialised with default
+// values.
+func NewReplaceParams() *ReplaceParams {
+	return &ReplaceParams{
+		CheckInterval: DefaultCheckInterval,
+		GracePeriod:   DefaultGracePeriod,
+	}
+}
+
+// State defines the state methods used by the worker.
+type State interface {
+	AvailabilityPolicy() (state.AvailabilityPolicy, error)
+	UnavailableStateServers() ([]string, error)
+	EnsureAvailability(
+		numStateServers int, cons constraints.Value, series string, placement []string,
+	) (state.StateServersChanges, error)
+}
+
+// New returns a worker which periodically checks the availability of
+// the state servers and, if the availability policy asks for it,
+// replaces those that have been unavailable for longer than the grace
+// period by calling EnsureAvailability as it was last requested. The
+// new machines are provisioned and added to the replica set, and the
+// API addresses updated, by the workers that do so for any state
+// server. This worker is intended to run just once, on the MongoDB
+// master.
+func New(st State, params *ReplaceParams) worker.Worker {
+	w := &replaceWorker{
+		st:          st,
+		params:      params,
+		unavailable: make(map[string]time.Time),
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type replaceWorker struct {
+	st     State
+	params *ReplaceParams
+
+	// unavailable records when each unavailable state server
+	// was first seen to be unavailable.
+	unavailable map[string]time.Time
+}
+
+func (w *replaceWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.params.CheckInterval):
+			if err := w.check(time.Now()); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// check replaces the state servers that have been unavailable
+// for longer than the grace period, if the policy allows.
+func (w *replaceWorker) check(now time.Time) error {
+	policy, err := w.st.AvailabilityPolicy()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !policy.AutoReplace {
+		w.unavailable = make(map[string]time.Time)
+		return nil
+	}
+	ids, err := w.st.UnavailableStateServers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unavailable := make(map[string]time.Time)
+	replace := false
+	for _, id := range ids {
+		since, ok := w.unavailable[id]
+		if !ok {
+			logger.Infof("state server machine %s is unavailable", id)
+			since = now
+		}
+		unavailable[id] = since
+		if now.Sub(since) >= w.params.GracePeriod {
+			replace = true
+		}
+	}
+	w.unavailable = unavailable
+	if !replace {
+		return nil
+	}
+	logger.Infof("replacing unavailable state server machines %v", ids)
+	changes, err := w.st.EnsureAvailability(policy.NumStateServers, policy.Constraints, policy.Series, nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot replace state servers")
+	}
+	logger.Infof("added state server machines %v; demoted %v", changes.Added, changes.Demoted)
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hareplacer_test
+
+import (
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/hareplacer"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type suite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&suite{})
+
+type ensureArgs struct {
+	numStateServers int
+	cons            constraints.Value
+	series          string
+}
+
+type mockState struct {
+	mu          sync.Mutex
+	policy      state.AvailabilityPolicy
+	unavailable []string
+	ensured     chan ensureArgs
+}
+
+func (st *mockState) AvailabilityPolicy() (state.AvailabilityPolicy, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.policy, nil
+}
+
+func (st *mockState) UnavailableStateServers() ([]string, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.unavailable, nil
+}
+
+func (st *mockState) setUnavailable(ids ...string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.unavailable = ids
+}
+
+func (st *mockState) EnsureAvailability(
+	numStateServers int, cons constraints.Value, series string, placement []string,
+) (state.StateServersChanges, error) {
+	st.ensured <- ensureArgs{numStateServers, cons, series}
+	// The replaced machines no longer want a vote.
+	st.setUnavailable()
+	return state.StateServersChanges{}, nil
+}
+
+func newMockState(autoReplace bool) *mockState {
+	return &mockState{
+		policy: state.AvailabilityPolicy{
+			AutoReplace:     autoReplace,
+			NumStateServers: 3,
+			Constraints:     constraints.MustParse("mem=4G"),
+			Series:          "trusty",
+		},
+		ensured: make(chan ensureArgs, 10),
+	}
+}
+
+func (s *suite) startWorker(c *gc.C, st *mockState, gracePeriod time.Duration) {
+	w := hareplacer.New(st, &hareplacer.ReplaceParams{
+		CheckInterval: time.Millisecond,
+		GracePeriod:   gracePeriod,
+	})
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) TestReplacesUnavailable(c *gc.C) {
+	st := newMockState(true)
+	st.setUnavailable("1")
+	s.startWorker(c, st, 50*time.Millisecond)
+
+	select {
+	case args := <-st.ensured:
+		c.Assert(args, jc.DeepEquals, ensureArgs{3, constraints.MustParse("mem=4G"), "trusty"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for state servers to be replaced")
+	}
+	// Once replaced, nothing more is done.
+	select {
+	case <-st.ensured:
+		c.Fatalf("unexpected replacement")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *suite) TestWaitsForGracePeriod(c *gc.C) {
+	st := newMockState(true)
+	st.setUnavailable("1")
+	s.startWorker(c, st, time.Hour)
+
+	select {
+	case <-st.ensured:
+		c.Fatalf("unexpected replacement")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *suite) TestNoAutoReplace(c *gc.C) {
+	st := newMockState(false)
+	st.setUnavailable("1")
+	s.startWorker(c, st, 0)
+
+	select {
+	case <-st.ensured:
+		c.Fatalf("unexpected replacement")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
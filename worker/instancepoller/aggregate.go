@@ -22,6 +22,11 @@ type aggregator struct {
 	environ instanceGetter
 	reqc    chan instanceInfoReq
 	tomb    tomb.Tomb
+
+	// backoff holds the extra time to wait before the next call to
+	// the provider; it grows while the provider keeps failing, for
+	// example because the environment exceeds its API rate limits.
+	backoff time.Duration
 }
 
 func newAggregator(env instanceGetter) *aggregator {
@@ -56,7 +61,26 @@ func (a *aggregator) instanceInfo(id instance.Id) (instanceInfo, error) {
 	return r.info, r.err
 }
 
-var gatherTime = 3 * time.Second
+var (
+	gatherTime = 3 * time.Second
+
+	// maxProviderBackoff is the longest the aggregator waits before
+	// calling a failing provider again.
+	maxProviderBackoff = 5 * time.Minute
+)
+
+// nextBackoff returns the time to wait before calling a provider that
+// failed after the given backoff.
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return gatherTime
+	}
+	backoff *= 2
+	if backoff > maxProviderBackoff {
+		backoff = maxProviderBackoff
+	}
+	return backoff
+}
 
 func (a *aggregator) loop() error {
 	timer := time.NewTimer(0)
@@ -71,7 +95,7 @@ func (a *aggregator) loop() error {
 			return tomb.ErrDying
 		case req := <-a.reqc:
 			if len(reqs) == 0 {
-				waitTime := bucket.Take(1)
+				waitTime := bucket.Take(1) + a.backoff
 				timer.Reset(waitTime)
 			}
 			reqs = append(reqs, req)
@@ -81,6 +105,12 @@ func (a *aggregator) loop() error {
 				ids[i] = req.instId
 			}
 			insts, err := a.environ.Instances(ids)
+			if err != nil && err != environs.ErrPartialInstances {
+				a.backoff = nextBackoff(a.backoff)
+				logger.Warningf("cannot get instances from the provider, waiting %v before the next attempt: %v", a.backoff, err)
+			} else {
+				a.backoff = 0
+			}
 			for i, req := range reqs {
 				var reply instanceInfoReply
 				if err != nil && err != environs.ErrPartialInstances {
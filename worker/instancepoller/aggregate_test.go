@@ -198,6 +198,32 @@ func (s *aggregateSuite) TestError(c *gc.C) {
 	c.Assert(err, gc.Equals, ourError)
 }
 
+func (s *aggregateSuite) TestBackoffAfterError(c *gc.C) {
+	s.PatchValue(&gatherTime, 10*time.Millisecond)
+	s.PatchValue(&maxProviderBackoff, 30*time.Millisecond)
+	testGetter := new(testInstanceGetter)
+	testGetter.newTestInstance("foo", "foobar", []string{"127.0.0.1"})
+	testGetter.err = fmt.Errorf("Request limit exceeded")
+
+	aggregator := newAggregator(testGetter)
+	for _, expect := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		30 * time.Millisecond,
+		30 * time.Millisecond,
+	} {
+		_, err := aggregator.instanceInfo("foo")
+		c.Assert(err, gc.ErrorMatches, "Request limit exceeded")
+		c.Assert(aggregator.backoff, gc.Equals, expect)
+	}
+
+	// A successful call resets the backoff.
+	testGetter.err = nil
+	_, err := aggregator.instanceInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(aggregator.backoff, gc.Equals, time.Duration(0))
+}
+
 func (s *aggregateSuite) TestPartialErrResponse(c *gc.C) {
 	testGetter := new(testInstanceGetter)
 	testGetter.err = environs.ErrPartialInstances
@@ -145,6 +145,38 @@ func countPolls(c *gc.C, addrs []network.Address, instId, instStatus string, mac
 	return int(count)
 }
 
+func (s *machineSuite) TestPollIntervalsFromContext(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.LongWait)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	count := int32(0)
+	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
+		atomic.AddInt32(&count, 1)
+		return instanceInfo{testAddrs, "running"}, nil
+	}
+	context := &testMachineContext{
+		getInstanceInfo: getInstanceInfo,
+		dyingc:          make(chan struct{}),
+		shortPoll:       coretesting.LongWait,
+		longPoll:        1 * time.Millisecond,
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		refresh:    func() error { return nil },
+		addresses:  testAddrs,
+		life:       state.Alive,
+		status:     state.StatusStarted,
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+
+	time.Sleep(coretesting.ShortWait)
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(atomic.LoadInt32(&count), jc.GreaterThan, int32(2))
+}
+
 func (s *machineSuite) TestSinglePollWhenInstancInfoUnimplemented(c *gc.C) {
 	s.PatchValue(&ShortPoll, 1*time.Millisecond)
 	s.PatchValue(&LongPoll, 1*time.Millisecond)
@@ -303,6 +335,10 @@ type testMachineContext struct {
 	killAllErr      error
 	getInstanceInfo func(instance.Id) (instanceInfo, error)
 	dyingc          chan struct{}
+
+	// shortPoll and longPoll, when set, override ShortPoll and
+	// LongPoll.
+	shortPoll, longPoll time.Duration
 }
 
 func (context *testMachineContext) killAll(err error) {
@@ -320,6 +356,17 @@ func (context *testMachineContext) dying() <-chan struct{} {
 	return context.dyingc
 }
 
+func (context *testMachineContext) pollIntervals() (short, long time.Duration) {
+	short, long = ShortPoll, LongPoll
+	if context.shortPoll > 0 {
+		short = context.shortPoll
+	}
+	if context.longPoll > 0 {
+		long = context.longPoll
+	}
+	return short, long
+}
+
 type testMachine struct {
 	instanceId      instance.Id
 	instanceIdErr   error
@@ -18,13 +18,17 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.instanceupdater")
 
-// ShortPoll and LongPoll hold the polling intervals for the instance
-// updater. When a machine has no address or is not started, it will be
-// polled at ShortPoll intervals until it does, exponentially backing off
-// with an exponent of ShortPollBackoff until a maximum(ish) of LongPoll.
+// ShortPoll and LongPoll hold the default polling intervals for the
+// instance updater. When a machine has no address or is not started, it
+// will be polled at ShortPoll intervals until it does, exponentially
+// backing off with an exponent of ShortPollBackoff until a maximum(ish)
+// of LongPoll.
 //
 // When a machine has an address and is started LongPoll will be used to
 // check that the instance address or status has not changed.
+//
+// The intervals can be overridden with the instance-poll-short-interval
+// and instance-poll-long-interval environment settings.
 var (
 	ShortPoll        = 1 * time.Second
 	ShortPollBackoff = 2.0
@@ -54,6 +58,7 @@ type machineContext interface {
 	killAll(err error)
 	instanceInfo(id instance.Id) (instanceInfo, error)
 	dying() <-chan struct{}
+	pollIntervals() (short, long time.Duration)
 }
 
 type machineAddress struct {
@@ -175,10 +180,13 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 	// Use a short poll interval when initially waiting for
 	// a machine's address and machine agent to start, and a long one when it already
 	// has an address and the machine agent is started.
-	pollInterval := ShortPoll
+	pollInterval, _ := context.pollIntervals()
 	pollInstance := true
 	for {
 		if pollInstance {
+			// The intervals are read each time, so that changes to
+			// the environment settings take effect without a restart.
+			_, longPoll := context.pollIntervals()
 			instInfo, err := pollInstanceInfo(context, m)
 			if err != nil && !errors.IsNotProvisioned(err) {
 				// If the provider doesn't implement Addresses/Status now,
@@ -202,8 +210,8 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 			}
 			if len(instInfo.addresses) > 0 && instInfo.status != "" && machineStatus == state.StatusStarted {
 				// We've got at least one address and a status and instance is started, so poll infrequently.
-				pollInterval = LongPoll
-			} else if pollInterval < LongPoll {
+				pollInterval = longPoll
+			} else if pollInterval < longPoll {
 				// We have no addresses or not started - poll increasingly rarely
 				// until we do.
 				pollInterval = time.Duration(float64(pollInterval) * ShortPollBackoff)
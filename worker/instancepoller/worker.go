@@ -4,6 +4,8 @@
 package instancepoller
 
 import (
+	"time"
+
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/state"
@@ -72,3 +74,17 @@ func (u *updaterWorker) dying() <-chan struct{} {
 func (u *updaterWorker) killAll(err error) {
 	u.tomb.Kill(err)
 }
+
+// pollIntervals returns the intervals set in the environment
+// configuration, or the defaults for those that are not set.
+func (u *updaterWorker) pollIntervals() (short, long time.Duration) {
+	opts := u.observer.Environ().Config().InstancePollOpts()
+	short, long = ShortPoll, LongPoll
+	if opts.ShortInterval > 0 {
+		short = opts.ShortInterval
+	}
+	if opts.LongInterval > 0 {
+		long = opts.LongInterval
+	}
+	return short, long
+}
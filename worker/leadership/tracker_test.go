@@ -343,6 +343,81 @@ func (s *TrackerSuite) TestWaitLeaderNeverBecomeLeader(c *gc.C) {
 	}})
 }
 
+func (s *TrackerSuite) TestWaitMinionAlreadyMinion(c *gc.C) {
+	s.manager.Stub.Errors = []error{coreleadership.ErrClaimDenied, nil}
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Check the ticket succeeds.
+	assertWaitMinion(c, tracker, true)
+
+	// Stop the tracker before trying to look at its stub.
+	assertStop(c, tracker)
+
+	// Unblock the release goroutine, lest data races.
+	s.unblockRelease(c)
+
+	s.manager.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "BlockUntilLeadershipReleased",
+		Args: []interface{}{
+			"led-service",
+		},
+	}})
+}
+
+func (s *TrackerSuite) TestWaitMinionBecomeMinion(c *gc.C) {
+	s.manager.Stub.Errors = []error{nil, coreleadership.ErrClaimDenied, nil}
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Check that a ticket requested while leader succeeds once the
+	// refresh triggers ErrClaimDenied.
+	ticket := tracker.WaitMinion()
+	assertTicket(c, ticket, true)
+	assertTicket(c, ticket, true)
+
+	// Stop the tracker before trying to look at its stub.
+	assertStop(c, tracker)
+
+	// Unblock the release goroutine, lest data races.
+	s.unblockRelease(c)
+
+	s.manager.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "BlockUntilLeadershipReleased",
+		Args: []interface{}{
+			"led-service",
+		},
+	}})
+}
+
+func (s *TrackerSuite) TestWaitMinionNeverBecomeMinion(c *gc.C) {
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Get a ticket and stop the tracker while it's pending.
+	ticket := tracker.WaitMinion()
+	assertStop(c, tracker)
+
+	// Check the ticket got closed without sending true.
+	assertTicket(c, ticket, false)
+	assertTicket(c, ticket, false)
+}
+
 func assertClaimLeader(c *gc.C, tracker leadership.Tracker, expect bool) {
 	// Grab a ticket...
 	ticket := tracker.ClaimLeader()
@@ -366,6 +441,20 @@ func assertWaitLeader(c *gc.C, tracker leadership.Tracker, expect bool) {
 	}
 }
 
+func assertWaitMinion(c *gc.C, tracker leadership.Tracker, expect bool) {
+	ticket := tracker.WaitMinion()
+	if expect {
+		assertTicket(c, ticket, true)
+		assertTicket(c, ticket, true)
+		return
+	}
+	select {
+	case <-time.After(coretesting.ShortWait):
+	case <-ticket.Ready():
+		c.Fatalf("got unexpected readiness: %v", ticket.Wait())
+	}
+}
+
 func assertTicket(c *gc.C, ticket leadership.Ticket, expect bool) {
 	// Wait for the ticket to give a value...
 	select {
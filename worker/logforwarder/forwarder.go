@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// maxBatchSize holds the largest number of records sent to a
+	// target at once.
+	maxBatchSize = 100
+
+	// maxRetryDelay holds the longest time a forwarder waits before
+	// retrying a target it failed to send to.
+	maxRetryDelay = 5 * time.Minute
+)
+
+// retryDelay holds how long a forwarder first waits before retrying a
+// target it failed to send to; the delay doubles after each failure.
+var retryDelay = 5 * time.Second
+
+// forwarder sends the log records that match a target's filters to
+// the target, reconnecting to it when sending fails. Records are read
+// from the database as they are sent, so none are lost while the
+// target is unavailable.
+type forwarder struct {
+	tomb      tomb.Tomb
+	envUUID   string
+	target    config.LogForwardTarget
+	tlsConfig *tls.Config
+	tailer    *state.LogTailer
+}
+
+func newForwarder(st *state.State, target config.LogForwardTarget, tlsConfig *tls.Config) *forwarder {
+	f := &forwarder{
+		envUUID:   st.EnvironUUID(),
+		target:    target,
+		tlsConfig: tlsConfig,
+		tailer: state.NewLogTailer(st, state.LogTailerParams{
+			MinLevel:      target.MinLevel,
+			IncludeEntity: target.IncludeEntity,
+			ExcludeEntity: target.ExcludeEntity,
+			IncludeModule: target.IncludeModule,
+			ExcludeModule: target.ExcludeModule,
+		}),
+	}
+	go func() {
+		defer f.tomb.Done()
+		defer f.tailer.Stop()
+		f.tomb.Kill(f.loop())
+	}()
+	return f
+}
+
+// Kill is part of the worker.Worker interface.
+func (f *forwarder) Kill() {
+	f.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (f *forwarder) Wait() error {
+	return f.tomb.Wait()
+}
+
+func (f *forwarder) loop() error {
+	var sender Sender
+	defer func() {
+		if sender != nil {
+			sender.Close()
+		}
+	}()
+	delay := retryDelay
+	for {
+		batch, err := f.nextBatch()
+		if err != nil {
+			return err
+		}
+		for {
+			if sender == nil {
+				sender, err = dial(f.target, f.tlsConfig, f.envUUID)
+			}
+			if err == nil {
+				if err = sender.Send(batch); err == nil {
+					delay = retryDelay
+					break
+				}
+				sender.Close()
+				sender = nil
+			}
+			logger.Warningf("cannot forward logs to %s (retrying in %v): %v", f.target.URL, delay, err)
+			select {
+			case <-f.tomb.Dying():
+				return tomb.ErrDying
+			case <-time.After(delay):
+			}
+			if delay *= 2; delay > maxRetryDelay {
+				delay = maxRetryDelay
+			}
+		}
+	}
+}
+
+// nextBatch waits for a log record, and returns it along with any
+// others that are ready to be sent.
+func (f *forwarder) nextBatch() ([]*state.LogRecord, error) {
+	var batch []*state.LogRecord
+	select {
+	case <-f.tomb.Dying():
+		return nil, tomb.ErrDying
+	case rec, ok := <-f.tailer.Logs():
+		if !ok {
+			if err := f.tailer.Err(); err != nil {
+				return nil, errors.Annotate(err, "cannot read logs")
+			}
+			return nil, errors.New("log tailer stopped")
+		}
+		batch = append(batch, rec)
+	}
+	for len(batch) < maxBatchSize {
+		select {
+		case rec, ok := <-f.tailer.Logs():
+			if !ok {
+				// The error is returned with the next batch.
+				return batch, nil
+			}
+			batch = append(batch, rec)
+		default:
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
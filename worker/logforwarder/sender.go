@@ -0,0 +1,187 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// sendTimeout holds how long connecting to a target, or sending a
+// batch of records to it, may take.
+const sendTimeout = 30 * time.Second
+
+// Sender sends log records to a target.
+type Sender interface {
+	// Send sends the given records, oldest first.
+	Send(records []*state.LogRecord) error
+
+	// Close releases the connection to the target.
+	Close() error
+}
+
+// dial returns a Sender for the given target, which forwards the logs
+// of the environment with the given UUID.
+func dial(target config.LogForwardTarget, tlsConfig *tls.Config, envUUID string) (Sender, error) {
+	switch target.URL.Scheme {
+	case config.LogForwardSyslogScheme:
+		return dialSyslog(target.URL.Host, tlsConfig, envUUID)
+	case "http", "https":
+		return newHTTPSender(target.URL.String(), tlsConfig, envUUID), nil
+	}
+	return nil, errors.Errorf("unsupported scheme %q", target.URL.Scheme)
+}
+
+// syslogFacility holds the facility of the forwarded syslog messages,
+// which is user-level messages.
+const syslogFacility = 1
+
+// syslogSender sends records to a syslog server as RFC 5424 messages,
+// framed with octet counting over TLS as described by RFC 5425.
+type syslogSender struct {
+	conn    net.Conn
+	appName string
+}
+
+func dialSyslog(addr string, tlsConfig *tls.Config, envUUID string) (Sender, error) {
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to %s", addr)
+	}
+	return &syslogSender{
+		conn:    conn,
+		appName: "juju-" + envUUID,
+	}, nil
+}
+
+// Send is part of the Sender interface.
+func (s *syslogSender) Send(records []*state.LogRecord) error {
+	var buf bytes.Buffer
+	for _, rec := range records {
+		msg := formatSyslogMessage(rec, s.appName)
+		fmt.Fprintf(&buf, "%d %s", len(msg), msg)
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(sendTimeout)); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := s.conn.Write(buf.Bytes())
+	return errors.Trace(err)
+}
+
+// Close is part of the Sender interface.
+func (s *syslogSender) Close() error {
+	return s.conn.Close()
+}
+
+// formatSyslogMessage returns the RFC 5424 message for the given
+// record. The entity that logged the record is given as the hostname,
+// and the logging module and location prefix the message.
+func formatSyslogMessage(rec *state.LogRecord, appName string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s - - - %s %s %s",
+		syslogFacility*8+syslogSeverity(rec.Level),
+		rec.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		rec.Entity,
+		appName,
+		rec.Module,
+		rec.Location,
+		rec.Message,
+	)
+}
+
+// syslogSeverity returns the syslog severity of the given log level.
+func syslogSeverity(level loggo.Level) int {
+	switch level {
+	case loggo.CRITICAL:
+		return 2
+	case loggo.ERROR:
+		return 3
+	case loggo.WARNING:
+		return 4
+	case loggo.INFO:
+		return 6
+	case loggo.DEBUG, loggo.TRACE:
+		return 7
+	}
+	// Notice.
+	return 5
+}
+
+// httpSender posts records to an HTTP endpoint as newline-delimited
+// JSON documents, as accepted by log collectors such as Logstash.
+type httpSender struct {
+	url     string
+	client  *http.Client
+	envUUID string
+}
+
+func newHTTPSender(url string, tlsConfig *tls.Config, envUUID string) Sender {
+	return &httpSender{
+		url: url,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   sendTimeout,
+		},
+		envUUID: envUUID,
+	}
+}
+
+// httpRecord holds the document posted for each log record.
+type httpRecord struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Environment string    `json:"environment"`
+	Entity      string    `json:"entity"`
+	Module      string    `json:"module"`
+	Location    string    `json:"location"`
+	Level       string    `json:"level"`
+	Message     string    `json:"message"`
+}
+
+// Send is part of the Sender interface.
+func (s *httpSender) Send(records []*state.LogRecord) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, rec := range records {
+		err := encoder.Encode(httpRecord{
+			Timestamp:   rec.Time.UTC(),
+			Environment: s.envUUID,
+			Entity:      rec.Entity,
+			Module:      rec.Module,
+			Location:    rec.Location,
+			Level:       rec.Level.String(),
+			Message:     rec.Message,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	resp, err := s.client.Post(s.url, "application/x-ndjson", &buf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("%s returned %q", s.url, resp.Status)
+	}
+	return nil
+}
+
+// Close is part of the Sender interface.
+func (s *httpSender) Close() error {
+	if transport, ok := s.client.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
+}
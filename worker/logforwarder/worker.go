@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logforwarder implements the worker that forwards the logs
+// collected by the state servers to the external targets given by the
+// environment's log-forward-targets setting.
+package logforwarder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.logforwarder")
+
+// New returns a worker which forwards the logs of the environment to
+// each of the targets in its log-forward-targets setting, restarting
+// the forwarders whenever the targets or their CA certificate change.
+// Only records logged after a forwarder starts are forwarded. This
+// worker is intended to run just once, on the MongoDB master.
+func New(st *state.State) worker.Worker {
+	w := &forwardWorker{
+		st: st,
+		runner: worker.NewRunner(
+			func(error) bool { return false },
+			func(err0, err1 error) bool { return true },
+		),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer w.runner.Wait()
+		defer w.runner.Kill()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type forwardWorker struct {
+	tomb   tomb.Tomb
+	st     *state.State
+	runner worker.Runner
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *forwardWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *forwardWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *forwardWorker) loop() error {
+	configWatcher := w.st.WatchForEnvironConfigChanges()
+	defer watcher.Stop(configWatcher, &w.tomb)
+
+	var targets, caCert string
+	var running []string
+	generation := 0
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(configWatcher)
+			}
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			newTargets, _ := cfg.AllAttrs()[config.LogForwardTargetsKey].(string)
+			if newTargets == targets && cfg.LogForwardCACert() == caCert {
+				continue
+			}
+			targets, caCert = newTargets, cfg.LogForwardCACert()
+			for _, id := range running {
+				if err := w.runner.StopWorker(id); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			running = nil
+			tlsConfig, err := newTLSConfig(caCert)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			generation++
+			for i, target := range cfg.LogForwardTargets() {
+				target := target
+				id := fmt.Sprintf("%d-%d %s", generation, i, target.URL)
+				logger.Infof("forwarding logs to %s", target.URL)
+				err := w.runner.StartWorker(id, func() (worker.Worker, error) {
+					return newForwarder(w.st, target, tlsConfig), nil
+				})
+				if err != nil {
+					return errors.Trace(err)
+				}
+				running = append(running, id)
+			}
+		}
+	}
+}
+
+// newTLSConfig returns the TLS configuration used to connect to the
+// targets, which trusts the given CA certificate or, if it is empty,
+// the system's root CAs.
+func newTLSConfig(caCert string) (*tls.Config, error) {
+	if caCert == "" {
+		return &tls.Config{}, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, errors.Errorf("cannot parse %s", config.LogForwardCACertKey)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
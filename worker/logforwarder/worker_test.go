@@ -0,0 +1,199 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type workerSuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.PatchValue(logforwarder.RetryDelay, 10*time.Millisecond)
+}
+
+func (s *workerSuite) startWorker(c *gc.C, attrs map[string]interface{}) {
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	w := logforwarder.New(s.State)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	})
+}
+
+// logUntil writes records at each of INFO and WARNING until done is
+// closed; a forwarder only forwards records logged after it starts.
+func (s *workerSuite) logUntil(c *gc.C, done <-chan struct{}) {
+	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
+	defer dbLogger.Close()
+	timeout := time.After(testing.LongWait)
+	for {
+		err := dbLogger.Log(time.Now(), "juju.worker", "foo.go:1", loggo.INFO, "ignored")
+		c.Assert(err, jc.ErrorIsNil)
+		err = dbLogger.Log(time.Now(), "juju.worker", "foo.go:1", loggo.WARNING, "forwarded")
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case <-done:
+			return
+		case <-timeout:
+			c.Fatalf("timed out waiting for logs to be forwarded")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// httpTarget records the bodies posted to it, failing the first
+// failures requests.
+type httpTarget struct {
+	mu       sync.Mutex
+	failures int
+	bodies   []string
+	received chan struct{}
+}
+
+func (t *httpTarget) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bodies = append(t.bodies, string(body))
+	if len(t.bodies) <= t.failures {
+		http.Error(w, "not now", http.StatusServiceUnavailable)
+		return
+	}
+	if len(t.bodies) == t.failures+1 {
+		close(t.received)
+	}
+}
+
+func (s *workerSuite) TestForwardsToHTTP(c *gc.C) {
+	target := &httpTarget{received: make(chan struct{})}
+	server := httptest.NewServer(target)
+	defer server.Close()
+	s.startWorker(c, map[string]interface{}{
+		"log-forward-targets": server.URL + "/logs?level=WARNING",
+	})
+	s.logUntil(c, target.received)
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	lines := strings.Split(strings.TrimSpace(target.bodies[0]), "\n")
+	for _, line := range lines {
+		c.Assert(line, gc.Matches, fmt.Sprintf(
+			`{"timestamp":".*","environment":"%s","entity":"machine-0","module":"juju.worker","location":"foo.go:1","level":"WARNING","message":"forwarded"}`,
+			s.State.EnvironUUID(),
+		))
+	}
+}
+
+func (s *workerSuite) TestRetriesHTTP(c *gc.C) {
+	target := &httpTarget{failures: 2, received: make(chan struct{})}
+	server := httptest.NewServer(target)
+	defer server.Close()
+	s.startWorker(c, map[string]interface{}{
+		"log-forward-targets": server.URL + "/logs",
+	})
+	s.logUntil(c, target.received)
+
+	// The batch that could not be sent is sent again.
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	c.Assert(len(target.bodies) >= 3, jc.IsTrue)
+	c.Assert(target.bodies[1], gc.Equals, target.bodies[0])
+	c.Assert(target.bodies[2], gc.Equals, target.bodies[0])
+}
+
+func (s *workerSuite) TestForwardsToSyslog(c *gc.C) {
+	srvCert, srvKey, err := cert.NewServer(testing.CACert, testing.CAKey, time.Now().AddDate(1, 0, 0), []string{"127.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	tlsCert, err := tls.X509KeyPair([]byte(srvCert), []byte(srvKey))
+	c.Assert(err, jc.ErrorIsNil)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	messages := make(chan string, 100)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		readSyslogMessages(conn, messages)
+	}()
+	s.startWorker(c, map[string]interface{}{
+		"log-forward-targets": "syslog+tls://" + listener.Addr().String() + "?level=WARNING",
+		"log-forward-ca-cert": testing.CACert,
+	})
+	received := make(chan struct{})
+	var message string
+	go func() {
+		message = <-messages
+		close(received)
+	}()
+	s.logUntil(c, received)
+
+	// WARNING messages have the user facility and warning severity.
+	c.Assert(message, gc.Matches, fmt.Sprintf(
+		`<12>1 \S+ machine-0 juju-%s - - - juju.worker foo.go:1 forwarded`,
+		s.State.EnvironUUID(),
+	))
+}
+
+// readSyslogMessages reads octet-counted syslog messages from r and
+// sends them on messages, until r fails.
+func readSyslogMessages(r io.Reader, messages chan<- string) {
+	reader := bufio.NewReader(r)
+	for {
+		var length int
+		if _, err := fmt.Fscanf(reader, "%d ", &length); err != nil {
+			return
+		}
+		msg := make([]byte, length)
+		if _, err := io.ReadFull(reader, msg); err != nil {
+			return
+		}
+		messages <- string(msg)
+	}
+}
+
+func (s *workerSuite) TestFormatSyslogMessage(c *gc.C) {
+	rec := &state.LogRecord{
+		Time:     time.Date(2015, 6, 1, 12, 30, 0, 500000000, time.UTC),
+		Entity:   "unit-mysql-0",
+		Module:   "juju.worker.uniter",
+		Location: "uniter.go:42",
+		Level:    loggo.ERROR,
+		Message:  "hook failed",
+	}
+	c.Assert(logforwarder.FormatSyslogMessage(rec, "juju-env"), gc.Equals,
+		"<11>1 2015-06-01T12:30:00.500000Z unit-mysql-0 juju-env - - - juju.worker.uniter uniter.go:42 hook failed")
+}
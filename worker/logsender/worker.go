@@ -5,6 +5,7 @@ package logsender
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/juju/errors"
@@ -26,13 +27,17 @@ type LogRecord struct {
 	Location string
 	Level    loggo.Level
 	Message  string
+
+	// DroppedAfter holds the number of log messages that were dropped
+	// before this one because the buffer was full.
+	DroppedAfter int
 }
 
 var logger = loggo.GetLogger("juju.worker.logsender")
 
 // New starts a logsender worker which reads log message structs from
 // a channel and sends them to the JES via the logsink API.
-func New(logs chan *LogRecord, apiInfo *api.Info) worker.Worker {
+func New(logs <-chan *LogRecord, apiInfo *api.Info) worker.Worker {
 	loop := func(stop <-chan struct{}) error {
 		logger.Debugf("starting logsender worker")
 
@@ -45,6 +50,19 @@ func New(logs chan *LogRecord, apiInfo *api.Info) worker.Worker {
 		for {
 			select {
 			case rec := <-logs:
+				if rec.DroppedAfter > 0 {
+					// Record the loss in the logs, so that gaps in
+					// them can be explained.
+					err := websocket.JSON.Send(conn, &apiserver.LogMessage{
+						Time:    rec.Time,
+						Module:  "juju.worker.logsender",
+						Level:   loggo.WARNING,
+						Message: fmt.Sprintf("%d log messages dropped due to lack of API connectivity", rec.DroppedAfter),
+					})
+					if err != nil {
+						return errors.Annotate(err, "logsink connection failed")
+					}
+				}
 				err := websocket.JSON.Send(conn, &apiserver.LogMessage{
 					Time:     rec.Time,
 					Module:   rec.Module,
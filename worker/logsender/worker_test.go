@@ -13,7 +13,6 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/api"
-	"github.com/juju/juju/feature"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -28,7 +27,6 @@ type Suite struct {
 var _ = gc.Suite(&Suite{})
 
 func (s *Suite) SetUpTest(c *gc.C) {
-	s.SetInitialFeatureFlags(feature.DbLog)
 	s.JujuConnSuite.SetUpTest(c)
 
 	// Create a machine for the client to log in as.
@@ -98,3 +96,36 @@ func (s *Suite) TestLogSending(c *gc.C) {
 		c.Assert(doc, gc.DeepEquals, expectedDocs[i])
 	}
 }
+
+func (s *Suite) TestDroppedLogs(c *gc.C) {
+	logsCh := make(chan *logsender.LogRecord, 1)
+	worker := logsender.New(logsCh, s.apiInfo)
+	defer func() {
+		worker.Kill()
+		c.Check(worker.Wait(), jc.ErrorIsNil)
+	}()
+
+	logsCh <- &logsender.LogRecord{
+		Time:         time.Now(),
+		Module:       "logsender-test",
+		Location:     "loc",
+		Level:        loggo.INFO,
+		Message:      "after the gap",
+		DroppedAfter: 42,
+	}
+
+	// Wait for the logs to appear in the database.
+	var docs []bson.M
+	logsColl := s.State.MongoSession().DB("logs").C("logs")
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		err := logsColl.Find(bson.M{"m": bson.M{"$in": []string{"juju.worker.logsender", "logsender-test"}}}).Sort("_id").All(&docs)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(docs) == 2 {
+			break
+		}
+	}
+	c.Assert(docs, gc.HasLen, 2)
+	c.Assert(docs[0]["v"], gc.Equals, int(loggo.WARNING))
+	c.Assert(docs[0]["x"], gc.Equals, "42 log messages dropped due to lack of API connectivity")
+	c.Assert(docs[1]["x"], gc.Equals, "after the gap")
+}
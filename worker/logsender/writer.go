@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+// writerName is the name under which the buffered log writer is
+// registered with loggo.
+const writerName = "buffered-logs"
+
+// InstallBufferedLogWriter creates a BufferedLogWriter holding at most
+// maxLen records, registers it with loggo so it receives all of the
+// agent's log messages, and returns it. Its records are intended to
+// be sent to the state servers by a logsender worker.
+func InstallBufferedLogWriter(maxLen int) (*BufferedLogWriter, error) {
+	writer := NewBufferedLogWriter(maxLen)
+	if err := loggo.RegisterWriter(writerName, writer, loggo.TRACE); err != nil {
+		return nil, errors.Annotate(err, "cannot register buffered log writer")
+	}
+	return writer, nil
+}
+
+// BufferedLogWriter is a loggo.Writer which buffers the log messages
+// it is given as LogRecords, to be read from its Logs channel. Messages
+// logged while the buffer is full are dropped, and the next record to
+// be buffered reports how many were.
+type BufferedLogWriter struct {
+	logs chan *LogRecord
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// NewBufferedLogWriter returns a BufferedLogWriter holding at most
+// maxLen records.
+func NewBufferedLogWriter(maxLen int) *BufferedLogWriter {
+	return &BufferedLogWriter{
+		logs: make(chan *LogRecord, maxLen),
+	}
+}
+
+// Logs returns the channel from which the buffered records are read.
+func (w *BufferedLogWriter) Logs() <-chan *LogRecord {
+	return w.logs
+}
+
+// Write is part of the loggo.Writer interface.
+func (w *BufferedLogWriter) Write(level loggo.Level, module, filename string, line int, ts time.Time, message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	rec := &LogRecord{
+		Time:         ts,
+		Module:       module,
+		Location:     fmt.Sprintf("%s:%d", filename, line),
+		Level:        level,
+		Message:      message,
+		DroppedAfter: w.dropped,
+	}
+	select {
+	case w.logs <- rec:
+		w.dropped = 0
+	default:
+		w.dropped++
+	}
+}
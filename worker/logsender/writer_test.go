@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logsender"
+)
+
+type bufferedLogWriterSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&bufferedLogWriterSuite{})
+
+func (s *bufferedLogWriterSuite) TestWrite(c *gc.C) {
+	writer := logsender.NewBufferedLogWriter(2)
+	t0 := time.Date(2015, 6, 1, 12, 30, 0, 0, time.UTC)
+	writer.Write(loggo.INFO, "some.module", "foo.go", 42, t0, "first")
+	writer.Write(loggo.ERROR, "other.module", "bar.go", 99, t0, "second")
+	// The buffer is full, so these are dropped.
+	writer.Write(loggo.INFO, "some.module", "foo.go", 42, t0, "dropped")
+	writer.Write(loggo.INFO, "some.module", "foo.go", 42, t0, "dropped")
+
+	c.Assert(<-writer.Logs(), jc.DeepEquals, &logsender.LogRecord{
+		Time:     t0,
+		Module:   "some.module",
+		Location: "foo.go:42",
+		Level:    loggo.INFO,
+		Message:  "first",
+	})
+	c.Assert((<-writer.Logs()).Message, gc.Equals, "second")
+
+	writer.Write(loggo.WARNING, "some.module", "foo.go", 42, t0, "third")
+	rec := <-writer.Logs()
+	c.Assert(rec.Message, gc.Equals, "third")
+	c.Assert(rec.DroppedAfter, gc.Equals, 2)
+
+	writer.Write(loggo.WARNING, "some.module", "foo.go", 42, t0, "fourth")
+	c.Assert((<-writer.Logs()).DroppedAfter, gc.Equals, 0)
+}
@@ -6,9 +6,13 @@ package metricworker_test
 import (
 	"time"
 
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api/metricsmanager"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/metricworker"
 )
@@ -41,6 +45,23 @@ func (s *SenderSuite) TestSender(c *gc.C) {
 	c.Assert(worker.Wait(), gc.IsNil)
 }
 
+// TestSetMeterStatus checks that the meter status set through the
+// metrics manager API client used by the state server's workers is
+// seen by the unit.
+func (s *SenderSuite) TestSetMeterStatus(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	st, _ := s.OpenAPIAsNewMachine(c, state.JobManageEnviron)
+	var client metricsmanager.MetricsManagerClient = metricsmanager.NewClient(st)
+
+	err := client.SetMeterStatus(unit.UnitTag(), "RED", "payment overdue")
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := unit.GetMeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Code, gc.Equals, state.MeterRed)
+	c.Assert(status.Info, gc.Equals, "payment overdue")
+}
+
 type mockClient struct {
 	calls []string
 }
@@ -53,3 +74,7 @@ func (m *mockClient) SendMetrics() error {
 	m.calls = append(m.calls, "SendMetrics")
 	return nil
 }
+func (m *mockClient) SetMeterStatus(unit names.UnitTag, code, info string) error {
+	m.calls = append(m.calls, "SetMeterStatus")
+	return nil
+}
@@ -144,12 +144,14 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		harvestMode,
 		p.st,
 		p.toolsFinder,
+		p.st,
 		machineWatcher,
 		retryWatcher,
 		p.broker,
 		auth,
 		envCfg.ImageStream(),
 		secureServerConnection,
+		envCfg.ProvisionerRetryAttempts(),
 	)
 	return task, nil
 }
@@ -269,7 +271,11 @@ func NewContainerProvisioner(
 }
 
 func (p *containerProvisioner) loop() error {
-	task, err := p.getStartTask(config.HarvestDestroyed)
+	envCfg, err := p.st.EnvironConfig()
+	if err != nil {
+		return errors.Annotate(err, "could not retrieve the environment config.")
+	}
+	task, err := p.getStartTask(containerHarvestMode(envCfg.ProvisionerHarvestMode()))
 	if err != nil {
 		return err
 	}
@@ -287,6 +293,18 @@ func (p *containerProvisioner) loop() error {
 	}
 }
 
+// containerHarvestMode returns the harvest mode a container provisioner
+// uses when the environment's is the given one. Containers unknown to
+// juju are never harvested, since they may have been created on the
+// host by other means; destroyed ones are harvested unless the
+// environment's mode says otherwise.
+func containerHarvestMode(mode config.HarvestMode) config.HarvestMode {
+	if mode.HarvestDestroyed() {
+		return config.HarvestDestroyed
+	}
+	return config.HarvestNone
+}
+
 func (p *containerProvisioner) getMachine() (*apiprovisioner.Machine, error) {
 	if p.machine == nil {
 		tag := p.agentConfig.Tag()
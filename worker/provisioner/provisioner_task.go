@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/watcher"
@@ -63,17 +64,20 @@ func NewProvisionerTask(
 	harvestMode config.HarvestMode,
 	machineGetter MachineGetter,
 	toolsFinder ToolsFinder,
+	zoneRecorder ZoneRecorder,
 	machineWatcher apiwatcher.StringsWatcher,
 	retryWatcher apiwatcher.NotifyWatcher,
 	broker environs.InstanceBroker,
 	auth authentication.AuthenticationProvider,
 	imageStream string,
 	secureServerConnection bool,
+	retryAttempts int,
 ) ProvisionerTask {
 	task := &provisionerTask{
 		machineTag:             machineTag,
 		machineGetter:          machineGetter,
 		toolsFinder:            toolsFinder,
+		zoneRecorder:           zoneRecorder,
 		machineWatcher:         machineWatcher,
 		retryWatcher:           retryWatcher,
 		broker:                 broker,
@@ -83,6 +87,8 @@ func NewProvisionerTask(
 		machines:               make(map[string]*apiprovisioner.Machine),
 		imageStream:            imageStream,
 		secureServerConnection: secureServerConnection,
+		retryAttempts:          retryAttempts,
+		retryCounts:            make(map[string]int),
 	}
 	go func() {
 		defer task.tomb.Done()
@@ -95,6 +101,7 @@ type provisionerTask struct {
 	machineTag             names.MachineTag
 	machineGetter          MachineGetter
 	toolsFinder            ToolsFinder
+	zoneRecorder           ZoneRecorder
 	machineWatcher         apiwatcher.StringsWatcher
 	retryWatcher           apiwatcher.NotifyWatcher
 	broker                 environs.InstanceBroker
@@ -104,10 +111,15 @@ type provisionerTask struct {
 	secureServerConnection bool
 	harvestMode            config.HarvestMode
 	harvestModeChan        chan config.HarvestMode
+	retryAttempts          int
+	// machine id -> number of times starting its instance was retried
+	retryCounts map[string]int
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
 	machines map[string]*apiprovisioner.Machine
+	// the availability zones last recorded
+	zones []params.AvailabilityZone
 }
 
 // Kill implements worker.Worker.Kill.
@@ -283,6 +295,7 @@ func (task *provisionerTask) processMachines(ids []string) error {
 			logger.Errorf("failed to remove dead machine %q", machine)
 		}
 		delete(task.machines, machine.Id())
+		delete(task.retryCounts, machine.Id())
 	}
 
 	// Start an instance for the pending ones
@@ -518,6 +531,24 @@ func constructStartInstanceParams(
 		}
 	}
 
+	var imageMetadata []*imagemetadata.ImageMetadata
+	for _, m := range provisioningInfo.ImageMetadata {
+		seriesVersion, err := version.SeriesVersion(m.Series)
+		if err != nil {
+			logger.Warningf("ignoring metadata of image %q: %v", m.ImageId, err)
+			continue
+		}
+		imageMetadata = append(imageMetadata, &imagemetadata.ImageMetadata{
+			Id:         m.ImageId,
+			Storage:    m.RootStorageType,
+			VirtType:   m.VirtType,
+			Arch:       m.Arch,
+			Version:    seriesVersion,
+			RegionName: m.Region,
+			Stream:     m.Stream,
+		})
+	}
+
 	return environs.StartInstanceParams{
 		Constraints:       provisioningInfo.Constraints,
 		Tools:             possibleTools,
@@ -525,10 +556,19 @@ func constructStartInstanceParams(
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
 		Volumes:           volumes,
+		ImageMetadata:     imageMetadata,
 	}, nil
 }
 
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
+	if len(machines) > 0 {
+		// Failing to discover the zones is not fatal: the zones
+		// recorded before are used, or else the broker chooses.
+		if err := task.updateZones(); err != nil {
+			logger.Warningf("%v", err)
+		}
+	}
+	var pending []pendingMachine
 	for _, m := range machines {
 
 		pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
@@ -549,7 +589,10 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 			pInfo.Constraints.Arch,
 		)
 		if err != nil {
-			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+			if err := task.setErrorStatus("cannot find tools for machine %q: %v", m, err); err != nil {
+				return err
+			}
+			continue
 		}
 
 		startInstanceParams, err := constructStartInstanceParams(
@@ -559,11 +602,88 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 			possibleTools,
 		)
 		if err != nil {
-			return task.setErrorStatus("cannot construct params for machine %q: %v", m, err)
+			if err := task.setErrorStatus("cannot construct params for machine %q: %v", m, err); err != nil {
+				return err
+			}
+			continue
 		}
+		if startInstanceParams.Placement == "" {
+			zones, err := task.distributionZones(m)
+			if err != nil {
+				if err := task.setErrorStatus("cannot distribute machine %q across availability zones: %v", m, err); err != nil {
+					return err
+				}
+				continue
+			}
+			startInstanceParams.AvailabilityZones = zones
+		}
+		pending = append(pending, pendingMachine{m, pInfo, startInstanceParams})
+	}
 
-		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot start machine %v", m)
+	bulkBroker, ok := task.broker.(environs.BulkInstanceBroker)
+	for _, batch := range batchPendingMachines(pending) {
+		if !ok || len(batch) == 1 {
+			for _, p := range batch {
+				if err := task.startMachine(p.machine, p.provisioningInfo, p.startInstanceParams); err != nil {
+					return errors.Annotatef(err, "cannot start machine %v", p.machine)
+				}
+			}
+			continue
+		}
+		if err := task.startMachineBatch(bulkBroker, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingMachine holds a machine that is ready to be started, along
+// with the parameters for starting its instance.
+type pendingMachine struct {
+	machine             *apiprovisioner.Machine
+	provisioningInfo    *params.ProvisioningInfo
+	startInstanceParams environs.StartInstanceParams
+}
+
+// batchPendingMachines groups the machines that can be started with a
+// single BulkInstanceBroker.StartInstances call: those with the same
+// series and constraints and no placement directive. The batches, and
+// the machines within them, keep the order in which they were given.
+func batchPendingMachines(pending []pendingMachine) [][]pendingMachine {
+	var batches [][]pendingMachine
+	batchIndex := make(map[string]int)
+	for _, p := range pending {
+		if p.startInstanceParams.Placement != "" {
+			batches = append(batches, []pendingMachine{p})
+			continue
+		}
+		key := p.provisioningInfo.Series + " " + p.provisioningInfo.Constraints.String()
+		if i, ok := batchIndex[key]; ok {
+			batches[i] = append(batches[i], p)
+			continue
+		}
+		batchIndex[key] = len(batches)
+		batches = append(batches, []pendingMachine{p})
+	}
+	return batches
+}
+
+// startMachineBatch starts the instances for a batch of machines with a
+// single call to the broker, and records the outcome for each machine.
+func (task *provisionerTask) startMachineBatch(broker environs.BulkInstanceBroker, batch []pendingMachine) error {
+	args := make([]environs.StartInstanceParams, len(batch))
+	for i, p := range batch {
+		args[i] = p.startInstanceParams
+	}
+	logger.Infof("starting %d instances with the same series and constraints", len(batch))
+	results := broker.StartInstances(args)
+	if len(results) != len(batch) {
+		return errors.Errorf("expected %d start instance results, got %d", len(batch), len(results))
+	}
+	for i, p := range batch {
+		err := task.finishStartMachine(p.machine, p.startInstanceParams, results[i].Result, results[i].Error)
+		if err != nil {
+			return errors.Annotatef(err, "cannot start machine %v", p.machine)
 		}
 	}
 	return nil
@@ -578,6 +698,49 @@ func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisio
 	return nil
 }
 
+// setStartErrorStatus sets the error status of a machine whose instance
+// could not be started. If the provider classified the error as one
+// that may go away, and the machine has not used up its retry budget,
+// the error is marked as transient so that starting the instance is
+// retried the next time machines with transient errors are processed.
+// The kind of the error and the number of retries made are recorded
+// in the status data.
+func (task *provisionerTask) setStartErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
+	logger.Errorf(message, machine, err)
+	info := err.Error()
+	var data map[string]interface{}
+	kind := environs.StartInstanceErrorKindOf(err)
+	if kind != "" {
+		data = map[string]interface{}{"error-kind": string(kind)}
+	}
+	retries := task.retryCounts[machine.Id()]
+	switch kind {
+	case environs.StartInstanceQuotaExceeded, environs.StartInstanceTransient:
+		if retries < task.retryAttempts {
+			retries++
+			task.retryCounts[machine.Id()] = retries
+			data["transient"] = true
+			data["retry-count"] = retries
+			info = fmt.Sprintf("%s (retry %d of %d)", info, retries, task.retryAttempts)
+		} else {
+			if retries > 0 {
+				data["retry-count"] = retries
+				info = fmt.Sprintf("%s (gave up after %d retries)", info, retries)
+			}
+			// Reset the budget, so that provisioning retried by the
+			// user is retried automatically again.
+			delete(task.retryCounts, machine.Id())
+		}
+	default:
+		delete(task.retryCounts, machine.Id())
+	}
+	if err1 := machine.SetStatus(params.StatusError, info, data); err1 != nil {
+		// Something is wrong with this machine, better report it back.
+		return errors.Annotatef(err1, "cannot set error status for machine %q", machine)
+	}
+	return nil
+}
+
 func (task *provisionerTask) prepareNetworkAndInterfaces(networkInfo []network.InterfaceInfo) (
 	networks []params.Network, ifaces []params.NetworkInterface, err error) {
 	if len(networkInfo) == 0 {
@@ -614,23 +777,35 @@ func (task *provisionerTask) startMachine(
 	provisioningInfo *params.ProvisioningInfo,
 	startInstanceParams environs.StartInstanceParams,
 ) error {
-
 	result, err := task.broker.StartInstance(startInstanceParams)
+	return task.finishStartMachine(machine, startInstanceParams, result, err)
+}
+
+// finishStartMachine records the outcome of starting the instance for
+// the given machine, retrying once if the broker reported that it may
+// succeed.
+func (task *provisionerTask) finishStartMachine(
+	machine *apiprovisioner.Machine,
+	startInstanceParams environs.StartInstanceParams,
+	result *environs.StartInstanceResult,
+	err error,
+) error {
 	if err != nil {
 		// If this is a retryable error, we retry once
 		if instance.IsRetryableCreationError(errors.Cause(err)) {
 			logger.Infof("retryable error received on start instance - retrying instance creation")
 			result, err = task.broker.StartInstance(startInstanceParams)
 			if err != nil {
-				return task.setErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
+				return task.setStartErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
 			}
 		} else {
 			// Set the state to error, so the machine will be skipped next
-			// time until the error is resolved, but don't return an
-			// error; just keep going with the other machines.
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+			// time until the error is resolved or retried, but don't
+			// return an error; just keep going with the other machines.
+			return task.setStartErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
 	}
+	delete(task.retryCounts, machine.Id())
 
 	inst := result.Instance
 	hardware := result.Hardware
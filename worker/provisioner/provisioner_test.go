@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -1175,12 +1176,14 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		harvestingMethod,
 		machineGetter,
 		toolsFinder,
+		s.provisioner,
 		machineWatcher,
 		retryWatcher,
 		broker,
 		auth,
 		imagemetadata.ReleasedStream,
 		true,
+		config.DefaultProvisionerRetryAttempts,
 	)
 }
 
@@ -1255,6 +1258,17 @@ func (s *ProvisionerSuite) TestHarvestDestroyedReapsOnlyDestroyed(c *gc.C) {
 	s.waitRemoved(c, m0)
 }
 
+func (s *ProvisionerSuite) TestContainerHarvestMode(c *gc.C) {
+	for mode, expect := range map[config.HarvestMode]config.HarvestMode{
+		config.HarvestAll:       config.HarvestDestroyed,
+		config.HarvestDestroyed: config.HarvestDestroyed,
+		config.HarvestUnknown:   config.HarvestNone,
+		config.HarvestNone:      config.HarvestNone,
+	} {
+		c.Check(provisioner.ContainerHarvestMode(mode), gc.Equals, expect, gc.Commentf("mode %s", mode))
+	}
+}
+
 func (s *ProvisionerSuite) TestHarvestAllReapsAllTheThings(c *gc.C) {
 
 	task := s.newProvisionerTask(c,
@@ -1364,6 +1378,203 @@ func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs
 	return nil, fmt.Errorf("error: some error")
 }
 
+func (s *ProvisionerSuite) TestProvisionerRetriesQuotaExceededErrors(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	quotaError := environs.NewStartInstanceError(
+		environs.StartInstanceQuotaExceeded, errors.New("instance quota exceeded"),
+	)
+	broker := &failingBroker{Environ: s.Environ, err: quotaError, failures: 2}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The machine is started once the provider stops refusing.
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+}
+
+func (s *ProvisionerSuite) TestProvisionerGivesUpRetryingAfterBudget(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	transientError := environs.NewStartInstanceError(
+		environs.StartInstanceTransient, errors.New("API request timed out"),
+	)
+	broker := &failingBroker{Environ: s.Environ, err: transientError, failures: -1}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	data := s.waitErrorStatus(c, m, fmt.Sprintf(
+		"API request timed out (gave up after %d retries)", config.DefaultProvisionerRetryAttempts,
+	))
+	c.Assert(data["error-kind"], gc.Equals, "transient")
+	c.Assert(data["retry-count"], gc.NotNil)
+	c.Assert(data["transient"], gc.IsNil)
+	_, err = m.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *ProvisionerSuite) TestProvisionerDoesNotRetryInvalidConstraints(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	constraintsError := environs.NewStartInstanceError(
+		environs.StartInstanceInvalidConstraints, errors.New("no instance type matches mem=1P"),
+	)
+	broker := &failingBroker{Environ: s.Environ, err: constraintsError, failures: -1}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	data := s.waitErrorStatus(c, m, "no instance type matches mem=1P")
+	c.Assert(data, jc.DeepEquals, map[string]interface{}{"error-kind": "invalid-constraints"})
+}
+
+// waitErrorStatus waits until the supplied machine has an error status
+// with the given info, and returns the status data.
+func (s *ProvisionerSuite) waitErrorStatus(c *gc.C, m *state.Machine, info string) map[string]interface{} {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		status, actual, data, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if status == state.StatusError && actual == info {
+			return data
+		}
+		c.Logf("machine %v has status %q (%q)", m, status, actual)
+	}
+	c.Fatalf("machine %v never had error %q", m, info)
+	return nil
+}
+
+func (s *ProvisionerSuite) TestProvisionerStartsBatchesWithBulkBroker(c *gc.C) {
+	otherConstraints := constraints.MustParse("arch=amd64 mem=8G cpu-cores=1 root-disk=8G")
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	m2, err := s.addMachineWithRequestedNetworks(nil, otherConstraints)
+	c.Assert(err, jc.ErrorIsNil)
+	m3, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machines are all pending when the task starts, so they are
+	// processed together; those with the same series and constraints
+	// are started in one batch.
+	broker := &bulkBroker{Environ: s.Environ}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	s.checkStartInstance(c, m1)
+	s.checkStartInstance(c, m3)
+	s.checkStartInstanceCustom(c, m2, "pork", otherConstraints, nil, nil, nil, true, nil, true)
+	c.Assert(broker.startedBatches(), jc.DeepEquals, [][]string{{m1.Id(), m3.Id()}})
+}
+
+// bulkBroker starts batches of instances one after the other, and
+// records the ids of the machines in each batch.
+type bulkBroker struct {
+	environs.Environ
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (b *bulkBroker) StartInstances(args []environs.StartInstanceParams) []environs.StartInstancesResult {
+	results := make([]environs.StartInstancesResult, len(args))
+	var ids []string
+	for i, arg := range args {
+		ids = append(ids, arg.MachineConfig.MachineId)
+		results[i].Result, results[i].Error = b.Environ.StartInstance(arg)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.batches = append(b.batches, ids)
+	return results
+}
+
+func (b *bulkBroker) startedBatches() [][]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches
+}
+
+// failingBroker fails to start instances with err the given number of
+// times, or always if failures is negative.
+type failingBroker struct {
+	environs.Environ
+	err      error
+	failures int
+	calls    int
+}
+
+func (b *failingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.calls++
+	if b.failures < 0 || b.calls <= b.failures {
+		return nil, b.err
+	}
+	return b.Environ.StartInstance(args)
+}
+
+func (s *ProvisionerSuite) TestProvisionerDistributesAcrossZones(c *gc.C) {
+	broker := &mockZonedBroker{Environ: s.Environ, started: make(chan []string, 1)}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// Every known instance is in zone-a, so the new machine should be
+	// started in zone-b first; zone-c is not available.
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case zones := <-broker.started:
+		c.Assert(zones, jc.DeepEquals, []string{"zone-b", "zone-a"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("instance not started")
+	}
+	s.checkStartInstance(c, m)
+
+	zones, err := s.State.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []state.AvailabilityZone{
+		{Name: "zone-a", Available: true},
+		{Name: "zone-b", Available: true},
+		{Name: "zone-c", Available: false},
+	})
+}
+
+type mockZonedBroker struct {
+	environs.Environ
+	started chan []string
+}
+
+func (b *mockZonedBroker) AvailabilityZones() ([]environs.AvailabilityZone, error) {
+	return []environs.AvailabilityZone{
+		&mockAvailabilityZone{"zone-c", false},
+		&mockAvailabilityZone{"zone-a", true},
+		&mockAvailabilityZone{"zone-b", true},
+	}, nil
+}
+
+func (b *mockZonedBroker) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	zones := make([]string, len(ids))
+	for i := range ids {
+		zones[i] = "zone-a"
+	}
+	return zones, nil
+}
+
+func (b *mockZonedBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.started <- args.AvailabilityZones
+	return b.Environ.StartInstance(args)
+}
+
+type mockAvailabilityZone struct {
+	name      string
+	available bool
+}
+
+func (z *mockAvailabilityZone) Name() string {
+	return z.name
+}
+
+func (z *mockAvailabilityZone) Available() bool {
+	return z.available
+}
+
 type mockToolsFinder struct {
 }
 
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+)
+
+// ZoneRecorder is an interface used for recording the availability
+// zones discovered from the provider.
+type ZoneRecorder interface {
+	// SetAvailabilityZones records the given zones, replacing any
+	// recorded before.
+	SetAvailabilityZones(zones []params.AvailabilityZone) error
+}
+
+var _ ZoneRecorder = (*apiprovisioner.State)(nil)
+
+// updateZones discovers the availability zones of the broker, if it
+// has any, and records them when they have changed since last time.
+func (task *provisionerTask) updateZones() error {
+	zenv, ok := task.broker.(environs.ZonedEnviron)
+	if !ok || task.zoneRecorder == nil {
+		return nil
+	}
+	providerZones, err := zenv.AvailabilityZones()
+	if err != nil {
+		return errors.Annotate(err, "cannot get availability zones")
+	}
+	zones := make([]params.AvailabilityZone, len(providerZones))
+	for i, zone := range providerZones {
+		zones[i] = params.AvailabilityZone{
+			Name:      zone.Name(),
+			Available: zone.Available(),
+		}
+	}
+	if sameZones(zones, task.zones) {
+		return nil
+	}
+	if err := task.zoneRecorder.SetAvailabilityZones(zones); err != nil {
+		return errors.Annotate(err, "cannot record availability zones")
+	}
+	task.zones = zones
+	return nil
+}
+
+func sameZones(a, b []params.AvailabilityZone) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// distributionZones returns the names of the available zones, in the
+// order in which the machine's instance should be tried in them to
+// spread its distribution group across zones: least populated by the
+// group first, then by name. If the machine has no distribution group,
+// all the instances known to the task are counted instead. It returns
+// nil if the broker has no availability zones.
+func (task *provisionerTask) distributionZones(machine *apiprovisioner.Machine) ([]string, error) {
+	zenv, ok := task.broker.(environs.ZonedEnviron)
+	if !ok || len(task.zones) == 0 {
+		return nil, nil
+	}
+	group, err := machine.DistributionGroup()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(group) == 0 {
+		for id := range task.instances {
+			group = append(group, id)
+		}
+	}
+	population := make(map[string]int)
+	if len(group) > 0 {
+		zoneNames, err := zenv.InstanceAvailabilityZoneNames(group)
+		if err != nil && err != environs.ErrPartialInstances && err != environs.ErrNoInstances {
+			return nil, errors.Trace(err)
+		}
+		for _, name := range zoneNames {
+			population[name]++
+		}
+	}
+	return orderZones(task.zones, population), nil
+}
+
+// orderZones returns the names of the available zones ordered by
+// their population, then by name.
+func orderZones(zones []params.AvailabilityZone, population map[string]int) []string {
+	var names []string
+	for _, zone := range zones {
+		if zone.Available {
+			names = append(names, zone.Name)
+		}
+	}
+	sort.Sort(byPopulationThenName{names, population})
+	return names
+}
+
+type byPopulationThenName struct {
+	names      []string
+	population map[string]int
+}
+
+func (b byPopulationThenName) Len() int {
+	return len(b.names)
+}
+
+func (b byPopulationThenName) Less(i, j int) bool {
+	pi, pj := b.population[b.names[i]], b.population[b.names[j]]
+	if pi != pj {
+		return pi < pj
+	}
+	return b.names[i] < b.names[j]
+}
+
+func (b byPopulationThenName) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
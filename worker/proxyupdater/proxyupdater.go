@@ -34,6 +34,10 @@ var (
 	// ProxyFile is the name of the file to be stored in the ProxyDirectory.
 	ProxyFile = ".juju-proxy"
 
+	// SystemProxyFile is the file, sourced by the login shells of all
+	// users, that exports the environment settings for the proxies.
+	SystemProxyFile = "/etc/profile.d/juju-proxy.sh"
+
 	// Started is a function that is called when the worker has started.
 	Started = func() {}
 )
@@ -109,6 +113,15 @@ func (w *proxyWorker) writeEnvironmentFile() error {
 	return nil
 }
 
+// writeSystemEnvironmentFile writes the proxy settings where every
+// user's login shell picks them up, so that processes started after the
+// change see the new values without the machine being rebooted.
+func (w *proxyWorker) writeSystemEnvironmentFile() error {
+	// Always finish with a new line.
+	content := w.proxy.AsScriptEnvironment() + "\n"
+	return utils.AtomicWriteFile(SystemProxyFile, []byte(content), 0644)
+}
+
 func (w *proxyWorker) writeEnvironmentToRegistry() error {
 	// On windows we write the proxy settings to the registry.
 	setProxyScript := `$value_path = "%s"
@@ -140,7 +153,10 @@ func (w *proxyWorker) writeEnvironment() error {
 	case version.Windows:
 		return w.writeEnvironmentToRegistry()
 	default:
-		return w.writeEnvironmentFile()
+		if err := w.writeEnvironmentFile(); err != nil {
+			return err
+		}
+		return w.writeSystemEnvironmentFile()
 	}
 }
 
@@ -33,8 +33,9 @@ type ProxyUpdaterSuite struct {
 	environmentAPI *environment.Facade
 	machine        *state.Machine
 
-	proxyFile string
-	started   chan struct{}
+	proxyFile       string
+	systemProxyFile string
+	started         chan struct{}
 }
 
 var _ = gc.Suite(&ProxyUpdaterSuite{})
@@ -60,6 +61,8 @@ func (s *ProxyUpdaterSuite) SetUpTest(c *gc.C) {
 	s.PatchValue(&proxyupdater.Started, s.setStarted)
 	s.PatchValue(&apt.ConfFile, path.Join(proxyDir, "juju-apt-proxy"))
 	s.proxyFile = path.Join(proxyDir, proxyupdater.ProxyFile)
+	s.systemProxyFile = path.Join(proxyDir, "juju-proxy.sh")
+	s.PatchValue(&proxyupdater.SystemProxyFile, s.systemProxyFile)
 }
 
 func (s *ProxyUpdaterSuite) waitForPostSetup(c *gc.C) {
@@ -167,6 +170,21 @@ func (s *ProxyUpdaterSuite) TestWriteSystemFiles(c *gc.C) {
 
 	s.waitProxySettings(c, proxySettings)
 	s.waitForFile(c, s.proxyFile, proxySettings.AsScriptEnvironment()+"\n")
+	s.waitForFile(c, s.systemProxyFile, proxySettings.AsScriptEnvironment()+"\n")
+	s.waitForFile(c, apt.ConfFile, apt.ProxyContent(aptProxySettings)+"\n")
+}
+
+func (s *ProxyUpdaterSuite) TestLiveUpdates(c *gc.C) {
+	updater := proxyupdater.New(s.environmentAPI, true)
+	defer worker.Stop(updater)
+	s.waitForPostSetup(c)
+
+	// Changing the proxies after the worker has started updates the
+	// agent's environment and the system files.
+	proxySettings, aptProxySettings := s.updateConfig(c)
+	s.waitProxySettings(c, proxySettings)
+	s.waitForFile(c, s.proxyFile, proxySettings.AsScriptEnvironment()+"\n")
+	s.waitForFile(c, s.systemProxyFile, proxySettings.AsScriptEnvironment()+"\n")
 	s.waitForFile(c, apt.ConfFile, apt.ProxyContent(aptProxySettings)+"\n")
 }
 
@@ -207,4 +225,5 @@ func (s *ProxyUpdaterSuite) TestDontWriteSystemFiles(c *gc.C) {
 	s.waitProxySettings(c, proxySettings)
 	c.Assert(apt.ConfFile, jc.DoesNotExist)
 	c.Assert(s.proxyFile, jc.DoesNotExist)
+	c.Assert(s.systemProxyFile, jc.DoesNotExist)
 }
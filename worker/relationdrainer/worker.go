@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdrainer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.relationdrainer")
+
+// State defines the state methods used by the worker.
+type State interface {
+	DrainingRelations() ([]*state.Relation, error)
+	WatchDrainingRelations() state.NotifyWatcher
+}
+
+// New returns a worker which destroys draining relations once their
+// drain deadlines have passed. This worker is intended to run just
+// once, on the MongoDB master.
+func New(st State) worker.Worker {
+	w := &drainWorker{st: st}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+type drainWorker struct {
+	tomb tomb.Tomb
+	st   State
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *drainWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *drainWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *drainWorker) loop() error {
+	relationsWatcher := w.st.WatchDrainingRelations()
+	defer watcher.Stop(relationsWatcher, &w.tomb)
+
+	var due <-chan time.Time
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-relationsWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(relationsWatcher)
+			}
+		case <-due:
+		}
+		next, err := w.destroyDrained(time.Now())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		due = nil
+		if !next.IsZero() {
+			due = time.After(next.Sub(time.Now()))
+		}
+	}
+}
+
+// destroyDrained destroys the relations whose drain deadlines have
+// passed by now, returning the deadline of the next relation to drain,
+// or the zero time if there is none.
+func (w *drainWorker) destroyDrained(now time.Time) (time.Time, error) {
+	relations, err := w.st.DrainingRelations()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		deadline, _ := rel.DrainDeadline()
+		if deadline.After(now) {
+			// Relations are ordered by their drain deadlines.
+			return deadline, nil
+		}
+		if err := rel.Destroy(); err != nil {
+			return time.Time{}, errors.Trace(err)
+		}
+		logger.Infof("relation %q drained", rel)
+	}
+	return time.Time{}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package relationdrainer_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/relationdrainer"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type suite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&suite{})
+
+// addRelation returns a relation between wordpress and the named
+// service, with a wordpress unit in scope.
+func (s *suite) addRelation(c *gc.C, wordpress *state.Service, name string) *state.Relation {
+	s.AddTestingService(c, name, s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", name)
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(units[0])
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *suite) TestDestroysDrainedRelations(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	drained := s.addRelation(c, wordpress, "mysql")
+	draining := s.addRelation(c, wordpress, "otherdb")
+	err = draining.Drain(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := relationdrainer.New(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// A relation that starts draining while the worker is running is
+	// destroyed once its deadline passes.
+	err = drained.Drain(time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := drained.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if drained.Life() == state.Dying {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for relation to drain")
+		}
+	}
+
+	err = draining.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(draining.Life(), gc.Equals, state.Alive)
+}
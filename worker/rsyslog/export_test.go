@@ -1,14 +0,0 @@
-// Copyright 2014 Canonical Ltd.
-// Licensed under the AGPLv3, see LICENCE file for details.
-
-package rsyslog
-
-var (
-	RestartRsyslog          = &restartRsyslog
-	LogDir                  = &logDir
-	RsyslogConfDir          = &rsyslogConfDir
-	LookupUser              = &lookupUser
-	DialSyslog              = &dialSyslog
-	SyslogTargets           = &syslogTargets
-	NewRsyslogConfigHandler = newRsyslogConfigHandler
-)
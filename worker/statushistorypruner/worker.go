@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statushistorypruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// HistoryPruneParams specifies how status history should be pruned.
+type HistoryPruneParams struct {
+	MaxHistoryAge       time.Duration
+	MaxEntriesPerEntity int
+	PruneInterval       time.Duration
+}
+
+const DefaultMaxHistoryAge = 7 * 24 * time.Hour // 1 week
+const DefaultMaxEntriesPerEntity = 100
+const DefaultPruneInterval = 5 * time.Minute
+
+// NewHistoryPruneParams returns a HistoryPruneParams initialised with
+// default values.
+func NewHistoryPruneParams() *HistoryPruneParams {
+	return &HistoryPruneParams{
+		MaxHistoryAge:       DefaultMaxHistoryAge,
+		MaxEntriesPerEntity: DefaultMaxEntriesPerEntity,
+		PruneInterval:       DefaultPruneInterval,
+	}
+}
+
+// New returns a worker which periodically wakes up to remove old
+// status history entries stored in MongoDB. This worker is intended
+// to run just once, on the MongoDB master.
+func New(st *state.State, params *HistoryPruneParams) worker.Worker {
+	w := &pruneWorker{
+		st:     st,
+		params: params,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st     *state.State
+	params *HistoryPruneParams
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	p := w.params
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(p.PruneInterval):
+			minHistoryTime := time.Now().Add(-p.MaxHistoryAge)
+			err := state.PruneStatusHistory(w.st, minHistoryTime, p.MaxEntriesPerEntity)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statushistorypruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/statushistorypruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	pruner worker.Worker
+}
+
+func (s *suite) StartWorker(c *gc.C, maxHistoryAge time.Duration, maxEntriesPerEntity int) {
+	params := &statushistorypruner.HistoryPruneParams{
+		MaxHistoryAge:       maxHistoryAge,
+		MaxEntriesPerEntity: maxEntriesPerEntity,
+		PruneInterval:       time.Millisecond, // Speed up pruning interval for testing
+	}
+	s.pruner = statushistorypruner.New(s.State, params)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) TestPrunesHistoryBySize(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 10; i++ {
+		err := m.SetStatus(state.StatusStarted, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	noPruneAge := 999 * time.Hour
+	s.StartWorker(c, noPruneAge, 3)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		history, err := s.State.StatusHistory(m.Tag(), 100)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(history) == 3 {
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
@@ -116,6 +117,73 @@ func removeAttachments(ctx *context, ids []params.MachineStorageId) error {
 	return nil
 }
 
+// provisioningFailure records why a volume or filesystem could not be
+// provisioned.
+type provisioningFailure struct {
+	tag names.Tag
+	err error
+}
+
+// setProvisioningErrors records in state why each of the specified
+// volumes and filesystems could not be provisioned, and notes them in
+// the context so they are checked periodically in case provisioning
+// has been retried.
+func setProvisioningErrors(ctx *context, failures []provisioningFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	args := make([]params.StorageProvisioningError, len(failures))
+	for i, failure := range failures {
+		logger.Errorf("cannot provision %s: %v", names.ReadableString(failure.tag), failure.err)
+		args[i] = params.StorageProvisioningError{
+			Tag:     failure.tag.String(),
+			Message: failure.err.Error(),
+		}
+	}
+	errorResults, err := ctx.life.SetProvisioningErrors(args)
+	if err != nil {
+		return errors.Annotate(err, "setting provisioning errors")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "setting provisioning error for %s",
+				names.ReadableString(failures[i].tag),
+			)
+		}
+	}
+	for _, failure := range failures {
+		switch tag := failure.tag.(type) {
+		case names.VolumeTag:
+			ctx.failedVolumes.Add(tag.Id())
+		case names.FilesystemTag:
+			ctx.failedFilesystems.Add(tag.Id())
+		}
+	}
+	return nil
+}
+
+// retryProvisioning attempts again to provision the volumes and
+// filesystems that could not be provisioned before. Those for which
+// provisioning has not been retried since remain failed.
+func retryProvisioning(ctx *context) error {
+	volumeIds := ctx.failedVolumes.SortedValues()
+	filesystemIds := ctx.failedFilesystems.SortedValues()
+	ctx.failedVolumes = set.NewStrings()
+	ctx.failedFilesystems = set.NewStrings()
+	if len(volumeIds) > 0 {
+		if err := volumesChanged(ctx, volumeIds); err != nil {
+			return errors.Annotate(err, "retrying volume provisioning")
+		}
+	}
+	if len(filesystemIds) > 0 {
+		if err := filesystemsChanged(ctx, filesystemIds); err != nil {
+			return errors.Annotate(err, "retrying filesystem provisioning")
+		}
+	}
+	return nil
+}
+
 var errNonDynamic = errors.New("non-dynamic storage provider")
 
 // volumeSource returns a volume source given a name, provider type,
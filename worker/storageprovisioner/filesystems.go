@@ -38,6 +38,11 @@ func filesystemsChanged(ctx *context, changes []string) error {
 	// Once the entities are Dead, they can be removed from state
 	// after the corresponding cloud storage resources are removed.
 	dead = append(dead, dying...)
+	for _, tag := range dead {
+		// There is no point retrying provisioning
+		// of filesystems that are going away.
+		ctx.failedFilesystems.Remove(tag.Id())
+	}
 	if len(alive)+len(dead) == 0 {
 		return nil
 	}
@@ -202,20 +207,31 @@ func processAliveFilesystems(ctx *context, tags []names.Tag, filesystemResults [
 		return errors.Annotate(err, "getting filesystem params")
 	}
 	filesystemParams := make([]storage.FilesystemParams, 0, len(paramsResults))
-	for _, result := range paramsResults {
+	for i, result := range paramsResults {
+		if params.IsCodeProvisioningFailed(result.Error) {
+			// Provisioning failed before, and has not been
+			// retried since; check again later.
+			logger.Debugf("%v", result.Error)
+			ctx.failedFilesystems.Add(pending[i].Id())
+			continue
+		}
 		if result.Error != nil {
-			return errors.Annotate(err, "getting filesystem parameters")
+			return errors.Annotate(result.Error, "getting filesystem parameters")
 		}
+		ctx.failedFilesystems.Remove(pending[i].Id())
 		params, err := filesystemParamsFromParams(result.Result)
 		if err != nil {
 			return errors.Annotate(err, "getting filesystem parameters")
 		}
 		filesystemParams = append(filesystemParams, params)
 	}
-	filesystems, err := createFilesystems(ctx.environConfig, ctx.storageDir, filesystemParams)
+	filesystems, failures, err := createFilesystems(ctx.environConfig, ctx.storageDir, filesystemParams)
 	if err != nil {
 		return errors.Annotate(err, "creating filesystems")
 	}
+	if err := setProvisioningErrors(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
 	if len(filesystems) > 0 {
 		// TODO(axw) we need to be able to list filesystems in the provider,
 		// by environment, so that we can "harvest" them if they're
@@ -341,12 +357,13 @@ func setFilesystemAttachmentInfo(ctx *context, filesystemAttachments []params.Fi
 	return nil
 }
 
-// createFilesystems creates filesystems with the specified parameters.
+// createFilesystems creates filesystems with the specified parameters. The
+// filesystems that could not be created are returned as failures.
 func createFilesystems(
 	environConfig *config.Config,
 	baseStorageDir string,
 	params []storage.FilesystemParams,
-) ([]params.Filesystem, error) {
+) ([]params.Filesystem, []provisioningFailure, error) {
 	// TODO(axw) later we may have multiple instantiations (sources)
 	// for a storage provider, e.g. multiple Ceph installations. For
 	// now we assume a single source for each provider type, with no
@@ -363,20 +380,25 @@ func createFilesystems(
 			environConfig, baseStorageDir, sourceName, params.Provider,
 		)
 		if err != nil {
-			return nil, errors.Annotate(err, "getting filesystem source")
+			return nil, nil, errors.Annotate(err, "getting filesystem source")
 		}
 		filesystemSources[sourceName] = filesystemSource
 	}
 	var allFilesystems []storage.Filesystem
+	var failures []provisioningFailure
 	for sourceName, params := range paramsBySource {
 		filesystemSource := filesystemSources[sourceName]
 		filesystems, err := filesystemSource.CreateFilesystems(params)
 		if err != nil {
-			return nil, errors.Annotatef(err, "creating filesystems from source %q", sourceName)
+			err = errors.Annotatef(err, "creating filesystems from source %q", sourceName)
+			for _, p := range params {
+				failures = append(failures, provisioningFailure{p.Tag, err})
+			}
+			continue
 		}
 		allFilesystems = append(allFilesystems, filesystems...)
 	}
-	return filesystemsFromStorage(allFilesystems), nil
+	return filesystemsFromStorage(allFilesystems), failures, nil
 }
 
 // createFilesystemAttachments creates filesystem attachments with the specified parameters.
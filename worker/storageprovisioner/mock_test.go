@@ -22,6 +22,7 @@ import (
 
 const attachedVolumeId = "1"
 const needsInstanceVolumeId = "23"
+const invalidVolumeId = "24"
 
 var dyingVolumeAttachmentId = params.MachineStorageId{
 	MachineTag:    "machine-0",
@@ -120,6 +121,17 @@ type mockVolumeAccessor struct {
 
 	setVolumeInfo           func([]params.Volume) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo func([]params.VolumeAttachment) ([]params.ErrorResult, error)
+
+	mu                 sync.Mutex
+	provisioningFailed map[string]bool
+}
+
+// setProvisioningFailed records whether provisioning of the volume with
+// the given tag has failed, and has not been retried since.
+func (v *mockVolumeAccessor) setProvisioningFailed(tag string, failed bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.provisioningFailed[tag] = failed
 }
 
 func (w *mockVolumeAccessor) WatchVolumes() (apiwatcher.StringsWatcher, error) {
@@ -159,9 +171,15 @@ func (v *mockVolumeAccessor) VolumeAttachments(ids []params.MachineStorageId) ([
 }
 
 func (v *mockVolumeAccessor) VolumeParams(volumes []names.VolumeTag) ([]params.VolumeParamsResult, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	var result []params.VolumeParamsResult
 	for _, tag := range volumes {
-		if _, ok := v.provisionedVolumes[tag.String()]; ok {
+		if v.provisioningFailed[tag.String()] {
+			result = append(result, params.VolumeParamsResult{
+				Error: &params.Error{Message: "provisioning failed", Code: params.CodeProvisioningFailed},
+			})
+		} else if _, ok := v.provisionedVolumes[tag.String()]; ok {
 			result = append(result, params.VolumeParamsResult{
 				Error: &params.Error{Message: "already provisioned"},
 			})
@@ -224,6 +242,7 @@ func newMockVolumeAccessor() *mockVolumeAccessor {
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
+		provisioningFailed:     make(map[string]bool),
 	}
 }
 
@@ -334,6 +353,7 @@ func newMockFilesystemAccessor() *mockFilesystemAccessor {
 }
 
 type mockLifecycleManager struct {
+	setProvisioningErrors func([]params.StorageProvisioningError) ([]params.ErrorResult, error)
 }
 
 func (m *mockLifecycleManager) Life(volumes []names.Tag) ([]params.LifeResult, error) {
@@ -378,6 +398,13 @@ func (m *mockLifecycleManager) RemoveAttachments([]params.MachineStorageId) ([]p
 	return nil, nil
 }
 
+func (m *mockLifecycleManager) SetProvisioningErrors(errs []params.StorageProvisioningError) ([]params.ErrorResult, error) {
+	if m.setProvisioningErrors == nil {
+		return nil, nil
+	}
+	return m.setProvisioningErrors(errs)
+}
+
 // Set up a dummy storage provider so we can stub out volume creation.
 type dummyProvider struct {
 	storage.Provider
@@ -410,8 +437,11 @@ func (p *dummyProvider) Dynamic() bool {
 }
 
 func (*dummyVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
-	if params.Tag.Id() == needsInstanceVolumeId {
+	switch params.Tag.Id() {
+	case needsInstanceVolumeId:
 		return storage.ErrVolumeNeedsInstance
+	case invalidVolumeId:
+		return errors.New("volume too small")
 	}
 	return nil
 }
@@ -440,6 +470,15 @@ func (*dummyVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]storag
 	return volumes, volumeAttachments, nil
 }
 
+// failingVolumeSource is a volume source that fails to create volumes.
+type failingVolumeSource struct {
+	dummyVolumeSource
+}
+
+func (*failingVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]storage.Volume, []storage.VolumeAttachment, error) {
+	return nil, nil, errors.New("no capacity")
+}
+
 // AttachVolumes attaches volumes to machines.
 func (*dummyVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
 	var volumeAttachments []storage.VolumeAttachment
@@ -4,9 +4,12 @@
 package storageprovisioner
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 	"launchpad.net/tomb"
 
 	apiwatcher "github.com/juju/juju/api/watcher"
@@ -18,6 +21,10 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.storageprovisioner")
 
+// retryInterval is how often the worker checks whether provisioning of
+// the volumes and filesystems that failed has been retried.
+var retryInterval = time.Minute
+
 // VolumeAccessor defines an interface used to allow a storage provisioner
 // worker to perform volume related operations.
 type VolumeAccessor interface {
@@ -107,6 +114,10 @@ type LifecycleManager interface {
 	// RemoveAttachments removes the specified machine/entity attachments
 	// from state.
 	RemoveAttachments([]params.MachineStorageId) ([]params.ErrorResult, error)
+
+	// SetProvisioningErrors records why the specified entities could
+	// not be provisioned.
+	SetProvisioningErrors([]params.StorageProvisioningError) ([]params.ErrorResult, error)
 }
 
 // EnvironAccessor defines an interface used to enable a storage provisioner
@@ -219,13 +230,19 @@ func (w *storageprovisioner) loop() error {
 	}
 
 	ctx := context{
-		storageDir:  w.storageDir,
-		volumes:     w.volumes,
-		filesystems: w.filesystems,
-		life:        w.life,
+		storageDir:        w.storageDir,
+		volumes:           w.volumes,
+		filesystems:       w.filesystems,
+		life:              w.life,
+		failedVolumes:     set.NewStrings(),
+		failedFilesystems: set.NewStrings(),
 	}
 
+	var retryChanges <-chan time.Time
 	for {
+		if retryChanges == nil && (!ctx.failedVolumes.IsEmpty() || !ctx.failedFilesystems.IsEmpty()) {
+			retryChanges = time.After(retryInterval)
+		}
 		select {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
@@ -273,6 +290,11 @@ func (w *storageprovisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case <-retryChanges:
+			retryChanges = nil
+			if err := retryProvisioning(&ctx); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -289,4 +311,9 @@ type context struct {
 	volumes       VolumeAccessor
 	filesystems   FilesystemAccessor
 	life          LifecycleManager
+
+	// failedVolumes and failedFilesystems hold the IDs of the
+	// volumes and filesystems that could not be provisioned.
+	failedVolumes     set.Strings
+	failedFilesystems set.Strings
 }
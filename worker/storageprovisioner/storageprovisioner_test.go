@@ -170,6 +170,91 @@ func (s *storageProvisionerSuite) TestVolumeNeedsInstance(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `provisioning volumes: creating volumes: need running instance to provision volume`)
 }
 
+func (s *storageProvisionerSuite) TestVolumeInvalidParams(c *gc.C) {
+	provisioningErrorsSet := make(chan struct{})
+	lifecycleManager := &mockLifecycleManager{
+		setProvisioningErrors: func(errs []params.StorageProvisioningError) ([]params.ErrorResult, error) {
+			defer close(provisioningErrorsSet)
+			c.Assert(errs, jc.DeepEquals, []params.StorageProvisioningError{{
+				Tag:     "volume-" + invalidVolumeId,
+				Message: "invalid volume parameters: volume too small",
+			}})
+			return nil, nil
+		},
+	}
+	volumeAccessor := newMockVolumeAccessor()
+	filesystemAccessor := newMockFilesystemAccessor()
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		"storage-dir",
+		volumeAccessor,
+		filesystemAccessor,
+		lifecycleManager,
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.volumesWatcher.changes <- []string{invalidVolumeId}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, provisioningErrorsSet, "waiting for provisioning errors to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeProvisioningRetried(c *gc.C) {
+	s.PatchValue(storageprovisioner.RetryInterval, coretesting.ShortWait)
+	var volumeSources int
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		// Creating the volume fails the first time only.
+		volumeSources++
+		if volumeSources == 1 {
+			return &failingVolumeSource{}, nil
+		}
+		return &dummyVolumeSource{}, nil
+	}
+
+	volumeAccessor := newMockVolumeAccessor()
+	provisioningErrorsSet := make(chan struct{})
+	lifecycleManager := &mockLifecycleManager{
+		setProvisioningErrors: func(errs []params.StorageProvisioningError) ([]params.ErrorResult, error) {
+			defer close(provisioningErrorsSet)
+			c.Assert(errs, jc.DeepEquals, []params.StorageProvisioningError{{
+				Tag:     "volume-2",
+				Message: `creating volumes from source "dummy": no capacity`,
+			}})
+			volumeAccessor.setProvisioningFailed("volume-2", true)
+			return nil, nil
+		},
+	}
+	volumeInfoSet := make(chan struct{})
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		c.Assert(volumes, gc.HasLen, 1)
+		c.Assert(volumes[0].VolumeTag, gc.Equals, "volume-2")
+		return nil, nil
+	}
+	filesystemAccessor := newMockFilesystemAccessor()
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		"storage-dir",
+		volumeAccessor,
+		filesystemAccessor,
+		lifecycleManager,
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.volumesWatcher.changes <- []string{"2"}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, provisioningErrorsSet, "waiting for provisioning errors to be set")
+
+	// The volume is not provisioned again until provisioning
+	// is retried.
+	assertNoEvent(c, volumeInfoSet, "volume info set")
+	volumeAccessor.setProvisioningFailed("volume-2", false)
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+}
+
 func (s *storageProvisionerSuite) TestVolumeNonDynamic(c *gc.C) {
 	volumeInfoSet := make(chan struct{})
 	volumeAccessor := newMockVolumeAccessor()
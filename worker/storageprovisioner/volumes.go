@@ -37,6 +37,11 @@ func volumesChanged(ctx *context, changes []string) error {
 	// Once the entities are Dead, they can be removed from state
 	// after the corresponding cloud storage resources are removed.
 	dead = append(dead, dying...)
+	for _, tag := range dead {
+		// There is no point retrying provisioning
+		// of volumes that are going away.
+		ctx.failedVolumes.Remove(tag.Id())
+	}
 	if len(alive)+len(dead) == 0 {
 		return nil
 	}
@@ -201,22 +206,33 @@ func processAliveVolumes(ctx *context, tags []names.Tag, volumeResults []params.
 		return errors.Annotate(err, "getting volume params")
 	}
 	volumeParams := make([]storage.VolumeParams, 0, len(paramsResults))
-	for _, result := range paramsResults {
+	for i, result := range paramsResults {
+		if params.IsCodeProvisioningFailed(result.Error) {
+			// Provisioning failed before, and has not been
+			// retried since; check again later.
+			logger.Debugf("%v", result.Error)
+			ctx.failedVolumes.Add(pending[i].Id())
+			continue
+		}
 		if result.Error != nil {
-			return errors.Annotate(err, "getting volume parameters")
+			return errors.Annotate(result.Error, "getting volume parameters")
 		}
+		ctx.failedVolumes.Remove(pending[i].Id())
 		params, err := volumeParamsFromParams(result.Result)
 		if err != nil {
 			return errors.Annotate(err, "getting volume parameters")
 		}
 		volumeParams = append(volumeParams, params)
 	}
-	volumes, volumeAttachments, err := createVolumes(
+	volumes, volumeAttachments, failures, err := createVolumes(
 		ctx.environConfig, ctx.storageDir, volumeParams,
 	)
 	if err != nil {
 		return errors.Annotate(err, "creating volumes")
 	}
+	if err := setProvisioningErrors(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
 	if len(volumes) > 0 {
 		// TODO(axw) we need to be able to list volumes in the provider,
 		// by environment, so that we can "harvest" them if they're
@@ -342,12 +358,13 @@ func setVolumeAttachmentInfo(ctx *context, volumeAttachments []params.VolumeAtta
 	return nil
 }
 
-// createVolumes creates volumes with the specified parameters.
+// createVolumes creates volumes with the specified parameters. The
+// volumes that could not be created are returned as failures.
 func createVolumes(
 	environConfig *config.Config,
 	baseStorageDir string,
 	params []storage.VolumeParams,
-) ([]params.Volume, []params.VolumeAttachment, error) {
+) ([]params.Volume, []params.VolumeAttachment, []provisioningFailure, error) {
 	// TODO(axw) later we may have multiple instantiations (sources)
 	// for a storage provider, e.g. multiple Ceph installations. For
 	// now we assume a single source for each provider type, with no
@@ -366,12 +383,13 @@ func createVolumes(
 		if errors.Cause(err) == errNonDynamic {
 			volumeSource = nil
 		} else if err != nil {
-			return nil, nil, errors.Annotate(err, "getting volume source")
+			return nil, nil, nil, errors.Annotate(err, "getting volume source")
 		}
 		volumeSources[sourceName] = volumeSource
 	}
 
 	// Validate and gather volume parameters.
+	var failures []provisioningFailure
 	paramsBySource := make(map[string][]storage.VolumeParams)
 	for _, params := range params {
 		sourceName := string(params.Provider)
@@ -390,10 +408,11 @@ func createVolumes(
 			// is created. This requires that we watch machines.
 			//
 			// For now, rely on the worker bouncing to retry.
-			return nil, nil, err
+			return nil, nil, nil, err
 		default:
-			// TODO(axw) we should set an error status for params.Tag here.
-			logger.Errorf("ignoring invalid volume parameters: %v", err)
+			failures = append(failures, provisioningFailure{
+				params.Tag, errors.Annotate(err, "invalid volume parameters"),
+			})
 		}
 	}
 
@@ -403,12 +422,16 @@ func createVolumes(
 		volumeSource := volumeSources[sourceName]
 		volumes, volumeAttachments, err := volumeSource.CreateVolumes(params)
 		if err != nil {
-			return nil, nil, errors.Annotatef(err, "creating volumes from source %q", sourceName)
+			err = errors.Annotatef(err, "creating volumes from source %q", sourceName)
+			for _, p := range params {
+				failures = append(failures, provisioningFailure{p.Tag, err})
+			}
+			continue
 		}
 		allVolumes = append(allVolumes, volumes...)
 		allVolumeAttachments = append(allVolumeAttachments, volumeAttachments...)
 	}
-	return volumesFromStorage(allVolumes), volumeAttachmentsFromStorage(allVolumeAttachments), nil
+	return volumesFromStorage(allVolumes), volumeAttachmentsFromStorage(allVolumeAttachments), failures, nil
 }
 
 // createVolumeAttachments creates volume attachments with the specified parameters.
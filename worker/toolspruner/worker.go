@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolspruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// ToolsPruneParams specifies how tools should be pruned.
+type ToolsPruneParams struct {
+	Retention     time.Duration
+	PruneInterval time.Duration
+}
+
+const DefaultRetention = 7 * 24 * time.Hour // 1 week
+const DefaultPruneInterval = 6 * time.Hour
+
+// NewToolsPruneParams returns a ToolsPruneParams initialised with
+// default values.
+func NewToolsPruneParams() *ToolsPruneParams {
+	return &ToolsPruneParams{
+		Retention:     DefaultRetention,
+		PruneInterval: DefaultPruneInterval,
+	}
+}
+
+// New returns a worker which periodically wakes up to remove the tools
+// stored by the state server that no agent uses, once they have been
+// kept for the retention period. This worker is intended to run just
+// once, on the MongoDB master.
+func New(st *state.State, params *ToolsPruneParams) worker.Worker {
+	w := &pruneWorker{
+		st:     st,
+		params: params,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st     *state.State
+	params *ToolsPruneParams
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	p := w.params
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(p.PruneInterval):
+			minToolsTime := time.Now().Add(-p.Retention)
+			_, err := state.PruneTools(w.st, minToolsTime, false)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
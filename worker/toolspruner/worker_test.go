@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package toolspruner_test
+
+import (
+	"strings"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/state/toolstorage"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/toolspruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	pruner worker.Worker
+}
+
+func (s *suite) StartWorker(c *gc.C, retention time.Duration) {
+	params := &toolspruner.ToolsPruneParams{
+		Retention:     retention,
+		PruneInterval: time.Millisecond, // Speed up pruning interval for testing
+	}
+	s.pruner = toolspruner.New(s.State, params)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) TestPrunesUnusedTools(c *gc.C) {
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	vers := version.MustParseBinary("1.2.3-trusty-amd64")
+	err = storage.AddTools(strings.NewReader("abc"), toolstorage.Metadata{
+		Version: vers,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.StartWorker(c, 0)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		metadata, err := storage.AllMetadata()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(metadata) == 0 {
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestKeepsToolsWithinRetention(c *gc.C) {
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	vers := version.MustParseBinary("1.2.3-trusty-amd64")
+	err = storage.AddTools(strings.NewReader("abc"), toolstorage.Metadata{
+		Version: vers,
+		Size:    3,
+		SHA256:  "hash(abc)",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.StartWorker(c, 999*time.Hour)
+	time.Sleep(testing.ShortWait)
+
+	_, err = storage.Metadata(vers)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package txnpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// TxnPruneParams specifies how transactions should be pruned.
+type TxnPruneParams struct {
+	MaxTxnAge       time.Duration
+	MaxCollectionMB int
+	PruneInterval   time.Duration
+}
+
+const DefaultMaxTxnAge = time.Duration(config.DefaultTxnPruneMaxAge) * time.Second
+const DefaultMaxCollectionMB = config.DefaultTxnPruneMaxCollectionMB
+const DefaultPruneInterval = time.Duration(config.DefaultTxnPruneInterval) * time.Second
+
+// NewTxnPruneParams returns a TxnPruneParams initialised with default
+// values.
+func NewTxnPruneParams() *TxnPruneParams {
+	return &TxnPruneParams{
+		MaxTxnAge:       DefaultMaxTxnAge,
+		MaxCollectionMB: DefaultMaxCollectionMB,
+		PruneInterval:   DefaultPruneInterval,
+	}
+}
+
+// NewTxnPruneParamsFromConfig returns a TxnPruneParams initialised
+// from the txn-prune-* settings of the given environment config, which
+// default to the values above.
+func NewTxnPruneParamsFromConfig(cfg *config.Config) *TxnPruneParams {
+	opts := cfg.TxnPruneOpts()
+	return &TxnPruneParams{
+		MaxTxnAge:       opts.MaxAge,
+		MaxCollectionMB: opts.MaxCollectionMB,
+		PruneInterval:   opts.Interval,
+	}
+}
+
+// New returns a worker which periodically wakes up to remove completed
+// transactions from the txns collection. This worker is intended to
+// run just once, on the MongoDB master.
+func New(st *state.State, params *TxnPruneParams) worker.Worker {
+	w := &pruneWorker{
+		st:     st,
+		params: params,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st     *state.State
+	params *TxnPruneParams
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	p := w.params
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(p.PruneInterval):
+			minTxnTime := time.Now().Add(-p.MaxTxnAge)
+			err := state.PruneTxns(w.st, minTxnTime, p.MaxCollectionMB)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
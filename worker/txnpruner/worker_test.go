@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package txnpruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/environs/config"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/txnpruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+	pruner   worker.Worker
+	txnsColl *mgo.Collection
+}
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.txnsColl = s.State.MongoSession().DB("juju").C("txns")
+}
+
+func (s *suite) StartWorker(c *gc.C, maxTxnAge time.Duration, maxCollectionMB int) {
+	params := &txnpruner.TxnPruneParams{
+		MaxTxnAge:       maxTxnAge,
+		MaxCollectionMB: maxCollectionMB,
+		PruneInterval:   time.Millisecond, // Speed up pruning interval for testing
+	}
+	s.pruner = txnpruner.New(s.State, params)
+	s.AddCleanup(func(*gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+}
+
+func (s *suite) TestPrunesOldTxns(c *gc.C) {
+	maxTxnAge := 24 * time.Hour
+	noPruneMB := int(1e9)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		// Ids made from a time alone are identical, so space the
+		// transactions a second apart.
+		offset := time.Duration(i) * time.Second
+		s.addTxn(c, now.Add(-maxTxnAge-time.Minute-offset), "prune")
+		s.addTxn(c, now.Add(-offset), "keep")
+	}
+	s.StartWorker(c, maxTxnAge, noPruneMB)
+
+	// Wait for all the old transactions to be removed.
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := s.txnsColl.Find(bson.M{"x": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			// All the recent transactions should still be there.
+			keepCount, err := s.txnsColl.Find(bson.M{"x": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 10)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestParamsFromConfig(c *gc.C) {
+	cfg, err := config.New(config.UseDefaults, testing.FakeConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(txnpruner.NewTxnPruneParamsFromConfig(cfg), jc.DeepEquals, txnpruner.NewTxnPruneParams())
+
+	cfg, err = cfg.Apply(map[string]interface{}{
+		"txn-prune-interval":          600,
+		"txn-prune-max-age":           3600,
+		"txn-prune-max-collection-mb": 256,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(txnpruner.NewTxnPruneParamsFromConfig(cfg), jc.DeepEquals, &txnpruner.TxnPruneParams{
+		MaxTxnAge:       time.Hour,
+		MaxCollectionMB: 256,
+		PruneInterval:   10 * time.Minute,
+	})
+}
+
+// addTxn adds an applied transaction started at the given time,
+// labelled so the test can find it.
+func (s *suite) addTxn(c *gc.C, t time.Time, label string) {
+	err := s.txnsColl.Insert(bson.M{
+		"_id": bson.NewObjectIdWithTime(t),
+		"s":   6,
+		"x":   label,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
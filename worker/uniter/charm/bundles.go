@@ -43,7 +43,8 @@ func (d *BundlesDir) Read(info BundleInfo, abort <-chan struct{}) (Bundle, error
 
 // download fetches the supplied charm and checks that it has the correct sha256
 // hash, then copies it into the directory. If a value is received on abort, the
-// download will be stopped.
+// download will be stopped. A download that fails part way through is resumed
+// by the next call.
 func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error) {
 	archiveURLs, err := info.ArchiveURLs()
 	if err != nil {
@@ -54,11 +55,12 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
+	partialPath := d.partialPath(info)
 	var st downloader.Status
 	for _, archiveURL := range archiveURLs {
 		aurl := archiveURL.String()
 		logger.Infof("downloading %s from %s", info.URL(), aurl)
-		st, err = tryDownload(aurl, dir, abort)
+		st, err = tryDownload(aurl, partialPath, abort)
 		if err == nil {
 			break
 		}
@@ -77,6 +79,11 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 		return err
 	}
 	if actualSha256 != archiveSha256 {
+		// The data cannot be resumed, so start again next time.
+		st.File.Close()
+		if err := os.Remove(partialPath); err != nil {
+			logger.Warningf("cannot remove partial download %q: %v", partialPath, err)
+		}
 		return fmt.Errorf(
 			"expected sha256 %q, got %q", archiveSha256, actualSha256,
 		)
@@ -90,13 +97,13 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 	return os.Rename(st.File.Name(), d.bundlePath(info))
 }
 
-func tryDownload(url, dir string, abort <-chan struct{}) (downloader.Status, error) {
+func tryDownload(url, path string, abort <-chan struct{}) (downloader.Status, error) {
 	// Downloads always go through the API server, which at
 	// present cannot be verified due to the certificates
 	// being inadequate. We always verify the SHA-256 hash,
 	// and the data transferred is not sensitive, so this
 	// does not pose a problem.
-	dl := downloader.New(url, dir, utils.NoVerifySSLHostnames)
+	dl := downloader.NewResumable(url, path, utils.NoVerifySSLHostnames)
 	defer dl.Stop()
 	select {
 	case <-abort:
@@ -122,6 +129,12 @@ func (d *BundlesDir) bundleURLPath(url *charm.URL) string {
 	return path.Join(d.path, charm.Quote(url.String()))
 }
 
+// partialPath returns the path to the location where the charm bundle
+// identified by info is saved while it is being downloaded.
+func (d *BundlesDir) partialPath(info BundleInfo) string {
+	return path.Join(d.downloadsPath(), charm.Quote(info.URL().String())+".partial")
+}
+
 // downloadsPath returns the path to the directory into which charms are
 // downloaded.
 func (d *BundlesDir) downloadsPath() string {
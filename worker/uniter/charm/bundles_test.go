@@ -166,6 +166,36 @@ func (s *BundlesDirSuite) TestGet(c *gc.C) {
 	}
 }
 
+func (s *BundlesDirSuite) TestResumeDownload(c *gc.C) {
+	bunsdir := filepath.Join(c.MkDir(), "bundles")
+	d := charm.NewBundlesDir(bunsdir)
+	apiCharm, sch, bundata := s.AddCharm(c)
+
+	// Leave the start of the bundle from an earlier download.
+	downloadsDir := filepath.Join(bunsdir, "downloads")
+	err := os.MkdirAll(downloadsDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	partialPath := filepath.Join(downloadsDir, corecharm.Quote(sch.URL().String())+".partial")
+	offset := len(bundata) / 2
+	err = ioutil.WriteFile(partialPath, bundata[:offset], 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	gitjujutesting.Server.Response(404, nil, nil)
+	gitjujutesting.Server.Response(206, map[string]string{
+		"Content-Range": fmt.Sprintf("bytes %d-%d/%d", offset, len(bundata)-1, len(bundata)),
+	}, bundata[offset:])
+	ch, err := d.Read(apiCharm, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertCharm(c, ch, sch)
+
+	req := gitjujutesting.Server.WaitRequest()
+	c.Assert(req.Header.Get("Range"), gc.Equals, fmt.Sprintf("bytes=%d-", offset))
+	req = gitjujutesting.Server.WaitRequest()
+	c.Assert(req.Header.Get("Range"), gc.Equals, fmt.Sprintf("bytes=%d-", offset))
+	_, err = os.Stat(partialPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func readHash(c *gc.C, path string) ([]byte, string) {
 	data, err := ioutil.ReadFile(path)
 	c.Assert(err, jc.ErrorIsNil)
@@ -13,7 +13,9 @@ func SetUniterObserver(u *Uniter, observer UniterExecutionObserver) {
 }
 
 var (
-	ActiveMetricsTimer = &activeMetricsTimer
+	ActiveMetricsTimer               = &activeMetricsTimer
+	HookRetryDelay                   = hookRetryDelay
+	CharmAllowsParallelRelationHooks = charmAllowsParallelRelationHooks
 )
 
 // manualTicker will be used to generate collect-metrics events
@@ -34,24 +34,29 @@ type filter struct {
 	// The out* chans, when set to the corresponding out*On chan (rather than
 	// nil) indicate that an event of the appropriate type is ready to send
 	// to the client.
-	outConfig        chan struct{}
-	outConfigOn      chan struct{}
-	outAction        chan string
-	outActionOn      chan string
-	outUpgrade       chan *charm.URL
-	outUpgradeOn     chan *charm.URL
-	outResolved      chan params.ResolvedMode
-	outResolvedOn    chan params.ResolvedMode
-	outRelations     chan []int
-	outRelationsOn   chan []int
-	outMeterStatus   chan struct{}
-	outMeterStatusOn chan struct{}
-	outStorage       chan []names.StorageTag
-	outStorageOn     chan []names.StorageTag
+	outConfig           chan struct{}
+	outConfigOn         chan struct{}
+	outAction           chan string
+	outActionOn         chan string
+	outUpgrade          chan *charm.URL
+	outUpgradeOn        chan *charm.URL
+	outResolved         chan params.ResolvedMode
+	outResolvedOn       chan params.ResolvedMode
+	outRelations        chan []int
+	outRelationsOn      chan []int
+	outMeterStatus      chan struct{}
+	outMeterStatusOn    chan struct{}
+	outStorage          chan []names.StorageTag
+	outStorageOn        chan []names.StorageTag
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
+	outUpgradeSeries    chan struct{}
+	outUpgradeSeriesOn  chan struct{}
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
-	wantForcedUpgrade chan bool
-	wantResolved      chan struct{}
+	wantForcedUpgrade  chan bool
+	wantResolved       chan struct{}
+	wantLeaderSettings chan bool
 
 	// discardConfig is used to indicate that any pending config event
 	// should be discarded.
@@ -98,35 +103,43 @@ type filter struct {
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// leaderSettingsWanted records whether leader settings events
+	// should be sent.
+	leaderSettingsWanted bool
 }
 
 // NewFilter returns a filter that handles state changes pertaining to the
 // supplied unit.
 func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
 	f := &filter{
-		st:                st,
-		outUnitDying:      make(chan struct{}),
-		outConfig:         nil,
-		outConfigOn:       make(chan struct{}),
-		outAction:         nil,
-		outActionOn:       make(chan string),
-		outUpgrade:        nil,
-		outUpgradeOn:      make(chan *charm.URL),
-		outResolved:       nil,
-		outResolvedOn:     make(chan params.ResolvedMode),
-		outRelations:      nil,
-		outRelationsOn:    make(chan []int),
-		outMeterStatus:    nil,
-		outMeterStatusOn:  make(chan struct{}),
-		outStorage:        nil,
-		outStorageOn:      make(chan []names.StorageTag),
-		wantForcedUpgrade: make(chan bool),
-		wantResolved:      make(chan struct{}),
-		discardConfig:     make(chan struct{}),
-		setCharm:          make(chan *charm.URL),
-		didSetCharm:       make(chan struct{}),
-		clearResolved:     make(chan struct{}),
-		didClearResolved:  make(chan struct{}),
+		st:                  st,
+		outUnitDying:        make(chan struct{}),
+		outConfig:           nil,
+		outConfigOn:         make(chan struct{}),
+		outAction:           nil,
+		outActionOn:         make(chan string),
+		outUpgrade:          nil,
+		outUpgradeOn:        make(chan *charm.URL),
+		outResolved:         nil,
+		outResolvedOn:       make(chan params.ResolvedMode),
+		outRelations:        nil,
+		outRelationsOn:      make(chan []int),
+		outMeterStatus:      nil,
+		outMeterStatusOn:    make(chan struct{}),
+		outStorage:          nil,
+		outStorageOn:        make(chan []names.StorageTag),
+		outLeaderSettings:   nil,
+		outLeaderSettingsOn: make(chan struct{}),
+		outUpgradeSeriesOn:  make(chan struct{}),
+		wantForcedUpgrade:   make(chan bool),
+		wantResolved:        make(chan struct{}),
+		wantLeaderSettings:  make(chan bool),
+		discardConfig:       make(chan struct{}),
+		setCharm:            make(chan *charm.URL),
+		didSetCharm:         make(chan struct{}),
+		clearResolved:       make(chan struct{}),
+		didClearResolved:    make(chan struct{}),
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -200,6 +213,30 @@ func (f *filter) StorageEvents() <-chan []names.StorageTag {
 	return f.outStorageOn
 }
 
+// LeaderSettingsEvents returns a channel that will receive a signal
+// whenever the service's leader settings change, while leader settings
+// events are wanted.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
+// UpgradeSeriesEvents returns a channel that will receive a signal
+// whenever the series upgrade of the unit's machine changes.
+func (f *filter) UpgradeSeriesEvents() <-chan struct{} {
+	return f.outUpgradeSeriesOn
+}
+
+// WantLeaderSettingsEvents controls whether the filter will generate
+// leader settings events. When they start to be wanted, an event is
+// generated immediately, because the settings may have changed while
+// they were not.
+func (f *filter) WantLeaderSettingsEvents(want bool) {
+	select {
+	case <-f.tomb.Dying():
+	case f.wantLeaderSettings <- want:
+	}
+}
+
 // WantUpgradeEvent controls whether the filter will generate upgrade
 // events for unforced service charm changes.
 func (f *filter) WantUpgradeEvent(mustForce bool) {
@@ -354,6 +391,31 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(storagew, &f.tomb)
+	// Leader settings cannot be watched through API servers that
+	// predate them.
+	var leaderSettingsw apiwatcher.NotifyWatcher
+	var leaderSettingsChanges <-chan struct{}
+	if f.st.LeadershipSettings != nil {
+		leaderSettingsw, err = f.st.LeadershipSettings.WatchLeadershipSettings(f.service.Name())
+		if err != nil {
+			return err
+		}
+		leaderSettingsChanges = leaderSettingsw.Changes()
+	}
+	defer f.maybeStopWatcher(leaderSettingsw)
+	// Series upgrades cannot be watched through API servers that
+	// predate them.
+	var upgradeSeriesw apiwatcher.NotifyWatcher
+	var upgradeSeriesChanges <-chan struct{}
+	upgradeSeriesw, err = f.unit.WatchUpgradeSeriesNotifications()
+	if params.IsCodeNotImplemented(err) {
+		filterLogger.Debugf("series upgrades not supported by the API server")
+	} else if err != nil {
+		return err
+	} else {
+		upgradeSeriesChanges = upgradeSeriesw.Changes()
+	}
+	defer f.maybeStopWatcher(upgradeSeriesw)
 
 	// Config events cannot be meaningfully discarded until one is available;
 	// once we receive the initial config and address changes, we unblock
@@ -457,6 +519,20 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 				tags[i] = tag
 			}
 			f.storageChanged(tags)
+		case _, ok = <-leaderSettingsChanges:
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.EnsureErr(leaderSettingsw)
+			}
+			if f.leaderSettingsWanted {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
+		case _, ok = <-upgradeSeriesChanges:
+			filterLogger.Debugf("got series upgrade change")
+			if !ok {
+				return watcher.EnsureErr(upgradeSeriesw)
+			}
+			f.outUpgradeSeries = f.outUpgradeSeriesOn
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -482,6 +558,12 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			filterLogger.Debugf("sent storage event")
 			f.outStorage = nil
 			f.storage = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings event")
+			f.outLeaderSettings = nil
+		case f.outUpgradeSeries <- nothing:
+			filterLogger.Debugf("sent series upgrade event")
+			f.outUpgradeSeries = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -528,6 +610,14 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			if err = f.upgradeChanged(); err != nil {
 				return err
 			}
+		case want := <-f.wantLeaderSettings:
+			filterLogger.Debugf("want leader settings events %v", want)
+			if want && !f.leaderSettingsWanted && leaderSettingsChanges != nil {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			} else if !want {
+				f.outLeaderSettings = nil
+			}
+			f.leaderSettingsWanted = want
 		case <-f.wantResolved:
 			filterLogger.Debugf("want resolved event")
 			if f.resolved != params.ResolvedNone {
@@ -598,6 +598,61 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	meterC.AssertOneReceive()
 }
 
+func (s *FilterSuite) TestUpgradeSeriesEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	upgradeSeriesC := s.notifyAsserterC(c, f.UpgradeSeriesEvents())
+	// The initial series upgrade state triggers an event.
+	upgradeSeriesC.AssertOneReceive()
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	upgradeSeriesC.AssertOneReceive()
+
+	err = s.unit.SetUpgradeSeriesStatus(state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	upgradeSeriesC.AssertOneReceive()
+}
+
+func (s *FilterSuite) setLeaderSetting(c *gc.C, key, value string) {
+	settings, err := s.State.ReadLeadershipSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set(key, value)
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FilterSuite) TestLeaderSettingsEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	leaderC := s.notifyAsserterC(c, f.LeaderSettingsEvents())
+	// No events are sent until they are wanted.
+	leaderC.AssertNoReceive()
+	s.setLeaderSetting(c, "foo", "bar")
+	leaderC.AssertNoReceive()
+
+	// Wanting events sends one immediately...
+	f.WantLeaderSettingsEvents(true)
+	leaderC.AssertOneReceive()
+	// ...but wanting them again does not.
+	f.WantLeaderSettingsEvents(true)
+	leaderC.AssertNoReceive()
+
+	// Changes are reported, and coalesced.
+	s.setLeaderSetting(c, "foo", "baz")
+	s.setLeaderSetting(c, "foo", "qux")
+	leaderC.AssertOneReceive()
+
+	// Pending events are discarded when events stop being wanted.
+	s.setLeaderSetting(c, "foo", "quux")
+	f.WantLeaderSettingsEvents(false)
+	leaderC.AssertNoReceive()
+	s.setLeaderSetting(c, "foo", "corge")
+	leaderC.AssertNoReceive()
+}
+
 func (s *FilterSuite) TestStorageEvents(c *gc.C) {
 	storageCharm := s.AddTestingCharm(c, "storage-block2")
 	svc := s.AddTestingServiceWithStorage(c, "storage-block2", storageCharm, map[string]state.StorageConstraints{
@@ -58,6 +58,21 @@ type Filter interface {
 	// associated storage instances whose Life status has changed.
 	StorageEvents() <-chan []names.StorageTag
 
+	// LeaderSettingsEvents returns a channel that will receive a signal
+	// whenever the service's leader settings change, while leader settings
+	// events are wanted.
+	LeaderSettingsEvents() <-chan struct{}
+
+	// WantLeaderSettingsEvents controls whether the filter will generate
+	// leader settings events. When they start to be wanted, an event is
+	// generated immediately.
+	WantLeaderSettingsEvents(want bool)
+
+	// UpgradeSeriesEvents returns a channel that will receive a signal
+	// whenever the series upgrade of the unit's machine changes. An
+	// event is always generated once the filter has started.
+	UpgradeSeriesEvents() <-chan struct{}
+
 	// WantUpgradeEvent controls whether the filter will generate upgrade
 	// events for unforced service charm changes.
 	WantUpgradeEvent(mustForce bool)
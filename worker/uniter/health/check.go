@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health
+
+import (
+	"bytes"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/version"
+)
+
+// check runs the given probe once, with the charm directory as its
+// working directory, and returns an error describing why it failed.
+func check(probe Probe, charmDir string) error {
+	if probe.HTTP != "" {
+		return checkHTTP(probe.HTTP, probe.Timeout)
+	}
+	return checkCommand(probe.Command, charmDir, probe.Timeout)
+}
+
+func checkHTTP(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.Errorf("%s returned %q", url, resp.Status)
+	}
+	return nil
+}
+
+func checkCommand(command, charmDir string, timeout time.Duration) error {
+	var cmd *exec.Cmd
+	if version.Current.OS == version.Windows {
+		cmd = exec.Command("cmd.exe", "/C", command)
+	} else {
+		cmd = exec.Command("/bin/bash", "-c", command)
+	}
+	cmd.Dir = charmDir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			return nil
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return errors.Annotate(err, out)
+		}
+		return errors.Trace(err)
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		return errors.Errorf("timed out after %v", timeout)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package health implements the workload health probes that charms
+// may declare, and the worker that runs them on behalf of a unit.
+//
+// Probes are declared in a health.yaml file in the charm directory:
+//
+//	probes:
+//	  web:
+//	    http: http://localhost:8080/health
+//	    interval: 30s
+//	    timeout: 5s
+//	    threshold: 3
+//	  db:
+//	    command: hooks/check-db
+//
+// Each probe either runs a command in the charm directory, which must
+// exit successfully, or requests a URL, which must respond with a
+// success or redirect status code.
+package health
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// FileName is the name of the file in the charm directory in which
+// health probes are declared.
+const FileName = "health.yaml"
+
+const (
+	// DefaultInterval is how often a probe is run if its interval
+	// is not declared.
+	DefaultInterval = 30 * time.Second
+
+	// DefaultTimeout is how long a probe may take before it fails if
+	// its timeout is not declared.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultThreshold is how many consecutive times a probe must fail
+	// before the workload is reported unhealthy, if its threshold is
+	// not declared.
+	DefaultThreshold = 1
+)
+
+// Probe describes a check of the health of a charm's workload.
+type Probe struct {
+	// Name holds the name of the probe, unique within the charm.
+	Name string
+
+	// Command holds a command to run in the charm directory; the
+	// workload is healthy if it exits successfully.
+	Command string
+
+	// HTTP holds a URL to request; the workload is healthy if the
+	// response has a success or redirect status code.
+	HTTP string
+
+	// Interval holds how often the probe is run.
+	Interval time.Duration
+
+	// Timeout holds how long the probe may run before it fails.
+	Timeout time.Duration
+
+	// Threshold holds how many consecutive times the probe must fail
+	// before the workload is reported unhealthy.
+	Threshold int
+}
+
+// probesDoc holds the contents of a health.yaml file.
+type probesDoc struct {
+	Probes map[string]probeDoc `yaml:"probes"`
+}
+
+type probeDoc struct {
+	Command   string `yaml:"command"`
+	HTTP      string `yaml:"http"`
+	Interval  string `yaml:"interval"`
+	Timeout   string `yaml:"timeout"`
+	Threshold int    `yaml:"threshold"`
+}
+
+// ReadProbes returns the health probes declared by the charm in the
+// given directory, sorted by name. It returns no probes if the charm
+// does not declare any.
+func ReadProbes(charmDir string) ([]Probe, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	probes, err := ParseProbes(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read %s", FileName)
+	}
+	return probes, nil
+}
+
+// ParseProbes parses the health probes declared in the given YAML
+// data, and returns them sorted by name.
+func ParseProbes(data []byte) ([]Probe, error) {
+	var doc probesDoc
+	if err := goyaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	probes := make([]Probe, 0, len(doc.Probes))
+	for name, pdoc := range doc.Probes {
+		probe, err := newProbe(name, pdoc)
+		if err != nil {
+			return nil, errors.Annotatef(err, "probe %q", name)
+		}
+		probes = append(probes, probe)
+	}
+	sort.Sort(probesByName(probes))
+	return probes, nil
+}
+
+func newProbe(name string, doc probeDoc) (Probe, error) {
+	probe := Probe{
+		Name:      name,
+		Command:   doc.Command,
+		HTTP:      doc.HTTP,
+		Interval:  DefaultInterval,
+		Timeout:   DefaultTimeout,
+		Threshold: DefaultThreshold,
+	}
+	switch {
+	case probe.Command == "" && probe.HTTP == "":
+		return Probe{}, errors.New("no command or http endpoint specified")
+	case probe.Command != "" && probe.HTTP != "":
+		return Probe{}, errors.New("both command and http endpoint specified")
+	}
+	var err error
+	if doc.Interval != "" {
+		if probe.Interval, err = parsePositiveDuration(doc.Interval); err != nil {
+			return Probe{}, errors.Annotate(err, "invalid interval")
+		}
+	}
+	if doc.Timeout != "" {
+		if probe.Timeout, err = parsePositiveDuration(doc.Timeout); err != nil {
+			return Probe{}, errors.Annotate(err, "invalid timeout")
+		}
+	}
+	if doc.Threshold < 0 {
+		return Probe{}, errors.Errorf("invalid threshold %d", doc.Threshold)
+	} else if doc.Threshold > 0 {
+		probe.Threshold = doc.Threshold
+	}
+	return probe, nil
+}
+
+func parsePositiveDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.Errorf("%q is not positive", value)
+	}
+	return d, nil
+}
+
+type probesByName []Probe
+
+func (p probesByName) Len() int           { return len(p) }
+func (p probesByName) Less(i, j int) bool { return p[i].Name < p[j].Name }
+func (p probesByName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/health"
+)
+
+type probeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&probeSuite{})
+
+func (s *probeSuite) TestReadProbesNoFile(c *gc.C) {
+	probes, err := health.ReadProbes(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(probes, gc.HasLen, 0)
+}
+
+func (s *probeSuite) TestReadProbes(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, health.FileName), []byte(`
+probes:
+  web:
+    http: http://localhost:8080/health
+    interval: 1m
+    timeout: 5s
+    threshold: 3
+  db:
+    command: hooks/check-db
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	probes, err := health.ReadProbes(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(probes, jc.DeepEquals, []health.Probe{{
+		Name:      "db",
+		Command:   "hooks/check-db",
+		Interval:  health.DefaultInterval,
+		Timeout:   health.DefaultTimeout,
+		Threshold: health.DefaultThreshold,
+	}, {
+		Name:      "web",
+		HTTP:      "http://localhost:8080/health",
+		Interval:  time.Minute,
+		Timeout:   5 * time.Second,
+		Threshold: 3,
+	}})
+}
+
+func (s *probeSuite) TestReadProbesInvalid(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, health.FileName), []byte("probes: [x"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = health.ReadProbes(dir)
+	c.Assert(err, gc.ErrorMatches, "cannot read health.yaml: .*")
+}
+
+func (s *probeSuite) TestParseProbesErrors(c *gc.C) {
+	for i, test := range []struct {
+		yaml     string
+		errMatch string
+	}{{
+		yaml:     "probes: {web: {}}",
+		errMatch: `probe "web": no command or http endpoint specified`,
+	}, {
+		yaml:     "probes: {web: {command: check, http: 'http://localhost/'}}",
+		errMatch: `probe "web": both command and http endpoint specified`,
+	}, {
+		yaml:     "probes: {web: {command: check, interval: often}}",
+		errMatch: `probe "web": invalid interval: time: invalid duration .*often.*`,
+	}, {
+		yaml:     "probes: {web: {command: check, timeout: 0s}}",
+		errMatch: `probe "web": invalid timeout: "0s" is not positive`,
+	}, {
+		yaml:     "probes: {web: {command: check, threshold: -1}}",
+		errMatch: `probe "web": invalid threshold -1`,
+	}} {
+		c.Logf("test %d: %s", i, test.yaml)
+		_, err := health.ParseProbes([]byte(test.yaml))
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
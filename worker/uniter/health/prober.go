@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.health")
+
+// StatusSetter is implemented by the unit whose workload is probed.
+type StatusSetter interface {
+	SetUnitStatus(status params.Status, info string, data map[string]interface{}) error
+}
+
+// Prober runs the health probes of a charm's workload, and reports the
+// workload status of the unit when its health changes: the unit is in
+// error while any probe has failed at least its threshold number of
+// consecutive times, and active again once all probes pass.
+//
+// Statuses set by the charm are left alone until the health of the
+// workload changes.
+type Prober struct {
+	tomb     tomb.Tomb
+	unit     StatusSetter
+	charmDir string
+	probes   []Probe
+}
+
+// NewProber starts a Prober that runs the given probes in the charm
+// directory, and reports the health of the workload to the unit.
+func NewProber(unit StatusSetter, charmDir string, probes []Probe) *Prober {
+	p := &Prober{
+		unit:     unit,
+		charmDir: charmDir,
+		probes:   probes,
+	}
+	go func() {
+		defer p.tomb.Done()
+		p.tomb.Kill(p.loop())
+	}()
+	return p
+}
+
+// Kill asks the prober to stop, without waiting for it to do so.
+func (p *Prober) Kill() {
+	p.tomb.Kill(nil)
+}
+
+// Wait waits for the prober to stop, and returns the error that
+// stopped it.
+func (p *Prober) Wait() error {
+	return p.tomb.Wait()
+}
+
+// Stop stops the prober and returns any error it encountered.
+func (p *Prober) Stop() error {
+	p.tomb.Kill(nil)
+	return p.tomb.Wait()
+}
+
+// probeResult holds the outcome of a single run of a probe.
+type probeResult struct {
+	name string
+	err  error
+}
+
+func (p *Prober) loop() error {
+	results := make(chan probeResult)
+	thresholds := make(map[string]int)
+	for _, probe := range p.probes {
+		thresholds[probe.Name] = probe.Threshold
+		go p.run(probe, results)
+	}
+	failures := make(map[string]int)
+	lastErrors := make(map[string]error)
+	// failing holds the name of the probe the unit was last reported
+	// unhealthy for, or is empty if it was not.
+	failing := ""
+	for {
+		select {
+		case <-p.tomb.Dying():
+			return tomb.ErrDying
+		case result := <-results:
+			if result.err == nil {
+				failures[result.name] = 0
+			} else {
+				logger.Debugf("health probe %q failed: %v", result.name, result.err)
+				failures[result.name]++
+				lastErrors[result.name] = result.err
+			}
+			if failing != "" && failures[failing] >= thresholds[failing] {
+				// Still unhealthy for the same reason.
+				continue
+			}
+			// Probes are sorted by name, so the probe reported
+			// is stable while several are failing.
+			unhealthy := ""
+			for _, probe := range p.probes {
+				if failures[probe.Name] >= probe.Threshold {
+					unhealthy = probe.Name
+					break
+				}
+			}
+			if unhealthy == failing {
+				continue
+			}
+			if err := p.report(unhealthy, lastErrors[unhealthy]); err != nil {
+				return errors.Trace(err)
+			}
+			failing = unhealthy
+		}
+	}
+}
+
+// report sets the workload status of the unit to error because of the
+// named probe's failure, or to active if no probe is named.
+func (p *Prober) report(name string, cause error) error {
+	if name == "" {
+		logger.Infof("workload is healthy")
+		return p.unit.SetUnitStatus(params.StatusActive, "", nil)
+	}
+	info := fmt.Sprintf("health probe %q failed: %v", name, cause)
+	logger.Infof("workload is unhealthy: %s", info)
+	return p.unit.SetUnitStatus(params.StatusError, info, map[string]interface{}{
+		"probe": name,
+	})
+}
+
+// run runs the probe once straight away, and then at its interval,
+// sending each result to the prober's loop.
+func (p *Prober) run(probe Probe, results chan<- probeResult) {
+	var delay time.Duration
+	for {
+		select {
+		case <-p.tomb.Dying():
+			return
+		case <-time.After(delay):
+		}
+		result := probeResult{probe.Name, check(probe, p.charmDir)}
+		select {
+		case <-p.tomb.Dying():
+			return
+		case results <- result:
+		}
+		delay = probe.Interval
+	}
+}
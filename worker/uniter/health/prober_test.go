@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package health_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/health"
+)
+
+type proberSuite struct {
+	testing.BaseSuite
+	charmDir string
+	unit     *fakeUnit
+}
+
+var _ = gc.Suite(&proberSuite{})
+
+func (s *proberSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.charmDir = c.MkDir()
+	s.unit = &fakeUnit{statuses: make(chan unitStatus, 10)}
+}
+
+// fileProbe returns a probe that passes while the file "healthy"
+// exists in the charm directory.
+func (s *proberSuite) fileProbe(c *gc.C, threshold int) health.Probe {
+	if runtime.GOOS == "windows" {
+		c.Skip("test uses bash commands")
+	}
+	return health.Probe{
+		Name:      "check",
+		Command:   "test -f healthy",
+		Interval:  10 * time.Millisecond,
+		Timeout:   testing.LongWait,
+		Threshold: threshold,
+	}
+}
+
+func (s *proberSuite) setHealthy(c *gc.C, healthy bool) {
+	path := filepath.Join(s.charmDir, "healthy")
+	if healthy {
+		err := ioutil.WriteFile(path, nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	} else {
+		err := os.Remove(path)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *proberSuite) assertStatus(c *gc.C, expect unitStatus) {
+	select {
+	case status := <-s.unit.statuses:
+		c.Assert(status, jc.DeepEquals, expect)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for status %q", expect.status)
+	}
+}
+
+func (s *proberSuite) assertNoStatus(c *gc.C) {
+	select {
+	case status := <-s.unit.statuses:
+		c.Fatalf("unexpected status %#v", status)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *proberSuite) TestHealthyNotReported(c *gc.C) {
+	probe := s.fileProbe(c, 1)
+	s.setHealthy(c, true)
+	prober := health.NewProber(s.unit, s.charmDir, []health.Probe{probe})
+	defer func() { c.Assert(prober.Stop(), jc.ErrorIsNil) }()
+	s.assertNoStatus(c)
+}
+
+func (s *proberSuite) TestUnhealthyAndRecovered(c *gc.C) {
+	probe := s.fileProbe(c, 3)
+	prober := health.NewProber(s.unit, s.charmDir, []health.Probe{probe})
+	defer func() { c.Assert(prober.Stop(), jc.ErrorIsNil) }()
+
+	s.assertStatus(c, unitStatus{
+		status: params.StatusError,
+		info:   `health probe "check" failed: exit status 1`,
+		data:   map[string]interface{}{"probe": "check"},
+	})
+	// Further failures are not reported again.
+	s.assertNoStatus(c)
+
+	s.setHealthy(c, true)
+	s.assertStatus(c, unitStatus{status: params.StatusActive})
+	s.assertNoStatus(c)
+}
+
+func (s *proberSuite) TestHTTPProbe(c *gc.C) {
+	var mu sync.Mutex
+	code := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	prober := health.NewProber(s.unit, s.charmDir, []health.Probe{{
+		Name:      "web",
+		HTTP:      server.URL,
+		Interval:  10 * time.Millisecond,
+		Timeout:   testing.LongWait,
+		Threshold: 1,
+	}})
+	defer func() { c.Assert(prober.Stop(), jc.ErrorIsNil) }()
+
+	select {
+	case status := <-s.unit.statuses:
+		c.Assert(status.status, gc.Equals, params.StatusError)
+		c.Assert(status.info, gc.Matches, `health probe "web" failed: .* returned "503 Service Unavailable"`)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+
+	mu.Lock()
+	code = http.StatusOK
+	mu.Unlock()
+	s.assertStatus(c, unitStatus{status: params.StatusActive})
+}
+
+func (s *proberSuite) TestReportError(c *gc.C) {
+	probe := s.fileProbe(c, 1)
+	s.unit.err = errors.New("boom")
+	prober := health.NewProber(s.unit, s.charmDir, []health.Probe{probe})
+	err := prober.Wait()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type unitStatus struct {
+	status params.Status
+	info   string
+	data   map[string]interface{}
+}
+
+type fakeUnit struct {
+	statuses chan unitStatus
+	err      error
+}
+
+func (u *fakeUnit) SetUnitStatus(status params.Status, info string, data map[string]interface{}) error {
+	u.statuses <- unitStatus{status, info, data}
+	return u.err
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hook
+
+import (
+	"sync/atomic"
+)
+
+// Counter tracks the total number of hooks scheduled by a set of Sources
+// created with NewCountingSource. It is safe for concurrent use.
+type Counter struct {
+	count int64
+}
+
+// Count returns the number of hooks currently scheduled.
+func (c *Counter) Count() int {
+	return int(atomic.LoadInt64(&c.count))
+}
+
+func (c *Counter) add(delta int) {
+	atomic.AddInt64(&c.count, int64(delta))
+}
+
+// countingSource wraps a Source and records the number of hooks it has
+// scheduled in a Counter.
+type countingSource struct {
+	Source
+	counter *Counter
+	size    int
+}
+
+// NewCountingSource returns a Source that behaves exactly like the supplied
+// one, but additionally tracks the number of hooks it has scheduled in the
+// supplied Counter. If the wrapped Source does not implement Sizer, it is
+// treated as having a single scheduled hook whenever it is not empty.
+func NewCountingSource(source Source, counter *Counter) Source {
+	return &countingSource{
+		Source:  source,
+		counter: counter,
+	}
+}
+
+// Empty is defined in Source.
+func (s *countingSource) Empty() bool {
+	// The Source's client must call Empty after every Apply or Pop before
+	// it can use the schedule, so this is where the size is refreshed.
+	empty := s.Source.Empty()
+	size := 0
+	if sizer, ok := s.Source.(Sizer); ok {
+		size = sizer.Size()
+	} else if !empty {
+		size = 1
+	}
+	s.counter.add(size - s.size)
+	s.size = size
+	return empty
+}
+
+// Stop is defined in Source.
+func (s *countingSource) Stop() error {
+	s.counter.add(-s.size)
+	s.size = 0
+	return s.Source.Stop()
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hook_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/hook/hooktesting"
+)
+
+type CounterSuite struct{}
+
+var _ = gc.Suite(&CounterSuite{})
+
+func (s *CounterSuite) TestCountsSizer(c *gc.C) {
+	counter := &hook.Counter{}
+	source1 := hook.NewCountingSource(
+		hook.NewListSource(hooktesting.HookList(hooks.Install, hooks.Start)), counter,
+	)
+	source2 := hook.NewCountingSource(
+		hook.NewListSource(hooktesting.HookList(hooks.ConfigChanged)), counter,
+	)
+	c.Check(counter.Count(), gc.Equals, 0)
+
+	c.Check(source1.Empty(), jc.IsFalse)
+	c.Check(counter.Count(), gc.Equals, 2)
+	c.Check(source2.Empty(), jc.IsFalse)
+	c.Check(counter.Count(), gc.Equals, 3)
+
+	source1.Pop()
+	c.Check(source1.Empty(), jc.IsFalse)
+	c.Check(counter.Count(), gc.Equals, 2)
+
+	err := source2.Stop()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(counter.Count(), gc.Equals, 1)
+
+	source1.Pop()
+	c.Check(source1.Empty(), jc.IsTrue)
+	c.Check(counter.Count(), gc.Equals, 0)
+}
+
+func (s *CounterSuite) TestCountsNonSizer(c *gc.C) {
+	counter := &hook.Counter{}
+	inner := hooktesting.NewFullUnbufferedSource()
+	source := hook.NewCountingSource(inner, counter)
+
+	c.Check(source.Empty(), jc.IsFalse)
+	c.Check(counter.Count(), gc.Equals, 1)
+
+	err := source.Stop()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(counter.Count(), gc.Equals, 0)
+}
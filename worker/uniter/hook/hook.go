@@ -14,6 +14,17 @@ import (
 	"github.com/juju/juju/feature"
 )
 
+const (
+	// PreSeriesUpgrade is run before the series of the unit's machine
+	// is upgraded, so the charm can prepare for the new series. The
+	// unit's agent is paused once it has run.
+	PreSeriesUpgrade hooks.Kind = "pre-series-upgrade"
+
+	// PostSeriesUpgrade is run once the series of the unit's machine
+	// has been upgraded, so the charm can adapt to the new series.
+	PostSeriesUpgrade hooks.Kind = "post-series-upgrade"
+)
+
 // Info holds details required to execute a hook. Not all fields are
 // relevant to all Kind values.
 type Info struct {
@@ -45,6 +56,8 @@ func (hi Info) Validate() error {
 		fallthrough
 	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged:
 		return nil
+	case PreSeriesUpgrade, PostSeriesUpgrade:
+		return nil
 	case hooks.Action:
 		return fmt.Errorf("hooks.Kind Action is deprecated")
 	case hooks.StorageAttached, hooks.StorageDetached:
@@ -55,6 +68,11 @@ func (hi Info) Validate() error {
 			}
 			return nil
 		}
+	case hooks.LeaderElected, hooks.LeaderDeposed, hooks.LeaderSettingsChanged:
+		// TODO: stop checking feature flag once leader election has graduated.
+		if featureflag.Enabled(feature.LeaderElection) {
+			return nil
+		}
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
 }
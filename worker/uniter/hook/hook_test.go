@@ -53,10 +53,15 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetached, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hooks.LeaderElected}, ""},
+	{hook.Info{Kind: hooks.LeaderDeposed}, ""},
+	{hook.Info{Kind: hooks.LeaderSettingsChanged}, ""},
+	{hook.Info{Kind: hook.PreSeriesUpgrade}, ""},
+	{hook.Info{Kind: hook.PostSeriesUpgrade}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
-	s.SetFeatureFlags(feature.Storage)
+	s.SetFeatureFlags(feature.Storage, feature.LeaderElection)
 	featureflag.SetFlagsFromEnvironment(osenv.JujuFeatureFlagEnvKey)
 	for i, t := range validateTests {
 		c.Logf("test %d", i)
@@ -75,3 +80,12 @@ func (s *InfoSuite) TestStorageHooksRequireFeatureFlag(c *gc.C) {
 	err = hook.Info{Kind: hooks.StorageDetached}.Validate()
 	c.Assert(err, gc.ErrorMatches, `unknown hook kind "storage-detached"`)
 }
+
+func (s *InfoSuite) TestLeaderHooksRequireFeatureFlag(c *gc.C) {
+	err := hook.Info{Kind: hooks.LeaderElected}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown hook kind "leader-elected"`)
+	err = hook.Info{Kind: hooks.LeaderDeposed}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown hook kind "leader-deposed"`)
+	err = hook.Info{Kind: hooks.LeaderSettingsChanged}.Validate()
+	c.Assert(err, gc.ErrorMatches, `unknown hook kind "leader-settings-changed"`)
+}
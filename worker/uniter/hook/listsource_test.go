@@ -35,7 +35,8 @@ func (s *ListSourceSuite) TestQueue(c *gc.C) {
 	} {
 		c.Logf("test %d: %v", i, test)
 		source := hook.NewListSource(test)
-		for _, expect := range test {
+		for j, expect := range test {
+			c.Check(source.(hook.Sizer).Size(), gc.Equals, len(test)-j)
 			c.Check(source.Empty(), jc.IsFalse)
 			c.Check(source.Next(), gc.DeepEquals, expect)
 			source.Pop()
@@ -35,6 +35,12 @@ type Source interface {
 	Pop()
 }
 
+// Sizer is implemented by Sources that can report the number of hooks they
+// currently have scheduled.
+type Sizer interface {
+	Size() int
+}
+
 // SourceChange is the type of functions returned via Source.Changes().
 type SourceChange func() error
 
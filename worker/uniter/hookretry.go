@@ -0,0 +1,26 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+)
+
+// maxHookRetryDelay bounds the exponential backoff applied between
+// automatic retries of a failed hook.
+const maxHookRetryDelay = 10 * time.Minute
+
+// hookRetryDelay returns the time to wait before making the given
+// (zero-based) automatic retry of a failed hook. The initial delay
+// doubles with every prior attempt, up to maxHookRetryDelay.
+func hookRetryDelay(initial time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 0; i < attempt && delay < maxHookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxHookRetryDelay {
+		delay = maxHookRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter"
+)
+
+type HookRetryDelaySuite struct{}
+
+var _ = gc.Suite(&HookRetryDelaySuite{})
+
+func (*HookRetryDelaySuite) TestHookRetryDelay(c *gc.C) {
+	for i, test := range []struct {
+		initial time.Duration
+		attempt int
+		expect  time.Duration
+	}{
+		{5 * time.Second, 0, 5 * time.Second},
+		{5 * time.Second, 1, 10 * time.Second},
+		{5 * time.Second, 3, 40 * time.Second},
+		{5 * time.Second, 100, 10 * time.Minute},
+		{time.Hour, 0, 10 * time.Minute},
+	} {
+		c.Logf("test %d: initial %v, attempt %d", i, test.initial, test.attempt)
+		delay := uniter.HookRetryDelay(test.initial, test.attempt)
+		c.Check(delay, gc.Equals, test.expect)
+	}
+}
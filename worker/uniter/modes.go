@@ -8,13 +8,16 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/featureflag"
 	"gopkg.in/juju/charm.v5-unstable"
 	"gopkg.in/juju/charm.v5-unstable/hooks"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 )
 
@@ -42,6 +45,9 @@ func ModeContinue(u *Uniter) (next Mode, err error) {
 	if err := u.initializeMetricsCollector(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := u.initializeHealthProber(); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	var creator creator
 	switch opState.Kind {
@@ -107,6 +113,43 @@ func ModeUpgrading(curl *charm.URL) Mode {
 	}
 }
 
+// ModeUpgradingSeries takes the unit through the series upgrade of its
+// machine. The unit runs its pre-series-upgrade hook when the upgrade is
+// prepared, and then runs no other hooks until the upgrade is completed
+// and it has run its post-series-upgrade hook. Committing either hook
+// records the unit's progress, so an interrupted upgrade resumes where
+// it left off.
+func ModeUpgradingSeries(u *Uniter) (next Mode, err error) {
+	defer modeContext("ModeUpgradingSeries", &err)()
+	for {
+		status, err := u.unit.UpgradeSeriesStatus()
+		if params.IsCodeNotFound(err) {
+			return ModeContinue, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch status {
+		case params.UpgradeSeriesPrepareStarted:
+			return continueAfter(u, newSimpleRunHookOp(hook.PreSeriesUpgrade))
+		case params.UpgradeSeriesCompleteStarted:
+			return continueAfter(u, newSimpleRunHookOp(hook.PostSeriesUpgrade))
+		case params.UpgradeSeriesPrepareCompleted:
+			// The unit stays paused while the operating system of its
+			// machine is upgraded.
+			if err := u.unit.SetUnitStatus(params.StatusMaintenance, "paused for series upgrade", nil); err != nil {
+				return nil, errors.Trace(err)
+			}
+			select {
+			case <-u.tomb.Dying():
+				return nil, tomb.ErrDying
+			case <-u.f.UpgradeSeriesEvents():
+			}
+		default:
+			return ModeContinue, nil
+		}
+	}
+}
+
 // ModeTerminating marks the unit dead and returns ErrTerminateAgent.
 func ModeTerminating(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeTerminating", &err)()
@@ -164,6 +207,8 @@ func ModeTerminating(u *Uniter) (next Mode, err error) {
 // * service configuration changes
 // * charm upgrade requests
 // * relation changes
+// * leadership and leader settings changes
+// * series upgrades of the unit's machine
 // * unit death
 func ModeAbide(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeAbide", &err)()
@@ -174,7 +219,17 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 	if err := u.deployer.Fix(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	u.parallelRelationHooks, err = charmAllowsParallelRelationHooks(u.paths.State.CharmDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	u.deferredRelationHook = nil
 	if !u.ranConfigChanged {
+		// A unit that is already leader runs leader-elected before
+		// its first config-changed hook.
+		if leadershipHooksEnabled() && !opState.Leader && u.leadershipTracker.ClaimLeader().Wait() {
+			return continueAfter(u, newAcceptLeadershipOp())
+		}
 		return continueAfter(u, newSimpleRunHookOp(hooks.ConfigChanged))
 	}
 	if !opState.Started {
@@ -206,12 +261,36 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 // modeAbideAliveLoop handles all state changes for ModeAbide when the unit
 // is in an Alive state.
 func modeAbideAliveLoop(u *Uniter) (Mode, error) {
+	if err := u.updateHooksSuspended(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	for {
 		lastCollectMetrics := time.Unix(u.operationState().CollectMetricsTime, 0)
 		collectMetricsSignal := u.collectMetricsAt(
 			time.Now(), lastCollectMetrics, metricsPollInterval,
 		)
+		configEvents := u.f.ConfigEvents()
+		relationHooks := u.relations.Hooks()
+		storageHooks := u.storage.Hooks()
+		leadershipChanges := u.leadershipChanges()
+		leaderSettingsEvents := u.f.LeaderSettingsEvents()
+		if u.hooksSuspended {
+			// Leave the hooks queued until the meter status improves.
+			collectMetricsSignal = nil
+			configEvents = nil
+			relationHooks = nil
+			storageHooks = nil
+			leadershipChanges = nil
+			leaderSettingsEvents = nil
+		}
 		var creator creator
+		if hookInfo := u.deferredRelationHook; hookInfo != nil && !u.hooksSuspended {
+			u.deferredRelationHook = nil
+			if err := u.runOperation(u.relationHooksCreator(*hookInfo)); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
 		select {
 		case <-u.tomb.Dying():
 			return nil, tomb.ErrDying
@@ -219,22 +298,43 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			return modeAbideDyingLoop(u)
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
+		case <-u.f.UpgradeSeriesEvents():
+			return ModeUpgradingSeries, nil
 		case ids := <-u.f.RelationsEvents():
 			creator = newUpdateRelationsOp(ids)
 		case actionId := <-u.f.ActionEvents():
 			creator = newActionOp(actionId)
 		case tags := <-u.f.StorageEvents():
 			creator = newUpdateStorageOp(tags)
-		case <-u.f.ConfigEvents():
+		case <-configEvents:
 			creator = newSimpleRunHookOp(hooks.ConfigChanged)
 		case <-u.f.MeterStatusEvents():
+			if err := u.updateHooksSuspended(); err != nil {
+				return nil, errors.Trace(err)
+			}
 			creator = newSimpleRunHookOp(hooks.MeterStatusChanged)
 		case <-collectMetricsSignal:
 			creator = newSimpleRunHookOp(hooks.CollectMetrics)
-		case hookInfo := <-u.relations.Hooks():
-			creator = newRunHookOp(hookInfo)
-		case hookInfo := <-u.storage.Hooks():
+		case hookInfo := <-relationHooks:
+			creator = u.relationHooksCreator(hookInfo)
+		case hookInfo := <-storageHooks:
 			creator = newRunHookOp(hookInfo)
+		case <-leaderSettingsEvents:
+			creator = newSimpleRunHookOp(hooks.LeaderSettingsChanged)
+		case <-leadershipChanges:
+			ticket, wasLeader := u.leadershipTicket, u.leadershipTicketLeader
+			u.leadershipTicket = nil
+			if !ticket.Wait() {
+				// The tracker has stopped, and the uniter stops with it.
+				<-u.tomb.Dying()
+				return nil, tomb.ErrDying
+			}
+			// Both operations queue a hook, which ModeContinue runs
+			// before any other.
+			if wasLeader {
+				return continueAfter(u, newResignLeadershipOp())
+			}
+			return continueAfter(u, newAcceptLeadershipOp())
 		}
 		if err := u.runOperation(creator); err != nil {
 			return nil, errors.Trace(err)
@@ -242,6 +342,61 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 	}
 }
 
+// leadershipChanges returns a channel that is closed when the unit's
+// leadership no longer matches that recorded in its operation state.
+// Leader settings events are only wanted while the unit is not leader,
+// since the leader is the only unit that changes them.
+func (u *Uniter) leadershipChanges() <-chan struct{} {
+	if !leadershipHooksEnabled() {
+		return nil
+	}
+	leader := u.operationState().Leader
+	if u.leadershipTicket == nil || u.leadershipTicketLeader != leader {
+		if leader {
+			u.leadershipTicket = u.leadershipTracker.WaitMinion()
+		} else {
+			u.leadershipTicket = u.leadershipTracker.WaitLeader()
+		}
+		u.leadershipTicketLeader = leader
+		u.f.WantLeaderSettingsEvents(!leader)
+	}
+	return u.leadershipTicket.Ready()
+}
+
+// leadershipHooksEnabled returns whether the leadership hooks are run.
+// TODO: stop checking feature flag once leader election has graduated.
+func leadershipHooksEnabled() bool {
+	return featureflag.Enabled(feature.LeaderElection)
+}
+
+// meterStatusRed is the meter status code that may suspend hooks.
+const meterStatusRed = "RED"
+
+// updateHooksSuspended records whether hooks other than
+// meter-status-changed must be suspended, because the unit's meter
+// status is RED and the environment is configured to suspend them.
+func (u *Uniter) updateHooksSuspended() error {
+	code, _, err := u.unit.MeterStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	suspended := false
+	if code == meterStatusRed {
+		environConfig, err := u.st.EnvironConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		suspended = environConfig.SuspendHooksOnRedMeter()
+	}
+	if suspended && !u.hooksSuspended {
+		logger.Infof("meter status is %s; suspending hooks", code)
+	} else if !suspended && u.hooksSuspended {
+		logger.Infof("meter status is %s; resuming hooks", code)
+	}
+	u.hooksSuspended = suspended
+	return nil
+}
+
 // modeAbideDyingLoop handles the proper termination of all relations in
 // response to a Dying unit.
 func modeAbideDyingLoop(u *Uniter) (next Mode, err error) {
@@ -301,19 +456,43 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	environConfig, err := u.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	retryOpts := environConfig.HookRetryOpts()
 	u.f.WantResolvedEvent()
 	u.f.WantUpgradeEvent(true)
 	for {
+		// Automatically retry the hook, backing off exponentially, until
+		// the configured number of attempts is exhausted; after that, the
+		// error must be resolved by the user.
+		var retrySignal <-chan time.Time
+		if u.hookRetries < retryOpts.Attempts {
+			delay := hookRetryDelay(retryOpts.Delay, u.hookRetries)
+			logger.Infof("retrying %q hook in %v (attempt %d of %d)",
+				hookName, delay, u.hookRetries+1, retryOpts.Attempts)
+			retrySignal = time.After(delay)
+			statusData["retry-attempt"] = u.hookRetries + 1
+			statusData["retry-attempts"] = retryOpts.Attempts
+		} else {
+			delete(statusData, "retry-attempt")
+			delete(statusData, "retry-attempts")
+		}
 		if err = u.unit.SetUnitStatus(params.StatusError, statusMessage, statusData); err != nil {
 			return nil, errors.Trace(err)
 		}
+		var creator creator
 		select {
 		case <-u.tomb.Dying():
 			return nil, tomb.ErrDying
 		case curl := <-u.f.UpgradeEvents():
+			u.hookRetries = 0
 			return ModeUpgrading(curl), nil
+		case <-retrySignal:
+			u.hookRetries++
+			creator = newRetryHookOp(hookInfo)
 		case rm := <-u.f.ResolvedEvents():
-			var creator creator
 			switch rm {
 			case params.ResolvedRetryHooks:
 				creator = newRetryHookOp(hookInfo)
@@ -322,18 +501,20 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 			default:
 				return nil, errors.Errorf("unknown resolved mode %q", rm)
 			}
-			err := u.runOperation(creator)
-			if errors.Cause(err) == operation.ErrHookFailed {
-				continue
-			} else if err != nil {
-				return nil, errors.Trace(err)
-			}
-			return ModeContinue, nil
 		case actionId := <-u.f.ActionEvents():
 			if err := u.runOperation(newActionOp(actionId)); err != nil {
 				return nil, errors.Trace(err)
 			}
+			continue
+		}
+		err := u.runOperation(creator)
+		if errors.Cause(err) == operation.ErrHookFailed {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
 		}
+		u.hookRetries = 0
+		return ModeContinue, nil
 	}
 }
 
@@ -94,10 +94,31 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 		return opc.u.storage.CommitHook(hi)
 	case hi.Kind == hooks.ConfigChanged:
 		opc.u.ranConfigChanged = true
+		opc.recordObservedConfig()
+	case hi.Kind == hook.PreSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	case hi.Kind == hook.PostSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesCompleted)
 	}
 	return nil
 }
 
+// recordObservedConfig records the config settings seen by the
+// config-changed hook that has just run, so operators can tell whether
+// the unit has caught up with the service's settings. Should they have
+// changed while the hook ran, another config-changed hook will follow
+// and record them. Failures are not fatal, as the API server may be too
+// old to record the settings.
+func (opc *operationCallbacks) recordObservedConfig() {
+	settings, err := opc.u.unit.ConfigSettings()
+	if err == nil {
+		err = opc.u.unit.SetObservedConfigSettings(settings)
+	}
+	if err != nil {
+		logger.Warningf("cannot record observed config settings: %v", err)
+	}
+}
+
 // UpdateRelations is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) UpdateRelations(ids []int) error {
 	return opc.u.relations.Update(ids)
@@ -161,6 +182,11 @@ func (opc *operationCallbacks) ClearResolvedFlag() error {
 }
 
 // InitializeMetricsCollector is part of the operation.Callbacks interface.
+// The health probes also depend on the deployed charm, so they are
+// restarted here too.
 func (opc *operationCallbacks) InitializeMetricsCollector() error {
-	return opc.u.initializeMetricsCollector()
+	if err := opc.u.initializeMetricsCollector(); err != nil {
+		return err
+	}
+	return opc.u.initializeHealthProber()
 }
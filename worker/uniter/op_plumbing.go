@@ -58,6 +58,12 @@ func newRunHookOp(hookInfo hook.Info) creator {
 	}
 }
 
+func newRunRelationHooksOp(hookInfos []hook.Info) creator {
+	return func(factory operation.Factory) (operation.Operation, error) {
+		return factory.NewRunRelationHooks(hookInfos)
+	}
+}
+
 func newRetryHookOp(hookInfo hook.Info) creator {
 	return func(factory operation.Factory) (operation.Operation, error) {
 		return factory.NewRetryHook(hookInfo)
@@ -82,6 +88,18 @@ func newActionOp(actionId string) creator {
 	}
 }
 
+func newAcceptLeadershipOp() creator {
+	return func(factory operation.Factory) (operation.Operation, error) {
+		return factory.NewAcceptLeadership()
+	}
+}
+
+func newResignLeadershipOp() creator {
+	return func(factory operation.Factory) (operation.Operation, error) {
+		return factory.NewResignLeadership()
+	}
+}
+
 func newUpdateRelationsOp(ids []int) creator {
 	return func(factory operation.Factory) (operation.Operation, error) {
 		return factory.NewUpdateRelations(ids)
@@ -15,6 +15,8 @@ var (
 	ErrSkipExecute = errors.New("operation already executed")
 	ErrNeedsReboot = errors.New("reboot request issued")
 	ErrHookFailed  = errors.New("hook failed")
+
+	ErrCannotChangeLeadership = errors.New("cannot change leadership while not in Continue mode")
 )
 
 type deployConflictError struct {
@@ -109,6 +109,31 @@ func (f *factory) NewRunHook(hookInfo hook.Info) (Operation, error) {
 	}, nil
 }
 
+// NewRunRelationHooks is part of the Factory interface.
+func (f *factory) NewRunRelationHooks(hookInfos []hook.Info) (Operation, error) {
+	if len(hookInfos) == 0 {
+		return nil, errors.New("hooks required")
+	}
+	seen := make(map[int]bool)
+	for _, hookInfo := range hookInfos {
+		if err := hookInfo.Validate(); err != nil {
+			return nil, err
+		}
+		if !hookInfo.Kind.IsRelation() {
+			return nil, errors.Errorf("not a relation hook: %q", hookInfo.Kind)
+		}
+		if seen[hookInfo.RelationId] {
+			return nil, errors.Errorf("multiple hooks for relation %d", hookInfo.RelationId)
+		}
+		seen[hookInfo.RelationId] = true
+	}
+	return &runRelationHooks{
+		infos:         hookInfos,
+		callbacks:     f.callbacks,
+		runnerFactory: f.runnerFactory,
+	}, nil
+}
+
 // NewRetryHook is part of the Factory interface.
 func (f *factory) NewRetryHook(hookInfo hook.Info) (Operation, error) {
 	hookOp, err := f.NewRunHook(hookInfo)
@@ -161,6 +186,16 @@ func (f *factory) NewCommands(args CommandArgs, sendResponse CommandResponseFunc
 	}, nil
 }
 
+// NewAcceptLeadership is part of the Factory interface.
+func (f *factory) NewAcceptLeadership() (Operation, error) {
+	return &acceptLeadership{}, nil
+}
+
+// NewResignLeadership is part of the Factory interface.
+func (f *factory) NewResignLeadership() (Operation, error) {
+	return &resignLeadership{}, nil
+}
+
 // NewUpdateRelations is part of the Factory interface.
 func (f *factory) NewUpdateRelations(ids []int) (Operation, error) {
 	return &updateRelations{
@@ -212,6 +212,18 @@ func (s *FactorySuite) TestNewHookString_Skip(c *gc.C) {
 	c.Check(op.String(), gc.Equals, "clear resolved flag and skip run relation-joined (123; foo/22) hook")
 }
 
+func (s *FactorySuite) TestNewAcceptLeadershipString(c *gc.C) {
+	op, err := s.factory.NewAcceptLeadership()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "accept leadership")
+}
+
+func (s *FactorySuite) TestNewResignLeadershipString(c *gc.C) {
+	op, err := s.factory.NewResignLeadership()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "resign leadership")
+}
+
 func (s *FactorySuite) TestNewUpdateRelations(c *gc.C) {
 	op, err := s.factory.NewUpdateRelations([]int{1, 2, 3})
 	c.Check(err, jc.ErrorIsNil)
@@ -80,6 +80,11 @@ type Factory interface {
 	// NewRunHook creates an operation to execute the supplied hook.
 	NewRunHook(hookInfo hook.Info) (Operation, error)
 
+	// NewRunRelationHooks creates an operation to execute the supplied
+	// hooks concurrently. Every hook must be a relation hook, and no two
+	// hooks may belong to the same relation.
+	NewRunRelationHooks(hookInfos []hook.Info) (Operation, error)
+
 	// NewRetryHook creates an operation to clear the unit's resolved flag, and
 	// re-execute the supplied hook.
 	NewRetryHook(hookInfo hook.Info) (Operation, error)
@@ -96,6 +101,14 @@ type Factory interface {
 	// func.
 	NewCommands(args CommandArgs, sendResponse CommandResponseFunc) (Operation, error)
 
+	// NewAcceptLeadership creates an operation to queue a leader-elected
+	// hook, and record that the unit is leader.
+	NewAcceptLeadership() (Operation, error)
+
+	// NewResignLeadership creates an operation to queue a leader-deposed
+	// hook; the unit is recorded as no longer leader once it has run.
+	NewResignLeadership() (Operation, error)
+
 	// NewUpdateRelations creates an operation to ensure the supplied relation
 	// ids are known and tracked.
 	NewUpdateRelations(ids []int) (Operation, error)
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+)
+
+type acceptLeadership struct{}
+
+// String is part of the Operation interface.
+func (al *acceptLeadership) String() string {
+	return "accept leadership"
+}
+
+// Prepare is part of the Operation interface.
+func (al *acceptLeadership) Prepare(state State) (*State, error) {
+	if err := checkLeadershipState(state); err != nil {
+		return nil, err
+	}
+	return nil, ErrSkipExecute
+}
+
+// Execute is part of the Operation interface.
+func (al *acceptLeadership) Execute(state State) (*State, error) {
+	return nil, errors.New("prepare always errors; Execute is never valid")
+}
+
+// Commit queues a leader-elected hook, and records that the unit is
+// leader, unless that has already been done. It leaves ModeContinue to
+// run the hook, so that it runs before any other hook after a restart.
+// Commit is part of the Operation interface.
+func (al *acceptLeadership) Commit(state State) (*State, error) {
+	if err := checkLeadershipState(state); err != nil {
+		return nil, err
+	}
+	if state.Leader {
+		// A leader-elected hook is only queued when Leader is set, so
+		// it has already been queued or run.
+		return nil, nil
+	}
+	newState := stateChange{
+		Kind: RunHook,
+		Step: Queued,
+		Hook: &hook.Info{Kind: hooks.LeaderElected},
+	}.apply(state)
+	newState.Leader = true
+	return newState, nil
+}
+
+type resignLeadership struct{}
+
+// String is part of the Operation interface.
+func (rl *resignLeadership) String() string {
+	return "resign leadership"
+}
+
+// Prepare is part of the Operation interface.
+func (rl *resignLeadership) Prepare(state State) (*State, error) {
+	if err := checkLeadershipState(state); err != nil {
+		return nil, err
+	}
+	return nil, ErrSkipExecute
+}
+
+// Execute is part of the Operation interface.
+func (rl *resignLeadership) Execute(state State) (*State, error) {
+	return nil, errors.New("prepare always errors; Execute is never valid")
+}
+
+// Commit queues a leader-deposed hook, unless the unit is not leader.
+// The unit remains leader, as far as the recorded state is concerned,
+// until the hook has been committed.
+// Commit is part of the Operation interface.
+func (rl *resignLeadership) Commit(state State) (*State, error) {
+	if err := checkLeadershipState(state); err != nil {
+		return nil, err
+	}
+	if !state.Leader {
+		return nil, nil
+	}
+	return stateChange{
+		Kind: RunHook,
+		Step: Queued,
+		Hook: &hook.Info{Kind: hooks.LeaderDeposed},
+	}.apply(state), nil
+}
+
+// checkLeadershipState returns an error unless leadership changes can
+// be recorded in the supplied state.
+func checkLeadershipState(state State) error {
+	if state.Kind != Continue {
+		return ErrCannotChangeLeadership
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+type LeaderSuite struct {
+	testing.IsolationSuite
+	factory operation.Factory
+}
+
+var _ = gc.Suite(&LeaderSuite{})
+
+func (s *LeaderSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.factory = operation.NewFactory(nil, nil, nil, nil, nil)
+}
+
+func (s *LeaderSuite) TestAcceptLeadershipPrepareBadState(c *gc.C) {
+	op, err := s.factory.NewAcceptLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Prepare(operation.State{Kind: operation.RunHook})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.Equals, operation.ErrCannotChangeLeadership)
+}
+
+func (s *LeaderSuite) TestAcceptLeadershipPrepareSkipsExecute(c *gc.C) {
+	op, err := s.factory.NewAcceptLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Prepare(operation.State{Kind: operation.Continue})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.Equals, operation.ErrSkipExecute)
+}
+
+func (s *LeaderSuite) TestAcceptLeadershipCommitQueuesHook(c *gc.C) {
+	op, err := s.factory.NewAcceptLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Commit(operation.State{
+		Kind:    operation.Continue,
+		Step:    operation.Pending,
+		Started: true,
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(newState, jc.DeepEquals, &operation.State{
+		Kind:    operation.RunHook,
+		Step:    operation.Queued,
+		Hook:    &hook.Info{Kind: hooks.LeaderElected},
+		Leader:  true,
+		Started: true,
+	})
+}
+
+func (s *LeaderSuite) TestAcceptLeadershipCommitAlreadyLeader(c *gc.C) {
+	op, err := s.factory.NewAcceptLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Commit(operation.State{
+		Kind:   operation.Continue,
+		Leader: true,
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(newState, gc.IsNil)
+}
+
+func (s *LeaderSuite) TestResignLeadershipPrepareBadState(c *gc.C) {
+	op, err := s.factory.NewResignLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Prepare(operation.State{Kind: operation.RunAction})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.Equals, operation.ErrCannotChangeLeadership)
+}
+
+func (s *LeaderSuite) TestResignLeadershipCommitQueuesHook(c *gc.C) {
+	op, err := s.factory.NewResignLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Commit(operation.State{
+		Kind:   operation.Continue,
+		Step:   operation.Pending,
+		Leader: true,
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(newState, jc.DeepEquals, &operation.State{
+		Kind:   operation.RunHook,
+		Step:   operation.Queued,
+		Hook:   &hook.Info{Kind: hooks.LeaderDeposed},
+		Leader: true,
+	})
+}
+
+func (s *LeaderSuite) TestResignLeadershipCommitNotLeader(c *gc.C) {
+	op, err := s.factory.NewResignLeadership()
+	c.Assert(err, jc.ErrorIsNil)
+	newState, err := op.Commit(operation.State{Kind: operation.Continue})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(newState, gc.IsNil)
+}
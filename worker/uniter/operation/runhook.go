@@ -105,8 +105,9 @@ func (rh *runHook) Execute(state State) (*State, error) {
 }
 
 // Commit updates relation state to include the fact of the hook's execution,
-// records the impact of start and collect-metrics hooks, and queues follow-up
-// config-changed hooks to directly follow install and upgrade-charm hooks.
+// records the impact of start, leader-deposed and collect-metrics hooks, and
+// queues follow-up config-changed hooks to directly follow install and
+// upgrade-charm hooks.
 // Commit is part of the Operation interface.
 func (rh *runHook) Commit(state State) (*State, error) {
 	if err := rh.callbacks.CommitHook(rh.info); err != nil {
@@ -141,6 +142,8 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Started = true
 	case hooks.Stop:
 		newState.Stopped = true
+	case hooks.LeaderDeposed:
+		newState.Leader = false
 	case hooks.CollectMetrics:
 		newState.CollectMetricsTime = time.Now().Unix()
 	}
@@ -9,9 +9,12 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/featureflag"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5-unstable/hooks"
 
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -401,6 +404,34 @@ func (s *RunHookSuite) testCommitSuccess(c *gc.C, newHook newHook, hookInfo hook
 	c.Assert(newState, gc.DeepEquals, &after)
 }
 
+func (s *RunHookSuite) TestCommitSuccess_LeaderDeposed_ClearsLeader(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.LeaderElection)
+	featureflag.SetFlagsFromEnvironment(osenv.JujuFeatureFlagEnvKey)
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+		(operation.Factory).NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hooks.LeaderDeposed},
+			operation.State{
+				Kind:    operation.RunHook,
+				Step:    operation.Done,
+				Hook:    &hook.Info{Kind: hooks.LeaderDeposed},
+				Leader:  true,
+				Started: true,
+			},
+			operation.State{
+				Kind:    operation.Continue,
+				Step:    operation.Pending,
+				Started: true,
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) TestCommitSuccess_ConfigChanged_QueueStartHook(c *gc.C) {
 	for i, newHook := range []newHook{
 		(operation.Factory).NewRunHook,
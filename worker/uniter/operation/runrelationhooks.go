@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+// runRelationHooks executes hooks for several different relations at the
+// same time, each in its own hook context.
+//
+// Unlike runHook, it does not record the running hooks in the operation
+// state: the relations' own state is only updated as each successful hook
+// is committed, so any hook interrupted by an agent restart will be sent
+// again by its relation when hooks are next started.
+type runRelationHooks struct {
+	infos []hook.Info
+
+	callbacks     Callbacks
+	runnerFactory runner.Factory
+
+	names   []string
+	runners []runner.Runner
+	failed  []bool
+}
+
+// String is part of the Operation interface.
+func (rh *runRelationHooks) String() string {
+	descs := make([]string, len(rh.infos))
+	for i, info := range rh.infos {
+		if info.RemoteUnit == "" {
+			descs[i] = fmt.Sprintf("%s (%d)", info.Kind, info.RelationId)
+		} else {
+			descs[i] = fmt.Sprintf("%s (%d; %s)", info.Kind, info.RelationId, info.RemoteUnit)
+		}
+	}
+	return fmt.Sprintf("run relation hooks concurrently: %s", strings.Join(descs, ", "))
+}
+
+// Prepare ensures all the hooks can be executed.
+// Prepare is part of the Operation interface.
+func (rh *runRelationHooks) Prepare(state State) (*State, error) {
+	for _, info := range rh.infos {
+		name, err := rh.callbacks.PrepareHook(info)
+		if err != nil {
+			return nil, err
+		}
+		rnr, err := rh.runnerFactory.NewConcurrentHookRunner(info)
+		if err != nil {
+			return nil, err
+		}
+		rh.names = append(rh.names, name)
+		rh.runners = append(rh.runners, rnr)
+	}
+	rh.failed = make([]bool, len(rh.infos))
+	return nil, nil
+}
+
+// Execute runs all the hooks, and waits for them to complete.
+// Execute is part of the Operation interface.
+func (rh *runRelationHooks) Execute(state State) (*State, error) {
+	message := fmt.Sprintf("running hooks %s", strings.Join(rh.names, ", "))
+	unlock, err := rh.callbacks.AcquireExecutionLock(message)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	errs := make([]error, len(rh.runners))
+	var wg sync.WaitGroup
+	for i, rnr := range rh.runners {
+		wg.Add(1)
+		go func(i int, rnr runner.Runner) {
+			defer wg.Done()
+			errs[i] = rnr.RunHook(rh.names[i])
+		}(i, rnr)
+	}
+	wg.Wait()
+
+	// Report outcomes serially, in the order the hooks were requested.
+	for i, err := range errs {
+		switch cause := errors.Cause(err); {
+		case err == nil:
+			logger.Infof("ran %q hook", rh.names[i])
+			rh.callbacks.NotifyHookCompleted(rh.names[i], rh.runners[i].Context())
+		case runner.IsMissingHookError(cause):
+			logger.Infof("skipped %q hook (missing)", rh.names[i])
+		default:
+			// Reboot requests are not supported in concurrently run hooks,
+			// so they're treated just like any other failure.
+			logger.Errorf("hook %q failed: %v", rh.names[i], err)
+			rh.callbacks.NotifyHookFailed(rh.names[i], rh.runners[i].Context())
+			rh.failed[i] = true
+		}
+	}
+	return nil, nil
+}
+
+// Commit records the execution of every hook that succeeded. If any hook
+// failed, the first such hook is recorded as pending, so that the uniter
+// will wait for its error to be resolved; the remaining failed hooks will
+// be requested again by their relations once that has happened.
+// Commit is part of the Operation interface.
+func (rh *runRelationHooks) Commit(state State) (*State, error) {
+	var firstFailed *hook.Info
+	for i, info := range rh.infos {
+		if rh.failed[i] {
+			if firstFailed == nil {
+				firstFailed = &rh.infos[i]
+			}
+			continue
+		}
+		if err := rh.callbacks.CommitHook(info); err != nil {
+			return nil, err
+		}
+	}
+	if firstFailed == nil {
+		return nil, nil
+	}
+	return stateChange{
+		Kind: RunHook,
+		Step: Pending,
+		Hook: firstFailed,
+	}.apply(state), ErrHookFailed
+}
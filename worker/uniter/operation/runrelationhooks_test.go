@@ -0,0 +1,202 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+type RunRelationHooksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RunRelationHooksSuite{})
+
+var (
+	relationHook0 = hook.Info{Kind: hooks.RelationJoined, RelationId: 0, RemoteUnit: "foo/0"}
+	relationHook1 = hook.Info{Kind: hooks.RelationChanged, RelationId: 1, RemoteUnit: "bar/1"}
+	relationHook2 = hook.Info{Kind: hooks.RelationBroken, RelationId: 2}
+)
+
+// RelationHooksCallbacks records the calls made by a runRelationHooks
+// operation.
+type RelationHooksCallbacks struct {
+	operation.Callbacks
+	lockMessage string
+	unlocked    bool
+	commitErr   error
+	prepared    []hook.Info
+	committed   []hook.Info
+	completed   []string
+	failed      []string
+}
+
+func (cb *RelationHooksCallbacks) PrepareHook(hookInfo hook.Info) (string, error) {
+	cb.prepared = append(cb.prepared, hookInfo)
+	return fmt.Sprintf("rel%d-%s", hookInfo.RelationId, hookInfo.Kind), nil
+}
+
+func (cb *RelationHooksCallbacks) AcquireExecutionLock(message string) (func(), error) {
+	cb.lockMessage = message
+	return func() { cb.unlocked = true }, nil
+}
+
+func (cb *RelationHooksCallbacks) NotifyHookCompleted(hookName string, _ runner.Context) {
+	cb.completed = append(cb.completed, hookName)
+}
+
+func (cb *RelationHooksCallbacks) NotifyHookFailed(hookName string, _ runner.Context) {
+	cb.failed = append(cb.failed, hookName)
+}
+
+func (cb *RelationHooksCallbacks) CommitHook(hookInfo hook.Info) error {
+	cb.committed = append(cb.committed, hookInfo)
+	return cb.commitErr
+}
+
+// ConcurrentRunnerFactory returns a distinct runner for every hook, which
+// fails with the error recorded against the hook's relation id.
+type ConcurrentRunnerFactory struct {
+	runner.Factory
+	errs    map[int]error
+	runners map[int]*MockRunner
+}
+
+func (f *ConcurrentRunnerFactory) NewConcurrentHookRunner(hookInfo hook.Info) (runner.Runner, error) {
+	rnr := &MockRunner{
+		MockRunHook: &MockRunHook{err: f.errs[hookInfo.RelationId]},
+		context:     &MockContext{},
+	}
+	f.runners[hookInfo.RelationId] = rnr
+	return rnr, nil
+}
+
+func (s *RunRelationHooksSuite) newOp(c *gc.C, errs map[int]error) (
+	operation.Operation, *RelationHooksCallbacks, *ConcurrentRunnerFactory,
+) {
+	callbacks := &RelationHooksCallbacks{}
+	runnerFactory := &ConcurrentRunnerFactory{
+		errs:    errs,
+		runners: make(map[int]*MockRunner),
+	}
+	factory := operation.NewFactory(nil, runnerFactory, callbacks, nil, nil)
+	op, err := factory.NewRunRelationHooks([]hook.Info{relationHook0, relationHook1, relationHook2})
+	c.Assert(err, jc.ErrorIsNil)
+	return op, callbacks, runnerFactory
+}
+
+func (s *RunRelationHooksSuite) TestNewRunRelationHooksValidation(c *gc.C) {
+	factory := operation.NewFactory(nil, nil, nil, nil, nil)
+	for i, test := range []struct {
+		hookInfos []hook.Info
+		err       string
+	}{{
+		err: "hooks required",
+	}, {
+		hookInfos: []hook.Info{relationHook0, {Kind: hooks.ConfigChanged}},
+		err:       `not a relation hook: "config-changed"`,
+	}, {
+		hookInfos: []hook.Info{relationHook0, {Kind: hooks.RelationDeparted, RelationId: 0, RemoteUnit: "foo/1"}},
+		err:       "multiple hooks for relation 0",
+	}, {
+		hookInfos: []hook.Info{{Kind: hooks.RelationJoined, RelationId: 3}},
+		err:       `"relation-joined" hook requires a remote unit`,
+	}} {
+		c.Logf("test %d", i)
+		op, err := factory.NewRunRelationHooks(test.hookInfos)
+		c.Check(op, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RunRelationHooksSuite) TestString(c *gc.C) {
+	op, _, _ := s.newOp(c, nil)
+	c.Assert(op.String(), gc.Equals, "run relation hooks concurrently: "+
+		"relation-joined (0; foo/0), relation-changed (1; bar/1), relation-broken (2)")
+}
+
+func (s *RunRelationHooksSuite) TestPrepare(c *gc.C) {
+	op, callbacks, runnerFactory := s.newOp(c, nil)
+	newState, err := op.Prepare(operation.State{Kind: operation.Continue, Step: operation.Pending})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(callbacks.prepared, jc.DeepEquals, []hook.Info{relationHook0, relationHook1, relationHook2})
+	c.Assert(runnerFactory.runners, gc.HasLen, 3)
+}
+
+func (s *RunRelationHooksSuite) TestExecuteAndCommitSuccess(c *gc.C) {
+	op, callbacks, runnerFactory := s.newOp(c, map[int]error{
+		2: runner.NewMissingHookError("rel2-relation-broken"),
+	})
+	state := operation.State{Kind: operation.Continue, Step: operation.Pending}
+	_, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(callbacks.lockMessage, gc.Equals,
+		"running hooks rel0-relation-joined, rel1-relation-changed, rel2-relation-broken")
+	c.Assert(callbacks.unlocked, jc.IsTrue)
+	c.Assert(*runnerFactory.runners[0].MockRunHook.gotName, gc.Equals, "rel0-relation-joined")
+	c.Assert(*runnerFactory.runners[1].MockRunHook.gotName, gc.Equals, "rel1-relation-changed")
+	c.Assert(*runnerFactory.runners[2].MockRunHook.gotName, gc.Equals, "rel2-relation-broken")
+	c.Assert(callbacks.completed, jc.DeepEquals, []string{"rel0-relation-joined", "rel1-relation-changed"})
+	c.Assert(callbacks.failed, gc.HasLen, 0)
+
+	newState, err = op.Commit(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(callbacks.committed, jc.DeepEquals, []hook.Info{relationHook0, relationHook1, relationHook2})
+}
+
+func (s *RunRelationHooksSuite) TestExecuteAndCommitFailure(c *gc.C) {
+	op, callbacks, _ := s.newOp(c, map[int]error{
+		1: errors.New("graaargh"),
+		2: errors.New("blam"),
+	})
+	state := operation.State{Kind: operation.Continue, Step: operation.Pending, Started: true}
+	_, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(callbacks.completed, jc.DeepEquals, []string{"rel0-relation-joined"})
+	c.Assert(callbacks.failed, jc.DeepEquals, []string{"rel1-relation-changed", "rel2-relation-broken"})
+
+	newState, err = op.Commit(state)
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, jc.DeepEquals, &operation.State{
+		Kind:    operation.RunHook,
+		Step:    operation.Pending,
+		Hook:    &relationHook1,
+		Started: true,
+	})
+	c.Assert(callbacks.committed, jc.DeepEquals, []hook.Info{relationHook0})
+}
+
+func (s *RunRelationHooksSuite) TestCommitError(c *gc.C) {
+	op, callbacks, _ := s.newOp(c, nil)
+	callbacks.commitErr = errors.New("pow")
+	state := operation.State{Kind: operation.Continue, Step: operation.Pending}
+	_, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Commit(state)
+	c.Assert(err, gc.ErrorMatches, "pow")
+	c.Assert(newState, gc.IsNil)
+}
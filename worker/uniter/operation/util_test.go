@@ -298,6 +298,10 @@ func (f *MockRunnerFactory) NewHookRunner(hookInfo hook.Info) (runner.Runner, er
 	return f.MockNewHookRunner.Call(hookInfo)
 }
 
+func (f *MockRunnerFactory) NewConcurrentHookRunner(hookInfo hook.Info) (runner.Runner, error) {
+	return f.MockNewHookRunner.Call(hookInfo)
+}
+
 func (f *MockRunnerFactory) NewCommandRunner(commandInfo runner.CommandInfo) (runner.Runner, error) {
 	return f.MockNewCommandRunner.Call(commandInfo)
 }
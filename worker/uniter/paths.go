@@ -48,6 +48,11 @@ func (paths Paths) GetJujucSocket() string {
 	return paths.Runtime.JujucServerSocket
 }
 
+// GetResourcesDir exists to satisfy the context.Paths interface.
+func (paths Paths) GetResourcesDir() string {
+	return paths.State.ResourcesDir
+}
+
 // RuntimePaths represents the set of paths that are relevant at runtime.
 type RuntimePaths struct {
 
@@ -85,6 +90,9 @@ type StatePaths struct {
 	// StorageDir holds storage-specific information about what the
 	// uniter is doing and/or has done.
 	StorageDir string
+
+	// ResourcesDir holds the charm resources fetched by resource-get.
+	ResourcesDir string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -120,6 +128,7 @@ func NewPaths(dataDir string, unitTag names.UnitTag) Paths {
 			BundlesDir:     join(stateDir, "bundles"),
 			DeployerDir:    join(stateDir, "deployer"),
 			StorageDir:     join(stateDir, "storage"),
+			ResourcesDir:   join(baseDir, "resources"),
 		},
 	}
 }
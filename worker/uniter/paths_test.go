@@ -50,6 +50,7 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 			BundlesDir:     relAgent("state", "bundles"),
 			DeployerDir:    relAgent("state", "deployer"),
 			StorageDir:     relAgent("state", "storage"),
+			ResourcesDir:   relAgent("resources"),
 		},
 	})
 }
@@ -76,6 +77,7 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 			BundlesDir:     relAgent("state", "bundles"),
 			DeployerDir:    relAgent("state", "deployer"),
 			StorageDir:     relAgent("state", "storage"),
+			ResourcesDir:   relAgent("resources"),
 		},
 	})
 }
@@ -87,10 +89,12 @@ func (s *PathsSuite) TestContextInterface(c *gc.C) {
 			JujucServerSocket: "/path/to/socket",
 		},
 		State: uniter.StatePaths{
-			CharmDir: "/path/to/charm",
+			CharmDir:     "/path/to/charm",
+			ResourcesDir: "/path/to/resources",
 		},
 	}
 	c.Assert(paths.GetToolsDir(), gc.Equals, "/path/to/tools")
 	c.Assert(paths.GetCharmDir(), gc.Equals, "/path/to/charm")
 	c.Assert(paths.GetJujucSocket(), gc.Equals, "/path/to/socket")
+	c.Assert(paths.GetResourcesDir(), gc.Equals, "/path/to/resources")
 }
@@ -157,6 +157,22 @@ func (q *liveSource) Empty() bool {
 	return q.head == nil && q.changedPending == ""
 }
 
+// Size returns the number of hooks currently scheduled; it is part of the
+// hook.Sizer interface.
+func (q *liveSource) Size() int {
+	if q.Empty() {
+		return 0
+	}
+	size := 0
+	if q.changedPending != "" {
+		size++
+	}
+	for info := q.head; info != nil; info = info.next {
+		size++
+	}
+	return size
+}
+
 // Next returns the next hook.Info value to send. It will panic if the queue is
 // empty.
 func (q *liveSource) Next() hook.Info {
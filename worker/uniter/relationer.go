@@ -21,6 +21,10 @@ type Relationer struct {
 	queue relation.HookQueue
 	hooks chan<- hook.Info
 	dying bool
+
+	// hookCounter, if set, records the number of hooks scheduled by
+	// the relationer's hook source.
+	hookCounter *hook.Counter
 }
 
 // NewRelationer creates a new Relationer. The unit will not join the
@@ -104,15 +108,20 @@ func (r *Relationer) StartHooks() error {
 	if r.queue != nil {
 		panic("hooks already started!")
 	}
+	var source hook.Source
 	if r.dying {
-		r.queue = relation.NewDyingHookQueue(r.dir.State(), r.hooks)
+		source = relation.NewDyingHookSource(r.dir.State())
 	} else {
 		w, err := r.ru.Watch()
 		if err != nil {
 			return err
 		}
-		r.queue = relation.NewAliveHookQueue(r.dir.State(), r.hooks, w)
+		source = relation.NewLiveHookSource(r.dir.State(), w)
+	}
+	if r.hookCounter != nil {
+		source = hook.NewCountingSource(source, r.hookCounter)
 	}
+	r.queue = hook.NewSender(r.hooks, source)
 	return nil
 }
 
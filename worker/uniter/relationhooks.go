@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/worker/uniter/hook"
+)
+
+// parallelRelationHooksKey is the charm metadata key with which a charm
+// declares that hooks for different relations may safely run concurrently.
+const parallelRelationHooksKey = "parallel-relation-hooks"
+
+// maxParallelRelationHooks limits the number of relation hooks that will
+// be run at the same time.
+var maxParallelRelationHooks = 8
+
+// charmAllowsParallelRelationHooks reports whether the charm deployed in
+// the supplied directory declares that hooks for different relations may
+// be run concurrently. A missing charm is not an error.
+func charmAllowsParallelRelationHooks(charmDir string) (bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, "metadata.yaml"))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	var meta map[string]interface{}
+	if err := goyaml.Unmarshal(data, &meta); err != nil {
+		return false, errors.Annotate(err, "cannot parse charm metadata")
+	}
+	allowed, _ := meta[parallelRelationHooksKey].(bool)
+	return allowed, nil
+}
+
+// relationHooksCreator returns a creator for an operation that runs the
+// supplied relation hook. If the charm allows it, hooks for other relations
+// that are already waiting to be run are collected and run alongside it.
+// A waiting hook for a relation that is already represented is recorded in
+// u.deferredRelationHook, and must be run next.
+func (u *Uniter) relationHooksCreator(first hook.Info) creator {
+	if !u.parallelRelationHooks {
+		return newRunHookOp(first)
+	}
+	hookInfos := []hook.Info{first}
+	relationIds := map[int]bool{first.RelationId: true}
+collect:
+	for len(hookInfos) < maxParallelRelationHooks {
+		select {
+		case hookInfo := <-u.relations.Hooks():
+			if relationIds[hookInfo.RelationId] {
+				u.deferredRelationHook = &hookInfo
+				break collect
+			}
+			relationIds[hookInfo.RelationId] = true
+			hookInfos = append(hookInfos, hookInfo)
+		default:
+			break collect
+		}
+	}
+	if len(hookInfos) == 1 {
+		return newRunHookOp(first)
+	}
+	logger.Infof("running %d relation hooks concurrently", len(hookInfos))
+	return newRunRelationHooksOp(hookInfos)
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter"
+)
+
+type ParallelRelationHooksSuite struct{}
+
+var _ = gc.Suite(&ParallelRelationHooksSuite{})
+
+func (*ParallelRelationHooksSuite) TestCharmAllowsParallelRelationHooks(c *gc.C) {
+	for i, test := range []struct {
+		metadata string
+		expect   bool
+		err      string
+	}{{
+		metadata: "name: wordpress\nparallel-relation-hooks: true\n",
+		expect:   true,
+	}, {
+		metadata: "name: wordpress\nparallel-relation-hooks: false\n",
+	}, {
+		metadata: "name: wordpress\n",
+	}, {
+		metadata: "name: [wordpress\n",
+		err:      "cannot parse charm metadata: .*",
+	}} {
+		c.Logf("test %d", i)
+		charmDir := c.MkDir()
+		err := ioutil.WriteFile(filepath.Join(charmDir, "metadata.yaml"), []byte(test.metadata), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+		allowed, err := uniter.CharmAllowsParallelRelationHooks(charmDir)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(allowed, gc.Equals, test.expect)
+	}
+}
+
+func (*ParallelRelationHooksSuite) TestCharmAllowsParallelRelationHooksNoCharm(c *gc.C) {
+	allowed, err := uniter.CharmAllowsParallelRelationHooks(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allowed, jc.IsFalse)
+}
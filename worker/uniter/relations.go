@@ -67,10 +67,13 @@ type relations struct {
 	relationsDir  string
 	relationers   map[int]*Relationer
 	relationHooks chan hook.Info
+	hookCounter   *hook.Counter
 	abort         <-chan struct{}
 }
 
-func newRelations(st *uniter.State, tag names.UnitTag, paths Paths, abort <-chan struct{}) (*relations, error) {
+func newRelations(
+	st *uniter.State, tag names.UnitTag, paths Paths, hookCounter *hook.Counter, abort <-chan struct{},
+) (*relations, error) {
 	unit, err := st.Unit(tag)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -82,6 +85,7 @@ func newRelations(st *uniter.State, tag names.UnitTag, paths Paths, abort <-chan
 		relationsDir:  paths.State.RelationsDir,
 		relationers:   make(map[int]*Relationer),
 		relationHooks: make(chan hook.Info),
+		hookCounter:   hookCounter,
 		abort:         abort,
 	}
 	if err := r.init(); err != nil {
@@ -296,6 +300,7 @@ func (r *relations) add(rel *uniter.Relation, dir *relation.StateDir) (err error
 		return errors.Trace(err)
 	}
 	relationer := NewRelationer(ru, dir, r.relationHooks)
+	relationer.hookCounter = r.hookCounter
 	w, err := r.unit.Watch()
 	if err != nil {
 		return errors.Trace(err)
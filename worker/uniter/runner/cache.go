@@ -12,6 +12,10 @@ import (
 // SettingsFunc returns the relation settings for a unit.
 type SettingsFunc func(unitName string) (params.Settings, error)
 
+// BulkSettingsFunc returns the relation settings for several units at once.
+// Units whose settings cannot be read are omitted from the result.
+type BulkSettingsFunc func(unitNames []string) (map[string]params.Settings, error)
+
 // SettingsMap is a map from unit name to relation settings.
 type SettingsMap map[string]params.Settings
 
@@ -21,6 +25,13 @@ type SettingsMap map[string]params.Settings
 type RelationCache struct {
 	// readSettings is used to get settings data if when not already present.
 	readSettings SettingsFunc
+	// readMembers, if not nil, is used to get the settings of all the
+	// members not already present, the first time any of them is needed
+	// after the cache is pruned.
+	readMembers BulkSettingsFunc
+	// membersRead is true if readMembers has been used since the cache
+	// was last pruned.
+	membersRead bool
 	// members' keys define the relation's membership; non-nil values hold
 	// cached settings.
 	members SettingsMap
@@ -32,8 +43,18 @@ type RelationCache struct {
 // SettingsFunc to populate itself on demand. Initial membership is determined
 // by memberNames.
 func NewRelationCache(readSettings SettingsFunc, memberNames []string) *RelationCache {
+	return NewBulkRelationCache(readSettings, nil, memberNames)
+}
+
+// NewBulkRelationCache creates a new RelationCache like NewRelationCache,
+// except that when the settings of a member are first needed after the
+// cache is pruned, those of every member not already present are read at
+// once with readMembers. A hook that reads the settings of many members
+// thus makes a single call to do so.
+func NewBulkRelationCache(readSettings SettingsFunc, readMembers BulkSettingsFunc, memberNames []string) *RelationCache {
 	cache := &RelationCache{
 		readSettings: readSettings,
+		readMembers:  readMembers,
 	}
 	cache.Prune(memberNames)
 	return cache
@@ -48,6 +69,7 @@ func (cache *RelationCache) Prune(memberNames []string) {
 	}
 	cache.members = newMembers
 	cache.others = SettingsMap{}
+	cache.membersRead = false
 }
 
 // MemberNames returns the names of the remote units present in the relation.
@@ -63,6 +85,12 @@ func (cache *RelationCache) MemberNames() (memberNames []string) {
 // the settings of any unit that has ever been in the relation.
 func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
 	settings, isMember := cache.members[unitName]
+	if settings == nil && isMember && cache.readMembers != nil && !cache.membersRead {
+		if err := cache.fillMembers(); err != nil {
+			return nil, err
+		}
+		settings = cache.members[unitName]
+	}
 	if settings == nil {
 		if !isMember {
 			settings = cache.others[unitName]
@@ -83,6 +111,30 @@ func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
 	return settings, nil
 }
 
+// fillMembers reads the settings of all members not already present. The
+// settings of any members it cannot read are read individually when needed,
+// so that the reason is reported.
+func (cache *RelationCache) fillMembers() error {
+	var unitNames []string
+	for memberName, settings := range cache.members {
+		if settings == nil {
+			unitNames = append(unitNames, memberName)
+		}
+	}
+	sort.Strings(unitNames)
+	read, err := cache.readMembers(unitNames)
+	if err != nil {
+		return err
+	}
+	for unitName, settings := range read {
+		if _, isMember := cache.members[unitName]; isMember && settings != nil {
+			cache.members[unitName] = settings
+		}
+	}
+	cache.membersRead = true
+	return nil
+}
+
 // InvalidateMember ensures that the named remote unit will be considered a
 // member of the relation, and that the next attempt to read its settings will
 // use fresh data.
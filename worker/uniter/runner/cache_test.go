@@ -213,3 +213,69 @@ func (s *RelationCacheSuite) TestPruneUncachesOtherSettings(c *gc.C) {
 	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
 }
+
+func (s *RelationCacheSuite) TestBulkReadsMembersOnce(c *gc.C) {
+	var bulkCalls [][]string
+	readMembers := func(unitNames []string) (map[string]params.Settings, error) {
+		bulkCalls = append(bulkCalls, unitNames)
+		return map[string]params.Settings{
+			"x/1": {"foo": "bar"},
+			"x/2": {"baz": "qux"},
+		}, nil
+	}
+	cache := runner.NewBulkRelationCache(s.ReadSettings, readMembers, []string{"x/2", "x/1"})
+
+	settings, err := cache.Settings("x/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	settings, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
+	c.Assert(bulkCalls, jc.DeepEquals, [][]string{{"x/1", "x/2"}})
+	c.Assert(s.calls, gc.HasLen, 0)
+
+	// Once pruned, only the members not already present are read.
+	cache.InvalidateMember("x/1")
+	cache.Prune([]string{"x/1", "x/2"})
+	_, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bulkCalls, gc.HasLen, 1)
+	_, err = cache.Settings("x/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bulkCalls, jc.DeepEquals, [][]string{{"x/1", "x/2"}, {"x/1"}})
+	c.Assert(s.calls, gc.HasLen, 0)
+}
+
+func (s *RelationCacheSuite) TestBulkFallsBackForUnreadMembers(c *gc.C) {
+	s.results = []settingsResult{{
+		nil, errors.New("blam"),
+	}}
+	readMembers := func(unitNames []string) (map[string]params.Settings, error) {
+		return map[string]params.Settings{"x/1": {"foo": "bar"}}, nil
+	}
+	cache := runner.NewBulkRelationCache(s.ReadSettings, readMembers, []string{"x/1", "x/2"})
+
+	// The reason a member's settings could not be read is reported
+	// when they are read individually.
+	settings, err := cache.Settings("x/2")
+	c.Assert(settings, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "blam")
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
+
+	settings, err = cache.Settings("x/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
+}
+
+func (s *RelationCacheSuite) TestBulkPropagatesError(c *gc.C) {
+	readMembers := func(unitNames []string) (map[string]params.Settings, error) {
+		return nil, errors.New("blam")
+	}
+	cache := runner.NewBulkRelationCache(s.ReadSettings, readMembers, []string{"x/1"})
+
+	settings, err := cache.Settings("x/1")
+	c.Assert(settings, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "blam")
+	c.Assert(s.calls, gc.HasLen, 0)
+}
@@ -5,7 +5,10 @@ package runner
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +16,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils"
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v5-unstable"
 
@@ -101,6 +105,10 @@ type HookContext struct {
 	// proxySettings are the current proxy settings that the uniter knows about.
 	proxySettings proxy.Settings
 
+	// hookTimeout is the maximum time a hook run in this context may take;
+	// zero means no limit.
+	hookTimeout time.Duration
+
 	// metrics are the metrics recorded by calls to add-metric.
 	metrics []jujuc.Metric
 
@@ -139,6 +147,10 @@ type HookContext struct {
 
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
+
+	// resourcesDir is the directory to which resource-get fetches
+	// charm resources.
+	resourcesDir string
 }
 
 func (ctx *HookContext) RequestReboot(priority jujuc.RebootPriority) error {
@@ -180,6 +192,12 @@ func (ctx *HookContext) SetProcess(process *os.Process) {
 	ctx.process = process
 }
 
+// HookTimeout returns the maximum time a hook run in the context may take
+// before it is killed. A zero duration means no timeout is enforced.
+func (ctx *HookContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *HookContext) Id() string {
 	return ctx.id
 }
@@ -220,6 +238,11 @@ func (ctx *HookContext) PrivateAddress() (string, bool) {
 	return ctx.privateAddress, ctx.privateAddress != ""
 }
 
+// EndpointAddresses is part of the jujuc.Context interface.
+func (ctx *HookContext) EndpointAddresses(endpoint string) ([]string, error) {
+	return ctx.unit.EndpointAddresses(endpoint)
+}
+
 func (ctx *HookContext) AvailabilityZone() (string, bool) {
 	return ctx.availabilityzone, ctx.availabilityzone != ""
 }
@@ -232,17 +255,52 @@ func (ctx *HookContext) Storage(tag names.StorageTag) (jujuc.ContextStorage, boo
 	return ctx.storage.Storage(tag)
 }
 
-func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
+// DownloadResource is part of the jujuc.Context interface. The resource
+// is written to a temporary file which then replaces any earlier copy,
+// so hooks never see a partial download.
+func (ctx *HookContext) DownloadResource(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", errors.NotValidf("resource name %q", name)
+	}
+	if err := os.MkdirAll(ctx.resourcesDir, 0755); err != nil {
+		return "", errors.Trace(err)
+	}
+	rc, err := ctx.state.Resource(name)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot fetch resource %q", name)
+	}
+	defer rc.Close()
+
+	f, err := ioutil.TempFile(ctx.resourcesDir, name+".")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, rc)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot fetch resource %q", name)
+	}
+	path := filepath.Join(ctx.resourcesDir, name)
+	if err := utils.ReplaceFile(f.Name(), path); err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
+
+func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	return tryOpenPorts(
-		protocol, fromPort, toPort,
+		protocol, fromPort, toPort, sourceCIDR,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
 }
 
-func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error {
+func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	return tryClosePorts(
-		protocol, fromPort, toPort,
+		protocol, fromPort, toPort, sourceCIDR,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
@@ -312,6 +370,16 @@ func (ctx *HookContext) SetActionFailed() error {
 	return nil
 }
 
+// AppendActionOutput sends output written by the running Action to the
+// given stream to the state server, so that it can be followed before
+// the Action completes.
+func (ctx *HookContext) AppendActionOutput(stream, data string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return ctx.state.ActionOutput(ctx.actionData.ActionTag, stream, data)
+}
+
 // UpdateActionResults inserts new values for use with action-set and
 // action-fail.  The results struct will be delivered to the state server
 // upon completion of the Action.  It returns an error if not called on an
@@ -464,17 +532,19 @@ func (ctx *HookContext) FlushContext(process string, ctxErr error) (err error) {
 			var e error
 			var op string
 			if rangeInfo.ShouldOpen {
-				e = ctx.unit.OpenPorts(
+				e = ctx.unit.OpenPortsFrom(
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
+					rangeKey.Ports.SourceCIDR,
 				)
 				op = "open"
 			} else {
-				e = ctx.unit.ClosePorts(
+				e = ctx.unit.ClosePortsFrom(
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
+					rangeKey.Ports.SourceCIDR,
 				)
 				op = "close"
 			}
@@ -4,8 +4,11 @@
 package runner_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 
 	jc "github.com/juju/testing/checkers"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/resourcestorage"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -73,6 +77,50 @@ func (s *InterfaceSuite) TestAvailabilityZone(c *gc.C) {
 	c.Check(zone, gc.Equals, "a-zone")
 }
 
+func (s *InterfaceSuite) TestEndpointAddresses(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	addresses, err := ctx.EndpointAddresses("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"u-0.testing.invalid"})
+
+	_, err = ctx.EndpointAddresses("missing")
+	c.Assert(err, gc.ErrorMatches, `endpoint "missing" of service "u" not found`)
+}
+
+func (s *InterfaceSuite) TestDownloadResource(c *gc.C) {
+	storage, err := s.State.ResourceStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	_, err = storage.AddResource(strings.NewReader("abc"), resourcestorage.Metadata{
+		ServiceName: "u",
+		Name:        "theme",
+		Size:        3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies)
+	dir := filepath.Join(c.MkDir(), "resources")
+	runner.SetResourcesDir(ctx, dir)
+	path, err := ctx.DownloadResource("theme")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(path, gc.Equals, filepath.Join(dir, "theme"))
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "abc")
+
+	_, err = ctx.DownloadResource("plugins")
+	c.Assert(err, gc.ErrorMatches, `cannot fetch resource "plugins": resource "plugins" of service "u" not found`)
+	_, err = ctx.DownloadResource("../theme")
+	c.Assert(err, gc.ErrorMatches, `resource name "../theme" not valid`)
+
+	// Only the fetched resource is left behind.
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 1)
+}
+
 func (s *InterfaceSuite) TestUnitStatus(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	defer runner.PatchCachedStatus(ctx.(runner.Context), "maintenance", "working", map[string]interface{}{"hello": "world"})()
@@ -76,6 +76,10 @@ func ContextMachineTag(ctx Context) names.MachineTag {
 	return hctx.assignedMachineTag
 }
 
+func SetResourcesDir(ctx Context, dir string) {
+	ctx.(*HookContext).resourcesDir = dir
+}
+
 func GetStubActionContext(in map[string]interface{}) *HookContext {
 	return &HookContext{
 		actionData: &ActionData{
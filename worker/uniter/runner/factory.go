@@ -43,6 +43,11 @@ type Factory interface {
 	// supplied hook definition (which must be valid).
 	NewHookRunner(hookInfo hook.Info) (Runner, error)
 
+	// NewConcurrentHookRunner returns an execution context suitable for
+	// running the supplied hook definition alongside other hooks. Its hook
+	// tools are served on a socket dedicated to that context.
+	NewConcurrentHookRunner(hookInfo hook.Info) (Runner, error)
+
 	// NewActionRunner returns an execution context suitable for running the
 	// action identified by the supplied id.
 	NewActionRunner(actionId string) (Runner, error)
@@ -200,6 +205,31 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 	return runner, nil
 }
 
+// NewConcurrentHookRunner exists to satisfy the Factory interface.
+func (f *factory) NewConcurrentHookRunner(hookInfo hook.Info) (Runner, error) {
+	rnr, err := f.NewHookRunner(hookInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	paths := &contextPaths{
+		Paths:  f.paths,
+		socket: fmt.Sprintf("%s-%d", f.paths.GetJujucSocket(), f.rand.Int63()),
+	}
+	return NewRunner(rnr.Context(), paths), nil
+}
+
+// contextPaths overrides the jujuc socket of the wrapped Paths, so that
+// several contexts can serve hook tools at the same time.
+type contextPaths struct {
+	Paths
+	socket string
+}
+
+// GetJujucSocket is part of the Paths interface.
+func (paths *contextPaths) GetJujucSocket() string {
+	return paths.socket
+}
+
 // NewActionRunner exists to satisfy the Factory interface.
 func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	ch, err := f.getCharm()
@@ -273,6 +303,7 @@ func (f *factory) coreContext() (*HookContext, error) {
 		definedMetrics:     nil,
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		storage:            f.storage,
+		resourcesDir:       f.paths.GetResourcesDir(),
 	}
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
@@ -301,7 +332,7 @@ func (f *factory) getContextRelations() map[int]*ContextRelation {
 		if found {
 			cache.Prune(memberNames)
 		} else {
-			cache = NewRelationCache(relationUnit.ReadSettings, memberNames)
+			cache = NewBulkRelationCache(relationUnit.ReadSettings, relationUnit.ReadSettingsForUnits, memberNames)
 		}
 		relationCaches[id] = cache
 		contextRelations[id] = NewContextRelation(relationUnit, cache)
@@ -315,8 +346,7 @@ func (f *factory) getContextRelations() map[int]*ContextRelation {
 //
 // Approximately *every* line of code in this function represents a bug: ie, some
 // piece of information we expose to the charm but which we fail to report changes
-// to via hooks. Furthermore, the fact that we make multiple API calls at this
-// time, rather than grabbing everything we need in one go, is unforgivably yucky.
+// to via hooks.
 func (f *factory) updateContext(ctx *HookContext) (err error) {
 	defer errors.Trace(err)
 
@@ -324,6 +354,38 @@ func (f *factory) updateContext(ctx *HookContext) (err error) {
 	if err != nil {
 		return err
 	}
+
+	// TODO(fwereade) 23-10-2014 bug 1384572
+	// Nothing here should ever be getting the environ config directly.
+	environConfig, err := f.state.EnvironConfig()
+	if err != nil {
+		return err
+	}
+	ctx.proxySettings = environConfig.ProxySettings()
+	ctx.hookTimeout = environConfig.HookRetryOpts().Timeout
+
+	snapshot, err := f.unit.HookContextSnapshot()
+	if errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err) {
+		// The API server is too old to return a snapshot.
+		return f.updateUnitContext(ctx)
+	} else if err != nil {
+		return errors.Annotate(err, "cannot get hook context snapshot")
+	}
+	ctx.configSettings = snapshot.ConfigSettings
+	ctx.machinePorts = snapshot.MachinePorts
+	ctx.meterStatus = &meterStatus{
+		code: snapshot.MeterStatusCode,
+		info: snapshot.MeterStatusInfo,
+	}
+	ctx.publicAddress = snapshot.PublicAddress
+	ctx.privateAddress = snapshot.PrivateAddress
+	return nil
+}
+
+// updateUnitContext fills in the fields of the context that describe the
+// unit, making a separate API call for each of them, as is needed with
+// API servers that cannot return a hook context snapshot.
+func (f *factory) updateUnitContext(ctx *HookContext) (err error) {
 	ctx.machinePorts, err = f.state.AllMachinePorts(f.machineTag)
 	if err != nil {
 		return errors.Trace(err)
@@ -338,14 +400,6 @@ func (f *factory) updateContext(ctx *HookContext) (err error) {
 		info: statusInfo,
 	}
 
-	// TODO(fwereade) 23-10-2014 bug 1384572
-	// Nothing here should ever be getting the environ config directly.
-	environConfig, err := f.state.EnvironConfig()
-	if err != nil {
-		return err
-	}
-	ctx.proxySettings = environConfig.ProxySettings()
-
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
 	// unset as we always have; this isn't great but it's about behaviour preservation.
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/fs"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5-unstable"
 	"gopkg.in/juju/charm.v5-unstable/hooks"
 
 	"github.com/juju/juju/apiserver/params"
@@ -278,6 +279,31 @@ func (s *FactorySuite) TestNewHookRunner(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *FactorySuite) TestNewHookRunnerHookTimeout(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"hook-timeout": 90,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewHookRunner(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rnr.Context().HookTimeout(), gc.Equals, 90*time.Second)
+}
+
+func (s *FactorySuite) TestNewHookRunnerReadsConfigSettings(c *gc.C) {
+	rnr, err := s.factory.NewHookRunner(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The config settings are read when the context is created, so
+	// later changes are not seen by the hook.
+	err = s.service.UpdateConfigSettings(charm.Settings{
+		"blog-title": "Something Else",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := rnr.Context().ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+}
+
 func (s *FactorySuite) TestNewHookRunnerWithBadHook(c *gc.C) {
 	rnr, err := s.factory.NewHookRunner(hook.Info{})
 	c.Assert(rnr, gc.IsNil)
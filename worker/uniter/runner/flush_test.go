@@ -186,7 +186,7 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 	unitRanges, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(unitRanges, jc.DeepEquals, []network.PortRange{
-		{100, 200, "tcp"},
+		{100, 200, "tcp", ""},
 	})
 
 	// Get the context.
@@ -195,38 +195,38 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies)
 
 	// Try opening some ports via the context.
-	err = ctx.OpenPorts("tcp", 100, 200)
+	err = ctx.OpenPorts("tcp", 100, 200, "")
 	c.Assert(err, jc.ErrorIsNil) // duplicates are ignored
-	err = ctx.OpenPorts("udp", 200, 300)
+	err = ctx.OpenPorts("udp", 200, 300, "")
 	c.Assert(err, gc.ErrorMatches, `cannot open 200-300/udp \(unit "u/0"\): conflicts with existing 200-300/udp \(unit "u/1"\)`)
-	err = ctx.OpenPorts("udp", 100, 200)
+	err = ctx.OpenPorts("udp", 100, 200, "")
 	c.Assert(err, gc.ErrorMatches, `cannot open 100-200/udp \(unit "u/0"\): conflicts with existing 200-300/udp \(unit "u/1"\)`)
-	err = ctx.OpenPorts("udp", 10, 20)
+	err = ctx.OpenPorts("udp", 10, 20, "")
 	c.Assert(err, jc.ErrorIsNil)
-	err = ctx.OpenPorts("tcp", 50, 100)
+	err = ctx.OpenPorts("tcp", 50, 100, "")
 	c.Assert(err, gc.ErrorMatches, `cannot open 50-100/tcp \(unit "u/0"\): conflicts with existing 100-200/tcp \(unit "u/0"\)`)
-	err = ctx.OpenPorts("tcp", 50, 80)
+	err = ctx.OpenPorts("tcp", 50, 80, "")
 	c.Assert(err, jc.ErrorIsNil)
-	err = ctx.OpenPorts("tcp", 40, 90)
+	err = ctx.OpenPorts("tcp", 40, 90, "")
 	c.Assert(err, gc.ErrorMatches, `cannot open 40-90/tcp \(unit "u/0"\): conflicts with 50-80/tcp requested earlier`)
 
 	// Now try closing some ports as well.
-	err = ctx.ClosePorts("udp", 8080, 8088)
+	err = ctx.ClosePorts("udp", 8080, 8088, "")
 	c.Assert(err, jc.ErrorIsNil) // not existing -> ignored
-	err = ctx.ClosePorts("tcp", 100, 200)
+	err = ctx.ClosePorts("tcp", 100, 200, "")
 	c.Assert(err, jc.ErrorIsNil)
-	err = ctx.ClosePorts("tcp", 100, 200)
+	err = ctx.ClosePorts("tcp", 100, 200, "")
 	c.Assert(err, jc.ErrorIsNil) // duplicates are ignored
-	err = ctx.ClosePorts("udp", 200, 300)
+	err = ctx.ClosePorts("udp", 200, 300, "")
 	c.Assert(err, gc.ErrorMatches, `cannot close 200-300/udp \(opened by "u/1"\) from "u/0"`)
-	err = ctx.ClosePorts("tcp", 50, 80)
+	err = ctx.ClosePorts("tcp", 50, 80, "")
 	c.Assert(err, jc.ErrorIsNil) // still pending -> no longer pending
 
 	// Ensure the ports are not actually changed on the unit yet.
 	unitRanges, err = s.unit.OpenedPorts()
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(unitRanges, jc.DeepEquals, []network.PortRange{
-		{100, 200, "tcp"},
+		{100, 200, "tcp", ""},
 	})
 
 	// Flush the context with a success.
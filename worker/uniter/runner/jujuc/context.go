@@ -48,17 +48,22 @@ type Context interface {
 	// PrivateAddress returns the executing unit's private address.
 	PrivateAddress() (string, bool)
 
+	// EndpointAddresses returns the addresses on which the executing
+	// unit should serve the named relation endpoint.
+	EndpointAddresses(endpoint string) ([]string, error)
+
 	// AvailabilityZone returns the executing unit's availablilty zone.
 	AvailabilityZone() (string, bool)
 
 	// OpenPorts marks the supplied port range for opening when the
-	// executing unit's service is exposed.
-	OpenPorts(protocol string, fromPort, toPort int) error
+	// executing unit's service is exposed. If sourceCIDR is not empty,
+	// the range is only opened to connections from that CIDR.
+	OpenPorts(protocol string, fromPort, toPort int, sourceCIDR string) error
 
-	// ClosePorts ensures the supplied port range is closed even when
-	// the executing unit's service is exposed (unless it is opened
-	// separately by a co- located unit).
-	ClosePorts(protocol string, fromPort, toPort int) error
+	// ClosePorts ensures the supplied port range, opened to the given
+	// source CIDR, is closed even when the executing unit's service is
+	// exposed (unless it is opened separately by a co- located unit).
+	ClosePorts(protocol string, fromPort, toPort int, sourceCIDR string) error
 
 	// OpenedPorts returns all port ranges currently opened by this
 	// unit on its assigned machine. The result is sorted first by
@@ -128,6 +133,10 @@ type Context interface {
 	// HookStorageAttachment returns the storage attachment associated
 	// the executing hook if it was found, and whether it was found.
 	HookStorage() (ContextStorage, bool)
+
+	// DownloadResource fetches the named resource attached to the
+	// executing unit's service, and returns the path of the local copy.
+	DownloadResource(name string) (string, error)
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
@@ -154,6 +163,11 @@ type ContextRelation interface {
 
 	// ReadSettings returns the settings of any remote unit in the relation.
 	ReadSettings(unit string) (params.Settings, error)
+
+	// DrainDeadline returns the time at which the relation, if it has
+	// been removed and is draining, will be broken, and whether it is
+	// draining at all.
+	DrainDeadline() (time.Time, bool, error)
 }
 
 // ContextStorage expresses the capabilities of a hook with respect to a
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	Endpoint       string
+	PrimaryAddress bool
+	out            cmd.Output
+}
+
+func NewNetworkGetCommand(ctx Context) cmd.Command {
+	return &NetworkGetCommand{ctx: ctx}
+}
+
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get prints the addresses on which the unit should serve the
+given relation endpoint. Charms should bind the endpoint's services to
+these addresses, rather than to the one printed by
+"unit-get private-address".
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<endpoint>",
+		Purpose: "print the addresses to bind for a relation endpoint",
+		Doc:     doc,
+	}
+}
+
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.PrimaryAddress, "primary-address", false, "print only the endpoint's primary address")
+}
+
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no endpoint specified")
+	}
+	c.Endpoint = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	addresses, err := c.ctx.EndpointAddresses(c.Endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.PrimaryAddress {
+		if len(addresses) == 0 {
+			return errors.Errorf("endpoint %q has no addresses", c.Endpoint)
+		}
+		return c.out.Write(ctx, addresses[0])
+	}
+	return c.out.Write(ctx, addresses)
+}
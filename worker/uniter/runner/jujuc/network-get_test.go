@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type NetworkGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&NetworkGetSuite{})
+
+func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.endpoints = map[string][]string{
+		"db":      {"10.0.0.1", "10.1.0.1"},
+		"website": {},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("network-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"db"}, "10.0.0.1\n10.1.0.1\n"},
+		{[]string{"db", "--format", "json"}, `["10.0.0.1","10.1.0.1"]` + "\n"},
+		{[]string{"db", "--primary-address"}, "10.0.0.1\n"},
+		{[]string{"--primary-address", "db", "--format", "yaml"}, "10.0.0.1\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *NetworkGetSuite) TestNetworkGetErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"cache"}, "error: endpoint \"cache\" not found\n"},
+		{[]string{"website", "--primary-address"}, "error: endpoint \"website\" has no addresses\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 1)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.err)
+	}
+}
+
+func (s *NetworkGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no endpoint specified"},
+		{[]string{"db", "website"}, `unrecognized args: \["website"\]`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		err := testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
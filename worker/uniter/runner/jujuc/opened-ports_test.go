@@ -23,10 +23,10 @@ var _ = gc.Suite(&OpenedPortsSuite{})
 
 func (s *OpenedPortsSuite) TestRunAllFormats(c *gc.C) {
 	expectedPorts := []network.PortRange{
-		{10, 20, "tcp"},
-		{80, 80, "tcp"},
-		{53, 55, "udp"},
-		{63, 63, "udp"},
+		{10, 20, "tcp", ""},
+		{80, 80, "tcp", ""},
+		{53, 55, "udp", ""},
+		{63, 63, "udp", ""},
 	}
 	network.SortPortRanges(expectedPorts)
 	portsAsStrings := make([]string, len(expectedPorts))
@@ -82,10 +82,10 @@ Each list entry has format <port>/<protocol> (e.g. "80/tcp") or
 
 func (s *OpenedPortsSuite) getContextAndOpenPorts(c *gc.C) *Context {
 	hctx := s.GetHookContext(c, -1, "")
-	hctx.OpenPorts("tcp", 80, 80)
-	hctx.OpenPorts("tcp", 10, 20)
-	hctx.OpenPorts("udp", 63, 63)
-	hctx.OpenPorts("udp", 53, 55)
+	hctx.OpenPorts("tcp", 80, 80, "")
+	hctx.OpenPorts("tcp", 10, 20, "")
+	hctx.OpenPorts("udp", 63, 63, "")
+	hctx.OpenPorts("udp", 53, 55, "")
 	return hctx
 }
 
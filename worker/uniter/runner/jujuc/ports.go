@@ -5,6 +5,7 @@ package jujuc
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -110,6 +111,7 @@ type portCommand struct {
 	Protocol   string
 	FromPort   int
 	ToPort     int
+	SourceCIDR string
 	formatFlag string // deprecated
 }
 
@@ -119,6 +121,7 @@ func (c *portCommand) Info() *cmd.Info {
 
 func (c *portCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
+	f.StringVar(&c.SourceCIDR, "from", "", "only allow connections from the given CIDR")
 }
 
 func (c *portCommand) Init(args []string) error {
@@ -130,6 +133,11 @@ func (c *portCommand) Init(args []string) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.SourceCIDR != "" {
+		if _, _, err := net.ParseCIDR(c.SourceCIDR); err != nil {
+			return errors.Errorf("invalid source CIDR %q", c.SourceCIDR)
+		}
+	}
 
 	c.FromPort = portRange.fromPort
 	c.ToPort = portRange.toPort
@@ -148,14 +156,23 @@ var openPortInfo = &cmd.Info{
 	Name:    "open-port",
 	Args:    portFormat,
 	Purpose: "register a port or range to open",
-	Doc:     "The port range will only be open while the service is exposed.",
+	Doc: `
+The port range will only be open while the service is exposed.
+
+With --from, the port range is only opened to connections from the
+given CIDR, for example:
+
+    open-port 443 --from 10.0.0.0/8
+
+The same port range can be opened to several source CIDRs by running
+open-port once for each of them.`,
 }
 
 func NewOpenPortCommand(ctx Context) cmd.Command {
 	return &portCommand{
 		info: openPortInfo,
 		action: func(c *portCommand) error {
-			return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort)
+			return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort, c.SourceCIDR)
 		},
 	}
 }
@@ -170,7 +187,7 @@ func NewClosePortCommand(ctx Context) cmd.Command {
 	return &portCommand{
 		info: closePortInfo,
 		action: func(c *portCommand) error {
-			return ctx.ClosePorts(c.Protocol, c.FromPort, c.ToPort)
+			return ctx.ClosePorts(c.Protocol, c.FromPort, c.ToPort, c.SourceCIDR)
 		},
 	}
 }
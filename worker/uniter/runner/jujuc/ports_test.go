@@ -37,6 +37,10 @@ var portsTests = []struct {
 	{[]string{"close-port", "443/udp"}, makeRanges("99/tcp")},
 	{[]string{"open-port", "123/udp"}, makeRanges("99/tcp", "123/udp")},
 	{[]string{"close-port", "9999/UDP"}, makeRanges("99/tcp", "123/udp")},
+	{[]string{"open-port", "--from", "10.0.0.0/8", "443"}, append(makeRanges("99/tcp", "123/udp"),
+		network.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp", SourceCIDR: "10.0.0.0/8"},
+	)},
+	{[]string{"close-port", "443", "--from", "10.0.0.0/8"}, makeRanges("99/tcp", "123/udp")},
 }
 
 func makeRanges(stringRanges ...string) []network.PortRange {
@@ -99,6 +103,7 @@ var badPortsTests = []struct {
 	{[]string{"9999/foo"}, `protocol must be "tcp" or "udp"; got "foo"`},
 	{[]string{"80-90/http"}, `protocol must be "tcp" or "udp"; got "http"`},
 	{[]string{"20-10/tcp"}, `invalid port range 20-10/tcp; expected fromPort <= toPort`},
+	{[]string{"443", "--from", "10.0.0.0"}, `invalid source CIDR "10.0.0.0"`},
 }
 
 func (s *PortsSuite) TestBadArgs(c *gc.C) {
@@ -123,6 +128,14 @@ usage: open-port <port>[/<protocol>] or <from>-<to>[/<protocol>]
 purpose: register a port or range to open
 
 The port range will only be open while the service is exposed.
+
+With --from, the port range is only opened to connections from the
+given CIDR, for example:
+
+    open-port 443 --from 10.0.0.0/8
+
+The same port range can be opened to several source CIDRs by running
+open-port once for each of them.
 `[1:])
 
 	close, err := jujuc.NewCommand(hctx, cmdString("close-port"))
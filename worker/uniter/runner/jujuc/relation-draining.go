@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// RelationDrainingCommand implements the relation-draining command.
+type RelationDrainingCommand struct {
+	cmd.CommandBase
+	ctx        Context
+	RelationId int
+	out        cmd.Output
+}
+
+func NewRelationDrainingCommand(ctx Context) cmd.Command {
+	return &RelationDrainingCommand{ctx: ctx}
+}
+
+func (c *RelationDrainingCommand) Info() *cmd.Info {
+	doc := `
+relation-draining reports whether the relation has been removed and is
+draining. A draining relation is broken once its deadline passes; until
+then, its units stay related so that traffic can be moved elsewhere.
+When the relation is draining, the deadline and the number of seconds
+remaining until it are also reported.
+`
+	if _, found := c.ctx.HookRelation(); !found {
+		doc = "-r must be specified when not in a relation hook\n" + doc
+	}
+	return &cmd.Info{
+		Name:    "relation-draining",
+		Purpose: "report whether a relation is draining",
+		Doc:     doc,
+	}
+}
+
+func (c *RelationDrainingCommand) SetFlags(f *gnuflag.FlagSet) {
+	rV := newRelationIdValue(c.ctx, &c.RelationId)
+
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(rV, "r", "specify a relation by id")
+	f.Var(rV, "relation", "")
+}
+
+func (c *RelationDrainingCommand) Init(args []string) error {
+	if c.RelationId == -1 {
+		return fmt.Errorf("no relation id specified")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *RelationDrainingCommand) Run(ctx *cmd.Context) error {
+	r, found := c.ctx.Relation(c.RelationId)
+	if !found {
+		return fmt.Errorf("unknown relation id")
+	}
+	deadline, draining, err := r.DrainDeadline()
+	if err != nil {
+		return errors.Annotate(err, "cannot read relation drain status")
+	}
+	result := map[string]interface{}{"draining": draining}
+	if draining {
+		remaining := deadline.Sub(time.Now())
+		if remaining < 0 {
+			remaining = 0
+		}
+		result["deadline"] = deadline.UTC().Format(time.RFC3339)
+		result["remaining"] = int(remaining.Seconds())
+	}
+	return c.out.Write(ctx, result)
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type RelationDrainingSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&RelationDrainingSuite{})
+
+func (s *RelationDrainingSuite) run(c *gc.C, relid int, args ...string) (int, string, string) {
+	hctx := s.GetHookContext(c, relid, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-draining"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, args)
+	return code, bufferString(ctx.Stdout), bufferString(ctx.Stderr)
+}
+
+func (s *RelationDrainingSuite) TestNotDraining(c *gc.C) {
+	code, stdout, stderr := s.run(c, 0, "--format", "json")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(stdout, gc.Equals, `{"draining":false}`+"\n")
+}
+
+func (s *RelationDrainingSuite) TestDraining(c *gc.C) {
+	deadline := time.Now().Add(time.Hour).UTC()
+	s.rels[1].drainDeadline = deadline
+	code, stdout, stderr := s.run(c, 0, "-r", "peer1:1", "--format", "yaml")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(stdout, gc.Matches, `deadline: "?`+deadline.Format(time.RFC3339)+`"?\ndraining: true\nremaining: 3[56][0-9][0-9]\n`)
+}
+
+func (s *RelationDrainingSuite) TestNoRelation(c *gc.C) {
+	code, _, stderr := s.run(c, -1)
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(stderr, gc.Matches, "(.|\n)*error: no relation id specified\n")
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// ResourceGetCommand implements the resource-get command.
+type ResourceGetCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	Name string
+	out  cmd.Output
+}
+
+func NewResourceGetCommand(ctx Context) cmd.Command {
+	return &ResourceGetCommand{ctx: ctx}
+}
+
+func (c *ResourceGetCommand) Info() *cmd.Info {
+	doc := `
+resource-get fetches the named resource attached to the unit's service
+with "juju attach", and prints the path of the local copy. The copy is
+replaced each time the resource is fetched.
+`
+	return &cmd.Info{
+		Name:    "resource-get",
+		Args:    "<name>",
+		Purpose: "fetch a resource attached to the service",
+		Doc:     doc,
+	}
+}
+
+func (c *ResourceGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *ResourceGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no resource name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ResourceGetCommand) Run(ctx *cmd.Context) error {
+	path, err := c.ctx.DownloadResource(c.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, path)
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ResourceGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ResourceGetSuite{})
+
+func (s *ResourceGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.resources = map[string]string{"theme": "/var/lib/juju/resources/theme"}
+	com, err := jujuc.NewCommand(hctx, cmdString("resource-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *ResourceGetSuite) TestResourceGet(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{
+		{[]string{"theme"}, "/var/lib/juju/resources/theme\n"},
+		{[]string{"theme", "--format", "json"}, `"/var/lib/juju/resources/theme"` + "\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *ResourceGetSuite) TestResourceGetNotFound(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"plugins"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: resource \"plugins\" not found\n")
+}
+
+func (s *ResourceGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no resource name specified"},
+		{[]string{"theme", "plugins"}, `unrecognized args: \["plugins"\]`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		err := testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
@@ -215,7 +215,10 @@ var newCommandTests = []struct {
 	{"relation-get", ""},
 	{"relation-ids", ""},
 	{"relation-list", ""},
+	{"relation-draining", ""},
 	{"relation-set", ""},
+	{"network-get", ""},
+	{"resource-get", ""},
 	{"unit-get", ""},
 	{"storage-get", ""},
 	{"status-get", ""},
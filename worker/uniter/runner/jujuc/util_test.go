@@ -121,6 +121,8 @@ type Context struct {
 	storageTag     names.StorageTag
 	storage        map[names.StorageTag]*ContextStorage
 	status         jujuc.StatusInfo
+	resources      map[string]string
+	endpoints      map[string][]string
 }
 
 func (c *Context) AddMetric(key, value string, created time.Time) error {
@@ -152,6 +154,14 @@ func (c *Context) PrivateAddress() (string, bool) {
 	return "192.168.0.99", true
 }
 
+func (c *Context) EndpointAddresses(endpoint string) ([]string, error) {
+	addresses, ok := c.endpoints[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("endpoint %q not found", endpoint)
+	}
+	return addresses, nil
+}
+
 func (c *Context) AvailabilityZone() (string, bool) {
 	return "us-east-1a", true
 }
@@ -165,21 +175,23 @@ func (c *Context) HookStorage() (jujuc.ContextStorage, bool) {
 	return c.Storage(c.storageTag)
 }
 
-func (c *Context) OpenPorts(protocol string, fromPort, toPort int) error {
+func (c *Context) OpenPorts(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	c.ports = append(c.ports, network.PortRange{
-		Protocol: protocol,
-		FromPort: fromPort,
-		ToPort:   toPort,
+		Protocol:   protocol,
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		SourceCIDR: sourceCIDR,
 	})
 	network.SortPortRanges(c.ports)
 	return nil
 }
 
-func (c *Context) ClosePorts(protocol string, fromPort, toPort int) error {
+func (c *Context) ClosePorts(protocol string, fromPort, toPort int, sourceCIDR string) error {
 	portRange := network.PortRange{
-		Protocol: protocol,
-		FromPort: fromPort,
-		ToPort:   toPort,
+		Protocol:   protocol,
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		SourceCIDR: sourceCIDR,
 	}
 	for i, port := range c.ports {
 		if port == portRange {
@@ -247,9 +259,10 @@ func (c *Context) OwnerTag() string {
 }
 
 type ContextRelation struct {
-	id    int
-	name  string
-	units map[string]Settings
+	id            int
+	name          string
+	units         map[string]Settings
+	drainDeadline time.Time
 }
 
 func (r *ContextRelation) Id() int {
@@ -277,6 +290,10 @@ func (r *ContextRelation) UnitNames() []string {
 	return s
 }
 
+func (r *ContextRelation) DrainDeadline() (time.Time, bool, error) {
+	return r.drainDeadline, !r.drainDeadline.IsZero(), nil
+}
+
 func (r *ContextRelation) ReadSettings(name string) (params.Settings, error) {
 	s, found := r.units[name]
 	if !found {
@@ -326,6 +343,14 @@ func (s Settings) Map() params.Settings {
 	return r
 }
 
+func (c *Context) DownloadResource(name string) (string, error) {
+	path, ok := c.resources[name]
+	if !ok {
+		return "", fmt.Errorf("resource %q not found", name)
+	}
+	return path, nil
+}
+
 func (c *Context) RequestReboot(priority jujuc.RebootPriority) error {
 	c.rebootPriority = priority
 	if c.shouldError {
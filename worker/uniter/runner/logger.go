@@ -18,6 +18,9 @@ type hookLogger struct {
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+
+	// output, if set, receives the output as well as the logger.
+	output *outputStreamer
 }
 
 func (l *hookLogger) run() {
@@ -38,6 +41,9 @@ func (l *hookLogger) run() {
 			return
 		}
 		l.logger.Infof("%s", line)
+		if l.output != nil {
+			l.output.writeLine(line)
+		}
 		l.mu.Unlock()
 	}
 }
@@ -57,4 +63,7 @@ func (l *hookLogger) stop() {
 	l.mu.Lock()
 	l.stopped = true
 	l.mu.Unlock()
+	if l.output != nil {
+		l.output.stop()
+	}
 }
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// Action output is sent to the state server in chunks of at most
+// maxOutputChunk bytes, and is held for no more than outputFlushDelay
+// before being sent, so that it can be followed while the action runs
+// without a call for every line.
+var (
+	maxOutputChunk   = 64 * 1024
+	outputFlushDelay = time.Second
+)
+
+// outputStreamer collects the lines written by an action to one of its
+// streams and sends them on in chunks.
+type outputStreamer struct {
+	stream string
+	send   func(stream, data string) error
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	timer   *time.Timer
+	failed  bool
+	stopped bool
+}
+
+func newOutputStreamer(stream string, send func(stream, data string) error) *outputStreamer {
+	return &outputStreamer{
+		stream: stream,
+		send:   send,
+	}
+}
+
+// writeLine adds a line of output, sending the output collected so far
+// if there is enough of it, and otherwise arranging for it to be sent
+// shortly.
+func (s *outputStreamer) writeLine(line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed || s.stopped {
+		return
+	}
+	s.buf.Write(line)
+	s.buf.WriteByte('\n')
+	if s.buf.Len() >= maxOutputChunk {
+		s.flushLocked()
+		return
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(outputFlushDelay, s.flush)
+	}
+}
+
+// flush sends any output that has not yet been sent.
+func (s *outputStreamer) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLocked()
+}
+
+// stop sends any remaining output, after which no more is accepted.
+func (s *outputStreamer) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLocked()
+	s.stopped = true
+}
+
+func (s *outputStreamer) flushLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.buf.Len() == 0 || s.failed {
+		return
+	}
+	data := s.buf.String()
+	s.buf.Reset()
+	// Output is a convenience to those following the action; a
+	// failure to send it must not affect the action itself, so the
+	// rest of its output is only logged.
+	if err := s.send(s.stream, data); err != nil {
+		logger.Warningf("cannot send action %s; no more will be sent: %v", s.stream, err)
+		s.failed = true
+	}
+}
@@ -28,12 +28,13 @@ type PortRange struct {
 	RelationId int
 }
 
-func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange, error) {
+func validatePortRange(protocol string, fromPort, toPort int, sourceCIDR string) (network.PortRange, error) {
 	// Validate the given range.
 	newRange := network.PortRange{
-		Protocol: strings.ToLower(protocol),
-		FromPort: fromPort,
-		ToPort:   toPort,
+		Protocol:   strings.ToLower(protocol),
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		SourceCIDR: sourceCIDR,
 	}
 	if err := newRange.Validate(); err != nil {
 		return network.PortRange{}, err
@@ -44,6 +45,7 @@ func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange
 func tryOpenPorts(
 	protocol string,
 	fromPort, toPort int,
+	sourceCIDR string,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
 	pendingPorts map[PortRange]PortRangeInfo,
@@ -54,7 +56,7 @@ func tryOpenPorts(
 	relationId := -1
 
 	//Validate the given range.
-	newRange, err := validatePortRange(protocol, fromPort, toPort)
+	newRange, err := validatePortRange(protocol, fromPort, toPort, sourceCIDR)
 	if err != nil {
 		return err
 	}
@@ -91,6 +93,11 @@ func tryOpenPorts(
 				// ignored.
 				return nil
 			}
+			if newRange.SamePorts(portRange) && relUnitTag == unitTag {
+				// The same unit can open the same range to several
+				// sources.
+				continue
+			}
 			return errors.Errorf(
 				"cannot open %v (unit %q): conflicts with existing %v (unit %q)",
 				newRange, unitTag.Id(), portRange, relUnitTag.Id(),
@@ -99,6 +106,9 @@ func tryOpenPorts(
 	}
 	// Ensure other pending port ranges do not conflict with this one.
 	for rangeKey, rangeInfo := range pendingPorts {
+		if newRange.SamePorts(rangeKey.Ports) {
+			continue
+		}
 		if newRange.ConflictsWith(rangeKey.Ports) && rangeInfo.ShouldOpen {
 			return errors.Errorf(
 				"cannot open %v (unit %q): conflicts with %v requested earlier",
@@ -116,6 +126,7 @@ func tryOpenPorts(
 func tryClosePorts(
 	protocol string,
 	fromPort, toPort int,
+	sourceCIDR string,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
 	pendingPorts map[PortRange]PortRangeInfo,
@@ -126,7 +137,7 @@ func tryClosePorts(
 	relationId := -1
 
 	// Validate the given range.
-	newRange, err := validatePortRange(protocol, fromPort, toPort)
+	newRange, err := validatePortRange(protocol, fromPort, toPort, sourceCIDR)
 	if err != nil {
 		return err
 	}
@@ -82,6 +82,7 @@ func (s *PortsSuite) TestValidatePortRange(c *gc.C) {
 			test.proto,
 			test.ports[0],
 			test.ports[1],
+			"",
 		)
 		if test.expectErr != "" {
 			c.Check(err, gc.ErrorMatches, test.expectErr)
@@ -116,12 +117,19 @@ func makeMachinePorts(
 
 func makePendingPorts(
 	proto string, fromPort, toPort int, shouldOpen bool,
+) map[runner.PortRange]runner.PortRangeInfo {
+	return makeSourcePendingPorts(proto, fromPort, toPort, "", shouldOpen)
+}
+
+func makeSourcePendingPorts(
+	proto string, fromPort, toPort int, sourceCIDR string, shouldOpen bool,
 ) map[runner.PortRange]runner.PortRangeInfo {
 	result := make(map[runner.PortRange]runner.PortRangeInfo)
 	portRange := network.PortRange{
-		FromPort: fromPort,
-		ToPort:   toPort,
-		Protocol: proto,
+		FromPort:   fromPort,
+		ToPort:     toPort,
+		Protocol:   proto,
+		SourceCIDR: sourceCIDR,
 	}
 	key := runner.PortRange{
 		Ports:      portRange,
@@ -137,6 +145,7 @@ type portsTest struct {
 	about         string
 	proto         string
 	ports         []int
+	source        string
 	machinePorts  map[network.PortRange]params.RelationUnit
 	pendingPorts  map[runner.PortRange]runner.PortRangeInfo
 	expectErr     string
@@ -196,6 +205,28 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 		about:        "try opening a range conflicting with another pending range",
 		pendingPorts: makePendingPorts("tcp", 5, 25, true),
 		expectErr:    `cannot open 10-20/tcp \(unit "u/0"\): conflicts with 5-25/tcp requested earlier`,
+	}, {
+		about:     "invalid source CIDR",
+		source:    "10.0.0.0",
+		expectErr: `invalid source CIDR "10.0.0.0"`,
+	}, {
+		about:         "open a range already opened to another source by the same unit",
+		source:        "10.0.0.0/8",
+		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
+		expectPending: makeSourcePendingPorts("tcp", 10, 20, "10.0.0.0/8", true),
+	}, {
+		about:        "try opening a range opened to another source by another unit",
+		source:       "10.0.0.0/8",
+		machinePorts: makeMachinePorts("u/1", "tcp", 10, 20),
+		expectErr:    `cannot open 10-20/tcp from 10.0.0.0/8 \(unit "u/0"\): conflicts with existing 10-20/tcp \(unit "u/1"\)`,
+	}, {
+		about:        "open a range pending to be opened to another source",
+		source:       "10.0.0.0/8",
+		pendingPorts: makePendingPorts("tcp", 10, 20, true),
+		expectPending: map[runner.PortRange]runner.PortRangeInfo{
+			{Ports: network.PortRange{10, 20, "tcp", ""}, RelationId: -1}:           {ShouldOpen: true},
+			{Ports: network.PortRange{10, 20, "tcp", "10.0.0.0/8"}, RelationId: -1}: {ShouldOpen: true},
+		},
 	}}
 	for i, test := range tests {
 		c.Logf("test %d: %s", i, test.about)
@@ -205,6 +236,7 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 			test.proto,
 			test.ports[0],
 			test.ports[1],
+			test.source,
 			names.NewUnitTag("u/0"),
 			test.machinePorts,
 			test.pendingPorts,
@@ -259,6 +291,7 @@ func (s *PortsSuite) TestTryClosePorts(c *gc.C) {
 			test.proto,
 			test.ports[0],
 			test.ports[1],
+			test.source,
 			names.NewUnitTag("u/0"),
 			test.machinePorts,
 			test.pendingPorts,
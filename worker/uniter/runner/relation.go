@@ -5,6 +5,7 @@ package runner
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -71,6 +72,17 @@ func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 	return ctx.settings, nil
 }
 
+// DrainDeadline refreshes the relation, so that hooks learn promptly
+// that it has started draining, and returns its drain deadline.
+func (ctx *ContextRelation) DrainDeadline() (time.Time, bool, error) {
+	rel := ctx.ru.Relation()
+	if err := rel.Refresh(); err != nil {
+		return time.Time{}, false, err
+	}
+	deadline, draining := rel.DrainDeadline()
+	return deadline, draining, nil
+}
+
 // WriteSettings persists all changes made to the unit's relation settings.
 func (ctx *ContextRelation) WriteSettings() (err error) {
 	if ctx.settings != nil {
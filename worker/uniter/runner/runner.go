@@ -5,15 +5,18 @@ package runner
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -41,7 +44,9 @@ type Context interface {
 	Id() string
 	HookVars(paths Paths) []string
 	ActionData() (*ActionData, error)
+	AppendActionOutput(stream, data string) error
 	SetProcess(process *os.Process)
+	HookTimeout() time.Duration
 	FlushContext(badge string, failure error) error
 }
 
@@ -60,6 +65,10 @@ type Paths interface {
 	// to communicate back to the executing uniter process. It might be a
 	// filesystem path, or it might be abstract.
 	GetJujucSocket() string
+
+	// GetResourcesDir returns the filesystem path to the directory in
+	// which charm resources are stored when fetched.
+	GetResourcesDir() string
 }
 
 // NewRunner returns a Runner backed by the supplied context and paths.
@@ -160,24 +169,83 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	}
 	ps.Stdout = outWriter
 	ps.Stderr = outWriter
-	hookLogger := &hookLogger{
-		r:      outReader,
-		done:   make(chan struct{}),
-		logger: runner.getLogger(hookName),
+	isAction := charmLocation == "actions"
+	loggers := []*hookLogger{
+		runner.newHookLogger(hookName, outReader, params.ActionOutputStdout, isAction),
+	}
+	writers := []*os.File{outWriter}
+	if isAction {
+		// The output of an action is also streamed to the state
+		// server, so its stdout and stderr are kept apart.
+		errReader, errWriter, err := os.Pipe()
+		if err != nil {
+			outReader.Close()
+			outWriter.Close()
+			return errors.Errorf("cannot make logging pipe: %v", err)
+		}
+		ps.Stderr = errWriter
+		loggers = append(loggers, runner.newHookLogger(hookName, errReader, params.ActionOutputStderr, isAction))
+		writers = append(writers, errWriter)
+	}
+	for _, l := range loggers {
+		go l.run()
 	}
-	go hookLogger.run()
 	err = ps.Start()
-	outWriter.Close()
+	for _, w := range writers {
+		w.Close()
+	}
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(ps.Process)
 		// Block until execution finishes
-		err = ps.Wait()
+		err = runner.waitHook(hookName, ps)
+	}
+	for _, l := range loggers {
+		l.stop()
 	}
-	hookLogger.stop()
 	return errors.Trace(err)
 }
 
+// newHookLogger returns a hookLogger that logs the output read from r,
+// and if streamOutput is set, also sends it to the state server as the
+// output of the running action written to stream.
+func (runner *runner) newHookLogger(hookName string, r io.ReadCloser, stream string, streamOutput bool) *hookLogger {
+	l := &hookLogger{
+		r:      r,
+		done:   make(chan struct{}),
+		logger: runner.getLogger(hookName),
+	}
+	if streamOutput {
+		l.output = newOutputStreamer(stream, runner.context.AppendActionOutput)
+	}
+	return l
+}
+
+// waitHook blocks until the hook process exits. If the context defines a
+// hook timeout, and the process is still running when it elapses, the
+// process is killed and a hook timeout error is returned.
+func (runner *runner) waitHook(hookName string, ps *exec.Cmd) error {
+	timeout := runner.context.HookTimeout()
+	if timeout <= 0 {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+	}
+	logger.Warningf("%q hook exceeded timeout of %v; killing process %d", hookName, timeout, ps.Process.Pid)
+	if err := ps.Process.Kill(); err != nil {
+		logger.Infof("kill returned: %s", err)
+	}
+	<-done
+	return NewHookTimeoutError(hookName, timeout)
+}
+
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
@@ -151,6 +153,10 @@ type MockContext struct {
 	flushBadge   string
 	flushFailure error
 	flushResult  error
+	hookTimeout  time.Duration
+
+	mu     sync.Mutex
+	output []string
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -168,10 +174,21 @@ func (ctx *MockContext) ActionData() (*runner.ActionData, error) {
 	return ctx.actionData, nil
 }
 
+func (ctx *MockContext) AppendActionOutput(stream, data string) error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.output = append(ctx.output, stream+": "+data)
+	return nil
+}
+
 func (ctx *MockContext) SetProcess(process *os.Process) {
 	ctx.expectPid = process.Pid
 }
 
+func (ctx *MockContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *MockContext) FlushContext(badge string, failure error) error {
 	ctx.flushBadge = badge
 	ctx.flushFailure = failure
@@ -233,6 +250,44 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	ctx := &MockContext{
+		hookTimeout: 100 * time.Millisecond,
+	}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10,
+	}, s.paths.charm)
+	t0 := time.Now()
+	actualErr := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(actualErr, gc.IsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "something-happened timed out after 100ms")
+	c.Assert(runner.IsHookTimeoutError(errors.Cause(ctx.flushFailure)), jc.IsTrue)
+	if time.Now().Sub(t0) > 5*time.Second {
+		c.Errorf("hook was not killed when its timeout expired")
+	}
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunHookWithinTimeout(c *gc.C) {
+	ctx := &MockContext{
+		hookTimeout: coretesting.LongWait,
+	}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+		code: 123,
+	}, s.paths.charm)
+	actualErr := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(actualErr, gc.IsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -270,6 +325,38 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunActionStreamsOutput(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &runner.ActionData{},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "to stdout",
+		stderr: "to stderr",
+	}, s.paths.charm)
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.output, jc.SameContents, []string{
+		"stdout: to stdout\n",
+		"stderr: to stderr\n",
+	})
+}
+
+func (s *RunMockContextSuite) TestRunHookDoesNotStreamOutput(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "to stdout",
+	}, s.paths.charm)
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.output, gc.HasLen, 0)
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -48,11 +48,16 @@ func (MockEnvPaths) GetJujucSocket() string {
 	return "path-to-jujuc.socket"
 }
 
+func (MockEnvPaths) GetResourcesDir() string {
+	return "path-to-resources"
+}
+
 // RealPaths implements Paths for tests that do touch the filesystem.
 type RealPaths struct {
-	tools  string
-	charm  string
-	socket string
+	tools     string
+	charm     string
+	socket    string
+	resources string
 }
 
 func osDependentSockPath(c *gc.C) string {
@@ -65,9 +70,10 @@ func osDependentSockPath(c *gc.C) string {
 
 func NewRealPaths(c *gc.C) RealPaths {
 	return RealPaths{
-		tools:  c.MkDir(),
-		charm:  c.MkDir(),
-		socket: osDependentSockPath(c),
+		tools:     c.MkDir(),
+		charm:     c.MkDir(),
+		socket:    osDependentSockPath(c),
+		resources: c.MkDir(),
 	}
 }
 
@@ -83,6 +89,10 @@ func (p RealPaths) GetJujucSocket() string {
 	return p.socket
 }
 
+func (p RealPaths) GetResourcesDir() string {
+	return p.resources
+}
+
 // HookContextSuite contains shared setup for various other test suites. Test
 // methods should not be added to this type, because they'll get run repeatedly.
 type HookContextSuite struct {
@@ -273,6 +283,8 @@ type hookSpec struct {
 	stderr string
 	// background holds a string to print in the background after 0.2s.
 	background string
+	// sleep holds the number of seconds the hook should block before exiting.
+	sleep int
 }
 
 // makeCharm constructs a fake charm dir containing a single named hook
@@ -317,6 +329,13 @@ func makeCharm(c *gc.C, spec hookSpec, charmDir string) {
 		// expected.
 		printf("(sleep 0.2; echo %s; sleep 10) &", spec.background)
 	}
+	if spec.sleep != 0 {
+		if runtime.GOOS != "windows" {
+			printf("sleep %d", spec.sleep)
+		} else {
+			printf("Start-Sleep -s %d", spec.sleep)
+		}
+	}
 	printf("exit %d", spec.code)
 }
 
@@ -25,6 +25,8 @@ import (
 	"github.com/juju/juju/worker/leadership"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/filter"
+	"github.com/juju/juju/worker/uniter/health"
+	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -66,11 +68,35 @@ type Uniter struct {
 	leadershipManager coreleadership.LeadershipManager
 	leadershipTracker leadership.Tracker
 
+	// leadershipTicket, when not nil, will become ready when the unit's
+	// leadership changes from that recorded by leadershipTicketLeader.
+	// It is kept across modes, so that the tracker holds at most one
+	// ticket for the uniter.
+	leadershipTicket       leadership.Ticket
+	leadershipTicketLeader bool
+
 	hookLock    *fslock.Lock
 	runListener *RunListener
 
 	ranConfigChanged bool
 
+	// hookRetries holds the number of automatic retries made of the
+	// currently failed hook.
+	hookRetries int
+
+	// parallelRelationHooks records whether the deployed charm allows
+	// hooks for different relations to run concurrently.
+	parallelRelationHooks bool
+
+	// deferredRelationHook holds a relation hook that was received while
+	// collecting hooks to run concurrently, but which belongs to a relation
+	// already represented; it must be run before any other hook is accepted.
+	deferredRelationHook *hook.Info
+
+	// hookCounter tracks the number of relation hooks that are scheduled
+	// but have not yet been delivered to the uniter.
+	hookCounter hook.Counter
+
 	// The execution observer is only used in tests at this stage. Should this
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
@@ -78,6 +104,14 @@ type Uniter struct {
 	// collectMetricsAt defines a function that will be used to generate signals
 	// for the collect-metrics hook.
 	collectMetricsAt CollectMetricsSignal
+
+	// healthProber runs the health probes declared by the deployed
+	// charm, if any.
+	healthProber *health.Prober
+
+	// hooksSuspended records whether the unit's RED meter status
+	// suspends all hooks other than meter-status-changed.
+	hooksSuspended bool
 }
 
 // NewUniter creates a new Uniter which will install, run, and upgrade
@@ -105,6 +139,12 @@ func NewUniter(
 	return u
 }
 
+// HookQueueDepth returns the number of relation hooks that are waiting to
+// be run. It is safe to call from any goroutine.
+func (u *Uniter) HookQueueDepth() int {
+	return u.hookCounter.Count()
+}
+
 type cleanup func() error
 
 func (u *Uniter) addCleanup(cleanup cleanup) {
@@ -213,7 +253,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err := os.MkdirAll(u.paths.State.RelationsDir, 0755); err != nil {
 		return errors.Trace(err)
 	}
-	relations, err := newRelations(u.st, unitTag, u.paths, u.tomb.Dying())
+	relations, err := newRelations(u.st, unitTag, u.paths, &u.hookCounter, u.tomb.Dying())
 	if err != nil {
 		return errors.Annotatef(err, "cannot create relations")
 	}
@@ -236,6 +276,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		return errors.Annotatef(err, "cannot create deployer")
 	}
 	u.deployer = &deployerProxy{deployer}
+	u.addCleanup(u.stopHealthProber)
 	runnerFactory, err := runner.NewFactory(
 		u.st, unitTag, u.leadershipTracker, u.relations.GetInfo, u.storage, u.paths,
 	)
@@ -317,6 +358,34 @@ func (u *Uniter) initializeMetricsCollector() error {
 	return nil
 }
 
+// initializeHealthProber replaces any running health prober with one
+// that runs the probes declared by the currently deployed charm.
+func (u *Uniter) initializeHealthProber() error {
+	if err := u.stopHealthProber(); err != nil {
+		return errors.Trace(err)
+	}
+	probes, err := health.ReadProbes(u.paths.State.CharmDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(probes) == 0 {
+		return nil
+	}
+	prober := health.NewProber(u.unit, u.paths.State.CharmDir, probes)
+	go func() { u.tomb.Kill(prober.Wait()) }()
+	u.healthProber = prober
+	return nil
+}
+
+func (u *Uniter) stopHealthProber() error {
+	if u.healthProber == nil {
+		return nil
+	}
+	err := u.healthProber.Stop()
+	u.healthProber = nil
+	return err
+}
+
 // RunCommands executes the supplied commands in a hook context.
 func (u *Uniter) RunCommands(args RunCommandsArgs) (results *exec.ExecResponse, err error) {
 	// TODO(fwereade): this is *still* all sorts of messed-up and not especially
@@ -194,6 +194,38 @@ func (s *UniterSuite) TestUniterInstallHook(c *gc.C) {
 				status: params.StatusActive,
 			},
 			waitHooks{"install", "config-changed", "start"},
+		), ut(
+			"install hook fail and automatic retry",
+			setHookRetryOpts{attempts: 2, delay: 1},
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			createUniter{},
+			waitHooks{"fail-install", "fail-install", "fail-install"},
+			waitUnit{
+				status: params.StatusError,
+				info:   `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			fixHook{"install"},
+			resolveError{state.ResolvedRetryHooks},
+			waitUnit{
+				status: params.StatusActive,
+			},
+			waitHooks{"install", "config-changed", "start"},
+		), ut(
+			"install hook fixed before automatic retry",
+			setHookRetryOpts{attempts: 1, delay: 1},
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			createUniter{},
+			waitHooks{"fail-install"},
+			fixHook{"install"},
+			waitUnit{
+				status: params.StatusActive,
+			},
+			waitHooks{"install", "config-changed", "start"},
 		),
 	})
 }
@@ -1168,6 +1200,23 @@ func (s *UniterSuite) TestUniterMeterStatusChanged(c *gc.C) {
 			quickStart{},
 			changeMeterStatus{"AMBER", "Investigate charm."},
 			waitHooks{"meter-status-changed"},
+		), ut(
+			"hooks suspended while meter status is RED",
+			setSuspendHooksOnRedMeter(true),
+			quickStart{},
+			changeMeterStatus{"RED", "Payment overdue."},
+			waitHooks{"meter-status-changed"},
+			changeConfig{"blog-title": "Goodness Gracious Me"},
+			waitHooks{},
+			changeMeterStatus{"GREEN", "Paid."},
+			waitHooks{"meter-status-changed", "config-changed"},
+		), ut(
+			"hooks not suspended by RED meter status by default",
+			quickStart{},
+			changeMeterStatus{"RED", "Payment overdue."},
+			waitHooks{"meter-status-changed"},
+			changeConfig{"blog-title": "Goodness Gracious Me"},
+			waitHooks{"config-changed"},
 		),
 	})
 }
@@ -1706,6 +1755,19 @@ func (s *UniterSuite) TestLeadership(c *gc.C) {
 			quickStart{},
 			runCommands{fmt.Sprintf("leader-set foo=bar baz=qux")},
 			verifyLeaderSettings{"foo": "bar", "baz": "qux"},
+		), ut(
+			"leader-elected runs before the first config-changed",
+			createCharm{
+				customize: func(c *gc.C, ctx *context, path string) {
+					hpath := filepath.Join(path, "hooks", "leader-elected")
+					ctx.writeHook(c, hpath, true)
+				},
+			},
+			serveCharm{},
+			createUniter{},
+			waitUnit{status: params.StatusActive},
+			waitHooks{"install", "leader-elected", "config-changed", "start"},
+			verifyCharm{},
 		),
 	})
 }
@@ -1268,6 +1268,30 @@ func (s setProxySettings) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setHookRetryOpts struct {
+	attempts int
+	delay    int
+}
+
+func (s setHookRetryOpts) step(c *gc.C, ctx *context) {
+	attrs := map[string]interface{}{
+		"hook-retry-attempts": s.attempts,
+		"hook-retry-delay":    s.delay,
+	}
+	err := ctx.st.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type setSuspendHooksOnRedMeter bool
+
+func (s setSuspendHooksOnRedMeter) step(c *gc.C, ctx *context) {
+	attrs := map[string]interface{}{
+		"suspend-hooks-on-red-meter": bool(s),
+	}
+	err := ctx.st.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type relationRunCommands []string
 
 func (cmds relationRunCommands) step(c *gc.C, ctx *context) {
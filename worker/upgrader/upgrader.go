@@ -84,7 +84,9 @@ func (u *Upgrader) Stop() error {
 }
 
 // allowedTargetVersion checks if targetVersion is too different from
-// curVersion to allow a downgrade.
+// curVersion to allow a downgrade. Agents may be rolled back to any
+// earlier version within the same minor series, or to the version
+// they were upgrading from while the upgrade steps are running.
 func allowedTargetVersion(
 	origAgentVersion version.Number,
 	curVersion version.Number,
@@ -149,7 +151,11 @@ func (u *Upgrader) loop() error {
 				wantVersion, version.Current)
 			continue
 		}
-		logger.Infof("upgrade requested from %v to %v", version.Current, wantVersion)
+		if wantVersion.Compare(version.Current.Number) < 0 {
+			logger.Infof("rollback requested from %v to %v", version.Current, wantVersion)
+		} else {
+			logger.Infof("upgrade requested from %v to %v", version.Current, wantVersion)
+		}
 
 		// Check if tools have already been downloaded.
 		wantVersionBinary := toBinaryVersion(wantVersion)